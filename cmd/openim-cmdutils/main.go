@@ -53,7 +53,14 @@ func main() {
 	// openIM clear msg --userID=xxx --beginSeq=100 --limit=10
 	// openIM clear msg --superGroupID=xxx --beginSeq=100 --limit=10
 	// openIM clear msg --clearAll
-	msgUtilsCmd.AddCommand(&getCmd.Command, &fixCmd.Command, &clearCmd.Command)
+	seqGapCmd := cmd.NewSeqGapCmd()
+	// openIM seqgap --conversationID=xxx
+	// openIM seqgap --conversationID=xxx --repair=placeholder
+	// openIM seqgap --conversationID=xxx --repair=compact
+	replayCmd := cmd.NewDeadLetterReplayCmd()
+	// openIM replaydlq
+	// openIM replaydlq --idle=30
+	msgUtilsCmd.AddCommand(&getCmd.Command, &fixCmd.Command, &clearCmd.Command, seqGapCmd.RunCmd(), replayCmd.RunCmd())
 	if err := msgUtilsCmd.Execute(); err != nil {
 		util.ExitWithError(err)
 	}
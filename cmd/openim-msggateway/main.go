@@ -24,6 +24,7 @@ func main() {
 	msgGatewayCmd.AddWsPortFlag()
 	msgGatewayCmd.AddPortFlag()
 	msgGatewayCmd.AddPrometheusPortFlag()
+	msgGatewayCmd.AddConformanceVerifyCmd()
 	if err := msgGatewayCmd.Exec(); err != nil {
 		util.ExitWithError(err)
 	}
@@ -0,0 +1,108 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+)
+
+type AntiSpamApi struct {
+	database controller.AntiSpamDatabase
+	config   *config.GlobalConfig
+}
+
+func NewAntiSpamApi(database controller.AntiSpamDatabase, config *config.GlobalConfig) AntiSpamApi {
+	return AntiSpamApi{database: database, config: config}
+}
+
+func (a *AntiSpamApi) RestrictUser(c *gin.Context) {
+	var req apistruct.RestrictUserReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, a.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	restriction := &controller.UserRestriction{
+		UserID:         req.UserID,
+		Action:         req.Action,
+		Rate:           req.Rate,
+		Reason:         req.Reason,
+		OperatorUserID: mcontext.GetOpUserID(c),
+	}
+	if err := a.database.Restrict(c, restriction, time.Second*time.Duration(req.Duration)); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (a *AntiSpamApi) UnrestrictUser(c *gin.Context) {
+	var req apistruct.UnrestrictUserReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, a.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := a.database.Unrestrict(c, req.UserID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (a *AntiSpamApi) GetUserRestriction(c *gin.Context) {
+	var req apistruct.GetUserRestrictionReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, a.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	restriction, err := a.database.GetRestriction(c, req.UserID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetUserRestrictionResp{}
+	if restriction != nil {
+		resp.Restriction = &apistruct.UserRestriction{
+			UserID:         restriction.UserID,
+			Action:         restriction.Action,
+			Rate:           restriction.Rate,
+			Reason:         restriction.Reason,
+			OperatorUserID: restriction.OperatorUserID,
+			CreateTime:     restriction.CreateTime,
+			ExpireTime:     restriction.ExpireTime,
+		}
+	}
+	apiresp.GinSuccess(c, resp)
+}
@@ -0,0 +1,115 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+)
+
+// AppApi registers the apps of the cluster, the services pick up changes within tenant.reloadInterval.
+type AppApi struct {
+	database controller.AppDatabase
+	config   *config.GlobalConfig
+}
+
+func NewAppApi(database controller.AppDatabase, config *config.GlobalConfig) AppApi {
+	return AppApi{database: database, config: config}
+}
+
+func (a *AppApi) CreateApp(c *gin.Context) {
+	var req apistruct.CreateAppReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckIMAdmin(c, a.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	app, err := a.database.CreateApp(c, req.AppID, req.Name, req.ManagerUserIDs)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.CreateAppResp{AppID: app.AppID, Secret: app.Secret})
+}
+
+func (a *AppApi) ResetAppSecret(c *gin.Context) {
+	var req apistruct.ResetAppSecretReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckIMAdmin(c, a.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	secret, err := a.database.ResetAppSecret(c, req.AppID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.ResetAppSecretResp{Secret: secret})
+}
+
+func (a *AppApi) SetAppManagers(c *gin.Context) {
+	var req apistruct.SetAppManagersReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckIMAdmin(c, a.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := a.database.SetAppManagers(c, req.AppID, req.ManagerUserIDs); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (a *AppApi) GetApps(c *gin.Context) {
+	var req apistruct.GetAppsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckIMAdmin(c, a.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, apps, err := a.database.PageApps(c, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetAppsResp{Total: total, Apps: make([]*apistruct.App, 0, len(apps))}
+	for _, app := range apps {
+		resp.Apps = append(resp.Apps, &apistruct.App{
+			AppID:          app.AppID,
+			Name:           app.Name,
+			ManagerUserIDs: app.ManagerUserIDs,
+			CreateTime:     app.CreateTime.UnixMilli(),
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
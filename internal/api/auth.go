@@ -17,22 +17,58 @@ package api
 import (
 	"github.com/OpenIMSDK/protocol/auth"
 	"github.com/OpenIMSDK/tools/a2r"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
 	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 )
 
-type AuthApi rpcclient.Auth
+type AuthApi struct {
+	rpcclient.Auth
+	database controller.AuthDatabase
+}
 
-func NewAuthApi(client rpcclient.Auth) AuthApi {
-	return AuthApi(client)
+func NewAuthApi(client rpcclient.Auth, database controller.AuthDatabase) AuthApi {
+	return AuthApi{Auth: client, database: database}
 }
 
+// UserToken issues a token, binding it to the deviceFingerprint header when one is sent.
 func (o *AuthApi) UserToken(c *gin.Context) {
-	a2r.Call(auth.AuthClient.UserToken, o.Client, c)
+	var req auth.UserTokenReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	resp, err := o.Client.UserToken(c, &req)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := o.database.BindFingerprint(c, resp.Token, c.GetHeader(authverify.DeviceFingerprint)); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, resp)
 }
 
 func (o *AuthApi) GetUserToken(c *gin.Context) {
-	a2r.Call(auth.AuthClient.GetUserToken, o.Client, c)
+	var req auth.GetUserTokenReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	resp, err := o.Client.GetUserToken(c, &req)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := o.database.BindFingerprint(c, resp.Token, c.GetHeader(authverify.DeviceFingerprint)); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, resp)
 }
 
 func (o *AuthApi) ParseToken(c *gin.Context) {
@@ -42,3 +78,13 @@ func (o *AuthApi) ParseToken(c *gin.Context) {
 func (o *AuthApi) ForceLogout(c *gin.Context) {
 	a2r.Call(auth.AuthClient.ForceLogout, o.Client, c)
 }
+
+// GetPublicKeys returns the token verification keys in JWK set format.
+func (o *AuthApi) GetPublicKeys(c *gin.Context) {
+	keys, err := authverify.PublicKeys(o.Config)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, gin.H{"keys": keys})
+}
@@ -0,0 +1,168 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/protocol/user"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"google.golang.org/protobuf/proto"
+)
+
+// BroadcastApi queues messages to every user, the cron task sends them at the configured rate.
+type BroadcastApi struct {
+	MessageApi
+	database controller.BroadcastJobDatabase
+	config   *config.GlobalConfig
+}
+
+func NewBroadcastApi(msgApi MessageApi, database controller.BroadcastJobDatabase, config *config.GlobalConfig) BroadcastApi {
+	return BroadcastApi{MessageApi: msgApi, database: database, config: config}
+}
+
+func (b *BroadcastApi) BroadcastMsg(c *gin.Context) {
+	var req apistruct.BroadcastMsgReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, b.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if !b.config.Broadcast.Enable {
+		apiresp.GinError(c, errs.ErrNoPermission.Wrap("broadcast msg is disabled"))
+		return
+	}
+	sendMsgReq, err := b.getSendMsgReq(c, req.SendMsg)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if sessionType := sendMsgReq.MsgData.SessionType; sessionType != constant.SingleChatType && sessionType != constant.NotificationChatType {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("a broadcast msg is a single chat or notification msg"))
+		return
+	}
+	appID := tenant.GetAppID(c)
+	if appID == "" {
+		appID = c.GetHeader(tenant.AppIDKey)
+	}
+	job := &relation.BroadcastJobModel{
+		AppID:          appID,
+		OperatorUserID: mcontext.GetOpUserID(c),
+		RecvIDs:        req.RecvIDs,
+		PageSize:       b.config.Broadcast.PageSize,
+	}
+	if len(req.RecvIDs) == 0 {
+		resp, err := b.userRpcClient.Client.GetAllUserID(c, &user.GetAllUserIDReq{Pagination: &sdkws.RequestPagination{PageNumber: 1, ShowNumber: 1}})
+		if err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		job.Total = int64(resp.Total)
+	}
+	jobID, err := b.database.CreateJob(c, job, sendMsgReq.MsgData, b.config.Broadcast.MaxRecvIDs)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.BroadcastMsgResp{JobID: jobID})
+}
+
+func (b *BroadcastApi) GetBroadcastJob(c *gin.Context) {
+	var req apistruct.GetBroadcastJobReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, b.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	job, err := b.database.TakeJob(c, req.JobID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	var msgData sdkws.MsgData
+	if err := proto.Unmarshal(job.MsgData, &msgData); err != nil {
+		apiresp.GinError(c, errs.Wrap(err))
+		return
+	}
+	resp := convertBroadcastJob(job)
+	resp.MsgData = &msgData
+	apiresp.GinSuccess(c, resp)
+}
+
+func (b *BroadcastApi) GetBroadcastJobs(c *gin.Context) {
+	var req apistruct.GetBroadcastJobsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, b.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, jobs, err := b.database.GetJobs(c, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.GetBroadcastJobsResp{Total: total, Jobs: utils.Slice(jobs, convertBroadcastJob)})
+}
+
+func (b *BroadcastApi) CancelBroadcast(c *gin.Context) {
+	var req apistruct.CancelBroadcastReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, b.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := b.database.Cancel(c, req.JobID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func convertBroadcastJob(job *relation.BroadcastJobModel) *apistruct.BroadcastJob {
+	return &apistruct.BroadcastJob{
+		JobID:          job.JobID,
+		OperatorUserID: job.OperatorUserID,
+		Status:         job.Status,
+		Err:            job.Err,
+		Total:          job.Total,
+		Sent:           job.Sent,
+		Failed:         job.Failed,
+		CreateTime:     unixMilli(job.CreateTime),
+		UpdateTime:     unixMilli(job.UpdateTime),
+		FinishTime:     unixMilli(job.FinishTime),
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+)
+
+// Bulkhead isolates route groups from each other: every group gets its own pool of concurrent
+// requests and every user is capped across all isolated groups, so a burst of uploads from one
+// user cannot occupy the goroutines that serve message sending.
+type Bulkhead struct {
+	enable  bool
+	perUser int
+	wait    time.Duration
+
+	lock  sync.Mutex
+	users map[string]int
+}
+
+func NewBulkhead(config *config.GlobalConfig) *Bulkhead {
+	conf := config.Api.Bulkhead
+	return &Bulkhead{
+		enable:  conf.Enable,
+		perUser: conf.PerUser,
+		wait:    time.Duration(conf.Wait) * time.Millisecond,
+		users:   make(map[string]int),
+	}
+}
+
+// Pool returns a middleware that lets at most size requests of the route group run at once.
+// A request waits up to the configured wait for a free slot and is rejected otherwise.
+func (b *Bulkhead) Pool(name string, size int) gin.HandlerFunc {
+	if !b.enable || size <= 0 {
+		return func(c *gin.Context) {}
+	}
+	slots := make(chan struct{}, size)
+	return func(c *gin.Context) {
+		if !b.acquire(slots) {
+			log.ZWarn(c, "bulkhead pool is full", nil, "pool", name, "size", size)
+			apiresp.GinError(c, servererrs.ErrTooManyRequests.Wrap("too many requests for "+name))
+			c.Abort()
+			return
+		}
+		defer func() { <-slots }()
+		c.Next()
+	}
+}
+
+// User returns a middleware that caps the concurrent requests of the token user. It must run after token parsing.
+func (b *Bulkhead) User() gin.HandlerFunc {
+	if !b.enable || b.perUser <= 0 {
+		return func(c *gin.Context) {}
+	}
+	return func(c *gin.Context) {
+		userID := c.GetString(constant.OpUserID)
+		if userID == "" {
+			return
+		}
+		if !b.enterUser(userID) {
+			log.ZWarn(c, "bulkhead user limit reached", nil, "userID", userID, "perUser", b.perUser)
+			apiresp.GinError(c, servererrs.ErrTooManyRequests.Wrap("too many concurrent requests of the user"))
+			c.Abort()
+			return
+		}
+		defer b.leaveUser(userID)
+		c.Next()
+	}
+}
+
+func (b *Bulkhead) acquire(slots chan struct{}) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+	}
+	if b.wait <= 0 {
+		return false
+	}
+	timer := time.NewTimer(b.wait)
+	defer timer.Stop()
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func (b *Bulkhead) enterUser(userID string) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.users[userID] >= b.perUser {
+		return false
+	}
+	b.users[userID]++
+	return true
+}
+
+func (b *Bulkhead) leaveUser(userID string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.users[userID] <= 1 {
+		delete(b.users, userID)
+		return
+	}
+	b.users[userID]--
+}
@@ -0,0 +1,125 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/notificationcatalog"
+)
+
+type BusinessTopicApi struct {
+	database controller.BusinessTopicDatabase
+	config   *config.GlobalConfig
+}
+
+func NewBusinessTopicApi(database controller.BusinessTopicDatabase, config *config.GlobalConfig) BusinessTopicApi {
+	return BusinessTopicApi{database: database, config: config}
+}
+
+func (b *BusinessTopicApi) SubscribeBusinessTopic(c *gin.Context) {
+	var req apistruct.SubscribeBusinessTopicReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, b.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := b.database.Subscribe(c, req.UserID, utils.Distinct(req.Topics)); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (b *BusinessTopicApi) UnsubscribeBusinessTopic(c *gin.Context) {
+	var req apistruct.SubscribeBusinessTopicReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, b.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := b.database.Unsubscribe(c, req.UserID, req.Topics); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (b *BusinessTopicApi) GetSubscribedBusinessTopics(c *gin.Context) {
+	var req apistruct.GetSubscribedBusinessTopicsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, b.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	topics, err := b.database.GetUserTopics(c, req.UserID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.GetSubscribedBusinessTopicsResp{Topics: topics})
+}
+
+// PublishBusinessNotification queues a notification for the topic; msgtransfer delivers it to each subscriber.
+func (b *BusinessTopicApi) PublishBusinessNotification(c *gin.Context) {
+	var req apistruct.PublishBusinessNotificationReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if !authverify.IsAppManagerUid(c, b.config) {
+		apiresp.GinError(c, errs.ErrNoPermission.Wrap("only app manager can publish business notification"))
+		return
+	}
+	msgData := &sdkws.MsgData{
+		SendID: req.SendUserID,
+		Content: []byte(utils.StructToJsonString(&sdkws.NotificationElem{
+			Detail: utils.StructToJsonString(&notificationcatalog.BusinessElem{Key: req.Key, Data: req.Data}),
+		})),
+		MsgFrom:     constant.SysMsgType,
+		ContentType: constant.BusinessNotification,
+		SessionType: constant.SingleChatType,
+		CreateTime:  utils.GetCurrentTimestampByMill(),
+		ClientMsgID: utils.GetMsgID(mcontext.GetOpUserID(c)),
+		Options: config.GetOptionsByNotification(config.NotificationConf{
+			IsSendMsg:        false,
+			ReliabilityLevel: 1,
+			UnreadCount:      false,
+		}),
+	}
+	if err := b.database.Publish(c, req.Topic, msgData); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
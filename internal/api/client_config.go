@@ -0,0 +1,45 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+type ClientConfigApi struct {
+	config *config.GlobalConfig
+}
+
+func NewClientConfigApi(config *config.GlobalConfig) ClientConfigApi {
+	return ClientConfigApi{config: config}
+}
+
+// GetClientConfig returns the limits the msg rpc enforces, so that clients reject an oversized message before
+// uploading it.
+func (cc *ClientConfigApi) GetClientConfig(c *gin.Context) {
+	contentTypes := make(map[int32]int, len(cc.config.MsgSize.ContentTypes))
+	for contentType, limit := range cc.config.MsgSize.ContentTypes {
+		contentTypes[contentType] = limit
+	}
+	apiresp.GinSuccess(c, &apistruct.ClientConfigResp{
+		MsgSizeLimits: &apistruct.MsgSizeLimits{
+			Default:      cc.config.MsgSize.Default,
+			ContentTypes: contentTypes,
+		},
+	})
+}
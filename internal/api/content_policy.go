@@ -0,0 +1,110 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+)
+
+type ContentPolicyApi struct {
+	database controller.ContentPolicyDatabase
+	config   *config.GlobalConfig
+}
+
+func NewContentPolicyApi(database controller.ContentPolicyDatabase, config *config.GlobalConfig) ContentPolicyApi {
+	return ContentPolicyApi{database: database, config: config}
+}
+
+func (p *ContentPolicyApi) SetContentPolicy(c *gin.Context) {
+	var req apistruct.SetContentPolicyReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, p.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := p.database.SetPolicy(c, req.SessionType, req.GroupID, req.ContentTypes); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (p *ContentPolicyApi) DeleteContentPolicy(c *gin.Context) {
+	var req apistruct.DeleteContentPolicyReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, p.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := p.database.DeletePolicy(c, req.SessionType, req.GroupID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+// GetContentPolicy is open to every user, clients use it to hide the attachments a conversation does not allow.
+func (p *ContentPolicyApi) GetContentPolicy(c *gin.Context) {
+	var req apistruct.GetContentPolicyReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	contentTypes, err := p.database.GetContentTypes(c, req.SessionType, req.GroupID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.GetContentPolicyResp{Restricted: contentTypes != nil, ContentTypes: contentTypes})
+}
+
+func (p *ContentPolicyApi) GetContentPolicies(c *gin.Context) {
+	var req apistruct.GetContentPoliciesReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, p.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, policies, err := p.database.PagePolicies(c, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetContentPoliciesResp{Total: total, Policies: make([]*apistruct.ContentPolicy, 0, len(policies))}
+	for _, policy := range policies {
+		resp.Policies = append(resp.Policies, &apistruct.ContentPolicy{
+			SessionType:  policy.SessionType,
+			GroupID:      policy.GroupID,
+			ContentTypes: policy.ContentTypes,
+			UpdateTime:   policy.UpdateTime.UnixMilli(),
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
@@ -17,7 +17,11 @@ package api
 import (
 	"github.com/OpenIMSDK/protocol/conversation"
 	"github.com/OpenIMSDK/tools/a2r"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
 	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/convsort"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 )
 
@@ -32,7 +36,25 @@ func (o *ConversationApi) GetAllConversations(c *gin.Context) {
 }
 
 func (o *ConversationApi) GetSortedConversationList(c *gin.Context) {
-	a2r.Call(conversation.ConversationClient.GetSortedConversationList, o.Client, c)
+	var req apistruct.GetSortedConversationListReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if !convsort.Valid(req.SortBy) {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("unknown sortBy "+req.SortBy))
+		return
+	}
+	resp, err := o.Client.GetSortedConversationList(convsort.WithSortBy(c, req.SortBy), &conversation.GetSortedConversationListReq{
+		UserID:          req.UserID,
+		ConversationIDs: req.ConversationIDs,
+		Pagination:      req.Pagination,
+	})
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, resp)
 }
 
 func (o *ConversationApi) GetConversation(c *gin.Context) {
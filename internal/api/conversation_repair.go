@@ -0,0 +1,88 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+)
+
+// mergeConversationDuplicatesMaxRecords is the most non canonical records one request looks at.
+const mergeConversationDuplicatesMaxRecords = 1000
+
+type ConversationRepairApi struct {
+	database controller.ConversationRepairDatabase
+	config   *config.GlobalConfig
+}
+
+func NewConversationRepairApi(database controller.ConversationRepairDatabase, config *config.GlobalConfig) ConversationRepairApi {
+	return ConversationRepairApi{database: database, config: config}
+}
+
+// MergeConversationDuplicates finds single chats stored under more than one conversation id and merges each into its
+// canonical id, a pair that fails is reported and the others are still merged.
+func (r *ConversationRepairApi) MergeConversationDuplicates(c *gin.Context) {
+	var req apistruct.MergeConversationDuplicatesReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, r.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if req.Limit > mergeConversationDuplicatesMaxRecords {
+		apiresp.GinError(c, errs.ErrArgs.Wrap(fmt.Sprintf("limit is at most %d", mergeConversationDuplicatesMaxRecords)))
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = mergeConversationDuplicatesMaxRecords
+	}
+	duplicates, err := r.database.FindDuplicates(c, req.UserIDs, req.Limit)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.MergeConversationDuplicatesResp{Duplicates: make([]*apistruct.ConversationDuplicate, 0, len(duplicates))}
+	for _, duplicate := range duplicates {
+		item := &apistruct.ConversationDuplicate{
+			ConversationID: duplicate.ConversationID,
+			UserIDs:        duplicate.UserIDs,
+			DuplicateIDs:   duplicate.DuplicateIDs,
+			MaxSeqs:        duplicate.MaxSeqs,
+			Records:        len(duplicate.Records),
+		}
+		resp.Duplicates = append(resp.Duplicates, item)
+		if req.DryRun {
+			continue
+		}
+		item.MovedMsgs, err = r.database.Merge(c, duplicate)
+		if err != nil {
+			log.ZError(c, "merge conversation duplicates failed", err, "conversationID", duplicate.ConversationID)
+			item.Err = err.Error()
+			continue
+		}
+		resp.Merged++
+	}
+	apiresp.GinSuccess(c, resp)
+}
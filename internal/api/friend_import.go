@@ -0,0 +1,187 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/user"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	cbapi "github.com/openimsdk/open-im-server/v3/pkg/callbackstruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/http"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+const (
+	importFriendsDefaultBatchSize = 1000
+	importFriendsMaxBatchSize     = 10000
+	importFriendsMaxNum           = 100000
+)
+
+// FriendImportApi imports friends in batches without the per-friend notifications of import_friend,
+// so migrations of tens of thousands of relations finish in one request.
+type FriendImportApi struct {
+	userRpc  *rpcclient.UserRpcClient
+	database controller.FriendDatabase
+	blackDB  relation.BlackModelInterface
+	config   *config.GlobalConfig
+}
+
+func NewFriendImportApi(userRpc *rpcclient.UserRpcClient, database controller.FriendDatabase, blackDB relation.BlackModelInterface, config *config.GlobalConfig) FriendImportApi {
+	return FriendImportApi{userRpc: userRpc, database: database, blackDB: blackDB, config: config}
+}
+
+func (f *FriendImportApi) ImportFriendsBulk(c *gin.Context) {
+	var req apistruct.ImportFriendsBulkReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, f.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if len(req.FriendUserIDs) > importFriendsMaxNum {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("too many friendUserIDs"))
+		return
+	}
+	friendUserIDs := utils.Distinct(req.FriendUserIDs)
+	if utils.Contain(req.OwnerUserID, friendUserIDs...) {
+		apiresp.GinError(c, errs.ErrCanNotAddYourself.Wrap())
+		return
+	}
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = importFriendsDefaultBatchSize
+	}
+	if batchSize > importFriendsMaxBatchSize {
+		batchSize = importFriendsMaxBatchSize
+	}
+	bidirectional := req.Bidirectional == nil || *req.Bidirectional
+	if _, err := f.userRpc.GetUserInfo(c, req.OwnerUserID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	ownerFriendIDs, err := f.database.FindFriendUserIDs(c, req.OwnerUserID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	ownerFriends := make(map[string]struct{}, len(ownerFriendIDs))
+	for _, friendUserID := range ownerFriendIDs {
+		ownerFriends[friendUserID] = struct{}{}
+	}
+	resp := &apistruct.ImportFriendsBulkResp{
+		Total:          len(friendUserIDs),
+		AlreadyFriends: []string{},
+		Blocked:        []string{},
+		NotExist:       []string{},
+	}
+	for start := 0; start < len(friendUserIDs); start += batchSize {
+		end := start + batchSize
+		if end > len(friendUserIDs) {
+			end = len(friendUserIDs)
+		}
+		importable, err := f.checkImportBatch(c, req.OwnerUserID, friendUserIDs[start:end], ownerFriends, resp)
+		if err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		if !req.DryRun && len(importable) > 0 {
+			if err := f.database.ImportFriends(c, req.OwnerUserID, importable, bidirectional, constant.BecomeFriendByImport); err != nil {
+				apiresp.GinError(c, err)
+				return
+			}
+		}
+		resp.Imported += len(importable)
+		if err := callbackImportFriendsProgress(c, f.config, &cbapi.CallbackImportFriendsProgressReq{
+			OwnerUserID: req.OwnerUserID,
+			DryRun:      req.DryRun,
+			Total:       resp.Total,
+			Processed:   end,
+			Imported:    resp.Imported,
+		}); err != nil {
+			log.ZWarn(c, "callbackImportFriendsProgress failed", err, "ownerUserID", req.OwnerUserID, "processed", end)
+		}
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+// checkImportBatch returns the users of the batch that can be imported and records the conflicts of the others in resp.
+func (f *FriendImportApi) checkImportBatch(ctx context.Context, ownerUserID string, batch []string, ownerFriends map[string]struct{}, resp *apistruct.ImportFriendsBulkResp) ([]string, error) {
+	users, err := f.userRpc.Client.GetDesignateUsers(ctx, &user.GetDesignateUsersReq{UserIDs: batch})
+	if err != nil {
+		return nil, err
+	}
+	exist := make(map[string]struct{}, len(users.UsersInfo))
+	for _, userInfo := range users.UsersInfo {
+		exist[userInfo.UserID] = struct{}{}
+	}
+	blackPairs := make([]*relation.BlackModel, 0, len(batch)*2)
+	for _, userID := range batch {
+		blackPairs = append(blackPairs,
+			&relation.BlackModel{OwnerUserID: ownerUserID, BlockUserID: userID},
+			&relation.BlackModel{OwnerUserID: userID, BlockUserID: ownerUserID},
+		)
+	}
+	blacks, err := f.blackDB.Find(ctx, blackPairs)
+	if err != nil {
+		return nil, err
+	}
+	blocked := make(map[string]struct{}, len(blacks))
+	for _, black := range blacks {
+		if black.OwnerUserID == ownerUserID {
+			blocked[black.BlockUserID] = struct{}{}
+		} else {
+			blocked[black.OwnerUserID] = struct{}{}
+		}
+	}
+	importable := make([]string, 0, len(batch))
+	for _, userID := range batch {
+		if _, ok := exist[userID]; !ok {
+			resp.NotExist = append(resp.NotExist, userID)
+			continue
+		}
+		if _, ok := ownerFriends[userID]; ok {
+			resp.AlreadyFriends = append(resp.AlreadyFriends, userID)
+			continue
+		}
+		if _, ok := blocked[userID]; ok {
+			resp.Blocked = append(resp.Blocked, userID)
+			continue
+		}
+		importable = append(importable, userID)
+	}
+	return importable, nil
+}
+
+func callbackImportFriendsProgress(ctx context.Context, globalConfig *config.GlobalConfig, cbReq *cbapi.CallbackImportFriendsProgressReq) error {
+	if !globalConfig.Callback.CallbackImportFriendsProgress.Enable {
+		return nil
+	}
+	cbReq.CallbackCommand = cbapi.CallbackImportFriendsProgressCommand
+	resp := &cbapi.CallbackImportFriendsProgressResp{}
+	return http.CallBackPostReturn(ctx, globalConfig.Callback.CallbackUrl, cbReq, resp, globalConfig.Callback.CallbackImportFriendsProgress)
+}
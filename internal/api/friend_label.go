@@ -0,0 +1,226 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/friend"
+	"github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/notificationcatalog"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type FriendLabelApi struct {
+	msgRpc    *rpcclient.Message
+	friendRpc *rpcclient.Friend
+	database  controller.FriendLabelDatabase
+	config    *config.GlobalConfig
+}
+
+func NewFriendLabelApi(msgRpc *rpcclient.Message, friendRpc *rpcclient.Friend, database controller.FriendLabelDatabase, config *config.GlobalConfig) FriendLabelApi {
+	return FriendLabelApi{msgRpc: msgRpc, friendRpc: friendRpc, database: database, config: config}
+}
+
+func (f *FriendLabelApi) CreateFriendLabel(c *gin.Context) {
+	var req apistruct.CreateFriendLabelReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.OwnerUserID, f.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	label, err := f.database.CreateLabel(c, req.OwnerUserID, req.Name)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	f.notifyLabelChanged(c, req.OwnerUserID, label.LabelID, false)
+	apiresp.GinSuccess(c, &apistruct.CreateFriendLabelResp{LabelID: label.LabelID})
+}
+
+func (f *FriendLabelApi) UpdateFriendLabel(c *gin.Context) {
+	var req apistruct.UpdateFriendLabelReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.OwnerUserID, f.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := f.database.RenameLabel(c, req.OwnerUserID, req.LabelID, req.Name); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	f.notifyLabelChanged(c, req.OwnerUserID, req.LabelID, false)
+	apiresp.GinSuccess(c, nil)
+}
+
+func (f *FriendLabelApi) DeleteFriendLabel(c *gin.Context) {
+	var req apistruct.DeleteFriendLabelReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.OwnerUserID, f.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := f.database.DeleteLabel(c, req.OwnerUserID, req.LabelID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	f.notifyLabelChanged(c, req.OwnerUserID, req.LabelID, true)
+	apiresp.GinSuccess(c, nil)
+}
+
+func (f *FriendLabelApi) SetFriendLabelFriends(c *gin.Context) {
+	var req apistruct.SetFriendLabelFriendsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.OwnerUserID, f.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if len(req.FriendUserIDs) == 0 {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("friendUserIDs is empty"))
+		return
+	}
+	var err error
+	if req.Remove {
+		err = f.database.RemoveFriends(c, req.OwnerUserID, req.LabelID, req.FriendUserIDs)
+	} else {
+		// Only friends can be labeled, GetDesignatedFriends fails when one of them is not.
+		_, err = f.friendRpc.Client.GetDesignatedFriends(c, &friend.GetDesignatedFriendsReq{
+			OwnerUserID:   req.OwnerUserID,
+			FriendUserIDs: utils.Distinct(req.FriendUserIDs),
+		})
+		if err == nil {
+			err = f.database.AddFriends(c, req.OwnerUserID, req.LabelID, req.FriendUserIDs)
+		}
+	}
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	f.notifyLabelChanged(c, req.OwnerUserID, req.LabelID, false)
+	apiresp.GinSuccess(c, nil)
+}
+
+func (f *FriendLabelApi) GetFriendLabels(c *gin.Context) {
+	var req apistruct.GetFriendLabelsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.OwnerUserID, f.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	labels, err := f.database.GetLabels(c, req.OwnerUserID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetFriendLabelsResp{Labels: utils.Slice(labels, func(label *relation.FriendLabelModel) *apistruct.FriendLabel {
+		return &apistruct.FriendLabel{
+			LabelID:       label.LabelID,
+			Name:          label.Name,
+			FriendUserIDs: label.FriendUserIDs,
+			CreateTime:    label.CreateTime.UnixMilli(),
+			UpdateTime:    label.UpdateTime.UnixMilli(),
+		}
+	})}
+	apiresp.GinSuccess(c, resp)
+}
+
+func (f *FriendLabelApi) GetFriendsByLabel(c *gin.Context) {
+	var req apistruct.GetFriendsByLabelReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.OwnerUserID, f.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	label, err := f.database.TakeLabel(c, req.OwnerUserID, req.LabelID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetFriendsByLabelResp{Total: int64(len(label.FriendUserIDs)), Friends: []*sdkws.FriendInfo{}}
+	friendUserIDs := utils.Paginate(label.FriendUserIDs, int(req.Pagination.GetPageNumber()), int(req.Pagination.GetShowNumber()))
+	if len(friendUserIDs) == 0 {
+		apiresp.GinSuccess(c, resp)
+		return
+	}
+	friends, err := f.friendRpc.Client.GetDesignatedFriends(c, &friend.GetDesignatedFriendsReq{
+		OwnerUserID:   req.OwnerUserID,
+		FriendUserIDs: friendUserIDs,
+	})
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp.Friends = friends.FriendsInfo
+	apiresp.GinSuccess(c, resp)
+}
+
+// notifyLabelChanged tells the other devices of ownerUserID to reload the label, a failure only delays them until
+// they reload the labels themselves.
+func (f *FriendLabelApi) notifyLabelChanged(ctx context.Context, ownerUserID string, labelID string, deleted bool) {
+	tips := &apistruct.FriendLabelChangedTips{OwnerUserID: ownerUserID, LabelID: labelID, Deleted: deleted}
+	if err := notificationcatalog.Validate(msgprocessor.FriendLabelChangedNotification, tips); err != nil {
+		log.ZError(ctx, "invalid friend label notification", err)
+		return
+	}
+	msgData := &sdkws.MsgData{
+		SendID:      ownerUserID,
+		RecvID:      ownerUserID,
+		Content:     []byte(utils.StructToJsonString(&sdkws.NotificationElem{Detail: utils.StructToJsonString(tips)})),
+		MsgFrom:     constant.SysMsgType,
+		ContentType: msgprocessor.FriendLabelChangedNotification,
+		SessionType: constant.SingleChatType,
+		CreateTime:  utils.GetCurrentTimestampByMill(),
+		ClientMsgID: utils.GetMsgID(ownerUserID),
+		Options: config.GetOptionsByNotification(config.NotificationConf{
+			IsSendMsg:        false,
+			ReliabilityLevel: 1,
+			UnreadCount:      false,
+		}),
+	}
+	if _, err := f.msgRpc.Client.SendMsg(ctx, &msg.SendMsgReq{MsgData: msgData}); err != nil {
+		log.ZWarn(ctx, "send friend label notification failed", err, "ownerUserID", ownerUserID, "labelID", labelID)
+	}
+}
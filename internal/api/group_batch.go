@@ -0,0 +1,196 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+)
+
+// GroupBatchApi queues batches of group operations, the cron task carries them out.
+type GroupBatchApi struct {
+	database controller.GroupBatchJobDatabase
+	config   *config.GlobalConfig
+}
+
+func NewGroupBatchApi(database controller.GroupBatchJobDatabase, config *config.GlobalConfig) GroupBatchApi {
+	return GroupBatchApi{database: database, config: config}
+}
+
+func (g *GroupBatchApi) check(c *gin.Context) error {
+	if err := authverify.CheckAdmin(c, g.config); err != nil {
+		return err
+	}
+	if !g.config.GroupBatch.Enable {
+		return errs.ErrNoPermission.Wrap("group batch jobs are disabled")
+	}
+	return nil
+}
+
+// createJob queues a job of kind for the app of the request.
+func (g *GroupBatchApi) createJob(c *gin.Context, kind string, items []*relation.GroupBatchItemModel, settings *apistruct.GroupBatchSettings) {
+	appID := tenant.GetAppID(c)
+	if appID == "" {
+		appID = c.GetHeader(tenant.AppIDKey)
+	}
+	job := &relation.GroupBatchJobModel{
+		Kind:           kind,
+		AppID:          appID,
+		OperatorUserID: mcontext.GetOpUserID(c),
+		Items:          items,
+	}
+	if settings != nil {
+		job.Settings = &relation.GroupBatchSettingsModel{
+			Notification:      settings.Notification,
+			Introduction:      settings.Introduction,
+			FaceURL:           settings.FaceURL,
+			Ex:                settings.Ex,
+			NeedVerification:  settings.NeedVerification,
+			LookMemberInfo:    settings.LookMemberInfo,
+			ApplyMemberFriend: settings.ApplyMemberFriend,
+		}
+	}
+	jobID, err := g.database.CreateJob(c, job, g.config.GroupBatch.MaxItems)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.GroupBatchJobResp{JobID: jobID})
+}
+
+func (g *GroupBatchApi) BatchCreateGroups(c *gin.Context) {
+	var req apistruct.BatchCreateGroupsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := g.check(c); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	items := utils.Slice(req.Groups, func(spec *apistruct.BatchGroupSpec) *relation.GroupBatchItemModel {
+		return &relation.GroupBatchItemModel{
+			GroupID:       spec.GroupID,
+			GroupName:     spec.GroupName,
+			OwnerUserID:   spec.OwnerUserID,
+			AdminUserIDs:  spec.AdminUserIDs,
+			MemberUserIDs: spec.MemberUserIDs,
+		}
+	})
+	g.createJob(c, relation.GroupBatchCreateGroups, items, req.Settings)
+}
+
+func (g *GroupBatchApi) batchMembers(c *gin.Context, kind string) {
+	var req apistruct.BatchGroupMembersReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := g.check(c); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	items := utils.Slice(req.Groups, func(members *apistruct.BatchGroupMembers) *relation.GroupBatchItemModel {
+		return &relation.GroupBatchItemModel{GroupID: members.GroupID, MemberUserIDs: members.UserIDs}
+	})
+	g.createJob(c, kind, items, nil)
+}
+
+func (g *GroupBatchApi) BatchAddGroupMembers(c *gin.Context) {
+	g.batchMembers(c, relation.GroupBatchAddMembers)
+}
+
+func (g *GroupBatchApi) BatchRemoveGroupMembers(c *gin.Context) {
+	g.batchMembers(c, relation.GroupBatchRemoveMembers)
+}
+
+func (g *GroupBatchApi) BatchApplyGroupSettings(c *gin.Context) {
+	var req apistruct.BatchApplyGroupSettingsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := g.check(c); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	items := utils.Slice(req.GroupIDs, func(groupID string) *relation.GroupBatchItemModel {
+		return &relation.GroupBatchItemModel{GroupID: groupID}
+	})
+	g.createJob(c, relation.GroupBatchApplySettings, items, req.Settings)
+}
+
+func (g *GroupBatchApi) GetGroupBatchJob(c *gin.Context) {
+	var req apistruct.GetGroupBatchJobReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, g.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	job, err := g.database.TakeJob(c, req.JobID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := convertGroupBatchJob(job)
+	resp.Items = utils.Slice(job.Items, func(item *relation.GroupBatchItemModel) *apistruct.GroupBatchItem {
+		return &apistruct.GroupBatchItem{GroupID: item.GroupID, GroupName: item.GroupName, Err: item.Err}
+	})
+	apiresp.GinSuccess(c, resp)
+}
+
+func (g *GroupBatchApi) GetGroupBatchJobs(c *gin.Context) {
+	var req apistruct.GetGroupBatchJobsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, g.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, jobs, err := g.database.GetJobs(c, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.GetGroupBatchJobsResp{Total: total, Jobs: utils.Slice(jobs, convertGroupBatchJob)})
+}
+
+func convertGroupBatchJob(job *relation.GroupBatchJobModel) *apistruct.GroupBatchJob {
+	return &apistruct.GroupBatchJob{
+		JobID:          job.JobID,
+		Kind:           job.Kind,
+		OperatorUserID: job.OperatorUserID,
+		Status:         job.Status,
+		Total:          job.Total,
+		Succeeded:      job.Succeeded,
+		Failed:         job.Failed,
+		CreateTime:     unixMilli(job.CreateTime),
+		FinishTime:     unixMilli(job.FinishTime),
+	}
+}
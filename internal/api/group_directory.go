@@ -0,0 +1,153 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type GroupDirectoryApi struct {
+	groupRpc *rpcclient.GroupRpcClient
+	database controller.GroupDirectoryDatabase
+	config   *config.GlobalConfig
+}
+
+func NewGroupDirectoryApi(groupRpc *rpcclient.GroupRpcClient, database controller.GroupDirectoryDatabase, config *config.GlobalConfig) GroupDirectoryApi {
+	return GroupDirectoryApi{groupRpc: groupRpc, database: database, config: config}
+}
+
+func (g *GroupDirectoryApi) SetGroupDiscoverable(c *gin.Context) {
+	var req apistruct.SetGroupDiscoverableReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if !authverify.IsAppManagerUid(c, g.config) {
+		member, err := g.groupRpc.GetGroupMemberInfo(c, req.GroupID, mcontext.GetOpUserID(c))
+		if err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		if member.RoleLevel != constant.GroupOwner && member.RoleLevel != constant.GroupAdmin {
+			apiresp.GinError(c, errs.ErrNoPermission.Wrap("only the group owner or admins can list the group"))
+			return
+		}
+	}
+	if !req.Discoverable {
+		if err := g.database.Unlist(c, req.GroupID); err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		apiresp.GinSuccess(c, nil)
+		return
+	}
+	info, err := g.groupRpc.GetGroupInfo(c, req.GroupID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if info.Status == constant.GroupStatusDismissed {
+		apiresp.GinError(c, errs.ErrDismissedAlready.Wrap())
+		return
+	}
+	entry := &relation.GroupDirectoryModel{
+		GroupID:      info.GroupID,
+		GroupName:    info.GroupName,
+		Tags:         req.Tags,
+		Introduction: info.Introduction,
+		FaceURL:      info.FaceURL,
+	}
+	if err := g.database.List(c, entry); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (g *GroupDirectoryApi) GetGroupDiscoverable(c *gin.Context) {
+	var req apistruct.GetGroupDiscoverableReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	entry, err := g.database.TakeEntry(c, req.GroupID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetGroupDiscoverableResp{Tags: []string{}}
+	if entry != nil {
+		resp.Discoverable = true
+		resp.Tags = entry.Tags
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+func (g *GroupDirectoryApi) SearchPublicGroups(c *gin.Context) {
+	var req apistruct.SearchPublicGroupsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if req.Pagination.ShowNumber <= 0 {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("pagination is required"))
+		return
+	}
+	total, entries, err := g.database.Search(c, req.Keyword, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.SearchPublicGroupsResp{Total: total, Groups: []*apistruct.PublicGroup{}}
+	if len(entries) == 0 {
+		apiresp.GinSuccess(c, resp)
+		return
+	}
+	infos, err := g.groupRpc.GetGroupInfos(c, utils.Slice(entries, func(e *relation.GroupDirectoryModel) string { return e.GroupID }), false)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	infoMap := utils.SliceToMap(infos, func(e *sdkws.GroupInfo) string { return e.GroupID })
+	for _, entry := range entries {
+		info, ok := infoMap[entry.GroupID]
+		// Entries of groups dismissed while the unlisting failed are left out.
+		if !ok || info.Status == constant.GroupStatusDismissed {
+			continue
+		}
+		resp.Groups = append(resp.Groups, &apistruct.PublicGroup{
+			GroupID:          entry.GroupID,
+			GroupName:        entry.GroupName,
+			FaceURL:          entry.FaceURL,
+			Introduction:     entry.Introduction,
+			Tags:             entry.Tags,
+			MemberCount:      info.MemberCount,
+			NeedVerification: info.NeedVerification,
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
@@ -0,0 +1,86 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type GroupHistoryApi struct {
+	groupRpc *rpcclient.GroupRpcClient
+	database controller.GroupHistoryDatabase
+	config   *config.GlobalConfig
+}
+
+func NewGroupHistoryApi(groupRpc *rpcclient.GroupRpcClient, database controller.GroupHistoryDatabase, config *config.GlobalConfig) GroupHistoryApi {
+	return GroupHistoryApi{groupRpc: groupRpc, database: database, config: config}
+}
+
+func (g *GroupHistoryApi) SetGroupHistoryVisibility(c *gin.Context) {
+	var req apistruct.SetGroupHistoryVisibilityReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if !authverify.IsAppManagerUid(c, g.config) {
+		member, err := g.groupRpc.GetGroupMemberInfo(c, req.GroupID, mcontext.GetOpUserID(c))
+		if err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		if member.RoleLevel != constant.GroupOwner && member.RoleLevel != constant.GroupAdmin {
+			apiresp.GinError(c, errs.ErrNoPermission.Wrap("only the group owner or admins can set history visibility"))
+			return
+		}
+	}
+	if err := g.database.SetVisibility(c, req.GroupID, req.Visibility, req.Days); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (g *GroupHistoryApi) GetGroupHistoryVisibility(c *gin.Context) {
+	var req apistruct.GetGroupHistoryVisibilityReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if !authverify.IsAppManagerUid(c, g.config) {
+		if _, err := g.groupRpc.GetGroupMemberInfo(c, req.GroupID, mcontext.GetOpUserID(c)); err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+	}
+	visibility, err := g.database.GetVisibility(c, req.GroupID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.GetGroupHistoryVisibilityResp{
+		GroupID:    req.GroupID,
+		Visibility: visibility.Visibility,
+		Days:       visibility.Days,
+	})
+}
@@ -0,0 +1,192 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/group"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type GroupInviteLinkApi struct {
+	groupRpc *rpcclient.GroupRpcClient
+	database controller.GroupInviteLinkDatabase
+	roleDB   controller.GroupRoleDatabase
+	config   *config.GlobalConfig
+}
+
+func NewGroupInviteLinkApi(groupRpc *rpcclient.GroupRpcClient, database controller.GroupInviteLinkDatabase, roleDB controller.GroupRoleDatabase, config *config.GlobalConfig) GroupInviteLinkApi {
+	return GroupInviteLinkApi{groupRpc: groupRpc, database: database, roleDB: roleDB, config: config}
+}
+
+// checkInvite lets app managers and members whose group role allows inviting through.
+func (g *GroupInviteLinkApi) checkInvite(c *gin.Context, groupID string) error {
+	if authverify.IsAppManagerUid(c, g.config) {
+		return nil
+	}
+	member, err := g.groupRpc.GetGroupMemberInfo(c, groupID, mcontext.GetOpUserID(c))
+	if err != nil {
+		return err
+	}
+	permissions, err := g.roleDB.GetMemberPermissions(c, groupID, member.UserID, member.RoleLevel)
+	if err != nil {
+		return err
+	}
+	if permissions&relation.GroupPermissionInvite == 0 {
+		return errs.ErrNoPermission.Wrap("the group role of the member does not allow inviting")
+	}
+	return nil
+}
+
+func (g *GroupInviteLinkApi) CreateGroupInviteLink(c *gin.Context) {
+	var req apistruct.CreateGroupInviteLinkReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := g.checkInvite(c, req.GroupID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	info, err := g.groupRpc.GetGroupInfo(c, req.GroupID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if info.Status == constant.GroupStatusDismissed {
+		apiresp.GinError(c, errs.ErrDismissedAlready.Wrap())
+		return
+	}
+	var expireTime time.Time
+	if req.ExpireTime > 0 {
+		expireTime = time.UnixMilli(req.ExpireTime)
+	}
+	link, err := g.database.CreateLink(c, req.GroupID, mcontext.GetOpUserID(c), expireTime, req.MaxUses, req.NeedApproval)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.CreateGroupInviteLinkResp{Link: groupInviteLinkDB2Api(link)})
+}
+
+func (g *GroupInviteLinkApi) RevokeGroupInviteLink(c *gin.Context) {
+	var req apistruct.RevokeGroupInviteLinkReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	link, err := g.database.TakeLink(c, req.Token)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if link.GroupID != req.GroupID {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("invite link is invalid or revoked"))
+		return
+	}
+	// The creator may revoke its own link, otherwise the group owner, admins and app managers.
+	if link.CreatorUserID != mcontext.GetOpUserID(c) && !authverify.IsAppManagerUid(c, g.config) {
+		member, err := g.groupRpc.GetGroupMemberInfo(c, req.GroupID, mcontext.GetOpUserID(c))
+		if err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		if member.RoleLevel != constant.GroupOwner && member.RoleLevel != constant.GroupAdmin {
+			apiresp.GinError(c, errs.ErrNoPermission.Wrap("only the creator, group owner or admins can revoke the link"))
+			return
+		}
+	}
+	if err := g.database.RevokeLink(c, req.GroupID, req.Token); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (g *GroupInviteLinkApi) GetGroupInviteLinks(c *gin.Context) {
+	var req apistruct.GetGroupInviteLinksReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := g.checkInvite(c, req.GroupID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	links, err := g.database.GetLinks(c, req.GroupID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetGroupInviteLinksResp{Links: make([]*apistruct.GroupInviteLink, 0, len(links))}
+	for _, link := range links {
+		resp.Links = append(resp.Links, groupInviteLinkDB2Api(link))
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+// JoinGroupByLink joins the user through the group rpc, which checks the link and counts the use.
+func (g *GroupInviteLinkApi) JoinGroupByLink(c *gin.Context) {
+	var req apistruct.JoinGroupByLinkReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	link, err := g.database.TakeLink(c, req.Token)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	_, err = g.groupRpc.Client.JoinGroup(c, &group.JoinGroupReq{
+		GroupID:       link.GroupID,
+		ReqMessage:    req.ReqMessage,
+		JoinSource:    relation.JoinByInviteLink,
+		InviterUserID: mcontext.GetOpUserID(c),
+		Ex:            req.Token,
+	})
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.JoinGroupByLinkResp{GroupID: link.GroupID, NeedApproval: link.NeedApproval})
+}
+
+func groupInviteLinkDB2Api(link *relation.GroupInviteLinkModel) *apistruct.GroupInviteLink {
+	var expireTime int64
+	if !link.ExpireTime.IsZero() {
+		expireTime = link.ExpireTime.UnixMilli()
+	}
+	return &apistruct.GroupInviteLink{
+		Token:         link.Token,
+		GroupID:       link.GroupID,
+		CreatorUserID: link.CreatorUserID,
+		ExpireTime:    expireTime,
+		MaxUses:       link.MaxUses,
+		Uses:          link.Uses,
+		NeedApproval:  link.NeedApproval,
+		CreateTime:    link.CreateTime.UnixMilli(),
+	}
+}
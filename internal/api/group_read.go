@@ -0,0 +1,81 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type GroupReadApi struct {
+	groupRpc *rpcclient.GroupRpcClient
+	cache    cache.MsgModel
+	config   *config.GlobalConfig
+}
+
+func NewGroupReadApi(groupRpc *rpcclient.GroupRpcClient, cache cache.MsgModel, config *config.GlobalConfig) GroupReadApi {
+	return GroupReadApi{groupRpc: groupRpc, cache: cache, config: config}
+}
+
+// GetGroupReadMembers answers from the per group has read seq index in redis, so it costs one sorted set range
+// and one member list lookup regardless of how many messages the group has.
+func (g *GroupReadApi) GetGroupReadMembers(c *gin.Context) {
+	var req apistruct.GetGroupReadMembersReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if !authverify.IsAppManagerUid(c, g.config) {
+		if _, err := g.groupRpc.GetGroupMemberInfo(c, req.GroupID, mcontext.GetOpUserID(c)); err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+	}
+	conversationID := msgprocessor.GetConversationIDBySessionType(constant.SuperGroupChatType, req.GroupID)
+	readUserIDs, err := g.cache.GetGroupReadMembers(c, conversationID, req.Seq)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	memberUserIDs, err := g.groupRpc.GetGroupMemberIDs(c, req.GroupID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	read := make(map[string]struct{}, len(readUserIDs))
+	for _, userID := range readUserIDs {
+		read[userID] = struct{}{}
+	}
+	resp := apistruct.GetGroupReadMembersResp{ReadUserIDs: []string{}, UnreadUserIDs: []string{}}
+	// Members who left the group are still in the index, only current members are reported.
+	for _, userID := range utils.Distinct(memberUserIDs) {
+		if _, ok := read[userID]; ok {
+			resp.ReadUserIDs = append(resp.ReadUserIDs, userID)
+		} else {
+			resp.UnreadUserIDs = append(resp.UnreadUserIDs, userID)
+		}
+	}
+	apiresp.GinSuccess(c, &resp)
+}
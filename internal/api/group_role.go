@@ -0,0 +1,155 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type GroupRoleApi struct {
+	groupRpc *rpcclient.GroupRpcClient
+	database controller.GroupRoleDatabase
+	config   *config.GlobalConfig
+}
+
+func NewGroupRoleApi(groupRpc *rpcclient.GroupRpcClient, database controller.GroupRoleDatabase, config *config.GlobalConfig) GroupRoleApi {
+	return GroupRoleApi{groupRpc: groupRpc, database: database, config: config}
+}
+
+// checkGroupAdmin lets app managers and the owner and admins of the group through.
+func (g *GroupRoleApi) checkGroupAdmin(c *gin.Context, groupID string) error {
+	if authverify.IsAppManagerUid(c, g.config) {
+		return nil
+	}
+	member, err := g.groupRpc.GetGroupMemberInfo(c, groupID, mcontext.GetOpUserID(c))
+	if err != nil {
+		return err
+	}
+	if member.RoleLevel != constant.GroupOwner && member.RoleLevel != constant.GroupAdmin {
+		return errs.ErrNoPermission.Wrap("only the group owner or admins can manage group roles")
+	}
+	return nil
+}
+
+func (g *GroupRoleApi) SetGroupRole(c *gin.Context) {
+	var req apistruct.SetGroupRoleReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := g.checkGroupAdmin(c, req.GroupID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := g.database.SetRole(c, req.GroupID, req.RoleID, req.Name, req.Permissions); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (g *GroupRoleApi) DeleteGroupRole(c *gin.Context) {
+	var req apistruct.DeleteGroupRoleReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := g.checkGroupAdmin(c, req.GroupID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := g.database.DeleteRole(c, req.GroupID, req.RoleID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (g *GroupRoleApi) GetGroupRoles(c *gin.Context) {
+	var req apistruct.GetGroupRolesReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if !authverify.IsAppManagerUid(c, g.config) {
+		if _, err := g.groupRpc.GetGroupMemberInfo(c, req.GroupID, mcontext.GetOpUserID(c)); err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+	}
+	roles, err := g.database.GetRoles(c, req.GroupID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	members, err := g.database.GetMemberRoles(c, req.GroupID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetGroupRolesResp{
+		Roles:   make([]*apistruct.GroupRole, 0, len(roles)),
+		Members: make([]*apistruct.GroupMemberRole, 0, len(members)),
+	}
+	for _, role := range roles {
+		resp.Roles = append(resp.Roles, &apistruct.GroupRole{
+			RoleID:      role.RoleID,
+			Name:        role.Name,
+			Permissions: role.Permissions,
+			CreateTime:  role.CreateTime.UnixMilli(),
+		})
+	}
+	for _, member := range members {
+		resp.Members = append(resp.Members, &apistruct.GroupMemberRole{UserID: member.UserID, RoleID: member.RoleID})
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+func (g *GroupRoleApi) SetGroupMemberRole(c *gin.Context) {
+	var req apistruct.SetGroupMemberRoleReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if len(req.UserIDs) == 0 {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("userIDs is empty"))
+		return
+	}
+	if err := g.checkGroupAdmin(c, req.GroupID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if req.RoleID != "" {
+		// Only members can hold a role.
+		if _, err := g.groupRpc.GetGroupMemberInfos(c, req.GroupID, req.UserIDs, true); err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+	}
+	if err := g.database.AssignRole(c, req.GroupID, req.RoleID, req.UserIDs); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
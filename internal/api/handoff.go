@@ -0,0 +1,170 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/handoff"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type HandoffApi struct {
+	conversationRpc *rpcclient.ConversationRpcClient
+	database        controller.HandoffDatabase
+	config          *config.GlobalConfig
+}
+
+func NewHandoffApi(conversationRpc *rpcclient.ConversationRpcClient, database controller.HandoffDatabase, config *config.GlobalConfig) HandoffApi {
+	return HandoffApi{conversationRpc: conversationRpc, database: database, config: config}
+}
+
+func (h *HandoffApi) checkEnable(c *gin.Context) bool {
+	if !h.config.Handoff.Enable {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("handoff links are not enabled"))
+		return false
+	}
+	return true
+}
+
+func (h *HandoffApi) audit(c *gin.Context) *controller.HandoffAudit {
+	return &controller.HandoffAudit{Platform: c.GetString(constant.OpUserPlatform), IP: c.ClientIP()}
+}
+
+func (h *HandoffApi) CreateHandoffLink(c *gin.Context) {
+	var req apistruct.CreateHandoffLinkReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if !h.checkEnable(c) {
+		return
+	}
+	userID := mcontext.GetOpUserID(c)
+	// Only a conversation of the user can be handed off to the user.
+	if _, err := h.conversationRpc.GetConversation(c, userID, req.ConversationID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	ttl := h.config.Handoff.TTL
+	if ttl <= 0 {
+		ttl = 300
+	}
+	expire := time.Now().Add(time.Duration(ttl) * time.Second)
+	grant := &handoff.Grant{
+		ID:             handoff.NewID(),
+		UserID:         userID,
+		ConversationID: req.ConversationID,
+		Seq:            req.Seq,
+		ClientMsgID:    req.ClientMsgID,
+		Expire:         expire.Unix(),
+	}
+	audit := h.audit(c)
+	audit.TokenID, audit.ConversationID = grant.ID, grant.ConversationID
+	if err := h.database.Issue(c, grant, audit); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.CreateHandoffLinkResp{
+		Token:      handoff.Token(h.config.Handoff.Secret, grant),
+		ExpireTime: expire.UnixMilli(),
+	})
+}
+
+func (h *HandoffApi) RedeemHandoffLink(c *gin.Context) {
+	var req apistruct.RedeemHandoffLinkReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if !h.checkEnable(c) {
+		return
+	}
+	userID := mcontext.GetOpUserID(c)
+	audit := h.audit(c)
+	audit.Action = controller.HandoffActionRedeem
+	grant, err := handoff.Parse(h.config.Handoff.Secret, req.Token, time.Now())
+	if err != nil {
+		audit.Result = controller.HandoffResultInvalid
+		if err == handoff.ErrTokenExpired {
+			audit.Result = controller.HandoffResultExpired
+		}
+		h.database.Audit(c, userID, audit)
+		apiresp.GinError(c, errs.ErrArgs.Wrap(err.Error()))
+		return
+	}
+	audit.TokenID, audit.ConversationID = grant.ID, grant.ConversationID
+	if grant.UserID != userID {
+		// The attempt is audited for the owner of the link as well, a leaked link shows up in their audit.
+		audit.Result = controller.HandoffResultWrongUser
+		h.database.Audit(c, userID, audit)
+		h.database.Audit(c, grant.UserID, audit)
+		apiresp.GinError(c, errs.ErrNoPermission.Wrap("the handoff link was issued for another user"))
+		return
+	}
+	ok, err := h.database.Redeem(c, userID, grant, audit)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if !ok {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("the handoff link was already used"))
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.RedeemHandoffLinkResp{
+		ConversationID: grant.ConversationID,
+		Seq:            grant.Seq,
+		ClientMsgID:    grant.ClientMsgID,
+	})
+}
+
+func (h *HandoffApi) GetHandoffAudit(c *gin.Context) {
+	var req apistruct.GetHandoffAuditReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, h.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	audits, err := h.database.GetAudit(c, req.UserID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetHandoffAuditResp{Records: make([]*apistruct.HandoffAuditRecord, 0, len(audits))}
+	for _, audit := range audits {
+		resp.Records = append(resp.Records, &apistruct.HandoffAuditRecord{
+			TokenID:        audit.TokenID,
+			Action:         audit.Action,
+			Result:         audit.Result,
+			ConversationID: audit.ConversationID,
+			Platform:       audit.Platform,
+			IP:             audit.IP,
+			Time:           audit.Time,
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
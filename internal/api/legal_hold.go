@@ -0,0 +1,126 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+)
+
+type LegalHoldApi struct {
+	database controller.LegalHoldDatabase
+	config   *config.GlobalConfig
+}
+
+func NewLegalHoldApi(database controller.LegalHoldDatabase, config *config.GlobalConfig) LegalHoldApi {
+	return LegalHoldApi{database: database, config: config}
+}
+
+func (l *LegalHoldApi) PlaceLegalHold(c *gin.Context) {
+	var req apistruct.PlaceLegalHoldReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, l.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := l.database.Place(c, req.TargetType, req.TargetID, req.Reason, mcontext.GetOpUserID(c)); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (l *LegalHoldApi) ReleaseLegalHold(c *gin.Context) {
+	var req apistruct.ReleaseLegalHoldReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, l.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := l.database.Release(c, req.TargetType, req.TargetID, mcontext.GetOpUserID(c)); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (l *LegalHoldApi) GetLegalHolds(c *gin.Context) {
+	var req apistruct.GetLegalHoldsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, l.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, holds, err := l.database.PageHolds(c, req.TargetType, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetLegalHoldsResp{Total: total, Holds: make([]*apistruct.LegalHold, 0, len(holds))}
+	for _, hold := range holds {
+		resp.Holds = append(resp.Holds, &apistruct.LegalHold{
+			TargetType:     hold.TargetType,
+			TargetID:       hold.TargetID,
+			Reason:         hold.Reason,
+			OperatorUserID: hold.OperatorUserID,
+			CreateTime:     hold.CreateTime.UnixMilli(),
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+func (l *LegalHoldApi) GetLegalHoldLogs(c *gin.Context) {
+	var req apistruct.GetLegalHoldLogsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, l.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, logs, err := l.database.PageLogs(c, req.TargetType, req.TargetID, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetLegalHoldLogsResp{Total: total, Logs: make([]*apistruct.LegalHoldLog, 0, len(logs))}
+	for _, log := range logs {
+		resp.Logs = append(resp.Logs, &apistruct.LegalHoldLog{
+			TargetType:     log.TargetType,
+			TargetID:       log.TargetID,
+			Action:         log.Action,
+			Reason:         log.Reason,
+			OperatorUserID: log.OperatorUserID,
+			CreateTime:     log.CreateTime.UnixMilli(),
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
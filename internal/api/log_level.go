@@ -0,0 +1,95 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/discoveryregistry"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/loglevel"
+)
+
+type LogLevelApi struct {
+	discov discoveryregistry.SvcDiscoveryRegistry
+	config *config.GlobalConfig
+}
+
+func NewLogLevelApi(discov discoveryregistry.SvcDiscoveryRegistry, config *config.GlobalConfig) LogLevelApi {
+	return LogLevelApi{discov: discov, config: config}
+}
+
+// SetLogLevel publishes a log level for each of the services through the discovery registry. The logger of a
+// running service can not change its level, instances use the level once they are restarted.
+func (l *LogLevelApi) SetLogLevel(c *gin.Context) {
+	var req apistruct.SetLogLevelReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, l.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := loglevel.Check(req.Level); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	serviceNames := utils.Distinct(req.ServiceNames)
+	if len(serviceNames) == 0 {
+		serviceNames = loglevel.ServiceNames
+	}
+	for _, name := range serviceNames {
+		if !utils.IsContain(name, loglevel.ServiceNames) {
+			apiresp.GinError(c, errs.ErrArgs.Wrap("unknown service "+name))
+			return
+		}
+	}
+	setting := &loglevel.Setting{Level: req.Level, UpdateTime: time.Now().UnixMilli()}
+	for _, name := range serviceNames {
+		if err := loglevel.Publish(l.discov, name, setting); err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+// GetLogLevel returns the level of the api instance serving the request and the levels published for the services.
+func (l *LogLevelApi) GetLogLevel(c *gin.Context) {
+	if err := authverify.CheckAdmin(c, l.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetLogLevelResp{Level: loglevel.Current(), Published: make(map[string]int)}
+	for _, name := range loglevel.ServiceNames {
+		setting, err := loglevel.Published(l.discov, name)
+		if err != nil {
+			log.ZDebug(c, "log level not published", "service", name, "err", err)
+			continue
+		}
+		if setting != nil {
+			resp.Published[name] = setting.Level
+		}
+	}
+	apiresp.GinSuccess(c, resp)
+}
@@ -0,0 +1,82 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/loginpolicy"
+)
+
+type LoginPolicyApi struct {
+	database controller.AuthDatabase
+	config   *config.GlobalConfig
+}
+
+func NewLoginPolicyApi(database controller.AuthDatabase, config *config.GlobalConfig) LoginPolicyApi {
+	return LoginPolicyApi{database: database, config: config}
+}
+
+func (l *LoginPolicyApi) GetLoginPolicyMatrix(c *gin.Context) {
+	if err := authverify.CheckAdmin(c, l.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	matrix, err := l.database.GetLoginPolicyMatrix(c)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetLoginPolicyMatrixResp{Enable: matrix.Enable, Groups: make([]*apistruct.LoginPolicyGroup, 0, len(matrix.Groups))}
+	for _, group := range matrix.Groups {
+		resp.Groups = append(resp.Groups, &apistruct.LoginPolicyGroup{
+			Name:        group.Name,
+			PlatformIDs: group.PlatformIDs,
+			MaxOnline:   group.MaxOnline,
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+// SetLoginPolicyMatrix replaces the login policy matrix at runtime, overriding loginPolicyMatrix in the config.
+func (l *LoginPolicyApi) SetLoginPolicyMatrix(c *gin.Context) {
+	var req apistruct.SetLoginPolicyMatrixReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, l.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	matrix := &loginpolicy.Matrix{Enable: req.Enable, Groups: make([]config.LoginPolicyGroup, 0, len(req.Groups))}
+	for _, group := range req.Groups {
+		matrix.Groups = append(matrix.Groups, config.LoginPolicyGroup{
+			Name:        group.Name,
+			PlatformIDs: group.PlatformIDs,
+			MaxOnline:   group.MaxOnline,
+		})
+	}
+	if err := l.database.SetLoginPolicyMatrix(c, matrix); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
@@ -0,0 +1,292 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"github.com/openimsdk/open-im-server/v3/pkg/rtc"
+)
+
+type MeetingRoomApi struct {
+	*rpcclient.Message
+	userRpc  *rpcclient.UserRpcClient
+	database controller.MeetingRoomDatabase
+	config   *config.GlobalConfig
+}
+
+func NewMeetingRoomApi(msgRpcClient *rpcclient.Message, userRpc *rpcclient.UserRpcClient, database controller.MeetingRoomDatabase, config *config.GlobalConfig) MeetingRoomApi {
+	return MeetingRoomApi{Message: msgRpcClient, userRpc: userRpc, database: database, config: config}
+}
+
+func (m *MeetingRoomApi) CreateMeetingRoom(c *gin.Context) {
+	var req apistruct.CreateMeetingRoomReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if !m.config.Meeting.Enable {
+		apiresp.GinError(c, errs.ErrNoPermission.Wrap("meeting is disabled"))
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.HostUserID, m.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	startTime, endTime := time.UnixMilli(req.StartTime), time.UnixMilli(req.EndTime)
+	if err := checkMeetingRoomTime(startTime, endTime); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	participantIDs, err := m.getParticipantIDs(c, req.HostUserID, req.ParticipantIDs)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	now := time.Now()
+	room := &relation.MeetingRoomModel{
+		RoomID:         utils.GetMsgID(req.HostUserID),
+		Title:          req.Title,
+		HostUserID:     req.HostUserID,
+		ParticipantIDs: participantIDs,
+		StartTime:      startTime,
+		EndTime:        endTime,
+		Status:         relation.MeetingRoomScheduled,
+		CreateTime:     now,
+		UpdateTime:     now,
+	}
+	if err := m.database.CreateRoom(c, room); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.CreateMeetingRoomResp{RoomID: room.RoomID})
+}
+
+func (m *MeetingRoomApi) UpdateMeetingRoom(c *gin.Context) {
+	var req apistruct.UpdateMeetingRoomReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	room, err := m.takeHostRoom(c, req.RoomID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	startTime, endTime := time.UnixMilli(req.StartTime), time.UnixMilli(req.EndTime)
+	if err := checkMeetingRoomTime(startTime, endTime); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	participantIDs, err := m.getParticipantIDs(c, room.HostUserID, req.ParticipantIDs)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := m.database.UpdateRoom(c, req.RoomID, req.Title, startTime, endTime, participantIDs); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (m *MeetingRoomApi) CancelMeetingRoom(c *gin.Context) {
+	var req apistruct.CancelMeetingRoomReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	room, err := m.takeHostRoom(c, req.RoomID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := m.database.CancelRoom(c, req.RoomID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	room.Status = relation.MeetingRoomCanceled
+	m.notifyMeetingRoom(c, msgprocessor.MeetingEndedNotification, room)
+	apiresp.GinSuccess(c, nil)
+}
+
+func (m *MeetingRoomApi) EndMeetingRoom(c *gin.Context) {
+	var req apistruct.EndMeetingRoomReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	room, err := m.takeHostRoom(c, req.RoomID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := m.database.EndRoom(c, req.RoomID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	room.Status = relation.MeetingRoomEnded
+	m.notifyMeetingRoom(c, msgprocessor.MeetingEndedNotification, room)
+	apiresp.GinSuccess(c, nil)
+}
+
+func (m *MeetingRoomApi) GetMeetingRoom(c *gin.Context) {
+	var req apistruct.GetMeetingRoomReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	room, err := m.database.TakeRoom(c, req.RoomID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if !authverify.IsAppManagerUid(c, m.config) && !utils.IsContain(mcontext.GetOpUserID(c), room.ParticipantIDs) {
+		apiresp.GinError(c, errs.ErrNoPermission.Wrap("not a participant of the meeting room"))
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.GetMeetingRoomResp{Room: convertMeetingRoom(room)})
+}
+
+func (m *MeetingRoomApi) GetUpcomingMeetingRooms(c *gin.Context) {
+	var req apistruct.GetUpcomingMeetingRoomsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, m.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, rooms, err := m.database.GetUpcomingRooms(c, req.UserID, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetUpcomingMeetingRoomsResp{Total: total, Rooms: make([]*apistruct.MeetingRoom, 0, len(rooms))}
+	for _, room := range rooms {
+		resp.Rooms = append(resp.Rooms, convertMeetingRoom(room))
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+func (m *MeetingRoomApi) JoinMeetingRoom(c *gin.Context) {
+	var req apistruct.JoinMeetingRoomReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if !m.config.Meeting.Enable {
+		apiresp.GinError(c, errs.ErrNoPermission.Wrap("meeting is disabled"))
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, m.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	room, err := m.database.TakeRoom(c, req.RoomID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if !utils.IsContain(req.UserID, room.ParticipantIDs) {
+		apiresp.GinError(c, errs.ErrNoPermission.Wrap("not a participant of the meeting room"))
+		return
+	}
+	if room.Status != relation.MeetingRoomScheduled && room.Status != relation.MeetingRoomStarted {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("meeting room is over"))
+		return
+	}
+	expire := time.Now().Add(time.Duration(m.config.Meeting.JoinTokenTTL) * time.Second)
+	apiresp.GinSuccess(c, &apistruct.JoinMeetingRoomResp{
+		Token:      rtc.RoomToken(m.config.Meeting.JoinSecret, room.RoomID, req.UserID, expire),
+		ExpireTime: expire.UnixMilli(),
+	})
+}
+
+// takeHostRoom returns the room if the token user is its host or an app manager.
+func (m *MeetingRoomApi) takeHostRoom(ctx context.Context, roomID string) (*relation.MeetingRoomModel, error) {
+	room, err := m.database.TakeRoom(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authverify.CheckAccessV3(ctx, room.HostUserID, m.config); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// getParticipantIDs returns the distinct participants with the host first, all of them must exist.
+func (m *MeetingRoomApi) getParticipantIDs(ctx context.Context, hostUserID string, userIDs []string) ([]string, error) {
+	participantIDs := utils.Distinct(append([]string{hostUserID}, userIDs...))
+	if _, err := m.userRpc.GetUsersInfo(ctx, participantIDs); err != nil {
+		return nil, err
+	}
+	return participantIDs, nil
+}
+
+func (m *MeetingRoomApi) notifyMeetingRoom(ctx context.Context, contentType int32, room *relation.MeetingRoomModel) {
+	elem := &msgprocessor.MeetingRoomElem{
+		RoomID:     room.RoomID,
+		Title:      room.Title,
+		HostUserID: room.HostUserID,
+		StartTime:  room.StartTime.UnixMilli(),
+		EndTime:    room.EndTime.UnixMilli(),
+		Status:     room.Status,
+	}
+	for _, userID := range room.ParticipantIDs {
+		if _, err := m.Client.SendMsg(ctx, &msg.SendMsgReq{MsgData: msgprocessor.NewMeetingRoomMsg(contentType, userID, elem)}); err != nil {
+			log.ZWarn(ctx, "send meeting room notification failed", err, "roomID", room.RoomID, "userID", userID)
+		}
+	}
+}
+
+func checkMeetingRoomTime(startTime, endTime time.Time) error {
+	if !endTime.After(startTime) {
+		return errs.ErrArgs.Wrap("endTime must be after startTime")
+	}
+	if !endTime.After(time.Now()) {
+		return errs.ErrArgs.Wrap("endTime must be in the future")
+	}
+	return nil
+}
+
+func convertMeetingRoom(room *relation.MeetingRoomModel) *apistruct.MeetingRoom {
+	return &apistruct.MeetingRoom{
+		RoomID:         room.RoomID,
+		Title:          room.Title,
+		HostUserID:     room.HostUserID,
+		ParticipantIDs: room.ParticipantIDs,
+		StartTime:      room.StartTime.UnixMilli(),
+		EndTime:        room.EndTime.UnixMilli(),
+		Status:         room.Status,
+		CreateTime:     room.CreateTime.UnixMilli(),
+	}
+}
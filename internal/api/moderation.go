@@ -0,0 +1,302 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/moderation"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type ModerationApi struct {
+	database      controller.ModerationDatabase
+	sensitivityDB controller.ModerationSensitivityDatabase
+	groupRpc      *rpcclient.GroupRpcClient
+	config        *config.GlobalConfig
+}
+
+func NewModerationApi(database controller.ModerationDatabase, sensitivityDB controller.ModerationSensitivityDatabase, groupRpc *rpcclient.GroupRpcClient, config *config.GlobalConfig) ModerationApi {
+	return ModerationApi{database: database, sensitivityDB: sensitivityDB, groupRpc: groupRpc, config: config}
+}
+
+func (m *ModerationApi) AddRule(c *gin.Context) {
+	var req apistruct.AddModerationRuleReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, m.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	rule := &relation.ModerationRuleModel{
+		Pattern:        req.Pattern,
+		Regex:          req.Regex,
+		Action:         req.Action,
+		Replacement:    req.Replacement,
+		OperatorUserID: mcontext.GetOpUserID(c),
+	}
+	if err := m.database.AddRule(c, rule); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.AddModerationRuleResp{RuleID: rule.RuleID})
+}
+
+func (m *ModerationApi) DeleteRules(c *gin.Context) {
+	var req apistruct.DeleteModerationRulesReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, m.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := m.database.DeleteRules(c, req.RuleIDs); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (m *ModerationApi) GetRules(c *gin.Context) {
+	var req apistruct.GetModerationRulesReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, m.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, rules, err := m.database.PageRules(c, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetModerationRulesResp{Total: total, Rules: make([]*apistruct.ModerationRule, 0, len(rules))}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, &apistruct.ModerationRule{
+			RuleID:         rule.RuleID,
+			Pattern:        rule.Pattern,
+			Regex:          rule.Regex,
+			Action:         rule.Action,
+			Replacement:    rule.Replacement,
+			OperatorUserID: rule.OperatorUserID,
+			CreateTime:     rule.CreateTime.UnixMilli(),
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+func (m *ModerationApi) GetFlags(c *gin.Context) {
+	var req apistruct.GetModerationFlagsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, m.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, flags, err := m.database.PageFlags(c, req.SendID, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetModerationFlagsResp{Total: total, Flags: make([]*apistruct.ModerationFlag, 0, len(flags))}
+	for _, flag := range flags {
+		resp.Flags = append(resp.Flags, &apistruct.ModerationFlag{
+			ServerMsgID:    flag.ServerMsgID,
+			ClientMsgID:    flag.ClientMsgID,
+			ConversationID: flag.ConversationID,
+			SendID:         flag.SendID,
+			ContentType:    flag.ContentType,
+			Source:         flag.Source,
+			RuleIDs:        flag.RuleIDs,
+			Reason:         flag.Reason,
+			Scores:         flag.Scores,
+			Sensitivity:    flag.Sensitivity,
+			CreateTime:     flag.CreateTime.UnixMilli(),
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+func (m *ModerationApi) checkScoring() error {
+	if !m.config.Moderation.Enable || !m.config.Moderation.Scoring.Enable {
+		return errs.ErrArgs.Wrap("moderation scoring is not enabled")
+	}
+	return nil
+}
+
+// defaultSensitivity returns the level of the conversations of the session type of conversationID.
+func (m *ModerationApi) defaultSensitivity(conversationID string) string {
+	switch {
+	case strings.HasPrefix(conversationID, "si_"):
+		return m.config.Moderation.Scoring.SingleLevel
+	case msgprocessor.IsGroupConversationID(conversationID):
+		return m.config.Moderation.Scoring.GroupLevel
+	default:
+		return moderation.SensitivityOff
+	}
+}
+
+// checkGroupOwner allows the owner of the group of conversationID to change its level when the current level lies
+// within the bounds the owners are given, a level set by an admin outside them cannot be overridden.
+func (m *ModerationApi) checkGroupOwner(ctx context.Context, conversationID string) error {
+	if !msgprocessor.IsGroupConversationID(conversationID) {
+		return errs.ErrNoPermission.Wrap("only the sensitivity of groups can be set by their owner")
+	}
+	groupID := conversationID[strings.Index(conversationID, "_")+1:]
+	group, err := m.groupRpc.GetGroupInfo(ctx, groupID)
+	if err != nil {
+		return err
+	}
+	if group.OwnerUserID != mcontext.GetOpUserID(ctx) {
+		return errs.ErrNoPermission.Wrap("only the group owner can set the sensitivity of the group")
+	}
+	level, err := m.sensitivityDB.GetLevel(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	if level != "" && !m.ownerMaySet(level) {
+		return errs.ErrNoPermission.Wrap("the sensitivity of the group was set by an admin")
+	}
+	return nil
+}
+
+func (m *ModerationApi) ownerMaySet(level string) bool {
+	scoring := m.config.Moderation.Scoring
+	return moderation.SensitivityInBounds(level, scoring.OwnerMinLevel, scoring.OwnerMaxLevel)
+}
+
+func (m *ModerationApi) SetSensitivity(c *gin.Context) {
+	var req apistruct.SetModerationSensitivityReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := m.checkScoring(); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if !moderation.ValidSensitivity(req.Level) {
+		apiresp.GinError(c, errs.ErrArgs.Wrap(fmt.Sprintf("unknown sensitivity level %s", req.Level)))
+		return
+	}
+	if !authverify.IsAppManagerUid(c, m.config) {
+		if err := m.checkGroupOwner(c, req.ConversationID); err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		if !m.ownerMaySet(req.Level) {
+			scoring := m.config.Moderation.Scoring
+			apiresp.GinError(c, errs.ErrNoPermission.Wrap(fmt.Sprintf("group owners may set levels from %s to %s", scoring.OwnerMinLevel, scoring.OwnerMaxLevel)))
+			return
+		}
+	}
+	if err := m.sensitivityDB.SetLevel(c, req.ConversationID, req.Level, mcontext.GetOpUserID(c)); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (m *ModerationApi) DeleteSensitivity(c *gin.Context) {
+	var req apistruct.DeleteModerationSensitivityReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := m.checkScoring(); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if !authverify.IsAppManagerUid(c, m.config) {
+		if err := m.checkGroupOwner(c, req.ConversationID); err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+	}
+	if err := m.sensitivityDB.DeleteLevel(c, req.ConversationID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (m *ModerationApi) GetSensitivity(c *gin.Context) {
+	var req apistruct.GetModerationSensitivityReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := m.checkScoring(); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	level, err := m.sensitivityDB.GetLevel(c, req.ConversationID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if level == "" {
+		apiresp.GinSuccess(c, &apistruct.GetModerationSensitivityResp{Level: m.defaultSensitivity(req.ConversationID), IsDefault: true})
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.GetModerationSensitivityResp{Level: level})
+}
+
+func (m *ModerationApi) GetSensitivities(c *gin.Context) {
+	var req apistruct.GetModerationSensitivitiesReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, m.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, sensitivities, err := m.sensitivityDB.PageLevels(c, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetModerationSensitivitiesResp{Total: total, Sensitivities: make([]*apistruct.ModerationSensitivity, 0, len(sensitivities))}
+	for _, sensitivity := range sensitivities {
+		resp.Sensitivities = append(resp.Sensitivities, &apistruct.ModerationSensitivity{
+			ConversationID: sensitivity.ConversationID,
+			Level:          sensitivity.Level,
+			OperatorUserID: sensitivity.OperatorUserID,
+			UpdateTime:     sensitivity.UpdateTime.UnixMilli(),
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
@@ -15,6 +15,8 @@
 package api
 
 import (
+	"context"
+
 	"github.com/OpenIMSDK/protocol/constant"
 	"github.com/OpenIMSDK/protocol/msg"
 	"github.com/OpenIMSDK/protocol/sdkws"
@@ -30,6 +32,9 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
 	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgext"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/notificationcatalog"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 )
 
@@ -97,6 +102,12 @@ func (m MessageApi) newUserSendMsgReq(_ *gin.Context, params *apistruct.SendMsg)
 			OfflinePushInfo:  params.OfflinePushInfo,
 		},
 	}
+	if params.ParentMsgID != "" {
+		msgprocessor.SetParentMsgID(pbData.MsgData, params.ParentMsgID)
+	}
+	if params.Priority != msgprocessor.PriorityNormal {
+		msgprocessor.SetPriority(pbData.MsgData, params.Priority)
+	}
 	return &pbData
 }
 
@@ -148,6 +159,30 @@ func (m *MessageApi) DeleteMsgPhysical(c *gin.Context) {
 	a2r.Call(msg.MsgClient.DeleteMsgPhysical, m.Client, c)
 }
 
+func (m *MessageApi) SetMsgReaction(c *gin.Context) {
+	a2r.Call((*msgext.Client).SetMsgReaction, m.Ext, c)
+}
+
+func (m *MessageApi) RemoveMsgReaction(c *gin.Context) {
+	a2r.Call((*msgext.Client).RemoveMsgReaction, m.Ext, c)
+}
+
+func (m *MessageApi) GetMsgReactions(c *gin.Context) {
+	a2r.Call((*msgext.Client).GetMsgReactions, m.Ext, c)
+}
+
+func (m *MessageApi) EditMsg(c *gin.Context) {
+	a2r.Call((*msgext.Client).EditMsg, m.Ext, c)
+}
+
+func (m *MessageApi) GetMsgEditHistory(c *gin.Context) {
+	a2r.Call((*msgext.Client).GetMsgEditHistory, m.Ext, c)
+}
+
+func (m *MessageApi) DeleteMsgsPhysical(c *gin.Context) {
+	a2r.Call((*msgext.Client).DeleteMsgsPhysical, m.Ext, c)
+}
+
 func (m *MessageApi) getSendMsgReq(c *gin.Context, req apistruct.SendMsg) (sendMsgReq *msg.SendMsgReq, err error) {
 	var data any
 	log.ZDebug(c, "getSendMsgReq", "req", req.Content)
@@ -265,10 +300,7 @@ func (m *MessageApi) SendBusinessNotification(c *gin.Context) {
 			SendID: req.SendUserID,
 			RecvID: req.RecvUserID,
 			Content: []byte(utils.StructToJsonString(&sdkws.NotificationElem{
-				Detail: utils.StructToJsonString(&struct {
-					Key  string `json:"key"`
-					Data string `json:"data"`
-				}{Key: req.Key, Data: req.Data}),
+				Detail: utils.StructToJsonString(&notificationcatalog.BusinessElem{Key: req.Key, Data: req.Data}),
 			})),
 			MsgFrom:     constant.SysMsgType,
 			ContentType: constant.BusinessNotification,
@@ -370,3 +402,25 @@ func (m *MessageApi) SearchMsg(c *gin.Context) {
 func (m *MessageApi) GetServerTime(c *gin.Context) {
 	a2r.Call(msg.MsgClient.GetServerTime, m.Client, c)
 }
+
+// getMsgBySeq pulls the message at seq as userID, which also ensures the user can see the conversation.
+func getMsgBySeq(ctx context.Context, msgClient *rpcclient.Message, userID string, conversationID string, seq int64) (*sdkws.MsgData, error) {
+	resp, err := msgClient.Client.PullMessageBySeqs(ctx, &sdkws.PullMessageBySeqsReq{
+		UserID: userID,
+		SeqRanges: []*sdkws.SeqRange{
+			{ConversationID: conversationID, Begin: seq, End: seq, Num: 1},
+		},
+		Order: sdkws.PullOrder_PullOrderAsc,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if pullMsgs := resp.Msgs[conversationID]; pullMsgs != nil {
+		for _, msgData := range pullMsgs.Msgs {
+			if msgData.Seq == seq && msgData.Status != constant.MsgDeleted {
+				return msgData, nil
+			}
+		}
+	}
+	return nil, errs.ErrRecordNotFound.Wrap("message not found")
+}
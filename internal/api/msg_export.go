@@ -0,0 +1,257 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/third"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgexport"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type MsgExportApi struct {
+	database        controller.MsgExportDatabase
+	conversationRpc *rpcclient.ConversationRpcClient
+	groupRpc        *rpcclient.GroupRpcClient
+	thirdRpc        *rpcclient.Third
+	config          *config.GlobalConfig
+}
+
+func NewMsgExportApi(database controller.MsgExportDatabase, conversationRpc *rpcclient.ConversationRpcClient, groupRpc *rpcclient.GroupRpcClient, thirdRpc *rpcclient.Third, config *config.GlobalConfig) MsgExportApi {
+	return MsgExportApi{database: database, conversationRpc: conversationRpc, groupRpc: groupRpc, thirdRpc: thirdRpc, config: config}
+}
+
+func (m *MsgExportApi) ExportConversationMsgs(c *gin.Context) {
+	var req apistruct.ExportConversationMsgsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if !m.config.MsgExport.Enable {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("message export is not enabled"))
+		return
+	}
+	if req.Format == "" {
+		req.Format = msgexport.FormatJSON
+	}
+	if !msgexport.ValidFormat(req.Format) {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("format must be json, csv or html"))
+		return
+	}
+	now := time.Now()
+	if req.EndTime <= 0 {
+		req.EndTime = now.UnixMilli()
+	}
+	if req.StartTime < 0 || req.StartTime >= req.EndTime {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("startTime must be before endTime"))
+		return
+	}
+	opUserID := mcontext.GetOpUserID(c)
+	isAdmin := authverify.IsAppManagerUid(c, m.config)
+	if !isAdmin {
+		if err := m.checkOwner(c, opUserID, req.ConversationID); err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+	}
+	maxMsgs := m.config.MsgExport.MaxMsgs
+	if maxMsgs <= 0 {
+		maxMsgs = 100000
+	}
+	// One more than an export takes tells whether the range was cut.
+	msgs, err := m.database.FindMsgs(c, req.ConversationID, req.StartTime, req.EndTime, maxMsgs+1)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	export := &msgexport.Export{
+		ConversationID: req.ConversationID,
+		StartTime:      req.StartTime,
+		EndTime:        req.EndTime,
+		ExportTime:     now.UnixMilli(),
+		Msgs:           []*msgexport.Msg{},
+	}
+	if int64(len(msgs)) > maxMsgs {
+		// Messages sharing the send time of the first one left out are left out too, so that the next export
+		// starting at EndTime repeats none and misses none.
+		export.EndTime = msgs[maxMsgs].Msg.SendTime
+		export.Truncated = true
+	}
+	for _, msg := range msgs {
+		if msg.Msg == nil || msg.Msg.SendTime >= export.EndTime || msg.Msg.Status == constant.MsgDeleted {
+			continue
+		}
+		if !isAdmin && utils.IsContain(opUserID, msg.DelList) {
+			continue
+		}
+		export.Msgs = append(export.Msgs, &msgexport.Msg{
+			Seq:            msg.Msg.Seq,
+			ClientMsgID:    msg.Msg.ClientMsgID,
+			ServerMsgID:    msg.Msg.ServerMsgID,
+			SendID:         msg.Msg.SendID,
+			SenderNickname: msg.Msg.SenderNickname,
+			SessionType:    msg.Msg.SessionType,
+			ContentType:    msg.Msg.ContentType,
+			Content:        msg.Msg.Content,
+			SendTime:       msg.Msg.SendTime,
+			Revoked:        msg.Revoke != nil,
+		})
+	}
+	var buf bytes.Buffer
+	if err := msgexport.Encode(&buf, req.Format, export); err != nil {
+		apiresp.GinError(c, errs.Wrap(err))
+		return
+	}
+	name := fmt.Sprintf("%s/msg_export/%s_%d.%s", opUserID, req.ConversationID, now.UnixMilli(), req.Format)
+	if err := m.upload(c, name, msgexport.ContentType(req.Format), buf.Bytes()); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	access, err := m.thirdRpc.Client.AccessURL(c, &third.AccessURLReq{Name: name})
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.ExportConversationMsgsResp{
+		URL:        access.Url,
+		ExpireTime: access.ExpireTime,
+		Name:       name,
+		MsgNum:     len(export.Msgs),
+		Truncated:  export.Truncated,
+		EndTime:    export.EndTime,
+	})
+}
+
+// checkOwner allows users to export their own single chats and the groups they own.
+func (m *MsgExportApi) checkOwner(ctx context.Context, userID string, conversationID string) error {
+	if _, err := m.conversationRpc.GetConversation(ctx, userID, conversationID); err != nil {
+		return err
+	}
+	switch {
+	case strings.HasPrefix(conversationID, "si_"):
+		return nil
+	case msgprocessor.IsGroupConversationID(conversationID):
+		groupID := conversationID[strings.Index(conversationID, "_")+1:]
+		group, err := m.groupRpc.GetGroupInfo(ctx, groupID)
+		if err != nil {
+			return err
+		}
+		if group.OwnerUserID != userID {
+			return errs.ErrNoPermission.Wrap("only the group owner can export the group")
+		}
+		return nil
+	default:
+		return errs.ErrNoPermission.Wrap("only single chats and groups can be exported")
+	}
+}
+
+// upload stores data as name through the multipart upload of the third service, the same protocol clients use.
+func (m *MsgExportApi) upload(ctx context.Context, name string, contentType string, data []byte) error {
+	partSize, err := m.thirdRpc.Client.PartSize(ctx, &third.PartSizeReq{Size: int64(len(data))})
+	if err != nil {
+		return err
+	}
+	parts, partHashes, hash := msgexport.SplitParts(data, partSize.Size)
+	initiate, err := m.thirdRpc.Client.InitiateMultipartUpload(ctx, &third.InitiateMultipartUploadReq{
+		Hash:        hash,
+		Size:        int64(len(data)),
+		PartSize:    partSize.Size,
+		MaxParts:    -1,
+		Cause:       "msg_export",
+		Name:        name,
+		ContentType: contentType,
+	})
+	if err != nil {
+		return err
+	}
+	// The same content was stored before, the third service already bound it to name.
+	if initiate.Upload == nil {
+		return nil
+	}
+	sign := initiate.Upload.Sign
+	if sign == nil || len(sign.Parts) != len(parts) {
+		return errs.ErrInternalServer.Wrap("upload is not signed for every part")
+	}
+	for i, part := range sign.Parts {
+		if err := putPart(ctx, sign, part, parts[i]); err != nil {
+			return err
+		}
+	}
+	_, err = m.thirdRpc.Client.CompleteMultipartUpload(ctx, &third.CompleteMultipartUploadReq{
+		UploadID:    initiate.Upload.UploadID,
+		Parts:       partHashes,
+		Name:        name,
+		ContentType: contentType,
+		Cause:       "msg_export",
+	})
+	return err
+}
+
+func putPart(ctx context.Context, sign *third.AuthSignParts, part *third.SignPart, data []byte) error {
+	rawURL := part.Url
+	if rawURL == "" {
+		rawURL = sign.Url
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	query := u.Query()
+	for _, kvs := range [][]*third.KeyValues{sign.Query, part.Query} {
+		for _, kv := range kvs {
+			query[kv.Key] = kv.Values
+		}
+	}
+	u.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	for _, kvs := range [][]*third.KeyValues{sign.Header, part.Header} {
+		for _, kv := range kvs {
+			req.Header[kv.Key] = kv.Values
+		}
+	}
+	req.ContentLength = int64(len(data))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errs.ErrInternalServer.Wrap(fmt.Sprintf("upload part %d: %s %s", part.PartNumber, resp.Status, body))
+	}
+	return nil
+}
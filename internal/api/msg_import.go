@@ -0,0 +1,184 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type MsgImportApi struct {
+	database        controller.MsgImportDatabase
+	userRpc         *rpcclient.UserRpcClient
+	groupRpc        *rpcclient.GroupRpcClient
+	conversationRpc *rpcclient.ConversationRpcClient
+	config          *config.GlobalConfig
+}
+
+func NewMsgImportApi(database controller.MsgImportDatabase, userRpc *rpcclient.UserRpcClient, groupRpc *rpcclient.GroupRpcClient, conversationRpc *rpcclient.ConversationRpcClient, config *config.GlobalConfig) MsgImportApi {
+	return MsgImportApi{database: database, userRpc: userRpc, groupRpc: groupRpc, conversationRpc: conversationRpc, config: config}
+}
+
+func (m *MsgImportApi) ImportMsgs(c *gin.Context) {
+	var req apistruct.ImportMsgsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, m.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if !m.config.MsgImport.Enable {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("message import is not enabled"))
+		return
+	}
+	maxBatch := m.config.MsgImport.MaxBatch
+	if maxBatch <= 0 {
+		maxBatch = 1000
+	}
+	if len(req.Msgs) > maxBatch {
+		apiresp.GinError(c, errs.ErrArgs.Wrap(fmt.Sprintf("at most %d msgs can be imported at once", maxBatch)))
+		return
+	}
+	var (
+		conversationID string
+		memberIDs      []string
+	)
+	switch req.SessionType {
+	case constant.SingleChatType:
+		if len(req.UserIDs) != 2 || req.UserIDs[0] == req.UserIDs[1] {
+			apiresp.GinError(c, errs.ErrArgs.Wrap("userIDs must be the two users of the single chat"))
+			return
+		}
+		if _, err := m.userRpc.GetUsersInfo(c, req.UserIDs); err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		memberIDs = req.UserIDs
+		conversationID = msgprocessor.GetConversationIDBySessionType(constant.SingleChatType, req.UserIDs...)
+	case constant.GroupChatType, constant.SuperGroupChatType:
+		if req.GroupID == "" {
+			apiresp.GinError(c, errs.ErrArgs.Wrap("groupID is required"))
+			return
+		}
+		if _, err := m.groupRpc.GetGroupInfo(c, req.GroupID); err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		var err error
+		if memberIDs, err = m.groupRpc.GetGroupMemberIDs(c, req.GroupID); err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		req.SessionType = constant.SuperGroupChatType
+		conversationID = msgprocessor.GetConversationIDBySessionType(constant.SuperGroupChatType, req.GroupID)
+	default:
+		apiresp.GinError(c, errs.ErrArgs.Wrap("only single chats and groups can be imported"))
+		return
+	}
+	msgs := make([]*sdkws.MsgData, 0, len(req.Msgs))
+	var skipped int
+	for i, msg := range req.Msgs {
+		if msg == nil || msg.SendID == "" || msg.ContentType == 0 || msg.SendTime <= 0 {
+			apiresp.GinError(c, errs.ErrArgs.Wrap(fmt.Sprintf("msgs[%d] needs sendID, contentType and sendTime", i)))
+			return
+		}
+		if !utils.IsContain(msg.SendID, memberIDs) {
+			apiresp.GinError(c, errs.ErrArgs.Wrap(fmt.Sprintf("msgs[%d] sender %s is not in the conversation", i, msg.SendID)))
+			return
+		}
+		// Notifications describe state of the old system, e.g. group changes, they are not history.
+		if msg.ContentType >= constant.NotificationBegin {
+			skipped++
+			continue
+		}
+		msgs = append(msgs, m.msgData(&req, msg))
+	}
+	resp := &apistruct.ImportMsgsResp{ConversationID: conversationID, Skipped: skipped}
+	if len(msgs) == 0 {
+		apiresp.GinSuccess(c, resp)
+		return
+	}
+	lastMaxSeq, err := m.database.Import(c, conversationID, msgs, memberIDs)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if lastMaxSeq == 0 {
+		m.createConversations(c, &req, conversationID, memberIDs)
+	}
+	resp.Imported = len(msgs)
+	resp.FirstSeq = lastMaxSeq + 1
+	resp.LastSeq = lastMaxSeq + int64(len(msgs))
+	apiresp.GinSuccess(c, resp)
+}
+
+func (m *MsgImportApi) msgData(req *apistruct.ImportMsgsReq, msg *apistruct.ImportMsg) *sdkws.MsgData {
+	data := &sdkws.MsgData{
+		SendID:           msg.SendID,
+		GroupID:          req.GroupID,
+		ClientMsgID:      msg.ClientMsgID,
+		ServerMsgID:      utils.GetMsgID(msg.SendID),
+		SenderPlatformID: msg.SenderPlatformID,
+		SenderNickname:   msg.SenderNickname,
+		SenderFaceURL:    msg.SenderFaceURL,
+		SessionType:      req.SessionType,
+		MsgFrom:          constant.UserMsgType,
+		ContentType:      msg.ContentType,
+		Content:          msg.Content,
+		SendTime:         msg.SendTime,
+		CreateTime:       msg.SendTime,
+		Status:           constant.MsgSendSuccessed,
+		Options:          msgprocessor.NewMsgOptions(),
+		Ex:               msg.Ex,
+	}
+	if data.ClientMsgID == "" {
+		data.ClientMsgID = utils.GetMsgID(msg.SendID)
+	}
+	if req.SessionType == constant.SingleChatType {
+		data.RecvID = req.UserIDs[0]
+		if data.RecvID == msg.SendID {
+			data.RecvID = req.UserIDs[1]
+		}
+	}
+	return data
+}
+
+// createConversations gives the members the conversation an imported history starts, as the first sent message would.
+func (m *MsgImportApi) createConversations(c *gin.Context, req *apistruct.ImportMsgsReq, conversationID string, memberIDs []string) {
+	var err error
+	if req.SessionType == constant.SingleChatType {
+		err = m.conversationRpc.SingleChatFirstCreateConversation(c, req.UserIDs[0], req.UserIDs[1], conversationID, constant.SingleChatType)
+	} else {
+		err = m.conversationRpc.GroupChatFirstCreateConversation(c, req.GroupID, memberIDs)
+	}
+	if err != nil {
+		log.ZWarn(c, "create imported conversation failed", err, "conversationID", conversationID)
+	}
+}
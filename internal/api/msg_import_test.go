@@ -0,0 +1,119 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	pbconversation "github.com/OpenIMSDK/protocol/conversation"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/protocol/user"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type importUserClient struct {
+	user.UserClient
+}
+
+func (importUserClient) GetDesignateUsers(_ context.Context, req *user.GetDesignateUsersReq, _ ...grpc.CallOption) (*user.GetDesignateUsersResp, error) {
+	resp := &user.GetDesignateUsersResp{}
+	for _, userID := range req.UserIDs {
+		resp.UsersInfo = append(resp.UsersInfo, &sdkws.UserInfo{UserID: userID})
+	}
+	return resp, nil
+}
+
+type importConversationClient struct {
+	pbconversation.ConversationClient
+	created []string
+}
+
+func (c *importConversationClient) CreateSingleChatConversations(_ context.Context, req *pbconversation.CreateSingleChatConversationsReq, _ ...grpc.CallOption) (*pbconversation.CreateSingleChatConversationsResp, error) {
+	c.created = append(c.created, req.ConversationID)
+	return &pbconversation.CreateSingleChatConversationsResp{}, nil
+}
+
+type importDatabase struct {
+	conversationID string
+	msgs           []*sdkws.MsgData
+	readUserIDs    []string
+}
+
+func (d *importDatabase) Import(_ context.Context, conversationID string, msgs []*sdkws.MsgData, readUserIDs []string) (int64, error) {
+	d.conversationID, d.msgs, d.readUserIDs = conversationID, msgs, readUserIDs
+	return 0, nil
+}
+
+func TestImportMsgs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	conf := config.NewGlobalConfig()
+	conf.IMAdmin.UserID = []string{"admin"}
+	conf.MsgImport.Enable = true
+	database := &importDatabase{}
+	conversationClient := &importConversationClient{}
+	m := NewMsgImportApi(database, &rpcclient.UserRpcClient{Client: importUserClient{}}, nil,
+		&rpcclient.ConversationRpcClient{Client: conversationClient}, conf)
+
+	body, err := json.Marshal(&apistruct.ImportMsgsReq{
+		SessionType: constant.SingleChatType,
+		UserIDs:     []string{"u1", "u2"},
+		Msgs: []*apistruct.ImportMsg{
+			{SendID: "u1", ContentType: constant.Text, Content: json.RawMessage(`{"content":"hi"}`), SendTime: 2000},
+			{SendID: "u2", ContentType: constant.Text, Content: json.RawMessage(`{"content":"hello"}`), SendTime: 1000},
+			{SendID: "u2", ContentType: constant.FriendAddedNotification, Content: json.RawMessage(`{}`), SendTime: 1500},
+		},
+	})
+	assert.NoError(t, err)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/msg/import_msgs", bytes.NewReader(body))
+	c.Set(constant.OpUserID, "admin")
+	m.ImportMsgs(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		ErrCode int                      `json:"errCode"`
+		ErrMsg  string                   `json:"errMsg"`
+		Data    apistruct.ImportMsgsResp `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 0, resp.ErrCode, resp.ErrMsg)
+	assert.Equal(t, 2, resp.Data.Imported)
+	assert.Equal(t, 1, resp.Data.Skipped)
+	assert.Equal(t, int64(1), resp.Data.FirstSeq)
+	assert.Equal(t, int64(2), resp.Data.LastSeq)
+
+	assert.Equal(t, "si_u1_u2", database.conversationID)
+	assert.Equal(t, []string{"u1", "u2"}, database.readUserIDs)
+	assert.Len(t, database.msgs, 2)
+	for _, msg := range database.msgs {
+		assert.Equal(t, int32(constant.MsgSendSuccessed), msg.Status)
+		assert.Equal(t, int32(constant.SingleChatType), msg.SessionType)
+		assert.NotEmpty(t, msg.ClientMsgID)
+		assert.NotEqual(t, msg.SendID, msg.RecvID)
+	}
+	assert.Equal(t, []string{database.conversationID}, conversationClient.created)
+}
@@ -0,0 +1,62 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+)
+
+type MsgReceiptApi struct {
+	database controller.MsgReceiptDatabase
+	config   *config.GlobalConfig
+}
+
+func NewMsgReceiptApi(database controller.MsgReceiptDatabase, config *config.GlobalConfig) MsgReceiptApi {
+	return MsgReceiptApi{database: database, config: config}
+}
+
+func (m *MsgReceiptApi) GetReceiptSummaries(c *gin.Context) {
+	var req apistruct.GetReceiptSummariesReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, m.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	summaries, err := m.database.GetReceiptSummaries(c, req.ConversationID, req.UserIDs)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetReceiptSummariesResp{Summaries: make([]*apistruct.ReceiptSummary, 0, len(summaries))}
+	for _, summary := range summaries {
+		resp.Summaries = append(resp.Summaries, &apistruct.ReceiptSummary{
+			UserID:        summary.UserID,
+			HasReadSeq:    summary.HasReadSeq,
+			ReceiptCount:  summary.ReceiptCount,
+			FirstReadTime: summary.FirstReadTime.UnixMilli(),
+			LastReadTime:  summary.LastReadTime.UnixMilli(),
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
@@ -0,0 +1,126 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+)
+
+type MsgRetentionApi struct {
+	database controller.MsgRetentionDatabase
+	config   *config.GlobalConfig
+}
+
+func NewMsgRetentionApi(database controller.MsgRetentionDatabase, config *config.GlobalConfig) MsgRetentionApi {
+	return MsgRetentionApi{database: database, config: config}
+}
+
+// retentionConversationID returns the conversation a policy is stored on, a group policy lives on the group chat conversation.
+func retentionConversationID(conversationID string, groupID string) (string, error) {
+	switch {
+	case conversationID != "" && groupID != "":
+		return "", errs.ErrArgs.Wrap("only one of conversationID and groupID can be set")
+	case groupID != "":
+		return "sg_" + groupID, nil
+	case conversationID != "":
+		return conversationID, nil
+	default:
+		return "", errs.ErrArgs.Wrap("conversationID or groupID is required")
+	}
+}
+
+func (m *MsgRetentionApi) SetMsgRetention(c *gin.Context) {
+	var req apistruct.SetMsgRetentionReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, m.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	conversationID, err := retentionConversationID(req.ConversationID, req.GroupID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := m.database.SetRetention(c, conversationID, req.GroupID, req.RetainDays); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (m *MsgRetentionApi) DeleteMsgRetention(c *gin.Context) {
+	var req apistruct.DeleteMsgRetentionReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, m.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	conversationID, err := retentionConversationID(req.ConversationID, req.GroupID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := m.database.DeleteRetention(c, conversationID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (m *MsgRetentionApi) GetMsgRetentions(c *gin.Context) {
+	var req apistruct.GetMsgRetentionsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, m.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	retentions, err := m.database.FindRetentions(c, req.ConversationIDs)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetMsgRetentionsResp{Retentions: make([]*apistruct.MsgRetention, 0, len(req.ConversationIDs))}
+	for _, conversationID := range req.ConversationIDs {
+		if retention, ok := retentions[conversationID]; ok {
+			resp.Retentions = append(resp.Retentions, &apistruct.MsgRetention{
+				ConversationID: conversationID,
+				GroupID:        retention.GroupID,
+				RetainDays:     retention.RetainDays,
+			})
+			continue
+		}
+		resp.Retentions = append(resp.Retentions, &apistruct.MsgRetention{
+			ConversationID: conversationID,
+			RetainDays:     int32(m.config.RetainChatRecords),
+			IsDefault:      true,
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
@@ -0,0 +1,146 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/search"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type MsgSearchApi struct {
+	MessageApi
+	backend         search.Backend
+	conversationRpc *rpcclient.ConversationRpcClient
+	groupRpc        *rpcclient.GroupRpcClient
+	groupHistory    controller.GroupHistoryDatabase
+	config          *config.GlobalConfig
+}
+
+func NewMsgSearchApi(msgApi MessageApi, backend search.Backend, conversationRpc *rpcclient.ConversationRpcClient, groupRpc *rpcclient.GroupRpcClient, groupHistory controller.GroupHistoryDatabase, config *config.GlobalConfig) MsgSearchApi {
+	return MsgSearchApi{
+		MessageApi:      msgApi,
+		backend:         backend,
+		conversationRpc: conversationRpc,
+		groupRpc:        groupRpc,
+		groupHistory:    groupHistory,
+		config:          config,
+	}
+}
+
+func (s *MsgSearchApi) SearchMsg(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	var req apistruct.SearchMsgReq
+	// The legacy request shape shares no required fields, so a failed decode just means no keyword.
+	_ = json.Unmarshal(body, &req)
+	if s.backend == nil || req.Keyword == "" {
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		s.MessageApi.SearchMsg(c)
+		return
+	}
+	if req.Pagination == nil || req.Pagination.ShowNumber <= 0 {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("pagination is required"))
+		return
+	}
+	conversationIDs := req.ConversationIDs
+	var minSendTimes map[string]int64
+	if !authverify.IsAppManagerUid(c, s.config) {
+		// Regular users only search the conversations they own.
+		ownConversationIDs, err := s.conversationRpc.GetConversationIDs(c, mcontext.GetOpUserID(c))
+		if err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		if len(conversationIDs) == 0 {
+			conversationIDs = ownConversationIDs
+		} else {
+			conversationIDs = utils.IntersectString(conversationIDs, ownConversationIDs)
+		}
+		if len(conversationIDs) == 0 {
+			apiresp.GinSuccess(c, &apistruct.SearchMsgResp{Hits: []*search.Hit{}})
+			return
+		}
+		minSendTimes, err = s.getMinSendTimes(c, mcontext.GetOpUserID(c), conversationIDs)
+		if err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+	}
+	total, hits, err := s.backend.Search(c, &search.Query{
+		Keyword:         req.Keyword,
+		ConversationIDs: conversationIDs,
+		SendID:          req.SendID,
+		ContentTypes:    req.ContentTypes,
+		StartTime:       req.StartTime,
+		EndTime:         req.EndTime,
+		MinSendTimes:    minSendTimes,
+		PageNumber:      req.Pagination.PageNumber,
+		ShowNumber:      req.Pagination.ShowNumber,
+	})
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.SearchMsgResp{Total: total, Hits: hits})
+}
+
+// getMinSendTimes returns the earliest send time userID may read in each of their group conversations
+// that restricts the history shown to new members.
+func (s *MsgSearchApi) getMinSendTimes(ctx context.Context, userID string, conversationIDs []string) (map[string]int64, error) {
+	minSendTimes := make(map[string]int64)
+	for _, conversationID := range conversationIDs {
+		if !strings.HasPrefix(conversationID, "sg_") {
+			continue
+		}
+		groupID := strings.TrimPrefix(conversationID, "sg_")
+		visibility, err := s.groupHistory.GetVisibility(ctx, groupID)
+		if err != nil {
+			return nil, err
+		}
+		if visibility.Visibility == relation.GroupHistoryVisibleAll {
+			continue
+		}
+		member, err := s.groupRpc.GetGroupMemberInfo(ctx, groupID, userID)
+		if err != nil {
+			if errs.ErrNotInGroupYet.Is(err) {
+				continue
+			}
+			return nil, err
+		}
+		if since := visibility.VisibleSince(member.JoinTime); since > 0 {
+			minSendTimes[conversationID] = since
+		}
+	}
+	return minSendTimes, nil
+}
@@ -0,0 +1,103 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type MsgThreadApi struct {
+	*rpcclient.Message
+	database controller.MsgThreadDatabase
+	config   *config.GlobalConfig
+}
+
+func NewMsgThreadApi(msgRpcClient *rpcclient.Message, database controller.MsgThreadDatabase, config *config.GlobalConfig) MsgThreadApi {
+	return MsgThreadApi{Message: msgRpcClient, database: database, config: config}
+}
+
+func (m *MsgThreadApi) GetThreadMsgs(c *gin.Context) {
+	var req apistruct.GetThreadMsgsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, m.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, replies, err := m.database.GetReplies(c, req.ConversationID, req.ParentMsgID, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetThreadMsgsResp{Total: total, Msgs: make([]*sdkws.MsgData, 0, len(replies))}
+	for _, reply := range replies {
+		// Pulled as the user, so replies deleted or out of reach for them are left out.
+		msgData, err := getMsgBySeq(c, m.Message, req.UserID, req.ConversationID, reply.Seq)
+		if err != nil {
+			if errs.ErrRecordNotFound.Is(err) {
+				continue
+			}
+			apiresp.GinError(c, err)
+			return
+		}
+		resp.Msgs = append(resp.Msgs, msgData)
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+func (m *MsgThreadApi) MarkThreadAsRead(c *gin.Context) {
+	var req apistruct.MarkThreadAsReadReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, m.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := m.database.MarkThreadAsRead(c, req.ConversationID, req.ParentMsgID, req.UserID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (m *MsgThreadApi) GetThreadUnreadCount(c *gin.Context) {
+	var req apistruct.GetThreadUnreadCountReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, m.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	counts, err := m.database.GetUnreadCounts(c, req.ConversationID, req.ParentMsgIDs, req.UserID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.GetThreadUnreadCountResp{UnreadCounts: counts})
+}
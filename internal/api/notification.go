@@ -0,0 +1,92 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+// broadcastPageSize is how many followers are loaded at a time while broadcasting.
+const broadcastPageSize = 500
+
+type NotificationApi struct {
+	MessageApi
+	userRpcClient *rpcclient.UserRpcClient
+	config        *config.GlobalConfig
+}
+
+func NewNotificationApi(msgApi MessageApi, userRpcClient *rpcclient.User, config *config.GlobalConfig) NotificationApi {
+	return NotificationApi{MessageApi: msgApi, userRpcClient: rpcclient.NewUserRpcClientByUser(userRpcClient), config: config}
+}
+
+// BroadcastNotification sends the message of a notification account to each of its followers, users that
+// do not follow the account do not receive it.
+func (n *NotificationApi) BroadcastNotification(c *gin.Context) {
+	var req apistruct.BroadcastNotificationReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, n.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := n.userRpcClient.GetNotificationByID(c, req.SendID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	sendMsgReq, err := n.getSendMsgReq(c, req.SendMsg)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	var resp apistruct.BroadcastNotificationResp
+	for pageNumber := int32(1); ; pageNumber++ {
+		followers, err := n.userRpcClient.Ext.GetNotificationAccountFollowers(c, &apistruct.GetNotificationAccountFollowersReq{
+			AccountID:  req.SendID,
+			Pagination: &sdkws.RequestPagination{PageNumber: pageNumber, ShowNumber: broadcastPageSize},
+		})
+		if err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		userIDs := followers.UserIDs
+		for _, userID := range userIDs {
+			sendMsgReq.MsgData.RecvID = userID
+			rpcResp, err := n.Client.SendMsg(c, sendMsgReq)
+			if err != nil {
+				resp.FailedIDs = append(resp.FailedIDs, userID)
+				continue
+			}
+			resp.Results = append(resp.Results, &apistruct.SingleReturnResult{
+				ServerMsgID: rpcResp.ServerMsgID,
+				ClientMsgID: rpcResp.ClientMsgID,
+				SendTime:    rpcResp.SendTime,
+				RecvID:      userID,
+			})
+		}
+		if len(userIDs) < broadcastPageSize {
+			break
+		}
+	}
+	apiresp.GinSuccess(c, resp)
+}
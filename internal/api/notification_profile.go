@@ -0,0 +1,150 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/notificationcatalog"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type NotificationProfileApi struct {
+	msgRpc   *rpcclient.Message
+	database controller.NotificationProfileDatabase
+	config   *config.GlobalConfig
+}
+
+func NewNotificationProfileApi(msgRpc *rpcclient.Message, database controller.NotificationProfileDatabase, config *config.GlobalConfig) NotificationProfileApi {
+	return NotificationProfileApi{msgRpc: msgRpc, database: database, config: config}
+}
+
+func (n *NotificationProfileApi) SetNotificationProfile(c *gin.Context) {
+	var req apistruct.SetNotificationProfileReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.OwnerUserID, n.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	profile := &relation.NotificationProfileModel{
+		OwnerUserID:    req.OwnerUserID,
+		ConversationID: req.ConversationID,
+		Sound:          req.Sound,
+		Vibration:      req.Vibration,
+		Language:       req.Language,
+	}
+	if err := n.database.SetProfile(c, profile); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	n.notifyProfileChanged(c, req.OwnerUserID, req.ConversationID, false)
+	apiresp.GinSuccess(c, nil)
+}
+
+func (n *NotificationProfileApi) DeleteNotificationProfile(c *gin.Context) {
+	var req apistruct.DeleteNotificationProfileReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.OwnerUserID, n.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := n.database.DeleteProfile(c, req.OwnerUserID, req.ConversationID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	n.notifyProfileChanged(c, req.OwnerUserID, req.ConversationID, true)
+	apiresp.GinSuccess(c, nil)
+}
+
+func (n *NotificationProfileApi) SyncNotificationProfiles(c *gin.Context) {
+	var req apistruct.SyncNotificationProfilesReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.OwnerUserID, n.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	var updateTime time.Time
+	if req.UpdateTime > 0 {
+		updateTime = time.UnixMilli(req.UpdateTime)
+	}
+	profiles, err := n.database.SyncProfiles(c, req.OwnerUserID, updateTime)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.SyncNotificationProfilesResp{Profiles: make([]*apistruct.NotificationProfile, 0, len(profiles))}
+	for _, profile := range profiles {
+		resp.Profiles = append(resp.Profiles, &apistruct.NotificationProfile{
+			ConversationID: profile.ConversationID,
+			Sound:          profile.Sound,
+			Vibration:      profile.Vibration,
+			Language:       profile.Language,
+			Deleted:        profile.Deleted,
+			UpdateTime:     profile.UpdateTime.UnixMilli(),
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+// notifyProfileChanged tells the devices of ownerUserID to sync their notification profiles.
+func (n *NotificationProfileApi) notifyProfileChanged(ctx context.Context, ownerUserID string, conversationID string, deleted bool) {
+	tips := &apistruct.NotificationProfileChangedTips{OwnerUserID: ownerUserID, ConversationID: conversationID, Deleted: deleted}
+	if err := notificationcatalog.Validate(msgprocessor.NotificationProfileChangedNotification, tips); err != nil {
+		log.ZError(ctx, "invalid notification profile notification", err)
+		return
+	}
+	msgData := &sdkws.MsgData{
+		SendID:      ownerUserID,
+		RecvID:      ownerUserID,
+		Content:     []byte(utils.StructToJsonString(&sdkws.NotificationElem{Detail: utils.StructToJsonString(tips)})),
+		MsgFrom:     constant.SysMsgType,
+		ContentType: msgprocessor.NotificationProfileChangedNotification,
+		SessionType: constant.SingleChatType,
+		CreateTime:  utils.GetCurrentTimestampByMill(),
+		ClientMsgID: utils.GetMsgID(ownerUserID),
+		Options: config.GetOptionsByNotification(config.NotificationConf{
+			IsSendMsg:        false,
+			ReliabilityLevel: 1,
+			UnreadCount:      false,
+		}),
+	}
+	if _, err := n.msgRpc.Client.SendMsg(ctx, &msg.SendMsgReq{MsgData: msgData}); err != nil {
+		log.ZWarn(ctx, "send notification profile notification failed", err, "ownerUserID", ownerUserID, "conversationID", conversationID)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/protocol/sdkws"
+	pbuser "github.com/OpenIMSDK/protocol/user"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type OnboardingApi struct {
+	groupRpc *rpcclient.GroupRpcClient
+	userRpc  *rpcclient.UserRpcClient
+	database controller.OnboardingDatabase
+	config   *config.GlobalConfig
+}
+
+func NewOnboardingApi(groupRpc *rpcclient.GroupRpcClient, userRpc *rpcclient.UserRpcClient, database controller.OnboardingDatabase, config *config.GlobalConfig) OnboardingApi {
+	return OnboardingApi{groupRpc: groupRpc, userRpc: userRpc, database: database, config: config}
+}
+
+func (o *OnboardingApi) AddRule(c *gin.Context) {
+	var req apistruct.AddOnboardingRuleReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, o.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	var err error
+	if req.TargetType == relation.OnboardingTargetGroup {
+		_, err = o.groupRpc.GetGroupInfo(c, req.TargetID)
+	} else {
+		_, err = o.userRpc.GetUserInfo(c, req.TargetID)
+	}
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	rule := &relation.OnboardingRuleModel{
+		AppID:          req.AppID,
+		Region:         req.Region,
+		TargetType:     req.TargetType,
+		TargetID:       req.TargetID,
+		AutoJoin:       req.AutoJoin,
+		Priority:       req.Priority,
+		OperatorUserID: mcontext.GetOpUserID(c),
+	}
+	if err := o.database.AddRule(c, rule); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.AddOnboardingRuleResp{RuleID: rule.RuleID})
+}
+
+func (o *OnboardingApi) DeleteRules(c *gin.Context) {
+	var req apistruct.DeleteOnboardingRulesReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, o.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := o.database.DeleteRules(c, req.RuleIDs); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (o *OnboardingApi) GetRules(c *gin.Context) {
+	var req apistruct.GetOnboardingRulesReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, o.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, rules, err := o.database.PageRules(c, req.AppID, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetOnboardingRulesResp{Total: total, Rules: make([]*apistruct.OnboardingRule, 0, len(rules))}
+	for _, rule := range rules {
+		resp.Rules = append(resp.Rules, &apistruct.OnboardingRule{
+			RuleID:         rule.RuleID,
+			AppID:          rule.AppID,
+			Region:         rule.Region,
+			TargetType:     rule.TargetType,
+			TargetID:       rule.TargetID,
+			AutoJoin:       rule.AutoJoin,
+			Priority:       rule.Priority,
+			OperatorUserID: rule.OperatorUserID,
+			CreateTime:     rule.CreateTime.UnixMilli(),
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+// GetSuggestions returns the suggestions of the app of the request, the targets deleted since their rule was
+// added are left out.
+func (o *OnboardingApi) GetSuggestions(c *gin.Context) {
+	var req apistruct.GetOnboardingSuggestionsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, o.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	rules, err := o.database.Suggest(c, tenant.GetAppID(c), req.Region)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	var groupIDs, userIDs []string
+	for _, rule := range rules {
+		if rule.TargetType == relation.OnboardingTargetGroup {
+			groupIDs = append(groupIDs, rule.TargetID)
+		} else {
+			userIDs = append(userIDs, rule.TargetID)
+		}
+	}
+	groups := make(map[string]*sdkws.GroupInfo)
+	if len(groupIDs) > 0 {
+		if groups, err = o.groupRpc.GetGroupInfoMap(c, groupIDs, false); err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+	}
+	users := make(map[string]*sdkws.PublicUserInfo)
+	if len(userIDs) > 0 {
+		usersResp, err := o.userRpc.Client.GetDesignateUsers(c, &pbuser.GetDesignateUsersReq{UserIDs: userIDs})
+		if err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		for _, user := range usersResp.UsersInfo {
+			users[user.UserID] = &sdkws.PublicUserInfo{UserID: user.UserID, Nickname: user.Nickname, FaceURL: user.FaceURL, Ex: user.Ex}
+		}
+	}
+	resp := &apistruct.GetOnboardingSuggestionsResp{Suggestions: make([]*apistruct.OnboardingSuggestion, 0, len(rules))}
+	for _, rule := range rules {
+		suggestion := &apistruct.OnboardingSuggestion{TargetType: rule.TargetType, TargetID: rule.TargetID}
+		if rule.TargetType == relation.OnboardingTargetGroup {
+			if suggestion.Group = groups[rule.TargetID]; suggestion.Group == nil {
+				continue
+			}
+		} else if suggestion.User = users[rule.TargetID]; suggestion.User == nil {
+			continue
+		}
+		resp.Suggestions = append(resp.Suggestions, suggestion)
+	}
+	apiresp.GinSuccess(c, resp)
+}
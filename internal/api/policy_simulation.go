@@ -0,0 +1,169 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/search"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/policysim"
+)
+
+type PolicySimulationApi struct {
+	database controller.PolicySimulationDatabase
+	config   *config.GlobalConfig
+}
+
+func NewPolicySimulationApi(database controller.PolicySimulationDatabase, config *config.GlobalConfig) PolicySimulationApi {
+	return PolicySimulationApi{database: database, config: config}
+}
+
+// SimulatePolicy reports what a word filter, rate limit or retention rule would have done to the recent traffic.
+// The policies are evaluated on a random sample of the chat messages and user and conversation ids are replaced by
+// aliases, no message content is returned.
+func (p *PolicySimulationApi) SimulatePolicy(c *gin.Context) {
+	var req apistruct.SimulatePolicyReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, p.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if req.WordFilter == nil && req.RateLimit == nil && req.Retention == nil {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("no policy to simulate"))
+		return
+	}
+	var retentionConversation string
+	if req.Retention != nil {
+		var err error
+		retentionConversation, err = retentionConversationID(req.Retention.ConversationID, req.Retention.GroupID)
+		if err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+	}
+	hours := req.Hours
+	if maxHours := p.config.PolicySimulation.MaxHours; maxHours > 0 && hours > maxHours {
+		hours = maxHours
+	}
+	now := time.Now()
+	since := now.Add(-time.Duration(hours) * time.Hour).UnixMilli()
+	total, sampled, err := p.database.SampleMsgs(c, since, int64(p.config.PolicySimulation.SampleSize))
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		apiresp.GinError(c, errs.Wrap(err))
+		return
+	}
+	anonymizer := policysim.NewAnonymizer(hex.EncodeToString(salt))
+	msgs := make([]*policysim.Msg, 0, len(sampled))
+	var retentionAlias string
+	if retentionConversation != "" {
+		retentionAlias = anonymizer.Alias(retentionConversation)
+	}
+	for _, msg := range sampled {
+		if msg.Msg == nil {
+			continue
+		}
+		conversationID := msg.DocID
+		if i := strings.LastIndex(conversationID, ":"); i >= 0 {
+			conversationID = conversationID[:i]
+		}
+		if msgprocessor.IsNotification(conversationID) {
+			continue
+		}
+		msgs = append(msgs, &policysim.Msg{
+			ConversationID: anonymizer.Alias(conversationID),
+			SendID:         anonymizer.Alias(msg.Msg.SendID),
+			SendTime:       msg.Msg.SendTime,
+			Text:           search.Text(&sdkws.MsgData{ContentType: msg.Msg.ContentType, Content: []byte(msg.Msg.Content)}),
+		})
+	}
+	resp := &apistruct.SimulatePolicyResp{Since: since, TotalMsgs: total, SampledMsgs: int64(len(sampled))}
+	// estimate scales a count over the sample to every message sent since then.
+	estimate := func(n int64) int64 {
+		if len(sampled) == 0 {
+			return 0
+		}
+		return int64(math.Round(float64(n) * float64(total) / float64(len(sampled))))
+	}
+	if req.WordFilter != nil {
+		res := policysim.WordFilter(req.WordFilter.Words, msgs)
+		resp.WordFilter = &apistruct.WordFilterSimulation{
+			Matched:          res.Matched,
+			EstimatedMatched: estimate(res.Matched),
+			Words:            res.Words,
+			Senders:          res.Senders,
+			Conversations:    res.Conversations,
+			TopSenders:       simulatedSenders(res.TopSenders),
+		}
+	}
+	if req.RateLimit != nil {
+		// A sender shows up in the sample at the sampling ratio, so the limit is scaled by the same ratio.
+		limit := req.RateLimit.Limit
+		if total > 0 {
+			limit = int(math.Max(1, math.Round(float64(limit)*float64(len(sampled))/float64(total))))
+		}
+		res := policysim.RateLimit(limit, int64(req.RateLimit.Window)*1000, msgs)
+		resp.RateLimit = &apistruct.RateLimitSimulation{
+			SampledLimit:     limit,
+			Limited:          res.Limited,
+			EstimatedLimited: estimate(res.Limited),
+			Senders:          res.Senders,
+			TopSenders:       simulatedSenders(res.TopSenders),
+		}
+	}
+	if req.Retention != nil {
+		covered := policysim.Covered([]string{retentionAlias}, msgs)
+		deleted, err := p.database.CountMsgsBefore(c, retentionConversation, now.AddDate(0, 0, -int(req.Retention.RetainDays)).UnixMilli())
+		if err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		resp.Retention = &apistruct.RetentionSimulation{
+			ConversationID:   retentionConversation,
+			Covered:          covered,
+			EstimatedCovered: estimate(covered),
+			Deleted:          deleted,
+		}
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+func simulatedSenders(senders []*policysim.SenderCount) []*apistruct.SimulatedSender {
+	res := make([]*apistruct.SimulatedSender, 0, len(senders))
+	for _, sender := range senders {
+		res = append(res, &apistruct.SimulatedSender{Alias: sender.SendID, Count: sender.Count})
+	}
+	return res
+}
@@ -0,0 +1,135 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+)
+
+// PushConsumerApi pauses partitions of the push consumer group to shed load on purpose, e.g. while a push
+// provider is down. The push instances apply the paused partitions within seconds.
+type PushConsumerApi struct {
+	cache  cache.ConsumerPauseCache
+	config *config.GlobalConfig
+	lock   sync.Mutex
+}
+
+func NewPushConsumerApi(cache cache.ConsumerPauseCache, config *config.GlobalConfig) *PushConsumerApi {
+	return &PushConsumerApi{cache: cache, config: config}
+}
+
+func (p *PushConsumerApi) PausePushConsumer(c *gin.Context) {
+	p.update(c, func(pause *cache.ConsumerPause, req *apistruct.PausePushConsumerReq) error {
+		if req.All {
+			pause.All = true
+			return nil
+		}
+		pause.Add(req.Topic, req.Partitions)
+		return nil
+	})
+}
+
+func (p *PushConsumerApi) ResumePushConsumer(c *gin.Context) {
+	p.update(c, func(pause *cache.ConsumerPause, req *apistruct.PausePushConsumerReq) error {
+		if req.All {
+			*pause = cache.ConsumerPause{}
+			return nil
+		}
+		if pause.All {
+			return errs.ErrArgs.Wrap("every partition is paused, resume all of them")
+		}
+		pause.Remove(req.Topic, req.Partitions)
+		return nil
+	})
+}
+
+// update applies fn to the current pause, the lock only orders the updates made through this api instance.
+func (p *PushConsumerApi) update(c *gin.Context, fn func(pause *cache.ConsumerPause, req *apistruct.PausePushConsumerReq) error) {
+	var req apistruct.PausePushConsumerReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, p.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if !req.All && len(req.Partitions) == 0 {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("partitions is empty"))
+		return
+	}
+	if req.Topic == "" {
+		req.Topic = p.config.Kafka.MsgToPush.Topic
+	}
+	for _, partition := range req.Partitions {
+		if partition < 0 {
+			apiresp.GinError(c, errs.ErrArgs.Wrap("partition must not be negative"))
+			return
+		}
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	groupID := p.config.Kafka.ConsumerGroupID.MsgToPush
+	pause, err := p.cache.GetPause(c, groupID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := fn(pause, &req); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	pause.UpdateTime = time.Now().UnixMilli()
+	if err := p.cache.SetPause(c, groupID, pause); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, toPushConsumerPause(pause))
+}
+
+func (p *PushConsumerApi) GetPushConsumerPause(c *gin.Context) {
+	var req apistruct.GetPushConsumerPauseReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, p.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	pause, err := p.cache.GetPause(c, p.config.Kafka.ConsumerGroupID.MsgToPush)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, toPushConsumerPause(pause))
+}
+
+func toPushConsumerPause(pause *cache.ConsumerPause) *apistruct.PushConsumerPause {
+	resp := &apistruct.PushConsumerPause{All: pause.All, Partitions: pause.Partitions, UpdateTime: pause.UpdateTime}
+	if resp.Partitions == nil {
+		resp.Partitions = make(map[string][]int32)
+	}
+	return resp
+}
@@ -0,0 +1,88 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+)
+
+type PushRetryApi struct {
+	database controller.PushRetryDatabase
+	config   *config.GlobalConfig
+}
+
+func NewPushRetryApi(database controller.PushRetryDatabase, config *config.GlobalConfig) PushRetryApi {
+	return PushRetryApi{database: database, config: config}
+}
+
+func (p *PushRetryApi) GetPushDeadLetters(c *gin.Context) {
+	var req apistruct.GetPushDeadLettersReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, p.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, entries, err := p.database.PageDeadLetters(c, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetPushDeadLettersResp{Total: total, DeadLetters: make([]*apistruct.PushDeadLetter, 0, len(entries))}
+	for _, entry := range entries {
+		resp.DeadLetters = append(resp.DeadLetters, &apistruct.PushDeadLetter{
+			ID:             entry.ID,
+			ConversationID: entry.ConversationID,
+			UserIDs:        entry.UserIDs,
+			Title:          entry.Title,
+			Content:        entry.Content,
+			Attempts:       entry.Attempts,
+			LastErr:        entry.LastErr,
+			CreateTime:     entry.CreateTime,
+			UpdateTime:     entry.UpdateTime,
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+func (p *PushRetryApi) RequeuePushDeadLetters(c *gin.Context) {
+	var req apistruct.RequeuePushDeadLettersReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, p.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if !p.config.PushRetry.Enable {
+		apiresp.GinError(c, errs.ErrNoPermission.Wrap("push retry is disabled"))
+		return
+	}
+	requeuedIDs, err := p.database.Requeue(c, req.IDs)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.RequeuePushDeadLettersResp{RequeuedIDs: requeuedIDs})
+}
@@ -31,7 +31,7 @@ import (
 	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/mw"
-	"github.com/OpenIMSDK/tools/tokenverify"
+	"github.com/OpenIMSDK/tools/network"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
@@ -39,9 +39,16 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/local"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/storage"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
 	kdisc "github.com/openimsdk/open-im-server/v3/pkg/common/discoveryregister"
 	ginprom "github.com/openimsdk/open-im-server/v3/pkg/common/ginprometheus"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/search"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/topology"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	util "github.com/openimsdk/open-im-server/v3/pkg/util/genutil"
 	"github.com/redis/go-redis/v9"
@@ -59,6 +66,10 @@ func Start(config *config.GlobalConfig, port int, proPort int) error {
 	if err != nil {
 		return err
 	}
+	mongo, err := unrelation.NewMongo(config)
+	if err != nil {
+		return err
+	}
 
 	var client discoveryregistry.SvcDiscoveryRegistry
 
@@ -79,9 +90,21 @@ func Start(config *config.GlobalConfig, port int, proPort int) error {
 		netDone = make(chan struct{}, 1)
 		netErr  error
 	)
-	router := newGinRouter(client, rdb, config)
+	if err = tenant.Init(config); err != nil {
+		return err
+	}
+	if err = authverify.CheckStandbyPolicy(config); err != nil {
+		return err
+	}
+	router, err := NewGinRouter(client, rdb, mongo, config)
+	if err != nil {
+		return err
+	}
 	if config.Prometheus.Enable {
 		go func() {
+			if err := prommetrics.RegisterGinCusCollectors("Api"); err != nil {
+				log.ZWarn(context.Background(), "register api collectors failed", err)
+			}
 			p := ginprom.NewPrometheus("app", prommetrics.GetGinCusMetrics("Api"))
 			p.SetListenAddress(fmt.Sprintf(":%d", proPort))
 			if err = p.Use(router); err != nil && err != http.ErrServerClosed {
@@ -99,6 +122,14 @@ func Start(config *config.GlobalConfig, port int, proPort int) error {
 		address = net.JoinHostPort("0.0.0.0", strconv.Itoa(port))
 	}
 
+	registerIP, err := network.GetRpcRegisterIP(config.Rpc.RegisterIP)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	if err := topology.Report(config, topology.ComponentApi, net.JoinHostPort(registerIP, strconv.Itoa(port))); err != nil {
+		return err
+	}
+
 	server := http.Server{Addr: address, Handler: router}
 
 	go func() {
@@ -129,14 +160,15 @@ func Start(config *config.GlobalConfig, port int, proPort int) error {
 	return nil
 }
 
-func newGinRouter(disCov discoveryregistry.SvcDiscoveryRegistry, rdb redis.UniversalClient, config *config.GlobalConfig) *gin.Engine {
+// NewGinRouter builds the api router on the given discovery registry, Start serves it over http.
+func NewGinRouter(disCov discoveryregistry.SvcDiscoveryRegistry, rdb redis.UniversalClient, mongo *unrelation.Mongo, config *config.GlobalConfig) (*gin.Engine, error) {
 	disCov.AddOption(mw.GrpcClient(), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"LoadBalancingPolicy": "%s"}`, "round_robin")))
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		_ = v.RegisterValidation("required_if", RequiredIf)
 	}
-	r.Use(gin.Recovery(), mw.CorsHandler(), mw.GinParseOperationID())
+	r.Use(gin.Recovery(), mw.CorsHandler(), mw.GinParseOperationID(), GinParseAppID())
 	// init rpc client here
 	userRpc := rpcclient.NewUser(disCov, config)
 	groupRpc := rpcclient.NewGroup(disCov, config)
@@ -146,9 +178,212 @@ func newGinRouter(disCov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 	authRpc := rpcclient.NewAuth(disCov, config)
 	thirdRpc := rpcclient.NewThird(disCov, config)
 
+	businessTopicDB, err := mgo.NewBusinessTopicMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	businessTopicDatabase, err := controller.NewBusinessTopicDatabase(businessTopicDB, config)
+	if err != nil {
+		return nil, err
+	}
+	msgThreadDB, err := mgo.NewMsgThreadMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	scheduledMsgDB, err := mgo.NewScheduledMsgMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	relationStorage, err := storage.NewRelation(config, mongo)
+	if err != nil {
+		return nil, err
+	}
+	friendDB, err := relationStorage.Friend()
+	if err != nil {
+		return nil, err
+	}
+	friendRequestDB, err := relationStorage.FriendRequest()
+	if err != nil {
+		return nil, err
+	}
+	blackDB, err := relationStorage.Black()
+	if err != nil {
+		return nil, err
+	}
+	groupHistoryDB, err := mgo.NewGroupHistoryVisibilityMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	groupRoleDB, err := mgo.NewGroupRoleMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	groupInviteLinkDB, err := mgo.NewGroupInviteLinkMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	groupDirectoryDB, err := mgo.NewGroupDirectoryMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	friendLabelDB, err := mgo.NewFriendLabelMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	userPrivacyDB, err := mgo.NewUserPrivacyMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	userBlockDB, err := mgo.NewUserBlockMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	userMuteDB, err := mgo.NewUserMuteMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	groupBatchJobDB, err := mgo.NewGroupBatchJobMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	broadcastJobDB, err := mgo.NewBroadcastJobMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	msgRetentionDB, err := mgo.NewMsgRetentionMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	msgReceiptSummaryDB, err := mgo.NewMsgReceiptSummaryMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	contentPolicyDB, err := mgo.NewContentPolicyMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	legalHoldDB, err := mgo.NewLegalHoldMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	onboardingDB, err := mgo.NewOnboardingRuleMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	moderationDB, err := mgo.NewModerationMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	moderationSensitivityDB, err := mgo.NewModerationSensitivityMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	userPurgeDB, err := mgo.NewUserPurgeMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	userAccountDB, err := mgo.NewUserAccountMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	notificationProfileDB, err := mgo.NewNotificationProfileMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	meetingRoomDB, err := mgo.NewMeetingRoomMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	tenantConfigDB, err := mgo.NewTenantConfigMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	appDB, err := mgo.NewAppMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	conversationDB, err := mgo.NewConversationMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	searchBackend, err := search.NewBackend(config)
+	if err != nil {
+		return nil, err
+	}
+	groupSearchBackend, err := search.NewGroupBackend(config)
+	if err != nil {
+		return nil, err
+	}
+	msgDocModel := unrelation.NewMsgMongoDriver(mongo.GetDatabase(config.Mongo.Database), mongo.ReadPreference(unrelation.MsgReadController))
+	var seqAlloc cache.SeqAllocator
+	if config.SeqAllocator.Enable {
+		seqDB, err := mgo.NewSeqMongo(mongo.GetDatabase(config.Mongo.Database))
+		if err != nil {
+			return nil, err
+		}
+		seqAlloc = cache.NewSeqAllocator(rdb, seqDB, int64(config.SeqAllocator.SegmentSize))
+	}
+	groupHistoryDatabase := controller.NewGroupHistoryDatabase(groupHistoryDB, msgDocModel, cache.NewGroupHistoryCacheRedis(rdb, groupHistoryDB, msgDocModel, cache.GetDefaultOpt()))
+
 	u := NewUserApi(*userRpc)
 	m := NewMessageApi(messageRpc, userRpc)
+	n := NewNotificationApi(m, userRpc, config)
+	bt := NewBusinessTopicApi(businessTopicDatabase, config)
+	sm := NewScheduledMsgApi(m, controller.NewScheduledMsgDatabase(scheduledMsgDB), config)
+	bc := NewBroadcastApi(m, controller.NewBroadcastJobDatabase(broadcastJobDB), config)
+	conversationRpcClient := rpcclient.NewConversationRpcClient(disCov, config)
+	groupRpcClient := rpcclient.GroupRpcClient(*groupRpc)
+	ms := NewMsgSearchApi(m, searchBackend, &conversationRpcClient, &groupRpcClient, groupHistoryDatabase, config)
+	userRpcClient := rpcclient.UserRpcClient(*userRpc)
+	fi := NewFriendImportApi(&userRpcClient, controller.NewFriendDatabase(
+		friendDB,
+		friendRequestDB,
+		cache.NewFriendCacheRedis(rdb, friendDB, cache.GetDefaultOpt()),
+		relationStorage.Tx(),
+	), blackDB, config)
+	us := NewUndoSendApi(controller.NewUndoSendDatabase(cache.NewUndoSendCache(rdb)), config)
+	sg := NewSocialGraphApi(&fi, friendRpc, &groupRpcClient, config)
+	fl := NewFriendLabelApi(messageRpc, friendRpc, controller.NewFriendLabelDatabase(friendLabelDB), config)
+	upr := NewUserPrivacyApi(controller.NewUserPrivacyDatabase(userPrivacyDB), config)
+	ub := NewUserBlockApi(&userRpcClient, controller.NewUserBlockDatabase(userBlockDB, cache.NewUserBlockCacheRedis(rdb, userBlockDB, cache.GetDefaultOpt())), config)
+	um := NewUserMuteApi(&userRpcClient, controller.NewUserMuteDatabase(userMuteDB, cache.NewUserMuteCacheRedis(rdb, userMuteDB, cache.GetDefaultOpt())), config)
+	np := NewNotificationProfileApi(messageRpc, controller.NewNotificationProfileDatabase(notificationProfileDB), config)
+	up := NewUserPurgeApi(&userRpcClient, controller.NewUserPurgeDatabase(userPurgeDB), config)
+	mtg := NewMeetingRoomApi(messageRpc, &userRpcClient, controller.NewMeetingRoomDatabase(meetingRoomDB), config)
+	gh := NewGroupHistoryApi(&groupRpcClient, groupHistoryDatabase, config)
+	groupRoleDatabase := controller.NewGroupRoleDatabase(groupRoleDB, cache.NewGroupRoleCacheRedis(rdb, groupRoleDB, cache.GetDefaultOpt()))
+	grl := NewGroupRoleApi(&groupRpcClient, groupRoleDatabase, config)
+	gil := NewGroupInviteLinkApi(&groupRpcClient, controller.NewGroupInviteLinkDatabase(groupInviteLinkDB), groupRoleDatabase, config)
+	gd := NewGroupDirectoryApi(&groupRpcClient, controller.NewGroupDirectoryDatabase(groupDirectoryDB, groupSearchBackend), config)
+	gb := NewGroupBatchApi(controller.NewGroupBatchJobDatabase(groupBatchJobDB), config)
+	gr := NewGroupReadApi(&groupRpcClient, cache.NewMsgCacheModel(rdb, config), config)
+	mrt := NewMsgRetentionApi(controller.NewMsgRetentionDatabase(msgRetentionDB), config)
+	cp := NewContentPolicyApi(controller.NewContentPolicyDatabase(contentPolicyDB, cache.NewContentPolicyCacheRedis(rdb, contentPolicyDB, cache.GetDefaultOpt())), config)
+	lh := NewLegalHoldApi(controller.NewLegalHoldDatabase(legalHoldDB), config)
+	asp := NewAntiSpamApi(controller.NewAntiSpamDatabase(cache.NewAntiSpamCache(rdb), controller.AntiSpamLimits{}), config)
+	sgp := NewSeqGapApi(controller.NewSeqGapDatabase(msgDocModel, cache.NewMsgCacheModel(rdb, config)), config)
+	mrc := NewMsgReceiptApi(controller.NewMsgReceiptDatabase(msgReceiptSummaryDB, msgDocModel, cache.NewMsgCacheModel(rdb, config), config.ReceiptCompaction.BatchSize), config)
+	mx := NewMsgExportApi(controller.NewMsgExportDatabase(msgDocModel), &conversationRpcClient, &groupRpcClient, thirdRpc, config)
+	msgImportDatabase := controller.NewMsgImportDatabase(msgDocModel, cache.NewMsgCacheModel(rdb, config), seqAlloc, config)
+	mi := NewMsgImportApi(msgImportDatabase, &userRpcClient, &groupRpcClient, &conversationRpcClient, config)
+	cr := NewConversationRepairApi(controller.NewConversationRepairDatabase(
+		conversationDB,
+		conversationDB,
+		cache.NewConversationRedis(rdb, cache.GetDefaultOpt(), conversationDB),
+		msgDocModel,
+		cache.NewMsgCacheModel(rdb, config),
+		msgImportDatabase,
+	), config)
+	mt := NewMsgThreadApi(messageRpc, controller.NewMsgThreadDatabase(msgThreadDB), config)
+	authDatabase := controller.NewAuthDatabase(cache.NewMsgCacheModel(rdb, config), config.TokenPolicy.Expire, config)
+	lp := NewLoginPolicyApi(authDatabase, config)
+	ua := NewUserAccountApi(&userRpcClient, authRpc, controller.NewUserAccountDatabase(userAccountDB), authDatabase, config)
 	ParseToken := GinParseToken(rdb, config)
+	bulkhead := NewBulkhead(config)
+	userBulkhead := bulkhead.User()
+	r.GET("/status", NewStatusApi(disCov, rdb, mongo, config).GetStatus)
+	va := NewVersionApi(disCov, config)
+	r.GET("/version", va.GetVersion)
 	userRouterGroup := r.Group("/user")
 	{
 		userRouterGroup.POST("/user_register", u.UserRegister)
@@ -165,6 +400,25 @@ func newGinRouter(disCov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 		userRouterGroup.POST("/get_users_status", ParseToken, u.GetUserStatus)
 		userRouterGroup.POST("/get_subscribe_users_status", ParseToken, u.GetSubscribeUsersStatus)
 
+		userRouterGroup.POST("/set_privacy", ParseToken, upr.SetUserPrivacy)
+		userRouterGroup.POST("/get_privacy", ParseToken, upr.GetUserPrivacy)
+		userRouterGroup.POST("/block_users", ParseToken, ub.BlockUsers)
+		userRouterGroup.POST("/unblock_users", ParseToken, ub.UnblockUsers)
+		userRouterGroup.POST("/get_blocked_users", ParseToken, ub.GetBlockedUsers)
+		userRouterGroup.POST("/mute_users", ParseToken, um.MuteUsers)
+		userRouterGroup.POST("/unmute_users", ParseToken, um.UnmuteUsers)
+		userRouterGroup.POST("/get_muted_users", ParseToken, um.GetMutedUsers)
+
+		userRouterGroup.POST("/purge_user", ParseToken, up.PurgeUser)
+		userRouterGroup.POST("/get_user_purge_reports", ParseToken, up.GetUserPurgeReports)
+		userRouterGroup.POST("/deactivate", ParseToken, ua.DeactivateUser)
+		userRouterGroup.POST("/delete_account", ParseToken, ua.DeleteAccount)
+		userRouterGroup.POST("/reactivate", ParseToken, ua.ReactivateUser)
+		userRouterGroup.POST("/get_account_status", ParseToken, ua.GetAccountStatus)
+		userRouterGroup.POST("/get_devices", ParseToken, ua.GetDevices)
+		userRouterGroup.POST("/revoke_device", ParseToken, ua.RevokeDevice)
+		userRouterGroup.POST("/rename_device", ParseToken, ua.RenameDevice)
+
 		userRouterGroup.POST("/process_user_command_add", ParseToken, u.ProcessUserCommandAdd)
 		userRouterGroup.POST("/process_user_command_delete", ParseToken, u.ProcessUserCommandDelete)
 		userRouterGroup.POST("/process_user_command_update", ParseToken, u.ProcessUserCommandUpdate)
@@ -174,6 +428,17 @@ func newGinRouter(disCov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 		userRouterGroup.POST("/add_notification_account", ParseToken, u.AddNotificationAccount)
 		userRouterGroup.POST("/update_notification_account", ParseToken, u.UpdateNotificationAccountInfo)
 		userRouterGroup.POST("/search_notification_account", ParseToken, u.SearchNotificationAccount)
+		userRouterGroup.POST("/set_notification_account_info", ParseToken, u.SetNotificationAccountInfo)
+		userRouterGroup.POST("/get_notification_accounts_info", ParseToken, u.GetNotificationAccountsInfo)
+		userRouterGroup.POST("/set_notification_opt_out", ParseToken, u.SetNotificationOptOut)
+		userRouterGroup.POST("/get_notification_opt_out", ParseToken, u.GetNotificationOptOut)
+		userRouterGroup.POST("/set_notification_quota", ParseToken, u.SetNotificationQuota)
+		userRouterGroup.POST("/get_notification_quota", ParseToken, u.GetNotificationQuota)
+		userRouterGroup.POST("/follow_notification_account", ParseToken, u.FollowNotificationAccount)
+		userRouterGroup.POST("/unfollow_notification_account", ParseToken, u.UnfollowNotificationAccount)
+		userRouterGroup.POST("/get_notification_account_followers", ParseToken, u.GetNotificationAccountFollowers)
+		userRouterGroup.POST("/get_followed_notification_accounts", ParseToken, u.GetFollowedNotificationAccounts)
+		userRouterGroup.POST("/get_notification_account_follower_counts", ParseToken, u.GetNotificationAccountFollowerCounts)
 	}
 	// friend routing group
 	friendRouterGroup := r.Group("/friend", ParseToken)
@@ -192,13 +457,22 @@ func newGinRouter(disCov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 		friendRouterGroup.POST("/get_black_list", f.GetPaginationBlacks)
 		friendRouterGroup.POST("/remove_black", f.RemoveBlack)
 		friendRouterGroup.POST("/import_friend", f.ImportFriends)
+		friendRouterGroup.POST("/import_friends_bulk", fi.ImportFriendsBulk)
+		friendRouterGroup.POST("/export_social_graph", sg.ExportSocialGraph)
+		friendRouterGroup.POST("/import_social_graph", sg.ImportSocialGraph)
 		friendRouterGroup.POST("/is_friend", f.IsFriend)
 		friendRouterGroup.POST("/get_friend_id", f.GetFriendIDs)
 		friendRouterGroup.POST("/get_specified_friends_info", f.GetSpecifiedFriendsInfo)
 		friendRouterGroup.POST("/update_friends", f.UpdateFriends)
+		friendRouterGroup.POST("/create_label", fl.CreateFriendLabel)
+		friendRouterGroup.POST("/update_label", fl.UpdateFriendLabel)
+		friendRouterGroup.POST("/delete_label", fl.DeleteFriendLabel)
+		friendRouterGroup.POST("/set_label_friends", fl.SetFriendLabelFriends)
+		friendRouterGroup.POST("/get_labels", fl.GetFriendLabels)
+		friendRouterGroup.POST("/get_friends_by_label", fl.GetFriendsByLabel)
 	}
 	g := NewGroupApi(*groupRpc)
-	groupRouterGroup := r.Group("/group", ParseToken)
+	groupRouterGroup := r.Group("/group", ParseToken, userBulkhead, bulkhead.Pool("group", config.Api.Bulkhead.Group))
 	{
 		groupRouterGroup.POST("/create_group", g.CreateGroup)
 		groupRouterGroup.POST("/set_group_info", g.SetGroupInfo)
@@ -224,6 +498,25 @@ func newGinRouter(disCov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 		groupRouterGroup.POST("/get_group_abstract_info", g.GetGroupAbstractInfo)
 		groupRouterGroup.POST("/get_groups", g.GetGroups)
 		groupRouterGroup.POST("/get_group_member_user_id", g.GetGroupMemberUserIDs)
+		groupRouterGroup.POST("/set_group_history_visibility", gh.SetGroupHistoryVisibility)
+		groupRouterGroup.POST("/get_group_history_visibility", gh.GetGroupHistoryVisibility)
+		groupRouterGroup.POST("/set_group_role", grl.SetGroupRole)
+		groupRouterGroup.POST("/delete_group_role", grl.DeleteGroupRole)
+		groupRouterGroup.POST("/get_group_roles", grl.GetGroupRoles)
+		groupRouterGroup.POST("/set_group_member_role", grl.SetGroupMemberRole)
+		groupRouterGroup.POST("/create_group_invite_link", gil.CreateGroupInviteLink)
+		groupRouterGroup.POST("/revoke_group_invite_link", gil.RevokeGroupInviteLink)
+		groupRouterGroup.POST("/get_group_invite_links", gil.GetGroupInviteLinks)
+		groupRouterGroup.POST("/join_by_link", gil.JoinGroupByLink)
+		groupRouterGroup.POST("/set_group_discoverable", gd.SetGroupDiscoverable)
+		groupRouterGroup.POST("/get_group_discoverable", gd.GetGroupDiscoverable)
+		groupRouterGroup.POST("/search_public", gd.SearchPublicGroups)
+		groupRouterGroup.POST("/batch/create_groups", gb.BatchCreateGroups)
+		groupRouterGroup.POST("/batch/add_members", gb.BatchAddGroupMembers)
+		groupRouterGroup.POST("/batch/remove_members", gb.BatchRemoveGroupMembers)
+		groupRouterGroup.POST("/batch/apply_settings", gb.BatchApplyGroupSettings)
+		groupRouterGroup.POST("/batch/get_job", gb.GetGroupBatchJob)
+		groupRouterGroup.POST("/batch/get_jobs", gb.GetGroupBatchJobs)
 	}
 	superGroupRouterGroup := r.Group("/super_group", ParseToken)
 	{
@@ -233,11 +526,14 @@ func newGinRouter(disCov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 	// certificate
 	authRouterGroup := r.Group("/auth")
 	{
-		a := NewAuthApi(*authRpc)
+		a := NewAuthApi(*authRpc, authDatabase)
 		authRouterGroup.POST("/user_token", a.UserToken)
 		authRouterGroup.POST("/get_user_token", ParseToken, a.GetUserToken)
 		authRouterGroup.POST("/parse_token", a.ParseToken)
 		authRouterGroup.POST("/force_logout", ParseToken, a.ForceLogout)
+		authRouterGroup.GET("/public_keys", a.GetPublicKeys)
+		authRouterGroup.POST("/get_login_policy_matrix", ParseToken, lp.GetLoginPolicyMatrix)
+		authRouterGroup.POST("/set_login_policy_matrix", ParseToken, lp.SetLoginPolicyMatrix)
 	}
 	// Third service
 	thirdGroup := r.Group("/third", ParseToken)
@@ -246,13 +542,23 @@ func newGinRouter(disCov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 		thirdGroup.GET("/prometheus", t.GetPrometheus)
 		thirdGroup.POST("/fcm_update_token", t.FcmUpdateToken)
 		thirdGroup.POST("/set_app_badge", t.SetAppBadge)
+		cc := NewClientConfigApi(config)
+		thirdGroup.POST("/get_client_config", cc.GetClientConfig)
+
+		pr := NewPushRetryApi(controller.NewPushRetryDatabase(cache.NewPushRetryCache(rdb)), config)
+		thirdGroup.POST("/push/get_dead_letters", pr.GetPushDeadLetters)
+		thirdGroup.POST("/push/requeue_dead_letters", pr.RequeuePushDeadLetters)
+		pc := NewPushConsumerApi(cache.NewConsumerPauseCache(rdb), config)
+		thirdGroup.POST("/push/pause_consumer", pc.PausePushConsumer)
+		thirdGroup.POST("/push/resume_consumer", pc.ResumePushConsumer)
+		thirdGroup.POST("/push/get_consumer_pause", pc.GetPushConsumerPause)
 
 		logs := thirdGroup.Group("/logs")
 		logs.POST("/upload", t.UploadLogs)
 		logs.POST("/delete", t.DeleteLogs)
 		logs.POST("/search", t.SearchLogs)
 
-		objectGroup := r.Group("/object", ParseToken)
+		objectGroup := r.Group("/object", ParseToken, userBulkhead, bulkhead.Pool("object", config.Api.Bulkhead.Object))
 
 		objectGroup.POST("/part_limit", t.PartLimit)
 		objectGroup.POST("/part_size", t.PartSize)
@@ -263,20 +569,65 @@ func newGinRouter(disCov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 		objectGroup.POST("/initiate_form_data", t.InitiateFormData)
 		objectGroup.POST("/complete_form_data", t.CompleteFormData)
 		objectGroup.GET("/*name", t.ObjectRedirect)
+		if config.Object.Enable == "local" {
+			// The urls signed by the local engine carry no token.
+			localObject, err := local.NewHandler(local.Config(config.Object.Local), config.Object.ApiURL, config.Secret)
+			if err != nil {
+				return nil, err
+			}
+			r.Any(local.Path+"*name", gin.WrapH(localObject))
+		}
 	}
 	// Message
-	msgGroup := r.Group("/msg", ParseToken)
+	msgGroup := r.Group("/msg", ParseToken, userBulkhead, bulkhead.Pool("msg", config.Api.Bulkhead.Msg))
 	{
 		msgGroup.POST("/newest_seq", m.GetSeq)
-		msgGroup.POST("/search_msg", m.SearchMsg)
+		msgGroup.POST("/search_msg", ms.SearchMsg)
 		msgGroup.POST("/send_msg", m.SendMessage)
+		msgGroup.POST("/cancel_send", us.CancelSend)
 		msgGroup.POST("/send_business_notification", m.SendBusinessNotification)
+		msgGroup.POST("/publish_business_notification", bt.PublishBusinessNotification)
+		msgGroup.POST("/subscribe_business_topic", bt.SubscribeBusinessTopic)
+		msgGroup.POST("/unsubscribe_business_topic", bt.UnsubscribeBusinessTopic)
+		msgGroup.POST("/get_subscribed_business_topics", bt.GetSubscribedBusinessTopics)
 		msgGroup.POST("/pull_msg_by_seq", m.PullMsgBySeqs)
 		msgGroup.POST("/revoke_msg", m.RevokeMsg)
+		msgGroup.POST("/edit_msg", m.EditMsg)
+		msgGroup.POST("/get_msg_edit_history", m.GetMsgEditHistory)
+		msgGroup.POST("/set_msg_reaction", m.SetMsgReaction)
+		msgGroup.POST("/remove_msg_reaction", m.RemoveMsgReaction)
+		msgGroup.POST("/get_msg_reactions", m.GetMsgReactions)
+		msgGroup.POST("/get_thread_msgs", mt.GetThreadMsgs)
+		msgGroup.POST("/mark_thread_as_read", mt.MarkThreadAsRead)
+		msgGroup.POST("/get_thread_unread_count", mt.GetThreadUnreadCount)
+		msgGroup.POST("/schedule_msg", sm.ScheduleMsg)
+		msgGroup.POST("/cancel_scheduled_msg", sm.CancelScheduledMsg)
+		msgGroup.POST("/get_scheduled_msgs", sm.GetScheduledMsgs)
+		msgGroup.POST("/set_msg_retention", mrt.SetMsgRetention)
+		msgGroup.POST("/delete_msg_retention", mrt.DeleteMsgRetention)
+		msgGroup.POST("/get_msg_retentions", mrt.GetMsgRetentions)
+		msgGroup.POST("/set_content_policy", cp.SetContentPolicy)
+		msgGroup.POST("/delete_content_policy", cp.DeleteContentPolicy)
+		msgGroup.POST("/get_content_policy", cp.GetContentPolicy)
+		msgGroup.POST("/get_content_policies", cp.GetContentPolicies)
+		msgGroup.POST("/place_legal_hold", lh.PlaceLegalHold)
+		msgGroup.POST("/release_legal_hold", lh.ReleaseLegalHold)
+		msgGroup.POST("/get_legal_holds", lh.GetLegalHolds)
+		msgGroup.POST("/get_legal_hold_logs", lh.GetLegalHoldLogs)
+		msgGroup.POST("/restrict_user", asp.RestrictUser)
+		msgGroup.POST("/unrestrict_user", asp.UnrestrictUser)
+		msgGroup.POST("/get_user_restriction", asp.GetUserRestriction)
+		msgGroup.POST("/find_seq_gaps", sgp.FindSeqGaps)
+		msgGroup.POST("/repair_seq_gaps", sgp.RepairSeqGaps)
+		msgGroup.POST("/get_receipt_summaries", mrc.GetReceiptSummaries)
 		msgGroup.POST("/mark_msgs_as_read", m.MarkMsgsAsRead)
 		msgGroup.POST("/mark_conversation_as_read", m.MarkConversationAsRead)
 		msgGroup.POST("/get_conversations_has_read_and_max_seq", m.GetConversationsHasReadAndMaxSeq)
 		msgGroup.POST("/set_conversation_has_read_seq", m.SetConversationHasReadSeq)
+		msgGroup.POST("/get_group_read_members", gr.GetGroupReadMembers)
+		msgGroup.POST("/export_conversation", mx.ExportConversationMsgs)
+		msgGroup.POST("/import_msgs", mi.ImportMsgs)
+		msgGroup.POST("/delete_msgs_physical", m.DeleteMsgsPhysical)
 
 		msgGroup.POST("/clear_conversation_msg", m.ClearConversationsMsg)
 		msgGroup.POST("/user_clear_all_msg", m.UserClearAllMsg)
@@ -285,8 +636,14 @@ func newGinRouter(disCov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 		msgGroup.POST("/delete_msg_physical", m.DeleteMsgPhysical)
 
 		msgGroup.POST("/batch_send_msg", m.BatchSendMsg)
+		msgGroup.POST("/broadcast_notification", n.BroadcastNotification)
+		msgGroup.POST("/broadcast", bc.BroadcastMsg)
+		msgGroup.POST("/get_broadcast_job", bc.GetBroadcastJob)
+		msgGroup.POST("/get_broadcast_jobs", bc.GetBroadcastJobs)
+		msgGroup.POST("/cancel_broadcast", bc.CancelBroadcast)
 		msgGroup.POST("/check_msg_is_send_success", m.CheckMsgIsSendSuccess)
 		msgGroup.POST("/get_server_time", m.GetServerTime)
+		msgGroup.POST("/get_notification_catalog", GetNotificationCatalog)
 	}
 	// Conversation
 	conversationGroup := r.Group("/conversation", ParseToken)
@@ -298,6 +655,80 @@ func newGinRouter(disCov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 		conversationGroup.POST("/get_conversations", c.GetConversations)
 		conversationGroup.POST("/set_conversations", c.SetConversations)
 		conversationGroup.POST("/get_conversation_offline_push_user_ids", c.GetConversationOfflinePushUserIDs)
+		conversationGroup.POST("/set_notification_profile", np.SetNotificationProfile)
+		conversationGroup.POST("/delete_notification_profile", np.DeleteNotificationProfile)
+		conversationGroup.POST("/sync_notification_profiles", np.SyncNotificationProfiles)
+		conversationGroup.POST("/merge_duplicates", cr.MergeConversationDuplicates)
+	}
+
+	rtcGroup := r.Group("/rtc", ParseToken)
+	{
+		rc := NewRtcApi(config)
+		rtcGroup.POST("/get_ice_servers", rc.GetIceServers)
+	}
+
+	meetingGroup := r.Group("/meeting", ParseToken)
+	{
+		meetingGroup.POST("/create_room", mtg.CreateMeetingRoom)
+		meetingGroup.POST("/update_room", mtg.UpdateMeetingRoom)
+		meetingGroup.POST("/cancel_room", mtg.CancelMeetingRoom)
+		meetingGroup.POST("/end_room", mtg.EndMeetingRoom)
+		meetingGroup.POST("/get_room", mtg.GetMeetingRoom)
+		meetingGroup.POST("/get_upcoming_rooms", mtg.GetUpcomingMeetingRooms)
+		meetingGroup.POST("/join_room", mtg.JoinMeetingRoom)
+	}
+
+	handoffGroup := r.Group("/handoff", ParseToken)
+	{
+		ho := NewHandoffApi(&conversationRpcClient, controller.NewHandoffDatabase(cache.NewHandoffCache(rdb), config.Handoff.AuditSize), config)
+		handoffGroup.POST("/create_link", ho.CreateHandoffLink)
+		handoffGroup.POST("/redeem", ho.RedeemHandoffLink)
+		handoffGroup.POST("/get_audit", ho.GetHandoffAudit)
+	}
+
+	tenantGroup := r.Group("/tenant", ParseToken)
+	{
+		tc := NewTenantConfigApi(controller.NewTenantConfigDatabase(tenantConfigDB), config)
+		tenantGroup.POST("/set_config", tc.SetTenantConfig)
+		tenantGroup.POST("/delete_config", tc.DeleteTenantConfig)
+		tenantGroup.POST("/get_configs", tc.GetTenantConfigs)
+		ap := NewAppApi(controller.NewAppDatabase(appDB), config)
+		tenantGroup.POST("/create_app", ap.CreateApp)
+		tenantGroup.POST("/reset_app_secret", ap.ResetAppSecret)
+		tenantGroup.POST("/set_app_managers", ap.SetAppManagers)
+		tenantGroup.POST("/get_apps", ap.GetApps)
+	}
+
+	adminGroup := r.Group("/admin", ParseToken)
+	{
+		ll := NewLogLevelApi(disCov, config)
+		adminGroup.POST("/log_level", ll.SetLogLevel)
+		adminGroup.POST("/get_log_level", ll.GetLogLevel)
+		ps := NewPolicySimulationApi(controller.NewPolicySimulationDatabase(msgDocModel), config)
+		adminGroup.POST("/simulate_policy", ps.SimulatePolicy)
+	}
+
+	moderationGroup := r.Group("/moderation", ParseToken)
+	{
+		moderationSensitivityDatabase := controller.NewModerationSensitivityDatabase(moderationSensitivityDB, cache.NewModerationSensitivityCacheRedis(rdb, moderationSensitivityDB, cache.GetDefaultOpt()))
+		md := NewModerationApi(controller.NewModerationDatabase(moderationDB, 0), moderationSensitivityDatabase, &groupRpcClient, config)
+		moderationGroup.POST("/add_rule", md.AddRule)
+		moderationGroup.POST("/delete_rules", md.DeleteRules)
+		moderationGroup.POST("/get_rules", md.GetRules)
+		moderationGroup.POST("/get_flags", md.GetFlags)
+		moderationGroup.POST("/set_sensitivity", md.SetSensitivity)
+		moderationGroup.POST("/delete_sensitivity", md.DeleteSensitivity)
+		moderationGroup.POST("/get_sensitivity", md.GetSensitivity)
+		moderationGroup.POST("/get_sensitivities", md.GetSensitivities)
+	}
+
+	onboardingGroup := r.Group("/onboarding", ParseToken)
+	{
+		ob := NewOnboardingApi(&groupRpcClient, &userRpcClient, controller.NewOnboardingDatabase(onboardingDB), config)
+		onboardingGroup.POST("/add_rule", ob.AddRule)
+		onboardingGroup.POST("/delete_rules", ob.DeleteRules)
+		onboardingGroup.POST("/get_rules", ob.GetRules)
+		onboardingGroup.POST("/get_suggestions", ob.GetSuggestions)
 	}
 
 	statisticsGroup := r.Group("/statistics", ParseToken)
@@ -306,14 +737,16 @@ func newGinRouter(disCov discoveryregistry.SvcDiscoveryRegistry, rdb redis.Unive
 		statisticsGroup.POST("/user/active", m.GetActiveUser)
 		statisticsGroup.POST("/group/create", g.GroupCreateCount)
 		statisticsGroup.POST("/group/active", m.GetActiveGroup)
+		statisticsGroup.POST("/system_overview", NewSystemOverviewApi(rdb, mongo, config).GetSystemOverview)
+		statisticsGroup.POST("/topology", NewTopologyApi(disCov, rdb, config).GetTopology)
+		statisticsGroup.POST("/versions", va.GetVersions)
 	}
-	return r
+	return r, nil
 }
 
 func GinParseToken(rdb redis.UniversalClient, config *config.GlobalConfig) gin.HandlerFunc {
 	dataBase := controller.NewAuthDatabase(
 		cache.NewMsgCacheModel(rdb, config),
-		config.Secret,
 		config.TokenPolicy.Expire,
 		config,
 	)
@@ -327,17 +760,33 @@ func GinParseToken(rdb redis.UniversalClient, config *config.GlobalConfig) gin.H
 				c.Abort()
 				return
 			}
-			claims, err := tokenverify.GetClaimFromToken(token, authverify.Secret(config.Secret))
+			claims, err := authverify.ParseClaims(token, config)
 			if err != nil {
 				log.ZWarn(c, "jwt get token error", errs.ErrTokenUnknown.Wrap())
 				apiresp.GinError(c, errs.ErrTokenUnknown.Wrap())
 				c.Abort()
 				return
 			}
+			// A token is only valid for the app it was issued for, requests without the appID header act for it.
+			// The admins of the cluster may act for any app.
+			if appID := c.GetHeader(tenant.AppIDKey); appID != "" && appID != claims.AppID &&
+				!(claims.AppID == "" && authverify.IsManagerUserID(claims.UserID, config)) {
+				apiresp.GinError(c, errs.ErrTokenInvalid.Wrap("token was issued for another app"))
+				c.Abort()
+				return
+			} else if appID == "" && claims.AppID != "" {
+				setAppID(c, claims.AppID)
+			}
 			m, err := dataBase.GetTokensWithoutError(c, claims.UserID, claims.PlatformID)
 			if err != nil {
-				apiresp.GinError(c, errs.ErrTokenNotExist.Wrap())
-				c.Abort()
+				if authverify.StandbyVerify(c, token, err, config) != nil {
+					apiresp.GinError(c, errs.ErrTokenNotExist.Wrap())
+					c.Abort()
+					return
+				}
+				c.Set(constant.OpUserPlatform, constant.PlatformIDToName(claims.PlatformID))
+				c.Set(constant.OpUserID, claims.UserID)
+				c.Next()
 				return
 			}
 			if len(m) == 0 {
@@ -362,6 +811,12 @@ func GinParseToken(rdb redis.UniversalClient, config *config.GlobalConfig) gin.H
 				c.Abort()
 				return
 			}
+			if err := dataBase.CheckFingerprint(c, token, c.GetHeader(authverify.DeviceFingerprint)); err != nil &&
+				authverify.StandbyVerify(c, token, err, config) != nil {
+				apiresp.GinError(c, err)
+				c.Abort()
+				return
+			}
 			c.Set(constant.OpUserPlatform, constant.PlatformIDToName(claims.PlatformID))
 			c.Set(constant.OpUserID, claims.UserID)
 			c.Next()
@@ -0,0 +1,64 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/rtc"
+)
+
+type RtcApi struct {
+	config *config.GlobalConfig
+}
+
+func NewRtcApi(config *config.GlobalConfig) RtcApi {
+	return RtcApi{config: config}
+}
+
+// GetIceServers returns the STUN servers and TURN servers with a credential of the token user,
+// the credential is checked by coturn against the shared secret until it expires.
+func (r *RtcApi) GetIceServers(c *gin.Context) {
+	conf := r.config.Rtc
+	if !conf.Enable {
+		apiresp.GinError(c, errs.ErrNoPermission.Wrap("rtc is disabled"))
+		return
+	}
+	if conf.TTL <= 0 {
+		apiresp.GinError(c, errs.ErrInternalServer.Wrap("rtc ttl must be positive"))
+		return
+	}
+	ttl := time.Duration(conf.TTL) * time.Second
+	expire := time.Now().Add(ttl)
+	resp := &apistruct.GetIceServersResp{TTL: int64(conf.TTL), ExpireTime: expire.UnixMilli()}
+	if len(conf.StunURIs) > 0 {
+		resp.IceServers = append(resp.IceServers, &apistruct.IceServer{URLs: conf.StunURIs})
+	}
+	if len(conf.TurnURIs) > 0 {
+		username, credential := rtc.TurnCredential(conf.TurnSecret, mcontext.GetOpUserID(c), expire)
+		resp.IceServers = append(resp.IceServers, &apistruct.IceServer{
+			URLs:       conf.TurnURIs,
+			Username:   username,
+			Credential: credential,
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
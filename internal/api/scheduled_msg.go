@@ -0,0 +1,141 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"google.golang.org/protobuf/proto"
+)
+
+type ScheduledMsgApi struct {
+	MessageApi
+	database controller.ScheduledMsgDatabase
+	config   *config.GlobalConfig
+}
+
+func NewScheduledMsgApi(msgApi MessageApi, database controller.ScheduledMsgDatabase, config *config.GlobalConfig) ScheduledMsgApi {
+	return ScheduledMsgApi{MessageApi: msgApi, database: database, config: config}
+}
+
+func (s *ScheduledMsgApi) ScheduleMsg(c *gin.Context) {
+	var req apistruct.ScheduleMsgReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if !s.config.ScheduledMsg.Enable {
+		apiresp.GinError(c, errs.ErrNoPermission.Wrap("scheduled msg is disabled"))
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.SendID, s.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	scheduleTime := time.UnixMilli(req.ScheduleTime)
+	now := time.Now()
+	if !scheduleTime.After(now) {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("scheduleTime must be in the future"))
+		return
+	}
+	if maxAhead := s.config.ScheduledMsg.MaxAhead; maxAhead > 0 && scheduleTime.After(now.Add(time.Duration(maxAhead)*time.Second)) {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("scheduleTime is too far ahead"))
+		return
+	}
+	sendMsgReq, err := s.getSendMsgReq(c, req.SendMsg)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	sendMsgReq.MsgData.RecvID = req.RecvID
+	if !authverify.IsAppManagerUid(c, s.config) {
+		sendMsgReq.MsgData.MsgFrom = constant.UserMsgType
+	}
+	scheduleID, err := s.database.Schedule(c, sendMsgReq.MsgData, scheduleTime)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.ScheduleMsgResp{ScheduleID: scheduleID})
+}
+
+func (s *ScheduledMsgApi) CancelScheduledMsg(c *gin.Context) {
+	var req apistruct.CancelScheduledMsgReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, s.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	scheduledMsg, err := s.database.TakeScheduledMsg(c, req.ScheduleID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if scheduledMsg.SendID != req.UserID {
+		apiresp.GinError(c, errs.ErrNoPermission.Wrap("scheduled msg does not belong to the user"))
+		return
+	}
+	if err := s.database.Cancel(c, req.ScheduleID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (s *ScheduledMsgApi) GetScheduledMsgs(c *gin.Context) {
+	var req apistruct.GetScheduledMsgsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, s.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, scheduledMsgs, err := s.database.GetScheduledMsgs(c, req.UserID, req.Status, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetScheduledMsgsResp{Total: total, ScheduledMsgs: make([]*apistruct.ScheduledMsg, 0, len(scheduledMsgs))}
+	for _, scheduledMsg := range scheduledMsgs {
+		var msgData sdkws.MsgData
+		if err := proto.Unmarshal(scheduledMsg.MsgData, &msgData); err != nil {
+			apiresp.GinError(c, errs.Wrap(err))
+			return
+		}
+		resp.ScheduledMsgs = append(resp.ScheduledMsgs, &apistruct.ScheduledMsg{
+			ScheduleID:   scheduledMsg.ScheduleID,
+			ScheduleTime: scheduledMsg.ScheduleTime.UnixMilli(),
+			Status:       scheduledMsg.Status,
+			Err:          scheduledMsg.Err,
+			CreateTime:   scheduledMsg.CreateTime.UnixMilli(),
+			MsgData:      &msgData,
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
@@ -0,0 +1,102 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+)
+
+type SeqGapApi struct {
+	database controller.SeqGapDatabase
+	config   *config.GlobalConfig
+}
+
+func NewSeqGapApi(database controller.SeqGapDatabase, config *config.GlobalConfig) SeqGapApi {
+	return SeqGapApi{database: database, config: config}
+}
+
+func (s *SeqGapApi) FindSeqGaps(c *gin.Context) {
+	var req apistruct.FindSeqGapsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, s.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	report, err := s.database.FindGaps(c, req.ConversationID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, seqGapsResp(report, 0))
+}
+
+func (s *SeqGapApi) RepairSeqGaps(c *gin.Context) {
+	var req apistruct.RepairSeqGapsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, s.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	report, err := s.database.FindGaps(c, req.ConversationID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	var repaired int64
+	switch req.Mode {
+	case controller.SeqGapRepairPlaceholder:
+		repaired, err = s.database.FillGaps(c, req.ConversationID, report.Gaps)
+	case controller.SeqGapRepairCompact:
+		var minSeq int64
+		minSeq, err = s.database.Compact(c, req.ConversationID, report)
+		repaired = minSeq - report.MinSeq
+	}
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	report, err = s.database.FindGaps(c, req.ConversationID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, seqGapsResp(report, repaired))
+}
+
+func seqGapsResp(report *controller.SeqGapReport, repaired int64) *apistruct.SeqGapsResp {
+	resp := &apistruct.SeqGapsResp{
+		MinSeq:   report.MinSeq,
+		MaxSeq:   report.MaxSeq,
+		Missing:  report.Missing,
+		Gaps:     make([]*apistruct.SeqGap, 0, len(report.Gaps)),
+		Repaired: repaired,
+	}
+	for _, gap := range report.Gaps {
+		resp.Gaps = append(resp.Gaps, &apistruct.SeqGap{Begin: gap.Begin, End: gap.End})
+	}
+	return resp
+}
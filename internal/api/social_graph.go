@@ -0,0 +1,357 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/friend"
+	"github.com/OpenIMSDK/protocol/group"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/protocol/user"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"github.com/openimsdk/open-im-server/v3/pkg/socialgraph"
+)
+
+const (
+	socialGraphPageSize = 500
+	// socialGraphImportMaxNum bounds the friends and the groups of one import.
+	socialGraphImportMaxNum = 5000
+)
+
+// SocialGraphApi exports the friends and groups of a user so that the user can move them to another deployment.
+type SocialGraphApi struct {
+	friendImport *FriendImportApi
+	friendRpc    *rpcclient.Friend
+	groupRpc     *rpcclient.GroupRpcClient
+	config       *config.GlobalConfig
+}
+
+func NewSocialGraphApi(friendImport *FriendImportApi, friendRpc *rpcclient.Friend, groupRpc *rpcclient.GroupRpcClient, config *config.GlobalConfig) SocialGraphApi {
+	return SocialGraphApi{friendImport: friendImport, friendRpc: friendRpc, groupRpc: groupRpc, config: config}
+}
+
+func (s *SocialGraphApi) ExportSocialGraph(c *gin.Context) {
+	var req apistruct.ExportSocialGraphReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if req.UserID == "" {
+		req.UserID = mcontext.GetOpUserID(c)
+	}
+	if req.Format == "" {
+		req.Format = socialgraph.FormatJSON
+	}
+	if req.Format != socialgraph.FormatJSON && req.Format != socialgraph.FormatVCard {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("format must be json or vcard"))
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, s.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	graph := &socialgraph.Graph{Version: socialgraph.Version, UserID: req.UserID, ExportTime: time.Now().UnixMilli()}
+	var err error
+	if graph.Friends, err = s.exportFriends(c, req.UserID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if graph.Groups, err = s.exportGroups(c, req.UserID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if req.Format == socialgraph.FormatVCard {
+		c.Data(http.StatusOK, "text/vcard; charset=utf-8", []byte(socialgraph.EncodeVCard(graph)))
+		return
+	}
+	apiresp.GinSuccess(c, graph)
+}
+
+func (s *SocialGraphApi) exportFriends(ctx context.Context, userID string) ([]*socialgraph.Friend, error) {
+	friends := []*socialgraph.Friend{}
+	for pageNumber := int32(1); ; pageNumber++ {
+		_, page, err := s.friendImport.database.PageOwnerFriends(ctx, userID, &sdkws.RequestPagination{PageNumber: pageNumber, ShowNumber: socialGraphPageSize})
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			return friends, nil
+		}
+		friendUserIDs := utils.Slice(page, func(e *relation.FriendModel) string { return e.FriendUserID })
+		users, err := s.friendImport.userRpc.Client.GetDesignateUsers(ctx, &user.GetDesignateUsersReq{UserIDs: friendUserIDs})
+		if err != nil {
+			return nil, err
+		}
+		nicknames := make(map[string]string, len(users.UsersInfo))
+		for _, userInfo := range users.UsersInfo {
+			nicknames[userInfo.UserID] = userInfo.Nickname
+		}
+		for _, f := range page {
+			friends = append(friends, &socialgraph.Friend{
+				UserID:   f.FriendUserID,
+				Nickname: nicknames[f.FriendUserID],
+				Remark:   f.Remark,
+				AddTime:  f.CreateTime.UnixMilli(),
+			})
+		}
+		if len(page) < socialGraphPageSize {
+			return friends, nil
+		}
+	}
+}
+
+func (s *SocialGraphApi) exportGroups(ctx context.Context, userID string) ([]*socialgraph.Group, error) {
+	groups := []*socialgraph.Group{}
+	for pageNumber := int32(1); ; pageNumber++ {
+		resp, err := s.groupRpc.Client.GetJoinedGroupList(ctx, &group.GetJoinedGroupListReq{
+			FromUserID: userID,
+			Pagination: &sdkws.RequestPagination{PageNumber: pageNumber, ShowNumber: socialGraphPageSize},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Groups) == 0 {
+			return groups, nil
+		}
+		groupIDs := utils.Slice(resp.Groups, func(e *sdkws.GroupInfo) string { return e.GroupID })
+		members, err := s.groupRpc.Client.GetUserInGroupMembers(ctx, &group.GetUserInGroupMembersReq{UserID: userID, GroupIDs: groupIDs})
+		if err != nil {
+			return nil, err
+		}
+		memberMap := utils.SliceToMap(members.Members, func(e *sdkws.GroupMemberFullInfo) string { return e.GroupID })
+		for _, info := range resp.Groups {
+			g := &socialgraph.Group{GroupID: info.GroupID, GroupName: info.GroupName, Role: socialgraph.RoleMember}
+			if member, ok := memberMap[info.GroupID]; ok {
+				g.Role = socialGraphRole(member.RoleLevel)
+				g.JoinTime = member.JoinTime
+			}
+			groups = append(groups, g)
+		}
+		if len(resp.Groups) < socialGraphPageSize {
+			return groups, nil
+		}
+	}
+}
+
+func socialGraphRole(roleLevel int32) string {
+	switch roleLevel {
+	case constant.GroupOwner:
+		return socialgraph.RoleOwner
+	case constant.GroupAdmin:
+		return socialgraph.RoleAdmin
+	default:
+		return socialgraph.RoleMember
+	}
+}
+
+// ImportSocialGraph restores the friends and groups of a graph that exist in this deployment. Roles are not
+// restored, they are granted by the owners of the groups here.
+func (s *SocialGraphApi) ImportSocialGraph(c *gin.Context) {
+	var req apistruct.ImportSocialGraphReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if req.UserID == "" {
+		req.UserID = mcontext.GetOpUserID(c)
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, s.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	graph := req.Graph
+	switch req.Format {
+	case "", socialgraph.FormatJSON:
+		if graph == nil {
+			apiresp.GinError(c, errs.ErrArgs.Wrap("graph is empty"))
+			return
+		}
+	case socialgraph.FormatVCard:
+		var err error
+		if graph, err = socialgraph.DecodeVCard(req.Data); err != nil {
+			apiresp.GinError(c, errs.ErrArgs.Wrap(err.Error()))
+			return
+		}
+	default:
+		apiresp.GinError(c, errs.ErrArgs.Wrap("format must be json or vcard"))
+		return
+	}
+	if len(graph.Friends) > socialGraphImportMaxNum || len(graph.Groups) > socialGraphImportMaxNum {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("too many friends or groups"))
+		return
+	}
+	// App managers migrate users without asking the other side, users go through the usual requests.
+	direct := authverify.IsAppManagerUid(c, s.config)
+	friends, err := s.importFriends(c, req.UserID, graph.Friends, req.ReqMessage, direct)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	groups, err := s.importGroups(c, req.UserID, graph.Groups, req.ReqMessage, direct)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.ImportSocialGraphResp{Friends: friends, Groups: groups})
+}
+
+func (s *SocialGraphApi) importFriends(ctx context.Context, userID string, friends []*socialgraph.Friend, reqMessage string, direct bool) (*apistruct.ImportSocialGraphFriends, error) {
+	res := &apistruct.ImportSocialGraphFriends{
+		Imported:  []string{},
+		Requested: []string{},
+		Failed:    []string{},
+	}
+	remarks := make(map[string]string, len(friends))
+	friendUserIDs := make([]string, 0, len(friends))
+	for _, f := range friends {
+		if f.UserID == "" || f.UserID == userID {
+			continue
+		}
+		if _, ok := remarks[f.UserID]; !ok {
+			friendUserIDs = append(friendUserIDs, f.UserID)
+		}
+		remarks[f.UserID] = f.Remark
+	}
+	ownerFriendIDs, err := s.friendImport.database.FindFriendUserIDs(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ownerFriends := make(map[string]struct{}, len(ownerFriendIDs))
+	for _, friendUserID := range ownerFriendIDs {
+		ownerFriends[friendUserID] = struct{}{}
+	}
+	conflicts := &apistruct.ImportFriendsBulkResp{AlreadyFriends: []string{}, Blocked: []string{}, NotExist: []string{}}
+	var importable []string
+	for start := 0; start < len(friendUserIDs); start += importFriendsDefaultBatchSize {
+		end := start + importFriendsDefaultBatchSize
+		if end > len(friendUserIDs) {
+			end = len(friendUserIDs)
+		}
+		batch, err := s.friendImport.checkImportBatch(ctx, userID, friendUserIDs[start:end], ownerFriends, conflicts)
+		if err != nil {
+			return nil, err
+		}
+		importable = append(importable, batch...)
+	}
+	res.AlreadyFriends, res.Blocked, res.NotExist = conflicts.AlreadyFriends, conflicts.Blocked, conflicts.NotExist
+	if direct {
+		if len(importable) > 0 {
+			if err := s.friendImport.database.ImportFriends(ctx, userID, importable, true, constant.BecomeFriendByImport); err != nil {
+				return nil, err
+			}
+		}
+		for _, friendUserID := range importable {
+			if remark := remarks[friendUserID]; remark != "" {
+				if err := s.friendImport.database.UpdateRemark(ctx, userID, friendUserID, remark); err != nil {
+					log.ZWarn(ctx, "restore friend remark failed", err, "userID", userID, "friendUserID", friendUserID)
+				}
+			}
+		}
+		res.Imported = append(res.Imported, importable...)
+		return res, nil
+	}
+	for _, friendUserID := range importable {
+		_, err := s.friendRpc.Client.ApplyToAddFriend(ctx, &friend.ApplyToAddFriendReq{
+			FromUserID: userID,
+			ToUserID:   friendUserID,
+			ReqMsg:     reqMessage,
+		})
+		if err != nil {
+			log.ZWarn(ctx, "social graph friend request failed", err, "userID", userID, "friendUserID", friendUserID)
+			res.Failed = append(res.Failed, friendUserID)
+			continue
+		}
+		res.Requested = append(res.Requested, friendUserID)
+	}
+	return res, nil
+}
+
+func (s *SocialGraphApi) importGroups(ctx context.Context, userID string, groups []*socialgraph.Group, reqMessage string, direct bool) (*apistruct.ImportSocialGraphGroups, error) {
+	res := &apistruct.ImportSocialGraphGroups{
+		Joined:        []string{},
+		Requested:     []string{},
+		AlreadyJoined: []string{},
+		NotExist:      []string{},
+		Failed:        []string{},
+	}
+	groupIDs := utils.Distinct(utils.Slice(groups, func(e *socialgraph.Group) string { return e.GroupID }))
+	for start := 0; start < len(groupIDs); start += socialGraphPageSize {
+		end := start + socialGraphPageSize
+		if end > len(groupIDs) {
+			end = len(groupIDs)
+		}
+		batch := groupIDs[start:end]
+		infos, err := s.groupRpc.GetGroupInfos(ctx, batch, false)
+		if err != nil {
+			return nil, err
+		}
+		exist := make(map[string]struct{}, len(infos))
+		for _, info := range infos {
+			if info.Status != constant.GroupStatusDismissed {
+				exist[info.GroupID] = struct{}{}
+			}
+		}
+		members, err := s.groupRpc.Client.GetUserInGroupMembers(ctx, &group.GetUserInGroupMembersReq{UserID: userID, GroupIDs: batch})
+		if err != nil {
+			return nil, err
+		}
+		joined := utils.SliceToMap(members.Members, func(e *sdkws.GroupMemberFullInfo) string { return e.GroupID })
+		for _, groupID := range batch {
+			if _, ok := exist[groupID]; !ok {
+				res.NotExist = append(res.NotExist, groupID)
+				continue
+			}
+			if _, ok := joined[groupID]; ok {
+				res.AlreadyJoined = append(res.AlreadyJoined, groupID)
+				continue
+			}
+			if direct {
+				_, err = s.groupRpc.Client.InviteUserToGroup(ctx, &group.InviteUserToGroupReq{GroupID: groupID, InvitedUserIDs: []string{userID}})
+			} else {
+				_, err = s.groupRpc.Client.JoinGroup(ctx, &group.JoinGroupReq{
+					GroupID:       groupID,
+					ReqMessage:    reqMessage,
+					JoinSource:    constant.JoinBySearch,
+					InviterUserID: userID,
+				})
+			}
+			if err != nil {
+				log.ZWarn(ctx, "social graph group join failed", err, "userID", userID, "groupID", groupID)
+				res.Failed = append(res.Failed, groupID)
+				continue
+			}
+			if direct {
+				res.Joined = append(res.Joined, groupID)
+			} else {
+				res.Requested = append(res.Requested, groupID)
+			}
+		}
+	}
+	return res, nil
+}
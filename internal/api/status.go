@@ -0,0 +1,179 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/discoveryregistry"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// statusCacheTime is how long a computed status is served before components are checked again.
+	statusCacheTime    = 30 * time.Second
+	statusCheckTimeout = 3 * time.Second
+	// statusRateLimit and statusRateBurst bound the requests served per second by one api instance.
+	statusRateLimit = 20
+	statusRateBurst = 40
+
+	componentUp   = "up"
+	componentDown = "down"
+
+	statusOperational = "operational"
+	statusDegraded    = "degraded"
+)
+
+// latencyBands are the upper bounds in milliseconds used to report delivery latency without exact values.
+var latencyBands = []struct {
+	bound int64
+	name  string
+}{
+	{100, "<100ms"},
+	{500, "100ms-500ms"},
+	{1000, "500ms-1s"},
+	{5000, "1s-5s"},
+}
+
+type StatusApi struct {
+	discov  discoveryregistry.SvcDiscoveryRegistry
+	rdb     redis.UniversalClient
+	mongo   *unrelation.Mongo
+	cache   cache.MsgModel
+	config  *config.GlobalConfig
+	limiter *rate.Limiter
+
+	lock sync.Mutex
+	resp *apistruct.StatusResp
+}
+
+func NewStatusApi(discov discoveryregistry.SvcDiscoveryRegistry, rdb redis.UniversalClient, mongo *unrelation.Mongo, config *config.GlobalConfig) *StatusApi {
+	return &StatusApi{
+		discov:  discov,
+		rdb:     rdb,
+		mongo:   mongo,
+		cache:   cache.NewMsgCacheModel(rdb, config),
+		config:  config,
+		limiter: rate.NewLimiter(statusRateLimit, statusRateBurst),
+	}
+}
+
+// GetStatus serves the public status page summary. It needs no token and is cached, so it can be polled freely.
+func (s *StatusApi) GetStatus(c *gin.Context) {
+	if !s.limiter.Allow() {
+		c.AbortWithStatus(http.StatusTooManyRequests)
+		return
+	}
+	resp := s.getStatus()
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(statusCacheTime.Seconds())))
+	apiresp.GinSuccess(c, resp)
+}
+
+func (s *StatusApi) getStatus() *apistruct.StatusResp {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.resp != nil && time.Since(time.UnixMilli(s.resp.UpdateTime)) < statusCacheTime {
+		return s.resp
+	}
+	ctx, cancel := context.WithTimeout(mcontext.NewCtx(utils.GetSelfFuncName()), statusCheckTimeout)
+	defer cancel()
+	resp := &apistruct.StatusResp{
+		Status:          statusOperational,
+		Version:         config.Version,
+		Components:      s.checkComponents(ctx),
+		DeliveryLatency: s.deliveryLatency(ctx),
+		UpdateTime:      time.Now().UnixMilli(),
+	}
+	for _, component := range resp.Components {
+		if component.Status != componentUp {
+			resp.Status = statusDegraded
+			break
+		}
+	}
+	s.resp = resp
+	return resp
+}
+
+func (s *StatusApi) checkComponents(ctx context.Context) []*apistruct.ComponentStatus {
+	components := []*apistruct.ComponentStatus{
+		{Name: "redis", Status: componentStatus(s.rdb.Ping(ctx).Err())},
+		{Name: "mongo", Status: componentStatus(s.mongo.GetClient().Ping(ctx, nil))},
+	}
+	for _, name := range s.config.GetServiceNames() {
+		conns, err := s.discov.GetConns(ctx, name)
+		if err == nil && len(conns) == 0 {
+			err = fmt.Errorf("no instance of %s", name)
+		}
+		components = append(components, &apistruct.ComponentStatus{Name: name, Status: componentStatus(err)})
+	}
+	return components
+}
+
+func componentStatus(err error) string {
+	if err != nil {
+		return componentDown
+	}
+	return componentUp
+}
+
+func (s *StatusApi) deliveryLatency(ctx context.Context) *apistruct.DeliveryLatency {
+	latencies, err := s.cache.GetDeliveryLatencies(ctx)
+	if err != nil {
+		log.ZWarn(ctx, "get delivery latencies failed", err)
+		return nil
+	}
+	if len(latencies) == 0 {
+		return nil
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	return &apistruct.DeliveryLatency{
+		P50: latencyBand(percentile(latencies, 50)),
+		P90: latencyBand(percentile(latencies, 90)),
+		P99: latencyBand(percentile(latencies, 99)),
+	}
+}
+
+// percentile returns the p-th percentile of sorted latencies using the nearest-rank method.
+func percentile(sorted []int64, p int) int64 {
+	rank := (len(sorted)*p + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func latencyBand(latency int64) string {
+	for _, band := range latencyBands {
+		if latency < band.bound {
+			return band.name
+		}
+	}
+	return ">5s"
+}
@@ -0,0 +1,145 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mqbuild"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/redis/go-redis/v9"
+)
+
+type SystemOverviewApi struct {
+	rdb    redis.UniversalClient
+	mongo  *unrelation.Mongo
+	cache  cache.OverviewCache
+	config *config.GlobalConfig
+
+	lock sync.Mutex
+	lag  *kafka.LagReader
+}
+
+func NewSystemOverviewApi(rdb redis.UniversalClient, mongo *unrelation.Mongo, config *config.GlobalConfig) *SystemOverviewApi {
+	return &SystemOverviewApi{rdb: rdb, mongo: mongo, cache: cache.NewOverviewCache(rdb), config: config}
+}
+
+// GetSystemOverview aggregates the metrics the rpc instances report every few seconds, it needs
+// prometheus.enable for anything but the component health and the consumer lag.
+func (s *SystemOverviewApi) GetSystemOverview(c *gin.Context) {
+	if err := authverify.CheckAdmin(c, s.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(c, statusCheckTimeout)
+	defer cancel()
+	resp := &apistruct.SystemOverviewResp{
+		OnlinePlatforms: make(map[string]int64),
+		Components: []*apistruct.ComponentStatus{
+			{Name: "redis", Status: componentStatus(s.rdb.Ping(ctx).Err())},
+			{Name: "mongo", Status: componentStatus(s.mongo.GetClient().Ping(ctx, nil))},
+		},
+		UpdateTime: time.Now().UnixMilli(),
+	}
+	calls := make(map[string]*prommetrics.RpcCalls)
+	for _, name := range s.config.GetServiceNames() {
+		reports, err := s.cache.GetReports(ctx, name)
+		if err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		for instance, data := range reports {
+			var report prommetrics.OverviewReport
+			if err := json.Unmarshal([]byte(data), &report); err != nil {
+				log.ZWarn(ctx, "unmarshal overview report failed", err, "service", name, "instance", instance)
+				continue
+			}
+			resp.OnlineUsers += report.OnlineUsers
+			for platform, n := range report.OnlinePlatforms {
+				resp.OnlinePlatforms[platform] += n
+			}
+			if report.Interval > 0 {
+				resp.MsgPerSecond += float64(report.MsgProcessed) / float64(report.Interval)
+			}
+			for service, call := range report.RpcCalls {
+				total, ok := calls[service]
+				if !ok {
+					total = &prommetrics.RpcCalls{}
+					calls[service] = total
+				}
+				total.Total += call.Total
+				total.Errors += call.Errors
+			}
+		}
+	}
+	for service, call := range calls {
+		rate := &apistruct.RpcErrorRate{Service: service, Total: call.Total, Errors: call.Errors}
+		if call.Total > 0 {
+			rate.ErrorRate = float64(call.Errors) / float64(call.Total)
+		}
+		resp.RpcErrorRates = append(resp.RpcErrorRates, rate)
+	}
+	sort.Slice(resp.RpcErrorRates, func(i, j int) bool { return resp.RpcErrorRates[i].Service < resp.RpcErrorRates[j].Service })
+	resp.ConsumerLags = s.consumerLags(ctx)
+	apiresp.GinSuccess(c, resp)
+}
+
+// consumerLags returns the lag of the msgtransfer and push consumer groups, the kafka client is created on
+// first use and dropped after an error so that a broker outage does not keep a broken client.
+// Lags are only reported for the kafka backend.
+func (s *SystemOverviewApi) consumerLags(ctx context.Context) []*apistruct.ConsumerLag {
+	if mqbuild.Backend(s.config) != mqbuild.BackendKafka {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.lag == nil {
+		lag, err := kafka.NewLagReader(s.config)
+		if err != nil {
+			log.ZWarn(ctx, "create kafka lag reader failed", err)
+			return nil
+		}
+		s.lag = lag
+	}
+	lags := []*apistruct.ConsumerLag{
+		{GroupID: s.config.Kafka.ConsumerGroupID.MsgToRedis, Topic: s.config.Kafka.LatestMsgToRedis.Topic},
+		{GroupID: s.config.Kafka.ConsumerGroupID.MsgToMongo, Topic: s.config.Kafka.MsgToMongo.Topic},
+		{GroupID: s.config.Kafka.ConsumerGroupID.MsgToPush, Topic: s.config.Kafka.MsgToPush.Topic},
+	}
+	for _, lag := range lags {
+		n, err := s.lag.Lag(lag.GroupID, lag.Topic)
+		if err != nil {
+			log.ZWarn(ctx, "get kafka consumer lag failed", err, "groupID", lag.GroupID, "topic", lag.Topic)
+			_ = s.lag.Close()
+			s.lag = nil
+			return nil
+		}
+		lag.Lag = n
+	}
+	return lags
+}
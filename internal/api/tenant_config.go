@@ -0,0 +1,121 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+)
+
+// GinParseAppID attaches the app named in the appID header to the request, it is carried to the rpc
+// servers and through kafka so each service resolves the config of that tenant.
+func GinParseAppID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if appID := c.GetHeader(tenant.AppIDKey); appID != "" {
+			setAppID(c, appID)
+		}
+		c.Next()
+	}
+}
+
+func setAppID(c *gin.Context, appID string) {
+	keys, _ := c.Value(constant.RpcCustomHeader).([]string)
+	if !utils.IsContain(tenant.AppIDKey, keys) {
+		keys = append(keys, tenant.AppIDKey)
+	}
+	c.Set(tenant.AppIDKey, []string{appID})
+	c.Set(constant.RpcCustomHeader, keys)
+}
+
+type TenantConfigApi struct {
+	database controller.TenantConfigDatabase
+	config   *config.GlobalConfig
+}
+
+func NewTenantConfigApi(database controller.TenantConfigDatabase, config *config.GlobalConfig) TenantConfigApi {
+	return TenantConfigApi{database: database, config: config}
+}
+
+func (t *TenantConfigApi) SetTenantConfig(c *gin.Context) {
+	var req apistruct.SetTenantConfigReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckIMAdmin(c, t.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if _, err := tenant.Merge(t.config, req.Overrides); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := t.database.SetTenantConfig(c, req.AppID, req.Overrides); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (t *TenantConfigApi) DeleteTenantConfig(c *gin.Context) {
+	var req apistruct.DeleteTenantConfigReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckIMAdmin(c, t.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := t.database.DeleteTenantConfig(c, req.AppID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (t *TenantConfigApi) GetTenantConfigs(c *gin.Context) {
+	var req apistruct.GetTenantConfigsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckIMAdmin(c, t.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, models, err := t.database.PageTenantConfigs(c, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetTenantConfigsResp{Total: total, Configs: make([]*apistruct.TenantConfig, 0, len(models))}
+	for _, model := range models {
+		resp.Configs = append(resp.Configs, &apistruct.TenantConfig{
+			AppID:      model.AppID,
+			Overrides:  model.Overrides,
+			UpdateTime: model.UpdateTime.UnixMilli(),
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
@@ -0,0 +1,150 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/discoveryregistry"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/topology"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/connectivity"
+)
+
+const (
+	instanceUnreported   = "unreported"
+	instanceUnregistered = "unregistered"
+)
+
+type TopologyApi struct {
+	discov discoveryregistry.SvcDiscoveryRegistry
+	cache  cache.OverviewCache
+	config *config.GlobalConfig
+}
+
+func NewTopologyApi(discov discoveryregistry.SvcDiscoveryRegistry, rdb redis.UniversalClient, config *config.GlobalConfig) *TopologyApi {
+	return &TopologyApi{discov: discov, cache: cache.NewTopologyCache(rdb), config: config}
+}
+
+// GetTopology lists the instances of every component from the discovery registry and the reports the instances
+// publish, registered instances are matched with reports on their address.
+func (t *TopologyApi) GetTopology(c *gin.Context) {
+	if err := authverify.CheckAdmin(c, t.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(c, statusCheckTimeout)
+	defer cancel()
+	now := time.Now()
+	resp := &apistruct.GetTopologyResp{UpdateTime: now.UnixMilli()}
+	components := []string{topology.ComponentApi, topology.ComponentMsgTransfer}
+	for _, name := range append(components, t.config.GetServiceNames()...) {
+		component, err := t.getComponent(ctx, name, now)
+		if err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		resp.Components = append(resp.Components, component)
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+func (t *TopologyApi) getComponent(ctx context.Context, name string, now time.Time) (*apistruct.TopologyComponent, error) {
+	reports, err := t.cache.GetReports(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	instances := make(map[string]*apistruct.TopologyInstance)
+	for key, data := range reports {
+		var report topology.Instance
+		if err := json.Unmarshal([]byte(data), &report); err != nil {
+			log.ZWarn(ctx, "unmarshal topology instance failed", err, "component", name, "instance", key)
+			continue
+		}
+		instances[key] = &apistruct.TopologyInstance{
+			Address:    report.Address,
+			Version:    report.Version,
+			Hostname:   report.Hostname,
+			PID:        report.PID,
+			StartTime:  report.StartTime,
+			Uptime:     int64(now.Sub(time.UnixMilli(report.StartTime)).Seconds()),
+			LastReport: report.UpdateTime,
+			Status:     componentUp,
+		}
+	}
+	// Only the rpc services are in the discovery registry.
+	if name != topology.ComponentApi && name != topology.ComponentMsgTransfer {
+		for _, instance := range instances {
+			instance.Status = instanceUnregistered
+		}
+		conns, err := t.discov.GetConns(ctx, name)
+		if err != nil {
+			log.ZWarn(ctx, "get conns failed", err, "component", name)
+		}
+		for _, conn := range conns {
+			instance, ok := instances[conn.Target()]
+			if !ok {
+				instance = &apistruct.TopologyInstance{Address: conn.Target(), Status: instanceUnreported}
+				instances[conn.Target()] = instance
+			}
+			instance.Registered = true
+			state := conn.GetState()
+			instance.ConnState = state.String()
+			switch {
+			case state == connectivity.TransientFailure || state == connectivity.Shutdown:
+				instance.Status = componentDown
+			case ok:
+				instance.Status = componentUp
+			}
+		}
+	}
+	component := &apistruct.TopologyComponent{
+		Name:      name,
+		Versions:  make(map[string]int),
+		Instances: make([]*apistruct.TopologyInstance, 0, len(instances)),
+	}
+	var up int
+	for _, instance := range instances {
+		component.Instances = append(component.Instances, instance)
+		if instance.Version != "" {
+			component.Versions[instance.Version]++
+		}
+		if instance.Status == componentUp {
+			up++
+		}
+	}
+	sort.Slice(component.Instances, func(i, j int) bool {
+		return component.Instances[i].Address+component.Instances[i].Hostname < component.Instances[j].Address+component.Instances[j].Hostname
+	})
+	switch {
+	case up == 0:
+		component.Status = componentDown
+	case up < len(component.Instances):
+		component.Status = statusDegraded
+	default:
+		component.Status = componentUp
+	}
+	return component, nil
+}
@@ -0,0 +1,64 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+)
+
+type UndoSendApi struct {
+	database controller.UndoSendDatabase
+	config   *config.GlobalConfig
+}
+
+func NewUndoSendApi(database controller.UndoSendDatabase, config *config.GlobalConfig) UndoSendApi {
+	return UndoSendApi{database: database, config: config}
+}
+
+func (u *UndoSendApi) CancelSend(c *gin.Context) {
+	var req apistruct.CancelSendReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if !u.config.UndoSend.Enable {
+		apiresp.GinError(c, errs.ErrArgs.Wrap("undo send is not enabled"))
+		return
+	}
+	if req.SendID == "" {
+		req.SendID = mcontext.GetOpUserID(c)
+	}
+	if err := authverify.CheckAccessV3(c, req.SendID, u.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	ok, err := u.database.Cancel(c, req.SendID, req.ClientMsgID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if !ok {
+		apiresp.GinError(c, errs.ErrRecordNotFound.Wrap("the message was already sent or is unknown"))
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/gin-gonic/gin"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"github.com/openimsdk/open-im-server/v3/pkg/userext"
 )
 
 type UserApi rpcclient.User
@@ -235,3 +236,47 @@ func (u *UserApi) UpdateNotificationAccountInfo(c *gin.Context) {
 func (u *UserApi) SearchNotificationAccount(c *gin.Context) {
 	a2r.Call(user.UserClient.SearchNotificationAccount, u.Client, c)
 }
+
+func (u *UserApi) SetNotificationAccountInfo(c *gin.Context) {
+	a2r.Call((*userext.Client).SetNotificationAccountInfo, u.Ext, c)
+}
+
+func (u *UserApi) GetNotificationAccountsInfo(c *gin.Context) {
+	a2r.Call((*userext.Client).GetNotificationAccountsInfo, u.Ext, c)
+}
+
+func (u *UserApi) SetNotificationOptOut(c *gin.Context) {
+	a2r.Call((*userext.Client).SetNotificationOptOut, u.Ext, c)
+}
+
+func (u *UserApi) GetNotificationOptOut(c *gin.Context) {
+	a2r.Call((*userext.Client).GetNotificationOptOut, u.Ext, c)
+}
+
+func (u *UserApi) SetNotificationQuota(c *gin.Context) {
+	a2r.Call((*userext.Client).SetNotificationQuota, u.Ext, c)
+}
+
+func (u *UserApi) GetNotificationQuota(c *gin.Context) {
+	a2r.Call((*userext.Client).GetNotificationQuota, u.Ext, c)
+}
+
+func (u *UserApi) FollowNotificationAccount(c *gin.Context) {
+	a2r.Call((*userext.Client).FollowNotificationAccount, u.Ext, c)
+}
+
+func (u *UserApi) UnfollowNotificationAccount(c *gin.Context) {
+	a2r.Call((*userext.Client).UnfollowNotificationAccount, u.Ext, c)
+}
+
+func (u *UserApi) GetNotificationAccountFollowers(c *gin.Context) {
+	a2r.Call((*userext.Client).GetNotificationAccountFollowers, u.Ext, c)
+}
+
+func (u *UserApi) GetFollowedNotificationAccounts(c *gin.Context) {
+	a2r.Call((*userext.Client).GetFollowedNotificationAccounts, u.Ext, c)
+}
+
+func (u *UserApi) GetNotificationAccountFollowerCounts(c *gin.Context) {
+	a2r.Call((*userext.Client).GetNotificationAccountFollowerCounts, u.Ext, c)
+}
@@ -0,0 +1,212 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"time"
+
+	pbauth "github.com/OpenIMSDK/protocol/auth"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	cbapi "github.com/openimsdk/open-im-server/v3/pkg/callbackstruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/http"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type UserAccountApi struct {
+	userRpc      *rpcclient.UserRpcClient
+	authRpc      *rpcclient.Auth
+	database     controller.UserAccountDatabase
+	authDatabase controller.AuthDatabase
+	config       *config.GlobalConfig
+}
+
+func NewUserAccountApi(userRpc *rpcclient.UserRpcClient, authRpc *rpcclient.Auth, database controller.UserAccountDatabase, authDatabase controller.AuthDatabase, config *config.GlobalConfig) UserAccountApi {
+	return UserAccountApi{userRpc: userRpc, authRpc: authRpc, database: database, authDatabase: authDatabase, config: config}
+}
+
+// DeactivateUser hides the user from searches and refuses it new tokens, its data is kept.
+func (u *UserAccountApi) DeactivateUser(c *gin.Context) {
+	var req apistruct.DeactivateUserReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := u.checkAccountAccess(c, req.UserID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	opUserID := mcontext.GetOpUserID(c)
+	if err := u.database.Deactivate(c, req.UserID, opUserID, req.Reason); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := u.logout(c, req.UserID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if u.config.Callback.CallbackAfterDeactivateUser.Enable {
+		cbReq := &cbapi.CallbackAfterDeactivateUserReq{
+			CallbackCommand: cbapi.CallbackAfterDeactivateUserCommand,
+			UserID:          req.UserID,
+			OperatorUserID:  opUserID,
+			Reason:          req.Reason,
+		}
+		resp := &cbapi.CallbackAfterDeactivateUserResp{}
+		if err := http.CallBackPostReturn(c, u.config.Callback.CallbackUrl, cbReq, resp, u.config.Callback.CallbackAfterDeactivateUser); err != nil {
+			log.ZWarn(c, "callback after deactivate user failed", err, "userID", req.UserID)
+		}
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+// DeleteAccount logs the user out and queues its deletion, the user purge task removes it from its friends and
+// groups and purges its data once the grace period has passed.
+func (u *UserAccountApi) DeleteAccount(c *gin.Context) {
+	var req apistruct.DeleteAccountReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := u.checkAccountAccess(c, req.UserID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if !u.config.UserPurge.Enable {
+		apiresp.GinError(c, errs.ErrNoPermission.Wrap("user purge is disabled"))
+		return
+	}
+	opUserID := mcontext.GetOpUserID(c)
+	gracePeriod := time.Duration(u.config.UserPurge.DeleteGracePeriod) * time.Second
+	deleteTime, err := u.database.RequestDeletion(c, req.UserID, opUserID, req.Reason, gracePeriod)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := u.logout(c, req.UserID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if u.config.Callback.CallbackAfterDeleteAccount.Enable {
+		cbReq := &cbapi.CallbackAfterDeleteAccountReq{
+			CallbackCommand: cbapi.CallbackAfterDeleteAccountCommand,
+			UserID:          req.UserID,
+			OperatorUserID:  opUserID,
+			Reason:          req.Reason,
+			DeleteTime:      deleteTime.UnixMilli(),
+		}
+		resp := &cbapi.CallbackAfterDeleteAccountResp{}
+		if err := http.CallBackPostReturn(c, u.config.Callback.CallbackUrl, cbReq, resp, u.config.Callback.CallbackAfterDeleteAccount); err != nil {
+			log.ZWarn(c, "callback after delete account failed", err, "userID", req.UserID)
+		}
+	}
+	apiresp.GinSuccess(c, &apistruct.DeleteAccountResp{DeleteTime: deleteTime.UnixMilli()})
+}
+
+// ReactivateUser is for app admins only, so that a deactivated user can not log itself back in.
+func (u *UserAccountApi) ReactivateUser(c *gin.Context) {
+	var req apistruct.ReactivateUserReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, u.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := u.database.Reactivate(c, req.UserID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (u *UserAccountApi) GetAccountStatus(c *gin.Context) {
+	var req apistruct.GetAccountStatusReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, u.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	account, err := u.database.GetAccount(c, req.UserID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.GetAccountStatusResp{
+		UserID:         account.UserID,
+		Status:         account.Status,
+		Reason:         account.Reason,
+		OperatorUserID: account.OperatorUserID,
+		DeleteTime:     unixMilli(account.DeleteTime),
+		PurgeID:        account.PurgeID,
+		UpdateTime:     unixMilli(account.UpdateTime),
+	})
+}
+
+// checkAccountAccess lets users act on their own account and app admins on any account but theirs.
+func (u *UserAccountApi) checkAccountAccess(c *gin.Context, userID string) error {
+	if err := authverify.CheckAccessV3(c, userID, u.config); err != nil {
+		return err
+	}
+	if authverify.IsManagerUserID(userID, u.config) {
+		return errs.ErrNoPermission.Wrap("app managers can not be deactivated or deleted")
+	}
+	if _, err := u.userRpc.GetUserInfo(c, userID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// logout kicks every token of userID and closes its connections, the gateways are asked as an app admin since
+// the caller may be the user itself.
+func (u *UserAccountApi) logout(ctx context.Context, userID string) error {
+	platformIDs, err := u.authDatabase.KickAllTokens(ctx, userID)
+	if err != nil {
+		return err
+	}
+	adminUserID := u.adminUserID()
+	if adminUserID == "" {
+		return nil
+	}
+	ctx = mcontext.WithOpUserIDContext(ctx, adminUserID)
+	for _, platformID := range platformIDs {
+		if _, err := u.authRpc.Client.ForceLogout(ctx, &pbauth.ForceLogoutReq{UserID: userID, PlatformID: int32(platformID)}); err != nil {
+			log.ZWarn(ctx, "force logout failed", err, "userID", userID, "platformID", platformID)
+		}
+	}
+	return nil
+}
+
+func (u *UserAccountApi) adminUserID() string {
+	if len(u.config.IMAdmin.UserID) > 0 {
+		return u.config.IMAdmin.UserID[0]
+	}
+	if len(u.config.Manager.UserID) > 0 {
+		return u.config.Manager.UserID[0]
+	}
+	return ""
+}
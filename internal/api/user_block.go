@@ -0,0 +1,96 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type UserBlockApi struct {
+	userRpc  *rpcclient.UserRpcClient
+	database controller.UserBlockDatabase
+	config   *config.GlobalConfig
+}
+
+func NewUserBlockApi(userRpc *rpcclient.UserRpcClient, database controller.UserBlockDatabase, config *config.GlobalConfig) UserBlockApi {
+	return UserBlockApi{userRpc: userRpc, database: database, config: config}
+}
+
+func (u *UserBlockApi) BlockUsers(c *gin.Context) {
+	var req apistruct.BlockUsersReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.OwnerUserID, u.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if _, err := u.userRpc.GetUsersInfo(c, req.BlockUserIDs); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := u.database.Block(c, req.OwnerUserID, req.BlockUserIDs); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (u *UserBlockApi) UnblockUsers(c *gin.Context) {
+	var req apistruct.UnblockUsersReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.OwnerUserID, u.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := u.database.Unblock(c, req.OwnerUserID, req.BlockUserIDs); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (u *UserBlockApi) GetBlockedUsers(c *gin.Context) {
+	var req apistruct.GetBlockedUsersReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.OwnerUserID, u.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, blocks, err := u.database.PageBlocks(c, req.OwnerUserID, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetBlockedUsersResp{Total: total, Users: make([]*apistruct.BlockedUser, 0, len(blocks))}
+	for _, block := range blocks {
+		resp.Users = append(resp.Users, &apistruct.BlockedUser{UserID: block.BlockUserID, CreateTime: block.CreateTime.UnixMilli()})
+	}
+	apiresp.GinSuccess(c, resp)
+}
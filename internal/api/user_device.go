@@ -0,0 +1,103 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	pbauth "github.com/OpenIMSDK/protocol/auth"
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+)
+
+// GetDevices lists the devices the user is logged in on.
+func (u *UserAccountApi) GetDevices(c *gin.Context) {
+	var req apistruct.GetDevicesReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, u.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	devices, err := u.authDatabase.GetDevices(c, req.UserID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	token := c.Request.Header.Get(constant.Token)
+	resp := &apistruct.GetDevicesResp{Devices: make([]*apistruct.Device, 0, len(devices))}
+	for _, device := range devices {
+		resp.Devices = append(resp.Devices, &apistruct.Device{
+			DeviceID:   device.DeviceID,
+			PlatformID: int32(device.PlatformID),
+			Name:       device.Name,
+			LoginTime:  device.LoginTime,
+			ExpireTime: device.ExpireTime,
+			LastActive: device.LastActive,
+			IP:         device.IP,
+			Current:    device.Token == token,
+		})
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+// RevokeDevice kicks the token of one device. When it was the last one of its platform the connections are
+// closed at once, otherwise the gateway closes the device's connection on its next heartbeat.
+func (u *UserAccountApi) RevokeDevice(c *gin.Context) {
+	var req apistruct.RevokeDeviceReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, u.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	platformID, last, err := u.authDatabase.RevokeDevice(c, req.UserID, req.DeviceID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if adminUserID := u.adminUserID(); last && adminUserID != "" {
+		ctx := mcontext.WithOpUserIDContext(c, adminUserID)
+		if _, err := u.authRpc.Client.ForceLogout(ctx, &pbauth.ForceLogoutReq{UserID: req.UserID, PlatformID: int32(platformID)}); err != nil {
+			log.ZWarn(ctx, "force logout failed", err, "userID", req.UserID, "platformID", platformID)
+		}
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (u *UserAccountApi) RenameDevice(c *gin.Context) {
+	var req apistruct.RenameDeviceReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, u.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := u.authDatabase.RenameDevice(c, req.UserID, req.DeviceID, req.Name); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
@@ -0,0 +1,96 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type UserMuteApi struct {
+	userRpc  *rpcclient.UserRpcClient
+	database controller.UserMuteDatabase
+	config   *config.GlobalConfig
+}
+
+func NewUserMuteApi(userRpc *rpcclient.UserRpcClient, database controller.UserMuteDatabase, config *config.GlobalConfig) UserMuteApi {
+	return UserMuteApi{userRpc: userRpc, database: database, config: config}
+}
+
+func (u *UserMuteApi) MuteUsers(c *gin.Context) {
+	var req apistruct.MuteUsersReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.OwnerUserID, u.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if _, err := u.userRpc.GetUsersInfo(c, req.MuteUserIDs); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := u.database.Mute(c, req.OwnerUserID, req.MuteUserIDs); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (u *UserMuteApi) UnmuteUsers(c *gin.Context) {
+	var req apistruct.UnmuteUsersReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.OwnerUserID, u.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if err := u.database.Unmute(c, req.OwnerUserID, req.MuteUserIDs); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (u *UserMuteApi) GetMutedUsers(c *gin.Context) {
+	var req apistruct.GetMutedUsersReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.OwnerUserID, u.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	total, mutes, err := u.database.PageMutes(c, req.OwnerUserID, req.Pagination)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	resp := &apistruct.GetMutedUsersResp{Total: total, Users: make([]*apistruct.MutedUser, 0, len(mutes))}
+	for _, mute := range mutes {
+		resp.Users = append(resp.Users, &apistruct.MutedUser{UserID: mute.MuteUserID, CreateTime: mute.CreateTime.UnixMilli()})
+	}
+	apiresp.GinSuccess(c, resp)
+}
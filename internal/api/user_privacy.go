@@ -0,0 +1,97 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type UserPrivacyApi struct {
+	database controller.UserPrivacyDatabase
+	config   *config.GlobalConfig
+}
+
+func NewUserPrivacyApi(database controller.UserPrivacyDatabase, config *config.GlobalConfig) UserPrivacyApi {
+	return UserPrivacyApi{database: database, config: config}
+}
+
+func (u *UserPrivacyApi) SetUserPrivacy(c *gin.Context) {
+	var req apistruct.SetUserPrivacyReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, u.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	privacy, err := u.database.GetPrivacy(c, req.UserID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if req.AddFriendPolicy != nil {
+		privacy.AddFriendPolicy = *req.AddFriendPolicy
+	}
+	if req.SearchByID != nil {
+		privacy.HideFromID = !*req.SearchByID
+	}
+	if req.SearchByPhone != nil {
+		privacy.HideFromPhone = !*req.SearchByPhone
+	}
+	if req.SearchByNickname != nil {
+		privacy.HideFromName = !*req.SearchByNickname
+	}
+	if err := u.database.SetPrivacy(c, privacy); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, nil)
+}
+
+func (u *UserPrivacyApi) GetUserPrivacy(c *gin.Context) {
+	var req apistruct.GetUserPrivacyReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAccessV3(c, req.UserID, u.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	privacy, err := u.database.GetPrivacy(c, req.UserID)
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.GetUserPrivacyResp{Privacy: userPrivacyDB2Api(privacy)})
+}
+
+func userPrivacyDB2Api(privacy *relation.UserPrivacyModel) *apistruct.UserPrivacy {
+	return &apistruct.UserPrivacy{
+		UserID:           privacy.UserID,
+		AddFriendPolicy:  privacy.AddFriendPolicy,
+		SearchByID:       !privacy.HideFromID,
+		SearchByPhone:    !privacy.HideFromPhone,
+		SearchByNickname: !privacy.HideFromName,
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+)
+
+type UserPurgeApi struct {
+	userRpc  *rpcclient.UserRpcClient
+	database controller.UserPurgeDatabase
+	config   *config.GlobalConfig
+}
+
+func NewUserPurgeApi(userRpc *rpcclient.UserRpcClient, database controller.UserPurgeDatabase, config *config.GlobalConfig) UserPurgeApi {
+	return UserPurgeApi{userRpc: userRpc, database: database, config: config}
+}
+
+func (u *UserPurgeApi) PurgeUser(c *gin.Context) {
+	var req apistruct.PurgeUserReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, u.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	if !u.config.UserPurge.Enable {
+		apiresp.GinError(c, errs.ErrNoPermission.Wrap("user purge is disabled"))
+		return
+	}
+	if authverify.IsManagerUserID(req.UserID, u.config) {
+		apiresp.GinError(c, errs.ErrNoPermission.Wrap("app managers can not be purged"))
+		return
+	}
+	if _, err := u.userRpc.GetUserInfo(c, req.UserID); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	purgeID, err := u.database.CreatePurge(c, req.UserID, mcontext.GetOpUserID(c))
+	if err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	apiresp.GinSuccess(c, &apistruct.PurgeUserResp{PurgeID: purgeID})
+}
+
+func (u *UserPurgeApi) GetUserPurgeReports(c *gin.Context) {
+	var req apistruct.GetUserPurgeReportsReq
+	if err := c.BindJSON(&req); err != nil {
+		apiresp.GinError(c, errs.ErrArgs.WithDetail(err.Error()).Wrap())
+		return
+	}
+	if err := authverify.CheckAdmin(c, u.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	var purges []*relation.UserPurgeModel
+	switch {
+	case req.PurgeID != "":
+		purge, err := u.database.TakePurge(c, req.PurgeID)
+		if err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+		purges = []*relation.UserPurgeModel{purge}
+	case req.UserID != "":
+		var err error
+		purges, err = u.database.GetUserPurges(c, req.UserID)
+		if err != nil {
+			apiresp.GinError(c, err)
+			return
+		}
+	default:
+		apiresp.GinError(c, errs.ErrArgs.Wrap("purgeID or userID is required"))
+		return
+	}
+	resp := &apistruct.GetUserPurgeReportsResp{Reports: make([]*apistruct.UserPurgeReport, 0, len(purges))}
+	for _, purge := range purges {
+		report := &apistruct.UserPurgeReport{
+			PurgeID:        purge.PurgeID,
+			UserID:         purge.UserID,
+			OperatorUserID: purge.OperatorUserID,
+			Status:         purge.Status,
+			Steps:          make([]*apistruct.UserPurgeStep, 0, len(purge.Steps)),
+			CreateTime:     unixMilli(purge.CreateTime),
+			FinishTime:     unixMilli(purge.FinishTime),
+		}
+		for _, step := range purge.Steps {
+			report.Steps = append(report.Steps, &apistruct.UserPurgeStep{
+				Name:       step.Name,
+				Count:      step.Count,
+				Err:        step.Err,
+				FinishTime: unixMilli(step.FinishTime),
+			})
+		}
+		resp.Reports = append(resp.Reports, report)
+	}
+	apiresp.GinSuccess(c, resp)
+}
+
+// unixMilli returns 0 for the zero time of steps and purges that have not finished.
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
@@ -0,0 +1,91 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/apiresp"
+	"github.com/OpenIMSDK/tools/discoveryregistry"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/gin-gonic/gin"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/topology"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/version"
+)
+
+type VersionApi struct {
+	discov discoveryregistry.SvcDiscoveryRegistry
+	info   *version.BuildInfo
+	config *config.GlobalConfig
+}
+
+func NewVersionApi(discov discoveryregistry.SvcDiscoveryRegistry, config *config.GlobalConfig) *VersionApi {
+	return &VersionApi{discov: discov, info: version.NewBuildInfo(topology.ComponentApi, config), config: config}
+}
+
+// GetVersion returns the build of this api instance, it needs no token so that deployment tools can check it.
+func (v *VersionApi) GetVersion(c *gin.Context) {
+	apiresp.GinSuccess(c, v.info)
+}
+
+// GetVersions asks every registered rpc instance for its build through the Version gRPC service.
+func (v *VersionApi) GetVersions(c *gin.Context) {
+	if err := authverify.CheckAdmin(c, v.config); err != nil {
+		apiresp.GinError(c, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(c, statusCheckTimeout)
+	defer cancel()
+	resp := &apistruct.GetVersionsResp{Instances: []*apistruct.VersionInstance{{Service: v.info.Service, Build: v.info}}}
+	for _, name := range v.config.GetServiceNames() {
+		conns, err := v.discov.GetConns(ctx, name)
+		if err != nil {
+			log.ZWarn(ctx, "get conns failed", err, "service", name)
+			resp.Instances = append(resp.Instances, &apistruct.VersionInstance{Service: name, Err: err.Error()})
+			continue
+		}
+		for _, conn := range conns {
+			instance := &apistruct.VersionInstance{Service: name, Address: conn.Target()}
+			instance.Build, err = version.GetBuildInfo(ctx, conn)
+			if err != nil {
+				instance.Err = err.Error()
+			}
+			resp.Instances = append(resp.Instances, instance)
+		}
+	}
+	resp.Consistent = consistentBuilds(resp.Instances)
+	apiresp.GinSuccess(c, resp)
+}
+
+// consistentBuilds reports whether the builds read share the same version and protocol version.
+func consistentBuilds(instances []*apistruct.VersionInstance) bool {
+	var first *version.BuildInfo
+	for _, instance := range instances {
+		if instance.Build == nil {
+			continue
+		}
+		if first == nil {
+			first = instance.Build
+			continue
+		}
+		if instance.Build.Version != first.Version || instance.Build.ProtocolVersion != first.ProtocolVersion {
+			return false
+		}
+	}
+	return true
+}
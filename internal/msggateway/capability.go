@@ -0,0 +1,60 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import "strconv"
+
+// Capabilities exchanged in the handshake. A client passes the bitmap of the ones it supports with the
+// capabilities query parameter, the gateway enables the ones it supports too and returns them in HandshakeData.
+const (
+	// CapabilityCompression gzips the binary frames, as compression=gzip does.
+	CapabilityCompression uint64 = 1 << iota
+	// CapabilityBinaryFrames sends the handshake response in a binary frame, so the client reads a single frame type.
+	CapabilityBinaryFrames
+	// CapabilitySyncProtocol enables WSGetConvMaxReadSeq, which returns the read and max seqs of every conversation.
+	CapabilitySyncProtocol
+	// CapabilityEphemeral pushes ephemeral states such as typing to the connection.
+	CapabilityEphemeral
+	// CapabilityPushAck makes the client ack pushes with WSPushAck, unacked pushes are written again and then
+	// answered with WSOutOfSync. The gateway drops the bit when longConnSvr.pushAck is disabled.
+	CapabilityPushAck
+)
+
+// serverCapabilities is what this gateway supports.
+const serverCapabilities = CapabilityCompression | CapabilityBinaryFrames | CapabilitySyncProtocol | CapabilityEphemeral | CapabilityPushAck
+
+// legacyCapabilities are enabled for clients that do not negotiate, which keeps their behavior unchanged.
+const legacyCapabilities = CapabilityEphemeral
+
+// negotiateCapabilities returns the capabilities enabled on a connection, client is the capabilities query
+// parameter.
+func negotiateCapabilities(client string, compression bool) uint64 {
+	capabilities := legacyCapabilities
+	if client != "" {
+		bits, err := strconv.ParseUint(client, 10, 64)
+		if err != nil {
+			bits = 0
+		}
+		capabilities = bits & serverCapabilities
+	}
+	if compression {
+		capabilities |= CapabilityCompression
+	}
+	return capabilities
+}
+
+func (c *Client) hasCapability(capability uint64) bool {
+	return c.capabilities&capability != 0
+}
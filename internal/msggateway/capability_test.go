@@ -0,0 +1,38 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateCapabilities(t *testing.T) {
+	// Clients that do not negotiate keep the behavior they had before.
+	assert.Equal(t, legacyCapabilities, negotiateCapabilities("", false))
+	assert.Equal(t, legacyCapabilities|CapabilityCompression, negotiateCapabilities("", true))
+
+	// Bits the gateway does not know are dropped.
+	client := CapabilitySyncProtocol | 1<<40
+	assert.Equal(t, CapabilitySyncProtocol, negotiateCapabilities(strconv.FormatUint(client, 10), false))
+
+	// A client negotiating without the ephemeral bit does not receive ephemeral states.
+	client = CapabilityCompression | CapabilityBinaryFrames
+	assert.Equal(t, client, negotiateCapabilities(strconv.FormatUint(client, 10), false))
+
+	assert.Equal(t, uint64(0), negotiateCapabilities("invalid", false))
+}
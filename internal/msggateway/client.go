@@ -21,6 +21,7 @@ import (
 	"runtime/debug"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/OpenIMSDK/protocol/constant"
 	"github.com/OpenIMSDK/protocol/sdkws"
@@ -29,6 +30,7 @@ import (
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/mcontext"
 	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
 	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
 	"google.golang.org/protobuf/proto"
 )
@@ -73,6 +75,14 @@ type Client struct {
 	closed         atomic.Bool
 	closedErr      error
 	token          string
+	clockSkew      int64
+	conformance    *conformanceConn
+	// lastActive (ms) is when the token session was last refreshed.
+	lastActive int64
+	// capabilities is the bitmap negotiated in the handshake.
+	capabilities uint64
+	// pushAcks is set when CapabilityPushAck was negotiated.
+	pushAcks *pushAckTracker
 }
 
 // function not used
@@ -88,7 +98,7 @@ type Client struct {
 // }
 
 // ResetClient updates the client's state with new connection and context information.
-func (c *Client) ResetClient(ctx *UserConnContext, conn LongConn, isBackground, isCompress bool, longConnServer LongConnServer, token string) {
+func (c *Client) ResetClient(ctx *UserConnContext, conn LongConn, isBackground, isCompress bool, longConnServer LongConnServer, token string, clockSkew int64) {
 	c.w = new(sync.Mutex)
 	c.conn = conn
 	c.PlatformID = utils.StringToInt(ctx.GetPlatformID())
@@ -101,16 +111,39 @@ func (c *Client) ResetClient(ctx *UserConnContext, conn LongConn, isBackground,
 	c.closed.Store(false)
 	c.closedErr = nil
 	c.token = token
+	c.clockSkew = clockSkew
+	c.conformance = nil
+	c.lastActive = time.Now().UnixMilli()
+	c.capabilities = legacyCapabilities
+	c.pushAcks = nil
 }
 
 func (c *Client) pingHandler(_ string) error {
 	if err := c.conn.SetReadDeadline(pongWait); err != nil {
 		return err
 	}
+	if err := c.activate(); err != nil {
+		return err
+	}
 
 	return c.writePongMsg()
 }
 
+// activate refreshes the token session at most once per sessionTouchInterval, the client is kicked once its
+// token is revoked.
+func (c *Client) activate() error {
+	now := time.Now().UnixMilli()
+	if now-c.lastActive < sessionTouchInterval.Milliseconds() {
+		return nil
+	}
+	c.lastActive = now
+	if err := c.longConnServer.touchSession(c); err != nil {
+		_ = c.KickOnlineMessage()
+		return err
+	}
+	return nil
+}
+
 // readMessage continuously reads messages from the connection.
 func (c *Client) readMessage() {
 	defer func() {
@@ -143,6 +176,10 @@ func (c *Client) readMessage() {
 		switch messageType {
 		case MessageBinary:
 			_ = c.conn.SetReadDeadline(pongWait)
+			if err := c.activate(); err != nil {
+				c.closedErr = err
+				return
+			}
 			parseDataErr := c.handleMessage(message)
 			if parseDataErr != nil {
 				c.closedErr = parseDataErr
@@ -189,10 +226,11 @@ func (c *Client) handleMessage(message []byte) error {
 	if binaryReq.SendID != c.UserID {
 		return errs.Wrap(errors.New("exception conn userID not same to req userID"), binaryReq.String())
 	}
+	c.conformance.recordRequest(binaryReq)
 
-	ctx := mcontext.WithMustInfoCtx(
+	ctx := tenant.WithAppID(mcontext.WithMustInfoCtx(
 		[]string{binaryReq.OperationID, binaryReq.SendID, constant.PlatformIDToName(c.PlatformID), c.ctx.GetConnID()},
-	)
+	), c.ctx.GetAppID())
 
 	log.ZDebug(ctx, "gateway req message", "req", binaryReq.String())
 
@@ -205,9 +243,17 @@ func (c *Client) handleMessage(message []byte) error {
 	case WSGetNewestSeq:
 		resp, messageErr = c.longConnServer.GetSeq(ctx, binaryReq)
 	case WSSendMsg:
-		resp, messageErr = c.longConnServer.SendMessage(ctx, binaryReq)
+		resp, messageErr = c.longConnServer.SendMessage(withClockSkew(ctx, c.clockSkew), binaryReq)
 	case WSSendSignalMsg:
 		resp, messageErr = c.longConnServer.SendSignalMessage(ctx, binaryReq)
+	case WSGetConvMaxReadSeq:
+		if !c.hasCapability(CapabilitySyncProtocol) {
+			messageErr = errs.ErrArgs.Wrap("the sync protocol was not negotiated")
+			break
+		}
+		resp, messageErr = c.longConnServer.GetConvMaxReadSeq(ctx, binaryReq)
+	case WSPushAck:
+		messageErr = c.ackPushes(binaryReq)
 	case WSPullMsgBySeqList:
 		resp, messageErr = c.longConnServer.PullMessageBySeqList(ctx, binaryReq)
 	case WsLogoutMsg:
@@ -273,8 +319,16 @@ func (c *Client) replyMessage(ctx context.Context, binaryReq *Req, err error, re
 }
 
 func (c *Client) PushMessage(ctx context.Context, msgData *sdkws.MsgData) error {
-	var msg sdkws.PushMessages
 	conversationID := msgprocessor.GetConversationIDByMsg(msgData)
+	if err := c.writePush(ctx, conversationID, msgData); err != nil {
+		return err
+	}
+	c.trackPush(ctx, conversationID, msgData)
+	return nil
+}
+
+func (c *Client) writePush(ctx context.Context, conversationID string, msgData *sdkws.MsgData) error {
+	var msg sdkws.PushMessages
 	m := map[string]*sdkws.PullMsgs{conversationID: {Msgs: []*sdkws.MsgData{msgData}}}
 	if msgprocessor.IsNotification(conversationID) {
 		msg.NotificationMsgs = m
@@ -320,6 +374,7 @@ func (c *Client) writeBinaryMsg(resp Resp) error {
 	if err != nil {
 		return err
 	}
+	c.conformance.recordResponse(&resp)
 
 	if c.IsCompress {
 		resultBuf, compressErr := c.longConnServer.CompressWithPool(encodedBuf)
@@ -0,0 +1,61 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/utils"
+)
+
+type clockSkewKey struct{}
+
+// HandshakeData is returned in the handshake response when the client asks for it with isMsgResp.
+type HandshakeData struct {
+	// ClockSkew is server time minus the clientTime passed when connecting, in ms.
+	ClockSkew  int64 `json:"clockSkew"`
+	ServerTime int64 `json:"serverTime"`
+	// Capabilities is the bitmap of the capabilities enabled on the connection.
+	Capabilities uint64 `json:"capabilities"`
+}
+
+// parseClockSkew returns server time minus the client time in ms, 0 if the client did not pass a valid time.
+func parseClockSkew(clientTime string) int64 {
+	if clientTime == "" {
+		return 0
+	}
+	t, err := strconv.ParseInt(clientTime, 10, 64)
+	if err != nil || t <= 0 {
+		return 0
+	}
+	return utils.GetCurrentTimestampByMill() - t
+}
+
+func withClockSkew(ctx context.Context, skew int64) context.Context {
+	if skew == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, clockSkewKey{}, skew)
+}
+
+// correctSendTime shifts the client stamped sendTime by the skew of the connection it was sent on.
+func correctSendTime(ctx context.Context, msg *sdkws.MsgData) {
+	skew, _ := ctx.Value(clockSkewKey{}).(int64)
+	if skew != 0 && msg.SendTime != 0 {
+		msg.SendTime += skew
+	}
+}
@@ -0,0 +1,467 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/protocol/push"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ConformanceSession groups the connections of one conformance run, it is only read in conformance mode.
+	ConformanceSession = "conformanceSession"
+	// conformanceToken is the token sent by VerifyTranscript, tokens are not checked in conformance mode.
+	conformanceToken = "conformance"
+	// conformanceEpoch is the send time of the first message of a session, each message is one second later.
+	conformanceEpoch = 1700000000000
+)
+
+var _ MessageHandler = (*conformanceServer)(nil)
+
+// conformanceServer answers the WS protocol from memory with deterministic seqs, ids and times, so that the
+// same client frames always get the same server frames. It needs no rpc service and checks no token,
+// it must never face real clients.
+type conformanceServer struct {
+	recordDir string
+	lock      sync.Mutex
+	sessions  map[string]*conformanceSession
+	conns     map[string]*conformanceConn
+}
+
+func newConformanceServer(recordDir string) *conformanceServer {
+	return &conformanceServer{
+		recordDir: recordDir,
+		sessions:  make(map[string]*conformanceSession),
+		conns:     make(map[string]*conformanceConn),
+	}
+}
+
+// conformanceSession is the state shared by the connections of one session, it is dropped with the last one.
+type conformanceSession struct {
+	name       string
+	lock       sync.Mutex
+	conns      []*conformanceConn
+	open       int
+	clock      int64
+	seqs       map[string]int64
+	msgs       map[string][]*sdkws.MsgData
+	members    map[string]map[string]struct{}
+	transcript Transcript
+}
+
+type conformanceConn struct {
+	session *conformanceSession
+	index   int
+	userID  string
+	connID  string
+	// pushLock orders pushes with attach, a push to a connection whose client is not registered yet is
+	// delivered once it is.
+	pushLock sync.Mutex
+	client   *Client
+	pending  []*conformancePush
+	closed   bool
+}
+
+type conformancePush struct {
+	ctx     context.Context
+	msgData *sdkws.MsgData
+}
+
+// join adds the connection to its session before the upgrade, so that the connections of a session are
+// numbered in the order the clients opened them. It returns nil outside conformance mode.
+func (s *conformanceServer) join(connContext *UserConnContext, args *WSArgs, pErr error) *conformanceConn {
+	if s == nil || (pErr != nil && !args.MsgResp) {
+		return nil
+	}
+	name, _ := connContext.Query(ConformanceSession)
+	if name == "" {
+		name = "default"
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	session, ok := s.sessions[name]
+	if !ok {
+		session = &conformanceSession{
+			name:       name,
+			seqs:       make(map[string]int64),
+			msgs:       make(map[string][]*sdkws.MsgData),
+			members:    make(map[string]map[string]struct{}),
+			transcript: Transcript{Name: name},
+		}
+		s.sessions[name] = session
+	}
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	conn := &conformanceConn{session: session, index: len(session.conns), userID: args.UserID, connID: connContext.GetConnID()}
+	session.conns = append(session.conns, conn)
+	session.open++
+	session.transcript.Conns = append(session.transcript.Conns, &TranscriptConn{
+		UserID:      args.UserID,
+		PlatformID:  args.PlatformID,
+		Compression: args.Compression,
+		MsgResp:     args.MsgResp,
+	})
+	session.transcript.Frames = append(session.transcript.Frames, &Frame{Conn: conn.index, Type: FrameConnect})
+	s.conns[conn.connID] = conn
+	return conn
+}
+
+// attach binds the registered client and delivers the pushes it missed.
+func (c *conformanceConn) attach(client *Client) {
+	if c == nil {
+		return
+	}
+	c.pushLock.Lock()
+	defer c.pushLock.Unlock()
+	c.client = client
+	for _, p := range c.pending {
+		if err := client.PushMessage(p.ctx, p.msgData); err != nil {
+			log.ZWarn(p.ctx, "conformance push failed", err, "userID", c.userID)
+		}
+	}
+	c.pending = nil
+}
+
+func (c *conformanceConn) push(ctx context.Context, msgData *sdkws.MsgData) {
+	c.pushLock.Lock()
+	defer c.pushLock.Unlock()
+	if c.closed {
+		return
+	}
+	if c.client == nil {
+		c.pending = append(c.pending, &conformancePush{ctx: ctx, msgData: msgData})
+		return
+	}
+	if err := c.client.PushMessage(ctx, msgData); err != nil {
+		log.ZWarn(ctx, "conformance push failed", err, "userID", c.userID)
+	}
+}
+
+func (c *conformanceConn) record(frame *Frame) {
+	if c == nil {
+		return
+	}
+	frame.Conn = c.index
+	c.session.lock.Lock()
+	defer c.session.lock.Unlock()
+	c.session.transcript.Frames = append(c.session.transcript.Frames, frame)
+}
+
+func (c *conformanceConn) recordHandshake(errCode int, errMsg string) {
+	c.record(&Frame{Type: FrameHandshake, ErrCode: errCode, ErrMsg: errMsg})
+}
+
+func (c *conformanceConn) recordRequest(req *Req) {
+	if c == nil {
+		return
+	}
+	c.record(&Frame{
+		Type:          FrameRequest,
+		ReqIdentifier: req.ReqIdentifier,
+		MsgIncr:       req.MsgIncr,
+		OperationID:   req.OperationID,
+		Data:          recordPayload(FrameRequest, req.ReqIdentifier, req.Data),
+	})
+}
+
+func (c *conformanceConn) recordResponse(resp *Resp) {
+	if c == nil {
+		return
+	}
+	c.record(&Frame{
+		Type:          FrameResponse,
+		ReqIdentifier: resp.ReqIdentifier,
+		MsgIncr:       resp.MsgIncr,
+		OperationID:   resp.OperationID,
+		ErrCode:       resp.ErrCode,
+		ErrMsg:        resp.ErrMsg,
+		Data:          recordPayload(FrameResponse, resp.ReqIdentifier, resp.Data),
+	})
+}
+
+// recordPayload keeps a payload that does not decode as raw bytes, so that broken frames still show up in the
+// transcript.
+func recordPayload(frameType string, reqIdentifier int32, data []byte) json.RawMessage {
+	payload, err := encodePayload(frameType, reqIdentifier, data)
+	if err != nil {
+		payload, _ = json.Marshal(data)
+	}
+	return payload
+}
+
+// leave drops the connection, the transcript of the session is written when its last connection leaves.
+func (s *conformanceServer) leave(c *conformanceConn) {
+	if c == nil {
+		return
+	}
+	c.pushLock.Lock()
+	c.closed = true
+	c.client = nil
+	c.pending = nil
+	c.pushLock.Unlock()
+
+	s.lock.Lock()
+	delete(s.conns, c.connID)
+	session := c.session
+	session.lock.Lock()
+	session.open--
+	done := session.open == 0
+	session.lock.Unlock()
+	if done {
+		delete(s.sessions, session.name)
+	}
+	s.lock.Unlock()
+	if done && s.recordDir != "" {
+		if err := s.writeTranscript(&session.transcript); err != nil {
+			log.ZError(context.Background(), "write conformance transcript failed", err, "session", session.name)
+		}
+	}
+}
+
+var transcriptNameRe = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+func (s *conformanceServer) writeTranscript(t *Transcript) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	if err := os.MkdirAll(s.recordDir, 0o755); err != nil {
+		return errs.Wrap(err)
+	}
+	file := filepath.Join(s.recordDir, transcriptNameRe.ReplaceAllString(t.Name, "_")+".json")
+	if err := os.WriteFile(file, append(data, '\n'), 0o644); err != nil {
+		return errs.Wrap(err)
+	}
+	return nil
+}
+
+// conn returns the connection the request was read from.
+func (s *conformanceServer) conn(ctx context.Context) (*conformanceConn, error) {
+	connID, _ := ctx.Value(constant.ConnID).(string)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	conn, ok := s.conns[connID]
+	if !ok {
+		return nil, errs.ErrArgs.Wrap("not a conformance connection")
+	}
+	return conn, nil
+}
+
+func (s *conformanceServer) GetSeq(ctx context.Context, data *Req) ([]byte, error) {
+	conn, err := s.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var req sdkws.GetMaxSeqReq
+	if err := proto.Unmarshal(data.Data, &req); err != nil {
+		return nil, errs.Wrap(err, "GetSeq: error unmarshaling request")
+	}
+	if req.UserID != data.SendID {
+		return nil, errs.ErrNoPermission.Wrap("GetSeq: userID is not the connection user")
+	}
+	session := conn.session
+	session.lock.Lock()
+	resp := &sdkws.GetMaxSeqResp{MaxSeqs: make(map[string]int64)}
+	for conversationID, members := range session.members {
+		if _, ok := members[req.UserID]; ok {
+			resp.MaxSeqs[conversationID] = session.seqs[conversationID]
+		}
+	}
+	session.lock.Unlock()
+	return proto.Marshal(resp)
+}
+
+// GetConvMaxReadSeq returns the max seqs of the user's conversations, reads are not tracked so nothing is read.
+func (s *conformanceServer) GetConvMaxReadSeq(ctx context.Context, data *Req) ([]byte, error) {
+	conn, err := s.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var req msg.GetConversationsHasReadAndMaxSeqReq
+	if err := proto.Unmarshal(data.Data, &req); err != nil {
+		return nil, errs.Wrap(err, "GetConvMaxReadSeq: error unmarshaling request")
+	}
+	if req.UserID != data.SendID {
+		return nil, errs.ErrNoPermission.Wrap("GetConvMaxReadSeq: userID is not the connection user")
+	}
+	session := conn.session
+	session.lock.Lock()
+	resp := &msg.GetConversationsHasReadAndMaxSeqResp{Seqs: make(map[string]*msg.Seqs)}
+	for conversationID, members := range session.members {
+		if _, ok := members[req.UserID]; ok {
+			resp.Seqs[conversationID] = &msg.Seqs{MaxSeq: session.seqs[conversationID]}
+		}
+	}
+	session.lock.Unlock()
+	return proto.Marshal(resp)
+}
+
+// SendMessage stores the message in the session and pushes it to the other connections of its members.
+func (s *conformanceServer) SendMessage(ctx context.Context, data *Req) ([]byte, error) {
+	conn, err := s.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var msgData sdkws.MsgData
+	if err := proto.Unmarshal(data.Data, &msgData); err != nil {
+		return nil, errs.Wrap(err, "error unmarshalling message data")
+	}
+	if msgData.SendID != data.SendID {
+		return nil, errs.ErrArgs.Wrap("sendID is not the connection user")
+	}
+	if msgData.ClientMsgID == "" {
+		return nil, errs.ErrArgs.Wrap("clientMsgID is empty")
+	}
+	switch msgData.SessionType {
+	case constant.SingleChatType:
+		if msgData.RecvID == "" {
+			return nil, errs.ErrArgs.Wrap("recvID is empty")
+		}
+	case constant.SuperGroupChatType:
+		if msgData.GroupID == "" {
+			return nil, errs.ErrArgs.Wrap("groupID is empty")
+		}
+	default:
+		return nil, errs.ErrArgs.Wrap(fmt.Sprintf("session type %d is not supported in conformance mode", msgData.SessionType))
+	}
+	conversationID := msgprocessor.GetConversationIDByMsg(&msgData)
+
+	session := conn.session
+	session.lock.Lock()
+	session.clock++
+	session.seqs[conversationID]++
+	msgData.Seq = session.seqs[conversationID]
+	msgData.SendTime = conformanceEpoch + session.clock*1000
+	msgData.CreateTime = msgData.SendTime
+	msgData.ServerMsgID = fmt.Sprintf("%s-%d", conversationID, msgData.Seq)
+	members, ok := session.members[conversationID]
+	if !ok {
+		members = make(map[string]struct{})
+		session.members[conversationID] = members
+	}
+	members[msgData.SendID] = struct{}{}
+	if msgData.SessionType == constant.SingleChatType {
+		members[msgData.RecvID] = struct{}{}
+	} else {
+		// Everyone in the session is in the group.
+		for _, c := range session.conns {
+			members[c.userID] = struct{}{}
+		}
+	}
+	session.msgs[conversationID] = append(session.msgs[conversationID], proto.Clone(&msgData).(*sdkws.MsgData))
+	var recipients []*conformanceConn
+	for _, c := range session.conns {
+		if _, ok := members[c.userID]; ok && c != conn {
+			recipients = append(recipients, c)
+		}
+	}
+	session.lock.Unlock()
+
+	for _, c := range recipients {
+		c.push(ctx, proto.Clone(&msgData).(*sdkws.MsgData))
+	}
+	return proto.Marshal(&msg.SendMsgResp{
+		ServerMsgID: msgData.ServerMsgID,
+		ClientMsgID: msgData.ClientMsgID,
+		SendTime:    msgData.SendTime,
+	})
+}
+
+func (s *conformanceServer) SendSignalMessage(context.Context, *Req) ([]byte, error) {
+	return nil, errs.ErrArgs.Wrap("signal messages are not supported in conformance mode")
+}
+
+// PullMessageBySeqList follows the paging of the msg rpc: Num messages from Begin for an ascending pull,
+// up to End for a descending one.
+func (s *conformanceServer) PullMessageBySeqList(ctx context.Context, data *Req) ([]byte, error) {
+	conn, err := s.conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var req sdkws.PullMessageBySeqsReq
+	if err := proto.Unmarshal(data.Data, &req); err != nil {
+		return nil, errs.Wrap(err, "error unmarshaling request")
+	}
+	if req.UserID != data.SendID {
+		return nil, errs.ErrNoPermission.Wrap("userID is not the connection user")
+	}
+	resp := &sdkws.PullMessageBySeqsResp{
+		Msgs:             make(map[string]*sdkws.PullMsgs),
+		NotificationMsgs: make(map[string]*sdkws.PullMsgs),
+	}
+	session := conn.session
+	session.lock.Lock()
+	defer session.lock.Unlock()
+	for _, seq := range req.SeqRanges {
+		if _, ok := session.members[seq.ConversationID][req.UserID]; !ok {
+			continue
+		}
+		var msgs []*sdkws.MsgData
+		for _, m := range session.msgs[seq.ConversationID] {
+			if m.Seq >= seq.Begin && m.Seq <= seq.End {
+				msgs = append(msgs, proto.Clone(m).(*sdkws.MsgData))
+			}
+		}
+		if seq.Num > 0 && int64(len(msgs)) > seq.Num {
+			if req.Order == sdkws.PullOrder_PullOrderDesc {
+				msgs = msgs[int64(len(msgs))-seq.Num:]
+			} else {
+				msgs = msgs[:seq.Num]
+			}
+		}
+		if len(msgs) == 0 {
+			continue
+		}
+		var isEnd bool
+		switch req.Order {
+		case sdkws.PullOrder_PullOrderAsc:
+			isEnd = session.seqs[seq.ConversationID] <= seq.End
+		case sdkws.PullOrder_PullOrderDesc:
+			isEnd = seq.Begin <= 1
+		}
+		resp.Msgs[seq.ConversationID] = &sdkws.PullMsgs{Msgs: msgs, IsEnd: isEnd}
+	}
+	return proto.Marshal(resp)
+}
+
+func (s *conformanceServer) UserLogout(_ context.Context, data *Req) ([]byte, error) {
+	var req push.DelUserPushTokenReq
+	if err := proto.Unmarshal(data.Data, &req); err != nil {
+		return nil, errs.Wrap(err, "error unmarshaling request")
+	}
+	return proto.Marshal(&push.DelUserPushTokenResp{})
+}
+
+func (s *conformanceServer) SetUserDeviceBackground(_ context.Context, data *Req) ([]byte, bool, error) {
+	var req sdkws.SetAppBackgroundStatusReq
+	if err := proto.Unmarshal(data.Data, &req); err != nil {
+		return nil, false, errs.Wrap(err, "error unmarshaling request")
+	}
+	return nil, req.IsBackground, nil
+}
@@ -0,0 +1,44 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConformanceGoldenTranscripts(t *testing.T) {
+	conf := config.NewGlobalConfig()
+	conf.LongConnSvr.Conformance.Enable = true
+	ws, err := NewWsServer(conf, WithMaxConnNum(100))
+	assert.Nil(t, err)
+	shutdownDone := make(chan struct{})
+	defer close(shutdownDone)
+	go ws.dispatch(shutdownDone)
+	server := httptest.NewServer(http.HandlerFunc(ws.wsHandler))
+	defer server.Close()
+
+	transcripts, err := LoadTranscripts("../../test/e2e/conformance/ws")
+	assert.Nil(t, err)
+	assert.NotEmpty(t, transcripts)
+	for _, transcript := range transcripts {
+		assert.Nil(t, VerifyTranscript("ws"+strings.TrimPrefix(server.URL, "http"), transcript), transcript.Name)
+	}
+}
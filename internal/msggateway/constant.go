@@ -27,6 +27,8 @@ const (
 	GzipCompressionProtocol = "gzip"
 	BackgroundStatus        = "isBackground"
 	MsgResp                 = "isMsgResp"
+	ClientTime              = "clientTime"
+	Capabilities            = "capabilities"
 )
 
 const (
@@ -39,10 +41,13 @@ const (
 	WSPullMsgBySeqList    = 1002
 	WSSendMsg             = 1003
 	WSSendSignalMsg       = 1004
+	WSGetConvMaxReadSeq   = 1005
+	WSPushAck             = 1006
 	WSPushMsg             = 2001
 	WSKickOnlineMsg       = 2002
 	WsLogoutMsg           = 2003
 	WsSetBackgroundStatus = 2004
+	WSOutOfSync           = 2005
 	WSDataError           = 3001
 )
 
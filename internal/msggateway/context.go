@@ -22,6 +22,7 @@ import (
 
 	"github.com/OpenIMSDK/protocol/constant"
 	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
 )
 
 type UserConnContext struct {
@@ -57,6 +58,16 @@ func (c *UserConnContext) Value(key any) any {
 		return constant.PlatformIDToName(utils.StringToInt(c.GetPlatformID()))
 	case constant.RemoteAddr:
 		return c.RemoteAddr
+	case constant.RpcCustomHeader:
+		if c.GetAppID() == "" {
+			return nil
+		}
+		return []string{tenant.AppIDKey}
+	case tenant.AppIDKey:
+		if appID := c.GetAppID(); appID != "" {
+			return []string{appID}
+		}
+		return nil
 	default:
 		return ""
 	}
@@ -119,6 +130,11 @@ func (c *UserConnContext) GetPlatformID() string {
 	return c.Req.URL.Query().Get(PlatformID)
 }
 
+// GetAppID returns the tenant the connection belongs to, empty for the default tenant.
+func (c *UserConnContext) GetAppID() string {
+	return c.Req.URL.Query().Get(tenant.AppIDKey)
+}
+
 func (c *UserConnContext) GetOperationID() string {
 	return c.Req.URL.Query().Get(OperationID)
 }
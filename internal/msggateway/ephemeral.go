@@ -0,0 +1,132 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"golang.org/x/time/rate"
+)
+
+// limiterIdle is how long a sender's or conversation's limiter is kept after its last state.
+const limiterIdle = time.Minute
+
+// Key prefixes separating sender and conversation limiters in the same map.
+const (
+	senderLimiterPrefix       = "s:"
+	conversationLimiterPrefix = "c:"
+)
+
+type keyedLimiter struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// ephemeralRelay stamps the TTL of ephemeral states and rate limits them per sender and per conversation.
+type ephemeralRelay struct {
+	config    *config.GlobalConfig
+	lock      sync.Mutex
+	limiters  map[string]*keyedLimiter
+	lastPrune time.Time
+}
+
+func newEphemeralRelay(config *config.GlobalConfig) *ephemeralRelay {
+	return &ephemeralRelay{config: config, limiters: make(map[string]*keyedLimiter), lastPrune: time.Now()}
+}
+
+func (e *ephemeralRelay) ttl(state int32) (int64, bool) {
+	switch state {
+	case msgprocessor.EphemeralTyping:
+		return int64(e.config.EphemeralState.TypingTTL), true
+	case msgprocessor.EphemeralRecording:
+		return int64(e.config.EphemeralState.RecordingTTL), true
+	case msgprocessor.EphemeralUploading:
+		return int64(e.config.EphemeralState.UploadingTTL), true
+	default:
+		return 0, false
+	}
+}
+
+// prepare validates the state and stamps its TTL into the content, it returns false when the sender
+// or the conversation is over the rate limit.
+func (e *ephemeralRelay) prepare(msg *sdkws.MsgData) (bool, error) {
+	var elem msgprocessor.EphemeralStateElem
+	if len(msg.Content) > 0 {
+		if err := json.Unmarshal(msg.Content, &elem); err != nil {
+			return false, errs.ErrArgs.Wrap("invalid ephemeral state content")
+		}
+	}
+	if elem.State == 0 {
+		elem.State = msgprocessor.EphemeralTyping
+	}
+	ttl, ok := e.ttl(elem.State)
+	if !ok {
+		return false, errs.ErrArgs.Wrap("unknown ephemeral state")
+	}
+	if !e.allow(msg.SendID, msgprocessor.GetChatConversationIDByMsg(msg)) {
+		return false, nil
+	}
+	elem.TTL = ttl
+	content, err := json.Marshal(&elem)
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	msg.Content = content
+	return true, nil
+}
+
+// allow reports whether both the sender and the conversation are within their rate limits.
+// A conversation limit keeps a large group from flooding its members when many of them type at once.
+func (e *ephemeralRelay) allow(sendID string, conversationID string) bool {
+	senderLimit := e.config.EphemeralState.RateLimit
+	conversationLimit := e.config.EphemeralState.ConversationRateLimit
+	if senderLimit <= 0 && conversationLimit <= 0 {
+		return true
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	now := time.Now()
+	if now.Sub(e.lastPrune) > limiterIdle {
+		for key, l := range e.limiters {
+			if now.Sub(l.lastUsed) > limiterIdle {
+				delete(e.limiters, key)
+			}
+		}
+		e.lastPrune = now
+	}
+	if !e.allowKey(conversationLimiterPrefix+conversationID, conversationLimit, now) {
+		return false
+	}
+	return e.allowKey(senderLimiterPrefix+sendID, senderLimit, now)
+}
+
+func (e *ephemeralRelay) allowKey(key string, limit int, now time.Time) bool {
+	if limit <= 0 {
+		return true
+	}
+	l, ok := e.limiters[key]
+	if !ok {
+		l = &keyedLimiter{limiter: rate.NewLimiter(rate.Limit(limit), limit)}
+		e.limiters[key] = l
+	}
+	l.lastUsed = now
+	return l.limiter.AllowN(now, 1)
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/OpenIMSDK/protocol/constant"
 	"github.com/OpenIMSDK/protocol/msggateway"
+	"github.com/OpenIMSDK/protocol/sdkws"
 	"github.com/OpenIMSDK/tools/discoveryregistry"
 	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/log"
@@ -27,6 +28,9 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/startrpc"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpccache"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	"google.golang.org/grpc"
 )
 
@@ -37,6 +41,9 @@ func (s *Server) InitServer(config *config.GlobalConfig, disCov discoveryregistr
 	}
 
 	msgModel := cache.NewMsgCacheModel(rdb, config)
+	if config.LongConnSvr.SkipMutedBackground {
+		s.conversationLocalCache = rpccache.NewConversationLocalCache(rpcclient.NewConversationRpcClient(disCov, config), rdb)
+	}
 	s.LongConnServer.SetDiscoveryRegistry(disCov, config)
 	s.LongConnServer.SetCacheHandler(msgModel)
 	msggateway.RegisterMsgGatewayServer(server, s)
@@ -59,6 +66,8 @@ type Server struct {
 	LongConnServer LongConnServer
 	config         *config.GlobalConfig
 	pushTerminal   map[int]struct{}
+	// conversationLocalCache is set when longConnSvr.skipMutedBackground is enabled.
+	conversationLocalCache *rpccache.ConversationLocalCache
 }
 
 func (s *Server) SetLongConnServer(LongConnServer LongConnServer) {
@@ -146,14 +155,34 @@ func (s *Server) SuperGroupOnlineBatchPushOneMsg(ctx context.Context, req *msgga
 		}
 
 		log.ZDebug(ctx, "push user online", "clients", clients, "userID", v)
+		var muted *bool
 		for _, client := range clients {
 			if client == nil {
 				continue
 			}
+			// Ephemeral states only matter to a member looking at the conversation with a client that shows them.
+			if msgprocessor.IsEphemeral(req.MsgData) && (client.IsBackground || !client.hasCapability(CapabilityEphemeral)) {
+				continue
+			}
 
 			userPlatform := &msggateway.SingleMsgToUserPlatform{
 				RecvPlatFormID: int32(client.PlatformID),
 			}
+			if client.IsBackground {
+				if muted == nil {
+					isMuted := s.isMuted(ctx, v, req.MsgData)
+					muted = &isMuted
+				}
+				// The message is pulled by seq once the app is in the foreground. Report it as delivered so
+				// a muted conversation does not fall back to an offline push either.
+				if *muted {
+					if _, ok := s.pushTerminal[client.PlatformID]; ok {
+						results.OnlinePush = true
+						resp = append(resp, userPlatform)
+					}
+					continue
+				}
+			}
 			if !client.IsBackground ||
 				(client.IsBackground && client.PlatformID != constant.IOSPlatformID) {
 				err := client.PushMessage(ctx, req.MsgData)
@@ -180,6 +209,23 @@ func (s *Server) SuperGroupOnlineBatchPushOneMsg(ctx context.Context, req *msgga
 	}, nil
 }
 
+// isMuted reports whether the conversation of msg is muted by userID, whose backgrounded platforms then
+// skip the real-time push. Notifications are always pushed.
+func (s *Server) isMuted(ctx context.Context, userID string, msg *sdkws.MsgData) bool {
+	if s.conversationLocalCache == nil || msgprocessor.IsNotificationByMsg(msg) {
+		return false
+	}
+	conversationID := msgprocessor.GetConversationIDByMsg(msg)
+	recvMsgOpt, err := s.conversationLocalCache.GetSingleConversationRecvMsgOpt(ctx, userID, conversationID)
+	if err != nil {
+		if !errs.ErrRecordNotFound.Is(err) {
+			log.ZWarn(ctx, "GetSingleConversationRecvMsgOpt failed", err, "userID", userID, "conversationID", conversationID)
+		}
+		return false
+	}
+	return recvMsgOpt == constant.ReceiveNotNotifyMessage
+}
+
 func (s *Server) KickUserOffline(
 	ctx context.Context,
 	req *msggateway.KickUserOfflineReq,
@@ -27,6 +27,7 @@ func RunWsAndServer(conf *config.GlobalConfig, rpcPort, wsPort, prometheusPort i
 	longServer, err := NewWsServer(
 		conf,
 		WithPort(wsPort),
+		WithMqttPort(mqttPort(conf, wsPort)),
 		WithMaxConnNum(int64(conf.LongConnSvr.WebsocketMaxConnNum)),
 		WithHandshakeTimeout(time.Duration(conf.LongConnSvr.WebsocketTimeout)*time.Second),
 		WithMessageMaxMsgLength(conf.LongConnSvr.WebsocketMaxMsgLen),
@@ -35,6 +36,11 @@ func RunWsAndServer(conf *config.GlobalConfig, rpcPort, wsPort, prometheusPort i
 	if err != nil {
 		return err
 	}
+	if conf.LongConnSvr.Conformance.Enable {
+		// The conformance server answers from memory, the rpc side and MQTT stay down.
+		fmt.Println("msg_gateway is in WS conformance mode, do not expose it to clients, record dir: ", conf.LongConnSvr.Conformance.RecordDir)
+		return longServer.Run(make(chan error))
+	}
 
 	hubServer := NewServer(rpcPort, prometheusPort, longServer, conf)
 	netDone := make(chan error)
@@ -44,3 +50,18 @@ func RunWsAndServer(conf *config.GlobalConfig, rpcPort, wsPort, prometheusPort i
 	}()
 	return hubServer.LongConnServer.Run(netDone)
 }
+
+// mqttPort returns the MQTT port paired with the websocket port of this instance, 0 when MQTT is disabled
+// or the gateway runs in conformance mode.
+func mqttPort(conf *config.GlobalConfig, wsPort int) int {
+	ports := conf.LongConnSvr.OpenImMqttPort
+	if !conf.LongConnSvr.MqttEnable || conf.LongConnSvr.Conformance.Enable || len(ports) == 0 {
+		return 0
+	}
+	for i, port := range conf.LongConnSvr.OpenImWsPort {
+		if port == wsPort && i < len(ports) {
+			return ports[i]
+		}
+	}
+	return ports[0]
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/OpenIMSDK/tools/utils"
 	"github.com/go-playground/validator/v10"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	"google.golang.org/protobuf/proto"
 )
@@ -94,6 +95,8 @@ type MessageHandler interface {
 	SendMessage(context context.Context, data *Req) ([]byte, error)
 	SendSignalMessage(context context.Context, data *Req) ([]byte, error)
 	PullMessageBySeqList(context context.Context, data *Req) ([]byte, error)
+	// GetConvMaxReadSeq is only served on connections that negotiated CapabilitySyncProtocol.
+	GetConvMaxReadSeq(context context.Context, data *Req) ([]byte, error)
 	UserLogout(context context.Context, data *Req) ([]byte, error)
 	SetUserDeviceBackground(context context.Context, data *Req) ([]byte, bool, error)
 }
@@ -104,6 +107,7 @@ type GrpcHandler struct {
 	msgRpcClient *rpcclient.MessageRpcClient
 	pushClient   *rpcclient.PushRpcClient
 	validate     *validator.Validate
+	ephemeral    *ephemeralRelay
 }
 
 func NewGrpcHandler(validate *validator.Validate, client discoveryregistry.SvcDiscoveryRegistry, config *config.GlobalConfig) *GrpcHandler {
@@ -111,7 +115,9 @@ func NewGrpcHandler(validate *validator.Validate, client discoveryregistry.SvcDi
 	pushRpcClient := rpcclient.NewPushRpcClient(client, config)
 	return &GrpcHandler{
 		msgRpcClient: &msgRpcClient,
-		pushClient:   &pushRpcClient, validate: validate,
+		pushClient:   &pushRpcClient,
+		validate:     validate,
+		ephemeral:    newEphemeralRelay(config),
 	}
 }
 
@@ -147,6 +153,18 @@ func (g GrpcHandler) SendMessage(ctx context.Context, data *Req) ([]byte, error)
 	if err := g.validate.Struct(&msgData); err != nil {
 		return nil, errs.Wrap(err, "message data validation failed")
 	}
+	correctSendTime(ctx, &msgData)
+
+	if msgprocessor.IsEphemeral(&msgData) {
+		ok, err := g.ephemeral.prepare(&msgData)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			// Over the rate limit, the state is dropped, the sender refreshes it anyway.
+			return proto.Marshal(&msg.SendMsgResp{ClientMsgID: msgData.ClientMsgID, SendTime: msgData.SendTime})
+		}
+	}
 
 	req := msg.SendMsgReq{MsgData: &msgData}
 
@@ -194,6 +212,25 @@ func (g GrpcHandler) PullMessageBySeqList(context context.Context, data *Req) ([
 	return c, nil
 }
 
+func (g GrpcHandler) GetConvMaxReadSeq(context context.Context, data *Req) ([]byte, error) {
+	req := msg.GetConversationsHasReadAndMaxSeqReq{}
+	if err := proto.Unmarshal(data.Data, &req); err != nil {
+		return nil, errs.Wrap(err, "GetConvMaxReadSeq: error unmarshaling request")
+	}
+	if req.UserID != data.SendID {
+		return nil, errs.ErrNoPermission.Wrap("GetConvMaxReadSeq: userID is not the connection user")
+	}
+	resp, err := g.msgRpcClient.Client.GetConversationsHasReadAndMaxSeq(context, &req)
+	if err != nil {
+		return nil, err
+	}
+	c, err := proto.Marshal(resp)
+	if err != nil {
+		return nil, errs.Wrap(err, "GetConvMaxReadSeq: error marshaling response")
+	}
+	return c, nil
+}
+
 func (g GrpcHandler) UserLogout(context context.Context, data *Req) ([]byte, error) {
 	req := push.DelUserPushTokenReq{}
 	if err := proto.Unmarshal(data.Data, &req); err != nil {
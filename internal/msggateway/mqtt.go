@@ -0,0 +1,464 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"google.golang.org/protobuf/proto"
+)
+
+// MQTT devices log in with their userID as username and their token as password, the client
+// identifier is checked as the device fingerprint. Messages of a conversation are published on
+// conversation/<conversationID> in both directions, send results on ack.
+const (
+	mqttConversationTopic = "conversation/"
+	mqttAckTopic          = "ack"
+)
+
+var errMQTTConversation = errors.New("conversation topic is not a chat the user is in")
+
+// mqttMsg is the JSON payload of a message on a conversation topic, devices only fill
+// clientMsgID, contentType, content and ex when sending.
+type mqttMsg struct {
+	ConversationID string          `json:"conversationID,omitempty"`
+	ServerMsgID    string          `json:"serverMsgID,omitempty"`
+	ClientMsgID    string          `json:"clientMsgID"`
+	SendID         string          `json:"sendID,omitempty"`
+	RecvID         string          `json:"recvID,omitempty"`
+	GroupID        string          `json:"groupID,omitempty"`
+	SessionType    int32           `json:"sessionType,omitempty"`
+	ContentType    int32           `json:"contentType"`
+	Content        json.RawMessage `json:"content"`
+	Seq            int64           `json:"seq,omitempty"`
+	SendTime       int64           `json:"sendTime,omitempty"`
+	Ex             string          `json:"ex,omitempty"`
+}
+
+// mqttAck is published on the ack topic for every message a device sent.
+type mqttAck struct {
+	ClientMsgID string `json:"clientMsgID"`
+	ServerMsgID string `json:"serverMsgID,omitempty"`
+	SendTime    int64  `json:"sendTime,omitempty"`
+	ErrCode     int    `json:"errCode"`
+	ErrMsg      string `json:"errMsg,omitempty"`
+}
+
+// mqttConn adapts an MQTT connection to LongConn, so devices are served as regular clients.
+// Publishes are turned into WSSendMsg requests and pushes into publishes on subscribed topics.
+type mqttConn struct {
+	conn          net.Conn
+	reader        *bufio.Reader
+	w             sync.Mutex
+	encoder       Encoder
+	userID        string
+	platformID    int
+	appID         string
+	keepAlive     time.Duration
+	readTimeout   time.Duration
+	readLimit     int64
+	subscriptions sync.Map
+}
+
+func newMQTTConn(conn net.Conn, encoder Encoder) *mqttConn {
+	return &mqttConn{conn: conn, reader: bufio.NewReader(conn), encoder: encoder}
+}
+
+func (m *mqttConn) Close() error {
+	return m.conn.Close()
+}
+
+func (m *mqttConn) write(packet []byte) error {
+	m.w.Lock()
+	defer m.w.Unlock()
+	_, err := m.conn.Write(packet)
+	return err
+}
+
+func (m *mqttConn) WriteMessage(messageType int, message []byte) error {
+	switch messageType {
+	case PongMessage:
+		return m.write(encodeMQTTPacket(mqttPingResp, 0, nil))
+	case MessageBinary:
+	default:
+		return nil
+	}
+	var resp Resp
+	if err := m.encoder.Decode(message, &resp); err != nil {
+		return err
+	}
+	switch resp.ReqIdentifier {
+	case WSPushMsg:
+		return m.publishPush(resp.Data)
+	case WSSendMsg:
+		ack := &mqttAck{ClientMsgID: resp.MsgIncr, ErrCode: resp.ErrCode, ErrMsg: resp.ErrMsg}
+		if resp.ErrCode == 0 {
+			var sendResp msg.SendMsgResp
+			if err := proto.Unmarshal(resp.Data, &sendResp); err != nil {
+				return errs.Wrap(err)
+			}
+			ack.ServerMsgID = sendResp.ServerMsgID
+			ack.SendTime = sendResp.SendTime
+		}
+		return m.publish(mqttAckTopic, ack)
+	default:
+		return nil
+	}
+}
+
+func (m *mqttConn) publishPush(data []byte) error {
+	var push sdkws.PushMessages
+	if err := proto.Unmarshal(data, &push); err != nil {
+		return errs.Wrap(err)
+	}
+	for _, pullMsgs := range []map[string]*sdkws.PullMsgs{push.Msgs, push.NotificationMsgs} {
+		for conversationID, pull := range pullMsgs {
+			topic := mqttConversationTopic + conversationID
+			for _, msgData := range pull.Msgs {
+				if err := m.publish(topic, newMQTTMsg(conversationID, msgData)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// publish sends payload as JSON when the device subscribed to topic.
+func (m *mqttConn) publish(topic string, payload any) error {
+	var subscribed bool
+	m.subscriptions.Range(func(filter, _ any) bool {
+		subscribed = mqttTopicMatch(filter.(string), topic)
+		return !subscribed
+	})
+	if !subscribed {
+		return nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return m.write(encodeMQTTPublish(topic, data))
+}
+
+func newMQTTMsg(conversationID string, msgData *sdkws.MsgData) *mqttMsg {
+	content := json.RawMessage(msgData.Content)
+	if !json.Valid(content) {
+		content, _ = json.Marshal(string(msgData.Content))
+	}
+	return &mqttMsg{
+		ConversationID: conversationID,
+		ServerMsgID:    msgData.ServerMsgID,
+		ClientMsgID:    msgData.ClientMsgID,
+		SendID:         msgData.SendID,
+		RecvID:         msgData.RecvID,
+		GroupID:        msgData.GroupID,
+		SessionType:    msgData.SessionType,
+		ContentType:    msgData.ContentType,
+		Content:        content,
+		Seq:            msgData.Seq,
+		SendTime:       msgData.SendTime,
+		Ex:             msgData.Ex,
+	}
+}
+
+// ReadMessage serves subscriptions and pings of the device and returns its next publish
+// as an encoded WSSendMsg request.
+func (m *mqttConn) ReadMessage() (int, []byte, error) {
+	for {
+		if m.readTimeout > 0 {
+			if err := m.conn.SetReadDeadline(time.Now().Add(m.readTimeout)); err != nil {
+				return 0, nil, err
+			}
+		}
+		packet, err := readMQTTPacket(m.reader, m.readLimit)
+		if err != nil {
+			return 0, nil, err
+		}
+		switch packet.typ {
+		case mqttPublish:
+			pub, err := parseMQTTPublish(packet)
+			if err != nil {
+				return 0, nil, err
+			}
+			switch pub.qos {
+			case 0:
+			case 1:
+				if err := m.write(encodeMQTTAck(mqttPubAck, pub.packetID)); err != nil {
+					return 0, nil, err
+				}
+			default:
+				return 0, nil, errs.Wrap(ErrNotSupportMessageProtocol, "mqtt qos 2 is not supported")
+			}
+			req, err := m.sendMsgReq(pub)
+			if err != nil {
+				if err := m.publish(mqttAckTopic, &mqttAck{ErrCode: errs.ErrArgs.Code(), ErrMsg: err.Error()}); err != nil {
+					return 0, nil, err
+				}
+				continue
+			}
+			return MessageBinary, req, nil
+		case mqttSubscribe:
+			packetID, filters, err := parseMQTTSubscribe(packet.body, true)
+			if err != nil {
+				return 0, nil, err
+			}
+			granted := make([]byte, 0, len(filters))
+			for _, filter := range filters {
+				// Everything is delivered at most once.
+				m.subscriptions.Store(filter, struct{}{})
+				granted = append(granted, 0)
+			}
+			if err := m.write(encodeMQTTAck(mqttSubAck, packetID, granted...)); err != nil {
+				return 0, nil, err
+			}
+		case mqttUnsubscribe:
+			packetID, filters, err := parseMQTTSubscribe(packet.body, false)
+			if err != nil {
+				return 0, nil, err
+			}
+			for _, filter := range filters {
+				m.subscriptions.Delete(filter)
+			}
+			if err := m.write(encodeMQTTAck(mqttUnsubAck, packetID)); err != nil {
+				return 0, nil, err
+			}
+		case mqttPingReq:
+			return PingMessage, nil, nil
+		case mqttDisconnect:
+			return CloseMessage, nil, nil
+		default:
+			return 0, nil, errs.Wrap(ErrNotSupportMessageProtocol, "mqtt packet type "+strconv.Itoa(int(packet.typ)))
+		}
+	}
+}
+
+// sendMsgReq builds the WSSendMsg request of a publish on a conversation topic,
+// the clientMsgID doubles as MsgIncr to match the reply to its ack.
+func (m *mqttConn) sendMsgReq(pub *mqttPublishPacket) ([]byte, error) {
+	if !strings.HasPrefix(pub.topic, mqttConversationTopic) {
+		return nil, errs.Wrap(errMQTTConversation, pub.topic)
+	}
+	conversationID := strings.TrimPrefix(pub.topic, mqttConversationTopic)
+	var in mqttMsg
+	if err := json.Unmarshal(pub.payload, &in); err != nil {
+		return nil, errs.Wrap(err, "mqtt payload is not a json message")
+	}
+	msgData := &sdkws.MsgData{
+		SendID:           m.userID,
+		ClientMsgID:      in.ClientMsgID,
+		SenderPlatformID: int32(m.platformID),
+		MsgFrom:          constant.UserMsgType,
+		ContentType:      in.ContentType,
+		Content:          in.Content,
+		CreateTime:       utils.GetCurrentTimestampByMill(),
+		Ex:               in.Ex,
+	}
+	var text string
+	if json.Unmarshal(in.Content, &text) == nil {
+		msgData.Content = []byte(text)
+	}
+	if msgData.ClientMsgID == "" {
+		msgData.ClientMsgID = utils.GetMsgID(m.userID)
+	}
+	switch {
+	case strings.HasPrefix(conversationID, "sg_"):
+		msgData.SessionType = constant.SuperGroupChatType
+		msgData.GroupID = strings.TrimPrefix(conversationID, "sg_")
+	case strings.HasPrefix(conversationID, "si_"):
+		// Single chat IDs join both user IDs, the receiver is the one that is not the sender.
+		userIDs := strings.TrimPrefix(conversationID, "si_")
+		msgData.SessionType = constant.SingleChatType
+		switch {
+		case strings.HasPrefix(userIDs, m.userID+"_"):
+			msgData.RecvID = strings.TrimPrefix(userIDs, m.userID+"_")
+		case strings.HasSuffix(userIDs, "_"+m.userID):
+			msgData.RecvID = strings.TrimSuffix(userIDs, "_"+m.userID)
+		default:
+			return nil, errs.Wrap(errMQTTConversation, conversationID)
+		}
+	default:
+		return nil, errs.Wrap(errMQTTConversation, conversationID)
+	}
+	data, err := proto.Marshal(msgData)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return m.encoder.Encode(Req{
+		ReqIdentifier: WSSendMsg,
+		SendID:        m.userID,
+		OperationID:   utils.OperationIDGenerator(),
+		MsgIncr:       msgData.ClientMsgID,
+		Data:          data,
+	})
+}
+
+// SetReadDeadline keeps the connection at least for one and a half keep alive intervals,
+// the deadline is renewed for every packet.
+func (m *mqttConn) SetReadDeadline(timeout time.Duration) error {
+	if keepAlive := m.keepAlive * 3 / 2; keepAlive > timeout {
+		timeout = keepAlive
+	}
+	m.readTimeout = timeout
+	return m.conn.SetReadDeadline(time.Now().Add(timeout))
+}
+
+func (m *mqttConn) SetWriteDeadline(timeout time.Duration) error {
+	if timeout <= 0 {
+		return errs.Wrap(errors.New("timeout must be greater than 0"))
+	}
+	return m.conn.SetWriteDeadline(time.Now().Add(timeout))
+}
+
+func (m *mqttConn) Dial(_ string, _ http.Header) (*http.Response, error) {
+	return nil, errs.Wrap(ErrNotSupportMessageProtocol, "mqtt connections are accepted only")
+}
+
+func (m *mqttConn) IsNil() bool {
+	return m.conn == nil
+}
+
+func (m *mqttConn) SetConnNil() {
+	m.conn = nil
+}
+
+func (m *mqttConn) SetReadLimit(limit int64) {
+	m.readLimit = limit
+}
+
+func (m *mqttConn) SetPongHandler(_ PingPongHandler) {}
+
+func (m *mqttConn) SetPingHandler(_ PingPongHandler) {}
+
+func (m *mqttConn) GenerateLongConn(_ http.ResponseWriter, _ *http.Request) error {
+	return errs.Wrap(ErrNotSupportMessageProtocol, "mqtt connections are not upgraded from http")
+}
+
+// serveMQTT accepts MQTT devices until the listener is closed.
+func (ws *WsServer) serveMQTT(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			log.ZWarn(context.Background(), "mqtt accept err", err)
+			continue
+		}
+		go ws.mqttHandler(conn)
+	}
+}
+
+func (ws *WsServer) mqttHandler(conn net.Conn) {
+	mqttLongConn := newMQTTConn(conn, ws.Encoder)
+	token, code, err := ws.acceptMQTT(mqttLongConn)
+	if err != nil {
+		log.ZWarn(context.Background(), "mqtt connect refused", err, "remoteAddr", conn.RemoteAddr().String(), "code", code)
+	}
+	if code != mqttConnAccepted {
+		_ = mqttLongConn.write(encodeMQTTPacket(mqttConnAck, 0, []byte{0, code}))
+		_ = conn.Close()
+		return
+	}
+	if err := mqttLongConn.write(encodeMQTTPacket(mqttConnAck, 0, []byte{0, mqttConnAccepted})); err != nil {
+		_ = conn.Close()
+		return
+	}
+	connContext := newMQTTContext(conn.RemoteAddr().String(), mqttLongConn.userID, mqttLongConn.platformID, mqttLongConn.appID, token)
+	client := ws.clientPool.Get().(*Client)
+	client.ResetClient(connContext, mqttLongConn, false, false, ws, token, 0)
+	ws.registerChan <- client
+	go client.readMessage()
+}
+
+// acceptMQTT reads the CONNECT packet and verifies the token with the same checks as websocket logins.
+func (ws *WsServer) acceptMQTT(conn *mqttConn) (token string, code byte, err error) {
+	if err := conn.conn.SetReadDeadline(time.Now().Add(ws.handshakeTimeout)); err != nil {
+		return "", mqttConnRefusedNoServer, errs.Wrap(err)
+	}
+	packet, err := readMQTTPacket(conn.reader, maxMessageSize)
+	if err != nil {
+		return "", mqttConnRefusedProtocol, errs.Wrap(err)
+	}
+	if packet.typ != mqttConnect {
+		return "", mqttConnRefusedProtocol, errs.Wrap(errMQTTMalformed, "first packet is not connect")
+	}
+	connect, err := parseMQTTConnect(packet.body)
+	if err != nil {
+		return "", mqttConnRefusedProtocol, err
+	}
+	if ws.onlineUserConnNum.Load() >= ws.wsMaxConnNum {
+		return "", mqttConnRefusedNoServer, errs.ErrConnOverMaxNumLimit.Wrap("over max conn num limit")
+	}
+	// MQTT has no query, the app of the device is the one its token was issued for.
+	claim, err := authverify.ParseClaims(connect.password, ws.globalConfig)
+	if err != nil {
+		return "", mqttConnRefusedBadAuth, err
+	}
+	if err := authverify.WsVerifyToken(connect.password, connect.username, claim.PlatformID, claim.AppID, ws.globalConfig); err != nil {
+		return "", mqttConnRefusedBadAuth, err
+	}
+	ctx := context.Background()
+	if err := ws.checkTokenStatus(ctx, connect.username, claim.PlatformID, connect.password); err != nil {
+		return "", mqttConnRefusedNotAllowed, err
+	}
+	if err := ws.checkFingerprint(ctx, connect.clientID, connect.password); err != nil {
+		return "", mqttConnRefusedNotAllowed, err
+	}
+	if err := conn.conn.SetReadDeadline(time.Time{}); err != nil {
+		return "", mqttConnRefusedNoServer, errs.Wrap(err)
+	}
+	conn.userID = connect.username
+	conn.platformID = claim.PlatformID
+	conn.appID = claim.AppID
+	conn.keepAlive = time.Duration(connect.keepAlive) * time.Second
+	return connect.password, mqttConnAccepted, nil
+}
+
+// newMQTTContext carries the login of an MQTT device the way a websocket query does.
+func newMQTTContext(remoteAddr string, userID string, platformID int, appID string, token string) *UserConnContext {
+	query := url.Values{}
+	query.Set(WsUserID, userID)
+	query.Set(PlatformID, strconv.Itoa(platformID))
+	query.Set(Token, token)
+	if appID != "" {
+		query.Set(tenant.AppIDKey, appID)
+	}
+	return &UserConnContext{
+		Req:        &http.Request{URL: &url.URL{Path: "/mqtt", RawQuery: query.Encode()}, Header: http.Header{}},
+		Path:       "/mqtt",
+		Method:     "MQTT",
+		RemoteAddr: remoteAddr,
+		ConnID:     utils.Md5(remoteAddr + "_" + strconv.Itoa(int(utils.GetCurrentTimestampByMill()))),
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/OpenIMSDK/tools/errs"
+)
+
+// MQTT 3.1.1 control packet types, only the subset used by IM devices is served.
+const (
+	mqttConnect     = 1
+	mqttConnAck     = 2
+	mqttPublish     = 3
+	mqttPubAck      = 4
+	mqttSubscribe   = 8
+	mqttSubAck      = 9
+	mqttUnsubscribe = 10
+	mqttUnsubAck    = 11
+	mqttPingReq     = 12
+	mqttPingResp    = 13
+	mqttDisconnect  = 14
+)
+
+// CONNACK return codes.
+const (
+	mqttConnAccepted          = 0
+	mqttConnRefusedProtocol   = 1
+	mqttConnRefusedNoServer   = 3
+	mqttConnRefusedBadAuth    = 4
+	mqttConnRefusedNotAllowed = 5
+)
+
+var errMQTTMalformed = errors.New("malformed mqtt packet")
+
+type mqttPacket struct {
+	typ   byte
+	flags byte
+	body  []byte
+}
+
+type mqttConnectPacket struct {
+	protocolLevel byte
+	clientID      string
+	username      string
+	password      string
+	keepAlive     uint16
+}
+
+type mqttPublishPacket struct {
+	topic    string
+	qos      byte
+	packetID uint16
+	payload  []byte
+}
+
+func readMQTTPacket(r *bufio.Reader, maxSize int64) (*mqttPacket, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	var length, multiplier int64 = 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return nil, errs.Wrap(errMQTTMalformed, "remaining length too long")
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		length += int64(b&0x7f) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+	if maxSize > 0 && length > maxSize {
+		return nil, errs.Wrap(errMQTTMalformed, "packet over read limit")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return &mqttPacket{typ: header >> 4, flags: header & 0x0f, body: body}, nil
+}
+
+func encodeMQTTPacket(typ byte, flags byte, body []byte) []byte {
+	buf := make([]byte, 0, len(body)+5)
+	buf = append(buf, typ<<4|flags)
+	length := len(body)
+	for {
+		b := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			b |= 0x80
+		}
+		buf = append(buf, b)
+		if length == 0 {
+			break
+		}
+	}
+	return append(buf, body...)
+}
+
+func readMQTTString(b []byte) (string, []byte, error) {
+	data, rest, err := readMQTTBytes(b)
+	return string(data), rest, err
+}
+
+func readMQTTBytes(b []byte) ([]byte, []byte, error) {
+	if len(b) < 2 {
+		return nil, nil, errs.Wrap(errMQTTMalformed)
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	if len(b) < 2+n {
+		return nil, nil, errs.Wrap(errMQTTMalformed)
+	}
+	return b[2 : 2+n], b[2+n:], nil
+}
+
+func appendMQTTString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+func parseMQTTConnect(body []byte) (*mqttConnectPacket, error) {
+	protocol, rest, err := readMQTTString(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < 4 {
+		return nil, errs.Wrap(errMQTTMalformed)
+	}
+	c := &mqttConnectPacket{protocolLevel: rest[0]}
+	if (protocol != "MQTT" || c.protocolLevel != 4) && (protocol != "MQIsdp" || c.protocolLevel != 3) {
+		return c, errs.Wrap(errMQTTMalformed, "unsupported protocol "+protocol)
+	}
+	flags := rest[1]
+	c.keepAlive = binary.BigEndian.Uint16(rest[2:4])
+	if c.clientID, rest, err = readMQTTString(rest[4:]); err != nil {
+		return nil, err
+	}
+	if flags&0x04 != 0 {
+		// The will message is not supported, it is read past and ignored.
+		if _, rest, err = readMQTTBytes(rest); err != nil {
+			return nil, err
+		}
+		if _, rest, err = readMQTTBytes(rest); err != nil {
+			return nil, err
+		}
+	}
+	if flags&0x80 != 0 {
+		if c.username, rest, err = readMQTTString(rest); err != nil {
+			return nil, err
+		}
+	}
+	if flags&0x40 != 0 {
+		if c.password, _, err = readMQTTString(rest); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func parseMQTTPublish(p *mqttPacket) (*mqttPublishPacket, error) {
+	topic, rest, err := readMQTTString(p.body)
+	if err != nil {
+		return nil, err
+	}
+	pub := &mqttPublishPacket{topic: topic, qos: (p.flags >> 1) & 0x03}
+	if pub.qos > 0 {
+		if len(rest) < 2 {
+			return nil, errs.Wrap(errMQTTMalformed)
+		}
+		pub.packetID = binary.BigEndian.Uint16(rest)
+		rest = rest[2:]
+	}
+	pub.payload = rest
+	return pub, nil
+}
+
+// parseMQTTSubscribe returns the packet id and topic filters of a SUBSCRIBE or UNSUBSCRIBE,
+// withQoS is set for SUBSCRIBE where every filter is followed by its requested QoS.
+func parseMQTTSubscribe(body []byte, withQoS bool) (uint16, []string, error) {
+	if len(body) < 2 {
+		return 0, nil, errs.Wrap(errMQTTMalformed)
+	}
+	packetID := binary.BigEndian.Uint16(body)
+	rest := body[2:]
+	var filters []string
+	for len(rest) > 0 {
+		filter, next, err := readMQTTString(rest)
+		if err != nil {
+			return 0, nil, err
+		}
+		if withQoS {
+			if len(next) < 1 {
+				return 0, nil, errs.Wrap(errMQTTMalformed)
+			}
+			next = next[1:]
+		}
+		filters = append(filters, filter)
+		rest = next
+	}
+	return packetID, filters, nil
+}
+
+func encodeMQTTPublish(topic string, payload []byte) []byte {
+	body := appendMQTTString(make([]byte, 0, len(topic)+len(payload)+2), topic)
+	return encodeMQTTPacket(mqttPublish, 0, append(body, payload...))
+}
+
+func encodeMQTTAck(typ byte, packetID uint16, payload ...byte) []byte {
+	body := binary.BigEndian.AppendUint16(make([]byte, 0, 2+len(payload)), packetID)
+	return encodeMQTTPacket(typ, 0, append(body, payload...))
+}
+
+// mqttTopicMatch reports whether topic matches filter, with the + and # wildcards.
+func mqttTopicMatch(filter string, topic string) bool {
+	filterLevels := strings.Split(filter, "/")
+	topicLevels := strings.Split(topic, "/")
+	for i, level := range filterLevels {
+		if level == "#" {
+			return true
+		}
+		if i >= len(topicLevels) {
+			return false
+		}
+		if level != "+" && level != topicLevels[i] {
+			return false
+		}
+	}
+	return len(filterLevels) == len(topicLevels)
+}
@@ -0,0 +1,63 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMQTTPacketRoundTrip(t *testing.T) {
+	payload := bytes.Repeat([]byte("a"), 300)
+	packet, err := readMQTTPacket(bufio.NewReader(bytes.NewReader(encodeMQTTPublish("conversation/sg_1", payload))), 0)
+	assert.Nil(t, err)
+	assert.EqualValues(t, mqttPublish, packet.typ)
+
+	pub, err := parseMQTTPublish(packet)
+	assert.Nil(t, err)
+	assert.Equal(t, "conversation/sg_1", pub.topic)
+	assert.EqualValues(t, 0, pub.qos)
+	assert.Equal(t, payload, pub.payload)
+
+	_, err = readMQTTPacket(bufio.NewReader(bytes.NewReader(encodeMQTTPublish("t", payload))), 100)
+	assert.NotNil(t, err)
+}
+
+func TestParseMQTTConnect(t *testing.T) {
+	body := appendMQTTString(nil, "MQTT")
+	body = append(body, 4, 0xc2, 0, 60)
+	body = appendMQTTString(body, "device-1")
+	body = appendMQTTString(body, "user1")
+	body = appendMQTTString(body, "token")
+
+	connect, err := parseMQTTConnect(body)
+	assert.Nil(t, err)
+	assert.Equal(t, "device-1", connect.clientID)
+	assert.Equal(t, "user1", connect.username)
+	assert.Equal(t, "token", connect.password)
+	assert.EqualValues(t, 60, connect.keepAlive)
+}
+
+func TestMQTTTopicMatch(t *testing.T) {
+	assert.True(t, mqttTopicMatch("conversation/#", "conversation/sg_1"))
+	assert.True(t, mqttTopicMatch("conversation/+", "conversation/si_a_b"))
+	assert.True(t, mqttTopicMatch("ack", "ack"))
+	assert.False(t, mqttTopicMatch("conversation/+", "ack"))
+	assert.False(t, mqttTopicMatch("conversation/sg_1", "conversation/sg_2"))
+	assert.False(t, mqttTopicMatch("conversation", "conversation/sg_1"))
+}
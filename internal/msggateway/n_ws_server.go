@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"strconv"
 	"sync"
@@ -37,6 +38,8 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"github.com/openimsdk/open-im-server/v3/pkg/loginpolicy"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	"github.com/redis/go-redis/v9"
 	"golang.org/x/sync/errgroup"
@@ -51,6 +54,7 @@ type LongConnServer interface {
 	SetCacheHandler(cache cache.MsgModel)
 	SetDiscoveryRegistry(client discoveryregistry.SvcDiscoveryRegistry, config *config.GlobalConfig)
 	KickUserConn(client *Client) error
+	touchSession(client *Client) error
 	UnRegister(c *Client)
 	SetKickHandlerInfo(i *kickHandler)
 	Compressor
@@ -68,6 +72,7 @@ type LongConnServer interface {
 type WsServer struct {
 	globalConfig      *config.GlobalConfig
 	port              int
+	mqttPort          int
 	wsMaxConnNum      int64
 	registerChan      chan *Client
 	unregisterChan    chan *Client
@@ -82,6 +87,7 @@ type WsServer struct {
 	cache             cache.MsgModel
 	userClient        *rpcclient.UserRpcClient
 	disCov            discoveryregistry.SvcDiscoveryRegistry
+	conformance       *conformanceServer
 	Compressor
 	Encoder
 	MessageHandler
@@ -101,6 +107,9 @@ func (ws *WsServer) SetDiscoveryRegistry(disCov discoveryregistry.SvcDiscoveryRe
 }
 
 func (ws *WsServer) SetUserOnlineStatus(ctx context.Context, client *Client, status int32) {
+	if ws.conformance != nil {
+		return
+	}
 	err := ws.userClient.SetUserStatus(ctx, client.UserID, status, client.PlatformID)
 	if err != nil {
 		log.ZWarn(ctx, "SetUserStatus err", err)
@@ -143,14 +152,18 @@ func (ws *WsServer) GetUserPlatformCons(userID string, platform int) ([]*Client,
 }
 
 func NewWsServer(globalConfig *config.GlobalConfig, opts ...Option) (*WsServer, error) {
+	if err := authverify.CheckStandbyPolicy(globalConfig); err != nil {
+		return nil, err
+	}
 	var config configs
 	for _, o := range opts {
 		o(&config)
 	}
 	v := validator.New()
-	return &WsServer{
+	ws := &WsServer{
 		globalConfig:     globalConfig,
 		port:             config.port,
+		mqttPort:         config.mqttPort,
 		wsMaxConnNum:     config.maxConnNum,
 		writeBufferSize:  config.writeBufferSize,
 		handshakeTimeout: config.handshakeTimeout,
@@ -166,32 +179,26 @@ func NewWsServer(globalConfig *config.GlobalConfig, opts ...Option) (*WsServer,
 		clients:         newUserMap(),
 		Compressor:      NewGzipCompressor(),
 		Encoder:         NewGobEncoder(),
-	}, nil
+	}
+	if conf := globalConfig.LongConnSvr.Conformance; conf.Enable {
+		ws.conformance = newConformanceServer(conf.RecordDir)
+		ws.MessageHandler = ws.conformance
+	}
+	return ws, nil
 }
 
 func (ws *WsServer) Run(done chan error) error {
 	var (
-		client       *Client
 		netErr       error
 		shutdownDone = make(chan struct{}, 1)
 	)
 
 	server := http.Server{Addr: ":" + utils.IntToString(ws.port), Handler: nil}
 
-	go func() {
-		for {
-			select {
-			case <-shutdownDone:
-				return
-			case client = <-ws.registerChan:
-				ws.registerClient(client)
-			case client = <-ws.unregisterChan:
-				ws.unregisterClient(client)
-			case onlineInfo := <-ws.kickHandlerChan:
-				ws.multiTerminalLoginChecker(onlineInfo.clientOK, onlineInfo.oldClients, onlineInfo.newClient)
-			}
-		}
-	}()
+	go ws.dispatch(shutdownDone)
+	if ws.globalConfig.LongConnSvr.PushAck.Enable {
+		go ws.redeliverPushes(shutdownDone)
+	}
 	netDone := make(chan struct{}, 1)
 	go func() {
 		http.HandleFunc("/", ws.wsHandler)
@@ -201,6 +208,14 @@ func (ws *WsServer) Run(done chan error) error {
 			close(netDone)
 		}
 	}()
+	if ws.mqttPort > 0 {
+		listener, err := net.Listen("tcp", ":"+utils.IntToString(ws.mqttPort))
+		if err != nil {
+			return errs.Wrap(err, "mqtt start err", utils.IntToString(ws.mqttPort))
+		}
+		defer listener.Close()
+		go ws.serveMQTT(listener)
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 	var err error
@@ -220,6 +235,22 @@ func (ws *WsServer) Run(done chan error) error {
 
 }
 
+// dispatch serializes the registration, unregistration and kicks of clients until shutdownDone is closed.
+func (ws *WsServer) dispatch(shutdownDone chan struct{}) {
+	for {
+		select {
+		case <-shutdownDone:
+			return
+		case client := <-ws.registerChan:
+			ws.registerClient(client)
+		case client := <-ws.unregisterChan:
+			ws.unregisterClient(client)
+		case onlineInfo := <-ws.kickHandlerChan:
+			ws.multiTerminalLoginChecker(onlineInfo.clientOK, onlineInfo.oldClients, onlineInfo.newClient)
+		}
+	}
+}
+
 var concurrentRequest = 3
 
 func (ws *WsServer) sendUserOnlineInfoToOtherNode(ctx context.Context, client *Client) error {
@@ -267,6 +298,7 @@ func (ws *WsServer) registerClient(client *Client) {
 		oldClients []*Client
 	)
 	oldClients, userOK, clientOK = ws.clients.Get(client.UserID, client.PlatformID)
+	prommetrics.OnlinePlatformGauge.WithLabelValues(constant.PlatformIDToName(client.PlatformID)).Inc()
 	if !userOK {
 		ws.clients.Set(client.UserID, client)
 		log.ZDebug(client.ctx, "user not exist", "userID", client.UserID, "platformID", client.PlatformID)
@@ -288,7 +320,7 @@ func (ws *WsServer) registerClient(client *Client) {
 	}
 
 	wg := sync.WaitGroup{}
-	if ws.globalConfig.Envs.Discovery == "zookeeper" {
+	if ws.globalConfig.Envs.Discovery == "zookeeper" && ws.conformance == nil {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
@@ -300,6 +332,11 @@ func (ws *WsServer) registerClient(client *Client) {
 		defer wg.Done()
 		ws.SetUserOnlineStatus(client.ctx, client, constant.Online)
 	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ws.recordSession(client)
+	}()
 
 	wg.Wait()
 
@@ -331,6 +368,17 @@ func (ws *WsServer) KickUserConn(client *Client) error {
 }
 
 func (ws *WsServer) multiTerminalLoginChecker(clientOK bool, oldClients []*Client, newClient *Client) {
+	if ws.conformance != nil {
+		// Sessions open several connections of the same user and platform on purpose.
+		return
+	}
+	matrix, err := loginpolicy.Load(newClient.ctx, ws.cache, ws.globalConfig)
+	if err != nil {
+		log.ZWarn(newClient.ctx, "load login policy matrix failed", err, "userID", newClient.UserID)
+	} else if matrix.Enable {
+		ws.loginPolicyMatrixChecker(matrix, newClient)
+		return
+	}
 	switch ws.globalConfig.MultiLoginPolicy {
 	case constant.DefalutNotKick:
 	case constant.PCAndOther:
@@ -404,14 +452,49 @@ func (ws *WsServer) multiTerminalLoginChecker(clientOK bool, oldClients []*Clien
 	}
 }
 
+// loginPolicyMatrixChecker kicks the oldest connections of the new client's platform group beyond its limit.
+func (ws *WsServer) loginPolicyMatrixChecker(matrix *loginpolicy.Matrix, newClient *Client) {
+	group := matrix.Group(newClient.PlatformID)
+	if group == nil {
+		return
+	}
+	allClients, _ := ws.clients.GetAll(newClient.UserID)
+	var groupClients []*Client
+	for _, c := range allClients {
+		if matrix.Group(c.PlatformID) == group {
+			groupClients = append(groupClients, c)
+		}
+	}
+	// Clients are kept in connection order, so the oldest come first.
+	if len(groupClients) < group.MaxOnline {
+		return
+	}
+	kickClients := groupClients[:len(groupClients)-group.MaxOnline+1]
+	ws.clients.deleteClients(newClient.UserID, kickClients)
+	for _, c := range kickClients {
+		if err := c.KickOnlineMessage(); err != nil {
+			log.ZWarn(c.ctx, "KickOnlineMessage", err)
+		}
+		if c.token == newClient.ctx.GetToken() {
+			continue
+		}
+		err := ws.cache.SetTokenMapByUidPid(newClient.ctx, c.UserID, c.PlatformID, map[string]int{c.token: constant.KickedToken})
+		if err != nil {
+			log.ZWarn(newClient.ctx, "SetTokenMapByUidPid err", err, "userID", c.UserID, "platformID", c.PlatformID)
+		}
+	}
+}
+
 func (ws *WsServer) unregisterClient(client *Client) {
 	defer ws.clientPool.Put(client)
+	ws.conformance.leave(client.conformance)
 	isDeleteUser := ws.clients.delete(client.UserID, client.ctx.GetRemoteAddr())
 	if isDeleteUser {
 		ws.onlineUserNum.Add(-1)
 		prommetrics.OnlineUserGauge.Dec()
 	}
 	ws.onlineUserConnNum.Add(-1)
+	prommetrics.OnlinePlatformGauge.WithLabelValues(constant.PlatformIDToName(client.PlatformID)).Dec()
 	ws.SetUserOnlineStatus(client.ctx, client, constant.Offline)
 	log.ZInfo(client.ctx, "user offline", "close reason", client.closedErr, "online user Num", ws.onlineUserNum.Load(), "online user conn Num",
 		ws.onlineUserConnNum.Load(),
@@ -443,61 +526,123 @@ func (ws *WsServer) ParseWSArgs(r *http.Request) (args *WSArgs, err error) {
 		return nil, errs.ErrConnArgsErr.Wrap("platformID is not int")
 	}
 	v.PlatformID = platformID
-	if err = authverify.WsVerifyToken(v.Token, v.UserID, ws.globalConfig.Secret, platformID); err != nil {
-		return nil, err
-	}
 	if query.Get(Compression) == GzipCompressionProtocol {
 		v.Compression = true
 	}
 	if r.Header.Get(Compression) == GzipCompressionProtocol {
 		v.Compression = true
 	}
-	m, err := ws.cache.GetTokensWithoutError(context.Background(), v.UserID, platformID)
-	if err != nil {
+	v.Capabilities = negotiateCapabilities(query.Get(Capabilities), v.Compression)
+	if v.Capabilities&CapabilityCompression != 0 {
+		v.Compression = true
+	}
+	if !ws.globalConfig.LongConnSvr.PushAck.Enable {
+		v.Capabilities &^= CapabilityPushAck
+	}
+	if ws.conformance != nil {
+		// Conformance runs have no user service, any token is accepted.
+		return &v, nil
+	}
+	if err = authverify.WsVerifyToken(v.Token, v.UserID, platformID, query.Get(tenant.AppIDKey), ws.globalConfig); err != nil {
 		return nil, err
 	}
-	if v, ok := m[v.Token]; ok {
-		switch v {
-		case constant.NormalToken:
-		case constant.KickedToken:
-			return nil, errs.ErrTokenKicked.Wrap()
-		default:
-			return nil, errs.ErrTokenUnknown.Wrap(fmt.Sprintf("token status is %d", v))
-		}
-	} else {
-		return nil, errs.ErrTokenNotExist.Wrap()
+	if err := ws.checkTokenStatus(context.Background(), v.UserID, platformID, v.Token); err != nil {
+		return nil, err
+	}
+	fingerprint := query.Get(authverify.DeviceFingerprint)
+	if fingerprint == "" {
+		fingerprint = r.Header.Get(authverify.DeviceFingerprint)
+	}
+	if err := ws.checkFingerprint(r.Context(), fingerprint, v.Token); err != nil {
+		return nil, err
+	}
+	if ws.globalConfig.ClockSkew.Enable {
+		v.ClockSkew = parseClockSkew(query.Get(ClientTime))
 	}
 	return &v, nil
 }
 
+// checkTokenStatus rejects a token that was kicked or is no longer stored for the user's platform.
+func (ws *WsServer) checkTokenStatus(ctx context.Context, userID string, platformID int, token string) error {
+	m, err := ws.cache.GetTokensWithoutError(ctx, userID, platformID)
+	if err != nil {
+		return authverify.StandbyVerify(ctx, token, err, ws.globalConfig)
+	}
+	status, ok := m[token]
+	if !ok {
+		return errs.ErrTokenNotExist.Wrap()
+	}
+	switch status {
+	case constant.NormalToken:
+		return nil
+	case constant.KickedToken:
+		return errs.ErrTokenKicked.Wrap()
+	default:
+		return errs.ErrTokenUnknown.Wrap(fmt.Sprintf("token status is %d", status))
+	}
+}
+
+// checkFingerprint rejects a token bound to another device.
+func (ws *WsServer) checkFingerprint(ctx context.Context, fingerprint string, token string) error {
+	if !ws.globalConfig.TokenPolicy.Fingerprint.Enable {
+		return nil
+	}
+	bound, err := ws.cache.GetTokenFingerprint(ctx, token)
+	if err != nil && errs.Unwrap(err) != redis.Nil {
+		return authverify.StandbyVerify(ctx, token, err, ws.globalConfig)
+	}
+	return authverify.CheckFingerprint(ctx, bound, fingerprint, ws.globalConfig)
+}
+
 type WSArgs struct {
 	Token       string
 	UserID      string
 	PlatformID  int
 	Compression bool
 	MsgResp     bool
+	ClockSkew   int64
+	// Capabilities is the bitmap negotiated with the client.
+	Capabilities uint64
 }
 
 func (ws *WsServer) wsHandler(w http.ResponseWriter, r *http.Request) {
 	connContext := newContext(w, r)
 	args, pErr := ws.ParseWSArgs(r)
+	conformance := ws.conformance.join(connContext, args, pErr)
 	var wsLongConn *GWebSocket
 	if args.MsgResp {
 		wsLongConn = newGWebSocket(WebSocket, ws.handshakeTimeout, ws.writeBufferSize)
 		if err := wsLongConn.GenerateLongConn(w, r); err != nil {
+			ws.conformance.leave(conformance)
 			httpError(connContext, err)
 			return
 		}
-		data, err := json.Marshal(apiresp.ParseError(pErr))
+		resp := apiresp.ParseError(pErr)
+		if pErr == nil {
+			resp.Data = &HandshakeData{
+				ClockSkew:    args.ClockSkew,
+				ServerTime:   utils.GetCurrentTimestampByMill(),
+				Capabilities: args.Capabilities,
+			}
+		}
+		data, err := json.Marshal(resp)
 		if err != nil {
+			ws.conformance.leave(conformance)
 			_ = wsLongConn.Close()
 			return
 		}
-		if err := wsLongConn.WriteMessage(MessageText, data); err != nil {
+		conformance.recordHandshake(resp.ErrCode, resp.ErrMsg)
+		frame := MessageText
+		if args.Capabilities&CapabilityBinaryFrames != 0 {
+			frame = MessageBinary
+		}
+		if err := wsLongConn.WriteMessage(frame, data); err != nil {
+			ws.conformance.leave(conformance)
 			_ = wsLongConn.Close()
 			return
 		}
 		if pErr != nil {
+			ws.conformance.leave(conformance)
 			_ = wsLongConn.Close()
 			return
 		}
@@ -508,12 +653,19 @@ func (ws *WsServer) wsHandler(w http.ResponseWriter, r *http.Request) {
 		}
 		wsLongConn = newGWebSocket(WebSocket, ws.handshakeTimeout, ws.writeBufferSize)
 		if err := wsLongConn.GenerateLongConn(w, r); err != nil {
+			ws.conformance.leave(conformance)
 			httpError(connContext, err)
 			return
 		}
 	}
 	client := ws.clientPool.Get().(*Client)
-	client.ResetClient(connContext, wsLongConn, connContext.GetBackground(), args.Compression, ws, args.Token)
+	client.ResetClient(connContext, wsLongConn, connContext.GetBackground(), args.Compression, ws, args.Token, args.ClockSkew)
+	client.conformance = conformance
+	client.capabilities = args.Capabilities
+	if client.hasCapability(CapabilityPushAck) {
+		client.pushAcks = newPushAckTracker(ws.globalConfig.LongConnSvr.PushAck.MaxPending)
+	}
+	conformance.attach(client)
 	ws.registerChan <- client
 	go client.readMessage()
 }
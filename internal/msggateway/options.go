@@ -29,6 +29,8 @@ type (
 		messageMaxMsgLength int
 		// Websocket write buffer, default: 4096, 4kb.
 		writeBufferSize int
+		// MQTT listening port, 0 disables the MQTT endpoint
+		mqttPort int
 	}
 )
 
@@ -61,3 +63,9 @@ func WithWriteBufferSize(size int) Option {
 		opt.writeBufferSize = size
 	}
 }
+
+func WithMqttPort(port int) Option {
+	return func(opt *configs) {
+		opt.mqttPort = port
+	}
+}
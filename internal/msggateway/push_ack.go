@@ -0,0 +1,241 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"golang.org/x/time/rate"
+)
+
+// minRedeliverInterval bounds how often the gateway looks for unacked pushes.
+const minRedeliverInterval = 100 * time.Millisecond
+
+// PushAckReq is the data of WSPushAck, the client acks every pushed seq up to Seqs[conversationID].
+type PushAckReq struct {
+	Seqs map[string]int64 `json:"seqs"`
+}
+
+// OutOfSyncTips is the data of WSOutOfSync, the client missed pushes of each conversation and pulls it up to
+// Seqs[conversationID].
+type OutOfSyncTips struct {
+	Seqs map[string]int64 `json:"seqs"`
+}
+
+type pendingPush struct {
+	msg      *sdkws.MsgData
+	pushedAt time.Time
+	// attempts is the number of times the msg was written, the first push included.
+	attempts int
+}
+
+// pushAckTracker keeps the msgs pushed to a connection that negotiated CapabilityPushAck until the client acks
+// them.
+type pushAckTracker struct {
+	lock       sync.Mutex
+	pending    map[string][]*pendingPush
+	count      int
+	maxPending int
+}
+
+func newPushAckTracker(maxPending int) *pushAckTracker {
+	return &pushAckTracker{pending: make(map[string][]*pendingPush), maxPending: maxPending}
+}
+
+// track adds msg pushed at now, it returns false when maxPending msgs are already waiting for an ack.
+func (t *pushAckTracker) track(conversationID string, msg *sdkws.MsgData, now time.Time) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.maxPending > 0 && t.count >= t.maxPending {
+		return false
+	}
+	t.pending[conversationID] = append(t.pending[conversationID], &pendingPush{msg: msg, pushedAt: now, attempts: 1})
+	t.count++
+	return true
+}
+
+// ack drops the msgs of each conversation up to its seq.
+func (t *pushAckTracker) ack(seqs map[string]int64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for conversationID, seq := range seqs {
+		pending := t.pending[conversationID]
+		kept := pending[:0]
+		for _, p := range pending {
+			if p.msg.Seq > seq {
+				kept = append(kept, p)
+			}
+		}
+		t.count -= len(pending) - len(kept)
+		if len(kept) == 0 {
+			delete(t.pending, conversationID)
+		} else {
+			t.pending[conversationID] = kept
+		}
+	}
+}
+
+// due returns the msgs unacked for timeout that are to be written again, as long as allow grants it, and the
+// conversations whose msgs were written maxAttempts times with the highest seq pushed. The msgs of these
+// conversations are dropped, the client is expected to pull them.
+func (t *pushAckTracker) due(now time.Time, timeout time.Duration, maxAttempts int, allow func() bool) ([]*sdkws.MsgData, map[string]int64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	var (
+		redeliver []*sdkws.MsgData
+		outOfSync map[string]int64
+	)
+	conversationIDs := make([]string, 0, len(t.pending))
+	for conversationID := range t.pending {
+		conversationIDs = append(conversationIDs, conversationID)
+	}
+	sort.Strings(conversationIDs)
+	for _, conversationID := range conversationIDs {
+		pending := t.pending[conversationID]
+		var exhausted bool
+		for _, p := range pending {
+			if now.Sub(p.pushedAt) >= timeout && p.attempts >= maxAttempts {
+				exhausted = true
+				break
+			}
+		}
+		if exhausted {
+			if outOfSync == nil {
+				outOfSync = make(map[string]int64)
+			}
+			for _, p := range pending {
+				if p.msg.Seq > outOfSync[conversationID] {
+					outOfSync[conversationID] = p.msg.Seq
+				}
+			}
+			t.count -= len(pending)
+			delete(t.pending, conversationID)
+			continue
+		}
+		for _, p := range pending {
+			if now.Sub(p.pushedAt) < timeout || !allow() {
+				continue
+			}
+			p.attempts++
+			p.pushedAt = now
+			redeliver = append(redeliver, p.msg)
+		}
+	}
+	return redeliver, outOfSync
+}
+
+// trackPush keeps msgData until the client acks it, a connection holding too many unacked msgs is told to pull
+// the conversation instead.
+func (c *Client) trackPush(ctx context.Context, conversationID string, msgData *sdkws.MsgData) {
+	tracker := c.pushAcks
+	// Msgs without a seq, such as ephemeral states, cannot be pulled and are not worth redelivering.
+	if tracker == nil || msgData.Seq == 0 {
+		return
+	}
+	if tracker.track(conversationID, msgData, time.Now()) {
+		return
+	}
+	c.writeOutOfSync(ctx, map[string]int64{conversationID: msgData.Seq})
+}
+
+func (c *Client) ackPushes(req *Req) error {
+	if c.pushAcks == nil {
+		return errs.ErrArgs.Wrap("push ack was not negotiated")
+	}
+	var ack PushAckReq
+	if err := json.Unmarshal(req.Data, &ack); err != nil {
+		return errs.ErrArgs.Wrap("invalid push ack " + err.Error())
+	}
+	c.pushAcks.ack(ack.Seqs)
+	return nil
+}
+
+// redeliver writes the unacked msgs that are due again and tells the client which conversations it has to pull.
+func (c *Client) redeliver(now time.Time, timeout time.Duration, maxAttempts int, allow func() bool) {
+	tracker := c.pushAcks
+	if tracker == nil || c.closed.Load() {
+		return
+	}
+	msgs, outOfSync := tracker.due(now, timeout, maxAttempts, allow)
+	if len(msgs) == 0 && len(outOfSync) == 0 {
+		return
+	}
+	ctx := mcontext.SetOperationID(context.Background(), utils.OperationIDGenerator())
+	for _, msg := range msgs {
+		prommetrics.PushRedeliveredCounter.Inc()
+		if err := c.writePush(ctx, msgprocessor.GetConversationIDByMsg(msg), msg); err != nil {
+			log.ZWarn(ctx, "redeliver push failed", err, "userID", c.UserID, "platformID", c.PlatformID, "seq", msg.Seq)
+		}
+	}
+	if len(outOfSync) > 0 {
+		c.writeOutOfSync(ctx, outOfSync)
+	}
+}
+
+func (c *Client) writeOutOfSync(ctx context.Context, seqs map[string]int64) {
+	prommetrics.PushOutOfSyncCounter.Add(float64(len(seqs)))
+	data, err := json.Marshal(&OutOfSyncTips{Seqs: seqs})
+	if err != nil {
+		log.ZError(ctx, "marshal out of sync tips failed", err)
+		return
+	}
+	resp := Resp{
+		ReqIdentifier: WSOutOfSync,
+		OperationID:   mcontext.GetOperationID(ctx),
+		Data:          data,
+	}
+	if err := c.writeBinaryMsg(resp); err != nil {
+		log.ZWarn(ctx, "write out of sync tips failed", err, "userID", c.UserID, "platformID", c.PlatformID, "seqs", seqs)
+	}
+}
+
+// redeliverPushes writes the pushes unacked for longConnSvr.pushAck.ackTimeout again until shutdownDone is
+// closed, throttled to redeliverRate msgs per second across the gateway.
+func (ws *WsServer) redeliverPushes(shutdownDone chan struct{}) {
+	conf := ws.globalConfig.LongConnSvr.PushAck
+	timeout := time.Duration(conf.AckTimeout) * time.Millisecond
+	interval := timeout / 2
+	if interval < minRedeliverInterval {
+		interval = minRedeliverInterval
+	}
+	allow := func() bool { return true }
+	if conf.RedeliverRate > 0 {
+		limiter := rate.NewLimiter(rate.Limit(conf.RedeliverRate), conf.RedeliverRate)
+		allow = limiter.Allow
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-shutdownDone:
+			return
+		case now := <-ticker.C:
+			ws.clients.ForEach(func(client *Client) {
+				client.redeliver(now, timeout, conf.MaxAttempts, allow)
+			})
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushAckTracker(t *testing.T) {
+	now := time.Unix(0, 0)
+	allow := func() bool { return true }
+	tracker := newPushAckTracker(4)
+	for seq := int64(1); seq <= 3; seq++ {
+		assert.True(t, tracker.track("si_a_b", &sdkws.MsgData{Seq: seq}, now))
+	}
+	assert.True(t, tracker.track("sg_g", &sdkws.MsgData{Seq: 7}, now))
+	// The connection holds as many pending msgs as allowed.
+	assert.False(t, tracker.track("sg_g", &sdkws.MsgData{Seq: 8}, now))
+
+	tracker.ack(map[string]int64{"si_a_b": 2})
+	redeliver, outOfSync := tracker.due(now.Add(time.Second), 2*time.Second, 2, allow)
+	assert.Empty(t, redeliver)
+	assert.Empty(t, outOfSync)
+
+	redeliver, outOfSync = tracker.due(now.Add(2*time.Second), 2*time.Second, 2, allow)
+	assert.Equal(t, []*sdkws.MsgData{{Seq: 7}, {Seq: 3}}, redeliver)
+	assert.Empty(t, outOfSync)
+
+	// The redelivered msg of si_a_b is acked, sg_g runs out of attempts.
+	tracker.ack(map[string]int64{"si_a_b": 3})
+	redeliver, outOfSync = tracker.due(now.Add(4*time.Second), 2*time.Second, 2, allow)
+	assert.Empty(t, redeliver)
+	assert.Equal(t, map[string]int64{"sg_g": 7}, outOfSync)
+	assert.Equal(t, 0, tracker.count)
+}
+
+func TestPushAckTrackerThrottled(t *testing.T) {
+	now := time.Unix(0, 0)
+	tracker := newPushAckTracker(0)
+	for seq := int64(1); seq <= 3; seq++ {
+		tracker.track("si_a_b", &sdkws.MsgData{Seq: seq}, now)
+	}
+	granted := 1
+	allow := func() bool {
+		granted--
+		return granted >= 0
+	}
+	redeliver, _ := tracker.due(now.Add(time.Second), time.Second, 3, allow)
+	assert.Equal(t, []*sdkws.MsgData{{Seq: 1}}, redeliver)
+	// The msgs the limiter held back are written on a later round without losing an attempt.
+	granted = 2
+	redeliver, _ = tracker.due(now.Add(time.Second), time.Second, 3, allow)
+	assert.Equal(t, []*sdkws.MsgData{{Seq: 2}, {Seq: 3}}, redeliver)
+}
@@ -0,0 +1,84 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+)
+
+// sessionTouchInterval is how often a connection refreshes the last activity of its token and checks that the
+// token was not revoked meanwhile.
+const sessionTouchInterval = 30 * time.Second
+
+// clientIP returns the address of the device, proxies in front of the gateway are expected to set X-Forwarded-For
+// or X-Real-Ip.
+func clientIP(ctx *UserConnContext) string {
+	if forwarded, ok := ctx.GetHeader("X-Forwarded-For"); ok {
+		ip, _, _ := strings.Cut(forwarded, ",")
+		return strings.TrimSpace(ip)
+	}
+	if ip, ok := ctx.GetHeader("X-Real-Ip"); ok {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(ctx.GetRemoteAddr())
+	if err != nil {
+		return ctx.GetRemoteAddr()
+	}
+	return host
+}
+
+func (ws *WsServer) sessionExpiration() time.Duration {
+	return time.Duration(ws.globalConfig.TokenPolicy.Expire) * 24 * time.Hour
+}
+
+// recordSession stores the IP and connection time of the client's token for the device apis.
+func (ws *WsServer) recordSession(client *Client) {
+	if ws.conformance != nil {
+		return
+	}
+	fields := map[string]string{
+		cache.TokenSessionIP:         clientIP(client.ctx),
+		cache.TokenSessionLastActive: strconv.FormatInt(client.lastActive, 10),
+	}
+	if err := ws.cache.SetTokenSession(client.ctx, client.token, fields, ws.sessionExpiration()); err != nil {
+		log.ZWarn(client.ctx, "record token session failed", err, "userID", client.UserID, "platformID", client.PlatformID)
+	}
+}
+
+// touchSession refreshes the last activity of the client's token, an error is returned once the token is revoked.
+func (ws *WsServer) touchSession(client *Client) error {
+	if ws.conformance != nil {
+		return nil
+	}
+	err := ws.checkTokenStatus(client.ctx, client.UserID, client.PlatformID, client.token)
+	if errs.ErrTokenKicked.Is(err) || errs.ErrTokenNotExist.Is(err) {
+		return err
+	}
+	if err != nil {
+		log.ZWarn(client.ctx, "check token status failed", err, "userID", client.UserID, "platformID", client.PlatformID)
+	}
+	fields := map[string]string{cache.TokenSessionLastActive: strconv.FormatInt(client.lastActive, 10)}
+	if err := ws.cache.SetTokenSession(client.ctx, client.token, fields, ws.sessionExpiration()); err != nil {
+		log.ZWarn(client.ctx, "touch token session failed", err, "userID", client.UserID, "platformID", client.PlatformID)
+	}
+	return nil
+}
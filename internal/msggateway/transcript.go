@@ -0,0 +1,339 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msggateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/protocol/push"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Transcript is a conformance session: the connections the clients opened and every frame exchanged on them,
+// in the order the server saw them. Golden transcripts are recorded by the conformance server and replayed
+// against later releases with VerifyTranscript.
+type Transcript struct {
+	Name   string            `json:"name"`
+	Conns  []*TranscriptConn `json:"conns"`
+	Frames []*Frame          `json:"frames"`
+}
+
+// TranscriptConn is the handshake query of a connection, the token is not recorded.
+type TranscriptConn struct {
+	UserID      string `json:"userID"`
+	PlatformID  int    `json:"platformID"`
+	Compression bool   `json:"compression,omitempty"`
+	MsgResp     bool   `json:"msgResp,omitempty"`
+}
+
+const (
+	// FrameConnect opens the connection Conn.
+	FrameConnect = "connect"
+	// FrameHandshake is the text frame answering the upgrade of a connection opened with isMsgResp.
+	FrameHandshake = "handshake"
+	// FrameRequest is sent by the client, FrameResponse by the server, pushes and kicks included.
+	FrameRequest  = "request"
+	FrameResponse = "response"
+)
+
+// Frame is one frame of a transcript, Data is the payload as protobuf JSON.
+type Frame struct {
+	Conn          int             `json:"conn"`
+	Type          string          `json:"type"`
+	ReqIdentifier int32           `json:"reqIdentifier,omitempty"`
+	MsgIncr       string          `json:"msgIncr,omitempty"`
+	OperationID   string          `json:"operationID,omitempty"`
+	ErrCode       int             `json:"errCode,omitempty"`
+	ErrMsg        string          `json:"errMsg,omitempty"`
+	Data          json.RawMessage `json:"data,omitempty"`
+}
+
+// verifyTimeout bounds the wait for each frame the server is expected to send.
+const verifyTimeout = 10 * time.Second
+
+// newPayload returns the message carried by the frames of reqIdentifier, nil when they carry none
+// or raw bytes.
+func newPayload(frameType string, reqIdentifier int32) proto.Message {
+	request := frameType == FrameRequest
+	switch reqIdentifier {
+	case WSGetNewestSeq:
+		if request {
+			return &sdkws.GetMaxSeqReq{}
+		}
+		return &sdkws.GetMaxSeqResp{}
+	case WSPullMsgBySeqList:
+		if request {
+			return &sdkws.PullMessageBySeqsReq{}
+		}
+		return &sdkws.PullMessageBySeqsResp{}
+	case WSSendMsg:
+		if request {
+			return &sdkws.MsgData{}
+		}
+		return &msg.SendMsgResp{}
+	case WSPushMsg:
+		return &sdkws.PushMessages{}
+	case WsLogoutMsg:
+		if request {
+			return &push.DelUserPushTokenReq{}
+		}
+		return &push.DelUserPushTokenResp{}
+	case WsSetBackgroundStatus:
+		if request {
+			return &sdkws.SetAppBackgroundStatusReq{}
+		}
+	}
+	return nil
+}
+
+// encodePayload converts the protobuf payload of a frame to JSON.
+func encodePayload(frameType string, reqIdentifier int32, data []byte) (json.RawMessage, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	m := newPayload(frameType, reqIdentifier)
+	if m == nil {
+		return json.Marshal(data)
+	}
+	if err := proto.Unmarshal(data, m); err != nil {
+		return nil, errs.Wrap(err, "unmarshal frame payload")
+	}
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, b); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodePayload converts the JSON payload of a frame back to protobuf.
+func decodePayload(frameType string, reqIdentifier int32, raw json.RawMessage) ([]byte, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	m := newPayload(frameType, reqIdentifier)
+	if m == nil {
+		var data []byte
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, errs.Wrap(err, "frame payload")
+		}
+		return data, nil
+	}
+	if err := protojson.Unmarshal(raw, m); err != nil {
+		return nil, errs.Wrap(err, "frame payload")
+	}
+	return proto.Marshal(m)
+}
+
+// samePayload compares the payloads as messages, the encoding of equal messages may differ.
+func samePayload(frameType string, reqIdentifier int32, expected json.RawMessage, actual []byte) bool {
+	m := newPayload(frameType, reqIdentifier)
+	if m == nil {
+		data, err := decodePayload(frameType, reqIdentifier, expected)
+		return err == nil && bytes.Equal(data, actual)
+	}
+	if len(expected) > 0 {
+		if err := protojson.Unmarshal(expected, m); err != nil {
+			return false
+		}
+	}
+	got := newPayload(frameType, reqIdentifier)
+	if err := proto.Unmarshal(actual, got); err != nil {
+		return false
+	}
+	return proto.Equal(m, got)
+}
+
+// compareFrame returns how the frame the server sent differs from the expected one.
+func compareFrame(expected *Frame, resp *Resp) error {
+	if resp.ReqIdentifier != expected.ReqIdentifier || resp.MsgIncr != expected.MsgIncr || resp.OperationID != expected.OperationID {
+		return fmt.Errorf("got reqIdentifier %d msgIncr %q operationID %q, want %d %q %q", resp.ReqIdentifier, resp.MsgIncr,
+			resp.OperationID, expected.ReqIdentifier, expected.MsgIncr, expected.OperationID)
+	}
+	if resp.ErrCode != expected.ErrCode || resp.ErrMsg != expected.ErrMsg {
+		return fmt.Errorf("got error %d %q, want %d %q", resp.ErrCode, resp.ErrMsg, expected.ErrCode, expected.ErrMsg)
+	}
+	if !samePayload(FrameResponse, expected.ReqIdentifier, expected.Data, resp.Data) {
+		got, _ := encodePayload(FrameResponse, resp.ReqIdentifier, resp.Data)
+		return fmt.Errorf("got data %s, want %s", got, expected.Data)
+	}
+	return nil
+}
+
+// LoadTranscripts reads the transcripts of dir, ordered by file name.
+func LoadTranscripts(dir string) ([]*Transcript, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	sort.Strings(files)
+	transcripts := make([]*Transcript, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		var t Transcript
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, errs.Wrap(err, file)
+		}
+		if t.Name == "" {
+			t.Name = filepath.Base(file)
+		}
+		transcripts = append(transcripts, &t)
+	}
+	return transcripts, nil
+}
+
+// verifyConn is a connection opened by VerifyTranscript.
+type verifyConn struct {
+	conf *TranscriptConn
+	conn *websocket.Conn
+}
+
+// VerifyTranscript replays t against the conformance server at addr (ws://host:port) in a new session and
+// returns the first frame the server sent differently.
+func VerifyTranscript(addr string, t *Transcript) error {
+	session := t.Name + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	conns := make([]*verifyConn, len(t.Conns))
+	defer func() {
+		for _, c := range conns {
+			if c != nil {
+				_ = c.conn.Close()
+			}
+		}
+	}()
+	encoder := NewGobEncoder()
+	compressor := NewGzipCompressor()
+	for i, frame := range t.Frames {
+		if frame.Conn < 0 || frame.Conn >= len(t.Conns) {
+			return fmt.Errorf("frame %d: unknown conn %d", i, frame.Conn)
+		}
+		if frame.Type != FrameConnect && conns[frame.Conn] == nil {
+			return fmt.Errorf("frame %d: conn %d is not connected", i, frame.Conn)
+		}
+		c := conns[frame.Conn]
+		switch frame.Type {
+		case FrameConnect:
+			conn, err := dialTranscriptConn(addr, session, t.Conns[frame.Conn])
+			if err != nil {
+				return fmt.Errorf("frame %d: %w", i, err)
+			}
+			conns[frame.Conn] = &verifyConn{conf: t.Conns[frame.Conn], conn: conn}
+		case FrameHandshake:
+			_ = c.conn.SetReadDeadline(time.Now().Add(verifyTimeout))
+			messageType, data, err := c.conn.ReadMessage()
+			if err != nil {
+				return fmt.Errorf("frame %d: read handshake: %w", i, err)
+			}
+			var resp struct {
+				ErrCode int    `json:"errCode"`
+				ErrMsg  string `json:"errMsg"`
+			}
+			if messageType != websocket.TextMessage || json.Unmarshal(data, &resp) != nil {
+				return fmt.Errorf("frame %d: handshake is not a json text frame", i)
+			}
+			if resp.ErrCode != frame.ErrCode || resp.ErrMsg != frame.ErrMsg {
+				return fmt.Errorf("frame %d: got handshake error %d %q, want %d %q", i, resp.ErrCode, resp.ErrMsg, frame.ErrCode, frame.ErrMsg)
+			}
+		case FrameRequest:
+			data, err := decodePayload(FrameRequest, frame.ReqIdentifier, frame.Data)
+			if err != nil {
+				return fmt.Errorf("frame %d: %w", i, err)
+			}
+			req := Req{
+				ReqIdentifier: frame.ReqIdentifier,
+				Token:         conformanceToken,
+				SendID:        c.conf.UserID,
+				OperationID:   frame.OperationID,
+				MsgIncr:       frame.MsgIncr,
+				Data:          data,
+			}
+			buf, err := encoder.Encode(req)
+			if err != nil {
+				return fmt.Errorf("frame %d: %w", i, err)
+			}
+			if c.conf.Compression {
+				if buf, err = compressor.Compress(buf); err != nil {
+					return fmt.Errorf("frame %d: %w", i, err)
+				}
+			}
+			if err := c.conn.WriteMessage(websocket.BinaryMessage, buf); err != nil {
+				return fmt.Errorf("frame %d: write request: %w", i, err)
+			}
+		case FrameResponse:
+			_ = c.conn.SetReadDeadline(time.Now().Add(verifyTimeout))
+			messageType, buf, err := c.conn.ReadMessage()
+			if err != nil {
+				return fmt.Errorf("frame %d: read response: %w", i, err)
+			}
+			if messageType != websocket.BinaryMessage {
+				return fmt.Errorf("frame %d: response is not a binary frame", i)
+			}
+			if c.conf.Compression {
+				if buf, err = compressor.DeCompress(buf); err != nil {
+					return fmt.Errorf("frame %d: %w", i, err)
+				}
+			}
+			var resp Resp
+			if err := encoder.Decode(buf, &resp); err != nil {
+				return fmt.Errorf("frame %d: %w", i, err)
+			}
+			if err := compareFrame(frame, &resp); err != nil {
+				return fmt.Errorf("frame %d: %w", i, err)
+			}
+		default:
+			return fmt.Errorf("frame %d: unknown type %q", i, frame.Type)
+		}
+	}
+	return nil
+}
+
+func dialTranscriptConn(addr string, session string, conf *TranscriptConn) (*websocket.Conn, error) {
+	query := url.Values{}
+	query.Set(WsUserID, conf.UserID)
+	query.Set(PlatformID, strconv.Itoa(conf.PlatformID))
+	query.Set(Token, conformanceToken)
+	query.Set(OperationID, session)
+	query.Set(ConformanceSession, session)
+	if conf.Compression {
+		query.Set(Compression, GzipCompressionProtocol)
+	}
+	if conf.MsgResp {
+		query.Set(MsgResp, "true")
+	}
+	dialer := websocket.Dialer{HandshakeTimeout: verifyTimeout}
+	conn, _, err := dialer.Dial(addr+"/?"+query.Encode(), nil)
+	if err != nil {
+		return nil, errs.Wrap(err, "dial "+conf.UserID)
+	}
+	return conn, nil
+}
@@ -38,6 +38,16 @@ func (u *UserMap) GetAll(key string) ([]*Client, bool) {
 	return nil, ok
 }
 
+// ForEach calls f for each connection of every user.
+func (u *UserMap) ForEach(f func(client *Client)) {
+	u.m.Range(func(_, value any) bool {
+		for _, client := range value.([]*Client) {
+			f(client)
+		}
+		return true
+	})
+}
+
 func (u *UserMap) Get(key string, platformID int) ([]*Client, bool, bool) {
 	allClients, userExisted := u.m.Load(key)
 	if userExisted {
@@ -0,0 +1,136 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgtransfer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mqbuild"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"golang.org/x/time/rate"
+	"google.golang.org/protobuf/proto"
+)
+
+const businessSubscriberPageSize = 500
+
+// BusinessNotificationConsumerHandler fans business notifications published to a topic out to its subscribers.
+// Each topic has its own rate limiter so that a large topic can not starve the others.
+type BusinessNotificationConsumerHandler struct {
+	historyConsumerGroup mq.ConsumerGroup
+	topicDatabase        controller.BusinessTopicDatabase
+	msgRpcClient         *rpcclient.MessageRpcClient
+	fanoutRate           int
+
+	lock     sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func NewBusinessNotificationConsumerHandler(
+	config *config.GlobalConfig,
+	topicDatabase controller.BusinessTopicDatabase,
+	msgRpcClient *rpcclient.MessageRpcClient,
+) (*BusinessNotificationConsumerHandler, error) {
+	historyConsumerGroup, err := mqbuild.NewConsumerGroup(config, config.Kafka.ConsumerGroupID.BusinessNotification, []string{config.Kafka.BusinessNotification.Topic})
+	if err != nil {
+		return nil, err
+	}
+	return &BusinessNotificationConsumerHandler{
+		historyConsumerGroup: historyConsumerGroup,
+		topicDatabase:        topicDatabase,
+		msgRpcClient:         msgRpcClient,
+		fanoutRate:           config.BusinessNotification.FanoutRate,
+		limiters:             make(map[string]*rate.Limiter),
+	}, nil
+}
+
+func (b *BusinessNotificationConsumerHandler) limiter(topic string) *rate.Limiter {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	limiter, ok := b.limiters[topic]
+	if !ok {
+		if b.fanoutRate > 0 {
+			limiter = rate.NewLimiter(rate.Limit(b.fanoutRate), b.fanoutRate)
+		} else {
+			limiter = rate.NewLimiter(rate.Inf, 0)
+		}
+		b.limiters[topic] = limiter
+	}
+	return limiter
+}
+
+// handleBusinessNotification returns an error only when the notification can not be decoded.
+func (b *BusinessNotificationConsumerHandler) handleBusinessNotification(ctx context.Context, topic string, value []byte) error {
+	var msgData sdkws.MsgData
+	if err := proto.Unmarshal(value, &msgData); err != nil {
+		log.ZError(ctx, "unmarshal business notification failed", err, "topic", topic)
+		return errs.Wrap(err, "unmarshal business notification")
+	}
+	limiter := b.limiter(topic)
+	page := &sdkws.RequestPagination{PageNumber: 1, ShowNumber: businessSubscriberPageSize}
+	var sent int
+	for {
+		_, userIDs, err := b.topicDatabase.PageSubscriberIDs(ctx, topic, page)
+		if err != nil {
+			log.ZError(ctx, "page business topic subscribers failed", err, "topic", topic, "pageNumber", page.PageNumber)
+			return nil
+		}
+		for _, userID := range userIDs {
+			if err := limiter.Wait(ctx); err != nil {
+				log.ZWarn(ctx, "business notification fanout interrupted", err, "topic", topic, "sent", sent)
+				return nil
+			}
+			data := proto.Clone(&msgData).(*sdkws.MsgData)
+			data.RecvID = userID
+			data.ClientMsgID = utils.GetMsgID(msgData.SendID)
+			if _, err := b.msgRpcClient.SendMsg(ctx, &msg.SendMsgReq{MsgData: data}); err != nil {
+				log.ZWarn(ctx, "send business notification failed", err, "topic", topic, "userID", userID)
+				continue
+			}
+			sent++
+		}
+		if len(userIDs) < businessSubscriberPageSize {
+			break
+		}
+		page.PageNumber++
+	}
+	log.ZDebug(ctx, "business notification fanout finished", "topic", topic, "sent", sent)
+	return nil
+}
+
+func (b *BusinessNotificationConsumerHandler) ConsumeClaim(ctx context.Context, claim mq.Claim) error {
+	for msg := range claim.Messages() {
+		ctx := msg.Context()
+		if len(msg.Value) == 0 {
+			log.ZError(ctx, "business notification get from mq but is nil", nil, "topic", msg.Key)
+			msg.Ack()
+			continue
+		}
+		if err := b.handleBusinessNotification(ctx, msg.Key, msg.Value); err != nil {
+			msg.DeadLetter(err)
+			continue
+		}
+		msg.Ack()
+	}
+	return nil
+}
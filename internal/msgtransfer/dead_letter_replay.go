@@ -0,0 +1,149 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgtransfer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pbmsg "github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mqbuild"
+	"google.golang.org/protobuf/proto"
+)
+
+// replayHealthInterval is how often mongo is pinged while waiting for it before a replay.
+const replayHealthInterval = 5 * time.Second
+
+// ReplayMongoDeadLetters sends the msgs dead-lettered by the mongo consumer back to its topic, the replay consumer
+// group remembers how far it got so each msg is replayed once. It waits for mongo to answer first, and returns
+// once no msg arrived for idle. Msgs that can not be decoded are skipped.
+func ReplayMongoDeadLetters(ctx context.Context, config *config.GlobalConfig, idle time.Duration) (replayed int64, skipped int64, err error) {
+	if err := waitMongo(ctx, config); err != nil {
+		return 0, 0, err
+	}
+	topic := config.Kafka.MsgToMongo.Topic
+	producer, err := mqbuild.NewProducer(config, topic)
+	if err != nil {
+		return 0, 0, err
+	}
+	group, err := mqbuild.NewDeadLetterConsumerGroup(config, config.Kafka.ConsumerGroupID.MsgToMongo+"-replay", topic)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer group.Close()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	h := &deadLetterReplayHandler{producer: producer, activity: make(chan struct{}, 1), cancel: cancel}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		group.Consume(ctx, h)
+	}()
+	timer := time.NewTimer(idle)
+	defer timer.Stop()
+	for running := true; running; {
+		select {
+		case <-h.activity:
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(idle)
+		case <-timer.C:
+			running = false
+		case <-ctx.Done():
+			running = false
+		}
+	}
+	cancel()
+	<-done
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.replayed, h.skipped, h.err
+}
+
+// waitMongo returns once mongo answers a ping or ctx is done.
+func waitMongo(ctx context.Context, config *config.GlobalConfig) error {
+	mongo, err := unrelation.NewMongo(config)
+	if err != nil {
+		return err
+	}
+	defer mongo.GetClient().Disconnect(context.Background())
+	for {
+		err := mongo.GetClient().Ping(ctx, nil)
+		if err == nil {
+			return nil
+		}
+		log.ZWarn(ctx, "mongo is not healthy, waiting before replaying dead letters", err)
+		select {
+		case <-ctx.Done():
+			return errs.Wrap(ctx.Err())
+		case <-time.After(replayHealthInterval):
+		}
+	}
+}
+
+type deadLetterReplayHandler struct {
+	producer mq.Producer
+	activity chan struct{}
+	cancel   context.CancelFunc
+
+	lock     sync.Mutex
+	replayed int64
+	skipped  int64
+	err      error
+}
+
+func (h *deadLetterReplayHandler) ConsumeClaim(ctx context.Context, claim mq.Claim) error {
+	for msg := range claim.Messages() {
+		select {
+		case h.activity <- struct{}{}:
+		default:
+		}
+		var msgFromMQ pbmsg.MsgDataToMongoByMQ
+		if err := proto.Unmarshal(msg.Value, &msgFromMQ); err != nil {
+			log.ZWarn(msg.Context(), "skip dead letter that can not be decoded", err, "topic", claim.Topic(), "key", msg.Key)
+			h.count(&h.skipped)
+			msg.Ack()
+			continue
+		}
+		if _, _, err := h.producer.SendMessage(msg.Context(), msg.Key, &msgFromMQ); err != nil {
+			// Stop without acking, the msgs after this one must not be committed before it.
+			log.ZError(msg.Context(), "replay dead letter failed", err, "topic", claim.Topic(), "key", msg.Key)
+			h.lock.Lock()
+			if h.err == nil {
+				h.err = err
+			}
+			h.lock.Unlock()
+			h.cancel()
+			return err
+		}
+		log.ZInfo(msg.Context(), "dead letter replayed", "topic", claim.Topic(), "conversationID", msgFromMQ.ConversationID, "lastSeq", msgFromMQ.LastSeq)
+		h.count(&h.replayed)
+		msg.Ack()
+	}
+	return nil
+}
+
+func (h *deadLetterReplayHandler) count(n *int64) {
+	h.lock.Lock()
+	*n++
+	h.lock.Unlock()
+}
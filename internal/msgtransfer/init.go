@@ -28,9 +28,11 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
 	kdisc "github.com/openimsdk/open-im-server/v3/pkg/common/discoveryregister"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/topology"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	util "github.com/openimsdk/open-im-server/v3/pkg/util/genutil"
 	"github.com/prometheus/client_golang/prometheus"
@@ -46,6 +48,8 @@ type MsgTransfer struct {
 	// and then the message is sent to ms2pschat topic for push, and the message is sent to msg_to_mongo topic for persistence
 	historyCH      *OnlineHistoryRedisConsumerHandler
 	historyMongoCH *OnlineHistoryMongoConsumerHandler
+	// fan out business notifications published to topic subscribers, subscribed to the topic: businessNotification
+	businessCH *BusinessNotificationConsumerHandler
 	// mongoDB batch insert, delete messages in redis after success,
 	// and handle the deletion notification message deleted subscriptions topic: msg_to_mongo
 	ctx    context.Context
@@ -78,14 +82,39 @@ func StartTransfer(config *config.GlobalConfig, prometheusPort int) error {
 
 	client.AddOption(mw.GrpcClient(), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"LoadBalancingPolicy": "%s"}`, "round_robin")))
 	msgModel := cache.NewMsgCacheModel(rdb, config)
-	msgDocModel := unrelation.NewMsgMongoDriver(mongo.GetDatabase(config.Mongo.Database))
-	msgDatabase, err := controller.NewCommonMsgDatabase(msgDocModel, msgModel, config)
+	msgDocModel := unrelation.NewMsgMongoDriver(mongo.GetDatabase(config.Mongo.Database), nil)
+	archiveDB, err := mgo.NewArchiveWatermarkMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	var seqAlloc cache.SeqAllocator
+	if config.SeqAllocator.Enable {
+		seqDB, err := mgo.NewSeqMongo(mongo.GetDatabase(config.Mongo.Database))
+		if err != nil {
+			return err
+		}
+		seqAlloc = cache.NewSeqAllocator(rdb, seqDB, int64(config.SeqAllocator.SegmentSize))
+	}
+	msgDatabase, err := controller.NewCommonMsgDatabase(msgDocModel, msgModel, archiveDB, seqAlloc, config)
+	if err != nil {
+		return err
+	}
+	businessTopicDB, err := mgo.NewBusinessTopicMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	businessTopicDatabase, err := controller.NewBusinessTopicDatabase(businessTopicDB, config)
+	if err != nil {
+		return err
+	}
+	msgThreadDB, err := mgo.NewMsgThreadMongo(mongo.GetDatabase(config.Mongo.Database))
 	if err != nil {
 		return err
 	}
 	conversationRpcClient := rpcclient.NewConversationRpcClient(client, config)
 	groupRpcClient := rpcclient.NewGroupRpcClient(client, config)
-	msgTransfer, err := NewMsgTransfer(config, msgDatabase, &conversationRpcClient, &groupRpcClient)
+	msgRpcClient := rpcclient.NewMessageRpcClient(client, config)
+	msgTransfer, err := NewMsgTransfer(config, msgDatabase, businessTopicDatabase, controller.NewMsgThreadDatabase(msgThreadDB), &conversationRpcClient, &groupRpcClient, &msgRpcClient)
 	if err != nil {
 		return err
 	}
@@ -95,14 +124,21 @@ func StartTransfer(config *config.GlobalConfig, prometheusPort int) error {
 func NewMsgTransfer(
 	config *config.GlobalConfig,
 	msgDatabase controller.CommonMsgDatabase,
+	businessTopicDatabase controller.BusinessTopicDatabase,
+	threadDatabase controller.MsgThreadDatabase,
 	conversationRpcClient *rpcclient.ConversationRpcClient,
 	groupRpcClient *rpcclient.GroupRpcClient,
+	msgRpcClient *rpcclient.MessageRpcClient,
 ) (*MsgTransfer, error) {
 	historyCH, err := NewOnlineHistoryRedisConsumerHandler(config, msgDatabase, conversationRpcClient, groupRpcClient)
 	if err != nil {
 		return nil, err
 	}
-	historyMongoCH, err := NewOnlineHistoryMongoConsumerHandler(config, msgDatabase)
+	historyMongoCH, err := NewOnlineHistoryMongoConsumerHandler(config, msgDatabase, threadDatabase)
+	if err != nil {
+		return nil, err
+	}
+	businessCH, err := NewBusinessNotificationConsumerHandler(config, businessTopicDatabase, msgRpcClient)
 	if err != nil {
 		return nil, err
 	}
@@ -110,6 +146,7 @@ func NewMsgTransfer(
 	return &MsgTransfer{
 		historyCH:      historyCH,
 		historyMongoCH: historyMongoCH,
+		businessCH:     businessCH,
 		config:         config,
 	}, nil
 }
@@ -126,8 +163,13 @@ func (m *MsgTransfer) Start(prometheusPort int, config *config.GlobalConfig) err
 		netErr  error
 	)
 
-	go m.historyCH.historyConsumerGroup.RegisterHandleAndConsumer(m.ctx, m.historyCH)
-	go m.historyMongoCH.historyConsumerGroup.RegisterHandleAndConsumer(m.ctx, m.historyMongoCH)
+	if err := topology.Report(config, topology.ComponentMsgTransfer, ""); err != nil {
+		return err
+	}
+
+	go m.historyCH.historyConsumerGroup.Consume(m.ctx, m.historyCH)
+	go m.historyMongoCH.historyConsumerGroup.Consume(m.ctx, m.historyMongoCH)
+	go m.businessCH.historyConsumerGroup.Consume(m.ctx, m.businessCH)
 
 	if config.Prometheus.Enable {
 		go func() {
@@ -150,15 +192,17 @@ func (m *MsgTransfer) Start(prometheusPort int, config *config.GlobalConfig) err
 	select {
 	case <-sigs:
 		util.SIGTERMExit()
-		// graceful close kafka client.
+		// graceful close mq client.
 		m.cancel()
 		m.historyCH.historyConsumerGroup.Close()
 		m.historyMongoCH.historyConsumerGroup.Close()
+		m.businessCH.historyConsumerGroup.Close()
 		return nil
 	case <-netDone:
 		m.cancel()
 		m.historyCH.historyConsumerGroup.Close()
 		m.historyMongoCH.historyConsumerGroup.Close()
+		m.businessCH.historyConsumerGroup.Close()
 		close(netDone)
 		return netErr
 	}
@@ -16,13 +16,10 @@ package msgtransfer
 
 import (
 	"context"
-	"strconv"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"github.com/IBM/sarama"
 	"github.com/OpenIMSDK/protocol/constant"
 	"github.com/OpenIMSDK/protocol/sdkws"
 	"github.com/OpenIMSDK/tools/errs"
@@ -32,7 +29,8 @@ import (
 	"github.com/go-redis/redis"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
-	"github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mqbuild"
 	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	"google.golang.org/protobuf/proto"
@@ -53,7 +51,7 @@ type MsgChannelValue struct {
 
 type TriggerChannelValue struct {
 	ctx      context.Context
-	cMsgList []*sarama.ConsumerMessage
+	cMsgList []*mq.Message
 }
 
 type Cmd2Value struct {
@@ -66,7 +64,7 @@ type ContextMsg struct {
 }
 
 type OnlineHistoryRedisConsumerHandler struct {
-	historyConsumerGroup *kafka.MConsumerGroup
+	historyConsumerGroup mq.ConsumerGroup
 	chArrays             [ChannelNum]chan Cmd2Value
 	msgDistributionCh    chan Cmd2Value
 
@@ -78,6 +76,8 @@ type OnlineHistoryRedisConsumerHandler struct {
 	msgDatabase           controller.CommonMsgDatabase
 	conversationRpcClient *rpcclient.ConversationRpcClient
 	groupRpcClient        *rpcclient.GroupRpcClient
+	// highPriorityTopic carries high priority messages, they are distributed as they arrive instead of in batches.
+	highPriorityTopic string
 }
 
 func NewOnlineHistoryRedisConsumerHandler(
@@ -96,35 +96,21 @@ func NewOnlineHistoryRedisConsumerHandler(
 	}
 	och.conversationRpcClient = conversationRpcClient
 	och.groupRpcClient = groupRpcClient
+	och.highPriorityTopic = config.Kafka.LatestMsgToRedis.HighPriorityTopic
 	var err error
-
-	var tlsConfig *kafka.TLSConfig
-	if config.Kafka.TLS != nil {
-		tlsConfig = &kafka.TLSConfig{
-			CACrt:              config.Kafka.TLS.CACrt,
-			ClientCrt:          config.Kafka.TLS.ClientCrt,
-			ClientKey:          config.Kafka.TLS.ClientKey,
-			ClientKeyPwd:       config.Kafka.TLS.ClientKeyPwd,
-			InsecureSkipVerify: false,
-		}
-	}
-
-	och.historyConsumerGroup, err = kafka.NewMConsumerGroup(&kafka.MConsumerGroupConfig{
-		KafkaVersion:   sarama.V2_0_0_0,
-		OffsetsInitial: sarama.OffsetNewest,
-		IsReturnErr:    false,
-		UserName:       config.Kafka.Username,
-		Password:       config.Kafka.Password,
-	}, []string{config.Kafka.LatestMsgToRedis.Topic},
-		config.Kafka.Addr,
-		config.Kafka.ConsumerGroupID.MsgToRedis,
-		tlsConfig,
-	)
+	och.historyConsumerGroup, err = mqbuild.NewConsumerGroup(config, config.Kafka.ConsumerGroupID.MsgToRedis, och.topics(config))
 	// statistics.NewStatistics(&och.singleMsgSuccessCount, config.Config.ModuleName.MsgTransferName, fmt.Sprintf("%d
 	// second singleMsgCount insert to mongo", constant.StatisticsTimeInterval), constant.StatisticsTimeInterval)
 	return &och, err
 }
 
+func (och *OnlineHistoryRedisConsumerHandler) topics(config *config.GlobalConfig) []string {
+	if och.highPriorityTopic == "" {
+		return []string{config.Kafka.LatestMsgToRedis.Topic}
+	}
+	return []string{config.Kafka.LatestMsgToRedis.Topic, och.highPriorityTopic}
+}
+
 func (och *OnlineHistoryRedisConsumerHandler) Run(channelID int) {
 	for cmd := range och.chArrays[channelID] {
 		switch cmd.Cmd {
@@ -344,21 +330,10 @@ func (och *OnlineHistoryRedisConsumerHandler) MessagesDistributionHandle() {
 					err := proto.Unmarshal(consumerMessages[i].Value, msgFromMQ)
 					if err != nil {
 						log.ZError(ctx, "msg_transfer Unmarshal msg err", err, string(consumerMessages[i].Value))
+						consumerMessages[i].DeadLetter(errs.Wrap(err, "unmarshal msg"))
 						continue
 					}
-					var arr []string
-					for i, header := range consumerMessages[i].Headers {
-						arr = append(arr, strconv.Itoa(i), string(header.Key), string(header.Value))
-					}
-					log.ZInfo(
-						ctx,
-						"consumer.kafka.GetContextWithMQHeader",
-						"len",
-						len(consumerMessages[i].Headers),
-						"header",
-						strings.Join(arr, ", "),
-					)
-					ctxMsg.ctx = kafka.GetContextWithMQHeader(consumerMessages[i].Headers)
+					ctxMsg.ctx = consumerMessages[i].Context()
 					ctxMsg.message = msgFromMQ
 					log.ZDebug(
 						ctx,
@@ -366,17 +341,17 @@ func (och *OnlineHistoryRedisConsumerHandler) MessagesDistributionHandle() {
 						"message",
 						msgFromMQ,
 						"key",
-						string(consumerMessages[i].Key),
+						consumerMessages[i].Key,
 					)
-					// aggregationMsgs[string(consumerMessages[i].Key)] =
-					// append(aggregationMsgs[string(consumerMessages[i].Key)], ctxMsg)
-					if oldM, ok := aggregationMsgs[string(consumerMessages[i].Key)]; ok {
+					// aggregationMsgs[consumerMessages[i].Key] =
+					// append(aggregationMsgs[consumerMessages[i].Key], ctxMsg)
+					if oldM, ok := aggregationMsgs[consumerMessages[i].Key]; ok {
 						oldM = append(oldM, ctxMsg)
-						aggregationMsgs[string(consumerMessages[i].Key)] = oldM
+						aggregationMsgs[consumerMessages[i].Key] = oldM
 					} else {
 						m := make([]*ContextMsg, 0, 100)
 						m = append(m, ctxMsg)
-						aggregationMsgs[string(consumerMessages[i].Key)] = m
+						aggregationMsgs[consumerMessages[i].Key] = m
 					}
 				}
 				log.ZDebug(ctx, "generate map list users len", "length", len(aggregationMsgs))
@@ -403,6 +378,29 @@ func (och *OnlineHistoryRedisConsumerHandler) MessagesDistributionHandle() {
 	}
 }
 
+// consumeHighPriorityClaim distributes every message as soon as it arrives, skipping the batching ticker.
+func (och *OnlineHistoryRedisConsumerHandler) consumeHighPriorityClaim(ctx context.Context, claim mq.Claim) error {
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+			if len(msg.Value) == 0 {
+				msg.Ack()
+				continue
+			}
+			ctx := mcontext.WithTriggerIDContext(context.Background(), utils.OperationIDGenerator())
+			och.msgDistributionCh <- Cmd2Value{Cmd: ConsumerMsgs, Value: TriggerChannelValue{
+				ctx: ctx, cMsgList: []*mq.Message{msg},
+			}}
+			msg.Ack()
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
 func withAggregationCtx(ctx context.Context, values []*ContextMsg) context.Context {
 	var allMessageOperationID string
 	for i, v := range values {
@@ -417,30 +415,16 @@ func withAggregationCtx(ctx context.Context, values []*ContextMsg) context.Conte
 	return mcontext.SetOperationID(ctx, allMessageOperationID)
 }
 
-func (och *OnlineHistoryRedisConsumerHandler) Setup(_ sarama.ConsumerGroupSession) error { return nil }
-func (och *OnlineHistoryRedisConsumerHandler) Cleanup(_ sarama.ConsumerGroupSession) error {
-	return nil
-}
-
-func (och *OnlineHistoryRedisConsumerHandler) ConsumeClaim(
-	sess sarama.ConsumerGroupSession,
-	claim sarama.ConsumerGroupClaim,
-) error { // a instance in the consumer group
-	for {
-		if sess == nil {
-			log.ZWarn(context.Background(), "sess == nil, waiting", nil)
-			time.Sleep(100 * time.Millisecond)
-		} else {
-			break
-		}
+func (och *OnlineHistoryRedisConsumerHandler) ConsumeClaim(ctx context.Context, claim mq.Claim) error { // a instance in the consumer group
+	log.ZDebug(ctx, "online new session msg come", "topic", claim.Topic())
+	if och.highPriorityTopic != "" && claim.Topic() == och.highPriorityTopic {
+		return och.consumeHighPriorityClaim(ctx, claim)
 	}
-	log.ZDebug(context.Background(), "online new session msg come", "highWaterMarkOffset",
-		claim.HighWaterMarkOffset(), "topic", claim.Topic(), "partition", claim.Partition())
 
 	var (
 		split    = 1000
 		rwLock   = new(sync.RWMutex)
-		messages = make([]*sarama.ConsumerMessage, 0, 1000)
+		messages = make([]*mq.Message, 0, 1000)
 		ticker   = time.NewTicker(time.Millisecond * 100)
 
 		wg      = sync.WaitGroup{}
@@ -465,7 +449,7 @@ func (och *OnlineHistoryRedisConsumerHandler) ConsumeClaim(
 				}
 
 				rwLock.Lock()
-				buffer := make([]*sarama.ConsumerMessage, 0, len(messages))
+				buffer := make([]*mq.Message, 0, len(messages))
 				buffer = append(buffer, messages...)
 
 				// reuse slice, set cap to 0
@@ -506,6 +490,7 @@ func (och *OnlineHistoryRedisConsumerHandler) ConsumeClaim(
 				}
 
 				if len(msg.Value) == 0 {
+					msg.Ack()
 					continue
 				}
 
@@ -513,9 +498,9 @@ func (och *OnlineHistoryRedisConsumerHandler) ConsumeClaim(
 				messages = append(messages, msg)
 				rwLock.Unlock()
 
-				sess.MarkMessage(msg, "")
+				msg.Ack()
 
-			case <-sess.Context().Done():
+			case <-ctx.Done():
 				running.Store(false)
 				return
 			}
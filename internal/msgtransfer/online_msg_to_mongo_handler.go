@@ -16,44 +16,38 @@ package msgtransfer
 
 import (
 	"context"
+	"time"
 
-	"github.com/IBM/sarama"
 	pbmsg "github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
-	kfk "github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mqbuild"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/search"
 	"google.golang.org/protobuf/proto"
 )
 
+const (
+	insertMongoAttempts      = 3
+	insertMongoRetryInterval = 500 * time.Millisecond
+)
+
 type OnlineHistoryMongoConsumerHandler struct {
-	historyConsumerGroup *kfk.MConsumerGroup
+	historyConsumerGroup mq.ConsumerGroup
 	msgDatabase          controller.CommonMsgDatabase
+	threadDatabase       controller.MsgThreadDatabase
+	searchBackend        search.Backend
 }
 
-func NewOnlineHistoryMongoConsumerHandler(config *config.GlobalConfig, database controller.CommonMsgDatabase) (*OnlineHistoryMongoConsumerHandler, error) {
-	var tlsConfig *kfk.TLSConfig
-	if config.Kafka.TLS != nil {
-		tlsConfig = &kfk.TLSConfig{
-			CACrt:              config.Kafka.TLS.CACrt,
-			ClientCrt:          config.Kafka.TLS.ClientCrt,
-			ClientKey:          config.Kafka.TLS.ClientKey,
-			ClientKeyPwd:       config.Kafka.TLS.ClientKeyPwd,
-			InsecureSkipVerify: false,
-		}
+func NewOnlineHistoryMongoConsumerHandler(config *config.GlobalConfig, database controller.CommonMsgDatabase, threadDatabase controller.MsgThreadDatabase) (*OnlineHistoryMongoConsumerHandler, error) {
+	historyConsumerGroup, err := mqbuild.NewConsumerGroup(config, config.Kafka.ConsumerGroupID.MsgToMongo, []string{config.Kafka.MsgToMongo.Topic})
+	if err != nil {
+		return nil, err
 	}
-	historyConsumerGroup, err := kfk.NewMConsumerGroup(&kfk.MConsumerGroupConfig{
-		KafkaVersion:   sarama.V2_0_0_0,
-		OffsetsInitial: sarama.OffsetNewest,
-		IsReturnErr:    false,
-		UserName:       config.Kafka.Username,
-		Password:       config.Kafka.Password,
-	}, []string{config.Kafka.MsgToMongo.Topic},
-		config.Kafka.Addr,
-		config.Kafka.ConsumerGroupID.MsgToMongo,
-		tlsConfig,
-	)
+	searchBackend, err := search.NewBackend(config)
 	if err != nil {
 		return nil, err
 	}
@@ -61,29 +55,27 @@ func NewOnlineHistoryMongoConsumerHandler(config *config.GlobalConfig, database
 	mc := &OnlineHistoryMongoConsumerHandler{
 		historyConsumerGroup: historyConsumerGroup,
 		msgDatabase:          database,
+		threadDatabase:       threadDatabase,
+		searchBackend:        searchBackend,
 	}
 	return mc, nil
 }
 
-func (mc *OnlineHistoryMongoConsumerHandler) handleChatWs2Mongo(
-	ctx context.Context,
-	cMsg *sarama.ConsumerMessage,
-	key string,
-	session sarama.ConsumerGroupSession,
-) {
+// handleChatWs2Mongo returns an error only when the msg can not be stored, the caller moves it to the dead letter topic.
+func (mc *OnlineHistoryMongoConsumerHandler) handleChatWs2Mongo(ctx context.Context, cMsg *mq.Message) error {
 	msg := cMsg.Value
 	msgFromMQ := pbmsg.MsgDataToMongoByMQ{}
 	err := proto.Unmarshal(msg, &msgFromMQ)
 	if err != nil {
-		log.ZError(ctx, "unmarshall failed", err, "key", key, "len", len(msg))
-		return
+		log.ZError(ctx, "unmarshall failed", err, "key", cMsg.Key, "len", len(msg))
+		return errs.Wrap(err, "unmarshal msg")
 	}
 	if len(msgFromMQ.MsgData) == 0 {
-		log.ZError(ctx, "msgFromMQ.MsgData is empty", nil, "cMsg", cMsg)
-		return
+		log.ZError(ctx, "msgFromMQ.MsgData is empty", nil, "key", cMsg.Key)
+		return nil
 	}
 	log.ZInfo(ctx, "mongo consumer recv msg", "msgs", msgFromMQ.String())
-	err = mc.msgDatabase.BatchInsertChat2DB(ctx, msgFromMQ.ConversationID, msgFromMQ.MsgData, msgFromMQ.LastSeq)
+	err = mc.insertChat2DB(ctx, &msgFromMQ)
 	if err != nil {
 		log.ZError(
 			ctx,
@@ -94,9 +86,16 @@ func (mc *OnlineHistoryMongoConsumerHandler) handleChatWs2Mongo(
 			"conversationID",
 			msgFromMQ.ConversationID,
 		)
-		prommetrics.MsgInsertMongoFailedCounter.Inc()
-	} else {
-		prommetrics.MsgInsertMongoSuccessCounter.Inc()
+		return err
+	}
+	prommetrics.MsgInsertMongoSuccessCounter.Inc()
+	if err := mc.threadDatabase.AddReplies(ctx, msgFromMQ.ConversationID, msgFromMQ.MsgData); err != nil {
+		log.ZError(ctx, "add thread replies err", err, "conversationID", msgFromMQ.ConversationID)
+	}
+	if mc.searchBackend != nil {
+		if err := mc.searchBackend.Index(ctx, msgFromMQ.ConversationID, msgFromMQ.MsgData); err != nil {
+			log.ZError(ctx, "index msgs for search err", err, "conversationID", msgFromMQ.ConversationID)
+		}
 	}
 	var seqs []int64
 	for _, msg := range msgFromMQ.MsgData {
@@ -115,25 +114,45 @@ func (mc *OnlineHistoryMongoConsumerHandler) handleChatWs2Mongo(
 		)
 	}
 	mc.msgDatabase.DelUserDeleteMsgsList(ctx, msgFromMQ.ConversationID, seqs)
+	return nil
 }
 
-func (OnlineHistoryMongoConsumerHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
-func (OnlineHistoryMongoConsumerHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return nil }
+// insertChat2DB makes up to insertMongoAttempts attempts, storage errors are often transient and a msg that is
+// dead-lettered stays out of mongo until it is replayed.
+func (mc *OnlineHistoryMongoConsumerHandler) insertChat2DB(ctx context.Context, msgFromMQ *pbmsg.MsgDataToMongoByMQ) error {
+	for attempt := 1; ; attempt++ {
+		err := mc.msgDatabase.BatchInsertChat2DB(ctx, msgFromMQ.ConversationID, msgFromMQ.MsgData, msgFromMQ.LastSeq)
+		if err == nil {
+			return nil
+		}
+		prommetrics.MsgInsertMongoFailedCounter.Inc()
+		if attempt >= insertMongoAttempts {
+			return err
+		}
+		log.ZWarn(ctx, "insert msgs to mongo failed, retrying", err, "conversationID", msgFromMQ.ConversationID, "attempt", attempt)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * insertMongoRetryInterval):
+		}
+	}
+}
 
-func (mc *OnlineHistoryMongoConsumerHandler) ConsumeClaim(
-	sess sarama.ConsumerGroupSession,
-	claim sarama.ConsumerGroupClaim,
-) error { // a instance in the consumer group
-	log.ZDebug(context.Background(), "online new session msg come", "highWaterMarkOffset",
-		claim.HighWaterMarkOffset(), "topic", claim.Topic(), "partition", claim.Partition())
+func (mc *OnlineHistoryMongoConsumerHandler) ConsumeClaim(ctx context.Context, claim mq.Claim) error { // a instance in the consumer group
+	log.ZDebug(ctx, "online new session msg come", "topic", claim.Topic())
 	for msg := range claim.Messages() {
-		ctx := mc.historyConsumerGroup.GetContextFromMsg(msg)
-		if len(msg.Value) != 0 {
-			mc.handleChatWs2Mongo(ctx, msg, string(msg.Key), sess)
-		} else {
-			log.ZError(ctx, "mongo msg get from kafka but is nil", nil, "conversationID", msg.Key)
+		ctx := msg.Context()
+		if len(msg.Value) == 0 {
+			log.ZError(ctx, "mongo msg get from mq but is nil", nil, "conversationID", msg.Key)
+			msg.Ack()
+			continue
+		}
+		if err := mc.handleChatWs2Mongo(ctx, msg); err != nil {
+			prommetrics.MsgInsertMongoDeadLetterCounter.Inc()
+			msg.DeadLetter(err)
+			continue
 		}
-		sess.MarkMessage(msg, "")
+		msg.Ack()
 	}
 	return nil
 }
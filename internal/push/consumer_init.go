@@ -37,5 +37,5 @@ func NewConsumer(config *config.GlobalConfig, pusher *Pusher) (*Consumer, error)
 }
 
 func (c *Consumer) Start() {
-	go c.pushCh.pushConsumerGroup.RegisterHandleAndConsumer(context.Background(), &c.pushCh)
+	go c.pushCh.pushConsumerGroup.Consume(context.Background(), &c.pushCh)
 }
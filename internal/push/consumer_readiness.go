@@ -0,0 +1,152 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// pauseWatchInterval is how often the paused partitions are read and applied again, so that the partitions an
+// instance claims after a rebalance are paused too.
+const pauseWatchInterval = time.Second * 10
+
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+// lagTracker keeps the lag of the partitions this instance consumes, as reported with the latest message of each.
+type lagTracker struct {
+	lock  sync.Mutex
+	lags  map[topicPartition]int64
+	pause *cache.ConsumerPause
+}
+
+func newLagTracker() *lagTracker {
+	return &lagTracker{lags: make(map[topicPartition]int64), pause: &cache.ConsumerPause{}}
+}
+
+func (t *lagTracker) set(topic string, partition int32, lag int64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.lags[topicPartition{topic: topic, partition: partition}] = lag
+	prommetrics.PushConsumerLagGauge.WithLabelValues(topic, strconv.Itoa(int(partition))).Set(float64(lag))
+}
+
+// remove forgets partitions once their claim ended, another instance may consume them after the rebalance.
+func (t *lagTracker) remove(partitions map[topicPartition]struct{}) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for p := range partitions {
+		delete(t.lags, p)
+		prommetrics.PushConsumerLagGauge.DeleteLabelValues(p.topic, strconv.Itoa(int(p.partition)))
+	}
+}
+
+func (t *lagTracker) setPause(pause *cache.ConsumerPause) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.pause = pause
+}
+
+// ready reports whether no partition lags more than maxPartitionLag and all of them no more than maxTotalLag,
+// paused partitions are not counted and a threshold of 0 is not checked.
+func (t *lagTracker) ready(maxPartitionLag int64, maxTotalLag int64) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	var total int64
+	for p, lag := range t.lags {
+		if t.pause.Paused(p.topic, p.partition) {
+			continue
+		}
+		if maxPartitionLag > 0 && lag > maxPartitionLag {
+			return false
+		}
+		total += lag
+	}
+	return maxTotalLag <= 0 || total <= maxTotalLag
+}
+
+// reportReadiness sets the status of the grpc health service from the lag every pushReadiness.checkInterval
+// seconds until the process exits.
+func (c *Consumer) reportReadiness(healthServer *health.Server) {
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	conf := c.pushCh.config.PushReadiness
+	interval := time.Duration(conf.CheckInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Second * 5
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	status := grpc_health_v1.HealthCheckResponse_SERVING
+	for range ticker.C {
+		newStatus := grpc_health_v1.HealthCheckResponse_SERVING
+		if !c.pushCh.lags.ready(conf.MaxPartitionLag, conf.MaxTotalLag) {
+			newStatus = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+		}
+		if newStatus == status {
+			continue
+		}
+		status = newStatus
+		log.ZWarn(ctx, "push consumer readiness changed", nil, "status", status.String())
+		healthServer.SetServingStatus("", status)
+	}
+}
+
+// watchPause applies the partitions paused through the api to the consumer group until the process exits.
+func (c *Consumer) watchPause(pauseCache cache.ConsumerPauseCache) {
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	groupID := c.pushCh.config.Kafka.ConsumerGroupID.MsgToPush
+	pauser, _ := c.pushCh.pushConsumerGroup.(mq.Pauser)
+	var updateTime int64
+	ticker := time.NewTicker(pauseWatchInterval)
+	defer ticker.Stop()
+	for ; true; <-ticker.C {
+		pause, err := pauseCache.GetPause(ctx, groupID)
+		if err != nil {
+			log.ZWarn(ctx, "get push consumer pause failed", err)
+			continue
+		}
+		c.pushCh.lags.setPause(pause)
+		if pauser == nil {
+			if pause.UpdateTime != updateTime {
+				updateTime = pause.UpdateTime
+				log.ZWarn(ctx, "the mq backend can not pause partitions", nil, "pause", pause)
+			}
+			continue
+		}
+		if pause.UpdateTime != updateTime {
+			updateTime = pause.UpdateTime
+			log.ZInfo(ctx, "apply push consumer pause", "pause", pause)
+			pauser.ResumeAll()
+		}
+		if pause.All {
+			pauser.PauseAll()
+		} else if len(pause.Partitions) > 0 {
+			pauser.Pause(pause.Partitions)
+		}
+	}
+}
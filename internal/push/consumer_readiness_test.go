@@ -0,0 +1,63 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+)
+
+func TestLagTrackerReady(t *testing.T) {
+	lags := newLagTracker()
+	assert.True(t, lags.ready(100, 150))
+	lags.set("toPush", 0, 90)
+	lags.set("toPush", 1, 80)
+	assert.True(t, lags.ready(100, 0))
+	// The partitions are each under their threshold, but not all of them together.
+	assert.False(t, lags.ready(100, 150))
+	lags.set("toPush", 1, 120)
+	assert.False(t, lags.ready(100, 0))
+
+	// Paused partitions are lagging on purpose.
+	pause := &cache.ConsumerPause{}
+	pause.Add("toPush", []int32{1})
+	lags.setPause(pause)
+	assert.True(t, lags.ready(100, 150))
+	lags.setPause(&cache.ConsumerPause{All: true})
+	assert.True(t, lags.ready(1, 1))
+
+	lags.setPause(&cache.ConsumerPause{})
+	lags.remove(map[topicPartition]struct{}{{topic: "toPush", partition: 1}: {}})
+	assert.True(t, lags.ready(100, 150))
+}
+
+func TestConsumerPause(t *testing.T) {
+	pause := &cache.ConsumerPause{}
+	pause.Add("toPush", []int32{0, 2, 2})
+	pause.Add("toPush-app", []int32{1})
+	assert.Equal(t, []int32{0, 2}, pause.Partitions["toPush"])
+	assert.True(t, pause.Paused("toPush", 2))
+	assert.False(t, pause.Paused("toPush", 1))
+
+	pause.Remove("toPush", []int32{0})
+	assert.Equal(t, []int32{2}, pause.Partitions["toPush"])
+	pause.Remove("toPush", []int32{2})
+	_, ok := pause.Partitions["toPush"]
+	assert.False(t, ok)
+	assert.True(t, pause.Paused("toPush-app", 1))
+}
@@ -0,0 +1,271 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apns
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/offlinepush"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	productionHost = "https://api.push.apple.com"
+	sandboxHost    = "https://api.sandbox.push.apple.com"
+
+	// tokenRefreshInterval keeps the provider token inside the one hour window accepted by APNs.
+	tokenRefreshInterval = 50 * time.Minute
+	requestTimeout       = 10 * time.Second
+)
+
+// APNs rejection reasons that mean the device token should not be used again.
+const (
+	reasonBadDeviceToken         = "BadDeviceToken"
+	reasonUnregistered           = "Unregistered"
+	reasonDeviceTokenNotForTopic = "DeviceTokenNotForTopic"
+)
+
+// Apns pushes to iOS devices through APNs using token-based (.p8) authentication.
+// Device tokens are the ones registered through the fcm_update_token API for the iOS platform.
+type Apns struct {
+	keyID   string
+	teamID  string
+	authKey *ecdsa.PrivateKey
+	bundles []config.ApnsBundle
+	cache   cache.MsgModel
+	client  *http.Client
+
+	lock      sync.Mutex
+	token     string
+	tokenTime time.Time
+}
+
+func init() {
+	offlinepush.Register("apns", func(config *config.GlobalConfig, cache cache.MsgModel) (offlinepush.OfflinePusher, error) {
+		return NewClient(config, cache)
+	})
+}
+
+func NewClient(globalConfig *config.GlobalConfig, cache cache.MsgModel) (*Apns, error) {
+	conf := globalConfig.Push.Apns
+	if conf.KeyID == "" || conf.TeamID == "" {
+		return nil, errs.Wrap(fmt.Errorf("apns keyID and teamID must be set"))
+	}
+	if len(conf.Bundles) == 0 {
+		return nil, errs.Wrap(fmt.Errorf("apns bundles is empty"))
+	}
+	authKeyPath := conf.AuthKey
+	if !filepath.IsAbs(authKeyPath) {
+		authKeyPath = filepath.Join(config.GetProjectRoot(), "config", authKeyPath)
+	}
+	data, err := os.ReadFile(authKeyPath)
+	if err != nil {
+		return nil, errs.Wrap(err, "read apns auth key")
+	}
+	authKey, err := jwt.ParseECPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, errs.Wrap(err, "parse apns auth key")
+	}
+	return &Apns{
+		keyID:   conf.KeyID,
+		teamID:  conf.TeamID,
+		authKey: authKey,
+		bundles: conf.Bundles,
+		cache:   cache,
+		client: &http.Client{
+			Timeout:   requestTimeout,
+			Transport: &http.Transport{ForceAttemptHTTP2: true},
+		},
+	}, nil
+}
+
+type alert struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type aps struct {
+	Alert          alert  `json:"alert"`
+	Sound          string `json:"sound,omitempty"`
+	Badge          *int   `json:"badge,omitempty"`
+	MutableContent int    `json:"mutable-content"`
+}
+
+type payload struct {
+	Aps aps    `json:"aps"`
+	Ex  string `json:"ex,omitempty"`
+}
+
+type response struct {
+	Reason string `json:"reason"`
+}
+
+func (a *Apns) Push(ctx context.Context, userIDs []string, title, content string, opts *offlinepush.Opts) error {
+	var success, fail int
+	for _, userID := range userIDs {
+		deviceToken, err := a.cache.GetFcmToken(ctx, userID, constant.IOSPlatformID)
+		if err != nil || deviceToken == "" {
+			continue
+		}
+		badge, err := a.badge(ctx, userID, opts.IOSBadgeCount)
+		if err != nil {
+			log.ZWarn(ctx, "apns get badge failed", err, "userID", userID)
+			fail++
+			continue
+		}
+		body, err := json.Marshal(&payload{
+			Aps: aps{
+				Alert:          alert{Title: title, Body: content},
+				Sound:          opts.IOSSound(),
+				Badge:          badge,
+				MutableContent: 1,
+			},
+			Ex: opts.Ex,
+		})
+		if err != nil {
+			return errs.Wrap(err)
+		}
+		priority := "10"
+		if opts.IsBulkPriority() {
+			priority = "5"
+		}
+		if err := a.pushDevice(ctx, userID, deviceToken, priority, body); err != nil {
+			log.ZWarn(ctx, "apns push failed", err, "userID", userID)
+			fail++
+			continue
+		}
+		success++
+	}
+	log.ZDebug(ctx, "apns push result", "success", success, "fail", fail)
+	return nil
+}
+
+func (a *Apns) badge(ctx context.Context, userID string, incr bool) (*int, error) {
+	if incr {
+		count, err := a.cache.IncrUserBadgeUnreadCountSum(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		return &count, nil
+	}
+	count, err := a.cache.GetUserBadgeUnreadCountSum(ctx, userID)
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &count, nil
+}
+
+// pushDevice tries each configured bundle until APNs accepts the device token for the topic.
+// Tokens reported as invalid are removed so they are not used for later pushes.
+func (a *Apns) pushDevice(ctx context.Context, userID, deviceToken, priority string, body []byte) error {
+	var lastErr error
+	for _, bundle := range a.bundles {
+		status, reason, err := a.send(ctx, bundle, deviceToken, priority, body)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if status == http.StatusOK {
+			return nil
+		}
+		lastErr = errs.Wrap(fmt.Errorf("apns status %d reason %s", status, reason))
+		switch {
+		case reason == reasonDeviceTokenNotForTopic:
+			continue
+		case status == http.StatusGone || reason == reasonBadDeviceToken || reason == reasonUnregistered:
+			if err := a.cache.DelFcmToken(ctx, userID, constant.IOSPlatformID); err != nil {
+				log.ZWarn(ctx, "apns delete invalid device token failed", err, "userID", userID)
+			}
+			return lastErr
+		default:
+			return lastErr
+		}
+	}
+	return lastErr
+}
+
+func (a *Apns) send(ctx context.Context, bundle config.ApnsBundle, deviceToken, priority string, body []byte) (int, string, error) {
+	token, err := a.providerToken()
+	if err != nil {
+		return 0, "", err
+	}
+	host := sandboxHost
+	if bundle.Production {
+		host = productionHost
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, host+"/3/device/"+deviceToken, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", errs.Wrap(err)
+	}
+	req.Header.Set("authorization", "bearer "+token)
+	req.Header.Set("apns-topic", bundle.BundleID)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", priority)
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return 0, "", errs.Wrap(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return resp.StatusCode, "", nil
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, "", errs.Wrap(err)
+	}
+	var r response
+	_ = json.Unmarshal(data, &r)
+	return resp.StatusCode, r.Reason, nil
+}
+
+// providerToken returns the cached provider token, signing a new one when it is close to expiry.
+func (a *Apns) providerToken() (string, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.token != "" && time.Since(a.tokenTime) < tokenRefreshInterval {
+		return a.token, nil
+	}
+	now := time.Now()
+	t := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Issuer:   a.teamID,
+		IssuedAt: jwt.NewNumericDate(now),
+	})
+	t.Header["kid"] = a.keyID
+	token, err := t.SignedString(a.authKey)
+	if err != nil {
+		return "", errs.Wrap(err, "sign apns provider token")
+	}
+	a.token, a.tokenTime = token, now
+	return token, nil
+}
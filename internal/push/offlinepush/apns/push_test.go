@@ -0,0 +1,174 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apns
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/offlinepush"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type fakeCache struct {
+	cache.MsgModel
+	tokens  map[string]string
+	deleted []string
+}
+
+func (f *fakeCache) GetFcmToken(ctx context.Context, account string, platformID int) (string, error) {
+	return f.tokens[account], nil
+}
+
+func (f *fakeCache) DelFcmToken(ctx context.Context, account string, platformID int) error {
+	f.deleted = append(f.deleted, account)
+	delete(f.tokens, account)
+	return nil
+}
+
+func (f *fakeCache) GetUserBadgeUnreadCountSum(ctx context.Context, userID string) (int, error) {
+	return 0, redis.Nil
+}
+
+func (f *fakeCache) IncrUserBadgeUnreadCountSum(ctx context.Context, userID string) (int, error) {
+	return 1, nil
+}
+
+// reply answers every bundle with the status and reason set for its topic, recording the topics tried.
+type reply struct {
+	status int
+	reason string
+}
+
+func newTestApns(t *testing.T, fake *fakeCache, replies map[string]reply, topics *[]string) *Apns {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Apns{
+		keyID:   "KEY123",
+		teamID:  "TEAM123",
+		authKey: key,
+		bundles: []config.ApnsBundle{{BundleID: "io.openim.a"}, {BundleID: "io.openim.b", Production: true}},
+		cache:   fake,
+		client: &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			topic := req.Header.Get("apns-topic")
+			*topics = append(*topics, topic)
+			r := replies[topic]
+			body := ""
+			if r.reason != "" {
+				body = `{"reason":"` + r.reason + `"}`
+			}
+			return &http.Response{StatusCode: r.status, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}}, nil
+		})},
+	}
+}
+
+func TestProviderToken(t *testing.T) {
+	var topics []string
+	a := newTestApns(t, &fakeCache{}, nil, &topics)
+
+	token, err := a.providerToken()
+	assert.Nil(t, err)
+	claims := &jwt.RegisteredClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return &a.authKey.PublicKey, nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "ES256", parsed.Header["alg"])
+	assert.Equal(t, "KEY123", parsed.Header["kid"])
+	assert.Equal(t, "TEAM123", claims.Issuer)
+
+	cached, err := a.providerToken()
+	assert.Nil(t, err)
+	assert.Equal(t, token, cached)
+
+	// Once the token is close to the APNs one hour limit a new one is signed.
+	a.tokenTime = time.Now().Add(-tokenRefreshInterval)
+	refreshed, err := a.providerToken()
+	assert.Nil(t, err)
+	assert.NotEqual(t, token, refreshed)
+	assert.WithinDuration(t, time.Now(), a.tokenTime, time.Second)
+}
+
+func TestPushInvalidToken(t *testing.T) {
+	ctx := context.Background()
+	opts := &offlinepush.Opts{}
+	testCases := []struct {
+		name    string
+		replies map[string]reply
+		topics  []string
+		deleted []string
+	}{
+		{
+			name:    "accepted",
+			replies: map[string]reply{"io.openim.a": {status: http.StatusOK}},
+			topics:  []string{"io.openim.a"},
+		},
+		{
+			name: "wrong topic tries the next bundle",
+			replies: map[string]reply{
+				"io.openim.a": {status: http.StatusBadRequest, reason: reasonDeviceTokenNotForTopic},
+				"io.openim.b": {status: http.StatusOK},
+			},
+			topics: []string{"io.openim.a", "io.openim.b"},
+		},
+		{
+			name:    "bad device token",
+			replies: map[string]reply{"io.openim.a": {status: http.StatusBadRequest, reason: reasonBadDeviceToken}},
+			topics:  []string{"io.openim.a"},
+			deleted: []string{"u1"},
+		},
+		{
+			name:    "unregistered",
+			replies: map[string]reply{"io.openim.a": {status: http.StatusGone, reason: reasonUnregistered}},
+			topics:  []string{"io.openim.a"},
+			deleted: []string{"u1"},
+		},
+		{
+			name:    "server error keeps the token",
+			replies: map[string]reply{"io.openim.a": {status: http.StatusServiceUnavailable, reason: "ServiceUnavailable"}},
+			topics:  []string{"io.openim.a"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var topics []string
+			fake := &fakeCache{tokens: map[string]string{"u1": "device1"}}
+			a := newTestApns(t, fake, tc.replies, &topics)
+			assert.Nil(t, a.Push(ctx, []string{"u1", "u2"}, "title", "content", opts))
+			assert.Equal(t, tc.topics, topics)
+			assert.Equal(t, tc.deleted, fake.deleted)
+		})
+	}
+}
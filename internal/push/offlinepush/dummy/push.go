@@ -17,9 +17,17 @@ package dummy
 import (
 	"context"
 
-	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/offlinepush"
 )
 
+func init() {
+	offlinepush.Register(offlinepush.DefaultProvider, func(*config.GlobalConfig, cache.MsgModel) (offlinepush.OfflinePusher, error) {
+		return NewClient(), nil
+	})
+}
+
 func NewClient() *Dummy {
 	return &Dummy{}
 }
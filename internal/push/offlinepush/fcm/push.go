@@ -16,14 +16,16 @@ package fcm
 
 import (
 	"context"
+	"errors"
 	"path/filepath"
 
 	firebase "firebase.google.com/go"
 	"firebase.google.com/go/messaging"
 	"github.com/OpenIMSDK/protocol/constant"
-	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush"
+	"github.com/OpenIMSDK/tools/errs"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/offlinepush"
 	"github.com/redis/go-redis/v9"
 	"google.golang.org/api/option"
 )
@@ -37,6 +39,16 @@ type Fcm struct {
 	cache     cache.MsgModel
 }
 
+func init() {
+	offlinepush.Register("fcm", func(config *config.GlobalConfig, cache cache.MsgModel) (offlinepush.OfflinePusher, error) {
+		client := NewClient(config, cache)
+		if client == nil {
+			return nil, errs.Wrap(errors.New("fcm client init failed, check the service account file"))
+		}
+		return client, nil
+	})
+}
+
 // NewClient initializes a new FCM client using the Firebase Admin SDK.
 // It requires the FCM service account credentials file located within the project's configuration directory.
 func NewClient(globalConfig *config.GlobalConfig, cache cache.MsgModel) *Fcm {
@@ -62,6 +74,9 @@ func (f *Fcm) Push(ctx context.Context, userIDs []string, title, content string,
 	for _, account := range userIDs {
 		var personTokens []string
 		for _, v := range Terminal {
+			if opts.PlatformID != 0 && opts.PlatformID != v {
+				continue
+			}
 			Token, err := f.cache.GetFcmToken(ctx, account, v)
 			if err == nil {
 				personTokens = append(personTokens, Token)
@@ -76,7 +91,22 @@ func (f *Fcm) Push(ctx context.Context, userIDs []string, title, content string,
 	notification.Title = title
 	var messages []*messaging.Message
 	for userID, personTokens := range allTokens {
-		apns := &messaging.APNSConfig{Payload: &messaging.APNSPayload{Aps: &messaging.Aps{Sound: opts.IOSPushSound}}}
+		apns := &messaging.APNSConfig{Payload: &messaging.APNSPayload{Aps: &messaging.Aps{Sound: opts.IOSSound()}}}
+		var android *messaging.AndroidConfig
+		switch {
+		case opts.IsHighPriority():
+			android = &messaging.AndroidConfig{Priority: "high"}
+			apns.Headers = map[string]string{"apns-priority": "10"}
+		case opts.IsBulkPriority():
+			android = &messaging.AndroidConfig{Priority: "normal"}
+			apns.Headers = map[string]string{"apns-priority": "5"}
+		}
+		if opts.Sound != "" {
+			if android == nil {
+				android = &messaging.AndroidConfig{}
+			}
+			android.Notification = &messaging.AndroidNotification{Sound: opts.Sound}
+		}
 		messageCount := len(messages)
 		if messageCount >= SinglePushCountLimit {
 			response, err := f.fcmMsgCli.SendAll(ctx, messages)
@@ -115,6 +145,7 @@ func (f *Fcm) Push(ctx context.Context, userIDs []string, title, content string,
 				Data:         map[string]string{"ex": opts.Ex},
 				Token:        token,
 				Notification: notification,
+				Android:      android,
 				APNS:         apns,
 			}
 			messages = append(messages, temp)
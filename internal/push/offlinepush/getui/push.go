@@ -27,10 +27,10 @@ import (
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/mcontext"
 	"github.com/OpenIMSDK/tools/utils/splitter"
-	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	http2 "github.com/openimsdk/open-im-server/v3/pkg/common/http"
+	"github.com/openimsdk/open-im-server/v3/pkg/offlinepush"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -58,6 +58,12 @@ type Client struct {
 	config          *config.GlobalConfig
 }
 
+func init() {
+	offlinepush.Register("getui", func(config *config.GlobalConfig, cache cache.MsgModel) (offlinepush.OfflinePusher, error) {
+		return NewClient(config, cache), nil
+	})
+}
+
 func NewClient(config *config.GlobalConfig, cache cache.MsgModel) *Client {
 	return &Client{cache: cache,
 		tokenExpireTime: tokenExpireTime,
@@ -0,0 +1,331 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils/splitter"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	http2 "github.com/openimsdk/open-im-server/v3/pkg/common/http"
+	"github.com/openimsdk/open-im-server/v3/pkg/offlinepush"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	authURL = "https://oauth-login.cloud.huawei.com/oauth2/v3/token"
+	pushURL = "https://push-api.cloud.huawei.com/v1/%s/messages:send"
+
+	// maxTokenNum is the number of device tokens Push Kit accepts in one request.
+	maxTokenNum = 1000
+	// tokenExpireMargin is subtracted from the access token lifetime so a cached token is never used right at expiry.
+	tokenExpireMargin = 5 * 60
+	requestTimeout    = 10
+)
+
+// Push Kit result codes.
+const (
+	codeSuccess          = "80000000"
+	codePartialSuccess   = "80100000"
+	codeOAuthFailed      = "80200001"
+	codeTokenExpired     = "80200003"
+	codeAllTokensInvalid = "80300007"
+)
+
+// Push Kit self-classification categories, notification importance and delivery urgency.
+const (
+	categoryIM           = "IM"
+	categoryVoip         = "VOIP"
+	categorySubscription = "SUBSCRIPTION"
+	categoryMarketing    = "MARKETING"
+
+	importanceNormal = "NORMAL"
+	importanceLow    = "LOW"
+
+	urgencyHigh   = "HIGH"
+	urgencyNormal = "NORMAL"
+
+	clickActionIntent  = 1
+	clickActionOpenApp = 3
+)
+
+var errTokenExpired = errors.New("hms access token expired")
+
+// Hms pushes to Huawei devices without GMS through Huawei Push Kit.
+// Device tokens are the ones registered through the fcm_update_token API for the Android platform.
+type Hms struct {
+	appID        string
+	clientSecret string
+	intent       string
+	cache        cache.MsgModel
+	client       *http.Client
+}
+
+func init() {
+	offlinepush.Register("hms", func(config *config.GlobalConfig, cache cache.MsgModel) (offlinepush.OfflinePusher, error) {
+		return NewClient(config, cache)
+	})
+}
+
+func NewClient(globalConfig *config.GlobalConfig, cache cache.MsgModel) (*Hms, error) {
+	conf := globalConfig.Push.Hms
+	if conf.AppID == "" || conf.ClientSecret == "" {
+		return nil, errs.Wrap(fmt.Errorf("hms appID and clientSecret must be set"))
+	}
+	return &Hms{
+		appID:        conf.AppID,
+		clientSecret: conf.ClientSecret,
+		intent:       conf.Intent,
+		cache:        cache,
+		client:       &http.Client{Timeout: requestTimeout * time.Second},
+	}, nil
+}
+
+type clickAction struct {
+	Type   int    `json:"type"`
+	Intent string `json:"intent,omitempty"`
+}
+
+type androidNotification struct {
+	Title       string      `json:"title"`
+	Body        string      `json:"body"`
+	ClickAction clickAction `json:"click_action"`
+	Importance  string      `json:"importance"`
+}
+
+type androidConfig struct {
+	Category     string               `json:"category,omitempty"`
+	Urgency      string               `json:"urgency,omitempty"`
+	Notification *androidNotification `json:"notification"`
+}
+
+type message struct {
+	Android *androidConfig `json:"android"`
+	Token   []string       `json:"token"`
+}
+
+type pushReq struct {
+	ValidateOnly bool     `json:"validate_only"`
+	Message      *message `json:"message"`
+}
+
+type pushResp struct {
+	Code      string `json:"code"`
+	Msg       string `json:"msg"`
+	RequestID string `json:"requestId"`
+}
+
+// partialResult is carried as a JSON string in pushResp.Msg when only some tokens were accepted.
+type partialResult struct {
+	Success       int      `json:"success"`
+	Failure       int      `json:"failure"`
+	IllegalTokens []string `json:"illegal_tokens"`
+}
+
+type authResp struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Error       int    `json:"error"`
+	ErrorDesc   string `json:"error_description"`
+}
+
+// classify maps a message to its Push Kit category and importance. Messages classified as marketing
+// are shown silently, everything else is delivered with normal importance.
+func classify(opts *offlinepush.Opts) (category, importance string) {
+	switch {
+	case opts.ContentType > constant.SignalingNotificationBegin && opts.ContentType < constant.SignalingNotificationEnd:
+		return categoryVoip, importanceNormal
+	case opts.ContentType == constant.BusinessNotification:
+		return categoryMarketing, importanceLow
+	case opts.SessionType == constant.NotificationChatType:
+		return categorySubscription, importanceNormal
+	default:
+		return categoryIM, importanceNormal
+	}
+}
+
+// urgency maps the message priority to the Push Kit delivery urgency, empty leaves the Push Kit default.
+func urgency(opts *offlinepush.Opts) string {
+	switch {
+	case opts.IsHighPriority():
+		return urgencyHigh
+	case opts.IsBulkPriority():
+		return urgencyNormal
+	default:
+		return ""
+	}
+}
+
+func (h *Hms) Push(ctx context.Context, userIDs []string, title, content string, opts *offlinepush.Opts) error {
+	tokenUsers := make(map[string]string)
+	tokens := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		deviceToken, err := h.cache.GetFcmToken(ctx, userID, constant.AndroidPlatformID)
+		if err != nil || deviceToken == "" {
+			continue
+		}
+		if _, ok := tokenUsers[deviceToken]; ok {
+			continue
+		}
+		tokenUsers[deviceToken] = userID
+		tokens = append(tokens, deviceToken)
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+	category, importance := classify(opts)
+	action := clickAction{Type: clickActionOpenApp}
+	if h.intent != "" {
+		action = clickAction{Type: clickActionIntent, Intent: h.intent}
+	}
+	for _, v := range splitter.NewSplitter(maxTokenNum, tokens).GetSplitResult() {
+		msg := &message{
+			Android: &androidConfig{
+				Category: category,
+				Urgency:  urgency(opts),
+				Notification: &androidNotification{
+					Title:       title,
+					Body:        content,
+					ClickAction: action,
+					Importance:  importance,
+				},
+			},
+			Token: v.Item,
+		}
+		illegalTokens, err := h.send(ctx, msg)
+		if err != nil {
+			log.ZWarn(ctx, "hms push failed", err, "tokenNum", len(v.Item))
+		}
+		h.removeTokens(ctx, illegalTokens, tokenUsers)
+	}
+	return nil
+}
+
+// send pushes msg, refreshing the access token once if Push Kit reports it expired,
+// and returns the device tokens Push Kit rejected as invalid.
+func (h *Hms) send(ctx context.Context, msg *message) ([]string, error) {
+	illegalTokens, err := h.sendWithToken(ctx, msg, false)
+	if err == errTokenExpired {
+		if err := h.cache.DelHmsToken(ctx); err != nil {
+			log.ZWarn(ctx, "hms delete expired access token failed", err)
+		}
+		return h.sendWithToken(ctx, msg, true)
+	}
+	return illegalTokens, err
+}
+
+func (h *Hms) sendWithToken(ctx context.Context, msg *message, refresh bool) ([]string, error) {
+	accessToken, err := h.accessToken(ctx, refresh)
+	if err != nil {
+		return nil, err
+	}
+	header := map[string]string{"Authorization": "Bearer " + accessToken}
+	data, err := http2.Post(ctx, fmt.Sprintf(pushURL, h.appID), header, &pushReq{Message: msg}, requestTimeout)
+	if err != nil {
+		return nil, err
+	}
+	var resp pushResp
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, errs.Wrap(err, "hms unmarshal push resp")
+	}
+	switch resp.Code {
+	case codeSuccess:
+		return nil, nil
+	case codePartialSuccess:
+		var result partialResult
+		if err := json.Unmarshal([]byte(resp.Msg), &result); err != nil {
+			return nil, errs.Wrap(err, "hms unmarshal partial result")
+		}
+		log.ZDebug(ctx, "hms push partial success", "success", result.Success, "failure", result.Failure, "requestID", resp.RequestID)
+		return result.IllegalTokens, nil
+	case codeAllTokensInvalid:
+		return msg.Token, errs.Wrap(fmt.Errorf("hms all tokens invalid, requestID %s", resp.RequestID))
+	case codeTokenExpired, codeOAuthFailed:
+		if !refresh {
+			return nil, errTokenExpired
+		}
+	}
+	return nil, errs.Wrap(fmt.Errorf("hms push code %s msg %s requestID %s", resp.Code, resp.Msg, resp.RequestID))
+}
+
+// accessToken returns the OAuth access token cached in redis, requesting a new one when it is missing or refresh is set.
+func (h *Hms) accessToken(ctx context.Context, refresh bool) (string, error) {
+	if !refresh {
+		token, err := h.cache.GetHmsToken(ctx)
+		if err == nil {
+			return token, nil
+		}
+		if errs.Unwrap(err) != redis.Nil {
+			return "", err
+		}
+	}
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {h.appID},
+		"client_secret": {h.clientSecret},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, authURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+	var auth authResp
+	if err := json.Unmarshal(data, &auth); err != nil {
+		return "", errs.Wrap(err, "hms unmarshal auth resp")
+	}
+	if auth.AccessToken == "" {
+		return "", errs.Wrap(fmt.Errorf("hms auth failed, error %d %s", auth.Error, auth.ErrorDesc))
+	}
+	if expire := auth.ExpiresIn - tokenExpireMargin; expire > 0 {
+		if err := h.cache.SetHmsToken(ctx, auth.AccessToken, expire); err != nil {
+			log.ZWarn(ctx, "hms cache access token failed", err)
+		}
+	}
+	return auth.AccessToken, nil
+}
+
+// removeTokens deletes device tokens rejected by Push Kit so they are not used for later pushes.
+func (h *Hms) removeTokens(ctx context.Context, tokens []string, tokenUsers map[string]string) {
+	for _, token := range tokens {
+		userID, ok := tokenUsers[token]
+		if !ok {
+			continue
+		}
+		if err := h.cache.DelFcmToken(ctx, userID, constant.AndroidPlatformID); err != nil {
+			log.ZWarn(ctx, "hms delete invalid device token failed", err, "userID", userID)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hms
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/offlinepush"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type fakeCache struct {
+	cache.MsgModel
+	tokens      map[string]string
+	deleted     []string
+	hmsToken    string
+	hmsExpire   int64
+	hmsTokenDel int
+}
+
+func (f *fakeCache) GetFcmToken(ctx context.Context, account string, platformID int) (string, error) {
+	return f.tokens[account], nil
+}
+
+func (f *fakeCache) DelFcmToken(ctx context.Context, account string, platformID int) error {
+	f.deleted = append(f.deleted, account)
+	return nil
+}
+
+func (f *fakeCache) SetHmsToken(ctx context.Context, token string, expireTime int64) error {
+	f.hmsToken, f.hmsExpire = token, expireTime
+	return nil
+}
+
+func (f *fakeCache) GetHmsToken(ctx context.Context) (string, error) {
+	if f.hmsToken == "" {
+		return "", errs.Wrap(redis.Nil)
+	}
+	return f.hmsToken, nil
+}
+
+func (f *fakeCache) DelHmsToken(ctx context.Context) error {
+	f.hmsTokenDel++
+	f.hmsToken = ""
+	return nil
+}
+
+// fakePushKit answers OAuth requests with a new numbered access token and push requests with
+// the next code from codes, recording the access token each push was sent with.
+type fakePushKit struct {
+	codes      []pushResp
+	authCount  int
+	pushTokens []string
+	pushed     [][]string
+}
+
+func (p *fakePushKit) roundTrip(req *http.Request) (*http.Response, error) {
+	var body string
+	if req.URL.String() == authURL {
+		p.authCount++
+		data, _ := json.Marshal(&authResp{AccessToken: "access" + strconv.Itoa(p.authCount), ExpiresIn: 3600})
+		body = string(data)
+	} else {
+		var r pushReq
+		data, _ := io.ReadAll(req.Body)
+		_ = json.Unmarshal(data, &r)
+		p.pushTokens = append(p.pushTokens, strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "))
+		p.pushed = append(p.pushed, r.Message.Token)
+		resp := p.codes[0]
+		p.codes = p.codes[1:]
+		data, _ = json.Marshal(&resp)
+		body = string(data)
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}}, nil
+}
+
+func newTestHms(t *testing.T, fake *fakeCache, kit *fakePushKit) *Hms {
+	// Push requests go through the shared http client, which uses the default transport.
+	transport := http.DefaultTransport
+	http.DefaultTransport = roundTripFunc(kit.roundTrip)
+	t.Cleanup(func() { http.DefaultTransport = transport })
+	return &Hms{
+		appID:        "app",
+		clientSecret: "secret",
+		cache:        fake,
+		client:       &http.Client{Transport: roundTripFunc(kit.roundTrip)},
+	}
+}
+
+func TestAccessToken(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeCache{}
+	kit := &fakePushKit{}
+	h := newTestHms(t, fake, kit)
+
+	token, err := h.accessToken(ctx, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "access1", token)
+	assert.Equal(t, "access1", fake.hmsToken)
+	assert.Equal(t, int64(3600-tokenExpireMargin), fake.hmsExpire)
+
+	// The cached token is used until a refresh is asked for.
+	token, err = h.accessToken(ctx, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "access1", token)
+	assert.Equal(t, 1, kit.authCount)
+
+	token, err = h.accessToken(ctx, true)
+	assert.Nil(t, err)
+	assert.Equal(t, "access2", token)
+	assert.Equal(t, "access2", fake.hmsToken)
+}
+
+func TestPushTokenExpired(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeCache{tokens: map[string]string{"u1": "device1"}, hmsToken: "stale"}
+	kit := &fakePushKit{codes: []pushResp{{Code: codeTokenExpired}, {Code: codeSuccess}}}
+	h := newTestHms(t, fake, kit)
+
+	assert.Nil(t, h.Push(ctx, []string{"u1"}, "title", "content", &offlinepush.Opts{}))
+	assert.Equal(t, []string{"stale", "access1"}, kit.pushTokens)
+	assert.Equal(t, 1, fake.hmsTokenDel)
+	assert.Equal(t, "access1", fake.hmsToken)
+	assert.Empty(t, fake.deleted)
+}
+
+func TestPushInvalidTokens(t *testing.T) {
+	ctx := context.Background()
+	partial, _ := json.Marshal(&partialResult{Success: 1, Failure: 1, IllegalTokens: []string{"device2"}})
+	testCases := []struct {
+		name    string
+		resp    pushResp
+		deleted []string
+	}{
+		{name: "success", resp: pushResp{Code: codeSuccess}},
+		{name: "partial success", resp: pushResp{Code: codePartialSuccess, Msg: string(partial)}, deleted: []string{"u2"}},
+		{name: "all invalid", resp: pushResp{Code: codeAllTokensInvalid}, deleted: []string{"u1", "u2"}},
+		{name: "other error keeps the tokens", resp: pushResp{Code: "81000001"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := &fakeCache{tokens: map[string]string{"u1": "device1", "u2": "device2", "u3": "device1"}, hmsToken: "access"}
+			kit := &fakePushKit{codes: []pushResp{tc.resp}}
+			h := newTestHms(t, fake, kit)
+			assert.Nil(t, h.Push(ctx, []string{"u1", "u2", "u3", "u4"}, "title", "content", &offlinepush.Opts{}))
+			// A device token shared by two users is only pushed once.
+			assert.Equal(t, [][]string{{"device1", "device2"}}, kit.pushed)
+			assert.Equal(t, tc.deleted, fake.deleted)
+		})
+	}
+}
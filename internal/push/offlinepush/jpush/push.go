@@ -19,16 +19,23 @@ import (
 	"encoding/base64"
 	"fmt"
 
-	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush"
 	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/jpush/body"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	http2 "github.com/openimsdk/open-im-server/v3/pkg/common/http"
+	"github.com/openimsdk/open-im-server/v3/pkg/offlinepush"
 )
 
 type JPush struct {
 	config *config.GlobalConfig
 }
 
+func init() {
+	offlinepush.Register("jpush", func(config *config.GlobalConfig, _ cache.MsgModel) (offlinepush.OfflinePusher, error) {
+		return NewClient(config), nil
+	})
+}
+
 func NewClient(config *config.GlobalConfig) *JPush {
 	return &JPush{config: config}
 }
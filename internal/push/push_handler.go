@@ -17,56 +17,44 @@ package push
 import (
 	"context"
 
-	"github.com/IBM/sarama"
 	"github.com/OpenIMSDK/protocol/constant"
 	pbchat "github.com/OpenIMSDK/protocol/msg"
 	pbpush "github.com/OpenIMSDK/protocol/push"
+	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/utils"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
-	kfk "github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mqbuild"
 	"google.golang.org/protobuf/proto"
 )
 
 type ConsumerHandler struct {
-	pushConsumerGroup *kfk.MConsumerGroup
+	pushConsumerGroup mq.ConsumerGroup
 	pusher            *Pusher
+	config            *config.GlobalConfig
+	lags              *lagTracker
 }
 
 func NewConsumerHandler(config *config.GlobalConfig, pusher *Pusher) (*ConsumerHandler, error) {
 	var consumerHandler ConsumerHandler
 	consumerHandler.pusher = pusher
+	consumerHandler.config = config
+	consumerHandler.lags = newLagTracker()
 	var err error
-	var tlsConfig *kfk.TLSConfig
-	if config.Kafka.TLS != nil {
-		tlsConfig = &kfk.TLSConfig{
-			CACrt:              config.Kafka.TLS.CACrt,
-			ClientCrt:          config.Kafka.TLS.ClientCrt,
-			ClientKey:          config.Kafka.TLS.ClientKey,
-			ClientKeyPwd:       config.Kafka.TLS.ClientKeyPwd,
-			InsecureSkipVerify: false,
-		}
-	}
-	consumerHandler.pushConsumerGroup, err = kfk.NewMConsumerGroup(&kfk.MConsumerGroupConfig{
-		KafkaVersion:   sarama.V2_0_0_0,
-		OffsetsInitial: sarama.OffsetNewest,
-		IsReturnErr:    false,
-		UserName:       config.Kafka.Username,
-		Password:       config.Kafka.Password,
-	}, []string{config.Kafka.MsgToPush.Topic}, config.Kafka.Addr,
-		config.Kafka.ConsumerGroupID.MsgToPush,
-		tlsConfig)
+	consumerHandler.pushConsumerGroup, err = mqbuild.NewConsumerGroup(config, config.Kafka.ConsumerGroupID.MsgToPush, []string{config.Kafka.MsgToPush.Topic})
 	if err != nil {
 		return nil, err
 	}
 	return &consumerHandler, nil
 }
 
-func (c *ConsumerHandler) handleMs2PsChat(ctx context.Context, msg []byte) {
+// handleMs2PsChat returns an error only when the msg can not be decoded, failed pushes are logged and dropped.
+func (c *ConsumerHandler) handleMs2PsChat(ctx context.Context, msg []byte) error {
 	msgFromMQ := pbchat.PushMsgDataToMQ{}
 	if err := proto.Unmarshal(msg, &msgFromMQ); err != nil {
 		log.ZError(ctx, "push Unmarshal msg err", err, "msg", string(msg))
-		return
+		return errs.Wrap(err, "unmarshal push msg")
 	}
 	pbData := &pbpush.PushMsgReq{
 		MsgData:        msgFromMQ.MsgData,
@@ -76,7 +64,7 @@ func (c *ConsumerHandler) handleMs2PsChat(ctx context.Context, msg []byte) {
 	nowSec := utils.GetCurrentTimestampBySecond()
 	log.ZDebug(ctx, "push msg", "msg", pbData.String(), "sec", sec, "nowSec", nowSec)
 	if nowSec-sec > 10 {
-		return
+		return nil
 	}
 	var err error
 	switch msgFromMQ.MsgData.SessionType {
@@ -99,16 +87,20 @@ func (c *ConsumerHandler) handleMs2PsChat(ctx context.Context, msg []byte) {
 			log.ZError(ctx, "push failed", err, "msg", pbData.String())
 		}
 	}
+	return nil
 }
-func (ConsumerHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
-func (ConsumerHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return nil }
-func (c *ConsumerHandler) ConsumeClaim(sess sarama.ConsumerGroupSession,
-	claim sarama.ConsumerGroupClaim,
-) error {
+
+func (c *ConsumerHandler) ConsumeClaim(ctx context.Context, claim mq.Claim) error {
+	partitions := make(map[topicPartition]struct{})
+	defer c.lags.remove(partitions)
 	for msg := range claim.Messages() {
-		ctx := c.pushConsumerGroup.GetContextFromMsg(msg)
-		c.handleMs2PsChat(ctx, msg.Value)
-		sess.MarkMessage(msg, "")
+		partitions[topicPartition{topic: msg.Topic, partition: msg.Partition}] = struct{}{}
+		c.lags.set(msg.Topic, msg.Partition, msg.Lag)
+		if err := c.handleMs2PsChat(msg.Context(), msg.Value); err != nil {
+			msg.DeadLetter(err)
+			continue
+		}
+		msg.Ack()
 	}
 	return nil
 }
@@ -0,0 +1,111 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"github.com/openimsdk/open-im-server/v3/pkg/offlinepush"
+)
+
+// retryOfflinePush queues a failed offline push for the retry loop.
+func (p *Pusher) retryOfflinePush(ctx context.Context, conversationID string, userIDs []string, title, content string, opts *offlinepush.Opts, pushErr error) {
+	now := time.Now().UnixMilli()
+	entry := &controller.PushRetryEntry{
+		ID:             utils.OperationIDGenerator(),
+		ConversationID: conversationID,
+		AppID:          tenant.GetAppID(ctx),
+		UserIDs:        userIDs,
+		Title:          title,
+		Content:        content,
+		Opts:           opts,
+		CreateTime:     now,
+	}
+	p.scheduleRetry(ctx, entry, pushErr)
+}
+
+// retryPushes retries the due pushes every scanInterval until the process exits.
+func (p *Pusher) retryPushes() {
+	interval := time.Duration(p.config.PushRetry.ScanInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		p.retryDuePushes()
+	}
+}
+
+func (p *Pusher) retryDuePushes() {
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	entries, err := p.pushRetryDB.ClaimDue(ctx, int64(p.config.PushRetry.BatchSize))
+	if err != nil {
+		log.ZError(ctx, "claim due push retries failed", err)
+		return
+	}
+	for _, entry := range entries {
+		if err := p.offlinePusher.Push(tenant.WithAppID(ctx, entry.AppID), entry.UserIDs, entry.Title, entry.Content, entry.Opts); err != nil {
+			prommetrics.MsgOfflinePushRetryCounter.WithLabelValues("failed").Inc()
+			p.scheduleRetry(ctx, entry, err)
+			continue
+		}
+		prommetrics.MsgOfflinePushRetryCounter.WithLabelValues("success").Inc()
+		if err := p.pushRetryDB.Finish(ctx, entry.ID); err != nil {
+			log.ZWarn(ctx, "finish push retry failed", err, "id", entry.ID)
+		}
+	}
+}
+
+// scheduleRetry records a failed attempt of entry, it is dead-lettered once maxAttempts attempts failed.
+func (p *Pusher) scheduleRetry(ctx context.Context, entry *controller.PushRetryEntry, pushErr error) {
+	conf := p.config.PushRetry
+	entry.Attempts++
+	entry.LastErr = pushErr.Error()
+	entry.UpdateTime = time.Now().UnixMilli()
+	if entry.Attempts >= conf.MaxAttempts {
+		prommetrics.MsgOfflinePushDeadLetterCounter.Inc()
+		if err := p.pushRetryDB.DeadLetter(ctx, entry); err != nil {
+			log.ZError(ctx, "dead letter push failed", err, "id", entry.ID, "conversationID", entry.ConversationID)
+		}
+		return
+	}
+	backoff := retryBackoff(entry.Attempts, time.Duration(conf.BaseInterval)*time.Second, time.Duration(conf.MaxInterval)*time.Second)
+	if err := p.pushRetryDB.AddRetry(ctx, entry, time.Now().Add(backoff)); err != nil {
+		log.ZError(ctx, "add push retry failed", err, "id", entry.ID, "conversationID", entry.ConversationID)
+	}
+}
+
+// retryBackoff doubles base after each failed attempt, up to max.
+func retryBackoff(attempts int, base, max time.Duration) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 30 {
+		return max
+	}
+	backoff := base << (attempts - 1)
+	if backoff <= 0 || backoff > max {
+		return max
+	}
+	return backoff
+}
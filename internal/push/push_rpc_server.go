@@ -22,12 +22,23 @@ import (
 	"github.com/OpenIMSDK/tools/discoveryregistry"
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/utils"
+	_ "github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/apns"
+	_ "github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/dummy"
+	_ "github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/fcm"
+	_ "github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/getui"
+	_ "github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/hms"
+	_ "github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/jpush"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/offlinepush"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpccache"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
 )
 
 type pushServer struct {
@@ -40,9 +51,32 @@ func Start(config *config.GlobalConfig, client discoveryregistry.SvcDiscoveryReg
 	if err != nil {
 		return err
 	}
+	mongo, err := unrelation.NewMongo(config)
+	if err != nil {
+		return err
+	}
+	notificationAccountDB, err := mgo.NewNotificationAccountMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	notificationProfileDB, err := mgo.NewNotificationProfileMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	userMuteDB, err := mgo.NewUserMuteMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
 	cacheModel := cache.NewMsgCacheModel(rdb, config)
-	offlinePusher := NewOfflinePusher(config, cacheModel)
+	offlinePusher, err := offlinepush.NewOfflinePusher(config, cacheModel)
+	if err != nil {
+		return err
+	}
 	database := controller.NewPushDatabase(cacheModel)
+	var pushRetryDB controller.PushRetryDatabase
+	if config.PushRetry.Enable {
+		pushRetryDB = controller.NewPushRetryDatabase(cache.NewPushRetryCache(rdb))
+	}
 	groupRpcClient := rpcclient.NewGroupRpcClient(client, config)
 	conversationRpcClient := rpcclient.NewConversationRpcClient(client, config)
 	msgRpcClient := rpcclient.NewMessageRpcClient(client, config)
@@ -51,12 +85,19 @@ func Start(config *config.GlobalConfig, client discoveryregistry.SvcDiscoveryReg
 		client,
 		offlinePusher,
 		database,
-		rpccache.NewGroupLocalCache(groupRpcClient, rdb),
+		rpccache.NewGroupLocalCache(groupRpcClient, rdb, config.HotConversation),
 		rpccache.NewConversationLocalCache(conversationRpcClient, rdb),
 		&conversationRpcClient,
 		&groupRpcClient,
 		&msgRpcClient,
+		controller.NewNotificationAccountDatabase(notificationAccountDB),
+		pushRetryDB,
+		controller.NewNotificationProfileDatabase(notificationProfileDB),
+		controller.NewUserMuteDatabase(userMuteDB, cache.NewUserMuteCacheRedis(rdb, userMuteDB, cache.GetDefaultOpt())),
 	)
+	if pushRetryDB != nil {
+		go pusher.retryPushes()
+	}
 
 	pbpush.RegisterPushMsgServiceServer(server, &pushServer{
 		pusher: pusher,
@@ -69,6 +110,12 @@ func Start(config *config.GlobalConfig, client discoveryregistry.SvcDiscoveryReg
 	}
 
 	consumer.Start()
+	go consumer.watchPause(cache.NewConsumerPauseCache(rdb))
+	if config.PushReadiness.Enable {
+		healthServer := health.NewServer()
+		grpc_health_v1.RegisterHealthServer(server, healthServer)
+		go consumer.reportReadiness(healthServer)
+	}
 
 	return nil
 }
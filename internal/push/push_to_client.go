@@ -28,16 +28,11 @@ import (
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/mcontext"
 	"github.com/OpenIMSDK/tools/utils"
-	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush"
-	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/dummy"
-	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/fcm"
-	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/getui"
-	"github.com/openimsdk/open-im-server/v3/internal/push/offlinepush/jpush"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
-	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
 	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/offlinepush"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpccache"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	"golang.org/x/sync/errgroup"
@@ -54,6 +49,11 @@ type Pusher struct {
 	msgRpcClient           *rpcclient.MessageRpcClient
 	conversationRpcClient  *rpcclient.ConversationRpcClient
 	groupRpcClient         *rpcclient.GroupRpcClient
+	notificationAccountDB  controller.NotificationAccountDatabase
+	// pushRetryDB is nil when failed offline pushes are not retried.
+	pushRetryDB           controller.PushRetryDatabase
+	notificationProfileDB controller.NotificationProfileDatabase
+	userMuteDB            controller.UserMuteDatabase
 }
 
 var errNoOfflinePusher = errors.New("no offlinePusher is configured")
@@ -61,6 +61,8 @@ var errNoOfflinePusher = errors.New("no offlinePusher is configured")
 func NewPusher(config *config.GlobalConfig, discov discoveryregistry.SvcDiscoveryRegistry, offlinePusher offlinepush.OfflinePusher, database controller.PushDatabase,
 	groupLocalCache *rpccache.GroupLocalCache, conversationLocalCache *rpccache.ConversationLocalCache,
 	conversationRpcClient *rpcclient.ConversationRpcClient, groupRpcClient *rpcclient.GroupRpcClient, msgRpcClient *rpcclient.MessageRpcClient,
+	notificationAccountDB controller.NotificationAccountDatabase, pushRetryDB controller.PushRetryDatabase,
+	notificationProfileDB controller.NotificationProfileDatabase, userMuteDB controller.UserMuteDatabase,
 ) *Pusher {
 	return &Pusher{
 		config:                 config,
@@ -72,24 +74,13 @@ func NewPusher(config *config.GlobalConfig, discov discoveryregistry.SvcDiscover
 		msgRpcClient:           msgRpcClient,
 		conversationRpcClient:  conversationRpcClient,
 		groupRpcClient:         groupRpcClient,
+		notificationAccountDB:  notificationAccountDB,
+		pushRetryDB:            pushRetryDB,
+		notificationProfileDB:  notificationProfileDB,
+		userMuteDB:             userMuteDB,
 	}
 }
 
-func NewOfflinePusher(config *config.GlobalConfig, cache cache.MsgModel) offlinepush.OfflinePusher {
-	var offlinePusher offlinepush.OfflinePusher
-	switch config.Push.Enable {
-	case "getui":
-		offlinePusher = getui.NewClient(config, cache)
-	case "fcm":
-		offlinePusher = fcm.NewClient(config, cache)
-	case "jpush":
-		offlinePusher = jpush.NewClient(config)
-	default:
-		offlinePusher = dummy.NewClient()
-	}
-	return offlinePusher
-}
-
 func (p *Pusher) DeleteMemberAndSetConversationSeq(ctx context.Context, groupID string, userIDs []string) error {
 	conevrsationID := msgprocessor.GetConversationIDBySessionType(constant.SuperGroupChatType, groupID)
 	maxSeq, err := p.msgRpcClient.GetConversationMaxSeq(ctx, conevrsationID)
@@ -101,9 +92,22 @@ func (p *Pusher) DeleteMemberAndSetConversationSeq(ctx context.Context, groupID
 
 func (p *Pusher) Push2User(ctx context.Context, userIDs []string, msg *sdkws.MsgData) error {
 	log.ZDebug(ctx, "Get msg from msg_transfer And push msg", "userIDs", userIDs, "msg", msg.String())
+	if msg.SessionType == constant.NotificationChatType {
+		// Notification accounts must respect the category opt-outs of the receivers.
+		filtered, err := p.notificationAccountDB.FilterRecvUserIDs(ctx, msg.SendID, userIDs)
+		if err != nil {
+			return err
+		}
+		if len(filtered) == 0 {
+			log.ZDebug(ctx, "all receivers opted out of notification category", "sendID", msg.SendID, "userIDs", userIDs)
+			return nil
+		}
+		userIDs = filtered
+	}
 	if err := callbackOnlinePush(ctx, p.config, userIDs, msg); err != nil {
 		return err
 	}
+	p.recordMutedMsg(ctx, msg, userIDs)
 	// push
 	wsResults, err := p.GetConnsAndOnlinePush(ctx, msg, userIDs)
 	if err != nil {
@@ -192,6 +196,7 @@ func (p *Pusher) k8sOfflinePush2SuperGroup(ctx context.Context, groupID string,
 }
 func (p *Pusher) Push2SuperGroup(ctx context.Context, groupID string, msg *sdkws.MsgData) (err error) {
 	log.ZDebug(ctx, "Get super group msg from msg_transfer and push msg", "msg", msg.String(), "groupID", groupID)
+	p.groupLocalCache.RecordMsg(ctx, groupID)
 	var pushToUserIDs []string
 	if err = callbackBeforeSuperGroupOnlinePush(ctx, p.config, groupID, msg, &pushToUserIDs); err != nil {
 		return err
@@ -250,6 +255,7 @@ func (p *Pusher) Push2SuperGroup(ctx context.Context, groupID string, msg *sdkws
 		}
 	}
 
+	p.recordMutedMsg(ctx, msg, pushToUserIDs)
 	wsResults, err := p.GetConnsAndOnlinePush(ctx, msg, pushToUserIDs)
 	if err != nil {
 		return err
@@ -387,7 +393,11 @@ func (p *Pusher) k8sOnlinePush(ctx context.Context, msg *sdkws.MsgData, pushToUs
 }
 func (p *Pusher) GetConnsAndOnlinePush(ctx context.Context, msg *sdkws.MsgData, pushToUserIDs []string) (wsResults []*msggateway.SingleMsgToUserResults, err error) {
 	if p.config.Envs.Discovery == "k8s" {
-		return p.k8sOnlinePush(ctx, msg, pushToUserIDs)
+		wsResults, err = p.k8sOnlinePush(ctx, msg, pushToUserIDs)
+		if err == nil {
+			p.recordDeliveryLatency(ctx, msg, wsResults)
+		}
+		return wsResults, err
 	}
 	conns, err := p.discov.GetConns(ctx, p.config.RpcRegisterName.OpenImMessageGatewayName)
 	log.ZDebug(ctx, "get gateway conn", "conn length", len(conns))
@@ -430,26 +440,102 @@ func (p *Pusher) GetConnsAndOnlinePush(ctx context.Context, msg *sdkws.MsgData,
 	}
 
 	_ = wg.Wait()
+	p.recordDeliveryLatency(ctx, msg, wsResults)
 
 	// always return nil
 	return wsResults, nil
 }
 
+// recordDeliveryLatency samples the delivery latency of msg once it reached at least one online user.
+func (p *Pusher) recordDeliveryLatency(ctx context.Context, msg *sdkws.MsgData, wsResults []*msggateway.SingleMsgToUserResults) {
+	if msg.SendTime == 0 {
+		return
+	}
+	for _, result := range wsResults {
+		if !result.OnlinePush {
+			continue
+		}
+		if err := p.database.AddDeliveryLatency(ctx, utils.GetCurrentTimestampByMill()-msg.SendTime); err != nil {
+			log.ZWarn(ctx, "record delivery latency failed", err, "clientMsgID", msg.ClientMsgID)
+		}
+		return
+	}
+}
+
 func (p *Pusher) offlinePushMsg(ctx context.Context, conversationID string, msg *sdkws.MsgData, offlinePushUserIDs []string) error {
+	offlinePushUserIDs = p.filterMuters(ctx, msg, offlinePushUserIDs)
+	if len(offlinePushUserIDs) == 0 {
+		return nil
+	}
 	title, content, opts, err := p.getOfflinePushInfos(conversationID, msg)
 	if err != nil {
 		return err
 	}
-	err = p.offlinePusher.Push(ctx, offlinePushUserIDs, title, content, opts)
+	prommetrics.MsgOfflinePushPriorityCounter.WithLabelValues(msgprocessor.PriorityName(opts.Priority)).Inc()
+	var pushErr error
+	for sound, userIDs := range p.groupBySound(ctx, msg, offlinePushUserIDs) {
+		soundOpts := *opts
+		soundOpts.Sound = sound
+		for push, userIDs := range p.groupByLanguage(ctx, msg, userIDs, msgprocessor.LocalizedPush{Title: title, Desc: content}) {
+			if err := p.offlinePusher.Push(ctx, userIDs, push.Title, push.Desc, &soundOpts); err != nil {
+				prommetrics.MsgOfflinePushFailedCounter.Inc()
+				if p.pushRetryDB != nil {
+					p.retryOfflinePush(ctx, conversationID, userIDs, push.Title, push.Desc, &soundOpts, err)
+				}
+				pushErr = err
+			}
+		}
+	}
+	return pushErr
+}
+
+// groupByLanguage groups userIDs by the localized push msg carries for the language of their notification profile,
+// falling back to the default language of the config and then to fallback.
+func (p *Pusher) groupByLanguage(ctx context.Context, msg *sdkws.MsgData, userIDs []string, fallback msgprocessor.LocalizedPush) map[msgprocessor.LocalizedPush][]string {
+	pushes := msgprocessor.GetLocalizedPushes(msg)
+	if len(pushes) == 0 {
+		return map[msgprocessor.LocalizedPush][]string{fallback: userIDs}
+	}
+	languages, err := p.notificationProfileDB.FindLanguages(ctx, userIDs, msgprocessor.GetConversationIDByMsg(msg))
 	if err != nil {
-		prommetrics.MsgOfflinePushFailedCounter.Inc()
-		return err
+		log.ZWarn(ctx, "find notification languages failed", err, "clientMsgID", msg.ClientMsgID)
+	}
+	groups := make(map[msgprocessor.LocalizedPush][]string)
+	for _, userID := range userIDs {
+		push := fallback
+		if localized := msgprocessor.SelectLocalizedPush(pushes, languages[userID], p.config.Push.DefaultLanguage); localized != nil {
+			push = *localized
+			if push.Desc == "" {
+				push.Desc = push.Title
+			}
+		}
+		groups[push] = append(groups[push], userID)
 	}
-	return nil
+	return groups
+}
+
+// groupBySound groups userIDs by the sound of their notification profile for the conversation of msg, so that
+// each group is pushed with its sound. Users without a sound are grouped under "".
+func (p *Pusher) groupBySound(ctx context.Context, msg *sdkws.MsgData, userIDs []string) map[string][]string {
+	sounds, err := p.notificationProfileDB.FindSounds(ctx, userIDs, msgprocessor.GetConversationIDByMsg(msg))
+	if err != nil {
+		log.ZWarn(ctx, "find notification sounds failed", err, "clientMsgID", msg.ClientMsgID)
+	}
+	groups := make(map[string][]string)
+	for _, userID := range userIDs {
+		sound := sounds[userID]
+		groups[sound] = append(groups[sound], userID)
+	}
+	return groups
 }
 
 func (p *Pusher) GetOfflinePushOpts(msg *sdkws.MsgData) (opts *offlinepush.Opts, err error) {
-	opts = &offlinepush.Opts{Signal: &offlinepush.Signal{}}
+	opts = &offlinepush.Opts{
+		Signal:      &offlinepush.Signal{},
+		ContentType: msg.ContentType,
+		SessionType: msg.SessionType,
+		Priority:    msgprocessor.GetPriority(msg),
+	}
 	// if msg.ContentType > constant.SignalingNotificationBegin && msg.ContentType < constant.SignalingNotificationEnd {
 	// 	req := &sdkws.SignalReq{}
 	// 	if err := proto.Unmarshal(msg.Content, req); err != nil {
@@ -0,0 +1,67 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package push
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+)
+
+// isMutable reports whether msg is sent by a user, and so may be muted by its receivers.
+func isMutable(msg *sdkws.MsgData) bool {
+	if msg.SessionType == constant.NotificationChatType || msg.Seq == 0 {
+		return false
+	}
+	return msg.ContentType < constant.NotificationBegin || msg.ContentType > constant.NotificationEnd
+}
+
+// recordMutedMsg keeps msg out of the unread count of the users of userIDs who muted its sender.
+func (p *Pusher) recordMutedMsg(ctx context.Context, msg *sdkws.MsgData, userIDs []string) {
+	if !isMutable(msg) {
+		return
+	}
+	muters, err := p.userMuteDB.FindMuters(ctx, msg.SendID, userIDs)
+	if err != nil {
+		log.ZWarn(ctx, "find muters failed", err, "sendID", msg.SendID)
+		return
+	}
+	if len(muters) == 0 {
+		return
+	}
+	if err := p.userMuteDB.RecordMutedMsg(ctx, muters, msgprocessor.GetConversationIDByMsg(msg), msg.Seq); err != nil {
+		log.ZWarn(ctx, "record muted msg failed", err, "sendID", msg.SendID, "seq", msg.Seq)
+	}
+}
+
+// filterMuters drops the users who muted the sender of msg from offlinePushUserIDs.
+func (p *Pusher) filterMuters(ctx context.Context, msg *sdkws.MsgData, offlinePushUserIDs []string) []string {
+	if !isMutable(msg) {
+		return offlinePushUserIDs
+	}
+	muters, err := p.userMuteDB.FindMuters(ctx, msg.SendID, offlinePushUserIDs)
+	if err != nil {
+		log.ZWarn(ctx, "find muters failed", err, "sendID", msg.SendID)
+		return offlinePushUserIDs
+	}
+	if len(muters) == 0 {
+		return offlinePushUserIDs
+	}
+	return utils.DifferenceString(muters, offlinePushUserIDs)
+}
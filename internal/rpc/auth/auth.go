@@ -29,16 +29,21 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
+	tablerelation "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	"google.golang.org/grpc"
 )
 
 type authServer struct {
-	authDatabase   controller.AuthDatabase
-	userRpcClient  *rpcclient.UserRpcClient
-	RegisterCenter discoveryregistry.SvcDiscoveryRegistry
-	config         *config.GlobalConfig
+	authDatabase    controller.AuthDatabase
+	accountDatabase controller.UserAccountDatabase
+	userRpcClient   *rpcclient.UserRpcClient
+	RegisterCenter  discoveryregistry.SvcDiscoveryRegistry
+	config          *config.GlobalConfig
 }
 
 func Start(config *config.GlobalConfig, client discoveryregistry.SvcDiscoveryRegistry, server *grpc.Server) error {
@@ -46,33 +51,51 @@ func Start(config *config.GlobalConfig, client discoveryregistry.SvcDiscoveryReg
 	if err != nil {
 		return err
 	}
+	mongo, err := unrelation.NewMongo(config)
+	if err != nil {
+		return err
+	}
+	userAccountDB, err := mgo.NewUserAccountMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
 	userRpcClient := rpcclient.NewUserRpcClient(client, config)
 	pbauth.RegisterAuthServer(server, &authServer{
 		userRpcClient:  &userRpcClient,
 		RegisterCenter: client,
 		authDatabase: controller.NewAuthDatabase(
 			cache.NewMsgCacheModel(rdb, config),
-			config.Secret,
 			config.TokenPolicy.Expire,
 			config,
 		),
-		config: config,
+		accountDatabase: controller.NewUserAccountDatabase(userAccountDB),
+		config:          config,
 	})
 	return nil
 }
 
 func (s *authServer) UserToken(ctx context.Context, req *pbauth.UserTokenReq) (*pbauth.UserTokenResp, error) {
 	resp := pbauth.UserTokenResp{}
-	if req.Secret != s.config.Secret {
+	secret, err := tenant.Secret(ctx, s.config)
+	if err != nil {
+		return nil, err
+	}
+	if req.Secret != secret {
 		return nil, errs.ErrNoPermission.Wrap("secret invalid")
 	}
 	if _, err := s.userRpcClient.GetUserInfo(ctx, req.UserID); err != nil {
 		return nil, err
 	}
+	if err := s.checkAccountActive(ctx, req.UserID); err != nil {
+		return nil, err
+	}
 	token, err := s.authDatabase.CreateToken(ctx, req.UserID, int(req.PlatformID))
 	if err != nil {
 		return nil, err
 	}
+	if err := s.kickByLoginPolicy(ctx, req.UserID, int(req.PlatformID), token); err != nil {
+		return nil, err
+	}
 	prommetrics.UserLoginCounter.Inc()
 	resp.Token = token
 	resp.ExpireTimeSeconds = s.config.TokenPolicy.Expire * 24 * 60 * 60
@@ -92,20 +115,46 @@ func (s *authServer) GetUserToken(ctx context.Context, req *pbauth.GetUserTokenR
 	if _, err := s.userRpcClient.GetUserInfo(ctx, req.UserID); err != nil {
 		return nil, err
 	}
+	if err := s.checkAccountActive(ctx, req.UserID); err != nil {
+		return nil, err
+	}
 	token, err := s.authDatabase.CreateToken(ctx, req.UserID, int(req.PlatformID))
 	if err != nil {
 		return nil, err
 	}
+	if err := s.kickByLoginPolicy(ctx, req.UserID, int(req.PlatformID), token); err != nil {
+		return nil, err
+	}
 	resp.Token = token
 	resp.ExpireTimeSeconds = s.config.TokenPolicy.Expire * 24 * 60 * 60
 	return &resp, nil
 }
 
+// checkAccountActive refuses tokens to deactivated users and users being deleted.
+func (s *authServer) checkAccountActive(ctx context.Context, userID string) error {
+	account, err := s.accountDatabase.GetAccount(ctx, userID)
+	if err != nil {
+		return err
+	}
+	switch account.Status {
+	case tablerelation.UserAccountActive:
+		return nil
+	case tablerelation.UserAccountDeactivated:
+		return errs.ErrNoPermission.Wrap("account is deactivated")
+	default:
+		return errs.ErrNoPermission.Wrap("account is deleted")
+	}
+}
+
 func (s *authServer) parseToken(ctx context.Context, tokensString string) (claims *tokenverify.Claims, err error) {
-	claims, err = tokenverify.GetClaimFromToken(tokensString, authverify.Secret(s.config.Secret))
+	appClaims, err := authverify.ParseClaims(tokensString, s.config)
 	if err != nil {
 		return nil, errs.Wrap(err)
 	}
+	if appID := tenant.GetAppID(ctx); appID != "" && appID != appClaims.AppID {
+		return nil, errs.ErrTokenInvalid.Wrap("token was issued for another app")
+	}
+	claims = &appClaims.Claims
 	m, err := s.authDatabase.GetTokensWithoutError(ctx, claims.UserID, claims.PlatformID)
 	if err != nil {
 		return nil, err
@@ -169,3 +218,17 @@ func (s *authServer) forceKickOff(ctx context.Context, userID string, platformID
 	}
 	return nil
 }
+
+// kickByLoginPolicy kicks the sessions pushed out of the login policy matrix by the newly issued token.
+func (s *authServer) kickByLoginPolicy(ctx context.Context, userID string, platformID int, token string) error {
+	platformIDs, err := s.authDatabase.KickTokensByLoginPolicy(ctx, userID, platformID, token)
+	if err != nil {
+		return err
+	}
+	for _, id := range platformIDs {
+		if err := s.forceKickOff(ctx, userID, int32(id), mcontext.GetOperationID(ctx)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
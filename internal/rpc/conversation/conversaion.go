@@ -17,7 +17,6 @@ package conversation
 import (
 	"context"
 	"errors"
-	"sort"
 
 	"github.com/OpenIMSDK/protocol/constant"
 	pbconversation "github.com/OpenIMSDK/protocol/conversation"
@@ -25,13 +24,14 @@ import (
 	"github.com/OpenIMSDK/tools/discoveryregistry"
 	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/log"
-	"github.com/OpenIMSDK/tools/tx"
 	"github.com/OpenIMSDK/tools/utils"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/convert"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/convsort"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/storage"
 	tablerelation "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
@@ -44,6 +44,7 @@ type conversationServer struct {
 	user                           *rpcclient.UserRpcClient
 	groupRpcClient                 *rpcclient.GroupRpcClient
 	conversationDatabase           controller.ConversationDatabase
+	userMuteDatabase               controller.UserMuteDatabase
 	conversationNotificationSender *notification.ConversationNotificationSender
 	config                         *config.GlobalConfig
 }
@@ -57,7 +58,15 @@ func Start(config *config.GlobalConfig, client discoveryregistry.SvcDiscoveryReg
 	if err != nil {
 		return err
 	}
-	conversationDB, err := mgo.NewConversationMongo(mongo.GetDatabase(config.Mongo.Database))
+	relationStorage, err := storage.NewRelation(config, mongo)
+	if err != nil {
+		return err
+	}
+	conversationDB, err := relationStorage.Conversation()
+	if err != nil {
+		return err
+	}
+	userMuteDB, err := mgo.NewUserMuteMongo(mongo.GetDatabase(config.Mongo.Database))
 	if err != nil {
 		return err
 	}
@@ -69,7 +78,8 @@ func Start(config *config.GlobalConfig, client discoveryregistry.SvcDiscoveryReg
 		user:                           &userRpcClient,
 		conversationNotificationSender: notification.NewConversationNotificationSender(config, &msgRpcClient),
 		groupRpcClient:                 &groupRpcClient,
-		conversationDatabase:           controller.NewConversationDatabase(conversationDB, cache.NewConversationRedis(rdb, cache.GetDefaultOpt(), conversationDB), tx.NewMongo(mongo.GetClient())),
+		conversationDatabase:           controller.NewConversationDatabase(conversationDB, cache.NewConversationRedis(rdb, cache.GetDefaultOpt(), conversationDB), relationStorage.Tx()),
+		userMuteDatabase:               controller.NewUserMuteDatabase(userMuteDB, cache.NewUserMuteCacheRedis(rdb, userMuteDB, cache.GetDefaultOpt())),
 		config:                         config,
 	})
 	return nil
@@ -128,36 +138,59 @@ func (c *conversationServer) GetSortedConversationList(ctx context.Context, req
 		return nil, err
 	}
 
+	// The messages of the users muted by req.UserID do not count as unread.
+	readSeqs := make(map[string]int64, len(maxSeqs))
+	for conversationID := range maxSeqs {
+		readSeqs[conversationID] = hasReadSeqs[conversationID]
+	}
+	mutedCounts, err := c.userMuteDatabase.MutedUnreadCounts(ctx, req.UserID, readSeqs)
+	if err != nil {
+		return nil, err
+	}
+
 	var unreadTotal int64
 	conversation_unreadCount := make(map[string]int64)
 	for conversationID, maxSeq := range maxSeqs {
-		unreadCount := maxSeq - hasReadSeqs[conversationID]
+		unreadCount := maxSeq - hasReadSeqs[conversationID] - mutedCounts[conversationID]
+		if unreadCount < 0 {
+			unreadCount = 0
+		}
 		conversation_unreadCount[conversationID] = unreadCount
 		unreadTotal += unreadCount
 	}
 
-	conversation_isPinTime := make(map[int64]string)
-	conversation_notPinTime := make(map[int64]string)
+	var (
+		pinned    []*pbconversation.ConversationElem
+		notPinned []*pbconversation.ConversationElem
+		mentioned = make(map[string]bool)
+	)
 	for _, v := range conversations {
-		conversationID := v.ConversationID
-		time := conversationMsg[conversationID].MsgInfo.LatestMsgRecvTime
-		conversationMsg[conversationID].RecvMsgOpt = v.RecvMsgOpt
+		conversationElem, ok := conversationMsg[v.ConversationID]
+		if !ok {
+			continue
+		}
+		conversationElem.RecvMsgOpt = v.RecvMsgOpt
+		conversationElem.UnreadCount = conversation_unreadCount[v.ConversationID]
+		if convsort.Mentioned(v.GroupAtType) {
+			mentioned[v.ConversationID] = true
+		}
 		if v.IsPinned {
-			conversationMsg[conversationID].IsPinned = v.IsPinned
-			conversation_isPinTime[time] = conversationID
+			conversationElem.IsPinned = v.IsPinned
+			pinned = append(pinned, conversationElem)
 			continue
 		}
-		conversation_notPinTime[time] = conversationID
+		notPinned = append(notPinned, conversationElem)
 	}
+	// Pinned conversations stay on top whatever the sort.
+	sortBy := convsort.GetSortBy(ctx)
+	convsort.Sort(pinned, sortBy, mentioned)
+	convsort.Sort(notPinned, sortBy, mentioned)
 	resp = &pbconversation.GetSortedConversationListResp{
 		ConversationTotal: int64(len(chatLogs)),
-		ConversationElems: []*pbconversation.ConversationElem{},
+		ConversationElems: append(append([]*pbconversation.ConversationElem{}, pinned...), notPinned...),
 		UnreadTotal:       unreadTotal,
 	}
 
-	c.conversationSort(conversation_isPinTime, resp, conversation_unreadCount, conversationMsg)
-	c.conversationSort(conversation_notPinTime, resp, conversation_unreadCount, conversationMsg)
-
 	resp.ConversationElems = utils.Paginate(resp.ConversationElems, int(req.Pagination.GetPageNumber()), int(req.Pagination.GetShowNumber()))
 	return resp, nil
 }
@@ -429,33 +462,6 @@ func (c *conversationServer) GetConversationOfflinePushUserIDs(
 	return &pbconversation.GetConversationOfflinePushUserIDsResp{UserIDs: utils.Keys(userIDSet)}, nil
 }
 
-func (c *conversationServer) conversationSort(
-	conversations map[int64]string,
-	resp *pbconversation.GetSortedConversationListResp,
-	conversation_unreadCount map[string]int64,
-	conversationMsg map[string]*pbconversation.ConversationElem,
-) {
-	keys := []int64{}
-	for key := range conversations {
-		keys = append(keys, key)
-	}
-
-	sort.Slice(keys[:], func(i, j int) bool {
-		return keys[i] > keys[j]
-	})
-	index := 0
-
-	cons := make([]*pbconversation.ConversationElem, len(conversations))
-	for _, v := range keys {
-		conversationID := conversations[v]
-		conversationElem := conversationMsg[conversationID]
-		conversationElem.UnreadCount = conversation_unreadCount[conversationID]
-		cons[index] = conversationElem
-		index++
-	}
-	resp.ConversationElems = append(resp.ConversationElems, cons...)
-}
-
 func (c *conversationServer) getConversationInfo(
 	ctx context.Context,
 	chatLogs map[string]*sdkws.MsgData,
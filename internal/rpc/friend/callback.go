@@ -189,3 +189,24 @@ func CallbackAfterRemoveBlack(ctx context.Context, globalConfig *config.GlobalCo
 	}
 	return nil
 }
+
+// CallbackAddFriendPrivacy returns the decision of the addFriendPrivacy callback, or allowed when it is disabled
+// or leaves the decision unchanged.
+func CallbackAddFriendPrivacy(ctx context.Context, globalConfig *config.GlobalConfig, fromUserID, toUserID string, addFriendPolicy int32, allowed bool) (bool, error) {
+	if !globalConfig.Callback.CallbackAddFriendPrivacy.Enable {
+		return allowed, nil
+	}
+	cbReq := &cbapi.CallbackAddFriendPrivacyReq{
+		CallbackCommand: cbapi.CallbackAddFriendPrivacyCommand,
+		FromUserID:      fromUserID,
+		ToUserID:        toUserID,
+		AddFriendPolicy: addFriendPolicy,
+		Allowed:         allowed,
+	}
+	resp := &cbapi.CallbackAddFriendPrivacyResp{}
+	if err := http.CallBackPostReturn(ctx, globalConfig.Callback.CallbackUrl, cbReq, resp, globalConfig.Callback.CallbackAddFriendPrivacy); err != nil {
+		return allowed, err
+	}
+	utils.NotNilReplace(&allowed, resp.Allowed)
+	return allowed, nil
+}
@@ -23,7 +23,6 @@ import (
 	registry "github.com/OpenIMSDK/tools/discoveryregistry"
 	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/log"
-	"github.com/OpenIMSDK/tools/tx"
 	"github.com/OpenIMSDK/tools/utils"
 	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
@@ -31,8 +30,11 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/storage"
 	tablerelation "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/eventexport"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient/notification"
 	"google.golang.org/grpc"
@@ -41,10 +43,14 @@ import (
 type friendServer struct {
 	friendDatabase        controller.FriendDatabase
 	blackDatabase         controller.BlackDatabase
+	labelDatabase         controller.FriendLabelDatabase
+	privacyDatabase       controller.UserPrivacyDatabase
+	userBlockDatabase     controller.UserBlockDatabase
 	userRpcClient         *rpcclient.UserRpcClient
 	notificationSender    *notification.FriendNotificationSender
 	conversationRpcClient rpcclient.ConversationRpcClient
 	RegisterCenter        registry.SvcDiscoveryRegistry
+	eventExporter         *eventexport.Exporter
 	config                *config.GlobalConfig
 }
 
@@ -61,17 +67,43 @@ func Start(config *config.GlobalConfig, client registry.SvcDiscoveryRegistry, se
 		return err
 	}
 
-	friendMongoDB, err := mgo.NewFriendMongo(mongo.GetDatabase(config.Mongo.Database))
+	// Initialize the storage of friends and blacklists, mongo or PostgreSQL
+	relationStorage, err := storage.NewRelation(config, mongo)
 	if err != nil {
 		return err
 	}
 
-	friendRequestMongoDB, err := mgo.NewFriendRequestMongo(mongo.GetDatabase(config.Mongo.Database))
+	friendDB, err := relationStorage.Friend()
 	if err != nil {
 		return err
 	}
 
-	blackMongoDB, err := mgo.NewBlackMongo(mongo.GetDatabase(config.Mongo.Database))
+	friendRequestDB, err := relationStorage.FriendRequest()
+	if err != nil {
+		return err
+	}
+
+	blackDB, err := relationStorage.Black()
+	if err != nil {
+		return err
+	}
+
+	friendLabelDB, err := mgo.NewFriendLabelMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+
+	userPrivacyDB, err := mgo.NewUserPrivacyMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+
+	userBlockDB, err := mgo.NewUserBlockMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+
+	eventExporter, err := eventexport.NewExporter(config)
 	if err != nil {
 		return err
 	}
@@ -86,22 +118,26 @@ func Start(config *config.GlobalConfig, client registry.SvcDiscoveryRegistry, se
 		&msgRpcClient,
 		notification.WithRpcFunc(userRpcClient.GetUsersInfo),
 	)
-	// Register Friend server with refactored MongoDB and Redis integrations
+	// Register Friend server with the relational storage and Redis integrations
 	pbfriend.RegisterFriendServer(server, &friendServer{
 		friendDatabase: controller.NewFriendDatabase(
-			friendMongoDB,
-			friendRequestMongoDB,
-			cache.NewFriendCacheRedis(rdb, friendMongoDB, cache.GetDefaultOpt()),
-			tx.NewMongo(mongo.GetClient()),
+			friendDB,
+			friendRequestDB,
+			cache.NewFriendCacheRedis(rdb, friendDB, cache.GetDefaultOpt()),
+			relationStorage.Tx(),
 		),
 		blackDatabase: controller.NewBlackDatabase(
-			blackMongoDB,
-			cache.NewBlackCacheRedis(rdb, blackMongoDB, cache.GetDefaultOpt()),
+			blackDB,
+			cache.NewBlackCacheRedis(rdb, blackDB, cache.GetDefaultOpt()),
 		),
+		labelDatabase:         controller.NewFriendLabelDatabase(friendLabelDB),
+		privacyDatabase:       controller.NewUserPrivacyDatabase(userPrivacyDB),
+		userBlockDatabase:     controller.NewUserBlockDatabase(userBlockDB, cache.NewUserBlockCacheRedis(rdb, userBlockDB, cache.GetDefaultOpt())),
 		userRpcClient:         &userRpcClient,
 		notificationSender:    notificationSender,
 		RegisterCenter:        client,
 		conversationRpcClient: rpcclient.NewConversationRpcClient(client, config),
+		eventExporter:         eventExporter,
 		config:                config,
 	})
 
@@ -117,12 +153,27 @@ func (s *friendServer) ApplyToAddFriend(ctx context.Context, req *pbfriend.Apply
 	if req.ToUserID == req.FromUserID {
 		return nil, errs.ErrCanNotAddYourself.Wrap("req.ToUserID", req.ToUserID)
 	}
+	if err := tenant.CheckIDs(ctx, req.FromUserID, req.ToUserID); err != nil {
+		return nil, err
+	}
 	if err = CallbackBeforeAddFriend(ctx, s.config, req); err != nil && err != errs.ErrCallbackContinue {
 		return nil, err
 	}
 	if _, err := s.userRpcClient.GetUsersInfoMap(ctx, []string{req.ToUserID, req.FromUserID}); err != nil {
 		return nil, err
 	}
+	if !authverify.IsAppManagerUid(ctx, s.config) {
+		blocked, err := s.userBlockDatabase.IsBlocked(ctx, req.ToUserID, req.FromUserID)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			return nil, errs.ErrBlockedByPeer.Wrap()
+		}
+		if err := s.checkAddFriendPrivacy(ctx, req.FromUserID, req.ToUserID); err != nil {
+			return nil, err
+		}
+	}
 	in1, in2, err := s.friendDatabase.CheckIn(ctx, req.FromUserID, req.ToUserID)
 	if err != nil {
 		return nil, err
@@ -155,6 +206,9 @@ func (s *friendServer) ImportFriends(ctx context.Context, req *pbfriend.ImportFr
 	if utils.Duplicate(req.FriendUserIDs) {
 		return nil, errs.ErrArgs.Wrap("friend userID repeated")
 	}
+	if err := tenant.CheckIDs(ctx, append([]string{req.OwnerUserID}, req.FriendUserIDs...)...); err != nil {
+		return nil, err
+	}
 	if err := CallbackBeforeImportFriends(ctx, s.config, req); err != nil {
 		return nil, err
 	}
@@ -168,6 +222,7 @@ func (s *friendServer) ImportFriends(ctx context.Context, req *pbfriend.ImportFr
 			ToUserID:     userID,
 			HandleResult: constant.FriendResponseAgree,
 		})
+		s.exportFriendAdded(ctx, req.OwnerUserID, userID, constant.BecomeFriendByImport)
 	}
 	if err := CallbackAfterImportFriends(ctx, s.config, req); err != nil {
 		return nil, err
@@ -175,6 +230,15 @@ func (s *friendServer) ImportFriends(ctx context.Context, req *pbfriend.ImportFr
 	return &pbfriend.ImportFriendResp{}, nil
 }
 
+// exportFriendAdded publishes a friendship, keyed by the user who added the friend.
+func (s *friendServer) exportFriendAdded(ctx context.Context, ownerUserID string, friendUserID string, addSource int) {
+	s.eventExporter.Publish(ctx, eventexport.TypeFriendAdded, ownerUserID, &eventexport.FriendAdded{
+		OwnerUserID:  ownerUserID,
+		FriendUserID: friendUserID,
+		AddSource:    int32(addSource),
+	})
+}
+
 // ok.
 func (s *friendServer) RespondFriendApply(ctx context.Context, req *pbfriend.RespondFriendApplyReq) (resp *pbfriend.RespondFriendApplyResp, err error) {
 	defer log.ZInfo(ctx, utils.GetFuncName()+" Return")
@@ -198,6 +262,7 @@ func (s *friendServer) RespondFriendApply(ctx context.Context, req *pbfriend.Res
 			return nil, err
 		}
 		s.notificationSender.FriendApplicationAgreedNotification(ctx, req)
+		s.exportFriendAdded(ctx, req.FromUserID, req.ToUserID, constant.BecomeFriendByApply)
 		return resp, nil
 	}
 	if req.HandleResult == constant.FriendResponseRefuse {
@@ -225,6 +290,9 @@ func (s *friendServer) DeleteFriend(ctx context.Context, req *pbfriend.DeleteFri
 	if err := s.friendDatabase.Delete(ctx, req.OwnerUserID, []string{req.FriendUserID}); err != nil {
 		return nil, err
 	}
+	if err := s.labelDatabase.RemoveDeletedFriends(ctx, req.OwnerUserID, []string{req.FriendUserID}); err != nil {
+		log.ZWarn(ctx, "remove deleted friend from labels failed", err, "ownerUserID", req.OwnerUserID, "friendUserID", req.FriendUserID)
+	}
 	s.notificationSender.FriendDeletedNotification(ctx, req)
 	if err := CallbackAfterDeleteFriend(ctx, s.config, req); err != nil {
 		return nil, err
@@ -0,0 +1,73 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package friend
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/utils"
+	tablerelation "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+// checkAddFriendPrivacy rejects the friend request of fromUserID when the AddFriendPolicy of toUserID, or the
+// addFriendPrivacy callback, does not allow it.
+func (s *friendServer) checkAddFriendPrivacy(ctx context.Context, fromUserID, toUserID string) error {
+	privacy, err := s.privacyDatabase.GetPrivacy(ctx, toUserID)
+	if err != nil {
+		return err
+	}
+	var allowed bool
+	switch privacy.AddFriendPolicy {
+	case tablerelation.AddFriendPolicyNobody:
+	case tablerelation.AddFriendPolicyContactsOfContacts:
+		allowed, err = s.hasCommonFriend(ctx, fromUserID, toUserID)
+		if err != nil {
+			return err
+		}
+	default:
+		allowed = true
+	}
+	allowed, err = CallbackAddFriendPrivacy(ctx, s.config, fromUserID, toUserID, privacy.AddFriendPolicy, allowed)
+	if err != nil && err != errs.ErrCallbackContinue {
+		return err
+	}
+	if !allowed {
+		return errs.ErrNoPermission.Wrap("the user does not accept friend requests from you")
+	}
+	return nil
+}
+
+// hasCommonFriend reports whether the two users share a friend, or toUserID already has fromUserID as a friend.
+func (s *friendServer) hasCommonFriend(ctx context.Context, fromUserID, toUserID string) (bool, error) {
+	toFriendUserIDs, err := s.friendDatabase.FindFriendUserIDs(ctx, toUserID)
+	if err != nil {
+		return false, err
+	}
+	if utils.IsContain(fromUserID, toFriendUserIDs) {
+		return true, nil
+	}
+	fromFriendUserIDs, err := s.friendDatabase.FindFriendUserIDs(ctx, fromUserID)
+	if err != nil {
+		return false, err
+	}
+	fromFriends := utils.SliceSet(fromFriendUserIDs)
+	for _, userID := range toFriendUserIDs {
+		if _, ok := fromFriends[userID]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -17,9 +17,6 @@ package group
 import (
 	"context"
 	"fmt"
-	"math/big"
-	"math/rand"
-	"strconv"
 	"strings"
 	"time"
 
@@ -33,7 +30,6 @@ import (
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/mcontext"
 	"github.com/OpenIMSDK/tools/mw/specialerror"
-	"github.com/OpenIMSDK/tools/tx"
 	"github.com/OpenIMSDK/tools/utils"
 	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
 	"github.com/openimsdk/open-im-server/v3/pkg/callbackstruct"
@@ -42,8 +38,13 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/storage"
 	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/eventexport"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/idgen"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/search"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
 	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient/grouphash"
@@ -60,15 +61,52 @@ func Start(config *config.GlobalConfig, client discoveryregistry.SvcDiscoveryReg
 	if err != nil {
 		return err
 	}
-	groupDB, err := mgo.NewGroupMongo(mongo.GetDatabase(config.Mongo.Database))
+	relationStorage, err := storage.NewRelation(config, mongo)
 	if err != nil {
 		return err
 	}
-	groupMemberDB, err := mgo.NewGroupMember(mongo.GetDatabase(config.Mongo.Database))
+	groupDB, err := relationStorage.Group()
 	if err != nil {
 		return err
 	}
-	groupRequestDB, err := mgo.NewGroupRequestMgo(mongo.GetDatabase(config.Mongo.Database))
+	groupMemberDB, err := relationStorage.GroupMember()
+	if err != nil {
+		return err
+	}
+	groupRequestDB, err := relationStorage.GroupRequest()
+	if err != nil {
+		return err
+	}
+	idGenerator, err := idgen.New(config, client)
+	if err != nil {
+		return err
+	}
+	groupRoleDB, err := mgo.NewGroupRoleMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	groupInviteLinkDB, err := mgo.NewGroupInviteLinkMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	groupDirectoryDB, err := mgo.NewGroupDirectoryMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	groupBackend, err := search.NewGroupBackend(config)
+	if err != nil {
+		return err
+	}
+	groupMemberEventDB, err := mgo.NewGroupMemberEventMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	groupHistoryDB, err := mgo.NewGroupHistoryVisibilityMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	msgDocModel := unrelation.NewMsgMongoDriver(mongo.GetDatabase(config.Mongo.Database), nil)
+	eventExporter, err := eventexport.NewExporter(config)
 	if err != nil {
 		return err
 	}
@@ -76,7 +114,7 @@ func Start(config *config.GlobalConfig, client discoveryregistry.SvcDiscoveryReg
 	msgRpcClient := rpcclient.NewMessageRpcClient(client, config)
 	conversationRpcClient := rpcclient.NewConversationRpcClient(client, config)
 	var gs groupServer
-	database := controller.NewGroupDatabase(rdb, groupDB, groupMemberDB, groupRequestDB, tx.NewMongo(mongo.GetClient()), grouphash.NewGroupHashFromGroupServer(&gs))
+	database := controller.NewGroupDatabase(rdb, groupDB, groupMemberDB, groupRequestDB, relationStorage.Tx(), grouphash.NewGroupHashFromGroupServer(&gs))
 	gs.db = database
 	gs.User = userRpcClient
 	gs.Notification = notification.NewGroupNotificationSender(database, &msgRpcClient, &userRpcClient, config, func(ctx context.Context, userIDs []string) ([]notification.CommonUser, error) {
@@ -88,6 +126,13 @@ func Start(config *config.GlobalConfig, client discoveryregistry.SvcDiscoveryReg
 	})
 	gs.conversationRpcClient = conversationRpcClient
 	gs.msgRpcClient = msgRpcClient
+	gs.idGenerator = idGenerator
+	gs.roleDB = controller.NewGroupRoleDatabase(groupRoleDB, cache.NewGroupRoleCacheRedis(rdb, groupRoleDB, cache.GetDefaultOpt()))
+	gs.inviteLinkDB = controller.NewGroupInviteLinkDatabase(groupInviteLinkDB)
+	gs.directoryDB = controller.NewGroupDirectoryDatabase(groupDirectoryDB, groupBackend)
+	gs.memberEventDB = controller.NewGroupMemberEventDatabase(groupMemberEventDB, config)
+	gs.historyDB = controller.NewGroupHistoryDatabase(groupHistoryDB, msgDocModel, cache.NewGroupHistoryCacheRedis(rdb, groupHistoryDB, msgDocModel, cache.GetDefaultOpt()))
+	gs.eventExporter = eventExporter
 	gs.config = config
 	pbgroup.RegisterGroupServer(server, &gs)
 	return nil
@@ -99,6 +144,13 @@ type groupServer struct {
 	Notification          *notification.GroupNotificationSender
 	conversationRpcClient rpcclient.ConversationRpcClient
 	msgRpcClient          rpcclient.MessageRpcClient
+	idGenerator           *idgen.IDGenerator
+	roleDB                controller.GroupRoleDatabase
+	inviteLinkDB          controller.GroupInviteLinkDatabase
+	directoryDB           controller.GroupDirectoryDatabase
+	memberEventDB         controller.GroupMemberEventDatabase
+	historyDB             controller.GroupHistoryDatabase
+	eventExporter         *eventexport.Exporter
 	config                *config.GlobalConfig
 }
 
@@ -173,10 +225,7 @@ func (s *groupServer) GenGroupID(ctx context.Context, groupID *string) error {
 		}
 	}
 	for i := 0; i < 10; i++ {
-		id := utils.Md5(strings.Join([]string{mcontext.GetOperationID(ctx), strconv.FormatInt(time.Now().UnixNano(), 10), strconv.Itoa(rand.Int())}, ",;,"))
-		bi := big.NewInt(0)
-		bi.SetString(id[0:8], 16)
-		id = bi.String()
+		id := s.idGenerator.GroupID(ctx)
 		_, err := s.db.TakeGroup(ctx, id)
 		if err == nil {
 			continue
@@ -208,6 +257,9 @@ func (s *groupServer) CreateGroup(ctx context.Context, req *pbgroup.CreateGroupR
 	if utils.Duplicate(userIDs) {
 		return nil, errs.ErrArgs.Wrap("group member repeated")
 	}
+	if err := tenant.CheckIDs(ctx, append([]string{req.GroupInfo.GroupID}, userIDs...)...); err != nil {
+		return nil, err
+	}
 	userMap, err := s.User.GetUsersInfoMap(ctx, userIDs)
 	if err != nil {
 		return nil, err
@@ -257,6 +309,15 @@ func (s *groupServer) CreateGroup(ctx context.Context, req *pbgroup.CreateGroupR
 	if err := s.db.CreateGroup(ctx, []*relationtb.GroupModel{group}, groupMembers); err != nil {
 		return nil, err
 	}
+	s.eventExporter.Publish(ctx, eventexport.TypeGroupCreated, group.GroupID, &eventexport.GroupCreated{
+		GroupID:       group.GroupID,
+		GroupName:     group.GroupName,
+		GroupType:     group.GroupType,
+		OwnerUserID:   req.OwnerUserID,
+		CreatorUserID: group.CreatorUserID,
+		MemberCount:   len(userIDs),
+	})
+	s.recordMemberEvent(ctx, group.GroupID, relationtb.GroupMemberEventJoin, userIDs, 0)
 	resp := &pbgroup.CreateGroupResp{GroupInfo: &sdkws.GroupInfo{}}
 	resp.GroupInfo = convert.Db2PbGroupInfo(group, req.OwnerUserID, uint32(len(userIDs)))
 	resp.GroupInfo.MemberCount = uint32(len(userIDs))
@@ -365,6 +426,9 @@ func (s *groupServer) InviteUserToGroup(ctx context.Context, req *pbgroup.Invite
 	if utils.Duplicate(req.InvitedUserIDs) {
 		return nil, errs.ErrArgs.Wrap("userID duplicate")
 	}
+	if err := tenant.CheckIDs(ctx, append([]string{req.GroupID}, req.InvitedUserIDs...)...); err != nil {
+		return nil, err
+	}
 	group, err := s.db.TakeGroup(ctx, req.GroupID)
 	if err != nil {
 		return nil, err
@@ -392,6 +456,13 @@ func (s *groupServer) InviteUserToGroup(ctx context.Context, req *pbgroup.Invite
 		if err := s.PopulateGroupMember(ctx, groupMember); err != nil {
 			return nil, err
 		}
+		ok, err := s.hasPermission(ctx, groupMember, relationtb.GroupPermissionInvite)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errs.ErrNoPermission.Wrap("the group role of the member does not allow inviting")
+		}
 	}
 
 	if err := CallbackBeforeInviteUserToGroup(ctx, s.config, req); err != nil {
@@ -450,6 +521,7 @@ func (s *groupServer) InviteUserToGroup(ctx context.Context, req *pbgroup.Invite
 		return nil, err
 	}
 	s.Notification.MemberInvitedNotification(ctx, req.GroupID, req.Reason, req.InvitedUserIDs)
+	s.recordMemberEvent(ctx, req.GroupID, relationtb.GroupMemberEventJoin, req.InvitedUserIDs, 0)
 	return resp, nil
 }
 
@@ -556,7 +628,9 @@ func (s *groupServer) KickGroupMember(ctx context.Context, req *pbgroup.KickGrou
 					return nil, errs.ErrNoPermission.Wrap("group admins cannot remove the group owner and other admins")
 				}
 			case constant.GroupOrdinaryUsers:
-				return nil, errs.ErrNoPermission.Wrap("opUserID no permission")
+				if err := s.checkOrdinaryTarget(ctx, opMember, member, relationtb.GroupPermissionKick); err != nil {
+					return nil, err
+				}
 			default:
 				return nil, errs.ErrNoPermission.Wrap("opUserID roleLevel unknown")
 			}
@@ -574,6 +648,13 @@ func (s *groupServer) KickGroupMember(ctx context.Context, req *pbgroup.KickGrou
 	if len(ownerUserIDs) > 0 {
 		ownerUserID = ownerUserIDs[0]
 	}
+	kicked := make([]*relationtb.GroupMemberModel, 0, len(req.KickedUserIDs))
+	for _, userID := range req.KickedUserIDs {
+		kicked = append(kicked, memberMap[userID])
+	}
+	if err := s.historyDB.RecordFormerMembers(ctx, kicked); err != nil {
+		return nil, err
+	}
 	if err := s.db.DeleteGroupMember(ctx, group.GroupID, req.KickedUserIDs); err != nil {
 		return nil, err
 	}
@@ -606,6 +687,7 @@ func (s *groupServer) KickGroupMember(ctx context.Context, req *pbgroup.KickGrou
 		tips.KickedUserList = append(tips.KickedUserList, convert.Db2PbGroupMember(memberMap[userID]))
 	}
 	s.Notification.MemberKickedNotification(ctx, tips)
+	s.recordMemberEvent(ctx, req.GroupID, relationtb.GroupMemberEventKick, req.KickedUserIDs, 0)
 	if err := s.deleteMemberAndSetConversationSeq(ctx, req.GroupID, req.KickedUserIDs); err != nil {
 		return nil, err
 	}
@@ -798,6 +880,7 @@ func (s *groupServer) GroupApplicationResponse(ctx context.Context, req *pbgroup
 			log.ZDebug(ctx, "GroupApplicationResponse", "member is nil")
 		} else {
 			s.Notification.MemberEnterNotification(ctx, req.GroupID, req.FromUserID)
+			s.recordMemberEvent(ctx, req.GroupID, relationtb.GroupMemberEventJoin, []string{req.FromUserID}, 0)
 		}
 	case constant.GroupResponseRefuse:
 		s.Notification.GroupApplicationRejectedNotification(ctx, req)
@@ -808,6 +891,14 @@ func (s *groupServer) GroupApplicationResponse(ctx context.Context, req *pbgroup
 
 func (s *groupServer) JoinGroup(ctx context.Context, req *pbgroup.JoinGroupReq) (resp *pbgroup.JoinGroupResp, err error) {
 	defer log.ZInfo(ctx, "JoinGroup.Return")
+	if err := tenant.CheckIDs(ctx, req.GroupID, req.InviterUserID); err != nil {
+		return nil, err
+	}
+	var linkToken string
+	if req.JoinSource == relationtb.JoinByInviteLink {
+		// The token of the invite link is not passed to callbacks nor kept in the request.
+		linkToken, req.Ex = req.Ex, ""
+	}
 	user, err := s.User.GetUserInfo(ctx, req.InviterUserID)
 	if err != nil {
 		return nil, err
@@ -838,8 +929,17 @@ func (s *groupServer) JoinGroup(ctx context.Context, req *pbgroup.JoinGroupReq)
 		return nil, err
 	}
 	log.ZInfo(ctx, "JoinGroup.groupInfo", "group", group, "eq", group.NeedVerification == constant.Directly)
+	directly := group.NeedVerification == constant.Directly
+	if req.JoinSource == relationtb.JoinByInviteLink {
+		// The link decides whether the join needs approval.
+		link, err := s.inviteLinkDB.UseLink(ctx, req.GroupID, linkToken)
+		if err != nil {
+			return nil, err
+		}
+		directly = !link.NeedApproval
+	}
 	resp = &pbgroup.JoinGroupResp{}
-	if group.NeedVerification == constant.Directly {
+	if directly {
 		groupMember := &relationtb.GroupMemberModel{
 			GroupID:        group.GroupID,
 			UserID:         user.UserID,
@@ -860,6 +960,7 @@ func (s *groupServer) JoinGroup(ctx context.Context, req *pbgroup.JoinGroupReq)
 			return nil, err
 		}
 		s.Notification.MemberEnterNotification(ctx, req.GroupID, req.InviterUserID)
+		s.recordMemberEvent(ctx, req.GroupID, relationtb.GroupMemberEventJoin, []string{req.InviterUserID}, 0)
 		if err = CallbackAfterJoinGroup(ctx, s.config, req); err != nil {
 			return nil, err
 		}
@@ -900,11 +1001,15 @@ func (s *groupServer) QuitGroup(ctx context.Context, req *pbgroup.QuitGroupReq)
 	if err := s.PopulateGroupMember(ctx, member); err != nil {
 		return nil, err
 	}
+	if err := s.historyDB.RecordFormerMembers(ctx, []*relationtb.GroupMemberModel{member}); err != nil {
+		return nil, err
+	}
 	err = s.db.DeleteGroupMember(ctx, req.GroupID, []string{req.UserID})
 	if err != nil {
 		return nil, err
 	}
 	_ = s.Notification.MemberQuitNotification(ctx, s.groupMemberDB2PB(member, 0))
+	s.recordMemberEvent(ctx, req.GroupID, relationtb.GroupMemberEventLeave, []string{req.UserID}, 0)
 	if err := s.deleteMemberAndSetConversationSeq(ctx, req.GroupID, []string{req.UserID}); err != nil {
 		return nil, err
 	}
@@ -916,7 +1021,19 @@ func (s *groupServer) QuitGroup(ctx context.Context, req *pbgroup.QuitGroupReq)
 	return resp, nil
 }
 
+// recordMemberEvent queues a membership change for the group member webhook, a failure does not fail the change.
+// Joins are exported as TypeGroupMemberJoined as well.
+func (s *groupServer) recordMemberEvent(ctx context.Context, groupID string, event string, userIDs []string, roleLevel int32) {
+	if event == relationtb.GroupMemberEventJoin {
+		s.eventExporter.Publish(ctx, eventexport.TypeGroupMemberJoined, groupID, &eventexport.GroupMemberJoined{GroupID: groupID, UserIDs: userIDs})
+	}
+	if err := s.memberEventDB.Record(ctx, groupID, event, userIDs, roleLevel); err != nil {
+		log.ZWarn(ctx, "record group member event failed", err, "groupID", groupID, "event", event, "userIDs", userIDs)
+	}
+}
+
 func (s *groupServer) deleteMemberAndSetConversationSeq(ctx context.Context, groupID string, userIDs []string) error {
+	s.unassignRoles(ctx, groupID, userIDs)
 	conevrsationID := msgprocessor.GetConversationIDBySessionType(constant.SuperGroupChatType, groupID)
 	maxSeq, err := s.msgRpcClient.GetConversationMaxSeq(ctx, conevrsationID)
 	if err != nil {
@@ -933,7 +1050,11 @@ func (s *groupServer) SetGroupInfo(ctx context.Context, req *pbgroup.SetGroupInf
 		if err != nil {
 			return nil, err
 		}
-		if !(opMember.RoleLevel == constant.GroupOwner || opMember.RoleLevel == constant.GroupAdmin) {
+		ok, err := s.hasPermission(ctx, opMember, relationtb.GroupPermissionEditInfo)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
 			return nil, errs.ErrNoPermission.Wrap("no group owner or admin")
 		}
 		if err := s.PopulateGroupMember(ctx, opMember); err != nil {
@@ -973,6 +1094,9 @@ func (s *groupServer) SetGroupInfo(ctx context.Context, req *pbgroup.SetGroupInf
 	if err != nil {
 		return nil, err
 	}
+	if err := s.directoryDB.UpdateGroupInfo(ctx, group.GroupID, group.GroupName, group.Introduction, group.FaceURL); err != nil {
+		log.ZWarn(ctx, "update group directory entry failed", err, "groupID", group.GroupID)
+	}
 	tips := &sdkws.GroupInfoSetTips{
 		Group:    s.groupDB2PB(group, owner.UserID, count),
 		MuteTime: 0,
@@ -1059,6 +1183,8 @@ func (s *groupServer) TransferGroupOwner(ctx context.Context, req *pbgroup.Trans
 		return nil, err
 	}
 	s.Notification.GroupOwnerTransferredNotification(ctx, req)
+	s.recordMemberEvent(ctx, req.GroupID, relationtb.GroupMemberEventRoleChange, []string{req.NewOwnerUserID}, constant.GroupOwner)
+	s.recordMemberEvent(ctx, req.GroupID, relationtb.GroupMemberEventRoleChange, []string{req.OldOwnerUserID}, newOwner.RoleLevel)
 	return resp, nil
 }
 
@@ -1205,6 +1331,9 @@ func (s *groupServer) DismissGroup(ctx context.Context, req *pbgroup.DismissGrou
 	if err := s.db.DismissGroup(ctx, req.GroupID, req.DeleteMember); err != nil {
 		return nil, err
 	}
+	if err := s.directoryDB.Unlist(ctx, req.GroupID); err != nil {
+		log.ZWarn(ctx, "unlist dismissed group failed", err, "groupID", req.GroupID)
+	}
 	if !req.DeleteMember {
 		num, err := s.db.FindGroupMemberNum(ctx, req.GroupID)
 		if err != nil {
@@ -1258,7 +1387,11 @@ func (s *groupServer) MuteGroupMember(ctx context.Context, req *pbgroup.MuteGrou
 				return nil, errs.ErrNoPermission.Wrap("set group admin mute")
 			}
 		case constant.GroupOrdinaryUsers:
-			if !(opMember.RoleLevel == constant.GroupAdmin || opMember.RoleLevel == constant.GroupOwner) {
+			ok, err := s.hasPermission(ctx, opMember, relationtb.GroupPermissionMute)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
 				return nil, errs.ErrNoPermission.Wrap("set group ordinary users mute")
 			}
 		}
@@ -1292,7 +1425,11 @@ func (s *groupServer) CancelMuteGroupMember(ctx context.Context, req *pbgroup.Ca
 				return nil, errs.ErrNoPermission.Wrap("set group admin mute")
 			}
 		case constant.GroupOrdinaryUsers:
-			if !(opMember.RoleLevel == constant.GroupAdmin || opMember.RoleLevel == constant.GroupOwner) {
+			ok, err := s.hasPermission(ctx, opMember, relationtb.GroupPermissionMute)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
 				return nil, errs.ErrNoPermission.Wrap("set group ordinary users mute")
 			}
 		}
@@ -1307,7 +1444,7 @@ func (s *groupServer) CancelMuteGroupMember(ctx context.Context, req *pbgroup.Ca
 
 func (s *groupServer) MuteGroup(ctx context.Context, req *pbgroup.MuteGroupReq) (*pbgroup.MuteGroupResp, error) {
 	resp := &pbgroup.MuteGroupResp{}
-	if err := s.CheckGroupAdmin(ctx, req.GroupID); err != nil {
+	if err := s.checkGroupPermission(ctx, req.GroupID, relationtb.GroupPermissionMute); err != nil {
 		return nil, err
 	}
 	if err := s.db.UpdateGroup(ctx, req.GroupID, UpdateGroupStatusMap(constant.GroupStatusMuted)); err != nil {
@@ -1319,7 +1456,7 @@ func (s *groupServer) MuteGroup(ctx context.Context, req *pbgroup.MuteGroupReq)
 
 func (s *groupServer) CancelMuteGroup(ctx context.Context, req *pbgroup.CancelMuteGroupReq) (*pbgroup.CancelMuteGroupResp, error) {
 	resp := &pbgroup.CancelMuteGroupResp{}
-	if err := s.CheckGroupAdmin(ctx, req.GroupID); err != nil {
+	if err := s.checkGroupPermission(ctx, req.GroupID, relationtb.GroupPermissionMute); err != nil {
 		return nil, err
 	}
 	if err := s.db.UpdateGroup(ctx, req.GroupID, UpdateGroupStatusMap(constant.GroupOk)); err != nil {
@@ -1436,6 +1573,7 @@ func (s *groupServer) SetGroupMemberInfo(ctx context.Context, req *pbgroup.SetGr
 	}
 	for _, member := range req.Members {
 		if member.RoleLevel != nil {
+			s.recordMemberEvent(ctx, member.GroupID, relationtb.GroupMemberEventRoleChange, []string{member.UserID}, member.RoleLevel.Value)
 			switch member.RoleLevel.Value {
 			case constant.GroupAdmin:
 				s.Notification.GroupMemberSetToAdminNotification(ctx, member.GroupID, member.UserID)
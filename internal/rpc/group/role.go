@@ -0,0 +1,76 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package group
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+// hasPermission reports whether the member may perform the operation guarded by permission.
+func (s *groupServer) hasPermission(ctx context.Context, member *relationtb.GroupMemberModel, permission int64) (bool, error) {
+	permissions, err := s.roleDB.GetMemberPermissions(ctx, member.GroupID, member.UserID, member.RoleLevel)
+	if err != nil {
+		return false, err
+	}
+	return permissions&permission != 0, nil
+}
+
+// checkGroupPermission lets app managers and members whose role grants the permission through.
+func (s *groupServer) checkGroupPermission(ctx context.Context, groupID string, permission int64) error {
+	if authverify.IsAppManagerUid(ctx, s.config) {
+		return nil
+	}
+	member, err := s.db.TakeGroupMember(ctx, groupID, mcontext.GetOpUserID(ctx))
+	if err != nil {
+		return err
+	}
+	ok, err := s.hasPermission(ctx, member, permission)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errs.ErrNoPermission.Wrap("the group role of the member does not allow this")
+	}
+	return nil
+}
+
+// checkOrdinaryTarget lets the op member act on an ordinary member when its role grants the permission.
+func (s *groupServer) checkOrdinaryTarget(ctx context.Context, opMember *relationtb.GroupMemberModel, member *relationtb.GroupMemberModel, permission int64) error {
+	if member.RoleLevel != constant.GroupOrdinaryUsers {
+		return errs.ErrNoPermission.Wrap("only the group owner or admins can act on the group owner and admins")
+	}
+	ok, err := s.hasPermission(ctx, opMember, permission)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errs.ErrNoPermission.Wrap("the group role of the member does not allow this")
+	}
+	return nil
+}
+
+// unassignRoles takes the custom role away from members leaving the group.
+func (s *groupServer) unassignRoles(ctx context.Context, groupID string, userIDs []string) {
+	if err := s.roleDB.UnassignMembers(ctx, groupID, userIDs); err != nil {
+		log.ZWarn(ctx, "unassign group roles failed", err, "groupID", groupID, "userIDs", userIDs)
+	}
+}
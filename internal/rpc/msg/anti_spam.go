@@ -0,0 +1,55 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+)
+
+func antiSpamLimits(config *config.GlobalConfig) controller.AntiSpamLimits {
+	return controller.AntiSpamLimits{
+		Rate:           config.AntiSpam.Rate,
+		RepeatLimit:    config.AntiSpam.RepeatLimit,
+		RepeatWindow:   time.Second * time.Duration(config.AntiSpam.RepeatWindow),
+		NewAccountAge:  time.Second * time.Duration(config.AntiSpam.NewAccount.Age),
+		NewAccountRate: config.AntiSpam.NewAccount.Rate,
+	}
+}
+
+// checkSpam counts msg against the limits of its sender, notifications and the messages of the managers are not
+// limited.
+func (m *msgServer) checkSpam(ctx context.Context, msg *sdkws.MsgData) error {
+	if m.antiSpamDB == nil || msg.SessionType == constant.NotificationChatType {
+		return nil
+	}
+	if msg.ContentType >= constant.NotificationBegin && msg.ContentType <= constant.NotificationEnd {
+		return nil
+	}
+	if authverify.IsManagerUserID(msg.SendID, m.config) {
+		return nil
+	}
+	user, err := m.UserLocalCache.GetUserInfo(ctx, msg.SendID)
+	if err != nil {
+		return err
+	}
+	return m.antiSpamDB.Check(ctx, msg.SendID, user.CreateTime, msg.Content)
+}
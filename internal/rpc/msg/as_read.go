@@ -108,8 +108,11 @@ func (m *msgServer) MarkMsgsAsRead(
 	if err != nil {
 		return
 	}
-	if err = m.MsgDatabase.MarkSingleChatMsgsAsRead(ctx, req.UserID, req.ConversationID, req.Seqs); err != nil {
-		return
+	// Group messages are shared by all members, who has read them is tracked by the members' has read seqs instead.
+	if conversation.ConversationType != constant.SuperGroupChatType {
+		if err = m.MsgDatabase.MarkSingleChatMsgsAsRead(ctx, req.UserID, req.ConversationID, req.Seqs); err != nil {
+			return
+		}
 	}
 
 	currentHasReadSeq, err := m.MsgDatabase.GetHasReadSeq(ctx, req.UserID, req.ConversationID)
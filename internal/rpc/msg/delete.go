@@ -16,14 +16,19 @@ package msg
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/OpenIMSDK/protocol/constant"
 	"github.com/OpenIMSDK/protocol/conversation"
 	"github.com/OpenIMSDK/protocol/msg"
 	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
 	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
 )
 
 func (m *msgServer) getMinSeqs(maxSeqs map[string]int64) map[string]int64 {
@@ -78,6 +83,9 @@ func (m *msgServer) DeleteMsgs(ctx context.Context, req *msg.DeleteMsgsReq) (*ms
 	}
 	isSyncSelf, isSyncOther := m.validateDeleteSyncOpt(req.DeleteSyncOpt)
 	if isSyncOther {
+		if err := m.checkLegalHold(ctx, req.UserID, req.ConversationID, req.Seqs); err != nil {
+			return nil, err
+		}
 		if err := m.MsgDatabase.DeleteMsgsPhysicalBySeqs(ctx, req.ConversationID, req.Seqs); err != nil {
 			return nil, err
 		}
@@ -110,6 +118,9 @@ func (m *msgServer) DeleteMsgPhysicalBySeq(
 	ctx context.Context,
 	req *msg.DeleteMsgPhysicalBySeqReq,
 ) (*msg.DeleteMsgPhysicalBySeqResp, error) {
+	if err := m.checkLegalHold(ctx, "", req.ConversationID, req.Seqs); err != nil {
+		return nil, err
+	}
 	err := m.MsgDatabase.DeleteMsgsPhysicalBySeqs(ctx, req.ConversationID, req.Seqs)
 	if err != nil {
 		return nil, err
@@ -141,6 +152,85 @@ func (m *msgServer) DeleteMsgPhysical(
 	return &msg.DeleteMsgPhysicalResp{}, nil
 }
 
+// deleteMsgsPhysicalMaxMsgs is the most messages one DeleteMsgsPhysical call deletes.
+const deleteMsgsPhysicalMaxMsgs = 1000
+
+// DeleteMsgsPhysical deletes messages like DeleteMsgPhysicalBySeq, removing them from the search index first and
+// optionally deleting the objects no remaining message refers to.
+func (m *msgServer) DeleteMsgsPhysical(ctx context.Context, req *apistruct.DeleteMsgsPhysicalReq) (*apistruct.DeleteMsgsPhysicalResp, error) {
+	if err := authverify.CheckAdmin(ctx, m.config); err != nil {
+		return nil, err
+	}
+	if (len(req.Seqs) == 0) == (req.SendTimeBefore <= 0) {
+		return nil, errs.ErrArgs.Wrap("either seqs or sendTimeBefore is set")
+	}
+	if len(req.Seqs) > deleteMsgsPhysicalMaxMsgs {
+		return nil, errs.ErrArgs.Wrap(fmt.Sprintf("at most %d seqs are deleted at once", deleteMsgsPhysicalMaxMsgs))
+	}
+	if req.DeleteObjects && m.s3Database == nil {
+		return nil, errs.ErrArgs.Wrap("no object storage is configured")
+	}
+	resp := &apistruct.DeleteMsgsPhysicalResp{}
+	var (
+		msgs []*unrelationtb.MsgInfoModel
+		err  error
+	)
+	if len(req.Seqs) > 0 {
+		msgs, err = m.PhysicalDeleteDatabase.FindMsgsBySeqs(ctx, req.ConversationID, req.Seqs)
+	} else {
+		msgs, err = m.PhysicalDeleteDatabase.FindMsgsBefore(ctx, req.ConversationID, req.SendTimeBefore, deleteMsgsPhysicalMaxMsgs+1)
+		if len(msgs) > deleteMsgsPhysicalMaxMsgs {
+			msgs = msgs[:deleteMsgsPhysicalMaxMsgs]
+			resp.HasMore = true
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return resp, nil
+	}
+	resp.Seqs = make([]int64, 0, len(msgs))
+	var names []string
+	for _, info := range msgs {
+		resp.Seqs = append(resp.Seqs, info.Msg.Seq)
+		for _, rawURL := range msgprocessor.ObjectURLs(info.Msg.ContentType, info.Msg.Content) {
+			if name, ok := msgprocessor.ObjectName(m.config.Object.ApiURL, rawURL); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	// The index goes first, a failed call can then be retried while the messages still exist.
+	if m.searchBackend != nil {
+		if err := m.searchBackend.Delete(ctx, req.ConversationID, resp.Seqs); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := m.DeleteMsgPhysicalBySeq(ctx, &msg.DeleteMsgPhysicalBySeqReq{ConversationID: req.ConversationID, Seqs: resp.Seqs}); err != nil {
+		return nil, err
+	}
+	if !req.DeleteObjects || len(names) == 0 {
+		return resp, nil
+	}
+	unreferenced := make([]string, 0, len(names))
+	for _, name := range utils.Distinct(names) {
+		referenced, err := m.PhysicalDeleteDatabase.IsObjectReferenced(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if referenced {
+			resp.KeptObjects++
+			continue
+		}
+		unreferenced = append(unreferenced, name)
+	}
+	resp.DeletedObjects, resp.ReclaimedBytes, err = m.s3Database.DeleteObjects(ctx, unreferenced)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 func (m *msgServer) clearConversation(
 	ctx context.Context,
 	conversationIDs []string,
@@ -0,0 +1,113 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+)
+
+func (m *msgServer) EditMsg(ctx context.Context, req *apistruct.EditMsgReq) (*apistruct.EditMsgResp, error) {
+	if !m.config.MsgEdit.Enable {
+		return nil, errs.ErrNoPermission.Wrap("msg edit is disabled")
+	}
+	if err := authverify.CheckAccessV3(ctx, req.UserID, m.config); err != nil {
+		return nil, err
+	}
+	target, err := m.getMsgBySeq(ctx, req.UserID, req.ConversationID, req.Seq)
+	if err != nil {
+		return nil, err
+	}
+	if target.ClientMsgID != req.ClientMsgID {
+		return nil, errs.ErrArgs.Wrap("clientMsgID does not match seq")
+	}
+	if target.SendID != req.UserID {
+		return nil, errs.ErrNoPermission.Wrap("only the sender can edit the msg")
+	}
+	switch target.ContentType {
+	case constant.Text, constant.AtText:
+	default:
+		return nil, errs.ErrArgs.Wrap("msg content type can not be edited")
+	}
+	now := time.Now()
+	if editWindowExpired(target.SendTime, tenant.Resolve(ctx, m.config).MsgEdit.Window, now) {
+		return nil, errs.ErrNoPermission.Wrap("msg edit window has expired")
+	}
+	content := utils.StructToJsonString(req.Content)
+	if err := m.MsgDatabase.EditMsg(ctx, req.ConversationID, req.Seq, content); err != nil {
+		return nil, err
+	}
+	history := &relation.MsgEditHistoryModel{
+		ConversationID: req.ConversationID,
+		Seq:            req.Seq,
+		ClientMsgID:    req.ClientMsgID,
+		EditorUserID:   req.UserID,
+		Content:        string(target.Content),
+		EditTime:       now,
+	}
+	if err := m.MsgEditDatabase.AddEditHistory(ctx, history); err != nil {
+		return nil, err
+	}
+	tips := &apistruct.MsgEditTips{
+		ConversationID: req.ConversationID,
+		Seq:            req.Seq,
+		ClientMsgID:    req.ClientMsgID,
+		EditorUserID:   req.UserID,
+		Content:        content,
+		EditTime:       now.UnixMilli(),
+	}
+	// Clients that miss the notification get the new content the next time they pull the message.
+	if err := m.sendTargetMsgNotification(ctx, msgprocessor.MsgEditNotification, req.UserID, target, tips); err != nil {
+		log.ZWarn(ctx, "msg edit notification failed", err, "conversationID", req.ConversationID, "seq", req.Seq)
+	}
+	return &apistruct.EditMsgResp{}, nil
+}
+
+// editWindowExpired reports whether a message sent at sendTime can no longer be edited, a window of 0 seconds never expires.
+func editWindowExpired(sendTime int64, window int, now time.Time) bool {
+	return window > 0 && now.UnixMilli()-sendTime > int64(window)*1000
+}
+
+func (m *msgServer) GetMsgEditHistory(ctx context.Context, req *apistruct.GetMsgEditHistoryReq) (*apistruct.GetMsgEditHistoryResp, error) {
+	if err := authverify.CheckAccessV3(ctx, req.UserID, m.config); err != nil {
+		return nil, err
+	}
+	if _, err := m.getMsgBySeq(ctx, req.UserID, req.ConversationID, req.Seq); err != nil {
+		return nil, err
+	}
+	histories, err := m.MsgEditDatabase.GetEditHistory(ctx, req.ConversationID, req.Seq)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apistruct.GetMsgEditHistoryResp{Histories: make([]*apistruct.MsgEditHistory, 0, len(histories))}
+	for _, history := range histories {
+		resp.Histories = append(resp.Histories, &apistruct.MsgEditHistory{
+			EditorUserID: history.EditorUserID,
+			Content:      history.Content,
+			EditTime:     history.EditTime.UnixMilli(),
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,32 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditWindowExpired(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	sendTime := now.Add(-10 * time.Minute).UnixMilli()
+	assert.False(t, editWindowExpired(sendTime, 600, now))
+	assert.True(t, editWindowExpired(sendTime-1, 600, now))
+	assert.False(t, editWindowExpired(sendTime+1, 600, now))
+	// A window of 0 lets messages be edited at any time.
+	assert.False(t, editWindowExpired(0, 0, now))
+}
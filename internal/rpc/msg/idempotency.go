@@ -0,0 +1,51 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+
+	pbmsg "github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+)
+
+// sendMsgOnce sends the message unless its sender sent the same clientMsgID within the dedup window, a retry
+// after a timeout gets the result of the first send instead of persisting a duplicate.
+func (m *msgServer) sendMsgOnce(ctx context.Context, req *pbmsg.SendMsgReq) (*pbmsg.SendMsgResp, error) {
+	sendID, clientMsgID := req.MsgData.SendID, req.MsgData.ClientMsgID
+	claimed, resp, err := m.msgDedupDB.Claim(ctx, sendID, clientMsgID)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		if resp == nil {
+			return nil, servererrs.ErrMsgSending.Wrap("the msg is being sent, retry later")
+		}
+		log.ZInfo(ctx, "duplicate msg, return the first result", "sendID", sendID, "clientMsgID", clientMsgID, "serverMsgID", resp.ServerMsgID)
+		return resp, nil
+	}
+	resp, err = m.sendMsg(ctx, req)
+	if err != nil || resp == nil {
+		if err := m.msgDedupDB.Release(ctx, sendID, clientMsgID); err != nil {
+			log.ZWarn(ctx, "release msg dedup claim failed", err, "sendID", sendID, "clientMsgID", clientMsgID)
+		}
+		return resp, err
+	}
+	if err := m.msgDedupDB.Done(ctx, sendID, clientMsgID, resp); err != nil {
+		log.ZWarn(ctx, "save msg dedup result failed", err, "sendID", sendID, "clientMsgID", clientMsgID)
+	}
+	return resp, nil
+}
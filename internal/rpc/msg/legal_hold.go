@@ -0,0 +1,34 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/utils"
+)
+
+// checkLegalHold refuses to physically delete the seqs of a conversation on hold or sent by a user on hold.
+func (m *msgServer) checkLegalHold(ctx context.Context, userID string, conversationID string, seqs []int64) error {
+	_, _, msgs, err := m.MsgDatabase.GetMsgBySeqs(ctx, userID, conversationID, seqs)
+	if err != nil {
+		return err
+	}
+	sendIDs := make([]string, 0, len(msgs))
+	for _, msg := range msgs {
+		sendIDs = append(sendIDs, msg.SendID)
+	}
+	return m.LegalHoldDatabase.CheckDelete(ctx, conversationID, utils.Distinct(sendIDs))
+}
@@ -0,0 +1,156 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/http"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"github.com/openimsdk/open-im-server/v3/pkg/moderation"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+)
+
+const (
+	moderationDefaultReloadInterval      = 30
+	moderationDefaultReviewerConcurrency = 10
+)
+
+// isModerated reports whether msg goes through moderation, notifications and the messages of the managers do not.
+func (m *msgServer) isModerated(msg *sdkws.MsgData) bool {
+	if m.moderationDB == nil || msg.SessionType == constant.NotificationChatType {
+		return false
+	}
+	if msg.ContentType >= constant.NotificationBegin && msg.ContentType <= constant.NotificationEnd {
+		return false
+	}
+	return !authverify.IsManagerUserID(msg.SendID, m.config)
+}
+
+// moderate applies the rules to the text of msg before it is stored, it returns ErrMsgBlocked for a blocked message
+// and masks the matches of the replace rules.
+func (m *msgServer) moderate(ctx context.Context, msg *sdkws.MsgData) error {
+	if !m.isModerated(msg) {
+		return nil
+	}
+	text, ok := moderation.Text(msg)
+	if !ok {
+		return nil
+	}
+	filter, err := m.moderationDB.Filter(ctx)
+	if err != nil {
+		return err
+	}
+	res := filter.Apply(text)
+	if res.BlockedBy != "" {
+		return servererrs.ErrMsgBlocked.Wrap("blocked by rule " + res.BlockedBy)
+	}
+	if res.Replaced {
+		if err := moderation.SetText(msg, res.Text); err != nil {
+			return err
+		}
+	}
+	if len(res.Flags) > 0 {
+		m.flagMsg(ctx, msg, &relation.ModerationFlagModel{Source: relation.ModerationFlagSourceRule, RuleIDs: res.Flags})
+	}
+	return nil
+}
+
+// flagMsg records flag for msg, the fields identifying the message are filled from msg.
+func (m *msgServer) flagMsg(ctx context.Context, msg *sdkws.MsgData, flag *relation.ModerationFlagModel) {
+	flag.ServerMsgID = msg.ServerMsgID
+	flag.ClientMsgID = msg.ClientMsgID
+	flag.ConversationID = msgprocessor.GetConversationIDByMsg(msg)
+	flag.SendID = msg.SendID
+	flag.ContentType = msg.ContentType
+	if err := m.moderationDB.Flag(ctx, flag); err != nil {
+		log.ZWarn(ctx, "flag msg failed", err, "serverMsgID", msg.ServerMsgID, "source", flag.Source)
+	}
+}
+
+// reviewReq returns what the reviewer and the scorers are sent for msg, nil when it has neither text nor picture.
+func reviewReq(msg *sdkws.MsgData) *moderation.ReviewReq {
+	req := &moderation.ReviewReq{
+		ServerMsgID:    msg.ServerMsgID,
+		ClientMsgID:    msg.ClientMsgID,
+		ConversationID: msgprocessor.GetConversationIDByMsg(msg),
+		SendID:         msg.SendID,
+		ContentType:    msg.ContentType,
+		ImageURL:       moderation.ImageURL(msg),
+	}
+	req.Text, _ = moderation.Text(msg)
+	if req.Text == "" && req.ImageURL == "" {
+		return nil
+	}
+	return req
+}
+
+// review posts a sent message to the external reviewer in the background. When the reviewer already has
+// Concurrency messages in flight the message is not reviewed.
+func (m *msgServer) review(ctx context.Context, msg *sdkws.MsgData) {
+	if m.reviewerSem == nil || !m.isModerated(msg) {
+		return
+	}
+	req := reviewReq(msg)
+	if req == nil {
+		return
+	}
+	select {
+	case m.reviewerSem <- struct{}{}:
+	default:
+		log.ZWarn(ctx, "moderation reviewer busy, msg not reviewed", nil, "serverMsgID", msg.ServerMsgID)
+		return
+	}
+	reviewCtx := tenant.WithAppID(mcontext.WithOpUserIDContext(mcontext.NewCtx("review_"+mcontext.GetOperationID(ctx)), msg.SendID), tenant.GetAppID(ctx))
+	go func() {
+		defer func() { <-m.reviewerSem }()
+		resp, err := m.callReviewer(reviewCtx, req)
+		if err != nil {
+			log.ZWarn(reviewCtx, "moderation reviewer failed", err, "serverMsgID", msg.ServerMsgID)
+			return
+		}
+		if resp.Verdict == moderation.VerdictFlag {
+			m.flagMsg(reviewCtx, msg, &relation.ModerationFlagModel{Source: relation.ModerationFlagSourceReviewer, Reason: resp.Reason})
+		}
+	}()
+}
+
+func (m *msgServer) callReviewer(ctx context.Context, req *moderation.ReviewReq) (*moderation.ReviewResp, error) {
+	reviewer := m.config.Moderation.Reviewer
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	var header map[string]string
+	if reviewer.Secret != "" {
+		if header, err = http.SignCallback(reviewer.Secret, body, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	resp := &moderation.ReviewResp{}
+	if err := http.PostReturn(ctx, reviewer.Url, header, json.RawMessage(body), resp, reviewer.Timeout); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
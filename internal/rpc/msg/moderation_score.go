@@ -0,0 +1,154 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/http"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"github.com/openimsdk/open-im-server/v3/pkg/moderation"
+)
+
+const (
+	scorerWords = "words"
+	scorerHttp  = "http"
+)
+
+// httpScorer posts the messages to an external scorer, signed like the callbacks when secret is set.
+type httpScorer struct {
+	url     string
+	secret  string
+	timeout int
+}
+
+func (s *httpScorer) Score(ctx context.Context, req *moderation.ReviewReq) (moderation.Scores, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	var header map[string]string
+	if s.secret != "" {
+		if header, err = http.SignCallback(s.secret, body, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	resp := &moderation.ScoreResp{}
+	if err := http.PostReturn(ctx, s.url, header, json.RawMessage(body), resp, s.timeout); err != nil {
+		return nil, err
+	}
+	return resp.Scores, nil
+}
+
+func newScorers(config *config.GlobalConfig) ([]moderation.Scorer, error) {
+	scoring := config.Moderation.Scoring
+	scorers := make([]moderation.Scorer, 0, len(scoring.Scorers))
+	for _, name := range scoring.Scorers {
+		switch name {
+		case scorerWords:
+			scorers = append(scorers, moderation.NewWordScorer(scoring.Words))
+		case scorerHttp:
+			if scoring.Url == "" {
+				return nil, errs.Wrap(fmt.Errorf("moderation scorer %s needs a url", name))
+			}
+			scorers = append(scorers, &httpScorer{url: scoring.Url, secret: scoring.Secret, timeout: scoring.Timeout})
+		default:
+			return nil, errs.Wrap(fmt.Errorf("unknown moderation scorer %s", name))
+		}
+	}
+	return scorers, nil
+}
+
+// sensitivity returns the level msg is scored at, the one set on its conversation or the default of its session type.
+func (m *msgServer) sensitivity(ctx context.Context, msg *sdkws.MsgData, conversationID string) (string, error) {
+	level, err := m.sensitivityDB.GetLevel(ctx, conversationID)
+	if err != nil || level != "" {
+		return level, err
+	}
+	switch msg.SessionType {
+	case constant.SingleChatType:
+		return m.config.Moderation.Scoring.SingleLevel, nil
+	case constant.SuperGroupChatType:
+		return m.config.Moderation.Scoring.GroupLevel, nil
+	default:
+		return moderation.SensitivityOff, nil
+	}
+}
+
+// score rates a sent message with the scorers in the background and flags it when a score reaches the threshold of
+// the sensitivity of its conversation. When Concurrency messages are being scored the message is not scored.
+func (m *msgServer) score(ctx context.Context, msg *sdkws.MsgData) {
+	if m.scorerSem == nil || len(m.scorers) == 0 || !m.isModerated(msg) {
+		return
+	}
+	req := reviewReq(msg)
+	if req == nil {
+		return
+	}
+	select {
+	case m.scorerSem <- struct{}{}:
+	default:
+		log.ZWarn(ctx, "moderation scorers busy, msg not scored", nil, "serverMsgID", msg.ServerMsgID)
+		return
+	}
+	scoreCtx := tenant.WithAppID(mcontext.WithOpUserIDContext(mcontext.NewCtx("score_"+mcontext.GetOperationID(ctx)), msg.SendID), tenant.GetAppID(ctx))
+	go func() {
+		defer func() { <-m.scorerSem }()
+		level, err := m.sensitivity(scoreCtx, msg, req.ConversationID)
+		if err != nil {
+			log.ZWarn(scoreCtx, "get moderation sensitivity failed", err, "conversationID", req.ConversationID)
+			return
+		}
+		thresholds := m.config.Moderation.Scoring.Thresholds
+		threshold, ok := moderation.Thresholds{Low: thresholds.Low, Medium: thresholds.Medium, Strict: thresholds.Strict}.Of(level)
+		if !ok {
+			return
+		}
+		scores := moderation.Scores{}
+		for _, scorer := range m.scorers {
+			res, err := scorer.Score(scoreCtx, req)
+			if err != nil {
+				log.ZWarn(scoreCtx, "moderation scorer failed", err, "serverMsgID", msg.ServerMsgID)
+				continue
+			}
+			scores.Merge(res)
+		}
+		exceeded := scores.Exceeded(threshold)
+		if len(exceeded) == 0 {
+			return
+		}
+		reasons := make([]string, 0, len(exceeded))
+		for _, category := range exceeded {
+			reasons = append(reasons, fmt.Sprintf("%s %.2f", category, scores[category]))
+		}
+		m.flagMsg(scoreCtx, msg, &relation.ModerationFlagModel{
+			Source:      relation.ModerationFlagSourceScorer,
+			Reason:      strings.Join(reasons, ", "),
+			Scores:      scores,
+			Sensitivity: level,
+		})
+	}()
+}
@@ -0,0 +1,58 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+)
+
+func notificationQuotaDefaults(config *config.GlobalConfig) controller.NotificationQuota {
+	return controller.NotificationQuota{
+		Rate:     config.NotificationQuota.Rate,
+		DailyCap: config.NotificationQuota.DailyCap,
+	}
+}
+
+// checkNotificationQuota counts msg against the quota of its sender when it is a notification account. The
+// system notifications and the accounts of the managers are not limited.
+func (m *msgServer) checkNotificationQuota(ctx context.Context, msg *sdkws.MsgData) error {
+	if m.notificationQuotaDB == nil {
+		return nil
+	}
+	if msg.ContentType >= constant.NotificationBegin && msg.ContentType <= constant.NotificationEnd {
+		return nil
+	}
+	if authverify.IsManagerUserID(msg.SendID, m.config) {
+		return nil
+	}
+	user, err := m.UserLocalCache.GetUserInfo(ctx, msg.SendID)
+	if err != nil {
+		return err
+	}
+	if user.AppMangerLevel != constant.AppNotificationAdmin {
+		return nil
+	}
+	var recvID string
+	if msg.SessionType == constant.SingleChatType || msg.SessionType == constant.NotificationChatType {
+		recvID = msg.RecvID
+	}
+	return m.notificationQuotaDB.Check(ctx, msg.SendID, recvID)
+}
@@ -0,0 +1,52 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"golang.org/x/time/rate"
+)
+
+// newBulkLimiter returns the limiter of bulk priority messages, nil when they are not throttled.
+func newBulkLimiter(config *config.GlobalConfig) *rate.Limiter {
+	if config.MsgPriority.BulkRate <= 0 {
+		return nil
+	}
+	burst := config.MsgPriority.BulkBurst
+	if burst < 1 {
+		burst = 1
+	}
+	return rate.NewLimiter(rate.Limit(config.MsgPriority.BulkRate), burst)
+}
+
+// throttle counts the message by priority and holds bulk priority messages back until the bulk rate allows them.
+func (m *msgServer) throttle(ctx context.Context, msg *sdkws.MsgData) error {
+	priority := msgprocessor.GetPriority(msg)
+	prommetrics.MsgPriorityCounter.WithLabelValues(msgprocessor.PriorityName(priority)).Inc()
+	if priority != msgprocessor.PriorityBulk || m.bulkLimiter == nil || m.bulkLimiter.Allow() {
+		return nil
+	}
+	prommetrics.MsgPriorityThrottledCounter.WithLabelValues(msgprocessor.PriorityName(priority)).Inc()
+	if err := m.bulkLimiter.Wait(ctx); err != nil {
+		return errs.Wrap(err, "bulk priority msg throttled")
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+)
+
+func (m *msgServer) SetMsgReaction(ctx context.Context, req *apistruct.SetMsgReactionReq) (*apistruct.SetMsgReactionResp, error) {
+	return m.changeMsgReaction(ctx, req, constant.ReactionMessageModifier)
+}
+
+func (m *msgServer) RemoveMsgReaction(ctx context.Context, req *apistruct.SetMsgReactionReq) (*apistruct.SetMsgReactionResp, error) {
+	return m.changeMsgReaction(ctx, req, constant.ReactionMessageDeleter)
+}
+
+func (m *msgServer) GetMsgReactions(ctx context.Context, req *apistruct.GetMsgReactionsReq) (*apistruct.GetMsgReactionsResp, error) {
+	if err := authverify.CheckAccessV3(ctx, req.UserID, m.config); err != nil {
+		return nil, err
+	}
+	if _, err := m.getReactionTargetMsg(ctx, req.UserID, req.ConversationID, req.Seq); err != nil {
+		return nil, err
+	}
+	reactions, err := m.MsgReactionDatabase.GetReactions(ctx, req.ConversationID, req.Seq)
+	if err != nil {
+		return nil, err
+	}
+	return &apistruct.GetMsgReactionsResp{Reactions: groupMsgReactions(reactions)}, nil
+}
+
+func (m *msgServer) changeMsgReaction(ctx context.Context, req *apistruct.SetMsgReactionReq, contentType int32) (*apistruct.SetMsgReactionResp, error) {
+	if req.Emoji == "" {
+		return nil, errs.ErrArgs.Wrap("emoji is empty")
+	}
+	if err := authverify.CheckAccessV3(ctx, req.UserID, m.config); err != nil {
+		return nil, err
+	}
+	target, err := m.getReactionTargetMsg(ctx, req.UserID, req.ConversationID, req.Seq)
+	if err != nil {
+		return nil, err
+	}
+	if contentType == constant.ReactionMessageModifier {
+		err = m.MsgReactionDatabase.SetReaction(ctx, req.ConversationID, req.Seq, req.UserID, req.Emoji)
+	} else {
+		err = m.MsgReactionDatabase.RemoveReaction(ctx, req.ConversationID, req.Seq, req.UserID, req.Emoji)
+	}
+	if err != nil {
+		return nil, err
+	}
+	reactions, err := m.MsgReactionDatabase.GetReactions(ctx, req.ConversationID, req.Seq)
+	if err != nil {
+		return nil, err
+	}
+	tips := &apistruct.MsgReactionTips{
+		ConversationID: req.ConversationID,
+		Seq:            req.Seq,
+		ClientMsgID:    target.ClientMsgID,
+		OpUserID:       req.UserID,
+		Emoji:          req.Emoji,
+		Reactions:      groupMsgReactions(reactions),
+	}
+	// The reaction is already stored, clients that miss the notification pick it up from get_msg_reactions.
+	if err := m.sendTargetMsgNotification(ctx, contentType, req.UserID, target, tips); err != nil {
+		log.ZWarn(ctx, "msg reaction notification failed", err, "conversationID", req.ConversationID, "seq", req.Seq)
+	}
+	return &apistruct.SetMsgReactionResp{}, nil
+}
+
+func (m *msgServer) getReactionTargetMsg(ctx context.Context, userID string, conversationID string, seq int64) (*sdkws.MsgData, error) {
+	if msgprocessor.IsNotification(conversationID) {
+		return nil, errs.ErrArgs.Wrap("notification conversation does not support reactions")
+	}
+	return m.getMsgBySeq(ctx, userID, conversationID, seq)
+}
+
+// groupMsgReactions folds the stored reactions into one entry per emoji, keeping first-reacted order.
+func groupMsgReactions(reactions []*relation.MsgReactionModel) []*apistruct.MsgReaction {
+	res := make([]*apistruct.MsgReaction, 0)
+	index := make(map[string]*apistruct.MsgReaction)
+	for _, reaction := range reactions {
+		r, ok := index[reaction.Emoji]
+		if !ok {
+			r = &apistruct.MsgReaction{Emoji: reaction.Emoji}
+			index[reaction.Emoji] = r
+			res = append(res, r)
+		}
+		r.UserIDs = append(r.UserIDs, reaction.UserID)
+	}
+	return res
+}
@@ -0,0 +1,37 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+func TestGroupMsgReactions(t *testing.T) {
+	assert.Equal(t, []*apistruct.MsgReaction{}, groupMsgReactions(nil))
+	reactions := []*relation.MsgReactionModel{
+		{UserID: "a", Emoji: "heart"},
+		{UserID: "b", Emoji: "+1"},
+		{UserID: "c", Emoji: "heart"},
+	}
+	assert.Equal(t, []*apistruct.MsgReaction{
+		{Emoji: "heart", UserIDs: []string{"a", "c"}},
+		{Emoji: "+1", UserIDs: []string{"b"}},
+	}, groupMsgReactions(reactions))
+}
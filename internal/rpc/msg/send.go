@@ -27,7 +27,9 @@ import (
 	"github.com/OpenIMSDK/tools/mcontext"
 	"github.com/OpenIMSDK/tools/utils"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
 	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgsize"
 )
 
 func (m *msgServer) SendMsg(ctx context.Context, req *pbmsg.SendMsgReq) (resp *pbmsg.SendMsgResp, error error) {
@@ -37,26 +39,55 @@ func (m *msgServer) SendMsg(ctx context.Context, req *pbmsg.SendMsgReq) (resp *p
 		if !flag {
 			return nil, errs.ErrMessageHasReadDisable.Wrap()
 		}
-		m.encapsulateMsgData(req.MsgData)
-		switch req.MsgData.SessionType {
-		case constant.SingleChatType:
-			return m.sendMsgSingleChat(ctx, req)
-		case constant.NotificationChatType:
-			return m.sendMsgNotification(ctx, req)
-		case constant.SuperGroupChatType:
-			return m.sendMsgSuperGroupChat(ctx, req)
-		default:
-			return nil, errs.ErrArgs.Wrap("unknown sessionType")
+		if parentMsgID := msgprocessor.GetParentMsgID(req.MsgData); parentMsgID != "" && parentMsgID == req.MsgData.ClientMsgID {
+			return nil, errs.ErrArgs.Wrap("a msg can not reply in its own thread")
 		}
+		if err := tenant.CheckIDs(ctx, req.MsgData.SendID, req.MsgData.RecvID, req.MsgData.GroupID); err != nil {
+			return nil, err
+		}
+		if err := msgsize.Check(m.config, req.MsgData); err != nil {
+			return nil, err
+		}
+		if err := m.checkSpam(ctx, req.MsgData); err != nil {
+			return nil, err
+		}
+		if err := m.checkNotificationQuota(ctx, req.MsgData); err != nil {
+			return nil, err
+		}
+		m.encapsulateMsgData(ctx, req.MsgData)
+		if err := m.throttle(ctx, req.MsgData); err != nil {
+			return nil, err
+		}
+		if err := m.moderate(ctx, req.MsgData); err != nil {
+			return nil, err
+		}
+		if m.msgDedupDB != nil && req.MsgData.ClientMsgID != "" && req.MsgData.SessionType != constant.NotificationChatType {
+			return m.sendMsgOnce(ctx, req)
+		}
+		return m.sendMsg(ctx, req)
 	} else {
 		return nil, errs.ErrArgs.Wrap("msgData is nil")
 	}
 }
 
+func (m *msgServer) sendMsg(ctx context.Context, req *pbmsg.SendMsgReq) (*pbmsg.SendMsgResp, error) {
+	switch req.MsgData.SessionType {
+	case constant.SingleChatType:
+		return m.sendMsgSingleChat(ctx, req)
+	case constant.NotificationChatType:
+		return m.sendMsgNotification(ctx, req)
+	case constant.SuperGroupChatType:
+		return m.sendMsgSuperGroupChat(ctx, req)
+	default:
+		return nil, errs.ErrArgs.Wrap("unknown sessionType")
+	}
+}
+
 func (m *msgServer) sendMsgSuperGroupChat(
 	ctx context.Context,
 	req *pbmsg.SendMsgReq,
 ) (resp *pbmsg.SendMsgResp, err error) {
+	m.GroupLocalCache.RecordMsg(ctx, req.MsgData.GroupID)
 	if err = m.messageVerification(ctx, req); err != nil {
 		prommetrics.GroupChatMsgProcessFailedCounter.Inc()
 		return nil, err
@@ -68,15 +99,12 @@ func (m *msgServer) sendMsgSuperGroupChat(
 	if err := callbackMsgModify(ctx, m.config, req); err != nil {
 		return nil, err
 	}
-	err = m.MsgDatabase.MsgToMQ(ctx, utils.GenConversationUniqueKeyForGroup(req.MsgData.GroupID), req.MsgData)
+	held, err := m.deliver(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	if req.MsgData.ContentType == constant.AtText {
-		go m.setConversationAtInfo(ctx, req.MsgData)
-	}
-	if err = callbackAfterSendGroupMsg(ctx, m.config, req); err != nil {
-		log.ZWarn(ctx, "CallbackAfterSendGroupMsg", err)
+	if !held {
+		m.afterDeliver(ctx, req)
 	}
 	prommetrics.GroupChatMsgProcessSuccessCounter.Inc()
 	resp = &pbmsg.SendMsgResp{}
@@ -172,13 +200,13 @@ func (m *msgServer) sendMsgSingleChat(ctx context.Context, req *pbmsg.SendMsgReq
 		if err := callbackMsgModify(ctx, m.config, req); err != nil {
 			return nil, err
 		}
-		if err := m.MsgDatabase.MsgToMQ(ctx, utils.GenConversationUniqueKeyForSingle(req.MsgData.SendID, req.MsgData.RecvID), req.MsgData); err != nil {
+		held, err := m.deliver(ctx, req)
+		if err != nil {
 			prommetrics.SingleChatMsgProcessFailedCounter.Inc()
 			return nil, err
 		}
-		err = callbackAfterSendSingleMsg(ctx, m.config, req)
-		if err != nil {
-			log.ZWarn(ctx, "CallbackAfterSendSingleMsg", err, "req", req)
+		if !held {
+			m.afterDeliver(ctx, req)
 		}
 		resp = &pbmsg.SendMsgResp{
 			ServerMsgID: req.MsgData.ServerMsgID,
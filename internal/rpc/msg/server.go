@@ -15,6 +15,8 @@
 package msg
 
 import (
+	"time"
+
 	"github.com/OpenIMSDK/protocol/constant"
 	"github.com/OpenIMSDK/protocol/conversation"
 	"github.com/OpenIMSDK/protocol/msg"
@@ -22,9 +24,16 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/eventexport"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/idgen"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/search"
+	"github.com/openimsdk/open-im-server/v3/pkg/moderation"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgext"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpccache"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 )
 
@@ -33,6 +42,14 @@ type (
 	msgServer               struct {
 		RegisterCenter         discoveryregistry.SvcDiscoveryRegistry
 		MsgDatabase            controller.CommonMsgDatabase
+		GroupHistoryDatabase   controller.GroupHistoryDatabase
+		GroupRoleDatabase      controller.GroupRoleDatabase
+		UserBlockDatabase      controller.UserBlockDatabase
+		ContentPolicyDatabase  controller.ContentPolicyDatabase
+		LegalHoldDatabase      controller.LegalHoldDatabase
+		MsgReactionDatabase    controller.MsgReactionDatabase
+		MsgEditDatabase        controller.MsgEditDatabase
+		PhysicalDeleteDatabase controller.MsgPhysicalDeleteDatabase
 		Conversation           *rpcclient.ConversationRpcClient
 		UserLocalCache         *rpccache.UserLocalCache
 		FriendLocalCache       *rpccache.FriendLocalCache
@@ -40,7 +57,22 @@ type (
 		ConversationLocalCache *rpccache.ConversationLocalCache
 		Handlers               MessageInterceptorChain
 		notificationSender     *rpcclient.NotificationSender
+		bulkLimiter            *rate.Limiter
+		undoSendDB             controller.UndoSendDatabase
+		msgDedupDB             controller.MsgDedupDatabase
+		moderationDB           controller.ModerationDatabase
+		antiSpamDB             controller.AntiSpamDatabase
+		notificationQuotaDB    controller.NotificationQuotaDatabase
+		reviewerSem            chan struct{}
+		sensitivityDB          controller.ModerationSensitivityDatabase
+		scorers                []moderation.Scorer
+		scorerSem              chan struct{}
+		idGenerator            *idgen.IDGenerator
+		eventExporter          *eventexport.Exporter
 		config                 *config.GlobalConfig
+		// searchBackend is nil when search stays on mongo, s3Database when no object storage is configured.
+		searchBackend search.Backend
+		s3Database    controller.S3Database
 	}
 )
 
@@ -72,28 +104,146 @@ func Start(config *config.GlobalConfig, client discoveryregistry.SvcDiscoveryReg
 		return err
 	}
 	cacheModel := cache.NewMsgCacheModel(rdb, config)
-	msgDocModel := unrelation.NewMsgMongoDriver(mongo.GetDatabase(config.Mongo.Database))
+	msgDocModel := unrelation.NewMsgMongoDriver(mongo.GetDatabase(config.Mongo.Database), mongo.ReadPreference(unrelation.MsgReadController))
 	conversationClient := rpcclient.NewConversationRpcClient(client, config)
 	userRpcClient := rpcclient.NewUserRpcClient(client, config)
 	groupRpcClient := rpcclient.NewGroupRpcClient(client, config)
 	friendRpcClient := rpcclient.NewFriendRpcClient(client, config)
-	msgDatabase, err := controller.NewCommonMsgDatabase(msgDocModel, cacheModel, config)
+	archiveDB, err := mgo.NewArchiveWatermarkMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	msgDatabase, err := controller.NewCommonMsgDatabase(msgDocModel, cacheModel, archiveDB, nil, config)
+	if err != nil {
+		return err
+	}
+	groupHistoryDB, err := mgo.NewGroupHistoryVisibilityMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	groupRoleDB, err := mgo.NewGroupRoleMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	userBlockDB, err := mgo.NewUserBlockMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	contentPolicyDB, err := mgo.NewContentPolicyMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	legalHoldDB, err := mgo.NewLegalHoldMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	msgReactionDB, err := mgo.NewMsgReactionMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	msgEditHistoryDB, err := mgo.NewMsgEditHistoryMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	idGenerator, err := idgen.New(config, client)
+	if err != nil {
+		return err
+	}
+	eventExporter, err := eventexport.NewExporter(config)
+	if err != nil {
+		return err
+	}
+	searchBackend, err := search.NewBackend(config)
 	if err != nil {
 		return err
 	}
 	s := &msgServer{
 		Conversation:           &conversationClient,
 		MsgDatabase:            msgDatabase,
+		GroupHistoryDatabase:   controller.NewGroupHistoryDatabase(groupHistoryDB, msgDocModel, cache.NewGroupHistoryCacheRedis(rdb, groupHistoryDB, msgDocModel, cache.GetDefaultOpt())),
+		GroupRoleDatabase:      controller.NewGroupRoleDatabase(groupRoleDB, cache.NewGroupRoleCacheRedis(rdb, groupRoleDB, cache.GetDefaultOpt())),
+		UserBlockDatabase:      controller.NewUserBlockDatabase(userBlockDB, cache.NewUserBlockCacheRedis(rdb, userBlockDB, cache.GetDefaultOpt())),
+		ContentPolicyDatabase:  controller.NewContentPolicyDatabase(contentPolicyDB, cache.NewContentPolicyCacheRedis(rdb, contentPolicyDB, cache.GetDefaultOpt())),
+		LegalHoldDatabase:      controller.NewLegalHoldDatabase(legalHoldDB),
+		MsgReactionDatabase:    controller.NewMsgReactionDatabase(msgReactionDB, cache.NewMsgReactionCacheRedis(rdb, msgReactionDB, cache.GetDefaultOpt())),
+		MsgEditDatabase:        controller.NewMsgEditDatabase(msgEditHistoryDB),
+		PhysicalDeleteDatabase: controller.NewMsgPhysicalDeleteDatabase(msgDocModel),
 		RegisterCenter:         client,
 		UserLocalCache:         rpccache.NewUserLocalCache(userRpcClient, rdb),
-		GroupLocalCache:        rpccache.NewGroupLocalCache(groupRpcClient, rdb),
+		GroupLocalCache:        rpccache.NewGroupLocalCache(groupRpcClient, rdb, config.HotConversation),
 		ConversationLocalCache: rpccache.NewConversationLocalCache(conversationClient, rdb),
 		FriendLocalCache:       rpccache.NewFriendLocalCache(friendRpcClient, rdb),
+		bulkLimiter:            newBulkLimiter(config),
+		idGenerator:            idGenerator,
+		eventExporter:          eventExporter,
+		searchBackend:          searchBackend,
 		config:                 config,
 	}
+	if config.Object.Enable != "" {
+		s3DB, err := mgo.NewS3Mongo(mongo.GetDatabase(config.Mongo.Database))
+		if err != nil {
+			return err
+		}
+		o, err := controller.NewS3(config, rdb)
+		if err != nil {
+			return err
+		}
+		s.s3Database = controller.NewS3Database(rdb, o, s3DB)
+	}
+	if config.UndoSend.Enable {
+		s.undoSendDB = controller.NewUndoSendDatabase(cache.NewUndoSendCache(rdb))
+		go s.releaseHeldMsgs()
+	}
+	if config.MsgDedup.Enable {
+		s.msgDedupDB = controller.NewMsgDedupDatabase(cache.NewMsgDedupCache(rdb), time.Second*time.Duration(config.MsgDedup.Window))
+	}
+	if config.AntiSpam.Enable {
+		s.antiSpamDB = controller.NewAntiSpamDatabase(cache.NewAntiSpamCache(rdb), antiSpamLimits(config))
+	}
+	if config.NotificationQuota.Enable {
+		notificationAccountDB, err := mgo.NewNotificationAccountMongo(mongo.GetDatabase(config.Mongo.Database))
+		if err != nil {
+			return err
+		}
+		s.notificationQuotaDB = controller.NewNotificationQuotaDatabase(notificationAccountDB, cache.NewNotificationQuotaCache(rdb), notificationQuotaDefaults(config))
+	}
+	if config.Moderation.Enable {
+		moderationDB, err := mgo.NewModerationMongo(mongo.GetDatabase(config.Mongo.Database))
+		if err != nil {
+			return err
+		}
+		reloadInterval := config.Moderation.ReloadInterval
+		if reloadInterval <= 0 {
+			reloadInterval = moderationDefaultReloadInterval
+		}
+		s.moderationDB = controller.NewModerationDatabase(moderationDB, time.Second*time.Duration(reloadInterval))
+		if config.Moderation.Reviewer.Url != "" {
+			concurrency := config.Moderation.Reviewer.Concurrency
+			if concurrency <= 0 {
+				concurrency = moderationDefaultReviewerConcurrency
+			}
+			s.reviewerSem = make(chan struct{}, concurrency)
+		}
+		if config.Moderation.Scoring.Enable {
+			sensitivityDB, err := mgo.NewModerationSensitivityMongo(mongo.GetDatabase(config.Mongo.Database))
+			if err != nil {
+				return err
+			}
+			s.sensitivityDB = controller.NewModerationSensitivityDatabase(sensitivityDB, cache.NewModerationSensitivityCacheRedis(rdb, sensitivityDB, cache.GetDefaultOpt()))
+			if s.scorers, err = newScorers(config); err != nil {
+				return err
+			}
+			concurrency := config.Moderation.Scoring.Concurrency
+			if concurrency <= 0 {
+				concurrency = moderationDefaultReviewerConcurrency
+			}
+			s.scorerSem = make(chan struct{}, concurrency)
+		}
+	}
 	s.notificationSender = rpcclient.NewNotificationSender(config, rpcclient.WithLocalSendMsg(s.SendMsg))
 	s.addInterceptorHandler(MessageHasReadEnabled)
 	msg.RegisterMsgServer(server, s)
+	msgext.Register(server, s)
 	return nil
 }
 
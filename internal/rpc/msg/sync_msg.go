@@ -20,6 +20,7 @@ import (
 	"github.com/OpenIMSDK/protocol/constant"
 	"github.com/OpenIMSDK/protocol/msg"
 	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/utils"
 	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
@@ -40,12 +41,28 @@ func (m *msgServer) PullMessageBySeqs(
 				log.ZError(ctx, "GetConversation error", err, "conversationID", seq.ConversationID)
 				continue
 			}
+			visibleMinSeq, err := m.getVisibleMinSeq(ctx, req.UserID, conversation.GroupID, seq.ConversationID, conversation.ConversationType)
+			if err != nil {
+				log.ZError(ctx, "getVisibleMinSeq error", err, "conversationID", seq.ConversationID)
+				continue
+			}
+			if seq.Begin < visibleMinSeq {
+				seq.Begin = visibleMinSeq
+			}
+			if seq.End < seq.Begin {
+				log.ZDebug(ctx, "seq range is before the visible history", "conversationID", seq.ConversationID, "visibleMinSeq", visibleMinSeq)
+				continue
+			}
+			gapBegin, gapEnd, capped := m.capSyncRange(ctx, req.Order, seq)
 			minSeq, maxSeq, msgs, err := m.MsgDatabase.GetMsgBySeqsRange(ctx, req.UserID, seq.ConversationID,
 				seq.Begin, seq.End, seq.Num, conversation.MaxSeq)
 			if err != nil {
 				log.ZWarn(ctx, "GetMsgBySeqsRange error", err, "conversationID", seq.ConversationID, "seq", seq)
 				continue
 			}
+			if minSeq < visibleMinSeq {
+				minSeq = visibleMinSeq
+			}
 			var isEnd bool
 			switch req.Order {
 			case sdkws.PullOrder_PullOrderAsc:
@@ -57,8 +74,12 @@ func (m *msgServer) PullMessageBySeqs(
 				log.ZWarn(ctx, "not have msgs", nil, "conversationID", seq.ConversationID, "seq", seq)
 				continue
 			}
+			if capped {
+				msgs = append([]*sdkws.MsgData{msgprocessor.NewGapSummaryMsg(seq.ConversationID, gapBegin, gapEnd)}, msgs...)
+			}
 			resp.Msgs[seq.ConversationID] = &sdkws.PullMsgs{Msgs: msgs, IsEnd: isEnd}
 		} else {
+			gapBegin, gapEnd, capped := m.capSyncRange(ctx, req.Order, seq)
 			var seqs []int64
 			for i := seq.Begin; i <= seq.End; i++ {
 				seqs = append(seqs, i)
@@ -81,12 +102,45 @@ func (m *msgServer) PullMessageBySeqs(
 
 				continue
 			}
+			if capped {
+				notificationMsgs = append([]*sdkws.MsgData{msgprocessor.NewGapSummaryMsg(seq.ConversationID, gapBegin, gapEnd)}, notificationMsgs...)
+			}
 			resp.NotificationMsgs[seq.ConversationID] = &sdkws.PullMsgs{Msgs: notificationMsgs, IsEnd: isEnd}
 		}
 	}
 	return resp, nil
 }
 
+// capSyncRange limits a forward pull, the sync of a reconnecting client, to the newest offlineSync.maxSeqs seqs
+// so long-dormant devices do not pull their whole backlog. Backward history pulls are never capped.
+func (m *msgServer) capSyncRange(ctx context.Context, order sdkws.PullOrder, seq *sdkws.SeqRange) (gapBegin, gapEnd int64, capped bool) {
+	if order != sdkws.PullOrder_PullOrderAsc {
+		return 0, 0, false
+	}
+	seq.Begin, gapBegin, gapEnd, capped = msgprocessor.CapSyncRange(seq.Begin, seq.End, m.config.OfflineSync.MaxSeqs)
+	if capped {
+		log.ZInfo(ctx, "offline sync capped", "conversationID", seq.ConversationID, "gapBegin", gapBegin, "gapEnd", gapEnd)
+	}
+	return gapBegin, gapEnd, capped
+}
+
+// getVisibleMinSeq returns the smallest seq userID may pull from a group conversation under the
+// group's history visibility, counted from the member's join time. 0 means no restriction.
+func (m *msgServer) getVisibleMinSeq(ctx context.Context, userID string, groupID string, conversationID string, conversationType int32) (int64, error) {
+	if conversationType != constant.SuperGroupChatType || groupID == "" {
+		return 0, nil
+	}
+	member, err := m.GroupLocalCache.GetGroupMember(ctx, groupID, userID)
+	if err != nil {
+		if errs.ErrRecordNotFound.Is(err) {
+			// Former members keep the history they could read as members, their max seq caps the rest.
+			return m.GroupHistoryDatabase.GetFormerMemberVisibleMinSeq(ctx, groupID, conversationID, userID)
+		}
+		return 0, err
+	}
+	return m.GroupHistoryDatabase.GetVisibleMinSeq(ctx, groupID, conversationID, member.JoinTime)
+}
+
 func (m *msgServer) GetMaxSeq(ctx context.Context, req *sdkws.GetMaxSeqReq) (*sdkws.GetMaxSeqResp, error) {
 	if err := authverify.CheckAccessV3(ctx, req.UserID, m.config); err != nil {
 		return nil, err
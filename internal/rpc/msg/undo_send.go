@@ -0,0 +1,132 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msg
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	pbmsg "github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/eventexport"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+)
+
+const (
+	undoSendScanInterval     = time.Second
+	undoSendDefaultBatchSize = 100
+	// undoSendLease is how long a claimed message waits before another release sends it again, it outlasts
+	// producing a batch.
+	undoSendLease = time.Minute
+)
+
+// deliver hands the message of req to fan-out, or holds it for the undo send window when it is enabled. It
+// reports whether the message was held.
+func (m *msgServer) deliver(ctx context.Context, req *pbmsg.SendMsgReq) (bool, error) {
+	if m.undoSendDB != nil && isUndoable(req.MsgData) {
+		due := time.Now().Add(time.Duration(m.config.UndoSend.Window) * time.Second)
+		return true, m.undoSendDB.Hold(ctx, tenant.GetAppID(ctx), req.MsgData, due)
+	}
+	return false, m.MsgDatabase.MsgToMQ(ctx, conversationUniqueKey(req.MsgData), req.MsgData)
+}
+
+// afterDeliver runs what follows the fan-out of a message, for held messages once they are released.
+func (m *msgServer) afterDeliver(ctx context.Context, req *pbmsg.SendMsgReq) {
+	switch req.MsgData.SessionType {
+	case constant.SingleChatType:
+		if err := callbackAfterSendSingleMsg(ctx, m.config, req); err != nil {
+			log.ZWarn(ctx, "CallbackAfterSendSingleMsg", err, "req", req)
+		}
+	case constant.SuperGroupChatType:
+		if req.MsgData.ContentType == constant.AtText {
+			go m.setConversationAtInfo(ctx, req.MsgData)
+		}
+		if err := callbackAfterSendGroupMsg(ctx, m.config, req); err != nil {
+			log.ZWarn(ctx, "CallbackAfterSendGroupMsg", err)
+		}
+	}
+	m.exportMsgSent(ctx, req.MsgData)
+	m.review(ctx, req.MsgData)
+	m.score(ctx, req.MsgData)
+}
+
+func (m *msgServer) exportMsgSent(ctx context.Context, msg *sdkws.MsgData) {
+	conversationID := msgprocessor.GetConversationIDByMsg(msg)
+	m.eventExporter.Publish(ctx, eventexport.TypeMessageSent, conversationID, &eventexport.MessageSent{
+		ServerMsgID:    msg.ServerMsgID,
+		ClientMsgID:    msg.ClientMsgID,
+		ConversationID: conversationID,
+		SendID:         msg.SendID,
+		RecvID:         msg.RecvID,
+		GroupID:        msg.GroupID,
+		SessionType:    msg.SessionType,
+		ContentType:    msg.ContentType,
+		SendTime:       msg.SendTime,
+	})
+}
+
+// isUndoable reports whether msg is sent by a user, notifications are never held.
+func isUndoable(msg *sdkws.MsgData) bool {
+	if msg.ContentType >= constant.NotificationBegin && msg.ContentType <= constant.NotificationEnd {
+		return false
+	}
+	return msg.SessionType == constant.SingleChatType || msg.SessionType == constant.SuperGroupChatType
+}
+
+func conversationUniqueKey(msg *sdkws.MsgData) string {
+	if msg.SessionType == constant.SuperGroupChatType {
+		return utils.GenConversationUniqueKeyForGroup(msg.GroupID)
+	}
+	return utils.GenConversationUniqueKeyForSingle(msg.SendID, msg.RecvID)
+}
+
+// releaseHeldMsgs sends the held messages whose window is over until the process exits.
+func (m *msgServer) releaseHeldMsgs() {
+	ticker := time.NewTicker(undoSendScanInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		m.releaseDueMsgs()
+	}
+}
+
+func (m *msgServer) releaseDueMsgs() {
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	batchSize := m.config.UndoSend.BatchSize
+	if batchSize <= 0 {
+		batchSize = undoSendDefaultBatchSize
+	}
+	msgs, err := m.undoSendDB.ClaimDue(ctx, int64(batchSize), undoSendLease)
+	if err != nil {
+		log.ZError(ctx, "claim held msgs failed", err)
+		return
+	}
+	for _, held := range msgs {
+		msgCtx := tenant.WithAppID(mcontext.WithOpUserIDContext(ctx, held.Msg.SendID), held.AppID)
+		if err := m.MsgDatabase.MsgToMQ(msgCtx, conversationUniqueKey(held.Msg), held.Msg); err != nil {
+			// The message stays claimed and is released again once its lease ends.
+			log.ZError(msgCtx, "release held msg failed", err, "sendID", held.Msg.SendID, "clientMsgID", held.Msg.ClientMsgID)
+			continue
+		}
+		if err := m.undoSendDB.Released(msgCtx, held.ID); err != nil {
+			log.ZError(msgCtx, "ack released held msg failed", err, "sendID", held.Msg.SendID, "clientMsgID", held.Msg.ClientMsgID)
+		}
+		m.afterDeliver(msgCtx, &pbmsg.SendMsgReq{MsgData: held.Msg})
+	}
+}
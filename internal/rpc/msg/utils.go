@@ -15,10 +15,15 @@
 package msg
 
 import (
+	"context"
+
 	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/msg"
 	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/utils"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/notificationcatalog"
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -49,3 +54,55 @@ func IsNotFound(err error) bool {
 		return false
 	}
 }
+
+// getMsgBySeq pulls the message at seq as userID, which also ensures the user can see the conversation.
+func (m *msgServer) getMsgBySeq(ctx context.Context, userID string, conversationID string, seq int64) (*sdkws.MsgData, error) {
+	resp, err := m.PullMessageBySeqs(ctx, &sdkws.PullMessageBySeqsReq{
+		UserID: userID,
+		SeqRanges: []*sdkws.SeqRange{
+			{ConversationID: conversationID, Begin: seq, End: seq, Num: 1},
+		},
+		Order: sdkws.PullOrder_PullOrderAsc,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if pullMsgs := resp.Msgs[conversationID]; pullMsgs != nil {
+		for _, msgData := range pullMsgs.Msgs {
+			if msgData.Seq == seq && msgData.Status != constant.MsgDeleted {
+				return msgData, nil
+			}
+		}
+	}
+	return nil, errs.ErrRecordNotFound.Wrap("message not found")
+}
+
+// sendTargetMsgNotification sends an online-only notification about the target message to the other side of its conversation.
+func (m *msgServer) sendTargetMsgNotification(ctx context.Context, contentType int32, opUserID string, target *sdkws.MsgData, tips any) error {
+	if err := notificationcatalog.Validate(contentType, tips); err != nil {
+		return err
+	}
+	msgData := &sdkws.MsgData{
+		SendID:      opUserID,
+		GroupID:     target.GroupID,
+		Content:     []byte(utils.StructToJsonString(&sdkws.NotificationElem{Detail: utils.StructToJsonString(tips)})),
+		MsgFrom:     constant.SysMsgType,
+		ContentType: contentType,
+		SessionType: target.SessionType,
+		CreateTime:  utils.GetCurrentTimestampByMill(),
+		ClientMsgID: utils.GetMsgID(opUserID),
+		Options: config.GetOptionsByNotification(config.NotificationConf{
+			IsSendMsg:        false,
+			ReliabilityLevel: 1,
+			UnreadCount:      false,
+		}),
+	}
+	if target.SessionType == constant.SingleChatType {
+		msgData.RecvID = target.RecvID
+		if target.RecvID == opUserID {
+			msgData.RecvID = target.SendID
+		}
+	}
+	_, err := m.SendMsg(ctx, &msg.SendMsgReq{MsgData: msgData})
+	return err
+}
@@ -16,8 +16,6 @@ package msg
 
 import (
 	"context"
-	"math/rand"
-	"strconv"
 	"time"
 
 	"github.com/OpenIMSDK/protocol/constant"
@@ -26,6 +24,9 @@ import (
 	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
 )
 
 var ExcludeContentType = []int{constant.HasReadReceipt}
@@ -48,6 +49,9 @@ type MessageRevoked struct {
 }
 
 func (m *msgServer) messageVerification(ctx context.Context, data *msg.SendMsgReq) error {
+	if err := m.checkContentPolicy(ctx, data.MsgData); err != nil {
+		return err
+	}
 	switch data.MsgData.SessionType {
 	case constant.SingleChatType:
 		if len(m.config.Manager.UserID) > 0 && utils.IsContain(data.MsgData.SendID, m.config.Manager.UserID) {
@@ -67,7 +71,14 @@ func (m *msgServer) messageVerification(ctx context.Context, data *msg.SendMsgRe
 		if black {
 			return errs.ErrBlockedByPeer.Wrap()
 		}
-		if m.config.MessageVerify.FriendVerify != nil && *m.config.MessageVerify.FriendVerify {
+		blocked, err := m.UserBlockDatabase.IsBlocked(ctx, data.MsgData.RecvID, data.MsgData.SendID)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return errs.ErrBlockedByPeer.Wrap()
+		}
+		if friendVerify := tenant.Resolve(ctx, m.config).MessageVerify.FriendVerify; friendVerify != nil && *friendVerify {
 			friend, err := m.FriendLocalCache.IsFriend(ctx, data.MsgData.SendID, data.MsgData.RecvID)
 			if err != nil {
 				return err
@@ -107,6 +118,9 @@ func (m *msgServer) messageVerification(ctx context.Context, data *msg.SendMsgRe
 		if _, ok := memberIDs[data.MsgData.SendID]; !ok {
 			return errs.ErrNotInGroupYet.Wrap()
 		}
+		if err := m.dropBlockedMentions(ctx, data.MsgData); err != nil {
+			return err
+		}
 
 		groupMemberInfo, err := m.GroupLocalCache.GetGroupMember(ctx, data.MsgData.GroupID, data.MsgData.SendID)
 		if err != nil {
@@ -124,6 +138,13 @@ func (m *msgServer) messageVerification(ctx context.Context, data *msg.SendMsgRe
 			if groupInfo.Status == constant.GroupStatusMuted && groupMemberInfo.RoleLevel != constant.GroupAdmin {
 				return errs.ErrMutedGroup.Wrap()
 			}
+			permissions, err := m.GroupRoleDatabase.GetMemberPermissions(ctx, data.MsgData.GroupID, data.MsgData.SendID, groupMemberInfo.RoleLevel)
+			if err != nil {
+				return err
+			}
+			if permissions&relation.GroupPermissionSendMsg == 0 {
+				return errs.ErrNoPermission.Wrap("the group role of the member does not allow sending messages")
+			}
 		}
 		return nil
 	default:
@@ -131,10 +152,48 @@ func (m *msgServer) messageVerification(ctx context.Context, data *msg.SendMsgRe
 	}
 }
 
-func (m *msgServer) encapsulateMsgData(msg *sdkws.MsgData) {
-	msg.ServerMsgID = GetMsgID(msg.SendID)
+// checkContentPolicy rejects a content type the policy of the conversation does not allow, app admins are not
+// restricted.
+func (m *msgServer) checkContentPolicy(ctx context.Context, msgData *sdkws.MsgData) error {
+	if authverify.IsManagerUserID(msgData.SendID, m.config) {
+		return nil
+	}
+	switch msgData.SessionType {
+	case constant.SingleChatType:
+		return m.ContentPolicyDatabase.Check(ctx, msgData.SessionType, "", msgData.ContentType)
+	case constant.SuperGroupChatType:
+		return m.ContentPolicyDatabase.Check(ctx, msgData.SessionType, msgData.GroupID, msgData.ContentType)
+	default:
+		return nil
+	}
+}
+
+// dropBlockedMentions takes the users who blocked the sender off the @ list of a group message, they still
+// receive the message but are not mentioned.
+func (m *msgServer) dropBlockedMentions(ctx context.Context, msgData *sdkws.MsgData) error {
+	if msgData.ContentType != constant.AtText || len(msgData.AtUserIDList) == 0 {
+		return nil
+	}
+	atUserIDs := utils.DifferenceString([]string{constant.AtAllString}, msgData.AtUserIDList)
+	blockers, err := m.UserBlockDatabase.FindBlockers(ctx, atUserIDs, msgData.SendID)
+	if err != nil {
+		return err
+	}
+	if len(blockers) > 0 {
+		msgData.AtUserIDList = utils.DifferenceString(blockers, msgData.AtUserIDList)
+	}
+	return nil
+}
+
+func (m *msgServer) encapsulateMsgData(ctx context.Context, msg *sdkws.MsgData) {
+	msg.ServerMsgID = m.idGenerator.MsgID(ctx, msg.SendID)
 	if msg.SendTime == 0 {
 		msg.SendTime = utils.GetCurrentTimestampByMill()
+	} else if m.config.ClockSkew.Enable {
+		// Server time is authoritative, a sendTime still off after the gateway's skew correction is replaced.
+		if now := utils.GetCurrentTimestampByMill(); msg.SendTime > now+m.config.ClockSkew.Tolerance || msg.SendTime < now-m.config.ClockSkew.Tolerance {
+			msg.SendTime = now
+		}
 	}
 	switch msg.ContentType {
 	case constant.Text:
@@ -180,11 +239,6 @@ func (m *msgServer) encapsulateMsgData(msg *sdkws.MsgData) {
 	}
 }
 
-func GetMsgID(sendID string) string {
-	t := time.Now().Format("2006-01-02 15:04:05")
-	return utils.Md5(t + "-" + sendID + "-" + strconv.Itoa(rand.Int()))
-}
-
 func (m *msgServer) modifyMessageByUserMessageReceiveOpt(
 	ctx context.Context,
 	userID, conversationID string,
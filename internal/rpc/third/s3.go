@@ -160,13 +160,25 @@ func (t *thirdServer) CompleteMultipartUpload(ctx context.Context, req *third.Co
 func (t *thirdServer) AccessURL(ctx context.Context, req *third.AccessURLReq) (*third.AccessURLResp, error) {
 	opt := &s3.AccessURLOption{}
 	if len(req.Query) > 0 {
-		switch req.Query["type"] {
+		typ := req.Query["type"]
+		// The image options imply the image type.
+		if typ == "" && (req.Query["width"] != "" || req.Query["height"] != "" || req.Query["quality"] != "") {
+			typ = "image"
+		}
+		switch typ {
 		case "":
 		case "image":
 			opt.Image = &s3.Image{}
 			opt.Image.Format = req.Query["format"]
 			opt.Image.Width, _ = strconv.Atoi(req.Query["width"])
 			opt.Image.Height, _ = strconv.Atoi(req.Query["height"])
+			if quality := req.Query["quality"]; quality != "" {
+				q, err := strconv.Atoi(quality)
+				if err != nil || q < 1 || q > 100 {
+					return nil, errs.ErrArgs.Wrap("quality must be between 1 and 100")
+				}
+				opt.Image.Quality = q
+			}
 			log.ZDebug(ctx, "AccessURL image", "name", req.Name, "option", opt.Image)
 		default:
 			return nil, errs.ErrArgs.Wrap("invalid query type")
@@ -27,10 +27,6 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
-	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3"
-	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/cos"
-	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/minio"
-	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/oss"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	"google.golang.org/grpc"
@@ -65,18 +61,7 @@ func Start(config *config.GlobalConfig, client discoveryregistry.SvcDiscoveryReg
 		return err
 	}
 	// Select the oss method according to the profile policy
-	enable := config.Object.Enable
-	var o s3.Interface
-	switch enable {
-	case "minio":
-		o, err = minio.NewMinio(cache.NewMinioCache(rdb), minio.Config(config.Object.Minio))
-	case "cos":
-		o, err = cos.NewCos(cos.Config(config.Object.Cos))
-	case "oss":
-		o, err = oss.NewOSS(oss.Config(config.Object.Oss))
-	default:
-		err = fmt.Errorf("invalid object enable: %s", enable)
-	}
+	o, err := controller.NewS3(config, rdb)
 	if err != nil {
 		return err
 	}
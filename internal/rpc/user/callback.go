@@ -131,3 +131,19 @@ func CallbackAfterUserRegister(ctx context.Context, globalConfig *config.GlobalC
 	}
 	return nil
 }
+
+// CallbackSearchUserPrivacy returns the users to hide from a search, the built-in hiddenUserIDs unless the
+// searchUserPrivacy callback replaces them.
+func CallbackSearchUserPrivacy(ctx context.Context, globalConfig *config.GlobalConfig, cbReq *cbapi.CallbackSearchUserPrivacyReq) ([]string, error) {
+	if !globalConfig.Callback.CallbackSearchUserPrivacy.Enable {
+		return cbReq.HiddenUserIDs, nil
+	}
+	cbReq.CallbackCommand = cbapi.CallbackSearchUserPrivacyCommand
+	resp := &cbapi.CallbackSearchUserPrivacyResp{}
+	if err := http.CallBackPostReturn(ctx, globalConfig.Callback.CallbackUrl, cbReq, resp, globalConfig.Callback.CallbackSearchUserPrivacy); err != nil {
+		return cbReq.HiddenUserIDs, err
+	}
+	hiddenUserIDs := cbReq.HiddenUserIDs
+	utils.NotNilReplace(&hiddenUserIDs, resp.HiddenUserIDs)
+	return hiddenUserIDs, nil
+}
@@ -0,0 +1,174 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+
+	pbuser "github.com/OpenIMSDK/protocol/user"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+)
+
+// checkNotificationAccount returns an error unless userID is a notification account.
+func (s *userServer) checkNotificationAccount(ctx context.Context, userID string) error {
+	_, err := s.GetNotificationAccount(ctx, &pbuser.GetNotificationAccountReq{UserID: userID})
+	return err
+}
+
+func (s *userServer) SetNotificationAccountInfo(ctx context.Context, req *apistruct.SetNotificationAccountInfoReq) (*apistruct.SetNotificationAccountInfoResp, error) {
+	if err := authverify.CheckIMAdmin(ctx, s.config); err != nil {
+		return nil, err
+	}
+	if err := s.checkNotificationAccount(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.notificationAccountDB.SetAccount(ctx, req.UserID, req.Verified, req.Category); err != nil {
+		return nil, err
+	}
+	return &apistruct.SetNotificationAccountInfoResp{}, nil
+}
+
+func (s *userServer) GetNotificationAccountsInfo(ctx context.Context, req *apistruct.GetNotificationAccountsInfoReq) (*apistruct.GetNotificationAccountsInfoResp, error) {
+	accounts, err := s.notificationAccountDB.FindAccounts(ctx, req.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apistruct.GetNotificationAccountsInfoResp{Accounts: make([]*apistruct.NotificationAccountInfo, 0, len(accounts))}
+	for _, account := range accounts {
+		resp.Accounts = append(resp.Accounts, &apistruct.NotificationAccountInfo{
+			UserID:   account.UserID,
+			Verified: account.Verified,
+			Category: account.Category,
+		})
+	}
+	return resp, nil
+}
+
+func (s *userServer) SetNotificationQuota(ctx context.Context, req *apistruct.SetNotificationQuotaReq) (*apistruct.SetNotificationQuotaResp, error) {
+	if err := authverify.CheckIMAdmin(ctx, s.config); err != nil {
+		return nil, err
+	}
+	if err := s.checkNotificationAccount(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	if err := s.notificationQuotaDB.SetQuota(ctx, req.UserID, req.Rate, req.DailyCap); err != nil {
+		return nil, err
+	}
+	return &apistruct.SetNotificationQuotaResp{}, nil
+}
+
+// GetNotificationQuota is open to the admins and to the account itself.
+func (s *userServer) GetNotificationQuota(ctx context.Context, req *apistruct.GetNotificationQuotaReq) (*apistruct.GetNotificationQuotaResp, error) {
+	if err := authverify.CheckAccessV3(ctx, req.AccountID, s.config); err != nil {
+		return nil, err
+	}
+	quota, err := s.notificationQuotaDB.GetQuota(ctx, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	used, err := s.notificationQuotaDB.GetDailyUsage(ctx, req.AccountID, req.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+	resp := &apistruct.GetNotificationQuotaResp{Rate: quota.Rate, DailyCap: quota.DailyCap, Used: used}
+	if quota.DailyCap > 0 {
+		resp.Remaining = make(map[string]int64, len(used))
+		for userID, count := range used {
+			remaining := int64(quota.DailyCap) - count
+			if remaining < 0 {
+				remaining = 0
+			}
+			resp.Remaining[userID] = remaining
+		}
+	}
+	return resp, nil
+}
+
+func (s *userServer) SetNotificationOptOut(ctx context.Context, req *apistruct.SetNotificationOptOutReq) (*apistruct.SetNotificationOptOutResp, error) {
+	if err := authverify.CheckAccessV3(ctx, req.UserID, s.config); err != nil {
+		return nil, err
+	}
+	if err := s.notificationAccountDB.SetOptOut(ctx, req.UserID, req.Category, req.OptOut); err != nil {
+		return nil, err
+	}
+	return &apistruct.SetNotificationOptOutResp{}, nil
+}
+
+func (s *userServer) GetNotificationOptOut(ctx context.Context, req *apistruct.GetNotificationOptOutReq) (*apistruct.GetNotificationOptOutResp, error) {
+	if err := authverify.CheckAccessV3(ctx, req.UserID, s.config); err != nil {
+		return nil, err
+	}
+	categories, err := s.notificationAccountDB.GetOptOutCategories(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return &apistruct.GetNotificationOptOutResp{Categories: categories}, nil
+}
+
+func (s *userServer) FollowNotificationAccount(ctx context.Context, req *apistruct.FollowNotificationAccountReq) (*apistruct.FollowNotificationAccountResp, error) {
+	if err := authverify.CheckAccessV3(ctx, req.UserID, s.config); err != nil {
+		return nil, err
+	}
+	if err := s.checkNotificationAccount(ctx, req.AccountID); err != nil {
+		return nil, err
+	}
+	if err := s.notificationAccountDB.Follow(ctx, req.AccountID, req.UserID); err != nil {
+		return nil, err
+	}
+	return &apistruct.FollowNotificationAccountResp{}, nil
+}
+
+func (s *userServer) UnfollowNotificationAccount(ctx context.Context, req *apistruct.FollowNotificationAccountReq) (*apistruct.FollowNotificationAccountResp, error) {
+	if err := authverify.CheckAccessV3(ctx, req.UserID, s.config); err != nil {
+		return nil, err
+	}
+	if err := s.notificationAccountDB.Unfollow(ctx, req.AccountID, req.UserID); err != nil {
+		return nil, err
+	}
+	return &apistruct.FollowNotificationAccountResp{}, nil
+}
+
+// GetNotificationAccountFollowers lists the followers of an account, for the account itself and app managers.
+func (s *userServer) GetNotificationAccountFollowers(ctx context.Context, req *apistruct.GetNotificationAccountFollowersReq) (*apistruct.GetNotificationAccountFollowersResp, error) {
+	if err := authverify.CheckAccessV3(ctx, req.AccountID, s.config); err != nil {
+		return nil, err
+	}
+	total, userIDs, err := s.notificationAccountDB.PageFollowerIDs(ctx, req.AccountID, req.Pagination)
+	if err != nil {
+		return nil, err
+	}
+	return &apistruct.GetNotificationAccountFollowersResp{Total: total, UserIDs: userIDs}, nil
+}
+
+func (s *userServer) GetFollowedNotificationAccounts(ctx context.Context, req *apistruct.GetFollowedNotificationAccountsReq) (*apistruct.GetFollowedNotificationAccountsResp, error) {
+	if err := authverify.CheckAccessV3(ctx, req.UserID, s.config); err != nil {
+		return nil, err
+	}
+	total, accountIDs, err := s.notificationAccountDB.PageFollowingIDs(ctx, req.UserID, req.Pagination)
+	if err != nil {
+		return nil, err
+	}
+	return &apistruct.GetFollowedNotificationAccountsResp{Total: total, AccountIDs: accountIDs}, nil
+}
+
+func (s *userServer) GetNotificationAccountFollowerCounts(ctx context.Context, req *apistruct.GetNotificationAccountFollowerCountsReq) (*apistruct.GetNotificationAccountFollowerCountsResp, error) {
+	counts, err := s.notificationAccountDB.CountFollowers(ctx, utils.Distinct(req.AccountIDs))
+	if err != nil {
+		return nil, err
+	}
+	return &apistruct.GetNotificationAccountFollowerCountsResp{Counts: counts}, nil
+}
@@ -0,0 +1,54 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+
+	pbgroup "github.com/OpenIMSDK/protocol/group"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+)
+
+// joinDefaultGroups adds the registered users to the groups their app joins them to, a group that fails is logged
+// and does not fail the registration.
+func (s *userServer) joinDefaultGroups(ctx context.Context, userIDs []string) {
+	if s.onboardingDatabase == nil {
+		return
+	}
+	groupIDs, err := s.onboardingDatabase.AutoJoinGroupIDs(ctx, tenant.GetAppID(ctx))
+	if err != nil {
+		log.ZWarn(ctx, "find onboarding groups failed", err)
+		return
+	}
+	if len(groupIDs) == 0 {
+		return
+	}
+	// The groups are joined on behalf of an admin, who does not need the approval of the group.
+	if len(s.config.IMAdmin.UserID) > 0 {
+		ctx = mcontext.WithOpUserIDContext(ctx, s.config.IMAdmin.UserID[0])
+	}
+	for _, groupID := range groupIDs {
+		_, err := s.groupRpcClient.Client.InviteUserToGroup(ctx, &pbgroup.InviteUserToGroupReq{
+			GroupID:        groupID,
+			Reason:         "onboarding",
+			InvitedUserIDs: userIDs,
+		})
+		if err != nil {
+			log.ZWarn(ctx, "join onboarding group failed", err, "groupID", groupID, "userIDs", userIDs)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	cbapi "github.com/openimsdk/open-im-server/v3/pkg/callbackstruct"
+	tablerelation "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+// filterSearchPrivacy drops the users who hide themselves from the kind of search the caller made: a user is
+// kept when they are searchable by userID and userID was given, or by nickname and nickName was given. The
+// caller and app managers always see everyone. Deactivated users and users being deleted are dropped for everyone
+// but themselves, whatever the callback answers. total is lowered by the number of users dropped from this page.
+func (s *userServer) filterSearchPrivacy(ctx context.Context, userID, nickName string, total int64, users []*tablerelation.UserModel) (int64, []*tablerelation.UserModel, error) {
+	if len(users) == 0 {
+		return total, users, nil
+	}
+	opUserID := mcontext.GetOpUserID(ctx)
+	userIDs := utils.Slice(users, func(user *tablerelation.UserModel) string { return user.UserID })
+	privacies, err := s.privacyDatabase.FindPrivacy(ctx, userIDs)
+	if err != nil {
+		return 0, nil, err
+	}
+	hiddenUserIDs := make([]string, 0)
+	for _, privacy := range privacies {
+		if privacy.UserID == opUserID {
+			continue
+		}
+		if (userID != "" && !privacy.HideFromID) || (nickName != "" && !privacy.HideFromName) {
+			continue
+		}
+		hiddenUserIDs = append(hiddenUserIDs, privacy.UserID)
+	}
+	hiddenUserIDs, err = CallbackSearchUserPrivacy(ctx, s.config, &cbapi.CallbackSearchUserPrivacyReq{
+		FromUserID:    opUserID,
+		UserID:        userID,
+		NickName:      nickName,
+		UserIDs:       userIDs,
+		HiddenUserIDs: hiddenUserIDs,
+	})
+	if err != nil && err != errs.ErrCallbackContinue {
+		return 0, nil, err
+	}
+	inactiveUserIDs, err := s.accountDatabase.FindInactiveUserIDs(ctx, userIDs)
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, inactiveUserID := range inactiveUserIDs {
+		if inactiveUserID != opUserID && !utils.Contain(inactiveUserID, hiddenUserIDs...) {
+			hiddenUserIDs = append(hiddenUserIDs, inactiveUserID)
+		}
+	}
+	if len(hiddenUserIDs) == 0 {
+		return total, users, nil
+	}
+	hidden := utils.SliceSet(hiddenUserIDs)
+	visible := make([]*tablerelation.UserModel, 0, len(users))
+	for _, user := range users {
+		if _, ok := hidden[user.UserID]; !ok {
+			visible = append(visible, user)
+		}
+	}
+	return total - int64(len(users)-len(visible)), visible, nil
+}
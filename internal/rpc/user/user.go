@@ -17,7 +17,6 @@ package user
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"strings"
 	"time"
 
@@ -28,7 +27,6 @@ import (
 	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/pagination"
-	"github.com/OpenIMSDK/tools/tx"
 	"github.com/OpenIMSDK/tools/utils"
 	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
@@ -36,11 +34,16 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/storage"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
 	tablerelation "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/eventexport"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/idgen"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient/notification"
+	"github.com/openimsdk/open-im-server/v3/pkg/userext"
 	"google.golang.org/grpc"
 )
 
@@ -51,6 +54,13 @@ type userServer struct {
 	friendRpcClient          *rpcclient.FriendRpcClient
 	groupRpcClient           *rpcclient.GroupRpcClient
 	RegisterCenter           registry.SvcDiscoveryRegistry
+	idGenerator              *idgen.IDGenerator
+	privacyDatabase          controller.UserPrivacyDatabase
+	accountDatabase          controller.UserAccountDatabase
+	eventExporter            *eventexport.Exporter
+	onboardingDatabase       controller.OnboardingDatabase
+	notificationAccountDB    controller.NotificationAccountDatabase
+	notificationQuotaDB      controller.NotificationQuotaDatabase
 	config                   *config.GlobalConfig
 }
 
@@ -75,13 +85,40 @@ func Start(config *config.GlobalConfig, client registry.SvcDiscoveryRegistry, se
 	for k, v := range config.IMAdmin.UserID {
 		users = append(users, &tablerelation.UserModel{UserID: v, Nickname: config.IMAdmin.Nickname[k], AppMangerLevel: constant.AppNotificationAdmin})
 	}
-	userDB, err := mgo.NewUserMongo(mongo.GetDatabase(config.Mongo.Database))
+	relationStorage, err := storage.NewRelation(config, mongo)
 	if err != nil {
 		return err
 	}
+	userDB, err := relationStorage.User()
+	if err != nil {
+		return err
+	}
+	notificationAccountDB, err := mgo.NewNotificationAccountMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	notificationQuota := controller.NotificationQuota{Rate: config.NotificationQuota.Rate, DailyCap: config.NotificationQuota.DailyCap}
+	// The user cache shadows the cache package below.
+	notificationQuotaDB := controller.NewNotificationQuotaDatabase(notificationAccountDB, cache.NewNotificationQuotaCache(rdb), notificationQuota)
 	cache := cache.NewUserCacheRedis(rdb, userDB, cache.GetDefaultOpt())
 	userMongoDB := unrelation.NewUserMongoDriver(mongo.GetDatabase(config.Mongo.Database))
-	database := controller.NewUserDatabase(userDB, cache, tx.NewMongo(mongo.GetClient()), userMongoDB)
+	database := controller.NewUserDatabase(userDB, cache, relationStorage.Tx(), userMongoDB)
+	idGenerator, err := idgen.New(config, client)
+	if err != nil {
+		return err
+	}
+	userPrivacyDB, err := mgo.NewUserPrivacyMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	userAccountDB, err := mgo.NewUserAccountMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	eventExporter, err := eventexport.NewExporter(config)
+	if err != nil {
+		return err
+	}
 	friendRpcClient := rpcclient.NewFriendRpcClient(client, config)
 	groupRpcClient := rpcclient.NewGroupRpcClient(client, config)
 	msgRpcClient := rpcclient.NewMessageRpcClient(client, config)
@@ -92,9 +129,23 @@ func Start(config *config.GlobalConfig, client registry.SvcDiscoveryRegistry, se
 		groupRpcClient:           &groupRpcClient,
 		friendNotificationSender: notification.NewFriendNotificationSender(config, &msgRpcClient, notification.WithDBFunc(database.FindWithError)),
 		userNotificationSender:   notification.NewUserNotificationSender(config, &msgRpcClient, notification.WithUserFunc(database.FindWithError)),
+		idGenerator:              idGenerator,
+		privacyDatabase:          controller.NewUserPrivacyDatabase(userPrivacyDB),
+		accountDatabase:          controller.NewUserAccountDatabase(userAccountDB),
+		eventExporter:            eventExporter,
+		notificationAccountDB:    controller.NewNotificationAccountDatabase(notificationAccountDB),
+		notificationQuotaDB:      notificationQuotaDB,
 		config:                   config,
 	}
+	if config.Onboarding.AutoJoin {
+		onboardingDB, err := mgo.NewOnboardingRuleMongo(mongo.GetDatabase(config.Mongo.Database))
+		if err != nil {
+			return err
+		}
+		u.onboardingDatabase = controller.NewOnboardingDatabase(onboardingDB)
+	}
 	pbuser.RegisterUserServer(server, u)
+	userext.Register(server, u)
 	return u.UserDatabase.InitOnce(context.Background(), users)
 }
 
@@ -232,6 +283,12 @@ func (s *userServer) GetPaginationUsers(ctx context.Context, req *pbuser.GetPagi
 		if err != nil {
 			return nil, err
 		}
+		if !authverify.IsAppManagerUid(ctx, s.config) {
+			total, users, err = s.filterSearchPrivacy(ctx, req.UserID, req.NickName, total, users)
+			if err != nil {
+				return nil, err
+			}
+		}
 		return &pbuser.GetPaginationUsersResp{Total: int32(total), Users: convert.UsersDB2Pb(users)}, err
 
 	}
@@ -243,23 +300,34 @@ func (s *userServer) UserRegister(ctx context.Context, req *pbuser.UserRegisterR
 	if len(req.Users) == 0 {
 		return nil, errs.ErrArgs.Wrap("users is empty")
 	}
-	if req.Secret != s.config.Secret {
-		log.ZDebug(ctx, "UserRegister", s.config.Secret, req.Secret)
+	secret, err := tenant.Secret(ctx, s.config)
+	if err != nil {
+		return nil, err
+	}
+	if req.Secret != secret {
+		log.ZDebug(ctx, "UserRegister", secret, req.Secret)
 		return nil, errs.ErrNoPermission.Wrap("secret invalid")
 	}
+	// Users without userID get a generated one, the caller learns it from the afterUserRegister callback.
+	for _, user := range req.Users {
+		if user.UserID == "" {
+			user.UserID = s.idGenerator.UserID(ctx)
+			log.ZInfo(ctx, "UserRegister generated userID", "userID", user.UserID, "nickname", user.Nickname)
+		}
+	}
 	if utils.DuplicateAny(req.Users, func(e *sdkws.UserInfo) string { return e.UserID }) {
 		return nil, errs.ErrArgs.Wrap("userID repeated")
 	}
 	userIDs := make([]string, 0)
 	for _, user := range req.Users {
-		if user.UserID == "" {
-			return nil, errs.ErrArgs.Wrap("userID is empty")
-		}
 		if strings.Contains(user.UserID, ":") {
 			return nil, errs.ErrArgs.Wrap("userID contains ':' is invalid userID")
 		}
 		userIDs = append(userIDs, user.UserID)
 	}
+	if err := tenant.CheckIDs(ctx, userIDs...); err != nil {
+		return nil, err
+	}
 	exist, err := s.IsExist(ctx, userIDs)
 	if err != nil {
 		return nil, err
@@ -287,6 +355,11 @@ func (s *userServer) UserRegister(ctx context.Context, req *pbuser.UserRegisterR
 		return nil, err
 	}
 
+	for _, user := range users {
+		s.eventExporter.Publish(ctx, eventexport.TypeUserRegistered, user.UserID, &eventexport.UserRegistered{UserID: user.UserID, Nickname: user.Nickname})
+	}
+	s.joinDefaultGroups(ctx, userIDs)
+
 	if err := CallbackAfterUserRegister(ctx, s.config, req); err != nil {
 		return nil, err
 	}
@@ -532,7 +605,7 @@ func (s *userServer) AddNotificationAccount(ctx context.Context, req *pbuser.Add
 
 	if req.UserID == "" {
 		for i := 0; i < 20; i++ {
-			userId := s.genUserID()
+			userId := s.idGenerator.UserID(ctx)
 			_, err := s.UserDatabase.FindWithError(ctx, []string{userId})
 			if err == nil {
 				continue
@@ -651,21 +724,6 @@ func (s *userServer) GetNotificationAccount(ctx context.Context, req *pbuser.Get
 	return nil, errs.ErrNoPermission.Wrap("notification messages cannot be sent for this ID")
 }
 
-func (s *userServer) genUserID() string {
-	const l = 10
-	data := make([]byte, l)
-	rand.Read(data)
-	chars := []byte("0123456789")
-	for i := 0; i < len(data); i++ {
-		if i == 0 {
-			data[i] = chars[1:][data[i]%9]
-		} else {
-			data[i] = chars[data[i]%10]
-		}
-	}
-	return string(data)
-}
-
 func (s *userServer) userModelToResp(users []*relation.UserModel, pagination pagination.Pagination) *pbuser.SearchNotificationAccountResp {
 	accounts := make([]*pbuser.NotificationAccountInfo, 0)
 	var total int64
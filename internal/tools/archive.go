@@ -0,0 +1,43 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+)
+
+// AllConversationArchiveMsgs streams the messages of every conversation stored since the last run to the compliance archive.
+func (c *MsgTool) AllConversationArchiveMsgs() {
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	log.ZInfo(ctx, "============================ start archive cron task ============================")
+	conversationIDs, err := c.conversationDatabase.GetAllConversationIDs(ctx)
+	if err != nil {
+		log.ZError(ctx, "GetAllConversationIDs failed", err)
+		return
+	}
+	for _, conversationID := range conversationIDs {
+		conversationIDs = append(conversationIDs, utils.GetNotificationConversationIDByConversationID(conversationID))
+	}
+	var failed int
+	for _, conversationID := range conversationIDs {
+		if err := c.archiveDatabase.ArchiveConversation(ctx, conversationID); err != nil {
+			log.ZError(ctx, "ArchiveConversation failed", err, "conversationID", conversationID)
+			failed++
+		}
+	}
+	log.ZInfo(ctx, "============================ archive cron task finished ============================", "total", len(conversationIDs), "failed", failed)
+}
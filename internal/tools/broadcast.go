@@ -0,0 +1,180 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/mw"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+	kdisc "github.com/openimsdk/open-im-server/v3/pkg/common/discoveryregister"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+)
+
+// BroadcastTool sends the messages queued through /msg/broadcast, one single chat message per user through the msg
+// rpc at the configured rate. A job is leased by one instance at a time and sent page by page, the progress stored
+// after each page lets the next cron run, or another instance after a crash, carry on with the next page. Users of
+// a page interrupted by a crash receive the message again.
+type BroadcastTool struct {
+	database      controller.BroadcastJobDatabase
+	userRpcClient *rpcclient.UserRpcClient
+	msgRpcClient  *rpcclient.MessageRpcClient
+	config        *config.GlobalConfig
+}
+
+func InitBroadcastTool(config *config.GlobalConfig) (*BroadcastTool, error) {
+	mongoClient, err := unrelation.NewMongo(config)
+	if err != nil {
+		return nil, err
+	}
+	jobDB, err := mgo.NewBroadcastJobMongo(mongoClient.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	discov, err := kdisc.NewDiscoveryRegister(config)
+	if err != nil {
+		return nil, err
+	}
+	discov.AddOption(mw.GrpcClient(), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"LoadBalancingPolicy": "%s"}`, "round_robin")))
+	userRpcClient := rpcclient.NewUserRpcClient(discov, config)
+	msgRpcClient := rpcclient.NewMessageRpcClient(discov, config)
+	return &BroadcastTool{
+		database:      controller.NewBroadcastJobDatabase(jobDB),
+		userRpcClient: &userRpcClient,
+		msgRpcClient:  &msgRpcClient,
+		config:        config,
+	}, nil
+}
+
+// RunJobs sends the jobs one after the other for at most maxRunTime seconds.
+func (b *BroadcastTool) RunJobs() {
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	deadline := time.Now().Add(time.Duration(b.config.Broadcast.MaxRunTime) * time.Second)
+	// The page being sent when the deadline passes is sent completely, the lease covers it.
+	leaseUntil := deadline.Add(b.pageDuration() + time.Minute)
+	limiter := rate.NewLimiter(rate.Inf, 1)
+	if b.config.Broadcast.Rate > 0 {
+		limiter = rate.NewLimiter(rate.Limit(b.config.Broadcast.Rate), 1)
+	}
+	for time.Now().Before(deadline) {
+		job, err := b.database.Lease(ctx, leaseUntil)
+		if err != nil {
+			log.ZError(ctx, "Lease broadcast job failed", err)
+			return
+		}
+		if job == nil {
+			return
+		}
+		b.runJob(ctx, job, limiter, deadline)
+	}
+}
+
+func (b *BroadcastTool) pageDuration() time.Duration {
+	if b.config.Broadcast.Rate <= 0 {
+		return 0
+	}
+	return time.Duration(b.config.Broadcast.PageSize) * time.Second / time.Duration(b.config.Broadcast.Rate)
+}
+
+// runJob sends the pages of job until the last one or the deadline, it stops once the job is canceled.
+func (b *BroadcastTool) runJob(ctx context.Context, job *relation.BroadcastJobModel, limiter *rate.Limiter, deadline time.Time) {
+	ctx = tenant.WithAppID(mcontext.WithOpUserIDContext(ctx, job.OperatorUserID), job.AppID)
+	var msgData sdkws.MsgData
+	if err := proto.Unmarshal(job.MsgData, &msgData); err != nil {
+		if err := b.database.Finish(ctx, job.JobID, errs.Wrap(err)); err != nil {
+			log.ZError(ctx, "Finish broadcast job failed", err, "jobID", job.JobID)
+		}
+		return
+	}
+	for time.Now().Before(deadline) {
+		recvIDs, err := b.page(ctx, job, job.Page+1)
+		if err != nil {
+			// Tried again on the next cron run once the lease expires.
+			log.ZError(ctx, "get broadcast page failed", err, "jobID", job.JobID, "page", job.Page+1)
+			return
+		}
+		for _, recvID := range recvIDs {
+			if recvID == msgData.SendID {
+				continue
+			}
+			if err := limiter.Wait(ctx); err != nil {
+				log.ZError(ctx, "broadcast limiter failed", err, "jobID", job.JobID)
+				return
+			}
+			msgData.RecvID = recvID
+			msgData.ClientMsgID = utils.GetMsgID(msgData.SendID)
+			msgData.SendTime = utils.GetCurrentTimestampByMill()
+			if _, err := b.msgRpcClient.SendMsg(ctx, &msg.SendMsgReq{MsgData: &msgData}); err != nil {
+				log.ZWarn(ctx, "send broadcast msg failed", err, "jobID", job.JobID, "recvID", recvID)
+				job.Failed++
+			} else {
+				job.Sent++
+			}
+		}
+		job.Page++
+		running, err := b.database.UpdateProgress(ctx, job)
+		if err != nil {
+			log.ZError(ctx, "UpdateProgress broadcast job failed", err, "jobID", job.JobID)
+			return
+		}
+		if !running {
+			log.ZInfo(ctx, "broadcast job canceled", "jobID", job.JobID, "sent", job.Sent)
+			return
+		}
+		if int32(len(recvIDs)) < job.PageSize {
+			if err := b.database.Finish(ctx, job.JobID, nil); err != nil {
+				log.ZError(ctx, "Finish broadcast job failed", err, "jobID", job.JobID)
+			}
+			return
+		}
+	}
+}
+
+// page returns the recipients of page (from 1) of job, the users in registration order when it has no RecvIDs.
+func (b *BroadcastTool) page(ctx context.Context, job *relation.BroadcastJobModel, page int32) ([]string, error) {
+	if len(job.RecvIDs) > 0 {
+		return broadcastPage(job.RecvIDs, page, job.PageSize), nil
+	}
+	return b.userRpcClient.GetAllUserIDs(ctx, page, job.PageSize)
+}
+
+func broadcastPage(recvIDs []string, page int32, pageSize int32) []string {
+	start := int(page-1) * int(pageSize)
+	if start >= len(recvIDs) {
+		return nil
+	}
+	end := start + int(pageSize)
+	if end > len(recvIDs) {
+		end = len(recvIDs)
+	}
+	return recvIDs[start:end]
+}
@@ -0,0 +1,30 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroadcastPage(t *testing.T) {
+	recvIDs := []string{"u1", "u2", "u3", "u4", "u5"}
+	assert.Equal(t, []string{"u1", "u2"}, broadcastPage(recvIDs, 1, 2))
+	assert.Equal(t, []string{"u3", "u4"}, broadcastPage(recvIDs, 2, 2))
+	// The last page is short, which ends the job.
+	assert.Equal(t, []string{"u5"}, broadcastPage(recvIDs, 3, 2))
+	assert.Empty(t, broadcastPage(recvIDs, 4, 2))
+}
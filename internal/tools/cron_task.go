@@ -58,6 +58,90 @@ func StartTask(config *config.GlobalConfig) error {
 		return errs.Wrap(err, "cron_conversations_destruct_msgs")
 	}
 
+	if config.Archive.Enable {
+		fmt.Printf("Start archive cron task, cron config: %s\n", config.Archive.CronTime)
+		_, err = crontab.AddFunc(config.Archive.CronTime, cronWrapFunc(config, rdb, "cron_archive_msgs", msgTool.AllConversationArchiveMsgs))
+		if err != nil {
+			return errs.Wrap(err, "cron_archive_msgs")
+		}
+	}
+
+	if config.ScheduledMsg.Enable {
+		fmt.Printf("Start scheduled msg cron task, cron config: %s\n", config.ScheduledMsg.CronTime)
+		_, err = crontab.AddFunc(config.ScheduledMsg.CronTime, cronWrapFunc(config, rdb, "cron_send_scheduled_msgs", msgTool.SendDueScheduledMsgs))
+		if err != nil {
+			return errs.Wrap(err, "cron_send_scheduled_msgs")
+		}
+	}
+
+	if config.Meeting.Enable {
+		fmt.Printf("Start meeting room cron task, cron config: %s\n", config.Meeting.CronTime)
+		_, err = crontab.AddFunc(config.Meeting.CronTime, cronWrapFunc(config, rdb, "cron_meeting_rooms", msgTool.AdvanceMeetingRooms))
+		if err != nil {
+			return errs.Wrap(err, "cron_meeting_rooms")
+		}
+	}
+
+	if config.ReceiptCompaction.Enable {
+		fmt.Printf("Start receipt compaction cron task, cron config: %s\n", config.ReceiptCompaction.CronTime)
+		_, err = crontab.AddFunc(config.ReceiptCompaction.CronTime, cronWrapFunc(config, rdb, "cron_compact_receipts", msgTool.AllConversationCompactReceipts))
+		if err != nil {
+			return errs.Wrap(err, "cron_compact_receipts")
+		}
+	}
+
+	if config.UserPurge.Enable {
+		userPurgeTool, err := InitUserPurgeTool(config)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Start user purge cron task, cron config: %s\n", config.UserPurge.CronTime)
+		_, err = crontab.AddFunc(config.UserPurge.CronTime, cronWrapFunc(config, rdb, "cron_purge_users", userPurgeTool.PurgeUsers))
+		if err != nil {
+			return errs.Wrap(err, "cron_purge_users")
+		}
+		_, err = crontab.AddFunc(config.UserPurge.CronTime, cronWrapFunc(config, rdb, "cron_delete_accounts", userPurgeTool.DeleteAccounts))
+		if err != nil {
+			return errs.Wrap(err, "cron_delete_accounts")
+		}
+	}
+
+	if config.GroupBatch.Enable {
+		groupBatchTool, err := InitGroupBatchTool(config)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Start group batch cron task, cron config: %s\n", config.GroupBatch.CronTime)
+		_, err = crontab.AddFunc(config.GroupBatch.CronTime, cronWrapFunc(config, rdb, "cron_group_batch_jobs", groupBatchTool.RunJobs))
+		if err != nil {
+			return errs.Wrap(err, "cron_group_batch_jobs")
+		}
+	}
+
+	if config.Broadcast.Enable {
+		broadcastTool, err := InitBroadcastTool(config)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Start broadcast cron task, cron config: %s\n", config.Broadcast.CronTime)
+		_, err = crontab.AddFunc(config.Broadcast.CronTime, cronWrapFunc(config, rdb, "cron_broadcast_msgs", broadcastTool.RunJobs))
+		if err != nil {
+			return errs.Wrap(err, "cron_broadcast_msgs")
+		}
+	}
+
+	if config.GroupMemberWebhook.Enable {
+		groupMemberWebhookTool, err := InitGroupMemberWebhookTool(config)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Start group member webhook cron task, cron config: %s\n", config.GroupMemberWebhook.CronTime)
+		_, err = crontab.AddFunc(config.GroupMemberWebhook.CronTime, cronWrapFunc(config, rdb, "cron_group_member_webhook", groupMemberWebhookTool.DeliverEvents))
+		if err != nil {
+			return errs.Wrap(err, "cron_group_member_webhook")
+		}
+	}
+
 	// start crontab
 	crontab.Start()
 
@@ -0,0 +1,164 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	pbgroup "github.com/OpenIMSDK/protocol/group"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/protocol/wrapperspb"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/mw"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+	kdisc "github.com/openimsdk/open-im-server/v3/pkg/common/discoveryregister"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// GroupBatchTool carries out the group batch jobs queued through /group/batch, one item after the other. It goes
+// through the group rpc acting as the admin who queued the job, so the groups are notified as for single operations.
+type GroupBatchTool struct {
+	database       controller.GroupBatchJobDatabase
+	groupRpcClient *rpcclient.GroupRpcClient
+	config         *config.GlobalConfig
+}
+
+func InitGroupBatchTool(config *config.GlobalConfig) (*GroupBatchTool, error) {
+	mongoClient, err := unrelation.NewMongo(config)
+	if err != nil {
+		return nil, err
+	}
+	jobDB, err := mgo.NewGroupBatchJobMongo(mongoClient.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	discov, err := kdisc.NewDiscoveryRegister(config)
+	if err != nil {
+		return nil, err
+	}
+	discov.AddOption(mw.GrpcClient(), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"LoadBalancingPolicy": "%s"}`, "round_robin")))
+	groupRpcClient := rpcclient.NewGroupRpcClient(discov, config)
+	return &GroupBatchTool{
+		database:       controller.NewGroupBatchJobDatabase(jobDB),
+		groupRpcClient: &groupRpcClient,
+		config:         config,
+	}, nil
+}
+
+// RunJobs runs the pending jobs claimed by this instance.
+func (g *GroupBatchTool) RunJobs() {
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	jobs, err := g.database.ClaimPending(ctx, int64(g.config.GroupBatch.BatchSize))
+	if err != nil {
+		log.ZError(ctx, "ClaimPending failed", err)
+		return
+	}
+	for _, job := range jobs {
+		g.runJob(ctx, job)
+		if err := g.database.Finish(ctx, job); err != nil {
+			log.ZError(ctx, "Finish group batch job failed", err, "jobID", job.JobID)
+		}
+	}
+}
+
+// runJob carries out every item even if an earlier one failed, the error of each failed item is kept in the job.
+func (g *GroupBatchTool) runJob(ctx context.Context, job *relation.GroupBatchJobModel) {
+	ctx = tenant.WithAppID(mcontext.WithOpUserIDContext(ctx, job.OperatorUserID), job.AppID)
+	for _, item := range job.Items {
+		if err := g.runItem(ctx, job, item); err != nil {
+			log.ZWarn(ctx, "group batch item failed", err, "jobID", job.JobID, "kind", job.Kind, "groupID", item.GroupID)
+			item.Err = err.Error()
+		}
+	}
+}
+
+func (g *GroupBatchTool) runItem(ctx context.Context, job *relation.GroupBatchJobModel, item *relation.GroupBatchItemModel) error {
+	client := g.groupRpcClient.Client
+	switch job.Kind {
+	case relation.GroupBatchCreateGroups:
+		info := &sdkws.GroupInfo{GroupID: item.GroupID, GroupName: item.GroupName, GroupType: constant.WorkingGroup}
+		if s := job.Settings; s != nil {
+			info.Notification, info.Introduction, info.FaceURL = s.Notification, s.Introduction, s.FaceURL
+			if s.Ex != nil {
+				info.Ex = *s.Ex
+			}
+			if s.NeedVerification != nil {
+				info.NeedVerification = *s.NeedVerification
+			}
+			if s.LookMemberInfo != nil {
+				info.LookMemberInfo = *s.LookMemberInfo
+			}
+			if s.ApplyMemberFriend != nil {
+				info.ApplyMemberFriend = *s.ApplyMemberFriend
+			}
+		}
+		resp, err := client.CreateGroup(ctx, &pbgroup.CreateGroupReq{
+			MemberUserIDs: item.MemberUserIDs,
+			GroupInfo:     info,
+			AdminUserIDs:  item.AdminUserIDs,
+			OwnerUserID:   item.OwnerUserID,
+		})
+		if err != nil {
+			return err
+		}
+		item.GroupID = resp.GroupInfo.GroupID
+		return nil
+	case relation.GroupBatchAddMembers:
+		_, err := client.InviteUserToGroup(ctx, &pbgroup.InviteUserToGroupReq{GroupID: item.GroupID, InvitedUserIDs: item.MemberUserIDs})
+		return err
+	case relation.GroupBatchRemoveMembers:
+		_, err := client.KickGroupMember(ctx, &pbgroup.KickGroupMemberReq{GroupID: item.GroupID, KickedUserIDs: item.MemberUserIDs})
+		return err
+	case relation.GroupBatchApplySettings:
+		_, err := client.SetGroupInfo(ctx, &pbgroup.SetGroupInfoReq{GroupInfoForSet: groupBatchInfoForSet(item.GroupID, job.Settings)})
+		return err
+	default:
+		return errs.ErrArgs.Wrap("unknown group batch kind " + job.Kind)
+	}
+}
+
+func groupBatchInfoForSet(groupID string, s *relation.GroupBatchSettingsModel) *sdkws.GroupInfoForSet {
+	set := &sdkws.GroupInfoForSet{
+		GroupID:      groupID,
+		Notification: s.Notification,
+		Introduction: s.Introduction,
+		FaceURL:      s.FaceURL,
+	}
+	if s.Ex != nil {
+		set.Ex = &wrapperspb.StringValue{Value: *s.Ex}
+	}
+	if s.NeedVerification != nil {
+		set.NeedVerification = &wrapperspb.Int32Value{Value: *s.NeedVerification}
+	}
+	if s.LookMemberInfo != nil {
+		set.LookMemberInfo = &wrapperspb.Int32Value{Value: *s.LookMemberInfo}
+	}
+	if s.ApplyMemberFriend != nil {
+		set.ApplyMemberFriend = &wrapperspb.Int32Value{Value: *s.ApplyMemberFriend}
+	}
+	return set
+}
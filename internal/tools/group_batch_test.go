@@ -0,0 +1,35 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+func TestGroupBatchInfoForSet(t *testing.T) {
+	needVerification := int32(2)
+	set := groupBatchInfoForSet("g1", &relation.GroupBatchSettingsModel{Introduction: "class 3", NeedVerification: &needVerification})
+	assert.Equal(t, "g1", set.GroupID)
+	assert.Equal(t, "class 3", set.Introduction)
+	assert.Equal(t, int32(2), set.NeedVerification.GetValue())
+	// Settings left out of the template must not be touched.
+	assert.Nil(t, set.LookMemberInfo)
+	assert.Nil(t, set.ApplyMemberFriend)
+	assert.Nil(t, set.Ex)
+}
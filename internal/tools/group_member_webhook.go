@@ -0,0 +1,120 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"time"
+
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	cbapi "github.com/openimsdk/open-im-server/v3/pkg/callbackstruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/http"
+)
+
+// groupMemberWebhookLease is how long a claimed event waits before another run sends it again, it outlasts the
+// delivery of a batch.
+const groupMemberWebhookLease = time.Minute
+
+// GroupMemberWebhookTool delivers the membership changes the group rpc queued to the group member webhook.
+type GroupMemberWebhookTool struct {
+	database controller.GroupMemberEventDatabase
+	config   *config.GlobalConfig
+}
+
+func InitGroupMemberWebhookTool(config *config.GlobalConfig) (*GroupMemberWebhookTool, error) {
+	mongoClient, err := unrelation.NewMongo(config)
+	if err != nil {
+		return nil, err
+	}
+	eventDB, err := mgo.NewGroupMemberEventMongo(mongoClient.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	return &GroupMemberWebhookTool{
+		database: controller.NewGroupMemberEventDatabase(eventDB, config),
+		config:   config,
+	}, nil
+}
+
+// DeliverEvents posts the due events in one batch, a failed batch is retried with backoff.
+func (g *GroupMemberWebhookTool) DeliverEvents() {
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	conf := g.config.GroupMemberWebhook
+	events, err := g.database.ClaimDue(ctx, int64(conf.BatchSize), groupMemberWebhookLease)
+	if err != nil {
+		log.ZError(ctx, "ClaimDue failed", err)
+		return
+	}
+	if len(events) == 0 {
+		return
+	}
+	err = http.PostWebhook(ctx, conf.Url, conf.Secret, groupMemberWebhookReq(events), conf.Timeout)
+	if err == nil {
+		if err := g.database.Delivered(ctx, utils.Slice(events, func(e *relation.GroupMemberEventModel) string { return e.EventID })); err != nil {
+			log.ZError(ctx, "set group member events delivered failed", err, "count", len(events))
+		}
+		return
+	}
+	log.ZWarn(ctx, "group member webhook failed", err, "count", len(events))
+	for _, event := range events {
+		attempts := int(event.Attempts) + 1
+		failed := attempts >= conf.MaxAttempts
+		nextTime := time.Now().Add(webhookBackoff(attempts, time.Duration(conf.BaseInterval)*time.Second, time.Duration(conf.MaxInterval)*time.Second))
+		if err := g.database.Retry(ctx, event, failed, nextTime, err.Error()); err != nil {
+			log.ZError(ctx, "retry group member event failed", err, "eventID", event.EventID)
+		}
+		if failed {
+			log.ZWarn(ctx, "group member event given up", err, "eventID", event.EventID, "groupID", event.GroupID, "event", event.Event)
+		}
+	}
+}
+
+func groupMemberWebhookReq(events []*relation.GroupMemberEventModel) *cbapi.GroupMemberWebhookReq {
+	req := &cbapi.GroupMemberWebhookReq{Events: make([]*cbapi.GroupMemberWebhookEvent, 0, len(events))}
+	for _, event := range events {
+		req.Events = append(req.Events, &cbapi.GroupMemberWebhookEvent{
+			EventID:        event.EventID,
+			AppID:          event.AppID,
+			GroupID:        event.GroupID,
+			Event:          event.Event,
+			UserIDs:        event.UserIDs,
+			OperatorUserID: event.OperatorUserID,
+			RoleLevel:      event.RoleLevel,
+			EventTime:      event.EventTime.UnixMilli(),
+		})
+	}
+	return req
+}
+
+// webhookBackoff doubles base after each failed attempt, up to max.
+func webhookBackoff(attempts int, base, max time.Duration) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 30 {
+		return max
+	}
+	backoff := base << (attempts - 1)
+	if backoff <= 0 || backoff > max {
+		return max
+	}
+	return backoff
+}
@@ -0,0 +1,48 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, 10*time.Second, webhookBackoff(1, 10*time.Second, time.Minute))
+	assert.Equal(t, 40*time.Second, webhookBackoff(3, 10*time.Second, time.Minute))
+	assert.Equal(t, time.Minute, webhookBackoff(4, 10*time.Second, time.Minute))
+	assert.Equal(t, time.Minute, webhookBackoff(100, 10*time.Second, time.Minute))
+}
+
+func TestGroupMemberWebhookReq(t *testing.T) {
+	eventTime := time.UnixMilli(1700000000000)
+	req := groupMemberWebhookReq([]*relation.GroupMemberEventModel{{
+		EventID:   "e1",
+		GroupID:   "g1",
+		Event:     relation.GroupMemberEventKick,
+		UserIDs:   []string{"u1", "u2"},
+		EventTime: eventTime,
+		Attempts:  3,
+	}})
+	assert.Len(t, req.Events, 1)
+	assert.Equal(t, "e1", req.Events[0].EventID)
+	assert.Equal(t, relation.GroupMemberEventKick, req.Events[0].Event)
+	assert.Equal(t, []string{"u1", "u2"}, req.Events[0].UserIDs)
+	assert.Equal(t, eventTime.UnixMilli(), req.Events[0].EventTime)
+}
@@ -0,0 +1,71 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+)
+
+// AdvanceMeetingRooms sends the starting soon notifications and starts and ends the rooms whose time has come.
+func (c *MsgTool) AdvanceMeetingRooms() {
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	conf := c.Config.Meeting
+	limit := int64(conf.BatchSize)
+	rooms, err := c.meetingRoomDatabase.ClaimStartingSoon(ctx, time.Duration(conf.RemindBefore)*time.Second, limit)
+	if err != nil {
+		log.ZError(ctx, "ClaimStartingSoon failed", err)
+	}
+	for _, room := range rooms {
+		c.notifyMeetingRoom(ctx, msgprocessor.MeetingStartingSoonNotification, room)
+	}
+	rooms, err = c.meetingRoomDatabase.ClaimDueStart(ctx, limit)
+	if err != nil {
+		log.ZError(ctx, "ClaimDueStart failed", err)
+	}
+	for _, room := range rooms {
+		c.notifyMeetingRoom(ctx, msgprocessor.MeetingStartedNotification, room)
+	}
+	rooms, err = c.meetingRoomDatabase.ClaimDueEnd(ctx, limit)
+	if err != nil {
+		log.ZError(ctx, "ClaimDueEnd failed", err)
+	}
+	for _, room := range rooms {
+		c.notifyMeetingRoom(ctx, msgprocessor.MeetingEndedNotification, room)
+	}
+}
+
+func (c *MsgTool) notifyMeetingRoom(ctx context.Context, contentType int32, room *relation.MeetingRoomModel) {
+	elem := &msgprocessor.MeetingRoomElem{
+		RoomID:     room.RoomID,
+		Title:      room.Title,
+		HostUserID: room.HostUserID,
+		StartTime:  room.StartTime.UnixMilli(),
+		EndTime:    room.EndTime.UnixMilli(),
+		Status:     room.Status,
+	}
+	for _, userID := range room.ParticipantIDs {
+		if _, err := c.msgRpcClient.SendMsg(ctx, &msg.SendMsgReq{MsgData: msgprocessor.NewMeetingRoomMsg(contentType, userID, elem)}); err != nil {
+			log.ZWarn(ctx, "send meeting room notification failed", err, "roomID", room.RoomID, "userID", userID, "contentType", contentType)
+		}
+	}
+}
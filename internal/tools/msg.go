@@ -25,14 +25,17 @@ import (
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/mcontext"
 	"github.com/OpenIMSDK/tools/mw"
-	"github.com/OpenIMSDK/tools/tx"
 	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/archive"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/storage"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
 	kdisc "github.com/openimsdk/open-im-server/v3/pkg/common/discoveryregister"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient/notification"
 	"github.com/redis/go-redis/v9"
@@ -45,19 +48,34 @@ type MsgTool struct {
 	conversationDatabase  controller.ConversationDatabase
 	userDatabase          controller.UserDatabase
 	groupDatabase         controller.GroupDatabase
+	archiveDatabase       controller.ArchiveDatabase
+	scheduledMsgDatabase  controller.ScheduledMsgDatabase
+	msgRetentionDatabase  controller.MsgRetentionDatabase
+	meetingRoomDatabase   controller.MeetingRoomDatabase
+	msgReceiptDatabase    controller.MsgReceiptDatabase
+	legalHoldDatabase     controller.LegalHoldDatabase
+	msgRpcClient          *rpcclient.MessageRpcClient
 	msgNotificationSender *notification.MsgNotificationSender
 	Config                *config.GlobalConfig
 }
 
 func NewMsgTool(msgDatabase controller.CommonMsgDatabase, userDatabase controller.UserDatabase,
 	groupDatabase controller.GroupDatabase, conversationDatabase controller.ConversationDatabase,
-	msgNotificationSender *notification.MsgNotificationSender, config *config.GlobalConfig,
+	archiveDatabase controller.ArchiveDatabase, scheduledMsgDatabase controller.ScheduledMsgDatabase, msgRetentionDatabase controller.MsgRetentionDatabase,
+	meetingRoomDatabase controller.MeetingRoomDatabase, msgReceiptDatabase controller.MsgReceiptDatabase, legalHoldDatabase controller.LegalHoldDatabase, msgRpcClient *rpcclient.MessageRpcClient, msgNotificationSender *notification.MsgNotificationSender, config *config.GlobalConfig,
 ) *MsgTool {
 	return &MsgTool{
 		msgDatabase:           msgDatabase,
 		userDatabase:          userDatabase,
 		groupDatabase:         groupDatabase,
 		conversationDatabase:  conversationDatabase,
+		archiveDatabase:       archiveDatabase,
+		scheduledMsgDatabase:  scheduledMsgDatabase,
+		msgRetentionDatabase:  msgRetentionDatabase,
+		meetingRoomDatabase:   meetingRoomDatabase,
+		msgReceiptDatabase:    msgReceiptDatabase,
+		legalHoldDatabase:     legalHoldDatabase,
+		msgRpcClient:          msgRpcClient,
 		msgNotificationSender: msgNotificationSender,
 		Config:                config,
 	}
@@ -77,35 +95,52 @@ func InitMsgTool(config *config.GlobalConfig) (*MsgTool, error) {
 		return nil, err
 	}
 	discov.AddOption(mw.GrpcClient(), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"LoadBalancingPolicy": "%s"}`, "round_robin")))
-	userDB, err := mgo.NewUserMongo(mongo.GetDatabase(config.Mongo.Database))
+	relationStorage, err := storage.NewRelation(config, mongo)
 	if err != nil {
 		return nil, err
 	}
-	msgDatabase, err := controller.InitCommonMsgDatabase(rdb, mongo.GetDatabase(config.Mongo.Database), config)
+	userDB, err := relationStorage.User()
 	if err != nil {
 		return nil, err
 	}
+	archiveDB, err := mgo.NewArchiveWatermarkMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	msgDatabase, err := controller.InitCommonMsgDatabase(rdb, mongo.GetDatabase(config.Mongo.Database), archiveDB, config)
+	if err != nil {
+		return nil, err
+	}
+	var archiveDatabase controller.ArchiveDatabase
+	if config.Archive.Enable {
+		archiver, err := archive.NewHttpArchiver(config)
+		if err != nil {
+			return nil, err
+		}
+		msgDocModel := unrelation.NewMsgMongoDriver(mongo.GetDatabase(config.Mongo.Database), nil)
+		archiveDatabase = controller.NewArchiveDatabase(archiveDB, msgDocModel, archiver, config.Archive.BatchSize)
+	}
 	userMongoDB := unrelation.NewUserMongoDriver(mongo.GetDatabase(config.Mongo.Database))
-	ctxTx := tx.NewMongo(mongo.GetClient())
+	ctxTx := relationStorage.Tx()
 	userDatabase := controller.NewUserDatabase(
 		userDB,
 		cache.NewUserCacheRedis(rdb, userDB, cache.GetDefaultOpt()),
 		ctxTx,
 		userMongoDB,
 	)
-	groupDB, err := mgo.NewGroupMongo(mongo.GetDatabase(config.Mongo.Database))
+	groupDB, err := relationStorage.Group()
 	if err != nil {
 		return nil, err
 	}
-	groupMemberDB, err := mgo.NewGroupMember(mongo.GetDatabase(config.Mongo.Database))
+	groupMemberDB, err := relationStorage.GroupMember()
 	if err != nil {
 		return nil, err
 	}
-	groupRequestDB, err := mgo.NewGroupRequestMgo(mongo.GetDatabase(config.Mongo.Database))
+	groupRequestDB, err := relationStorage.GroupRequest()
 	if err != nil {
 		return nil, err
 	}
-	conversationDB, err := mgo.NewConversationMongo(mongo.GetDatabase(config.Mongo.Database))
+	conversationDB, err := relationStorage.Conversation()
 	if err != nil {
 		return nil, err
 	}
@@ -115,9 +150,36 @@ func InitMsgTool(config *config.GlobalConfig) (*MsgTool, error) {
 		cache.NewConversationRedis(rdb, cache.GetDefaultOpt(), conversationDB),
 		ctxTx,
 	)
+	scheduledMsgDB, err := mgo.NewScheduledMsgMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	msgRetentionDB, err := mgo.NewMsgRetentionMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	meetingRoomDB, err := mgo.NewMeetingRoomMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	legalHoldDB, err := mgo.NewLegalHoldMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return nil, err
+	}
+	var msgReceiptDatabase controller.MsgReceiptDatabase
+	if config.ReceiptCompaction.Enable {
+		msgReceiptSummaryDB, err := mgo.NewMsgReceiptSummaryMongo(mongo.GetDatabase(config.Mongo.Database))
+		if err != nil {
+			return nil, err
+		}
+		msgReceiptDatabase = controller.NewMsgReceiptDatabase(msgReceiptSummaryDB, unrelation.NewMsgMongoDriver(mongo.GetDatabase(config.Mongo.Database), nil),
+			cache.NewMsgCacheModel(rdb, config), config.ReceiptCompaction.BatchSize)
+	}
 	msgRpcClient := rpcclient.NewMessageRpcClient(discov, config)
 	msgNotificationSender := notification.NewMsgNotificationSender(config, rpcclient.WithRpcClient(&msgRpcClient))
-	msgTool := NewMsgTool(msgDatabase, userDatabase, groupDatabase, conversationDatabase, msgNotificationSender, config)
+	msgTool := NewMsgTool(msgDatabase, userDatabase, groupDatabase, conversationDatabase, archiveDatabase,
+		controller.NewScheduledMsgDatabase(scheduledMsgDB), controller.NewMsgRetentionDatabase(msgRetentionDB),
+		controller.NewMeetingRoomDatabase(meetingRoomDB), msgReceiptDatabase, controller.NewLegalHoldDatabase(legalHoldDB), &msgRpcClient, msgNotificationSender, config)
 	return msgTool, nil
 }
 
@@ -139,6 +201,11 @@ func InitMsgTool(config *config.GlobalConfig) (*MsgTool, error) {
 func (c *MsgTool) AllConversationClearMsgAndFixSeq() {
 	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
 	log.ZInfo(ctx, "============================ start del cron task ============================")
+	held, err := c.heldConversationIDs(ctx)
+	if err != nil {
+		log.ZError(ctx, "load legal holds failed, no msg is cleared", err)
+		return
+	}
 	num, err := c.conversationDatabase.GetAllConversationIDsNumber(ctx)
 	if err != nil {
 		log.ZError(ctx, "GetAllConversationIDsNumber failed", err)
@@ -172,15 +239,115 @@ func (c *MsgTool) AllConversationClearMsgAndFixSeq() {
 		if len(conversationIDs) == 0 {
 			continue
 		}
-		c.ClearConversationsMsg(ctx, conversationIDs)
+		c.ClearConversationsMsg(ctx, conversationIDs, held)
 	}
+	c.clearRetentionOverridesMsg(ctx, held)
 	log.ZInfo(ctx, "============================ start del cron finished ============================")
 }
 
-func (c *MsgTool) ClearConversationsMsg(ctx context.Context, conversationIDs []string) {
+// clearRetentionOverridesMsg clears every conversation with a retention override, the random pages above may miss
+// them and overrides are usually shorter than retainChatRecords.
+func (c *MsgTool) clearRetentionOverridesMsg(ctx context.Context, held map[string]struct{}) {
+	const batchNum = 100
+	for pageNumber := int32(1); ; pageNumber++ {
+		_, retentions, err := c.msgRetentionDatabase.PageRetentions(ctx, &sdkws.RequestPagination{PageNumber: pageNumber, ShowNumber: batchNum})
+		if err != nil {
+			log.ZError(ctx, "PageRetentions failed", err, "pageNumber", pageNumber)
+			return
+		}
+		conversationIDs := make([]string, 0, len(retentions)*2)
+		for _, retention := range retentions {
+			conversationIDs = append(conversationIDs, retention.ConversationID)
+			if !msgprocessor.IsNotification(retention.ConversationID) {
+				conversationIDs = append(conversationIDs, msgprocessor.GetNotificationConversationIDByConversationID(retention.ConversationID))
+			}
+		}
+		c.ClearConversationsMsg(ctx, conversationIDs, held)
+		if len(retentions) < batchNum {
+			return
+		}
+	}
+}
+
+// retainDays returns the retention in days of each conversation, -1 if it keeps messages forever.
+// A notification conversation without its own override follows the chat conversation it belongs to.
+func (c *MsgTool) retainDays(ctx context.Context, conversationIDs []string) map[string]int32 {
+	res := make(map[string]int32, len(conversationIDs))
+	lookup := make([]string, 0, len(conversationIDs))
+	for _, conversationID := range conversationIDs {
+		res[conversationID] = int32(c.Config.RetainChatRecords)
+		lookup = append(lookup, retentionKeys(conversationID)...)
+	}
+	retentions, err := c.msgRetentionDatabase.FindRetentions(ctx, lookup)
+	if err != nil {
+		log.ZError(ctx, "FindRetentions failed, use retainChatRecords", err, "conversationIDs", conversationIDs)
+		return res
+	}
+	for _, conversationID := range conversationIDs {
+		for _, key := range retentionKeys(conversationID) {
+			if retention, ok := retentions[key]; ok {
+				res[conversationID] = retention.RetainDays
+				if retention.RetainDays == 0 {
+					res[conversationID] = -1
+				}
+				break
+			}
+		}
+	}
+	return res
+}
+
+// retentionKeys returns the conversations whose override applies to conversationID, in order of precedence.
+func retentionKeys(conversationID string) []string {
+	if !msgprocessor.IsNotification(conversationID) {
+		return []string{conversationID}
+	}
+	return []string{conversationID, "si" + conversationID[1:], "sg" + conversationID[1:]}
+}
+
+// heldConversationIDs returns the conversations on legal hold and the conversations of the users on hold, with
+// their notification conversations.
+func (c *MsgTool) heldConversationIDs(ctx context.Context) (map[string]struct{}, error) {
+	held := make(map[string]struct{})
+	add := func(conversationID string) {
+		held[conversationID] = struct{}{}
+		if !msgprocessor.IsNotification(conversationID) {
+			held[msgprocessor.GetNotificationConversationIDByConversationID(conversationID)] = struct{}{}
+		}
+	}
+	conversationHolds, err := c.legalHoldDatabase.FindAllHolds(ctx, relation.LegalHoldTargetConversation)
+	if err != nil {
+		return nil, err
+	}
+	for _, hold := range conversationHolds {
+		add(hold.TargetID)
+	}
+	userHolds, err := c.legalHoldDatabase.FindAllHolds(ctx, relation.LegalHoldTargetUser)
+	if err != nil {
+		return nil, err
+	}
+	for _, hold := range userHolds {
+		conversationIDs, err := c.conversationDatabase.GetConversationIDs(ctx, hold.TargetID)
+		if err != nil {
+			return nil, err
+		}
+		for _, conversationID := range conversationIDs {
+			add(conversationID)
+		}
+	}
+	return held, nil
+}
+
+// ClearConversationsMsg deletes the messages past the retention of each conversation, the held ones are skipped.
+func (c *MsgTool) ClearConversationsMsg(ctx context.Context, conversationIDs []string, held map[string]struct{}) {
+	retainDays := c.retainDays(ctx, conversationIDs)
 	for _, conversationID := range conversationIDs {
-		if err := c.msgDatabase.DeleteConversationMsgsAndSetMinSeq(ctx, conversationID, int64(c.Config.RetainChatRecords*24*60*60)); err != nil {
-			log.ZError(ctx, "DeleteUserSuperGroupMsgsAndSetMinSeq failed", err, "conversationID", conversationID, "DBRetainChatRecords", c.Config.RetainChatRecords)
+		if _, ok := held[conversationID]; ok {
+			log.ZInfo(ctx, "conversation on legal hold, msgs are kept", "conversationID", conversationID)
+		} else if days := retainDays[conversationID]; days >= 0 {
+			if err := c.msgDatabase.DeleteConversationMsgsAndSetMinSeq(ctx, conversationID, int64(days)*24*60*60); err != nil {
+				log.ZError(ctx, "DeleteUserSuperGroupMsgsAndSetMinSeq failed", err, "conversationID", conversationID, "retainDays", days)
+			}
 		}
 		if err := c.checkMaxSeq(ctx, conversationID); err != nil {
 			log.ZError(ctx, "fixSeq failed", err, "conversationID", conversationID)
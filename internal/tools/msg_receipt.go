@@ -0,0 +1,51 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"time"
+
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+)
+
+// AllConversationCompactReceipts folds the read receipts older than the configured retention into summaries.
+func (c *MsgTool) AllConversationCompactReceipts() {
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	log.ZInfo(ctx, "============================ start receipt compaction cron task ============================")
+	conversationIDs, err := c.conversationDatabase.GetAllConversationIDs(ctx)
+	if err != nil {
+		log.ZError(ctx, "GetAllConversationIDs failed", err)
+		return
+	}
+	// receipts are notifications, they live in the notification conversation of each conversation
+	notificationConversationIDs := make([]string, 0, len(conversationIDs))
+	for _, conversationID := range conversationIDs {
+		notificationConversationIDs = append(notificationConversationIDs, utils.GetNotificationConversationIDByConversationID(conversationID))
+	}
+	notificationConversationIDs = utils.Distinct(notificationConversationIDs)
+	before := time.Now().AddDate(0, 0, -c.Config.ReceiptCompaction.RetainDays)
+	var compacted, failed int
+	for _, conversationID := range notificationConversationIDs {
+		num, err := c.msgReceiptDatabase.CompactReceipts(ctx, conversationID, before)
+		compacted += num
+		if err != nil {
+			log.ZError(ctx, "CompactReceipts failed", err, "conversationID", conversationID)
+			failed++
+		}
+	}
+	log.ZInfo(ctx, "============================ receipt compaction cron task finished ============================", "total", len(notificationConversationIDs), "compacted", compacted, "failed", failed)
+}
@@ -0,0 +1,52 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"google.golang.org/protobuf/proto"
+)
+
+// SendDueScheduledMsgs injects the scheduled messages whose delivery time has come into the normal send pipeline.
+func (c *MsgTool) SendDueScheduledMsgs() {
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	scheduledMsgs, err := c.scheduledMsgDatabase.ClaimDue(ctx, int64(c.Config.ScheduledMsg.BatchSize))
+	if err != nil {
+		log.ZError(ctx, "ClaimDue failed", err)
+		return
+	}
+	for _, scheduledMsg := range scheduledMsgs {
+		var msgData sdkws.MsgData
+		sendErr := proto.Unmarshal(scheduledMsg.MsgData, &msgData)
+		if sendErr == nil {
+			// Delivered at server time, the scheduled time is when it was due.
+			msgData.SendTime = utils.GetCurrentTimestampByMill()
+			_, sendErr = c.msgRpcClient.SendMsg(ctx, &msg.SendMsgReq{MsgData: &msgData})
+		} else {
+			sendErr = errs.Wrap(sendErr)
+		}
+		if sendErr != nil {
+			log.ZError(ctx, "send scheduled msg failed", sendErr, "scheduleID", scheduledMsg.ScheduleID)
+		}
+		if err := c.scheduledMsgDatabase.Finish(ctx, scheduledMsg.ScheduleID, sendErr); err != nil {
+			log.ZError(ctx, "Finish scheduled msg failed", err, "scheduleID", scheduledMsg.ScheduleID)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+)
+
+// SeqGapTool finds and repairs the seqs of a conversation that have no message, from the command line.
+type SeqGapTool struct {
+	database controller.SeqGapDatabase
+}
+
+func InitSeqGapTool(config *config.GlobalConfig) (*SeqGapTool, error) {
+	rdb, err := cache.NewRedis(config)
+	if err != nil {
+		return nil, err
+	}
+	mongo, err := unrelation.NewMongo(config)
+	if err != nil {
+		return nil, err
+	}
+	msgDocModel := unrelation.NewMsgMongoDriver(mongo.GetDatabase(config.Mongo.Database), nil)
+	return &SeqGapTool{
+		database: controller.NewSeqGapDatabase(msgDocModel, cache.NewMsgCacheModel(rdb, config)),
+	}, nil
+}
+
+func (s *SeqGapTool) FindGaps(ctx context.Context, conversationID string) (*controller.SeqGapReport, error) {
+	return s.database.FindGaps(ctx, conversationID)
+}
+
+// Repair repairs the gaps of the conversation with mode and returns the number of repaired seqs.
+func (s *SeqGapTool) Repair(ctx context.Context, conversationID string, mode string) (int64, error) {
+	report, err := s.database.FindGaps(ctx, conversationID)
+	if err != nil {
+		return 0, err
+	}
+	switch mode {
+	case controller.SeqGapRepairPlaceholder:
+		return s.database.FillGaps(ctx, conversationID, report.Gaps)
+	case controller.SeqGapRepairCompact:
+		minSeq, err := s.database.Compact(ctx, conversationID, report)
+		if err != nil {
+			return 0, err
+		}
+		return minSeq - report.MinSeq, nil
+	default:
+		return 0, errs.ErrArgs.Wrap("unknown seq gap repair mode " + mode)
+	}
+}
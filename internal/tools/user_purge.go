@@ -0,0 +1,541 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	pbgroup "github.com/OpenIMSDK/protocol/group"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/mw"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/controller"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/storage"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+	kdisc "github.com/openimsdk/open-im-server/v3/pkg/common/discoveryregister"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Steps of a user purge, in the order they run.
+const (
+	PurgeStepFriends    = "friends"
+	PurgeStepBlacks     = "blacks"
+	PurgeStepGroups     = "groups"
+	PurgeStepPushTokens = "push_tokens"
+	PurgeStepObjects    = "objects"
+	PurgeStepMsgs       = "msgs"
+	PurgeStepUser       = "user"
+)
+
+// Fates of the groups owned by a purged user, set in userPurge.groupSuccession.
+const (
+	GroupSuccessionTransfer = "transfer"
+	GroupSuccessionDismiss  = "dismiss"
+	GroupSuccessionFreeze   = "freeze"
+)
+
+// UserPurgeTool erases the users queued through /user/purge_user and records a report for each of them.
+// It also carries out the account deletions requested through /user/delete_account.
+type UserPurgeTool struct {
+	purgeDatabase   controller.UserPurgeDatabase
+	accountDatabase controller.UserAccountDatabase
+	userDatabase    controller.UserDatabase
+	friendDatabase  controller.FriendDatabase
+	friendRequestDB relation.FriendRequestModelInterface
+	blackDatabase   controller.BlackDatabase
+	groupDatabase   controller.GroupDatabase
+	// groupRpcClient carries out the group succession, so that the group rpc sends the notifications.
+	groupRpcClient *rpcclient.GroupRpcClient
+	// s3Database is nil when no object storage is configured.
+	s3Database        controller.S3Database
+	msgDocModel       unrelationtb.MsgDocModelInterface
+	msgCache          cache.MsgModel
+	legalHoldDatabase controller.LegalHoldDatabase
+	config            *config.GlobalConfig
+}
+
+func InitUserPurgeTool(config *config.GlobalConfig) (*UserPurgeTool, error) {
+	switch config.UserPurge.GroupSuccession {
+	case "":
+		config.UserPurge.GroupSuccession = GroupSuccessionTransfer
+	case GroupSuccessionTransfer, GroupSuccessionDismiss, GroupSuccessionFreeze:
+	default:
+		return nil, errs.ErrArgs.Wrap("invalid userPurge.groupSuccession " + config.UserPurge.GroupSuccession)
+	}
+	if len(config.IMAdmin.UserID) == 0 && len(config.Manager.UserID) == 0 {
+		return nil, errs.ErrArgs.Wrap("user purge needs an imAdmin or manager user to run the group succession")
+	}
+	rdb, err := cache.NewRedis(config)
+	if err != nil {
+		return nil, err
+	}
+	mongoClient, err := unrelation.NewMongo(config)
+	if err != nil {
+		return nil, err
+	}
+	db := mongoClient.GetDatabase(config.Mongo.Database)
+	relationStorage, err := storage.NewRelation(config, mongoClient)
+	if err != nil {
+		return nil, err
+	}
+	ctxTx := relationStorage.Tx()
+	purgeDB, err := mgo.NewUserPurgeMongo(db)
+	if err != nil {
+		return nil, err
+	}
+	accountDB, err := mgo.NewUserAccountMongo(db)
+	if err != nil {
+		return nil, err
+	}
+	userDB, err := relationStorage.User()
+	if err != nil {
+		return nil, err
+	}
+	friendDB, err := relationStorage.Friend()
+	if err != nil {
+		return nil, err
+	}
+	friendRequestDB, err := relationStorage.FriendRequest()
+	if err != nil {
+		return nil, err
+	}
+	blackDB, err := relationStorage.Black()
+	if err != nil {
+		return nil, err
+	}
+	groupDB, err := relationStorage.Group()
+	if err != nil {
+		return nil, err
+	}
+	groupMemberDB, err := relationStorage.GroupMember()
+	if err != nil {
+		return nil, err
+	}
+	groupRequestDB, err := relationStorage.GroupRequest()
+	if err != nil {
+		return nil, err
+	}
+	legalHoldDB, err := mgo.NewLegalHoldMongo(db)
+	if err != nil {
+		return nil, err
+	}
+	s3Database, err := newS3Database(config, rdb, db)
+	if err != nil {
+		return nil, err
+	}
+	discov, err := kdisc.NewDiscoveryRegister(config)
+	if err != nil {
+		return nil, err
+	}
+	discov.AddOption(mw.GrpcClient(), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"LoadBalancingPolicy": "%s"}`, "round_robin")))
+	groupRpcClient := rpcclient.NewGroupRpcClient(discov, config)
+	return &UserPurgeTool{
+		purgeDatabase:   controller.NewUserPurgeDatabase(purgeDB),
+		accountDatabase: controller.NewUserAccountDatabase(accountDB),
+		userDatabase: controller.NewUserDatabase(
+			userDB,
+			cache.NewUserCacheRedis(rdb, userDB, cache.GetDefaultOpt()),
+			ctxTx,
+			unrelation.NewUserMongoDriver(db),
+		),
+		friendDatabase: controller.NewFriendDatabase(
+			friendDB,
+			friendRequestDB,
+			cache.NewFriendCacheRedis(rdb, friendDB, cache.GetDefaultOpt()),
+			ctxTx,
+		),
+		friendRequestDB:   friendRequestDB,
+		blackDatabase:     controller.NewBlackDatabase(blackDB, cache.NewBlackCacheRedis(rdb, blackDB, cache.GetDefaultOpt())),
+		groupDatabase:     controller.NewGroupDatabase(rdb, groupDB, groupMemberDB, groupRequestDB, ctxTx, nil),
+		groupRpcClient:    &groupRpcClient,
+		s3Database:        s3Database,
+		msgDocModel:       unrelation.NewMsgMongoDriver(db, nil),
+		msgCache:          cache.NewMsgCacheModel(rdb, config),
+		legalHoldDatabase: controller.NewLegalHoldDatabase(legalHoldDB),
+		config:            config,
+	}, nil
+}
+
+// newS3Database builds the object storage the third rpc uses, nil if object.enable is empty.
+func newS3Database(config *config.GlobalConfig, rdb redis.UniversalClient, db *mongo.Database) (controller.S3Database, error) {
+	if config.Object.Enable == "" {
+		return nil, nil
+	}
+	s3db, err := mgo.NewS3Mongo(db)
+	if err != nil {
+		return nil, err
+	}
+	o, err := controller.NewS3(config, rdb)
+	if err != nil {
+		return nil, err
+	}
+	return controller.NewS3Database(rdb, o, s3db), nil
+}
+
+// PurgeUsers runs the pending purges claimed by this instance.
+func (u *UserPurgeTool) PurgeUsers() {
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	purges, err := u.purgeDatabase.ClaimPending(ctx, int64(u.config.UserPurge.BatchSize))
+	if err != nil {
+		log.ZError(ctx, "ClaimPending failed", err)
+		return
+	}
+	for _, purge := range purges {
+		steps := u.purgeUser(ctx, purge)
+		if err := u.purgeDatabase.Finish(ctx, purge.PurgeID, steps); err != nil {
+			log.ZError(ctx, "Finish user purge failed", err, "purgeID", purge.PurgeID, "userID", purge.UserID)
+		}
+	}
+}
+
+// DeleteAccounts removes the deleting users from their friends and groups and queues the purge of those whose
+// grace period has passed.
+func (u *UserPurgeTool) DeleteAccounts() {
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	accounts, err := u.accountDatabase.FindDeleting(ctx, int64(u.config.UserPurge.BatchSize))
+	if err != nil {
+		log.ZError(ctx, "FindDeleting failed", err)
+		return
+	}
+	for _, account := range accounts {
+		if !account.Detached {
+			if err := u.detachAccount(ctx, account.UserID); err != nil {
+				log.ZError(ctx, "detach deleting account failed", err, "userID", account.UserID)
+				continue
+			}
+		}
+		if account.DeleteTime.After(time.Now()) {
+			continue
+		}
+		ok, err := u.accountDatabase.SetDeleted(ctx, account.UserID)
+		if err != nil {
+			log.ZError(ctx, "SetDeleted failed", err, "userID", account.UserID)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		purgeID, err := u.purgeDatabase.CreatePurge(ctx, account.UserID, account.OperatorUserID)
+		if err != nil {
+			log.ZError(ctx, "queue purge of deleted account failed", err, "userID", account.UserID)
+		}
+		if err := u.accountDatabase.SetPurgeID(ctx, account.UserID, purgeID); err != nil {
+			log.ZError(ctx, "SetPurgeID failed", err, "userID", account.UserID, "purgeID", purgeID)
+		}
+	}
+}
+
+// detachAccount removes userID from its friends and groups, the groups it owns follow userPurge.groupSuccession.
+func (u *UserPurgeTool) detachAccount(ctx context.Context, userID string) error {
+	if _, err := u.purgeFriends(ctx, userID); err != nil {
+		return err
+	}
+	if _, err := u.purgeGroups(ctx, userID); err != nil {
+		return err
+	}
+	_, err := u.accountDatabase.SetDetached(ctx, userID)
+	return err
+}
+
+// purgeUser runs every step even if an earlier one failed, the report tells which ones failed.
+// The user itself is kept when a step failed so the purge can be queued again for it. The objects and
+// messages of a user on legal hold are kept, the purge fails until the hold is released.
+func (u *UserPurgeTool) purgeUser(ctx context.Context, purge *relation.UserPurgeModel) []*relation.UserPurgeStepModel {
+	userID := purge.UserID
+	var holdErr string
+	if held, err := u.legalHoldDatabase.HeldUserIDs(ctx, []string{userID}); err != nil {
+		holdErr = "skipped, legal hold check failed: " + err.Error()
+	} else if len(held) > 0 {
+		holdErr = "skipped, the user is on legal hold"
+	}
+	steps := []struct {
+		name string
+		fn   func(ctx context.Context) (int64, error)
+	}{
+		{name: PurgeStepFriends, fn: func(ctx context.Context) (int64, error) { return u.purgeFriends(ctx, userID) }},
+		{name: PurgeStepBlacks, fn: func(ctx context.Context) (int64, error) { return u.purgeBlacks(ctx, userID) }},
+		{name: PurgeStepGroups, fn: func(ctx context.Context) (int64, error) { return u.purgeGroups(ctx, userID) }},
+		{name: PurgeStepPushTokens, fn: func(ctx context.Context) (int64, error) { return u.purgePushTokens(ctx, userID) }},
+		{name: PurgeStepObjects, fn: func(ctx context.Context) (int64, error) { return u.purgeObjects(ctx, userID) }},
+		{name: PurgeStepMsgs, fn: func(ctx context.Context) (int64, error) {
+			return u.msgDocModel.AnonymizeUserMsgs(ctx, userID, "deleted_"+purge.PurgeID)
+		}},
+		{name: PurgeStepUser, fn: func(ctx context.Context) (int64, error) {
+			if err := u.userDatabase.Delete(ctx, userID); err != nil {
+				return 0, err
+			}
+			return 1, nil
+		}},
+	}
+	report := make([]*relation.UserPurgeStepModel, 0, len(steps))
+	var failed bool
+	for _, step := range steps {
+		if step.name == PurgeStepUser && failed {
+			report = append(report, &relation.UserPurgeStepModel{Name: step.name, Err: "skipped, an earlier step failed", FinishTime: time.Now()})
+			continue
+		}
+		if holdErr != "" && (step.name == PurgeStepObjects || step.name == PurgeStepMsgs) {
+			report = append(report, &relation.UserPurgeStepModel{Name: step.name, Err: holdErr, FinishTime: time.Now()})
+			failed = true
+			continue
+		}
+		count, err := step.fn(ctx)
+		record := &relation.UserPurgeStepModel{Name: step.name, Count: count, FinishTime: time.Now()}
+		if err != nil {
+			log.ZError(ctx, "user purge step failed", err, "purgeID", purge.PurgeID, "userID", userID, "step", step.name)
+			record.Err = err.Error()
+			failed = true
+		}
+		report = append(report, record)
+	}
+	return report
+}
+
+// purgeFriends removes both directions of every friendship and the friend requests sent or received by userID.
+func (u *UserPurgeTool) purgeFriends(ctx context.Context, userID string) (int64, error) {
+	friendUserIDs, err := u.friendDatabase.FindFriendUserIDs(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	if len(friendUserIDs) > 0 {
+		if err := u.friendDatabase.Delete(ctx, userID, friendUserIDs); err != nil {
+			return 0, err
+		}
+		count += int64(len(friendUserIDs))
+	}
+	page := &sdkws.RequestPagination{PageNumber: 1, ShowNumber: 100}
+	for {
+		_, friends, err := u.friendDatabase.PageInWhoseFriends(ctx, userID, page)
+		if err != nil {
+			return count, err
+		}
+		if len(friends) == 0 {
+			break
+		}
+		for _, friend := range friends {
+			if err := u.friendDatabase.Delete(ctx, friend.OwnerUserID, []string{userID}); err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+	findRequests := []func(ctx context.Context, userID string, pagination pagination.Pagination) (int64, []*relation.FriendRequestModel, error){
+		u.friendRequestDB.FindFromUserID,
+		u.friendRequestDB.FindToUserID,
+	}
+	for _, find := range findRequests {
+		for {
+			_, requests, err := find(ctx, userID, page)
+			if err != nil {
+				return count, err
+			}
+			if len(requests) == 0 {
+				break
+			}
+			for _, request := range requests {
+				if err := u.friendRequestDB.Delete(ctx, request.FromUserID, request.ToUserID); err != nil {
+					return count, err
+				}
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+// purgeBlacks removes the blacklist of userID and its entries in the blacklists of others.
+func (u *UserPurgeTool) purgeBlacks(ctx context.Context, userID string) (int64, error) {
+	blacks, err := u.blackDatabase.FindBlackInfos(ctx, userID, nil)
+	if err != nil {
+		return 0, err
+	}
+	blockedBy, err := u.blackDatabase.FindInWhoseBlacks(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	blacks = append(blacks, blockedBy...)
+	if len(blacks) == 0 {
+		return 0, nil
+	}
+	if err := u.blackDatabase.Delete(ctx, blacks); err != nil {
+		return 0, err
+	}
+	return int64(len(blacks)), nil
+}
+
+// purgeGroups removes userID from every group it joined. The groups it owns follow userPurge.groupSuccession,
+// a frozen group keeps the user as its owner until an app admin steps in.
+func (u *UserPurgeTool) purgeGroups(ctx context.Context, userID string) (int64, error) {
+	var members []*relation.GroupMemberModel
+	for pageNumber := int32(1); ; pageNumber++ {
+		total, page, err := u.groupDatabase.PageGetJoinGroup(ctx, userID, &sdkws.RequestPagination{PageNumber: pageNumber, ShowNumber: 100})
+		if err != nil {
+			return 0, err
+		}
+		members = append(members, page...)
+		if len(page) == 0 || int64(pageNumber)*100 >= total {
+			break
+		}
+	}
+	var count int64
+	for _, member := range members {
+		if member.RoleLevel == constant.GroupOwner {
+			frozen, err := u.succeedGroupOwner(ctx, member.GroupID, userID)
+			if err != nil {
+				return count, err
+			}
+			if frozen {
+				count++
+				continue
+			}
+		}
+		if err := u.groupDatabase.DeleteGroupMember(ctx, member.GroupID, []string{userID}); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// succeedGroupOwner applies the group succession policy to a group owned by ownerUserID through the group rpc,
+// acting as an app admin. It reports whether the group was frozen, the owner is then kept in it.
+func (u *UserPurgeTool) succeedGroupOwner(ctx context.Context, groupID string, ownerUserID string) (bool, error) {
+	group, err := u.groupDatabase.TakeGroup(ctx, groupID)
+	if err != nil {
+		return false, err
+	}
+	if group.Status == constant.GroupStatusDismissed {
+		return false, nil
+	}
+	ctx = mcontext.WithOpUserIDContext(ctx, u.adminUserID())
+	policy := u.config.UserPurge.GroupSuccession
+	if policy == GroupSuccessionTransfer {
+		newOwnerUserID, err := u.groupSuccessor(ctx, groupID, ownerUserID)
+		if err != nil {
+			return false, err
+		}
+		if newOwnerUserID != "" {
+			log.ZInfo(ctx, "transfer group of purged owner", "groupID", groupID, "ownerUserID", ownerUserID, "newOwnerUserID", newOwnerUserID)
+			return false, u.groupRpcClient.TransferGroupOwner(ctx, groupID, ownerUserID, newOwnerUserID)
+		}
+		policy = GroupSuccessionDismiss
+	}
+	switch policy {
+	case GroupSuccessionDismiss:
+		log.ZInfo(ctx, "dismiss group of purged owner", "groupID", groupID, "ownerUserID", ownerUserID)
+		// The members are removed once the dismissal notification is pushed.
+		_, err := u.groupRpcClient.Client.DismissGroup(ctx, &pbgroup.DismissGroupReq{GroupID: groupID})
+		return false, err
+	case GroupSuccessionFreeze:
+		log.ZInfo(ctx, "freeze group of purged owner", "groupID", groupID, "ownerUserID", ownerUserID)
+		if group.Status == constant.GroupStatusMuted {
+			return true, nil
+		}
+		return true, u.groupRpcClient.MuteGroup(ctx, groupID)
+	}
+	return false, nil
+}
+
+// adminUserID is the user the group succession runs as.
+func (u *UserPurgeTool) adminUserID() string {
+	if len(u.config.IMAdmin.UserID) > 0 {
+		return u.config.IMAdmin.UserID[0]
+	}
+	return u.config.Manager.UserID[0]
+}
+
+// groupSuccessor returns the admin who joined first or, without admins, the member who joined first.
+func (u *UserPurgeTool) groupSuccessor(ctx context.Context, groupID string, ownerUserID string) (string, error) {
+	admins, err := u.groupDatabase.FindGroupMemberRoleLevels(ctx, groupID, []int32{constant.GroupAdmin})
+	if err != nil {
+		return "", err
+	}
+	if userID := oldestMember(admins, ownerUserID); userID != "" {
+		return userID, nil
+	}
+	members, err := u.groupDatabase.FindGroupMemberAll(ctx, groupID)
+	if err != nil {
+		return "", err
+	}
+	return oldestMember(members, ownerUserID), nil
+}
+
+// oldestMember returns the member other than excludeUserID who joined first, ties go to the smaller user id.
+func oldestMember(members []*relation.GroupMemberModel, excludeUserID string) string {
+	members = utils.Filter(members, func(m *relation.GroupMemberModel) (*relation.GroupMemberModel, bool) {
+		return m, m.UserID != excludeUserID
+	})
+	if len(members) == 0 {
+		return ""
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].JoinTime.Equal(members[j].JoinTime) {
+			return members[i].UserID < members[j].UserID
+		}
+		return members[i].JoinTime.Before(members[j].JoinTime)
+	})
+	return members[0].UserID
+}
+
+// purgePushTokens removes the offline push tokens and the login tokens of userID on every platform.
+func (u *UserPurgeTool) purgePushTokens(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	for platformID := range constant.PlatformID2Name {
+		if err := u.msgCache.DelFcmToken(ctx, userID, platformID); err != nil {
+			return count, err
+		}
+		tokens, err := u.msgCache.GetTokensWithoutError(ctx, userID, platformID)
+		if err != nil {
+			return count, err
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+		fields := make([]string, 0, len(tokens))
+		for token := range tokens {
+			fields = append(fields, token)
+		}
+		if err := u.msgCache.DeleteTokenByUidPid(ctx, userID, platformID, fields); err != nil {
+			return count, err
+		}
+		count += int64(len(tokens))
+	}
+	return count, nil
+}
+
+func (u *UserPurgeTool) purgeObjects(ctx context.Context, userID string) (int64, error) {
+	if u.s3Database == nil {
+		return 0, nil
+	}
+	return u.s3Database.DeleteUserObjects(ctx, userID)
+}
@@ -0,0 +1,38 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+func TestOldestMember(t *testing.T) {
+	now := time.Now()
+	members := []*relation.GroupMemberModel{
+		{UserID: "owner", JoinTime: now.Add(-3 * time.Hour)},
+		{UserID: "c", JoinTime: now.Add(-time.Hour)},
+		{UserID: "b", JoinTime: now.Add(-2 * time.Hour)},
+		{UserID: "a", JoinTime: now.Add(-2 * time.Hour)},
+	}
+	assert.Equal(t, "a", oldestMember(members, "owner"))
+	assert.Equal(t, "owner", members[0].UserID)
+	assert.Equal(t, "", oldestMember(members[:1], "owner"))
+	assert.Equal(t, "", oldestMember(nil, "owner"))
+}
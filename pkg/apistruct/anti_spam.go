@@ -0,0 +1,48 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// RestrictUserReq throttles UserID to Rate messages per second, or silences it, for Duration seconds. It replaces
+// the current restriction of the user.
+type RestrictUserReq struct {
+	UserID   string `json:"userID"   binding:"required"`
+	Action   string `json:"action"   binding:"required,oneof=throttle silence"`
+	Rate     int    `json:"rate"`
+	Duration int64  `json:"duration" binding:"required"`
+	Reason   string `json:"reason"`
+}
+
+type UnrestrictUserReq struct {
+	UserID string `json:"userID" binding:"required"`
+}
+
+type GetUserRestrictionReq struct {
+	UserID string `json:"userID" binding:"required"`
+}
+
+type UserRestriction struct {
+	UserID         string `json:"userID"`
+	Action         string `json:"action"`
+	Rate           int    `json:"rate"`
+	Reason         string `json:"reason"`
+	OperatorUserID string `json:"operatorUserID"`
+	CreateTime     int64  `json:"createTime"`
+	ExpireTime     int64  `json:"expireTime"`
+}
+
+// GetUserRestrictionResp has no restriction when the user is not restricted.
+type GetUserRestrictionResp struct {
+	Restriction *UserRestriction `json:"restriction"`
+}
@@ -0,0 +1,59 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/OpenIMSDK/protocol/sdkws"
+
+// CreateAppReq registers the app AppID, ManagerUserIDs are admins inside the app only.
+type CreateAppReq struct {
+	AppID          string   `json:"appID"          binding:"required"`
+	Name           string   `json:"name"`
+	ManagerUserIDs []string `json:"managerUserIDs"`
+}
+
+// CreateAppResp returns the secret the app uses to get tokens and register users, it is not returned again.
+type CreateAppResp struct {
+	AppID  string `json:"appID"`
+	Secret string `json:"secret"`
+}
+
+type ResetAppSecretReq struct {
+	AppID string `json:"appID" binding:"required"`
+}
+
+type ResetAppSecretResp struct {
+	Secret string `json:"secret"`
+}
+
+type SetAppManagersReq struct {
+	AppID          string   `json:"appID"          binding:"required"`
+	ManagerUserIDs []string `json:"managerUserIDs"`
+}
+
+type GetAppsReq struct {
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+type App struct {
+	AppID          string   `json:"appID"`
+	Name           string   `json:"name"`
+	ManagerUserIDs []string `json:"managerUserIDs"`
+	CreateTime     int64    `json:"createTime"`
+}
+
+type GetAppsResp struct {
+	Total int64  `json:"total"`
+	Apps  []*App `json:"apps"`
+}
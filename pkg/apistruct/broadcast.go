@@ -0,0 +1,60 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/OpenIMSDK/protocol/sdkws"
+
+// BroadcastMsgReq sends a single chat or notification message to every user, or to RecvIDs when it is set.
+type BroadcastMsgReq struct {
+	SendMsg
+	RecvIDs []string `json:"recvIDs"`
+}
+
+type BroadcastMsgResp struct {
+	JobID string `json:"jobID"`
+}
+
+type GetBroadcastJobReq struct {
+	JobID string `json:"jobID" binding:"required"`
+}
+
+type CancelBroadcastReq struct {
+	JobID string `json:"jobID" binding:"required"`
+}
+
+// BroadcastJob is the progress of a job, Status is 0 pending, 1 running, 2 finished, 3 canceled and 4 failed.
+// Total is the number of users when the job was created.
+type BroadcastJob struct {
+	JobID          string         `json:"jobID"`
+	OperatorUserID string         `json:"operatorUserID"`
+	Status         int32          `json:"status"`
+	Err            string         `json:"err"`
+	Total          int64          `json:"total"`
+	Sent           int64          `json:"sent"`
+	Failed         int64          `json:"failed"`
+	MsgData        *sdkws.MsgData `json:"msgData,omitempty"`
+	CreateTime     int64          `json:"createTime"`
+	UpdateTime     int64          `json:"updateTime"`
+	FinishTime     int64          `json:"finishTime"`
+}
+
+type GetBroadcastJobsReq struct {
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+type GetBroadcastJobsResp struct {
+	Total int64           `json:"total"`
+	Jobs  []*BroadcastJob `json:"jobs"`
+}
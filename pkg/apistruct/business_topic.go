@@ -0,0 +1,37 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// SubscribeBusinessTopicReq subscribes or unsubscribes a user to business notification topics.
+type SubscribeBusinessTopicReq struct {
+	UserID string   `json:"userID" binding:"required"`
+	Topics []string `json:"topics" binding:"required,min=1"`
+}
+
+type GetSubscribedBusinessTopicsReq struct {
+	UserID string `json:"userID" binding:"required"`
+}
+
+type GetSubscribedBusinessTopicsResp struct {
+	Topics []string `json:"topics"`
+}
+
+// PublishBusinessNotificationReq publishes a business notification to every subscriber of a topic.
+type PublishBusinessNotificationReq struct {
+	Topic      string `json:"topic"      binding:"required"`
+	Key        string `json:"key"`
+	Data       string `json:"data"`
+	SendUserID string `json:"sendUserID" binding:"required"`
+}
@@ -0,0 +1,27 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// MsgSizeLimits are the maximum payload sizes in bytes of the content and ex of a message, 0 is no limit.
+// ContentTypes overrides Default for the content types it lists.
+type MsgSizeLimits struct {
+	Default      int           `json:"default"`
+	ContentTypes map[int32]int `json:"contentTypes"`
+}
+
+// ClientConfigResp is the server settings a client enforces before sending.
+type ClientConfigResp struct {
+	MsgSizeLimits *MsgSizeLimits `json:"msgSizeLimits"`
+}
@@ -0,0 +1,58 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/OpenIMSDK/protocol/sdkws"
+
+// SetContentPolicyReq allows only ContentTypes in the conversations of SessionType, GroupID sets the policy of one
+// group and overrides the one of its session type.
+type SetContentPolicyReq struct {
+	SessionType  int32   `json:"sessionType"  binding:"required"`
+	GroupID      string  `json:"groupID"`
+	ContentTypes []int32 `json:"contentTypes" binding:"required"`
+}
+
+type DeleteContentPolicyReq struct {
+	SessionType int32  `json:"sessionType" binding:"required"`
+	GroupID     string `json:"groupID"`
+}
+
+type GetContentPolicyReq struct {
+	SessionType int32  `json:"sessionType" binding:"required"`
+	GroupID     string `json:"groupID"`
+}
+
+// GetContentPolicyResp returns the content types allowed in the conversation, Restricted is false when no policy
+// applies and every content type is allowed.
+type GetContentPolicyResp struct {
+	Restricted   bool    `json:"restricted"`
+	ContentTypes []int32 `json:"contentTypes"`
+}
+
+type GetContentPoliciesReq struct {
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+type ContentPolicy struct {
+	SessionType  int32   `json:"sessionType"`
+	GroupID      string  `json:"groupID"`
+	ContentTypes []int32 `json:"contentTypes"`
+	UpdateTime   int64   `json:"updateTime"`
+}
+
+type GetContentPoliciesResp struct {
+	Total    int64            `json:"total"`
+	Policies []*ContentPolicy `json:"policies"`
+}
@@ -0,0 +1,40 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// MergeConversationDuplicatesReq merges the single chats stored under more than one conversation id, DryRun only
+// reports them. Limit is the number of non canonical records looked at, only those of UserIDs if given.
+type MergeConversationDuplicatesReq struct {
+	UserIDs []string `json:"userIDs"`
+	Limit   int64    `json:"limit"`
+	DryRun  bool     `json:"dryRun"`
+}
+
+type ConversationDuplicate struct {
+	// ConversationID is the canonical id the DuplicateIDs are merged into.
+	ConversationID string           `json:"conversationID"`
+	UserIDs        []string         `json:"userIDs"`
+	DuplicateIDs   []string         `json:"duplicateIDs"`
+	MaxSeqs        map[string]int64 `json:"maxSeqs"`
+	Records        int              `json:"records"`
+	// MovedMsgs is the number of messages moved to ConversationID, Err is set when the merge failed.
+	MovedMsgs int64  `json:"movedMsgs"`
+	Err       string `json:"err,omitempty"`
+}
+
+type MergeConversationDuplicatesResp struct {
+	Duplicates []*ConversationDuplicate `json:"duplicates"`
+	Merged     int                      `json:"merged"`
+}
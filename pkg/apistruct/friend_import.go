@@ -0,0 +1,35 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// ImportFriendsBulkReq imports friends for large migrations, in batches of BatchSize users.
+type ImportFriendsBulkReq struct {
+	OwnerUserID   string   `json:"ownerUserID"   binding:"required"`
+	FriendUserIDs []string `json:"friendUserIDs" binding:"required"`
+	// Bidirectional also adds the owner to each friend's list, it defaults to true like import_friend.
+	Bidirectional *bool `json:"bidirectional"`
+	// DryRun reports the conflicts and the number of friends that would be imported without writing.
+	DryRun    bool `json:"dryRun"`
+	BatchSize int  `json:"batchSize"`
+}
+
+type ImportFriendsBulkResp struct {
+	Total          int      `json:"total"`
+	Imported       int      `json:"imported"`
+	AlreadyFriends []string `json:"alreadyFriends"`
+	// Blocked lists the users in a blacklist relation with the owner, in either direction.
+	Blocked  []string `json:"blocked"`
+	NotExist []string `json:"notExist"`
+}
@@ -0,0 +1,80 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/OpenIMSDK/protocol/sdkws"
+
+type CreateFriendLabelReq struct {
+	OwnerUserID string `json:"ownerUserID" binding:"required"`
+	Name        string `json:"name"        binding:"required"`
+}
+
+type CreateFriendLabelResp struct {
+	LabelID string `json:"labelID"`
+}
+
+type UpdateFriendLabelReq struct {
+	OwnerUserID string `json:"ownerUserID" binding:"required"`
+	LabelID     string `json:"labelID"     binding:"required"`
+	Name        string `json:"name"        binding:"required"`
+}
+
+type DeleteFriendLabelReq struct {
+	OwnerUserID string `json:"ownerUserID" binding:"required"`
+	LabelID     string `json:"labelID"     binding:"required"`
+}
+
+// SetFriendLabelFriendsReq adds friends to a label or, with Remove, takes them off.
+type SetFriendLabelFriendsReq struct {
+	OwnerUserID   string   `json:"ownerUserID"   binding:"required"`
+	LabelID       string   `json:"labelID"       binding:"required"`
+	FriendUserIDs []string `json:"friendUserIDs" binding:"required"`
+	Remove        bool     `json:"remove"`
+}
+
+type GetFriendLabelsReq struct {
+	OwnerUserID string `json:"ownerUserID" binding:"required"`
+}
+
+type FriendLabel struct {
+	LabelID       string   `json:"labelID"`
+	Name          string   `json:"name"`
+	FriendUserIDs []string `json:"friendUserIDs"`
+	CreateTime    int64    `json:"createTime"`
+	UpdateTime    int64    `json:"updateTime"`
+}
+
+type GetFriendLabelsResp struct {
+	Labels []*FriendLabel `json:"labels"`
+}
+
+type GetFriendsByLabelReq struct {
+	OwnerUserID string                   `json:"ownerUserID" binding:"required"`
+	LabelID     string                   `json:"labelID"     binding:"required"`
+	Pagination  *sdkws.RequestPagination `json:"pagination"  binding:"required"`
+}
+
+type GetFriendsByLabelResp struct {
+	Total   int64               `json:"total"`
+	Friends []*sdkws.FriendInfo `json:"friends"`
+}
+
+// FriendLabelChangedTips is the detail of the notification sent to the other devices of the owner of a label,
+// Deleted is set when the label was deleted.
+type FriendLabelChangedTips struct {
+	OwnerUserID string `json:"ownerUserID"`
+	LabelID     string `json:"labelID"`
+	Deleted     bool   `json:"deleted"`
+}
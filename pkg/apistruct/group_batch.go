@@ -0,0 +1,95 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/OpenIMSDK/protocol/sdkws"
+
+// GroupBatchSettings is a settings template, empty fields are left as they are.
+type GroupBatchSettings struct {
+	Notification      string  `json:"notification"`
+	Introduction      string  `json:"introduction"`
+	FaceURL           string  `json:"faceURL"`
+	Ex                *string `json:"ex"`
+	NeedVerification  *int32  `json:"needVerification"`
+	LookMemberInfo    *int32  `json:"lookMemberInfo"`
+	ApplyMemberFriend *int32  `json:"applyMemberFriend"`
+}
+
+// BatchGroupSpec describes a group to create, an empty GroupID is generated.
+type BatchGroupSpec struct {
+	GroupID       string   `json:"groupID"`
+	GroupName     string   `json:"groupName"     binding:"required"`
+	OwnerUserID   string   `json:"ownerUserID"   binding:"required"`
+	AdminUserIDs  []string `json:"adminUserIDs"`
+	MemberUserIDs []string `json:"memberUserIDs"`
+}
+
+// BatchCreateGroupsReq creates the groups, each with the Settings template when it is set.
+type BatchCreateGroupsReq struct {
+	Groups   []*BatchGroupSpec   `json:"groups"   binding:"required"`
+	Settings *GroupBatchSettings `json:"settings"`
+}
+
+type BatchGroupMembers struct {
+	GroupID string   `json:"groupID" binding:"required"`
+	UserIDs []string `json:"userIDs" binding:"required"`
+}
+
+// BatchGroupMembersReq adds or removes members of several groups, depending on the route.
+type BatchGroupMembersReq struct {
+	Groups []*BatchGroupMembers `json:"groups" binding:"required"`
+}
+
+type BatchApplyGroupSettingsReq struct {
+	GroupIDs []string            `json:"groupIDs" binding:"required"`
+	Settings *GroupBatchSettings `json:"settings" binding:"required"`
+}
+
+type GroupBatchJobResp struct {
+	JobID string `json:"jobID"`
+}
+
+type GetGroupBatchJobReq struct {
+	JobID string `json:"jobID" binding:"required"`
+}
+
+type GroupBatchItem struct {
+	GroupID   string `json:"groupID"`
+	GroupName string `json:"groupName"`
+	Err       string `json:"err"`
+}
+
+// GroupBatchJob is the status of a job, Status is 0 pending, 1 running, 2 finished and 3 finished with failed items.
+type GroupBatchJob struct {
+	JobID          string            `json:"jobID"`
+	Kind           string            `json:"kind"`
+	OperatorUserID string            `json:"operatorUserID"`
+	Status         int32             `json:"status"`
+	Total          int64             `json:"total"`
+	Succeeded      int64             `json:"succeeded"`
+	Failed         int64             `json:"failed"`
+	Items          []*GroupBatchItem `json:"items,omitempty"`
+	CreateTime     int64             `json:"createTime"`
+	FinishTime     int64             `json:"finishTime"`
+}
+
+type GetGroupBatchJobsReq struct {
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+type GetGroupBatchJobsResp struct {
+	Total int64            `json:"total"`
+	Jobs  []*GroupBatchJob `json:"jobs"`
+}
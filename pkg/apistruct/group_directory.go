@@ -0,0 +1,54 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/OpenIMSDK/protocol/sdkws"
+
+// SetGroupDiscoverableReq lists the group in the public directory with its tags, or unlists it.
+type SetGroupDiscoverableReq struct {
+	GroupID      string   `json:"groupID"      binding:"required"`
+	Discoverable bool     `json:"discoverable"`
+	Tags         []string `json:"tags"`
+}
+
+type GetGroupDiscoverableReq struct {
+	GroupID string `json:"groupID" binding:"required"`
+}
+
+type GetGroupDiscoverableResp struct {
+	Discoverable bool     `json:"discoverable"`
+	Tags         []string `json:"tags"`
+}
+
+// SearchPublicGroupsReq searches the names and tags of the listed groups, an empty Keyword browses the directory.
+type SearchPublicGroupsReq struct {
+	Keyword    string                   `json:"keyword"`
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+type PublicGroup struct {
+	GroupID          string   `json:"groupID"`
+	GroupName        string   `json:"groupName"`
+	FaceURL          string   `json:"faceURL"`
+	Introduction     string   `json:"introduction"`
+	Tags             []string `json:"tags"`
+	MemberCount      uint32   `json:"memberCount"`
+	NeedVerification int32    `json:"needVerification"`
+}
+
+type SearchPublicGroupsResp struct {
+	Total  int64          `json:"total"`
+	Groups []*PublicGroup `json:"groups"`
+}
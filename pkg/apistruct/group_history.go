@@ -0,0 +1,33 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// SetGroupHistoryVisibilityReq sets how much history new members of the group can read.
+// Visibility is 0 for all history, 1 for none before joining and 2 for the Days days before joining.
+type SetGroupHistoryVisibilityReq struct {
+	GroupID    string `json:"groupID"    binding:"required"`
+	Visibility int32  `json:"visibility"`
+	Days       int32  `json:"days"`
+}
+
+type GetGroupHistoryVisibilityReq struct {
+	GroupID string `json:"groupID" binding:"required"`
+}
+
+type GetGroupHistoryVisibilityResp struct {
+	GroupID    string `json:"groupID"`
+	Visibility int32  `json:"visibility"`
+	Days       int32  `json:"days"`
+}
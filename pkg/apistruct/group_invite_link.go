@@ -0,0 +1,62 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// CreateGroupInviteLinkReq creates an invite link, ExpireTime is a unix ms time and 0 with MaxUses 0 means no limit.
+type CreateGroupInviteLinkReq struct {
+	GroupID      string `json:"groupID"      binding:"required"`
+	ExpireTime   int64  `json:"expireTime"`
+	MaxUses      int32  `json:"maxUses"`
+	NeedApproval bool   `json:"needApproval"`
+}
+
+type GroupInviteLink struct {
+	Token         string `json:"token"`
+	GroupID       string `json:"groupID"`
+	CreatorUserID string `json:"creatorUserID"`
+	ExpireTime    int64  `json:"expireTime"`
+	MaxUses       int32  `json:"maxUses"`
+	Uses          int32  `json:"uses"`
+	NeedApproval  bool   `json:"needApproval"`
+	CreateTime    int64  `json:"createTime"`
+}
+
+type CreateGroupInviteLinkResp struct {
+	Link *GroupInviteLink `json:"link"`
+}
+
+type RevokeGroupInviteLinkReq struct {
+	GroupID string `json:"groupID" binding:"required"`
+	Token   string `json:"token"   binding:"required"`
+}
+
+type GetGroupInviteLinksReq struct {
+	GroupID string `json:"groupID" binding:"required"`
+}
+
+type GetGroupInviteLinksResp struct {
+	Links []*GroupInviteLink `json:"links"`
+}
+
+type JoinGroupByLinkReq struct {
+	Token      string `json:"token"      binding:"required"`
+	ReqMessage string `json:"reqMessage"`
+}
+
+// JoinGroupByLinkResp tells whether the user joined or is waiting for the approval of the group admins.
+type JoinGroupByLinkResp struct {
+	GroupID      string `json:"groupID"`
+	NeedApproval bool   `json:"needApproval"`
+}
@@ -0,0 +1,27 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// GetGroupReadMembersReq asks which members of the group have read the message at Seq.
+type GetGroupReadMembersReq struct {
+	GroupID string `json:"groupID" binding:"required"`
+	Seq     int64  `json:"seq"     binding:"required,gt=0"`
+}
+
+// GetGroupReadMembersResp splits the current group members by whether their has read seq reached Seq.
+type GetGroupReadMembersResp struct {
+	ReadUserIDs   []string `json:"readUserIDs"`
+	UnreadUserIDs []string `json:"unreadUserIDs"`
+}
@@ -0,0 +1,57 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// SetGroupRoleReq creates a custom role of the group or updates it. Permissions is a bitmask: 1 invite, 2 kick,
+// 4 mute, 8 pin, 16 edit group info, 32 send messages.
+type SetGroupRoleReq struct {
+	GroupID     string `json:"groupID"     binding:"required"`
+	RoleID      string `json:"roleID"      binding:"required"`
+	Name        string `json:"name"`
+	Permissions int64  `json:"permissions"`
+}
+
+type DeleteGroupRoleReq struct {
+	GroupID string `json:"groupID" binding:"required"`
+	RoleID  string `json:"roleID"  binding:"required"`
+}
+
+type GetGroupRolesReq struct {
+	GroupID string `json:"groupID" binding:"required"`
+}
+
+type GroupRole struct {
+	RoleID      string `json:"roleID"`
+	Name        string `json:"name"`
+	Permissions int64  `json:"permissions"`
+	CreateTime  int64  `json:"createTime"`
+}
+
+type GroupMemberRole struct {
+	UserID string `json:"userID"`
+	RoleID string `json:"roleID"`
+}
+
+type GetGroupRolesResp struct {
+	Roles   []*GroupRole       `json:"roles"`
+	Members []*GroupMemberRole `json:"members"`
+}
+
+// SetGroupMemberRoleReq gives the role to the members, an empty RoleID takes their custom role away.
+type SetGroupMemberRoleReq struct {
+	GroupID string   `json:"groupID" binding:"required"`
+	RoleID  string   `json:"roleID"`
+	UserIDs []string `json:"userIDs" binding:"required"`
+}
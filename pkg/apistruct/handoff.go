@@ -0,0 +1,55 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// CreateHandoffLinkReq opens ConversationID of the op user, at the message Seq or ClientMsgID when set.
+type CreateHandoffLinkReq struct {
+	ConversationID string `json:"conversationID" binding:"required"`
+	Seq            int64  `json:"seq"`
+	ClientMsgID    string `json:"clientMsgID"`
+}
+
+type CreateHandoffLinkResp struct {
+	Token      string `json:"token"`
+	ExpireTime int64  `json:"expireTime"`
+}
+
+type RedeemHandoffLinkReq struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type RedeemHandoffLinkResp struct {
+	ConversationID string `json:"conversationID"`
+	Seq            int64  `json:"seq"`
+	ClientMsgID    string `json:"clientMsgID"`
+}
+
+type GetHandoffAuditReq struct {
+	UserID string `json:"userID" binding:"required"`
+}
+
+type HandoffAuditRecord struct {
+	TokenID        string `json:"tokenID"`
+	Action         string `json:"action"`
+	Result         string `json:"result"`
+	ConversationID string `json:"conversationID"`
+	Platform       string `json:"platform"`
+	IP             string `json:"ip"`
+	Time           int64  `json:"time"`
+}
+
+type GetHandoffAuditResp struct {
+	Records []*HandoffAuditRecord `json:"records"`
+}
@@ -0,0 +1,70 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/OpenIMSDK/protocol/sdkws"
+
+// PlaceLegalHoldReq keeps the messages and objects of a user, or the messages of a conversation, from being deleted
+// until the hold is released. TargetType is user or conversation.
+type PlaceLegalHoldReq struct {
+	TargetType string `json:"targetType" binding:"required,oneof=user conversation"`
+	TargetID   string `json:"targetID"   binding:"required"`
+	Reason     string `json:"reason"`
+}
+
+type ReleaseLegalHoldReq struct {
+	TargetType string `json:"targetType" binding:"required,oneof=user conversation"`
+	TargetID   string `json:"targetID"   binding:"required"`
+}
+
+// GetLegalHoldsReq returns the holds of TargetType, every hold when it is empty.
+type GetLegalHoldsReq struct {
+	TargetType string                   `json:"targetType"`
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+type LegalHold struct {
+	TargetType     string `json:"targetType"`
+	TargetID       string `json:"targetID"`
+	Reason         string `json:"reason"`
+	OperatorUserID string `json:"operatorUserID"`
+	CreateTime     int64  `json:"createTime"`
+}
+
+type GetLegalHoldsResp struct {
+	Total int64        `json:"total"`
+	Holds []*LegalHold `json:"holds"`
+}
+
+// GetLegalHoldLogsReq returns the log of one target newest first, the whole log when TargetID is empty.
+type GetLegalHoldLogsReq struct {
+	TargetType string                   `json:"targetType"`
+	TargetID   string                   `json:"targetID"`
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+type LegalHoldLog struct {
+	TargetType     string `json:"targetType"`
+	TargetID       string `json:"targetID"`
+	Action         string `json:"action"`
+	Reason         string `json:"reason"`
+	OperatorUserID string `json:"operatorUserID"`
+	CreateTime     int64  `json:"createTime"`
+}
+
+type GetLegalHoldLogsResp struct {
+	Total int64           `json:"total"`
+	Logs  []*LegalHoldLog `json:"logs"`
+}
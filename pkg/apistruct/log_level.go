@@ -0,0 +1,27 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// SetLogLevelReq publishes the log level of the services in ServiceNames, e.g. "msg" or "push", or of every
+// service when it is empty. Level ranges from 0 (panic) to 6 (debug) and applies when a service starts.
+type SetLogLevelReq struct {
+	Level        int      `json:"level"`
+	ServiceNames []string `json:"serviceNames"`
+}
+
+type GetLogLevelResp struct {
+	Level     int            `json:"level"`
+	Published map[string]int `json:"published"`
+}
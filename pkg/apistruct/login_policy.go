@@ -0,0 +1,32 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// LoginPolicyGroup limits the sessions a user keeps online across the listed platforms, MaxOnline 0 means unlimited.
+type LoginPolicyGroup struct {
+	Name        string `json:"name"        binding:"required"`
+	PlatformIDs []int  `json:"platformIDs" binding:"required,min=1"`
+	MaxOnline   int    `json:"maxOnline"`
+}
+
+type SetLoginPolicyMatrixReq struct {
+	Enable bool                `json:"enable"`
+	Groups []*LoginPolicyGroup `json:"groups" binding:"dive"`
+}
+
+type GetLoginPolicyMatrixResp struct {
+	Enable bool                `json:"enable"`
+	Groups []*LoginPolicyGroup `json:"groups"`
+}
@@ -55,6 +55,12 @@ type SendMsg struct {
 
 	// OfflinePushInfo contains information for offline push notifications.
 	OfflinePushInfo *sdkws.OfflinePushInfo `json:"offlinePushInfo"`
+
+	// ParentMsgID is the clientMsgID of the thread root when the message is a thread reply.
+	ParentMsgID string `json:"parentMsgID"`
+
+	// Priority is 0 for normal, 1 for high (calls, one time passwords) and 2 for bulk messages.
+	Priority int32 `json:"priority" binding:"min=0,max=2"`
 }
 
 // SendMsgReq extends SendMsg with the requirement of RecvID when SessionType indicates a one-on-one or notification chat.
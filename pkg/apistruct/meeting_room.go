@@ -0,0 +1,88 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/OpenIMSDK/protocol/sdkws"
+
+// CreateMeetingRoomReq schedules a meeting room, StartTime and EndTime are unix milliseconds.
+// The host is always a participant.
+type CreateMeetingRoomReq struct {
+	HostUserID     string   `json:"hostUserID"     binding:"required"`
+	Title          string   `json:"title"          binding:"required"`
+	StartTime      int64    `json:"startTime"      binding:"required"`
+	EndTime        int64    `json:"endTime"        binding:"required"`
+	ParticipantIDs []string `json:"participantIDs"`
+}
+
+type CreateMeetingRoomResp struct {
+	RoomID string `json:"roomID"`
+}
+
+// UpdateMeetingRoomReq replaces the schedule of a room that has not started yet.
+type UpdateMeetingRoomReq struct {
+	RoomID         string   `json:"roomID"         binding:"required"`
+	Title          string   `json:"title"          binding:"required"`
+	StartTime      int64    `json:"startTime"      binding:"required"`
+	EndTime        int64    `json:"endTime"        binding:"required"`
+	ParticipantIDs []string `json:"participantIDs"`
+}
+
+type CancelMeetingRoomReq struct {
+	RoomID string `json:"roomID" binding:"required"`
+}
+
+type EndMeetingRoomReq struct {
+	RoomID string `json:"roomID" binding:"required"`
+}
+
+type GetMeetingRoomReq struct {
+	RoomID string `json:"roomID" binding:"required"`
+}
+
+type MeetingRoom struct {
+	RoomID         string   `json:"roomID"`
+	Title          string   `json:"title"`
+	HostUserID     string   `json:"hostUserID"`
+	ParticipantIDs []string `json:"participantIDs"`
+	StartTime      int64    `json:"startTime"`
+	EndTime        int64    `json:"endTime"`
+	Status         int32    `json:"status"`
+	CreateTime     int64    `json:"createTime"`
+}
+
+type GetMeetingRoomResp struct {
+	Room *MeetingRoom `json:"room"`
+}
+
+type GetUpcomingMeetingRoomsReq struct {
+	UserID     string                   `json:"userID"     binding:"required"`
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+type GetUpcomingMeetingRoomsResp struct {
+	Total int64          `json:"total"`
+	Rooms []*MeetingRoom `json:"rooms"`
+}
+
+// JoinMeetingRoomReq returns the token the media server checks before letting UserID in.
+type JoinMeetingRoomReq struct {
+	RoomID string `json:"roomID" binding:"required"`
+	UserID string `json:"userID" binding:"required"`
+}
+
+type JoinMeetingRoomResp struct {
+	Token      string `json:"token"`
+	ExpireTime int64  `json:"expireTime"`
+}
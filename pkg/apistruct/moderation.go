@@ -0,0 +1,118 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/OpenIMSDK/protocol/sdkws"
+
+// AddModerationRuleReq adds a rule matched case-insensitively against the text of the messages sent by users,
+// Pattern is a regular expression when Regex is set. Replacement is used by replace rules, *** when it is empty.
+type AddModerationRuleReq struct {
+	Pattern     string `json:"pattern"     binding:"required"`
+	Regex       bool   `json:"regex"`
+	Action      string `json:"action"      binding:"required,oneof=block replace flag"`
+	Replacement string `json:"replacement"`
+}
+
+type AddModerationRuleResp struct {
+	RuleID string `json:"ruleID"`
+}
+
+type DeleteModerationRulesReq struct {
+	RuleIDs []string `json:"ruleIDs" binding:"required"`
+}
+
+type GetModerationRulesReq struct {
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+type ModerationRule struct {
+	RuleID         string `json:"ruleID"`
+	Pattern        string `json:"pattern"`
+	Regex          bool   `json:"regex"`
+	Action         string `json:"action"`
+	Replacement    string `json:"replacement"`
+	OperatorUserID string `json:"operatorUserID"`
+	CreateTime     int64  `json:"createTime"`
+}
+
+type GetModerationRulesResp struct {
+	Total int64             `json:"total"`
+	Rules []*ModerationRule `json:"rules"`
+}
+
+// GetModerationFlagsReq returns the flagged messages newest first, only those sent by SendID when it is not empty.
+type GetModerationFlagsReq struct {
+	SendID     string                   `json:"sendID"`
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+// ModerationFlag is a message flagged by rules or, when Source is reviewer or scorer, by the external reviewer or
+// the scorers. The flags of the scorers carry the scores of the message and the sensitivity level they exceeded.
+type ModerationFlag struct {
+	ServerMsgID    string             `json:"serverMsgID"`
+	ClientMsgID    string             `json:"clientMsgID"`
+	ConversationID string             `json:"conversationID"`
+	SendID         string             `json:"sendID"`
+	ContentType    int32              `json:"contentType"`
+	Source         string             `json:"source"`
+	RuleIDs        []string           `json:"ruleIDs"`
+	Reason         string             `json:"reason"`
+	Scores         map[string]float64 `json:"scores,omitempty"`
+	Sensitivity    string             `json:"sensitivity,omitempty"`
+	CreateTime     int64              `json:"createTime"`
+}
+
+type GetModerationFlagsResp struct {
+	Total int64             `json:"total"`
+	Flags []*ModerationFlag `json:"flags"`
+}
+
+// SetModerationSensitivityReq sets the sensitivity level ConversationID is scored at. Group owners may only set the
+// levels of their groups within the bounds of the config.
+type SetModerationSensitivityReq struct {
+	ConversationID string `json:"conversationID" binding:"required"`
+	Level          string `json:"level"          binding:"required"`
+}
+
+type DeleteModerationSensitivityReq struct {
+	ConversationID string `json:"conversationID" binding:"required"`
+}
+
+type GetModerationSensitivityReq struct {
+	ConversationID string `json:"conversationID" binding:"required"`
+}
+
+// GetModerationSensitivityResp is the level the conversation is scored at, IsDefault is true when no level is set
+// and the default of its session type applies.
+type GetModerationSensitivityResp struct {
+	Level     string `json:"level"`
+	IsDefault bool   `json:"isDefault"`
+}
+
+type GetModerationSensitivitiesReq struct {
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+type ModerationSensitivity struct {
+	ConversationID string `json:"conversationID"`
+	Level          string `json:"level"`
+	OperatorUserID string `json:"operatorUserID"`
+	UpdateTime     int64  `json:"updateTime"`
+}
+
+type GetModerationSensitivitiesResp struct {
+	Total         int64                    `json:"total"`
+	Sensitivities []*ModerationSensitivity `json:"sensitivities"`
+}
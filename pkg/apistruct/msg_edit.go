@@ -0,0 +1,52 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// EditMsgReq replaces the content of a message the user sent, ClientMsgID must match the message at Seq.
+type EditMsgReq struct {
+	UserID         string         `json:"userID"         binding:"required"`
+	ConversationID string         `json:"conversationID" binding:"required"`
+	Seq            int64          `json:"seq"            binding:"required"`
+	ClientMsgID    string         `json:"clientMsgID"    binding:"required"`
+	Content        map[string]any `json:"content"        binding:"required"`
+}
+
+type EditMsgResp struct{}
+
+type GetMsgEditHistoryReq struct {
+	UserID         string `json:"userID"         binding:"required"`
+	ConversationID string `json:"conversationID" binding:"required"`
+	Seq            int64  `json:"seq"            binding:"required"`
+}
+
+type MsgEditHistory struct {
+	EditorUserID string `json:"editorUserID"`
+	Content      string `json:"content"`
+	EditTime     int64  `json:"editTime"`
+}
+
+type GetMsgEditHistoryResp struct {
+	Histories []*MsgEditHistory `json:"histories"`
+}
+
+// MsgEditTips is the detail of the edit notification pushed to the conversation members.
+type MsgEditTips struct {
+	ConversationID string `json:"conversationID"`
+	Seq            int64  `json:"seq"`
+	ClientMsgID    string `json:"clientMsgID"`
+	EditorUserID   string `json:"editorUserID"`
+	Content        string `json:"content"`
+	EditTime       int64  `json:"editTime"`
+}
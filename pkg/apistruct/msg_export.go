@@ -0,0 +1,35 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// ExportConversationMsgsReq exports the messages of ConversationID sent at or after StartTime and before
+// EndTime (ms), EndTime defaults to now. Format is json, csv or html.
+type ExportConversationMsgsReq struct {
+	ConversationID string `json:"conversationID" binding:"required"`
+	Format         string `json:"format"`
+	StartTime      int64  `json:"startTime"`
+	EndTime        int64  `json:"endTime"`
+}
+
+type ExportConversationMsgsResp struct {
+	URL        string `json:"url"`
+	ExpireTime int64  `json:"expireTime"`
+	Name       string `json:"name"`
+	MsgNum     int    `json:"msgNum"`
+	// Truncated is set when the range held more messages than an export takes, EndTime is then the send time
+	// to continue from.
+	Truncated bool  `json:"truncated"`
+	EndTime   int64 `json:"endTime"`
+}
@@ -0,0 +1,48 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "encoding/json"
+
+// ImportMsgsReq imports Msgs into one conversation, the single chat between the two UserIDs or the group GroupID.
+type ImportMsgsReq struct {
+	SessionType int32        `json:"sessionType" binding:"required"`
+	UserIDs     []string     `json:"userIDs"`
+	GroupID     string       `json:"groupID"`
+	Msgs        []*ImportMsg `json:"msgs" binding:"required"`
+}
+
+// ImportMsg is a message as it was sent in the system it is migrated from, Content is the content object as
+// given to /msg/send_msg.
+type ImportMsg struct {
+	ClientMsgID      string          `json:"clientMsgID"`
+	SendID           string          `json:"sendID"`
+	SenderNickname   string          `json:"senderNickname"`
+	SenderFaceURL    string          `json:"senderFaceURL"`
+	SenderPlatformID int32           `json:"senderPlatformID"`
+	ContentType      int32           `json:"contentType"`
+	Content          json.RawMessage `json:"content"`
+	SendTime         int64           `json:"sendTime"`
+	Ex               string          `json:"ex"`
+}
+
+type ImportMsgsResp struct {
+	ConversationID string `json:"conversationID"`
+	Imported       int    `json:"imported"`
+	// Skipped is the number of notifications left out.
+	Skipped  int   `json:"skipped"`
+	FirstSeq int64 `json:"firstSeq"`
+	LastSeq  int64 `json:"lastSeq"`
+}
@@ -0,0 +1,37 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// DeleteMsgsPhysicalReq physically deletes messages of ConversationID, either those with Seqs or those sent before
+// SendTimeBefore (ms). When DeleteObjects is set the pictures, voices, videos and files of the deleted messages are
+// deleted from the object storage too, unless other messages such as forwarded copies still refer to them.
+type DeleteMsgsPhysicalReq struct {
+	ConversationID string  `json:"conversationID" binding:"required"`
+	Seqs           []int64 `json:"seqs"`
+	SendTimeBefore int64   `json:"sendTimeBefore"`
+	DeleteObjects  bool    `json:"deleteObjects"`
+}
+
+type DeleteMsgsPhysicalResp struct {
+	Seqs []int64 `json:"seqs"`
+	// HasMore is set when more messages were sent before SendTimeBefore than a request deletes.
+	HasMore        bool  `json:"hasMore"`
+	DeletedObjects int64 `json:"deletedObjects"`
+	// KeptObjects is the number of objects that were not deleted because other messages still refer to them.
+	KeptObjects int64 `json:"keptObjects"`
+	// ReclaimedBytes is the size of the stored content deleted with the objects, content other objects still
+	// refer to is kept.
+	ReclaimedBytes int64 `json:"reclaimedBytes"`
+}
@@ -0,0 +1,50 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// SetMsgReactionReq adds or removes an emoji reaction on the message at seq.
+type SetMsgReactionReq struct {
+	UserID         string `json:"userID"         binding:"required"`
+	ConversationID string `json:"conversationID" binding:"required"`
+	Seq            int64  `json:"seq"            binding:"required"`
+	Emoji          string `json:"emoji"          binding:"required"`
+}
+
+type SetMsgReactionResp struct{}
+
+type GetMsgReactionsReq struct {
+	UserID         string `json:"userID"         binding:"required"`
+	ConversationID string `json:"conversationID" binding:"required"`
+	Seq            int64  `json:"seq"            binding:"required"`
+}
+
+type MsgReaction struct {
+	Emoji   string   `json:"emoji"`
+	UserIDs []string `json:"userIDs"`
+}
+
+type GetMsgReactionsResp struct {
+	Reactions []*MsgReaction `json:"reactions"`
+}
+
+// MsgReactionTips is the detail of the reaction notification pushed to the conversation members.
+type MsgReactionTips struct {
+	ConversationID string         `json:"conversationID"`
+	Seq            int64          `json:"seq"`
+	ClientMsgID    string         `json:"clientMsgID"`
+	OpUserID       string         `json:"opUserID"`
+	Emoji          string         `json:"emoji"`
+	Reactions      []*MsgReaction `json:"reactions"`
+}
@@ -0,0 +1,33 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// GetReceiptSummariesReq queries the compacted read receipts of a conversation, all readers when UserIDs is empty.
+type GetReceiptSummariesReq struct {
+	ConversationID string   `json:"conversationID" binding:"required"`
+	UserIDs        []string `json:"userIDs"`
+}
+
+type ReceiptSummary struct {
+	UserID        string `json:"userID"`
+	HasReadSeq    int64  `json:"hasReadSeq"`
+	ReceiptCount  int64  `json:"receiptCount"`
+	FirstReadTime int64  `json:"firstReadTime"`
+	LastReadTime  int64  `json:"lastReadTime"`
+}
+
+type GetReceiptSummariesResp struct {
+	Summaries []*ReceiptSummary `json:"summaries"`
+}
@@ -0,0 +1,44 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// SetMsgRetentionReq overrides the global retainChatRecords of a conversation, GroupID sets the group chat conversation.
+// RetainDays 0 keeps messages forever.
+type SetMsgRetentionReq struct {
+	ConversationID string `json:"conversationID"`
+	GroupID        string `json:"groupID"`
+	RetainDays     int32  `json:"retainDays"     binding:"min=0"`
+}
+
+type DeleteMsgRetentionReq struct {
+	ConversationID string `json:"conversationID"`
+	GroupID        string `json:"groupID"`
+}
+
+type GetMsgRetentionsReq struct {
+	ConversationIDs []string `json:"conversationIDs" binding:"required"`
+}
+
+type MsgRetention struct {
+	ConversationID string `json:"conversationID"`
+	GroupID        string `json:"groupID"`
+	RetainDays     int32  `json:"retainDays"`
+	// IsDefault is true when the conversation has no override and uses retainChatRecords.
+	IsDefault bool `json:"isDefault"`
+}
+
+type GetMsgRetentionsResp struct {
+	Retentions []*MsgRetention `json:"retentions"`
+}
@@ -0,0 +1,37 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import (
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/search"
+)
+
+// SearchMsgReq is a full-text search, served by the configured search backend when Keyword is set.
+// Without a keyword the request falls through to the mongo search of the msg rpc.
+type SearchMsgReq struct {
+	Keyword         string                   `json:"keyword"`
+	ConversationIDs []string                 `json:"conversationIDs"`
+	SendID          string                   `json:"sendID"`
+	ContentTypes    []int32                  `json:"contentTypes"`
+	StartTime       int64                    `json:"startTime"`
+	EndTime         int64                    `json:"endTime"`
+	Pagination      *sdkws.RequestPagination `json:"pagination"`
+}
+
+type SearchMsgResp struct {
+	Total int64         `json:"total"`
+	Hits  []*search.Hit `json:"hits"`
+}
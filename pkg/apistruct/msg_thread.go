@@ -0,0 +1,47 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/OpenIMSDK/protocol/sdkws"
+
+// GetThreadMsgsReq pages the replies in the thread of the root message ParentMsgID, oldest first.
+type GetThreadMsgsReq struct {
+	UserID         string                   `json:"userID"         binding:"required"`
+	ConversationID string                   `json:"conversationID" binding:"required"`
+	ParentMsgID    string                   `json:"parentMsgID"    binding:"required"`
+	Pagination     *sdkws.RequestPagination `json:"pagination"     binding:"required"`
+}
+
+type GetThreadMsgsResp struct {
+	Total int64            `json:"total"`
+	Msgs  []*sdkws.MsgData `json:"msgs"`
+}
+
+type MarkThreadAsReadReq struct {
+	UserID         string `json:"userID"         binding:"required"`
+	ConversationID string `json:"conversationID" binding:"required"`
+	ParentMsgID    string `json:"parentMsgID"    binding:"required"`
+}
+
+type GetThreadUnreadCountReq struct {
+	UserID         string   `json:"userID"         binding:"required"`
+	ConversationID string   `json:"conversationID" binding:"required"`
+	ParentMsgIDs   []string `json:"parentMsgIDs"   binding:"required"`
+}
+
+type GetThreadUnreadCountResp struct {
+	// UnreadCounts maps parentMsgID to the number of unread replies in its thread.
+	UnreadCounts map[string]int64 `json:"unreadCounts"`
+}
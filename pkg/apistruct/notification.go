@@ -0,0 +1,128 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/OpenIMSDK/protocol/sdkws"
+
+// SetNotificationAccountInfoReq sets the verification badge and category of a notification account.
+type SetNotificationAccountInfoReq struct {
+	UserID   string `json:"userID"   binding:"required"`
+	Verified bool   `json:"verified"`
+	Category int32  `json:"category" binding:"required,oneof=1 2 3"`
+}
+
+type SetNotificationAccountInfoResp struct{}
+
+type GetNotificationAccountsInfoReq struct {
+	UserIDs []string `json:"userIDs" binding:"required"`
+}
+
+// NotificationAccountInfo describes how a notification account is presented and routed.
+type NotificationAccountInfo struct {
+	UserID   string `json:"userID"`
+	Verified bool   `json:"verified"`
+	Category int32  `json:"category"`
+}
+
+type GetNotificationAccountsInfoResp struct {
+	Accounts []*NotificationAccountInfo `json:"accounts"`
+}
+
+// SetNotificationQuotaReq overrides the quota of a notification account, 0 keeps the configured limit and a
+// negative value lifts it.
+type SetNotificationQuotaReq struct {
+	UserID   string `json:"userID"   binding:"required"`
+	Rate     int32  `json:"rate"`
+	DailyCap int32  `json:"dailyCap"`
+}
+
+type SetNotificationQuotaResp struct{}
+
+// GetNotificationQuotaReq reads the quota of a notification account and what it sent to UserIDs today.
+type GetNotificationQuotaReq struct {
+	AccountID string   `json:"accountID" binding:"required"`
+	UserIDs   []string `json:"userIDs"   binding:"max=100"`
+}
+
+// GetNotificationQuotaResp is the quota in force, 0 is no limit. Remaining is only set when DailyCap is.
+type GetNotificationQuotaResp struct {
+	Rate      int              `json:"rate"`
+	DailyCap  int              `json:"dailyCap"`
+	Used      map[string]int64 `json:"used"`
+	Remaining map[string]int64 `json:"remaining,omitempty"`
+}
+
+// SetNotificationOptOutReq opts a user out of (or back into) a notification category.
+type SetNotificationOptOutReq struct {
+	UserID   string `json:"userID"   binding:"required"`
+	Category int32  `json:"category" binding:"required,oneof=2 3"`
+	OptOut   bool   `json:"optOut"`
+}
+
+type SetNotificationOptOutResp struct{}
+
+type GetNotificationOptOutReq struct {
+	UserID string `json:"userID" binding:"required"`
+}
+
+type GetNotificationOptOutResp struct {
+	Categories []int32 `json:"categories"`
+}
+
+// FollowNotificationAccountReq makes the user follow (or unfollow) a notification account.
+type FollowNotificationAccountReq struct {
+	UserID    string `json:"userID"    binding:"required"`
+	AccountID string `json:"accountID" binding:"required"`
+}
+
+type FollowNotificationAccountResp struct{}
+
+type GetNotificationAccountFollowersReq struct {
+	AccountID  string                   `json:"accountID"  binding:"required"`
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+type GetNotificationAccountFollowersResp struct {
+	Total   int64    `json:"total"`
+	UserIDs []string `json:"userIDs"`
+}
+
+type GetFollowedNotificationAccountsReq struct {
+	UserID     string                   `json:"userID"     binding:"required"`
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+type GetFollowedNotificationAccountsResp struct {
+	Total      int64    `json:"total"`
+	AccountIDs []string `json:"accountIDs"`
+}
+
+type GetNotificationAccountFollowerCountsReq struct {
+	AccountIDs []string `json:"accountIDs" binding:"required,min=1,max=100"`
+}
+
+type GetNotificationAccountFollowerCountsResp struct {
+	Counts map[string]int64 `json:"counts"`
+}
+
+// BroadcastNotificationReq sends a message from a notification account to all its followers.
+type BroadcastNotificationReq struct {
+	SendMsg
+}
+
+type BroadcastNotificationResp struct {
+	Results   []*SingleReturnResult `json:"results"`
+	FailedIDs []string              `json:"failedUserIDs"`
+}
@@ -0,0 +1,58 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// NotificationProfile is the sound and vibration picked for ConversationID, or for all conversations when it is
+// empty. Sound and Vibration are keys of the client's resources, empty for the device default. Language, e.g.
+// zh-CN, picks the localized offline push of messages that carry one.
+type NotificationProfile struct {
+	ConversationID string `json:"conversationID"`
+	Sound          string `json:"sound"`
+	Vibration      string `json:"vibration"`
+	Language       string `json:"language"`
+	// Deleted is only set in synced profiles, the profile was removed and the default applies again.
+	Deleted    bool  `json:"deleted"`
+	UpdateTime int64 `json:"updateTime"`
+}
+
+type SetNotificationProfileReq struct {
+	OwnerUserID    string `json:"ownerUserID"    binding:"required"`
+	ConversationID string `json:"conversationID"`
+	Sound          string `json:"sound"`
+	Vibration      string `json:"vibration"`
+	Language       string `json:"language"`
+}
+
+type DeleteNotificationProfileReq struct {
+	OwnerUserID    string `json:"ownerUserID"    binding:"required"`
+	ConversationID string `json:"conversationID"`
+}
+
+// SyncNotificationProfilesReq returns the profiles changed since UpdateTime (ms), 0 returns all of them.
+type SyncNotificationProfilesReq struct {
+	OwnerUserID string `json:"ownerUserID" binding:"required"`
+	UpdateTime  int64  `json:"updateTime"`
+}
+
+type SyncNotificationProfilesResp struct {
+	Profiles []*NotificationProfile `json:"profiles"`
+}
+
+// NotificationProfileChangedTips is the detail of the notification sent to the devices of the owner of a profile.
+type NotificationProfileChangedTips struct {
+	OwnerUserID    string `json:"ownerUserID"`
+	ConversationID string `json:"conversationID"`
+	Deleted        bool   `json:"deleted"`
+}
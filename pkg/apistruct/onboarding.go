@@ -0,0 +1,80 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import (
+	"github.com/OpenIMSDK/protocol/sdkws"
+)
+
+// AddOnboardingRuleReq suggests a group, a user or an official account to the new users of AppID, to every app
+// when AppID is empty, and of Region only when it is set. A group with AutoJoin and no Region is joined at
+// registration when onboarding.autoJoin is enabled.
+type AddOnboardingRuleReq struct {
+	AppID      string `json:"appID"`
+	Region     string `json:"region"`
+	TargetType string `json:"targetType" binding:"required,oneof=group user official"`
+	TargetID   string `json:"targetID"   binding:"required"`
+	AutoJoin   bool   `json:"autoJoin"`
+	Priority   int32  `json:"priority"`
+}
+
+type AddOnboardingRuleResp struct {
+	RuleID string `json:"ruleID"`
+}
+
+type DeleteOnboardingRulesReq struct {
+	RuleIDs []string `json:"ruleIDs" binding:"required"`
+}
+
+// GetOnboardingRulesReq returns the rules of AppID, the rules of every app when it is empty.
+type GetOnboardingRulesReq struct {
+	AppID      string                   `json:"appID"`
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+type OnboardingRule struct {
+	RuleID         string `json:"ruleID"`
+	AppID          string `json:"appID"`
+	Region         string `json:"region"`
+	TargetType     string `json:"targetType"`
+	TargetID       string `json:"targetID"`
+	AutoJoin       bool   `json:"autoJoin"`
+	Priority       int32  `json:"priority"`
+	OperatorUserID string `json:"operatorUserID"`
+	CreateTime     int64  `json:"createTime"`
+}
+
+type GetOnboardingRulesResp struct {
+	Total int64             `json:"total"`
+	Rules []*OnboardingRule `json:"rules"`
+}
+
+// GetOnboardingSuggestionsReq returns the suggestions for UserID in Region, highest priority first.
+type GetOnboardingSuggestionsReq struct {
+	UserID string `json:"userID" binding:"required"`
+	Region string `json:"region"`
+}
+
+// OnboardingSuggestion has Group set for a group and User set for a user or an official account.
+type OnboardingSuggestion struct {
+	TargetType string                `json:"targetType"`
+	TargetID   string                `json:"targetID"`
+	Group      *sdkws.GroupInfo      `json:"group,omitempty"`
+	User       *sdkws.PublicUserInfo `json:"user,omitempty"`
+}
+
+type GetOnboardingSuggestionsResp struct {
+	Suggestions []*OnboardingSuggestion `json:"suggestions"`
+}
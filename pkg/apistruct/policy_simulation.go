@@ -0,0 +1,84 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// SimulatePolicyReq evaluates the set policies against the messages sent in the last Hours hours without enabling them,
+// Hours is capped by policySimulation.maxHours.
+type SimulatePolicyReq struct {
+	Hours      int                 `json:"hours"      binding:"min=1"`
+	WordFilter *SimulateWordFilter `json:"wordFilter"`
+	RateLimit  *SimulateRateLimit  `json:"rateLimit"`
+	Retention  *SimulateRetention  `json:"retention"`
+}
+
+// SimulateWordFilter matches Words case-insensitively in the text of text, @, quote and file messages.
+type SimulateWordFilter struct {
+	Words []string `json:"words" binding:"required"`
+}
+
+// SimulateRateLimit allows each user Limit messages per Window seconds.
+type SimulateRateLimit struct {
+	Limit  int `json:"limit"  binding:"min=1"`
+	Window int `json:"window" binding:"min=1"`
+}
+
+// SimulateRetention keeps the messages of a conversation, or of a group chat when GroupID is set, for RetainDays days.
+type SimulateRetention struct {
+	ConversationID string `json:"conversationID"`
+	GroupID        string `json:"groupID"`
+	RetainDays     int32  `json:"retainDays"     binding:"min=1"`
+}
+
+// SimulatedSender is a sender anonymized with an alias that is only stable within one response.
+type SimulatedSender struct {
+	Alias string `json:"alias"`
+	Count int64  `json:"count"`
+}
+
+// WordFilterSimulation counts the sampled messages, Estimated is scaled to every message sent since Since.
+type WordFilterSimulation struct {
+	Matched          int64              `json:"matched"`
+	EstimatedMatched int64              `json:"estimatedMatched"`
+	Words            map[string]int64   `json:"words"`
+	Senders          int64              `json:"senders"`
+	Conversations    int64              `json:"conversations"`
+	TopSenders       []*SimulatedSender `json:"topSenders"`
+}
+
+// RateLimitSimulation applies SampledLimit, the limit scaled down to the sample, to the sampled messages.
+type RateLimitSimulation struct {
+	SampledLimit     int                `json:"sampledLimit"`
+	Limited          int64              `json:"limited"`
+	EstimatedLimited int64              `json:"estimatedLimited"`
+	Senders          int64              `json:"senders"`
+	TopSenders       []*SimulatedSender `json:"topSenders"`
+}
+
+// RetentionSimulation reports the sampled messages the rule covers and the stored messages the next clear would delete.
+type RetentionSimulation struct {
+	ConversationID   string `json:"conversationID"`
+	Covered          int64  `json:"covered"`
+	EstimatedCovered int64  `json:"estimatedCovered"`
+	Deleted          int64  `json:"deleted"`
+}
+
+type SimulatePolicyResp struct {
+	Since       int64                 `json:"since"`
+	TotalMsgs   int64                 `json:"totalMsgs"`
+	SampledMsgs int64                 `json:"sampledMsgs"`
+	WordFilter  *WordFilterSimulation `json:"wordFilter,omitempty"`
+	RateLimit   *RateLimitSimulation  `json:"rateLimit,omitempty"`
+	Retention   *RetentionSimulation  `json:"retention,omitempty"`
+}
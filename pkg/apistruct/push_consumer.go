@@ -0,0 +1,32 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// PausePushConsumerReq pauses or resumes Partitions of Topic in the push consumer group, or every partition of
+// every topic with All. Topic is the push topic when empty.
+type PausePushConsumerReq struct {
+	Topic      string  `json:"topic"`
+	Partitions []int32 `json:"partitions"`
+	All        bool    `json:"all"`
+}
+
+type GetPushConsumerPauseReq struct{}
+
+// PushConsumerPause is the partitions paused in the push consumer group by topic, every partition when All is set.
+type PushConsumerPause struct {
+	All        bool               `json:"all"`
+	Partitions map[string][]int32 `json:"partitions"`
+	UpdateTime int64              `json:"updateTime"`
+}
@@ -0,0 +1,47 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/OpenIMSDK/protocol/sdkws"
+
+type GetPushDeadLettersReq struct {
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+type PushDeadLetter struct {
+	ID             string   `json:"id"`
+	ConversationID string   `json:"conversationID"`
+	UserIDs        []string `json:"userIDs"`
+	Title          string   `json:"title"`
+	Content        string   `json:"content"`
+	Attempts       int      `json:"attempts"`
+	LastErr        string   `json:"lastErr"`
+	CreateTime     int64    `json:"createTime"`
+	UpdateTime     int64    `json:"updateTime"`
+}
+
+type GetPushDeadLettersResp struct {
+	Total       int64             `json:"total"`
+	DeadLetters []*PushDeadLetter `json:"deadLetters"`
+}
+
+type RequeuePushDeadLettersReq struct {
+	IDs []string `json:"ids" binding:"required"`
+}
+
+type RequeuePushDeadLettersResp struct {
+	// RequeuedIDs skips the ids that are no longer dead-lettered.
+	RequeuedIDs []string `json:"requeuedIDs"`
+}
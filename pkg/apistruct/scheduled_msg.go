@@ -0,0 +1,53 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/OpenIMSDK/protocol/sdkws"
+
+// ScheduleMsgReq is a SendMsgReq delivered at ScheduleTime (ms) instead of immediately.
+type ScheduleMsgReq struct {
+	SendMsgReq
+	ScheduleTime int64 `json:"scheduleTime" binding:"required"`
+}
+
+type ScheduleMsgResp struct {
+	ScheduleID string `json:"scheduleID"`
+}
+
+type CancelScheduledMsgReq struct {
+	UserID     string `json:"userID"     binding:"required"`
+	ScheduleID string `json:"scheduleID" binding:"required"`
+}
+
+// GetScheduledMsgsReq lists the messages scheduled by the user, filtered by Status when it is set.
+type GetScheduledMsgsReq struct {
+	UserID     string                   `json:"userID"     binding:"required"`
+	Status     []int32                  `json:"status"`
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+type ScheduledMsg struct {
+	ScheduleID   string         `json:"scheduleID"`
+	ScheduleTime int64          `json:"scheduleTime"`
+	Status       int32          `json:"status"`
+	Err          string         `json:"err"`
+	CreateTime   int64          `json:"createTime"`
+	MsgData      *sdkws.MsgData `json:"msgData"`
+}
+
+type GetScheduledMsgsResp struct {
+	Total         int64           `json:"total"`
+	ScheduledMsgs []*ScheduledMsg `json:"scheduledMsgs"`
+}
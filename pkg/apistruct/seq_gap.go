@@ -0,0 +1,41 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+type FindSeqGapsReq struct {
+	ConversationID string `json:"conversationID" binding:"required"`
+}
+
+// RepairSeqGapsReq fills the gaps of a conversation with deleted placeholder messages, or with Mode compact raises
+// its min seq past a gap at the start.
+type RepairSeqGapsReq struct {
+	ConversationID string `json:"conversationID" binding:"required"`
+	Mode           string `json:"mode"           binding:"required,oneof=placeholder compact"`
+}
+
+type SeqGap struct {
+	Begin int64 `json:"begin"`
+	End   int64 `json:"end"`
+}
+
+// SeqGapsResp is the scan of the conversation, after a repair it is taken again so Gaps lists what is left.
+type SeqGapsResp struct {
+	MinSeq  int64     `json:"minSeq"`
+	MaxSeq  int64     `json:"maxSeq"`
+	Missing int64     `json:"missing"`
+	Gaps    []*SeqGap `json:"gaps"`
+	// Repaired is the number of placeholders stored, or of seqs cut off by compaction.
+	Repaired int64 `json:"repaired"`
+}
@@ -0,0 +1,59 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/openimsdk/open-im-server/v3/pkg/socialgraph"
+
+// ExportSocialGraphReq exports the friends and groups of UserID, the op user when empty. Format is json, the
+// default, or vcard, which is returned as a text/vcard body.
+type ExportSocialGraphReq struct {
+	UserID string `json:"userID"`
+	Format string `json:"format"`
+}
+
+// ImportSocialGraphReq imports a graph exported by another deployment, Graph for the json format and Data for
+// vcard. Users send friend and group join requests, app managers add the friends and members directly.
+type ImportSocialGraphReq struct {
+	UserID     string             `json:"userID"`
+	Format     string             `json:"format"`
+	Graph      *socialgraph.Graph `json:"graph"`
+	Data       string             `json:"data"`
+	ReqMessage string             `json:"reqMessage"`
+}
+
+type ImportSocialGraphResp struct {
+	Friends *ImportSocialGraphFriends `json:"friends"`
+	Groups  *ImportSocialGraphGroups  `json:"groups"`
+}
+
+type ImportSocialGraphFriends struct {
+	// Imported lists the friends added directly, Requested the ones a friend request was sent to.
+	Imported       []string `json:"imported"`
+	Requested      []string `json:"requested"`
+	AlreadyFriends []string `json:"alreadyFriends"`
+	Blocked        []string `json:"blocked"`
+	NotExist       []string `json:"notExist"`
+	Failed         []string `json:"failed"`
+}
+
+type ImportSocialGraphGroups struct {
+	// Joined lists the groups joined directly, Requested the ones joined or applied to according to their
+	// verification setting.
+	Joined        []string `json:"joined"`
+	Requested     []string `json:"requested"`
+	AlreadyJoined []string `json:"alreadyJoined"`
+	NotExist      []string `json:"notExist"`
+	Failed        []string `json:"failed"`
+}
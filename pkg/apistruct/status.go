@@ -0,0 +1,37 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// ComponentStatus is the health summary of one component, Status is "up" or "down".
+type ComponentStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// DeliveryLatency reports message delivery latency percentiles as coarse bands, e.g. "100ms-500ms".
+type DeliveryLatency struct {
+	P50 string `json:"p50"`
+	P90 string `json:"p90"`
+	P99 string `json:"p99"`
+}
+
+// StatusResp is served by the public /status endpoint, Status is "operational" or "degraded".
+type StatusResp struct {
+	Status          string             `json:"status"`
+	Version         string             `json:"version"`
+	Components      []*ComponentStatus `json:"components"`
+	DeliveryLatency *DeliveryLatency   `json:"deliveryLatency"`
+	UpdateTime      int64              `json:"updateTime"`
+}
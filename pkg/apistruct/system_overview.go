@@ -0,0 +1,42 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// RpcErrorRate is the share of the calls of one rpc service that failed over the last minute.
+type RpcErrorRate struct {
+	Service   string  `json:"service"`
+	Total     int64   `json:"total"`
+	Errors    int64   `json:"errors"`
+	ErrorRate float64 `json:"errorRate"`
+}
+
+// ConsumerLag is how many messages of Topic the consumer group GroupID has not consumed yet.
+type ConsumerLag struct {
+	GroupID string `json:"groupID"`
+	Topic   string `json:"topic"`
+	Lag     int64  `json:"lag"`
+}
+
+// SystemOverviewResp aggregates the latest metrics reported by every rpc instance. ConsumerLags is empty when
+// kafka cannot be reached.
+type SystemOverviewResp struct {
+	OnlineUsers     int64              `json:"onlineUsers"`
+	OnlinePlatforms map[string]int64   `json:"onlinePlatforms"`
+	MsgPerSecond    float64            `json:"msgPerSecond"`
+	RpcErrorRates   []*RpcErrorRate    `json:"rpcErrorRates"`
+	ConsumerLags    []*ConsumerLag     `json:"consumerLags"`
+	Components      []*ComponentStatus `json:"components"`
+	UpdateTime      int64              `json:"updateTime"`
+}
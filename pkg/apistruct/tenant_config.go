@@ -0,0 +1,43 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/OpenIMSDK/protocol/sdkws"
+
+// SetTenantConfigReq replaces the config overrides of the app AppID. Overrides is a yaml document shaped
+// like config.yaml limited to the sections a tenant may override.
+type SetTenantConfigReq struct {
+	AppID     string `json:"appID"     binding:"required"`
+	Overrides string `json:"overrides" binding:"required"`
+}
+
+type DeleteTenantConfigReq struct {
+	AppID string `json:"appID" binding:"required"`
+}
+
+type GetTenantConfigsReq struct {
+	Pagination *sdkws.RequestPagination `json:"pagination" binding:"required"`
+}
+
+type TenantConfig struct {
+	AppID      string `json:"appID"`
+	Overrides  string `json:"overrides"`
+	UpdateTime int64  `json:"updateTime"`
+}
+
+type GetTenantConfigsResp struct {
+	Total   int64           `json:"total"`
+	Configs []*TenantConfig `json:"configs"`
+}
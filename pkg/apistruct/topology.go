@@ -0,0 +1,46 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// TopologyInstance is one running or registered instance of a component. Status is "up", "down" when its
+// connection is failing, "unreported" when it is registered but sends no reports, e.g. an instance of a version
+// before the topology view, and "unregistered" when it reports but is missing from the discovery registry.
+type TopologyInstance struct {
+	Address    string `json:"address"`
+	Registered bool   `json:"registered"`
+	ConnState  string `json:"connState,omitempty"`
+	Version    string `json:"version"`
+	Hostname   string `json:"hostname"`
+	PID        int    `json:"pid"`
+	StartTime  int64  `json:"startTime"`
+	// Uptime is in seconds.
+	Uptime     int64  `json:"uptime"`
+	LastReport int64  `json:"lastReport"`
+	Status     string `json:"status"`
+}
+
+// TopologyComponent is "up" when all its instances are, "degraded" when some are and "down" when none is.
+// Versions counts the instances by version, a rollout completed once it has a single entry.
+type TopologyComponent struct {
+	Name      string              `json:"name"`
+	Status    string              `json:"status"`
+	Versions  map[string]int      `json:"versions"`
+	Instances []*TopologyInstance `json:"instances"`
+}
+
+type GetTopologyResp struct {
+	Components []*TopologyComponent `json:"components"`
+	UpdateTime int64                `json:"updateTime"`
+}
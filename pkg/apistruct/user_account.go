@@ -0,0 +1,49 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+type DeactivateUserReq struct {
+	UserID string `json:"userID" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+type DeleteAccountReq struct {
+	UserID string `json:"userID" binding:"required"`
+	Reason string `json:"reason"`
+}
+
+type DeleteAccountResp struct {
+	// DeleteTime (ms) is when the data of the user is purged.
+	DeleteTime int64 `json:"deleteTime"`
+}
+
+type ReactivateUserReq struct {
+	UserID string `json:"userID" binding:"required"`
+}
+
+type GetAccountStatusReq struct {
+	UserID string `json:"userID" binding:"required"`
+}
+
+// GetAccountStatusResp has the status of the account: 0 active, 1 deactivated, 2 deleting, 3 deleted.
+type GetAccountStatusResp struct {
+	UserID         string `json:"userID"`
+	Status         int32  `json:"status"`
+	Reason         string `json:"reason"`
+	OperatorUserID string `json:"operatorUserID"`
+	DeleteTime     int64  `json:"deleteTime"`
+	PurgeID        string `json:"purgeID"`
+	UpdateTime     int64  `json:"updateTime"`
+}
@@ -0,0 +1,43 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/OpenIMSDK/protocol/sdkws"
+
+// BlockUsersReq blocks BlockUserIDs for OwnerUserID, or unblocks them in UnblockUsersReq.
+type BlockUsersReq struct {
+	OwnerUserID  string   `json:"ownerUserID"  binding:"required"`
+	BlockUserIDs []string `json:"blockUserIDs" binding:"required"`
+}
+
+type UnblockUsersReq struct {
+	OwnerUserID  string   `json:"ownerUserID"  binding:"required"`
+	BlockUserIDs []string `json:"blockUserIDs" binding:"required"`
+}
+
+type GetBlockedUsersReq struct {
+	OwnerUserID string                   `json:"ownerUserID" binding:"required"`
+	Pagination  *sdkws.RequestPagination `json:"pagination"  binding:"required"`
+}
+
+type BlockedUser struct {
+	UserID     string `json:"userID"`
+	CreateTime int64  `json:"createTime"`
+}
+
+type GetBlockedUsersResp struct {
+	Total int64          `json:"total"`
+	Users []*BlockedUser `json:"users"`
+}
@@ -0,0 +1,47 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+type GetDevicesReq struct {
+	UserID string `json:"userID" binding:"required"`
+}
+
+// Device is a logged in device of the user, LastActive and IP are kept while it is connected to a gateway.
+type Device struct {
+	DeviceID   string `json:"deviceID"`
+	PlatformID int32  `json:"platformID"`
+	Name       string `json:"name"`
+	LoginTime  int64  `json:"loginTime"`
+	ExpireTime int64  `json:"expireTime"`
+	LastActive int64  `json:"lastActive"`
+	IP         string `json:"ip"`
+	// Current is set on the device the request was sent with.
+	Current bool `json:"current"`
+}
+
+type GetDevicesResp struct {
+	Devices []*Device `json:"devices"`
+}
+
+type RevokeDeviceReq struct {
+	UserID   string `json:"userID" binding:"required"`
+	DeviceID string `json:"deviceID" binding:"required"`
+}
+
+type RenameDeviceReq struct {
+	UserID   string `json:"userID" binding:"required"`
+	DeviceID string `json:"deviceID" binding:"required"`
+	Name     string `json:"name"`
+}
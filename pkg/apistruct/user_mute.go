@@ -0,0 +1,44 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/OpenIMSDK/protocol/sdkws"
+
+// MuteUsersReq mutes MuteUserIDs for OwnerUserID, or unmutes them in UnmuteUsersReq. The messages of a muted user
+// are still delivered but neither trigger an offline push nor count as unread.
+type MuteUsersReq struct {
+	OwnerUserID string   `json:"ownerUserID" binding:"required"`
+	MuteUserIDs []string `json:"muteUserIDs" binding:"required"`
+}
+
+type UnmuteUsersReq struct {
+	OwnerUserID string   `json:"ownerUserID" binding:"required"`
+	MuteUserIDs []string `json:"muteUserIDs" binding:"required"`
+}
+
+type GetMutedUsersReq struct {
+	OwnerUserID string                   `json:"ownerUserID" binding:"required"`
+	Pagination  *sdkws.RequestPagination `json:"pagination"  binding:"required"`
+}
+
+type MutedUser struct {
+	UserID     string `json:"userID"`
+	CreateTime int64  `json:"createTime"`
+}
+
+type GetMutedUsersResp struct {
+	Total int64        `json:"total"`
+	Users []*MutedUser `json:"users"`
+}
@@ -0,0 +1,42 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// UserPrivacy is the privacy settings of a user, AddFriendPolicy is 0 for anyone, 1 for contacts of contacts
+// and 2 for no one.
+type UserPrivacy struct {
+	UserID           string `json:"userID"`
+	AddFriendPolicy  int32  `json:"addFriendPolicy"`
+	SearchByID       bool   `json:"searchByID"`
+	SearchByPhone    bool   `json:"searchByPhone"`
+	SearchByNickname bool   `json:"searchByNickname"`
+}
+
+// SetUserPrivacyReq changes the settings that are not nil.
+type SetUserPrivacyReq struct {
+	UserID           string `json:"userID"           binding:"required"`
+	AddFriendPolicy  *int32 `json:"addFriendPolicy"`
+	SearchByID       *bool  `json:"searchByID"`
+	SearchByPhone    *bool  `json:"searchByPhone"`
+	SearchByNickname *bool  `json:"searchByNickname"`
+}
+
+type GetUserPrivacyReq struct {
+	UserID string `json:"userID" binding:"required"`
+}
+
+type GetUserPrivacyResp struct {
+	Privacy *UserPrivacy `json:"privacy"`
+}
@@ -0,0 +1,52 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+// PurgeUserReq queues the erasure of a user, it is run by the cron task.
+type PurgeUserReq struct {
+	UserID string `json:"userID" binding:"required"`
+}
+
+type PurgeUserResp struct {
+	PurgeID string `json:"purgeID"`
+}
+
+// GetUserPurgeReportsReq returns the purge with PurgeID, or every purge of UserID.
+type GetUserPurgeReportsReq struct {
+	PurgeID string `json:"purgeID"`
+	UserID  string `json:"userID"`
+}
+
+type UserPurgeStep struct {
+	Name       string `json:"name"`
+	Count      int64  `json:"count"`
+	Err        string `json:"err"`
+	FinishTime int64  `json:"finishTime"`
+}
+
+// UserPurgeReport is the audit record of a purge, Status is 0 pending, 1 running, 2 finished and 3 failed.
+type UserPurgeReport struct {
+	PurgeID        string           `json:"purgeID"`
+	UserID         string           `json:"userID"`
+	OperatorUserID string           `json:"operatorUserID"`
+	Status         int32            `json:"status"`
+	Steps          []*UserPurgeStep `json:"steps"`
+	CreateTime     int64            `json:"createTime"`
+	FinishTime     int64            `json:"finishTime"`
+}
+
+type GetUserPurgeReportsResp struct {
+	Reports []*UserPurgeReport `json:"reports"`
+}
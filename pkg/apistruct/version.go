@@ -0,0 +1,32 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apistruct
+
+import "github.com/openimsdk/open-im-server/v3/pkg/common/version"
+
+// VersionInstance is the build of a registered rpc instance, Err is set instead when it could not be read.
+type VersionInstance struct {
+	Service string             `json:"service"`
+	Address string             `json:"address"`
+	Build   *version.BuildInfo `json:"build,omitempty"`
+	Err     string             `json:"err,omitempty"`
+}
+
+// GetVersionsResp lists the builds of the api instance answering and of every registered rpc instance.
+// Consistent is true when all the builds read share the same version and protocol version.
+type GetVersionsResp struct {
+	Instances  []*VersionInstance `json:"instances"`
+	Consistent bool               `json:"consistent"`
+}
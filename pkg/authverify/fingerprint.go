@@ -0,0 +1,50 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authverify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+// DeviceFingerprint is the header, or websocket query parameter, carrying the fingerprint of the device using a token.
+const DeviceFingerprint = "deviceFingerprint"
+
+// HashFingerprint returns the form a fingerprint is stored and compared in, so raw device identifiers are never kept.
+func HashFingerprint(fingerprint string) string {
+	sum := sha256.Sum256([]byte(fingerprint))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckFingerprint compares the fingerprint presented with a token against the hashed one bound at issuance.
+// Tokens issued without a fingerprint are not bound, in grace mode a mismatch is only logged.
+func CheckFingerprint(ctx context.Context, bound string, fingerprint string, config *config.GlobalConfig) error {
+	if !config.TokenPolicy.Fingerprint.Enable || bound == "" {
+		return nil
+	}
+	if fingerprint != "" && HashFingerprint(fingerprint) == bound {
+		return nil
+	}
+	if config.TokenPolicy.Fingerprint.Grace {
+		log.ZWarn(ctx, "token used from another device fingerprint", nil, "fingerprint", fingerprint)
+		return nil
+	}
+	return errs.ErrTokenInvalid.Wrap("token is bound to another device")
+}
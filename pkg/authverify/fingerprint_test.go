@@ -0,0 +1,53 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authverify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func fingerprintConfig(enable, grace bool) *config.GlobalConfig {
+	conf := config.NewGlobalConfig()
+	conf.TokenPolicy.Fingerprint.Enable = enable
+	conf.TokenPolicy.Fingerprint.Grace = grace
+	return conf
+}
+
+func TestCheckFingerprint(t *testing.T) {
+	ctx := context.Background()
+	bound := HashFingerprint("device-a")
+	assert.NotEqual(t, "device-a", bound)
+
+	strict := fingerprintConfig(true, false)
+	assert.Nil(t, CheckFingerprint(ctx, bound, "device-a", strict))
+	// Tokens issued without a fingerprint are accepted from any device.
+	assert.Nil(t, CheckFingerprint(ctx, "", "device-b", strict))
+	assert.True(t, errs.ErrTokenInvalid.Is(CheckFingerprint(ctx, bound, "device-b", strict)))
+	assert.True(t, errs.ErrTokenInvalid.Is(CheckFingerprint(ctx, bound, "", strict)))
+	// The stored hash is not accepted as the fingerprint itself.
+	assert.True(t, errs.ErrTokenInvalid.Is(CheckFingerprint(ctx, bound, bound, strict)))
+
+	grace := fingerprintConfig(true, true)
+	assert.Nil(t, CheckFingerprint(ctx, bound, "device-a", grace))
+	assert.Nil(t, CheckFingerprint(ctx, bound, "device-b", grace))
+	assert.Nil(t, CheckFingerprint(ctx, bound, "", grace))
+
+	assert.Nil(t, CheckFingerprint(ctx, bound, "device-b", fingerprintConfig(false, false)))
+}
@@ -0,0 +1,304 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authverify
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+const keyIDHeader = "kid"
+
+// keySet holds the signing key and all verification keys loaded from the token policy.
+type keySet struct {
+	method     jwt.SigningMethod
+	signKeyID  string
+	signKey    any
+	verifyKeys map[string]any
+	// secret is nil once the secret is retired.
+	secret []byte
+}
+
+// keyCheckInterval is how often the key files of a token policy are checked for changes, so rotated keys
+// are picked up without a restart.
+const keyCheckInterval = 10 * time.Second
+
+type keySetEntry struct {
+	ks      *keySet
+	files   string
+	checked time.Time
+}
+
+var keySets sync.Map // policy key -> *keySetEntry
+
+// policyKey identifies a token policy by its content, so configs with the same policy share their keys.
+func policyKey(conf *config.GlobalConfig) string {
+	var b strings.Builder
+	p := conf.TokenPolicy
+	fmt.Fprintf(&b, "%s|%s|%t|%s", p.SigningMethod, p.SigningKeyID, p.SecretRetired, conf.Secret)
+	for _, key := range p.Keys {
+		fmt.Fprintf(&b, "|%s|%s|%s", key.KeyID, key.PublicKey, key.PrivateKey)
+	}
+	return b.String()
+}
+
+// keyFilesState returns the size and modification time of every key file of the policy.
+func keyFilesState(conf *config.GlobalConfig) (string, error) {
+	var b strings.Builder
+	for _, key := range conf.TokenPolicy.Keys {
+		for _, path := range []string{key.PublicKey, key.PrivateKey} {
+			if path == "" {
+				continue
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&b, "%s:%d:%d|", path, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return b.String(), nil
+}
+
+// getKeySet returns the keys of the token policy, reloaded when a key file changed. A reload that fails
+// keeps the previous keys so a half written key file does not reject every token.
+func getKeySet(conf *config.GlobalConfig) (*keySet, error) {
+	name := policyKey(conf)
+	now := time.Now()
+	var last *keySetEntry
+	if v, ok := keySets.Load(name); ok {
+		last = v.(*keySetEntry)
+		if now.Sub(last.checked) < keyCheckInterval {
+			return last.ks, nil
+		}
+	}
+	files, err := keyFilesState(conf)
+	if err == nil && last != nil && last.files == files {
+		keySets.Store(name, &keySetEntry{ks: last.ks, files: files, checked: now})
+		return last.ks, nil
+	}
+	var ks *keySet
+	if err == nil {
+		ks, err = loadKeySet(conf)
+	}
+	if err != nil {
+		if last == nil {
+			return nil, err
+		}
+		log.ZWarn(context.Background(), "reload token keys failed, the previous keys are kept", err)
+		keySets.Store(name, &keySetEntry{ks: last.ks, files: last.files, checked: now})
+		return last.ks, nil
+	}
+	keySets.Store(name, &keySetEntry{ks: ks, files: files, checked: now})
+	return ks, nil
+}
+
+func loadKeySet(conf *config.GlobalConfig) (*keySet, error) {
+	ks := &keySet{
+		signKeyID:  conf.TokenPolicy.SigningKeyID,
+		verifyKeys: make(map[string]any),
+	}
+	if !conf.TokenPolicy.SecretRetired {
+		ks.secret = []byte(conf.Secret)
+	}
+	switch conf.TokenPolicy.SigningMethod {
+	case "", jwt.SigningMethodHS256.Alg():
+		ks.method = jwt.SigningMethodHS256
+	case jwt.SigningMethodRS256.Alg():
+		ks.method = jwt.SigningMethodRS256
+	case jwt.SigningMethodES256.Alg():
+		ks.method = jwt.SigningMethodES256
+	default:
+		return nil, errs.Wrap(fmt.Errorf("unsupported token signing method %s", conf.TokenPolicy.SigningMethod))
+	}
+	for _, key := range conf.TokenPolicy.Keys {
+		if key.KeyID == "" {
+			return nil, errs.Wrap(fmt.Errorf("token key id is empty"))
+		}
+		if _, ok := ks.verifyKeys[key.KeyID]; ok {
+			return nil, errs.Wrap(fmt.Errorf("duplicate token key id %s", key.KeyID))
+		}
+		pub, err := parsePublicKey(key.PublicKey)
+		if err != nil {
+			return nil, errs.Wrap(err, "token key "+key.KeyID)
+		}
+		ks.verifyKeys[key.KeyID] = pub
+		if key.KeyID == ks.signKeyID && key.PrivateKey != "" {
+			if ks.signKey, err = parsePrivateKey(key.PrivateKey); err != nil {
+				return nil, errs.Wrap(err, "token key "+key.KeyID)
+			}
+		}
+	}
+	if ks.method == jwt.SigningMethodHS256 && conf.TokenPolicy.SecretRetired {
+		return nil, errs.Wrap(fmt.Errorf("the secret is retired, token signing method %s can not be used", ks.method.Alg()))
+	}
+	if ks.method != jwt.SigningMethodHS256 {
+		if ks.signKey == nil {
+			return nil, errs.Wrap(fmt.Errorf("signing key %s has no private key", ks.signKeyID))
+		}
+		if !keyMatchesMethod(ks.method, ks.signKey) {
+			return nil, errs.Wrap(fmt.Errorf("signing key %s does not match method %s", ks.signKeyID, ks.method.Alg()))
+		}
+	}
+	return ks, nil
+}
+
+func readPEM(path string) ([]byte, error) {
+	if path == "" {
+		return nil, fmt.Errorf("key file is empty")
+	}
+	return os.ReadFile(path)
+}
+
+func parsePublicKey(path string) (any, error) {
+	data, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	return jwt.ParseECPublicKeyFromPEM(data)
+}
+
+func parsePrivateKey(path string) (any, error) {
+	data, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	return jwt.ParseECPrivateKeyFromPEM(data)
+}
+
+func keyMatchesMethod(method jwt.SigningMethod, key any) bool {
+	switch key.(type) {
+	case *rsa.PrivateKey, *rsa.PublicKey:
+		_, ok := method.(*jwt.SigningMethodRSA)
+		return ok
+	case *ecdsa.PrivateKey, *ecdsa.PublicKey:
+		_, ok := method.(*jwt.SigningMethodECDSA)
+		return ok
+	default:
+		return false
+	}
+}
+
+// Keyfunc returns the jwt.Keyfunc used to verify tokens issued under the configured token policy.
+// Tokens carrying a kid header are verified with the matching public key, tokens without one
+// fall back to the shared secret so that tokens issued before a switch to asymmetric keys stay valid,
+// until TokenPolicy.SecretRetired is set.
+func Keyfunc(conf *config.GlobalConfig) jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		ks, err := getKeySet(conf)
+		if err != nil {
+			return nil, err
+		}
+		kid, _ := token.Header[keyIDHeader].(string)
+		if kid == "" {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, errs.ErrTokenUnknown.Wrap("token kid is empty")
+			}
+			if ks.secret == nil {
+				return nil, errs.ErrTokenUnknown.Wrap("tokens signed with the secret are no longer accepted")
+			}
+			return ks.secret, nil
+		}
+		key, ok := ks.verifyKeys[kid]
+		if !ok {
+			return nil, errs.ErrTokenUnknown.Wrap(fmt.Sprintf("token kid %s not found", kid))
+		}
+		if !keyMatchesMethod(token.Method, key) {
+			return nil, errs.ErrTokenUnknown.Wrap(fmt.Sprintf("token alg %s does not match kid %s", token.Method.Alg(), kid))
+		}
+		return key, nil
+	}
+}
+
+// SignToken signs claims with the configured signing method and key.
+func SignToken(conf *config.GlobalConfig, claims jwt.Claims) (string, error) {
+	ks, err := getKeySet(conf)
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(ks.method, claims)
+	if ks.method == jwt.SigningMethodHS256 {
+		return token.SignedString(ks.secret)
+	}
+	token.Header[keyIDHeader] = ks.signKeyID
+	return token.SignedString(ks.signKey)
+}
+
+// JSONWebKey is the public part of a verification key in JWK format (RFC 7517).
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// PublicKeys returns all configured verification keys so that third-party services can verify tokens
+// without knowing the signing secret.
+func PublicKeys(conf *config.GlobalConfig) ([]*JSONWebKey, error) {
+	ks, err := getKeySet(conf)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*JSONWebKey, 0, len(ks.verifyKeys))
+	for kid, key := range ks.verifyKeys {
+		switch k := key.(type) {
+		case *rsa.PublicKey:
+			keys = append(keys, &JSONWebKey{
+				Kty: "RSA",
+				Kid: kid,
+				Use: "sig",
+				Alg: jwt.SigningMethodRS256.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+			})
+		case *ecdsa.PublicKey:
+			size := (k.Curve.Params().BitSize + 7) / 8
+			keys = append(keys, &JSONWebKey{
+				Kty: "EC",
+				Kid: kid,
+				Use: "sig",
+				Alg: jwt.SigningMethodES256.Alg(),
+				Crv: k.Curve.Params().Name,
+				X:   base64.RawURLEncoding.EncodeToString(k.X.FillBytes(make([]byte, size))),
+				Y:   base64.RawURLEncoding.EncodeToString(k.Y.FillBytes(make([]byte, size))),
+			})
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,268 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authverify
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OpenIMSDK/tools/tokenverify"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeKey writes the PEM files of key to dir and returns the token key, without the private key unless private.
+func writeKey(t *testing.T, dir string, keyID string, key any, private bool) config.TokenKey {
+	var (
+		der []byte
+		typ string
+		err error
+		pub any
+	)
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		der, typ, pub = x509.MarshalPKCS1PrivateKey(k), "RSA PRIVATE KEY", &k.PublicKey
+	case *ecdsa.PrivateKey:
+		der, err = x509.MarshalECPrivateKey(k)
+		assert.NoError(t, err)
+		typ, pub = "EC PRIVATE KEY", &k.PublicKey
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	assert.NoError(t, err)
+	tk := config.TokenKey{KeyID: keyID, PublicKey: filepath.Join(dir, keyID+".pub.pem")}
+	assert.NoError(t, os.WriteFile(tk.PublicKey, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o600))
+	if private {
+		tk.PrivateKey = filepath.Join(dir, keyID+".pem")
+		assert.NoError(t, os.WriteFile(tk.PrivateKey, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600))
+	}
+	return tk
+}
+
+func keyConfig(method string, signKeyID string, keys ...config.TokenKey) *config.GlobalConfig {
+	conf := config.NewGlobalConfig()
+	conf.Secret = "key-secret"
+	conf.TokenPolicy.SigningMethod = method
+	conf.TokenPolicy.SigningKeyID = signKeyID
+	conf.TokenPolicy.Keys = keys
+	return conf
+}
+
+func testClaims() *Claims {
+	return &Claims{Claims: tokenverify.Claims{UserID: "u1", PlatformID: 1, RegisteredClaims: jwt.RegisteredClaims{
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}}}
+}
+
+func TestKeyLoading(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	rsaPriv := writeKey(t, dir, "rsa", rsaKey, true)
+	ecPriv := writeKey(t, dir, "ec", ecKey, true)
+
+	_, err = loadKeySet(keyConfig("RS256", "rsa", rsaPriv))
+	assert.NoError(t, err)
+	_, err = loadKeySet(keyConfig("ES256", "ec", ecPriv))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name string
+		conf *config.GlobalConfig
+	}{
+		{name: "unknown method", conf: keyConfig("PS256", "rsa", rsaPriv)},
+		{name: "empty key id", conf: keyConfig("RS256", "rsa", config.TokenKey{PublicKey: rsaPriv.PublicKey})},
+		{name: "duplicate key id", conf: keyConfig("RS256", "rsa", rsaPriv, rsaPriv)},
+		{name: "missing file", conf: keyConfig("RS256", "rsa", config.TokenKey{KeyID: "rsa", PublicKey: filepath.Join(dir, "none.pem")})},
+		{name: "no private key", conf: keyConfig("RS256", "rsa", writeKey(t, dir, "rsa", rsaKey, false))},
+		{name: "key does not match method", conf: keyConfig("ES256", "rsa", rsaPriv)},
+		{name: "retired secret with HS256", conf: func() *config.GlobalConfig {
+			conf := keyConfig("HS256", "")
+			conf.TokenPolicy.SecretRetired = true
+			return conf
+		}()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadKeySet(tt.conf)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	dir := t.TempDir()
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	secretConf := keyConfig("HS256", "")
+	secretToken, err := SignToken(secretConf, testClaims())
+	assert.NoError(t, err)
+	oldConf := keyConfig("RS256", "old", writeKey(t, dir, "old", oldKey, true))
+	oldToken, err := SignToken(oldConf, testClaims())
+	assert.NoError(t, err)
+
+	// The old key is kept without its private key, new tokens are signed with the new one.
+	rotated := keyConfig("ES256", "new", writeKey(t, dir, "old", oldKey, false), writeKey(t, dir, "new", newKey, true))
+	newToken, err := SignToken(rotated, testClaims())
+	assert.NoError(t, err)
+	for _, token := range []string{secretToken, oldToken, newToken} {
+		claims, err := ParseClaims(token, rotated)
+		assert.NoError(t, err)
+		assert.Equal(t, "u1", claims.UserID)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &Claims{})
+	assert.NoError(t, err)
+	assert.Equal(t, "new", parsed.Header[keyIDHeader])
+	assert.Equal(t, "ES256", parsed.Method.Alg())
+
+	retired := keyConfig("ES256", "new", writeKey(t, dir, "old", oldKey, false), writeKey(t, dir, "new", newKey, true))
+	retired.TokenPolicy.SecretRetired = true
+	_, err = ParseClaims(secretToken, retired)
+	assert.Error(t, err, "tokens signed with a retired secret are rejected")
+	_, err = ParseClaims(oldToken, retired)
+	assert.NoError(t, err)
+	_, err = ParseClaims(newToken, retired)
+	assert.NoError(t, err)
+}
+
+func TestKeyFileReload(t *testing.T) {
+	dir := t.TempDir()
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	conf := keyConfig("RS256", "k1", writeKey(t, dir, "k1", oldKey, true))
+	expire := func() {
+		v, ok := keySets.Load(policyKey(conf))
+		assert.True(t, ok)
+		e := v.(*keySetEntry)
+		keySets.Store(policyKey(conf), &keySetEntry{ks: e.ks, files: e.files})
+	}
+	oldToken, err := SignToken(conf, testClaims())
+	assert.NoError(t, err)
+
+	// The key files are replaced in place, the keys are reloaded once the check interval passed.
+	key := writeKey(t, dir, "k1", newKey, true)
+	later := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(key.PublicKey, later, later))
+	assert.NoError(t, os.Chtimes(key.PrivateKey, later, later))
+	_, err = ParseClaims(oldToken, conf)
+	assert.NoError(t, err, "keys are not checked again within the interval")
+	expire()
+	newToken, err := SignToken(conf, testClaims())
+	assert.NoError(t, err)
+	_, err = ParseClaims(newToken, conf)
+	assert.NoError(t, err)
+	_, err = ParseClaims(oldToken, conf)
+	assert.Error(t, err)
+
+	// A broken key file keeps the loaded keys.
+	assert.NoError(t, os.WriteFile(key.PublicKey, []byte("broken"), 0o600))
+	expire()
+	_, err = ParseClaims(newToken, conf)
+	assert.NoError(t, err)
+}
+
+func TestKeyfuncMismatch(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	conf := keyConfig("RS256", "rsa", writeKey(t, dir, "rsa", rsaKey, true), writeKey(t, dir, "ec", ecKey, true))
+
+	sign := func(method jwt.SigningMethod, kid string, key any) string {
+		token := jwt.NewWithClaims(method, testClaims())
+		if kid != "" {
+			token.Header[keyIDHeader] = kid
+		}
+		s, err := token.SignedString(key)
+		assert.NoError(t, err)
+		return s
+	}
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "alg does not match kid", token: sign(jwt.SigningMethodES256, "rsa", ecKey)},
+		{name: "hmac with kid", token: sign(jwt.SigningMethodHS256, "rsa", []byte("key-secret"))},
+		{name: "unknown kid", token: sign(jwt.SigningMethodRS256, "other", rsaKey)},
+		{name: "asymmetric without kid", token: sign(jwt.SigningMethodRS256, "", rsaKey)},
+		{name: "signed by another key", token: sign(jwt.SigningMethodES256, "ec", func() *ecdsa.PrivateKey {
+			k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			assert.NoError(t, err)
+			return k
+		}())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseClaims(tt.token, conf)
+			assert.Error(t, err)
+		})
+	}
+	_, err = ParseClaims(sign(jwt.SigningMethodES256, "ec", ecKey), conf)
+	assert.NoError(t, err)
+}
+
+func TestPublicKeys(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	conf := keyConfig("RS256", "rsa", writeKey(t, dir, "rsa", rsaKey, true), writeKey(t, dir, "ec", ecKey, false))
+
+	keys, err := PublicKeys(conf)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	byID := make(map[string]*JSONWebKey)
+	for _, key := range keys {
+		byID[key.Kid] = key
+		assert.Equal(t, "sig", key.Use)
+	}
+	decode := func(s string) *big.Int {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		assert.NoError(t, err)
+		return new(big.Int).SetBytes(b)
+	}
+	r := byID["rsa"]
+	assert.Equal(t, "RSA", r.Kty)
+	assert.Equal(t, "RS256", r.Alg)
+	assert.Equal(t, rsaKey.N, decode(r.N))
+	assert.Equal(t, int64(rsaKey.E), decode(r.E).Int64())
+	e := byID["ec"]
+	assert.Equal(t, "EC", e.Kty)
+	assert.Equal(t, "ES256", e.Alg)
+	assert.Equal(t, "P-256", e.Crv)
+	assert.Equal(t, ecKey.X, decode(e.X))
+	assert.Equal(t, ecKey.Y, decode(e.Y))
+	assert.Len(t, e.X, 43, "coordinates are padded to the curve size")
+}
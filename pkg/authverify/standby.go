@@ -0,0 +1,68 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authverify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/redis/go-redis/v9"
+)
+
+// StandbyVerify decides whether a token is accepted although its status could not be read from the token cache.
+// With the standby policy enabled and the cache unreachable, a token passing the signature and expiry checks is
+// accepted while it is younger than TokenPolicy.Standby.MaxAge hours, so a redis outage does not log out every user.
+// Revocation is not enforced in standby: the kicked and logged out tokens live in the cache, so they are accepted
+// like any other until the cache is back. Every accepted token is logged as an error for alerting.
+func StandbyVerify(ctx context.Context, token string, cacheErr error, config *config.GlobalConfig) error {
+	if cacheErr == nil || !config.TokenPolicy.Standby.Enable {
+		return cacheErr
+	}
+	// Only failures to reach the cache fall back, not the errors of checks made on what it returned.
+	if cause := errs.Unwrap(cacheErr); cause == redis.Nil {
+		return cacheErr
+	} else if _, ok := cause.(errs.CodeError); ok {
+		return cacheErr
+	}
+	claims, err := ParseClaims(token, config)
+	if err != nil {
+		return err
+	}
+	maxAge := time.Duration(config.TokenPolicy.Standby.MaxAge) * time.Hour
+	if claims.IssuedAt == nil || time.Since(claims.IssuedAt.Time) > maxAge {
+		prommetrics.TokenStandbyCounter.WithLabelValues("rejected").Inc()
+		log.ZWarn(ctx, "token cache unreachable, token too old for standby", cacheErr, "userID", claims.UserID,
+			"platformID", claims.PlatformID)
+		return cacheErr
+	}
+	prommetrics.TokenStandbyCounter.WithLabelValues("accepted").Inc()
+	log.ZError(ctx, "token cache unreachable, token accepted by signature", cacheErr, "userID", claims.UserID,
+		"platformID", claims.PlatformID)
+	return nil
+}
+
+// CheckStandbyPolicy rejects an enabled standby policy whose MaxAge would refuse every token, the services call it
+// when they start.
+func CheckStandbyPolicy(config *config.GlobalConfig) error {
+	if standby := config.TokenPolicy.Standby; standby.Enable && standby.MaxAge <= 0 {
+		return errs.Wrap(fmt.Errorf("tokenPolicy.standby.maxAge must be positive when standby is enabled, got %d", standby.MaxAge))
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authverify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/tokenverify"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func standbyConfig(maxAge int64) *config.GlobalConfig {
+	conf := config.NewGlobalConfig()
+	conf.Secret = "standby-secret"
+	conf.TokenPolicy.Standby.Enable = true
+	conf.TokenPolicy.Standby.MaxAge = maxAge
+	return conf
+}
+
+// issuedToken signs a token issued age ago that expires in a day.
+func issuedToken(t *testing.T, conf *config.GlobalConfig, age time.Duration) string {
+	now := time.Now()
+	token, err := SignToken(conf, &Claims{Claims: tokenverify.Claims{
+		UserID:     "u1",
+		PlatformID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now.Add(-age)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(24 * time.Hour)),
+		},
+	}})
+	assert.NoError(t, err)
+	return token
+}
+
+func TestStandbyVerify(t *testing.T) {
+	ctx := context.Background()
+	unreachable := errs.Wrap(errors.New("dial tcp: connection refused"))
+	conf := standbyConfig(2)
+
+	assert.NoError(t, StandbyVerify(ctx, "", nil, conf))
+	// Answers of the cache are not overridden.
+	assert.Equal(t, errs.Wrap(redis.Nil).Error(), StandbyVerify(ctx, issuedToken(t, conf, 0), errs.Wrap(redis.Nil), conf).Error())
+	kicked := errs.ErrTokenKicked.Wrap()
+	assert.Equal(t, kicked, StandbyVerify(ctx, issuedToken(t, conf, 0), kicked, conf))
+	assert.Error(t, StandbyVerify(ctx, "not a token", unreachable, conf))
+
+	disabled := standbyConfig(2)
+	disabled.TokenPolicy.Standby.Enable = false
+	assert.Equal(t, unreachable, StandbyVerify(ctx, issuedToken(t, disabled, 0), unreachable, disabled))
+}
+
+func TestStandbyVerifyMaxAge(t *testing.T) {
+	ctx := context.Background()
+	unreachable := errs.Wrap(errors.New("dial tcp: connection refused"))
+	tests := []struct {
+		name     string
+		maxAge   int64
+		age      time.Duration
+		accepted bool
+	}{
+		{name: "fresh", maxAge: 2, age: 0, accepted: true},
+		{name: "just under max age", maxAge: 2, age: 2*time.Hour - time.Minute, accepted: true},
+		{name: "just over max age", maxAge: 2, age: 2*time.Hour + time.Minute},
+		{name: "zero max age", maxAge: 0, age: 0},
+		{name: "negative max age", maxAge: -1, age: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := standbyConfig(tt.maxAge)
+			err := StandbyVerify(ctx, issuedToken(t, conf, tt.age), unreachable, conf)
+			if tt.accepted {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, unreachable, err)
+			}
+		})
+	}
+
+	conf := standbyConfig(2)
+	token, err := SignToken(conf, &Claims{Claims: tokenverify.Claims{UserID: "u1", RegisteredClaims: jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}}})
+	assert.NoError(t, err)
+	assert.Equal(t, unreachable, StandbyVerify(ctx, token, unreachable, conf), "a token without iat has no age")
+}
+
+func TestCheckStandbyPolicy(t *testing.T) {
+	assert.NoError(t, CheckStandbyPolicy(standbyConfig(1)))
+	assert.Error(t, CheckStandbyPolicy(standbyConfig(0)))
+	assert.Error(t, CheckStandbyPolicy(standbyConfig(-1)))
+	disabled := standbyConfig(0)
+	disabled.TokenPolicy.Standby.Enable = false
+	assert.NoError(t, CheckStandbyPolicy(disabled))
+}
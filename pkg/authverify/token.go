@@ -24,8 +24,27 @@ import (
 	"github.com/OpenIMSDK/tools/utils"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
 )
 
+// Claims are the token claims with the app the token was issued for, empty for the default tenant.
+type Claims struct {
+	tokenverify.Claims
+	AppID string `json:"appID,omitempty"`
+}
+
+// ParseClaims verifies token and returns its claims.
+func ParseClaims(token string, config *config.GlobalConfig) (*Claims, error) {
+	if _, err := tokenverify.GetClaimFromToken(token, Keyfunc(config)); err != nil {
+		return nil, err
+	}
+	claims := &Claims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return nil, errs.ErrTokenMalformed.Wrap(err.Error())
+	}
+	return claims, nil
+}
+
 func Secret(secret string) jwt.Keyfunc {
 	return func(token *jwt.Token) (any, error) {
 		return []byte(secret), nil
@@ -43,12 +62,16 @@ func CheckAccessV3(ctx context.Context, ownerUserID string, config *config.Globa
 	if opUserID == ownerUserID {
 		return nil
 	}
+	if tenant.ManagesUser(ctx, opUserID, ownerUserID) {
+		return nil
+	}
 	return errs.ErrNoPermission.Wrap("ownerUserID", ownerUserID)
 }
 
 func IsAppManagerUid(ctx context.Context, config *config.GlobalConfig) bool {
 	return (len(config.Manager.UserID) > 0 && utils.IsContain(mcontext.GetOpUserID(ctx), config.Manager.UserID)) ||
-		utils.IsContain(mcontext.GetOpUserID(ctx), config.IMAdmin.UserID)
+		utils.IsContain(mcontext.GetOpUserID(ctx), config.IMAdmin.UserID) ||
+		tenant.IsAppAdmin(ctx, mcontext.GetOpUserID(ctx))
 }
 
 func CheckAdmin(ctx context.Context, config *config.GlobalConfig) error {
@@ -58,8 +81,13 @@ func CheckAdmin(ctx context.Context, config *config.GlobalConfig) error {
 	if utils.IsContain(mcontext.GetOpUserID(ctx), config.IMAdmin.UserID) {
 		return nil
 	}
+	if tenant.IsAppAdmin(ctx, mcontext.GetOpUserID(ctx)) {
+		return nil
+	}
 	return errs.ErrNoPermission.Wrap(fmt.Sprintf("user %s is not admin userID", mcontext.GetOpUserID(ctx)))
 }
+
+// CheckIMAdmin only accepts the admins of the cluster, not the managers of an app.
 func CheckIMAdmin(ctx context.Context, config *config.GlobalConfig) error {
 	if utils.IsContain(mcontext.GetOpUserID(ctx), config.IMAdmin.UserID) {
 		return nil
@@ -70,19 +98,22 @@ func CheckIMAdmin(ctx context.Context, config *config.GlobalConfig) error {
 	return errs.ErrNoPermission.Wrap(fmt.Sprintf("user %s is not CheckIMAdmin userID", mcontext.GetOpUserID(ctx)))
 }
 
-func ParseRedisInterfaceToken(redisToken any, secret string) (*tokenverify.Claims, error) {
-	return tokenverify.GetClaimFromToken(string(redisToken.([]uint8)), Secret(secret))
+func ParseRedisInterfaceToken(redisToken any, config *config.GlobalConfig) (*tokenverify.Claims, error) {
+	return tokenverify.GetClaimFromToken(string(redisToken.([]uint8)), Keyfunc(config))
 }
 
 func IsManagerUserID(opUserID string, config *config.GlobalConfig) bool {
 	return (len(config.Manager.UserID) > 0 && utils.IsContain(opUserID, config.Manager.UserID)) || utils.IsContain(opUserID, config.IMAdmin.UserID)
 }
 
-func WsVerifyToken(token, userID, secret string, platformID int) error {
-	claim, err := tokenverify.GetClaimFromToken(token, Secret(secret))
+func WsVerifyToken(token, userID string, platformID int, appID string, config *config.GlobalConfig) error {
+	claim, err := ParseClaims(token, config)
 	if err != nil {
 		return err
 	}
+	if claim.AppID != appID {
+		return errs.ErrTokenInvalid.Wrap(fmt.Sprintf("token app %q != app %q", claim.AppID, appID))
+	}
 	if claim.UserID != userID {
 		return errs.ErrTokenInvalid.Wrap(fmt.Sprintf("token uid %s != userID %s", claim.UserID, userID))
 	}
@@ -0,0 +1,120 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package callbackgrpc carries callbacks over gRPC instead of HTTP. The business backend implements the Callback
+// service of callback.proto, the values exchanged are the JSON bodies of the HTTP callbacks.
+package callbackgrpc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	ServiceName = "openim.callback.Callback"
+	// CommandKey is the metadata key of the callback command.
+	CommandKey = "openim-callback-command"
+	// OperationIDKey is the metadata key of the operation id.
+	OperationIDKey = "operationid"
+
+	callMethod = "/" + ServiceName + "/Call"
+)
+
+// conns are the connections to the callback backends, kept open so that a callback does not pay for a dial.
+var conns = struct {
+	lock sync.Mutex
+	m    map[string]*grpc.ClientConn
+}{m: make(map[string]*grpc.ClientConn)}
+
+func getConn(addr string) (*grpc.ClientConn, error) {
+	conns.lock.Lock()
+	defer conns.lock.Unlock()
+	if conn, ok := conns.m[addr]; ok {
+		return conn, nil
+	}
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, errs.Wrap(err, "dial callback backend "+addr)
+	}
+	conns.m[addr] = conn
+	return conn, nil
+}
+
+// Call sends the JSON body of command to the backend at addr and returns the JSON body it answers, header is
+// sent as metadata.
+func Call(ctx context.Context, addr string, command string, header map[string]string, body []byte) ([]byte, error) {
+	conn, err := getConn(addr)
+	if err != nil {
+		return nil, err
+	}
+	md := metadata.New(header)
+	md.Set(CommandKey, command)
+	if operationID := mcontext.GetOperationID(ctx); operationID != "" {
+		md.Set(OperationIDKey, operationID)
+	}
+	var resp wrapperspb.BytesValue
+	if err := conn.Invoke(metadata.NewOutgoingContext(ctx, md), callMethod, wrapperspb.Bytes(body), &resp); err != nil {
+		return nil, errs.Wrap(err, "callback grpc call failed")
+	}
+	return resp.Value, nil
+}
+
+// Handler is the Callback service of a backend written in go.
+type Handler interface {
+	Call(ctx context.Context, command string, body []byte) ([]byte, error)
+}
+
+// Register serves h as the Callback service of s.
+func Register(s *grpc.Server, h Handler) {
+	s.RegisterService(&serviceDesc, h)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*Handler)(nil),
+	Methods:     []grpc.MethodDesc{{MethodName: "Call", Handler: callHandler}},
+	Streams:     []grpc.StreamDesc{},
+	Metadata:    "callback.proto",
+}
+
+func callHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(wrapperspb.BytesValue)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	handle := func(ctx context.Context, req any) (any, error) {
+		var command string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(CommandKey); len(values) > 0 {
+				command = values[0]
+			}
+		}
+		out, err := srv.(Handler).Call(ctx, command, req.(*wrapperspb.BytesValue).Value)
+		if err != nil {
+			return nil, err
+		}
+		return wrapperspb.Bytes(out), nil
+	}
+	if interceptor == nil {
+		return handle(ctx, in)
+	}
+	return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: callMethod}, handle)
+}
@@ -0,0 +1,46 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package callbackgrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type echoHandler struct{}
+
+func (echoHandler) Call(ctx context.Context, command string, body []byte) ([]byte, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return []byte(command + ":" + md.Get("x-openim-nonce")[0] + ":" + string(body)), nil
+}
+
+func TestCall(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	s := grpc.NewServer()
+	Register(s, echoHandler{})
+	go s.Serve(lis)
+	defer s.Stop()
+
+	resp, err := Call(context.Background(), lis.Addr().String(), "callbackBeforeSendSingleMsgCommand",
+		map[string]string{"X-OpenIM-Nonce": "n"}, []byte(`{"sendID":"u1"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `callbackBeforeSendSingleMsgCommand:n:{"sendID":"u1"}`, string(resp))
+}
@@ -27,7 +27,12 @@ const CallbackBeforeAddFriendAgreeCommand = "callbackBeforeAddFriendAgreeCommand
 const CallbackAfterDeleteFriendCommand = "callbackAfterDeleteFriendCommand"
 const CallbackBeforeImportFriendsCommand = "callbackBeforeImportFriendsCommand"
 const CallbackAfterImportFriendsCommand = "callbackAfterImportFriendsCommand"
+const CallbackImportFriendsProgressCommand = "callbackImportFriendsProgressCommand"
 const CallbackAfterRemoveBlackCommand = "callbackAfterRemoveBlackCommand"
+const CallbackAddFriendPrivacyCommand = "callbackAddFriendPrivacyCommand"
+const CallbackSearchUserPrivacyCommand = "callbackSearchUserPrivacyCommand"
+const CallbackAfterDeactivateUserCommand = "callbackAfterDeactivateUserCommand"
+const CallbackAfterDeleteAccountCommand = "callbackAfterDeleteAccountCommand"
 
 const (
 	CallbackQuitGroupCommand                = "callbackQuitGroupCommand"
@@ -117,6 +117,19 @@ type CallbackAfterImportFriendsResp struct {
 	CommonCallbackResp
 }
 
+// CallbackImportFriendsProgressReq is sent after every batch of a bulk friend import.
+type CallbackImportFriendsProgressReq struct {
+	CallbackCommand `json:"callbackCommand"`
+	OwnerUserID     string `json:"ownerUserID"`
+	DryRun          bool   `json:"dryRun"`
+	Total           int    `json:"total"`
+	Processed       int    `json:"processed"`
+	Imported        int    `json:"imported"`
+}
+type CallbackImportFriendsProgressResp struct {
+	CommonCallbackResp
+}
+
 type CallbackAfterRemoveBlackReq struct {
 	CallbackCommand `json:"callbackCommand"`
 	OwnerUserID     string `json:"ownerUserID"`
@@ -125,3 +138,17 @@ type CallbackAfterRemoveBlackReq struct {
 type CallbackAfterRemoveBlackResp struct {
 	CommonCallbackResp
 }
+
+// CallbackAddFriendPrivacyReq lets the business server decide whether FromUserID may send ToUserID a friend
+// request, Allowed is the decision of the built-in AddFriendPolicy.
+type CallbackAddFriendPrivacyReq struct {
+	CallbackCommand `json:"callbackCommand"`
+	FromUserID      string `json:"fromUserID"`
+	ToUserID        string `json:"toUserID"`
+	AddFriendPolicy int32  `json:"addFriendPolicy"`
+	Allowed         bool   `json:"allowed"`
+}
+type CallbackAddFriendPrivacyResp struct {
+	CommonCallbackResp
+	Allowed *bool `json:"allowed"`
+}
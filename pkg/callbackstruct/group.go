@@ -233,3 +233,20 @@ type CallbackAfterSetGroupInfoReq struct {
 type CallbackAfterSetGroupInfoResp struct {
 	CommonCallbackResp
 }
+
+// GroupMemberWebhookReq is the body posted to the group member webhook. Events may be delivered more than once and,
+// after retries, out of order: receivers dedupe on EventID and order by EventTime.
+type GroupMemberWebhookReq struct {
+	Events []*GroupMemberWebhookEvent `json:"events"`
+}
+
+type GroupMemberWebhookEvent struct {
+	EventID        string   `json:"eventID"`
+	AppID          string   `json:"appID,omitempty"`
+	GroupID        string   `json:"groupID"`
+	Event          string   `json:"event"`
+	UserIDs        []string `json:"userIDs"`
+	OperatorUserID string   `json:"operatorUserID"`
+	RoleLevel      int32    `json:"roleLevel,omitempty"`
+	EventTime      int64    `json:"eventTime"`
+}
@@ -89,3 +89,41 @@ type CallbackAfterUserRegisterReq struct {
 type CallbackAfterUserRegisterResp struct {
 	CommonCallbackResp
 }
+
+// CallbackSearchUserPrivacyReq is sent when a user searches others by userID or nickname, HiddenUserIDs are the
+// users in UserIDs the built-in privacy settings hide from the search.
+type CallbackSearchUserPrivacyReq struct {
+	CallbackCommand `json:"callbackCommand"`
+	FromUserID      string   `json:"fromUserID"`
+	UserID          string   `json:"userID"`
+	NickName        string   `json:"nickName"`
+	UserIDs         []string `json:"userIDs"`
+	HiddenUserIDs   []string `json:"hiddenUserIDs"`
+}
+type CallbackSearchUserPrivacyResp struct {
+	CommonCallbackResp
+	HiddenUserIDs *[]string `json:"hiddenUserIDs"`
+}
+
+type CallbackAfterDeactivateUserReq struct {
+	CallbackCommand `json:"callbackCommand"`
+	UserID          string `json:"userID"`
+	OperatorUserID  string `json:"operatorUserID"`
+	Reason          string `json:"reason"`
+}
+type CallbackAfterDeactivateUserResp struct {
+	CommonCallbackResp
+}
+
+// CallbackAfterDeleteAccountReq is sent when the deletion of an account is requested, DeleteTime (ms) is when
+// its data is purged.
+type CallbackAfterDeleteAccountReq struct {
+	CallbackCommand `json:"callbackCommand"`
+	UserID          string `json:"userID"`
+	OperatorUserID  string `json:"operatorUserID"`
+	Reason          string `json:"reason"`
+	DeleteTime      int64  `json:"deleteTime"`
+}
+type CallbackAfterDeleteAccountResp struct {
+	CommonCallbackResp
+}
@@ -0,0 +1,66 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/http"
+)
+
+// Archiver stores messages in an external write-once (WORM) archive.
+type Archiver interface {
+	// Archive sends a batch of messages of one conversation, ordered by seq, and returns the
+	// highest seq the archive acknowledged as durably stored.
+	Archive(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) (ackSeq int64, err error)
+}
+
+type archiveReq struct {
+	ConversationID string           `json:"conversationID"`
+	Msgs           []*sdkws.MsgData `json:"msgs"`
+}
+
+type archiveResp struct {
+	AckSeq int64 `json:"ackSeq"`
+}
+
+// NewHttpArchiver returns an Archiver posting batches as JSON to the configured endpoint.
+func NewHttpArchiver(config *config.GlobalConfig) (Archiver, error) {
+	if config.Archive.Endpoint == "" {
+		return nil, errs.ErrArgs.Wrap("archive endpoint is empty")
+	}
+	header := make(map[string]string)
+	if config.Archive.Token != "" {
+		header["Authorization"] = "Bearer " + config.Archive.Token
+	}
+	return &httpArchiver{endpoint: config.Archive.Endpoint, header: header, timeout: config.Archive.Timeout}, nil
+}
+
+type httpArchiver struct {
+	endpoint string
+	header   map[string]string
+	timeout  int
+}
+
+func (h *httpArchiver) Archive(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) (int64, error) {
+	var resp archiveResp
+	if err := http.PostReturn(ctx, h.endpoint, h.header, &archiveReq{ConversationID: conversationID, Msgs: msgs}, &resp, h.timeout); err != nil {
+		return 0, err
+	}
+	return resp.AckSeq, nil
+}
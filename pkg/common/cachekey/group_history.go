@@ -0,0 +1,30 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cachekey
+
+import "strconv"
+
+const (
+	GroupHistoryVisibilityKey = "GROUP_HISTORY_VISIBILITY:"
+	GroupHistoryMinSeqKey     = "GROUP_HISTORY_MIN_SEQ:"
+)
+
+func GetGroupHistoryVisibilityKey(groupID string) string {
+	return GroupHistoryVisibilityKey + groupID
+}
+
+func GetGroupHistoryMinSeqKey(conversationID string, since int64) string {
+	return GroupHistoryMinSeqKey + conversationID + ":" + strconv.FormatInt(since, 10)
+}
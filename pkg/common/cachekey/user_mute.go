@@ -0,0 +1,33 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cachekey
+
+const (
+	UserMuteIDsKey      = "USER_MUTE_IDS:"
+	UserMuteOwnerIDsKey = "USER_MUTE_OWNER_IDS:"
+	MutedSeqsKey        = "MUTED_SEQS:"
+)
+
+func GetUserMuteIDsKey(ownerUserID string) string {
+	return UserMuteIDsKey + ownerUserID
+}
+
+func GetUserMuteOwnerIDsKey(muteUserID string) string {
+	return UserMuteOwnerIDsKey + muteUserID
+}
+
+func GetMutedSeqsKey(userID string, conversationID string) string {
+	return MutedSeqsKey + userID + ":" + conversationID
+}
@@ -15,6 +15,7 @@
 package cmd
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/OpenIMSDK/protocol/constant"
@@ -54,6 +55,45 @@ func (m *MsgGatewayCmd) addRunE() {
 	}
 }
 
+// AddConformanceVerifyCmd adds the conformance-verify command, which replays the golden WS transcripts against
+// a gateway running in conformance mode. It reads no config.
+func (m *MsgGatewayCmd) AddConformanceVerifyCmd() {
+	verifyCmd := &cobra.Command{
+		Use:   "conformance-verify",
+		Short: "Replay golden WS transcripts against a msg gateway in conformance mode",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			addr, _ := cmd.Flags().GetString("addr")
+			dir, _ := cmd.Flags().GetString("dir")
+			transcripts, err := msggateway.LoadTranscripts(dir)
+			if err != nil {
+				return err
+			}
+			if len(transcripts) == 0 {
+				return fmt.Errorf("no transcript in %s", dir)
+			}
+			var failed int
+			for _, t := range transcripts {
+				if err := msggateway.VerifyTranscript(addr, t); err != nil {
+					fmt.Printf("FAIL %s: %v\n", t.Name, err)
+					failed++
+					continue
+				}
+				fmt.Printf("ok   %s\n", t.Name)
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d transcripts failed", failed, len(transcripts))
+			}
+			return nil
+		},
+	}
+	verifyCmd.Flags().String("addr", "ws://127.0.0.1:10001", "address of the msg gateway in conformance mode")
+	verifyCmd.Flags().String("dir", "test/e2e/conformance/ws", "directory of the golden transcripts")
+	m.AddCommand(verifyCmd)
+}
+
 func (m *MsgGatewayCmd) Exec() error {
 	return m.Execute()
 }
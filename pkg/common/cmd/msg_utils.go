@@ -15,7 +15,18 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/openimsdk/open-im-server/v3/internal/msgtransfer"
 	"github.com/openimsdk/open-im-server/v3/internal/tools"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	util "github.com/openimsdk/open-im-server/v3/pkg/util/genutil"
 	"github.com/spf13/cobra"
 )
@@ -183,3 +194,84 @@ func (m *MsgCmd) GetMsgCmd() *cobra.Command {
 func (m *MsgCmd) ClearMsgCmd() *cobra.Command {
 	return &m.Command
 }
+
+type SeqGapCmd struct {
+	*MsgUtilsCmd
+}
+
+func NewSeqGapCmd() *SeqGapCmd {
+	seqGapCmd := &SeqGapCmd{
+		NewMsgUtilsCmd("seqgap", "find the seqs of a conversation with no message and repair them", nil),
+	}
+	seqGapCmd.Command.Flags().StringP(constant.FlagConf, "c", "", "path to config file folder")
+	seqGapCmd.Command.Flags().String("conversationID", "", "openIM conversationID")
+	seqGapCmd.Command.Flags().String("repair", "", "repair the gaps, placeholder or compact")
+	return seqGapCmd
+}
+
+func (s *SeqGapCmd) RunCmd() *cobra.Command {
+	s.Command.RunE = func(cmdLines *cobra.Command, args []string) error {
+		configFolderPath, _ := cmdLines.Flags().GetString(constant.FlagConf)
+		conversationID, _ := cmdLines.Flags().GetString("conversationID")
+		mode, _ := cmdLines.Flags().GetString("repair")
+		if conversationID == "" {
+			return errs.ErrArgs.Wrap("conversationID is empty")
+		}
+		conf := config.NewGlobalConfig()
+		if err := config.InitConfig(conf, configFolderPath); err != nil {
+			return err
+		}
+		seqGapTool, err := tools.InitSeqGapTool(conf)
+		if err != nil {
+			return err
+		}
+		ctx := mcontext.NewCtx("seqgap")
+		if mode != "" {
+			repaired, err := seqGapTool.Repair(ctx, conversationID, mode)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("repaired %d seqs with %s\n", repaired, mode)
+		}
+		report, err := seqGapTool.FindGaps(ctx, conversationID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("conversation %s minSeq %d maxSeq %d missing %d\n", conversationID, report.MinSeq, report.MaxSeq, report.Missing)
+		for _, gap := range report.Gaps {
+			fmt.Printf("gap %d-%d\n", gap.Begin, gap.End)
+		}
+		return nil
+	}
+	return &s.Command
+}
+
+type DeadLetterReplayCmd struct {
+	*MsgUtilsCmd
+}
+
+func NewDeadLetterReplayCmd() *DeadLetterReplayCmd {
+	replayCmd := &DeadLetterReplayCmd{
+		NewMsgUtilsCmd("replaydlq", "send the msgs dead-lettered by the mongo consumer of msgtransfer back to it", nil),
+	}
+	replayCmd.Command.Flags().StringP(constant.FlagConf, "c", "", "path to config file folder")
+	replayCmd.Command.Flags().Int("idle", 10, "seconds without a dead letter after which the replay stops")
+	return replayCmd
+}
+
+func (r *DeadLetterReplayCmd) RunCmd() *cobra.Command {
+	r.Command.RunE = func(cmdLines *cobra.Command, args []string) error {
+		configFolderPath, _ := cmdLines.Flags().GetString(constant.FlagConf)
+		idle, _ := cmdLines.Flags().GetInt("idle")
+		conf := config.NewGlobalConfig()
+		if err := config.InitConfig(conf, configFolderPath); err != nil {
+			return err
+		}
+		ctx, cancel := signal.NotifyContext(mcontext.NewCtx("replaydlq"), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+		replayed, skipped, err := msgtransfer.ReplayMongoDeadLetters(ctx, conf, time.Duration(idle)*time.Second)
+		fmt.Printf("replayed %d dead letters, skipped %d\n", replayed, skipped)
+		return err
+	}
+	return &r.Command
+}
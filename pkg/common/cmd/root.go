@@ -19,9 +19,11 @@ import (
 
 	"github.com/OpenIMSDK/protocol/constant"
 	"github.com/OpenIMSDK/tools/errs"
-	"github.com/OpenIMSDK/tools/log"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	config2 "github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/http"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/loglevel"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/throttle"
 	"github.com/spf13/cobra"
 )
 
@@ -84,6 +86,9 @@ func (rc *RootCmd) persistentPreRun(cmd *cobra.Command, opts ...func(*CmdOpts))
 		return errs.Wrap(err, "failed to initialize logger")
 	}
 
+	throttle.Init(rc.config)
+	http.InitCallback(rc.config)
+
 	return nil
 }
 
@@ -101,19 +106,7 @@ func (rc *RootCmd) applyOptions(opts ...func(*CmdOpts)) *CmdOpts {
 }
 
 func (rc *RootCmd) initializeLogger(cmdOpts *CmdOpts) error {
-	logConfig := rc.config.Log
-
-	return log.InitFromConfig(
-
-		cmdOpts.loggerPrefixName,
-		rc.Name,
-		logConfig.RemainLogLevel,
-		logConfig.IsStdout,
-		logConfig.IsJson,
-		logConfig.StorageLocation,
-		logConfig.RemainRotationCount,
-		logConfig.RotationTime,
-	)
+	return loglevel.Init(cmdOpts.loggerPrefixName, rc.Name, rc.config)
 }
 
 func defaultCmdOpts() *CmdOpts {
@@ -27,9 +27,12 @@ var Config GlobalConfig
 const ConfKey = "conf"
 
 type CallBackConfig struct {
-	Enable                 bool  `yaml:"enable"`
-	CallbackTimeOut        int   `yaml:"timeout"`
-	CallbackFailedContinue *bool `yaml:"failedContinue"`
+	Enable                 bool   `yaml:"enable"`
+	CallbackTimeOut        int    `yaml:"timeout"`
+	CallbackFailedContinue *bool  `yaml:"failedContinue"`
+	Secret                 string `yaml:"secret"`
+	// Transport is http or grpc, http when empty.
+	Transport string `yaml:"transport"`
 }
 
 type NotificationConf struct {
@@ -46,6 +49,27 @@ type POfflinePush struct {
 	Ext    string `yaml:"ext"`
 }
 
+// TokenKey is an asymmetric key pair used to sign or verify tokens.
+// PrivateKey may be left empty for keys that are only kept for verification during rotation.
+type TokenKey struct {
+	KeyID      string `yaml:"keyID"`
+	PrivateKey string `yaml:"privateKey"`
+	PublicKey  string `yaml:"publicKey"`
+}
+
+// LoginPolicyGroup limits the sessions a user keeps online across a group of platforms, MaxOnline 0 means unlimited.
+type LoginPolicyGroup struct {
+	Name        string `yaml:"name"        json:"name"`
+	PlatformIDs []int  `yaml:"platformIDs" json:"platformIDs"`
+	MaxOnline   int    `yaml:"maxOnline"   json:"maxOnline"`
+}
+
+// ApnsBundle is an iOS app that receives APNs pushes, Production selects the production or sandbox gateway.
+type ApnsBundle struct {
+	BundleID   string `yaml:"bundleID"`
+	Production bool   `yaml:"production"`
+}
+
 type MYSQL struct {
 	Address       []string `yaml:"address"`
 	Username      string   `yaml:"username"`
@@ -68,6 +92,17 @@ type GlobalConfig struct {
 		Username string   `yaml:"username"`
 		Password string   `yaml:"password"`
 	} `yaml:"zookeeper"`
+	Etcd struct {
+		Schema   string   `yaml:"schema"`
+		Address  []string `yaml:"address"`
+		Username string   `yaml:"username"`
+		Password string   `yaml:"password"`
+	} `yaml:"etcd"`
+	Consul struct {
+		Schema  string `yaml:"schema"`
+		Address string `yaml:"address"`
+		Token   string `yaml:"token"`
+	} `yaml:"consul"`
 
 	Mysql *MYSQL `yaml:"mysql"`
 
@@ -78,8 +113,26 @@ type GlobalConfig struct {
 		Username    string   `yaml:"username"`
 		Password    string   `yaml:"password"`
 		MaxPoolSize int      `yaml:"maxPoolSize"`
+		// ReadPreference applies to the heavy reads only, writes always go to the primary.
+		ReadPreference struct {
+			Mode         string            `yaml:"mode"`
+			MaxStaleness int               `yaml:"maxStaleness"` // second
+			Controllers  map[string]string `yaml:"controllers"`
+		} `yaml:"readPreference"`
 	} `yaml:"mongo"`
 
+	Postgres struct {
+		Enable      bool   `yaml:"enable"`
+		Address     string `yaml:"address"`
+		Database    string `yaml:"database"`
+		Username    string `yaml:"username"`
+		Password    string `yaml:"password"`
+		SSLMode     string `yaml:"sslMode"`
+		MaxOpenConn int    `yaml:"maxOpenConn"`
+		MaxIdleConn int    `yaml:"maxIdleConn"`
+		MaxLifeTime int    `yaml:"maxLifeTime"` // second
+	} `yaml:"postgres"`
+
 	Redis struct {
 		ClusterMode    bool     `yaml:"clusterMode"`
 		Address        []string `yaml:"address"`
@@ -89,12 +142,13 @@ type GlobalConfig struct {
 	} `yaml:"redis"`
 
 	Kafka struct {
-		Username     string   `yaml:"username"`
-		Password     string   `yaml:"password"`
-		ProducerAck  string   `yaml:"producerAck"`
-		CompressType string   `yaml:"compressType"`
-		Addr         []string `yaml:"addr"`
-		TLS          *struct {
+		Username      string   `yaml:"username"`
+		Password      string   `yaml:"password"`
+		SASLMechanism string   `yaml:"saslMechanism"`
+		ProducerAck   string   `yaml:"producerAck"`
+		CompressType  string   `yaml:"compressType"`
+		Addr          []string `yaml:"addr"`
+		TLS           *struct {
 			CACrt              string `yaml:"caCrt"`
 			ClientCrt          string `yaml:"clientCrt"`
 			ClientKey          string `yaml:"clientKey"`
@@ -102,7 +156,8 @@ type GlobalConfig struct {
 			InsecureSkipVerify bool   `yaml:"insecureSkipVerify"`
 		} `yaml:"tls"`
 		LatestMsgToRedis struct {
-			Topic string `yaml:"topic"`
+			Topic             string `yaml:"topic"`
+			HighPriorityTopic string `yaml:"highPriorityTopic"`
 		} `yaml:"latestMsgToRedis"`
 		MsgToMongo struct {
 			Topic string `yaml:"topic"`
@@ -110,13 +165,35 @@ type GlobalConfig struct {
 		MsgToPush struct {
 			Topic string `yaml:"topic"`
 		} `yaml:"msgToPush"`
+		BusinessNotification struct {
+			Topic string `yaml:"topic"`
+		} `yaml:"businessNotification"`
 		ConsumerGroupID struct {
-			MsgToRedis string `yaml:"msgToRedis"`
-			MsgToMongo string `yaml:"msgToMongo"`
-			MsgToMySql string `yaml:"msgToMySql"`
-			MsgToPush  string `yaml:"msgToPush"`
+			MsgToRedis           string `yaml:"msgToRedis"`
+			MsgToMongo           string `yaml:"msgToMongo"`
+			MsgToMySql           string `yaml:"msgToMySql"`
+			MsgToPush            string `yaml:"msgToPush"`
+			BusinessNotification string `yaml:"businessNotification"`
 		} `yaml:"consumerGroupID"`
 	} `yaml:"kafka"`
+	MQ struct {
+		Backend          string `yaml:"backend"`
+		DeadLetterSuffix string `yaml:"deadLetterSuffix"`
+	} `yaml:"mq"`
+	EventExport struct {
+		Enable    bool     `yaml:"enable"`
+		Topic     string   `yaml:"topic"`
+		Events    []string `yaml:"events"`
+		QueueSize int      `yaml:"queueSize"`
+	} `yaml:"eventExport"`
+	Nats struct {
+		Addr     []string `yaml:"addr"`
+		Username string   `yaml:"username"`
+		Password string   `yaml:"password"`
+		Stream   string   `yaml:"stream"`
+		MaxAge   int      `yaml:"maxAge"`
+		AckWait  int      `yaml:"ackWait"`
+	} `yaml:"nats"`
 
 	Rpc struct {
 		RegisterIP string `yaml:"registerIP"`
@@ -126,6 +203,14 @@ type GlobalConfig struct {
 	Api struct {
 		OpenImApiPort []int  `yaml:"openImApiPort"`
 		ListenIP      string `yaml:"listenIP"`
+		Bulkhead      struct {
+			Enable  bool `yaml:"enable"`
+			Msg     int  `yaml:"msg"`
+			Object  int  `yaml:"object"`
+			Group   int  `yaml:"group"`
+			PerUser int  `yaml:"perUser"`
+			Wait    int  `yaml:"wait"`
+		} `yaml:"bulkhead"`
 	} `yaml:"api"`
 
 	Object struct {
@@ -156,6 +241,23 @@ type GlobalConfig struct {
 			SessionToken    string `yaml:"sessionToken"`
 			PublicRead      bool   `yaml:"publicRead"`
 		} `yaml:"oss"`
+		Azure struct {
+			Endpoint    string `yaml:"endpoint"`
+			AccountName string `yaml:"accountName"`
+			AccountKey  string `yaml:"accountKey"`
+			Container   string `yaml:"container"`
+			PublicRead  bool   `yaml:"publicRead"`
+		} `yaml:"azure"`
+		Gcs struct {
+			Endpoint        string `yaml:"endpoint"`
+			Bucket          string `yaml:"bucket"`
+			CredentialsFile string `yaml:"credentialsFile"`
+			PublicRead      bool   `yaml:"publicRead"`
+		} `yaml:"gcs"`
+		Local struct {
+			Dir     string `yaml:"dir"`
+			QuotaMB int64  `yaml:"quotaMB"`
+		} `yaml:"local"`
 		Kodo struct {
 			Endpoint        string `yaml:"endpoint"`
 			Bucket          string `yaml:"bucket"`
@@ -217,11 +319,27 @@ type GlobalConfig struct {
 		WebsocketMaxMsgLen       int   `yaml:"websocketMaxMsgLen"`
 		WebsocketTimeout         int   `yaml:"websocketTimeout"`
 		WebsocketWriteBufferSize int   `yaml:"websocketWriteBufferSize"`
+		MqttEnable               bool  `yaml:"mqttEnable"`
+		OpenImMqttPort           []int `yaml:"openImMqttPort"`
+		SkipMutedBackground      bool  `yaml:"skipMutedBackground"`
+		Conformance              struct {
+			Enable    bool   `yaml:"enable"`
+			RecordDir string `yaml:"recordDir"`
+		} `yaml:"conformance"`
+		PushAck struct {
+			Enable        bool `yaml:"enable"`
+			AckTimeout    int  `yaml:"ackTimeout"`
+			MaxAttempts   int  `yaml:"maxAttempts"`
+			MaxPending    int  `yaml:"maxPending"`
+			RedeliverRate int  `yaml:"redeliverRate"`
+		} `yaml:"pushAck"`
 	} `yaml:"longConnSvr"`
 
 	Push struct {
-		MaxConcurrentWorkers int    `yaml:"maxConcurrentWorkers"`
-		Enable               string `yaml:"enable"`
+		MaxConcurrentWorkers int            `yaml:"maxConcurrentWorkers"`
+		Enable               string         `yaml:"enable"`
+		PlatformProviders    map[int]string `yaml:"platformProviders"`
+		DefaultLanguage      string         `yaml:"defaultLanguage"`
 		GeTui                struct {
 			PushUrl      string `yaml:"pushUrl"`
 			AppKey       string `yaml:"appKey"`
@@ -239,6 +357,17 @@ type GlobalConfig struct {
 			PushUrl      string `yaml:"pushUrl"`
 			PushIntent   string `yaml:"pushIntent"`
 		} `yaml:"jpns"`
+		Apns struct {
+			AuthKey string       `yaml:"authKey"`
+			KeyID   string       `yaml:"keyID"`
+			TeamID  string       `yaml:"teamID"`
+			Bundles []ApnsBundle `yaml:"bundles"`
+		} `yaml:"apns"`
+		Hms struct {
+			AppID        string `yaml:"appID"`
+			ClientSecret string `yaml:"clientSecret"`
+			Intent       string `yaml:"intent"`
+		} `yaml:"hms"`
 	}
 	Manager struct {
 		UserID   []string `yaml:"userID"`
@@ -261,11 +390,273 @@ type GlobalConfig struct {
 	Secret                            string `yaml:"secret"`
 	EnableCronLocker                  bool   `yaml:"enableCronLocker"`
 	TokenPolicy                       struct {
-		Expire int64 `yaml:"expire"`
+		Expire        int64      `yaml:"expire"`
+		SigningMethod string     `yaml:"signingMethod"`
+		SigningKeyID  string     `yaml:"signingKeyID"`
+		Keys          []TokenKey `yaml:"keys"`
+		// SecretRetired rejects the tokens signed with the secret once every client holds a token signed with keys.
+		SecretRetired bool `yaml:"secretRetired"`
+		Fingerprint   struct {
+			Enable bool `yaml:"enable"`
+			Grace  bool `yaml:"grace"`
+		} `yaml:"fingerprint"`
+		// Standby accepts tokens by signature and expiry only while the token cache is unreachable.
+		Standby struct {
+			Enable bool `yaml:"enable"`
+			// MaxAge is the age in hours after which a token is no longer accepted in standby.
+			MaxAge int64 `yaml:"maxAge"`
+		} `yaml:"standby"`
 	} `yaml:"tokenPolicy"`
 	MessageVerify struct {
 		FriendVerify *bool `yaml:"friendVerify"`
 	} `yaml:"messageVerify"`
+	MsgSize struct {
+		Default      int           `yaml:"default"`
+		ContentTypes map[int32]int `yaml:"contentTypes"`
+	} `yaml:"msgSize"`
+	Moderation struct {
+		Enable         bool `yaml:"enable"`
+		ReloadInterval int  `yaml:"reloadInterval"`
+		Reviewer       struct {
+			Url         string `yaml:"url"`
+			Timeout     int    `yaml:"timeout"`
+			Secret      string `yaml:"secret"`
+			Concurrency int    `yaml:"concurrency"`
+		} `yaml:"reviewer"`
+		Scoring struct {
+			Enable      bool     `yaml:"enable"`
+			Scorers     []string `yaml:"scorers"`
+			Words       []string `yaml:"words"`
+			Url         string   `yaml:"url"`
+			Timeout     int      `yaml:"timeout"`
+			Secret      string   `yaml:"secret"`
+			Concurrency int      `yaml:"concurrency"`
+			Thresholds  struct {
+				Low    float64 `yaml:"low"`
+				Medium float64 `yaml:"medium"`
+				Strict float64 `yaml:"strict"`
+			} `yaml:"thresholds"`
+			SingleLevel   string `yaml:"singleLevel"`
+			GroupLevel    string `yaml:"groupLevel"`
+			OwnerMinLevel string `yaml:"ownerMinLevel"`
+			OwnerMaxLevel string `yaml:"ownerMaxLevel"`
+		} `yaml:"scoring"`
+	} `yaml:"moderation"`
+	LoginPolicyMatrix struct {
+		Enable bool               `yaml:"enable"`
+		Groups []LoginPolicyGroup `yaml:"groups"`
+	} `yaml:"loginPolicyMatrix"`
+	MsgEdit struct {
+		Enable bool `yaml:"enable"`
+		Window int  `yaml:"window"`
+	} `yaml:"msgEdit"`
+	ClockSkew struct {
+		Enable    bool  `yaml:"enable"`
+		Tolerance int64 `yaml:"tolerance"`
+	} `yaml:"clockSkew"`
+	EphemeralState struct {
+		TypingTTL             int `yaml:"typingTTL"`
+		RecordingTTL          int `yaml:"recordingTTL"`
+		UploadingTTL          int `yaml:"uploadingTTL"`
+		RateLimit             int `yaml:"rateLimit"`
+		ConversationRateLimit int `yaml:"conversationRateLimit"`
+	} `yaml:"ephemeralState"`
+	Search struct {
+		Backend       string `yaml:"backend"`
+		Elasticsearch struct {
+			Address    string `yaml:"address"`
+			Index      string `yaml:"index"`
+			GroupIndex string `yaml:"groupIndex"`
+			Username   string `yaml:"username"`
+			Password   string `yaml:"password"`
+			Timeout    int    `yaml:"timeout"`
+		} `yaml:"elasticsearch"`
+	} `yaml:"search"`
+	BusinessNotification struct {
+		FanoutRate int `yaml:"fanoutRate"`
+	} `yaml:"businessNotification"`
+	MsgPriority struct {
+		BulkRate  int `yaml:"bulkRate"`
+		BulkBurst int `yaml:"bulkBurst"`
+	} `yaml:"msgPriority"`
+	Archive struct {
+		Enable    bool   `yaml:"enable"`
+		Endpoint  string `yaml:"endpoint"`
+		Token     string `yaml:"token"`
+		BatchSize int    `yaml:"batchSize"`
+		Timeout   int    `yaml:"timeout"`
+		CronTime  string `yaml:"cronTime"`
+	} `yaml:"archive"`
+	ScheduledMsg struct {
+		Enable    bool   `yaml:"enable"`
+		MaxAhead  int    `yaml:"maxAhead"`
+		BatchSize int    `yaml:"batchSize"`
+		CronTime  string `yaml:"cronTime"`
+	} `yaml:"scheduledMsg"`
+	UserPurge struct {
+		Enable    bool   `yaml:"enable"`
+		BatchSize int    `yaml:"batchSize"`
+		CronTime  string `yaml:"cronTime"`
+		// GroupSuccession is what happens to the groups a purged user owns: transfer, dismiss or freeze.
+		GroupSuccession string `yaml:"groupSuccession"`
+		// DeleteGracePeriod is the number of seconds between /user/delete_account and the purge of the user.
+		DeleteGracePeriod int64 `yaml:"deleteGracePeriod"`
+	} `yaml:"userPurge"`
+	GroupBatch struct {
+		Enable    bool   `yaml:"enable"`
+		BatchSize int    `yaml:"batchSize"`
+		CronTime  string `yaml:"cronTime"`
+		MaxItems  int    `yaml:"maxItems"`
+	} `yaml:"groupBatch"`
+	Broadcast struct {
+		Enable   bool   `yaml:"enable"`
+		CronTime string `yaml:"cronTime"`
+		// Rate is the number of messages sent per second, PageSize the number of users a job moves on by.
+		Rate     int   `yaml:"rate"`
+		PageSize int32 `yaml:"pageSize"`
+		// MaxRunTime is the number of seconds a cron run sends for, a job still running then resumes on the next run.
+		MaxRunTime int64 `yaml:"maxRunTime"`
+		MaxRecvIDs int   `yaml:"maxRecvIDs"`
+	} `yaml:"broadcast"`
+	GroupMemberWebhook struct {
+		Enable bool   `yaml:"enable"`
+		Url    string `yaml:"url"`
+		Secret string `yaml:"secret"`
+		// GroupIDs and Events limit the webhook to these groups and events, all of them when empty.
+		GroupIDs     []string `yaml:"groupIDs"`
+		Events       []string `yaml:"events"`
+		CronTime     string   `yaml:"cronTime"`
+		BatchSize    int      `yaml:"batchSize"`
+		MaxAttempts  int      `yaml:"maxAttempts"`
+		BaseInterval int      `yaml:"baseInterval"`
+		MaxInterval  int      `yaml:"maxInterval"`
+		Timeout      int      `yaml:"timeout"`
+	} `yaml:"groupMemberWebhook"`
+	OfflineSync struct {
+		MaxSeqs int64 `yaml:"maxSeqs"`
+	} `yaml:"offlineSync"`
+	PushRetry struct {
+		Enable       bool `yaml:"enable"`
+		MaxAttempts  int  `yaml:"maxAttempts"`
+		BaseInterval int  `yaml:"baseInterval"`
+		MaxInterval  int  `yaml:"maxInterval"`
+		BatchSize    int  `yaml:"batchSize"`
+		ScanInterval int  `yaml:"scanInterval"`
+	} `yaml:"pushRetry"`
+	PushReadiness struct {
+		Enable          bool  `yaml:"enable"`
+		MaxPartitionLag int64 `yaml:"maxPartitionLag"`
+		MaxTotalLag     int64 `yaml:"maxTotalLag"`
+		CheckInterval   int   `yaml:"checkInterval"`
+	} `yaml:"pushReadiness"`
+	DestinationThrottle struct {
+		Enable               bool   `yaml:"enable"`
+		Window               int    `yaml:"window"`
+		MinCalls             int    `yaml:"minCalls"`
+		ThrottleErrorPercent int    `yaml:"throttleErrorPercent"`
+		DisableErrorPercent  int    `yaml:"disableErrorPercent"`
+		SlowLatency          int    `yaml:"slowLatency"`
+		ThrottledRate        int    `yaml:"throttledRate"`
+		DisableDuration      int    `yaml:"disableDuration"`
+		MaxDisableDuration   int    `yaml:"maxDisableDuration"`
+		AlertUrl             string `yaml:"alertUrl"`
+	} `yaml:"destinationThrottle"`
+	Meeting struct {
+		Enable       bool   `yaml:"enable"`
+		JoinSecret   string `yaml:"joinSecret"`
+		JoinTokenTTL int    `yaml:"joinTokenTTL"`
+		RemindBefore int    `yaml:"remindBefore"`
+		BatchSize    int    `yaml:"batchSize"`
+		CronTime     string `yaml:"cronTime"`
+	} `yaml:"meeting"`
+	Rtc struct {
+		Enable     bool     `yaml:"enable"`
+		TurnSecret string   `yaml:"turnSecret"`
+		TurnURIs   []string `yaml:"turnURIs"`
+		StunURIs   []string `yaml:"stunURIs"`
+		TTL        int      `yaml:"ttl"`
+	} `yaml:"rtc"`
+	Tenant struct {
+		Enable             bool     `yaml:"enable"`
+		ReloadInterval     int      `yaml:"reloadInterval"`
+		Isolation          bool     `yaml:"isolation"`
+		DedicatedTopicApps []string `yaml:"dedicatedTopicApps"`
+	} `yaml:"tenant"`
+	HotConversation HotConversation `yaml:"hotConversation"`
+	RedisLocalTier  struct {
+		Enable bool `yaml:"enable"`
+	} `yaml:"redisLocalTier"`
+	RecentMsgCache struct {
+		Enable bool `yaml:"enable"`
+		// Size is the number of latest messages kept per conversation.
+		Size int `yaml:"size"`
+		// Expire (seconds) drops the conversations without a new message for that long.
+		Expire int `yaml:"expire"`
+	} `yaml:"recentMsgCache"`
+	ReceiptCompaction struct {
+		Enable     bool   `yaml:"enable"`
+		RetainDays int    `yaml:"retainDays"`
+		BatchSize  int    `yaml:"batchSize"`
+		CronTime   string `yaml:"cronTime"`
+	} `yaml:"receiptCompaction"`
+	PolicySimulation struct {
+		MaxHours   int `yaml:"maxHours"`
+		SampleSize int `yaml:"sampleSize"`
+	} `yaml:"policySimulation"`
+	IDGenerator struct {
+		Strategy     string `yaml:"strategy"`
+		TenantPrefix bool   `yaml:"tenantPrefix"`
+		Snowflake    struct {
+			Epoch    int64 `yaml:"epoch"`
+			WorkerID int64 `yaml:"workerID"`
+		} `yaml:"snowflake"`
+	} `yaml:"idGenerator"`
+	UndoSend struct {
+		Enable    bool `yaml:"enable"`
+		Window    int  `yaml:"window"`
+		BatchSize int  `yaml:"batchSize"`
+	} `yaml:"undoSend"`
+	MsgDedup struct {
+		Enable bool `yaml:"enable"`
+		Window int  `yaml:"window"`
+	} `yaml:"msgDedup"`
+	AntiSpam struct {
+		Enable       bool `yaml:"enable"`
+		Rate         int  `yaml:"rate"`
+		RepeatLimit  int  `yaml:"repeatLimit"`
+		RepeatWindow int  `yaml:"repeatWindow"`
+		NewAccount   struct {
+			Age  int `yaml:"age"`
+			Rate int `yaml:"rate"`
+		} `yaml:"newAccount"`
+	} `yaml:"antiSpam"`
+	NotificationQuota struct {
+		Enable   bool `yaml:"enable"`
+		Rate     int  `yaml:"rate"`
+		DailyCap int  `yaml:"dailyCap"`
+	} `yaml:"notificationQuota"`
+	Onboarding struct {
+		AutoJoin bool `yaml:"autoJoin"`
+	} `yaml:"onboarding"`
+	SeqAllocator struct {
+		Enable      bool `yaml:"enable"`
+		SegmentSize int  `yaml:"segmentSize"`
+	} `yaml:"seqAllocator"`
+	Handoff struct {
+		Enable    bool   `yaml:"enable"`
+		Secret    string `yaml:"secret"`
+		TTL       int    `yaml:"ttl"`
+		AuditSize int    `yaml:"auditSize"`
+	} `yaml:"handoff"`
+	MsgExport struct {
+		Enable bool `yaml:"enable"`
+		// MaxMsgs is the number of messages one export holds, longer ranges are cut and marked truncated.
+		MaxMsgs int64 `yaml:"maxMsgs"`
+	} `yaml:"msgExport"`
+	MsgImport struct {
+		Enable   bool `yaml:"enable"`
+		MaxBatch int  `yaml:"maxBatch"`
+	} `yaml:"msgImport"`
 
 	LocalCache localCache `yaml:"localCache"`
 
@@ -276,6 +667,7 @@ type GlobalConfig struct {
 	} `yaml:"iosPush"`
 	Callback struct {
 		CallbackUrl                        string         `yaml:"url"`
+		GrpcAddr                           string         `yaml:"grpcAddr"`
 		CallbackBeforeSendSingleMsg        CallBackConfig `yaml:"beforeSendSingleMsg"`
 		CallbackAfterSendSingleMsg         CallBackConfig `yaml:"afterSendSingleMsg"`
 		CallbackBeforeSendGroupMsg         CallBackConfig `yaml:"beforeSendGroupMsg"`
@@ -317,10 +709,15 @@ type GlobalConfig struct {
 		CallbackAfterAddFriend             CallBackConfig `yaml:"addFriendAfter"`
 		CallbackBeforeAddFriendAgree       CallBackConfig `yaml:"addFriendAgreeBefore"`
 
-		CallbackAfterDeleteFriend   CallBackConfig `yaml:"deleteFriendAfter"`
-		CallbackBeforeImportFriends CallBackConfig `yaml:"importFriendsBefore"`
-		CallbackAfterImportFriends  CallBackConfig `yaml:"importFriendsAfter"`
-		CallbackAfterRemoveBlack    CallBackConfig `yaml:"removeBlackAfter"`
+		CallbackAfterDeleteFriend     CallBackConfig `yaml:"deleteFriendAfter"`
+		CallbackBeforeImportFriends   CallBackConfig `yaml:"importFriendsBefore"`
+		CallbackAfterImportFriends    CallBackConfig `yaml:"importFriendsAfter"`
+		CallbackImportFriendsProgress CallBackConfig `yaml:"importFriendsProgress"`
+		CallbackAfterRemoveBlack      CallBackConfig `yaml:"removeBlackAfter"`
+		CallbackAddFriendPrivacy      CallBackConfig `yaml:"addFriendPrivacy"`
+		CallbackSearchUserPrivacy     CallBackConfig `yaml:"searchUserPrivacy"`
+		CallbackAfterDeactivateUser   CallBackConfig `yaml:"deactivateUserAfter"`
+		CallbackAfterDeleteAccount    CallBackConfig `yaml:"deleteAccountAfter"`
 	} `yaml:"callback"`
 
 	Prometheus struct {
@@ -405,6 +802,23 @@ func (l LocalCache) Enable() bool {
 	return l.Topic != "" && l.SlotNum > 0 && l.SlotSize > 0
 }
 
+// HotConversation configures the promotion of busy groups to a longer lived local cache.
+type HotConversation struct {
+	Enable    bool `yaml:"enable"`
+	Threshold int  `yaml:"threshold"`
+	Window    int  `yaml:"window"` // second
+	Expire    int  `yaml:"expire"` // second
+	MaxNum    int  `yaml:"maxNum"`
+}
+
+func (h HotConversation) WindowDuration() time.Duration {
+	return time.Second * time.Duration(h.Window)
+}
+
+func (h HotConversation) ExpireDuration() time.Duration {
+	return time.Second * time.Duration(h.Expire)
+}
+
 type localCache struct {
 	User         LocalCache `yaml:"user"`
 	Group        LocalCache `yaml:"group"`
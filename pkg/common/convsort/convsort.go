@@ -0,0 +1,105 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package convsort orders the sorted conversation list on the server so that clients showing thousands of
+// conversations do not have to sort them again.
+package convsort
+
+import (
+	"context"
+	"sort"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	pbconversation "github.com/OpenIMSDK/protocol/conversation"
+	"github.com/OpenIMSDK/tools/utils"
+)
+
+// SortByKey names the sort of get_sorted_conversation_list in the rpc metadata, the proto request has no field for it.
+const SortByKey = "conversationSortBy"
+
+const (
+	// Activity orders by the time of the latest message, it is the default.
+	Activity = "activity"
+	// Unread puts the conversations with the most unread messages first.
+	Unread = "unread"
+	// Mention puts the group conversations where the user was mentioned first.
+	Mention = "mention"
+	// Member puts the groups with the most members first.
+	Member = "member"
+)
+
+// Valid reports whether sortBy is a known sort, empty means Activity.
+func Valid(sortBy string) bool {
+	switch sortBy {
+	case "", Activity, Unread, Mention, Member:
+		return true
+	}
+	return false
+}
+
+// WithSortBy attaches sortBy to ctx as an rpc custom header.
+func WithSortBy(ctx context.Context, sortBy string) context.Context {
+	if sortBy == "" {
+		return ctx
+	}
+	keys, _ := ctx.Value(constant.RpcCustomHeader).([]string)
+	if !utils.IsContain(SortByKey, keys) {
+		keys = append(append([]string{}, keys...), SortByKey)
+	}
+	ctx = context.WithValue(ctx, SortByKey, []string{sortBy})
+	return context.WithValue(ctx, constant.RpcCustomHeader, keys)
+}
+
+// GetSortBy returns the sort attached to ctx, Activity when there is none.
+func GetSortBy(ctx context.Context) string {
+	if values, ok := ctx.Value(SortByKey).([]string); ok && len(values) > 0 && values[0] != "" {
+		return values[0]
+	}
+	return Activity
+}
+
+// Mentioned reports whether groupAtType marks a conversation where the user was mentioned and has not read it yet.
+func Mentioned(groupAtType int32) bool {
+	switch groupAtType {
+	case constant.AtMe, constant.AtAll, constant.AtAllAtMe:
+		return true
+	}
+	return false
+}
+
+// Sort orders elems by sortBy, ties fall back to the latest message and then to the conversation id so that
+// pages stay stable. mentioned holds the conversation ids where the user was mentioned.
+func Sort(elems []*pbconversation.ConversationElem, sortBy string, mentioned map[string]bool) {
+	sort.SliceStable(elems, func(i, j int) bool {
+		a, b := elems[i], elems[j]
+		switch sortBy {
+		case Unread:
+			if a.UnreadCount != b.UnreadCount {
+				return a.UnreadCount > b.UnreadCount
+			}
+		case Mention:
+			if mentioned[a.ConversationID] != mentioned[b.ConversationID] {
+				return mentioned[a.ConversationID]
+			}
+		case Member:
+			if ma, mb := a.GetMsgInfo().GetGroupMemberCount(), b.GetMsgInfo().GetGroupMemberCount(); ma != mb {
+				return ma > mb
+			}
+		}
+		if ta, tb := a.GetMsgInfo().GetLatestMsgRecvTime(), b.GetMsgInfo().GetLatestMsgRecvTime(); ta != tb {
+			return ta > tb
+		}
+		return a.ConversationID < b.ConversationID
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convsort
+
+import (
+	"context"
+	"testing"
+
+	pbconversation "github.com/OpenIMSDK/protocol/conversation"
+	"github.com/stretchr/testify/assert"
+)
+
+func elem(conversationID string, recvTime int64, unread int64, members uint32) *pbconversation.ConversationElem {
+	return &pbconversation.ConversationElem{
+		ConversationID: conversationID,
+		UnreadCount:    unread,
+		MsgInfo:        &pbconversation.MsgInfo{LatestMsgRecvTime: recvTime, GroupMemberCount: members},
+	}
+}
+
+func ids(elems []*pbconversation.ConversationElem) []string {
+	res := make([]string, 0, len(elems))
+	for _, e := range elems {
+		res = append(res, e.ConversationID)
+	}
+	return res
+}
+
+func testElems() []*pbconversation.ConversationElem {
+	return []*pbconversation.ConversationElem{
+		elem("a", 100, 0, 3),
+		elem("b", 300, 2, 0),
+		elem("c", 200, 5, 50),
+		elem("d", 300, 2, 10),
+	}
+}
+
+func TestSort(t *testing.T) {
+	elems := testElems()
+	Sort(elems, Activity, nil)
+	assert.Equal(t, []string{"b", "d", "c", "a"}, ids(elems))
+
+	elems = testElems()
+	Sort(elems, Unread, nil)
+	assert.Equal(t, []string{"c", "b", "d", "a"}, ids(elems))
+
+	elems = testElems()
+	Sort(elems, Mention, map[string]bool{"a": true, "c": true})
+	assert.Equal(t, []string{"c", "a", "b", "d"}, ids(elems))
+
+	elems = testElems()
+	Sort(elems, Member, nil)
+	assert.Equal(t, []string{"c", "d", "a", "b"}, ids(elems))
+}
+
+func TestSortByContext(t *testing.T) {
+	assert.Equal(t, Activity, GetSortBy(context.Background()))
+	assert.Equal(t, Unread, GetSortBy(WithSortBy(context.Background(), Unread)))
+	assert.True(t, Valid(""))
+	assert.False(t, Valid("name"))
+}
@@ -0,0 +1,93 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	antiSpamRate        = "ANTI_SPAM_RATE:"
+	antiSpamRepeat      = "ANTI_SPAM_REPEAT:"
+	antiSpamRestriction = "ANTI_SPAM_RESTRICTION:"
+)
+
+// AntiSpamCache counts the messages of the users in fixed windows and keeps the restrictions placed on them.
+type AntiSpamCache interface {
+	// IncrRate counts a message of userID in the second second and returns the count.
+	IncrRate(ctx context.Context, userID string, second int64) (int64, error)
+	// IncrRepeat counts a message of userID with the content digest and returns the count, the count is reset
+	// window after the first of them.
+	IncrRepeat(ctx context.Context, userID string, digest string, window time.Duration) (int64, error)
+	SetRestriction(ctx context.Context, userID string, restriction string, expire time.Duration) error
+	// GetRestriction returns an empty restriction when userID is not restricted.
+	GetRestriction(ctx context.Context, userID string) (string, error)
+	DelRestriction(ctx context.Context, userID string) error
+}
+
+func NewAntiSpamCache(rdb redis.UniversalClient) AntiSpamCache {
+	return &antiSpamCache{rdb: rdb}
+}
+
+type antiSpamCache struct {
+	rdb redis.UniversalClient
+}
+
+// incrExpire increments key and sets its expiration when it is created.
+func incrExpire(ctx context.Context, rdb redis.UniversalClient, key string, expire time.Duration) (int64, error) {
+	n, err := rdb.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+	if n == 1 {
+		if err := rdb.Expire(ctx, key, expire).Err(); err != nil {
+			return 0, errs.Wrap(err)
+		}
+	}
+	return n, nil
+}
+
+func (a *antiSpamCache) IncrRate(ctx context.Context, userID string, second int64) (int64, error) {
+	return incrExpire(ctx, a.rdb, antiSpamRate+userID+":"+strconv.FormatInt(second, 10), time.Second*2)
+}
+
+func (a *antiSpamCache) IncrRepeat(ctx context.Context, userID string, digest string, window time.Duration) (int64, error) {
+	return incrExpire(ctx, a.rdb, antiSpamRepeat+userID+":"+digest, window)
+}
+
+func (a *antiSpamCache) SetRestriction(ctx context.Context, userID string, restriction string, expire time.Duration) error {
+	return errs.Wrap(a.rdb.Set(ctx, antiSpamRestriction+userID, restriction, expire).Err())
+}
+
+func (a *antiSpamCache) GetRestriction(ctx context.Context, userID string) (string, error) {
+	restriction, err := a.rdb.Get(ctx, antiSpamRestriction+userID).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", nil
+		}
+		return "", errs.Wrap(err)
+	}
+	return restriction, nil
+}
+
+func (a *antiSpamCache) DelRestriction(ctx context.Context, userID string) error {
+	return errs.Wrap(a.rdb.Del(ctx, antiSpamRestriction+userID).Err())
+}
@@ -0,0 +1,122 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/redis/go-redis/v9"
+)
+
+const consumerPause = "CONSUMER_PAUSE:"
+
+// ConsumerPause is the partitions of a consumer group paused on every instance, all of them when All is set.
+// Partitions maps topics to partitions.
+type ConsumerPause struct {
+	All        bool               `json:"all"`
+	Partitions map[string][]int32 `json:"partitions,omitempty"`
+	UpdateTime int64              `json:"updateTime"`
+}
+
+// Paused reports whether partition of topic is paused.
+func (p *ConsumerPause) Paused(topic string, partition int32) bool {
+	if p.All {
+		return true
+	}
+	return containsPartition(p.Partitions[topic], partition)
+}
+
+// Add pauses partitions of topic as well.
+func (p *ConsumerPause) Add(topic string, partitions []int32) {
+	if p.Partitions == nil {
+		p.Partitions = make(map[string][]int32)
+	}
+	for _, partition := range partitions {
+		if !containsPartition(p.Partitions[topic], partition) {
+			p.Partitions[topic] = append(p.Partitions[topic], partition)
+		}
+	}
+}
+
+// Remove resumes partitions of topic, the topic is dropped once none of its partitions is paused.
+func (p *ConsumerPause) Remove(topic string, partitions []int32) {
+	paused := p.Partitions[topic][:0]
+	for _, partition := range p.Partitions[topic] {
+		if !containsPartition(partitions, partition) {
+			paused = append(paused, partition)
+		}
+	}
+	if len(paused) == 0 {
+		delete(p.Partitions, topic)
+		return
+	}
+	p.Partitions[topic] = paused
+}
+
+func containsPartition(partitions []int32, partition int32) bool {
+	for _, p := range partitions {
+		if p == partition {
+			return true
+		}
+	}
+	return false
+}
+
+// ConsumerPauseCache stores the partitions paused in each consumer group, the instances of a group read it
+// periodically and apply it.
+type ConsumerPauseCache interface {
+	// GetPause returns the pause of groupID, an empty one when nothing is paused.
+	GetPause(ctx context.Context, groupID string) (*ConsumerPause, error)
+	SetPause(ctx context.Context, groupID string, pause *ConsumerPause) error
+}
+
+func NewConsumerPauseCache(rdb redis.UniversalClient) ConsumerPauseCache {
+	return &consumerPauseCache{rdb: rdb}
+}
+
+type consumerPauseCache struct {
+	rdb redis.UniversalClient
+}
+
+func (c *consumerPauseCache) getPauseKey(groupID string) string {
+	return consumerPause + groupID
+}
+
+func (c *consumerPauseCache) GetPause(ctx context.Context, groupID string) (*ConsumerPause, error) {
+	data, err := c.rdb.Get(ctx, c.getPauseKey(groupID)).Bytes()
+	if err == redis.Nil {
+		return &ConsumerPause{}, nil
+	} else if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	var pause ConsumerPause
+	if err := json.Unmarshal(data, &pause); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &pause, nil
+}
+
+func (c *consumerPauseCache) SetPause(ctx context.Context, groupID string, pause *ConsumerPause) error {
+	if !pause.All && len(pause.Partitions) == 0 {
+		return errs.Wrap(c.rdb.Del(ctx, c.getPauseKey(groupID)).Err())
+	}
+	data, err := json.Marshal(pause)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return errs.Wrap(c.rdb.Set(ctx, c.getPauseKey(groupID), data, 0).Err())
+}
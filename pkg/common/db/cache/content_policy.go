@@ -0,0 +1,77 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/dtm-labs/rockscache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/cachekey"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	contentPolicyExpireTime = time.Second * 60 * 60 * 12
+)
+
+type ContentPolicyCache interface {
+	metaCache
+	NewCache() ContentPolicyCache
+	// GetContentTypes returns the allowed content types, nil when there is no policy.
+	GetContentTypes(ctx context.Context, sessionType int32, groupID string) ([]int32, error)
+	DelContentTypes(sessionType int32, groupID string) ContentPolicyCache
+}
+
+type ContentPolicyCacheRedis struct {
+	metaCache
+	expireTime time.Duration
+	rcClient   *rockscache.Client
+	policyDB   relationtb.ContentPolicyModelInterface
+}
+
+func NewContentPolicyCacheRedis(rdb redis.UniversalClient, policyDB relationtb.ContentPolicyModelInterface, options rockscache.Options) ContentPolicyCache {
+	rcClient := rockscache.NewClient(rdb, options)
+	mc := NewMetaCacheRedis(rcClient)
+	mc.SetRawRedisClient(rdb)
+	return &ContentPolicyCacheRedis{
+		expireTime: contentPolicyExpireTime,
+		rcClient:   rcClient,
+		metaCache:  mc,
+		policyDB:   policyDB,
+	}
+}
+
+func (c *ContentPolicyCacheRedis) NewCache() ContentPolicyCache {
+	return &ContentPolicyCacheRedis{
+		expireTime: c.expireTime,
+		rcClient:   c.rcClient,
+		policyDB:   c.policyDB,
+		metaCache:  c.Copy(),
+	}
+}
+
+func (c *ContentPolicyCacheRedis) GetContentTypes(ctx context.Context, sessionType int32, groupID string) ([]int32, error) {
+	return getCache(ctx, c.rcClient, cachekey.GetContentPolicyKey(sessionType, groupID), c.expireTime, func(ctx context.Context) ([]int32, error) {
+		return c.policyDB.FindContentTypes(ctx, sessionType, groupID)
+	})
+}
+
+func (c *ContentPolicyCacheRedis) DelContentTypes(sessionType int32, groupID string) ContentPolicyCache {
+	cache := c.NewCache()
+	cache.AddKeys(cachekey.GetContentPolicyKey(sessionType, groupID))
+	return cache
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/utils"
 	"github.com/dtm-labs/rockscache"
+	"github.com/openimsdk/localcache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/cachekey"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
@@ -89,11 +90,14 @@ func NewConversationRedis(rdb redis.UniversalClient, opts rockscache.Options, db
 	log.ZDebug(context.Background(), "black local cache init", "Topic", c.Topic, "SlotNum", c.SlotNum, "SlotSize", c.SlotSize, "enable", c.Enable())
 	mc.SetTopic(c.Topic)
 	mc.SetRawRedisClient(rdb)
+	local := newLocalTier(rdb, c)
+	mc.SetLocalTier(local)
 	return &ConversationRedisCache{
 		rcClient:       rcClient,
 		metaCache:      mc,
 		conversationDB: db,
 		expireTime:     conversationExpireTime,
+		local:          local,
 	}
 }
 
@@ -102,6 +106,7 @@ type ConversationRedisCache struct {
 	rcClient       *rockscache.Client
 	conversationDB relationtb.ConversationModelInterface
 	expireTime     time.Duration
+	local          localcache.Cache[string]
 }
 
 //func NewNewConversationRedis(
@@ -125,6 +130,7 @@ func (c *ConversationRedisCache) NewCache() ConversationCache {
 		metaCache:      c.Copy(),
 		conversationDB: c.conversationDB,
 		expireTime:     c.expireTime,
+		local:          c.local,
 	}
 }
 
@@ -161,7 +167,7 @@ func (c *ConversationRedisCache) getUserConversationIDsHashKey(ownerUserID strin
 }
 
 func (c *ConversationRedisCache) GetUserConversationIDs(ctx context.Context, ownerUserID string) ([]string, error) {
-	return getCache(ctx, c.rcClient, c.getConversationIDsKey(ownerUserID), c.expireTime, func(ctx context.Context) ([]string, error) {
+	return getLocalCache(ctx, c.local, c.rcClient, c.getConversationIDsKey(ownerUserID), c.expireTime, func(ctx context.Context) ([]string, error) {
 		return c.conversationDB.FindUserIDAllConversationID(ctx, ownerUserID)
 	})
 }
@@ -208,7 +214,7 @@ func (c *ConversationRedisCache) DelUserConversationIDsHash(ownerUserIDs ...stri
 }
 
 func (c *ConversationRedisCache) GetConversation(ctx context.Context, ownerUserID, conversationID string) (*relationtb.ConversationModel, error) {
-	return getCache(ctx, c.rcClient, c.getConversationKey(ownerUserID, conversationID), c.expireTime, func(ctx context.Context) (*relationtb.ConversationModel, error) {
+	return getLocalCache(ctx, c.local, c.rcClient, c.getConversationKey(ownerUserID, conversationID), c.expireTime, func(ctx context.Context) (*relationtb.ConversationModel, error) {
 		return c.conversationDB.Take(ctx, ownerUserID, conversationID)
 	})
 }
@@ -250,7 +256,7 @@ func (c *ConversationRedisCache) GetConversations(ctx context.Context, ownerUser
 	//		return c.conversationDB.Find(ctx, ownerUserID, conversationIDs)
 	//	},
 	//)
-	return batchGetCache2(ctx, c.rcClient, c.expireTime, conversationIDs, func(conversationID string) string {
+	return batchGetCache2(ctx, c.local, c.rcClient, c.expireTime, conversationIDs, func(conversationID string) string {
 		return c.getConversationKey(ownerUserID, conversationID)
 	}, func(ctx context.Context, conversationID string) (*relationtb.ConversationModel, error) {
 		return c.conversationDB.Take(ctx, ownerUserID, conversationID)
@@ -379,7 +385,7 @@ func (c *ConversationRedisCache) DelConversationByConversationID(conversationIDs
 }
 
 func (c *ConversationRedisCache) GetConversationNotReceiveMessageUserIDs(ctx context.Context, conversationID string) ([]string, error) {
-	return getCache(ctx, c.rcClient, c.getConversationNotReceiveMessageUserIDsKey(conversationID), c.expireTime, func(ctx context.Context) ([]string, error) {
+	return getLocalCache(ctx, c.local, c.rcClient, c.getConversationNotReceiveMessageUserIDsKey(conversationID), c.expireTime, func(ctx context.Context) ([]string, error) {
 		return c.conversationDB.GetConversationNotReceiveMessageUserIDs(ctx, conversationID)
 	})
 }
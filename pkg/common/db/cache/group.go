@@ -24,6 +24,7 @@ import (
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/utils"
 	"github.com/dtm-labs/rockscache"
+	"github.com/openimsdk/localcache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/cachekey"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
@@ -90,6 +91,7 @@ type GroupCacheRedis struct {
 	expireTime     time.Duration
 	rcClient       *rockscache.Client
 	groupHash      GroupHash
+	local          localcache.Cache[string]
 }
 
 func NewGroupCacheRedis(
@@ -106,11 +108,14 @@ func NewGroupCacheRedis(
 	mc.SetTopic(g.Topic)
 	log.ZDebug(context.Background(), "group local cache init", "Topic", g.Topic, "SlotNum", g.SlotNum, "SlotSize", g.SlotSize, "enable", g.Enable())
 	mc.SetRawRedisClient(rdb)
+	local := newLocalTier(rdb, g)
+	mc.SetLocalTier(local)
 	return &GroupCacheRedis{
 		rcClient: rcClient, expireTime: groupExpireTime,
 		groupDB: groupDB, groupMemberDB: groupMemberDB, groupRequestDB: groupRequestDB,
 		groupHash: hashCode,
 		metaCache: mc,
+		local:     local,
 	}
 }
 
@@ -122,6 +127,7 @@ func (g *GroupCacheRedis) NewCache() GroupCache {
 		groupMemberDB:  g.groupMemberDB,
 		groupRequestDB: g.groupRequestDB,
 		metaCache:      g.Copy(),
+		local:          g.local,
 	}
 }
 
@@ -176,7 +182,7 @@ func (g *GroupCacheRedis) GetGroupMemberIndex(groupMember *relationtb.GroupMembe
 }
 
 func (g *GroupCacheRedis) GetGroupsInfo(ctx context.Context, groupIDs []string) (groups []*relationtb.GroupModel, err error) {
-	return batchGetCache2(ctx, g.rcClient, g.expireTime, groupIDs, func(groupID string) string {
+	return batchGetCache2(ctx, g.local, g.rcClient, g.expireTime, groupIDs, func(groupID string) string {
 		return g.getGroupInfoKey(groupID)
 	}, func(ctx context.Context, groupID string) (*relationtb.GroupModel, error) {
 		return g.groupDB.Take(ctx, groupID)
@@ -184,7 +190,7 @@ func (g *GroupCacheRedis) GetGroupsInfo(ctx context.Context, groupIDs []string)
 }
 
 func (g *GroupCacheRedis) GetGroupInfo(ctx context.Context, groupID string) (group *relationtb.GroupModel, err error) {
-	return getCache(ctx, g.rcClient, g.getGroupInfoKey(groupID), g.expireTime, func(ctx context.Context) (*relationtb.GroupModel, error) {
+	return getLocalCache(ctx, g.local, g.rcClient, g.getGroupInfoKey(groupID), g.expireTime, func(ctx context.Context) (*relationtb.GroupModel, error) {
 		return g.groupDB.Take(ctx, groupID)
 	})
 }
@@ -263,7 +269,7 @@ func (g *GroupCacheRedis) DelGroupMembersHash(groupID string) GroupCache {
 }
 
 func (g *GroupCacheRedis) GetGroupMemberIDs(ctx context.Context, groupID string) (groupMemberIDs []string, err error) {
-	return getCache(ctx, g.rcClient, g.getGroupMemberIDsKey(groupID), g.expireTime, func(ctx context.Context) ([]string, error) {
+	return getLocalCache(ctx, g.local, g.rcClient, g.getGroupMemberIDsKey(groupID), g.expireTime, func(ctx context.Context) ([]string, error) {
 		return g.groupMemberDB.FindMemberUserID(ctx, groupID)
 	})
 }
@@ -306,13 +312,13 @@ func (g *GroupCacheRedis) DelJoinedGroupID(userIDs ...string) GroupCache {
 }
 
 func (g *GroupCacheRedis) GetGroupMemberInfo(ctx context.Context, groupID, userID string) (groupMember *relationtb.GroupMemberModel, err error) {
-	return getCache(ctx, g.rcClient, g.getGroupMemberInfoKey(groupID, userID), g.expireTime, func(ctx context.Context) (*relationtb.GroupMemberModel, error) {
+	return getLocalCache(ctx, g.local, g.rcClient, g.getGroupMemberInfoKey(groupID, userID), g.expireTime, func(ctx context.Context) (*relationtb.GroupMemberModel, error) {
 		return g.groupMemberDB.Take(ctx, groupID, userID)
 	})
 }
 
 func (g *GroupCacheRedis) GetGroupMembersInfo(ctx context.Context, groupID string, userIDs []string) ([]*relationtb.GroupMemberModel, error) {
-	return batchGetCache2(ctx, g.rcClient, g.expireTime, userIDs, func(userID string) string {
+	return batchGetCache2(ctx, g.local, g.rcClient, g.expireTime, userIDs, func(userID string) string {
 		return g.getGroupMemberInfoKey(groupID, userID)
 	}, func(ctx context.Context, userID string) (*relationtb.GroupMemberModel, error) {
 		return g.groupMemberDB.Take(ctx, groupID, userID)
@@ -442,7 +448,7 @@ func (g *GroupCacheRedis) FindGroupMemberUser(ctx context.Context, groupIDs []st
 			return nil, err
 		}
 	}
-	return batchGetCache2(ctx, g.rcClient, g.expireTime, groupIDs, func(groupID string) string {
+	return batchGetCache2(ctx, g.local, g.rcClient, g.expireTime, groupIDs, func(groupID string) string {
 		return g.getGroupMemberInfoKey(groupID, userID)
 	}, func(ctx context.Context, groupID string) (*relationtb.GroupMemberModel, error) {
 		return g.groupMemberDB.Take(ctx, groupID, userID)
@@ -0,0 +1,110 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/dtm-labs/rockscache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/cachekey"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	groupHistoryExpireTime = time.Second * 60 * 60 * 12
+)
+
+type GroupHistoryCache interface {
+	metaCache
+	NewCache() GroupHistoryCache
+	// GetVisibility returns the history visibility of a group, GroupHistoryVisibleAll when it was never set.
+	GetVisibility(ctx context.Context, groupID string) (*relationtb.GroupHistoryVisibilityModel, error)
+	DelVisibility(groupIDs ...string) GroupHistoryCache
+	// GetMinSeqSince caches the first seq sent at or after since, which no longer changes once found.
+	GetMinSeqSince(ctx context.Context, conversationID string, since int64) (int64, error)
+}
+
+type GroupHistoryCacheRedis struct {
+	metaCache
+	expireTime   time.Duration
+	rcClient     *rockscache.Client
+	visibilityDB relationtb.GroupHistoryVisibilityModelInterface
+	msgDocDB     unrelationtb.MsgDocModelInterface
+}
+
+func NewGroupHistoryCacheRedis(rdb redis.UniversalClient, visibilityDB relationtb.GroupHistoryVisibilityModelInterface, msgDocDB unrelationtb.MsgDocModelInterface, options rockscache.Options) GroupHistoryCache {
+	rcClient := rockscache.NewClient(rdb, options)
+	mc := NewMetaCacheRedis(rcClient)
+	mc.SetRawRedisClient(rdb)
+	return &GroupHistoryCacheRedis{
+		expireTime:   groupHistoryExpireTime,
+		rcClient:     rcClient,
+		metaCache:    mc,
+		visibilityDB: visibilityDB,
+		msgDocDB:     msgDocDB,
+	}
+}
+
+func (g *GroupHistoryCacheRedis) NewCache() GroupHistoryCache {
+	return &GroupHistoryCacheRedis{
+		expireTime:   g.expireTime,
+		rcClient:     g.rcClient,
+		visibilityDB: g.visibilityDB,
+		msgDocDB:     g.msgDocDB,
+		metaCache:    g.Copy(),
+	}
+}
+
+func (g *GroupHistoryCacheRedis) GetVisibility(ctx context.Context, groupID string) (*relationtb.GroupHistoryVisibilityModel, error) {
+	return getCache(
+		ctx,
+		g.rcClient,
+		cachekey.GetGroupHistoryVisibilityKey(groupID),
+		g.expireTime,
+		func(ctx context.Context) (*relationtb.GroupHistoryVisibilityModel, error) {
+			visibility, err := g.visibilityDB.Take(ctx, groupID)
+			if err != nil {
+				if relationtb.IsNotFound(err) {
+					return &relationtb.GroupHistoryVisibilityModel{GroupID: groupID, Visibility: relationtb.GroupHistoryVisibleAll}, nil
+				}
+				return nil, err
+			}
+			return visibility, nil
+		},
+	)
+}
+
+func (g *GroupHistoryCacheRedis) DelVisibility(groupIDs ...string) GroupHistoryCache {
+	cache := g.NewCache()
+	for _, groupID := range groupIDs {
+		cache.AddKeys(cachekey.GetGroupHistoryVisibilityKey(groupID))
+	}
+	return cache
+}
+
+func (g *GroupHistoryCacheRedis) GetMinSeqSince(ctx context.Context, conversationID string, since int64) (int64, error) {
+	return getCache(
+		ctx,
+		g.rcClient,
+		cachekey.GetGroupHistoryMinSeqKey(conversationID, since),
+		g.expireTime,
+		func(ctx context.Context) (int64, error) {
+			return g.msgDocDB.GetFirstSeqSince(ctx, conversationID, since)
+		},
+	)
+}
@@ -0,0 +1,102 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/dtm-labs/rockscache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/cachekey"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	groupRoleExpireTime = time.Second * 60 * 60 * 12
+)
+
+type GroupRoleCache interface {
+	metaCache
+	NewCache() GroupRoleCache
+	GetRoles(ctx context.Context, groupID string) ([]*relationtb.GroupRoleModel, error)
+	DelRoles(groupIDs ...string) GroupRoleCache
+	// GetMemberRoleID returns the custom role of a member, empty when it has none.
+	GetMemberRoleID(ctx context.Context, groupID string, userID string) (string, error)
+	DelMemberRoleID(groupID string, userIDs ...string) GroupRoleCache
+}
+
+type GroupRoleCacheRedis struct {
+	metaCache
+	expireTime time.Duration
+	rcClient   *rockscache.Client
+	roleDB     relationtb.GroupRoleModelInterface
+}
+
+func NewGroupRoleCacheRedis(rdb redis.UniversalClient, roleDB relationtb.GroupRoleModelInterface, options rockscache.Options) GroupRoleCache {
+	rcClient := rockscache.NewClient(rdb, options)
+	mc := NewMetaCacheRedis(rcClient)
+	mc.SetRawRedisClient(rdb)
+	return &GroupRoleCacheRedis{
+		expireTime: groupRoleExpireTime,
+		rcClient:   rcClient,
+		metaCache:  mc,
+		roleDB:     roleDB,
+	}
+}
+
+func (g *GroupRoleCacheRedis) NewCache() GroupRoleCache {
+	return &GroupRoleCacheRedis{
+		expireTime: g.expireTime,
+		rcClient:   g.rcClient,
+		roleDB:     g.roleDB,
+		metaCache:  g.Copy(),
+	}
+}
+
+func (g *GroupRoleCacheRedis) GetRoles(ctx context.Context, groupID string) ([]*relationtb.GroupRoleModel, error) {
+	return getCache(ctx, g.rcClient, cachekey.GetGroupRolesKey(groupID), g.expireTime, func(ctx context.Context) ([]*relationtb.GroupRoleModel, error) {
+		return g.roleDB.FindRoles(ctx, groupID)
+	})
+}
+
+func (g *GroupRoleCacheRedis) DelRoles(groupIDs ...string) GroupRoleCache {
+	cache := g.NewCache()
+	for _, groupID := range groupIDs {
+		cache.AddKeys(cachekey.GetGroupRolesKey(groupID))
+	}
+	return cache
+}
+
+func (g *GroupRoleCacheRedis) GetMemberRoleID(ctx context.Context, groupID string, userID string) (string, error) {
+	return getCache(ctx, g.rcClient, cachekey.GetGroupMemberRoleKey(groupID, userID), g.expireTime, func(ctx context.Context) (string, error) {
+		member, err := g.roleDB.TakeMemberRole(ctx, groupID, userID)
+		if err != nil {
+			if relationtb.IsNotFound(err) {
+				return "", nil
+			}
+			return "", err
+		}
+		return member.RoleID, nil
+	})
+}
+
+func (g *GroupRoleCacheRedis) DelMemberRoleID(groupID string, userIDs ...string) GroupRoleCache {
+	cache := g.NewCache()
+	for _, userID := range userIDs {
+		cache.AddKeys(cachekey.GetGroupMemberRoleKey(groupID, userID))
+	}
+	return cache
+}
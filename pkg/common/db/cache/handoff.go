@@ -0,0 +1,78 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	handoffToken = "HANDOFF_TOKEN:"
+	handoffAudit = "HANDOFF_AUDIT:"
+	// handoffAuditExpire drops the audit of users who stopped using handoff links.
+	handoffAuditExpire = time.Hour * 24 * 30
+)
+
+// HandoffCache tracks the handoff tokens not redeemed yet and keeps the latest audit records of each user.
+type HandoffCache interface {
+	Issue(ctx context.Context, id string, userID string, ttl time.Duration) error
+	// Redeem marks the token id as used, false when it was already used or expired.
+	Redeem(ctx context.Context, id string) (bool, error)
+	AddAudit(ctx context.Context, userID string, record string, size int64) error
+	// GetAudit returns the audit records of userID, newest first.
+	GetAudit(ctx context.Context, userID string) ([]string, error)
+}
+
+func NewHandoffCache(rdb redis.UniversalClient) HandoffCache {
+	return &handoffCache{rdb: rdb}
+}
+
+type handoffCache struct {
+	rdb redis.UniversalClient
+}
+
+func (h *handoffCache) Issue(ctx context.Context, id string, userID string, ttl time.Duration) error {
+	return errs.Wrap(h.rdb.Set(ctx, handoffToken+id, userID, ttl).Err())
+}
+
+func (h *handoffCache) Redeem(ctx context.Context, id string) (bool, error) {
+	n, err := h.rdb.Del(ctx, handoffToken+id).Result()
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return n > 0, nil
+}
+
+func (h *handoffCache) AddAudit(ctx context.Context, userID string, record string, size int64) error {
+	key := handoffAudit + userID
+	pipe := h.rdb.Pipeline()
+	pipe.LPush(ctx, key, record)
+	pipe.LTrim(ctx, key, 0, size-1)
+	pipe.Expire(ctx, key, handoffAuditExpire)
+	_, err := pipe.Exec(ctx)
+	return errs.Wrap(err)
+}
+
+func (h *handoffCache) GetAudit(ctx context.Context, userID string) ([]string, error) {
+	records, err := h.rdb.LRange(ctx, handoffAudit+userID, 0, -1).Result()
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return records, nil
+}
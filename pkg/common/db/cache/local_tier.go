@@ -0,0 +1,81 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/dtm-labs/rockscache"
+	"github.com/openimsdk/localcache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// newLocalTier returns the in-process tier kept in front of redis for the keys published on the topic of lc, nil
+// when redisLocalTier or lc is disabled. Entries are dropped by ExecDel on this instance and by the topic on
+// the others.
+func newLocalTier(rdb redis.UniversalClient, lc config.LocalCache) localcache.Cache[string] {
+	if !config.Config.RedisLocalTier.Enable || !lc.Enable() {
+		return nil
+	}
+	log.ZDebug(context.Background(), "redis local tier init", "topic", lc.Topic, "slotNum", lc.SlotNum, "slotSize", lc.SlotSize)
+	local := localcache.New[string](
+		localcache.WithLocalSlotNum(lc.SlotNum),
+		localcache.WithLocalSlotSize(lc.SlotSize),
+		localcache.WithLinkSlotNum(lc.SlotNum),
+		localcache.WithLocalSuccessTTL(lc.Success()),
+		localcache.WithLocalFailedTTL(lc.Failed()),
+	)
+	go SubscribeDeleteCache(context.Background(), rdb, lc.Topic, local.DelLocal)
+	return local
+}
+
+// getLocalCache is getCache with local in front of redis, a nil local reads redis directly. local holds the
+// json and not the value, every caller decodes its own copy and may modify it.
+func getLocalCache[T any](ctx context.Context, local localcache.Cache[string], rcClient *rockscache.Client, key string, expire time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	if local == nil {
+		return getCache(ctx, rcClient, key, expire, fn)
+	}
+	var t T
+	v, err := local.Get(ctx, key, func(ctx context.Context) (string, error) {
+		return rcClient.Fetch2(ctx, key, expire, func() (string, error) {
+			t, err := fn(ctx)
+			if err != nil {
+				return "", err
+			}
+			bs, err := json.Marshal(t)
+			if err != nil {
+				return "", errs.Wrap(err, "marshal failed")
+			}
+			return string(bs), nil
+		})
+	})
+	if err != nil {
+		return t, errs.Wrap(err)
+	}
+	if v == "" {
+		return t, errs.ErrRecordNotFound.Wrap("cache is not found")
+	}
+	if err := json.Unmarshal([]byte(v), &t); err != nil {
+		errInfo := fmt.Sprintf("cache json.Unmarshal failed, key:%s, value:%s, expire:%s", key, v, expire)
+		return t, errs.Wrap(err, errInfo)
+	}
+	return t, nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/OpenIMSDK/tools/mw/specialerror"
 	"github.com/OpenIMSDK/tools/utils"
 	"github.com/dtm-labs/rockscache"
+	"github.com/openimsdk/localcache"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -46,6 +47,8 @@ type metaCache interface {
 	GetPreDelKeys() []string
 	SetTopic(topic string)
 	SetRawRedisClient(cli redis.UniversalClient)
+	// SetLocalTier sets the in-process tier ExecDel drops the keys from before they are published.
+	SetLocalTier(local localcache.Cache[string])
 	Copy() metaCache
 }
 
@@ -60,6 +63,7 @@ type metaCacheRedis struct {
 	maxRetryTimes int
 	retryInterval time.Duration
 	redisClient   redis.UniversalClient
+	local         localcache.Cache[string]
 }
 
 func (m *metaCacheRedis) Copy() metaCache {
@@ -75,6 +79,7 @@ func (m *metaCacheRedis) Copy() metaCache {
 		maxRetryTimes: m.maxRetryTimes,
 		retryInterval: m.retryInterval,
 		redisClient:   redisClient,
+		local:         m.local,
 	}
 }
 
@@ -86,6 +91,10 @@ func (m *metaCacheRedis) SetRawRedisClient(cli redis.UniversalClient) {
 	m.redisClient = cli
 }
 
+func (m *metaCacheRedis) SetLocalTier(local localcache.Cache[string]) {
+	m.local = local
+}
+
 func (m *metaCacheRedis) ExecDel(ctx context.Context, distinct ...bool) error {
 	if len(distinct) > 0 && distinct[0] {
 		m.keys = utils.Distinct(m.keys)
@@ -102,6 +111,9 @@ func (m *metaCacheRedis) ExecDel(ctx context.Context, distinct ...bool) error {
 				break
 			}
 		}
+		if m.local != nil {
+			m.local.DelLocal(ctx, m.keys...)
+		}
 		if pk := getPublishKey(m.topic, m.keys); len(pk) > 0 {
 			data, err := json.Marshal(pk)
 			if err != nil {
@@ -212,8 +224,10 @@ func getCache[T any](ctx context.Context, rcClient *rockscache.Client, key strin
 //	return tArrays, nil
 //}
 
+// batchGetCache2 reads the keys one by one through local and redis, a nil local reads redis directly.
 func batchGetCache2[T any, K comparable](
 	ctx context.Context,
+	local localcache.Cache[string],
 	rcClient *rockscache.Client,
 	expire time.Duration,
 	keys []K,
@@ -225,7 +239,7 @@ func batchGetCache2[T any, K comparable](
 	}
 	res := make([]T, 0, len(keys))
 	for _, key := range keys {
-		val, err := getCache(ctx, rcClient, keyFn(key), expire, func(ctx context.Context) (T, error) {
+		val, err := getLocalCache(ctx, local, rcClient, keyFn(key), expire, func(ctx context.Context) (T, error) {
 			return fns(ctx, key)
 		})
 		if err != nil {
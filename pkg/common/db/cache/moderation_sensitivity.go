@@ -0,0 +1,81 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/dtm-labs/rockscache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/cachekey"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	moderationSensitivityExpireTime = time.Second * 60 * 60 * 12
+)
+
+type ModerationSensitivityCache interface {
+	metaCache
+	NewCache() ModerationSensitivityCache
+	// GetLevel returns the sensitivity level set on the conversation, empty when none is set.
+	GetLevel(ctx context.Context, conversationID string) (string, error)
+	DelLevel(conversationID string) ModerationSensitivityCache
+}
+
+type ModerationSensitivityCacheRedis struct {
+	metaCache
+	expireTime    time.Duration
+	rcClient      *rockscache.Client
+	sensitivityDB relationtb.ModerationSensitivityModelInterface
+}
+
+func NewModerationSensitivityCacheRedis(rdb redis.UniversalClient, sensitivityDB relationtb.ModerationSensitivityModelInterface, options rockscache.Options) ModerationSensitivityCache {
+	rcClient := rockscache.NewClient(rdb, options)
+	mc := NewMetaCacheRedis(rcClient)
+	mc.SetRawRedisClient(rdb)
+	return &ModerationSensitivityCacheRedis{
+		expireTime:    moderationSensitivityExpireTime,
+		rcClient:      rcClient,
+		metaCache:     mc,
+		sensitivityDB: sensitivityDB,
+	}
+}
+
+func (c *ModerationSensitivityCacheRedis) NewCache() ModerationSensitivityCache {
+	return &ModerationSensitivityCacheRedis{
+		expireTime:    c.expireTime,
+		rcClient:      c.rcClient,
+		sensitivityDB: c.sensitivityDB,
+		metaCache:     c.Copy(),
+	}
+}
+
+func (c *ModerationSensitivityCacheRedis) GetLevel(ctx context.Context, conversationID string) (string, error) {
+	return getCache(ctx, c.rcClient, cachekey.GetModerationSensitivityKey(conversationID), c.expireTime, func(ctx context.Context) (string, error) {
+		sensitivity, err := c.sensitivityDB.Take(ctx, conversationID)
+		if err != nil || sensitivity == nil {
+			return "", err
+		}
+		return sensitivity.Level, nil
+	})
+}
+
+func (c *ModerationSensitivityCacheRedis) DelLevel(conversationID string) ModerationSensitivityCache {
+	cache := c.NewCache()
+	cache.AddKeys(cachekey.GetModerationSensitivityKey(conversationID))
+	return cache
+}
@@ -37,10 +37,16 @@ const (
 	minSeq                 = "MIN_SEQ:"
 	conversationUserMinSeq = "CON_USER_MIN_SEQ:"
 	hasReadSeq             = "HAS_READ_SEQ:"
+	groupReadSeq           = "GROUP_READ_SEQ:"
 
 	//appleDeviceToken = "DEVICE_TOKEN".
 	getuiToken  = "GETUI_TOKEN"
 	getuiTaskID = "GETUI_TASK_ID"
+	hmsToken    = "HMS_TOKEN"
+
+	msgDeliveryLatency = "MSG_DELIVERY_LATENCY"
+	// msgDeliveryLatencySamples is the number of most recent delivery latencies kept.
+	msgDeliveryLatencySamples = 1000
 	//signalCache      = "SIGNAL_CACHE:"
 	//signalListCache  = "SIGNAL_LIST_CACHE:".
 	FCM_TOKEN = "FCM_TOKEN:"
@@ -52,6 +58,16 @@ const (
 	userBadgeUnreadCountSum = "USER_BADGE_UNREAD_COUNT_SUM:"
 	exTypeKeyLocker         = "EX_LOCK:"
 	uidPidToken             = "UID_PID_TOKEN_STATUS:"
+	loginPolicyMatrix       = "LOGIN_POLICY_MATRIX"
+	tokenFingerprint        = "TOKEN_FINGERPRINT:"
+	tokenSession            = "TOKEN_SESSION:"
+)
+
+// Fields of the session of a token, set by the gateway on connection and heartbeats and by the device apis.
+const (
+	TokenSessionIP         = "ip"
+	TokenSessionLastActive = "lastActive"
+	TokenSessionName       = "name"
 )
 
 var concurrentLimit = 3
@@ -79,6 +95,10 @@ type SeqCache interface {
 	UserSetHasReadSeqs(ctx context.Context, userID string, hasReadSeqs map[string]int64) error
 	GetHasReadSeqs(ctx context.Context, userID string, conversationIDs []string) (map[string]int64, error)
 	GetHasReadSeq(ctx context.Context, userID string, conversationID string) (int64, error)
+	// group read members, k: user, v: seq, seqs only move forward
+	SetGroupMemberReadSeqs(ctx context.Context, conversationID string, hasReadSeqs map[string]int64) error
+	// members whose has read seq is at least seq
+	GetGroupReadMembers(ctx context.Context, conversationID string, seq int64) ([]string, error)
 }
 
 type thirdCache interface {
@@ -92,6 +112,11 @@ type thirdCache interface {
 	GetGetuiToken(ctx context.Context) (string, error)
 	SetGetuiTaskID(ctx context.Context, taskID string, expireTime int64) error
 	GetGetuiTaskID(ctx context.Context) (string, error)
+	SetHmsToken(ctx context.Context, token string, expireTime int64) error
+	GetHmsToken(ctx context.Context) (string, error)
+	DelHmsToken(ctx context.Context) error
+	AddDeliveryLatency(ctx context.Context, latency int64) error
+	GetDeliveryLatencies(ctx context.Context) ([]int64, error)
 }
 
 type MsgModel interface {
@@ -101,8 +126,21 @@ type MsgModel interface {
 	GetTokensWithoutError(ctx context.Context, userID string, platformID int) (map[string]int, error)
 	SetTokenMapByUidPid(ctx context.Context, userID string, platformID int, m map[string]int) error
 	DeleteTokenByUidPid(ctx context.Context, userID string, platformID int, fields []string) error
+	SetLoginPolicyMatrix(ctx context.Context, matrix string) error
+	GetLoginPolicyMatrix(ctx context.Context) (string, error)
+	SetTokenFingerprint(ctx context.Context, token string, fingerprint string, expiration time.Duration) error
+	GetTokenFingerprint(ctx context.Context, token string) (string, error)
+	// SetTokenSession sets fields of the session of token, see the TokenSession constants.
+	SetTokenSession(ctx context.Context, token string, fields map[string]string, expiration time.Duration) error
+	// GetTokenSessions returns the session fields of each token, tokens without a session are left out.
+	GetTokenSessions(ctx context.Context, tokens []string) (map[string]map[string]string, error)
+	DelTokenSession(ctx context.Context, token string) error
 	GetMessagesBySeq(ctx context.Context, conversationID string, seqs []int64) (seqMsg []*sdkws.MsgData, failedSeqList []int64, err error)
 	SetMessageToCache(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) (int, error)
+	// AddRecentMessages adds msgs to the latest messages of the conversation, which keeps recentMsgCache.size of them.
+	AddRecentMessages(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) error
+	// GetRecentMessages returns the latest messages of the conversation with a seq between begin and end, by seq.
+	GetRecentMessages(ctx context.Context, conversationID string, begin, end int64) ([]*sdkws.MsgData, error)
 	UserDeleteMsgs(ctx context.Context, conversationID string, seqs []int64, userID string) error
 	DelUserDeleteMsgsList(ctx context.Context, conversationID string, seqs []int64)
 	DeleteMessages(ctx context.Context, conversationID string, seqs []int64) error
@@ -143,6 +181,10 @@ func (c *msgCache) getHasReadSeqKey(conversationID string, userID string) string
 	return hasReadSeq + userID + ":" + conversationID
 }
 
+func (c *msgCache) getGroupReadSeqKey(conversationID string) string {
+	return groupReadSeq + conversationID
+}
+
 func (c *msgCache) getConversationUserMinSeqKey(conversationID, userID string) string {
 	return conversationUserMinSeq + conversationID + "u:" + userID
 }
@@ -272,6 +314,28 @@ func (c *msgCache) GetHasReadSeq(ctx context.Context, userID string, conversatio
 	return val, nil
 }
 
+func (c *msgCache) SetGroupMemberReadSeqs(ctx context.Context, conversationID string, hasReadSeqs map[string]int64) error {
+	if len(hasReadSeqs) == 0 {
+		return nil
+	}
+	members := make([]redis.Z, 0, len(hasReadSeqs))
+	for userID, seq := range hasReadSeqs {
+		members = append(members, redis.Z{Score: float64(seq), Member: userID})
+	}
+	return errs.Wrap(c.rdb.ZAddGT(ctx, c.getGroupReadSeqKey(conversationID), members...).Err())
+}
+
+func (c *msgCache) GetGroupReadMembers(ctx context.Context, conversationID string, seq int64) ([]string, error) {
+	userIDs, err := c.rdb.ZRangeByScore(ctx, c.getGroupReadSeqKey(conversationID), &redis.ZRangeBy{
+		Min: strconv.FormatInt(seq, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return userIDs, nil
+}
+
 func (c *msgCache) AddTokenFlag(ctx context.Context, userID string, platformID int, token string, flag int) error {
 	key := uidPidToken + userID + ":" + constant.PlatformIDToName(platformID)
 	return errs.Wrap(c.rdb.HSet(ctx, key, token, flag).Err())
@@ -307,6 +371,65 @@ func (c *msgCache) DeleteTokenByUidPid(ctx context.Context, userID string, platf
 	return errs.Wrap(c.rdb.HDel(ctx, key, fields...).Err())
 }
 
+func (c *msgCache) SetLoginPolicyMatrix(ctx context.Context, matrix string) error {
+	return errs.Wrap(c.rdb.Set(ctx, loginPolicyMatrix, matrix, 0).Err())
+}
+
+func (c *msgCache) GetLoginPolicyMatrix(ctx context.Context) (string, error) {
+	val, err := c.rdb.Get(ctx, loginPolicyMatrix).Result()
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+	return val, nil
+}
+
+func (c *msgCache) SetTokenFingerprint(ctx context.Context, token string, fingerprint string, expiration time.Duration) error {
+	return errs.Wrap(c.rdb.Set(ctx, tokenFingerprint+token, fingerprint, expiration).Err())
+}
+
+func (c *msgCache) GetTokenFingerprint(ctx context.Context, token string) (string, error) {
+	val, err := c.rdb.Get(ctx, tokenFingerprint+token).Result()
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+	return val, nil
+}
+
+func (c *msgCache) SetTokenSession(ctx context.Context, token string, fields map[string]string, expiration time.Duration) error {
+	key := tokenSession + token
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, fields)
+		pipe.Expire(ctx, key, expiration)
+		return nil
+	})
+	return errs.Wrap(err)
+}
+
+func (c *msgCache) GetTokenSessions(ctx context.Context, tokens []string) (map[string]map[string]string, error) {
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	pipe := c.rdb.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(tokens))
+	for i, token := range tokens {
+		cmds[i] = pipe.HGetAll(ctx, tokenSession+token)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	sessions := make(map[string]map[string]string, len(tokens))
+	for i, cmd := range cmds {
+		if fields := cmd.Val(); len(fields) > 0 {
+			sessions[tokens[i]] = fields
+		}
+	}
+	return sessions, nil
+}
+
+func (c *msgCache) DelTokenSession(ctx context.Context, token string) error {
+	return errs.Wrap(c.rdb.Del(ctx, tokenSession+token).Err())
+}
+
 func (c *msgCache) getMessageCacheKey(conversationID string, seq int64) string {
 	return messageCache + conversationID + "_" + strconv.Itoa(int(seq))
 }
@@ -605,6 +728,9 @@ func (c *msgCache) DelUserDeleteMsgsList(ctx context.Context, conversationID str
 }
 
 func (c *msgCache) DeleteMessages(ctx context.Context, conversationID string, seqs []int64) error {
+	if err := c.delRecentMessages(ctx, conversationID, seqs); err != nil {
+		return err
+	}
 	if c.config.Redis.EnablePipeline {
 		return c.PipeDeleteMessages(ctx, conversationID, seqs)
 	}
@@ -651,6 +777,9 @@ func (c *msgCache) PipeDeleteMessages(ctx context.Context, conversationID string
 }
 
 func (c *msgCache) CleanUpOneConversationAllMsg(ctx context.Context, conversationID string) error {
+	if err := c.rdb.Del(ctx, c.getRecentMessagesKey(conversationID)).Err(); err != nil {
+		return errs.Wrap(err)
+	}
 	vals, err := c.rdb.Keys(ctx, c.allMessageCacheKey(conversationID)).Result()
 	if errors.Is(err, redis.Nil) {
 		return nil
@@ -667,6 +796,9 @@ func (c *msgCache) CleanUpOneConversationAllMsg(ctx context.Context, conversatio
 }
 
 func (c *msgCache) DelMsgFromCache(ctx context.Context, userID string, seqs []int64) error {
+	if err := c.delRecentMessages(ctx, userID, seqs); err != nil {
+		return err
+	}
 	for _, seq := range seqs {
 		key := c.getMessageCacheKey(userID, seq)
 		result, err := c.rdb.Get(ctx, key).Result()
@@ -719,6 +851,46 @@ func (c *msgCache) GetGetuiTaskID(ctx context.Context) (string, error) {
 	return val, nil
 }
 
+func (c *msgCache) SetHmsToken(ctx context.Context, token string, expireTime int64) error {
+	return errs.Wrap(c.rdb.Set(ctx, hmsToken, token, time.Duration(expireTime)*time.Second).Err())
+}
+
+func (c *msgCache) GetHmsToken(ctx context.Context) (string, error) {
+	val, err := c.rdb.Get(ctx, hmsToken).Result()
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+	return val, nil
+}
+
+func (c *msgCache) DelHmsToken(ctx context.Context) error {
+	return errs.Wrap(c.rdb.Del(ctx, hmsToken).Err())
+}
+
+func (c *msgCache) AddDeliveryLatency(ctx context.Context, latency int64) error {
+	pipe := c.rdb.Pipeline()
+	pipe.LPush(ctx, msgDeliveryLatency, latency)
+	pipe.LTrim(ctx, msgDeliveryLatency, 0, msgDeliveryLatencySamples-1)
+	_, err := pipe.Exec(ctx)
+	return errs.Wrap(err)
+}
+
+func (c *msgCache) GetDeliveryLatencies(ctx context.Context) ([]int64, error) {
+	vals, err := c.rdb.LRange(ctx, msgDeliveryLatency, 0, -1).Result()
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	latencies := make([]int64, 0, len(vals))
+	for _, val := range vals {
+		latency, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			continue
+		}
+		latencies = append(latencies, latency)
+	}
+	return latencies, nil
+}
+
 func (c *msgCache) SetSendMsgStatus(ctx context.Context, id string, status int32) error {
 	return errs.Wrap(c.rdb.Set(ctx, sendMsgFailedFlag+id, status, time.Hour*24).Err())
 }
@@ -0,0 +1,76 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/redis/go-redis/v9"
+)
+
+const msgDedup = "MSG_DEDUP:"
+
+// MsgDedupCache remembers the result of the messages sent in the dedup window by sender and clientMsgID. A
+// claimed message has an empty result until it is sent.
+type MsgDedupCache interface {
+	// Claim returns true when the message was not sent within expire, otherwise the result stored for it.
+	Claim(ctx context.Context, sendID string, clientMsgID string, expire time.Duration) (bool, string, error)
+	SetResult(ctx context.Context, sendID string, clientMsgID string, result string, expire time.Duration) error
+	// Release forgets a claimed message so that it can be sent again.
+	Release(ctx context.Context, sendID string, clientMsgID string) error
+}
+
+func NewMsgDedupCache(rdb redis.UniversalClient) MsgDedupCache {
+	return &msgDedupCache{rdb: rdb}
+}
+
+type msgDedupCache struct {
+	rdb redis.UniversalClient
+}
+
+func (m *msgDedupCache) getKey(sendID string, clientMsgID string) string {
+	return msgDedup + sendID + ":" + clientMsgID
+}
+
+func (m *msgDedupCache) Claim(ctx context.Context, sendID string, clientMsgID string, expire time.Duration) (bool, string, error) {
+	key := m.getKey(sendID, clientMsgID)
+	ok, err := m.rdb.SetNX(ctx, key, "", expire).Result()
+	if err != nil {
+		return false, "", errs.Wrap(err)
+	}
+	if ok {
+		return true, "", nil
+	}
+	result, err := m.rdb.Get(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			// the claim expired in between, the message can be sent once more
+			return m.Claim(ctx, sendID, clientMsgID, expire)
+		}
+		return false, "", errs.Wrap(err)
+	}
+	return false, result, nil
+}
+
+func (m *msgDedupCache) SetResult(ctx context.Context, sendID string, clientMsgID string, result string, expire time.Duration) error {
+	return errs.Wrap(m.rdb.Set(ctx, m.getKey(sendID, clientMsgID), result, expire).Err())
+}
+
+func (m *msgDedupCache) Release(ctx context.Context, sendID string, clientMsgID string) error {
+	return errs.Wrap(m.rdb.Del(ctx, m.getKey(sendID, clientMsgID)).Err())
+}
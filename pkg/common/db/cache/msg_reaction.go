@@ -0,0 +1,87 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/dtm-labs/rockscache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/cachekey"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	msgReactionExpireTime = time.Second * 60 * 60 * 12
+)
+
+type MsgReactionCache interface {
+	metaCache
+	NewCache() MsgReactionCache
+	GetMsgReactions(ctx context.Context, conversationID string, seq int64) ([]*relationtb.MsgReactionModel, error)
+	DelMsgReactions(conversationID string, seq int64) MsgReactionCache
+}
+
+type MsgReactionCacheRedis struct {
+	metaCache
+	expireTime    time.Duration
+	rcClient      *rockscache.Client
+	msgReactionDB relationtb.MsgReactionModelInterface
+}
+
+func NewMsgReactionCacheRedis(rdb redis.UniversalClient, msgReactionDB relationtb.MsgReactionModelInterface, options rockscache.Options) MsgReactionCache {
+	rcClient := rockscache.NewClient(rdb, options)
+	mc := NewMetaCacheRedis(rcClient)
+	mc.SetRawRedisClient(rdb)
+	return &MsgReactionCacheRedis{
+		expireTime:    msgReactionExpireTime,
+		rcClient:      rcClient,
+		metaCache:     mc,
+		msgReactionDB: msgReactionDB,
+	}
+}
+
+func (m *MsgReactionCacheRedis) NewCache() MsgReactionCache {
+	return &MsgReactionCacheRedis{
+		expireTime:    m.expireTime,
+		rcClient:      m.rcClient,
+		msgReactionDB: m.msgReactionDB,
+		metaCache:     m.Copy(),
+	}
+}
+
+func (m *MsgReactionCacheRedis) getMsgReactionKey(conversationID string, seq int64) string {
+	return cachekey.GetMsgReactionKey(conversationID, seq)
+}
+
+func (m *MsgReactionCacheRedis) GetMsgReactions(ctx context.Context, conversationID string, seq int64) ([]*relationtb.MsgReactionModel, error) {
+	return getCache(
+		ctx,
+		m.rcClient,
+		m.getMsgReactionKey(conversationID, seq),
+		m.expireTime,
+		func(ctx context.Context) ([]*relationtb.MsgReactionModel, error) {
+			return m.msgReactionDB.Find(ctx, conversationID, seq)
+		},
+	)
+}
+
+func (m *MsgReactionCacheRedis) DelMsgReactions(conversationID string, seq int64) MsgReactionCache {
+	cache := m.NewCache()
+	cache.AddKeys(m.getMsgReactionKey(conversationID, seq))
+
+	return cache
+}
@@ -0,0 +1,74 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	notificationQuotaRate  = "NOTIFICATION_QUOTA_RATE:"
+	notificationQuotaDaily = "NOTIFICATION_QUOTA_DAILY:"
+)
+
+// NotificationQuotaCache counts the messages of the notification accounts per second and per recipient per day.
+type NotificationQuotaCache interface {
+	// IncrRate counts a message of accountID in the second second and returns the count.
+	IncrRate(ctx context.Context, accountID string, second int64) (int64, error)
+	// IncrDaily counts a message of accountID to userID on day and returns the count.
+	IncrDaily(ctx context.Context, accountID string, userID string, day string) (int64, error)
+	// GetDaily returns the number of messages of accountID to each of userIDs on day.
+	GetDaily(ctx context.Context, accountID string, userIDs []string, day string) (map[string]int64, error)
+}
+
+func NewNotificationQuotaCache(rdb redis.UniversalClient) NotificationQuotaCache {
+	return &notificationQuotaCache{rdb: rdb}
+}
+
+type notificationQuotaCache struct {
+	rdb redis.UniversalClient
+}
+
+func (n *notificationQuotaCache) getDailyKey(accountID string, userID string, day string) string {
+	return notificationQuotaDaily + accountID + ":" + day + ":" + userID
+}
+
+func (n *notificationQuotaCache) IncrRate(ctx context.Context, accountID string, second int64) (int64, error) {
+	return incrExpire(ctx, n.rdb, notificationQuotaRate+accountID+":"+strconv.FormatInt(second, 10), time.Second*2)
+}
+
+func (n *notificationQuotaCache) IncrDaily(ctx context.Context, accountID string, userID string, day string) (int64, error) {
+	// Kept past the end of the day so that the count of the day can still be read.
+	return incrExpire(ctx, n.rdb, n.getDailyKey(accountID, userID, day), time.Hour*48)
+}
+
+func (n *notificationQuotaCache) GetDaily(ctx context.Context, accountID string, userIDs []string, day string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(userIDs))
+	// The keys are read one by one, they may be on different slots of a cluster.
+	for _, userID := range userIDs {
+		count, err := n.rdb.Get(ctx, n.getDailyKey(accountID, userID, day)).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, errs.Wrap(err)
+		}
+		counts[userID] = count
+	}
+	return counts, nil
+}
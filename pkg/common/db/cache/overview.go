@@ -0,0 +1,94 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	overviewReport   = "OVERVIEW_REPORT:"
+	topologyInstance = "TOPOLOGY_INSTANCE:"
+)
+
+// OverviewCache holds the latest metrics report of every running instance of a component, a report that is
+// not refreshed before it expires is dropped on read.
+type OverviewCache interface {
+	SetReport(ctx context.Context, component string, instance string, report string, expiration time.Duration) error
+	// GetReports returns the unexpired reports of component by instance.
+	GetReports(ctx context.Context, component string) (map[string]string, error)
+}
+
+func NewOverviewCache(rdb redis.UniversalClient) OverviewCache {
+	return &overviewCache{rdb: rdb, prefix: overviewReport}
+}
+
+// NewTopologyCache holds what every running instance of a component reports about itself for the topology view,
+// keyed by component and instance like the overview reports.
+func NewTopologyCache(rdb redis.UniversalClient) OverviewCache {
+	return &overviewCache{rdb: rdb, prefix: topologyInstance}
+}
+
+type overviewCache struct {
+	rdb    redis.UniversalClient
+	prefix string
+}
+
+type overviewEntry struct {
+	Report   string `json:"report"`
+	ExpireAt int64  `json:"expireAt"`
+}
+
+func (o *overviewCache) getReportKey(component string) string {
+	return o.prefix + component
+}
+
+func (o *overviewCache) SetReport(ctx context.Context, component string, instance string, report string, expiration time.Duration) error {
+	data, err := json.Marshal(overviewEntry{Report: report, ExpireAt: time.Now().Add(expiration).UnixMilli()})
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return errs.Wrap(o.rdb.HSet(ctx, o.getReportKey(component), instance, string(data)).Err())
+}
+
+func (o *overviewCache) GetReports(ctx context.Context, component string) (map[string]string, error) {
+	key := o.getReportKey(component)
+	values, err := o.rdb.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	now := time.Now().UnixMilli()
+	reports := make(map[string]string, len(values))
+	var expired []string
+	for instance, value := range values {
+		var entry overviewEntry
+		if err := json.Unmarshal([]byte(value), &entry); err != nil || entry.ExpireAt < now {
+			expired = append(expired, instance)
+			continue
+		}
+		reports[instance] = entry.Report
+	}
+	if len(expired) > 0 {
+		if err := o.rdb.HDel(ctx, key, expired...).Err(); err != nil {
+			return nil, errs.Wrap(err)
+		}
+	}
+	return reports, nil
+}
@@ -0,0 +1,133 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	pushRetryEntry = "PUSH_RETRY_ENTRY"
+	pushRetryQueue = "PUSH_RETRY_QUEUE"
+	pushDeadLetter = "PUSH_DEAD_LETTER"
+)
+
+// PushRetryCache keeps failed offline pushes: entries are scheduled in the retry queue by their next attempt
+// time and moved to the dead letter queue once their attempts are exhausted.
+type PushRetryCache interface {
+	// AddRetry stores the entry and schedules its next attempt at due.
+	AddRetry(ctx context.Context, id string, entry string, due time.Time) error
+	// ClaimDue removes up to count entries due at now from the retry queue, each id is claimed by one caller only.
+	ClaimDue(ctx context.Context, now time.Time, count int64) ([]string, error)
+	GetEntries(ctx context.Context, ids []string) (map[string]string, error)
+	DelEntry(ctx context.Context, id string) error
+	AddDeadLetter(ctx context.Context, id string, entry string, deadTime time.Time) error
+	// RemoveDeadLetter returns false when id is not in the dead letter queue.
+	RemoveDeadLetter(ctx context.Context, id string) (bool, error)
+	// PageDeadLetters returns the dead letter ids, most recent first.
+	PageDeadLetters(ctx context.Context, offset int64, count int64) (int64, []string, error)
+}
+
+func NewPushRetryCache(rdb redis.UniversalClient) PushRetryCache {
+	return &pushRetryCache{rdb: rdb}
+}
+
+type pushRetryCache struct {
+	rdb redis.UniversalClient
+}
+
+func (p *pushRetryCache) AddRetry(ctx context.Context, id string, entry string, due time.Time) error {
+	pipe := p.rdb.Pipeline()
+	pipe.HSet(ctx, pushRetryEntry, id, entry)
+	pipe.ZAdd(ctx, pushRetryQueue, redis.Z{Score: float64(due.UnixMilli()), Member: id})
+	_, err := pipe.Exec(ctx)
+	return errs.Wrap(err)
+}
+
+func (p *pushRetryCache) ClaimDue(ctx context.Context, now time.Time, count int64) ([]string, error) {
+	ids, err := p.rdb.ZRangeByScore(ctx, pushRetryQueue, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.UnixMilli(), 10),
+		Count: count,
+	}).Result()
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	claimed := make([]string, 0, len(ids))
+	for _, id := range ids {
+		n, err := p.rdb.ZRem(ctx, pushRetryQueue, id).Result()
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		if n > 0 {
+			claimed = append(claimed, id)
+		}
+	}
+	return claimed, nil
+}
+
+func (p *pushRetryCache) GetEntries(ctx context.Context, ids []string) (map[string]string, error) {
+	if len(ids) == 0 {
+		return map[string]string{}, nil
+	}
+	vals, err := p.rdb.HMGet(ctx, pushRetryEntry, ids...).Result()
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	entries := make(map[string]string, len(ids))
+	for i, val := range vals {
+		if entry, ok := val.(string); ok {
+			entries[ids[i]] = entry
+		}
+	}
+	return entries, nil
+}
+
+func (p *pushRetryCache) DelEntry(ctx context.Context, id string) error {
+	return errs.Wrap(p.rdb.HDel(ctx, pushRetryEntry, id).Err())
+}
+
+func (p *pushRetryCache) AddDeadLetter(ctx context.Context, id string, entry string, deadTime time.Time) error {
+	pipe := p.rdb.Pipeline()
+	pipe.HSet(ctx, pushRetryEntry, id, entry)
+	pipe.ZAdd(ctx, pushDeadLetter, redis.Z{Score: float64(deadTime.UnixMilli()), Member: id})
+	_, err := pipe.Exec(ctx)
+	return errs.Wrap(err)
+}
+
+func (p *pushRetryCache) RemoveDeadLetter(ctx context.Context, id string) (bool, error) {
+	n, err := p.rdb.ZRem(ctx, pushDeadLetter, id).Result()
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return n > 0, nil
+}
+
+func (p *pushRetryCache) PageDeadLetters(ctx context.Context, offset int64, count int64) (int64, []string, error) {
+	total, err := p.rdb.ZCard(ctx, pushDeadLetter).Result()
+	if err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	ids, err := p.rdb.ZRevRange(ctx, pushDeadLetter, offset, offset+count-1).Result()
+	if err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	return total, ids, nil
+}
@@ -0,0 +1,99 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"github.com/redis/go-redis/v9"
+)
+
+const recentMessages = "RECENT_MESSAGES:"
+
+// The latest messages of a conversation are kept in a sorted set scored by seq, so that a range of seqs is read
+// with a single request.
+
+func (c *msgCache) getRecentMessagesKey(conversationID string) string {
+	return recentMessages + conversationID
+}
+
+func (c *msgCache) AddRecentMessages(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	members := make([]redis.Z, 0, len(msgs))
+	for _, msg := range msgs {
+		s, err := msgprocessor.Pb2String(msg)
+		if err != nil {
+			return err
+		}
+		members = append(members, redis.Z{Score: float64(msg.Seq), Member: s})
+	}
+	key := c.getRecentMessagesKey(conversationID)
+	size := int64(c.config.RecentMsgCache.Size)
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		// A seq is written again when msgtransfer retries a batch, the old copy must not stay next to the new one.
+		for _, msg := range msgs {
+			seq := strconv.FormatInt(msg.Seq, 10)
+			pipe.ZRemRangeByScore(ctx, key, seq, seq)
+		}
+		pipe.ZAdd(ctx, key, members...)
+		pipe.ZRemRangeByRank(ctx, key, 0, -size-1)
+		pipe.Expire(ctx, key, time.Duration(c.config.RecentMsgCache.Expire)*time.Second)
+		return nil
+	})
+	return errs.Wrap(err)
+}
+
+func (c *msgCache) GetRecentMessages(ctx context.Context, conversationID string, begin, end int64) ([]*sdkws.MsgData, error) {
+	vals, err := c.rdb.ZRangeByScore(ctx, c.getRecentMessagesKey(conversationID), &redis.ZRangeBy{
+		Min: strconv.FormatInt(begin, 10),
+		Max: strconv.FormatInt(end, 10),
+	}).Result()
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	msgs := make([]*sdkws.MsgData, 0, len(vals))
+	for _, val := range vals {
+		var msg sdkws.MsgData
+		if err := msgprocessor.String2Pb(val, &msg); err != nil {
+			log.ZError(ctx, "GetRecentMessages Unmarshal failed", err, "conversationID", conversationID)
+			continue
+		}
+		msgs = append(msgs, &msg)
+	}
+	return msgs, nil
+}
+
+// delRecentMessages drops seqs from the latest messages, the next read of them goes to the message cache.
+func (c *msgCache) delRecentMessages(ctx context.Context, conversationID string, seqs []int64) error {
+	if len(seqs) == 0 {
+		return nil
+	}
+	key := c.getRecentMessagesKey(conversationID)
+	pipe := c.rdb.Pipeline()
+	for _, seq := range seqs {
+		s := strconv.FormatInt(seq, 10)
+		pipe.ZRemRangeByScore(ctx, key, s, s)
+	}
+	_, err := pipe.Exec(ctx)
+	return errs.Wrap(err)
+}
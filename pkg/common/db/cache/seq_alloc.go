@@ -0,0 +1,151 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	seqSegment = "SEQ_SEGMENT:"
+	// seqLeaseAttempts bounds the leases of one Malloc racing other allocators of the conversation.
+	seqLeaseAttempts = 5
+)
+
+// SeqAllocator hands out the seqs of new messages from segments leased in mongo. The segment of a conversation
+// lives in redis, a segment lost on failover is recovered from the high-water mark in mongo so that no seq is
+// handed out twice, at the cost of a gap of at most one segment.
+type SeqAllocator interface {
+	// Malloc reserves size seqs of the conversation and returns the seq before them, 0 for its first messages.
+	Malloc(ctx context.Context, conversationID string, size int64) (int64, error)
+}
+
+// seqMallocScript takes size seqs from the segment, it returns {1, curr} on success, {0, curr, last} when the
+// segment is too short and {-1} when there is none.
+var seqMallocScript = redis.NewScript(`
+local curr = redis.call('HGET', KEYS[1], 'curr')
+local last = redis.call('HGET', KEYS[1], 'last')
+if not curr or not last then
+	return {-1}
+end
+curr = tonumber(curr)
+last = tonumber(last)
+local size = tonumber(ARGV[1])
+if curr + size > last then
+	return {0, curr, last}
+end
+redis.call('HSET', KEYS[1], 'curr', curr + size)
+return {1, curr}
+`)
+
+// seqSetScript replaces the segment unless it changed since it was read, ARGV[1] is the curr read, empty when
+// there was no segment.
+var seqSetScript = redis.NewScript(`
+local curr = redis.call('HGET', KEYS[1], 'curr')
+if (curr or '') ~= ARGV[1] then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'curr', ARGV[2], 'last', ARGV[3])
+return 1
+`)
+
+func NewSeqAllocator(rdb redis.UniversalClient, seqDB relation.SeqModelInterface, segmentSize int64) SeqAllocator {
+	if segmentSize <= 0 {
+		segmentSize = 1
+	}
+	return &seqAllocator{rdb: rdb, seqDB: seqDB, segmentSize: segmentSize}
+}
+
+type seqAllocator struct {
+	rdb         redis.UniversalClient
+	seqDB       relation.SeqModelInterface
+	segmentSize int64
+}
+
+func (s *seqAllocator) getSegmentKey(conversationID string) string {
+	return seqSegment + conversationID
+}
+
+func (s *seqAllocator) Malloc(ctx context.Context, conversationID string, size int64) (int64, error) {
+	if size <= 0 {
+		return 0, errs.ErrArgs.Wrap("seq size must be positive")
+	}
+	key := s.getSegmentKey(conversationID)
+	for i := 0; i < seqLeaseAttempts; i++ {
+		res, err := seqMallocScript.Run(ctx, s.rdb, []string{key}, size).Int64Slice()
+		if err != nil {
+			return 0, errs.Wrap(err)
+		}
+		var expected string
+		switch res[0] {
+		case 1:
+			return res[1], nil
+		case 0:
+			expected = strconv.FormatInt(res[1], 10)
+		default:
+			// The segment is new or was lost, messages sent before the allocator was enabled only moved MAX_SEQ.
+			if err := s.raiseToMaxSeq(ctx, conversationID); err != nil {
+				return 0, err
+			}
+		}
+		lease := s.segmentSize
+		if size > lease {
+			lease = size
+		}
+		prev, err := s.seqDB.Malloc(ctx, conversationID, lease)
+		if err != nil {
+			return 0, err
+		}
+		prommetrics.SeqSegmentLeaseCounter.Inc()
+		// The lease follows the segment when nobody leased in between, the rest of the segment is kept.
+		start := prev
+		if res[0] == 0 && res[2] == prev {
+			start = res[1]
+		} else if res[0] == 0 || prev > 0 {
+			prommetrics.SeqSegmentRecoverCounter.Inc()
+			log.ZWarn(ctx, "seq segment recovered from the high-water mark", nil, "conversationID", conversationID, "seq", prev)
+		}
+		ok, err := seqSetScript.Run(ctx, s.rdb, []string{key}, expected, start+size, prev+lease).Bool()
+		if err != nil {
+			return 0, errs.Wrap(err)
+		}
+		if ok {
+			return start, nil
+		}
+		// Another allocator changed the segment first, the lease is dropped and leaves a gap.
+		prommetrics.SeqSegmentConflictCounter.Inc()
+	}
+	return 0, errs.ErrInternalServer.Wrap("seq segment of " + conversationID + " keeps changing")
+}
+
+// raiseToMaxSeq raises the high-water mark to MAX_SEQ of the conversation.
+func (s *seqAllocator) raiseToMaxSeq(ctx context.Context, conversationID string) error {
+	seq, err := s.rdb.Get(ctx, maxSeq+conversationID).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		return errs.Wrap(err)
+	}
+	return s.seqDB.Raise(ctx, conversationID, seq)
+}
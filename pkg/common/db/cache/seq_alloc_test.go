@@ -0,0 +1,130 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+type memorySeqDB struct {
+	lock sync.Mutex
+	seqs map[string]int64
+}
+
+func (m *memorySeqDB) Malloc(_ context.Context, conversationID string, size int64) (int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	prev := m.seqs[conversationID]
+	m.seqs[conversationID] = prev + size
+	return prev, nil
+}
+
+func (m *memorySeqDB) Raise(_ context.Context, conversationID string, seq int64) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.seqs[conversationID] < seq {
+		m.seqs[conversationID] = seq
+	}
+	return nil
+}
+
+func (m *memorySeqDB) Get(_ context.Context, conversationID string) (int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.seqs[conversationID], nil
+}
+
+func TestSeqAllocatorMalloc(t *testing.T) {
+	ctx := context.Background()
+	rdb := redis.NewClient(&redis.Options{})
+	defer rdb.Close()
+	cid := fmt.Sprintf("cid-%v", rand.Int63())
+	seqDB := &memorySeqDB{seqs: make(map[string]int64)}
+	alloc := NewSeqAllocator(rdb, seqDB, 10)
+	defer rdb.Del(ctx, seqSegment+cid, maxSeq+cid)
+
+	var seqs []int64
+	for _, size := range []int64{1, 4, 8, 20} {
+		seq, err := alloc.Malloc(ctx, cid, size)
+		assert.Nil(t, err)
+		seqs = append(seqs, seq)
+	}
+	// Segments are extended in place, the seqs are contiguous.
+	assert.Equal(t, []int64{0, 1, 5, 13}, seqs)
+
+	// A lost segment resumes above the high-water mark.
+	mark, _ := seqDB.Get(ctx, cid)
+	assert.Nil(t, rdb.Del(ctx, seqSegment+cid).Err())
+	seq, err := alloc.Malloc(ctx, cid, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, mark, seq)
+}
+
+func TestSeqAllocatorMaxSeq(t *testing.T) {
+	ctx := context.Background()
+	rdb := redis.NewClient(&redis.Options{})
+	defer rdb.Close()
+	cid := fmt.Sprintf("cid-%v", rand.Int63())
+	defer rdb.Del(ctx, seqSegment+cid, maxSeq+cid)
+	assert.Nil(t, rdb.Set(ctx, maxSeq+cid, 42, 0).Err())
+
+	// Seqs handed out before the allocator was enabled are not handed out again.
+	seq, err := NewSeqAllocator(rdb, &memorySeqDB{seqs: make(map[string]int64)}, 10).Malloc(ctx, cid, 1)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 42, seq)
+}
+
+func TestSeqAllocatorParallel(t *testing.T) {
+	ctx := context.Background()
+	rdb := redis.NewClient(&redis.Options{})
+	defer rdb.Close()
+	cid := fmt.Sprintf("cid-%v", rand.Int63())
+	defer rdb.Del(ctx, seqSegment+cid, maxSeq+cid)
+	seqDB := &memorySeqDB{seqs: make(map[string]int64)}
+
+	var (
+		lock sync.Mutex
+		seen = make(map[int64]struct{})
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < 4; i++ {
+		alloc := NewSeqAllocator(rdb, seqDB, 3)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				seq, err := alloc.Malloc(ctx, cid, 2)
+				if !assert.Nil(t, err) {
+					return
+				}
+				lock.Lock()
+				for _, s := range []int64{seq + 1, seq + 2} {
+					_, ok := seen[s]
+					assert.False(t, ok, "seq %d handed out twice", s)
+					seen[s] = struct{}{}
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
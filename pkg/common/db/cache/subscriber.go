@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package rpccache
+package cache
 
 import (
 	"context"
@@ -22,12 +22,14 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-func subscriberRedisDeleteCache(ctx context.Context, client redis.UniversalClient, channel string, del func(ctx context.Context, key ...string)) {
+// SubscribeDeleteCache drops the keys published on channel by ExecDel from a local cache with del, it returns when
+// ctx is done.
+func SubscribeDeleteCache(ctx context.Context, client redis.UniversalClient, channel string, del func(ctx context.Context, key ...string)) {
 	for message := range client.Subscribe(ctx, channel).Channel() {
-		log.ZDebug(ctx, "subscriberRedisDeleteCache", "channel", channel, "payload", message.Payload)
+		log.ZDebug(ctx, "SubscribeDeleteCache", "channel", channel, "payload", message.Payload)
 		var keys []string
 		if err := json.Unmarshal([]byte(message.Payload), &keys); err != nil {
-			log.ZError(ctx, "subscriberRedisDeleteCache json.Unmarshal error", err)
+			log.ZError(ctx, "SubscribeDeleteCache json.Unmarshal error", err)
 			continue
 		}
 		if len(keys) == 0 {
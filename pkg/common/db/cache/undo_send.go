@@ -0,0 +1,125 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/redis/go-redis/v9"
+)
+
+// The queue, the leases and the entries share a hash tag so the scripts touching them run in one cluster slot.
+const (
+	undoSendQueue   = "{UNDO_SEND}:QUEUE"
+	undoSendLease   = "{UNDO_SEND}:LEASE"
+	undoSendEntries = "{UNDO_SEND}:ENTRY"
+)
+
+// undoSendClaimScript moves the ids due at ARGV[1], and the ids whose lease ended by then, to the leases until
+// ARGV[2]. It returns the ids followed by their entries, ids whose entry is gone are dropped.
+var undoSendClaimScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[3])
+local count = tonumber(ARGV[3]) - #ids
+if count > 0 then
+	for _, id in ipairs(redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[1], 'LIMIT', 0, count)) do
+		table.insert(ids, id)
+	end
+end
+local res = {}
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[1], id)
+	local entry = redis.call('HGET', KEYS[3], id)
+	if entry then
+		redis.call('ZADD', KEYS[2], ARGV[2], id)
+		table.insert(res, id)
+		table.insert(res, entry)
+	else
+		redis.call('ZREM', KEYS[2], id)
+	end
+end
+return res
+`)
+
+// undoSendCancelScript drops the entry of ARGV[1] while it is still waiting in the queue, it returns 0 once the
+// entry was claimed.
+var undoSendCancelScript = redis.NewScript(`
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('HDEL', KEYS[2], ARGV[1])
+return 1
+`)
+
+// UndoSendCache holds messages during the undo send window, entries are queued by their release time. An entry is
+// either cancelled while it waits or claimed by the release, a claimed entry stays until it is acked.
+type UndoSendCache interface {
+	Hold(ctx context.Context, id string, entry string, due time.Time) error
+	// ClaimDue leases up to count entries due at now until now+lease and returns their ids and entries. An entry
+	// not acked before its lease ends is claimed again.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, count int64) ([]string, []string, error)
+	// Ack deletes a claimed entry once it was released.
+	Ack(ctx context.Context, id string) error
+	// Cancel deletes the entry of id, false when it was already claimed or never held.
+	Cancel(ctx context.Context, id string) (bool, error)
+}
+
+func NewUndoSendCache(rdb redis.UniversalClient) UndoSendCache {
+	return &undoSendCache{rdb: rdb}
+}
+
+type undoSendCache struct {
+	rdb redis.UniversalClient
+}
+
+func (u *undoSendCache) Hold(ctx context.Context, id string, entry string, due time.Time) error {
+	pipe := u.rdb.TxPipeline()
+	pipe.HSet(ctx, undoSendEntries, id, entry)
+	pipe.ZAdd(ctx, undoSendQueue, redis.Z{Score: float64(due.UnixMilli()), Member: id})
+	_, err := pipe.Exec(ctx)
+	return errs.Wrap(err)
+}
+
+func (u *undoSendCache) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, count int64) ([]string, []string, error) {
+	keys := []string{undoSendQueue, undoSendLease, undoSendEntries}
+	res, err := undoSendClaimScript.Run(ctx, u.rdb, keys, now.UnixMilli(), now.Add(lease).UnixMilli(), count).StringSlice()
+	if err != nil {
+		return nil, nil, errs.Wrap(err)
+	}
+	ids := make([]string, 0, len(res)/2)
+	entries := make([]string, 0, len(res)/2)
+	for i := 0; i+1 < len(res); i += 2 {
+		ids = append(ids, res[i])
+		entries = append(entries, res[i+1])
+	}
+	return ids, entries, nil
+}
+
+func (u *undoSendCache) Ack(ctx context.Context, id string) error {
+	pipe := u.rdb.TxPipeline()
+	pipe.ZRem(ctx, undoSendLease, id)
+	pipe.HDel(ctx, undoSendEntries, id)
+	_, err := pipe.Exec(ctx)
+	return errs.Wrap(err)
+}
+
+func (u *undoSendCache) Cancel(ctx context.Context, id string) (bool, error) {
+	n, err := undoSendCancelScript.Run(ctx, u.rdb, []string{undoSendQueue, undoSendEntries}, id).Int64()
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return n == 1, nil
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/dtm-labs/rockscache"
+	"github.com/openimsdk/localcache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/cachekey"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
@@ -62,6 +63,7 @@ type UserCacheRedis struct {
 	userDB     relationtb.UserModelInterface
 	expireTime time.Duration
 	rcClient   *rockscache.Client
+	local      localcache.Cache[string]
 }
 
 func NewUserCacheRedis(
@@ -75,12 +77,15 @@ func NewUserCacheRedis(
 	log.ZDebug(context.Background(), "user local cache init", "Topic", u.Topic, "SlotNum", u.SlotNum, "SlotSize", u.SlotSize, "enable", u.Enable())
 	mc.SetTopic(u.Topic)
 	mc.SetRawRedisClient(rdb)
+	local := newLocalTier(rdb, u)
+	mc.SetLocalTier(local)
 	return &UserCacheRedis{
 		rdb:        rdb,
-		metaCache:  NewMetaCacheRedis(rcClient),
+		metaCache:  mc,
 		userDB:     userDB,
 		expireTime: userExpireTime,
 		rcClient:   rcClient,
+		local:      local,
 	}
 }
 
@@ -91,6 +96,7 @@ func (u *UserCacheRedis) NewCache() UserCache {
 		userDB:     u.userDB,
 		expireTime: u.expireTime,
 		rcClient:   u.rcClient,
+		local:      u.local,
 	}
 }
 
@@ -103,13 +109,13 @@ func (u *UserCacheRedis) getUserGlobalRecvMsgOptKey(userID string) string {
 }
 
 func (u *UserCacheRedis) GetUserInfo(ctx context.Context, userID string) (userInfo *relationtb.UserModel, err error) {
-	return getCache(ctx, u.rcClient, u.getUserInfoKey(userID), u.expireTime, func(ctx context.Context) (*relationtb.UserModel, error) {
+	return getLocalCache(ctx, u.local, u.rcClient, u.getUserInfoKey(userID), u.expireTime, func(ctx context.Context) (*relationtb.UserModel, error) {
 		return u.userDB.Take(ctx, userID)
 	})
 }
 
 func (u *UserCacheRedis) GetUsersInfo(ctx context.Context, userIDs []string) ([]*relationtb.UserModel, error) {
-	return batchGetCache2(ctx, u.rcClient, u.expireTime, userIDs, func(userID string) string {
+	return batchGetCache2(ctx, u.local, u.rcClient, u.expireTime, userIDs, func(userID string) string {
 		return u.getUserInfoKey(userID)
 	}, func(ctx context.Context, userID string) (*relationtb.UserModel, error) {
 		return u.userDB.Take(ctx, userID)
@@ -128,8 +134,9 @@ func (u *UserCacheRedis) DelUsersInfo(userIDs ...string) UserCache {
 }
 
 func (u *UserCacheRedis) GetUserGlobalRecvMsgOpt(ctx context.Context, userID string) (opt int, err error) {
-	return getCache(
+	return getLocalCache(
 		ctx,
+		u.local,
 		u.rcClient,
 		u.getUserGlobalRecvMsgOptKey(userID),
 		u.expireTime,
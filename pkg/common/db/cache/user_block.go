@@ -0,0 +1,76 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/dtm-labs/rockscache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/cachekey"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	userBlockExpireTime = time.Second * 60 * 60 * 12
+)
+
+type UserBlockCache interface {
+	metaCache
+	NewCache() UserBlockCache
+	GetBlockUserIDs(ctx context.Context, ownerUserID string) ([]string, error)
+	DelBlockUserIDs(ownerUserID string) UserBlockCache
+}
+
+type UserBlockCacheRedis struct {
+	metaCache
+	expireTime time.Duration
+	rcClient   *rockscache.Client
+	blockDB    relationtb.UserBlockModelInterface
+}
+
+func NewUserBlockCacheRedis(rdb redis.UniversalClient, blockDB relationtb.UserBlockModelInterface, options rockscache.Options) UserBlockCache {
+	rcClient := rockscache.NewClient(rdb, options)
+	mc := NewMetaCacheRedis(rcClient)
+	mc.SetRawRedisClient(rdb)
+	return &UserBlockCacheRedis{
+		expireTime: userBlockExpireTime,
+		rcClient:   rcClient,
+		metaCache:  mc,
+		blockDB:    blockDB,
+	}
+}
+
+func (u *UserBlockCacheRedis) NewCache() UserBlockCache {
+	return &UserBlockCacheRedis{
+		expireTime: u.expireTime,
+		rcClient:   u.rcClient,
+		blockDB:    u.blockDB,
+		metaCache:  u.Copy(),
+	}
+}
+
+func (u *UserBlockCacheRedis) GetBlockUserIDs(ctx context.Context, ownerUserID string) ([]string, error) {
+	return getCache(ctx, u.rcClient, cachekey.GetUserBlockIDsKey(ownerUserID), u.expireTime, func(ctx context.Context) ([]string, error) {
+		return u.blockDB.FindBlockUserIDs(ctx, ownerUserID)
+	})
+}
+
+func (u *UserBlockCacheRedis) DelBlockUserIDs(ownerUserID string) UserBlockCache {
+	cache := u.NewCache()
+	cache.AddKeys(cachekey.GetUserBlockIDsKey(ownerUserID))
+	return cache
+}
@@ -0,0 +1,145 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/dtm-labs/rockscache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/cachekey"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	userMuteExpireTime = time.Second * 60 * 60 * 12
+	// mutedSeqsExpireTime bounds how long the muted messages of a conversation nobody reads are remembered.
+	mutedSeqsExpireTime = time.Hour * 24 * 30
+)
+
+type UserMuteCache interface {
+	metaCache
+	NewCache() UserMuteCache
+	GetMuteUserIDs(ctx context.Context, ownerUserID string) ([]string, error)
+	// GetOwnerUserIDs returns the users who muted muteUserID.
+	GetOwnerUserIDs(ctx context.Context, muteUserID string) ([]string, error)
+	DelMuteUserIDs(ownerUserID string) UserMuteCache
+	DelOwnerUserIDs(muteUserIDs ...string) UserMuteCache
+	// AddMutedSeq remembers that the message seq of conversationID is muted for userIDs.
+	AddMutedSeq(ctx context.Context, userIDs []string, conversationID string, seq int64) error
+	// CountMutedSeqs returns the number of muted seqs above the has read seq of each conversation, the seqs already
+	// read are forgotten.
+	CountMutedSeqs(ctx context.Context, userID string, hasReadSeqs map[string]int64) (map[string]int64, error)
+}
+
+type UserMuteCacheRedis struct {
+	metaCache
+	expireTime time.Duration
+	rdb        redis.UniversalClient
+	rcClient   *rockscache.Client
+	muteDB     relationtb.UserMuteModelInterface
+}
+
+func NewUserMuteCacheRedis(rdb redis.UniversalClient, muteDB relationtb.UserMuteModelInterface, options rockscache.Options) UserMuteCache {
+	rcClient := rockscache.NewClient(rdb, options)
+	mc := NewMetaCacheRedis(rcClient)
+	mc.SetRawRedisClient(rdb)
+	return &UserMuteCacheRedis{
+		expireTime: userMuteExpireTime,
+		rdb:        rdb,
+		rcClient:   rcClient,
+		metaCache:  mc,
+		muteDB:     muteDB,
+	}
+}
+
+func (u *UserMuteCacheRedis) NewCache() UserMuteCache {
+	return &UserMuteCacheRedis{
+		expireTime: u.expireTime,
+		rdb:        u.rdb,
+		rcClient:   u.rcClient,
+		muteDB:     u.muteDB,
+		metaCache:  u.Copy(),
+	}
+}
+
+func (u *UserMuteCacheRedis) GetMuteUserIDs(ctx context.Context, ownerUserID string) ([]string, error) {
+	return getCache(ctx, u.rcClient, cachekey.GetUserMuteIDsKey(ownerUserID), u.expireTime, func(ctx context.Context) ([]string, error) {
+		return u.muteDB.FindMuteUserIDs(ctx, ownerUserID)
+	})
+}
+
+func (u *UserMuteCacheRedis) GetOwnerUserIDs(ctx context.Context, muteUserID string) ([]string, error) {
+	return getCache(ctx, u.rcClient, cachekey.GetUserMuteOwnerIDsKey(muteUserID), u.expireTime, func(ctx context.Context) ([]string, error) {
+		return u.muteDB.FindOwnerUserIDs(ctx, muteUserID)
+	})
+}
+
+func (u *UserMuteCacheRedis) DelMuteUserIDs(ownerUserID string) UserMuteCache {
+	cache := u.NewCache()
+	cache.AddKeys(cachekey.GetUserMuteIDsKey(ownerUserID))
+	return cache
+}
+
+func (u *UserMuteCacheRedis) DelOwnerUserIDs(muteUserIDs ...string) UserMuteCache {
+	keys := make([]string, 0, len(muteUserIDs))
+	for _, muteUserID := range muteUserIDs {
+		keys = append(keys, cachekey.GetUserMuteOwnerIDsKey(muteUserID))
+	}
+	cache := u.NewCache()
+	cache.AddKeys(keys...)
+	return cache
+}
+
+func (u *UserMuteCacheRedis) AddMutedSeq(ctx context.Context, userIDs []string, conversationID string, seq int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	member := redis.Z{Score: float64(seq), Member: strconv.FormatInt(seq, 10)}
+	pipe := u.rdb.Pipeline()
+	for _, userID := range userIDs {
+		key := cachekey.GetMutedSeqsKey(userID, conversationID)
+		pipe.ZAdd(ctx, key, member)
+		pipe.Expire(ctx, key, mutedSeqsExpireTime)
+	}
+	_, err := pipe.Exec(ctx)
+	return errs.Wrap(err)
+}
+
+func (u *UserMuteCacheRedis) CountMutedSeqs(ctx context.Context, userID string, hasReadSeqs map[string]int64) (map[string]int64, error) {
+	if len(hasReadSeqs) == 0 {
+		return map[string]int64{}, nil
+	}
+	pipe := u.rdb.Pipeline()
+	cmds := make(map[string]*redis.IntCmd, len(hasReadSeqs))
+	for conversationID, hasReadSeq := range hasReadSeqs {
+		key := cachekey.GetMutedSeqsKey(userID, conversationID)
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(hasReadSeq, 10))
+		cmds[conversationID] = pipe.ZCard(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	res := make(map[string]int64, len(cmds))
+	for conversationID, cmd := range cmds {
+		if n := cmd.Val(); n > 0 {
+			res[conversationID] = n
+		}
+	}
+	return res, nil
+}
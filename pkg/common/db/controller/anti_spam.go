@@ -0,0 +1,168 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+)
+
+// Actions of a user restriction.
+const (
+	// RestrictionThrottle lowers the rate of the user to the rate of the restriction.
+	RestrictionThrottle = "throttle"
+	// RestrictionSilence rejects every message of the user.
+	RestrictionSilence = "silence"
+)
+
+// UserRestriction is placed on a user by an admin until ExpireTime (ms).
+type UserRestriction struct {
+	UserID         string `json:"userID"`
+	Action         string `json:"action"`
+	Rate           int    `json:"rate"`
+	Reason         string `json:"reason"`
+	OperatorUserID string `json:"operatorUserID"`
+	CreateTime     int64  `json:"createTime"`
+	ExpireTime     int64  `json:"expireTime"`
+}
+
+// AntiSpamLimits are the limits of every user, 0 disables a limit.
+type AntiSpamLimits struct {
+	// Rate is the number of messages a user sends per second.
+	Rate int
+	// RepeatLimit is the number of messages with the same content a user sends within RepeatWindow.
+	RepeatLimit  int
+	RepeatWindow time.Duration
+	// Accounts younger than NewAccountAge send at most NewAccountRate messages per second.
+	NewAccountAge  time.Duration
+	NewAccountRate int
+}
+
+type AntiSpamDatabase interface {
+	// Check counts a message of userID, whose account was created at accountCreateTime (ms), and returns
+	// servererrs.ErrAntiSpam when it is over a limit or the user is silenced.
+	Check(ctx context.Context, userID string, accountCreateTime int64, content []byte) error
+	// Restrict places restriction on its user for duration, replacing the current one.
+	Restrict(ctx context.Context, restriction *UserRestriction, duration time.Duration) error
+	Unrestrict(ctx context.Context, userID string) error
+	// GetRestriction returns nil when userID is not restricted.
+	GetRestriction(ctx context.Context, userID string) (*UserRestriction, error)
+}
+
+type antiSpamDatabase struct {
+	cache  cache.AntiSpamCache
+	limits AntiSpamLimits
+}
+
+func NewAntiSpamDatabase(cache cache.AntiSpamCache, limits AntiSpamLimits) AntiSpamDatabase {
+	return &antiSpamDatabase{cache: cache, limits: limits}
+}
+
+// antiSpamRate returns the rate allowed to a user, 0 is no limit, and whether the user is silenced.
+func antiSpamRate(limits AntiSpamLimits, restriction *UserRestriction, accountCreateTime int64, now time.Time) (int, bool) {
+	if restriction != nil {
+		switch restriction.Action {
+		case RestrictionSilence:
+			return 0, true
+		case RestrictionThrottle:
+			return restriction.Rate, false
+		}
+	}
+	rate := limits.Rate
+	if limits.NewAccountRate > 0 && now.Sub(time.UnixMilli(accountCreateTime)) < limits.NewAccountAge {
+		if rate == 0 || limits.NewAccountRate < rate {
+			rate = limits.NewAccountRate
+		}
+	}
+	return rate, false
+}
+
+func (a *antiSpamDatabase) Check(ctx context.Context, userID string, accountCreateTime int64, content []byte) error {
+	restriction, err := a.GetRestriction(ctx, userID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	rate, silenced := antiSpamRate(a.limits, restriction, accountCreateTime, now)
+	if silenced {
+		return servererrs.ErrAntiSpam.Wrap("the user is silenced")
+	}
+	if rate > 0 {
+		n, err := a.cache.IncrRate(ctx, userID, now.Unix())
+		if err != nil {
+			return err
+		}
+		if n > int64(rate) {
+			return servererrs.ErrAntiSpam.Wrap("too many messages per second")
+		}
+	}
+	if a.limits.RepeatLimit > 0 && a.limits.RepeatWindow > 0 && len(content) > 0 {
+		sum := sha256.Sum256(content)
+		n, err := a.cache.IncrRepeat(ctx, userID, hex.EncodeToString(sum[:]), a.limits.RepeatWindow)
+		if err != nil {
+			return err
+		}
+		if n > int64(a.limits.RepeatLimit) {
+			return servererrs.ErrAntiSpam.Wrap("the same content is sent too often")
+		}
+	}
+	return nil
+}
+
+func (a *antiSpamDatabase) Restrict(ctx context.Context, restriction *UserRestriction, duration time.Duration) error {
+	switch restriction.Action {
+	case RestrictionSilence:
+	case RestrictionThrottle:
+		if restriction.Rate <= 0 {
+			return errs.ErrArgs.Wrap("the rate of a throttle must be positive")
+		}
+	default:
+		return errs.ErrArgs.Wrap("unknown restriction action " + restriction.Action)
+	}
+	if duration <= 0 {
+		return errs.ErrArgs.Wrap("the duration must be positive")
+	}
+	now := time.Now()
+	restriction.CreateTime = now.UnixMilli()
+	restriction.ExpireTime = now.Add(duration).UnixMilli()
+	data, err := json.Marshal(restriction)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return a.cache.SetRestriction(ctx, restriction.UserID, string(data), duration)
+}
+
+func (a *antiSpamDatabase) Unrestrict(ctx context.Context, userID string) error {
+	return a.cache.DelRestriction(ctx, userID)
+}
+
+func (a *antiSpamDatabase) GetRestriction(ctx context.Context, userID string) (*UserRestriction, error) {
+	data, err := a.cache.GetRestriction(ctx, userID)
+	if err != nil || data == "" {
+		return nil, err
+	}
+	var restriction UserRestriction
+	if err := json.Unmarshal([]byte(data), &restriction); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &restriction, nil
+}
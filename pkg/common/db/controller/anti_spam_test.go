@@ -0,0 +1,43 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAntiSpamRate(t *testing.T) {
+	now := time.Now()
+	limits := AntiSpamLimits{Rate: 10, NewAccountAge: time.Hour, NewAccountRate: 2}
+	old := now.Add(-time.Hour * 2).UnixMilli()
+	young := now.Add(-time.Minute).UnixMilli()
+
+	if rate, silenced := antiSpamRate(limits, nil, old, now); rate != 10 || silenced {
+		t.Fatalf("old account rate = %d, %v", rate, silenced)
+	}
+	if rate, _ := antiSpamRate(limits, nil, young, now); rate != 2 {
+		t.Fatalf("new account rate = %d", rate)
+	}
+	if rate, _ := antiSpamRate(AntiSpamLimits{NewAccountAge: time.Hour, NewAccountRate: 2}, nil, young, now); rate != 2 {
+		t.Fatalf("new account rate without a rate = %d", rate)
+	}
+	if rate, _ := antiSpamRate(limits, &UserRestriction{Action: RestrictionThrottle, Rate: 30}, young, now); rate != 30 {
+		t.Fatalf("throttled rate = %d", rate)
+	}
+	if _, silenced := antiSpamRate(limits, &UserRestriction{Action: RestrictionSilence}, old, now); !silenced {
+		t.Fatal("the silenced user is not silenced")
+	}
+}
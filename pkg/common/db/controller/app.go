@@ -0,0 +1,116 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// appIDRegexp keeps app ids usable as an id prefix and a kafka topic suffix.
+var appIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9-]{1,32}$`)
+
+type AppDatabase interface {
+	// CreateApp creates appID with a new random secret.
+	CreateApp(ctx context.Context, appID string, name string, managerUserIDs []string) (*relation.AppModel, error)
+	TakeApp(ctx context.Context, appID string) (*relation.AppModel, error)
+	// ResetAppSecret replaces the secret of appID with a new random one and returns it.
+	ResetAppSecret(ctx context.Context, appID string) (string, error)
+	SetAppManagers(ctx context.Context, appID string, managerUserIDs []string) error
+	PageApps(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.AppModel, error)
+}
+
+type appDatabase struct {
+	db relation.AppModelInterface
+}
+
+func NewAppDatabase(db relation.AppModelInterface) AppDatabase {
+	return &appDatabase{db: db}
+}
+
+func (a *appDatabase) CreateApp(ctx context.Context, appID string, name string, managerUserIDs []string) (*relation.AppModel, error) {
+	if !appIDRegexp.MatchString(appID) {
+		return nil, errs.ErrArgs.Wrap("appID must be 1 to 32 letters, digits or dashes")
+	}
+	secret, err := newAppSecret()
+	if err != nil {
+		return nil, err
+	}
+	app := &relation.AppModel{
+		AppID:          appID,
+		Name:           name,
+		Secret:         secret,
+		ManagerUserIDs: managerUserIDs,
+		CreateTime:     time.Now(),
+	}
+	if app.ManagerUserIDs == nil {
+		app.ManagerUserIDs = []string{}
+	}
+	if err := a.db.Create(ctx, app); err != nil {
+		if mongo.IsDuplicateKeyError(errs.Unwrap(err)) {
+			return nil, errs.ErrArgs.Wrap("app already exists")
+		}
+		return nil, err
+	}
+	return app, nil
+}
+
+func (a *appDatabase) TakeApp(ctx context.Context, appID string) (*relation.AppModel, error) {
+	return a.db.Take(ctx, appID)
+}
+
+func (a *appDatabase) ResetAppSecret(ctx context.Context, appID string) (string, error) {
+	if _, err := a.db.Take(ctx, appID); err != nil {
+		return "", err
+	}
+	secret, err := newAppSecret()
+	if err != nil {
+		return "", err
+	}
+	if err := a.db.UpdateSecret(ctx, appID, secret); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+func (a *appDatabase) SetAppManagers(ctx context.Context, appID string, managerUserIDs []string) error {
+	if _, err := a.db.Take(ctx, appID); err != nil {
+		return err
+	}
+	if managerUserIDs == nil {
+		managerUserIDs = []string{}
+	}
+	return a.db.UpdateManagers(ctx, appID, managerUserIDs)
+}
+
+func (a *appDatabase) PageApps(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.AppModel, error) {
+	return a.db.Page(ctx, pagination)
+}
+
+func newAppSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", errs.Wrap(err)
+	}
+	return hex.EncodeToString(b), nil
+}
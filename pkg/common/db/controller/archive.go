@@ -0,0 +1,136 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/archive"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/convert"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+)
+
+const defaultArchiveBatchSize = 500
+
+type ArchiveDatabase interface {
+	// GetWatermarks returns the highest archived seq of each conversation.
+	GetWatermarks(ctx context.Context, conversationIDs []string) (map[string]int64, error)
+	// ArchiveConversation streams the messages stored after the watermark to the archive and moves the
+	// watermark forward as batches are acknowledged.
+	ArchiveConversation(ctx context.Context, conversationID string) error
+}
+
+type archiveDatabase struct {
+	watermark      relation.ArchiveWatermarkModelInterface
+	msgDocDatabase unrelationtb.MsgDocModelInterface
+	msg            unrelationtb.MsgDocModel
+	archiver       archive.Archiver
+	batchSize      int64
+}
+
+func NewArchiveDatabase(watermark relation.ArchiveWatermarkModelInterface, msgDocModel unrelationtb.MsgDocModelInterface, archiver archive.Archiver, batchSize int) ArchiveDatabase {
+	if batchSize <= 0 {
+		batchSize = defaultArchiveBatchSize
+	}
+	return &archiveDatabase{
+		watermark:      watermark,
+		msgDocDatabase: msgDocModel,
+		archiver:       archiver,
+		batchSize:      int64(batchSize),
+	}
+}
+
+func (a *archiveDatabase) GetWatermarks(ctx context.Context, conversationIDs []string) (map[string]int64, error) {
+	return a.watermark.GetSeqs(ctx, conversationIDs)
+}
+
+func (a *archiveDatabase) ArchiveConversation(ctx context.Context, conversationID string) error {
+	watermarks, err := a.watermark.GetSeqs(ctx, []string{conversationID})
+	if err != nil {
+		return err
+	}
+	newest, err := a.msgDocDatabase.GetNewestMsg(ctx, conversationID)
+	if err != nil {
+		if errs.Unwrap(err) == unrelation.ErrMsgListNotExist {
+			return nil
+		}
+		return err
+	}
+	seq, maxSeq := watermarks[conversationID], newest.Msg.Seq
+	for seq < maxSeq {
+		end := seq + a.batchSize
+		if end > maxSeq {
+			end = maxSeq
+		}
+		seqs := make([]int64, 0, end-seq)
+		for i := seq + 1; i <= end; i++ {
+			seqs = append(seqs, i)
+		}
+		msgs, err := a.findMsgs(ctx, conversationID, seqs)
+		if err != nil {
+			return err
+		}
+		ackSeq := end
+		if len(msgs) > 0 {
+			if ackSeq, err = a.archiver.Archive(ctx, conversationID, msgs); err != nil {
+				return err
+			}
+			if ackSeq <= seq {
+				return errs.ErrInternalServer.Wrap("archive acknowledged no new messages")
+			}
+			if ackSeq > end {
+				ackSeq = end
+			}
+		}
+		if err := a.watermark.SetSeq(ctx, conversationID, ackSeq); err != nil {
+			return err
+		}
+		log.ZDebug(ctx, "archive conversation msgs", "conversationID", conversationID, "from", seq+1, "ackSeq", ackSeq, "num", len(msgs))
+		seq = ackSeq
+	}
+	return nil
+}
+
+// findMsgs returns the messages stored in Mongo for seqs, ordered by seq. Seqs that were never stored are skipped.
+func (a *archiveDatabase) findMsgs(ctx context.Context, conversationID string, seqs []int64) ([]*sdkws.MsgData, error) {
+	var (
+		msgs   []*sdkws.MsgData
+		docIDs []string
+	)
+	docSeqs := a.msg.GetDocIDSeqsMap(conversationID, seqs)
+	for _, seq := range seqs {
+		if docID := a.msg.GetDocID(conversationID, seq); len(docIDs) == 0 || docIDs[len(docIDs)-1] != docID {
+			docIDs = append(docIDs, docID)
+		}
+	}
+	for _, docID := range docIDs {
+		infos, err := a.msgDocDatabase.GetMsgBySeqIndexIn1Doc(ctx, "", docID, docSeqs[docID])
+		if err != nil {
+			return nil, err
+		}
+		for _, info := range infos {
+			if info == nil || info.Msg == nil {
+				continue
+			}
+			msgs = append(msgs, convert.MsgDB2Pb(info.Msg))
+		}
+	}
+	return msgs, nil
+}
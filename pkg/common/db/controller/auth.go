@@ -16,14 +16,21 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/OpenIMSDK/protocol/constant"
 	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/tokenverify"
-	"github.com/golang-jwt/jwt/v4"
 	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"github.com/openimsdk/open-im-server/v3/pkg/loginpolicy"
+	"github.com/redis/go-redis/v9"
 )
 
 type AuthDatabase interface {
@@ -31,17 +38,53 @@ type AuthDatabase interface {
 	GetTokensWithoutError(ctx context.Context, userID string, platformID int) (map[string]int, error)
 	// Create token
 	CreateToken(ctx context.Context, userID string, platformID int) (string, error)
+	// KickTokensByLoginPolicy applies the login policy matrix to the newly issued token,
+	// returning the platforms left without any valid token.
+	KickTokensByLoginPolicy(ctx context.Context, userID string, platformID int, token string) ([]int, error)
+	// KickAllTokens marks every token of userID as kicked, returning the platforms that had a valid token.
+	KickAllTokens(ctx context.Context, userID string) ([]int, error)
+	GetLoginPolicyMatrix(ctx context.Context) (*loginpolicy.Matrix, error)
+	SetLoginPolicyMatrix(ctx context.Context, matrix *loginpolicy.Matrix) error
+	// BindFingerprint binds the token to a device fingerprint, an empty fingerprint leaves it unbound.
+	BindFingerprint(ctx context.Context, token string, fingerprint string) error
+	CheckFingerprint(ctx context.Context, token string, fingerprint string) error
+	// GetDevices returns the valid tokens of userID with their sessions, newest login first.
+	GetDevices(ctx context.Context, userID string) ([]*Device, error)
+	// RevokeDevice kicks the token of userID identified by deviceID, returning its platform and whether the
+	// platform is left without a valid token.
+	RevokeDevice(ctx context.Context, userID string, deviceID string) (int, bool, error)
+	RenameDevice(ctx context.Context, userID string, deviceID string, name string) error
+}
+
+// MaxDeviceNameLen is the longest name a device can be given.
+const MaxDeviceNameLen = 64
+
+// Device is a valid token of a user, the gateways record its IP and last activity while it is connected.
+type Device struct {
+	DeviceID   string
+	Token      string
+	PlatformID int
+	LoginTime  int64
+	ExpireTime int64
+	LastActive int64
+	IP         string
+	Name       string
+}
+
+// DeviceID identifies a token without exposing it.
+func DeviceID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:16])
 }
 
 type authDatabase struct {
 	cache        cache.MsgModel
-	accessSecret string
 	accessExpire int64
 	config       *config.GlobalConfig
 }
 
-func NewAuthDatabase(cache cache.MsgModel, accessSecret string, accessExpire int64, config *config.GlobalConfig) AuthDatabase {
-	return &authDatabase{cache: cache, accessSecret: accessSecret, accessExpire: accessExpire, config: config}
+func NewAuthDatabase(cache cache.MsgModel, accessExpire int64, config *config.GlobalConfig) AuthDatabase {
+	return &authDatabase{cache: cache, accessExpire: accessExpire, config: config}
 }
 
 // If the result is empty.
@@ -57,7 +100,7 @@ func (a *authDatabase) CreateToken(ctx context.Context, userID string, platformI
 	}
 	var deleteTokenKey []string
 	for k, v := range tokens {
-		_, err = tokenverify.GetClaimFromToken(k, authverify.Secret(a.config.Secret))
+		_, err = tokenverify.GetClaimFromToken(k, authverify.Keyfunc(a.config))
 		if err != nil || v != constant.NormalToken {
 			deleteTokenKey = append(deleteTokenKey, k)
 		}
@@ -69,11 +112,225 @@ func (a *authDatabase) CreateToken(ctx context.Context, userID string, platformI
 		}
 	}
 
-	claims := tokenverify.BuildClaims(userID, platformID, a.accessExpire)
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(a.accessSecret))
+	claims := authverify.Claims{
+		Claims: tokenverify.BuildClaims(userID, platformID, a.accessExpire),
+		AppID:  tenant.GetAppID(ctx),
+	}
+	tokenString, err := authverify.SignToken(a.config, claims)
 	if err != nil {
 		return "", errs.Wrap(err, "token.SignedString")
 	}
 	return tokenString, a.cache.AddTokenFlag(ctx, userID, platformID, tokenString, constant.NormalToken)
 }
+
+func (a *authDatabase) KickTokensByLoginPolicy(ctx context.Context, userID string, platformID int, token string) ([]int, error) {
+	matrix, err := loginpolicy.Load(ctx, a.cache, a.config)
+	if err != nil {
+		return nil, err
+	}
+	group := matrix.Group(platformID)
+	if group == nil {
+		return nil, nil
+	}
+	type issuedToken struct {
+		platformID int
+		token      string
+		issuedAt   time.Time
+	}
+	var issued []issuedToken
+	normal := make(map[int]int)
+	for _, id := range group.PlatformIDs {
+		tokens, err := a.cache.GetTokensWithoutError(ctx, userID, id)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range tokens {
+			if k == token || v != constant.NormalToken {
+				continue
+			}
+			claims, err := tokenverify.GetClaimFromToken(k, authverify.Keyfunc(a.config))
+			if err != nil {
+				continue
+			}
+			var issuedAt time.Time
+			if claims.IssuedAt != nil {
+				issuedAt = claims.IssuedAt.Time
+			}
+			issued = append(issued, issuedToken{platformID: id, token: k, issuedAt: issuedAt})
+			normal[id]++
+		}
+	}
+	// The new token takes one of the group's seats, the newest of the others keep the rest.
+	if len(issued) < group.MaxOnline {
+		return nil, nil
+	}
+	sort.Slice(issued, func(i, j int) bool {
+		return issued[i].issuedAt.After(issued[j].issuedAt)
+	})
+	kicked := make(map[int]map[string]int)
+	for _, t := range issued[group.MaxOnline-1:] {
+		if kicked[t.platformID] == nil {
+			kicked[t.platformID] = make(map[string]int)
+		}
+		kicked[t.platformID][t.token] = constant.KickedToken
+	}
+	var platformIDs []int
+	for id, tokens := range kicked {
+		if err := a.cache.SetTokenMapByUidPid(ctx, userID, id, tokens); err != nil {
+			return nil, err
+		}
+		if len(tokens) == normal[id] {
+			platformIDs = append(platformIDs, id)
+		}
+	}
+	return platformIDs, nil
+}
+
+func (a *authDatabase) KickAllTokens(ctx context.Context, userID string) ([]int, error) {
+	var platformIDs []int
+	for platformID := range constant.PlatformID2Name {
+		tokens, err := a.cache.GetTokensWithoutError(ctx, userID, platformID)
+		if err != nil {
+			return nil, err
+		}
+		kicked := make(map[string]int)
+		for k, v := range tokens {
+			if v == constant.NormalToken {
+				kicked[k] = constant.KickedToken
+			}
+		}
+		if len(kicked) == 0 {
+			continue
+		}
+		if err := a.cache.SetTokenMapByUidPid(ctx, userID, platformID, kicked); err != nil {
+			return nil, err
+		}
+		platformIDs = append(platformIDs, platformID)
+	}
+	return platformIDs, nil
+}
+
+func (a *authDatabase) GetLoginPolicyMatrix(ctx context.Context) (*loginpolicy.Matrix, error) {
+	return loginpolicy.Load(ctx, a.cache, a.config)
+}
+
+func (a *authDatabase) SetLoginPolicyMatrix(ctx context.Context, matrix *loginpolicy.Matrix) error {
+	return loginpolicy.Save(ctx, a.cache, matrix)
+}
+
+func (a *authDatabase) BindFingerprint(ctx context.Context, token string, fingerprint string) error {
+	if !a.config.TokenPolicy.Fingerprint.Enable || fingerprint == "" {
+		return nil
+	}
+	expiration := time.Duration(a.accessExpire) * 24 * time.Hour
+	return a.cache.SetTokenFingerprint(ctx, token, authverify.HashFingerprint(fingerprint), expiration)
+}
+
+func (a *authDatabase) CheckFingerprint(ctx context.Context, token string, fingerprint string) error {
+	if !a.config.TokenPolicy.Fingerprint.Enable {
+		return nil
+	}
+	bound, err := a.cache.GetTokenFingerprint(ctx, token)
+	if err != nil && errs.Unwrap(err) != redis.Nil {
+		return err
+	}
+	return authverify.CheckFingerprint(ctx, bound, fingerprint, a.config)
+}
+
+func (a *authDatabase) GetDevices(ctx context.Context, userID string) ([]*Device, error) {
+	var devices []*Device
+	for platformID := range constant.PlatformID2Name {
+		tokens, err := a.cache.GetTokensWithoutError(ctx, userID, platformID)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range tokens {
+			if v != constant.NormalToken {
+				continue
+			}
+			claims, err := tokenverify.GetClaimFromToken(k, authverify.Keyfunc(a.config))
+			if err != nil {
+				continue
+			}
+			device := &Device{DeviceID: DeviceID(k), Token: k, PlatformID: platformID}
+			if claims.IssuedAt != nil {
+				device.LoginTime = claims.IssuedAt.UnixMilli()
+			}
+			if claims.ExpiresAt != nil {
+				device.ExpireTime = claims.ExpiresAt.UnixMilli()
+			}
+			devices = append(devices, device)
+		}
+	}
+	tokens := make([]string, 0, len(devices))
+	for _, device := range devices {
+		tokens = append(tokens, device.Token)
+	}
+	sessions, err := a.cache.GetTokenSessions(ctx, tokens)
+	if err != nil {
+		return nil, err
+	}
+	for _, device := range devices {
+		session := sessions[device.Token]
+		device.IP = session[cache.TokenSessionIP]
+		device.Name = session[cache.TokenSessionName]
+		device.LastActive, _ = strconv.ParseInt(session[cache.TokenSessionLastActive], 10, 64)
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].LoginTime > devices[j].LoginTime
+	})
+	return devices, nil
+}
+
+// findDevice returns the valid token of userID identified by deviceID.
+func (a *authDatabase) findDevice(ctx context.Context, userID string, deviceID string) (*Device, map[string]int, error) {
+	devices, err := a.GetDevices(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, device := range devices {
+		if device.DeviceID != deviceID {
+			continue
+		}
+		tokens, err := a.cache.GetTokensWithoutError(ctx, userID, device.PlatformID)
+		if err != nil {
+			return nil, nil, err
+		}
+		return device, tokens, nil
+	}
+	return nil, nil, errs.ErrRecordNotFound.Wrap("device " + deviceID)
+}
+
+func (a *authDatabase) RevokeDevice(ctx context.Context, userID string, deviceID string) (int, bool, error) {
+	device, tokens, err := a.findDevice(ctx, userID, deviceID)
+	if err != nil {
+		return 0, false, err
+	}
+	if err := a.cache.SetTokenMapByUidPid(ctx, userID, device.PlatformID, map[string]int{device.Token: constant.KickedToken}); err != nil {
+		return 0, false, err
+	}
+	if err := a.cache.DelTokenSession(ctx, device.Token); err != nil {
+		return 0, false, err
+	}
+	for k, v := range tokens {
+		if k != device.Token && v == constant.NormalToken {
+			return device.PlatformID, false, nil
+		}
+	}
+	return device.PlatformID, true, nil
+}
+
+func (a *authDatabase) RenameDevice(ctx context.Context, userID string, deviceID string, name string) error {
+	if len(name) > MaxDeviceNameLen {
+		return errs.ErrArgs.Wrap("device name is too long")
+	}
+	device, _, err := a.findDevice(ctx, userID, deviceID)
+	if err != nil {
+		return err
+	}
+	expiration := time.Until(time.UnixMilli(device.ExpireTime))
+	if device.ExpireTime == 0 {
+		expiration = time.Duration(a.accessExpire) * 24 * time.Hour
+	}
+	return a.cache.SetTokenSession(ctx, device.Token, map[string]string{cache.TokenSessionName: name}, expiration)
+}
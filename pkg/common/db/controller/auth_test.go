@@ -0,0 +1,103 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+type fingerprintCache struct {
+	cache.MsgModel
+	fingerprints map[string]string
+	expiration   time.Duration
+}
+
+func (f *fingerprintCache) SetTokenFingerprint(_ context.Context, token string, fingerprint string, expiration time.Duration) error {
+	f.fingerprints[token] = fingerprint
+	f.expiration = expiration
+	return nil
+}
+
+func (f *fingerprintCache) GetTokenFingerprint(_ context.Context, token string) (string, error) {
+	fingerprint, ok := f.fingerprints[token]
+	if !ok {
+		return "", errs.Wrap(redis.Nil)
+	}
+	return fingerprint, nil
+}
+
+func TestTokenFingerprint(t *testing.T) {
+	ctx := context.Background()
+	for _, grace := range []bool{false, true} {
+		conf := config.NewGlobalConfig()
+		conf.TokenPolicy.Fingerprint.Enable = true
+		conf.TokenPolicy.Fingerprint.Grace = grace
+		fingerprints := &fingerprintCache{fingerprints: make(map[string]string)}
+		db := NewAuthDatabase(fingerprints, 90, conf)
+
+		if err := db.BindFingerprint(ctx, "bound", "device-a"); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.BindFingerprint(ctx, "unbound", ""); err != nil {
+			t.Fatal(err)
+		}
+		if fingerprints.fingerprints["bound"] != authverify.HashFingerprint("device-a") {
+			t.Fatalf("bound fingerprint = %s", fingerprints.fingerprints["bound"])
+		}
+		if _, ok := fingerprints.fingerprints["unbound"]; ok {
+			t.Fatal("token issued without a fingerprint was bound")
+		}
+		if fingerprints.expiration != 90*24*time.Hour {
+			t.Fatalf("fingerprint expiration = %s", fingerprints.expiration)
+		}
+
+		if err := db.CheckFingerprint(ctx, "bound", "device-a"); err != nil {
+			t.Fatalf("grace %v, same device: %v", grace, err)
+		}
+		if err := db.CheckFingerprint(ctx, "unbound", "device-b"); err != nil {
+			t.Fatalf("grace %v, unbound token: %v", grace, err)
+		}
+		err := db.CheckFingerprint(ctx, "bound", "device-b")
+		if grace && err != nil {
+			t.Fatalf("grace mode rejected another device: %v", err)
+		}
+		if !grace && !errs.ErrTokenInvalid.Is(err) {
+			t.Fatalf("strict mode accepted another device: %v", err)
+		}
+	}
+}
+
+func TestTokenFingerprintDisabled(t *testing.T) {
+	ctx := context.Background()
+	fingerprints := &fingerprintCache{fingerprints: map[string]string{"bound": authverify.HashFingerprint("device-a")}}
+	db := NewAuthDatabase(fingerprints, 90, config.NewGlobalConfig())
+	if err := db.BindFingerprint(ctx, "token", "device-a"); err != nil {
+		t.Fatal(err)
+	}
+	if len(fingerprints.fingerprints) != 1 {
+		t.Fatalf("fingerprints = %v", fingerprints.fingerprints)
+	}
+	if err := db.CheckFingerprint(ctx, "bound", "device-b"); err != nil {
+		t.Fatal(err)
+	}
+}
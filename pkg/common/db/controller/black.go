@@ -32,6 +32,8 @@ type BlackDatabase interface {
 	// FindOwnerBlacks get BlackList list
 	FindOwnerBlacks(ctx context.Context, ownerUserID string, pagination pagination.Pagination) (total int64, blacks []*relation.BlackModel, err error)
 	FindBlackInfos(ctx context.Context, ownerUserID string, userIDs []string) (blacks []*relation.BlackModel, err error)
+	// FindInWhoseBlacks get the BlackList entries blocking blockUserID
+	FindInWhoseBlacks(ctx context.Context, blockUserID string) (blacks []*relation.BlackModel, err error)
 	// CheckIn Check whether user2 is in the black list of user1 (inUser1Blacks==true) Check whether user1 is in the black list of user2 (inUser2Blacks==true)
 	CheckIn(ctx context.Context, userID1, userID2 string) (inUser1Blacks bool, inUser2Blacks bool, err error)
 }
@@ -98,3 +100,7 @@ func (b *blackDatabase) FindBlackIDs(ctx context.Context, ownerUserID string) (b
 func (b *blackDatabase) FindBlackInfos(ctx context.Context, ownerUserID string, userIDs []string) (blacks []*relation.BlackModel, err error) {
 	return b.black.FindOwnerBlackInfos(ctx, ownerUserID, userIDs)
 }
+
+func (b *blackDatabase) FindInWhoseBlacks(ctx context.Context, blockUserID string) (blacks []*relation.BlackModel, err error) {
+	return b.black.FindInWhoseBlacks(ctx, blockUserID)
+}
@@ -0,0 +1,118 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"google.golang.org/protobuf/proto"
+)
+
+type BroadcastJobDatabase interface {
+	// CreateJob queues msg to be sent to the recipients of job and returns its jobID, job.RecvIDs holds at most
+	// maxRecvIDs users and is empty to send to every user.
+	CreateJob(ctx context.Context, job *relation.BroadcastJobModel, msg *sdkws.MsgData, maxRecvIDs int) (string, error)
+	TakeJob(ctx context.Context, jobID string) (*relation.BroadcastJobModel, error)
+	// GetJobs lists the jobs newest first, without their message and recipients.
+	GetJobs(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.BroadcastJobModel, error)
+	// Cancel stops a pending or running job, the page being sent when it is canceled is sent completely.
+	Cancel(ctx context.Context, jobID string) error
+	// Lease returns the oldest job to run, nil if there is none, no other caller gets it before leaseUntil.
+	Lease(ctx context.Context, leaseUntil time.Time) (*relation.BroadcastJobModel, error)
+	// UpdateProgress stores the progress of a leased job, reporting false if it was canceled.
+	UpdateProgress(ctx context.Context, job *relation.BroadcastJobModel) (bool, error)
+	// Finish marks a leased job as finished, or as failed when err is not nil.
+	Finish(ctx context.Context, jobID string, err error) error
+}
+
+type broadcastJobDatabase struct {
+	db relation.BroadcastJobModelInterface
+}
+
+func NewBroadcastJobDatabase(db relation.BroadcastJobModelInterface) BroadcastJobDatabase {
+	return &broadcastJobDatabase{db: db}
+}
+
+func (b *broadcastJobDatabase) CreateJob(ctx context.Context, job *relation.BroadcastJobModel, msg *sdkws.MsgData, maxRecvIDs int) (string, error) {
+	if maxRecvIDs > 0 && len(job.RecvIDs) > maxRecvIDs {
+		return "", errs.ErrArgs.Wrap(fmt.Sprintf("a job holds at most %d recvIDs", maxRecvIDs))
+	}
+	if job.PageSize <= 0 {
+		return "", errs.ErrArgs.Wrap("pageSize must be positive")
+	}
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+	if len(job.RecvIDs) > 0 {
+		job.RecvIDs = utils.Distinct(job.RecvIDs)
+		job.Total = int64(len(job.RecvIDs))
+	}
+	job.JobID = utils.GetMsgID(job.OperatorUserID)
+	job.MsgData = data
+	job.Status = relation.BroadcastPending
+	job.Page, job.Sent, job.Failed, job.Err = 0, 0, 0, ""
+	job.CreateTime = time.Now()
+	job.UpdateTime = job.CreateTime
+	if err := b.db.Create(ctx, job); err != nil {
+		return "", err
+	}
+	return job.JobID, nil
+}
+
+func (b *broadcastJobDatabase) TakeJob(ctx context.Context, jobID string) (*relation.BroadcastJobModel, error) {
+	return b.db.Take(ctx, jobID)
+}
+
+func (b *broadcastJobDatabase) GetJobs(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.BroadcastJobModel, error) {
+	return b.db.FindPage(ctx, pagination)
+}
+
+func (b *broadcastJobDatabase) Cancel(ctx context.Context, jobID string) error {
+	ok, err := b.db.UpdateStatus(ctx, jobID, []int32{relation.BroadcastPending, relation.BroadcastRunning}, relation.BroadcastCanceled, "")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errs.ErrArgs.Wrap("broadcast job is no longer pending or running")
+	}
+	return nil
+}
+
+func (b *broadcastJobDatabase) Lease(ctx context.Context, leaseUntil time.Time) (*relation.BroadcastJobModel, error) {
+	return b.db.Lease(ctx, time.Now(), leaseUntil)
+}
+
+func (b *broadcastJobDatabase) UpdateProgress(ctx context.Context, job *relation.BroadcastJobModel) (bool, error) {
+	job.UpdateTime = time.Now()
+	return b.db.UpdateProgress(ctx, job)
+}
+
+func (b *broadcastJobDatabase) Finish(ctx context.Context, jobID string, err error) error {
+	status, errMsg := int32(relation.BroadcastFinished), ""
+	if err != nil {
+		status, errMsg = relation.BroadcastFailed, err.Error()
+	}
+	// A job canceled meanwhile stays canceled.
+	_, err = b.db.UpdateStatus(ctx, jobID, []int32{relation.BroadcastRunning}, status, errMsg)
+	return err
+}
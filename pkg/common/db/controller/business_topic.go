@@ -0,0 +1,79 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mqbuild"
+)
+
+type BusinessTopicDatabase interface {
+	Subscribe(ctx context.Context, userID string, topics []string) error
+	Unsubscribe(ctx context.Context, userID string, topics []string) error
+	GetUserTopics(ctx context.Context, userID string) ([]string, error)
+	PageSubscriberIDs(ctx context.Context, topic string, pagination pagination.Pagination) (int64, []string, error)
+	// Publish hands a notification template over to msgtransfer, which fans it out to the topic subscribers.
+	Publish(ctx context.Context, topic string, msg *sdkws.MsgData) error
+}
+
+type businessTopicDatabase struct {
+	db       relation.BusinessTopicModelInterface
+	producer mq.Producer
+}
+
+func NewBusinessTopicDatabase(db relation.BusinessTopicModelInterface, config *config.GlobalConfig) (BusinessTopicDatabase, error) {
+	producer, err := mqbuild.NewProducer(config, config.Kafka.BusinessNotification.Topic)
+	if err != nil {
+		return nil, err
+	}
+	return &businessTopicDatabase{db: db, producer: producer}, nil
+}
+
+func (b *businessTopicDatabase) Subscribe(ctx context.Context, userID string, topics []string) error {
+	now := time.Now()
+	subscribers := make([]*relation.BusinessTopicSubscriberModel, 0, len(topics))
+	for _, topic := range topics {
+		subscribers = append(subscribers, &relation.BusinessTopicSubscriberModel{Topic: topic, UserID: userID, CreateTime: now})
+	}
+	return b.db.Subscribe(ctx, subscribers)
+}
+
+func (b *businessTopicDatabase) Unsubscribe(ctx context.Context, userID string, topics []string) error {
+	return b.db.Unsubscribe(ctx, userID, topics)
+}
+
+func (b *businessTopicDatabase) GetUserTopics(ctx context.Context, userID string) ([]string, error) {
+	return b.db.FindUserTopics(ctx, userID)
+}
+
+func (b *businessTopicDatabase) PageSubscriberIDs(ctx context.Context, topic string, pagination pagination.Pagination) (int64, []string, error) {
+	return b.db.PageSubscriberIDs(ctx, topic, pagination)
+}
+
+func (b *businessTopicDatabase) Publish(ctx context.Context, topic string, msg *sdkws.MsgData) error {
+	if topic == "" {
+		return errs.ErrArgs.Wrap("topic is empty")
+	}
+	_, _, err := b.producer.SendMessage(ctx, topic, msg)
+	return err
+}
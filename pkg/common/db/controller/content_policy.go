@@ -0,0 +1,128 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/contentpolicy"
+)
+
+type ContentPolicyDatabase interface {
+	// SetPolicy allows only contentTypes in the conversations of sessionType, or in the group when groupID is set.
+	SetPolicy(ctx context.Context, sessionType int32, groupID string, contentTypes []int32) error
+	DeletePolicy(ctx context.Context, sessionType int32, groupID string) error
+	// GetContentTypes returns the content types allowed in a conversation, nil when no policy applies. The policy
+	// of the group comes before the one of its session type.
+	GetContentTypes(ctx context.Context, sessionType int32, groupID string) ([]int32, error)
+	// Check returns servererrs.ErrContentTypeNotAllowed when contentType is not allowed in the conversation.
+	Check(ctx context.Context, sessionType int32, groupID string, contentType int32) error
+	PagePolicies(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.ContentPolicyModel, error)
+}
+
+type contentPolicyDatabase struct {
+	db    relation.ContentPolicyModelInterface
+	cache cache.ContentPolicyCache
+}
+
+func NewContentPolicyDatabase(db relation.ContentPolicyModelInterface, cache cache.ContentPolicyCache) ContentPolicyDatabase {
+	return &contentPolicyDatabase{db: db, cache: cache}
+}
+
+func checkPolicyScope(sessionType int32, groupID string) error {
+	switch sessionType {
+	case constant.SingleChatType:
+		if groupID != "" {
+			return errs.ErrArgs.Wrap("groupID is only set for group policies")
+		}
+	case constant.SuperGroupChatType:
+	default:
+		return errs.ErrArgs.Wrap(fmt.Sprintf("session type %d has no content policy", sessionType))
+	}
+	return nil
+}
+
+func (c *contentPolicyDatabase) SetPolicy(ctx context.Context, sessionType int32, groupID string, contentTypes []int32) error {
+	if err := checkPolicyScope(sessionType, groupID); err != nil {
+		return err
+	}
+	if len(contentTypes) == 0 {
+		return errs.ErrArgs.Wrap("a policy allows at least one content type")
+	}
+	allowed := make([]int32, 0, len(contentTypes))
+	seen := make(map[int32]struct{}, len(contentTypes))
+	for _, contentType := range contentTypes {
+		if !contentpolicy.Applies(contentType) {
+			return errs.ErrArgs.Wrap(fmt.Sprintf("content type %d is never restricted", contentType))
+		}
+		if _, ok := seen[contentType]; ok {
+			continue
+		}
+		seen[contentType] = struct{}{}
+		allowed = append(allowed, contentType)
+	}
+	sort.Slice(allowed, func(i, j int) bool { return allowed[i] < allowed[j] })
+	policy := &relation.ContentPolicyModel{SessionType: sessionType, GroupID: groupID, ContentTypes: allowed, UpdateTime: time.Now()}
+	if err := c.db.Set(ctx, policy); err != nil {
+		return err
+	}
+	return c.cache.DelContentTypes(sessionType, groupID).ExecDel(ctx)
+}
+
+func (c *contentPolicyDatabase) DeletePolicy(ctx context.Context, sessionType int32, groupID string) error {
+	if err := checkPolicyScope(sessionType, groupID); err != nil {
+		return err
+	}
+	if err := c.db.Delete(ctx, sessionType, groupID); err != nil {
+		return err
+	}
+	return c.cache.DelContentTypes(sessionType, groupID).ExecDel(ctx)
+}
+
+func (c *contentPolicyDatabase) GetContentTypes(ctx context.Context, sessionType int32, groupID string) ([]int32, error) {
+	if groupID != "" {
+		allowed, err := c.cache.GetContentTypes(ctx, sessionType, groupID)
+		if err != nil {
+			return nil, err
+		}
+		if allowed != nil {
+			return allowed, nil
+		}
+	}
+	return c.cache.GetContentTypes(ctx, sessionType, "")
+}
+
+func (c *contentPolicyDatabase) Check(ctx context.Context, sessionType int32, groupID string, contentType int32) error {
+	if !contentpolicy.Applies(contentType) {
+		return nil
+	}
+	allowed, err := c.GetContentTypes(ctx, sessionType, groupID)
+	if err != nil {
+		return err
+	}
+	return contentpolicy.Check(allowed, contentType)
+}
+
+func (c *contentPolicyDatabase) PagePolicies(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.ContentPolicyModel, error) {
+	return c.db.Page(ctx, pagination)
+}
@@ -0,0 +1,280 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"sort"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/convert"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+)
+
+// ConversationDuplicate is a user pair whose single chat is stored under more than one conversation id.
+type ConversationDuplicate struct {
+	// ConversationID is the canonical id of the pair, the one new messages are sent to.
+	ConversationID string
+	UserIDs        []string
+	// DuplicateIDs are the other conversation ids of the pair, MaxSeqs holds the max seq of each.
+	DuplicateIDs []string
+	MaxSeqs      map[string]int64
+	// Records are the records of both users under ConversationID and DuplicateIDs.
+	Records []*relationtb.ConversationModel
+}
+
+type ConversationRepairDatabase interface {
+	// FindDuplicates returns the pairs of up to limit non canonical records, only those owned by ownerUserIDs if given.
+	FindDuplicates(ctx context.Context, ownerUserIDs []string, limit int64) ([]*ConversationDuplicate, error)
+	// Merge appends the messages of the duplicate ids to the canonical conversation, merges the records of each user
+	// into the canonical one and deletes the rest. It returns the number of messages moved.
+	Merge(ctx context.Context, duplicate *ConversationDuplicate) (int64, error)
+}
+
+type conversationRepairDatabase struct {
+	repairDB       relationtb.ConversationRepairModelInterface
+	conversationDB relationtb.ConversationModelInterface
+	cache          cache.ConversationCache
+	msgDocDB       unrelationtb.MsgDocModelInterface
+	msgCache       cache.MsgModel
+	msgImport      MsgImportDatabase
+	msg            unrelationtb.MsgDocModel
+}
+
+func NewConversationRepairDatabase(repairDB relationtb.ConversationRepairModelInterface, conversationDB relationtb.ConversationModelInterface, cache cache.ConversationCache, msgDocDB unrelationtb.MsgDocModelInterface, msgCache cache.MsgModel, msgImport MsgImportDatabase) ConversationRepairDatabase {
+	return &conversationRepairDatabase{
+		repairDB:       repairDB,
+		conversationDB: conversationDB,
+		cache:          cache,
+		msgDocDB:       msgDocDB,
+		msgCache:       msgCache,
+		msgImport:      msgImport,
+	}
+}
+
+func (c *conversationRepairDatabase) FindDuplicates(ctx context.Context, ownerUserIDs []string, limit int64) ([]*ConversationDuplicate, error) {
+	records, err := c.repairDB.FindNonCanonicalSingleChats(ctx, ownerUserIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+	duplicates := groupConversationDuplicates(records)
+	for _, duplicate := range duplicates {
+		// The records of the other user are not necessarily among those found.
+		records, err := c.conversationDB.GetConversationsByConversationID(ctx, append([]string{duplicate.ConversationID}, duplicate.DuplicateIDs...))
+		if err != nil {
+			return nil, err
+		}
+		duplicate.Records = nil
+		for _, record := range records {
+			if record.ConversationType == constant.SingleChatType && utils.IsContain(record.OwnerUserID, duplicate.UserIDs) && utils.IsContain(record.UserID, duplicate.UserIDs) {
+				duplicate.Records = append(duplicate.Records, record)
+			}
+		}
+		for _, conversationID := range duplicate.DuplicateIDs {
+			if duplicate.MaxSeqs[conversationID], err = c.getMaxSeq(ctx, conversationID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return duplicates, nil
+}
+
+func (c *conversationRepairDatabase) Merge(ctx context.Context, duplicate *ConversationDuplicate) (int64, error) {
+	var moved int64
+	for _, conversationID := range duplicate.DuplicateIDs {
+		n, err := c.moveMsgs(ctx, conversationID, duplicate.ConversationID, duplicate.MaxSeqs[conversationID], duplicate.UserIDs)
+		moved += n
+		if err != nil {
+			return moved, err
+		}
+	}
+	owners := make(map[string][]*relationtb.ConversationModel)
+	for _, record := range duplicate.Records {
+		owners[record.OwnerUserID] = append(owners[record.OwnerUserID], record)
+	}
+	conversationIDs := append([]string{duplicate.ConversationID}, duplicate.DuplicateIDs...)
+	cache := c.cache.NewCache()
+	for ownerUserID, records := range owners {
+		keep, remove := mergeConversationRecords(duplicate.ConversationID, records)
+		// Update upserts, keep is created when the user had no record under the canonical id.
+		if err := c.conversationDB.Update(ctx, keep); err != nil {
+			return moved, err
+		}
+		if err := c.repairDB.DeleteConversations(ctx, ownerUserID, remove); err != nil {
+			return moved, err
+		}
+		cache = cache.DelConversations(ownerUserID, conversationIDs...).
+			DelUserRecvMsgOpt(ownerUserID, duplicate.ConversationID).
+			DelUserAllHasReadSeqs(ownerUserID, conversationIDs...).
+			DelConversationIDs(ownerUserID).
+			DelUserConversationIDsHash(ownerUserID)
+	}
+	cache = cache.DelConversationByConversationID(conversationIDs...).DelConversationNotReceiveMessageUserIDs(conversationIDs...)
+	if err := cache.ExecDel(ctx); err != nil {
+		return moved, err
+	}
+	log.ZInfo(ctx, "conversation duplicates merged", "conversationID", duplicate.ConversationID, "duplicateIDs", duplicate.DuplicateIDs, "moved", moved)
+	return moved, nil
+}
+
+// moveMsgs imports the messages of from into to doc by doc and deletes each doc once imported, so that a failed
+// merge can be run again without importing a doc twice. Messages deleted or revoked in from are dropped, and the
+// moved messages count as read for the users who had read both conversations to the end.
+func (c *conversationRepairDatabase) moveMsgs(ctx context.Context, from string, to string, maxSeq int64, userIDs []string) (int64, error) {
+	if maxSeq <= 0 {
+		return 0, nil
+	}
+	readUserIDs, err := c.readUserIDs(ctx, []string{from, to}, userIDs)
+	if err != nil {
+		return 0, err
+	}
+	var moved int64
+	num := c.msg.GetSingleGocMsgNum()
+	for begin := int64(1); begin <= maxSeq; begin += num {
+		docID := c.msg.GetDocID(from, begin)
+		doc, err := c.msgDocDB.FindOneByDocID(ctx, docID)
+		if err != nil {
+			if errs.Unwrap(err) == mongo.ErrNoDocuments {
+				continue
+			}
+			return moved, errs.Wrap(err)
+		}
+		var msgs []*sdkws.MsgData
+		for _, info := range doc.Msg {
+			if info == nil || info.Msg == nil || info.Revoke != nil || info.Msg.Status == constant.MsgDeleted {
+				continue
+			}
+			msgs = append(msgs, convert.MsgDB2Pb(info.Msg))
+		}
+		if len(msgs) > 0 {
+			if _, err := c.msgImport.Import(ctx, to, msgs, readUserIDs); err != nil {
+				return moved, err
+			}
+		}
+		if err := c.msgDocDB.DeleteDocs(ctx, []string{docID}); err != nil {
+			return moved, err
+		}
+		moved += int64(len(msgs))
+	}
+	return moved, nil
+}
+
+// readUserIDs returns the users of userIDs that have read every conversation of conversationIDs to its max seq.
+func (c *conversationRepairDatabase) readUserIDs(ctx context.Context, conversationIDs []string, userIDs []string) ([]string, error) {
+	maxSeqs, err := c.msgCache.GetMaxSeqs(ctx, conversationIDs)
+	if err != nil && errs.Unwrap(err) != redis.Nil {
+		return nil, err
+	}
+	var readUserIDs []string
+	for _, userID := range userIDs {
+		hasReadSeqs, err := c.msgCache.GetHasReadSeqs(ctx, userID, conversationIDs)
+		if err != nil && errs.Unwrap(err) != redis.Nil {
+			return nil, err
+		}
+		read := true
+		for _, conversationID := range conversationIDs {
+			if hasReadSeqs[conversationID] < maxSeqs[conversationID] {
+				read = false
+				break
+			}
+		}
+		if read {
+			readUserIDs = append(readUserIDs, userID)
+		}
+	}
+	return readUserIDs, nil
+}
+
+func (c *conversationRepairDatabase) getMaxSeq(ctx context.Context, conversationID string) (int64, error) {
+	maxSeq, err := c.msgCache.GetMaxSeq(ctx, conversationID)
+	if err != nil && errs.Unwrap(err) != redis.Nil {
+		return 0, err
+	}
+	return maxSeq, nil
+}
+
+// groupConversationDuplicates groups non canonical records by the canonical id of their user pair.
+func groupConversationDuplicates(records []*relationtb.ConversationModel) []*ConversationDuplicate {
+	var duplicates []*ConversationDuplicate
+	byID := make(map[string]*ConversationDuplicate)
+	for _, record := range records {
+		conversationID := msgprocessor.GetConversationIDBySessionType(constant.SingleChatType, record.OwnerUserID, record.UserID)
+		if conversationID == record.ConversationID {
+			continue
+		}
+		duplicate, ok := byID[conversationID]
+		if !ok {
+			userIDs := []string{record.OwnerUserID, record.UserID}
+			sort.Strings(userIDs)
+			duplicate = &ConversationDuplicate{ConversationID: conversationID, UserIDs: utils.Distinct(userIDs), MaxSeqs: make(map[string]int64)}
+			byID[conversationID] = duplicate
+			duplicates = append(duplicates, duplicate)
+		}
+		if !utils.IsContain(record.ConversationID, duplicate.DuplicateIDs) {
+			duplicate.DuplicateIDs = append(duplicate.DuplicateIDs, record.ConversationID)
+		}
+		duplicate.Records = append(duplicate.Records, record)
+	}
+	return duplicates
+}
+
+// mergeConversationRecords merges the records of one user into the record under conversationID, or into a copy of
+// the oldest record when there is none, and returns it with the conversation ids of the records to delete. Settings
+// left at their zero value in the kept record are taken from the others, a pin on any of them is kept.
+func mergeConversationRecords(conversationID string, records []*relationtb.ConversationModel) (*relationtb.ConversationModel, []string) {
+	sort.SliceStable(records, func(i, j int) bool {
+		if (records[i].ConversationID == conversationID) != (records[j].ConversationID == conversationID) {
+			return records[i].ConversationID == conversationID
+		}
+		return records[i].CreateTime.Before(records[j].CreateTime)
+	})
+	keep := *records[0]
+	keep.ConversationID = conversationID
+	var remove []string
+	if records[0].ConversationID != conversationID {
+		remove = append(remove, records[0].ConversationID)
+	}
+	for _, record := range records[1:] {
+		remove = append(remove, record.ConversationID)
+		keep.IsPinned = keep.IsPinned || record.IsPinned
+		keep.IsPrivateChat = keep.IsPrivateChat || record.IsPrivateChat
+		if keep.RecvMsgOpt == 0 {
+			keep.RecvMsgOpt = record.RecvMsgOpt
+		}
+		if keep.BurnDuration == 0 {
+			keep.BurnDuration = record.BurnDuration
+		}
+		if keep.AttachedInfo == "" {
+			keep.AttachedInfo = record.AttachedInfo
+		}
+		if keep.Ex == "" {
+			keep.Ex = record.Ex
+		}
+		if !record.CreateTime.IsZero() && (keep.CreateTime.IsZero() || record.CreateTime.Before(keep.CreateTime)) {
+			keep.CreateTime = record.CreateTime
+		}
+	}
+	return &keep, remove
+}
@@ -0,0 +1,76 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+
+	relationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+func TestGroupConversationDuplicates(t *testing.T) {
+	duplicates := groupConversationDuplicates([]*relationtb.ConversationModel{
+		{OwnerUserID: "b", UserID: "a", ConversationID: "si_b_a", ConversationType: constant.SingleChatType},
+		{OwnerUserID: "a", UserID: "b", ConversationID: "si_b_a", ConversationType: constant.SingleChatType},
+		{OwnerUserID: "a", UserID: "b", ConversationID: "single_1", ConversationType: constant.SingleChatType},
+		{OwnerUserID: "a", UserID: "c", ConversationID: "si_a_c", ConversationType: constant.SingleChatType},
+	})
+	if len(duplicates) != 1 {
+		t.Fatalf("duplicates = %d, want 1", len(duplicates))
+	}
+	duplicate := duplicates[0]
+	if duplicate.ConversationID != "si_a_b" {
+		t.Fatalf("conversationID = %s", duplicate.ConversationID)
+	}
+	if !reflect.DeepEqual(duplicate.UserIDs, []string{"a", "b"}) || !reflect.DeepEqual(duplicate.DuplicateIDs, []string{"si_b_a", "single_1"}) {
+		t.Fatalf("userIDs = %v, duplicateIDs = %v", duplicate.UserIDs, duplicate.DuplicateIDs)
+	}
+	if len(duplicate.Records) != 3 {
+		t.Fatalf("records = %d, want 3", len(duplicate.Records))
+	}
+}
+
+func TestMergeConversationRecords(t *testing.T) {
+	now := time.Now()
+	keep, remove := mergeConversationRecords("si_a_b", []*relationtb.ConversationModel{
+		{ConversationID: "si_b_a", IsPinned: true, RecvMsgOpt: 2, Ex: "old", CreateTime: now.Add(-time.Hour)},
+		{ConversationID: "si_a_b", AttachedInfo: "info", CreateTime: now},
+	})
+	if keep.ConversationID != "si_a_b" || !keep.IsPinned || keep.RecvMsgOpt != 2 || keep.Ex != "old" || keep.AttachedInfo != "info" {
+		t.Fatalf("keep = %+v", keep)
+	}
+	if !keep.CreateTime.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("create time = %v", keep.CreateTime)
+	}
+	if !reflect.DeepEqual(remove, []string{"si_b_a"}) {
+		t.Fatalf("remove = %v", remove)
+	}
+
+	// Without a canonical record the oldest one is kept under the canonical id.
+	keep, remove = mergeConversationRecords("si_a_b", []*relationtb.ConversationModel{
+		{ConversationID: "single_2", CreateTime: now},
+		{ConversationID: "single_1", Ex: "first", CreateTime: now.Add(-time.Hour)},
+	})
+	if keep.ConversationID != "si_a_b" || keep.Ex != "first" {
+		t.Fatalf("keep = %+v", keep)
+	}
+	if !reflect.DeepEqual(remove, []string{"single_1", "single_2"}) {
+		t.Fatalf("remove = %v", remove)
+	}
+}
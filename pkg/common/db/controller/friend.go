@@ -40,6 +40,10 @@ type FriendDatabase interface {
 	// BecomeFriends first checks if the users are already in the friends table; if not, it inserts them as friends
 	BecomeFriends(ctx context.Context, ownerUserID string, friendUserIDs []string, addSource int32) (err error)
 
+	// ImportFriends adds the friendUserIDs the owner does not have yet to the owner's friend list, and when
+	// bidirectional the owner to each of theirs; existing relations are left untouched
+	ImportFriends(ctx context.Context, ownerUserID string, friendUserIDs []string, bidirectional bool, addSource int32) (err error)
+
 	// RefuseFriendRequest refuses a friend request
 	RefuseFriendRequest(ctx context.Context, friendRequest *relation.FriendRequestModel) (err error)
 
@@ -179,6 +183,49 @@ func (f *friendDatabase) BecomeFriends(ctx context.Context, ownerUserID string,
 	})
 }
 
+func (f *friendDatabase) ImportFriends(ctx context.Context, ownerUserID string, friendUserIDs []string, bidirectional bool, addSource int32) error {
+	return f.tx.Transaction(ctx, func(ctx context.Context) error {
+		existing, err := f.friend.FindFriends(ctx, ownerUserID, friendUserIDs)
+		if err != nil {
+			return err
+		}
+		existingMap := utils.SliceToMap(existing, func(e *relation.FriendModel) string {
+			return e.FriendUserID
+		})
+		opUserID := mcontext.GetOpUserID(ctx)
+		now := time.Now()
+		var friends []*relation.FriendModel
+		for _, friendUserID := range friendUserIDs {
+			if _, ok := existingMap[friendUserID]; !ok {
+				friends = append(friends, &relation.FriendModel{OwnerUserID: ownerUserID, FriendUserID: friendUserID, CreateTime: now, AddSource: addSource, OperatorUserID: opUserID})
+			}
+		}
+		changedUserIDs := []string{ownerUserID}
+		if bidirectional {
+			reversed, err := f.friend.FindReversalFriends(ctx, ownerUserID, friendUserIDs)
+			if err != nil {
+				return err
+			}
+			reversedMap := utils.SliceToMap(reversed, func(e *relation.FriendModel) string {
+				return e.OwnerUserID
+			})
+			for _, friendUserID := range friendUserIDs {
+				if _, ok := reversedMap[friendUserID]; !ok {
+					friends = append(friends, &relation.FriendModel{OwnerUserID: friendUserID, FriendUserID: ownerUserID, CreateTime: now, AddSource: addSource, OperatorUserID: opUserID})
+					changedUserIDs = append(changedUserIDs, friendUserID)
+				}
+			}
+		}
+		if len(friends) == 0 {
+			return nil
+		}
+		if err := f.friend.Create(ctx, friends); err != nil {
+			return err
+		}
+		return f.cache.NewCache().DelFriendIDs(changedUserIDs...).ExecDel(ctx)
+	})
+}
+
 // RefuseFriendRequest rejects a friend request. It first checks for an existing, unprocessed request.
 // If no such request exists, it returns an error. Otherwise, it marks the request as refused.
 func (f *friendDatabase) RefuseFriendRequest(ctx context.Context, friendRequest *relation.FriendRequestModel) error {
@@ -0,0 +1,124 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+const (
+	// FriendLabelMaxNum is the number of labels a user may create.
+	FriendLabelMaxNum     = 100
+	friendLabelNameMaxLen = 64
+)
+
+type FriendLabelDatabase interface {
+	CreateLabel(ctx context.Context, ownerUserID string, name string) (*relation.FriendLabelModel, error)
+	RenameLabel(ctx context.Context, ownerUserID string, labelID string, name string) error
+	DeleteLabel(ctx context.Context, ownerUserID string, labelID string) error
+	TakeLabel(ctx context.Context, ownerUserID string, labelID string) (*relation.FriendLabelModel, error)
+	GetLabels(ctx context.Context, ownerUserID string) ([]*relation.FriendLabelModel, error)
+	AddFriends(ctx context.Context, ownerUserID string, labelID string, friendUserIDs []string) error
+	RemoveFriends(ctx context.Context, ownerUserID string, labelID string, friendUserIDs []string) error
+	// RemoveDeletedFriends takes friends the user deleted off all of their labels.
+	RemoveDeletedFriends(ctx context.Context, ownerUserID string, friendUserIDs []string) error
+}
+
+type friendLabelDatabase struct {
+	db relation.FriendLabelModelInterface
+}
+
+func NewFriendLabelDatabase(db relation.FriendLabelModelInterface) FriendLabelDatabase {
+	return &friendLabelDatabase{db: db}
+}
+
+func checkFriendLabelName(labels []*relation.FriendLabelModel, labelID string, name string) error {
+	if name == "" || len(name) > friendLabelNameMaxLen {
+		return errs.ErrArgs.Wrap(fmt.Sprintf("label name must be 1 to %d characters", friendLabelNameMaxLen))
+	}
+	for _, label := range labels {
+		if label.Name == name && label.LabelID != labelID {
+			return errs.ErrArgs.Wrap("a label with this name already exists")
+		}
+	}
+	return nil
+}
+
+func (f *friendLabelDatabase) CreateLabel(ctx context.Context, ownerUserID string, name string) (*relation.FriendLabelModel, error) {
+	labels, err := f.db.FindByOwner(ctx, ownerUserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) >= FriendLabelMaxNum {
+		return nil, errs.ErrArgs.Wrap(fmt.Sprintf("a user creates at most %d labels", FriendLabelMaxNum))
+	}
+	if err := checkFriendLabelName(labels, "", name); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	label := &relation.FriendLabelModel{
+		OwnerUserID:   ownerUserID,
+		LabelID:       utils.GetMsgID(ownerUserID),
+		Name:          name,
+		FriendUserIDs: []string{},
+		CreateTime:    now,
+		UpdateTime:    now,
+	}
+	if err := f.db.Create(ctx, label); err != nil {
+		return nil, err
+	}
+	return label, nil
+}
+
+func (f *friendLabelDatabase) RenameLabel(ctx context.Context, ownerUserID string, labelID string, name string) error {
+	labels, err := f.db.FindByOwner(ctx, ownerUserID)
+	if err != nil {
+		return err
+	}
+	if err := checkFriendLabelName(labels, labelID, name); err != nil {
+		return err
+	}
+	return f.db.UpdateName(ctx, ownerUserID, labelID, name)
+}
+
+func (f *friendLabelDatabase) DeleteLabel(ctx context.Context, ownerUserID string, labelID string) error {
+	return f.db.Delete(ctx, ownerUserID, labelID)
+}
+
+func (f *friendLabelDatabase) TakeLabel(ctx context.Context, ownerUserID string, labelID string) (*relation.FriendLabelModel, error) {
+	return f.db.Take(ctx, ownerUserID, labelID)
+}
+
+func (f *friendLabelDatabase) GetLabels(ctx context.Context, ownerUserID string) ([]*relation.FriendLabelModel, error) {
+	return f.db.FindByOwner(ctx, ownerUserID)
+}
+
+func (f *friendLabelDatabase) AddFriends(ctx context.Context, ownerUserID string, labelID string, friendUserIDs []string) error {
+	return f.db.AddFriends(ctx, ownerUserID, labelID, utils.Distinct(friendUserIDs))
+}
+
+func (f *friendLabelDatabase) RemoveFriends(ctx context.Context, ownerUserID string, labelID string, friendUserIDs []string) error {
+	return f.db.RemoveFriends(ctx, ownerUserID, labelID, friendUserIDs)
+}
+
+func (f *friendLabelDatabase) RemoveDeletedFriends(ctx context.Context, ownerUserID string, friendUserIDs []string) error {
+	return f.db.RemoveFriendsFromAll(ctx, ownerUserID, friendUserIDs)
+}
@@ -0,0 +1,142 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type GroupBatchJobDatabase interface {
+	// CreateJob checks and queues job and returns its jobID, a job holds at most maxItems items.
+	CreateJob(ctx context.Context, job *relation.GroupBatchJobModel, maxItems int) (string, error)
+	TakeJob(ctx context.Context, jobID string) (*relation.GroupBatchJobModel, error)
+	// GetJobs lists the jobs newest first, without their items.
+	GetJobs(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.GroupBatchJobModel, error)
+	// ClaimPending marks up to limit pending jobs as running and returns them, a job is claimed by one caller only.
+	ClaimPending(ctx context.Context, limit int64) ([]*relation.GroupBatchJobModel, error)
+	// Finish stores the results of the items of a claimed job, it is failed if any item failed.
+	Finish(ctx context.Context, job *relation.GroupBatchJobModel) error
+}
+
+type groupBatchJobDatabase struct {
+	db relation.GroupBatchJobModelInterface
+}
+
+func NewGroupBatchJobDatabase(db relation.GroupBatchJobModelInterface) GroupBatchJobDatabase {
+	return &groupBatchJobDatabase{db: db}
+}
+
+func checkGroupBatchItem(kind string, item *relation.GroupBatchItemModel) error {
+	switch kind {
+	case relation.GroupBatchCreateGroups:
+		if item.GroupName == "" || item.OwnerUserID == "" {
+			return errs.ErrArgs.Wrap("groupName and ownerUserID are required to create a group")
+		}
+	case relation.GroupBatchAddMembers, relation.GroupBatchRemoveMembers:
+		if item.GroupID == "" || len(item.MemberUserIDs) == 0 {
+			return errs.ErrArgs.Wrap("groupID and memberUserIDs are required")
+		}
+	case relation.GroupBatchApplySettings:
+		if item.GroupID == "" {
+			return errs.ErrArgs.Wrap("groupID is required")
+		}
+	default:
+		return errs.ErrArgs.Wrap("unknown group batch kind " + kind)
+	}
+	return nil
+}
+
+func (g *groupBatchJobDatabase) CreateJob(ctx context.Context, job *relation.GroupBatchJobModel, maxItems int) (string, error) {
+	if len(job.Items) == 0 {
+		return "", errs.ErrArgs.Wrap("items is empty")
+	}
+	if maxItems > 0 && len(job.Items) > maxItems {
+		return "", errs.ErrArgs.Wrap(fmt.Sprintf("a job holds at most %d items", maxItems))
+	}
+	if job.Kind == relation.GroupBatchApplySettings && job.Settings == nil {
+		return "", errs.ErrArgs.Wrap("settings is required")
+	}
+	groupIDs := make([]string, 0, len(job.Items))
+	for _, item := range job.Items {
+		if err := checkGroupBatchItem(job.Kind, item); err != nil {
+			return "", err
+		}
+		if item.GroupID != "" {
+			groupIDs = append(groupIDs, item.GroupID)
+		}
+		item.Err = ""
+	}
+	if utils.Duplicate(groupIDs) {
+		return "", errs.ErrArgs.Wrap("groupID repeated")
+	}
+	job.JobID = utils.GetMsgID(job.OperatorUserID)
+	job.Status = relation.GroupBatchPending
+	job.Total, job.Succeeded, job.Failed = int64(len(job.Items)), 0, 0
+	job.CreateTime = time.Now()
+	if err := g.db.Create(ctx, job); err != nil {
+		return "", err
+	}
+	return job.JobID, nil
+}
+
+func (g *groupBatchJobDatabase) TakeJob(ctx context.Context, jobID string) (*relation.GroupBatchJobModel, error) {
+	return g.db.Take(ctx, jobID)
+}
+
+func (g *groupBatchJobDatabase) GetJobs(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.GroupBatchJobModel, error) {
+	return g.db.FindPage(ctx, pagination)
+}
+
+func (g *groupBatchJobDatabase) ClaimPending(ctx context.Context, limit int64) ([]*relation.GroupBatchJobModel, error) {
+	pending, err := g.db.FindPending(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	claimed := make([]*relation.GroupBatchJobModel, 0, len(pending))
+	for _, job := range pending {
+		ok, err := g.db.UpdateStatus(ctx, job.JobID, relation.GroupBatchPending, relation.GroupBatchRunning)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			claimed = append(claimed, job)
+		}
+	}
+	return claimed, nil
+}
+
+func (g *groupBatchJobDatabase) Finish(ctx context.Context, job *relation.GroupBatchJobModel) error {
+	job.Succeeded, job.Failed = 0, 0
+	for _, item := range job.Items {
+		if item.Err == "" {
+			job.Succeeded++
+		} else {
+			job.Failed++
+		}
+	}
+	job.Status = relation.GroupBatchFinished
+	if job.Failed > 0 {
+		job.Status = relation.GroupBatchFailed
+	}
+	job.FinishTime = time.Now()
+	return g.db.Finish(ctx, job)
+}
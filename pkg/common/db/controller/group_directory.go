@@ -0,0 +1,137 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/search"
+)
+
+const (
+	// GroupTagMaxNum is the number of tags a listed group may carry.
+	GroupTagMaxNum    = 10
+	groupTagMaxLength = 32
+)
+
+type GroupDirectoryDatabase interface {
+	// List adds the group to the public directory or updates its entry.
+	List(ctx context.Context, entry *relation.GroupDirectoryModel) error
+	// Unlist removes the group from the public directory, it is a no-op for groups not listed.
+	Unlist(ctx context.Context, groupID string) error
+	// UpdateGroupInfo refreshes the entry of a listed group after its info changed.
+	UpdateGroupInfo(ctx context.Context, groupID string, groupName string, introduction string, faceURL string) error
+	// TakeEntry returns the entry of the group, nil when it is not listed.
+	TakeEntry(ctx context.Context, groupID string) (*relation.GroupDirectoryModel, error)
+	Search(ctx context.Context, keyword string, pagination pagination.Pagination) (int64, []*relation.GroupDirectoryModel, error)
+}
+
+type groupDirectoryDatabase struct {
+	db relation.GroupDirectoryModelInterface
+	// backend is nil when the directory is searched on mongo.
+	backend search.GroupBackend
+}
+
+func NewGroupDirectoryDatabase(db relation.GroupDirectoryModelInterface, backend search.GroupBackend) GroupDirectoryDatabase {
+	return &groupDirectoryDatabase{db: db, backend: backend}
+}
+
+func (g *groupDirectoryDatabase) List(ctx context.Context, entry *relation.GroupDirectoryModel) error {
+	if len(entry.Tags) > GroupTagMaxNum {
+		return errs.ErrArgs.Wrap(fmt.Sprintf("a group carries at most %d tags", GroupTagMaxNum))
+	}
+	for _, tag := range entry.Tags {
+		if tag == "" || len(tag) > groupTagMaxLength {
+			return errs.ErrArgs.Wrap(fmt.Sprintf("tags must be 1 to %d characters", groupTagMaxLength))
+		}
+	}
+	entry.Tags = utils.Distinct(entry.Tags)
+	now := time.Now()
+	entry.CreateTime, entry.UpdateTime = now, now
+	if err := g.db.Set(ctx, entry); err != nil {
+		return err
+	}
+	return g.index(ctx, entry)
+}
+
+func (g *groupDirectoryDatabase) Unlist(ctx context.Context, groupID string) error {
+	if err := g.db.Delete(ctx, groupID); err != nil {
+		return err
+	}
+	if g.backend == nil {
+		return nil
+	}
+	return g.backend.DeleteGroup(ctx, groupID)
+}
+
+func (g *groupDirectoryDatabase) UpdateGroupInfo(ctx context.Context, groupID string, groupName string, introduction string, faceURL string) error {
+	listed, err := g.db.UpdateGroupInfo(ctx, groupID, groupName, introduction, faceURL)
+	if err != nil || !listed || g.backend == nil {
+		return err
+	}
+	entry, err := g.db.Take(ctx, groupID)
+	if err != nil {
+		return err
+	}
+	return g.index(ctx, entry)
+}
+
+func (g *groupDirectoryDatabase) TakeEntry(ctx context.Context, groupID string) (*relation.GroupDirectoryModel, error) {
+	entry, err := g.db.Take(ctx, groupID)
+	if err != nil {
+		if relation.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (g *groupDirectoryDatabase) Search(ctx context.Context, keyword string, pagination pagination.Pagination) (int64, []*relation.GroupDirectoryModel, error) {
+	if g.backend == nil || keyword == "" {
+		return g.db.Search(ctx, keyword, pagination)
+	}
+	total, groupIDs, err := g.backend.SearchGroups(ctx, keyword, pagination.GetPageNumber(), pagination.GetShowNumber())
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(groupIDs) == 0 {
+		return total, []*relation.GroupDirectoryModel{}, nil
+	}
+	entries, err := g.db.Find(ctx, groupIDs)
+	if err != nil {
+		return 0, nil, err
+	}
+	// The index may lag behind an unlisting, the entries keep the order of the hits.
+	return total, utils.Order(groupIDs, entries, func(e *relation.GroupDirectoryModel) string { return e.GroupID }), nil
+}
+
+func (g *groupDirectoryDatabase) index(ctx context.Context, entry *relation.GroupDirectoryModel) error {
+	if g.backend == nil {
+		return nil
+	}
+	return g.backend.IndexGroup(ctx, &search.GroupDoc{
+		GroupID:      entry.GroupID,
+		GroupName:    entry.GroupName,
+		Tags:         entry.Tags,
+		Introduction: entry.Introduction,
+	})
+}
@@ -0,0 +1,131 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+)
+
+type GroupHistoryDatabase interface {
+	SetVisibility(ctx context.Context, groupID string, visibility int32, days int32) error
+	GetVisibility(ctx context.Context, groupID string) (*relation.GroupHistoryVisibilityModel, error)
+	// GetVisibleMinSeq returns the smallest seq of the group conversation a member who joined at joinTime (ms)
+	// may read, 0 when the group history is unrestricted for them.
+	GetVisibleMinSeq(ctx context.Context, groupID string, conversationID string, joinTime int64) (int64, error)
+	// RecordFormerMembers keeps the join time of members leaving the group, call it before they are deleted.
+	RecordFormerMembers(ctx context.Context, members []*relation.GroupMemberModel) error
+	// GetFormerMemberVisibleMinSeq is GetVisibleMinSeq for a member who left the group, with the join time they
+	// had as a member. For members who left before it was recorded the join time is unknown and taken as now,
+	// so a restricted history stays hidden rather than becoming readable.
+	GetFormerMemberVisibleMinSeq(ctx context.Context, groupID string, conversationID string, userID string) (int64, error)
+}
+
+type groupHistoryDatabase struct {
+	db       relation.GroupHistoryVisibilityModelInterface
+	msgDocDB unrelationtb.MsgDocModelInterface
+	cache    cache.GroupHistoryCache
+}
+
+func NewGroupHistoryDatabase(db relation.GroupHistoryVisibilityModelInterface, msgDocDB unrelationtb.MsgDocModelInterface, cache cache.GroupHistoryCache) GroupHistoryDatabase {
+	return &groupHistoryDatabase{db: db, msgDocDB: msgDocDB, cache: cache}
+}
+
+func (g *groupHistoryDatabase) SetVisibility(ctx context.Context, groupID string, visibility int32, days int32) error {
+	switch visibility {
+	case relation.GroupHistoryVisibleAll, relation.GroupHistoryVisibleNone:
+		days = 0
+	case relation.GroupHistoryVisibleDays:
+		if days <= 0 {
+			return errs.ErrArgs.Wrap("days must be positive")
+		}
+	default:
+		return errs.ErrArgs.Wrap("unknown history visibility")
+	}
+	if err := g.db.Set(ctx, &relation.GroupHistoryVisibilityModel{
+		GroupID:    groupID,
+		Visibility: visibility,
+		Days:       days,
+		UpdateTime: time.Now(),
+	}); err != nil {
+		return err
+	}
+	return g.cache.DelVisibility(groupID).ExecDel(ctx)
+}
+
+func (g *groupHistoryDatabase) GetVisibility(ctx context.Context, groupID string) (*relation.GroupHistoryVisibilityModel, error) {
+	return g.cache.GetVisibility(ctx, groupID)
+}
+
+func (g *groupHistoryDatabase) GetVisibleMinSeq(ctx context.Context, groupID string, conversationID string, joinTime int64) (int64, error) {
+	visibility, err := g.cache.GetVisibility(ctx, groupID)
+	if err != nil {
+		return 0, err
+	}
+	since := visibility.VisibleSince(joinTime)
+	if since == 0 {
+		return 0, nil
+	}
+	seq, err := g.cache.GetMinSeqSince(ctx, conversationID, since)
+	if err == nil {
+		return seq, nil
+	}
+	if errs.Unwrap(err) != unrelation.ErrMsgListNotExist {
+		return 0, err
+	}
+	// Nothing stored was sent since then, so everything up to the newest stored message stays hidden.
+	newest, err := g.msgDocDB.GetNewestMsg(ctx, conversationID)
+	if err != nil {
+		if errs.Unwrap(err) == unrelation.ErrMsgListNotExist {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return newest.Msg.Seq + 1, nil
+}
+
+func (g *groupHistoryDatabase) RecordFormerMembers(ctx context.Context, members []*relation.GroupMemberModel) error {
+	if len(members) == 0 {
+		return nil
+	}
+	now := time.Now()
+	formers := make([]*relation.GroupFormerMemberModel, 0, len(members))
+	for _, member := range members {
+		formers = append(formers, &relation.GroupFormerMemberModel{
+			GroupID:   member.GroupID,
+			UserID:    member.UserID,
+			JoinTime:  member.JoinTime,
+			LeaveTime: now,
+		})
+	}
+	return g.db.SetFormerMembers(ctx, formers)
+}
+
+func (g *groupHistoryDatabase) GetFormerMemberVisibleMinSeq(ctx context.Context, groupID string, conversationID string, userID string) (int64, error) {
+	joinTime := time.Now().UnixMilli()
+	former, err := g.db.TakeFormerMember(ctx, groupID, userID)
+	if err == nil {
+		joinTime = former.JoinTime.UnixMilli()
+	} else if !relation.IsNotFound(err) {
+		return 0, err
+	}
+	return g.GetVisibleMinSeq(ctx, groupID, conversationID, joinTime)
+}
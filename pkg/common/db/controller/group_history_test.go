@@ -0,0 +1,131 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type historyModel struct {
+	visibility *relation.GroupHistoryVisibilityModel
+	formers    map[string]*relation.GroupFormerMemberModel
+}
+
+func (h *historyModel) Set(_ context.Context, visibility *relation.GroupHistoryVisibilityModel) error {
+	h.visibility = visibility
+	return nil
+}
+
+func (h *historyModel) Take(context.Context, string) (*relation.GroupHistoryVisibilityModel, error) {
+	if h.visibility == nil {
+		return nil, errs.Wrap(mongo.ErrNoDocuments)
+	}
+	return h.visibility, nil
+}
+
+func (h *historyModel) SetFormerMembers(_ context.Context, members []*relation.GroupFormerMemberModel) error {
+	for _, member := range members {
+		h.formers[member.GroupID+":"+member.UserID] = member
+	}
+	return nil
+}
+
+func (h *historyModel) TakeFormerMember(_ context.Context, groupID string, userID string) (*relation.GroupFormerMemberModel, error) {
+	member, ok := h.formers[groupID+":"+userID]
+	if !ok {
+		return nil, errs.Wrap(mongo.ErrNoDocuments)
+	}
+	return member, nil
+}
+
+// historyCache reads the model directly, sendTimes are the send times of seqs 1, 2, ...
+type historyCache struct {
+	cache.GroupHistoryCache
+	model     *historyModel
+	sendTimes []int64
+}
+
+func (h *historyCache) GetVisibility(ctx context.Context, groupID string) (*relation.GroupHistoryVisibilityModel, error) {
+	visibility, err := h.model.Take(ctx, groupID)
+	if err != nil {
+		return &relation.GroupHistoryVisibilityModel{GroupID: groupID}, nil
+	}
+	return visibility, nil
+}
+
+func (h *historyCache) GetMinSeqSince(_ context.Context, _ string, since int64) (int64, error) {
+	for i, sendTime := range h.sendTimes {
+		if sendTime >= since {
+			return int64(i + 1), nil
+		}
+	}
+	return 0, errs.Wrap(unrelation.ErrMsgListNotExist)
+}
+
+type historyMsgDoc struct {
+	unrelationtb.MsgDocModelInterface
+	newest int64
+}
+
+func (h *historyMsgDoc) GetNewestMsg(context.Context, string) (*unrelationtb.MsgInfoModel, error) {
+	return &unrelationtb.MsgInfoModel{Msg: &unrelationtb.MsgDataModel{Seq: h.newest}}, nil
+}
+
+func TestFormerMemberVisibleMinSeq(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	// One message a day over the last ten days.
+	var sendTimes []int64
+	for i := 10; i > 0; i-- {
+		sendTimes = append(sendTimes, now.Add(-time.Duration(i)*24*time.Hour).UnixMilli())
+	}
+	model := &historyModel{formers: make(map[string]*relation.GroupFormerMemberModel)}
+	db := NewGroupHistoryDatabase(model, &historyMsgDoc{newest: int64(len(sendTimes))}, &historyCache{model: model, sendTimes: sendTimes})
+	model.visibility = &relation.GroupHistoryVisibilityModel{GroupID: "g1", Visibility: relation.GroupHistoryVisibleNone}
+	// u1 joined before the 6th message and left.
+	joinTime := time.UnixMilli(sendTimes[5] - 1)
+	if err := db.RecordFormerMembers(ctx, []*relation.GroupMemberModel{{GroupID: "g1", UserID: "u1", JoinTime: joinTime}}); err != nil {
+		t.Fatal(err)
+	}
+	member, err := db.GetVisibleMinSeq(ctx, "g1", "sg_g1", joinTime.UnixMilli())
+	if err != nil {
+		t.Fatal(err)
+	}
+	former, err := db.GetFormerMemberVisibleMinSeq(ctx, "g1", "sg_g1", "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if member != 6 || former != member {
+		t.Fatalf("visible min seq = %d as member and %d as former member, want 6", member, former)
+	}
+	// Nothing recorded for u2, the whole history before now stays hidden.
+	if seq, err := db.GetFormerMemberVisibleMinSeq(ctx, "g1", "sg_g1", "u2"); err != nil || seq != int64(len(sendTimes))+1 {
+		t.Fatalf("visible min seq of an unrecorded former member = %d, %v", seq, err)
+	}
+
+	model.visibility = &relation.GroupHistoryVisibilityModel{GroupID: "g1", Visibility: relation.GroupHistoryVisibleAll}
+	if seq, err := db.GetFormerMemberVisibleMinSeq(ctx, "g1", "sg_g1", "u2"); err != nil || seq != 0 {
+		t.Fatalf("visible min seq with unrestricted history = %d, %v", seq, err)
+	}
+}
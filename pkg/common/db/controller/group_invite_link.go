@@ -0,0 +1,130 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+// GroupInviteLinkMaxNum is the number of invite links a group may hold at once.
+const GroupInviteLinkMaxNum = 50
+
+type GroupInviteLinkDatabase interface {
+	// CreateLink creates a link with a new random token, a zero expireTime or maxUses means no limit.
+	CreateLink(ctx context.Context, groupID string, creatorUserID string, expireTime time.Time, maxUses int32, needApproval bool) (*relation.GroupInviteLinkModel, error)
+	RevokeLink(ctx context.Context, groupID string, token string) error
+	TakeLink(ctx context.Context, token string) (*relation.GroupInviteLinkModel, error)
+	GetLinks(ctx context.Context, groupID string) ([]*relation.GroupInviteLinkModel, error)
+	// UseLink checks that token is a usable link of groupID and counts one use of it.
+	UseLink(ctx context.Context, groupID string, token string) (*relation.GroupInviteLinkModel, error)
+}
+
+type groupInviteLinkDatabase struct {
+	db relation.GroupInviteLinkModelInterface
+}
+
+func NewGroupInviteLinkDatabase(db relation.GroupInviteLinkModelInterface) GroupInviteLinkDatabase {
+	return &groupInviteLinkDatabase{db: db}
+}
+
+func (g *groupInviteLinkDatabase) CreateLink(ctx context.Context, groupID string, creatorUserID string, expireTime time.Time, maxUses int32, needApproval bool) (*relation.GroupInviteLinkModel, error) {
+	now := time.Now()
+	if !expireTime.IsZero() && !expireTime.After(now) {
+		return nil, errs.ErrArgs.Wrap("expireTime is in the past")
+	}
+	if maxUses < 0 {
+		return nil, errs.ErrArgs.Wrap("maxUses is negative")
+	}
+	num, err := g.db.CountByGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if num >= GroupInviteLinkMaxNum {
+		return nil, errs.ErrArgs.Wrap(fmt.Sprintf("a group holds at most %d invite links", GroupInviteLinkMaxNum))
+	}
+	token, err := newInviteLinkToken()
+	if err != nil {
+		return nil, err
+	}
+	link := &relation.GroupInviteLinkModel{
+		Token:         token,
+		GroupID:       groupID,
+		CreatorUserID: creatorUserID,
+		ExpireTime:    expireTime,
+		MaxUses:       maxUses,
+		NeedApproval:  needApproval,
+		CreateTime:    now,
+	}
+	if err := g.db.Create(ctx, link); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+func (g *groupInviteLinkDatabase) RevokeLink(ctx context.Context, groupID string, token string) error {
+	return g.db.Delete(ctx, groupID, token)
+}
+
+func (g *groupInviteLinkDatabase) TakeLink(ctx context.Context, token string) (*relation.GroupInviteLinkModel, error) {
+	link, err := g.db.Take(ctx, token)
+	if err != nil {
+		if relation.IsNotFound(err) {
+			return nil, errs.ErrArgs.Wrap("invite link is invalid or revoked")
+		}
+		return nil, err
+	}
+	return link, nil
+}
+
+func (g *groupInviteLinkDatabase) GetLinks(ctx context.Context, groupID string) ([]*relation.GroupInviteLinkModel, error) {
+	return g.db.FindByGroup(ctx, groupID)
+}
+
+func (g *groupInviteLinkDatabase) UseLink(ctx context.Context, groupID string, token string) (*relation.GroupInviteLinkModel, error) {
+	link, err := g.TakeLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if link.GroupID != groupID {
+		return nil, errs.ErrArgs.Wrap("invite link is invalid or revoked")
+	}
+	if !link.Usable(time.Now()) {
+		return nil, errs.ErrArgs.Wrap("invite link has expired or run out of uses")
+	}
+	ok, err := g.db.IncrUses(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errs.ErrArgs.Wrap("invite link has expired or run out of uses")
+	}
+	link.Uses++
+	return link, nil
+}
+
+func newInviteLinkToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errs.Wrap(err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,108 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+)
+
+type GroupMemberEventDatabase interface {
+	// Record queues a membership change for the group member webhook, it does nothing when the webhook is
+	// disabled or does not subscribe to the group or the event.
+	Record(ctx context.Context, groupID string, event string, userIDs []string, roleLevel int32) error
+	// ClaimDue returns up to limit due events, each leased to the caller until lease has passed.
+	ClaimDue(ctx context.Context, limit int64, lease time.Duration) ([]*relation.GroupMemberEventModel, error)
+	Delivered(ctx context.Context, eventIDs []string) error
+	// Retry records a failed delivery of event, failed is true once it is not going to be retried.
+	Retry(ctx context.Context, event *relation.GroupMemberEventModel, failed bool, nextTime time.Time, lastErr string) error
+}
+
+type groupMemberEventDatabase struct {
+	db     relation.GroupMemberEventModelInterface
+	config *config.GlobalConfig
+}
+
+func NewGroupMemberEventDatabase(db relation.GroupMemberEventModelInterface, config *config.GlobalConfig) GroupMemberEventDatabase {
+	return &groupMemberEventDatabase{db: db, config: config}
+}
+
+func (g *groupMemberEventDatabase) subscribed(groupID string, event string) bool {
+	conf := g.config.GroupMemberWebhook
+	if !conf.Enable {
+		return false
+	}
+	if len(conf.GroupIDs) > 0 && !utils.IsContain(groupID, conf.GroupIDs) {
+		return false
+	}
+	return len(conf.Events) == 0 || utils.IsContain(event, conf.Events)
+}
+
+func (g *groupMemberEventDatabase) Record(ctx context.Context, groupID string, event string, userIDs []string, roleLevel int32) error {
+	if len(userIDs) == 0 || !g.subscribed(groupID, event) {
+		return nil
+	}
+	now := time.Now()
+	operatorUserID := mcontext.GetOpUserID(ctx)
+	return g.db.Create(ctx, []*relation.GroupMemberEventModel{{
+		EventID:        utils.GetMsgID(operatorUserID + groupID),
+		AppID:          tenant.GetAppID(ctx),
+		GroupID:        groupID,
+		Event:          event,
+		UserIDs:        userIDs,
+		OperatorUserID: operatorUserID,
+		RoleLevel:      roleLevel,
+		EventTime:      now,
+		Status:         relation.GroupMemberEventPending,
+		NextTime:       now,
+	}})
+}
+
+func (g *groupMemberEventDatabase) ClaimDue(ctx context.Context, limit int64, lease time.Duration) ([]*relation.GroupMemberEventModel, error) {
+	now := time.Now()
+	events, err := g.db.FindDue(ctx, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	claimed := make([]*relation.GroupMemberEventModel, 0, len(events))
+	for _, event := range events {
+		ok, err := g.db.Claim(ctx, event.EventID, event.NextTime, now.Add(lease))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			claimed = append(claimed, event)
+		}
+	}
+	return claimed, nil
+}
+
+func (g *groupMemberEventDatabase) Delivered(ctx context.Context, eventIDs []string) error {
+	return g.db.SetDelivered(ctx, eventIDs)
+}
+
+func (g *groupMemberEventDatabase) Retry(ctx context.Context, event *relation.GroupMemberEventModel, failed bool, nextTime time.Time, lastErr string) error {
+	status := int32(relation.GroupMemberEventPending)
+	if failed {
+		status = relation.GroupMemberEventFailed
+	}
+	return g.db.SetAttempt(ctx, event.EventID, status, event.Attempts+1, nextTime, lastErr)
+}
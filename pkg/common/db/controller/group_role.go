@@ -0,0 +1,168 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+const (
+	// GroupRoleMaxNum is the number of custom roles a group may define.
+	GroupRoleMaxNum   = 20
+	groupRoleIDMaxLen = 64
+)
+
+type GroupRoleDatabase interface {
+	SetRole(ctx context.Context, groupID string, roleID string, name string, permissions int64) error
+	// DeleteRole deletes the role and takes it from the members holding it.
+	DeleteRole(ctx context.Context, groupID string, roleID string) error
+	GetRoles(ctx context.Context, groupID string) ([]*relation.GroupRoleModel, error)
+	// AssignRole gives the role to the members, an empty roleID takes their custom role away.
+	AssignRole(ctx context.Context, groupID string, roleID string, userIDs []string) error
+	// UnassignMembers takes the custom role away from members leaving the group.
+	UnassignMembers(ctx context.Context, groupID string, userIDs []string) error
+	GetMemberRoles(ctx context.Context, groupID string) ([]*relation.GroupMemberRoleModel, error)
+	// GetMemberRole returns the custom role of a member, nil when it has none.
+	GetMemberRole(ctx context.Context, groupID string, userID string) (*relation.GroupRoleModel, error)
+	// GetMemberPermissions returns the permissions of a member with the given role level.
+	GetMemberPermissions(ctx context.Context, groupID string, userID string, roleLevel int32) (int64, error)
+}
+
+type groupRoleDatabase struct {
+	db    relation.GroupRoleModelInterface
+	cache cache.GroupRoleCache
+}
+
+func NewGroupRoleDatabase(db relation.GroupRoleModelInterface, cache cache.GroupRoleCache) GroupRoleDatabase {
+	return &groupRoleDatabase{db: db, cache: cache}
+}
+
+func (g *groupRoleDatabase) SetRole(ctx context.Context, groupID string, roleID string, name string, permissions int64) error {
+	if roleID == "" || len(roleID) > groupRoleIDMaxLen {
+		return errs.ErrArgs.Wrap(fmt.Sprintf("roleID must be 1 to %d characters", groupRoleIDMaxLen))
+	}
+	if permissions&^relation.GroupPermissionAll != 0 {
+		return errs.ErrArgs.Wrap("unknown permission bits")
+	}
+	roles, err := g.cache.GetRoles(ctx, groupID)
+	if err != nil {
+		return err
+	}
+	if findGroupRole(roles, roleID) == nil && len(roles) >= GroupRoleMaxNum {
+		return errs.ErrArgs.Wrap(fmt.Sprintf("a group defines at most %d roles", GroupRoleMaxNum))
+	}
+	now := time.Now()
+	if err := g.db.SetRole(ctx, &relation.GroupRoleModel{
+		GroupID:     groupID,
+		RoleID:      roleID,
+		Name:        name,
+		Permissions: permissions,
+		CreateTime:  now,
+		UpdateTime:  now,
+	}); err != nil {
+		return err
+	}
+	return g.cache.DelRoles(groupID).ExecDel(ctx)
+}
+
+func (g *groupRoleDatabase) DeleteRole(ctx context.Context, groupID string, roleID string) error {
+	userIDs, err := g.db.FindRoleMemberIDs(ctx, groupID, roleID)
+	if err != nil {
+		return err
+	}
+	if len(userIDs) > 0 {
+		if err := g.db.DeleteMemberRoles(ctx, groupID, userIDs); err != nil {
+			return err
+		}
+	}
+	if err := g.db.DeleteRole(ctx, groupID, roleID); err != nil {
+		return err
+	}
+	return g.cache.DelRoles(groupID).DelMemberRoleID(groupID, userIDs...).ExecDel(ctx)
+}
+
+func (g *groupRoleDatabase) GetRoles(ctx context.Context, groupID string) ([]*relation.GroupRoleModel, error) {
+	return g.cache.GetRoles(ctx, groupID)
+}
+
+func (g *groupRoleDatabase) AssignRole(ctx context.Context, groupID string, roleID string, userIDs []string) error {
+	if roleID == "" {
+		return g.UnassignMembers(ctx, groupID, userIDs)
+	}
+	roles, err := g.cache.GetRoles(ctx, groupID)
+	if err != nil {
+		return err
+	}
+	if findGroupRole(roles, roleID) == nil {
+		return errs.ErrRecordNotFound.Wrap("group role " + roleID)
+	}
+	if err := g.db.SetMemberRole(ctx, groupID, userIDs, roleID); err != nil {
+		return err
+	}
+	return g.cache.DelMemberRoleID(groupID, userIDs...).ExecDel(ctx)
+}
+
+func (g *groupRoleDatabase) UnassignMembers(ctx context.Context, groupID string, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	if err := g.db.DeleteMemberRoles(ctx, groupID, userIDs); err != nil {
+		return err
+	}
+	return g.cache.DelMemberRoleID(groupID, userIDs...).ExecDel(ctx)
+}
+
+func (g *groupRoleDatabase) GetMemberRoles(ctx context.Context, groupID string) ([]*relation.GroupMemberRoleModel, error) {
+	return g.db.FindMemberRoles(ctx, groupID)
+}
+
+func (g *groupRoleDatabase) GetMemberRole(ctx context.Context, groupID string, userID string) (*relation.GroupRoleModel, error) {
+	roleID, err := g.cache.GetMemberRoleID(ctx, groupID, userID)
+	if err != nil || roleID == "" {
+		return nil, err
+	}
+	roles, err := g.cache.GetRoles(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	return findGroupRole(roles, roleID), nil
+}
+
+func (g *groupRoleDatabase) GetMemberPermissions(ctx context.Context, groupID string, userID string, roleLevel int32) (int64, error) {
+	if roleLevel == constant.GroupOwner || roleLevel == constant.GroupAdmin {
+		return relation.GroupMemberPermissions(roleLevel, nil), nil
+	}
+	role, err := g.GetMemberRole(ctx, groupID, userID)
+	if err != nil {
+		return 0, err
+	}
+	return relation.GroupMemberPermissions(roleLevel, role), nil
+}
+
+func findGroupRole(roles []*relation.GroupRoleModel, roleID string) *relation.GroupRoleModel {
+	for _, role := range roles {
+		if role.RoleID == roleID {
+			return role
+		}
+	}
+	return nil
+}
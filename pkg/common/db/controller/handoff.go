@@ -0,0 +1,129 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/handoff"
+)
+
+const (
+	HandoffActionIssue  = "issue"
+	HandoffActionRedeem = "redeem"
+
+	HandoffResultOK        = "ok"
+	HandoffResultInvalid   = "invalid"
+	HandoffResultExpired   = "expired"
+	HandoffResultUsed      = "used"
+	HandoffResultWrongUser = "wrongUser"
+)
+
+// HandoffAudit records an issue or redeem attempt of a handoff token, TokenID and ConversationID are empty when
+// the token could not be parsed.
+type HandoffAudit struct {
+	TokenID        string `json:"tokenID"`
+	Action         string `json:"action"`
+	Result         string `json:"result"`
+	ConversationID string `json:"conversationID"`
+	Platform       string `json:"platform"`
+	IP             string `json:"ip"`
+	Time           int64  `json:"time"`
+}
+
+type HandoffDatabase interface {
+	// Issue registers grant for single use until it expires and audits it for its user.
+	Issue(ctx context.Context, grant *handoff.Grant, audit *HandoffAudit) error
+	// Redeem uses grant up, false when it was already redeemed. The attempt is audited for userID.
+	Redeem(ctx context.Context, userID string, grant *handoff.Grant, audit *HandoffAudit) (bool, error)
+	// Audit records a rejected attempt for userID.
+	Audit(ctx context.Context, userID string, audit *HandoffAudit)
+	GetAudit(ctx context.Context, userID string) ([]*HandoffAudit, error)
+}
+
+type handoffDatabase struct {
+	cache     cache.HandoffCache
+	auditSize int64
+}
+
+func NewHandoffDatabase(cache cache.HandoffCache, auditSize int) HandoffDatabase {
+	if auditSize <= 0 {
+		auditSize = 100
+	}
+	return &handoffDatabase{cache: cache, auditSize: int64(auditSize)}
+}
+
+func (h *handoffDatabase) Issue(ctx context.Context, grant *handoff.Grant, audit *HandoffAudit) error {
+	ttl := time.Until(time.Unix(grant.Expire, 0))
+	if ttl <= 0 {
+		return errs.ErrArgs.Wrap("handoff grant already expired")
+	}
+	if err := h.cache.Issue(ctx, grant.ID, grant.UserID, ttl); err != nil {
+		return err
+	}
+	audit.Action, audit.Result = HandoffActionIssue, HandoffResultOK
+	h.Audit(ctx, grant.UserID, audit)
+	return nil
+}
+
+func (h *handoffDatabase) Redeem(ctx context.Context, userID string, grant *handoff.Grant, audit *HandoffAudit) (bool, error) {
+	ok, err := h.cache.Redeem(ctx, grant.ID)
+	if err != nil {
+		return false, err
+	}
+	audit.Action, audit.Result = HandoffActionRedeem, HandoffResultOK
+	if !ok {
+		audit.Result = HandoffResultUsed
+	}
+	h.Audit(ctx, userID, audit)
+	return ok, nil
+}
+
+func (h *handoffDatabase) Audit(ctx context.Context, userID string, audit *HandoffAudit) {
+	if audit.Time == 0 {
+		audit.Time = time.Now().UnixMilli()
+	}
+	record, err := json.Marshal(audit)
+	if err != nil {
+		log.ZError(ctx, "marshal handoff audit failed", err)
+		return
+	}
+	// The audit is best effort, a redis hiccup must not fail a handoff.
+	if err := h.cache.AddAudit(ctx, userID, string(record), h.auditSize); err != nil {
+		log.ZWarn(ctx, "add handoff audit failed", err, "userID", userID)
+	}
+}
+
+func (h *handoffDatabase) GetAudit(ctx context.Context, userID string) ([]*HandoffAudit, error) {
+	records, err := h.cache.GetAudit(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	audits := make([]*HandoffAudit, 0, len(records))
+	for _, record := range records {
+		var audit HandoffAudit
+		if err := json.Unmarshal([]byte(record), &audit); err != nil {
+			log.ZWarn(ctx, "invalid handoff audit skipped", err, "userID", userID)
+			continue
+		}
+		audits = append(audits, &audit)
+	}
+	return audits, nil
+}
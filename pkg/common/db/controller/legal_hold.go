@@ -0,0 +1,205 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type LegalHoldDatabase interface {
+	// Place puts the target on hold and logs it, a target is on hold at most once.
+	Place(ctx context.Context, targetType string, targetID string, reason string, operatorUserID string) error
+	// Release takes the target off hold and logs it.
+	Release(ctx context.Context, targetType string, targetID string, operatorUserID string) error
+	// HeldUserIDs returns the users of userIDs that are on hold.
+	HeldUserIDs(ctx context.Context, userIDs []string) (map[string]struct{}, error)
+	// HeldConversationIDs returns the conversations of conversationIDs that are on hold, a notification
+	// conversation is on hold with its chat conversation.
+	HeldConversationIDs(ctx context.Context, conversationIDs []string) (map[string]struct{}, error)
+	// CheckDelete returns servererrs.ErrLegalHold when the conversation or one of the senders of the deleted
+	// messages is on hold.
+	CheckDelete(ctx context.Context, conversationID string, sendIDs []string) error
+	// FindAllHolds returns every hold of targetType.
+	FindAllHolds(ctx context.Context, targetType string) ([]*relation.LegalHoldModel, error)
+	PageHolds(ctx context.Context, targetType string, pagination pagination.Pagination) (int64, []*relation.LegalHoldModel, error)
+	PageLogs(ctx context.Context, targetType string, targetID string, pagination pagination.Pagination) (int64, []*relation.LegalHoldLogModel, error)
+}
+
+type legalHoldDatabase struct {
+	db relation.LegalHoldModelInterface
+}
+
+func NewLegalHoldDatabase(db relation.LegalHoldModelInterface) LegalHoldDatabase {
+	return &legalHoldDatabase{db: db}
+}
+
+func checkLegalHoldTarget(targetType string, targetID string) error {
+	switch targetType {
+	case relation.LegalHoldTargetUser, relation.LegalHoldTargetConversation:
+	default:
+		return errs.ErrArgs.Wrap("unknown legal hold target type " + targetType)
+	}
+	if targetID == "" {
+		return errs.ErrArgs.Wrap("targetID is empty")
+	}
+	return nil
+}
+
+func (l *legalHoldDatabase) Place(ctx context.Context, targetType string, targetID string, reason string, operatorUserID string) error {
+	if err := checkLegalHoldTarget(targetType, targetID); err != nil {
+		return err
+	}
+	now := time.Now()
+	hold := &relation.LegalHoldModel{
+		TargetType:     targetType,
+		TargetID:       targetID,
+		Reason:         reason,
+		OperatorUserID: operatorUserID,
+		CreateTime:     now,
+	}
+	if err := l.db.Create(ctx, hold); err != nil {
+		if mongo.IsDuplicateKeyError(errs.Unwrap(err)) {
+			return errs.ErrArgs.Wrap("the target is already on hold")
+		}
+		return err
+	}
+	return l.db.AddLog(ctx, &relation.LegalHoldLogModel{
+		TargetType:     targetType,
+		TargetID:       targetID,
+		Action:         relation.LegalHoldActionPlace,
+		Reason:         reason,
+		OperatorUserID: operatorUserID,
+		CreateTime:     now,
+	})
+}
+
+func (l *legalHoldDatabase) Release(ctx context.Context, targetType string, targetID string, operatorUserID string) error {
+	if err := checkLegalHoldTarget(targetType, targetID); err != nil {
+		return err
+	}
+	hold, err := l.db.Delete(ctx, targetType, targetID)
+	if err != nil {
+		return err
+	}
+	if hold == nil {
+		return errs.ErrRecordNotFound.Wrap("the target is not on hold")
+	}
+	return l.db.AddLog(ctx, &relation.LegalHoldLogModel{
+		TargetType:     targetType,
+		TargetID:       targetID,
+		Action:         relation.LegalHoldActionRelease,
+		Reason:         hold.Reason,
+		OperatorUserID: operatorUserID,
+		CreateTime:     time.Now(),
+	})
+}
+
+func (l *legalHoldDatabase) held(ctx context.Context, targetType string, targetIDs []string) (map[string]struct{}, error) {
+	res := make(map[string]struct{})
+	if len(targetIDs) == 0 {
+		return res, nil
+	}
+	holds, err := l.db.Find(ctx, targetType, targetIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, hold := range holds {
+		res[hold.TargetID] = struct{}{}
+	}
+	return res, nil
+}
+
+func (l *legalHoldDatabase) HeldUserIDs(ctx context.Context, userIDs []string) (map[string]struct{}, error) {
+	return l.held(ctx, relation.LegalHoldTargetUser, userIDs)
+}
+
+// holdConversationIDs returns the conversations whose hold covers conversationID.
+func holdConversationIDs(conversationID string) []string {
+	if !msgprocessor.IsNotification(conversationID) {
+		return []string{conversationID}
+	}
+	return []string{conversationID, "si" + conversationID[1:], "sg" + conversationID[1:]}
+}
+
+func (l *legalHoldDatabase) HeldConversationIDs(ctx context.Context, conversationIDs []string) (map[string]struct{}, error) {
+	lookup := make([]string, 0, len(conversationIDs))
+	for _, conversationID := range conversationIDs {
+		lookup = append(lookup, holdConversationIDs(conversationID)...)
+	}
+	held, err := l.held(ctx, relation.LegalHoldTargetConversation, lookup)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]struct{})
+	for _, conversationID := range conversationIDs {
+		for _, id := range holdConversationIDs(conversationID) {
+			if _, ok := held[id]; ok {
+				res[conversationID] = struct{}{}
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+func (l *legalHoldDatabase) CheckDelete(ctx context.Context, conversationID string, sendIDs []string) error {
+	held, err := l.HeldConversationIDs(ctx, []string{conversationID})
+	if err != nil {
+		return err
+	}
+	if len(held) > 0 {
+		return servererrs.ErrLegalHold.Wrap("the conversation is on legal hold")
+	}
+	heldUsers, err := l.HeldUserIDs(ctx, sendIDs)
+	if err != nil {
+		return err
+	}
+	if len(heldUsers) > 0 {
+		return servererrs.ErrLegalHold.Wrap("the sender of a message is on legal hold")
+	}
+	return nil
+}
+
+func (l *legalHoldDatabase) FindAllHolds(ctx context.Context, targetType string) ([]*relation.LegalHoldModel, error) {
+	const batchNum = 500
+	var res []*relation.LegalHoldModel
+	for pageNumber := int32(1); ; pageNumber++ {
+		_, holds, err := l.db.Page(ctx, targetType, &sdkws.RequestPagination{PageNumber: pageNumber, ShowNumber: batchNum})
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, holds...)
+		if len(holds) < batchNum {
+			return res, nil
+		}
+	}
+}
+
+func (l *legalHoldDatabase) PageHolds(ctx context.Context, targetType string, pagination pagination.Pagination) (int64, []*relation.LegalHoldModel, error) {
+	return l.db.Page(ctx, targetType, pagination)
+}
+
+func (l *legalHoldDatabase) PageLogs(ctx context.Context, targetType string, targetID string, pagination pagination.Pagination) (int64, []*relation.LegalHoldLogModel, error) {
+	return l.db.PageLogs(ctx, targetType, targetID, pagination)
+}
@@ -0,0 +1,155 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type MeetingRoomDatabase interface {
+	CreateRoom(ctx context.Context, room *relation.MeetingRoomModel) error
+	TakeRoom(ctx context.Context, roomID string) (*relation.MeetingRoomModel, error)
+	// UpdateRoom changes a scheduled room, moving its start time sends the starting soon notification again.
+	UpdateRoom(ctx context.Context, roomID string, title string, startTime, endTime time.Time, participantIDs []string) error
+	// CancelRoom cancels a scheduled room, it fails once the room has started.
+	CancelRoom(ctx context.Context, roomID string) error
+	// EndRoom ends a started room before its end time.
+	EndRoom(ctx context.Context, roomID string) error
+	GetUpcomingRooms(ctx context.Context, userID string, pagination pagination.Pagination) (int64, []*relation.MeetingRoomModel, error)
+	// ClaimStartingSoon returns up to limit rooms starting within remindBefore that still need the starting soon notification.
+	ClaimStartingSoon(ctx context.Context, remindBefore time.Duration, limit int64) ([]*relation.MeetingRoomModel, error)
+	// ClaimDueStart marks up to limit rooms whose start time has come as started and returns them.
+	ClaimDueStart(ctx context.Context, limit int64) ([]*relation.MeetingRoomModel, error)
+	// ClaimDueEnd marks up to limit started rooms whose end time has come as ended and returns them.
+	ClaimDueEnd(ctx context.Context, limit int64) ([]*relation.MeetingRoomModel, error)
+}
+
+type meetingRoomDatabase struct {
+	db relation.MeetingRoomModelInterface
+}
+
+func NewMeetingRoomDatabase(db relation.MeetingRoomModelInterface) MeetingRoomDatabase {
+	return &meetingRoomDatabase{db: db}
+}
+
+func (m *meetingRoomDatabase) CreateRoom(ctx context.Context, room *relation.MeetingRoomModel) error {
+	return m.db.Create(ctx, []*relation.MeetingRoomModel{room})
+}
+
+func (m *meetingRoomDatabase) TakeRoom(ctx context.Context, roomID string) (*relation.MeetingRoomModel, error) {
+	return m.db.Take(ctx, roomID)
+}
+
+func (m *meetingRoomDatabase) UpdateRoom(ctx context.Context, roomID string, title string, startTime, endTime time.Time, participantIDs []string) error {
+	room, err := m.db.Take(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	data := map[string]any{
+		"title":           title,
+		"start_time":      startTime,
+		"end_time":        endTime,
+		"participant_ids": participantIDs,
+		"update_time":     time.Now(),
+	}
+	if !room.StartTime.Equal(startTime) {
+		data["reminded"] = false
+	}
+	ok, err := m.db.Update(ctx, roomID, data)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errs.ErrArgs.Wrap("meeting room is no longer scheduled")
+	}
+	return nil
+}
+
+func (m *meetingRoomDatabase) CancelRoom(ctx context.Context, roomID string) error {
+	return m.updateStatus(ctx, roomID, relation.MeetingRoomScheduled, relation.MeetingRoomCanceled, "meeting room is no longer scheduled")
+}
+
+func (m *meetingRoomDatabase) EndRoom(ctx context.Context, roomID string) error {
+	return m.updateStatus(ctx, roomID, relation.MeetingRoomStarted, relation.MeetingRoomEnded, "meeting room is not started")
+}
+
+func (m *meetingRoomDatabase) updateStatus(ctx context.Context, roomID string, from int32, to int32, errMsg string) error {
+	ok, err := m.db.UpdateStatus(ctx, roomID, from, to)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errs.ErrArgs.Wrap(errMsg)
+	}
+	return nil
+}
+
+func (m *meetingRoomDatabase) GetUpcomingRooms(ctx context.Context, userID string, pagination pagination.Pagination) (int64, []*relation.MeetingRoomModel, error) {
+	return m.db.FindUpcoming(ctx, userID, pagination)
+}
+
+func (m *meetingRoomDatabase) ClaimStartingSoon(ctx context.Context, remindBefore time.Duration, limit int64) ([]*relation.MeetingRoomModel, error) {
+	rooms, err := m.db.FindToRemind(ctx, time.Now().Add(remindBefore), limit)
+	if err != nil {
+		return nil, err
+	}
+	claimed := make([]*relation.MeetingRoomModel, 0, len(rooms))
+	for _, room := range rooms {
+		ok, err := m.db.MarkReminded(ctx, room.RoomID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			claimed = append(claimed, room)
+		}
+	}
+	return claimed, nil
+}
+
+func (m *meetingRoomDatabase) ClaimDueStart(ctx context.Context, limit int64) ([]*relation.MeetingRoomModel, error) {
+	rooms, err := m.db.FindDueStart(ctx, time.Now(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return m.claimStatus(ctx, rooms, relation.MeetingRoomScheduled, relation.MeetingRoomStarted)
+}
+
+func (m *meetingRoomDatabase) ClaimDueEnd(ctx context.Context, limit int64) ([]*relation.MeetingRoomModel, error) {
+	rooms, err := m.db.FindDueEnd(ctx, time.Now(), limit)
+	if err != nil {
+		return nil, err
+	}
+	return m.claimStatus(ctx, rooms, relation.MeetingRoomStarted, relation.MeetingRoomEnded)
+}
+
+func (m *meetingRoomDatabase) claimStatus(ctx context.Context, rooms []*relation.MeetingRoomModel, from int32, to int32) ([]*relation.MeetingRoomModel, error) {
+	claimed := make([]*relation.MeetingRoomModel, 0, len(rooms))
+	for _, room := range rooms {
+		ok, err := m.db.UpdateStatus(ctx, room.RoomID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			room.Status = to
+			claimed = append(claimed, room)
+		}
+	}
+	return claimed, nil
+}
@@ -0,0 +1,113 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/moderation"
+)
+
+type ModerationDatabase interface {
+	// AddRule validates and stores a rule, it is applied by every msg instance after its next reload.
+	AddRule(ctx context.Context, rule *relation.ModerationRuleModel) error
+	DeleteRules(ctx context.Context, ruleIDs []string) error
+	PageRules(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.ModerationRuleModel, error)
+	// Filter returns the compiled rules, they are loaded again once they are older than the reload interval.
+	Filter(ctx context.Context) (*moderation.Filter, error)
+	Flag(ctx context.Context, flag *relation.ModerationFlagModel) error
+	PageFlags(ctx context.Context, sendID string, pagination pagination.Pagination) (int64, []*relation.ModerationFlagModel, error)
+}
+
+type moderationDatabase struct {
+	db             relation.ModerationModelInterface
+	reloadInterval time.Duration
+
+	lock     sync.Mutex
+	filter   *moderation.Filter
+	loadTime time.Time
+}
+
+func NewModerationDatabase(db relation.ModerationModelInterface, reloadInterval time.Duration) ModerationDatabase {
+	return &moderationDatabase{db: db, reloadInterval: reloadInterval}
+}
+
+func moderationRule(rule *relation.ModerationRuleModel) *moderation.Rule {
+	return &moderation.Rule{
+		RuleID:      rule.RuleID,
+		Pattern:     rule.Pattern,
+		Regex:       rule.Regex,
+		Action:      rule.Action,
+		Replacement: rule.Replacement,
+	}
+}
+
+func (m *moderationDatabase) AddRule(ctx context.Context, rule *relation.ModerationRuleModel) error {
+	if err := moderation.Validate(moderationRule(rule)); err != nil {
+		return err
+	}
+	rule.RuleID = utils.GetMsgID(rule.OperatorUserID)
+	rule.CreateTime = time.Now()
+	return m.db.CreateRule(ctx, rule)
+}
+
+func (m *moderationDatabase) DeleteRules(ctx context.Context, ruleIDs []string) error {
+	return m.db.DeleteRules(ctx, ruleIDs)
+}
+
+func (m *moderationDatabase) PageRules(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.ModerationRuleModel, error) {
+	return m.db.PageRules(ctx, pagination)
+}
+
+func (m *moderationDatabase) Filter(ctx context.Context) (*moderation.Filter, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.filter != nil && time.Since(m.loadTime) < m.reloadInterval {
+		return m.filter, nil
+	}
+	rules, err := m.db.FindAllRules(ctx)
+	if err != nil {
+		if m.filter != nil {
+			// Keep the rules loaded before rather than letting messages through unfiltered.
+			return m.filter, nil
+		}
+		return nil, err
+	}
+	filterRules := make([]*moderation.Rule, 0, len(rules))
+	for _, rule := range rules {
+		filterRules = append(filterRules, moderationRule(rule))
+	}
+	filter, err := moderation.NewFilter(filterRules)
+	if err != nil {
+		return nil, err
+	}
+	m.filter = filter
+	m.loadTime = time.Now()
+	return filter, nil
+}
+
+func (m *moderationDatabase) Flag(ctx context.Context, flag *relation.ModerationFlagModel) error {
+	flag.CreateTime = time.Now()
+	return m.db.CreateFlag(ctx, flag)
+}
+
+func (m *moderationDatabase) PageFlags(ctx context.Context, sendID string, pagination pagination.Pagination) (int64, []*relation.ModerationFlagModel, error) {
+	return m.db.PageFlags(ctx, sendID, pagination)
+}
@@ -0,0 +1,75 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/moderation"
+)
+
+type ModerationSensitivityDatabase interface {
+	SetLevel(ctx context.Context, conversationID string, level string, operatorUserID string) error
+	DeleteLevel(ctx context.Context, conversationID string) error
+	// GetLevel returns the sensitivity level set on the conversation, empty when it uses the default.
+	GetLevel(ctx context.Context, conversationID string) (string, error)
+	PageLevels(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.ModerationSensitivityModel, error)
+}
+
+type moderationSensitivityDatabase struct {
+	db    relation.ModerationSensitivityModelInterface
+	cache cache.ModerationSensitivityCache
+}
+
+func NewModerationSensitivityDatabase(db relation.ModerationSensitivityModelInterface, cache cache.ModerationSensitivityCache) ModerationSensitivityDatabase {
+	return &moderationSensitivityDatabase{db: db, cache: cache}
+}
+
+func (m *moderationSensitivityDatabase) SetLevel(ctx context.Context, conversationID string, level string, operatorUserID string) error {
+	if !moderation.ValidSensitivity(level) {
+		return errs.ErrArgs.Wrap(fmt.Sprintf("unknown sensitivity level %s", level))
+	}
+	sensitivity := &relation.ModerationSensitivityModel{
+		ConversationID: conversationID,
+		Level:          level,
+		OperatorUserID: operatorUserID,
+		UpdateTime:     time.Now(),
+	}
+	if err := m.db.Set(ctx, sensitivity); err != nil {
+		return err
+	}
+	return m.cache.DelLevel(conversationID).ExecDel(ctx)
+}
+
+func (m *moderationSensitivityDatabase) DeleteLevel(ctx context.Context, conversationID string) error {
+	if err := m.db.Delete(ctx, conversationID); err != nil {
+		return err
+	}
+	return m.cache.DelLevel(conversationID).ExecDel(ctx)
+}
+
+func (m *moderationSensitivityDatabase) GetLevel(ctx context.Context, conversationID string) (string, error) {
+	return m.cache.GetLevel(ctx, conversationID)
+}
+
+func (m *moderationSensitivityDatabase) PageLevels(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.ModerationSensitivityModel, error) {
+	return m.db.Page(ctx, pagination)
+}
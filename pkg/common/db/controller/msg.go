@@ -18,6 +18,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	"github.com/OpenIMSDK/protocol/constant"
@@ -29,10 +32,13 @@ import (
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/convert"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
 	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
-	"github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mqbuild"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -48,6 +54,8 @@ type CommonMsgDatabase interface {
 	BatchInsertChat2DB(ctx context.Context, conversationID string, msgs []*sdkws.MsgData, currentMaxSeq int64) error
 	// RevokeMsg revokes a message in a conversation.
 	RevokeMsg(ctx context.Context, conversationID string, seq int64, revoke *unrelationtb.RevokeModel) error
+	// EditMsg replaces the content of a message already stored in mongo.
+	EditMsg(ctx context.Context, conversationID string, seq int64, content string) error
 	// MarkSingleChatMsgsAsRead marks messages as read for a single chat by sequence numbers.
 	MarkSingleChatMsgsAsRead(ctx context.Context, userID string, conversationID string, seqs []int64) error
 	// DeleteMessagesFromCache deletes message caches from Redis by sequence numbers.
@@ -120,63 +128,82 @@ type CommonMsgDatabase interface {
 	ConvertMsgsDocLen(ctx context.Context, conversationIDs []string)
 }
 
-func NewCommonMsgDatabase(msgDocModel unrelationtb.MsgDocModelInterface, cacheModel cache.MsgModel, config *config.GlobalConfig) (CommonMsgDatabase, error) {
-	producerConfig := &kafka.ProducerConfig{
-		ProducerAck:  config.Kafka.ProducerAck,
-		CompressType: config.Kafka.CompressType,
-		Username:     config.Kafka.Username,
-		Password:     config.Kafka.Password,
-	}
-
-	var tlsConfig *kafka.TLSConfig
-	if config.Kafka.TLS != nil {
-		tlsConfig = &kafka.TLSConfig{
-			CACrt:              config.Kafka.TLS.CACrt,
-			ClientCrt:          config.Kafka.TLS.ClientCrt,
-			ClientKey:          config.Kafka.TLS.ClientKey,
-			ClientKeyPwd:       config.Kafka.TLS.ClientKeyPwd,
-			InsecureSkipVerify: false,
-		}
-	}
-	producerToRedis, err := kafka.NewKafkaProducer(config.Kafka.Addr, config.Kafka.LatestMsgToRedis.Topic, producerConfig, tlsConfig)
+// NewCommonMsgDatabase builds the message database, seqAlloc hands out the seqs of new messages and is nil unless
+// seqAllocator is enabled, the seqs then follow MAX_SEQ in redis.
+func NewCommonMsgDatabase(msgDocModel unrelationtb.MsgDocModelInterface, cacheModel cache.MsgModel, archiveModel relation.ArchiveWatermarkModelInterface, seqAlloc cache.SeqAllocator, config *config.GlobalConfig) (CommonMsgDatabase, error) {
+	producerToRedis, err := mqbuild.NewProducer(config, config.Kafka.LatestMsgToRedis.Topic)
 	if err != nil {
 		return nil, err
 	}
-	producerToMongo, err := kafka.NewKafkaProducer(config.Kafka.Addr, config.Kafka.MsgToMongo.Topic, producerConfig, tlsConfig)
+	producerToMongo, err := mqbuild.NewProducer(config, config.Kafka.MsgToMongo.Topic)
 	if err != nil {
 		return nil, err
 	}
-	producerToPush, err := kafka.NewKafkaProducer(config.Kafka.Addr, config.Kafka.MsgToPush.Topic, producerConfig, tlsConfig)
+	producerToPush, err := mqbuild.NewProducer(config, config.Kafka.MsgToPush.Topic)
 	if err != nil {
 		return nil, err
 	}
-	return &commonMsgDatabase{
+	db := newCommonMsgDatabase(msgDocModel, cacheModel, archiveModel, config, producerToRedis, producerToMongo, producerToPush)
+	db.seqAlloc = seqAlloc
+	if topic := config.Kafka.LatestMsgToRedis.HighPriorityTopic; topic != "" {
+		db.producerHighPriority, err = mqbuild.NewProducer(config, topic)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// NewCommonMsgDatabaseWithProducers builds the database on caller supplied producers, e.g. in-memory queues in tests.
+func NewCommonMsgDatabaseWithProducers(msgDocModel unrelationtb.MsgDocModelInterface, cacheModel cache.MsgModel, archiveModel relation.ArchiveWatermarkModelInterface, config *config.GlobalConfig, producerToRedis, producerToMongo, producerToPush mq.Producer) CommonMsgDatabase {
+	return newCommonMsgDatabase(msgDocModel, cacheModel, archiveModel, config, producerToRedis, producerToMongo, producerToPush)
+}
+
+func newCommonMsgDatabase(msgDocModel unrelationtb.MsgDocModelInterface, cacheModel cache.MsgModel, archiveModel relation.ArchiveWatermarkModelInterface, config *config.GlobalConfig, producerToRedis, producerToMongo, producerToPush mq.Producer) *commonMsgDatabase {
+	db := &commonMsgDatabase{
 		msgDocDatabase:  msgDocModel,
 		cache:           cacheModel,
 		producer:        producerToRedis,
 		producerToMongo: producerToMongo,
 		producerToPush:  producerToPush,
-	}, nil
+		recentMsgCache:  config.RecentMsgCache.Enable,
+	}
+	if config.Archive.Enable {
+		db.archive = archiveModel
+	}
+	return db
 }
 
-func InitCommonMsgDatabase(rdb redis.UniversalClient, database *mongo.Database, config *config.GlobalConfig) (CommonMsgDatabase, error) {
+func InitCommonMsgDatabase(rdb redis.UniversalClient, database *mongo.Database, archiveModel relation.ArchiveWatermarkModelInterface, config *config.GlobalConfig) (CommonMsgDatabase, error) {
 	cacheModel := cache.NewMsgCacheModel(rdb, config)
-	msgDocModel := unrelation.NewMsgMongoDriver(database)
-	return NewCommonMsgDatabase(msgDocModel, cacheModel, config)
+	msgDocModel := unrelation.NewMsgMongoDriver(database, nil)
+	return NewCommonMsgDatabase(msgDocModel, cacheModel, archiveModel, nil, config)
 }
 
 type commonMsgDatabase struct {
 	msgDocDatabase   unrelationtb.MsgDocModelInterface
 	msg              unrelationtb.MsgDocModel
 	cache            cache.MsgModel
-	producer         *kafka.Producer
-	producerToMongo  *kafka.Producer
-	producerToModify *kafka.Producer
-	producerToPush   *kafka.Producer
+	producer         mq.Producer
+	producerToMongo  mq.Producer
+	producerToModify mq.Producer
+	producerToPush   mq.Producer
+	// producerHighPriority is set when high priority messages have their own topic.
+	producerHighPriority mq.Producer
+	// archive is set when the compliance archive is enabled, messages above its watermark must not be physically deleted.
+	archive relation.ArchiveWatermarkModelInterface
+	// recentMsgCache serves the latest messages of active conversations from a single redis key.
+	recentMsgCache bool
+	// seqAlloc is set when seqs are leased in segments, see seqAllocator.
+	seqAlloc cache.SeqAllocator
 }
 
 func (db *commonMsgDatabase) MsgToMQ(ctx context.Context, key string, msg2mq *sdkws.MsgData) error {
-	_, _, err := db.producer.SendMessage(ctx, key, msg2mq)
+	producer := db.producer
+	if db.producerHighPriority != nil && msgprocessor.GetPriority(msg2mq) == msgprocessor.PriorityHigh {
+		producer = db.producerHighPriority
+	}
+	_, _, err := producer.SendMessage(ctx, key, msg2mq)
 	return err
 }
 
@@ -356,6 +383,18 @@ func (db *commonMsgDatabase) RevokeMsg(ctx context.Context, conversationID strin
 	return db.BatchInsertBlock(ctx, conversationID, []any{revoke}, updateKeyRevoke, seq)
 }
 
+func (db *commonMsgDatabase) EditMsg(ctx context.Context, conversationID string, seq int64, content string) error {
+	res, err := db.msgDocDatabase.UpdateMsg(ctx, db.msg.GetDocID(conversationID, seq), db.msg.GetMsgIndex(seq), "msg.content", content)
+	if err != nil {
+		return err
+	}
+	// Messages only reach mongo asynchronously through msgtransfer, an edit made before would be overwritten.
+	if res.MatchedCount == 0 {
+		return errs.ErrRecordNotFound.Wrap("msg is not stored yet")
+	}
+	return db.cache.DeleteMessages(ctx, conversationID, []int64{seq})
+}
+
 func (db *commonMsgDatabase) MarkSingleChatMsgsAsRead(ctx context.Context, userID string, conversationID string, totalSeqs []int64) error {
 	for docID, seqs := range db.msg.GetDocIDSeqsMap(conversationID, totalSeqs) {
 		var indexes []int64
@@ -380,11 +419,6 @@ func (db *commonMsgDatabase) DelUserDeleteMsgsList(ctx context.Context, conversa
 }
 
 func (db *commonMsgDatabase) BatchInsertChat2Cache(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) (seq int64, isNew bool, err error) {
-	currentMaxSeq, err := db.cache.GetMaxSeq(ctx, conversationID)
-	if err != nil && errs.Unwrap(err) != redis.Nil {
-		log.ZError(ctx, "db.cache.GetMaxSeq", err)
-		return 0, false, err
-	}
 	lenList := len(msgs)
 	if int64(lenList) > db.msg.GetSingleGocMsgNum() {
 		return 0, false, errors.New("too large")
@@ -392,8 +426,23 @@ func (db *commonMsgDatabase) BatchInsertChat2Cache(ctx context.Context, conversa
 	if lenList < 1 {
 		return 0, false, errors.New("too short as 0")
 	}
-	if errs.Unwrap(err) == redis.Nil {
-		isNew = true
+	var currentMaxSeq int64
+	if db.seqAlloc != nil {
+		currentMaxSeq, err = db.seqAlloc.Malloc(ctx, conversationID, int64(lenList))
+		if err != nil {
+			log.ZError(ctx, "db.seqAlloc.Malloc", err, "conversationID", conversationID)
+			return 0, false, err
+		}
+		isNew = currentMaxSeq == 0
+	} else {
+		currentMaxSeq, err = db.cache.GetMaxSeq(ctx, conversationID)
+		if err != nil && errs.Unwrap(err) != redis.Nil {
+			log.ZError(ctx, "db.cache.GetMaxSeq", err)
+			return 0, false, err
+		}
+		if errs.Unwrap(err) == redis.Nil {
+			isNew = true
+		}
 	}
 	lastMaxSeq := currentMaxSeq
 	userSeqMap := make(map[string]int64)
@@ -409,6 +458,11 @@ func (db *commonMsgDatabase) BatchInsertChat2Cache(ctx context.Context, conversa
 	} else {
 		prommetrics.MsgInsertRedisSuccessCounter.Inc()
 	}
+	if db.recentMsgCache {
+		if err := db.cache.AddRecentMessages(ctx, conversationID, msgs); err != nil {
+			log.ZError(ctx, "AddRecentMessages error", err, "len", len(msgs), "conversationID", conversationID)
+		}
+	}
 	err = db.cache.SetMaxSeq(ctx, conversationID, currentMaxSeq)
 	if err != nil {
 		log.ZError(ctx, "db.cache.SetMaxSeq error", err, "conversationID", conversationID)
@@ -419,6 +473,9 @@ func (db *commonMsgDatabase) BatchInsertChat2Cache(ctx context.Context, conversa
 		log.ZError(ctx, "SetHasReadSeqs error", err, "userSeqMap", userSeqMap, "conversationID", conversationID)
 		prommetrics.SeqSetFailedCounter.Inc()
 	}
+	if err := db.setGroupMemberReadSeqs(ctx, conversationID, userSeqMap); err != nil {
+		log.ZError(ctx, "setGroupMemberReadSeqs error", err, "userSeqMap", userSeqMap, "conversationID", conversationID)
+	}
 	return lastMaxSeq, isNew, errs.Wrap(err)
 }
 
@@ -541,6 +598,52 @@ func (db *commonMsgDatabase) getMsgBySeqsRange(ctx context.Context, userID strin
 // For new users joining the group, if they don't need to receive old messages,
 // "userMinSeq" can be set as the same value as the conversation's "maxSeq" at the moment they join the group.
 // This ensures that their message retrieval starts from the point they joined.
+// getCachedMsgs reads seqs from the latest messages of the conversation first and the rest from the message cache,
+// the messages are returned by seq.
+func (db *commonMsgDatabase) getCachedMsgs(ctx context.Context, conversationID string, seqs []int64) ([]*sdkws.MsgData, []int64, error) {
+	if !db.recentMsgCache || len(seqs) == 0 {
+		return db.cache.GetMessagesBySeq(ctx, conversationID, seqs)
+	}
+	begin, end := seqs[0], seqs[0]
+	for _, seq := range seqs {
+		if seq < begin {
+			begin = seq
+		}
+		if seq > end {
+			end = seq
+		}
+	}
+	recentMsgs, err := db.cache.GetRecentMessages(ctx, conversationID, begin, end)
+	if err != nil {
+		log.ZWarn(ctx, "GetRecentMessages failed", err, "conversationID", conversationID)
+		return db.cache.GetMessagesBySeq(ctx, conversationID, seqs)
+	}
+	found := make(map[int64]*sdkws.MsgData, len(recentMsgs))
+	for _, msg := range recentMsgs {
+		if msg.Status != constant.MsgDeleted {
+			found[msg.Seq] = msg
+		}
+	}
+	var missSeqs []int64
+	msgs := make([]*sdkws.MsgData, 0, len(seqs))
+	for _, seq := range seqs {
+		if msg, ok := found[seq]; ok {
+			msgs = append(msgs, msg)
+		} else {
+			missSeqs = append(missSeqs, seq)
+		}
+	}
+	if len(missSeqs) == 0 {
+		prommetrics.RecentMsgCacheHitCounter.Inc()
+		return msgs, nil, nil
+	}
+	prommetrics.RecentMsgCacheMissCounter.Inc()
+	cachedMsgs, failedSeqs, err := db.cache.GetMessagesBySeq(ctx, conversationID, missSeqs)
+	msgs = append(msgs, cachedMsgs...)
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].Seq < msgs[j].Seq })
+	return msgs, failedSeqs, err
+}
+
 func (db *commonMsgDatabase) GetMsgBySeqsRange(ctx context.Context, userID string, conversationID string, begin, end, num, userMaxSeq int64) (int64, int64, []*sdkws.MsgData, error) {
 	userMinSeq, err := db.cache.GetConversationUserMinSeq(ctx, conversationID, userID)
 	if err != nil && errs.Unwrap(err) != redis.Nil {
@@ -609,7 +712,7 @@ func (db *commonMsgDatabase) GetMsgBySeqsRange(ctx context.Context, userID strin
 	newBegin := seqs[0]
 	newEnd := seqs[len(seqs)-1]
 	log.ZDebug(ctx, "GetMsgBySeqsRange", "first seqs", seqs, "newBegin", newBegin, "newEnd", newEnd)
-	cachedMsgs, failedSeqs, err := db.cache.GetMessagesBySeq(ctx, conversationID, seqs)
+	cachedMsgs, failedSeqs, err := db.getCachedMsgs(ctx, conversationID, seqs)
 	if err != nil {
 		if err != redis.Nil {
 
@@ -697,7 +800,7 @@ func (db *commonMsgDatabase) GetMsgBySeqs(ctx context.Context, userID string, co
 			newSeqs = append(newSeqs, seq)
 		}
 	}
-	successMsgs, failedSeqs, err := db.cache.GetMessagesBySeq(ctx, conversationID, newSeqs)
+	successMsgs, failedSeqs, err := db.getCachedMsgs(ctx, conversationID, newSeqs)
 	if err != nil {
 		if err != redis.Nil {
 			log.ZError(ctx, "get message from redis exception", err, "failedSeqs", failedSeqs, "conversationID", conversationID)
@@ -732,8 +835,24 @@ func (db *commonMsgDatabase) GetMsgBySeqs(ctx context.Context, userID string, co
 	return minSeq, maxSeq, successMsgs, nil
 }
 
+// archivedSeq returns the highest seq that may be physically deleted in the conversation.
+func (db *commonMsgDatabase) archivedSeq(ctx context.Context, conversationID string) (int64, error) {
+	if db.archive == nil {
+		return math.MaxInt64, nil
+	}
+	seqs, err := db.archive.GetSeqs(ctx, []string{conversationID})
+	if err != nil {
+		return 0, err
+	}
+	return seqs[conversationID], nil
+}
+
 func (db *commonMsgDatabase) DeleteConversationMsgsAndSetMinSeq(ctx context.Context, conversationID string, remainTime int64) error {
-	var delStruct delMsgRecursionStruct
+	archivedSeq, err := db.archivedSeq(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	delStruct := delMsgRecursionStruct{archivedSeq: archivedSeq}
 	var skip int64
 	minSeq, err := db.deleteMsgRecursion(ctx, conversationID, skip, &delStruct, remainTime)
 	if err != nil {
@@ -809,8 +928,9 @@ func (db *commonMsgDatabase) UserMsgsDestruct(ctx context.Context, userID string
 
 // this is struct for recursion.
 type delMsgRecursionStruct struct {
-	minSeq    int64
-	delDocIDs []string
+	minSeq      int64
+	delDocIDs   []string
+	archivedSeq int64
 }
 
 func (d *delMsgRecursionStruct) getSetMinSeq() int64 {
@@ -843,7 +963,8 @@ func (db *commonMsgDatabase) deleteMsgRecursion(ctx context.Context, conversatio
 	if int64(len(msgDocModel.Msg)) > db.msg.GetSingleGocMsgNum() {
 		log.ZWarn(ctx, "msgs too large", nil, "lenth", len(msgDocModel.Msg), "docID:", msgDocModel.DocID)
 	}
-	if msgDocModel.IsFull() && msgDocModel.Msg[len(msgDocModel.Msg)-1].Msg.SendTime+(remainTime*1000) < utils.GetCurrentTimestampByMill() {
+	if msgDocModel.IsFull() && msgDocModel.Msg[len(msgDocModel.Msg)-1].Msg.SendTime+(remainTime*1000) < utils.GetCurrentTimestampByMill() &&
+		msgDocModel.Msg[len(msgDocModel.Msg)-1].Msg.Seq <= delStruct.archivedSeq {
 		log.ZDebug(ctx, "doc is full and all msg is expired", "docID", msgDocModel.DocID)
 		delStruct.delDocIDs = append(delStruct.delDocIDs, msgDocModel.DocID)
 		delStruct.minSeq = msgDocModel.Msg[len(msgDocModel.Msg)-1].Msg.Seq
@@ -851,7 +972,7 @@ func (db *commonMsgDatabase) deleteMsgRecursion(ctx context.Context, conversatio
 		var delMsgIndexs []int
 		for i, MsgInfoModel := range msgDocModel.Msg {
 			if MsgInfoModel != nil && MsgInfoModel.Msg != nil {
-				if utils.GetCurrentTimestampByMill() > MsgInfoModel.Msg.SendTime+(remainTime*1000) {
+				if utils.GetCurrentTimestampByMill() > MsgInfoModel.Msg.SendTime+(remainTime*1000) && MsgInfoModel.Msg.Seq <= delStruct.archivedSeq {
 					delMsgIndexs = append(delMsgIndexs, i)
 				}
 			}
@@ -868,6 +989,15 @@ func (db *commonMsgDatabase) deleteMsgRecursion(ctx context.Context, conversatio
 }
 
 func (db *commonMsgDatabase) DeleteMsgsPhysicalBySeqs(ctx context.Context, conversationID string, allSeqs []int64) error {
+	archivedSeq, err := db.archivedSeq(ctx, conversationID)
+	if err != nil {
+		return err
+	}
+	for _, seq := range allSeqs {
+		if seq > archivedSeq {
+			return errs.ErrNoPermission.Wrap(fmt.Sprintf("conversation %s seq %d is not archived yet", conversationID, seq))
+		}
+	}
 	if err := db.cache.DeleteMessages(ctx, conversationID, allSeqs); err != nil {
 		return err
 	}
@@ -979,11 +1109,30 @@ func (db *commonMsgDatabase) SetUserConversationsMinSeqs(ctx context.Context, us
 }
 
 func (db *commonMsgDatabase) UserSetHasReadSeqs(ctx context.Context, userID string, hasReadSeqs map[string]int64) error {
-	return db.cache.UserSetHasReadSeqs(ctx, userID, hasReadSeqs)
+	if err := db.cache.UserSetHasReadSeqs(ctx, userID, hasReadSeqs); err != nil {
+		return err
+	}
+	for conversationID, seq := range hasReadSeqs {
+		if err := db.setGroupMemberReadSeqs(ctx, conversationID, map[string]int64{userID: seq}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (db *commonMsgDatabase) SetHasReadSeq(ctx context.Context, userID string, conversationID string, hasReadSeq int64) error {
-	return db.cache.SetHasReadSeq(ctx, userID, conversationID, hasReadSeq)
+	if err := db.cache.SetHasReadSeq(ctx, userID, conversationID, hasReadSeq); err != nil {
+		return err
+	}
+	return db.setGroupMemberReadSeqs(ctx, conversationID, map[string]int64{userID: hasReadSeq})
+}
+
+// setGroupMemberReadSeqs keeps the per group index of member has read seqs that backs the group read members query.
+func (db *commonMsgDatabase) setGroupMemberReadSeqs(ctx context.Context, conversationID string, hasReadSeqs map[string]int64) error {
+	if !msgprocessor.IsGroupConversationID(conversationID) {
+		return nil
+	}
+	return db.cache.SetGroupMemberReadSeqs(ctx, conversationID, hasReadSeqs)
 }
 
 func (db *commonMsgDatabase) GetHasReadSeqs(ctx context.Context, userID string, conversationIDs []string) (map[string]int64, error) {
@@ -0,0 +1,75 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	pbmsg "github.com/OpenIMSDK/protocol/msg"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"google.golang.org/protobuf/proto"
+)
+
+// msgDedupPendingExpire bounds how long a claim without a result blocks the retries of a send that never finished.
+const msgDedupPendingExpire = time.Second * 30
+
+type MsgDedupDatabase interface {
+	// Claim returns true when sendID did not send clientMsgID within the window, otherwise the result of the first
+	// send, nil while that send is still in flight.
+	Claim(ctx context.Context, sendID string, clientMsgID string) (bool, *pbmsg.SendMsgResp, error)
+	// Done keeps resp for the retries of the message until the window is over.
+	Done(ctx context.Context, sendID string, clientMsgID string, resp *pbmsg.SendMsgResp) error
+	// Release forgets a claimed message whose send failed, so that the client can send it again.
+	Release(ctx context.Context, sendID string, clientMsgID string) error
+}
+
+type msgDedupDatabase struct {
+	cache  cache.MsgDedupCache
+	window time.Duration
+}
+
+func NewMsgDedupDatabase(cache cache.MsgDedupCache, window time.Duration) MsgDedupDatabase {
+	return &msgDedupDatabase{cache: cache, window: window}
+}
+
+func (m *msgDedupDatabase) Claim(ctx context.Context, sendID string, clientMsgID string) (bool, *pbmsg.SendMsgResp, error) {
+	pending := msgDedupPendingExpire
+	if m.window < pending {
+		pending = m.window
+	}
+	claimed, result, err := m.cache.Claim(ctx, sendID, clientMsgID, pending)
+	if err != nil || claimed || result == "" {
+		return claimed, nil, err
+	}
+	var resp pbmsg.SendMsgResp
+	if err := proto.Unmarshal([]byte(result), &resp); err != nil {
+		return false, nil, errs.Wrap(err)
+	}
+	return false, &resp, nil
+}
+
+func (m *msgDedupDatabase) Done(ctx context.Context, sendID string, clientMsgID string, resp *pbmsg.SendMsgResp) error {
+	data, err := proto.Marshal(resp)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return m.cache.SetResult(ctx, sendID, clientMsgID, string(data), m.window)
+}
+
+func (m *msgDedupDatabase) Release(ctx context.Context, sendID string, clientMsgID string) error {
+	return m.cache.Release(ctx, sendID, clientMsgID)
+}
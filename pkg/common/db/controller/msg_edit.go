@@ -0,0 +1,43 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+// MsgEditDatabase keeps the content messages had before their edits, the edits themselves go through CommonMsgDatabase.EditMsg.
+type MsgEditDatabase interface {
+	AddEditHistory(ctx context.Context, history *relation.MsgEditHistoryModel) error
+	GetEditHistory(ctx context.Context, conversationID string, seq int64) ([]*relation.MsgEditHistoryModel, error)
+}
+
+type msgEditDatabase struct {
+	history relation.MsgEditHistoryModelInterface
+}
+
+func NewMsgEditDatabase(history relation.MsgEditHistoryModelInterface) MsgEditDatabase {
+	return &msgEditDatabase{history: history}
+}
+
+func (m *msgEditDatabase) AddEditHistory(ctx context.Context, history *relation.MsgEditHistoryModel) error {
+	return m.history.Create(ctx, []*relation.MsgEditHistoryModel{history})
+}
+
+func (m *msgEditDatabase) GetEditHistory(ctx context.Context, conversationID string, seq int64) ([]*relation.MsgEditHistoryModel, error) {
+	return m.history.Find(ctx, conversationID, seq)
+}
@@ -0,0 +1,133 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type editMsgKey struct {
+	docID string
+	index int64
+}
+
+// editMsgDoc holds the content of the messages stored in mongo.
+type editMsgDoc struct {
+	unrelationtb.MsgDocModelInterface
+	contents map[editMsgKey]any
+}
+
+func (e *editMsgDoc) UpdateMsg(_ context.Context, docID string, index int64, key string, value any) (*mongo.UpdateResult, error) {
+	k := editMsgKey{docID: docID, index: index}
+	if _, ok := e.contents[k]; !ok || key != "msg.content" {
+		return &mongo.UpdateResult{}, nil
+	}
+	e.contents[k] = value
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+type editMsgCache struct {
+	cache.MsgModel
+	deleted []int64
+}
+
+func (e *editMsgCache) DeleteMessages(_ context.Context, _ string, seqs []int64) error {
+	e.deleted = append(e.deleted, seqs...)
+	return nil
+}
+
+type editHistory struct {
+	histories []*relation.MsgEditHistoryModel
+}
+
+func (e *editHistory) Create(_ context.Context, histories []*relation.MsgEditHistoryModel) error {
+	e.histories = append(e.histories, histories...)
+	return nil
+}
+
+func (e *editHistory) Find(_ context.Context, conversationID string, seq int64) ([]*relation.MsgEditHistoryModel, error) {
+	var res []*relation.MsgEditHistoryModel
+	for _, history := range e.histories {
+		if history.ConversationID == conversationID && history.Seq == seq {
+			res = append(res, history)
+		}
+	}
+	return res, nil
+}
+
+func TestMsgEdit(t *testing.T) {
+	ctx := context.Background()
+	var msg unrelationtb.MsgDocModel
+	stored := editMsgKey{docID: msg.GetDocID("si_a_b", 1), index: msg.GetMsgIndex(1)}
+	doc := &editMsgDoc{contents: map[editMsgKey]any{stored: "v1"}}
+	msgCache := &editMsgCache{}
+	db := &commonMsgDatabase{msgDocDatabase: doc, cache: msgCache}
+
+	if err := db.EditMsg(ctx, "si_a_b", 1, "v2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.EditMsg(ctx, "si_a_b", 1, "v3"); err != nil {
+		t.Fatal(err)
+	}
+	if doc.contents[stored] != "v3" {
+		t.Fatalf("stored content = %v", doc.contents[stored])
+	}
+	if len(msgCache.deleted) != 2 || msgCache.deleted[0] != 1 || msgCache.deleted[1] != 1 {
+		t.Fatalf("deleted cached seqs = %v", msgCache.deleted)
+	}
+
+	// A message that has not reached mongo yet can not be edited.
+	if err := db.EditMsg(ctx, "si_a_b", 2, "v2"); !errs.ErrRecordNotFound.Is(err) {
+		t.Fatalf("edit of an unstored msg = %v", err)
+	}
+	if len(msgCache.deleted) != 2 {
+		t.Fatalf("deleted cached seqs = %v", msgCache.deleted)
+	}
+}
+
+func TestMsgEditHistory(t *testing.T) {
+	ctx := context.Background()
+	db := NewMsgEditDatabase(&editHistory{})
+	for _, content := range []string{"v1", "v2"} {
+		err := db.AddEditHistory(ctx, &relation.MsgEditHistoryModel{
+			ConversationID: "si_a_b",
+			Seq:            1,
+			EditorUserID:   "a",
+			Content:        content,
+			EditTime:       time.Now(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	histories, err := db.GetEditHistory(ctx, "si_a_b", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(histories) != 2 || histories[0].Content != "v1" || histories[1].Content != "v2" {
+		t.Fatalf("edit history = %v", histories)
+	}
+	if histories, err := db.GetEditHistory(ctx, "si_a_b", 2); err != nil || len(histories) != 0 {
+		t.Fatalf("edit history of another msg = %v, %v", histories, err)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+)
+
+type MsgExportDatabase interface {
+	// FindMsgs returns up to limit messages of conversationID sent at or after start and before end (ms), ordered by seq.
+	FindMsgs(ctx context.Context, conversationID string, start int64, end int64, limit int64) ([]*unrelationtb.MsgInfoModel, error)
+}
+
+type msgExportDatabase struct {
+	msgDocDB unrelationtb.MsgDocModelInterface
+}
+
+func NewMsgExportDatabase(msgDocDB unrelationtb.MsgDocModelInterface) MsgExportDatabase {
+	return &msgExportDatabase{msgDocDB: msgDocDB}
+}
+
+func (m *msgExportDatabase) FindMsgs(ctx context.Context, conversationID string, start int64, end int64, limit int64) ([]*unrelationtb.MsgInfoModel, error) {
+	return m.msgDocDB.FindMsgsBetween(ctx, conversationID, start, end, limit)
+}
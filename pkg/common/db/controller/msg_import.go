@@ -0,0 +1,114 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"sort"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+	"github.com/redis/go-redis/v9"
+)
+
+type MsgImportDatabase interface {
+	// Import appends msgs to conversationID in send time order after its max seq and stores them in mongo only,
+	// the imported messages count as read for readUserIDs. It returns the seq before the first imported message.
+	Import(ctx context.Context, conversationID string, msgs []*sdkws.MsgData, readUserIDs []string) (int64, error)
+}
+
+type msgImportDatabase struct {
+	// msg has no producers, imported messages never go through the queues.
+	msg      *commonMsgDatabase
+	seqAlloc cache.SeqAllocator
+}
+
+// NewMsgImportDatabase builds the import on the message storage, seqAlloc is nil unless seqAllocator is enabled.
+func NewMsgImportDatabase(msgDocModel unrelationtb.MsgDocModelInterface, cacheModel cache.MsgModel, seqAlloc cache.SeqAllocator, config *config.GlobalConfig) MsgImportDatabase {
+	return &msgImportDatabase{
+		msg:      newCommonMsgDatabase(msgDocModel, cacheModel, nil, config, nil, nil, nil),
+		seqAlloc: seqAlloc,
+	}
+}
+
+func (m *msgImportDatabase) Import(ctx context.Context, conversationID string, msgs []*sdkws.MsgData, readUserIDs []string) (int64, error) {
+	if len(msgs) == 0 {
+		return 0, errs.ErrArgs.Wrap("msgs is empty")
+	}
+	sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].SendTime < msgs[j].SendTime })
+	lastMaxSeq, err := m.malloc(ctx, conversationID, int64(len(msgs)))
+	if err != nil {
+		return 0, err
+	}
+	for i, msg := range msgs {
+		msg.Seq = lastMaxSeq + int64(i) + 1
+	}
+	if err := m.msg.BatchInsertChat2DB(ctx, conversationID, msgs, lastMaxSeq); err != nil {
+		return 0, err
+	}
+	maxSeq := lastMaxSeq + int64(len(msgs))
+	if m.seqAlloc != nil {
+		// The allocator hands out seqs without moving MAX_SEQ, new messages may already have moved it further.
+		current, err := m.getMaxSeq(ctx, conversationID)
+		if err != nil {
+			return 0, err
+		}
+		if current < maxSeq {
+			if err := m.msg.cache.SetMaxSeq(ctx, conversationID, maxSeq); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if len(readUserIDs) > 0 {
+		hasReadSeqs := make(map[string]int64, len(readUserIDs))
+		for _, userID := range readUserIDs {
+			hasReadSeqs[userID] = maxSeq
+		}
+		if err := m.msg.cache.SetHasReadSeqs(ctx, conversationID, hasReadSeqs); err != nil {
+			return 0, err
+		}
+		if err := m.msg.setGroupMemberReadSeqs(ctx, conversationID, hasReadSeqs); err != nil {
+			return 0, err
+		}
+	}
+	return lastMaxSeq, nil
+}
+
+// malloc reserves size seqs and returns the seq before them. Without the allocator MAX_SEQ is moved before the
+// messages are written, so a failed import leaves a gap rather than seqs that are handed out twice.
+func (m *msgImportDatabase) malloc(ctx context.Context, conversationID string, size int64) (int64, error) {
+	if m.seqAlloc != nil {
+		return m.seqAlloc.Malloc(ctx, conversationID, size)
+	}
+	lastMaxSeq, err := m.getMaxSeq(ctx, conversationID)
+	if err != nil {
+		return 0, err
+	}
+	if err := m.msg.cache.SetMaxSeq(ctx, conversationID, lastMaxSeq+size); err != nil {
+		return 0, err
+	}
+	return lastMaxSeq, nil
+}
+
+func (m *msgImportDatabase) getMaxSeq(ctx context.Context, conversationID string) (int64, error) {
+	maxSeq, err := m.msg.cache.GetMaxSeq(ctx, conversationID)
+	if err != nil && errs.Unwrap(err) != redis.Nil {
+		return 0, err
+	}
+	return maxSeq, nil
+}
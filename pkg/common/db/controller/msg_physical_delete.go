@@ -0,0 +1,72 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"regexp"
+	"sort"
+
+	"github.com/OpenIMSDK/tools/errs"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MsgPhysicalDeleteDatabase interface {
+	// FindMsgsBySeqs returns the stored messages of conversationID among seqs, ordered by seq.
+	FindMsgsBySeqs(ctx context.Context, conversationID string, seqs []int64) ([]*unrelationtb.MsgInfoModel, error)
+	// FindMsgsBefore returns up to limit messages of conversationID sent before sendTime (ms), ordered by seq.
+	FindMsgsBefore(ctx context.Context, conversationID string, sendTime int64, limit int64) ([]*unrelationtb.MsgInfoModel, error)
+	// IsObjectReferenced reports whether a stored message still refers to the object named name.
+	IsObjectReferenced(ctx context.Context, name string) (bool, error)
+}
+
+type msgPhysicalDeleteDatabase struct {
+	msgDocDB unrelationtb.MsgDocModelInterface
+	msg      unrelationtb.MsgDocModel
+}
+
+func NewMsgPhysicalDeleteDatabase(msgDocDB unrelationtb.MsgDocModelInterface) MsgPhysicalDeleteDatabase {
+	return &msgPhysicalDeleteDatabase{msgDocDB: msgDocDB}
+}
+
+func (m *msgPhysicalDeleteDatabase) FindMsgsBySeqs(ctx context.Context, conversationID string, seqs []int64) ([]*unrelationtb.MsgInfoModel, error) {
+	var msgs []*unrelationtb.MsgInfoModel
+	for docID, docSeqs := range m.msg.GetDocIDSeqsMap(conversationID, seqs) {
+		docMsgs, err := m.msgDocDB.GetMsgBySeqIndexIn1Doc(ctx, "", docID, docSeqs)
+		if err != nil {
+			if errs.Unwrap(err) == mongo.ErrNoDocuments {
+				continue
+			}
+			return nil, err
+		}
+		for _, msg := range docMsgs {
+			if msg != nil && msg.Msg != nil && msg.Msg.Seq > 0 {
+				msgs = append(msgs, msg)
+			}
+		}
+	}
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].Msg.Seq < msgs[j].Msg.Seq })
+	return msgs, nil
+}
+
+func (m *msgPhysicalDeleteDatabase) FindMsgsBefore(ctx context.Context, conversationID string, sendTime int64, limit int64) ([]*unrelationtb.MsgInfoModel, error) {
+	return m.msgDocDB.FindMsgsBetween(ctx, conversationID, 0, sendTime, limit)
+}
+
+func (m *msgPhysicalDeleteDatabase) IsObjectReferenced(ctx context.Context, name string) (bool, error) {
+	// Object urls sit in the json of the message content, whatever host served them.
+	return m.msgDocDB.ExistMsgContentMatch(ctx, "/object/"+regexp.QuoteMeta(name)+`([?#"\\]|$)`)
+}
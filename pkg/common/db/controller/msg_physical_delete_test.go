@@ -0,0 +1,65 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+)
+
+// contentMsgDoc matches patterns against the contents of the stored messages.
+type contentMsgDoc struct {
+	unrelationtb.MsgDocModelInterface
+	contents []string
+}
+
+func (c *contentMsgDoc) ExistMsgContentMatch(_ context.Context, pattern string) (bool, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, err
+	}
+	for _, content := range c.contents {
+		if re.MatchString(content) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestIsObjectReferenced(t *testing.T) {
+	doc := &contentMsgDoc{contents: []string{
+		`{"sourcePicture":{"url":"http://a:10002/object/u1/pic.jpg"}}`,
+		`{"sourceUrl":"https://b/object/u2/file.bin?name=x"}`,
+	}}
+	db := NewMsgPhysicalDeleteDatabase(doc)
+	for name, want := range map[string]bool{
+		"u1/pic.jpg":  true,
+		"u2/file.bin": true,
+		"u1/pic.jp":   false,
+		"u1/pic.jpg2": false,
+		"u1/pic+jpg":  false,
+	} {
+		referenced, err := db.IsObjectReferenced(context.Background(), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if referenced != want {
+			t.Errorf("IsObjectReferenced(%s) = %v", name, referenced)
+		}
+	}
+}
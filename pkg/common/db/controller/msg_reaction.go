@@ -0,0 +1,63 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type MsgReactionDatabase interface {
+	SetReaction(ctx context.Context, conversationID string, seq int64, userID string, emoji string) error
+	RemoveReaction(ctx context.Context, conversationID string, seq int64, userID string, emoji string) error
+	GetReactions(ctx context.Context, conversationID string, seq int64) ([]*relation.MsgReactionModel, error)
+}
+
+type msgReactionDatabase struct {
+	db    relation.MsgReactionModelInterface
+	cache cache.MsgReactionCache
+}
+
+func NewMsgReactionDatabase(db relation.MsgReactionModelInterface, cache cache.MsgReactionCache) MsgReactionDatabase {
+	return &msgReactionDatabase{db: db, cache: cache}
+}
+
+func (m *msgReactionDatabase) SetReaction(ctx context.Context, conversationID string, seq int64, userID string, emoji string) error {
+	reaction := &relation.MsgReactionModel{
+		ConversationID: conversationID,
+		Seq:            seq,
+		UserID:         userID,
+		Emoji:          emoji,
+		CreateTime:     time.Now(),
+	}
+	if err := m.db.Set(ctx, reaction); err != nil {
+		return err
+	}
+	return m.cache.DelMsgReactions(conversationID, seq).ExecDel(ctx)
+}
+
+func (m *msgReactionDatabase) RemoveReaction(ctx context.Context, conversationID string, seq int64, userID string, emoji string) error {
+	if err := m.db.Remove(ctx, conversationID, seq, userID, emoji); err != nil {
+		return err
+	}
+	return m.cache.DelMsgReactions(conversationID, seq).ExecDel(ctx)
+}
+
+func (m *msgReactionDatabase) GetReactions(ctx context.Context, conversationID string, seq int64) ([]*relation.MsgReactionModel, error) {
+	return m.cache.GetMsgReactions(ctx, conversationID, seq)
+}
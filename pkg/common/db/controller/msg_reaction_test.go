@@ -0,0 +1,157 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/cachekey"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+// reactionModel keeps reactions unique per conversation, seq, user and emoji like the mongo index.
+type reactionModel struct {
+	reactions []*relation.MsgReactionModel
+	finds     int
+}
+
+func (r *reactionModel) Set(_ context.Context, reaction *relation.MsgReactionModel) error {
+	for _, v := range r.reactions {
+		if v.ConversationID == reaction.ConversationID && v.Seq == reaction.Seq && v.UserID == reaction.UserID && v.Emoji == reaction.Emoji {
+			return nil
+		}
+	}
+	r.reactions = append(r.reactions, reaction)
+	return nil
+}
+
+func (r *reactionModel) Remove(_ context.Context, conversationID string, seq int64, userID string, emoji string) error {
+	for i, v := range r.reactions {
+		if v.ConversationID == conversationID && v.Seq == seq && v.UserID == userID && v.Emoji == emoji {
+			r.reactions = append(r.reactions[:i], r.reactions[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *reactionModel) Find(_ context.Context, conversationID string, seq int64) ([]*relation.MsgReactionModel, error) {
+	r.finds++
+	var res []*relation.MsgReactionModel
+	for _, v := range r.reactions {
+		if v.ConversationID == conversationID && v.Seq == seq {
+			res = append(res, v)
+		}
+	}
+	return res, nil
+}
+
+// reactionCache caches Find results by key until the key is deleted.
+type reactionCache struct {
+	cache.MsgReactionCache
+	model  *reactionModel
+	values map[string][]*relation.MsgReactionModel
+	keys   []string
+}
+
+func (r *reactionCache) GetMsgReactions(ctx context.Context, conversationID string, seq int64) ([]*relation.MsgReactionModel, error) {
+	key := cachekey.GetMsgReactionKey(conversationID, seq)
+	if v, ok := r.values[key]; ok {
+		return v, nil
+	}
+	v, err := r.model.Find(ctx, conversationID, seq)
+	if err != nil {
+		return nil, err
+	}
+	r.values[key] = v
+	return v, nil
+}
+
+func (r *reactionCache) DelMsgReactions(conversationID string, seq int64) cache.MsgReactionCache {
+	return &reactionCache{
+		MsgReactionCache: r.MsgReactionCache,
+		model:            r.model,
+		values:           r.values,
+		keys:             []string{cachekey.GetMsgReactionKey(conversationID, seq)},
+	}
+}
+
+func (r *reactionCache) ExecDel(context.Context, ...bool) error {
+	for _, key := range r.keys {
+		delete(r.values, key)
+	}
+	return nil
+}
+
+func reactionUsers(reactions []*relation.MsgReactionModel) []string {
+	var res []string
+	for _, v := range reactions {
+		res = append(res, v.UserID+":"+v.Emoji)
+	}
+	return res
+}
+
+func TestMsgReaction(t *testing.T) {
+	ctx := context.Background()
+	model := &reactionModel{}
+	db := NewMsgReactionDatabase(model, &reactionCache{model: model, values: make(map[string][]*relation.MsgReactionModel)})
+
+	check := func(seq int64, want ...string) {
+		t.Helper()
+		reactions, err := db.GetReactions(ctx, "si_a_b", seq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := reactionUsers(reactions); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("seq %d reactions = %v, want %v", seq, got, want)
+		}
+	}
+
+	check(1)
+	for _, r := range []struct {
+		seq   int64
+		user  string
+		emoji string
+	}{{1, "a", "+1"}, {1, "b", "+1"}, {1, "a", "heart"}, {1, "a", "+1"}, {2, "b", "smile"}} {
+		if err := db.SetReaction(ctx, "si_a_b", r.seq, r.user, r.emoji); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Adding a reaction drops the cached list, so the repeated reaction is only stored once.
+	check(1, "a:+1", "b:+1", "a:heart")
+	check(2, "b:smile")
+
+	finds := model.finds
+	check(1, "a:+1", "b:+1", "a:heart")
+	if model.finds != finds {
+		t.Fatalf("cached reactions were read from the db again")
+	}
+
+	if err := db.RemoveReaction(ctx, "si_a_b", 1, "a", "+1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.RemoveReaction(ctx, "si_a_b", 1, "c", "+1"); err != nil {
+		t.Fatal(err)
+	}
+	check(1, "b:+1", "a:heart")
+	// Other messages keep their cached reactions.
+	check(2, "b:smile")
+	if model.finds != finds+1 {
+		t.Fatalf("db finds = %d, want %d", model.finds, finds+1)
+	}
+}
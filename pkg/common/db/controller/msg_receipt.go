@@ -0,0 +1,166 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+)
+
+const defaultReceiptBatchSize = 500
+
+type MsgReceiptDatabase interface {
+	// CompactReceipts folds the read receipts stored in the notification conversation before the given time
+	// into per conversation and reader summaries and deletes them, it returns the number of compacted receipts.
+	CompactReceipts(ctx context.Context, notificationConversationID string, before time.Time) (int, error)
+	// GetReceiptSummaries returns the compacted read receipts of conversationID, all readers when userIDs is empty.
+	GetReceiptSummaries(ctx context.Context, conversationID string, userIDs []string) ([]*relation.MsgReceiptSummaryModel, error)
+}
+
+type msgReceiptDatabase struct {
+	summary        relation.MsgReceiptSummaryModelInterface
+	msgDocDatabase unrelationtb.MsgDocModelInterface
+	msg            unrelationtb.MsgDocModel
+	cache          cache.MsgModel
+	batchSize      int64
+}
+
+func NewMsgReceiptDatabase(summary relation.MsgReceiptSummaryModelInterface, msgDocModel unrelationtb.MsgDocModelInterface, cache cache.MsgModel, batchSize int) MsgReceiptDatabase {
+	if batchSize <= 0 {
+		batchSize = defaultReceiptBatchSize
+	}
+	return &msgReceiptDatabase{
+		summary:        summary,
+		msgDocDatabase: msgDocModel,
+		cache:          cache,
+		batchSize:      int64(batchSize),
+	}
+}
+
+func (m *msgReceiptDatabase) CompactReceipts(ctx context.Context, notificationConversationID string, before time.Time) (int, error) {
+	var total int
+	for {
+		msgs, err := m.msgDocDatabase.FindMsgsByContentTypeBefore(ctx, notificationConversationID, constant.HasReadReceipt, before.UnixMilli(), m.batchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(msgs) == 0 {
+			return total, nil
+		}
+		seqs := make([]int64, 0, len(msgs))
+		for _, msg := range msgs {
+			seqs = append(seqs, msg.Msg.Seq)
+		}
+		// The summaries are merged before the receipts are deleted, a failed delete counts the receipts
+		// again on the next run but never loses them.
+		if err := m.summary.Merge(ctx, m.summarize(ctx, msgs)); err != nil {
+			return total, err
+		}
+		if err := m.deleteMsgs(ctx, notificationConversationID, seqs); err != nil {
+			return total, err
+		}
+		total += len(msgs)
+		if int64(len(msgs)) < m.batchSize {
+			return total, nil
+		}
+	}
+}
+
+func (m *msgReceiptDatabase) summarize(ctx context.Context, msgs []*unrelationtb.MsgInfoModel) []*relation.MsgReceiptSummaryModel {
+	type key struct {
+		conversationID string
+		userID         string
+	}
+	now := time.Now()
+	summaries := make(map[key]*relation.MsgReceiptSummaryModel)
+	for _, msg := range msgs {
+		var (
+			elem sdkws.NotificationElem
+			tips sdkws.MarkAsReadTips
+		)
+		if err := json.Unmarshal([]byte(msg.Msg.Content), &elem); err != nil {
+			log.ZWarn(ctx, "unmarshal read receipt failed", err, "seq", msg.Msg.Seq)
+			continue
+		}
+		if err := json.Unmarshal([]byte(elem.Detail), &tips); err != nil {
+			log.ZWarn(ctx, "unmarshal read receipt failed", err, "seq", msg.Msg.Seq)
+			continue
+		}
+		hasReadSeq := tips.HasReadSeq
+		for _, seq := range tips.Seqs {
+			if seq > hasReadSeq {
+				hasReadSeq = seq
+			}
+		}
+		readTime := time.UnixMilli(msg.Msg.SendTime)
+		k := key{conversationID: tips.ConversationID, userID: tips.MarkAsReadUserID}
+		summary, ok := summaries[k]
+		if !ok {
+			summaries[k] = &relation.MsgReceiptSummaryModel{
+				ConversationID: tips.ConversationID,
+				UserID:         tips.MarkAsReadUserID,
+				HasReadSeq:     hasReadSeq,
+				ReceiptCount:   1,
+				FirstReadTime:  readTime,
+				LastReadTime:   readTime,
+				UpdateTime:     now,
+			}
+			continue
+		}
+		summary.ReceiptCount++
+		if hasReadSeq > summary.HasReadSeq {
+			summary.HasReadSeq = hasReadSeq
+		}
+		if readTime.Before(summary.FirstReadTime) {
+			summary.FirstReadTime = readTime
+		}
+		if readTime.After(summary.LastReadTime) {
+			summary.LastReadTime = readTime
+		}
+	}
+	res := make([]*relation.MsgReceiptSummaryModel, 0, len(summaries))
+	for _, summary := range summaries {
+		res = append(res, summary)
+	}
+	return res
+}
+
+func (m *msgReceiptDatabase) deleteMsgs(ctx context.Context, conversationID string, allSeqs []int64) error {
+	if err := m.cache.DeleteMessages(ctx, conversationID, allSeqs); err != nil {
+		return err
+	}
+	for docID, seqs := range m.msg.GetDocIDSeqsMap(conversationID, allSeqs) {
+		indexes := make([]int, 0, len(seqs))
+		for _, seq := range seqs {
+			indexes = append(indexes, int(m.msg.GetMsgIndex(seq)))
+		}
+		if err := m.msgDocDatabase.DeleteMsgsInOneDocByIndex(ctx, docID, indexes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *msgReceiptDatabase) GetReceiptSummaries(ctx context.Context, conversationID string, userIDs []string) ([]*relation.MsgReceiptSummaryModel, error) {
+	return m.summary.Find(ctx, conversationID, userIDs)
+}
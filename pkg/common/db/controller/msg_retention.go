@@ -0,0 +1,79 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type MsgRetentionDatabase interface {
+	// SetRetention overrides the retention of conversationID, retainDays 0 keeps messages forever.
+	SetRetention(ctx context.Context, conversationID string, groupID string, retainDays int32) error
+	DeleteRetention(ctx context.Context, conversationID string) error
+	// FindRetentions returns the overrides of conversationIDs keyed by conversationID.
+	FindRetentions(ctx context.Context, conversationIDs []string) (map[string]*relation.MsgRetentionModel, error)
+	PageRetentions(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.MsgRetentionModel, error)
+}
+
+type msgRetentionDatabase struct {
+	db relation.MsgRetentionModelInterface
+}
+
+func NewMsgRetentionDatabase(db relation.MsgRetentionModelInterface) MsgRetentionDatabase {
+	return &msgRetentionDatabase{db: db}
+}
+
+func (m *msgRetentionDatabase) SetRetention(ctx context.Context, conversationID string, groupID string, retainDays int32) error {
+	if conversationID == "" {
+		return errs.ErrArgs.Wrap("conversationID is empty")
+	}
+	if retainDays < 0 {
+		return errs.ErrArgs.Wrap("retainDays must not be negative")
+	}
+	return m.db.Set(ctx, &relation.MsgRetentionModel{
+		ConversationID: conversationID,
+		GroupID:        groupID,
+		RetainDays:     retainDays,
+		UpdateTime:     time.Now(),
+	})
+}
+
+func (m *msgRetentionDatabase) DeleteRetention(ctx context.Context, conversationID string) error {
+	return m.db.Delete(ctx, conversationID)
+}
+
+func (m *msgRetentionDatabase) FindRetentions(ctx context.Context, conversationIDs []string) (map[string]*relation.MsgRetentionModel, error) {
+	if len(conversationIDs) == 0 {
+		return map[string]*relation.MsgRetentionModel{}, nil
+	}
+	retentions, err := m.db.Find(ctx, conversationIDs)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]*relation.MsgRetentionModel, len(retentions))
+	for _, retention := range retentions {
+		res[retention.ConversationID] = retention
+	}
+	return res, nil
+}
+
+func (m *msgRetentionDatabase) PageRetentions(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.MsgRetentionModel, error) {
+	return m.db.Page(ctx, pagination)
+}
@@ -54,7 +54,7 @@ func Test_BatchInsertChat2DB(t *testing.T) {
 	}
 
 	db := &commonMsgDatabase{
-		msgDocDatabase: unrelation.NewMsgMongoDriver(mongo.GetDatabase(conf.Mongo.Database)),
+		msgDocDatabase: unrelation.NewMsgMongoDriver(mongo.GetDatabase(conf.Mongo.Database), nil),
 	}
 
 	//ctx := context.Background()
@@ -165,7 +165,7 @@ func GetDB() *commonMsgDatabase {
 		panic(err)
 	}
 	return &commonMsgDatabase{
-		msgDocDatabase: unrelation.NewMsgMongoDriver(mongo.GetDatabase(conf.Mongo.Database)),
+		msgDocDatabase: unrelation.NewMsgMongoDriver(mongo.GetDatabase(conf.Mongo.Database), nil),
 	}
 }
 
@@ -0,0 +1,93 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+)
+
+type MsgThreadDatabase interface {
+	// AddReplies indexes the thread replies among msgs, messages without a parentMsgID are skipped.
+	AddReplies(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) error
+	GetReplies(ctx context.Context, conversationID string, parentMsgID string, pagination pagination.Pagination) (int64, []*relation.MsgThreadModel, error)
+	// MarkThreadAsRead marks every reply currently in the thread as read by the user.
+	MarkThreadAsRead(ctx context.Context, conversationID string, parentMsgID string, userID string) error
+	// GetUnreadCounts returns the number of replies not sent by the user and not yet read, by parentMsgID.
+	GetUnreadCounts(ctx context.Context, conversationID string, parentMsgIDs []string, userID string) (map[string]int64, error)
+}
+
+type msgThreadDatabase struct {
+	db relation.MsgThreadModelInterface
+}
+
+func NewMsgThreadDatabase(db relation.MsgThreadModelInterface) MsgThreadDatabase {
+	return &msgThreadDatabase{db: db}
+}
+
+func (m *msgThreadDatabase) AddReplies(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) error {
+	var replies []*relation.MsgThreadModel
+	for _, msg := range msgs {
+		parentMsgID := msgprocessor.GetParentMsgID(msg)
+		if parentMsgID == "" {
+			continue
+		}
+		replies = append(replies, &relation.MsgThreadModel{
+			ConversationID: conversationID,
+			ParentMsgID:    parentMsgID,
+			ClientMsgID:    msg.ClientMsgID,
+			Seq:            msg.Seq,
+			SendID:         msg.SendID,
+			SendTime:       time.UnixMilli(msg.SendTime),
+		})
+	}
+	if len(replies) == 0 {
+		return nil
+	}
+	return m.db.Create(ctx, replies)
+}
+
+func (m *msgThreadDatabase) GetReplies(ctx context.Context, conversationID string, parentMsgID string, pagination pagination.Pagination) (int64, []*relation.MsgThreadModel, error) {
+	return m.db.FindReplies(ctx, conversationID, parentMsgID, pagination)
+}
+
+func (m *msgThreadDatabase) MarkThreadAsRead(ctx context.Context, conversationID string, parentMsgID string, userID string) error {
+	maxSeq, err := m.db.GetMaxSeq(ctx, conversationID, parentMsgID)
+	if err != nil {
+		return err
+	}
+	return m.db.SetHasReadSeq(ctx, conversationID, parentMsgID, userID, maxSeq)
+}
+
+func (m *msgThreadDatabase) GetUnreadCounts(ctx context.Context, conversationID string, parentMsgIDs []string, userID string) (map[string]int64, error) {
+	hasReadSeqs, err := m.db.GetHasReadSeqs(ctx, conversationID, parentMsgIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(parentMsgIDs))
+	for _, parentMsgID := range parentMsgIDs {
+		count, err := m.db.CountUnread(ctx, conversationID, parentMsgID, userID, hasReadSeqs[parentMsgID])
+		if err != nil {
+			return nil, err
+		}
+		counts[parentMsgID] = count
+	}
+	return counts, nil
+}
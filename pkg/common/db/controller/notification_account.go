@@ -0,0 +1,136 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type NotificationAccountDatabase interface {
+	// SetAccount sets the verification badge and category of a notification account.
+	SetAccount(ctx context.Context, userID string, verified bool, category int32) error
+	FindAccounts(ctx context.Context, userIDs []string) ([]*relation.NotificationAccountModel, error)
+	// SetOptOut opts the user out of (or back into) a notification category.
+	SetOptOut(ctx context.Context, userID string, category int32, optOut bool) error
+	GetOptOutCategories(ctx context.Context, userID string) ([]int32, error)
+	// FilterRecvUserIDs removes the users that opted out of the sender account's category.
+	// Senders that are not notification accounts, or whose category is system, are not filtered.
+	FilterRecvUserIDs(ctx context.Context, sendID string, userIDs []string) ([]string, error)
+	Follow(ctx context.Context, accountID string, userID string) error
+	Unfollow(ctx context.Context, accountID string, userID string) error
+	// PageFollowerIDs returns the followers of a notification account, the receivers of its broadcasts.
+	PageFollowerIDs(ctx context.Context, accountID string, pagination pagination.Pagination) (int64, []string, error)
+	// PageFollowingIDs returns the notification accounts followed by the user, most recently followed first.
+	PageFollowingIDs(ctx context.Context, userID string, pagination pagination.Pagination) (int64, []string, error)
+	// CountFollowers returns the follower count of each account.
+	CountFollowers(ctx context.Context, accountIDs []string) (map[string]int64, error)
+}
+
+type notificationAccountDatabase struct {
+	db relation.NotificationAccountModelInterface
+}
+
+func NewNotificationAccountDatabase(db relation.NotificationAccountModelInterface) NotificationAccountDatabase {
+	return &notificationAccountDatabase{db: db}
+}
+
+func (n *notificationAccountDatabase) SetAccount(ctx context.Context, userID string, verified bool, category int32) error {
+	if !relation.IsValidNotificationCategory(category) {
+		return errs.ErrArgs.Wrap("invalid notification category")
+	}
+	return n.db.Upsert(ctx, &relation.NotificationAccountModel{
+		UserID:     userID,
+		Verified:   verified,
+		Category:   category,
+		UpdateTime: time.Now(),
+	})
+}
+
+func (n *notificationAccountDatabase) FindAccounts(ctx context.Context, userIDs []string) ([]*relation.NotificationAccountModel, error) {
+	return n.db.Find(ctx, userIDs)
+}
+
+func (n *notificationAccountDatabase) SetOptOut(ctx context.Context, userID string, category int32, optOut bool) error {
+	if !relation.IsValidNotificationCategory(category) {
+		return errs.ErrArgs.Wrap("invalid notification category")
+	}
+	if category == relation.NotificationCategorySystem {
+		return errs.ErrArgs.Wrap("system notifications cannot be opted out")
+	}
+	return n.db.SetOptOut(ctx, userID, category, optOut)
+}
+
+func (n *notificationAccountDatabase) GetOptOutCategories(ctx context.Context, userID string) ([]int32, error) {
+	return n.db.FindOptOutCategories(ctx, userID)
+}
+
+func (n *notificationAccountDatabase) FilterRecvUserIDs(ctx context.Context, sendID string, userIDs []string) ([]string, error) {
+	accounts, err := n.db.Find(ctx, []string{sendID})
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 || accounts[0].Category == relation.NotificationCategorySystem {
+		return userIDs, nil
+	}
+	optOutUserIDs, err := n.db.FindOptOutUserIDs(ctx, accounts[0].Category, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(optOutUserIDs) == 0 {
+		return userIDs, nil
+	}
+	optOut := utils.SliceSet(optOutUserIDs)
+	return utils.Filter(userIDs, func(userID string) (string, bool) {
+		_, ok := optOut[userID]
+		return userID, !ok
+	}), nil
+}
+
+func (n *notificationAccountDatabase) Follow(ctx context.Context, accountID string, userID string) error {
+	if accountID == userID {
+		return errs.ErrArgs.Wrap("notification account cannot follow itself")
+	}
+	return n.db.Follow(ctx, &relation.NotificationFollowerModel{AccountID: accountID, UserID: userID, CreateTime: time.Now()})
+}
+
+func (n *notificationAccountDatabase) Unfollow(ctx context.Context, accountID string, userID string) error {
+	return n.db.Unfollow(ctx, accountID, userID)
+}
+
+func (n *notificationAccountDatabase) PageFollowerIDs(ctx context.Context, accountID string, pagination pagination.Pagination) (int64, []string, error) {
+	return n.db.PageFollowerIDs(ctx, accountID, pagination)
+}
+
+func (n *notificationAccountDatabase) PageFollowingIDs(ctx context.Context, userID string, pagination pagination.Pagination) (int64, []string, error) {
+	return n.db.PageFollowingIDs(ctx, userID, pagination)
+}
+
+func (n *notificationAccountDatabase) CountFollowers(ctx context.Context, accountIDs []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(accountIDs))
+	for _, accountID := range accountIDs {
+		count, err := n.db.CountFollowers(ctx, accountID)
+		if err != nil {
+			return nil, err
+		}
+		counts[accountID] = count
+	}
+	return counts, nil
+}
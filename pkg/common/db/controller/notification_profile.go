@@ -0,0 +1,102 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+// maxNotificationProfileKeyLen is the longest sound or vibration key, or language, accepted.
+const maxNotificationProfileKeyLen = 128
+
+type NotificationProfileDatabase interface {
+	SetProfile(ctx context.Context, profile *relation.NotificationProfileModel) error
+	DeleteProfile(ctx context.Context, ownerUserID string, conversationID string) error
+	// SyncProfiles returns the profiles and tombstones of ownerUserID updated since updateTime, the zero time
+	// returns all of them.
+	SyncProfiles(ctx context.Context, ownerUserID string, updateTime time.Time) ([]*relation.NotificationProfileModel, error)
+	// FindSounds returns the sound each of userIDs picked for conversationID, falling back to their global profile.
+	// Users without a sound are left out.
+	FindSounds(ctx context.Context, userIDs []string, conversationID string) (map[string]string, error)
+	// FindLanguages returns the language of each of userIDs for conversationID like FindSounds.
+	FindLanguages(ctx context.Context, userIDs []string, conversationID string) (map[string]string, error)
+}
+
+type notificationProfileDatabase struct {
+	db relation.NotificationProfileModelInterface
+}
+
+func NewNotificationProfileDatabase(db relation.NotificationProfileModelInterface) NotificationProfileDatabase {
+	return &notificationProfileDatabase{db: db}
+}
+
+func (n *notificationProfileDatabase) SetProfile(ctx context.Context, profile *relation.NotificationProfileModel) error {
+	if len(profile.Sound) > maxNotificationProfileKeyLen || len(profile.Vibration) > maxNotificationProfileKeyLen || len(profile.Language) > maxNotificationProfileKeyLen {
+		return errs.ErrArgs.Wrap("sound, vibration or language is too long")
+	}
+	profile.Deleted = false
+	profile.UpdateTime = time.Now()
+	return n.db.Set(ctx, profile)
+}
+
+func (n *notificationProfileDatabase) DeleteProfile(ctx context.Context, ownerUserID string, conversationID string) error {
+	ok, err := n.db.Delete(ctx, ownerUserID, conversationID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errs.ErrRecordNotFound.Wrap("notification profile not found")
+	}
+	return nil
+}
+
+func (n *notificationProfileDatabase) SyncProfiles(ctx context.Context, ownerUserID string, updateTime time.Time) ([]*relation.NotificationProfileModel, error) {
+	return n.db.FindUpdated(ctx, ownerUserID, updateTime)
+}
+
+func (n *notificationProfileDatabase) FindSounds(ctx context.Context, userIDs []string, conversationID string) (map[string]string, error) {
+	return n.find(ctx, userIDs, conversationID, func(profile *relation.NotificationProfileModel) string { return profile.Sound })
+}
+
+func (n *notificationProfileDatabase) FindLanguages(ctx context.Context, userIDs []string, conversationID string) (map[string]string, error) {
+	return n.find(ctx, userIDs, conversationID, func(profile *relation.NotificationProfileModel) string { return profile.Language })
+}
+
+// find returns the non empty field of the conversation profile of each of userIDs, or else of their global profile.
+func (n *notificationProfileDatabase) find(ctx context.Context, userIDs []string, conversationID string, field func(*relation.NotificationProfileModel) string) (map[string]string, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	profiles, err := n.db.Find(ctx, userIDs, []string{"", conversationID})
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	for _, profile := range profiles {
+		value := field(profile)
+		if value == "" {
+			continue
+		}
+		if _, ok := values[profile.OwnerUserID]; ok && profile.ConversationID == "" {
+			continue
+		}
+		values[profile.OwnerUserID] = value
+	}
+	return values, nil
+}
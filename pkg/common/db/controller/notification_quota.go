@@ -0,0 +1,124 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+)
+
+// NotificationQuota limits a notification account, 0 is no limit.
+type NotificationQuota struct {
+	// Rate is the number of messages the account sends per second.
+	Rate int
+	// DailyCap is the number of messages the account sends to each user per day (UTC).
+	DailyCap int
+}
+
+type NotificationQuotaDatabase interface {
+	// Check counts a message of accountID to userID, empty for a group message which only counts against the
+	// rate, and returns servererrs.ErrNotificationQuota when it is over the quota of the account.
+	Check(ctx context.Context, accountID string, userID string) error
+	// SetQuota overrides the quota of accountID, 0 keeps the configured limit and a negative value lifts it.
+	SetQuota(ctx context.Context, accountID string, rate int32, dailyCap int32) error
+	// GetQuota returns the quota in force for accountID.
+	GetQuota(ctx context.Context, accountID string) (NotificationQuota, error)
+	// GetDailyUsage returns the number of messages accountID sent to each of userIDs today.
+	GetDailyUsage(ctx context.Context, accountID string, userIDs []string) (map[string]int64, error)
+}
+
+type notificationQuotaDatabase struct {
+	db       relation.NotificationAccountModelInterface
+	cache    cache.NotificationQuotaCache
+	defaults NotificationQuota
+}
+
+func NewNotificationQuotaDatabase(db relation.NotificationAccountModelInterface, cache cache.NotificationQuotaCache, defaults NotificationQuota) NotificationQuotaDatabase {
+	return &notificationQuotaDatabase{db: db, cache: cache, defaults: defaults}
+}
+
+// notificationQuota applies the overrides of account, nil when it has none, to the defaults.
+func notificationQuota(defaults NotificationQuota, account *relation.NotificationAccountModel) NotificationQuota {
+	quota := defaults
+	if account == nil {
+		return quota
+	}
+	if account.Rate > 0 {
+		quota.Rate = int(account.Rate)
+	} else if account.Rate < 0 {
+		quota.Rate = 0
+	}
+	if account.DailyCap > 0 {
+		quota.DailyCap = int(account.DailyCap)
+	} else if account.DailyCap < 0 {
+		quota.DailyCap = 0
+	}
+	return quota
+}
+
+func notificationQuotaDay(now time.Time) string {
+	return now.UTC().Format("20060102")
+}
+
+func (n *notificationQuotaDatabase) GetQuota(ctx context.Context, accountID string) (NotificationQuota, error) {
+	accounts, err := n.db.Find(ctx, []string{accountID})
+	if err != nil {
+		return NotificationQuota{}, err
+	}
+	var account *relation.NotificationAccountModel
+	if len(accounts) > 0 {
+		account = accounts[0]
+	}
+	return notificationQuota(n.defaults, account), nil
+}
+
+func (n *notificationQuotaDatabase) Check(ctx context.Context, accountID string, userID string) error {
+	quota, err := n.GetQuota(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if quota.Rate > 0 {
+		count, err := n.cache.IncrRate(ctx, accountID, now.Unix())
+		if err != nil {
+			return err
+		}
+		if count > int64(quota.Rate) {
+			return servererrs.ErrNotificationQuota.Wrap("too many messages per second")
+		}
+	}
+	if quota.DailyCap > 0 && userID != "" {
+		count, err := n.cache.IncrDaily(ctx, accountID, userID, notificationQuotaDay(now))
+		if err != nil {
+			return err
+		}
+		if count > int64(quota.DailyCap) {
+			return servererrs.ErrNotificationQuota.Wrap("too many messages to the user today")
+		}
+	}
+	return nil
+}
+
+func (n *notificationQuotaDatabase) SetQuota(ctx context.Context, accountID string, rate int32, dailyCap int32) error {
+	return n.db.SetQuota(ctx, accountID, rate, dailyCap)
+}
+
+func (n *notificationQuotaDatabase) GetDailyUsage(ctx context.Context, accountID string, userIDs []string) (map[string]int64, error) {
+	return n.cache.GetDaily(ctx, accountID, userIDs, notificationQuotaDay(time.Now()))
+}
@@ -0,0 +1,43 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+func TestNotificationQuota(t *testing.T) {
+	defaults := NotificationQuota{Rate: 20, DailyCap: 3}
+	if quota := notificationQuota(defaults, nil); quota != defaults {
+		t.Fatalf("quota without an account = %+v", quota)
+	}
+	if quota := notificationQuota(defaults, &relation.NotificationAccountModel{Rate: 50}); quota.Rate != 50 || quota.DailyCap != 3 {
+		t.Fatalf("overridden rate = %+v", quota)
+	}
+	if quota := notificationQuota(defaults, &relation.NotificationAccountModel{DailyCap: -1}); quota.Rate != 20 || quota.DailyCap != 0 {
+		t.Fatalf("lifted daily cap = %+v", quota)
+	}
+}
+
+func TestNotificationQuotaDay(t *testing.T) {
+	// The day is the UTC day whatever the zone of the server.
+	now := time.Date(2024, 3, 1, 1, 0, 0, 0, time.FixedZone("UTC+8", 8*3600))
+	if day := notificationQuotaDay(now); day != "20240229" {
+		t.Fatalf("day = %s", day)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type OnboardingDatabase interface {
+	AddRule(ctx context.Context, rule *relation.OnboardingRuleModel) error
+	DeleteRules(ctx context.Context, ruleIDs []string) error
+	PageRules(ctx context.Context, appID string, pagination pagination.Pagination) (int64, []*relation.OnboardingRuleModel, error)
+	// Suggest returns the rules for a new user of appID in region, highest priority first, one per target.
+	Suggest(ctx context.Context, appID string, region string) ([]*relation.OnboardingRuleModel, error)
+	// AutoJoinGroupIDs returns the groups every new user of appID joins at registration.
+	AutoJoinGroupIDs(ctx context.Context, appID string) ([]string, error)
+}
+
+type onboardingDatabase struct {
+	db relation.OnboardingRuleModelInterface
+}
+
+func NewOnboardingDatabase(db relation.OnboardingRuleModelInterface) OnboardingDatabase {
+	return &onboardingDatabase{db: db}
+}
+
+func (o *onboardingDatabase) AddRule(ctx context.Context, rule *relation.OnboardingRuleModel) error {
+	switch rule.TargetType {
+	case relation.OnboardingTargetGroup:
+	case relation.OnboardingTargetUser, relation.OnboardingTargetOfficial:
+		if rule.AutoJoin {
+			return errs.ErrArgs.Wrap("only a group is joined automatically")
+		}
+	default:
+		return errs.ErrArgs.Wrap("unknown onboarding target type " + rule.TargetType)
+	}
+	if rule.TargetID == "" {
+		return errs.ErrArgs.Wrap("targetID is empty")
+	}
+	rule.RuleID = utils.GetMsgID(rule.TargetID)
+	rule.CreateTime = time.Now()
+	return o.db.Create(ctx, rule)
+}
+
+func (o *onboardingDatabase) DeleteRules(ctx context.Context, ruleIDs []string) error {
+	return o.db.Delete(ctx, ruleIDs)
+}
+
+func (o *onboardingDatabase) PageRules(ctx context.Context, appID string, pagination pagination.Pagination) (int64, []*relation.OnboardingRuleModel, error) {
+	return o.db.Page(ctx, appID, pagination)
+}
+
+// find returns the rules of appID and of every app.
+func (o *onboardingDatabase) find(ctx context.Context, appID string) ([]*relation.OnboardingRuleModel, error) {
+	appIDs := []string{""}
+	if appID != "" {
+		appIDs = append(appIDs, appID)
+	}
+	return o.db.Find(ctx, appIDs)
+}
+
+// matchOnboardingRules keeps the rules without a region or of region, highest priority first and the rules of an
+// app before the rules of every app, one per target.
+func matchOnboardingRules(rules []*relation.OnboardingRuleModel, region string) []*relation.OnboardingRuleModel {
+	res := make([]*relation.OnboardingRuleModel, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Region == "" || strings.EqualFold(rule.Region, region) {
+			res = append(res, rule)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Priority != res[j].Priority {
+			return res[i].Priority > res[j].Priority
+		}
+		return res[i].AppID > res[j].AppID
+	})
+	seen := make(map[string]struct{}, len(res))
+	return utils.Filter(res, func(rule *relation.OnboardingRuleModel) (*relation.OnboardingRuleModel, bool) {
+		key := rule.TargetType + ":" + rule.TargetID
+		if _, ok := seen[key]; ok {
+			return nil, false
+		}
+		seen[key] = struct{}{}
+		return rule, true
+	})
+}
+
+func (o *onboardingDatabase) Suggest(ctx context.Context, appID string, region string) ([]*relation.OnboardingRuleModel, error) {
+	rules, err := o.find(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+	return matchOnboardingRules(rules, region), nil
+}
+
+func (o *onboardingDatabase) AutoJoinGroupIDs(ctx context.Context, appID string) ([]string, error) {
+	rules, err := o.find(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+	var groupIDs []string
+	for _, rule := range matchOnboardingRules(rules, "") {
+		if rule.AutoJoin && rule.Region == "" && rule.TargetType == relation.OnboardingTargetGroup {
+			groupIDs = append(groupIDs, rule.TargetID)
+		}
+	}
+	return groupIDs, nil
+}
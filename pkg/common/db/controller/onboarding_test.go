@@ -0,0 +1,44 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+func TestMatchOnboardingRules(t *testing.T) {
+	rules := []*relation.OnboardingRuleModel{
+		{RuleID: "global", TargetType: relation.OnboardingTargetGroup, TargetID: "g1"},
+		{RuleID: "eu", Region: "EU", TargetType: relation.OnboardingTargetGroup, TargetID: "g2", Priority: 5},
+		{RuleID: "us", Region: "US", TargetType: relation.OnboardingTargetGroup, TargetID: "g3"},
+		{RuleID: "app", AppID: "a1", TargetType: relation.OnboardingTargetGroup, TargetID: "g1"},
+		{RuleID: "official", TargetType: relation.OnboardingTargetOfficial, TargetID: "g1", Priority: 1},
+	}
+	var ids []string
+	for _, rule := range matchOnboardingRules(rules, "eu") {
+		ids = append(ids, rule.RuleID)
+	}
+	want := []string{"eu", "official", "app"}
+	if len(ids) != len(want) {
+		t.Fatalf("rules = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("rules = %v, want %v", ids, want)
+		}
+	}
+}
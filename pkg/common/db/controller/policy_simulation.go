@@ -0,0 +1,71 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+)
+
+type PolicySimulationDatabase interface {
+	// SampleMsgs returns the number of messages sent at or after since (ms) and up to size of them picked at random.
+	SampleMsgs(ctx context.Context, since int64, size int64) (int64, []*unrelationtb.SampledMsg, error)
+	// CountMsgsBefore returns the number of stored messages of conversationID sent before sendTime (ms).
+	CountMsgsBefore(ctx context.Context, conversationID string, sendTime int64) (int64, error)
+}
+
+type policySimulationDatabase struct {
+	msgDocDB unrelationtb.MsgDocModelInterface
+}
+
+func NewPolicySimulationDatabase(msgDocDB unrelationtb.MsgDocModelInterface) PolicySimulationDatabase {
+	return &policySimulationDatabase{msgDocDB: msgDocDB}
+}
+
+func (p *policySimulationDatabase) SampleMsgs(ctx context.Context, since int64, size int64) (int64, []*unrelationtb.SampledMsg, error) {
+	if size <= 0 {
+		return 0, nil, errs.ErrArgs.Wrap("sample size must be positive")
+	}
+	return p.msgDocDB.SampleMsgsSince(ctx, since, size)
+}
+
+func (p *policySimulationDatabase) CountMsgsBefore(ctx context.Context, conversationID string, sendTime int64) (int64, error) {
+	oldest, err := p.msgDocDB.GetOldestMsg(ctx, conversationID)
+	if err != nil {
+		if errs.Unwrap(err) == unrelation.ErrMsgListNotExist {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if oldest.Msg.SendTime >= sendTime {
+		return 0, nil
+	}
+	seq, err := p.msgDocDB.GetFirstSeqSince(ctx, conversationID, sendTime)
+	if err == nil {
+		return seq - oldest.Msg.Seq, nil
+	}
+	if errs.Unwrap(err) != unrelation.ErrMsgListNotExist {
+		return 0, err
+	}
+	// Nothing was sent since then, every stored message is older.
+	newest, err := p.msgDocDB.GetNewestMsg(ctx, conversationID)
+	if err != nil {
+		return 0, err
+	}
+	return newest.Msg.Seq - oldest.Msg.Seq + 1, nil
+}
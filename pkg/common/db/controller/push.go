@@ -22,6 +22,8 @@ import (
 
 type PushDatabase interface {
 	DelFcmToken(ctx context.Context, userID string, platformID int) error
+	// AddDeliveryLatency samples the milliseconds between sending a message and its online delivery.
+	AddDeliveryLatency(ctx context.Context, latency int64) error
 }
 
 type pushDataBase struct {
@@ -35,3 +37,7 @@ func NewPushDatabase(cache cache.MsgModel) PushDatabase {
 func (p *pushDataBase) DelFcmToken(ctx context.Context, userID string, platformID int) error {
 	return p.cache.DelFcmToken(ctx, userID, platformID)
 }
+
+func (p *pushDataBase) AddDeliveryLatency(ctx context.Context, latency int64) error {
+	return p.cache.AddDeliveryLatency(ctx, latency)
+}
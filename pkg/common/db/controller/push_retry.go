@@ -0,0 +1,154 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/offlinepush"
+)
+
+// PushRetryEntry is a failed offline push waiting for another attempt or dead-lettered.
+type PushRetryEntry struct {
+	ID             string            `json:"id"`
+	ConversationID string            `json:"conversationID"`
+	AppID          string            `json:"appID,omitempty"`
+	UserIDs        []string          `json:"userIDs"`
+	Title          string            `json:"title"`
+	Content        string            `json:"content"`
+	Opts           *offlinepush.Opts `json:"opts"`
+	Attempts       int               `json:"attempts"`
+	LastErr        string            `json:"lastErr"`
+	CreateTime     int64             `json:"createTime"`
+	UpdateTime     int64             `json:"updateTime"`
+}
+
+type PushRetryDatabase interface {
+	// AddRetry schedules the next attempt of entry at due.
+	AddRetry(ctx context.Context, entry *PushRetryEntry, due time.Time) error
+	// ClaimDue returns up to count entries due for another attempt, an entry is claimed by one caller only.
+	ClaimDue(ctx context.Context, count int64) ([]*PushRetryEntry, error)
+	// Finish drops a claimed entry once it has been pushed.
+	Finish(ctx context.Context, id string) error
+	DeadLetter(ctx context.Context, entry *PushRetryEntry) error
+	PageDeadLetters(ctx context.Context, pagination pagination.Pagination) (int64, []*PushRetryEntry, error)
+	// Requeue moves dead letters back to the retry queue with their attempts reset and returns the requeued ids.
+	Requeue(ctx context.Context, ids []string) ([]string, error)
+}
+
+type pushRetryDatabase struct {
+	cache cache.PushRetryCache
+}
+
+func NewPushRetryDatabase(cache cache.PushRetryCache) PushRetryDatabase {
+	return &pushRetryDatabase{cache: cache}
+}
+
+func (p *pushRetryDatabase) AddRetry(ctx context.Context, entry *PushRetryEntry, due time.Time) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return p.cache.AddRetry(ctx, entry.ID, string(data), due)
+}
+
+func (p *pushRetryDatabase) ClaimDue(ctx context.Context, count int64) ([]*PushRetryEntry, error) {
+	ids, err := p.cache.ClaimDue(ctx, time.Now(), count)
+	if err != nil {
+		return nil, err
+	}
+	return p.getEntries(ctx, ids)
+}
+
+func (p *pushRetryDatabase) Finish(ctx context.Context, id string) error {
+	return p.cache.DelEntry(ctx, id)
+}
+
+func (p *pushRetryDatabase) DeadLetter(ctx context.Context, entry *PushRetryEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return p.cache.AddDeadLetter(ctx, entry.ID, string(data), time.Now())
+}
+
+func (p *pushRetryDatabase) PageDeadLetters(ctx context.Context, pagination pagination.Pagination) (int64, []*PushRetryEntry, error) {
+	showNumber := int64(pagination.GetShowNumber())
+	offset := int64(pagination.GetPageNumber()-1) * showNumber
+	if offset < 0 {
+		offset = 0
+	}
+	total, ids, err := p.cache.PageDeadLetters(ctx, offset, showNumber)
+	if err != nil {
+		return 0, nil, err
+	}
+	entries, err := p.getEntries(ctx, ids)
+	if err != nil {
+		return 0, nil, err
+	}
+	return total, entries, nil
+}
+
+func (p *pushRetryDatabase) Requeue(ctx context.Context, ids []string) ([]string, error) {
+	entries, err := p.getEntries(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	requeued := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ok, err := p.cache.RemoveDeadLetter(ctx, entry.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		entry.Attempts = 0
+		entry.UpdateTime = time.Now().UnixMilli()
+		if err := p.AddRetry(ctx, entry, time.Now()); err != nil {
+			return nil, err
+		}
+		requeued = append(requeued, entry.ID)
+	}
+	return requeued, nil
+}
+
+// getEntries returns the entries of ids in order, skipping the missing or corrupted ones.
+func (p *pushRetryDatabase) getEntries(ctx context.Context, ids []string) ([]*PushRetryEntry, error) {
+	vals, err := p.cache.GetEntries(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*PushRetryEntry, 0, len(ids))
+	for _, id := range ids {
+		val, ok := vals[id]
+		if !ok {
+			continue
+		}
+		var entry PushRetryEntry
+		if err := json.Unmarshal([]byte(val), &entry); err != nil {
+			log.ZWarn(ctx, "push retry entry unmarshal failed", err, "id", id)
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
@@ -16,14 +16,24 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
 	"time"
 
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/azblob"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/cont"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/cos"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/gcs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/local"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/minio"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/oss"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
 	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type S3Database interface {
@@ -36,6 +46,32 @@ type S3Database interface {
 	SetObject(ctx context.Context, info *relation.ObjectModel) error
 	StatObject(ctx context.Context, name string) (*s3.ObjectInfo, error)
 	FormData(ctx context.Context, name string, size int64, contentType string, duration time.Duration) (*s3.FormData, error)
+	// DeleteUserObjects deletes the objects uploaded by userID and returns how many were deleted.
+	// Stored content is kept while objects of other users still refer to it.
+	DeleteUserObjects(ctx context.Context, userID string) (int64, error)
+	// DeleteObjects deletes the objects named names, it returns how many were deleted and the size of the stored
+	// content that was deleted with them. Unknown names are skipped.
+	DeleteObjects(ctx context.Context, names []string) (deleted int64, reclaimed int64, err error)
+}
+
+// NewS3 returns the object storage engine selected by object.enable.
+func NewS3(config *config.GlobalConfig, rdb redis.UniversalClient) (s3.Interface, error) {
+	switch config.Object.Enable {
+	case "minio":
+		return minio.NewMinio(cache.NewMinioCache(rdb), minio.Config(config.Object.Minio))
+	case "cos":
+		return cos.NewCos(cos.Config(config.Object.Cos))
+	case "oss":
+		return oss.NewOSS(oss.Config(config.Object.Oss))
+	case "azure":
+		return azblob.NewAzblob(azblob.Config(config.Object.Azure))
+	case "gcs":
+		return gcs.NewGcs(gcs.Config(config.Object.Gcs))
+	case "local":
+		return local.NewLocal(local.Config(config.Object.Local), config.Object.ApiURL, config.Secret)
+	default:
+		return nil, fmt.Errorf("invalid object enable: %s", config.Object.Enable)
+	}
 }
 
 func NewS3Database(rdb redis.UniversalClient, s3 s3.Interface, obj relation.ObjectInfoModelInterface) S3Database {
@@ -109,3 +145,63 @@ func (s *s3Database) StatObject(ctx context.Context, name string) (*s3.ObjectInf
 func (s *s3Database) FormData(ctx context.Context, name string, size int64, contentType string, duration time.Duration) (*s3.FormData, error) {
 	return s.s3.FormData(ctx, name, size, contentType, duration)
 }
+
+func (s *s3Database) DeleteUserObjects(ctx context.Context, userID string) (int64, error) {
+	objs, err := s.db.FindByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	for _, obj := range objs {
+		if _, err := s.deleteObject(ctx, obj); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (s *s3Database) DeleteObjects(ctx context.Context, names []string) (int64, int64, error) {
+	var deleted, reclaimed int64
+	for _, name := range names {
+		obj, err := s.db.Take(ctx, s.s3.Engine(), name)
+		if err != nil {
+			if errs.Unwrap(err) == mongo.ErrNoDocuments {
+				continue
+			}
+			return deleted, reclaimed, err
+		}
+		size, err := s.deleteObject(ctx, obj)
+		if err != nil {
+			return deleted, reclaimed, err
+		}
+		deleted++
+		reclaimed += size
+	}
+	return deleted, reclaimed, nil
+}
+
+// deleteObject deletes obj and its stored content unless other objects refer to it, it returns the size of the
+// deleted content.
+func (s *s3Database) deleteObject(ctx context.Context, obj *relation.ObjectModel) (int64, error) {
+	if err := s.db.Delete(ctx, obj.Engine, obj.Name); err != nil {
+		return 0, err
+	}
+	if err := s.cache.DelObjectName(obj.Engine, obj.Name).ExecDel(ctx); err != nil {
+		return 0, err
+	}
+	if obj.Engine != s.s3.Engine() {
+		return 0, nil
+	}
+	shared, err := s.db.FindByKey(ctx, obj.Engine, obj.Key)
+	if err != nil {
+		return 0, err
+	}
+	if len(shared) > 0 {
+		return 0, nil
+	}
+	if err := s.s3.DeleteObject(ctx, obj.Key); err != nil {
+		return 0, err
+	}
+	return obj.Size, nil
+}
@@ -0,0 +1,113 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"google.golang.org/protobuf/proto"
+)
+
+type ScheduledMsgDatabase interface {
+	// Schedule stores msg to be sent at scheduleTime and returns its scheduleID.
+	Schedule(ctx context.Context, msg *sdkws.MsgData, scheduleTime time.Time) (string, error)
+	TakeScheduledMsg(ctx context.Context, scheduleID string) (*relation.ScheduledMsgModel, error)
+	// Cancel cancels a pending message, it fails once the message is being sent.
+	Cancel(ctx context.Context, scheduleID string) error
+	GetScheduledMsgs(ctx context.Context, sendID string, status []int32, pagination pagination.Pagination) (int64, []*relation.ScheduledMsgModel, error)
+	// ClaimDue marks up to limit due messages as sending and returns them, a message is claimed by one caller only.
+	ClaimDue(ctx context.Context, limit int64) ([]*relation.ScheduledMsgModel, error)
+	// Finish records the result of sending a claimed message.
+	Finish(ctx context.Context, scheduleID string, sendErr error) error
+}
+
+type scheduledMsgDatabase struct {
+	db relation.ScheduledMsgModelInterface
+}
+
+func NewScheduledMsgDatabase(db relation.ScheduledMsgModelInterface) ScheduledMsgDatabase {
+	return &scheduledMsgDatabase{db: db}
+}
+
+func (s *scheduledMsgDatabase) Schedule(ctx context.Context, msg *sdkws.MsgData, scheduleTime time.Time) (string, error) {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+	scheduled := &relation.ScheduledMsgModel{
+		ScheduleID:   utils.GetMsgID(msg.SendID),
+		SendID:       msg.SendID,
+		MsgData:      data,
+		ScheduleTime: scheduleTime,
+		Status:       relation.ScheduledMsgPending,
+		CreateTime:   time.Now(),
+	}
+	if err := s.db.Create(ctx, []*relation.ScheduledMsgModel{scheduled}); err != nil {
+		return "", err
+	}
+	return scheduled.ScheduleID, nil
+}
+
+func (s *scheduledMsgDatabase) TakeScheduledMsg(ctx context.Context, scheduleID string) (*relation.ScheduledMsgModel, error) {
+	return s.db.Take(ctx, scheduleID)
+}
+
+func (s *scheduledMsgDatabase) Cancel(ctx context.Context, scheduleID string) error {
+	ok, err := s.db.UpdateStatus(ctx, scheduleID, relation.ScheduledMsgPending, relation.ScheduledMsgCanceled, "")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errs.ErrArgs.Wrap("scheduled msg is no longer pending")
+	}
+	return nil
+}
+
+func (s *scheduledMsgDatabase) GetScheduledMsgs(ctx context.Context, sendID string, status []int32, pagination pagination.Pagination) (int64, []*relation.ScheduledMsgModel, error) {
+	return s.db.FindBySendID(ctx, sendID, status, pagination)
+}
+
+func (s *scheduledMsgDatabase) ClaimDue(ctx context.Context, limit int64) ([]*relation.ScheduledMsgModel, error) {
+	due, err := s.db.FindDue(ctx, time.Now(), limit)
+	if err != nil {
+		return nil, err
+	}
+	claimed := make([]*relation.ScheduledMsgModel, 0, len(due))
+	for _, msg := range due {
+		ok, err := s.db.UpdateStatus(ctx, msg.ScheduleID, relation.ScheduledMsgPending, relation.ScheduledMsgSending, "")
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			claimed = append(claimed, msg)
+		}
+	}
+	return claimed, nil
+}
+
+func (s *scheduledMsgDatabase) Finish(ctx context.Context, scheduleID string, sendErr error) error {
+	status, errMsg := int32(relation.ScheduledMsgSent), ""
+	if sendErr != nil {
+		status, errMsg = relation.ScheduledMsgFailed, sendErr.Error()
+	}
+	_, err := s.db.UpdateStatus(ctx, scheduleID, relation.ScheduledMsgSending, status, errMsg)
+	return err
+}
@@ -0,0 +1,280 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	unrelationtb "github.com/openimsdk/open-im-server/v3/pkg/common/db/table/unrelation"
+)
+
+const (
+	SeqGapRepairPlaceholder = "placeholder"
+	SeqGapRepairCompact     = "compact"
+)
+
+// SeqGap is a range [Begin, End] of seqs that are neither stored in mongo nor in the message cache, clients pulling
+// such a range get nothing back and keep pulling it.
+type SeqGap struct {
+	Begin int64
+	End   int64
+}
+
+type SeqGapReport struct {
+	MinSeq int64
+	MaxSeq int64
+	// Missing is the number of seqs in Gaps.
+	Missing int64
+	Gaps    []*SeqGap
+}
+
+type SeqGapDatabase interface {
+	// FindGaps scans the seqs between the min and max seq of the conversation.
+	FindGaps(ctx context.Context, conversationID string) (*SeqGapReport, error)
+	// FillGaps stores a deleted placeholder message at every missing seq of gaps, it returns the number stored.
+	// Seqs holding a message by then are left alone.
+	FillGaps(ctx context.Context, conversationID string, gaps []*SeqGap) (int64, error)
+	// Compact raises the min seq of the conversation past the gap at the start of report and returns the new min seq.
+	// Gaps between stored messages cannot be compacted without renumbering them and are left to FillGaps.
+	Compact(ctx context.Context, conversationID string, report *SeqGapReport) (int64, error)
+}
+
+type seqGapDatabase struct {
+	msgDocDB unrelationtb.MsgDocModelInterface
+	cache    cache.MsgModel
+	msg      unrelationtb.MsgDocModel
+}
+
+func NewSeqGapDatabase(msgDocDB unrelationtb.MsgDocModelInterface, cache cache.MsgModel) SeqGapDatabase {
+	return &seqGapDatabase{msgDocDB: msgDocDB, cache: cache}
+}
+
+func (s *seqGapDatabase) FindGaps(ctx context.Context, conversationID string) (*SeqGapReport, error) {
+	minSeq, err := s.cache.GetMinSeq(ctx, conversationID)
+	if err != nil && errs.Unwrap(err) != redis.Nil {
+		return nil, err
+	}
+	maxSeq, err := s.cache.GetMaxSeq(ctx, conversationID)
+	if err != nil && errs.Unwrap(err) != redis.Nil {
+		return nil, err
+	}
+	if minSeq < 1 {
+		minSeq = 1
+	}
+	report := &SeqGapReport{MinSeq: minSeq, MaxSeq: maxSeq}
+	num := s.msg.GetSingleGocMsgNum()
+	var missing []int64
+	for begin := minSeq; begin <= maxSeq; {
+		end := (begin-1)/num*num + num
+		if end > maxSeq {
+			end = maxSeq
+		}
+		doc, err := s.msgDocDB.FindOneByDocID(ctx, s.msg.GetDocID(conversationID, begin))
+		if err != nil && errs.Unwrap(err) != mongo.ErrNoDocuments {
+			return nil, errs.Wrap(err)
+		}
+		var docMissing []int64
+		for seq := begin; seq <= end; seq++ {
+			index := s.msg.GetMsgIndex(seq)
+			if index < int64(len(doc.Msg)) && doc.Msg[index] != nil && doc.Msg[index].Msg != nil {
+				continue
+			}
+			docMissing = append(docMissing, seq)
+		}
+		if len(docMissing) > 0 {
+			// The latest messages may not have been written to mongo yet.
+			cached, _, err := s.cache.GetMessagesBySeq(ctx, conversationID, docMissing)
+			if err != nil && errs.Unwrap(err) != redis.Nil {
+				return nil, err
+			}
+			docMissing = withoutCachedSeqs(docMissing, cached)
+			missing = append(missing, docMissing...)
+		}
+		begin = end + 1
+	}
+	report.Missing = int64(len(missing))
+	report.Gaps = seqGaps(missing)
+	return report, nil
+}
+
+func (s *seqGapDatabase) FillGaps(ctx context.Context, conversationID string, gaps []*SeqGap) (int64, error) {
+	var filled int64
+	for _, gap := range gaps {
+		if gap.Begin < 1 || gap.End < gap.Begin {
+			return filled, errs.ErrArgs.Wrap(fmt.Sprintf("invalid seq gap %d-%d", gap.Begin, gap.End))
+		}
+		neighbour, err := s.neighbourMsg(ctx, conversationID, gap)
+		if err != nil {
+			return filled, err
+		}
+		seqs := make([]int64, 0, gap.End-gap.Begin+1)
+		for seq := gap.Begin; seq <= gap.End; seq++ {
+			seqs = append(seqs, seq)
+		}
+		for docID, docSeqs := range s.msg.GetDocIDSeqsMap(conversationID, seqs) {
+			n, err := s.fillDoc(ctx, conversationID, docID, docSeqs, neighbour)
+			filled += n
+			if err != nil {
+				return filled, err
+			}
+		}
+		log.ZInfo(ctx, "seq gap filled", "conversationID", conversationID, "begin", gap.Begin, "end", gap.End)
+	}
+	return filled, nil
+}
+
+// fillDoc creates the doc with the placeholders if it does not exist yet, otherwise it sets the empty slots.
+func (s *seqGapDatabase) fillDoc(ctx context.Context, conversationID string, docID string, seqs []int64, neighbour *unrelationtb.MsgDataModel) (int64, error) {
+	exist, err := s.msgDocDB.IsExistDocID(ctx, docID)
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		doc := &unrelationtb.MsgDocModel{DocID: docID, Msg: make([]*unrelationtb.MsgInfoModel, s.msg.GetSingleGocMsgNum())}
+		for _, seq := range seqs {
+			doc.Msg[s.msg.GetMsgIndex(seq)] = &unrelationtb.MsgInfoModel{Msg: seqGapPlaceholder(conversationID, seq, neighbour)}
+		}
+		for i, model := range doc.Msg {
+			if model == nil {
+				model = &unrelationtb.MsgInfoModel{}
+				doc.Msg[i] = model
+			}
+			if model.DelList == nil {
+				model.DelList = []string{}
+			}
+		}
+		err := s.msgDocDB.Create(ctx, doc)
+		if err == nil {
+			return int64(len(seqs)), nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return 0, errs.Wrap(err)
+		}
+		// The doc was created meanwhile, only fill what is still empty.
+	}
+	var filled int64
+	for _, seq := range seqs {
+		ok, err := s.msgDocDB.SetEmptyMsg(ctx, docID, s.msg.GetMsgIndex(seq), &unrelationtb.MsgInfoModel{
+			Msg:     seqGapPlaceholder(conversationID, seq, neighbour),
+			DelList: []string{},
+		})
+		if err != nil {
+			return filled, err
+		}
+		if ok {
+			filled++
+		}
+	}
+	return filled, nil
+}
+
+// neighbourMsg returns the message right before the gap, or right after it when there is none before.
+func (s *seqGapDatabase) neighbourMsg(ctx context.Context, conversationID string, gap *SeqGap) (*unrelationtb.MsgDataModel, error) {
+	for _, seq := range []int64{gap.Begin - 1, gap.End + 1} {
+		if seq < 1 {
+			continue
+		}
+		msgs, err := s.msgDocDB.GetMsgBySeqIndexIn1Doc(ctx, "", s.msg.GetDocID(conversationID, seq), []int64{seq})
+		if err != nil && errs.Unwrap(err) != mongo.ErrNoDocuments {
+			return nil, err
+		}
+		for _, msg := range msgs {
+			if msg != nil && msg.Msg != nil && msg.Msg.Seq == seq {
+				return msg.Msg, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (s *seqGapDatabase) Compact(ctx context.Context, conversationID string, report *SeqGapReport) (int64, error) {
+	if len(report.Gaps) == 0 || report.Gaps[0].Begin > report.MinSeq {
+		return report.MinSeq, nil
+	}
+	minSeq := report.Gaps[0].End + 1
+	if err := s.cache.SetMinSeq(ctx, conversationID, minSeq); err != nil {
+		return 0, err
+	}
+	log.ZInfo(ctx, "seq gap compacted", "conversationID", conversationID, "oldMinSeq", report.MinSeq, "minSeq", minSeq)
+	return minSeq, nil
+}
+
+// seqGapPlaceholder returns a deleted message at seq, it takes the session and send time of neighbour so that
+// clients sort and file it along with the messages around it.
+func seqGapPlaceholder(conversationID string, seq int64, neighbour *unrelationtb.MsgDataModel) *unrelationtb.MsgDataModel {
+	msg := &unrelationtb.MsgDataModel{
+		ClientMsgID: fmt.Sprintf("seqgap_%s_%d", conversationID, seq),
+		ServerMsgID: fmt.Sprintf("seqgap_%s_%d", conversationID, seq),
+		Seq:         seq,
+		Status:      constant.MsgDeleted,
+	}
+	if neighbour != nil {
+		msg.SendID = neighbour.SendID
+		msg.RecvID = neighbour.RecvID
+		msg.GroupID = neighbour.GroupID
+		msg.SessionType = neighbour.SessionType
+		msg.SendTime = neighbour.SendTime
+		msg.CreateTime = neighbour.CreateTime
+	}
+	return msg
+}
+
+// withoutCachedSeqs returns the seqs none of cached has.
+func withoutCachedSeqs(seqs []int64, cached []*sdkws.MsgData) []int64 {
+	if len(cached) == 0 {
+		return seqs
+	}
+	found := make(map[int64]struct{}, len(cached))
+	for _, msg := range cached {
+		found[msg.Seq] = struct{}{}
+	}
+	res := make([]int64, 0, len(seqs))
+	for _, seq := range seqs {
+		if _, ok := found[seq]; !ok {
+			res = append(res, seq)
+		}
+	}
+	return res
+}
+
+// seqGaps merges seqs into ranges of consecutive seqs.
+func seqGaps(seqs []int64) []*SeqGap {
+	if len(seqs) == 0 {
+		return nil
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	gaps := []*SeqGap{{Begin: seqs[0], End: seqs[0]}}
+	for _, seq := range seqs[1:] {
+		last := gaps[len(gaps)-1]
+		if seq <= last.End+1 {
+			if seq > last.End {
+				last.End = seq
+			}
+			continue
+		}
+		gaps = append(gaps, &SeqGap{Begin: seq, End: seq})
+	}
+	return gaps
+}
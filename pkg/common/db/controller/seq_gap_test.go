@@ -0,0 +1,40 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+)
+
+func TestSeqGaps(t *testing.T) {
+	gaps := seqGaps([]int64{9, 3, 4, 5, 12, 10, 4})
+	want := []*SeqGap{{Begin: 3, End: 5}, {Begin: 9, End: 10}, {Begin: 12, End: 12}}
+	if !reflect.DeepEqual(gaps, want) {
+		t.Fatalf("gaps = %v, want %v", gaps, want)
+	}
+	if gaps := seqGaps(nil); gaps != nil {
+		t.Fatalf("gaps of no seqs = %v", gaps)
+	}
+}
+
+func TestWithoutCachedSeqs(t *testing.T) {
+	seqs := withoutCachedSeqs([]int64{1, 2, 3, 4}, []*sdkws.MsgData{{Seq: 2}, {Seq: 4}})
+	if !reflect.DeepEqual(seqs, []int64{1, 3}) {
+		t.Fatalf("seqs = %v", seqs)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type TenantConfigDatabase interface {
+	// SetTenantConfig replaces the overrides of appID, the caller validates them with tenant.Merge first.
+	SetTenantConfig(ctx context.Context, appID string, overrides string) error
+	DeleteTenantConfig(ctx context.Context, appID string) error
+	FindTenantConfigs(ctx context.Context, appIDs []string) ([]*relation.TenantConfigModel, error)
+	PageTenantConfigs(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.TenantConfigModel, error)
+}
+
+type tenantConfigDatabase struct {
+	db relation.TenantConfigModelInterface
+}
+
+func NewTenantConfigDatabase(db relation.TenantConfigModelInterface) TenantConfigDatabase {
+	return &tenantConfigDatabase{db: db}
+}
+
+func (t *tenantConfigDatabase) SetTenantConfig(ctx context.Context, appID string, overrides string) error {
+	if appID == "" {
+		return errs.ErrArgs.Wrap("appID is empty")
+	}
+	return t.db.Set(ctx, &relation.TenantConfigModel{
+		AppID:      appID,
+		Overrides:  overrides,
+		UpdateTime: time.Now(),
+	})
+}
+
+func (t *tenantConfigDatabase) DeleteTenantConfig(ctx context.Context, appID string) error {
+	return t.db.Delete(ctx, appID)
+}
+
+func (t *tenantConfigDatabase) FindTenantConfigs(ctx context.Context, appIDs []string) ([]*relation.TenantConfigModel, error) {
+	if len(appIDs) == 0 {
+		return nil, nil
+	}
+	return t.db.Find(ctx, appIDs)
+}
+
+func (t *tenantConfigDatabase) PageTenantConfigs(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.TenantConfigModel, error) {
+	return t.db.Page(ctx, pagination)
+}
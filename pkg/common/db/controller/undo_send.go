@@ -0,0 +1,121 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"google.golang.org/protobuf/proto"
+)
+
+// heldMsgMaxDelay is how long after its window a held message that keeps failing to be released is dropped.
+const heldMsgMaxDelay = time.Hour
+
+// HeldMsg is a message waiting for the end of its undo send window, ID acks it once it is released.
+type HeldMsg struct {
+	ID    string
+	AppID string
+	Msg   *sdkws.MsgData
+}
+
+type heldMsgEntry struct {
+	AppID string `json:"appID,omitempty"`
+	Due   int64  `json:"due"`
+	Msg   []byte `json:"msg"`
+}
+
+type UndoSendDatabase interface {
+	// Hold keeps msg until due.
+	Hold(ctx context.Context, appID string, msg *sdkws.MsgData, due time.Time) error
+	// ClaimDue leases up to count messages whose window is over and that were not cancelled. A message is claimed
+	// by one caller at a time and is claimed again when it is not released before its lease ends.
+	ClaimDue(ctx context.Context, count int64, lease time.Duration) ([]*HeldMsg, error)
+	// Released drops a claimed message once it was sent.
+	Released(ctx context.Context, id string) error
+	// Cancel drops a held message, false when its release already started or it was never held.
+	Cancel(ctx context.Context, sendID string, clientMsgID string) (bool, error)
+}
+
+type undoSendDatabase struct {
+	cache cache.UndoSendCache
+}
+
+func NewUndoSendDatabase(cache cache.UndoSendCache) UndoSendDatabase {
+	return &undoSendDatabase{cache: cache}
+}
+
+func heldMsgID(sendID string, clientMsgID string) string {
+	return sendID + ":" + clientMsgID
+}
+
+func (u *undoSendDatabase) Hold(ctx context.Context, appID string, msg *sdkws.MsgData, due time.Time) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	entry, err := json.Marshal(&heldMsgEntry{AppID: appID, Due: due.UnixMilli(), Msg: data})
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return u.cache.Hold(ctx, heldMsgID(msg.SendID, msg.ClientMsgID), string(entry), due)
+}
+
+func (u *undoSendDatabase) ClaimDue(ctx context.Context, count int64, lease time.Duration) ([]*HeldMsg, error) {
+	now := time.Now()
+	ids, entries, err := u.cache.ClaimDue(ctx, now, lease, count)
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]*HeldMsg, 0, len(ids))
+	for i, id := range ids {
+		var held heldMsgEntry
+		if err := json.Unmarshal([]byte(entries[i]), &held); err != nil {
+			u.drop(ctx, id, "invalid held msg dropped", err)
+			continue
+		}
+		msg := &sdkws.MsgData{}
+		if err := proto.Unmarshal(held.Msg, msg); err != nil {
+			u.drop(ctx, id, "invalid held msg dropped", err)
+			continue
+		}
+		if now.Sub(time.UnixMilli(held.Due)) > heldMsgMaxDelay {
+			u.drop(ctx, id, "held msg not released in time dropped", nil)
+			continue
+		}
+		msgs = append(msgs, &HeldMsg{ID: id, AppID: held.AppID, Msg: msg})
+	}
+	return msgs, nil
+}
+
+func (u *undoSendDatabase) drop(ctx context.Context, id string, msg string, err error) {
+	log.ZError(ctx, msg, err, "id", id)
+	if err := u.cache.Ack(ctx, id); err != nil {
+		log.ZError(ctx, "drop held msg failed", err, "id", id)
+	}
+}
+
+func (u *undoSendDatabase) Released(ctx context.Context, id string) error {
+	return u.cache.Ack(ctx, id)
+}
+
+func (u *undoSendDatabase) Cancel(ctx context.Context, sendID string, clientMsgID string) (bool, error) {
+	return u.cache.Cancel(ctx, heldMsgID(sendID, clientMsgID))
+}
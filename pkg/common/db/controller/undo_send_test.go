@@ -0,0 +1,141 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+)
+
+// undoSendMemory keeps the queue and the leases of held entries by id.
+type undoSendMemory struct {
+	entries map[string]string
+	queue   map[string]time.Time
+	leases  map[string]time.Time
+}
+
+func newUndoSendMemory() *undoSendMemory {
+	return &undoSendMemory{entries: make(map[string]string), queue: make(map[string]time.Time), leases: make(map[string]time.Time)}
+}
+
+func (u *undoSendMemory) Hold(_ context.Context, id string, entry string, due time.Time) error {
+	u.entries[id] = entry
+	u.queue[id] = due
+	return nil
+}
+
+func (u *undoSendMemory) ClaimDue(_ context.Context, now time.Time, lease time.Duration, _ int64) ([]string, []string, error) {
+	var ids, entries []string
+	for _, m := range []map[string]time.Time{u.queue, u.leases} {
+		for id, t := range m {
+			if t.After(now) {
+				continue
+			}
+			delete(u.queue, id)
+			u.leases[id] = now.Add(lease)
+			ids = append(ids, id)
+			entries = append(entries, u.entries[id])
+		}
+	}
+	return ids, entries, nil
+}
+
+func (u *undoSendMemory) Ack(_ context.Context, id string) error {
+	delete(u.leases, id)
+	delete(u.entries, id)
+	return nil
+}
+
+func (u *undoSendMemory) Cancel(_ context.Context, id string) (bool, error) {
+	if _, ok := u.queue[id]; !ok {
+		return false, nil
+	}
+	delete(u.queue, id)
+	delete(u.entries, id)
+	return true, nil
+}
+
+func TestUndoSendRelease(t *testing.T) {
+	ctx := context.Background()
+	memory := newUndoSendMemory()
+	db := NewUndoSendDatabase(memory)
+	msg := &sdkws.MsgData{SendID: "a", RecvID: "b", ClientMsgID: "m1", Content: []byte("hi")}
+	if err := db.Hold(ctx, "app", msg, time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs, err := db.ClaimDue(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 1 || msgs[0].AppID != "app" || msgs[0].Msg.ClientMsgID != "m1" || string(msgs[0].Msg.Content) != "hi" {
+		t.Fatalf("claimed msgs = %v", msgs)
+	}
+	// A claimed message can not be cancelled any more, nor claimed again while its lease lasts.
+	if ok, err := db.Cancel(ctx, "a", "m1"); err != nil || ok {
+		t.Fatalf("cancel of a claimed msg = %v, %v", ok, err)
+	}
+	if again, _ := db.ClaimDue(ctx, 10, time.Minute); len(again) != 0 {
+		t.Fatalf("msg claimed twice during its lease: %v", again)
+	}
+
+	// Until it is released the message is kept and claimed again once the lease ends.
+	memory.leases[msgs[0].ID] = time.Now().Add(-time.Second)
+	again, err := db.ClaimDue(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again) != 1 || again[0].ID != msgs[0].ID {
+		t.Fatalf("msg not claimed again after its lease: %v", again)
+	}
+	if err := db.Released(ctx, again[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(memory.entries) != 0 || len(memory.leases) != 0 {
+		t.Fatalf("released msg kept: %v %v", memory.entries, memory.leases)
+	}
+}
+
+func TestUndoSendDrop(t *testing.T) {
+	ctx := context.Background()
+	memory := newUndoSendMemory()
+	db := NewUndoSendDatabase(memory)
+	if err := db.Hold(ctx, "", &sdkws.MsgData{SendID: "a", ClientMsgID: "late"}, time.Now().Add(-heldMsgMaxDelay-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := memory.Hold(ctx, "a:invalid", "{", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Hold(ctx, "", &sdkws.MsgData{SendID: "a", ClientMsgID: "held"}, time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := db.ClaimDue(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 0 {
+		t.Fatalf("claimed msgs = %v", msgs)
+	}
+	// Invalid and expired entries are dropped, the held one is still cancellable.
+	if len(memory.entries) != 1 {
+		t.Fatalf("entries = %v", memory.entries)
+	}
+	if ok, err := db.Cancel(ctx, "a", "held"); err != nil || !ok {
+		t.Fatalf("cancel = %v, %v", ok, err)
+	}
+}
@@ -43,6 +43,8 @@ type UserDatabase interface {
 	//Update(ctx context.Context, user *relation.UserModel) (err error)
 	// UpdateByMap update (zero value) external guarantee userID exists
 	UpdateByMap(ctx context.Context, userID string, args map[string]any) (err error)
+	// Delete removes the user, its commands and cache
+	Delete(ctx context.Context, userID string) (err error)
 	// FindUser
 	PageFindUser(ctx context.Context, level1 int64, level2 int64, pagination pagination.Pagination) (count int64, users []*relation.UserModel, err error)
 	//FindUser with keyword
@@ -177,6 +179,15 @@ func (u *userDatabase) UpdateByMap(ctx context.Context, userID string, args map[
 	})
 }
 
+func (u *userDatabase) Delete(ctx context.Context, userID string) (err error) {
+	return u.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := u.userDB.Delete(ctx, userID); err != nil {
+			return err
+		}
+		return u.cache.DelUsersInfo(userID).DelUsersGlobalRecvMsgOpt(userID).ExecDel(ctx)
+	})
+}
+
 // Page Gets, returns no error if not found.
 func (u *userDatabase) Page(ctx context.Context, pagination pagination.Pagination) (count int64, users []*relation.UserModel, err error) {
 	return u.userDB.Page(ctx, pagination)
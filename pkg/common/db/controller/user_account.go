@@ -0,0 +1,159 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type UserAccountDatabase interface {
+	// GetAccount returns the account state of userID, active if it has none.
+	GetAccount(ctx context.Context, userID string) (*relation.UserAccountModel, error)
+	// FindInactiveUserIDs returns the users of userIDs that are deactivated, being deleted or deleted.
+	FindInactiveUserIDs(ctx context.Context, userIDs []string) ([]string, error)
+	Deactivate(ctx context.Context, userID string, operatorUserID string, reason string) error
+	// RequestDeletion marks userID as deleting and returns the time its purge is due, gracePeriod after now.
+	RequestDeletion(ctx context.Context, userID string, operatorUserID string, reason string, gracePeriod time.Duration) (time.Time, error)
+	// Reactivate restores a deactivated user or cancels a deletion whose purge is not queued yet,
+	// the friends and groups a deleting user was removed from are not restored.
+	Reactivate(ctx context.Context, userID string) error
+	FindDeleting(ctx context.Context, limit int64) ([]*relation.UserAccountModel, error)
+	// SetDetached records that the deleting user was removed from its friends and groups.
+	SetDetached(ctx context.Context, userID string) (bool, error)
+	// SetDeleted moves the deleting user to deleted before its purge is queued, so that it can not be reactivated
+	// any more, reporting false if it was reactivated in the meantime.
+	SetDeleted(ctx context.Context, userID string) (bool, error)
+	// SetPurgeID records the purge queued for the deleted user, an empty purgeID means queuing it failed and
+	// moves the user back to deleting for the next run.
+	SetPurgeID(ctx context.Context, userID string, purgeID string) error
+}
+
+type userAccountDatabase struct {
+	db relation.UserAccountModelInterface
+}
+
+func NewUserAccountDatabase(db relation.UserAccountModelInterface) UserAccountDatabase {
+	return &userAccountDatabase{db: db}
+}
+
+func (u *userAccountDatabase) GetAccount(ctx context.Context, userID string) (*relation.UserAccountModel, error) {
+	accounts, err := u.db.Find(ctx, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+	if len(accounts) == 0 {
+		return &relation.UserAccountModel{UserID: userID, Status: relation.UserAccountActive}, nil
+	}
+	return accounts[0], nil
+}
+
+func (u *userAccountDatabase) FindInactiveUserIDs(ctx context.Context, userIDs []string) ([]string, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	accounts, err := u.db.Find(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	inactive := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		if account.Status != relation.UserAccountActive {
+			inactive = append(inactive, account.UserID)
+		}
+	}
+	return inactive, nil
+}
+
+func (u *userAccountDatabase) Deactivate(ctx context.Context, userID string, operatorUserID string, reason string) error {
+	account, err := u.GetAccount(ctx, userID)
+	if err != nil {
+		return err
+	}
+	switch account.Status {
+	case relation.UserAccountDeactivated:
+		return nil
+	case relation.UserAccountDeleting, relation.UserAccountDeleted:
+		return errs.ErrArgs.Wrap("account is being deleted")
+	}
+	return u.db.Set(ctx, &relation.UserAccountModel{
+		UserID:         userID,
+		Status:         relation.UserAccountDeactivated,
+		Reason:         reason,
+		OperatorUserID: operatorUserID,
+		UpdateTime:     time.Now(),
+	})
+}
+
+func (u *userAccountDatabase) RequestDeletion(ctx context.Context, userID string, operatorUserID string, reason string, gracePeriod time.Duration) (time.Time, error) {
+	account, err := u.GetAccount(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if account.Status == relation.UserAccountDeleting || account.Status == relation.UserAccountDeleted {
+		return account.DeleteTime, nil
+	}
+	now := time.Now()
+	account = &relation.UserAccountModel{
+		UserID:         userID,
+		Status:         relation.UserAccountDeleting,
+		Reason:         reason,
+		OperatorUserID: operatorUserID,
+		DeleteTime:     now.Add(gracePeriod),
+		UpdateTime:     now,
+	}
+	if err := u.db.Set(ctx, account); err != nil {
+		return time.Time{}, err
+	}
+	return account.DeleteTime, nil
+}
+
+func (u *userAccountDatabase) Reactivate(ctx context.Context, userID string) error {
+	account, err := u.GetAccount(ctx, userID)
+	if err != nil {
+		return err
+	}
+	switch account.Status {
+	case relation.UserAccountActive:
+		return nil
+	case relation.UserAccountDeleted:
+		return errs.ErrArgs.Wrap("account is already deleted")
+	}
+	return u.db.Delete(ctx, userID)
+}
+
+func (u *userAccountDatabase) FindDeleting(ctx context.Context, limit int64) ([]*relation.UserAccountModel, error) {
+	return u.db.FindDeleting(ctx, time.Now(), limit)
+}
+
+func (u *userAccountDatabase) SetDetached(ctx context.Context, userID string) (bool, error) {
+	return u.db.Update(ctx, userID, relation.UserAccountDeleting, map[string]any{"detached": true, "update_time": time.Now()})
+}
+
+func (u *userAccountDatabase) SetDeleted(ctx context.Context, userID string) (bool, error) {
+	return u.db.Update(ctx, userID, relation.UserAccountDeleting, map[string]any{"status": relation.UserAccountDeleted, "update_time": time.Now()})
+}
+
+func (u *userAccountDatabase) SetPurgeID(ctx context.Context, userID string, purgeID string) error {
+	update := map[string]any{"purge_id": purgeID, "update_time": time.Now()}
+	if purgeID == "" {
+		update["status"] = relation.UserAccountDeleting
+	}
+	_, err := u.db.Update(ctx, userID, relation.UserAccountDeleted, update)
+	return err
+}
@@ -0,0 +1,108 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+// UserBlockMaxNum is the number of users one user may block.
+const UserBlockMaxNum = 10000
+
+type UserBlockDatabase interface {
+	Block(ctx context.Context, ownerUserID string, blockUserIDs []string) error
+	Unblock(ctx context.Context, ownerUserID string, blockUserIDs []string) error
+	// IsBlocked reports whether ownerUserID blocked userID.
+	IsBlocked(ctx context.Context, ownerUserID string, userID string) (bool, error)
+	// FindBlockers returns the users in ownerUserIDs who blocked userID.
+	FindBlockers(ctx context.Context, ownerUserIDs []string, userID string) ([]string, error)
+	PageBlocks(ctx context.Context, ownerUserID string, pagination pagination.Pagination) (int64, []*relation.UserBlockModel, error)
+}
+
+type userBlockDatabase struct {
+	db    relation.UserBlockModelInterface
+	cache cache.UserBlockCache
+}
+
+func NewUserBlockDatabase(db relation.UserBlockModelInterface, cache cache.UserBlockCache) UserBlockDatabase {
+	return &userBlockDatabase{db: db, cache: cache}
+}
+
+func (u *userBlockDatabase) Block(ctx context.Context, ownerUserID string, blockUserIDs []string) error {
+	if utils.IsContain(ownerUserID, blockUserIDs) {
+		return errs.ErrArgs.Wrap("can not block yourself")
+	}
+	blocked, err := u.cache.GetBlockUserIDs(ctx, ownerUserID)
+	if err != nil {
+		return err
+	}
+	blockUserIDs = utils.DifferenceString(blocked, utils.Distinct(blockUserIDs))
+	if len(blockUserIDs) == 0 {
+		return nil
+	}
+	if len(blocked)+len(blockUserIDs) > UserBlockMaxNum {
+		return errs.ErrArgs.Wrap(fmt.Sprintf("a user blocks at most %d users", UserBlockMaxNum))
+	}
+	now := time.Now()
+	blocks := make([]*relation.UserBlockModel, 0, len(blockUserIDs))
+	for _, blockUserID := range blockUserIDs {
+		blocks = append(blocks, &relation.UserBlockModel{OwnerUserID: ownerUserID, BlockUserID: blockUserID, CreateTime: now})
+	}
+	if err := u.db.Create(ctx, blocks); err != nil {
+		return err
+	}
+	return u.cache.DelBlockUserIDs(ownerUserID).ExecDel(ctx)
+}
+
+func (u *userBlockDatabase) Unblock(ctx context.Context, ownerUserID string, blockUserIDs []string) error {
+	if err := u.db.Delete(ctx, ownerUserID, blockUserIDs); err != nil {
+		return err
+	}
+	return u.cache.DelBlockUserIDs(ownerUserID).ExecDel(ctx)
+}
+
+func (u *userBlockDatabase) IsBlocked(ctx context.Context, ownerUserID string, userID string) (bool, error) {
+	blocked, err := u.cache.GetBlockUserIDs(ctx, ownerUserID)
+	if err != nil {
+		return false, err
+	}
+	return utils.IsContain(userID, blocked), nil
+}
+
+func (u *userBlockDatabase) FindBlockers(ctx context.Context, ownerUserIDs []string, userID string) ([]string, error) {
+	var blockers []string
+	for _, ownerUserID := range ownerUserIDs {
+		blocked, err := u.IsBlocked(ctx, ownerUserID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			blockers = append(blockers, ownerUserID)
+		}
+	}
+	return blockers, nil
+}
+
+func (u *userBlockDatabase) PageBlocks(ctx context.Context, ownerUserID string, pagination pagination.Pagination) (int64, []*relation.UserBlockModel, error) {
+	return u.db.Page(ctx, ownerUserID, pagination)
+}
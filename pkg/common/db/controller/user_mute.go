@@ -0,0 +1,108 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+// UserMuteMaxNum is the number of users one user may mute.
+const UserMuteMaxNum = 10000
+
+type UserMuteDatabase interface {
+	Mute(ctx context.Context, ownerUserID string, muteUserIDs []string) error
+	// Unmute stops muting muteUserIDs, the messages they sent while muted stay out of the unread count.
+	Unmute(ctx context.Context, ownerUserID string, muteUserIDs []string) error
+	PageMutes(ctx context.Context, ownerUserID string, pagination pagination.Pagination) (int64, []*relation.UserMuteModel, error)
+	// FindMuters returns the users in userIDs who muted sendID.
+	FindMuters(ctx context.Context, sendID string, userIDs []string) ([]string, error)
+	// RecordMutedMsg keeps the message seq of conversationID out of the unread count of userIDs.
+	RecordMutedMsg(ctx context.Context, userIDs []string, conversationID string, seq int64) error
+	// MutedUnreadCounts returns the number of muted messages above the has read seq of each conversation of userID.
+	MutedUnreadCounts(ctx context.Context, userID string, hasReadSeqs map[string]int64) (map[string]int64, error)
+}
+
+type userMuteDatabase struct {
+	db    relation.UserMuteModelInterface
+	cache cache.UserMuteCache
+}
+
+func NewUserMuteDatabase(db relation.UserMuteModelInterface, cache cache.UserMuteCache) UserMuteDatabase {
+	return &userMuteDatabase{db: db, cache: cache}
+}
+
+func (u *userMuteDatabase) Mute(ctx context.Context, ownerUserID string, muteUserIDs []string) error {
+	if utils.IsContain(ownerUserID, muteUserIDs) {
+		return errs.ErrArgs.Wrap("can not mute yourself")
+	}
+	muted, err := u.cache.GetMuteUserIDs(ctx, ownerUserID)
+	if err != nil {
+		return err
+	}
+	muteUserIDs = utils.DifferenceString(muted, utils.Distinct(muteUserIDs))
+	if len(muteUserIDs) == 0 {
+		return nil
+	}
+	if len(muted)+len(muteUserIDs) > UserMuteMaxNum {
+		return errs.ErrArgs.Wrap(fmt.Sprintf("a user mutes at most %d users", UserMuteMaxNum))
+	}
+	now := time.Now()
+	mutes := make([]*relation.UserMuteModel, 0, len(muteUserIDs))
+	for _, muteUserID := range muteUserIDs {
+		mutes = append(mutes, &relation.UserMuteModel{OwnerUserID: ownerUserID, MuteUserID: muteUserID, CreateTime: now})
+	}
+	if err := u.db.Create(ctx, mutes); err != nil {
+		return err
+	}
+	return u.cache.DelMuteUserIDs(ownerUserID).DelOwnerUserIDs(muteUserIDs...).ExecDel(ctx)
+}
+
+func (u *userMuteDatabase) Unmute(ctx context.Context, ownerUserID string, muteUserIDs []string) error {
+	if err := u.db.Delete(ctx, ownerUserID, muteUserIDs); err != nil {
+		return err
+	}
+	return u.cache.DelMuteUserIDs(ownerUserID).DelOwnerUserIDs(muteUserIDs...).ExecDel(ctx)
+}
+
+func (u *userMuteDatabase) PageMutes(ctx context.Context, ownerUserID string, pagination pagination.Pagination) (int64, []*relation.UserMuteModel, error) {
+	return u.db.Page(ctx, ownerUserID, pagination)
+}
+
+func (u *userMuteDatabase) FindMuters(ctx context.Context, sendID string, userIDs []string) ([]string, error) {
+	owners, err := u.cache.GetOwnerUserIDs(ctx, sendID)
+	if err != nil {
+		return nil, err
+	}
+	if len(owners) == 0 {
+		return nil, nil
+	}
+	return utils.IntersectString(owners, userIDs), nil
+}
+
+func (u *userMuteDatabase) RecordMutedMsg(ctx context.Context, userIDs []string, conversationID string, seq int64) error {
+	return u.cache.AddMutedSeq(ctx, userIDs, conversationID, seq)
+}
+
+func (u *userMuteDatabase) MutedUnreadCounts(ctx context.Context, userID string, hasReadSeqs map[string]int64) (map[string]int64, error) {
+	return u.cache.CountMutedSeqs(ctx, userID, hasReadSeqs)
+}
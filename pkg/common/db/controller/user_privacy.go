@@ -0,0 +1,77 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type UserPrivacyDatabase interface {
+	SetPrivacy(ctx context.Context, privacy *relation.UserPrivacyModel) error
+	// GetPrivacy returns the settings of userID, or the defaults when they were never set.
+	GetPrivacy(ctx context.Context, userID string) (*relation.UserPrivacyModel, error)
+	// FindPrivacy returns the settings of every user in userIDs keyed by userID, defaults included.
+	FindPrivacy(ctx context.Context, userIDs []string) (map[string]*relation.UserPrivacyModel, error)
+}
+
+type userPrivacyDatabase struct {
+	db relation.UserPrivacyModelInterface
+}
+
+func NewUserPrivacyDatabase(db relation.UserPrivacyModelInterface) UserPrivacyDatabase {
+	return &userPrivacyDatabase{db: db}
+}
+
+func (u *userPrivacyDatabase) SetPrivacy(ctx context.Context, privacy *relation.UserPrivacyModel) error {
+	switch privacy.AddFriendPolicy {
+	case relation.AddFriendPolicyAnyone, relation.AddFriendPolicyContactsOfContacts, relation.AddFriendPolicyNobody:
+	default:
+		return errs.ErrArgs.Wrap("invalid addFriendPolicy")
+	}
+	privacy.UpdateTime = time.Now()
+	return u.db.Set(ctx, privacy)
+}
+
+func (u *userPrivacyDatabase) GetPrivacy(ctx context.Context, userID string) (*relation.UserPrivacyModel, error) {
+	privacies, err := u.FindPrivacy(ctx, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+	return privacies[userID], nil
+}
+
+func (u *userPrivacyDatabase) FindPrivacy(ctx context.Context, userIDs []string) (map[string]*relation.UserPrivacyModel, error) {
+	res := make(map[string]*relation.UserPrivacyModel, len(userIDs))
+	if len(userIDs) == 0 {
+		return res, nil
+	}
+	privacies, err := u.db.Find(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, privacy := range privacies {
+		res[privacy.UserID] = privacy
+	}
+	for _, userID := range userIDs {
+		if _, ok := res[userID]; !ok {
+			res[userID] = relation.DefaultUserPrivacy(userID)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,94 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+type UserPurgeDatabase interface {
+	// CreatePurge queues the erasure of userID and returns its purgeID.
+	CreatePurge(ctx context.Context, userID string, operatorUserID string) (string, error)
+	TakePurge(ctx context.Context, purgeID string) (*relation.UserPurgeModel, error)
+	GetUserPurges(ctx context.Context, userID string) ([]*relation.UserPurgeModel, error)
+	// ClaimPending marks up to limit pending purges as running and returns them, a purge is claimed by one caller only.
+	ClaimPending(ctx context.Context, limit int64) ([]*relation.UserPurgeModel, error)
+	// Finish stores the report of a claimed purge, it is failed if any step failed.
+	Finish(ctx context.Context, purgeID string, steps []*relation.UserPurgeStepModel) error
+}
+
+type userPurgeDatabase struct {
+	db relation.UserPurgeModelInterface
+}
+
+func NewUserPurgeDatabase(db relation.UserPurgeModelInterface) UserPurgeDatabase {
+	return &userPurgeDatabase{db: db}
+}
+
+func (u *userPurgeDatabase) CreatePurge(ctx context.Context, userID string, operatorUserID string) (string, error) {
+	purge := &relation.UserPurgeModel{
+		PurgeID:        utils.GetMsgID(userID),
+		UserID:         userID,
+		OperatorUserID: operatorUserID,
+		Status:         relation.UserPurgePending,
+		Steps:          []*relation.UserPurgeStepModel{},
+		CreateTime:     time.Now(),
+	}
+	if err := u.db.Create(ctx, purge); err != nil {
+		return "", err
+	}
+	return purge.PurgeID, nil
+}
+
+func (u *userPurgeDatabase) TakePurge(ctx context.Context, purgeID string) (*relation.UserPurgeModel, error) {
+	return u.db.Take(ctx, purgeID)
+}
+
+func (u *userPurgeDatabase) GetUserPurges(ctx context.Context, userID string) ([]*relation.UserPurgeModel, error) {
+	return u.db.FindByUserID(ctx, userID)
+}
+
+func (u *userPurgeDatabase) ClaimPending(ctx context.Context, limit int64) ([]*relation.UserPurgeModel, error) {
+	pending, err := u.db.FindPending(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	claimed := make([]*relation.UserPurgeModel, 0, len(pending))
+	for _, purge := range pending {
+		ok, err := u.db.UpdateStatus(ctx, purge.PurgeID, relation.UserPurgePending, relation.UserPurgeRunning)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			claimed = append(claimed, purge)
+		}
+	}
+	return claimed, nil
+}
+
+func (u *userPurgeDatabase) Finish(ctx context.Context, purgeID string, steps []*relation.UserPurgeStepModel) error {
+	status := int32(relation.UserPurgeFinished)
+	for _, step := range steps {
+		if step.Err != "" {
+			status = relation.UserPurgeFailed
+			break
+		}
+	}
+	return u.db.Finish(ctx, purgeID, status, steps, time.Now())
+}
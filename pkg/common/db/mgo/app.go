@@ -0,0 +1,67 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewAppMongo(db *mongo.Database) (relation.AppModelInterface, error) {
+	coll := db.Collection("app")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "app_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &AppMgo{coll: coll}, nil
+}
+
+type AppMgo struct {
+	coll *mongo.Collection
+}
+
+func (a *AppMgo) Create(ctx context.Context, app *relation.AppModel) error {
+	return mgoutil.InsertMany(ctx, a.coll, []*relation.AppModel{app})
+}
+
+func (a *AppMgo) Take(ctx context.Context, appID string) (*relation.AppModel, error) {
+	return mgoutil.FindOne[*relation.AppModel](ctx, a.coll, bson.M{"app_id": appID})
+}
+
+func (a *AppMgo) FindAll(ctx context.Context) ([]*relation.AppModel, error) {
+	return mgoutil.Find[*relation.AppModel](ctx, a.coll, bson.M{})
+}
+
+func (a *AppMgo) Page(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.AppModel, error) {
+	return mgoutil.FindPage[*relation.AppModel](ctx, a.coll, bson.M{}, pagination)
+}
+
+func (a *AppMgo) UpdateSecret(ctx context.Context, appID string, secret string) error {
+	return mgoutil.UpdateOne(ctx, a.coll, bson.M{"app_id": appID}, bson.M{"$set": bson.M{"secret": secret}}, true)
+}
+
+func (a *AppMgo) UpdateManagers(ctx context.Context, appID string, managerUserIDs []string) error {
+	return mgoutil.UpdateOne(ctx, a.coll, bson.M{"app_id": appID}, bson.M{"$set": bson.M{"manager_user_ids": managerUserIDs}}, true)
+}
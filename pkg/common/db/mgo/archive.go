@@ -0,0 +1,66 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewArchiveWatermarkMongo(db *mongo.Database) (relation.ArchiveWatermarkModelInterface, error) {
+	coll := db.Collection("archive_watermark")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "conversation_id", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &ArchiveWatermarkMgo{coll: coll}, nil
+}
+
+type ArchiveWatermarkMgo struct {
+	coll *mongo.Collection
+}
+
+func (a *ArchiveWatermarkMgo) SetSeq(ctx context.Context, conversationID string, seq int64) error {
+	filter := bson.M{"conversation_id": conversationID}
+	update := bson.M{
+		"$max": bson.M{"seq": seq},
+		"$set": bson.M{"update_time": time.Now()},
+	}
+	return mgoutil.UpdateOne(ctx, a.coll, filter, update, false, options.Update().SetUpsert(true))
+}
+
+func (a *ArchiveWatermarkMgo) GetSeqs(ctx context.Context, conversationIDs []string) (map[string]int64, error) {
+	watermarks, err := mgoutil.Find[*relation.ArchiveWatermarkModel](ctx, a.coll, bson.M{"conversation_id": bson.M{"$in": conversationIDs}})
+	if err != nil {
+		return nil, err
+	}
+	seqs := make(map[string]int64, len(watermarks))
+	for _, watermark := range watermarks {
+		seqs[watermark.ConversationID] = watermark.Seq
+	}
+	return seqs, nil
+}
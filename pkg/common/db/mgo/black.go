@@ -102,3 +102,7 @@ func (b *BlackMgo) FindOwnerBlackInfos(ctx context.Context, ownerUserID string,
 func (b *BlackMgo) FindBlackUserIDs(ctx context.Context, ownerUserID string) (blackUserIDs []string, err error) {
 	return mgoutil.Find[string](ctx, b.coll, bson.M{"owner_user_id": ownerUserID}, options.Find().SetProjection(bson.M{"_id": 0, "block_user_id": 1}))
 }
+
+func (b *BlackMgo) FindInWhoseBlacks(ctx context.Context, blockUserID string) (blacks []*relation.BlackModel, err error) {
+	return mgoutil.Find[*relation.BlackModel](ctx, b.coll, bson.M{"block_user_id": blockUserID})
+}
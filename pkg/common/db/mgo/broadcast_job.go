@@ -0,0 +1,111 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewBroadcastJobMongo(db *mongo.Database) (relation.BroadcastJobModelInterface, error) {
+	coll := db.Collection("broadcast_job")
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "job_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "create_time", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "create_time", Value: -1}},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &BroadcastJobMgo{coll: coll}, nil
+}
+
+type BroadcastJobMgo struct {
+	coll *mongo.Collection
+}
+
+func (b *BroadcastJobMgo) Create(ctx context.Context, job *relation.BroadcastJobModel) error {
+	return mgoutil.InsertMany(ctx, b.coll, []*relation.BroadcastJobModel{job})
+}
+
+func (b *BroadcastJobMgo) Take(ctx context.Context, jobID string) (*relation.BroadcastJobModel, error) {
+	return mgoutil.FindOne[*relation.BroadcastJobModel](ctx, b.coll, bson.M{"job_id": jobID})
+}
+
+func (b *BroadcastJobMgo) FindPage(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.BroadcastJobModel, error) {
+	// The message and the recipients are left out of the listing.
+	opts := options.Find().SetSort(bson.M{"create_time": -1}).SetProjection(bson.M{"msg_data": 0, "recv_ids": 0})
+	return mgoutil.FindPage[*relation.BroadcastJobModel](ctx, b.coll, bson.M{}, pagination, opts)
+}
+
+func (b *BroadcastJobMgo) Lease(ctx context.Context, now time.Time, leaseUntil time.Time) (*relation.BroadcastJobModel, error) {
+	filter := bson.M{
+		"status":      bson.M{"$in": []int32{relation.BroadcastPending, relation.BroadcastRunning}},
+		"lease_until": bson.M{"$lt": now},
+	}
+	update := bson.M{"$set": bson.M{"status": relation.BroadcastRunning, "lease_until": leaseUntil, "update_time": now}}
+	opts := options.FindOneAndUpdate().SetSort(bson.M{"create_time": 1}).SetReturnDocument(options.After)
+	var job relation.BroadcastJobModel
+	if err := b.coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, errs.Wrap(err)
+	}
+	return &job, nil
+}
+
+func (b *BroadcastJobMgo) UpdateProgress(ctx context.Context, job *relation.BroadcastJobModel) (bool, error) {
+	update := bson.M{"$set": bson.M{
+		"page":        job.Page,
+		"sent":        job.Sent,
+		"failed":      job.Failed,
+		"update_time": job.UpdateTime,
+	}}
+	result, err := b.coll.UpdateOne(ctx, bson.M{"job_id": job.JobID, "status": relation.BroadcastRunning}, update)
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (b *BroadcastJobMgo) UpdateStatus(ctx context.Context, jobID string, from []int32, to int32, errMsg string) (bool, error) {
+	now := time.Now()
+	set := bson.M{"status": to, "err": errMsg, "update_time": now}
+	if to != relation.BroadcastRunning {
+		set["finish_time"] = now
+	}
+	result, err := b.coll.UpdateOne(ctx, bson.M{"job_id": jobID, "status": bson.M{"$in": from}}, bson.M{"$set": set})
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return result.MatchedCount > 0, nil
+}
@@ -0,0 +1,77 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewBusinessTopicMongo(db *mongo.Database) (relation.BusinessTopicModelInterface, error) {
+	coll := db.Collection("business_topic_subscriber")
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "topic", Value: 1},
+				{Key: "user_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+			},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &BusinessTopicMgo{coll: coll}, nil
+}
+
+type BusinessTopicMgo struct {
+	coll *mongo.Collection
+}
+
+func (b *BusinessTopicMgo) Subscribe(ctx context.Context, subscribers []*relation.BusinessTopicSubscriberModel) error {
+	for _, subscriber := range subscribers {
+		filter := bson.M{"topic": subscriber.Topic, "user_id": subscriber.UserID}
+		update := bson.M{"$setOnInsert": bson.M{"create_time": subscriber.CreateTime}}
+		if err := mgoutil.UpdateOne(ctx, b.coll, filter, update, false, options.Update().SetUpsert(true)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *BusinessTopicMgo) Unsubscribe(ctx context.Context, userID string, topics []string) error {
+	return mgoutil.DeleteMany(ctx, b.coll, bson.M{"user_id": userID, "topic": bson.M{"$in": topics}})
+}
+
+func (b *BusinessTopicMgo) FindUserTopics(ctx context.Context, userID string) ([]string, error) {
+	return mgoutil.Find[string](ctx, b.coll, bson.M{"user_id": userID}, options.Find().SetProjection(bson.M{"_id": 0, "topic": 1}))
+}
+
+func (b *BusinessTopicMgo) PageSubscriberIDs(ctx context.Context, topic string, pagination pagination.Pagination) (int64, []string, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 0, "user_id": 1}).SetSort(bson.M{"user_id": 1})
+	return mgoutil.FindPage[string](ctx, b.coll, bson.M{"topic": topic}, pagination, opts)
+}
@@ -0,0 +1,68 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewContentPolicyMongo(db *mongo.Database) (relation.ContentPolicyModelInterface, error) {
+	coll := db.Collection("content_policy")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "session_type", Value: 1}, {Key: "group_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &ContentPolicyMgo{coll: coll}, nil
+}
+
+type ContentPolicyMgo struct {
+	coll *mongo.Collection
+}
+
+func (c *ContentPolicyMgo) Set(ctx context.Context, policy *relation.ContentPolicyModel) error {
+	filter := bson.M{"session_type": policy.SessionType, "group_id": policy.GroupID}
+	update := bson.M{"$set": bson.M{"content_types": policy.ContentTypes, "update_time": policy.UpdateTime}}
+	return mgoutil.UpdateOne(ctx, c.coll, filter, update, false, options.Update().SetUpsert(true))
+}
+
+func (c *ContentPolicyMgo) Delete(ctx context.Context, sessionType int32, groupID string) error {
+	return mgoutil.DeleteMany(ctx, c.coll, bson.M{"session_type": sessionType, "group_id": groupID})
+}
+
+func (c *ContentPolicyMgo) FindContentTypes(ctx context.Context, sessionType int32, groupID string) ([]int32, error) {
+	policies, err := mgoutil.Find[*relation.ContentPolicyModel](ctx, c.coll, bson.M{"session_type": sessionType, "group_id": groupID})
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, nil
+	}
+	return policies[0].ContentTypes, nil
+}
+
+func (c *ContentPolicyMgo) Page(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.ContentPolicyModel, error) {
+	return mgoutil.FindPage[*relation.ContentPolicyModel](ctx, c.coll, bson.M{}, pagination, options.Find().SetSort(bson.D{{Key: "session_type", Value: 1}, {Key: "group_id", Value: 1}}))
+}
@@ -169,3 +169,31 @@ func (c *ConversationMgo) GetConversationNotReceiveMessageUserIDs(ctx context.Co
 		options.Find().SetProjection(bson.M{"_id": 0, "owner_user_id": 1}),
 	)
 }
+
+func (c *ConversationMgo) FindNonCanonicalSingleChats(ctx context.Context, ownerUserIDs []string, limit int64) ([]*relation.ConversationModel, error) {
+	match := bson.M{"conversation_type": constant.SingleChatType, "user_id": bson.M{"$ne": ""}}
+	if len(ownerUserIDs) > 0 {
+		match["owner_user_id"] = bson.M{"$in": ownerUserIDs}
+	}
+	// The canonical id joins the sorted user ids, see msgprocessor.GetConversationIDBySessionType.
+	ownerFirst := bson.M{"$lt": bson.A{"$owner_user_id", "$user_id"}}
+	canonical := bson.M{"$concat": bson.A{
+		"si_",
+		bson.M{"$cond": bson.A{ownerFirst, "$owner_user_id", "$user_id"}},
+		"_",
+		bson.M{"$cond": bson.A{ownerFirst, "$user_id", "$owner_user_id"}},
+	}}
+	return mgoutil.Aggregate[*relation.ConversationModel](ctx, c.coll, []bson.M{
+		{"$match": match},
+		{"$match": bson.M{"$expr": bson.M{"$ne": bson.A{"$conversation_id", canonical}}}},
+		{"$sort": bson.D{{Key: "owner_user_id", Value: 1}, {Key: "conversation_id", Value: 1}}},
+		{"$limit": limit},
+	})
+}
+
+func (c *ConversationMgo) DeleteConversations(ctx context.Context, ownerUserID string, conversationIDs []string) error {
+	if len(conversationIDs) == 0 {
+		return nil
+	}
+	return mgoutil.DeleteMany(ctx, c.coll, bson.M{"owner_user_id": ownerUserID, "conversation_id": bson.M{"$in": conversationIDs}})
+}
@@ -0,0 +1,94 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewFriendLabelMongo(db *mongo.Database) (relation.FriendLabelModelInterface, error) {
+	coll := db.Collection("friend_label")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "owner_user_id", Value: 1}, {Key: "label_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &FriendLabelMgo{coll: coll}, nil
+}
+
+type FriendLabelMgo struct {
+	coll *mongo.Collection
+}
+
+func (f *FriendLabelMgo) filter(ownerUserID string, labelID string) bson.M {
+	return bson.M{"owner_user_id": ownerUserID, "label_id": labelID}
+}
+
+func (f *FriendLabelMgo) Create(ctx context.Context, label *relation.FriendLabelModel) error {
+	return mgoutil.InsertMany(ctx, f.coll, []*relation.FriendLabelModel{label})
+}
+
+func (f *FriendLabelMgo) UpdateName(ctx context.Context, ownerUserID string, labelID string, name string) error {
+	update := bson.M{"$set": bson.M{"name": name, "update_time": time.Now()}}
+	return mgoutil.UpdateOne(ctx, f.coll, f.filter(ownerUserID, labelID), update, true)
+}
+
+func (f *FriendLabelMgo) Delete(ctx context.Context, ownerUserID string, labelID string) error {
+	return mgoutil.DeleteOne(ctx, f.coll, f.filter(ownerUserID, labelID))
+}
+
+func (f *FriendLabelMgo) Take(ctx context.Context, ownerUserID string, labelID string) (*relation.FriendLabelModel, error) {
+	return mgoutil.FindOne[*relation.FriendLabelModel](ctx, f.coll, f.filter(ownerUserID, labelID))
+}
+
+func (f *FriendLabelMgo) FindByOwner(ctx context.Context, ownerUserID string) ([]*relation.FriendLabelModel, error) {
+	return mgoutil.Find[*relation.FriendLabelModel](ctx, f.coll, bson.M{"owner_user_id": ownerUserID}, options.Find().SetSort(bson.M{"create_time": 1}))
+}
+
+func (f *FriendLabelMgo) AddFriends(ctx context.Context, ownerUserID string, labelID string, friendUserIDs []string) error {
+	update := bson.M{
+		"$addToSet": bson.M{"friend_user_ids": bson.M{"$each": friendUserIDs}},
+		"$set":      bson.M{"update_time": time.Now()},
+	}
+	return mgoutil.UpdateOne(ctx, f.coll, f.filter(ownerUserID, labelID), update, true)
+}
+
+func (f *FriendLabelMgo) RemoveFriends(ctx context.Context, ownerUserID string, labelID string, friendUserIDs []string) error {
+	update := bson.M{
+		"$pullAll": bson.M{"friend_user_ids": friendUserIDs},
+		"$set":     bson.M{"update_time": time.Now()},
+	}
+	return mgoutil.UpdateOne(ctx, f.coll, f.filter(ownerUserID, labelID), update, true)
+}
+
+func (f *FriendLabelMgo) RemoveFriendsFromAll(ctx context.Context, ownerUserID string, friendUserIDs []string) error {
+	filter := bson.M{"owner_user_id": ownerUserID, "friend_user_ids": bson.M{"$in": friendUserIDs}}
+	update := bson.M{
+		"$pullAll": bson.M{"friend_user_ids": friendUserIDs},
+		"$set":     bson.M{"update_time": time.Now()},
+	}
+	_, err := mgoutil.UpdateMany(ctx, f.coll, filter, update)
+	return err
+}
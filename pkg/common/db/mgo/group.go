@@ -25,9 +25,11 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
-func NewGroupMongo(db *mongo.Database) (relation.GroupModelInterface, error) {
+// NewGroupMongo returns the groups, a nil readPref reads everything from the primary.
+func NewGroupMongo(db *mongo.Database, readPref *readpref.ReadPref) (relation.GroupModelInterface, error) {
 	coll := db.Collection("group")
 	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
 		Keys: bson.D{
@@ -38,11 +40,17 @@ func NewGroupMongo(db *mongo.Database) (relation.GroupModelInterface, error) {
 	if err != nil {
 		return nil, errs.Wrap(err)
 	}
-	return &GroupMgo{coll: coll}, nil
+	readColl := coll
+	if readPref != nil {
+		readColl = db.Collection("group", options.Collection().SetReadPreference(readPref))
+	}
+	return &GroupMgo{coll: coll, readColl: readColl}, nil
 }
 
 type GroupMgo struct {
 	coll *mongo.Collection
+	// readColl serves the searches and statistics with the group read preference.
+	readColl *mongo.Collection
 }
 
 func (g *GroupMgo) Create(ctx context.Context, groups []*relation.GroupModel) (err error) {
@@ -69,14 +77,14 @@ func (g *GroupMgo) Take(ctx context.Context, groupID string) (group *relation.Gr
 }
 
 func (g *GroupMgo) Search(ctx context.Context, keyword string, pagination pagination.Pagination) (total int64, groups []*relation.GroupModel, err error) {
-	return mgoutil.FindPage[*relation.GroupModel](ctx, g.coll, bson.M{"group_name": bson.M{"$regex": keyword}}, pagination)
+	return mgoutil.FindPage[*relation.GroupModel](ctx, g.readColl, bson.M{"group_name": bson.M{"$regex": keyword}}, pagination)
 }
 
 func (g *GroupMgo) CountTotal(ctx context.Context, before *time.Time) (count int64, err error) {
 	if before == nil {
-		return mgoutil.Count(ctx, g.coll, bson.M{})
+		return mgoutil.Count(ctx, g.readColl, bson.M{})
 	}
-	return mgoutil.Count(ctx, g.coll, bson.M{"create_time": bson.M{"$lt": before}})
+	return mgoutil.Count(ctx, g.readColl, bson.M{"create_time": bson.M{"$lt": before}})
 }
 
 func (g *GroupMgo) CountRangeEverydayTotal(ctx context.Context, start time.Time, end time.Time) (map[string]int64, error) {
@@ -107,7 +115,7 @@ func (g *GroupMgo) CountRangeEverydayTotal(ctx context.Context, start time.Time,
 		Date  string `bson:"_id"`
 		Count int64  `bson:"count"`
 	}
-	items, err := mgoutil.Aggregate[Item](ctx, g.coll, pipeline)
+	items, err := mgoutil.Aggregate[Item](ctx, g.readColl, pipeline)
 	if err != nil {
 		return nil, err
 	}
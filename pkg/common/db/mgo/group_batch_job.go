@@ -0,0 +1,89 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewGroupBatchJobMongo(db *mongo.Database) (relation.GroupBatchJobModelInterface, error) {
+	coll := db.Collection("group_batch_job")
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "job_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "create_time", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "create_time", Value: -1}},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &GroupBatchJobMgo{coll: coll}, nil
+}
+
+type GroupBatchJobMgo struct {
+	coll *mongo.Collection
+}
+
+func (g *GroupBatchJobMgo) Create(ctx context.Context, job *relation.GroupBatchJobModel) error {
+	return mgoutil.InsertMany(ctx, g.coll, []*relation.GroupBatchJobModel{job})
+}
+
+func (g *GroupBatchJobMgo) Take(ctx context.Context, jobID string) (*relation.GroupBatchJobModel, error) {
+	return mgoutil.FindOne[*relation.GroupBatchJobModel](ctx, g.coll, bson.M{"job_id": jobID})
+}
+
+func (g *GroupBatchJobMgo) FindPage(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.GroupBatchJobModel, error) {
+	// The items are left out of the listing, a job may hold thousands of them.
+	opts := options.Find().SetSort(bson.M{"create_time": -1}).SetProjection(bson.M{"items": 0})
+	return mgoutil.FindPage[*relation.GroupBatchJobModel](ctx, g.coll, bson.M{}, pagination, opts)
+}
+
+func (g *GroupBatchJobMgo) FindPending(ctx context.Context, limit int64) ([]*relation.GroupBatchJobModel, error) {
+	filter := bson.M{"status": relation.GroupBatchPending}
+	return mgoutil.Find[*relation.GroupBatchJobModel](ctx, g.coll, filter, options.Find().SetSort(bson.M{"create_time": 1}).SetLimit(limit))
+}
+
+func (g *GroupBatchJobMgo) UpdateStatus(ctx context.Context, jobID string, from int32, to int32) (bool, error) {
+	result, err := g.coll.UpdateOne(ctx, bson.M{"job_id": jobID, "status": from}, bson.M{"$set": bson.M{"status": to}})
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (g *GroupBatchJobMgo) Finish(ctx context.Context, job *relation.GroupBatchJobModel) error {
+	update := bson.M{"$set": bson.M{
+		"status":      job.Status,
+		"items":       job.Items,
+		"succeeded":   job.Succeeded,
+		"failed":      job.Failed,
+		"finish_time": job.FinishTime,
+	}}
+	return mgoutil.UpdateOne(ctx, g.coll, bson.M{"job_id": job.JobID}, update, false)
+}
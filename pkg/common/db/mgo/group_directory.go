@@ -0,0 +1,102 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewGroupDirectoryMongo(db *mongo.Database) (relation.GroupDirectoryModelInterface, error) {
+	coll := db.Collection("group_directory")
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "group_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "group_name", Value: "text"}, {Key: "tags", Value: "text"}},
+			Options: options.Index().SetWeights(bson.M{"group_name": 3, "tags": 2}),
+		},
+		{
+			Keys: bson.D{{Key: "update_time", Value: -1}},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &GroupDirectoryMgo{coll: coll}, nil
+}
+
+type GroupDirectoryMgo struct {
+	coll *mongo.Collection
+}
+
+func (g *GroupDirectoryMgo) Set(ctx context.Context, entry *relation.GroupDirectoryModel) error {
+	update := bson.M{
+		"$set": bson.M{
+			"group_name":   entry.GroupName,
+			"tags":         entry.Tags,
+			"introduction": entry.Introduction,
+			"face_url":     entry.FaceURL,
+			"update_time":  entry.UpdateTime,
+		},
+		"$setOnInsert": bson.M{"create_time": entry.CreateTime},
+	}
+	return mgoutil.UpdateOne(ctx, g.coll, bson.M{"group_id": entry.GroupID}, update, false, options.Update().SetUpsert(true))
+}
+
+func (g *GroupDirectoryMgo) UpdateGroupInfo(ctx context.Context, groupID string, groupName string, introduction string, faceURL string) (bool, error) {
+	update := bson.M{"$set": bson.M{
+		"group_name":   groupName,
+		"introduction": introduction,
+		"face_url":     faceURL,
+		"update_time":  time.Now(),
+	}}
+	result, err := g.coll.UpdateOne(ctx, bson.M{"group_id": groupID}, update)
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (g *GroupDirectoryMgo) Delete(ctx context.Context, groupID string) error {
+	return mgoutil.DeleteOne(ctx, g.coll, bson.M{"group_id": groupID})
+}
+
+func (g *GroupDirectoryMgo) Take(ctx context.Context, groupID string) (*relation.GroupDirectoryModel, error) {
+	return mgoutil.FindOne[*relation.GroupDirectoryModel](ctx, g.coll, bson.M{"group_id": groupID})
+}
+
+func (g *GroupDirectoryMgo) Find(ctx context.Context, groupIDs []string) ([]*relation.GroupDirectoryModel, error) {
+	return mgoutil.Find[*relation.GroupDirectoryModel](ctx, g.coll, bson.M{"group_id": bson.M{"$in": groupIDs}})
+}
+
+func (g *GroupDirectoryMgo) Search(ctx context.Context, keyword string, pagination pagination.Pagination) (int64, []*relation.GroupDirectoryModel, error) {
+	if keyword == "" {
+		return mgoutil.FindPage[*relation.GroupDirectoryModel](ctx, g.coll, bson.M{}, pagination, options.Find().SetSort(bson.M{"update_time": -1}))
+	}
+	score := bson.M{"$meta": "textScore"}
+	opts := options.Find().SetProjection(bson.M{"score": score}).SetSort(bson.D{{Key: "score", Value: score}})
+	return mgoutil.FindPage[*relation.GroupDirectoryModel](ctx, g.coll, bson.M{"$text": bson.M{"$search": keyword}}, pagination, opts)
+}
@@ -0,0 +1,82 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewGroupHistoryVisibilityMongo(db *mongo.Database) (relation.GroupHistoryVisibilityModelInterface, error) {
+	coll := db.Collection("group_history_visibility")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "group_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	formerColl := db.Collection("group_former_member")
+	_, err = formerColl.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &GroupHistoryVisibilityMgo{coll: coll, formerColl: formerColl}, nil
+}
+
+type GroupHistoryVisibilityMgo struct {
+	coll       *mongo.Collection
+	formerColl *mongo.Collection
+}
+
+func (g *GroupHistoryVisibilityMgo) Set(ctx context.Context, visibility *relation.GroupHistoryVisibilityModel) error {
+	update := bson.M{"$set": bson.M{
+		"visibility":  visibility.Visibility,
+		"days":        visibility.Days,
+		"update_time": visibility.UpdateTime,
+	}}
+	return mgoutil.UpdateOne(ctx, g.coll, bson.M{"group_id": visibility.GroupID}, update, false, options.Update().SetUpsert(true))
+}
+
+func (g *GroupHistoryVisibilityMgo) Take(ctx context.Context, groupID string) (*relation.GroupHistoryVisibilityModel, error) {
+	return mgoutil.FindOne[*relation.GroupHistoryVisibilityModel](ctx, g.coll, bson.M{"group_id": groupID})
+}
+
+func (g *GroupHistoryVisibilityMgo) SetFormerMembers(ctx context.Context, members []*relation.GroupFormerMemberModel) error {
+	for _, member := range members {
+		update := bson.M{"$set": bson.M{
+			"join_time":  member.JoinTime,
+			"leave_time": member.LeaveTime,
+		}}
+		filter := bson.M{"group_id": member.GroupID, "user_id": member.UserID}
+		if err := mgoutil.UpdateOne(ctx, g.formerColl, filter, update, false, options.Update().SetUpsert(true)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *GroupHistoryVisibilityMgo) TakeFormerMember(ctx context.Context, groupID string, userID string) (*relation.GroupFormerMemberModel, error) {
+	return mgoutil.FindOne[*relation.GroupFormerMemberModel](ctx, g.formerColl, bson.M{"group_id": groupID, "user_id": userID})
+}
@@ -0,0 +1,82 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewGroupInviteLinkMongo(db *mongo.Database) (relation.GroupInviteLinkModelInterface, error) {
+	coll := db.Collection("group_invite_link")
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "group_id", Value: 1}},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &GroupInviteLinkMgo{coll: coll}, nil
+}
+
+type GroupInviteLinkMgo struct {
+	coll *mongo.Collection
+}
+
+func (g *GroupInviteLinkMgo) Create(ctx context.Context, link *relation.GroupInviteLinkModel) error {
+	return mgoutil.InsertMany(ctx, g.coll, []*relation.GroupInviteLinkModel{link})
+}
+
+func (g *GroupInviteLinkMgo) Delete(ctx context.Context, groupID string, token string) error {
+	return mgoutil.DeleteOne(ctx, g.coll, bson.M{"group_id": groupID, "token": token})
+}
+
+func (g *GroupInviteLinkMgo) Take(ctx context.Context, token string) (*relation.GroupInviteLinkModel, error) {
+	return mgoutil.FindOne[*relation.GroupInviteLinkModel](ctx, g.coll, bson.M{"token": token})
+}
+
+func (g *GroupInviteLinkMgo) FindByGroup(ctx context.Context, groupID string) ([]*relation.GroupInviteLinkModel, error) {
+	return mgoutil.Find[*relation.GroupInviteLinkModel](ctx, g.coll, bson.M{"group_id": groupID}, options.Find().SetSort(bson.M{"create_time": -1}))
+}
+
+func (g *GroupInviteLinkMgo) CountByGroup(ctx context.Context, groupID string) (int64, error) {
+	return mgoutil.Count(ctx, g.coll, bson.M{"group_id": groupID})
+}
+
+func (g *GroupInviteLinkMgo) IncrUses(ctx context.Context, token string) (bool, error) {
+	filter := bson.M{
+		"token": token,
+		"$or": bson.A{
+			bson.M{"max_uses": 0},
+			bson.M{"$expr": bson.M{"$lt": bson.A{"$uses", "$max_uses"}}},
+		},
+	}
+	result, err := g.coll.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"uses": 1}})
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return result.MatchedCount > 0, nil
+}
@@ -0,0 +1,85 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewGroupMemberEventMongo(db *mongo.Database) (relation.GroupMemberEventModelInterface, error) {
+	coll := db.Collection("group_member_event")
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "event_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_time", Value: 1}},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &GroupMemberEventMgo{coll: coll}, nil
+}
+
+type GroupMemberEventMgo struct {
+	coll *mongo.Collection
+}
+
+func (g *GroupMemberEventMgo) Create(ctx context.Context, events []*relation.GroupMemberEventModel) error {
+	return mgoutil.InsertMany(ctx, g.coll, events)
+}
+
+func (g *GroupMemberEventMgo) FindDue(ctx context.Context, now time.Time, limit int64) ([]*relation.GroupMemberEventModel, error) {
+	filter := bson.M{"status": relation.GroupMemberEventPending, "next_time": bson.M{"$lte": now}}
+	return mgoutil.Find[*relation.GroupMemberEventModel](ctx, g.coll, filter, options.Find().SetSort(bson.M{"event_time": 1}).SetLimit(limit))
+}
+
+func (g *GroupMemberEventMgo) Claim(ctx context.Context, eventID string, nextTime time.Time, leaseTime time.Time) (bool, error) {
+	filter := bson.M{"event_id": eventID, "status": relation.GroupMemberEventPending, "next_time": nextTime}
+	result, err := g.coll.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"next_time": leaseTime}})
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (g *GroupMemberEventMgo) SetDelivered(ctx context.Context, eventIDs []string) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+	update := bson.M{"$set": bson.M{"status": relation.GroupMemberEventDelivered, "last_err": ""}}
+	_, err := mgoutil.UpdateMany(ctx, g.coll, bson.M{"event_id": bson.M{"$in": eventIDs}}, update)
+	return err
+}
+
+func (g *GroupMemberEventMgo) SetAttempt(ctx context.Context, eventID string, status int32, attempts int32, nextTime time.Time, lastErr string) error {
+	update := bson.M{"$set": bson.M{
+		"status":    status,
+		"attempts":  attempts,
+		"next_time": nextTime,
+		"last_err":  lastErr,
+	}}
+	return mgoutil.UpdateOne(ctx, g.coll, bson.M{"event_id": eventID}, update, false)
+}
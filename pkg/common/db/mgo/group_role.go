@@ -0,0 +1,113 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewGroupRoleMongo(db *mongo.Database) (relation.GroupRoleModelInterface, error) {
+	roleColl := db.Collection("group_role")
+	_, err := roleColl.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "role_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	memberColl := db.Collection("group_member_role")
+	_, err = memberColl.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "group_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "group_id", Value: 1}, {Key: "role_id", Value: 1}},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &GroupRoleMgo{roleColl: roleColl, memberColl: memberColl}, nil
+}
+
+type GroupRoleMgo struct {
+	roleColl   *mongo.Collection
+	memberColl *mongo.Collection
+}
+
+func (g *GroupRoleMgo) SetRole(ctx context.Context, role *relation.GroupRoleModel) error {
+	update := bson.M{
+		"$set": bson.M{
+			"name":        role.Name,
+			"permissions": role.Permissions,
+			"update_time": role.UpdateTime,
+		},
+		"$setOnInsert": bson.M{"create_time": role.CreateTime},
+	}
+	return mgoutil.UpdateOne(ctx, g.roleColl, bson.M{"group_id": role.GroupID, "role_id": role.RoleID}, update, false, options.Update().SetUpsert(true))
+}
+
+func (g *GroupRoleMgo) DeleteRole(ctx context.Context, groupID string, roleID string) error {
+	return mgoutil.DeleteOne(ctx, g.roleColl, bson.M{"group_id": groupID, "role_id": roleID})
+}
+
+func (g *GroupRoleMgo) FindRoles(ctx context.Context, groupID string) ([]*relation.GroupRoleModel, error) {
+	return mgoutil.Find[*relation.GroupRoleModel](ctx, g.roleColl, bson.M{"group_id": groupID}, options.Find().SetSort(bson.M{"create_time": 1}))
+}
+
+func (g *GroupRoleMgo) SetMemberRole(ctx context.Context, groupID string, userIDs []string, roleID string) error {
+	now := time.Now()
+	for _, userID := range userIDs {
+		update := bson.M{"$set": bson.M{"role_id": roleID, "update_time": now}}
+		err := mgoutil.UpdateOne(ctx, g.memberColl, bson.M{"group_id": groupID, "user_id": userID}, update, false, options.Update().SetUpsert(true))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *GroupRoleMgo) DeleteMemberRoles(ctx context.Context, groupID string, userIDs []string) error {
+	return mgoutil.DeleteMany(ctx, g.memberColl, bson.M{"group_id": groupID, "user_id": bson.M{"$in": userIDs}})
+}
+
+func (g *GroupRoleMgo) TakeMemberRole(ctx context.Context, groupID string, userID string) (*relation.GroupMemberRoleModel, error) {
+	return mgoutil.FindOne[*relation.GroupMemberRoleModel](ctx, g.memberColl, bson.M{"group_id": groupID, "user_id": userID})
+}
+
+func (g *GroupRoleMgo) FindMemberRoles(ctx context.Context, groupID string) ([]*relation.GroupMemberRoleModel, error) {
+	return mgoutil.Find[*relation.GroupMemberRoleModel](ctx, g.memberColl, bson.M{"group_id": groupID})
+}
+
+func (g *GroupRoleMgo) FindRoleMemberIDs(ctx context.Context, groupID string, roleID string) ([]string, error) {
+	members, err := mgoutil.Find[*relation.GroupMemberRoleModel](ctx, g.memberColl, bson.M{"group_id": groupID, "role_id": roleID})
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]string, 0, len(members))
+	for _, member := range members {
+		userIDs = append(userIDs, member.UserID)
+	}
+	return userIDs, nil
+}
@@ -0,0 +1,95 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewLegalHoldMongo(db *mongo.Database) (relation.LegalHoldModelInterface, error) {
+	coll := db.Collection("legal_hold")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	logColl := db.Collection("legal_hold_log")
+	_, err = logColl.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "create_time", Value: -1}},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &LegalHoldMgo{coll: coll, logColl: logColl}, nil
+}
+
+type LegalHoldMgo struct {
+	coll    *mongo.Collection
+	logColl *mongo.Collection
+}
+
+func (l *LegalHoldMgo) Create(ctx context.Context, hold *relation.LegalHoldModel) error {
+	return mgoutil.InsertMany(ctx, l.coll, []*relation.LegalHoldModel{hold})
+}
+
+func (l *LegalHoldMgo) Delete(ctx context.Context, targetType string, targetID string) (*relation.LegalHoldModel, error) {
+	var hold relation.LegalHoldModel
+	err := l.coll.FindOneAndDelete(ctx, bson.M{"target_type": targetType, "target_id": targetID}).Decode(&hold)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, errs.Wrap(err)
+	}
+	return &hold, nil
+}
+
+func (l *LegalHoldMgo) Find(ctx context.Context, targetType string, targetIDs []string) ([]*relation.LegalHoldModel, error) {
+	return mgoutil.Find[*relation.LegalHoldModel](ctx, l.coll, bson.M{"target_type": targetType, "target_id": bson.M{"$in": targetIDs}})
+}
+
+func (l *LegalHoldMgo) Page(ctx context.Context, targetType string, pagination pagination.Pagination) (int64, []*relation.LegalHoldModel, error) {
+	filter := bson.M{}
+	if targetType != "" {
+		filter["target_type"] = targetType
+	}
+	return mgoutil.FindPage[*relation.LegalHoldModel](ctx, l.coll, filter, pagination, options.Find().SetSort(bson.M{"create_time": -1}))
+}
+
+func (l *LegalHoldMgo) AddLog(ctx context.Context, log *relation.LegalHoldLogModel) error {
+	return mgoutil.InsertMany(ctx, l.logColl, []*relation.LegalHoldLogModel{log})
+}
+
+func (l *LegalHoldMgo) PageLogs(ctx context.Context, targetType string, targetID string, pagination pagination.Pagination) (int64, []*relation.LegalHoldLogModel, error) {
+	filter := bson.M{}
+	if targetType != "" {
+		filter["target_type"] = targetType
+	}
+	if targetID != "" {
+		filter["target_id"] = targetID
+	}
+	return mgoutil.FindPage[*relation.LegalHoldLogModel](ctx, l.logColl, filter, pagination, options.Find().SetSort(bson.M{"create_time": -1}))
+}
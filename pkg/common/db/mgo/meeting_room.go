@@ -0,0 +1,113 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewMeetingRoomMongo(db *mongo.Database) (relation.MeetingRoomModelInterface, error) {
+	coll := db.Collection("meeting_room")
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "room_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "participant_ids", Value: 1}, {Key: "start_time", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "start_time", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "end_time", Value: 1}},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &MeetingRoomMgo{coll: coll}, nil
+}
+
+type MeetingRoomMgo struct {
+	coll *mongo.Collection
+}
+
+func (m *MeetingRoomMgo) Create(ctx context.Context, rooms []*relation.MeetingRoomModel) error {
+	return mgoutil.InsertMany(ctx, m.coll, rooms)
+}
+
+func (m *MeetingRoomMgo) Take(ctx context.Context, roomID string) (*relation.MeetingRoomModel, error) {
+	return mgoutil.FindOne[*relation.MeetingRoomModel](ctx, m.coll, bson.M{"room_id": roomID})
+}
+
+func (m *MeetingRoomMgo) Update(ctx context.Context, roomID string, data map[string]any) (bool, error) {
+	filter := bson.M{"room_id": roomID, "status": relation.MeetingRoomScheduled}
+	result, err := m.coll.UpdateOne(ctx, filter, bson.M{"$set": data})
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (m *MeetingRoomMgo) UpdateStatus(ctx context.Context, roomID string, from int32, to int32) (bool, error) {
+	filter := bson.M{"room_id": roomID, "status": from}
+	result, err := m.coll.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"status": to, "update_time": time.Now()}})
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (m *MeetingRoomMgo) MarkReminded(ctx context.Context, roomID string) (bool, error) {
+	filter := bson.M{"room_id": roomID, "reminded": false}
+	result, err := m.coll.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"reminded": true}})
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (m *MeetingRoomMgo) FindUpcoming(ctx context.Context, userID string, pagination pagination.Pagination) (int64, []*relation.MeetingRoomModel, error) {
+	filter := bson.M{
+		"participant_ids": userID,
+		"status":          bson.M{"$in": []int32{relation.MeetingRoomScheduled, relation.MeetingRoomStarted}},
+	}
+	return mgoutil.FindPage[*relation.MeetingRoomModel](ctx, m.coll, filter, pagination, options.Find().SetSort(bson.M{"start_time": 1}))
+}
+
+func (m *MeetingRoomMgo) FindToRemind(ctx context.Context, before time.Time, limit int64) ([]*relation.MeetingRoomModel, error) {
+	filter := bson.M{"status": relation.MeetingRoomScheduled, "reminded": false, "start_time": bson.M{"$lte": before}}
+	return mgoutil.Find[*relation.MeetingRoomModel](ctx, m.coll, filter, options.Find().SetSort(bson.M{"start_time": 1}).SetLimit(limit))
+}
+
+func (m *MeetingRoomMgo) FindDueStart(ctx context.Context, now time.Time, limit int64) ([]*relation.MeetingRoomModel, error) {
+	filter := bson.M{"status": relation.MeetingRoomScheduled, "start_time": bson.M{"$lte": now}}
+	return mgoutil.Find[*relation.MeetingRoomModel](ctx, m.coll, filter, options.Find().SetSort(bson.M{"start_time": 1}).SetLimit(limit))
+}
+
+func (m *MeetingRoomMgo) FindDueEnd(ctx context.Context, now time.Time, limit int64) ([]*relation.MeetingRoomModel, error) {
+	filter := bson.M{"status": relation.MeetingRoomStarted, "end_time": bson.M{"$lte": now}}
+	return mgoutil.Find[*relation.MeetingRoomModel](ctx, m.coll, filter, options.Find().SetSort(bson.M{"end_time": 1}).SetLimit(limit))
+}
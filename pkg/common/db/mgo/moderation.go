@@ -0,0 +1,82 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewModerationMongo(db *mongo.Database) (relation.ModerationModelInterface, error) {
+	ruleColl := db.Collection("moderation_rule")
+	_, err := ruleColl.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "rule_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	flagColl := db.Collection("moderation_flag")
+	_, err = flagColl.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "send_id", Value: 1}, {Key: "create_time", Value: -1}},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &ModerationMgo{ruleColl: ruleColl, flagColl: flagColl}, nil
+}
+
+type ModerationMgo struct {
+	ruleColl *mongo.Collection
+	flagColl *mongo.Collection
+}
+
+func (m *ModerationMgo) CreateRule(ctx context.Context, rule *relation.ModerationRuleModel) error {
+	return mgoutil.InsertMany(ctx, m.ruleColl, []*relation.ModerationRuleModel{rule})
+}
+
+func (m *ModerationMgo) DeleteRules(ctx context.Context, ruleIDs []string) error {
+	if len(ruleIDs) == 0 {
+		return nil
+	}
+	return mgoutil.DeleteMany(ctx, m.ruleColl, bson.M{"rule_id": bson.M{"$in": ruleIDs}})
+}
+
+func (m *ModerationMgo) FindAllRules(ctx context.Context) ([]*relation.ModerationRuleModel, error) {
+	return mgoutil.Find[*relation.ModerationRuleModel](ctx, m.ruleColl, bson.M{})
+}
+
+func (m *ModerationMgo) PageRules(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.ModerationRuleModel, error) {
+	return mgoutil.FindPage[*relation.ModerationRuleModel](ctx, m.ruleColl, bson.M{}, pagination, options.Find().SetSort(bson.M{"create_time": -1}))
+}
+
+func (m *ModerationMgo) CreateFlag(ctx context.Context, flag *relation.ModerationFlagModel) error {
+	return mgoutil.InsertMany(ctx, m.flagColl, []*relation.ModerationFlagModel{flag})
+}
+
+func (m *ModerationMgo) PageFlags(ctx context.Context, sendID string, pagination pagination.Pagination) (int64, []*relation.ModerationFlagModel, error) {
+	filter := bson.M{}
+	if sendID != "" {
+		filter["send_id"] = sendID
+	}
+	return mgoutil.FindPage[*relation.ModerationFlagModel](ctx, m.flagColl, filter, pagination, options.Find().SetSort(bson.M{"create_time": -1}))
+}
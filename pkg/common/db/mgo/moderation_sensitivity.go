@@ -0,0 +1,73 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewModerationSensitivityMongo(db *mongo.Database) (relation.ModerationSensitivityModelInterface, error) {
+	coll := db.Collection("moderation_sensitivity")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "conversation_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &ModerationSensitivityMgo{coll: coll}, nil
+}
+
+type ModerationSensitivityMgo struct {
+	coll *mongo.Collection
+}
+
+func (m *ModerationSensitivityMgo) Set(ctx context.Context, sensitivity *relation.ModerationSensitivityModel) error {
+	filter := bson.M{"conversation_id": sensitivity.ConversationID}
+	update := bson.M{"$set": bson.M{
+		"level":            sensitivity.Level,
+		"operator_user_id": sensitivity.OperatorUserID,
+		"update_time":      sensitivity.UpdateTime,
+	}}
+	return mgoutil.UpdateOne(ctx, m.coll, filter, update, false, options.Update().SetUpsert(true))
+}
+
+func (m *ModerationSensitivityMgo) Delete(ctx context.Context, conversationID string) error {
+	return mgoutil.DeleteOne(ctx, m.coll, bson.M{"conversation_id": conversationID})
+}
+
+func (m *ModerationSensitivityMgo) Take(ctx context.Context, conversationID string) (*relation.ModerationSensitivityModel, error) {
+	sensitivity, err := mgoutil.FindOne[*relation.ModerationSensitivityModel](ctx, m.coll, bson.M{"conversation_id": conversationID})
+	if err != nil {
+		if errors.Is(errs.Unwrap(err), mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return sensitivity, nil
+}
+
+func (m *ModerationSensitivityMgo) Page(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.ModerationSensitivityModel, error) {
+	return mgoutil.FindPage[*relation.ModerationSensitivityModel](ctx, m.coll, bson.M{}, pagination, options.Find().SetSort(bson.M{"update_time": -1}))
+}
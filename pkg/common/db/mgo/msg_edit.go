@@ -0,0 +1,54 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewMsgEditHistoryMongo(db *mongo.Database) (relation.MsgEditHistoryModelInterface, error) {
+	coll := db.Collection("msg_edit_history")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "conversation_id", Value: 1},
+			{Key: "seq", Value: 1},
+			{Key: "edit_time", Value: 1},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &MsgEditHistoryMgo{coll: coll}, nil
+}
+
+type MsgEditHistoryMgo struct {
+	coll *mongo.Collection
+}
+
+func (m *MsgEditHistoryMgo) Create(ctx context.Context, histories []*relation.MsgEditHistoryModel) error {
+	return mgoutil.InsertMany(ctx, m.coll, histories)
+}
+
+func (m *MsgEditHistoryMgo) Find(ctx context.Context, conversationID string, seq int64) ([]*relation.MsgEditHistoryModel, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "edit_time", Value: 1}})
+	return mgoutil.Find[*relation.MsgEditHistoryModel](ctx, m.coll, bson.M{"conversation_id": conversationID, "seq": seq}, opts)
+}
@@ -0,0 +1,67 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewMsgReactionMongo(db *mongo.Database) (relation.MsgReactionModelInterface, error) {
+	coll := db.Collection("msg_reaction")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "conversation_id", Value: 1},
+			{Key: "seq", Value: 1},
+			{Key: "user_id", Value: 1},
+			{Key: "emoji", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &MsgReactionMgo{coll: coll}, nil
+}
+
+type MsgReactionMgo struct {
+	coll *mongo.Collection
+}
+
+func (m *MsgReactionMgo) Set(ctx context.Context, reaction *relation.MsgReactionModel) error {
+	filter := bson.M{
+		"conversation_id": reaction.ConversationID,
+		"seq":             reaction.Seq,
+		"user_id":         reaction.UserID,
+		"emoji":           reaction.Emoji,
+	}
+	update := bson.M{"$setOnInsert": bson.M{"create_time": reaction.CreateTime}}
+	return mgoutil.UpdateOne(ctx, m.coll, filter, update, false, options.Update().SetUpsert(true))
+}
+
+func (m *MsgReactionMgo) Remove(ctx context.Context, conversationID string, seq int64, userID string, emoji string) error {
+	return mgoutil.DeleteOne(ctx, m.coll, bson.M{"conversation_id": conversationID, "seq": seq, "user_id": userID, "emoji": emoji})
+}
+
+func (m *MsgReactionMgo) Find(ctx context.Context, conversationID string, seq int64) ([]*relation.MsgReactionModel, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "create_time", Value: 1}})
+	return mgoutil.Find[*relation.MsgReactionModel](ctx, m.coll, bson.M{"conversation_id": conversationID, "seq": seq}, opts)
+}
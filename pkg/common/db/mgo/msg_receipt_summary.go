@@ -0,0 +1,74 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewMsgReceiptSummaryMongo(db *mongo.Database) (relation.MsgReceiptSummaryModelInterface, error) {
+	coll := db.Collection("msg_receipt_summary")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "conversation_id", Value: 1},
+			{Key: "user_id", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &MsgReceiptSummaryMgo{coll: coll}, nil
+}
+
+type MsgReceiptSummaryMgo struct {
+	coll *mongo.Collection
+}
+
+func (m *MsgReceiptSummaryMgo) Merge(ctx context.Context, summaries []*relation.MsgReceiptSummaryModel) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+	models := make([]mongo.WriteModel, 0, len(summaries))
+	for _, summary := range summaries {
+		update := bson.M{
+			"$max": bson.M{"has_read_seq": summary.HasReadSeq, "last_read_time": summary.LastReadTime},
+			"$min": bson.M{"first_read_time": summary.FirstReadTime},
+			"$inc": bson.M{"receipt_count": summary.ReceiptCount},
+			"$set": bson.M{"update_time": summary.UpdateTime},
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"conversation_id": summary.ConversationID, "user_id": summary.UserID}).
+			SetUpdate(update).
+			SetUpsert(true))
+	}
+	_, err := m.coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return errs.Wrap(err)
+}
+
+func (m *MsgReceiptSummaryMgo) Find(ctx context.Context, conversationID string, userIDs []string) ([]*relation.MsgReceiptSummaryModel, error) {
+	filter := bson.M{"conversation_id": conversationID}
+	if len(userIDs) > 0 {
+		filter["user_id"] = bson.M{"$in": userIDs}
+	}
+	return mgoutil.Find[*relation.MsgReceiptSummaryModel](ctx, m.coll, filter)
+}
@@ -0,0 +1,64 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewMsgRetentionMongo(db *mongo.Database) (relation.MsgRetentionModelInterface, error) {
+	coll := db.Collection("msg_retention")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "conversation_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &MsgRetentionMgo{coll: coll}, nil
+}
+
+type MsgRetentionMgo struct {
+	coll *mongo.Collection
+}
+
+func (m *MsgRetentionMgo) Set(ctx context.Context, retention *relation.MsgRetentionModel) error {
+	update := bson.M{"$set": bson.M{
+		"group_id":    retention.GroupID,
+		"retain_days": retention.RetainDays,
+		"update_time": retention.UpdateTime,
+	}}
+	return mgoutil.UpdateOne(ctx, m.coll, bson.M{"conversation_id": retention.ConversationID}, update, false, options.Update().SetUpsert(true))
+}
+
+func (m *MsgRetentionMgo) Delete(ctx context.Context, conversationID string) error {
+	return mgoutil.DeleteOne(ctx, m.coll, bson.M{"conversation_id": conversationID})
+}
+
+func (m *MsgRetentionMgo) Find(ctx context.Context, conversationIDs []string) ([]*relation.MsgRetentionModel, error) {
+	return mgoutil.Find[*relation.MsgRetentionModel](ctx, m.coll, bson.M{"conversation_id": bson.M{"$in": conversationIDs}})
+}
+
+func (m *MsgRetentionMgo) Page(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.MsgRetentionModel, error) {
+	return mgoutil.FindPage[*relation.MsgRetentionModel](ctx, m.coll, bson.M{}, pagination)
+}
@@ -0,0 +1,125 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewMsgThreadMongo(db *mongo.Database) (relation.MsgThreadModelInterface, error) {
+	coll := db.Collection("msg_thread")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "conversation_id", Value: 1},
+			{Key: "parent_msg_id", Value: 1},
+			{Key: "seq", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	readColl := db.Collection("msg_thread_read")
+	_, err = readColl.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "conversation_id", Value: 1},
+			{Key: "user_id", Value: 1},
+			{Key: "parent_msg_id", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &MsgThreadMgo{coll: coll, readColl: readColl}, nil
+}
+
+type MsgThreadMgo struct {
+	coll     *mongo.Collection
+	readColl *mongo.Collection
+}
+
+// Create upserts the replies, so a redelivered batch is indexed only once.
+func (m *MsgThreadMgo) Create(ctx context.Context, replies []*relation.MsgThreadModel) error {
+	for _, reply := range replies {
+		filter := bson.M{"conversation_id": reply.ConversationID, "parent_msg_id": reply.ParentMsgID, "seq": reply.Seq}
+		update := bson.M{"$set": bson.M{
+			"client_msg_id": reply.ClientMsgID,
+			"send_id":       reply.SendID,
+			"send_time":     reply.SendTime,
+		}}
+		if err := mgoutil.UpdateOne(ctx, m.coll, filter, update, false, options.Update().SetUpsert(true)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MsgThreadMgo) FindReplies(ctx context.Context, conversationID string, parentMsgID string, pagination pagination.Pagination) (int64, []*relation.MsgThreadModel, error) {
+	filter := bson.M{"conversation_id": conversationID, "parent_msg_id": parentMsgID}
+	return mgoutil.FindPage[*relation.MsgThreadModel](ctx, m.coll, filter, pagination, options.Find().SetSort(bson.M{"seq": 1}))
+}
+
+func (m *MsgThreadMgo) GetMaxSeq(ctx context.Context, conversationID string, parentMsgID string) (int64, error) {
+	seqs, err := mgoutil.Find[int64](ctx, m.coll, bson.M{"conversation_id": conversationID, "parent_msg_id": parentMsgID},
+		options.Find().SetProjection(bson.M{"_id": 0, "seq": 1}).SetSort(bson.M{"seq": -1}).SetLimit(1))
+	if err != nil {
+		return 0, err
+	}
+	if len(seqs) == 0 {
+		return 0, nil
+	}
+	return seqs[0], nil
+}
+
+func (m *MsgThreadMgo) CountUnread(ctx context.Context, conversationID string, parentMsgID string, userID string, hasReadSeq int64) (int64, error) {
+	filter := bson.M{
+		"conversation_id": conversationID,
+		"parent_msg_id":   parentMsgID,
+		"seq":             bson.M{"$gt": hasReadSeq},
+		"send_id":         bson.M{"$ne": userID},
+	}
+	return mgoutil.Count(ctx, m.coll, filter)
+}
+
+func (m *MsgThreadMgo) SetHasReadSeq(ctx context.Context, conversationID string, parentMsgID string, userID string, seq int64) error {
+	filter := bson.M{"conversation_id": conversationID, "user_id": userID, "parent_msg_id": parentMsgID}
+	update := bson.M{"$max": bson.M{"has_read_seq": seq}}
+	return mgoutil.UpdateOne(ctx, m.readColl, filter, update, false, options.Update().SetUpsert(true))
+}
+
+func (m *MsgThreadMgo) GetHasReadSeqs(ctx context.Context, conversationID string, parentMsgIDs []string, userID string) (map[string]int64, error) {
+	reads, err := mgoutil.Find[*relation.MsgThreadReadModel](ctx, m.readColl, bson.M{
+		"conversation_id": conversationID,
+		"user_id":         userID,
+		"parent_msg_id":   bson.M{"$in": parentMsgIDs},
+	})
+	if err != nil {
+		return nil, err
+	}
+	hasReadSeqs := make(map[string]int64, len(reads))
+	for _, read := range reads {
+		hasReadSeqs[read.ParentMsgID] = read.HasReadSeq
+	}
+	return hasReadSeqs, nil
+}
@@ -0,0 +1,145 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewNotificationAccountMongo(db *mongo.Database) (relation.NotificationAccountModelInterface, error) {
+	coll := db.Collection("notification_account")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "user_id", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	optOut := db.Collection("notification_opt_out")
+	_, err = optOut.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "user_id", Value: 1},
+			{Key: "category", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	follower := db.Collection("notification_follower")
+	_, err = follower.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "account_id", Value: 1},
+				{Key: "user_id", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+			},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &NotificationAccountMgo{coll: coll, optOut: optOut, follower: follower}, nil
+}
+
+type NotificationAccountMgo struct {
+	coll     *mongo.Collection
+	optOut   *mongo.Collection
+	follower *mongo.Collection
+}
+
+func (n *NotificationAccountMgo) Upsert(ctx context.Context, account *relation.NotificationAccountModel) error {
+	update := bson.M{
+		"verified":    account.Verified,
+		"category":    account.Category,
+		"update_time": account.UpdateTime,
+	}
+	return mgoutil.UpdateOne(ctx, n.coll, bson.M{"user_id": account.UserID}, bson.M{"$set": update}, false, options.Update().SetUpsert(true))
+}
+
+func (n *NotificationAccountMgo) Find(ctx context.Context, userIDs []string) ([]*relation.NotificationAccountModel, error) {
+	return mgoutil.Find[*relation.NotificationAccountModel](ctx, n.coll, bson.M{"user_id": bson.M{"$in": userIDs}})
+}
+
+func (n *NotificationAccountMgo) Take(ctx context.Context, userID string) (*relation.NotificationAccountModel, error) {
+	return mgoutil.FindOne[*relation.NotificationAccountModel](ctx, n.coll, bson.M{"user_id": userID})
+}
+
+func (n *NotificationAccountMgo) SetQuota(ctx context.Context, userID string, rate int32, dailyCap int32) error {
+	update := bson.M{
+		"rate":        rate,
+		"daily_cap":   dailyCap,
+		"update_time": time.Now(),
+	}
+	return mgoutil.UpdateOne(ctx, n.coll, bson.M{"user_id": userID}, bson.M{"$set": update}, false, options.Update().SetUpsert(true))
+}
+
+func (n *NotificationAccountMgo) SetOptOut(ctx context.Context, userID string, category int32, optOut bool) error {
+	filter := bson.M{"user_id": userID, "category": category}
+	if !optOut {
+		return mgoutil.DeleteOne(ctx, n.optOut, filter)
+	}
+	update := bson.M{"$setOnInsert": bson.M{"create_time": time.Now()}}
+	return mgoutil.UpdateOne(ctx, n.optOut, filter, update, false, options.Update().SetUpsert(true))
+}
+
+func (n *NotificationAccountMgo) FindOptOutCategories(ctx context.Context, userID string) ([]int32, error) {
+	return mgoutil.Find[int32](ctx, n.optOut, bson.M{"user_id": userID}, options.Find().SetProjection(bson.M{"_id": 0, "category": 1}))
+}
+
+func (n *NotificationAccountMgo) FindOptOutUserIDs(ctx context.Context, category int32, userIDs []string) ([]string, error) {
+	filter := bson.M{"category": category, "user_id": bson.M{"$in": userIDs}}
+	return mgoutil.Find[string](ctx, n.optOut, filter, options.Find().SetProjection(bson.M{"_id": 0, "user_id": 1}))
+}
+
+func (n *NotificationAccountMgo) Follow(ctx context.Context, follower *relation.NotificationFollowerModel) error {
+	filter := bson.M{"account_id": follower.AccountID, "user_id": follower.UserID}
+	update := bson.M{"$setOnInsert": bson.M{"create_time": follower.CreateTime}}
+	return mgoutil.UpdateOne(ctx, n.follower, filter, update, false, options.Update().SetUpsert(true))
+}
+
+func (n *NotificationAccountMgo) Unfollow(ctx context.Context, accountID string, userID string) error {
+	return mgoutil.DeleteOne(ctx, n.follower, bson.M{"account_id": accountID, "user_id": userID})
+}
+
+func (n *NotificationAccountMgo) PageFollowerIDs(ctx context.Context, accountID string, pagination pagination.Pagination) (int64, []string, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 0, "user_id": 1}).SetSort(bson.M{"user_id": 1})
+	return mgoutil.FindPage[string](ctx, n.follower, bson.M{"account_id": accountID}, pagination, opts)
+}
+
+func (n *NotificationAccountMgo) PageFollowingIDs(ctx context.Context, userID string, pagination pagination.Pagination) (int64, []string, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 0, "account_id": 1}).SetSort(bson.M{"create_time": -1})
+	return mgoutil.FindPage[string](ctx, n.follower, bson.M{"user_id": userID}, pagination, opts)
+}
+
+func (n *NotificationAccountMgo) CountFollowers(ctx context.Context, accountID string) (int64, error) {
+	return mgoutil.Count(ctx, n.follower, bson.M{"account_id": accountID})
+}
@@ -0,0 +1,84 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewNotificationProfileMongo(db *mongo.Database) (relation.NotificationProfileModelInterface, error) {
+	coll := db.Collection("notification_profile")
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "owner_user_id", Value: 1}, {Key: "conversation_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "owner_user_id", Value: 1}, {Key: "update_time", Value: 1}},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &NotificationProfileMgo{coll: coll}, nil
+}
+
+type NotificationProfileMgo struct {
+	coll *mongo.Collection
+}
+
+func (n *NotificationProfileMgo) Set(ctx context.Context, profile *relation.NotificationProfileModel) error {
+	filter := bson.M{"owner_user_id": profile.OwnerUserID, "conversation_id": profile.ConversationID}
+	update := bson.M{"$set": bson.M{
+		"sound":       profile.Sound,
+		"vibration":   profile.Vibration,
+		"language":    profile.Language,
+		"deleted":     false,
+		"update_time": profile.UpdateTime,
+	}}
+	return mgoutil.UpdateOne(ctx, n.coll, filter, update, false, options.Update().SetUpsert(true))
+}
+
+func (n *NotificationProfileMgo) Delete(ctx context.Context, ownerUserID string, conversationID string, updateTime time.Time) (bool, error) {
+	filter := bson.M{"owner_user_id": ownerUserID, "conversation_id": conversationID, "deleted": false}
+	update := bson.M{"$set": bson.M{"sound": "", "vibration": "", "language": "", "deleted": true, "update_time": updateTime}}
+	result, err := n.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (n *NotificationProfileMgo) FindUpdated(ctx context.Context, ownerUserID string, updateTime time.Time) ([]*relation.NotificationProfileModel, error) {
+	filter := bson.M{"owner_user_id": ownerUserID, "update_time": bson.M{"$gte": updateTime}}
+	return mgoutil.Find[*relation.NotificationProfileModel](ctx, n.coll, filter, options.Find().SetSort(bson.M{"update_time": 1}))
+}
+
+func (n *NotificationProfileMgo) Find(ctx context.Context, userIDs []string, conversationIDs []string) ([]*relation.NotificationProfileModel, error) {
+	filter := bson.M{
+		"owner_user_id":   bson.M{"$in": userIDs},
+		"conversation_id": bson.M{"$in": conversationIDs},
+		"deleted":         false,
+	}
+	return mgoutil.Find[*relation.NotificationProfileModel](ctx, n.coll, filter)
+}
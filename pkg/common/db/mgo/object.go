@@ -27,11 +27,23 @@ import (
 
 func NewS3Mongo(db *mongo.Database) (relation.ObjectInfoModelInterface, error) {
 	coll := db.Collection("s3")
-	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
-		Keys: bson.D{
-			{Key: "name", Value: 1},
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "name", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "key", Value: 1},
+			},
 		},
-		Options: options.Index().SetUnique(true),
 	})
 	if err != nil {
 		return nil, errs.Wrap(err)
@@ -48,6 +60,8 @@ func (o *S3Mongo) SetObject(ctx context.Context, obj *relation.ObjectModel) erro
 	update := bson.M{
 		"name":         obj.Name,
 		"engine":       obj.Engine,
+		"user_id":      obj.UserID,
+		"hash":         obj.Hash,
 		"key":          obj.Key,
 		"size":         obj.Size,
 		"content_type": obj.ContentType,
@@ -67,3 +81,11 @@ func (o *S3Mongo) Take(ctx context.Context, engine string, name string) (*relati
 func (o *S3Mongo) Delete(ctx context.Context, engine string, name string) error {
 	return mgoutil.DeleteOne(ctx, o.coll, bson.M{"name": name, "engine": engine})
 }
+
+func (o *S3Mongo) FindByUserID(ctx context.Context, userID string) ([]*relation.ObjectModel, error) {
+	return mgoutil.Find[*relation.ObjectModel](ctx, o.coll, bson.M{"user_id": userID})
+}
+
+func (o *S3Mongo) FindByKey(ctx context.Context, engine string, key string) ([]*relation.ObjectModel, error) {
+	return mgoutil.Find[*relation.ObjectModel](ctx, o.coll, bson.M{"engine": engine, "key": key})
+}
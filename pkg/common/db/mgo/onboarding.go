@@ -0,0 +1,67 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewOnboardingRuleMongo(db *mongo.Database) (relation.OnboardingRuleModelInterface, error) {
+	coll := db.Collection("onboarding_rule")
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "rule_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "app_id", Value: 1}, {Key: "create_time", Value: -1}},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &OnboardingRuleMgo{coll: coll}, nil
+}
+
+type OnboardingRuleMgo struct {
+	coll *mongo.Collection
+}
+
+func (o *OnboardingRuleMgo) Create(ctx context.Context, rule *relation.OnboardingRuleModel) error {
+	return mgoutil.InsertMany(ctx, o.coll, []*relation.OnboardingRuleModel{rule})
+}
+
+func (o *OnboardingRuleMgo) Delete(ctx context.Context, ruleIDs []string) error {
+	if len(ruleIDs) == 0 {
+		return nil
+	}
+	return mgoutil.DeleteMany(ctx, o.coll, bson.M{"rule_id": bson.M{"$in": ruleIDs}})
+}
+
+func (o *OnboardingRuleMgo) Find(ctx context.Context, appIDs []string) ([]*relation.OnboardingRuleModel, error) {
+	return mgoutil.Find[*relation.OnboardingRuleModel](ctx, o.coll, bson.M{"app_id": bson.M{"$in": appIDs}})
+}
+
+func (o *OnboardingRuleMgo) Page(ctx context.Context, appID string, pagination pagination.Pagination) (int64, []*relation.OnboardingRuleModel, error) {
+	return mgoutil.FindPage[*relation.OnboardingRuleModel](ctx, o.coll, bson.M{"app_id": appID}, pagination, options.Find().SetSort(bson.M{"create_time": -1}))
+}
@@ -0,0 +1,82 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewScheduledMsgMongo(db *mongo.Database) (relation.ScheduledMsgModelInterface, error) {
+	coll := db.Collection("scheduled_msg")
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "schedule_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "schedule_time", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "send_id", Value: 1}, {Key: "schedule_time", Value: 1}},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &ScheduledMsgMgo{coll: coll}, nil
+}
+
+type ScheduledMsgMgo struct {
+	coll *mongo.Collection
+}
+
+func (s *ScheduledMsgMgo) Create(ctx context.Context, msgs []*relation.ScheduledMsgModel) error {
+	return mgoutil.InsertMany(ctx, s.coll, msgs)
+}
+
+func (s *ScheduledMsgMgo) Take(ctx context.Context, scheduleID string) (*relation.ScheduledMsgModel, error) {
+	return mgoutil.FindOne[*relation.ScheduledMsgModel](ctx, s.coll, bson.M{"schedule_id": scheduleID})
+}
+
+func (s *ScheduledMsgMgo) FindBySendID(ctx context.Context, sendID string, status []int32, pagination pagination.Pagination) (int64, []*relation.ScheduledMsgModel, error) {
+	filter := bson.M{"send_id": sendID}
+	if len(status) > 0 {
+		filter["status"] = bson.M{"$in": status}
+	}
+	return mgoutil.FindPage[*relation.ScheduledMsgModel](ctx, s.coll, filter, pagination, options.Find().SetSort(bson.M{"schedule_time": 1}))
+}
+
+func (s *ScheduledMsgMgo) FindDue(ctx context.Context, now time.Time, limit int64) ([]*relation.ScheduledMsgModel, error) {
+	filter := bson.M{"status": relation.ScheduledMsgPending, "schedule_time": bson.M{"$lte": now}}
+	return mgoutil.Find[*relation.ScheduledMsgModel](ctx, s.coll, filter, options.Find().SetSort(bson.M{"schedule_time": 1}).SetLimit(limit))
+}
+
+func (s *ScheduledMsgMgo) UpdateStatus(ctx context.Context, scheduleID string, from int32, to int32, errMsg string) (bool, error) {
+	filter := bson.M{"schedule_id": scheduleID, "status": from}
+	result, err := s.coll.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"status": to, "err": errMsg}})
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return result.MatchedCount > 0, nil
+}
@@ -0,0 +1,68 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewSeqMongo(db *mongo.Database) (relation.SeqModelInterface, error) {
+	coll := db.Collection("seq")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "conversation_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &SeqMgo{coll: coll}, nil
+}
+
+type SeqMgo struct {
+	coll *mongo.Collection
+}
+
+func (s *SeqMgo) Malloc(ctx context.Context, conversationID string, size int64) (int64, error) {
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var seq relation.SeqModel
+	err := s.coll.FindOneAndUpdate(ctx, bson.M{"conversation_id": conversationID}, bson.M{"$inc": bson.M{"max_seq": size}}, opts).Decode(&seq)
+	if err != nil {
+		return 0, errs.Wrap(err)
+	}
+	return seq.MaxSeq - size, nil
+}
+
+func (s *SeqMgo) Raise(ctx context.Context, conversationID string, seq int64) error {
+	return mgoutil.UpdateOne(ctx, s.coll, bson.M{"conversation_id": conversationID}, bson.M{"$max": bson.M{"max_seq": seq}}, false, options.Update().SetUpsert(true))
+}
+
+func (s *SeqMgo) Get(ctx context.Context, conversationID string) (int64, error) {
+	seq, err := mgoutil.FindOne[*relation.SeqModel](ctx, s.coll, bson.M{"conversation_id": conversationID})
+	if err != nil {
+		if errors.Is(errs.Unwrap(err), mongo.ErrNoDocuments) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return seq.MaxSeq, nil
+}
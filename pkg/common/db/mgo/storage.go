@@ -0,0 +1,71 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"github.com/OpenIMSDK/tools/tx"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// NewStorage returns the mongo backend of the relational data, readPref gives the read preference of the heavy
+// reads of each controller.
+func NewStorage(client *mongo.Client, db *mongo.Database, readPref func(controller string) *readpref.ReadPref) relation.Storage {
+	return &Storage{tx: tx.NewMongo(client), db: db, readPref: readPref}
+}
+
+type Storage struct {
+	tx       tx.CtxTx
+	db       *mongo.Database
+	readPref func(controller string) *readpref.ReadPref
+}
+
+func (s *Storage) Tx() tx.CtxTx {
+	return s.tx
+}
+
+func (s *Storage) User() (relation.UserModelInterface, error) {
+	return NewUserMongo(s.db, s.readPref(unrelation.UserReadController))
+}
+
+func (s *Storage) Friend() (relation.FriendModelInterface, error) {
+	return NewFriendMongo(s.db)
+}
+
+func (s *Storage) FriendRequest() (relation.FriendRequestModelInterface, error) {
+	return NewFriendRequestMongo(s.db)
+}
+
+func (s *Storage) Black() (relation.BlackModelInterface, error) {
+	return NewBlackMongo(s.db)
+}
+
+func (s *Storage) Group() (relation.GroupModelInterface, error) {
+	return NewGroupMongo(s.db, s.readPref(unrelation.GroupReadController))
+}
+
+func (s *Storage) GroupMember() (relation.GroupMemberModelInterface, error) {
+	return NewGroupMember(s.db)
+}
+
+func (s *Storage) GroupRequest() (relation.GroupRequestModelInterface, error) {
+	return NewGroupRequestMgo(s.db)
+}
+
+func (s *Storage) Conversation() (relation.ConversationModelInterface, error) {
+	return NewConversationMongo(s.db)
+}
@@ -0,0 +1,67 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewTenantConfigMongo(db *mongo.Database) (relation.TenantConfigModelInterface, error) {
+	coll := db.Collection("tenant_config")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "app_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &TenantConfigMgo{coll: coll}, nil
+}
+
+type TenantConfigMgo struct {
+	coll *mongo.Collection
+}
+
+func (t *TenantConfigMgo) Set(ctx context.Context, tenantConfig *relation.TenantConfigModel) error {
+	update := bson.M{"$set": bson.M{
+		"overrides":   tenantConfig.Overrides,
+		"update_time": tenantConfig.UpdateTime,
+	}}
+	return mgoutil.UpdateOne(ctx, t.coll, bson.M{"app_id": tenantConfig.AppID}, update, false, options.Update().SetUpsert(true))
+}
+
+func (t *TenantConfigMgo) Delete(ctx context.Context, appID string) error {
+	return mgoutil.DeleteOne(ctx, t.coll, bson.M{"app_id": appID})
+}
+
+func (t *TenantConfigMgo) Find(ctx context.Context, appIDs []string) ([]*relation.TenantConfigModel, error) {
+	return mgoutil.Find[*relation.TenantConfigModel](ctx, t.coll, bson.M{"app_id": bson.M{"$in": appIDs}})
+}
+
+func (t *TenantConfigMgo) FindAll(ctx context.Context) ([]*relation.TenantConfigModel, error) {
+	return mgoutil.Find[*relation.TenantConfigModel](ctx, t.coll, bson.M{})
+}
+
+func (t *TenantConfigMgo) Page(ctx context.Context, pagination pagination.Pagination) (int64, []*relation.TenantConfigModel, error) {
+	return mgoutil.FindPage[*relation.TenantConfigModel](ctx, t.coll, bson.M{}, pagination)
+}
@@ -27,9 +27,11 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
-func NewUserMongo(db *mongo.Database) (relation.UserModelInterface, error) {
+// NewUserMongo returns the users, a nil readPref reads everything from the primary.
+func NewUserMongo(db *mongo.Database, readPref *readpref.ReadPref) (relation.UserModelInterface, error) {
 	coll := db.Collection("user")
 	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
 		Keys: bson.D{
@@ -40,11 +42,17 @@ func NewUserMongo(db *mongo.Database) (relation.UserModelInterface, error) {
 	if err != nil {
 		return nil, errs.Wrap(err)
 	}
-	return &UserMgo{coll: coll}, nil
+	readColl := coll
+	if readPref != nil {
+		readColl = db.Collection("user", options.Collection().SetReadPreference(readPref))
+	}
+	return &UserMgo{coll: coll, readColl: readColl}, nil
 }
 
 type UserMgo struct {
 	coll *mongo.Collection
+	// readColl serves the searches and statistics with the user read preference.
+	readColl *mongo.Collection
 }
 
 func (u *UserMgo) Create(ctx context.Context, users []*relation.UserModel) error {
@@ -121,7 +129,7 @@ func (u *UserMgo) PageFindUserWithKeyword(
 	}
 
 	// Perform the paginated search
-	return mgoutil.FindPage[*relation.UserModel](ctx, u.coll, query, pagination)
+	return mgoutil.FindPage[*relation.UserModel](ctx, u.readColl, query, pagination)
 }
 
 func (u *UserMgo) GetAllUserID(ctx context.Context, pagination pagination.Pagination) (int64, []string, error) {
@@ -132,15 +140,22 @@ func (u *UserMgo) Exist(ctx context.Context, userID string) (exist bool, err err
 	return mgoutil.Exist(ctx, u.coll, bson.M{"user_id": userID})
 }
 
+func (u *UserMgo) Delete(ctx context.Context, userID string) (err error) {
+	if err := mgoutil.DeleteMany(ctx, u.coll.Database().Collection("userCommands"), bson.M{"userID": userID}); err != nil {
+		return err
+	}
+	return mgoutil.DeleteOne(ctx, u.coll, bson.M{"user_id": userID})
+}
+
 func (u *UserMgo) GetUserGlobalRecvMsgOpt(ctx context.Context, userID string) (opt int, err error) {
 	return mgoutil.FindOne[int](ctx, u.coll, bson.M{"user_id": userID}, options.FindOne().SetProjection(bson.M{"_id": 0, "global_recv_msg_opt": 1}))
 }
 
 func (u *UserMgo) CountTotal(ctx context.Context, before *time.Time) (count int64, err error) {
 	if before == nil {
-		return mgoutil.Count(ctx, u.coll, bson.M{})
+		return mgoutil.Count(ctx, u.readColl, bson.M{})
 	}
-	return mgoutil.Count(ctx, u.coll, bson.M{"create_time": bson.M{"$lt": before}})
+	return mgoutil.Count(ctx, u.readColl, bson.M{"create_time": bson.M{"$lt": before}})
 }
 
 func (u *UserMgo) AddUserCommand(ctx context.Context, userID string, Type int32, UUID string, value string, ex string) error {
@@ -308,7 +323,7 @@ func (u *UserMgo) CountRangeEverydayTotal(ctx context.Context, start time.Time,
 		Date  string `bson:"_id"`
 		Count int64  `bson:"count"`
 	}
-	items, err := mgoutil.Aggregate[Item](ctx, u.coll, pipeline)
+	items, err := mgoutil.Aggregate[Item](ctx, u.readColl, pipeline)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,88 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewUserAccountMongo(db *mongo.Database) (relation.UserAccountModelInterface, error) {
+	coll := db.Collection("user_account")
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "delete_time", Value: 1}},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &UserAccountMgo{coll: coll}, nil
+}
+
+type UserAccountMgo struct {
+	coll *mongo.Collection
+}
+
+func (u *UserAccountMgo) Set(ctx context.Context, account *relation.UserAccountModel) error {
+	update := bson.M{"$set": bson.M{
+		"status":           account.Status,
+		"reason":           account.Reason,
+		"operator_user_id": account.OperatorUserID,
+		"detached":         account.Detached,
+		"delete_time":      account.DeleteTime,
+		"purge_id":         account.PurgeID,
+		"update_time":      account.UpdateTime,
+	}}
+	return mgoutil.UpdateOne(ctx, u.coll, bson.M{"user_id": account.UserID}, update, false, options.Update().SetUpsert(true))
+}
+
+func (u *UserAccountMgo) Find(ctx context.Context, userIDs []string) ([]*relation.UserAccountModel, error) {
+	return mgoutil.Find[*relation.UserAccountModel](ctx, u.coll, bson.M{"user_id": bson.M{"$in": userIDs}})
+}
+
+func (u *UserAccountMgo) FindDeleting(ctx context.Context, now time.Time, limit int64) ([]*relation.UserAccountModel, error) {
+	filter := bson.M{
+		"status": relation.UserAccountDeleting,
+		"$or": []bson.M{
+			{"detached": false},
+			{"delete_time": bson.M{"$lte": now}},
+		},
+	}
+	return mgoutil.Find[*relation.UserAccountModel](ctx, u.coll, filter, options.Find().SetSort(bson.M{"delete_time": 1}).SetLimit(limit))
+}
+
+func (u *UserAccountMgo) Update(ctx context.Context, userID string, status int32, update map[string]any) (bool, error) {
+	result, err := u.coll.UpdateOne(ctx, bson.M{"user_id": userID, "status": status}, bson.M{"$set": update})
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (u *UserAccountMgo) Delete(ctx context.Context, userID string) error {
+	return mgoutil.DeleteOne(ctx, u.coll, bson.M{"user_id": userID})
+}
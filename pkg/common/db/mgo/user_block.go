@@ -0,0 +1,66 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewUserBlockMongo(db *mongo.Database) (relation.UserBlockModelInterface, error) {
+	coll := db.Collection("user_block")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "owner_user_id", Value: 1}, {Key: "block_user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &UserBlockMgo{coll: coll}, nil
+}
+
+type UserBlockMgo struct {
+	coll *mongo.Collection
+}
+
+func (u *UserBlockMgo) Create(ctx context.Context, blocks []*relation.UserBlockModel) error {
+	for _, block := range blocks {
+		filter := bson.M{"owner_user_id": block.OwnerUserID, "block_user_id": block.BlockUserID}
+		update := bson.M{"$setOnInsert": bson.M{"create_time": block.CreateTime}}
+		if err := mgoutil.UpdateOne(ctx, u.coll, filter, update, false, options.Update().SetUpsert(true)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *UserBlockMgo) Delete(ctx context.Context, ownerUserID string, blockUserIDs []string) error {
+	return mgoutil.DeleteMany(ctx, u.coll, bson.M{"owner_user_id": ownerUserID, "block_user_id": bson.M{"$in": blockUserIDs}})
+}
+
+func (u *UserBlockMgo) FindBlockUserIDs(ctx context.Context, ownerUserID string) ([]string, error) {
+	return mgoutil.Find[string](ctx, u.coll, bson.M{"owner_user_id": ownerUserID}, options.Find().SetProjection(bson.M{"_id": 0, "block_user_id": 1}))
+}
+
+func (u *UserBlockMgo) Page(ctx context.Context, ownerUserID string, pagination pagination.Pagination) (int64, []*relation.UserBlockModel, error) {
+	return mgoutil.FindPage[*relation.UserBlockModel](ctx, u.coll, bson.M{"owner_user_id": ownerUserID}, pagination, options.Find().SetSort(bson.M{"create_time": -1}))
+}
@@ -0,0 +1,75 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewUserMuteMongo(db *mongo.Database) (relation.UserMuteModelInterface, error) {
+	coll := db.Collection("user_mute")
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "owner_user_id", Value: 1}, {Key: "mute_user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "mute_user_id", Value: 1}},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &UserMuteMgo{coll: coll}, nil
+}
+
+type UserMuteMgo struct {
+	coll *mongo.Collection
+}
+
+func (u *UserMuteMgo) Create(ctx context.Context, mutes []*relation.UserMuteModel) error {
+	for _, mute := range mutes {
+		filter := bson.M{"owner_user_id": mute.OwnerUserID, "mute_user_id": mute.MuteUserID}
+		update := bson.M{"$setOnInsert": bson.M{"create_time": mute.CreateTime}}
+		if err := mgoutil.UpdateOne(ctx, u.coll, filter, update, false, options.Update().SetUpsert(true)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *UserMuteMgo) Delete(ctx context.Context, ownerUserID string, muteUserIDs []string) error {
+	return mgoutil.DeleteMany(ctx, u.coll, bson.M{"owner_user_id": ownerUserID, "mute_user_id": bson.M{"$in": muteUserIDs}})
+}
+
+func (u *UserMuteMgo) FindMuteUserIDs(ctx context.Context, ownerUserID string) ([]string, error) {
+	return mgoutil.Find[string](ctx, u.coll, bson.M{"owner_user_id": ownerUserID}, options.Find().SetProjection(bson.M{"_id": 0, "mute_user_id": 1}))
+}
+
+func (u *UserMuteMgo) FindOwnerUserIDs(ctx context.Context, muteUserID string) ([]string, error) {
+	return mgoutil.Find[string](ctx, u.coll, bson.M{"mute_user_id": muteUserID}, options.Find().SetProjection(bson.M{"_id": 0, "owner_user_id": 1}))
+}
+
+func (u *UserMuteMgo) Page(ctx context.Context, ownerUserID string, pagination pagination.Pagination) (int64, []*relation.UserMuteModel, error) {
+	return mgoutil.FindPage[*relation.UserMuteModel](ctx, u.coll, bson.M{"owner_user_id": ownerUserID}, pagination, options.Find().SetSort(bson.M{"create_time": -1}))
+}
@@ -0,0 +1,57 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewUserPrivacyMongo(db *mongo.Database) (relation.UserPrivacyModelInterface, error) {
+	coll := db.Collection("user_privacy")
+	_, err := coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &UserPrivacyMgo{coll: coll}, nil
+}
+
+type UserPrivacyMgo struct {
+	coll *mongo.Collection
+}
+
+func (u *UserPrivacyMgo) Set(ctx context.Context, privacy *relation.UserPrivacyModel) error {
+	update := bson.M{"$set": bson.M{
+		"add_friend_policy": privacy.AddFriendPolicy,
+		"hide_from_id":      privacy.HideFromID,
+		"hide_from_phone":   privacy.HideFromPhone,
+		"hide_from_name":    privacy.HideFromName,
+		"update_time":       privacy.UpdateTime,
+	}}
+	return mgoutil.UpdateOne(ctx, u.coll, bson.M{"user_id": privacy.UserID}, update, false, options.Update().SetUpsert(true))
+}
+
+func (u *UserPrivacyMgo) Find(ctx context.Context, userIDs []string) ([]*relation.UserPrivacyModel, error) {
+	return mgoutil.Find[*relation.UserPrivacyModel](ctx, u.coll, bson.M{"user_id": bson.M{"$in": userIDs}})
+}
@@ -0,0 +1,81 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mgo
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mgoutil"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewUserPurgeMongo(db *mongo.Database) (relation.UserPurgeModelInterface, error) {
+	coll := db.Collection("user_purge")
+	_, err := coll.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "purge_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "status", Value: 1}, {Key: "create_time", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		},
+	})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &UserPurgeMgo{coll: coll}, nil
+}
+
+type UserPurgeMgo struct {
+	coll *mongo.Collection
+}
+
+func (u *UserPurgeMgo) Create(ctx context.Context, purge *relation.UserPurgeModel) error {
+	return mgoutil.InsertMany(ctx, u.coll, []*relation.UserPurgeModel{purge})
+}
+
+func (u *UserPurgeMgo) Take(ctx context.Context, purgeID string) (*relation.UserPurgeModel, error) {
+	return mgoutil.FindOne[*relation.UserPurgeModel](ctx, u.coll, bson.M{"purge_id": purgeID})
+}
+
+func (u *UserPurgeMgo) FindByUserID(ctx context.Context, userID string) ([]*relation.UserPurgeModel, error) {
+	return mgoutil.Find[*relation.UserPurgeModel](ctx, u.coll, bson.M{"user_id": userID}, options.Find().SetSort(bson.M{"create_time": 1}))
+}
+
+func (u *UserPurgeMgo) FindPending(ctx context.Context, limit int64) ([]*relation.UserPurgeModel, error) {
+	filter := bson.M{"status": relation.UserPurgePending}
+	return mgoutil.Find[*relation.UserPurgeModel](ctx, u.coll, filter, options.Find().SetSort(bson.M{"create_time": 1}).SetLimit(limit))
+}
+
+func (u *UserPurgeMgo) UpdateStatus(ctx context.Context, purgeID string, from int32, to int32) (bool, error) {
+	result, err := u.coll.UpdateOne(ctx, bson.M{"purge_id": purgeID, "status": from}, bson.M{"$set": bson.M{"status": to}})
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+func (u *UserPurgeMgo) Finish(ctx context.Context, purgeID string, status int32, steps []*relation.UserPurgeStepModel, finishTime time.Time) error {
+	update := bson.M{"$set": bson.M{"status": status, "steps": steps, "finish_time": finishTime}}
+	return mgoutil.UpdateOne(ctx, u.coll, bson.M{"purge_id": purgeID}, update, false)
+}
@@ -0,0 +1,85 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgsql
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"gorm.io/gorm"
+)
+
+func NewBlackPg(db *gorm.DB) relation.BlackModelInterface {
+	return &BlackPg{db: db}
+}
+
+type BlackPg struct {
+	db *gorm.DB
+}
+
+func (b *BlackPg) table(ctx context.Context) *gorm.DB {
+	return conn(ctx, b.db).Table(blackTable)
+}
+
+func (b *BlackPg) blacks(ctx context.Context, blacks []*relation.BlackModel) *gorm.DB {
+	pairs := make([][]any, 0, len(blacks))
+	for _, black := range blacks {
+		pairs = append(pairs, []any{black.OwnerUserID, black.BlockUserID})
+	}
+	return b.table(ctx).Where("(owner_user_id, block_user_id) IN ?", pairs)
+}
+
+func (b *BlackPg) Create(ctx context.Context, blacks []*relation.BlackModel) (err error) {
+	return create(b.table(ctx), blacks)
+}
+
+func (b *BlackPg) Delete(ctx context.Context, blacks []*relation.BlackModel) (err error) {
+	if len(blacks) == 0 {
+		return nil
+	}
+	return remove[relation.BlackModel](b.blacks(ctx, blacks))
+}
+
+func (b *BlackPg) Find(ctx context.Context, blacks []*relation.BlackModel) (blackList []*relation.BlackModel, err error) {
+	if len(blacks) == 0 {
+		return nil, nil
+	}
+	return find[*relation.BlackModel](b.blacks(ctx, blacks))
+}
+
+func (b *BlackPg) Take(ctx context.Context, ownerUserID, blockUserID string) (black *relation.BlackModel, err error) {
+	return take[relation.BlackModel](b.table(ctx).Where("owner_user_id = ? AND block_user_id = ?", ownerUserID, blockUserID))
+}
+
+func (b *BlackPg) FindOwnerBlacks(ctx context.Context, ownerUserID string, pagination pagination.Pagination) (total int64, blacks []*relation.BlackModel, err error) {
+	return findPage[*relation.BlackModel](b.table(ctx).Where("owner_user_id = ?", ownerUserID), pagination)
+}
+
+func (b *BlackPg) FindOwnerBlackInfos(ctx context.Context, ownerUserID string, userIDs []string) (blacks []*relation.BlackModel, err error) {
+	db := b.table(ctx).Where("owner_user_id = ?", ownerUserID)
+	if len(userIDs) > 0 {
+		db = db.Where("block_user_id IN ?", userIDs)
+	}
+	return find[*relation.BlackModel](db)
+}
+
+func (b *BlackPg) FindBlackUserIDs(ctx context.Context, ownerUserID string) (blackUserIDs []string, err error) {
+	return pluck[string](b.table(ctx).Where("owner_user_id = ?", ownerUserID), "block_user_id")
+}
+
+func (b *BlackPg) FindInWhoseBlacks(ctx context.Context, blockUserID string) (blacks []*relation.BlackModel, err error) {
+	return find[*relation.BlackModel](b.table(ctx).Where("block_user_id = ?", blockUserID))
+}
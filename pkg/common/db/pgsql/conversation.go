@@ -0,0 +1,141 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgsql
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"gorm.io/gorm"
+)
+
+func NewConversationPg(db *gorm.DB) relation.ConversationModelInterface {
+	return &ConversationPg{db: db}
+}
+
+type ConversationPg struct {
+	db *gorm.DB
+}
+
+func (c *ConversationPg) table(ctx context.Context) *gorm.DB {
+	return conn(ctx, c.db).Table(conversationTable)
+}
+
+func (c *ConversationPg) Create(ctx context.Context, conversations []*relation.ConversationModel) (err error) {
+	return create(c.table(ctx), conversations)
+}
+
+func (c *ConversationPg) Delete(ctx context.Context, groupIDs []string) (err error) {
+	return remove[relation.ConversationModel](c.table(ctx).Where("group_id IN ?", groupIDs))
+}
+
+func (c *ConversationPg) UpdateByMap(ctx context.Context, userIDs []string, conversationID string, args map[string]any) (rows int64, err error) {
+	if len(args) == 0 {
+		return 0, nil
+	}
+	res := c.table(ctx).Where("owner_user_id IN ? AND conversation_id = ?", userIDs, conversationID).Updates(args)
+	if res.Error != nil {
+		return 0, errs.Wrap(res.Error)
+	}
+	return res.RowsAffected, nil
+}
+
+func (c *ConversationPg) Update(ctx context.Context, conversation *relation.ConversationModel) (err error) {
+	res := c.table(ctx).
+		Where("owner_user_id = ? AND conversation_id = ?", conversation.OwnerUserID, conversation.ConversationID).
+		Select("*").
+		Updates(conversation)
+	if res.Error != nil {
+		return errs.Wrap(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return errs.Wrap(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+func (c *ConversationPg) Find(ctx context.Context, ownerUserID string, conversationIDs []string) (conversations []*relation.ConversationModel, err error) {
+	return find[*relation.ConversationModel](c.table(ctx).Where("owner_user_id = ? AND conversation_id IN ?", ownerUserID, conversationIDs))
+}
+
+func (c *ConversationPg) FindUserID(ctx context.Context, userIDs []string, conversationIDs []string) ([]string, error) {
+	return pluck[string](c.table(ctx).Where("owner_user_id IN ? AND conversation_id IN ?", userIDs, conversationIDs), "owner_user_id")
+}
+
+func (c *ConversationPg) FindUserIDAllConversationID(ctx context.Context, userID string) ([]string, error) {
+	return pluck[string](c.table(ctx).Where("owner_user_id = ?", userID), "conversation_id")
+}
+
+func (c *ConversationPg) Take(ctx context.Context, userID, conversationID string) (conversation *relation.ConversationModel, err error) {
+	return take[relation.ConversationModel](c.table(ctx).Where("owner_user_id = ? AND conversation_id = ?", userID, conversationID))
+}
+
+func (c *ConversationPg) FindConversationID(ctx context.Context, userID string, conversationIDs []string) (existConversationID []string, err error) {
+	return pluck[string](c.table(ctx).Where("owner_user_id = ? AND conversation_id IN ?", userID, conversationIDs), "conversation_id")
+}
+
+func (c *ConversationPg) FindUserIDAllConversations(ctx context.Context, userID string) (conversations []*relation.ConversationModel, err error) {
+	return find[*relation.ConversationModel](c.table(ctx).Where("owner_user_id = ?", userID))
+}
+
+func (c *ConversationPg) FindRecvMsgUserIDs(ctx context.Context, conversationID string, recvOpts []int) ([]string, error) {
+	db := c.table(ctx).Where("conversation_id = ?", conversationID)
+	if len(recvOpts) > 0 {
+		db = db.Where("recv_msg_opt IN ?", recvOpts)
+	}
+	return pluck[string](db, "owner_user_id")
+}
+
+func (c *ConversationPg) GetUserRecvMsgOpt(ctx context.Context, ownerUserID, conversationID string) (opt int, err error) {
+	return takeColumn[int](c.table(ctx).Where("owner_user_id = ? AND conversation_id = ?", ownerUserID, conversationID), "recv_msg_opt")
+}
+
+func (c *ConversationPg) GetAllConversationIDs(ctx context.Context) ([]string, error) {
+	return pluck[string](c.table(ctx).Distinct("conversation_id"), "conversation_id")
+}
+
+func (c *ConversationPg) GetAllConversationIDsNumber(ctx context.Context) (int64, error) {
+	var count int64
+	if err := c.table(ctx).Distinct("conversation_id").Count(&count).Error; err != nil {
+		return 0, errs.Wrap(err)
+	}
+	return count, nil
+}
+
+func (c *ConversationPg) PageConversationIDs(ctx context.Context, pagination pagination.Pagination) (conversationIDs []string, err error) {
+	skip, limit, ok := offset(pagination)
+	if !ok {
+		return nil, nil
+	}
+	return pluck[string](c.table(ctx).Offset(skip).Limit(limit), "conversation_id")
+}
+
+func (c *ConversationPg) GetConversationsByConversationID(ctx context.Context, conversationIDs []string) ([]*relation.ConversationModel, error) {
+	return find[*relation.ConversationModel](c.table(ctx).Where("conversation_id IN ?", conversationIDs))
+}
+
+func (c *ConversationPg) GetConversationIDsNeedDestruct(ctx context.Context) ([]*relation.ConversationModel, error) {
+	return find[*relation.ConversationModel](c.table(ctx).Where(
+		"is_msg_destruct AND msg_destruct_time <> 0 AND " +
+			"(latest_msg_destruct_time IS NULL OR NOW() > latest_msg_destruct_time + msg_destruct_time * INTERVAL '1 second')",
+	))
+}
+
+func (c *ConversationPg) GetConversationNotReceiveMessageUserIDs(ctx context.Context, conversationID string) ([]string, error) {
+	return pluck[string](c.table(ctx).Where("conversation_id = ? AND recv_msg_opt <> ?", conversationID, constant.ReceiveMessage), "owner_user_id")
+}
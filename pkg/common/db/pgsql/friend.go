@@ -0,0 +1,93 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgsql
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"gorm.io/gorm"
+)
+
+func NewFriendPg(db *gorm.DB) relation.FriendModelInterface {
+	return &FriendPg{db: db}
+}
+
+// FriendPg implements FriendModelInterface using PostgreSQL as the storage backend.
+type FriendPg struct {
+	db *gorm.DB
+}
+
+func (f *FriendPg) table(ctx context.Context) *gorm.DB {
+	return conn(ctx, f.db).Table(friendTable)
+}
+
+func (f *FriendPg) Create(ctx context.Context, friends []*relation.FriendModel) error {
+	return create(f.table(ctx), friends)
+}
+
+func (f *FriendPg) Delete(ctx context.Context, ownerUserID string, friendUserIDs []string) error {
+	return remove[relation.FriendModel](f.table(ctx).Where("owner_user_id = ? AND friend_user_id IN ?", ownerUserID, friendUserIDs))
+}
+
+func (f *FriendPg) UpdateByMap(ctx context.Context, ownerUserID string, friendUserID string, args map[string]any) error {
+	if len(args) == 0 {
+		return nil
+	}
+	return updates(f.table(ctx).Where("owner_user_id = ? AND friend_user_id = ?", ownerUserID, friendUserID), args, true)
+}
+
+func (f *FriendPg) UpdateRemark(ctx context.Context, ownerUserID, friendUserID, remark string) error {
+	return f.UpdateByMap(ctx, ownerUserID, friendUserID, map[string]any{"remark": remark})
+}
+
+func (f *FriendPg) Take(ctx context.Context, ownerUserID, friendUserID string) (*relation.FriendModel, error) {
+	return take[relation.FriendModel](f.table(ctx).Where("owner_user_id = ? AND friend_user_id = ?", ownerUserID, friendUserID))
+}
+
+func (f *FriendPg) FindUserState(ctx context.Context, userID1, userID2 string) ([]*relation.FriendModel, error) {
+	return find[*relation.FriendModel](f.table(ctx).Where(
+		"(owner_user_id = ? AND friend_user_id = ?) OR (owner_user_id = ? AND friend_user_id = ?)",
+		userID1, userID2, userID2, userID1,
+	))
+}
+
+func (f *FriendPg) FindFriends(ctx context.Context, ownerUserID string, friendUserIDs []string) ([]*relation.FriendModel, error) {
+	return find[*relation.FriendModel](f.table(ctx).Where("owner_user_id = ? AND friend_user_id IN ?", ownerUserID, friendUserIDs))
+}
+
+func (f *FriendPg) FindReversalFriends(ctx context.Context, friendUserID string, ownerUserIDs []string) ([]*relation.FriendModel, error) {
+	return find[*relation.FriendModel](f.table(ctx).Where("owner_user_id IN ? AND friend_user_id = ?", ownerUserIDs, friendUserID))
+}
+
+func (f *FriendPg) FindOwnerFriends(ctx context.Context, ownerUserID string, pagination pagination.Pagination) (int64, []*relation.FriendModel, error) {
+	return findPage[*relation.FriendModel](f.table(ctx).Where("owner_user_id = ?", ownerUserID), pagination)
+}
+
+func (f *FriendPg) FindInWhoseFriends(ctx context.Context, friendUserID string, pagination pagination.Pagination) (int64, []*relation.FriendModel, error) {
+	return findPage[*relation.FriendModel](f.table(ctx).Where("friend_user_id = ?", friendUserID), pagination)
+}
+
+func (f *FriendPg) FindFriendUserIDs(ctx context.Context, ownerUserID string) ([]string, error) {
+	return pluck[string](f.table(ctx).Where("owner_user_id = ?", ownerUserID), "friend_user_id")
+}
+
+func (f *FriendPg) UpdateFriends(ctx context.Context, ownerUserID string, friendUserIDs []string, val map[string]any) error {
+	if len(friendUserIDs) == 0 || len(val) == 0 {
+		return nil
+	}
+	return updates(f.table(ctx).Where("owner_user_id = ? AND friend_user_id IN ?", ownerUserID, friendUserIDs), val, false)
+}
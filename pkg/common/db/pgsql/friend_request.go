@@ -0,0 +1,103 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgsql
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"gorm.io/gorm"
+)
+
+func NewFriendRequestPg(db *gorm.DB) relation.FriendRequestModelInterface {
+	return &FriendRequestPg{db: db}
+}
+
+type FriendRequestPg struct {
+	db *gorm.DB
+}
+
+func (f *FriendRequestPg) table(ctx context.Context) *gorm.DB {
+	return conn(ctx, f.db).Table(friendRequestTable)
+}
+
+func (f *FriendRequestPg) pair(ctx context.Context, fromUserID, toUserID string) *gorm.DB {
+	return f.table(ctx).Where("from_user_id = ? AND to_user_id = ?", fromUserID, toUserID)
+}
+
+func (f *FriendRequestPg) FindToUserID(ctx context.Context, toUserID string, pagination pagination.Pagination) (total int64, friendRequests []*relation.FriendRequestModel, err error) {
+	return findPage[*relation.FriendRequestModel](f.table(ctx).Where("to_user_id = ?", toUserID), pagination)
+}
+
+func (f *FriendRequestPg) FindFromUserID(ctx context.Context, fromUserID string, pagination pagination.Pagination) (total int64, friendRequests []*relation.FriendRequestModel, err error) {
+	return findPage[*relation.FriendRequestModel](f.table(ctx).Where("from_user_id = ?", fromUserID), pagination)
+}
+
+func (f *FriendRequestPg) FindBothFriendRequests(ctx context.Context, fromUserID, toUserID string) (friends []*relation.FriendRequestModel, err error) {
+	return find[*relation.FriendRequestModel](f.table(ctx).Where(
+		"(from_user_id = ? AND to_user_id = ?) OR (from_user_id = ? AND to_user_id = ?)",
+		fromUserID, toUserID, toUserID, fromUserID,
+	))
+}
+
+func (f *FriendRequestPg) Create(ctx context.Context, friendRequests []*relation.FriendRequestModel) error {
+	return create(f.table(ctx), friendRequests)
+}
+
+func (f *FriendRequestPg) Delete(ctx context.Context, fromUserID, toUserID string) (err error) {
+	return remove[relation.FriendRequestModel](f.pair(ctx, fromUserID, toUserID))
+}
+
+func (f *FriendRequestPg) UpdateByMap(ctx context.Context, formUserID, toUserID string, args map[string]any) (err error) {
+	if len(args) == 0 {
+		return nil
+	}
+	return updates(f.pair(ctx, formUserID, toUserID), args, true)
+}
+
+func (f *FriendRequestPg) Update(ctx context.Context, friendRequest *relation.FriendRequestModel) (err error) {
+	updater := map[string]any{}
+	if friendRequest.HandleResult != 0 {
+		updater["handle_result"] = friendRequest.HandleResult
+	}
+	if friendRequest.ReqMsg != "" {
+		updater["req_msg"] = friendRequest.ReqMsg
+	}
+	if friendRequest.HandlerUserID != "" {
+		updater["handler_user_id"] = friendRequest.HandlerUserID
+	}
+	if friendRequest.HandleMsg != "" {
+		updater["handle_msg"] = friendRequest.HandleMsg
+	}
+	if !friendRequest.HandleTime.IsZero() {
+		updater["handle_time"] = friendRequest.HandleTime
+	}
+	if friendRequest.Ex != "" {
+		updater["ex"] = friendRequest.Ex
+	}
+	if len(updater) == 0 {
+		return nil
+	}
+	return updates(f.pair(ctx, friendRequest.FromUserID, friendRequest.ToUserID), updater, true)
+}
+
+func (f *FriendRequestPg) Find(ctx context.Context, fromUserID, toUserID string) (friendRequest *relation.FriendRequestModel, err error) {
+	return take[relation.FriendRequestModel](f.pair(ctx, fromUserID, toUserID))
+}
+
+func (f *FriendRequestPg) Take(ctx context.Context, fromUserID, toUserID string) (friendRequest *relation.FriendRequestModel, err error) {
+	return f.Find(ctx, fromUserID, toUserID)
+}
@@ -0,0 +1,79 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgsql
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"gorm.io/gorm"
+)
+
+func NewGroupPg(db *gorm.DB) relation.GroupModelInterface {
+	return &GroupPg{db: db}
+}
+
+type GroupPg struct {
+	db *gorm.DB
+}
+
+func (g *GroupPg) table(ctx context.Context) *gorm.DB {
+	return conn(ctx, g.db).Table(groupTable)
+}
+
+func (g *GroupPg) Create(ctx context.Context, groups []*relation.GroupModel) (err error) {
+	return create(g.table(ctx), groups)
+}
+
+func (g *GroupPg) UpdateStatus(ctx context.Context, groupID string, status int32) (err error) {
+	return g.UpdateMap(ctx, groupID, map[string]any{"status": status})
+}
+
+func (g *GroupPg) UpdateMap(ctx context.Context, groupID string, args map[string]any) (err error) {
+	if len(args) == 0 {
+		return nil
+	}
+	return updates(g.table(ctx).Where("group_id = ?", groupID), args, true)
+}
+
+func (g *GroupPg) Find(ctx context.Context, groupIDs []string) (groups []*relation.GroupModel, err error) {
+	return find[*relation.GroupModel](g.table(ctx).Where("group_id IN ?", groupIDs))
+}
+
+func (g *GroupPg) Take(ctx context.Context, groupID string) (group *relation.GroupModel, err error) {
+	return take[relation.GroupModel](g.table(ctx).Where("group_id = ?", groupID))
+}
+
+func (g *GroupPg) Search(ctx context.Context, keyword string, pagination pagination.Pagination) (total int64, groups []*relation.GroupModel, err error) {
+	return findPage[*relation.GroupModel](g.table(ctx).Where("group_name ~ ?", keyword), pagination)
+}
+
+func (g *GroupPg) CountTotal(ctx context.Context, before *time.Time) (count int64, err error) {
+	db := g.table(ctx)
+	if before != nil {
+		db = db.Where("create_time < ?", *before)
+	}
+	if err := db.Count(&count).Error; err != nil {
+		return 0, errs.Wrap(err)
+	}
+	return count, nil
+}
+
+func (g *GroupPg) CountRangeEverydayTotal(ctx context.Context, start time.Time, end time.Time) (map[string]int64, error) {
+	return countRangeEveryday(g.table(ctx), start, end)
+}
@@ -0,0 +1,103 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgsql
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"gorm.io/gorm"
+)
+
+func NewGroupMemberPg(db *gorm.DB) relation.GroupMemberModelInterface {
+	return &GroupMemberPg{db: db}
+}
+
+type GroupMemberPg struct {
+	db *gorm.DB
+}
+
+func (g *GroupMemberPg) table(ctx context.Context) *gorm.DB {
+	return conn(ctx, g.db).Table(groupMemberTable)
+}
+
+func (g *GroupMemberPg) Create(ctx context.Context, groupMembers []*relation.GroupMemberModel) (err error) {
+	return create(g.table(ctx), groupMembers)
+}
+
+func (g *GroupMemberPg) Delete(ctx context.Context, groupID string, userIDs []string) (err error) {
+	db := g.table(ctx).Where("group_id = ?", groupID)
+	if len(userIDs) > 0 {
+		db = db.Where("user_id IN ?", userIDs)
+	}
+	return remove[relation.GroupMemberModel](db)
+}
+
+func (g *GroupMemberPg) UpdateRoleLevel(ctx context.Context, groupID string, userID string, roleLevel int32) error {
+	return g.Update(ctx, groupID, userID, map[string]any{"role_level": roleLevel})
+}
+
+func (g *GroupMemberPg) Update(ctx context.Context, groupID string, userID string, data map[string]any) (err error) {
+	if len(data) == 0 {
+		return nil
+	}
+	return updates(g.table(ctx).Where("group_id = ? AND user_id = ?", groupID, userID), data, true)
+}
+
+func (g *GroupMemberPg) FindMemberUserID(ctx context.Context, groupID string) (userIDs []string, err error) {
+	return pluck[string](g.table(ctx).Where("group_id = ?", groupID), "user_id")
+}
+
+func (g *GroupMemberPg) Take(ctx context.Context, groupID string, userID string) (groupMember *relation.GroupMemberModel, err error) {
+	return take[relation.GroupMemberModel](g.table(ctx).Where("group_id = ? AND user_id = ?", groupID, userID))
+}
+
+func (g *GroupMemberPg) TakeOwner(ctx context.Context, groupID string) (groupMember *relation.GroupMemberModel, err error) {
+	return take[relation.GroupMemberModel](g.table(ctx).Where("group_id = ? AND role_level = ?", groupID, constant.GroupOwner))
+}
+
+func (g *GroupMemberPg) FindRoleLevelUserIDs(ctx context.Context, groupID string, roleLevel int32) ([]string, error) {
+	return pluck[string](g.table(ctx).Where("group_id = ? AND role_level = ?", groupID, roleLevel), "user_id")
+}
+
+func (g *GroupMemberPg) SearchMember(ctx context.Context, keyword string, groupID string, pagination pagination.Pagination) (total int64, groupList []*relation.GroupMemberModel, err error) {
+	return findPage[*relation.GroupMemberModel](g.table(ctx).Where("group_id = ? AND nickname ~ ?", groupID, keyword), pagination)
+}
+
+func (g *GroupMemberPg) FindUserJoinedGroupID(ctx context.Context, userID string) (groupIDs []string, err error) {
+	return pluck[string](g.table(ctx).Where("user_id = ?", userID), "group_id")
+}
+
+func (g *GroupMemberPg) TakeGroupMemberNum(ctx context.Context, groupID string) (count int64, err error) {
+	if err := g.table(ctx).Where("group_id = ?", groupID).Count(&count).Error; err != nil {
+		return 0, errs.Wrap(err)
+	}
+	return count, nil
+}
+
+func (g *GroupMemberPg) FindUserManagedGroupID(ctx context.Context, userID string) (groupIDs []string, err error) {
+	return pluck[string](g.table(ctx).Where("user_id = ? AND role_level IN ?", userID, []int{constant.GroupOwner, constant.GroupAdmin}), "group_id")
+}
+
+func (g *GroupMemberPg) IsUpdateRoleLevel(data map[string]any) bool {
+	if len(data) == 0 {
+		return false
+	}
+	_, ok := data["role_level"]
+	return ok
+}
@@ -0,0 +1,64 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgsql
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"gorm.io/gorm"
+)
+
+func NewGroupRequestPg(db *gorm.DB) relation.GroupRequestModelInterface {
+	return &GroupRequestPg{db: db}
+}
+
+type GroupRequestPg struct {
+	db *gorm.DB
+}
+
+func (g *GroupRequestPg) table(ctx context.Context) *gorm.DB {
+	return conn(ctx, g.db).Table(groupRequestTable)
+}
+
+func (g *GroupRequestPg) Create(ctx context.Context, groupRequests []*relation.GroupRequestModel) (err error) {
+	return create(g.table(ctx), groupRequests)
+}
+
+func (g *GroupRequestPg) Delete(ctx context.Context, groupID string, userID string) (err error) {
+	return remove[relation.GroupRequestModel](g.table(ctx).Where("group_id = ? AND user_id = ?", groupID, userID))
+}
+
+func (g *GroupRequestPg) UpdateHandler(ctx context.Context, groupID string, userID string, handledMsg string, handleResult int32) (err error) {
+	args := map[string]any{"handled_msg": handledMsg, "handle_result": handleResult}
+	return updates(g.table(ctx).Where("group_id = ? AND user_id = ?", groupID, userID), args, true)
+}
+
+func (g *GroupRequestPg) Take(ctx context.Context, groupID string, userID string) (groupRequest *relation.GroupRequestModel, err error) {
+	return take[relation.GroupRequestModel](g.table(ctx).Where("group_id = ? AND user_id = ?", groupID, userID))
+}
+
+func (g *GroupRequestPg) FindGroupRequests(ctx context.Context, groupID string, userIDs []string) ([]*relation.GroupRequestModel, error) {
+	return find[*relation.GroupRequestModel](g.table(ctx).Where("group_id = ? AND user_id IN ?", groupID, userIDs))
+}
+
+func (g *GroupRequestPg) Page(ctx context.Context, userID string, pagination pagination.Pagination) (total int64, groups []*relation.GroupRequestModel, err error) {
+	return findPage[*relation.GroupRequestModel](g.table(ctx).Where("user_id = ?", userID), pagination)
+}
+
+func (g *GroupRequestPg) PageGroup(ctx context.Context, groupIDs []string, pagination pagination.Pagination) (total int64, groups []*relation.GroupRequestModel, err error) {
+	return findPage[*relation.GroupRequestModel](g.table(ctx).Where("group_id IN ?", groupIDs), pagination)
+}
@@ -0,0 +1,68 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgsql
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mw/specialerror"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const pgConnTimeout = 10 * time.Second
+
+// NewPostgres connects to the configured PostgreSQL and creates the missing tables.
+func NewPostgres(config *config.GlobalConfig) (*gorm.DB, error) {
+	specialerror.AddReplace(gorm.ErrRecordNotFound, errs.ErrRecordNotFound)
+	host, port, err := net.SplitHostPort(config.Postgres.Address)
+	if err != nil {
+		return nil, errs.Wrap(err, "postgres address "+config.Postgres.Address)
+	}
+	sslMode := config.Postgres.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s TimeZone=UTC",
+		host, port, config.Postgres.Username, config.Postgres.Password, config.Postgres.Database, sslMode)
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, errs.Wrap(err, "connect postgres "+config.Postgres.Address)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	sqlDB.SetMaxOpenConns(config.Postgres.MaxOpenConn)
+	sqlDB.SetMaxIdleConns(config.Postgres.MaxIdleConn)
+	sqlDB.SetConnMaxLifetime(time.Second * time.Duration(config.Postgres.MaxLifeTime))
+	ctx, cancel := context.WithTimeout(context.Background(), pgConnTimeout)
+	defer cancel()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return nil, errs.Wrap(err, "ping postgres "+config.Postgres.Address)
+	}
+	for _, stmt := range schema {
+		if err := db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			return nil, errs.Wrap(err, "create postgres schema")
+		}
+	}
+	return db, nil
+}
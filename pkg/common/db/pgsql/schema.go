@@ -0,0 +1,156 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgsql
+
+const (
+	userTable          = "user"
+	userCommandTable   = "user_command"
+	friendTable        = "friend"
+	friendRequestTable = "friend_request"
+	blackTable         = "black"
+	groupTable         = "group"
+	groupMemberTable   = "group_member"
+	groupRequestTable  = "group_request"
+	conversationTable  = "conversation"
+)
+
+// schema creates the tables on startup, the columns are named after the bson tags of the relation models.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS "user" (
+		user_id TEXT PRIMARY KEY,
+		nickname TEXT NOT NULL DEFAULT '',
+		face_url TEXT NOT NULL DEFAULT '',
+		ex TEXT NOT NULL DEFAULT '',
+		app_manger_level INTEGER NOT NULL DEFAULT 0,
+		global_recv_msg_opt INTEGER NOT NULL DEFAULT 0,
+		create_time TIMESTAMPTZ NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS user_create_time_idx ON "user" (create_time)`,
+	`CREATE TABLE IF NOT EXISTS user_command (
+		user_id TEXT NOT NULL,
+		type INTEGER NOT NULL,
+		uuid TEXT NOT NULL,
+		create_time BIGINT NOT NULL,
+		value TEXT NOT NULL DEFAULT '',
+		ex TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE INDEX IF NOT EXISTS user_command_user_id_type_idx ON user_command (user_id, type)`,
+	`CREATE TABLE IF NOT EXISTS friend (
+		owner_user_id TEXT NOT NULL,
+		friend_user_id TEXT NOT NULL,
+		remark TEXT NOT NULL DEFAULT '',
+		create_time TIMESTAMPTZ NOT NULL,
+		add_source INTEGER NOT NULL DEFAULT 0,
+		operator_user_id TEXT NOT NULL DEFAULT '',
+		ex TEXT NOT NULL DEFAULT '',
+		is_pinned BOOLEAN NOT NULL DEFAULT FALSE,
+		PRIMARY KEY (owner_user_id, friend_user_id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS friend_friend_user_id_idx ON friend (friend_user_id)`,
+	`CREATE TABLE IF NOT EXISTS friend_request (
+		from_user_id TEXT NOT NULL,
+		to_user_id TEXT NOT NULL,
+		handle_result INTEGER NOT NULL DEFAULT 0,
+		req_msg TEXT NOT NULL DEFAULT '',
+		create_time TIMESTAMPTZ NOT NULL,
+		handler_user_id TEXT NOT NULL DEFAULT '',
+		handle_msg TEXT NOT NULL DEFAULT '',
+		handle_time TIMESTAMPTZ NOT NULL,
+		ex TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (from_user_id, to_user_id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS friend_request_to_user_id_idx ON friend_request (to_user_id)`,
+	`CREATE TABLE IF NOT EXISTS black (
+		owner_user_id TEXT NOT NULL,
+		block_user_id TEXT NOT NULL,
+		create_time TIMESTAMPTZ NOT NULL,
+		add_source INTEGER NOT NULL DEFAULT 0,
+		operator_user_id TEXT NOT NULL DEFAULT '',
+		ex TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (owner_user_id, block_user_id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS black_block_user_id_idx ON black (block_user_id)`,
+	`CREATE TABLE IF NOT EXISTS "group" (
+		group_id TEXT PRIMARY KEY,
+		group_name TEXT NOT NULL DEFAULT '',
+		notification TEXT NOT NULL DEFAULT '',
+		introduction TEXT NOT NULL DEFAULT '',
+		face_url TEXT NOT NULL DEFAULT '',
+		create_time TIMESTAMPTZ NOT NULL,
+		ex TEXT NOT NULL DEFAULT '',
+		status INTEGER NOT NULL DEFAULT 0,
+		creator_user_id TEXT NOT NULL DEFAULT '',
+		group_type INTEGER NOT NULL DEFAULT 0,
+		need_verification INTEGER NOT NULL DEFAULT 0,
+		look_member_info INTEGER NOT NULL DEFAULT 0,
+		apply_member_friend INTEGER NOT NULL DEFAULT 0,
+		notification_update_time TIMESTAMPTZ NOT NULL,
+		notification_user_id TEXT NOT NULL DEFAULT ''
+	)`,
+	`CREATE INDEX IF NOT EXISTS group_create_time_idx ON "group" (create_time)`,
+	`CREATE TABLE IF NOT EXISTS group_member (
+		group_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		nickname TEXT NOT NULL DEFAULT '',
+		face_url TEXT NOT NULL DEFAULT '',
+		role_level INTEGER NOT NULL DEFAULT 0,
+		join_time TIMESTAMPTZ NOT NULL,
+		join_source INTEGER NOT NULL DEFAULT 0,
+		inviter_user_id TEXT NOT NULL DEFAULT '',
+		operator_user_id TEXT NOT NULL DEFAULT '',
+		mute_end_time TIMESTAMPTZ NOT NULL,
+		ex TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (group_id, user_id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS group_member_user_id_idx ON group_member (user_id)`,
+	`CREATE TABLE IF NOT EXISTS group_request (
+		user_id TEXT NOT NULL,
+		group_id TEXT NOT NULL,
+		handle_result INTEGER NOT NULL DEFAULT 0,
+		req_msg TEXT NOT NULL DEFAULT '',
+		handled_msg TEXT NOT NULL DEFAULT '',
+		req_time TIMESTAMPTZ NOT NULL,
+		handle_user_id TEXT NOT NULL DEFAULT '',
+		handled_time TIMESTAMPTZ NOT NULL,
+		join_source INTEGER NOT NULL DEFAULT 0,
+		inviter_user_id TEXT NOT NULL DEFAULT '',
+		ex TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (group_id, user_id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS group_request_user_id_idx ON group_request (user_id)`,
+	`CREATE TABLE IF NOT EXISTS conversation (
+		owner_user_id TEXT NOT NULL,
+		conversation_id TEXT NOT NULL,
+		conversation_type INTEGER NOT NULL DEFAULT 0,
+		user_id TEXT NOT NULL DEFAULT '',
+		group_id TEXT NOT NULL DEFAULT '',
+		recv_msg_opt INTEGER NOT NULL DEFAULT 0,
+		is_pinned BOOLEAN NOT NULL DEFAULT FALSE,
+		is_private_chat BOOLEAN NOT NULL DEFAULT FALSE,
+		burn_duration INTEGER NOT NULL DEFAULT 0,
+		group_at_type INTEGER NOT NULL DEFAULT 0,
+		attached_info TEXT NOT NULL DEFAULT '',
+		ex TEXT NOT NULL DEFAULT '',
+		max_seq BIGINT NOT NULL DEFAULT 0,
+		min_seq BIGINT NOT NULL DEFAULT 0,
+		create_time TIMESTAMPTZ NOT NULL,
+		is_msg_destruct BOOLEAN NOT NULL DEFAULT FALSE,
+		msg_destruct_time BIGINT NOT NULL DEFAULT 0,
+		latest_msg_destruct_time TIMESTAMPTZ,
+		PRIMARY KEY (owner_user_id, conversation_id)
+	)`,
+	`CREATE INDEX IF NOT EXISTS conversation_conversation_id_idx ON conversation (conversation_id)`,
+	`CREATE INDEX IF NOT EXISTS conversation_group_id_idx ON conversation (group_id)`,
+}
@@ -0,0 +1,67 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgsql
+
+import (
+	"github.com/OpenIMSDK/tools/tx"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"gorm.io/gorm"
+)
+
+// NewStorage returns the PostgreSQL backend of the relational data.
+func NewStorage(db *gorm.DB) relation.Storage {
+	return &Storage{tx: NewTx(db), db: db}
+}
+
+type Storage struct {
+	tx tx.CtxTx
+	db *gorm.DB
+}
+
+func (s *Storage) Tx() tx.CtxTx {
+	return s.tx
+}
+
+func (s *Storage) User() (relation.UserModelInterface, error) {
+	return NewUserPg(s.db), nil
+}
+
+func (s *Storage) Friend() (relation.FriendModelInterface, error) {
+	return NewFriendPg(s.db), nil
+}
+
+func (s *Storage) FriendRequest() (relation.FriendRequestModelInterface, error) {
+	return NewFriendRequestPg(s.db), nil
+}
+
+func (s *Storage) Black() (relation.BlackModelInterface, error) {
+	return NewBlackPg(s.db), nil
+}
+
+func (s *Storage) Group() (relation.GroupModelInterface, error) {
+	return NewGroupPg(s.db), nil
+}
+
+func (s *Storage) GroupMember() (relation.GroupMemberModelInterface, error) {
+	return NewGroupMemberPg(s.db), nil
+}
+
+func (s *Storage) GroupRequest() (relation.GroupRequestModelInterface, error) {
+	return NewGroupRequestPg(s.db), nil
+}
+
+func (s *Storage) Conversation() (relation.ConversationModelInterface, error) {
+	return NewConversationPg(s.db), nil
+}
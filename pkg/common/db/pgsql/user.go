@@ -0,0 +1,210 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgsql
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/user"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"gorm.io/gorm"
+)
+
+func NewUserPg(db *gorm.DB) relation.UserModelInterface {
+	return &UserPg{db: db}
+}
+
+type UserPg struct {
+	db *gorm.DB
+}
+
+type userCommand struct {
+	UserID     string `gorm:"column:user_id"`
+	Type       int32  `gorm:"column:type"`
+	UUID       string `gorm:"column:uuid"`
+	CreateTime int64  `gorm:"column:create_time"`
+	Value      string `gorm:"column:value"`
+	Ex         string `gorm:"column:ex"`
+}
+
+func (u *UserPg) table(ctx context.Context) *gorm.DB {
+	return conn(ctx, u.db).Table(userTable)
+}
+
+func (u *UserPg) commandTable(ctx context.Context) *gorm.DB {
+	return conn(ctx, u.db).Table(userCommandTable)
+}
+
+func (u *UserPg) Create(ctx context.Context, users []*relation.UserModel) error {
+	return create(u.table(ctx), users)
+}
+
+func (u *UserPg) UpdateByMap(ctx context.Context, userID string, args map[string]any) (err error) {
+	if len(args) == 0 {
+		return nil
+	}
+	return updates(u.table(ctx).Where("user_id = ?", userID), args, true)
+}
+
+func (u *UserPg) Find(ctx context.Context, userIDs []string) (users []*relation.UserModel, err error) {
+	return find[*relation.UserModel](u.table(ctx).Where("user_id IN ?", userIDs))
+}
+
+func (u *UserPg) Take(ctx context.Context, userID string) (user *relation.UserModel, err error) {
+	return take[relation.UserModel](u.table(ctx).Where("user_id = ?", userID))
+}
+
+func (u *UserPg) TakeNotification(ctx context.Context, level int64) (user []*relation.UserModel, err error) {
+	return find[*relation.UserModel](u.table(ctx).Where("app_manger_level = ?", level))
+}
+
+func (u *UserPg) TakeByNickname(ctx context.Context, nickname string) (user []*relation.UserModel, err error) {
+	return find[*relation.UserModel](u.table(ctx).Where("nickname = ?", nickname))
+}
+
+func (u *UserPg) Page(ctx context.Context, pagination pagination.Pagination) (count int64, users []*relation.UserModel, err error) {
+	return findPage[*relation.UserModel](u.table(ctx), pagination)
+}
+
+func (u *UserPg) PageFindUser(ctx context.Context, level1 int64, level2 int64, pagination pagination.Pagination) (count int64, users []*relation.UserModel, err error) {
+	return findPage[*relation.UserModel](u.table(ctx).Where("app_manger_level IN ?", []int64{level1, level2}), pagination)
+}
+
+func (u *UserPg) PageFindUserWithKeyword(ctx context.Context, level1 int64, level2 int64, userID string, nickName string, pagination pagination.Pagination) (count int64, users []*relation.UserModel, err error) {
+	db := u.table(ctx).Where("app_manger_level IN ?", []int64{level1, level2})
+	// case-insensitive regular expressions, like the mongo implementation
+	switch {
+	case userID != "" && nickName != "":
+		db = db.Where("user_id ~* ? OR nickname ~* ?", userID, nickName)
+	case userID != "":
+		db = db.Where("user_id ~* ?", userID)
+	case nickName != "":
+		db = db.Where("nickname ~* ?", nickName)
+	}
+	return findPage[*relation.UserModel](db, pagination)
+}
+
+func (u *UserPg) GetAllUserID(ctx context.Context, pagination pagination.Pagination) (int64, []string, error) {
+	return pluckPage[string](u.table(ctx), "user_id", pagination)
+}
+
+func (u *UserPg) Exist(ctx context.Context, userID string) (exist bool, err error) {
+	var count int64
+	if err := u.table(ctx).Where("user_id = ?", userID).Limit(1).Count(&count).Error; err != nil {
+		return false, errs.Wrap(err)
+	}
+	return count > 0, nil
+}
+
+func (u *UserPg) Delete(ctx context.Context, userID string) (err error) {
+	if err := remove[userCommand](u.commandTable(ctx).Where("user_id = ?", userID)); err != nil {
+		return err
+	}
+	return remove[relation.UserModel](u.table(ctx).Where("user_id = ?", userID))
+}
+
+func (u *UserPg) GetUserGlobalRecvMsgOpt(ctx context.Context, userID string) (opt int, err error) {
+	return takeColumn[int](u.table(ctx).Where("user_id = ?", userID), "global_recv_msg_opt")
+}
+
+func (u *UserPg) CountTotal(ctx context.Context, before *time.Time) (count int64, err error) {
+	db := u.table(ctx)
+	if before != nil {
+		db = db.Where("create_time < ?", *before)
+	}
+	if err := db.Count(&count).Error; err != nil {
+		return 0, errs.Wrap(err)
+	}
+	return count, nil
+}
+
+func (u *UserPg) CountRangeEverydayTotal(ctx context.Context, start time.Time, end time.Time) (map[string]int64, error) {
+	return countRangeEveryday(u.table(ctx), start, end)
+}
+
+func (u *UserPg) AddUserCommand(ctx context.Context, userID string, Type int32, UUID string, value string, ex string) error {
+	return errs.Wrap(u.commandTable(ctx).Create(&userCommand{
+		UserID:     userID,
+		Type:       Type,
+		UUID:       UUID,
+		CreateTime: time.Now().Unix(),
+		Value:      value,
+		Ex:         ex,
+	}).Error)
+}
+
+func (u *UserPg) DeleteUserCommand(ctx context.Context, userID string, Type int32, UUID string) error {
+	res := u.commandTable(ctx).Where("user_id = ? AND type = ? AND uuid = ?", userID, Type, UUID).Delete(&userCommand{})
+	if res.Error != nil {
+		return errs.Wrap(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return errs.Wrap(errs.ErrRecordNotFound)
+	}
+	return nil
+}
+
+func (u *UserPg) UpdateUserCommand(ctx context.Context, userID string, Type int32, UUID string, val map[string]any) error {
+	if len(val) == 0 {
+		return nil
+	}
+	res := u.commandTable(ctx).Where("user_id = ? AND type = ? AND uuid = ?", userID, Type, UUID).Updates(val)
+	if res.Error != nil {
+		return errs.Wrap(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return errs.Wrap(errs.ErrRecordNotFound)
+	}
+	return nil
+}
+
+func (u *UserPg) GetUserCommand(ctx context.Context, userID string, Type int32) ([]*user.CommandInfoResp, error) {
+	commands, err := find[*userCommand](u.commandTable(ctx).Where("user_id = ? AND type = ?", userID, Type))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*user.CommandInfoResp, 0, len(commands))
+	for _, command := range commands {
+		res = append(res, &user.CommandInfoResp{
+			Type:       command.Type,
+			Uuid:       command.UUID,
+			Value:      command.Value,
+			CreateTime: command.CreateTime,
+			Ex:         command.Ex,
+		})
+	}
+	return res, nil
+}
+
+func (u *UserPg) GetAllUserCommand(ctx context.Context, userID string) ([]*user.AllCommandInfoResp, error) {
+	commands, err := find[*userCommand](u.commandTable(ctx).Where("user_id = ?", userID))
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*user.AllCommandInfoResp, 0, len(commands))
+	for _, command := range commands {
+		res = append(res, &user.AllCommandInfoResp{
+			Type:       command.Type,
+			Uuid:       command.UUID,
+			Value:      command.Value,
+			CreateTime: command.CreateTime,
+			Ex:         command.Ex,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,154 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgsql
+
+import (
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/pagination"
+	"gorm.io/gorm"
+)
+
+func create[T any](db *gorm.DB, vals []T) error {
+	if len(vals) == 0 {
+		return nil
+	}
+	return errs.Wrap(db.Create(&vals).Error)
+}
+
+func find[T any](db *gorm.DB) ([]T, error) {
+	var res []T
+	if err := db.Find(&res).Error; err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return res, nil
+}
+
+// take returns gorm.ErrRecordNotFound when nothing matches.
+func take[T any](db *gorm.DB) (*T, error) {
+	var res T
+	if err := db.Take(&res).Error; err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &res, nil
+}
+
+func pluck[T any](db *gorm.DB, column string) ([]T, error) {
+	var res []T
+	if err := db.Pluck(column, &res).Error; err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return res, nil
+}
+
+// takeColumn returns column of the first matching row, gorm.ErrRecordNotFound when nothing matches.
+func takeColumn[T any](db *gorm.DB, column string) (res T, err error) {
+	vals, err := pluck[T](db.Limit(1), column)
+	if err != nil {
+		return res, err
+	}
+	if len(vals) == 0 {
+		return res, errs.Wrap(gorm.ErrRecordNotFound)
+	}
+	return vals[0], nil
+}
+
+// updates sets args on the matching rows, notMatchedErr returns gorm.ErrRecordNotFound when nothing matches.
+func updates(db *gorm.DB, args map[string]any, notMatchedErr bool) error {
+	res := db.Updates(args)
+	if res.Error != nil {
+		return errs.Wrap(res.Error)
+	}
+	if notMatchedErr && res.RowsAffected == 0 {
+		return errs.Wrap(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+func remove[T any](db *gorm.DB) error {
+	var model T
+	return errs.Wrap(db.Delete(&model).Error)
+}
+
+func offset(pagination pagination.Pagination) (skip int, limit int, ok bool) {
+	skip = int(pagination.GetPageNumber()-1) * int(pagination.GetShowNumber())
+	if skip < 0 || pagination.GetShowNumber() <= 0 {
+		return 0, 0, false
+	}
+	return skip, int(pagination.GetShowNumber()), true
+}
+
+// findPage counts the matching rows and returns the requested page of them, like mgoutil.FindPage.
+func findPage[T any](db *gorm.DB, pagination pagination.Pagination) (int64, []T, error) {
+	db = db.Session(&gorm.Session{})
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	if count == 0 || pagination == nil {
+		return count, nil, nil
+	}
+	skip, limit, ok := offset(pagination)
+	if !ok || int64(skip) >= count {
+		return count, nil, nil
+	}
+	res, err := find[T](db.Offset(skip).Limit(limit))
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, res, nil
+}
+
+// pluckPage is findPage for a single column.
+func pluckPage[T any](db *gorm.DB, column string, pagination pagination.Pagination) (int64, []T, error) {
+	db = db.Session(&gorm.Session{})
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	if count == 0 || pagination == nil {
+		return count, nil, nil
+	}
+	skip, limit, ok := offset(pagination)
+	if !ok || int64(skip) >= count {
+		return count, nil, nil
+	}
+	res, err := pluck[T](db.Offset(skip).Limit(limit), column)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, res, nil
+}
+
+// countRangeEveryday counts the rows created each day between start and end, keyed by yyyy-mm-dd (UTC).
+func countRangeEveryday(db *gorm.DB, start time.Time, end time.Time) (map[string]int64, error) {
+	var items []struct {
+		Date  string
+		Count int64
+	}
+	err := db.Select("to_char(create_time AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS date, COUNT(*) AS count").
+		Where("create_time >= ? AND create_time < ?", start, end).
+		Group("date").
+		Scan(&items).Error
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	res := make(map[string]int64, len(items))
+	for _, item := range items {
+		res[item.Date] = item.Count
+	}
+	return res, nil
+}
@@ -0,0 +1,433 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package azblob implements the object storage on Azure Blob Storage. Multipart uploads are block blobs whose
+// blocks are uploaded by the clients and committed with Put Block List, every request is authorized with a
+// service SAS signed by the account key.
+package azblob
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3"
+)
+
+const (
+	minPartSize int64 = 1024 * 1024 * 1    // 1MB
+	maxPartSize int64 = 1024 * 1024 * 4000 // 4000MB, the largest block
+	maxNumSize  int64 = 50000
+)
+
+const (
+	blobType       = "BlockBlob"
+	copyPollPeriod = time.Millisecond * 500
+)
+
+const successCode = http.StatusCreated
+
+type Config struct {
+	// Endpoint defaults to https://<AccountName>.blob.core.windows.net.
+	Endpoint    string
+	AccountName string
+	AccountKey  string
+	Container   string
+	PublicRead  bool
+}
+
+func NewAzblob(conf Config) (s3.Interface, error) {
+	if conf.AccountName == "" || conf.Container == "" {
+		return nil, errs.Wrap(errors.New("azure account name or container is empty"))
+	}
+	key, err := base64.StdEncoding.DecodeString(conf.AccountKey)
+	if err != nil {
+		return nil, errs.Wrap(err, "azure account key is not base64")
+	}
+	endpoint := strings.TrimSuffix(conf.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = "https://" + conf.AccountName + ".blob.core.windows.net"
+	}
+	return &Azblob{
+		containerURL: endpoint + "/" + conf.Container + "/",
+		account:      conf.AccountName,
+		container:    conf.Container,
+		key:          key,
+		publicRead:   conf.PublicRead,
+		client:       &http.Client{},
+	}, nil
+}
+
+type Azblob struct {
+	containerURL string
+	account      string
+	container    string
+	key          []byte
+	publicRead   bool
+	client       *http.Client
+}
+
+func (a *Azblob) Engine() string {
+	return "azure"
+}
+
+func (a *Azblob) PartLimit() *s3.PartLimit {
+	return &s3.PartLimit{
+		MinPartSize: minPartSize,
+		MaxPartSize: maxPartSize,
+		MaxNumSize:  maxNumSize,
+	}
+}
+
+// InitiateMultipartUpload only makes up the upload id, Azure has no multipart uploads but the ids of the blocks
+// start with it.
+func (a *Azblob) InitiateMultipartUpload(ctx context.Context, name string) (*s3.InitiateMultipartUploadResult, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &s3.InitiateMultipartUploadResult{
+		UploadID: hex.EncodeToString(id),
+		Bucket:   a.container,
+		Key:      name,
+	}, nil
+}
+
+func (a *Azblob) CompleteMultipartUpload(ctx context.Context, uploadID string, name string, parts []s3.Part) (*s3.CompleteMultipartUploadResult, error) {
+	blockList := blockList{Latest: make([]string, len(parts))}
+	for i, part := range parts {
+		blockList.Latest[i] = blockID(uploadID, part.PartNumber)
+	}
+	body, err := xml.Marshal(&blockList)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	resp, err := a.do(ctx, http.MethodPut, name, "w", url.Values{"comp": {"blocklist"}}, nil, append([]byte(xml.Header), body...))
+	if err != nil {
+		return nil, err
+	}
+	return &s3.CompleteMultipartUploadResult{
+		Location: a.blobURL(name),
+		Bucket:   a.container,
+		Key:      name,
+		ETag:     etag(resp.Header),
+	}, nil
+}
+
+func (a *Azblob) PartSize(ctx context.Context, size int64) (int64, error) {
+	if size <= 0 {
+		return 0, errs.Wrap(errors.New("size must be greater than 0"))
+	}
+	if size > maxPartSize*maxNumSize {
+		return 0, errs.Wrap(errors.New("size must be less than the maximum allowed limit"))
+	}
+	if size <= minPartSize*maxNumSize {
+		return minPartSize, nil
+	}
+	partSize := size / maxNumSize
+	if size%maxNumSize != 0 {
+		partSize++
+	}
+	return partSize, nil
+}
+
+// AuthSign signs one SAS for all the parts, each part is a Put Block of its own block id.
+func (a *Azblob) AuthSign(ctx context.Context, uploadID string, name string, expire time.Duration, partNumbers []int) (*s3.AuthSignResult, error) {
+	result := s3.AuthSignResult{
+		URL:   a.blobURL(name),
+		Query: a.sas(name, "w", time.Now().Add(expire), nil),
+		Parts: make([]s3.SignPart, len(partNumbers)),
+	}
+	for i, partNumber := range partNumbers {
+		result.Parts[i] = s3.SignPart{
+			PartNumber: partNumber,
+			Query:      url.Values{"comp": {"block"}, "blockid": {blockID(uploadID, partNumber)}},
+		}
+	}
+	return &result, nil
+}
+
+func (a *Azblob) PresignedPutObject(ctx context.Context, name string, expire time.Duration) (string, error) {
+	return a.signedURL(name, "cw", time.Now().Add(expire), nil), nil
+}
+
+// PresignedPutHeader is the header Put Blob requires on top of the presigned url.
+func (a *Azblob) PresignedPutHeader() http.Header {
+	return http.Header{"X-Ms-Blob-Type": {blobType}}
+}
+
+func (a *Azblob) DeleteObject(ctx context.Context, name string) error {
+	_, err := a.do(ctx, http.MethodDelete, name, "d", nil, nil, nil)
+	return err
+}
+
+// CopyObject copies within the account, Copy Blob may finish in the background so the copy is waited for.
+func (a *Azblob) CopyObject(ctx context.Context, src string, dst string) (*s3.CopyObjectInfo, error) {
+	header := http.Header{"X-Ms-Copy-Source": {a.signedURL(src, "r", time.Now().Add(time.Hour), nil)}}
+	resp, err := a.do(ctx, http.MethodPut, dst, "cw", nil, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	for status := resp.Header.Get("X-Ms-Copy-Status"); status != "success"; {
+		if status != "pending" {
+			return nil, errs.Wrap(fmt.Errorf("copy %s to %s %s: %s", src, dst, status, resp.Header.Get("X-Ms-Copy-Status-Description")))
+		}
+		select {
+		case <-ctx.Done():
+			return nil, errs.Wrap(ctx.Err())
+		case <-time.After(copyPollPeriod):
+		}
+		if resp, err = a.do(ctx, http.MethodHead, dst, "r", nil, nil, nil); err != nil {
+			return nil, err
+		}
+		status = resp.Header.Get("X-Ms-Copy-Status")
+	}
+	info, err := a.StatObject(ctx, dst)
+	if err != nil {
+		return nil, err
+	}
+	return &s3.CopyObjectInfo{
+		Key:  dst,
+		ETag: info.ETag,
+	}, nil
+}
+
+// StatObject returns the md5 of the blob as ETag like the other engines, Azure computes it for blobs uploaded
+// with a single Put Blob. Committed block lists have no md5 and return the etag of Azure.
+func (a *Azblob) StatObject(ctx context.Context, name string) (*s3.ObjectInfo, error) {
+	resp, err := a.do(ctx, http.MethodHead, name, "r", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	res := &s3.ObjectInfo{Key: name}
+	if md5 := resp.Header.Get("Content-Md5"); md5 != "" {
+		sum, err := base64.StdEncoding.DecodeString(md5)
+		if err != nil {
+			return nil, errs.Wrap(err, "StatObject content-md5 parse error")
+		}
+		res.ETag = hex.EncodeToString(sum)
+	} else if res.ETag = etag(resp.Header); res.ETag == "" {
+		return nil, errs.Wrap(errors.New("StatObject etag not found"))
+	}
+	if res.Size, err = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); err != nil {
+		return nil, errs.Wrap(err, "StatObject content-length parse error")
+	}
+	if res.LastModified, err = time.Parse(http.TimeFormat, resp.Header.Get("Last-Modified")); err != nil {
+		return nil, errs.Wrap(err, "StatObject last-modified parse error")
+	}
+	return res, nil
+}
+
+func (a *Azblob) IsNotFound(err error) bool {
+	if e, ok := errs.Unwrap(err).(*ResponseError); ok {
+		return e.StatusCode == http.StatusNotFound || e.Code == "BlobNotFound"
+	}
+	return false
+}
+
+// AbortMultipartUpload has nothing to do, Azure discards the blocks that are not committed after a week.
+func (a *Azblob) AbortMultipartUpload(ctx context.Context, uploadID string, name string) error {
+	return nil
+}
+
+func (a *Azblob) ListUploadedParts(ctx context.Context, uploadID string, name string, partNumberMarker int, maxParts int) (*s3.ListUploadedPartsResult, error) {
+	resp, err := a.do(ctx, http.MethodGet, name, "r", url.Values{"comp": {"blocklist"}, "blocklisttype": {"uncommitted"}}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var blocks struct {
+		Uncommitted []struct {
+			Name string `xml:"Name"`
+			Size int64  `xml:"Size"`
+		} `xml:"UncommittedBlocks>Block"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&blocks); err != nil {
+		return nil, errs.Wrap(err, "ListUploadedParts decode error")
+	}
+	res := &s3.ListUploadedPartsResult{Key: name, UploadID: uploadID, MaxParts: maxParts}
+	for _, block := range blocks.Uncommitted {
+		partNumber, ok := parseBlockID(uploadID, block.Name)
+		if !ok || partNumber <= partNumberMarker {
+			continue
+		}
+		res.UploadedParts = append(res.UploadedParts, s3.UploadedPart{PartNumber: partNumber, Size: block.Size})
+	}
+	sort.Slice(res.UploadedParts, func(i, j int) bool { return res.UploadedParts[i].PartNumber < res.UploadedParts[j].PartNumber })
+	if maxParts > 0 && len(res.UploadedParts) > maxParts {
+		res.UploadedParts = res.UploadedParts[:maxParts]
+	}
+	if n := len(res.UploadedParts); n > 0 {
+		res.NextPartNumberMarker = res.UploadedParts[n-1].PartNumber
+	}
+	return res, nil
+}
+
+// AccessURL ignores opt.Image, Azure does not process images and the controller resizes them through GetObject and
+// PutObject.
+func (a *Azblob) AccessURL(ctx context.Context, name string, expire time.Duration, opt *s3.AccessURLOption) (string, error) {
+	if a.publicRead {
+		return a.blobURL(name), nil
+	}
+	if expire <= 0 {
+		expire = time.Hour * 24 * 365 * 99 // 99 years
+	} else if expire < time.Second {
+		expire = time.Second
+	}
+	override := make(url.Values)
+	if opt != nil {
+		if opt.ContentType != "" {
+			override.Set("rsct", opt.ContentType)
+		}
+		if opt.Filename != "" {
+			override.Set("rscd", `attachment; filename=`+strconv.Quote(opt.Filename))
+		}
+	}
+	return a.signedURL(name, "r", time.Now().Add(expire), override), nil
+}
+
+func (a *Azblob) GetObject(ctx context.Context, name string) ([]byte, error) {
+	resp, err := a.do(ctx, http.MethodGet, name, "r", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return data, nil
+}
+
+func (a *Azblob) PutObject(ctx context.Context, name string, contentType string, data []byte) error {
+	header := a.PresignedPutHeader()
+	header.Set("X-Ms-Blob-Content-Type", contentType)
+	_, err := a.do(ctx, http.MethodPut, name, "cw", nil, header, data)
+	return err
+}
+
+// FormData returns a presigned Put Blob, Azure has no form uploads. The file is the body of a PUT to URL with
+// Header and FormData is empty.
+func (a *Azblob) FormData(ctx context.Context, name string, size int64, contentType string, duration time.Duration) (*s3.FormData, error) {
+	expires := time.Now().Add(duration)
+	header := a.PresignedPutHeader()
+	if contentType != "" {
+		header.Set("X-Ms-Blob-Content-Type", contentType)
+	}
+	return &s3.FormData{
+		URL:          a.signedURL(name, "cw", expires, nil),
+		Header:       header,
+		FormData:     map[string]string{},
+		Expires:      expires,
+		SuccessCodes: []int{successCode},
+	}, nil
+}
+
+// do sends a request on the blob name authorized by a SAS of permissions valid for a minute.
+func (a *Azblob) do(ctx context.Context, method string, name string, permissions string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	rawURL := a.signedURL(name, permissions, time.Now().Add(time.Minute), nil)
+	if len(query) > 0 {
+		rawURL += "&" + query.Encode()
+	}
+	request, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	resp, err := a.client.Do(request)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if resp.StatusCode/100 == 2 {
+		data, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	e := &ResponseError{StatusCode: resp.StatusCode, Code: resp.Header.Get("X-Ms-Error-Code")}
+	if data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096)); len(data) > 0 {
+		_ = xml.Unmarshal(data, e)
+	}
+	return nil, errs.Wrap(e)
+}
+
+func (a *Azblob) blobURL(name string) string {
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return a.containerURL + strings.Join(segments, "/")
+}
+
+func (a *Azblob) signedURL(name string, permissions string, expiry time.Time, override url.Values) string {
+	return a.blobURL(name) + "?" + a.sas(name, permissions, expiry, override).Encode()
+}
+
+// ResponseError is an error answered by Azure.
+type ResponseError struct {
+	StatusCode int    `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("azure blob %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+type blockList struct {
+	XMLName xml.Name `xml:"BlockList"`
+	Latest  []string `xml:"Latest"`
+}
+
+// blockID is the id of the block of partNumber, the ids of the blocks of a blob must have the same length.
+func blockID(uploadID string, partNumber int) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s_%05d", uploadID, partNumber)))
+}
+
+func parseBlockID(uploadID string, id string) (int, bool) {
+	data, err := base64.StdEncoding.DecodeString(id)
+	if err != nil {
+		return 0, false
+	}
+	prefix := uploadID + "_"
+	if !strings.HasPrefix(string(data), prefix) {
+		return 0, false
+	}
+	partNumber, err := strconv.Atoi(string(data[len(prefix):]))
+	if err != nil {
+		return 0, false
+	}
+	return partNumber, true
+}
+
+func etag(header http.Header) string {
+	return strings.ToLower(strings.Trim(header.Get("ETag"), `"`))
+}
@@ -0,0 +1,63 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azblob
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const sasVersion = "2020-12-06"
+
+// sas returns the query of a service SAS on the blob name. override may set the response headers rscc, rscd,
+// rsce, rscl and rsct, which are part of the signature.
+func (a *Azblob) sas(name string, permissions string, expiry time.Time, override url.Values) url.Values {
+	se := expiry.UTC().Format("2006-01-02T15:04:05Z")
+	stringToSign := strings.Join([]string{
+		permissions,
+		"", // signedStart
+		se,
+		"/blob/" + a.account + "/" + a.container + "/" + name,
+		"", // signedIdentifier
+		"", // signedIP
+		"", // signedProtocol
+		sasVersion,
+		"b",
+		"", // signedSnapshotTime
+		"", // signedEncryptionScope
+		override.Get("rscc"),
+		override.Get("rscd"),
+		override.Get("rsce"),
+		override.Get("rscl"),
+		override.Get("rsct"),
+	}, "\n")
+	h := hmac.New(sha256.New, a.key)
+	h.Write([]byte(stringToSign))
+	query := url.Values{
+		"sv":  {sasVersion},
+		"sr":  {"b"},
+		"sp":  {permissions},
+		"se":  {se},
+		"sig": {base64.StdEncoding.EncodeToString(h.Sum(nil))},
+	}
+	for key, values := range override {
+		query[key] = values
+	}
+	return query
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/thumbnail"
 )
 
 func New(cache cache.S3Cache, impl s3.Interface) *Controller {
@@ -80,6 +81,13 @@ func (c *Controller) StatObject(ctx context.Context, name string) (*s3.ObjectInf
 	return c.cache.GetKey(ctx, c.impl.Engine(), name)
 }
 
+func (c *Controller) DeleteObject(ctx context.Context, name string) error {
+	if err := c.impl.DeleteObject(ctx, name); err != nil {
+		return err
+	}
+	return c.cache.DelS3Key(c.impl.Engine(), name).ExecDel(ctx)
+}
+
 func (c *Controller) GetHashObject(ctx context.Context, hash string) (*s3.ObjectInfo, error) {
 	return c.StatObject(ctx, c.HashPath(hash))
 }
@@ -117,6 +125,13 @@ func (c *Controller) InitiateUpload(ctx context.Context, hash string, size int64
 		if err != nil {
 			return nil, err
 		}
+		part := s3.SignPart{
+			PartNumber: 1,
+			URL:        rawURL,
+		}
+		if putHeader, ok := c.impl.(s3.PutHeader); ok {
+			part.Header = putHeader.PresignedPutHeader()
+		}
 		return &InitiateUploadResult{
 			UploadID: newMultipartUploadID(multipartUploadID{
 				Type: UploadTypePresigned,
@@ -127,12 +142,7 @@ func (c *Controller) InitiateUpload(ctx context.Context, hash string, size int64
 			}),
 			PartSize: partSize,
 			Sign: &s3.AuthSignResult{
-				Parts: []s3.SignPart{
-					{
-						PartNumber: 1,
-						URL:        rawURL,
-					},
-				},
+				Parts: []s3.SignPart{part},
 			},
 		}, nil
 	} else {
@@ -269,10 +279,20 @@ func (c *Controller) IsNotFound(err error) bool {
 	return c.impl.IsNotFound(err) || errs.ErrRecordNotFound.Is(err)
 }
 
+// AccessURL resizes the images of the engines that do not process images themselves, see thumbnailURL.
 func (c *Controller) AccessURL(ctx context.Context, name string, expire time.Duration, opt *s3.AccessURLOption) (string, error) {
 	if opt.Image != nil {
 		opt.Filename = ""
 		opt.ContentType = ""
+		if rw, ok := c.impl.(s3.ObjectReadWriter); ok {
+			resize, err := thumbnail.Normalize(name, opt.Image)
+			if err != nil {
+				return "", err
+			}
+			if resize {
+				return c.thumbnailURL(ctx, rw, name, expire, opt.Image)
+			}
+		}
 	}
 	return c.impl.AccessURL(ctx, name, expire, opt)
 }
@@ -0,0 +1,73 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cont
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3/thumbnail"
+)
+
+// resizing bounds the images resized at once, resizing is cpu bound.
+var resizing = make(chan struct{}, runtime.NumCPU())
+
+// thumbnailURL returns the access url of the thumbnail of name described by opt, the thumbnail is made and stored
+// on first access. Images larger than thumbnail.MaxSize are served as they are.
+func (c *Controller) thumbnailURL(ctx context.Context, rw s3.ObjectReadWriter, name string, expire time.Duration, opt *s3.Image) (string, error) {
+	info, err := c.StatObject(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if info.Size > thumbnail.MaxSize {
+		log.ZWarn(ctx, "image too large to resize", nil, "name", name, "size", info.Size)
+		return c.impl.AccessURL(ctx, name, expire, &s3.AccessURLOption{})
+	}
+	key := thumbnail.Key(info.ETag, opt)
+	if _, err := c.StatObject(ctx, key); err != nil {
+		if !c.IsNotFound(err) {
+			return "", err
+		}
+		if err := c.makeThumbnail(ctx, rw, name, key, opt); err != nil {
+			return "", err
+		}
+	}
+	return c.impl.AccessURL(ctx, key, expire, &s3.AccessURLOption{ContentType: "image/" + opt.Format})
+}
+
+func (c *Controller) makeThumbnail(ctx context.Context, rw s3.ObjectReadWriter, name string, key string, opt *s3.Image) error {
+	select {
+	case resizing <- struct{}{}:
+		defer func() { <-resizing }()
+	case <-ctx.Done():
+		return errs.Wrap(ctx.Err())
+	}
+	data, err := rw.GetObject(ctx, name)
+	if err != nil {
+		return err
+	}
+	data, err = thumbnail.Resize(data, opt)
+	if err != nil {
+		return err
+	}
+	if err := rw.PutObject(ctx, key, "image/"+opt.Format, data); err != nil {
+		return err
+	}
+	return c.cache.DelS3Key(c.impl.Engine(), key).ExecDel(ctx)
+}
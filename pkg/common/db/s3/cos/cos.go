@@ -277,7 +277,7 @@ func (c *Cos) AccessURL(ctx context.Context, name string, expire time.Duration,
 		query := make(url.Values)
 		if opt.Image != nil {
 			// https://cloud.tencent.com/document/product/436/44880
-			style := make([]string, 0, 2)
+			style := make([]string, 0, 3)
 			wh := make([]string, 2)
 			if opt.Image.Width > 0 {
 				wh[0] = strconv.Itoa(opt.Image.Width)
@@ -286,7 +286,7 @@ func (c *Cos) AccessURL(ctx context.Context, name string, expire time.Duration,
 				wh[1] = strconv.Itoa(opt.Image.Height)
 			}
 			if opt.Image.Width > 0 || opt.Image.Height > 0 {
-				style = append(style, strings.Join(wh, "x"))
+				style = append(style, "thumbnail/"+strings.Join(wh, "x"))
 			}
 			switch opt.Image.Format {
 			case
@@ -297,8 +297,11 @@ func (c *Cos) AccessURL(ctx context.Context, name string, expire time.Duration,
 				imageWebp:
 				style = append(style, "format/"+opt.Image.Format)
 			}
+			if opt.Image.Quality > 0 {
+				style = append(style, "quality/"+strconv.Itoa(opt.Image.Quality))
+			}
 			if len(style) > 0 {
-				imageMogr = "imageMogr2/thumbnail/" + strings.Join(style, "/") + "/ignore-error/1"
+				imageMogr = "imageMogr2/" + strings.Join(style, "/") + "/ignore-error/1"
 			}
 		}
 		if opt.ContentType != "" {
@@ -0,0 +1,449 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gcs implements the object storage on Google Cloud Storage through its XML API. Uploads are XML API
+// multipart uploads, an interrupted upload resumes with the parts ListUploadedParts reports, and every request is
+// authorized with a V4 signature of the service account.
+package gcs
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3"
+)
+
+const (
+	minPartSize int64 = 1024 * 1024 * 5        // 5MB
+	maxPartSize int64 = 1024 * 1024 * 1024 * 5 // 5GB
+	maxNumSize  int64 = 10000
+)
+
+const defaultEndpoint = "https://storage.googleapis.com"
+
+const successCode = http.StatusCreated
+
+type Config struct {
+	// Endpoint defaults to https://storage.googleapis.com.
+	Endpoint string
+	Bucket   string
+	// CredentialsFile is the key file of the service account the requests are signed as.
+	CredentialsFile string
+	PublicRead      bool
+}
+
+func NewGcs(conf Config) (s3.Interface, error) {
+	if conf.Bucket == "" {
+		return nil, errs.Wrap(errors.New("gcs bucket is empty"))
+	}
+	data, err := os.ReadFile(conf.CredentialsFile)
+	if err != nil {
+		return nil, errs.Wrap(err, "read gcs credentials file")
+	}
+	clientEmail, key, err := parseCredentials(data)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := strings.TrimSuffix(conf.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errs.Wrap(err, "gcs endpoint parse error")
+	}
+	return &Gcs{
+		endpoint:    endpoint,
+		host:        u.Host,
+		bucket:      conf.Bucket,
+		clientEmail: clientEmail,
+		key:         key,
+		publicRead:  conf.PublicRead,
+		client:      &http.Client{},
+	}, nil
+}
+
+type Gcs struct {
+	endpoint    string
+	host        string
+	bucket      string
+	clientEmail string
+	key         *rsa.PrivateKey
+	publicRead  bool
+	client      *http.Client
+}
+
+func (g *Gcs) Engine() string {
+	return "gcs"
+}
+
+func (g *Gcs) PartLimit() *s3.PartLimit {
+	return &s3.PartLimit{
+		MinPartSize: minPartSize,
+		MaxPartSize: maxPartSize,
+		MaxNumSize:  maxNumSize,
+	}
+}
+
+func (g *Gcs) InitiateMultipartUpload(ctx context.Context, name string) (*s3.InitiateMultipartUploadResult, error) {
+	resp, err := g.do(ctx, http.MethodPost, name, url.Values{"uploads": {""}}, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Bucket   string `xml:"Bucket"`
+		Key      string `xml:"Key"`
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errs.Wrap(err, "InitiateMultipartUpload decode error")
+	}
+	return &s3.InitiateMultipartUploadResult{
+		UploadID: result.UploadID,
+		Bucket:   result.Bucket,
+		Key:      result.Key,
+	}, nil
+}
+
+func (g *Gcs) CompleteMultipartUpload(ctx context.Context, uploadID string, name string, parts []s3.Part) (*s3.CompleteMultipartUploadResult, error) {
+	complete := completeMultipartUpload{Parts: make([]completePart, len(parts))}
+	for i, part := range parts {
+		complete.Parts[i] = completePart{PartNumber: part.PartNumber, ETag: part.ETag}
+	}
+	body, err := xml.Marshal(&complete)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	resp, err := g.do(ctx, http.MethodPost, name, url.Values{"uploadId": {uploadID}}, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	var result s3.CompleteMultipartUploadResult
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errs.Wrap(err, "CompleteMultipartUpload decode error")
+	}
+	result.ETag = trimETag(result.ETag)
+	return &result, nil
+}
+
+func (g *Gcs) PartSize(ctx context.Context, size int64) (int64, error) {
+	if size <= 0 {
+		return 0, errs.Wrap(errors.New("size must be greater than 0"))
+	}
+	if size > maxPartSize*maxNumSize {
+		return 0, errs.Wrap(errors.New("size must be less than the maximum allowed limit"))
+	}
+	if size <= minPartSize*maxNumSize {
+		return minPartSize, nil
+	}
+	partSize := size / maxNumSize
+	if size%maxNumSize != 0 {
+		partSize++
+	}
+	return partSize, nil
+}
+
+// AuthSign signs every part on its own, the signature covers the part number. The query of a part is complete and
+// replaces that of the result.
+func (g *Gcs) AuthSign(ctx context.Context, uploadID string, name string, expire time.Duration, partNumbers []int) (*s3.AuthSignResult, error) {
+	result := s3.AuthSignResult{
+		URL:   g.objectURL(name),
+		Query: url.Values{},
+		Parts: make([]s3.SignPart, len(partNumbers)),
+	}
+	for i, partNumber := range partNumbers {
+		query, err := g.sign(http.MethodPut, name, url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {uploadID}}, nil, expire)
+		if err != nil {
+			return nil, err
+		}
+		result.Parts[i] = s3.SignPart{
+			PartNumber: partNumber,
+			URL:        result.URL,
+			Query:      query,
+		}
+	}
+	return &result, nil
+}
+
+func (g *Gcs) PresignedPutObject(ctx context.Context, name string, expire time.Duration) (string, error) {
+	return g.signedURL(http.MethodPut, name, nil, expire)
+}
+
+func (g *Gcs) DeleteObject(ctx context.Context, name string) error {
+	_, err := g.do(ctx, http.MethodDelete, name, nil, nil, nil)
+	return err
+}
+
+func (g *Gcs) CopyObject(ctx context.Context, src string, dst string) (*s3.CopyObjectInfo, error) {
+	header := http.Header{"X-Goog-Copy-Source": {g.objectPath(src)}}
+	resp, err := g.do(ctx, http.MethodPut, dst, nil, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		ETag string `xml:"ETag"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errs.Wrap(err, "CopyObject decode error")
+	}
+	return &s3.CopyObjectInfo{
+		Key:  dst,
+		ETag: trimETag(result.ETag),
+	}, nil
+}
+
+// StatObject returns the md5 of the object as ETag like the other engines, composed objects such as completed
+// multipart uploads have none and return the etag of GCS.
+func (g *Gcs) StatObject(ctx context.Context, name string) (*s3.ObjectInfo, error) {
+	resp, err := g.do(ctx, http.MethodHead, name, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	res := &s3.ObjectInfo{Key: name}
+	if md5 := googHash(resp.Header, "md5"); md5 != "" {
+		sum, err := base64.StdEncoding.DecodeString(md5)
+		if err != nil {
+			return nil, errs.Wrap(err, "StatObject md5 parse error")
+		}
+		res.ETag = hex.EncodeToString(sum)
+	} else if res.ETag = trimETag(resp.Header.Get("ETag")); res.ETag == "" {
+		return nil, errs.Wrap(errors.New("StatObject etag not found"))
+	}
+	if res.Size, err = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); err != nil {
+		return nil, errs.Wrap(err, "StatObject content-length parse error")
+	}
+	if res.LastModified, err = time.Parse(http.TimeFormat, resp.Header.Get("Last-Modified")); err != nil {
+		return nil, errs.Wrap(err, "StatObject last-modified parse error")
+	}
+	return res, nil
+}
+
+func (g *Gcs) IsNotFound(err error) bool {
+	if e, ok := errs.Unwrap(err).(*ResponseError); ok {
+		return e.StatusCode == http.StatusNotFound || e.Code == "NoSuchKey" || e.Code == "NoSuchUpload"
+	}
+	return false
+}
+
+func (g *Gcs) AbortMultipartUpload(ctx context.Context, uploadID string, name string) error {
+	_, err := g.do(ctx, http.MethodDelete, name, url.Values{"uploadId": {uploadID}}, nil, nil)
+	return err
+}
+
+func (g *Gcs) ListUploadedParts(ctx context.Context, uploadID string, name string, partNumberMarker int, maxParts int) (*s3.ListUploadedPartsResult, error) {
+	query := url.Values{"uploadId": {uploadID}}
+	if partNumberMarker > 0 {
+		query.Set("part-number-marker", strconv.Itoa(partNumberMarker))
+	}
+	if maxParts > 0 {
+		query.Set("max-parts", strconv.Itoa(maxParts))
+	}
+	resp, err := g.do(ctx, http.MethodGet, name, query, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var res s3.ListUploadedPartsResult
+	if err := xml.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, errs.Wrap(err, "ListUploadedParts decode error")
+	}
+	for i := range res.UploadedParts {
+		res.UploadedParts[i].ETag = trimETag(res.UploadedParts[i].ETag)
+	}
+	return &res, nil
+}
+
+// AccessURL ignores opt.Image, GCS does not process images and the controller resizes them through GetObject and
+// PutObject. Signed urls are valid for 7 days at most.
+func (g *Gcs) AccessURL(ctx context.Context, name string, expire time.Duration, opt *s3.AccessURLOption) (string, error) {
+	if g.publicRead {
+		return g.objectURL(name), nil
+	}
+	if expire <= 0 {
+		expire = maxSignExpire
+	} else if expire < time.Second {
+		expire = time.Second
+	}
+	query := make(url.Values)
+	if opt != nil {
+		if opt.ContentType != "" {
+			query.Set("response-content-type", opt.ContentType)
+		}
+		if opt.Filename != "" {
+			query.Set("response-content-disposition", `attachment; filename=`+strconv.Quote(opt.Filename))
+		}
+	}
+	return g.signedURL(http.MethodGet, name, query, expire)
+}
+
+func (g *Gcs) GetObject(ctx context.Context, name string) ([]byte, error) {
+	resp, err := g.do(ctx, http.MethodGet, name, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return data, nil
+}
+
+func (g *Gcs) PutObject(ctx context.Context, name string, contentType string, data []byte) error {
+	_, err := g.do(ctx, http.MethodPut, name, nil, http.Header{"Content-Type": {contentType}}, data)
+	return err
+}
+
+func (g *Gcs) FormData(ctx context.Context, name string, size int64, contentType string, duration time.Duration) (*s3.FormData, error) {
+	// https://cloud.google.com/storage/docs/xml-api/post-object-forms
+	if duration > maxSignExpire {
+		duration = maxSignExpire
+	}
+	now := time.Now().UTC()
+	expires := now.Add(duration)
+	fields := map[string]string{
+		"key":                   name,
+		"x-goog-algorithm":      signAlgorithm,
+		"x-goog-credential":     g.clientEmail + "/" + now.Format("20060102") + "/auto/storage/goog4_request",
+		"x-goog-date":           now.Format("20060102T150405Z"),
+		"success_action_status": strconv.Itoa(successCode),
+	}
+	if contentType != "" {
+		fields["Content-Type"] = contentType
+	}
+	conditions := []any{map[string]string{"bucket": g.bucket}}
+	for key, value := range fields {
+		conditions = append(conditions, map[string]string{key: value})
+	}
+	if size > 0 {
+		conditions = append(conditions, []any{"content-length-range", 0, size})
+	}
+	policy, signature, err := g.policy(expires, conditions)
+	if err != nil {
+		return nil, err
+	}
+	fields["policy"] = policy
+	fields["x-goog-signature"] = signature
+	return &s3.FormData{
+		URL:          g.endpoint + "/" + escape(g.bucket),
+		File:         "file",
+		FormData:     fields,
+		Expires:      expires,
+		SuccessCodes: []int{successCode},
+	}, nil
+}
+
+// do sends a request on the object name signed for a minute.
+func (g *Gcs) do(ctx context.Context, method string, name string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	signed, err := g.sign(method, name, query, header, time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequestWithContext(ctx, method, g.objectURL(name)+"?"+signed.Encode(), bytes.NewReader(body))
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	resp, err := g.client.Do(request)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if resp.StatusCode/100 == 2 {
+		data, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	e := &ResponseError{StatusCode: resp.StatusCode}
+	if data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096)); len(data) > 0 {
+		_ = xml.Unmarshal(data, e)
+	}
+	return nil, errs.Wrap(e)
+}
+
+func (g *Gcs) signedURL(method string, name string, query url.Values, expire time.Duration) (string, error) {
+	signed, err := g.sign(method, name, query, nil, expire)
+	if err != nil {
+		return "", err
+	}
+	return g.objectURL(name) + "?" + signed.Encode(), nil
+}
+
+// objectPath is the escaped path of the object name, the canonical uri of its signatures.
+func (g *Gcs) objectPath(name string) string {
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = escape(segment)
+	}
+	return "/" + escape(g.bucket) + "/" + strings.Join(segments, "/")
+}
+
+func (g *Gcs) objectURL(name string) string {
+	return g.endpoint + g.objectPath(name)
+}
+
+// ResponseError is an error answered by GCS.
+type ResponseError struct {
+	StatusCode int    `xml:"-"`
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("gcs %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name       `xml:"CompleteMultipartUpload"`
+	Parts   []completePart `xml:"Part"`
+}
+
+type completePart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// googHash returns the hash of type typ from the x-goog-hash headers.
+func googHash(header http.Header, typ string) string {
+	for _, values := range header.Values("X-Goog-Hash") {
+		for _, value := range strings.Split(values, ",") {
+			if value = strings.TrimSpace(value); strings.HasPrefix(value, typ+"=") {
+				return strings.TrimPrefix(value, typ+"=")
+			}
+		}
+	}
+	return ""
+}
+
+func trimETag(etag string) string {
+	return strings.ToLower(strings.Trim(etag, `"`))
+}
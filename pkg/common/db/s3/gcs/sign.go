@@ -0,0 +1,183 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcs
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+)
+
+const (
+	signAlgorithm   = "GOOG4-RSA-SHA256"
+	unsignedPayload = "UNSIGNED-PAYLOAD"
+	// maxSignExpire is the longest a V4 signature is valid.
+	maxSignExpire = time.Hour * 24 * 7
+)
+
+// credentials are the fields used of a service account key file.
+type credentials struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+}
+
+func parseCredentials(data []byte) (string, *rsa.PrivateKey, error) {
+	var creds credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return "", nil, errs.Wrap(err, "gcs credentials are not a service account key")
+	}
+	if creds.ClientEmail == "" {
+		return "", nil, errs.Wrap(errors.New("gcs credentials have no client_email"))
+	}
+	block, _ := pem.Decode([]byte(creds.PrivateKey))
+	if block == nil {
+		return "", nil, errs.Wrap(errors.New("gcs credentials have no private_key"))
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if key, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return "", nil, errs.Wrap(err, "gcs private key parse error")
+		}
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return "", nil, errs.Wrap(errors.New("gcs private key is not an RSA key"))
+	}
+	return creds.ClientEmail, rsaKey, nil
+}
+
+// sign returns query with the V4 signature of a request of method on the object name, header holds the headers
+// sent along other than host and is signed too. expire is cut to the 7 days a signature is valid at most.
+func (g *Gcs) sign(method string, name string, query url.Values, header http.Header, expire time.Duration) (url.Values, error) {
+	if expire > maxSignExpire {
+		expire = maxSignExpire
+	}
+	now := time.Now().UTC()
+	datetime := now.Format("20060102T150405Z")
+	scope := now.Format("20060102") + "/auto/storage/goog4_request"
+	headers := map[string]string{"host": g.host}
+	for key, values := range header {
+		headers[strings.ToLower(key)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for key := range headers {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, key := range names {
+		canonicalHeaders.WriteString(key + ":" + headers[key] + "\n")
+	}
+	signed := make(url.Values, len(query)+6)
+	for key, values := range query {
+		signed[key] = values
+	}
+	signed.Set("X-Goog-Algorithm", signAlgorithm)
+	signed.Set("X-Goog-Credential", g.clientEmail+"/"+scope)
+	signed.Set("X-Goog-Date", datetime)
+	signed.Set("X-Goog-Expires", strconv.FormatInt(int64(expire/time.Second), 10))
+	signed.Set("X-Goog-SignedHeaders", strings.Join(names, ";"))
+	canonicalRequest := strings.Join([]string{
+		method,
+		g.objectPath(name),
+		canonicalQuery(signed),
+		canonicalHeaders.String(),
+		strings.Join(names, ";"),
+		unsignedPayload,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	signature, err := g.signString(strings.Join([]string{signAlgorithm, datetime, scope, hex.EncodeToString(requestHash[:])}, "\n"))
+	if err != nil {
+		return nil, err
+	}
+	signed.Set("X-Goog-Signature", signature)
+	return signed, nil
+}
+
+// signString signs s with the private key of the service account and returns the signature in hex.
+func (g *Gcs) signString(s string) (string, error) {
+	digest := sha256.Sum256([]byte(s))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, g.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+	return hex.EncodeToString(signature), nil
+}
+
+// policy returns the base64 policy document of a V4 form upload and its signature.
+func (g *Gcs) policy(expires time.Time, conditions []any) (string, string, error) {
+	data, err := json.Marshal(map[string]any{
+		"expiration": expires.UTC().Format("2006-01-02T15:04:05Z"),
+		"conditions": conditions,
+	})
+	if err != nil {
+		return "", "", errs.Wrap(err, "Marshal json error")
+	}
+	policy := base64.StdEncoding.EncodeToString(data)
+	signature, err := g.signString(policy)
+	if err != nil {
+		return "", "", err
+	}
+	return policy, signature, nil
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return escape(keys[i]) < escape(keys[j]) })
+	var params []string
+	for _, key := range keys {
+		values := make([]string, len(query[key]))
+		for i, value := range query[key] {
+			values[i] = escape(value)
+		}
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(params, escape(key)+"="+value)
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// escape percent-encodes everything but the unreserved characters of RFC 3986.
+func escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{c})))
+	}
+	return b.String()
+}
@@ -0,0 +1,189 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"crypto/hmac"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+)
+
+var (
+	errSignature = errors.New("invalid or expired signature")
+	errTooLarge  = errors.New("body too large")
+)
+
+// NewHandler returns the handler the api serves Path with.
+func NewHandler(conf Config, apiURL string, secret string) (http.Handler, error) {
+	return newLocal(conf, apiURL, secret)
+}
+
+// ServeHTTP serves the urls signed by the engine. GET and HEAD download an object, PUT uploads an object or, with
+// uploadId and partNumber, a part of a multipart upload.
+func (l *Local) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, Path)
+	method := r.Method
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	if method != http.MethodGet && method != http.MethodPut {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	query := r.URL.Query()
+	if err := l.verify(method, name, query); err != nil {
+		writeError(w, err)
+		return
+	}
+	if method == http.MethodGet {
+		l.serveObject(w, r, name, query)
+		return
+	}
+	etag, err := l.put(r, name, query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("ETag", `"`+etag+`"`)
+	w.WriteHeader(successCode)
+}
+
+func (l *Local) verify(method string, name string, query url.Values) error {
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return errSignature
+	}
+	if !hmac.Equal([]byte(query.Get("sign")), []byte(l.signature(method, name, query))) {
+		return errSignature
+	}
+	return nil
+}
+
+func (l *Local) serveObject(w http.ResponseWriter, r *http.Request, name string, query url.Values) {
+	p, err := l.objectPath(name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		writeError(w, wrapErr(err))
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		writeError(w, ErrNotFound)
+		return
+	}
+	contentType := query.Get("contentType")
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(name))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	// Objects are served from the origin of the api, only media is shown inline and nothing is sniffed.
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if filename := query.Get("filename"); filename != "" {
+		w.Header().Set("Content-Disposition", `attachment; filename=`+strconv.Quote(filename))
+	} else if !isMedia(contentType) {
+		w.Header().Set("Content-Disposition", "attachment")
+	}
+	http.ServeContent(w, r, "", info.ModTime(), f)
+}
+
+// put stores the body as the object name or as a part of the upload of query, and returns its md5.
+func (l *Local) put(r *http.Request, name string, query url.Values) (string, error) {
+	limit := maxPartSize
+	var dst string
+	if uploadID := query.Get("uploadId"); uploadID != "" {
+		dir, err := l.uploadPath(uploadID)
+		if err != nil {
+			return "", err
+		}
+		if _, err := os.Stat(dir); err != nil {
+			return "", wrapErr(err)
+		}
+		partNumber, err := strconv.Atoi(query.Get("partNumber"))
+		if err != nil || partNumber < 1 || int64(partNumber) > maxNumSize {
+			return "", errs.ErrArgs.Wrap("invalid part number")
+		}
+		dst = filepath.Join(dir, strconv.Itoa(partNumber))
+	} else {
+		p, err := l.objectPath(name)
+		if err != nil {
+			return "", err
+		}
+		dst = p
+		if size, err := strconv.ParseInt(query.Get("size"), 10, 64); err == nil && size > 0 {
+			limit = size
+		}
+	}
+	if r.ContentLength > limit {
+		return "", errTooLarge
+	}
+	if l.quota > 0 && r.ContentLength < 0 {
+		return "", errs.ErrArgs.Wrap("content length is required")
+	}
+	return l.store(dst, r.ContentLength, func(w io.Writer) error {
+		n, err := io.Copy(w, io.LimitReader(r.Body, limit+1))
+		if err != nil {
+			return errs.Wrap(err)
+		}
+		if n > limit {
+			return errTooLarge
+		}
+		return nil
+	})
+}
+
+// isMedia reports whether contentType is an image, audio or video type a browser can not run scripts from.
+func isMedia(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "image/svg+xml" {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "image/") || strings.HasPrefix(mediaType, "audio/") || strings.HasPrefix(mediaType, "video/")
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch unwrap := errs.Unwrap(err); {
+	case unwrap == errSignature:
+		code = http.StatusForbidden
+	case unwrap == ErrNotFound:
+		code = http.StatusNotFound
+	case unwrap == ErrInvalidName, errs.ErrArgs.Is(err):
+		code = http.StatusBadRequest
+	case unwrap == ErrQuotaExceeded:
+		code = http.StatusInsufficientStorage
+	case unwrap == errTooLarge:
+		code = http.StatusRequestEntityTooLarge
+	}
+	http.Error(w, err.Error(), code)
+}
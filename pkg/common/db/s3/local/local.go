@@ -0,0 +1,624 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package local implements the object storage on the local filesystem for single node deployments. The files are
+// uploaded to and downloaded from the api, which serves the urls signed here under Path, so the api and the third
+// rpc must share Dir and the secret.
+package local
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3"
+)
+
+const (
+	minPartSize int64 = 1024 * 1024 * 1    // 1MB
+	maxPartSize int64 = 1024 * 1024 * 1024 // 1GB
+	maxNumSize  int64 = 10000
+)
+
+const (
+	// Path is where the api serves the objects.
+	Path = "/local_object/"
+
+	objectDir = "objects"
+	uploadDir = "uploads"
+	tempDir   = "tmp"
+
+	// uploadExpire is how long an upload that is neither completed nor aborted is kept.
+	uploadExpire = time.Hour * 24 * 7
+	// accessExpire replaces an access url expiry of zero.
+	accessExpire = time.Hour * 24 * 365 * 99 // 99 years
+	// usageSyncInterval is how often the usage is walked again, to pick up the files written or removed by the
+	// other process sharing Dir.
+	usageSyncInterval = time.Minute * 5
+)
+
+const successCode = 200
+
+var (
+	ErrNotFound      = errors.New("object not found")
+	ErrInvalidName   = errors.New("invalid object name")
+	ErrQuotaExceeded = errors.New("disk quota exceeded")
+)
+
+type Config struct {
+	Dir string
+	// QuotaMB is the most megabytes stored, uploads in progress included, 0 is unlimited.
+	QuotaMB int64
+}
+
+func NewLocal(conf Config, apiURL string, secret string) (s3.Interface, error) {
+	return newLocal(conf, apiURL, secret)
+}
+
+func newLocal(conf Config, apiURL string, secret string) (*Local, error) {
+	if conf.Dir == "" {
+		return nil, errs.Wrap(errors.New("local object dir is empty"))
+	}
+	if apiURL == "" {
+		return nil, errs.Wrap(errors.New("api url is empty"))
+	}
+	if secret == "" {
+		return nil, errs.Wrap(errors.New("secret is empty"))
+	}
+	dir, err := filepath.Abs(conf.Dir)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	for _, sub := range []string{objectDir, uploadDir, tempDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, errs.Wrap(err)
+		}
+	}
+	return &Local{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(apiURL, "/") + Path,
+		secret:  []byte(secret),
+		quota:   conf.QuotaMB * 1024 * 1024,
+	}, nil
+}
+
+type Local struct {
+	dir     string
+	baseURL string
+	secret  []byte
+	quota   int64
+	usage   diskUsage
+}
+
+// diskUsage is the number of bytes stored under Dir, kept up to date by the writes and removals of this process.
+type diskUsage struct {
+	lock   sync.Mutex
+	used   int64
+	synced time.Time
+}
+
+func (l *Local) Engine() string {
+	return "local"
+}
+
+func (l *Local) PartLimit() *s3.PartLimit {
+	return &s3.PartLimit{
+		MinPartSize: minPartSize,
+		MaxPartSize: maxPartSize,
+		MaxNumSize:  maxNumSize,
+	}
+}
+
+// InitiateMultipartUpload creates the directory the parts are uploaded to, and removes those of uploads older
+// than uploadExpire.
+func (l *Local) InitiateMultipartUpload(ctx context.Context, name string) (*s3.InitiateMultipartUploadResult, error) {
+	if _, err := l.objectPath(name); err != nil {
+		return nil, err
+	}
+	l.cleanUploads()
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	uploadID := hex.EncodeToString(id)
+	if err := os.Mkdir(filepath.Join(l.dir, uploadDir, uploadID), 0o755); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &s3.InitiateMultipartUploadResult{
+		UploadID: uploadID,
+		Key:      name,
+	}, nil
+}
+
+// CompleteMultipartUpload joins the parts into the object, every part must match the md5 it is completed with.
+func (l *Local) CompleteMultipartUpload(ctx context.Context, uploadID string, name string, parts []s3.Part) (*s3.CompleteMultipartUploadResult, error) {
+	dir, err := l.uploadPath(uploadID)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := l.objectPath(name)
+	if err != nil {
+		return nil, err
+	}
+	partsSize, err := l.dirUsage(dir)
+	if err != nil {
+		return nil, err
+	}
+	etag, err := l.store(dst, 0, func(w io.Writer) error {
+		for _, part := range parts {
+			if err := copyPart(w, filepath.Join(dir, strconv.Itoa(part.PartNumber)), part.ETag); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.RemoveAll(dir); err == nil {
+		l.addUsage(-partsSize)
+	}
+	if info, err := os.Stat(dst); err == nil {
+		l.addUsage(info.Size())
+	}
+	return &s3.CompleteMultipartUploadResult{
+		Location: l.baseURL + escapeName(name),
+		Key:      name,
+		ETag:     etag,
+	}, nil
+}
+
+func (l *Local) PartSize(ctx context.Context, size int64) (int64, error) {
+	if size <= 0 {
+		return 0, errs.Wrap(errors.New("size must be greater than 0"))
+	}
+	if size > maxPartSize*maxNumSize {
+		return 0, errs.Wrap(errors.New("size must be less than the maximum allowed limit"))
+	}
+	if size <= minPartSize*maxNumSize {
+		return minPartSize, nil
+	}
+	partSize := size / maxNumSize
+	if size%maxNumSize != 0 {
+		partSize++
+	}
+	return partSize, nil
+}
+
+func (l *Local) AuthSign(ctx context.Context, uploadID string, name string, expire time.Duration, partNumbers []int) (*s3.AuthSignResult, error) {
+	result := s3.AuthSignResult{
+		URL:   l.baseURL + escapeName(name),
+		Query: url.Values{},
+		Parts: make([]s3.SignPart, len(partNumbers)),
+	}
+	expires := time.Now().Add(expire)
+	for i, partNumber := range partNumbers {
+		result.Parts[i] = s3.SignPart{
+			PartNumber: partNumber,
+			URL:        result.URL,
+			Query:      l.sign("PUT", name, url.Values{"uploadId": {uploadID}, "partNumber": {strconv.Itoa(partNumber)}}, expires),
+		}
+	}
+	return &result, nil
+}
+
+func (l *Local) PresignedPutObject(ctx context.Context, name string, expire time.Duration) (string, error) {
+	if _, err := l.objectPath(name); err != nil {
+		return "", err
+	}
+	return l.signedURL("PUT", name, nil, time.Now().Add(expire)), nil
+}
+
+func (l *Local) DeleteObject(ctx context.Context, name string) error {
+	p, err := l.objectPath(name)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(p)
+	if err != nil {
+		return wrapErr(err)
+	}
+	if err := os.Remove(p); err != nil {
+		return wrapErr(err)
+	}
+	l.addUsage(-info.Size())
+	return nil
+}
+
+func (l *Local) CopyObject(ctx context.Context, src string, dst string) (*s3.CopyObjectInfo, error) {
+	srcPath, err := l.objectPath(src)
+	if err != nil {
+		return nil, err
+	}
+	dstPath, err := l.objectPath(dst)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(srcPath)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+	etag, err := l.store(dstPath, info.Size(), func(w io.Writer) error {
+		return copyPart(w, srcPath, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &s3.CopyObjectInfo{
+		Key:  dst,
+		ETag: etag,
+	}, nil
+}
+
+// StatObject hashes the file for its ETag, the controller caches the result.
+func (l *Local) StatObject(ctx context.Context, name string) (*s3.ObjectInfo, error) {
+	p, err := l.objectPath(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &s3.ObjectInfo{
+		ETag:         hex.EncodeToString(h.Sum(nil)),
+		Key:          name,
+		Size:         info.Size(),
+		LastModified: info.ModTime(),
+	}, nil
+}
+
+func (l *Local) IsNotFound(err error) bool {
+	return errs.Unwrap(err) == ErrNotFound
+}
+
+func (l *Local) AbortMultipartUpload(ctx context.Context, uploadID string, name string) error {
+	dir, err := l.uploadPath(uploadID)
+	if err != nil {
+		return err
+	}
+	return l.removeUpload(dir)
+}
+
+func (l *Local) ListUploadedParts(ctx context.Context, uploadID string, name string, partNumberMarker int, maxParts int) (*s3.ListUploadedPartsResult, error) {
+	dir, err := l.uploadPath(uploadID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+	res := &s3.ListUploadedPartsResult{Key: name, UploadID: uploadID, MaxParts: maxParts}
+	for _, entry := range entries {
+		partNumber, err := strconv.Atoi(entry.Name())
+		if err != nil || partNumber <= partNumberMarker {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, wrapErr(err)
+		}
+		res.UploadedParts = append(res.UploadedParts, s3.UploadedPart{PartNumber: partNumber, LastModified: info.ModTime(), Size: info.Size()})
+	}
+	sort.Slice(res.UploadedParts, func(i, j int) bool { return res.UploadedParts[i].PartNumber < res.UploadedParts[j].PartNumber })
+	if maxParts > 0 && len(res.UploadedParts) > maxParts {
+		res.UploadedParts = res.UploadedParts[:maxParts]
+	}
+	if n := len(res.UploadedParts); n > 0 {
+		res.NextPartNumberMarker = res.UploadedParts[n-1].PartNumber
+	}
+	return res, nil
+}
+
+// AccessURL ignores opt.Image, the controller resizes images through GetObject and PutObject.
+func (l *Local) AccessURL(ctx context.Context, name string, expire time.Duration, opt *s3.AccessURLOption) (string, error) {
+	if _, err := l.objectPath(name); err != nil {
+		return "", err
+	}
+	if expire <= 0 {
+		expire = accessExpire
+	} else if expire < time.Second {
+		expire = time.Second
+	}
+	query := make(url.Values)
+	if opt != nil {
+		if opt.ContentType != "" {
+			query.Set("contentType", opt.ContentType)
+		}
+		if opt.Filename != "" {
+			query.Set("filename", opt.Filename)
+		}
+	}
+	return l.signedURL("GET", name, query, time.Now().Add(expire)), nil
+}
+
+func (l *Local) GetObject(ctx context.Context, name string) ([]byte, error) {
+	p, err := l.objectPath(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, wrapErr(err)
+	}
+	return data, nil
+}
+
+// PutObject ignores contentType, the api serves objects with the type of their extension.
+func (l *Local) PutObject(ctx context.Context, name string, contentType string, data []byte) error {
+	p, err := l.objectPath(name)
+	if err != nil {
+		return err
+	}
+	_, err = l.store(p, int64(len(data)), func(w io.Writer) error {
+		_, err := w.Write(data)
+		return errs.Wrap(err)
+	})
+	return err
+}
+
+// FormData returns a presigned PUT, the api does not take form uploads. The file is the body of a PUT to URL and
+// FormData is empty.
+func (l *Local) FormData(ctx context.Context, name string, size int64, contentType string, duration time.Duration) (*s3.FormData, error) {
+	if _, err := l.objectPath(name); err != nil {
+		return nil, err
+	}
+	expires := time.Now().Add(duration)
+	query := make(url.Values)
+	if size > 0 {
+		query.Set("size", strconv.FormatInt(size, 10))
+	}
+	return &s3.FormData{
+		URL:          l.signedURL("PUT", name, query, expires),
+		FormData:     map[string]string{},
+		Expires:      expires,
+		SuccessCodes: []int{successCode},
+	}, nil
+}
+
+// objectPath returns the file of the object name. Names are slash separated relative paths without empty, "." or
+// ".." elements, so that no name leaves the object directory.
+func (l *Local) objectPath(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, "\\\x00") {
+		return "", errs.Wrap(ErrInvalidName, name)
+	}
+	for _, elem := range strings.Split(name, "/") {
+		if elem == "" || elem == "." || elem == ".." {
+			return "", errs.Wrap(ErrInvalidName, name)
+		}
+	}
+	return filepath.Join(l.dir, objectDir, filepath.FromSlash(name)), nil
+}
+
+func (l *Local) uploadPath(uploadID string) (string, error) {
+	if id, err := hex.DecodeString(uploadID); err != nil || len(id) != 16 {
+		return "", errs.Wrap(ErrNotFound, "upload "+uploadID)
+	}
+	return filepath.Join(l.dir, uploadDir, uploadID), nil
+}
+
+// writeFile writes p through a temporary file renamed into place once write succeeds, and returns its md5.
+func (l *Local) writeFile(p string, write func(w io.Writer) error) (string, error) {
+	f, err := os.CreateTemp(filepath.Join(l.dir, tempDir), "")
+	if err != nil {
+		return "", errs.Wrap(err)
+	}
+	defer os.Remove(f.Name())
+	h := md5.New()
+	err = write(io.MultiWriter(f, h))
+	if closeErr := f.Close(); err == nil {
+		err = errs.Wrap(closeErr)
+	}
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return "", errs.Wrap(err)
+	}
+	if err := os.Rename(f.Name(), p); err != nil {
+		return "", errs.Wrap(err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// store writes p like writeFile and counts size bytes more in the usage, less the size of the file it replaces.
+func (l *Local) store(p string, size int64, write func(w io.Writer) error) (string, error) {
+	if info, err := os.Stat(p); err == nil {
+		size -= info.Size()
+	}
+	if err := l.reserve(size); err != nil {
+		return "", err
+	}
+	etag, err := l.writeFile(p, write)
+	if err != nil {
+		l.addUsage(-size)
+		return "", err
+	}
+	return etag, nil
+}
+
+// reserve counts size more bytes in the usage, it reports ErrQuotaExceeded when they do not fit in the quota.
+func (l *Local) reserve(size int64) error {
+	if l.quota <= 0 {
+		return nil
+	}
+	l.usage.lock.Lock()
+	defer l.usage.lock.Unlock()
+	if l.usage.synced.IsZero() || time.Since(l.usage.synced) > usageSyncInterval {
+		used, err := dirSize(l.dir)
+		if err != nil {
+			return err
+		}
+		l.usage.used, l.usage.synced = used, time.Now()
+	}
+	if l.usage.used+size > l.quota {
+		return errs.Wrap(ErrQuotaExceeded, fmt.Sprintf("used %d, quota %d, size %d", l.usage.used, l.quota, size))
+	}
+	l.usage.used += size
+	return nil
+}
+
+func (l *Local) addUsage(delta int64) {
+	if l.quota <= 0 {
+		return
+	}
+	l.usage.lock.Lock()
+	defer l.usage.lock.Unlock()
+	l.usage.used += delta
+	if l.usage.used < 0 {
+		l.usage.used = 0
+	}
+}
+
+// dirUsage returns the size of dir, 0 when there is no quota to count it in.
+func (l *Local) dirUsage(dir string) (int64, error) {
+	if l.quota <= 0 {
+		return 0, nil
+	}
+	return dirSize(dir)
+}
+
+func (l *Local) removeUpload(dir string) error {
+	size, err := l.dirUsage(dir)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return errs.Wrap(err)
+	}
+	l.addUsage(-size)
+	return nil
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, errs.Wrap(err)
+}
+
+func (l *Local) cleanUploads() {
+	entries, err := os.ReadDir(filepath.Join(l.dir, uploadDir))
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil && time.Since(info.ModTime()) > uploadExpire {
+			_ = l.removeUpload(filepath.Join(l.dir, uploadDir, entry.Name()))
+		}
+	}
+}
+
+// sign returns query with the expiry and the signature of a request of method on the object name.
+func (l *Local) sign(method string, name string, query url.Values, expires time.Time) url.Values {
+	signed := make(url.Values, len(query)+2)
+	for key, values := range query {
+		signed[key] = values
+	}
+	signed.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	signed.Set("sign", l.signature(method, name, signed))
+	return signed
+}
+
+func (l *Local) signature(method string, name string, query url.Values) string {
+	unsigned := make(url.Values, len(query))
+	for key, values := range query {
+		if key != "sign" {
+			unsigned[key] = values
+		}
+	}
+	h := hmac.New(sha256.New, l.secret)
+	h.Write([]byte(method + "\n" + name + "\n" + unsigned.Encode()))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (l *Local) signedURL(method string, name string, query url.Values, expires time.Time) string {
+	return l.baseURL + escapeName(name) + "?" + l.sign(method, name, query, expires).Encode()
+}
+
+// copyPart copies the file p to w, it fails when etag is set and is not the md5 of the file.
+func copyPart(w io.Writer, p string, etag string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return wrapErr(err)
+	}
+	defer f.Close()
+	h := md5.New()
+	if _, err := io.Copy(io.MultiWriter(w, h), f); err != nil {
+		return errs.Wrap(err)
+	}
+	if etag != "" && !strings.EqualFold(strings.Trim(etag, `"`), hex.EncodeToString(h.Sum(nil))) {
+		return errs.ErrArgs.Wrap(fmt.Sprintf("part %s md5 mismatching", filepath.Base(p)))
+	}
+	return nil
+}
+
+func escapeName(name string) string {
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func wrapErr(err error) error {
+	if os.IsNotExist(err) {
+		return errs.Wrap(ErrNotFound, err.Error())
+	}
+	return errs.Wrap(err)
+}
@@ -0,0 +1,225 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package local
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3"
+)
+
+func newTestLocal(t *testing.T, quotaMB int64) *Local {
+	l, err := newLocal(Config{Dir: t.TempDir(), QuotaMB: quotaMB}, "http://127.0.0.1:10002/", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func do(l *Local, method string, rawURL string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, rawURL, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	l.ServeHTTP(w, req)
+	return w
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestPresignedPutAndAccess(t *testing.T) {
+	ctx := context.Background()
+	l := newTestLocal(t, 0)
+	rawURL, err := l.PresignedPutObject(ctx, "dir/a b.txt", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := do(l, http.MethodPut, rawURL, "hello"); w.Code != successCode {
+		t.Fatalf("put = %d %s", w.Code, w.Body)
+	}
+	info, err := l.StatObject(ctx, "dir/a b.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size != 5 || info.ETag != md5Hex("hello") {
+		t.Fatalf("info = %+v", info)
+	}
+	// The signature covers the method.
+	if w := do(l, http.MethodGet, rawURL, ""); w.Code != http.StatusForbidden {
+		t.Fatalf("get with put url = %d", w.Code)
+	}
+	rawURL, err = l.AccessURL(ctx, "dir/a b.txt", time.Minute, &s3.AccessURLOption{Filename: "a.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := do(l, http.MethodGet, rawURL, "")
+	if w.Code != http.StatusOK || w.Body.String() != "hello" || !strings.Contains(w.Header().Get("Content-Disposition"), "a.txt") {
+		t.Fatalf("get = %d %s %v", w.Code, w.Body, w.Header())
+	}
+	if w := do(l, http.MethodGet, strings.Replace(rawURL, "a%20b.txt", "c.txt", 1), ""); w.Code != http.StatusForbidden {
+		t.Fatalf("get other name = %d", w.Code)
+	}
+}
+
+func TestMultipartUpload(t *testing.T) {
+	ctx := context.Background()
+	l := newTestLocal(t, 0)
+	upload, err := l.InitiateMultipartUpload(ctx, "hash/x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign, err := l.AuthSign(ctx, upload.UploadID, upload.Key, time.Minute, []int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := []string{"part one,", "part two"}
+	for i, part := range sign.Parts {
+		if w := do(l, http.MethodPut, part.URL+"?"+part.Query.Encode(), parts[i]); w.Code != successCode {
+			t.Fatalf("put part %d = %d %s", part.PartNumber, w.Code, w.Body)
+		}
+	}
+	list, err := l.ListUploadedParts(ctx, upload.UploadID, upload.Key, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.UploadedParts) != 2 || list.NextPartNumberMarker != 2 {
+		t.Fatalf("parts = %+v", list)
+	}
+	if _, err := l.CompleteMultipartUpload(ctx, upload.UploadID, upload.Key, []s3.Part{{PartNumber: 1, ETag: md5Hex(parts[1])}, {PartNumber: 2, ETag: md5Hex(parts[1])}}); err == nil {
+		t.Fatal("completed with a wrong part md5")
+	}
+	res, err := l.CompleteMultipartUpload(ctx, upload.UploadID, upload.Key, []s3.Part{{PartNumber: 1, ETag: md5Hex(parts[0])}, {PartNumber: 2, ETag: md5Hex(parts[1])}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.ETag != md5Hex(parts[0]+parts[1]) {
+		t.Fatalf("etag = %s", res.ETag)
+	}
+	if _, err := l.ListUploadedParts(ctx, upload.UploadID, upload.Key, 0, 10); !l.IsNotFound(err) {
+		t.Fatalf("upload not removed: %v", err)
+	}
+}
+
+func TestObjectPath(t *testing.T) {
+	l := newTestLocal(t, 0)
+	for _, name := range []string{"", "../x", "a/../../x", "/abs", "a//b", "a/./b", `a\b`, "a/"} {
+		if _, err := l.objectPath(name); err == nil {
+			t.Errorf("name %q accepted", name)
+		}
+	}
+	if _, err := l.objectPath("openim/data/hash/abc"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQuota(t *testing.T) {
+	ctx := context.Background()
+	l := newTestLocal(t, 1)
+	rawURL, err := l.PresignedPutObject(ctx, "big", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := do(l, http.MethodPut, rawURL, strings.Repeat("x", 1024*1024+1)); w.Code != http.StatusInsufficientStorage {
+		t.Fatalf("put over quota = %d", w.Code)
+	}
+	if w := do(l, http.MethodPut, rawURL, "small"); w.Code != successCode {
+		t.Fatalf("put = %d %s", w.Code, w.Body)
+	}
+}
+
+func TestQuotaUsage(t *testing.T) {
+	ctx := context.Background()
+	l := newTestLocal(t, 1)
+	half := strings.Repeat("x", 600*1024)
+	if err := l.PutObject(ctx, "a", "", []byte(half)); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.PutObject(ctx, "a", "", []byte(half)); err != nil {
+		t.Fatalf("replacing an object: %v", err)
+	}
+	if err := l.PutObject(ctx, "b", "", []byte(half)); !errors.Is(errs.Unwrap(err), ErrQuotaExceeded) {
+		t.Fatalf("put over quota: %v", err)
+	}
+	if err := l.DeleteObject(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.PutObject(ctx, "b", "", []byte(half)); err != nil {
+		t.Fatalf("put after delete: %v", err)
+	}
+}
+
+func TestServeHeaders(t *testing.T) {
+	ctx := context.Background()
+	l := newTestLocal(t, 0)
+	for _, name := range []string{"page.html", "photo.png", "noext"} {
+		if err := l.PutObject(ctx, name, "", []byte("data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name        string
+		contentType string
+		attachment  bool
+	}{
+		{name: "page.html", attachment: true},
+		{name: "photo.png"},
+		{name: "noext", attachment: true},
+		{name: "photo.png", contentType: "image/svg+xml", attachment: true},
+		{name: "noext", contentType: "video/mp4"},
+	}
+	for _, tt := range tests {
+		rawURL, err := l.AccessURL(ctx, tt.name, time.Minute, &s3.AccessURLOption{ContentType: tt.contentType})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := do(l, http.MethodGet, rawURL, "")
+		if w.Header().Get("X-Content-Type-Options") != "nosniff" {
+			t.Errorf("%s %s: no nosniff", tt.name, tt.contentType)
+		}
+		if attachment := w.Header().Get("Content-Disposition") == "attachment"; attachment != tt.attachment {
+			t.Errorf("%s %s: attachment = %v", tt.name, tt.contentType, attachment)
+		}
+	}
+}
+
+func TestFormDataSize(t *testing.T) {
+	ctx := context.Background()
+	l := newTestLocal(t, 0)
+	form, err := l.FormData(ctx, "form", 3, "text/plain", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := do(l, http.MethodPut, form.URL, "four"); w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("put over size = %d", w.Code)
+	}
+	u, _ := url.Parse(form.URL)
+	query := u.Query()
+	query.Set("size", "10")
+	u.RawQuery = query.Encode()
+	if w := do(l, http.MethodPut, u.String(), "four"); w.Code != http.StatusForbidden {
+		t.Fatalf("put with changed size = %d", w.Code)
+	}
+}
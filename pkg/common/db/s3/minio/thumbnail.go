@@ -62,8 +62,15 @@ func (m *Minio) getImageThumbnailURL(ctx context.Context, name string, expire ti
 	default:
 		opt.Format = ""
 	}
+	// A quality is only kept by jpegs.
+	if opt.Format == "" && opt.Quality > 0 {
+		opt.Format = formatJpeg
+	}
+	if opt.Quality < 0 || opt.Quality > 100 || opt.Format != formatJpeg {
+		opt.Quality = 0
+	}
 	reqParams := make(url.Values)
-	if opt.Width == info.Width && opt.Height == info.Height && (opt.Format == info.Format || opt.Format == "") {
+	if opt.Width == info.Width && opt.Height == info.Height && (opt.Format == info.Format || opt.Format == "") && opt.Quality == 0 {
 		reqParams.Set("response-content-type", "image/"+info.Format)
 		return m.PresignedGetObject(ctx, name, expire, reqParams)
 	}
@@ -79,7 +86,13 @@ func (m *Minio) getImageThumbnailURL(ctx context.Context, name string, expire ti
 			opt.Format = formatPng
 		}
 	}
-	key, err := m.cache.GetThumbnailKey(ctx, name, opt.Format, opt.Width, opt.Height, func(ctx context.Context) (string, error) {
+	// The quality of a jpeg is kept apart from the format, thumbnails made before qualities existed keep their keys.
+	variant, suffix := opt.Format, ""
+	if opt.Quality > 0 {
+		suffix = fmt.Sprintf("_q%d", opt.Quality)
+		variant += suffix
+	}
+	key, err := m.cache.GetThumbnailKey(ctx, name, variant, opt.Width, opt.Height, func(ctx context.Context) (string, error) {
 		if img == nil {
 			var reader *minio.Object
 			reader, err = m.core.Client.GetObject(ctx, m.bucket, name, minio.GetObjectOptions{})
@@ -98,11 +111,15 @@ func (m *Minio) getImageThumbnailURL(ctx context.Context, name string, expire ti
 		case formatPng:
 			err = png.Encode(buf, thumbnail)
 		case formatJpeg:
-			err = jpeg.Encode(buf, thumbnail, nil)
+			var options *jpeg.Options
+			if opt.Quality > 0 {
+				options = &jpeg.Options{Quality: opt.Quality}
+			}
+			err = jpeg.Encode(buf, thumbnail, options)
 		case formatGif:
 			err = gif.Encode(buf, thumbnail, nil)
 		}
-		cacheKey := filepath.Join(imageThumbnailPath, info.Etag, fmt.Sprintf("image_w%d_h%d.%s", opt.Width, opt.Height, opt.Format))
+		cacheKey := filepath.Join(imageThumbnailPath, info.Etag, fmt.Sprintf("image_w%d_h%d%s.%s", opt.Width, opt.Height, suffix, opt.Format))
 		if _, err = m.core.Client.PutObject(ctx, m.bucket, cacheKey, buf, int64(buf.Len()), minio.PutObjectOptions{}); err != nil {
 			return "", err
 		}
@@ -312,6 +312,9 @@ func (o *OSS) AccessURL(ctx context.Context, name string, expire time.Duration,
 				process += ",h_" + strconv.Itoa(opt.Image.Height)
 			}
 			process += ",format," + format
+			if opt.Image.Quality > 0 {
+				process += "/quality,q_" + strconv.Itoa(opt.Image.Quality)
+			}
 			opts = append(opts, oss.Process(process))
 		}
 		if !o.publicRead {
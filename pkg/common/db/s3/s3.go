@@ -129,6 +129,8 @@ type Image struct {
 	Format string `json:"format"`
 	Width  int    `json:"width"`
 	Height int    `json:"height"`
+	// Quality is the jpeg quality from 1 to 100, 0 keeps the default of the engine.
+	Quality int `json:"quality"`
 }
 
 type AccessURLOption struct {
@@ -164,3 +166,15 @@ type Interface interface {
 
 	FormData(ctx context.Context, name string, size int64, contentType string, duration time.Duration) (*FormData, error)
 }
+
+// ObjectReadWriter is implemented by the engines that do not process images themselves, the controller reads their
+// images and writes the thumbnails it makes through it.
+type ObjectReadWriter interface {
+	GetObject(ctx context.Context, name string) ([]byte, error)
+	PutObject(ctx context.Context, name string, contentType string, data []byte) error
+}
+
+// PutHeader is implemented by the engines whose presigned put urls must be sent with headers.
+type PutHeader interface {
+	PresignedPutHeader() http.Header
+}
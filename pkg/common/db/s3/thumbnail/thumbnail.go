@@ -0,0 +1,170 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package thumbnail resizes images for the object engines that do not process images themselves. The controller
+// stores every resized image next to the objects, so each size of an image is only made once.
+package thumbnail
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"path"
+	"strings"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3"
+	"golang.org/x/image/draw"
+
+	_ "golang.org/x/image/bmp"
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// MaxWidth and MaxHeight bound the size of a thumbnail, larger sizes are reduced to them.
+	MaxWidth  = 1024
+	MaxHeight = 1024
+	// MaxSize is the largest object resized.
+	MaxSize = 1024 * 1024 * 20
+	// MaxPixels is the largest image decoded, a small file can declare a size that takes gigabytes to decode.
+	MaxPixels = 25000000
+	// Dir is where the thumbnails are stored, by the etag of their image.
+	Dir = "openim/thumbnail"
+)
+
+const (
+	FormatPng  = "png"
+	FormatJpeg = "jpeg"
+	FormatGif  = "gif"
+)
+
+// Normalize checks opt and completes it for name, it reports false when opt does not change the image.
+// Sizes above the maximum are reduced to it. Without a format the thumbnail is a jpeg when a quality is given,
+// otherwise the format of the extension of name, png for other extensions.
+func Normalize(name string, opt *s3.Image) (bool, error) {
+	if opt.Width < 0 || opt.Height < 0 {
+		return false, errs.ErrArgs.Wrap("width and height must not be negative")
+	}
+	if opt.Quality < 0 || opt.Quality > 100 {
+		return false, errs.ErrArgs.Wrap("quality must be between 1 and 100, or 0 for the default")
+	}
+	if opt.Width == 0 && opt.Height == 0 && opt.Format == "" && opt.Quality == 0 {
+		return false, nil
+	}
+	if opt.Width > MaxWidth {
+		opt.Width = MaxWidth
+	}
+	if opt.Height > MaxHeight {
+		opt.Height = MaxHeight
+	}
+	opt.Format = formatOf(opt.Format)
+	if opt.Format == "" {
+		if opt.Quality > 0 {
+			opt.Format = FormatJpeg
+		} else if opt.Format = formatOf(strings.TrimPrefix(path.Ext(name), ".")); opt.Format == "" {
+			opt.Format = FormatPng
+		}
+	}
+	if opt.Format != FormatJpeg {
+		opt.Quality = 0
+	}
+	return true, nil
+}
+
+// formatOf returns the thumbnail format named format, empty when there is none.
+func formatOf(format string) string {
+	switch format = strings.ToLower(format); format {
+	case FormatPng, FormatJpeg, FormatGif:
+		return format
+	case "jpg":
+		return FormatJpeg
+	default:
+		return ""
+	}
+}
+
+// Key returns where the thumbnail described by a normalized opt of the image with etag is stored.
+func Key(etag string, opt *s3.Image) string {
+	return path.Join(Dir, etag, fmt.Sprintf("image_w%d_h%d_q%d.%s", opt.Width, opt.Height, opt.Quality, opt.Format))
+}
+
+// Fit returns the size of a width x height image scaled down to fit in maxWidth x maxHeight with its aspect ratio,
+// a bound of 0 does not limit. Images are never enlarged.
+func Fit(width int, height int, maxWidth int, maxHeight int) (int, int) {
+	scale := 1.0
+	if maxWidth > 0 && maxWidth < width {
+		scale = float64(maxWidth) / float64(width)
+	}
+	if maxHeight > 0 && maxHeight < height {
+		if s := float64(maxHeight) / float64(height); s < scale {
+			scale = s
+		}
+	}
+	if scale == 1 {
+		return width, height
+	}
+	w, h := int(float64(width)*scale+0.5), int(float64(height)*scale+0.5)
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	return w, h
+}
+
+// Resize decodes the image data and encodes it fit in the size of a normalized opt, in its format and quality.
+func Resize(data []byte, opt *s3.Image) ([]byte, error) {
+	conf, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errs.ErrData.Wrap("object is not an image: " + err.Error())
+	}
+	if conf.Width <= 0 || conf.Height <= 0 || int64(conf.Width)*int64(conf.Height) > MaxPixels {
+		return nil, errs.ErrData.Wrap(fmt.Sprintf("image of %dx%d pixels is too large to resize", conf.Width, conf.Height))
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errs.ErrData.Wrap("object is not an image: " + err.Error())
+	}
+	bounds := img.Bounds()
+	width, height := Fit(bounds.Dx(), bounds.Dy(), opt.Width, opt.Height)
+	if width != bounds.Dx() || height != bounds.Dy() {
+		dst := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+		img = dst
+	}
+	var buf bytes.Buffer
+	switch opt.Format {
+	case FormatPng:
+		err = png.Encode(&buf, img)
+	case FormatJpeg:
+		var options *jpeg.Options
+		if opt.Quality > 0 {
+			options = &jpeg.Options{Quality: opt.Quality}
+		}
+		err = jpeg.Encode(&buf, img, options)
+	case FormatGif:
+		err = gif.Encode(&buf, img, nil)
+	default:
+		return nil, errs.ErrArgs.Wrap("unsupported image format " + opt.Format)
+	}
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return buf.Bytes(), nil
+}
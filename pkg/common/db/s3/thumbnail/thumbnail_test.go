@@ -0,0 +1,121 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package thumbnail
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/s3"
+)
+
+func TestNormalize(t *testing.T) {
+	opt := &s3.Image{}
+	if resize, err := Normalize("a.png", opt); err != nil || resize {
+		t.Fatalf("empty option resizes: %v %v", resize, err)
+	}
+	opt = &s3.Image{Width: 4096, Height: 100}
+	if resize, err := Normalize("a.JPG", opt); err != nil || !resize {
+		t.Fatalf("resize %v %v", resize, err)
+	}
+	if opt.Width != MaxWidth || opt.Format != FormatJpeg {
+		t.Fatalf("normalized to %+v", opt)
+	}
+	opt = &s3.Image{Width: 100, Quality: 80}
+	if _, err := Normalize("a.png", opt); err != nil || opt.Format != FormatJpeg || opt.Quality != 80 {
+		t.Fatalf("quality without format normalized to %+v, %v", opt, err)
+	}
+	opt = &s3.Image{Width: 100, Format: "gif", Quality: 80}
+	if _, err := Normalize("a.bin", opt); err != nil || opt.Format != FormatGif || opt.Quality != 0 {
+		t.Fatalf("quality of a gif normalized to %+v, %v", opt, err)
+	}
+	opt = &s3.Image{Width: 100}
+	if _, err := Normalize("a.bin", opt); err != nil || opt.Format != FormatPng {
+		t.Fatalf("unknown extension normalized to %+v, %v", opt, err)
+	}
+	if _, err := Normalize("a.png", &s3.Image{Quality: 101}); err == nil {
+		t.Fatal("quality above 100 accepted")
+	}
+	if _, err := Normalize("a.png", &s3.Image{Width: -1}); err == nil {
+		t.Fatal("negative width accepted")
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		width, height, maxWidth, maxHeight int
+		w, h                               int
+	}{
+		{800, 400, 200, 0, 200, 100},
+		{800, 400, 0, 100, 200, 100},
+		{800, 400, 200, 50, 100, 50},
+		{800, 400, 1000, 1000, 800, 400},
+		{800, 400, 0, 0, 800, 400},
+		{1000, 1, 10, 0, 10, 1},
+	}
+	for _, test := range tests {
+		w, h := Fit(test.width, test.height, test.maxWidth, test.maxHeight)
+		if w != test.w || h != test.h {
+			t.Errorf("Fit(%d, %d, %d, %d) = %d, %d, want %d, %d", test.width, test.height, test.maxWidth, test.maxHeight, w, h, test.w, test.h)
+		}
+	}
+}
+
+func TestResize(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 80, 40))
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 80; x++ {
+			src.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+	data, err := Resize(buf.Bytes(), &s3.Image{Width: 20, Format: FormatJpeg, Quality: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != FormatJpeg || img.Bounds().Dx() != 20 || img.Bounds().Dy() != 10 {
+		t.Fatalf("resized to %s %v", format, img.Bounds())
+	}
+	if _, err := Resize([]byte("not an image"), &s3.Image{Width: 20, Format: FormatPng}); err == nil {
+		t.Fatal("resized a non image")
+	}
+}
+
+func TestResizeTooLarge(t *testing.T) {
+	// A gif header declaring 60000x60000 pixels, decoding it would allocate gigabytes.
+	data := []byte("GIF89a\x60\xea\x60\xea\x00\x00\x00;")
+	if conf, _, err := image.DecodeConfig(bytes.NewReader(data)); err != nil || conf.Width != 60000 {
+		t.Fatalf("config %+v, %v", conf, err)
+	}
+	if _, err := Resize(data, &s3.Image{Width: 20, Format: FormatPng}); err == nil {
+		t.Fatal("resized an image above the pixel limit")
+	}
+}
+
+func TestKey(t *testing.T) {
+	if key := Key("etag", &s3.Image{Width: 100, Height: 0, Quality: 80, Format: FormatJpeg}); key != "openim/thumbnail/etag/image_w100_h0_q80.jpeg" {
+		t.Fatalf("key %s", key)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/pgsql"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+)
+
+// NewRelation returns the backend of users, friends, groups and conversations, PostgreSQL when it is enabled
+// in config and mongo otherwise.
+func NewRelation(config *config.GlobalConfig, mongo *unrelation.Mongo) (relation.Storage, error) {
+	if config.Postgres.Enable {
+		db, err := pgsql.NewPostgres(config)
+		if err != nil {
+			return nil, err
+		}
+		return pgsql.NewStorage(db), nil
+	}
+	return mgo.NewStorage(mongo.GetClient(), mongo.GetDatabase(config.Mongo.Database), mongo.ReadPreference), nil
+}
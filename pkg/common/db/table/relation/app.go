@@ -0,0 +1,41 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+// AppModel is a tenant of the cluster. Secret replaces the global secret for the app, and ManagerUserIDs
+// are admins inside the app only.
+type AppModel struct {
+	AppID          string    `bson:"app_id"`
+	Name           string    `bson:"name"`
+	Secret         string    `bson:"secret"`
+	ManagerUserIDs []string  `bson:"manager_user_ids"`
+	CreateTime     time.Time `bson:"create_time"`
+}
+
+type AppModelInterface interface {
+	Create(ctx context.Context, app *AppModel) error
+	Take(ctx context.Context, appID string) (*AppModel, error)
+	FindAll(ctx context.Context) ([]*AppModel, error)
+	Page(ctx context.Context, pagination pagination.Pagination) (int64, []*AppModel, error)
+	UpdateSecret(ctx context.Context, appID string, secret string) error
+	UpdateManagers(ctx context.Context, appID string, managerUserIDs []string) error
+}
@@ -0,0 +1,33 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+// ArchiveWatermarkModel records the highest seq of a conversation acknowledged by the compliance archive.
+type ArchiveWatermarkModel struct {
+	ConversationID string    `bson:"conversation_id"`
+	Seq            int64     `bson:"seq"`
+	UpdateTime     time.Time `bson:"update_time"`
+}
+
+type ArchiveWatermarkModelInterface interface {
+	// SetSeq moves the watermark forward, a lower seq never overwrites a higher one.
+	SetSeq(ctx context.Context, conversationID string, seq int64) error
+	GetSeqs(ctx context.Context, conversationIDs []string) (map[string]int64, error)
+}
@@ -40,4 +40,6 @@ type BlackModelInterface interface {
 	FindOwnerBlacks(ctx context.Context, ownerUserID string, pagination pagination.Pagination) (total int64, blacks []*BlackModel, err error)
 	FindOwnerBlackInfos(ctx context.Context, ownerUserID string, userIDs []string) (blacks []*BlackModel, err error)
 	FindBlackUserIDs(ctx context.Context, ownerUserID string) (blackUserIDs []string, err error)
+	// FindInWhoseBlacks returns the blacklist entries blocking blockUserID.
+	FindInWhoseBlacks(ctx context.Context, blockUserID string) (blacks []*BlackModel, err error)
 }
@@ -0,0 +1,67 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+const (
+	BroadcastPending  = 0
+	BroadcastRunning  = 1
+	BroadcastFinished = 2
+	BroadcastCanceled = 3
+	// BroadcastFailed is set when the job could not be carried out at all, failed sends are counted in Failed.
+	BroadcastFailed = 4
+)
+
+// BroadcastJobModel is a message sent by an app admin to every user, or to RecvIDs when it is set, by the cron task.
+// The recipients are sent to PageSize at a time, Page is the number of pages already sent so that a job interrupted
+// by a restart or by the run time of a cron task resumes with the next page.
+type BroadcastJobModel struct {
+	JobID          string   `bson:"job_id"`
+	AppID          string   `bson:"app_id"`
+	OperatorUserID string   `bson:"operator_user_id"`
+	MsgData        []byte   `bson:"msg_data"`
+	RecvIDs        []string `bson:"recv_ids"`
+	PageSize       int32    `bson:"page_size"`
+	Page           int32    `bson:"page"`
+	Status         int32    `bson:"status"`
+	Err            string   `bson:"err"`
+	// Total is the number of users when the job was created, users registered later are sent to as well.
+	Total      int64     `bson:"total"`
+	Sent       int64     `bson:"sent"`
+	Failed     int64     `bson:"failed"`
+	LeaseUntil time.Time `bson:"lease_until"`
+	CreateTime time.Time `bson:"create_time"`
+	UpdateTime time.Time `bson:"update_time"`
+	FinishTime time.Time `bson:"finish_time"`
+}
+
+type BroadcastJobModelInterface interface {
+	Create(ctx context.Context, job *BroadcastJobModel) error
+	Take(ctx context.Context, jobID string) (*BroadcastJobModel, error)
+	FindPage(ctx context.Context, pagination pagination.Pagination) (int64, []*BroadcastJobModel, error)
+	// Lease marks the oldest pending or running job whose lease expired before now as running until leaseUntil and
+	// returns it, nil if there is none.
+	Lease(ctx context.Context, now time.Time, leaseUntil time.Time) (*BroadcastJobModel, error)
+	// UpdateProgress stores the page and counters of a running job, reporting false if it is no longer running.
+	UpdateProgress(ctx context.Context, job *BroadcastJobModel) (bool, error)
+	// UpdateStatus moves the job from one of statuses from to status to, reporting false if it was in none of them.
+	UpdateStatus(ctx context.Context, jobID string, from []int32, to int32, errMsg string) (bool, error)
+}
@@ -0,0 +1,36 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+// BusinessTopicSubscriberModel records that a user subscribed to a business notification topic.
+type BusinessTopicSubscriberModel struct {
+	Topic      string    `bson:"topic"`
+	UserID     string    `bson:"user_id"`
+	CreateTime time.Time `bson:"create_time"`
+}
+
+type BusinessTopicModelInterface interface {
+	Subscribe(ctx context.Context, subscribers []*BusinessTopicSubscriberModel) error
+	Unsubscribe(ctx context.Context, userID string, topics []string) error
+	FindUserTopics(ctx context.Context, userID string) ([]string, error)
+	PageSubscriberIDs(ctx context.Context, topic string, pagination pagination.Pagination) (int64, []string, error)
+}
@@ -0,0 +1,39 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+// ContentPolicyModel restricts the content types users may send. A policy without GroupID applies to every
+// conversation of its session type, the policy of a group replaces it in that group.
+type ContentPolicyModel struct {
+	SessionType  int32     `bson:"session_type"`
+	GroupID      string    `bson:"group_id"`
+	ContentTypes []int32   `bson:"content_types"`
+	UpdateTime   time.Time `bson:"update_time"`
+}
+
+type ContentPolicyModelInterface interface {
+	Set(ctx context.Context, policy *ContentPolicyModel) error
+	Delete(ctx context.Context, sessionType int32, groupID string) error
+	// FindContentTypes returns the allowed content types, nil when there is no policy.
+	FindContentTypes(ctx context.Context, sessionType int32, groupID string) ([]int32, error)
+	Page(ctx context.Context, pagination pagination.Pagination) (int64, []*ContentPolicyModel, error)
+}
@@ -62,3 +62,11 @@ type ConversationModelInterface interface {
 	GetConversationIDsNeedDestruct(ctx context.Context) ([]*ConversationModel, error)
 	GetConversationNotReceiveMessageUserIDs(ctx context.Context, conversationID string) ([]string, error)
 }
+
+// ConversationRepairModelInterface finds single chat records stored under a conversation id other than the one
+// derived from the user pair, a leftover of migrations and of races creating the conversation.
+type ConversationRepairModelInterface interface {
+	// FindNonCanonicalSingleChats returns up to limit such records, only those owned by ownerUserIDs if given.
+	FindNonCanonicalSingleChats(ctx context.Context, ownerUserIDs []string, limit int64) ([]*ConversationModel, error)
+	DeleteConversations(ctx context.Context, ownerUserID string, conversationIDs []string) error
+}
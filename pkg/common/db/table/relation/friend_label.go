@@ -0,0 +1,43 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+// FriendLabelModel is a label a user puts on some of their friends, e.g. family or work. A friend may carry
+// several labels.
+type FriendLabelModel struct {
+	OwnerUserID   string    `bson:"owner_user_id"`
+	LabelID       string    `bson:"label_id"`
+	Name          string    `bson:"name"`
+	FriendUserIDs []string  `bson:"friend_user_ids"`
+	CreateTime    time.Time `bson:"create_time"`
+	UpdateTime    time.Time `bson:"update_time"`
+}
+
+type FriendLabelModelInterface interface {
+	Create(ctx context.Context, label *FriendLabelModel) error
+	UpdateName(ctx context.Context, ownerUserID string, labelID string, name string) error
+	Delete(ctx context.Context, ownerUserID string, labelID string) error
+	Take(ctx context.Context, ownerUserID string, labelID string) (*FriendLabelModel, error)
+	FindByOwner(ctx context.Context, ownerUserID string) ([]*FriendLabelModel, error)
+	AddFriends(ctx context.Context, ownerUserID string, labelID string, friendUserIDs []string) error
+	RemoveFriends(ctx context.Context, ownerUserID string, labelID string, friendUserIDs []string) error
+	// RemoveFriendsFromAll takes friendUserIDs off every label of ownerUserID.
+	RemoveFriendsFromAll(ctx context.Context, ownerUserID string, friendUserIDs []string) error
+}
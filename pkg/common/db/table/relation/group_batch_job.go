@@ -0,0 +1,87 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+// Kinds of group batch jobs.
+const (
+	GroupBatchCreateGroups  = "create_groups"
+	GroupBatchAddMembers    = "add_members"
+	GroupBatchRemoveMembers = "remove_members"
+	GroupBatchApplySettings = "apply_settings"
+)
+
+const (
+	GroupBatchPending  = 0
+	GroupBatchRunning  = 1
+	GroupBatchFinished = 2
+	// GroupBatchFailed is set when at least one item failed, the other items were still carried out.
+	GroupBatchFailed = 3
+)
+
+// GroupBatchJobModel is a batch of group operations queued by an app admin and carried out by the cron task.
+type GroupBatchJobModel struct {
+	JobID          string                   `bson:"job_id"`
+	Kind           string                   `bson:"kind"`
+	AppID          string                   `bson:"app_id"`
+	OperatorUserID string                   `bson:"operator_user_id"`
+	Status         int32                    `bson:"status"`
+	Items          []*GroupBatchItemModel   `bson:"items"`
+	Settings       *GroupBatchSettingsModel `bson:"settings"`
+	Total          int64                    `bson:"total"`
+	Succeeded      int64                    `bson:"succeeded"`
+	Failed         int64                    `bson:"failed"`
+	CreateTime     time.Time                `bson:"create_time"`
+	FinishTime     time.Time                `bson:"finish_time"`
+}
+
+// GroupBatchItemModel is one group of a job. GroupName, OwnerUserID and AdminUserIDs are only used to create
+// groups, GroupID is filled in once a group without one is created. Err is empty for items carried out.
+type GroupBatchItemModel struct {
+	GroupID       string   `bson:"group_id"`
+	GroupName     string   `bson:"group_name"`
+	OwnerUserID   string   `bson:"owner_user_id"`
+	AdminUserIDs  []string `bson:"admin_user_ids"`
+	MemberUserIDs []string `bson:"member_user_ids"`
+	Err           string   `bson:"err"`
+}
+
+// GroupBatchSettingsModel is the settings template of an apply_settings or create_groups job, empty fields are
+// left as they are.
+type GroupBatchSettingsModel struct {
+	Notification      string  `bson:"notification"`
+	Introduction      string  `bson:"introduction"`
+	FaceURL           string  `bson:"face_url"`
+	Ex                *string `bson:"ex"`
+	NeedVerification  *int32  `bson:"need_verification"`
+	LookMemberInfo    *int32  `bson:"look_member_info"`
+	ApplyMemberFriend *int32  `bson:"apply_member_friend"`
+}
+
+type GroupBatchJobModelInterface interface {
+	Create(ctx context.Context, job *GroupBatchJobModel) error
+	Take(ctx context.Context, jobID string) (*GroupBatchJobModel, error)
+	FindPage(ctx context.Context, pagination pagination.Pagination) (int64, []*GroupBatchJobModel, error)
+	FindPending(ctx context.Context, limit int64) ([]*GroupBatchJobModel, error)
+	// UpdateStatus moves the job from status from to status to, reporting false if it was no longer in status from.
+	UpdateStatus(ctx context.Context, jobID string, from int32, to int32) (bool, error)
+	Finish(ctx context.Context, job *GroupBatchJobModel) error
+}
@@ -0,0 +1,45 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+// GroupDirectoryModel lists a discoverable group in the public directory, it copies the group fields searched.
+type GroupDirectoryModel struct {
+	GroupID      string    `bson:"group_id"`
+	GroupName    string    `bson:"group_name"`
+	Tags         []string  `bson:"tags"`
+	Introduction string    `bson:"introduction"`
+	FaceURL      string    `bson:"face_url"`
+	CreateTime   time.Time `bson:"create_time"`
+	UpdateTime   time.Time `bson:"update_time"`
+}
+
+type GroupDirectoryModelInterface interface {
+	Set(ctx context.Context, entry *GroupDirectoryModel) error
+	// UpdateGroupInfo refreshes the copied group fields, false when the group is not listed.
+	UpdateGroupInfo(ctx context.Context, groupID string, groupName string, introduction string, faceURL string) (bool, error)
+	Delete(ctx context.Context, groupID string) error
+	Take(ctx context.Context, groupID string) (*GroupDirectoryModel, error)
+	Find(ctx context.Context, groupIDs []string) ([]*GroupDirectoryModel, error)
+	// Search matches keyword against the names and tags with the text index, best match first. An empty keyword
+	// lists the directory, recently updated first.
+	Search(ctx context.Context, keyword string, pagination pagination.Pagination) (int64, []*GroupDirectoryModel, error)
+}
@@ -0,0 +1,71 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// GroupHistoryVisibleAll lets new members read the whole group history, it is the default.
+	GroupHistoryVisibleAll = 0
+	// GroupHistoryVisibleNone hides everything sent before a member joined.
+	GroupHistoryVisibleNone = 1
+	// GroupHistoryVisibleDays shows the Days days of history before a member joined.
+	GroupHistoryVisibleDays = 2
+)
+
+// GroupHistoryVisibilityModel is the history a group shows its newly joined members.
+type GroupHistoryVisibilityModel struct {
+	GroupID    string    `bson:"group_id"`
+	Visibility int32     `bson:"visibility"`
+	Days       int32     `bson:"days"`
+	UpdateTime time.Time `bson:"update_time"`
+}
+
+// VisibleSince returns the earliest send time in ms a member who joined at joinTime (ms) can read, 0 when unrestricted.
+func (g *GroupHistoryVisibilityModel) VisibleSince(joinTime int64) int64 {
+	switch g.Visibility {
+	case GroupHistoryVisibleNone:
+		return joinTime
+	case GroupHistoryVisibleDays:
+		since := joinTime - int64(g.Days)*(24*time.Hour).Milliseconds()
+		if since < 1 {
+			return 0
+		}
+		return since
+	default:
+		return 0
+	}
+}
+
+// GroupFormerMemberModel keeps the join time of a member who left the group, so the history they may still
+// read is the one they could read as a member.
+type GroupFormerMemberModel struct {
+	GroupID   string    `bson:"group_id"`
+	UserID    string    `bson:"user_id"`
+	JoinTime  time.Time `bson:"join_time"`
+	LeaveTime time.Time `bson:"leave_time"`
+}
+
+type GroupHistoryVisibilityModelInterface interface {
+	Set(ctx context.Context, visibility *GroupHistoryVisibilityModel) error
+	Take(ctx context.Context, groupID string) (*GroupHistoryVisibilityModel, error)
+	// SetFormerMembers replaces the former member records of the same users, a member leaving again after
+	// rejoining keeps the last join time.
+	SetFormerMembers(ctx context.Context, members []*GroupFormerMemberModel) error
+	TakeFormerMember(ctx context.Context, groupID string, userID string) (*GroupFormerMemberModel, error)
+}
@@ -0,0 +1,55 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+// JoinByInviteLink is the join source of a member who joined through an invite link, the join request carries
+// the token of the link in Ex.
+const JoinByInviteLink int32 = 5
+
+// GroupInviteLinkModel lets users join a group without being invited one by one. A zero ExpireTime never expires
+// and a zero MaxUses never runs out.
+type GroupInviteLinkModel struct {
+	Token         string    `bson:"token"`
+	GroupID       string    `bson:"group_id"`
+	CreatorUserID string    `bson:"creator_user_id"`
+	ExpireTime    time.Time `bson:"expire_time"`
+	MaxUses       int32     `bson:"max_uses"`
+	Uses          int32     `bson:"uses"`
+	NeedApproval  bool      `bson:"need_approval"`
+	CreateTime    time.Time `bson:"create_time"`
+}
+
+// Usable reports whether the link can still be used at now.
+func (l *GroupInviteLinkModel) Usable(now time.Time) bool {
+	if !l.ExpireTime.IsZero() && !now.Before(l.ExpireTime) {
+		return false
+	}
+	return l.MaxUses == 0 || l.Uses < l.MaxUses
+}
+
+type GroupInviteLinkModelInterface interface {
+	Create(ctx context.Context, link *GroupInviteLinkModel) error
+	Delete(ctx context.Context, groupID string, token string) error
+	Take(ctx context.Context, token string) (*GroupInviteLinkModel, error)
+	FindByGroup(ctx context.Context, groupID string) ([]*GroupInviteLinkModel, error)
+	CountByGroup(ctx context.Context, groupID string) (int64, error)
+	// IncrUses counts one use of the link, false when it has run out.
+	IncrUses(ctx context.Context, token string) (bool, error)
+}
@@ -0,0 +1,40 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGroupInviteLinkUsable(t *testing.T) {
+	now := time.UnixMilli(1700000000000)
+	cases := []struct {
+		name string
+		link GroupInviteLinkModel
+		want bool
+	}{
+		{"unlimited", GroupInviteLinkModel{Uses: 100}, true},
+		{"expired", GroupInviteLinkModel{ExpireTime: now}, false},
+		{"not expired", GroupInviteLinkModel{ExpireTime: now.Add(time.Second)}, true},
+		{"uses left", GroupInviteLinkModel{MaxUses: 2, Uses: 1}, true},
+		{"used up", GroupInviteLinkModel{MaxUses: 2, Uses: 2}, false},
+	}
+	for _, c := range cases {
+		if got := c.link.Usable(now); got != c.want {
+			t.Errorf("%s: Usable = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
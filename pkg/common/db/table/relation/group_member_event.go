@@ -0,0 +1,64 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+// Group membership changes sent to the group member webhook.
+const (
+	GroupMemberEventJoin       = "join"
+	GroupMemberEventLeave      = "leave"
+	GroupMemberEventKick       = "kick"
+	GroupMemberEventRoleChange = "roleChange"
+)
+
+const (
+	GroupMemberEventPending   = 0
+	GroupMemberEventDelivered = 1
+	GroupMemberEventFailed    = 2
+)
+
+// GroupMemberEventModel is a membership change waiting in the outbox of the group member webhook. A pending event
+// is due at NextTime, claiming it moves NextTime forward so that an event whose delivery was interrupted is sent
+// again once the lease ran out. RoleLevel is the new role level of UserIDs on roleChange.
+type GroupMemberEventModel struct {
+	EventID        string    `bson:"event_id"`
+	AppID          string    `bson:"app_id"`
+	GroupID        string    `bson:"group_id"`
+	Event          string    `bson:"event"`
+	UserIDs        []string  `bson:"user_ids"`
+	OperatorUserID string    `bson:"operator_user_id"`
+	RoleLevel      int32     `bson:"role_level"`
+	EventTime      time.Time `bson:"event_time"`
+	Status         int32     `bson:"status"`
+	Attempts       int32     `bson:"attempts"`
+	NextTime       time.Time `bson:"next_time"`
+	LastErr        string    `bson:"last_err"`
+}
+
+type GroupMemberEventModelInterface interface {
+	Create(ctx context.Context, events []*GroupMemberEventModel) error
+	// FindDue returns up to limit pending events due at now, oldest first.
+	FindDue(ctx context.Context, now time.Time, limit int64) ([]*GroupMemberEventModel, error)
+	// Claim moves NextTime of a pending event from nextTime to leaseTime, it reports false when another
+	// dispatcher claimed the event first.
+	Claim(ctx context.Context, eventID string, nextTime time.Time, leaseTime time.Time) (bool, error)
+	SetDelivered(ctx context.Context, eventIDs []string) error
+	// SetAttempt records a failed delivery, status is pending with the next attempt at nextTime or failed.
+	SetAttempt(ctx context.Context, eventID string, status int32, attempts int32, nextTime time.Time, lastErr string) error
+}
@@ -0,0 +1,81 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+)
+
+// Permissions of a group member, a custom role grants a combination of them.
+const (
+	GroupPermissionInvite int64 = 1 << iota
+	GroupPermissionKick
+	GroupPermissionMute
+	// GroupPermissionPin is kept for clients, the server has no pin operation to enforce it on.
+	GroupPermissionPin
+	GroupPermissionEditInfo
+	GroupPermissionSendMsg
+
+	GroupPermissionAll = GroupPermissionInvite | GroupPermissionKick | GroupPermissionMute | GroupPermissionPin |
+		GroupPermissionEditInfo | GroupPermissionSendMsg
+	// GroupPermissionDefault is what an ordinary member without a custom role may do.
+	GroupPermissionDefault = GroupPermissionInvite | GroupPermissionSendMsg
+)
+
+// GroupRoleModel is a custom role defined by a group for its ordinary members.
+type GroupRoleModel struct {
+	GroupID     string    `bson:"group_id"`
+	RoleID      string    `bson:"role_id"`
+	Name        string    `bson:"name"`
+	Permissions int64     `bson:"permissions"`
+	CreateTime  time.Time `bson:"create_time"`
+	UpdateTime  time.Time `bson:"update_time"`
+}
+
+// GroupMemberRoleModel assigns a custom role to a member, a member has at most one.
+type GroupMemberRoleModel struct {
+	GroupID    string    `bson:"group_id"`
+	UserID     string    `bson:"user_id"`
+	RoleID     string    `bson:"role_id"`
+	UpdateTime time.Time `bson:"update_time"`
+}
+
+// GroupMemberPermissions returns the permissions of a member: all of them for the owner and admins, those of its
+// custom role for an ordinary member, GroupPermissionDefault when it has none.
+func GroupMemberPermissions(roleLevel int32, role *GroupRoleModel) int64 {
+	switch roleLevel {
+	case constant.GroupOwner, constant.GroupAdmin:
+		return GroupPermissionAll
+	}
+	if role == nil {
+		return GroupPermissionDefault
+	}
+	return role.Permissions
+}
+
+type GroupRoleModelInterface interface {
+	// SetRole creates the role or updates its name and permissions.
+	SetRole(ctx context.Context, role *GroupRoleModel) error
+	DeleteRole(ctx context.Context, groupID string, roleID string) error
+	FindRoles(ctx context.Context, groupID string) ([]*GroupRoleModel, error)
+	SetMemberRole(ctx context.Context, groupID string, userIDs []string, roleID string) error
+	DeleteMemberRoles(ctx context.Context, groupID string, userIDs []string) error
+	TakeMemberRole(ctx context.Context, groupID string, userID string) (*GroupMemberRoleModel, error)
+	FindMemberRoles(ctx context.Context, groupID string) ([]*GroupMemberRoleModel, error)
+	FindRoleMemberIDs(ctx context.Context, groupID string, roleID string) ([]string, error)
+}
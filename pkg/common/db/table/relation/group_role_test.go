@@ -0,0 +1,42 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"testing"
+
+	"github.com/OpenIMSDK/protocol/constant"
+)
+
+func TestGroupMemberPermissions(t *testing.T) {
+	kicker := &GroupRoleModel{Permissions: GroupPermissionKick | GroupPermissionSendMsg}
+	readOnly := &GroupRoleModel{}
+	cases := []struct {
+		roleLevel int32
+		role      *GroupRoleModel
+		want      int64
+	}{
+		{constant.GroupOwner, readOnly, GroupPermissionAll},
+		{constant.GroupAdmin, nil, GroupPermissionAll},
+		{constant.GroupOrdinaryUsers, nil, GroupPermissionDefault},
+		{constant.GroupOrdinaryUsers, kicker, GroupPermissionKick | GroupPermissionSendMsg},
+		{constant.GroupOrdinaryUsers, readOnly, 0},
+	}
+	for _, c := range cases {
+		if got := GroupMemberPermissions(c.roleLevel, c.role); got != c.want {
+			t.Errorf("GroupMemberPermissions(%d, %+v) = %d, want %d", c.roleLevel, c.role, got, c.want)
+		}
+	}
+}
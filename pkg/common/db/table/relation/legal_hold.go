@@ -0,0 +1,65 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+// Targets of a legal hold.
+const (
+	LegalHoldTargetUser         = "user"
+	LegalHoldTargetConversation = "conversation"
+)
+
+// Actions recorded in the legal hold log.
+const (
+	LegalHoldActionPlace   = "place"
+	LegalHoldActionRelease = "release"
+)
+
+// LegalHoldModel keeps the messages and objects of a user, or the messages of a conversation, from being deleted.
+type LegalHoldModel struct {
+	TargetType     string    `bson:"target_type"`
+	TargetID       string    `bson:"target_id"`
+	Reason         string    `bson:"reason"`
+	OperatorUserID string    `bson:"operator_user_id"`
+	CreateTime     time.Time `bson:"create_time"`
+}
+
+// LegalHoldLogModel records a hold placed or released, it is kept after the hold is released.
+type LegalHoldLogModel struct {
+	TargetType     string    `bson:"target_type"`
+	TargetID       string    `bson:"target_id"`
+	Action         string    `bson:"action"`
+	Reason         string    `bson:"reason"`
+	OperatorUserID string    `bson:"operator_user_id"`
+	CreateTime     time.Time `bson:"create_time"`
+}
+
+type LegalHoldModelInterface interface {
+	Create(ctx context.Context, hold *LegalHoldModel) error
+	// Delete returns the released hold, nil when the target was not on hold.
+	Delete(ctx context.Context, targetType string, targetID string) (*LegalHoldModel, error)
+	Find(ctx context.Context, targetType string, targetIDs []string) ([]*LegalHoldModel, error)
+	// Page returns the holds of targetType, every hold when it is empty.
+	Page(ctx context.Context, targetType string, pagination pagination.Pagination) (int64, []*LegalHoldModel, error)
+	AddLog(ctx context.Context, log *LegalHoldLogModel) error
+	// PageLogs returns the log of one target newest first, the whole log when targetID is empty.
+	PageLogs(ctx context.Context, targetType string, targetID string, pagination pagination.Pagination) (int64, []*LegalHoldLogModel, error)
+}
@@ -0,0 +1,63 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+const (
+	MeetingRoomScheduled = 0
+	MeetingRoomStarted   = 1
+	MeetingRoomEnded     = 2
+	MeetingRoomCanceled  = 3
+)
+
+// MeetingRoomModel is a scheduled call, ParticipantIDs includes the host.
+type MeetingRoomModel struct {
+	RoomID         string    `bson:"room_id"`
+	Title          string    `bson:"title"`
+	HostUserID     string    `bson:"host_user_id"`
+	ParticipantIDs []string  `bson:"participant_ids"`
+	StartTime      time.Time `bson:"start_time"`
+	EndTime        time.Time `bson:"end_time"`
+	Status         int32     `bson:"status"`
+	// Reminded is set once the starting soon notification was sent.
+	Reminded   bool      `bson:"reminded"`
+	CreateTime time.Time `bson:"create_time"`
+	UpdateTime time.Time `bson:"update_time"`
+}
+
+type MeetingRoomModelInterface interface {
+	Create(ctx context.Context, rooms []*MeetingRoomModel) error
+	Take(ctx context.Context, roomID string) (*MeetingRoomModel, error)
+	// Update changes a room while it is still scheduled, reporting false otherwise.
+	Update(ctx context.Context, roomID string, data map[string]any) (bool, error)
+	// UpdateStatus moves the room from status from to status to, reporting false if it was no longer in status from.
+	UpdateStatus(ctx context.Context, roomID string, from int32, to int32) (bool, error)
+	// MarkReminded reports false if the room was already reminded.
+	MarkReminded(ctx context.Context, roomID string) (bool, error)
+	// FindUpcoming returns the scheduled and started rooms of userID by start time.
+	FindUpcoming(ctx context.Context, userID string, pagination pagination.Pagination) (int64, []*MeetingRoomModel, error)
+	// FindToRemind returns the scheduled rooms starting before before that were not reminded yet.
+	FindToRemind(ctx context.Context, before time.Time, limit int64) ([]*MeetingRoomModel, error)
+	// FindDueStart returns the scheduled rooms whose start time has come.
+	FindDueStart(ctx context.Context, now time.Time, limit int64) ([]*MeetingRoomModel, error)
+	// FindDueEnd returns the started rooms whose end time has come.
+	FindDueEnd(ctx context.Context, now time.Time, limit int64) ([]*MeetingRoomModel, error)
+}
@@ -0,0 +1,84 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+// Sources of a moderation flag.
+const (
+	ModerationFlagSourceRule     = "rule"
+	ModerationFlagSourceReviewer = "reviewer"
+	ModerationFlagSourceScorer   = "scorer"
+)
+
+// ModerationRuleModel is a keyword or regex matched against the text of the messages sent by users.
+type ModerationRuleModel struct {
+	RuleID         string    `bson:"rule_id"`
+	Pattern        string    `bson:"pattern"`
+	Regex          bool      `bson:"regex"`
+	Action         string    `bson:"action"`
+	Replacement    string    `bson:"replacement"`
+	OperatorUserID string    `bson:"operator_user_id"`
+	CreateTime     time.Time `bson:"create_time"`
+}
+
+// ModerationFlagModel records a message flagged by a rule or by the external reviewer.
+type ModerationFlagModel struct {
+	ServerMsgID    string   `bson:"server_msg_id"`
+	ClientMsgID    string   `bson:"client_msg_id"`
+	ConversationID string   `bson:"conversation_id"`
+	SendID         string   `bson:"send_id"`
+	ContentType    int32    `bson:"content_type"`
+	Source         string   `bson:"source"`
+	RuleIDs        []string `bson:"rule_ids"`
+	Reason         string   `bson:"reason"`
+	// Scores and Sensitivity are set on the flags of the scorers, the scores of the message and the sensitivity
+	// level of the conversation they were compared to.
+	Scores      map[string]float64 `bson:"scores,omitempty"`
+	Sensitivity string             `bson:"sensitivity,omitempty"`
+	CreateTime  time.Time          `bson:"create_time"`
+}
+
+// ModerationSensitivityModel is the sensitivity level a conversation is scored at, conversations without one use
+// the default of their session type.
+type ModerationSensitivityModel struct {
+	ConversationID string    `bson:"conversation_id"`
+	Level          string    `bson:"level"`
+	OperatorUserID string    `bson:"operator_user_id"`
+	UpdateTime     time.Time `bson:"update_time"`
+}
+
+type ModerationSensitivityModelInterface interface {
+	Set(ctx context.Context, sensitivity *ModerationSensitivityModel) error
+	Delete(ctx context.Context, conversationID string) error
+	// Take returns the sensitivity of the conversation, nil when it has none.
+	Take(ctx context.Context, conversationID string) (*ModerationSensitivityModel, error)
+	Page(ctx context.Context, pagination pagination.Pagination) (int64, []*ModerationSensitivityModel, error)
+}
+
+type ModerationModelInterface interface {
+	CreateRule(ctx context.Context, rule *ModerationRuleModel) error
+	DeleteRules(ctx context.Context, ruleIDs []string) error
+	FindAllRules(ctx context.Context) ([]*ModerationRuleModel, error)
+	PageRules(ctx context.Context, pagination pagination.Pagination) (int64, []*ModerationRuleModel, error)
+	CreateFlag(ctx context.Context, flag *ModerationFlagModel) error
+	// PageFlags returns the flags newest first, only those of sendID when it is not empty.
+	PageFlags(ctx context.Context, sendID string, pagination pagination.Pagination) (int64, []*ModerationFlagModel, error)
+}
@@ -0,0 +1,35 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+// MsgEditHistoryModel keeps the content a message had before one of its edits.
+type MsgEditHistoryModel struct {
+	ConversationID string    `bson:"conversation_id"`
+	Seq            int64     `bson:"seq"`
+	ClientMsgID    string    `bson:"client_msg_id"`
+	EditorUserID   string    `bson:"editor_user_id"`
+	Content        string    `bson:"content"`
+	EditTime       time.Time `bson:"edit_time"`
+}
+
+type MsgEditHistoryModelInterface interface {
+	Create(ctx context.Context, histories []*MsgEditHistoryModel) error
+	Find(ctx context.Context, conversationID string, seq int64) ([]*MsgEditHistoryModel, error)
+}
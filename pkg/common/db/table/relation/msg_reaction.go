@@ -0,0 +1,35 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+// MsgReactionModel is a single emoji reaction left by a user on the message at seq within a conversation.
+type MsgReactionModel struct {
+	ConversationID string    `bson:"conversation_id"`
+	Seq            int64     `bson:"seq"`
+	UserID         string    `bson:"user_id"`
+	Emoji          string    `bson:"emoji"`
+	CreateTime     time.Time `bson:"create_time"`
+}
+
+type MsgReactionModelInterface interface {
+	Set(ctx context.Context, reaction *MsgReactionModel) error
+	Remove(ctx context.Context, conversationID string, seq int64, userID string, emoji string) error
+	Find(ctx context.Context, conversationID string, seq int64) ([]*MsgReactionModel, error)
+}
@@ -0,0 +1,39 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+// MsgReceiptSummaryModel is what remains of the read receipts UserID sent for ConversationID once they
+// are compacted.
+type MsgReceiptSummaryModel struct {
+	ConversationID string `bson:"conversation_id"`
+	UserID         string `bson:"user_id"`
+	// HasReadSeq is the highest seq any of the compacted receipts marked as read.
+	HasReadSeq    int64     `bson:"has_read_seq"`
+	ReceiptCount  int64     `bson:"receipt_count"`
+	FirstReadTime time.Time `bson:"first_read_time"`
+	LastReadTime  time.Time `bson:"last_read_time"`
+	UpdateTime    time.Time `bson:"update_time"`
+}
+
+type MsgReceiptSummaryModelInterface interface {
+	// Merge folds summaries into the stored ones, creating them when missing.
+	Merge(ctx context.Context, summaries []*MsgReceiptSummaryModel) error
+	Find(ctx context.Context, conversationID string, userIDs []string) ([]*MsgReceiptSummaryModel, error)
+}
@@ -0,0 +1,39 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+// MsgRetentionModel overrides the global retainChatRecords of one conversation. Group policies are
+// stored on the group chat conversation, GroupID is kept so they can be told apart.
+type MsgRetentionModel struct {
+	ConversationID string `bson:"conversation_id"`
+	GroupID        string `bson:"group_id"`
+	// RetainDays is how many days messages are kept, 0 keeps them forever.
+	RetainDays int32     `bson:"retain_days"`
+	UpdateTime time.Time `bson:"update_time"`
+}
+
+type MsgRetentionModelInterface interface {
+	Set(ctx context.Context, retention *MsgRetentionModel) error
+	Delete(ctx context.Context, conversationID string) error
+	Find(ctx context.Context, conversationIDs []string) ([]*MsgRetentionModel, error)
+	Page(ctx context.Context, pagination pagination.Pagination) (int64, []*MsgRetentionModel, error)
+}
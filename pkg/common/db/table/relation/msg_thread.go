@@ -0,0 +1,49 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+// MsgThreadModel indexes a reply in the thread of the root message ParentMsgID.
+type MsgThreadModel struct {
+	ConversationID string    `bson:"conversation_id"`
+	ParentMsgID    string    `bson:"parent_msg_id"`
+	ClientMsgID    string    `bson:"client_msg_id"`
+	Seq            int64     `bson:"seq"`
+	SendID         string    `bson:"send_id"`
+	SendTime       time.Time `bson:"send_time"`
+}
+
+// MsgThreadReadModel is the last reply seq a user has read in a thread.
+type MsgThreadReadModel struct {
+	ConversationID string `bson:"conversation_id"`
+	ParentMsgID    string `bson:"parent_msg_id"`
+	UserID         string `bson:"user_id"`
+	HasReadSeq     int64  `bson:"has_read_seq"`
+}
+
+type MsgThreadModelInterface interface {
+	Create(ctx context.Context, replies []*MsgThreadModel) error
+	FindReplies(ctx context.Context, conversationID string, parentMsgID string, pagination pagination.Pagination) (int64, []*MsgThreadModel, error)
+	GetMaxSeq(ctx context.Context, conversationID string, parentMsgID string) (int64, error)
+	CountUnread(ctx context.Context, conversationID string, parentMsgID string, userID string, hasReadSeq int64) (int64, error)
+	SetHasReadSeq(ctx context.Context, conversationID string, parentMsgID string, userID string, seq int64) error
+	GetHasReadSeqs(ctx context.Context, conversationID string, parentMsgIDs []string, userID string) (map[string]int64, error)
+}
@@ -0,0 +1,83 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+const (
+	// NotificationCategorySystem messages are always delivered and cannot be opted out of.
+	NotificationCategorySystem = 1
+	// NotificationCategoryTransactional messages are delivered unless the user opts out.
+	NotificationCategoryTransactional = 2
+	// NotificationCategoryMarketing messages are delivered unless the user opts out.
+	NotificationCategoryMarketing = 3
+)
+
+// IsValidNotificationCategory reports whether category is a known notification category.
+func IsValidNotificationCategory(category int32) bool {
+	switch category {
+	case NotificationCategorySystem, NotificationCategoryTransactional, NotificationCategoryMarketing:
+		return true
+	default:
+		return false
+	}
+}
+
+// NotificationAccountModel holds the attributes of a notification account that are not part of the user profile.
+// Rate and DailyCap override the configured quota of the account, 0 keeps it and a negative value lifts it.
+type NotificationAccountModel struct {
+	UserID     string    `bson:"user_id"`
+	Verified   bool      `bson:"verified"`
+	Category   int32     `bson:"category"`
+	Rate       int32     `bson:"rate"`
+	DailyCap   int32     `bson:"daily_cap"`
+	UpdateTime time.Time `bson:"update_time"`
+}
+
+// NotificationOptOutModel records that a user no longer wants to receive a notification category.
+type NotificationOptOutModel struct {
+	UserID     string    `bson:"user_id"`
+	Category   int32     `bson:"category"`
+	CreateTime time.Time `bson:"create_time"`
+}
+
+// NotificationFollowerModel records that UserID follows the notification account AccountID. It is independent of
+// friendship: following needs no approval and is not mutual.
+type NotificationFollowerModel struct {
+	AccountID  string    `bson:"account_id"`
+	UserID     string    `bson:"user_id"`
+	CreateTime time.Time `bson:"create_time"`
+}
+
+type NotificationAccountModelInterface interface {
+	Upsert(ctx context.Context, account *NotificationAccountModel) error
+	Find(ctx context.Context, userIDs []string) ([]*NotificationAccountModel, error)
+	Take(ctx context.Context, userID string) (*NotificationAccountModel, error)
+	SetQuota(ctx context.Context, userID string, rate int32, dailyCap int32) error
+	SetOptOut(ctx context.Context, userID string, category int32, optOut bool) error
+	FindOptOutCategories(ctx context.Context, userID string) ([]int32, error)
+	// FindOptOutUserIDs returns the subset of userIDs that opted out of category.
+	FindOptOutUserIDs(ctx context.Context, category int32, userIDs []string) ([]string, error)
+	Follow(ctx context.Context, follower *NotificationFollowerModel) error
+	Unfollow(ctx context.Context, accountID string, userID string) error
+	PageFollowerIDs(ctx context.Context, accountID string, pagination pagination.Pagination) (int64, []string, error)
+	PageFollowingIDs(ctx context.Context, userID string, pagination pagination.Pagination) (int64, []string, error)
+	CountFollowers(ctx context.Context, accountID string) (int64, error)
+}
@@ -0,0 +1,44 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+// NotificationProfileModel is the notification sound and vibration a user picked for a conversation or, with an
+// empty ConversationID, for all of them. Sound and Vibration are keys of the client's resources, empty for the
+// device default. Language picks the localized push of messages that carry one. A deleted profile is kept as a tombstone so that the other devices of the user sync its removal.
+type NotificationProfileModel struct {
+	OwnerUserID    string    `bson:"owner_user_id"`
+	ConversationID string    `bson:"conversation_id"`
+	Sound          string    `bson:"sound"`
+	Vibration      string    `bson:"vibration"`
+	Language       string    `bson:"language"`
+	Deleted        bool      `bson:"deleted"`
+	UpdateTime     time.Time `bson:"update_time"`
+}
+
+type NotificationProfileModelInterface interface {
+	// Set creates or replaces the profile, clearing its tombstone.
+	Set(ctx context.Context, profile *NotificationProfileModel) error
+	// Delete turns the profile into a tombstone, reporting false if there was no profile.
+	Delete(ctx context.Context, ownerUserID string, conversationID string, updateTime time.Time) (bool, error)
+	// FindUpdated returns the profiles and tombstones of ownerUserID updated at or after updateTime.
+	FindUpdated(ctx context.Context, ownerUserID string, updateTime time.Time) ([]*NotificationProfileModel, error)
+	// Find returns the profiles of userIDs for conversationIDs, tombstones excluded.
+	Find(ctx context.Context, userIDs []string, conversationIDs []string) ([]*NotificationProfileModel, error)
+}
@@ -35,4 +35,7 @@ type ObjectInfoModelInterface interface {
 	SetObject(ctx context.Context, obj *ObjectModel) error
 	Take(ctx context.Context, engine string, name string) (*ObjectModel, error)
 	Delete(ctx context.Context, engine string, name string) error
+	FindByUserID(ctx context.Context, userID string) ([]*ObjectModel, error)
+	// FindByKey returns the objects stored under key, uploads of the same content share a key.
+	FindByKey(ctx context.Context, engine string, key string) ([]*ObjectModel, error)
 }
@@ -0,0 +1,52 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+// Targets of an onboarding rule.
+const (
+	OnboardingTargetGroup    = "group"
+	OnboardingTargetUser     = "user"
+	OnboardingTargetOfficial = "official"
+)
+
+// OnboardingRuleModel suggests a group, a user or an official account to the new users of AppID, to every app when
+// AppID is empty. A rule with a Region is only suggested to the users of that region. The groups of the rules
+// with AutoJoin and no Region are joined at registration.
+type OnboardingRuleModel struct {
+	RuleID         string    `bson:"rule_id"`
+	AppID          string    `bson:"app_id"`
+	Region         string    `bson:"region"`
+	TargetType     string    `bson:"target_type"`
+	TargetID       string    `bson:"target_id"`
+	AutoJoin       bool      `bson:"auto_join"`
+	Priority       int32     `bson:"priority"`
+	OperatorUserID string    `bson:"operator_user_id"`
+	CreateTime     time.Time `bson:"create_time"`
+}
+
+type OnboardingRuleModelInterface interface {
+	Create(ctx context.Context, rule *OnboardingRuleModel) error
+	Delete(ctx context.Context, ruleIDs []string) error
+	// Find returns the rules of appIDs.
+	Find(ctx context.Context, appIDs []string) ([]*OnboardingRuleModel, error)
+	Page(ctx context.Context, appID string, pagination pagination.Pagination) (int64, []*OnboardingRuleModel, error)
+}
@@ -0,0 +1,50 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+const (
+	ScheduledMsgPending  = 0
+	ScheduledMsgSending  = 1
+	ScheduledMsgSent     = 2
+	ScheduledMsgCanceled = 3
+	ScheduledMsgFailed   = 4
+)
+
+// ScheduledMsgModel is a message waiting for its delivery time, MsgData is the marshaled sdkws.MsgData.
+type ScheduledMsgModel struct {
+	ScheduleID   string    `bson:"schedule_id"`
+	SendID       string    `bson:"send_id"`
+	MsgData      []byte    `bson:"msg_data"`
+	ScheduleTime time.Time `bson:"schedule_time"`
+	Status       int32     `bson:"status"`
+	Err          string    `bson:"err"`
+	CreateTime   time.Time `bson:"create_time"`
+}
+
+type ScheduledMsgModelInterface interface {
+	Create(ctx context.Context, msgs []*ScheduledMsgModel) error
+	Take(ctx context.Context, scheduleID string) (*ScheduledMsgModel, error)
+	FindBySendID(ctx context.Context, sendID string, status []int32, pagination pagination.Pagination) (int64, []*ScheduledMsgModel, error)
+	FindDue(ctx context.Context, now time.Time, limit int64) ([]*ScheduledMsgModel, error)
+	// UpdateStatus moves the message from status from to status to, reporting false if it was no longer in status from.
+	UpdateStatus(ctx context.Context, scheduleID string, from int32, to int32, errMsg string) (bool, error)
+}
@@ -0,0 +1,31 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import "context"
+
+// SeqModel is the high-water mark of the seqs leased for a conversation, no seq above MaxSeq was handed out.
+type SeqModel struct {
+	ConversationID string `bson:"conversation_id"`
+	MaxSeq         int64  `bson:"max_seq"`
+}
+
+type SeqModelInterface interface {
+	// Malloc raises the mark by size and returns the mark before, 0 for a conversation without one.
+	Malloc(ctx context.Context, conversationID string, size int64) (int64, error)
+	// Raise sets the mark to seq when it is lower.
+	Raise(ctx context.Context, conversationID string, seq int64) error
+	Get(ctx context.Context, conversationID string) (int64, error)
+}
@@ -0,0 +1,31 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import "github.com/OpenIMSDK/tools/tx"
+
+// Storage is the backend of the relational data: users, friends, groups and conversations.
+type Storage interface {
+	// Tx returns the transactions of the backend, the models only join the ones started through it.
+	Tx() tx.CtxTx
+	User() (UserModelInterface, error)
+	Friend() (FriendModelInterface, error)
+	FriendRequest() (FriendRequestModelInterface, error)
+	Black() (BlackModelInterface, error)
+	Group() (GroupModelInterface, error)
+	GroupMember() (GroupMemberModelInterface, error)
+	GroupRequest() (GroupRequestModelInterface, error)
+	Conversation() (ConversationModelInterface, error)
+}
@@ -0,0 +1,38 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+// TenantConfigModel overrides sections of the global config for the app AppID. Overrides is a yaml
+// document shaped like config.yaml holding only the overridden keys.
+type TenantConfigModel struct {
+	AppID      string    `bson:"app_id"`
+	Overrides  string    `bson:"overrides"`
+	UpdateTime time.Time `bson:"update_time"`
+}
+
+type TenantConfigModelInterface interface {
+	Set(ctx context.Context, tenantConfig *TenantConfigModel) error
+	Delete(ctx context.Context, appID string) error
+	Find(ctx context.Context, appIDs []string) ([]*TenantConfigModel, error)
+	FindAll(ctx context.Context) ([]*TenantConfigModel, error)
+	Page(ctx context.Context, pagination pagination.Pagination) (int64, []*TenantConfigModel, error)
+}
@@ -59,6 +59,8 @@ type UserModelInterface interface {
 	PageFindUser(ctx context.Context, level1 int64, level2 int64, pagination pagination.Pagination) (count int64, users []*UserModel, err error)
 	PageFindUserWithKeyword(ctx context.Context, level1 int64, level2 int64, userID, nickName string, pagination pagination.Pagination) (count int64, users []*UserModel, err error)
 	Exist(ctx context.Context, userID string) (exist bool, err error)
+	// Delete removes the user and its commands.
+	Delete(ctx context.Context, userID string) (err error)
 	GetAllUserID(ctx context.Context, pagination pagination.Pagination) (count int64, userIDs []string, err error)
 	GetUserGlobalRecvMsgOpt(ctx context.Context, userID string) (opt int, err error)
 	// Get user total quantity
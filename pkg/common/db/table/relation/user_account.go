@@ -0,0 +1,50 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	UserAccountActive      = 0
+	UserAccountDeactivated = 1
+	UserAccountDeleting    = 2
+	UserAccountDeleted     = 3
+)
+
+// UserAccountModel is the account state of a user, users without one are active. A deleting user is detached
+// from its friends and groups by the user purge task, which queues its purge once DeleteTime has passed.
+type UserAccountModel struct {
+	UserID         string    `bson:"user_id"`
+	Status         int32     `bson:"status"`
+	Reason         string    `bson:"reason"`
+	OperatorUserID string    `bson:"operator_user_id"`
+	Detached       bool      `bson:"detached"`
+	DeleteTime     time.Time `bson:"delete_time"`
+	PurgeID        string    `bson:"purge_id"`
+	UpdateTime     time.Time `bson:"update_time"`
+}
+
+type UserAccountModelInterface interface {
+	Set(ctx context.Context, account *UserAccountModel) error
+	Find(ctx context.Context, userIDs []string) ([]*UserAccountModel, error)
+	// FindDeleting returns the deleting accounts that are not detached yet or whose DeleteTime is before now.
+	FindDeleting(ctx context.Context, now time.Time, limit int64) ([]*UserAccountModel, error)
+	// Update applies update to the account of userID if it is still in status, reporting false if it was not.
+	Update(ctx context.Context, userID string, status int32, update map[string]any) (bool, error)
+	Delete(ctx context.Context, userID string) error
+}
@@ -0,0 +1,37 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+// UserBlockModel is a global block: BlockUserID can neither message, @-mention nor send friend requests to
+// OwnerUserID, whether or not they are friends. Unlike BlackModel it is not part of the friend relation.
+type UserBlockModel struct {
+	OwnerUserID string    `bson:"owner_user_id"`
+	BlockUserID string    `bson:"block_user_id"`
+	CreateTime  time.Time `bson:"create_time"`
+}
+
+type UserBlockModelInterface interface {
+	Create(ctx context.Context, blocks []*UserBlockModel) error
+	Delete(ctx context.Context, ownerUserID string, blockUserIDs []string) error
+	FindBlockUserIDs(ctx context.Context, ownerUserID string) ([]string, error)
+	Page(ctx context.Context, ownerUserID string, pagination pagination.Pagination) (int64, []*UserBlockModel, error)
+}
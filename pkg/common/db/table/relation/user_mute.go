@@ -0,0 +1,40 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenIMSDK/tools/pagination"
+)
+
+// UserMuteModel is a personal mute: the messages of MuteUserID are still delivered to and stored for OwnerUserID,
+// but they neither trigger an offline push nor count as unread for OwnerUserID. Unlike UserBlockModel it does
+// not stop MuteUserID from sending.
+type UserMuteModel struct {
+	OwnerUserID string    `bson:"owner_user_id"`
+	MuteUserID  string    `bson:"mute_user_id"`
+	CreateTime  time.Time `bson:"create_time"`
+}
+
+type UserMuteModelInterface interface {
+	Create(ctx context.Context, mutes []*UserMuteModel) error
+	Delete(ctx context.Context, ownerUserID string, muteUserIDs []string) error
+	FindMuteUserIDs(ctx context.Context, ownerUserID string) ([]string, error)
+	// FindOwnerUserIDs returns the users who muted muteUserID.
+	FindOwnerUserIDs(ctx context.Context, muteUserID string) ([]string, error)
+	Page(ctx context.Context, ownerUserID string, pagination pagination.Pagination) (int64, []*UserMuteModel, error)
+}
@@ -0,0 +1,48 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+// Who may send a user friend requests.
+const (
+	AddFriendPolicyAnyone             = 0
+	AddFriendPolicyContactsOfContacts = 1
+	AddFriendPolicyNobody             = 2
+)
+
+// UserPrivacyModel holds the privacy settings of a user, users without a document use DefaultUserPrivacy.
+// The Search fields are stored as "hide" flags so that the zero value means searchable.
+type UserPrivacyModel struct {
+	UserID          string    `bson:"user_id"`
+	AddFriendPolicy int32     `bson:"add_friend_policy"`
+	HideFromID      bool      `bson:"hide_from_id"`
+	HideFromPhone   bool      `bson:"hide_from_phone"`
+	HideFromName    bool      `bson:"hide_from_name"`
+	UpdateTime      time.Time `bson:"update_time"`
+}
+
+// DefaultUserPrivacy returns the settings of a user who never changed them.
+func DefaultUserPrivacy(userID string) *UserPrivacyModel {
+	return &UserPrivacyModel{UserID: userID, AddFriendPolicy: AddFriendPolicyAnyone}
+}
+
+type UserPrivacyModelInterface interface {
+	Set(ctx context.Context, privacy *UserPrivacyModel) error
+	Find(ctx context.Context, userIDs []string) ([]*UserPrivacyModel, error)
+}
@@ -0,0 +1,56 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package relation
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	UserPurgePending  = 0
+	UserPurgeRunning  = 1
+	UserPurgeFinished = 2
+	UserPurgeFailed   = 3
+)
+
+// UserPurgeModel is an erasure request for a user and, once run, its completion report.
+type UserPurgeModel struct {
+	PurgeID        string                `bson:"purge_id"`
+	UserID         string                `bson:"user_id"`
+	OperatorUserID string                `bson:"operator_user_id"`
+	Status         int32                 `bson:"status"`
+	Steps          []*UserPurgeStepModel `bson:"steps"`
+	CreateTime     time.Time             `bson:"create_time"`
+	FinishTime     time.Time             `bson:"finish_time"`
+}
+
+// UserPurgeStepModel records one erasure step, Count is the number of records it erased or anonymized.
+type UserPurgeStepModel struct {
+	Name       string    `bson:"name"`
+	Count      int64     `bson:"count"`
+	Err        string    `bson:"err"`
+	FinishTime time.Time `bson:"finish_time"`
+}
+
+type UserPurgeModelInterface interface {
+	Create(ctx context.Context, purge *UserPurgeModel) error
+	Take(ctx context.Context, purgeID string) (*UserPurgeModel, error)
+	FindByUserID(ctx context.Context, userID string) ([]*UserPurgeModel, error)
+	FindPending(ctx context.Context, limit int64) ([]*UserPurgeModel, error)
+	// UpdateStatus moves the purge from status from to status to, reporting false if it was no longer in status from.
+	UpdateStatus(ctx context.Context, purgeID string, from int32, to int32) (bool, error)
+	Finish(ctx context.Context, purgeID string, status int32, steps []*UserPurgeStepModel, finishTime time.Time) error
+}
@@ -17,6 +17,7 @@ package relation
 import (
 	"github.com/OpenIMSDK/tools/utils"
 	"go.mongodb.org/mongo-driver/mongo"
+	"gorm.io/gorm"
 )
 
 type BatchUpdateGroupMember struct {
@@ -31,5 +32,6 @@ type GroupSimpleUserID struct {
 }
 
 func IsNotFound(err error) bool {
-	return utils.Unwrap(err) == mongo.ErrNoDocuments
+	err = utils.Unwrap(err)
+	return err == mongo.ErrNoDocuments || err == gorm.ErrRecordNotFound
 }
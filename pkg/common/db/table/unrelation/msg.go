@@ -84,6 +84,12 @@ type MsgInfoModel struct {
 	IsRead  bool          `bson:"is_read"`
 }
 
+// SampledMsg is a message picked by SampleMsgsSince together with the doc it is stored in.
+type SampledMsg struct {
+	DocID string        `bson:"doc_id"`
+	Msg   *MsgDataModel `bson:"msg"`
+}
+
 type UserCount struct {
 	UserID string `bson:"user_id"`
 	Count  int64  `bson:"count"`
@@ -98,6 +104,8 @@ type MsgDocModelInterface interface {
 	PushMsgsToDoc(ctx context.Context, docID string, msgsToMongo []MsgInfoModel) error
 	Create(ctx context.Context, model *MsgDocModel) error
 	UpdateMsg(ctx context.Context, docID string, index int64, key string, value any) (*mongo.UpdateResult, error)
+	// SetEmptyMsg replaces the slot at index of docID with value unless it holds a message, it returns whether the slot was set.
+	SetEmptyMsg(ctx context.Context, docID string, index int64, value *MsgInfoModel) (bool, error)
 	PushUnique(ctx context.Context, docID string, index int64, key string, value any) (*mongo.UpdateResult, error)
 	UpdateMsgContent(ctx context.Context, docID string, index int64, msg []byte) error
 	IsExistDocID(ctx context.Context, docID string) (bool, error)
@@ -105,10 +113,25 @@ type MsgDocModelInterface interface {
 	GetMsgBySeqIndexIn1Doc(ctx context.Context, userID, docID string, seqs []int64) ([]*MsgInfoModel, error)
 	GetNewestMsg(ctx context.Context, conversationID string) (*MsgInfoModel, error)
 	GetOldestMsg(ctx context.Context, conversationID string) (*MsgInfoModel, error)
+	// GetFirstSeqSince returns the smallest seq of the conversation sent at or after sendTime (ms).
+	GetFirstSeqSince(ctx context.Context, conversationID string, sendTime int64) (int64, error)
+	// FindMsgsByContentTypeBefore returns up to limit messages of the conversation with the given content type
+	// sent before sendTime (ms), ordered by seq.
+	FindMsgsByContentTypeBefore(ctx context.Context, conversationID string, contentType int32, sendTime int64, limit int64) ([]*MsgInfoModel, error)
+	// FindMsgsBetween returns up to limit messages of the conversation sent at or after start and before end (ms),
+	// ordered by seq.
+	FindMsgsBetween(ctx context.Context, conversationID string, start int64, end int64, limit int64) ([]*MsgInfoModel, error)
+	// SampleMsgsSince returns the number of messages sent at or after sendTime (ms) and up to size of them picked at random.
+	SampleMsgsSince(ctx context.Context, sendTime int64, size int64) (int64, []*SampledMsg, error)
+	// ExistMsgContentMatch reports whether the content of any stored message matches the regular expression pattern.
+	ExistMsgContentMatch(ctx context.Context, pattern string) (bool, error)
 	DeleteDocs(ctx context.Context, docIDs []string) error
 	GetMsgDocModelByIndex(ctx context.Context, conversationID string, index, sort int64) (*MsgDocModel, error)
 	DeleteMsgsInOneDocByIndex(ctx context.Context, docID string, indexes []int) error
 	MarkSingleChatMsgsAsRead(ctx context.Context, userID string, docID string, indexes []int64) error
+	// AnonymizeUserMsgs replaces userID with alias as sender, receiver and revoker of every stored message
+	// and clears its nickname and face url, it returns the number of updated docs.
+	AnonymizeUserMsgs(ctx context.Context, userID string, alias string) (int64, error)
 	SearchMessage(ctx context.Context, req *msg.SearchMessageReq) (int32, []*MsgInfoModel, error)
 	RangeUserSendCount(
 		ctx context.Context,
@@ -28,6 +28,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 const (
@@ -35,6 +36,13 @@ const (
 	mongoConnTimeout = 10 * time.Second
 )
 
+// Controllers whose heavy reads follow mongo.readPreference.
+const (
+	MsgReadController   = "msg"
+	UserReadController  = "user"
+	GroupReadController = "group"
+)
+
 type Mongo struct {
 	db     *mongo.Client
 	config *config.GlobalConfig
@@ -43,6 +51,9 @@ type Mongo struct {
 // NewMongo Initialize MongoDB connection.
 func NewMongo(config *config.GlobalConfig) (*Mongo, error) {
 	specialerror.AddReplace(mongo.ErrNoDocuments, errs.ErrRecordNotFound)
+	if err := checkReadPreference(config); err != nil {
+		return nil, err
+	}
 	uri := buildMongoURI(config)
 
 	var mongoClient *mongo.Client
@@ -109,6 +120,51 @@ func buildMongoURI(config *config.GlobalConfig) string {
 	return fmt.Sprintf("mongodb://%s/%s?maxPoolSize=%s", address, database, maxPoolSize)
 }
 
+func checkReadPreference(config *config.GlobalConfig) error {
+	for controller := range config.Mongo.ReadPreference.Controllers {
+		switch controller {
+		case MsgReadController, UserReadController, GroupReadController:
+		default:
+			return errs.ErrArgs.Wrap("mongo.readPreference: unknown controller " + controller)
+		}
+	}
+	for _, controller := range []string{MsgReadController, UserReadController, GroupReadController} {
+		if _, err := readPreference(config, controller); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readPreference returns the read preference of the heavy reads of controller, its override in
+// mongo.readPreference.controllers comes before mongo.readPreference.mode.
+func readPreference(config *config.GlobalConfig, controller string) (*readpref.ReadPref, error) {
+	conf := config.Mongo.ReadPreference
+	mode := conf.Mode
+	if override := conf.Controllers[controller]; override != "" {
+		mode = override
+	}
+	if mode == "" {
+		return readpref.Primary(), nil
+	}
+	m, err := readpref.ModeFromString(mode)
+	if err != nil {
+		return nil, errs.Wrap(err, "mongo.readPreference")
+	}
+	if m == readpref.PrimaryMode {
+		return readpref.Primary(), nil
+	}
+	var opts []readpref.Option
+	if conf.MaxStaleness > 0 {
+		opts = append(opts, readpref.WithMaxStaleness(time.Duration(conf.MaxStaleness)*time.Second))
+	}
+	rp, err := readpref.New(m, opts...)
+	if err != nil {
+		return nil, errs.Wrap(err, "mongo.readPreference")
+	}
+	return rp, nil
+}
+
 func shouldRetry(err error) bool {
 	if cmdErr, ok := err.(mongo.CommandError); ok {
 		return cmdErr.Code != 13 && cmdErr.Code != 18
@@ -121,6 +177,15 @@ func (m *Mongo) GetClient() *mongo.Client {
 	return m.db
 }
 
+// ReadPreference returns the read preference of the heavy reads of controller, it was checked when connecting.
+func (m *Mongo) ReadPreference(controller string) *readpref.ReadPref {
+	rp, err := readPreference(m.config, controller)
+	if err != nil {
+		return readpref.Primary()
+	}
+	return rp
+}
+
 // GetDatabase returns the specific database from MongoDB.
 func (m *Mongo) GetDatabase(database string) *mongo.Database {
 	return m.db.Database(database)
@@ -31,6 +31,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -38,12 +39,19 @@ var ErrMsgListNotExist = errors.New("user not have msg in mongoDB")
 
 type MsgMongoDriver struct {
 	MsgCollection *mongo.Collection
-	model         table.MsgDocModel
+	// readCollection serves the history pulls, searches and statistics with the msg read preference.
+	readCollection *mongo.Collection
+	model          table.MsgDocModel
 }
 
-func NewMsgMongoDriver(database *mongo.Database) table.MsgDocModelInterface {
+// NewMsgMongoDriver returns the message documents, a nil readPref reads everything from the primary.
+func NewMsgMongoDriver(database *mongo.Database, readPref *readpref.ReadPref) table.MsgDocModelInterface {
 	collection := database.Collection(table.MsgDocModel{}.TableName())
-	return &MsgMongoDriver{MsgCollection: collection}
+	readCollection := collection
+	if readPref != nil {
+		readCollection = database.Collection(table.MsgDocModel{}.TableName(), options.Collection().SetReadPreference(readPref))
+	}
+	return &MsgMongoDriver{MsgCollection: collection, readCollection: readCollection}
 }
 
 func (m *MsgMongoDriver) PushMsgsToDoc(ctx context.Context, docID string, msgsToMongo []table.MsgInfoModel) error {
@@ -78,6 +86,16 @@ func (m *MsgMongoDriver) UpdateMsg(
 	return res, nil
 }
 
+func (m *MsgMongoDriver) SetEmptyMsg(ctx context.Context, docID string, index int64, value *table.MsgInfoModel) (bool, error) {
+	filter := bson.M{"doc_id": docID, fmt.Sprintf("msgs.%d.msg", index): nil}
+	update := bson.M{"$set": bson.M{fmt.Sprintf("msgs.%d", index): value}}
+	res, err := m.MsgCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, errs.Wrap(err)
+	}
+	return res.ModifiedCount > 0, nil
+}
+
 // PushUnique value must slice.
 func (m *MsgMongoDriver) PushUnique(
 	ctx context.Context,
@@ -200,6 +218,124 @@ func (m *MsgMongoDriver) GetOldestMsg(ctx context.Context, conversationID string
 	}
 }
 
+func (m *MsgMongoDriver) GetFirstSeqSince(ctx context.Context, conversationID string, sendTime int64) (int64, error) {
+	pipeline := []bson.M{
+		{
+			"$match": bson.M{
+				"doc_id":             primitive.Regex{Pattern: fmt.Sprintf("^%s:", conversationID)},
+				"msgs.msg.send_time": bson.M{"$gte": sendTime},
+			},
+		},
+		{"$unwind": "$msgs"},
+		{"$match": bson.M{"msgs.msg.send_time": bson.M{"$gte": sendTime}}},
+		{"$sort": bson.M{"msgs.msg.seq": 1}},
+		{"$limit": 1},
+		{"$project": bson.M{"_id": 0, "seq": "$msgs.msg.seq"}},
+	}
+	cursor, err := m.MsgCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, errs.Wrap(err, fmt.Sprintf("conversationID is %s", conversationID))
+	}
+	var res []struct {
+		Seq int64 `bson:"seq"`
+	}
+	if err := cursor.All(ctx, &res); err != nil {
+		return 0, errs.Wrap(err)
+	}
+	if len(res) == 0 {
+		return 0, ErrMsgListNotExist
+	}
+	return res[0].Seq, nil
+}
+
+func (m *MsgMongoDriver) FindMsgsByContentTypeBefore(ctx context.Context, conversationID string, contentType int32, sendTime int64, limit int64) ([]*table.MsgInfoModel, error) {
+	filter := bson.M{
+		"msgs.msg.content_type": contentType,
+		"msgs.msg.send_time":    bson.M{"$lt": sendTime},
+	}
+	pipeline := []bson.M{
+		{
+			"$match": bson.M{
+				"doc_id":                primitive.Regex{Pattern: fmt.Sprintf("^%s:", conversationID)},
+				"msgs.msg.content_type": contentType,
+			},
+		},
+		{"$unwind": "$msgs"},
+		{"$match": filter},
+		{"$sort": bson.M{"msgs.msg.seq": 1}},
+		{"$limit": limit},
+		{"$replaceRoot": bson.M{"newRoot": "$msgs"}},
+	}
+	cursor, err := m.readCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Sprintf("conversationID is %s", conversationID))
+	}
+	var msgs []*table.MsgInfoModel
+	if err := cursor.All(ctx, &msgs); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return msgs, nil
+}
+
+func (m *MsgMongoDriver) FindMsgsBetween(ctx context.Context, conversationID string, start int64, end int64, limit int64) ([]*table.MsgInfoModel, error) {
+	sendTime := bson.M{"$gte": start, "$lt": end}
+	pipeline := []bson.M{
+		{
+			"$match": bson.M{
+				"doc_id":             primitive.Regex{Pattern: fmt.Sprintf("^%s:", conversationID)},
+				"msgs.msg.send_time": sendTime,
+			},
+		},
+		{"$unwind": "$msgs"},
+		{"$match": bson.M{"msgs.msg.send_time": sendTime}},
+		{"$sort": bson.M{"msgs.msg.seq": 1}},
+		{"$limit": limit},
+		{"$replaceRoot": bson.M{"newRoot": "$msgs"}},
+	}
+	cursor, err := m.readCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, errs.Wrap(err, fmt.Sprintf("conversationID is %s", conversationID))
+	}
+	var msgs []*table.MsgInfoModel
+	if err := cursor.All(ctx, &msgs); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return msgs, nil
+}
+
+func (m *MsgMongoDriver) SampleMsgsSince(ctx context.Context, sendTime int64, size int64) (int64, []*table.SampledMsg, error) {
+	since := []bson.M{
+		{"$match": bson.M{"msgs.msg.send_time": bson.M{"$gte": sendTime}}},
+		{"$unwind": "$msgs"},
+		{"$match": bson.M{"msgs.msg.send_time": bson.M{"$gte": sendTime}}},
+	}
+	cursor, err := m.readCollection.Aggregate(ctx, append(since, bson.M{"$count": "count"}))
+	if err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	var count []struct {
+		Count int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &count); err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	if len(count) == 0 {
+		return 0, nil, nil
+	}
+	cursor, err = m.readCollection.Aggregate(ctx, append(since,
+		bson.M{"$sample": bson.M{"size": size}},
+		bson.M{"$project": bson.M{"_id": 0, "doc_id": 1, "msg": "$msgs.msg"}},
+	))
+	if err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	var msgs []*table.SampledMsg
+	if err := cursor.All(ctx, &msgs); err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	return count[0].Count, msgs, nil
+}
+
 func (m *MsgMongoDriver) DeleteMsgsInOneDocByIndex(ctx context.Context, docID string, indexes []int) error {
 	updates := bson.M{
 		"$set": bson.M{},
@@ -271,7 +407,7 @@ func (m *MsgMongoDriver) GetMsgBySeqIndexIn1Doc(
 		}}},
 	}
 
-	cur, err := m.MsgCollection.Aggregate(ctx, pipeline)
+	cur, err := m.readCollection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, errs.Wrap(err)
 	}
@@ -330,6 +466,14 @@ func (m *MsgMongoDriver) IsExistDocID(ctx context.Context, docID string) (bool,
 	return count > 0, nil
 }
 
+func (m *MsgMongoDriver) ExistMsgContentMatch(ctx context.Context, pattern string) (bool, error) {
+	count, err := m.MsgCollection.CountDocuments(ctx, bson.M{"msgs.msg.content": primitive.Regex{Pattern: pattern}}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, errs.Wrap(err, fmt.Sprintf("pattern is %s", pattern))
+	}
+	return count > 0, nil
+}
+
 func (m *MsgMongoDriver) MarkSingleChatMsgsAsRead(ctx context.Context, userID string, docID string, indexes []int64) error {
 	updates := []mongo.WriteModel{}
 	for _, index := range indexes {
@@ -353,6 +497,36 @@ func (m *MsgMongoDriver) MarkSingleChatMsgsAsRead(ctx context.Context, userID st
 	return errs.Wrap(err, fmt.Sprintf("docID is %s, indexes is %v", docID, indexes))
 }
 
+func (m *MsgMongoDriver) AnonymizeUserMsgs(ctx context.Context, userID string, alias string) (int64, error) {
+	updates := []struct {
+		field string
+		set   bson.M
+	}{
+		{
+			field: "msg.send_id",
+			set:   bson.M{"msgs.$[m].msg.send_id": alias, "msgs.$[m].msg.sender_nickname": "", "msgs.$[m].msg.sender_face_url": ""},
+		},
+		{
+			field: "msg.recv_id",
+			set:   bson.M{"msgs.$[m].msg.recv_id": alias},
+		},
+		{
+			field: "revoke.user_id",
+			set:   bson.M{"msgs.$[m].revoke.user_id": alias, "msgs.$[m].revoke.nickname": ""},
+		},
+	}
+	var count int64
+	for _, update := range updates {
+		opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []any{bson.M{"m." + update.field: userID}}})
+		res, err := m.MsgCollection.UpdateMany(ctx, bson.M{"msgs." + update.field: userID}, bson.M{"$set": update.set}, opts)
+		if err != nil {
+			return count, errs.Wrap(err, "anonymize "+update.field)
+		}
+		count += res.ModifiedCount
+	}
+	return count, nil
+}
+
 // RangeUserSendCount
 // db.msg.aggregate([
 //
@@ -773,7 +947,7 @@ func (m *MsgMongoDriver) RangeUserSendCount(
 			},
 		},
 	}
-	cur, err := m.MsgCollection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	cur, err := m.readCollection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
 	if err != nil {
 		return 0, 0, nil, nil, errs.Wrap(err)
 	}
@@ -1022,7 +1196,7 @@ func (m *MsgMongoDriver) RangeGroupSendCount(
 			},
 		},
 	}
-	cur, err := m.MsgCollection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	cur, err := m.readCollection.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
 	if err != nil {
 		return 0, 0, nil, nil, errs.Wrap(err)
 	}
@@ -1098,7 +1272,7 @@ func (m *MsgMongoDriver) searchMessage(ctx context.Context, req *msg.SearchMessa
 		{{"$unwind", bson.M{"path": "$msgs"}}},
 		{{"$sort", bson.M{"msgs.msg.send_time": -1}}},
 	}
-	cursor, err := m.MsgCollection.Aggregate(ctx, pipe)
+	cursor, err := m.readCollection.Aggregate(ctx, pipe)
 	if err != nil {
 		return 0, nil, err
 	}
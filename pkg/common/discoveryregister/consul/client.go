@@ -0,0 +1,184 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consul
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+)
+
+const (
+	requestTimeout = 5 * time.Second
+	// blockingWait is how long a blocking health query waits for a change before it returns unchanged.
+	blockingWait = 5 * time.Minute
+)
+
+// client is a minimal client of the consul http api, enough for registration, health lookups and kv.
+type client struct {
+	address string
+	token   string
+	http    *http.Client
+}
+
+type serviceRegistration struct {
+	ID      string        `json:"ID"`
+	Name    string        `json:"Name"`
+	Address string        `json:"Address"`
+	Port    int           `json:"Port"`
+	Check   *serviceCheck `json:"Check,omitempty"`
+}
+
+type serviceCheck struct {
+	CheckID                        string `json:"CheckID"`
+	TTL                            string `json:"TTL"`
+	DeregisterCriticalServiceAfter string `json:"DeregisterCriticalServiceAfter"`
+}
+
+type serviceEntry struct {
+	Service struct {
+		Address string `json:"Address"`
+		Port    int    `json:"Port"`
+	} `json:"Service"`
+	Node struct {
+		Address string `json:"Address"`
+	} `json:"Node"`
+}
+
+func newClient(address, token string) *client {
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "http://" + address
+	}
+	return &client{address: strings.TrimSuffix(address, "/"), token: token, http: &http.Client{}}
+}
+
+// do sends the request and returns the body and the X-Consul-Index of the response.
+func (c *client) do(ctx context.Context, method, path string, query url.Values, body io.Reader) ([]byte, uint64, error) {
+	u := c.address + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, 0, errs.Wrap(err)
+	}
+	if c.token != "" {
+		req.Header.Set("X-Consul-Token", c.token)
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return nil, 0, errs.Wrap(err, "address", c.address)
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, 0, errs.Wrap(err)
+	}
+	index, _ := strconv.ParseUint(res.Header.Get("X-Consul-Index"), 10, 64)
+	if res.StatusCode == http.StatusNotFound {
+		return nil, index, nil
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, 0, errs.Wrap(fmt.Errorf("consul %s %s status %d: %s", method, path, res.StatusCode, data))
+	}
+	return data, index, nil
+}
+
+func (c *client) call(ctx context.Context, method, path string, query url.Values, body any) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		reader = bytes.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	data, _, err := c.do(ctx, method, path, query, reader)
+	return data, err
+}
+
+func (c *client) register(ctx context.Context, reg *serviceRegistration) error {
+	_, err := c.call(ctx, http.MethodPut, "/v1/agent/service/register", nil, reg)
+	return err
+}
+
+func (c *client) deregister(ctx context.Context, serviceID string) error {
+	_, err := c.call(ctx, http.MethodPut, "/v1/agent/service/deregister/"+url.PathEscape(serviceID), nil, nil)
+	return err
+}
+
+// passCheck reports the ttl check as passing. It fails when the agent no longer knows the check.
+func (c *client) passCheck(ctx context.Context, checkID string) error {
+	_, err := c.call(ctx, http.MethodPut, "/v1/agent/check/pass/"+url.PathEscape(checkID), nil, nil)
+	return err
+}
+
+// healthyAddrs returns the host:port of the passing instances of service. With a non zero index the
+// call blocks until the result changes past index or blockingWait elapses, and returns the new index.
+func (c *client) healthyAddrs(ctx context.Context, service string, index uint64) ([]string, uint64, error) {
+	query := url.Values{"passing": []string{"true"}}
+	timeout := requestTimeout
+	if index > 0 {
+		query.Set("index", strconv.FormatUint(index, 10))
+		query.Set("wait", blockingWait.String())
+		timeout += blockingWait
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	data, newIndex, err := c.do(ctx, http.MethodGet, "/v1/health/service/"+url.PathEscape(service), query, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	var entries []serviceEntry
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, 0, errs.Wrap(err)
+		}
+	}
+	addrs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		host := entry.Service.Address
+		if host == "" {
+			host = entry.Node.Address
+		}
+		addrs = append(addrs, fmt.Sprintf("%s:%d", host, entry.Service.Port))
+	}
+	return addrs, newIndex, nil
+}
+
+func (c *client) putKV(ctx context.Context, key string, value []byte) error {
+	_, err := c.call(ctx, http.MethodPut, "/v1/kv/"+key, nil, value)
+	return err
+}
+
+// getKV returns the raw value of key, nil if it does not exist.
+func (c *client) getKV(ctx context.Context, key string) ([]byte, error) {
+	return c.call(ctx, http.MethodGet, "/v1/kv/"+key, url.Values{"raw": []string{"true"}}, nil)
+}
@@ -0,0 +1,386 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consul
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/tools/discoveryregistry"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/resolver"
+)
+
+const (
+	// checkTTL is the ttl of the health check, it is passed every checkTTL/3.
+	checkTTL = 15 * time.Second
+	// deregisterAfter removes instances whose check stayed critical, e.g. after a crash.
+	deregisterAfter = "1m"
+	// retryInterval is the pause before a failed health query is retried.
+	retryInterval = time.Second
+
+	confDir = "conf"
+)
+
+var _ discoveryregistry.SvcDiscoveryRegistry = (*ConsulDR)(nil)
+
+// ConsulDR registers every instance in the local consul agent with a ttl health check and resolves
+// services through the passing instances of the health api, so it joins an existing consul mesh.
+type ConsulDR struct {
+	client *client
+	schema string
+	opts   []grpc.DialOption
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	lock       sync.Mutex
+	conns      map[string][]*grpc.ClientConn
+	self       string
+	reg        *serviceRegistration
+	unregister context.CancelFunc
+}
+
+// NewConsulDiscoveryRegister creates a new instance of ConsulDR for consul service discovery and registration.
+func NewConsulDiscoveryRegister(config *config.GlobalConfig) (discoveryregistry.SvcDiscoveryRegistry, error) {
+	schema := getEnv("CONSUL_SCHEMA", config.Consul.Schema)
+	address := getConsulAddrFromEnv(config.Consul.Address)
+	token := getEnv("CONSUL_TOKEN", config.Consul.Token)
+	if address == "" {
+		return nil, errs.Wrap(errors.New("consul address is empty"))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	dr := &ConsulDR{
+		client: newClient(address, token),
+		schema: schema,
+		ctx:    ctx,
+		cancel: cancel,
+		conns:  make(map[string][]*grpc.ClientConn),
+	}
+	if _, err := dr.client.getKV(ctx, dr.confKey("")); err != nil {
+		cancel()
+		return nil, errs.Wrap(err, fmt.Sprintf("address:%s, schema:%s.", address, schema))
+	}
+	return dr, nil
+}
+
+func (c *ConsulDR) confKey(key string) string {
+	return c.schema + "/" + confDir + "/" + key
+}
+
+// GetConns returns one connection to every passing instance of serviceName, reusing the ones already dialed.
+func (c *ConsulDR) GetConns(ctx context.Context, serviceName string, opts ...grpc.DialOption) ([]*grpc.ClientConn, error) {
+	addrs, _, err := c.client.healthyAddrs(ctx, serviceName, 0)
+	if err != nil {
+		return nil, err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	dialed := make(map[string]*grpc.ClientConn, len(c.conns[serviceName]))
+	for _, conn := range c.conns[serviceName] {
+		dialed[conn.Target()] = conn
+	}
+	conns := make([]*grpc.ClientConn, 0, len(addrs))
+	for _, addr := range addrs {
+		conn, ok := dialed[addr]
+		if ok {
+			delete(dialed, addr)
+		} else {
+			conn, err = grpc.DialContext(ctx, addr, append(append([]grpc.DialOption{}, c.opts...), opts...)...)
+			if err != nil {
+				return nil, errs.Wrap(err, "serviceName", serviceName, "addr", addr)
+			}
+		}
+		conns = append(conns, conn)
+	}
+	for _, conn := range dialed {
+		conn.Close()
+	}
+	c.conns[serviceName] = conns
+	return conns, nil
+}
+
+// GetConn returns a round robin connection over the passing instances of serviceName that follows health changes.
+func (c *ConsulDR) GetConn(ctx context.Context, serviceName string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	options := append(append([]grpc.DialOption{}, c.opts...), opts...)
+	options = append(options,
+		grpc.WithResolvers(c),
+		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`),
+	)
+	conn, err := grpc.DialContext(ctx, c.schema+":///"+serviceName, options...)
+	if err != nil {
+		return nil, errs.Wrap(err, "serviceName", serviceName)
+	}
+	return conn, nil
+}
+
+func (c *ConsulDR) GetSelfConnTarget() string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.self
+}
+
+func (c *ConsulDR) AddOption(opts ...grpc.DialOption) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.opts = append(c.opts, opts...)
+}
+
+func (c *ConsulDR) CloseConn(conn *grpc.ClientConn) {
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// Register registers the instance with a ttl check and keeps the check passing until UnRegister or Close.
+// If the agent forgot the instance, for example after an agent restart, it is registered again.
+func (c *ConsulDR) Register(serviceName, host string, port int, opts ...grpc.DialOption) error {
+	c.lock.Lock()
+	if c.unregister != nil {
+		c.lock.Unlock()
+		return errs.Wrap(errors.New("already registered"), "self", c.self)
+	}
+	c.self = net.JoinHostPort(host, strconv.Itoa(port))
+	serviceID := serviceName + "-" + c.self
+	reg := &serviceRegistration{
+		ID:      serviceID,
+		Name:    serviceName,
+		Address: host,
+		Port:    port,
+		Check: &serviceCheck{
+			CheckID:                        "service:" + serviceID,
+			TTL:                            checkTTL.String(),
+			DeregisterCriticalServiceAfter: deregisterAfter,
+		},
+	}
+	c.opts = append(c.opts, opts...)
+	c.lock.Unlock()
+	if err := c.registerService(c.ctx, reg); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(c.ctx)
+	c.lock.Lock()
+	c.reg = reg
+	c.unregister = cancel
+	c.lock.Unlock()
+	go c.keepPassing(ctx, reg)
+	return nil
+}
+
+func (c *ConsulDR) registerService(ctx context.Context, reg *serviceRegistration) error {
+	if err := c.client.register(ctx, reg); err != nil {
+		return err
+	}
+	return c.client.passCheck(ctx, reg.Check.CheckID)
+}
+
+func (c *ConsulDR) keepPassing(ctx context.Context, reg *serviceRegistration) {
+	ticker := time.NewTicker(checkTTL / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		err := c.client.passCheck(ctx, reg.Check.CheckID)
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		log.ZWarn(ctx, "consul pass check failed, register again", err, "serviceID", reg.ID)
+		if err := c.registerService(ctx, reg); err != nil {
+			log.ZError(ctx, "consul register again failed", err, "serviceID", reg.ID)
+		}
+	}
+}
+
+// UnRegister deregisters the instance so it disappears from resolvers immediately.
+func (c *ConsulDR) UnRegister() error {
+	c.lock.Lock()
+	cancel, reg := c.unregister, c.reg
+	c.unregister, c.reg = nil, nil
+	c.lock.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	return c.client.deregister(context.Background(), reg.ID)
+}
+
+// CreateRpcRootNodes is a no-op, consul services need no parent nodes.
+func (c *ConsulDR) CreateRpcRootNodes(serviceNames []string) error {
+	return nil
+}
+
+func (c *ConsulDR) RegisterConf2Registry(key string, conf []byte) error {
+	return c.client.putKV(c.ctx, c.confKey(key), conf)
+}
+
+func (c *ConsulDR) GetConfFromRegistry(key string) ([]byte, error) {
+	conf, err := c.client.getKV(c.ctx, c.confKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if conf == nil {
+		return nil, errs.Wrap(errors.New("conf not found"), "key", key)
+	}
+	return conf, nil
+}
+
+// Close deregisters the instance, stops all resolvers and closes the connections of GetConns.
+func (c *ConsulDR) Close() {
+	if err := c.UnRegister(); err != nil {
+		log.ZWarn(c.ctx, "consul deregister failed", err)
+	}
+	c.cancel()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, conns := range c.conns {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}
+	c.conns = make(map[string][]*grpc.ClientConn)
+}
+
+func (c *ConsulDR) GetClientLocalConns() map[string][]*grpc.ClientConn {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	conns := make(map[string][]*grpc.ClientConn, len(c.conns))
+	for serviceName, cs := range c.conns {
+		conns[serviceName] = cs
+	}
+	return conns
+}
+
+func (c *ConsulDR) GetUserIdHashGatewayHost(ctx context.Context, userId string) (string, error) {
+	return "", nil
+}
+
+// Scheme and Build make ConsulDR the grpc resolver builder of the connections returned by GetConn.
+func (c *ConsulDR) Scheme() string {
+	return c.schema
+}
+
+func (c *ConsulDR) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	ctx, cancel := context.WithCancel(c.ctx)
+	r := &consulResolver{
+		dr:          c,
+		serviceName: strings.Trim(target.URL.Path, "/"),
+		cc:          cc,
+		ctx:         ctx,
+		cancel:      cancel,
+		refresh:     make(chan struct{}, 1),
+	}
+	go r.run()
+	return r, nil
+}
+
+type consulResolver struct {
+	dr          *ConsulDR
+	serviceName string
+	cc          resolver.ClientConn
+	ctx         context.Context
+	cancel      context.CancelFunc
+	refresh     chan struct{}
+}
+
+// run keeps a blocking health query open and pushes the passing instances to grpc whenever they change.
+func (r *consulResolver) run() {
+	var index uint64
+	for {
+		addrs, newIndex, err := r.dr.client.healthyAddrs(r.ctx, r.serviceName, index)
+		if r.ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.ZWarn(r.ctx, "consul resolve failed", err, "serviceName", r.serviceName)
+			r.cc.ReportError(err)
+			index = 0
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-r.refresh:
+			case <-time.After(retryInterval):
+			}
+			continue
+		}
+		if index == 0 || newIndex != index {
+			state := resolver.State{Addresses: make([]resolver.Address, 0, len(addrs))}
+			for _, addr := range addrs {
+				state.Addresses = append(state.Addresses, resolver.Address{Addr: addr})
+			}
+			if err := r.cc.UpdateState(state); err != nil {
+				log.ZWarn(r.ctx, "consul resolver update state failed", err, "serviceName", r.serviceName)
+			}
+		}
+		// a lower index means the consul state was reset, start over with a fresh query.
+		if newIndex < index {
+			newIndex = 0
+		}
+		index = newIndex
+		if index == 0 {
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+		}
+	}
+}
+
+// ResolveNow only shortens the retry pause, a healthy resolver is already notified of every change.
+func (r *consulResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.refresh <- struct{}{}:
+	default:
+	}
+}
+
+func (r *consulResolver) Close() {
+	r.cancel()
+}
+
+// getEnv returns the value of an environment variable if it exists, otherwise it returns the fallback value.
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
+
+// getConsulAddrFromEnv returns the consul agent address combined from the CONSUL_ADDRESS and CONSUL_PORT environment variables.
+// If the environment variables are not set, it returns the fallback value.
+func getConsulAddrFromEnv(fallback string) string {
+	address, addrExists := os.LookupEnv("CONSUL_ADDRESS")
+	port, portExists := os.LookupEnv("CONSUL_PORT")
+	if addrExists && portExists {
+		return address + ":" + port
+	}
+	return fallback
+}
@@ -21,7 +21,9 @@ import (
 	"github.com/OpenIMSDK/tools/discoveryregistry"
 	"github.com/OpenIMSDK/tools/errs"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/discoveryregister/consul"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/discoveryregister/direct"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/discoveryregister/etcd"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/discoveryregister/kubernetes"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/discoveryregister/zookeeper"
 )
@@ -36,6 +38,10 @@ func NewDiscoveryRegister(config *config.GlobalConfig) (discoveryregistry.SvcDis
 	switch config.Envs.Discovery {
 	case "zookeeper":
 		return zookeeper.NewZookeeperDiscoveryRegister(config)
+	case "etcd":
+		return etcd.NewEtcdDiscoveryRegister(config)
+	case "consul":
+		return consul.NewConsulDiscoveryRegister(config)
 	case "k8s":
 		return kubernetes.NewK8sDiscoveryRegister(config.RpcRegisterName.OpenImMessageGatewayName)
 	case "direct":
@@ -0,0 +1,290 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+)
+
+const requestTimeout = 5 * time.Second
+
+// client talks to the v3 JSON gateway that every etcd server exposes on its client urls,
+// which keeps the registry free of the etcd grpc client and its dependencies.
+type client struct {
+	endpoints []string
+	username  string
+	password  string
+	http      *http.Client
+
+	lock  sync.Mutex
+	next  int
+	token string
+}
+
+type keyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type event struct {
+	Type string   `json:"type"`
+	Kv   keyValue `json:"kv"`
+}
+
+func newClient(endpoints []string, username, password string) *client {
+	urls := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+			endpoint = "http://" + endpoint
+		}
+		urls = append(urls, strings.TrimSuffix(endpoint, "/"))
+	}
+	return &client{endpoints: urls, username: username, password: password, http: &http.Client{}}
+}
+
+func encode(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func decode(s string) string {
+	b, _ := base64.StdEncoding.DecodeString(s)
+	return string(b)
+}
+
+// prefixEnd returns the range end that selects every key starting with prefix.
+func prefixEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	return "\x00"
+}
+
+// endpoint returns the endpoint to use, rotating to the next one after a failure.
+func (c *client) endpoint(failed bool) string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if failed {
+		c.next = (c.next + 1) % len(c.endpoints)
+	}
+	return c.endpoints[c.next]
+}
+
+func (c *client) authToken(ctx context.Context) (string, error) {
+	if c.username == "" {
+		return "", nil
+	}
+	c.lock.Lock()
+	token := c.token
+	c.lock.Unlock()
+	if token != "" {
+		return token, nil
+	}
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := c.do(ctx, "/v3/auth/authenticate", map[string]string{"name": c.username, "password": c.password}, &resp, false); err != nil {
+		return "", err
+	}
+	c.lock.Lock()
+	c.token = resp.Token
+	c.lock.Unlock()
+	return resp.Token, nil
+}
+
+func (c *client) newRequest(ctx context.Context, endpoint, path string, body any, auth bool) (*http.Request, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+path, bytes.NewReader(data))
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if auth {
+		token, err := c.authToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if token != "" {
+			req.Header.Set("Authorization", token)
+		}
+	}
+	return req, nil
+}
+
+// call posts body to path on the current endpoint and tries the other endpoints when it is unreachable.
+func (c *client) call(ctx context.Context, path string, body any, resp any) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	return c.do(ctx, path, body, resp, true)
+}
+
+func (c *client) do(ctx context.Context, path string, body any, resp any, auth bool) error {
+	var lastErr error
+	endpoint := c.endpoint(false)
+	for i := 0; i < len(c.endpoints); i++ {
+		if i > 0 {
+			endpoint = c.endpoint(true)
+		}
+		req, err := c.newRequest(ctx, endpoint, path, body, auth)
+		if err != nil {
+			return err
+		}
+		res, err := c.http.Do(req)
+		if err != nil {
+			lastErr = errs.Wrap(err, "endpoint", endpoint)
+			continue
+		}
+		data, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			lastErr = errs.Wrap(err, "endpoint", endpoint)
+			continue
+		}
+		if res.StatusCode != http.StatusOK {
+			if auth && res.StatusCode == http.StatusUnauthorized {
+				c.lock.Lock()
+				c.token = ""
+				c.lock.Unlock()
+			}
+			return errs.Wrap(fmt.Errorf("etcd %s status %d: %s", path, res.StatusCode, data))
+		}
+		if resp == nil {
+			return nil
+		}
+		return errs.Wrap(json.Unmarshal(data, resp))
+	}
+	return lastErr
+}
+
+func (c *client) get(ctx context.Context, key string, prefix bool) ([]keyValue, error) {
+	req := map[string]string{"key": encode(key)}
+	if prefix {
+		req["range_end"] = encode(prefixEnd(key))
+	}
+	var resp struct {
+		Kvs []keyValue `json:"kvs"`
+	}
+	if err := c.call(ctx, "/v3/kv/range", req, &resp); err != nil {
+		return nil, err
+	}
+	kvs := make([]keyValue, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		kvs = append(kvs, keyValue{Key: decode(kv.Key), Value: decode(kv.Value)})
+	}
+	return kvs, nil
+}
+
+func (c *client) put(ctx context.Context, key, value string, leaseID int64) error {
+	req := map[string]string{"key": encode(key), "value": encode(value)}
+	if leaseID != 0 {
+		req["lease"] = strconv.FormatInt(leaseID, 10)
+	}
+	return c.call(ctx, "/v3/kv/put", req, nil)
+}
+
+// grant creates a lease of ttl seconds. The gateway encodes int64 values as strings.
+func (c *client) grant(ctx context.Context, ttl int64) (int64, error) {
+	var resp struct {
+		ID string `json:"ID"`
+	}
+	if err := c.call(ctx, "/v3/lease/grant", map[string]string{"TTL": strconv.FormatInt(ttl, 10)}, &resp); err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseInt(resp.ID, 10, 64)
+	if err != nil {
+		return 0, errs.Wrap(err, "lease", resp.ID)
+	}
+	return id, nil
+}
+
+// keepAlive refreshes the lease once and reports whether it still exists.
+func (c *client) keepAlive(ctx context.Context, leaseID int64) (bool, error) {
+	var resp struct {
+		Result struct {
+			TTL string `json:"TTL"`
+		} `json:"result"`
+	}
+	if err := c.call(ctx, "/v3/lease/keepalive", map[string]string{"ID": strconv.FormatInt(leaseID, 10)}, &resp); err != nil {
+		return false, err
+	}
+	ttl, _ := strconv.ParseInt(resp.Result.TTL, 10, 64)
+	return ttl > 0, nil
+}
+
+func (c *client) revoke(ctx context.Context, leaseID int64) error {
+	return c.call(ctx, "/v3/lease/revoke", map[string]string{"ID": strconv.FormatInt(leaseID, 10)}, nil)
+}
+
+// watch streams the events of every key under prefix to fn until ctx is done or the stream breaks.
+func (c *client) watch(ctx context.Context, prefix string, fn func([]event)) error {
+	body := map[string]any{"create_request": map[string]string{
+		"key":       encode(prefix),
+		"range_end": encode(prefixEnd(prefix)),
+	}}
+	req, err := c.newRequest(ctx, c.endpoint(false), "/v3/watch", body, true)
+	if err != nil {
+		return err
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		c.endpoint(true)
+		return errs.Wrap(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return errs.Wrap(fmt.Errorf("etcd watch status %d", res.StatusCode))
+	}
+	decoder := json.NewDecoder(bufio.NewReader(res.Body))
+	for {
+		var resp struct {
+			Result struct {
+				Events []event `json:"events"`
+			} `json:"result"`
+		}
+		if err := decoder.Decode(&resp); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return errs.Wrap(err)
+		}
+		if len(resp.Result.Events) == 0 {
+			continue
+		}
+		events := make([]event, 0, len(resp.Result.Events))
+		for _, e := range resp.Result.Events {
+			events = append(events, event{Type: e.Type, Kv: keyValue{Key: decode(e.Kv.Key), Value: decode(e.Kv.Value)}})
+		}
+		fn(events)
+	}
+}
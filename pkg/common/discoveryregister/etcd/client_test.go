@@ -0,0 +1,51 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixEnd(t *testing.T) {
+	assert.Equal(t, "/openim/user0", prefixEnd("/openim/user/"))
+	assert.Equal(t, "b", prefixEnd("a\xff"))
+	assert.Equal(t, "\x00", prefixEnd("\xff\xff"))
+}
+
+func TestClientGet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "/v3/kv/range", r.URL.Path)
+		assert.Equal(t, encode("/openim/user/"), req["key"])
+		assert.Equal(t, encode("/openim/user0"), req["range_end"])
+		json.NewEncoder(w).Encode(map[string]any{"kvs": []keyValue{
+			{Key: encode("/openim/user/127.0.0.1:10110"), Value: encode("127.0.0.1:10110")},
+		}})
+	}))
+	defer srv.Close()
+
+	// the first endpoint is unreachable, the client must fall over to the second one.
+	c := newClient([]string{"127.0.0.1:1", srv.URL}, "", "")
+	kvs, err := c.get(context.Background(), "/openim/user/", true)
+	assert.NoError(t, err)
+	assert.Equal(t, []keyValue{{Key: "/openim/user/127.0.0.1:10110", Value: "127.0.0.1:10110"}}, kvs)
+}
@@ -0,0 +1,408 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/tools/discoveryregistry"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/resolver"
+)
+
+const (
+	// leaseTTL is how long in seconds a registration outlives its process.
+	leaseTTL = 10
+	// retryInterval is the pause before a broken watch or registration is retried.
+	retryInterval = time.Second
+
+	confDir = "conf"
+)
+
+var _ discoveryregistry.SvcDiscoveryRegistry = (*EtcdDR)(nil)
+
+// EtcdDR registers services under /<schema>/<serviceName>/<host:port> with a lease kept alive by the
+// process and resolves them through a grpc resolver that watches the service prefix.
+type EtcdDR struct {
+	client *client
+	schema string
+	opts   []grpc.DialOption
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	lock       sync.Mutex
+	conns      map[string][]*grpc.ClientConn
+	self       string
+	serviceKey string
+	leaseID    int64
+	unregister context.CancelFunc
+}
+
+// NewEtcdDiscoveryRegister creates a new instance of EtcdDR for etcd service discovery and registration.
+func NewEtcdDiscoveryRegister(config *config.GlobalConfig) (discoveryregistry.SvcDiscoveryRegistry, error) {
+	schema := getEnv("ETCD_SCHEMA", config.Etcd.Schema)
+	address := getEtcdAddrFromEnv(config.Etcd.Address)
+	username := getEnv("ETCD_USERNAME", config.Etcd.Username)
+	password := getEnv("ETCD_PASSWORD", config.Etcd.Password)
+	if len(address) == 0 {
+		return nil, errs.Wrap(errors.New("etcd address is empty"))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	dr := &EtcdDR{
+		client: newClient(address, username, password),
+		schema: schema,
+		ctx:    ctx,
+		cancel: cancel,
+		conns:  make(map[string][]*grpc.ClientConn),
+	}
+	if _, err := dr.client.get(ctx, dr.confKey(""), true); err != nil {
+		cancel()
+		return nil, errs.Wrap(err, fmt.Sprintf("address:%v, username:%s, schema:%s.", address, username, schema))
+	}
+	return dr, nil
+}
+
+func (e *EtcdDR) servicePrefix(serviceName string) string {
+	return "/" + e.schema + "/" + serviceName + "/"
+}
+
+func (e *EtcdDR) confKey(key string) string {
+	return "/" + e.schema + "/" + confDir + "/" + key
+}
+
+// getAddrs returns the registered addresses of serviceName.
+func (e *EtcdDR) getAddrs(ctx context.Context, serviceName string) ([]string, error) {
+	kvs, err := e.client.get(ctx, e.servicePrefix(serviceName), true)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, 0, len(kvs))
+	for _, kv := range kvs {
+		addrs = append(addrs, kv.Value)
+	}
+	return addrs, nil
+}
+
+// GetConns returns one connection to every registered instance of serviceName, reusing the ones already dialed.
+func (e *EtcdDR) GetConns(ctx context.Context, serviceName string, opts ...grpc.DialOption) ([]*grpc.ClientConn, error) {
+	addrs, err := e.getAddrs(ctx, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	dialed := make(map[string]*grpc.ClientConn, len(e.conns[serviceName]))
+	for _, conn := range e.conns[serviceName] {
+		dialed[conn.Target()] = conn
+	}
+	conns := make([]*grpc.ClientConn, 0, len(addrs))
+	for _, addr := range addrs {
+		conn, ok := dialed[addr]
+		if ok {
+			delete(dialed, addr)
+		} else {
+			conn, err = grpc.DialContext(ctx, addr, append(append([]grpc.DialOption{}, e.opts...), opts...)...)
+			if err != nil {
+				return nil, errs.Wrap(err, "serviceName", serviceName, "addr", addr)
+			}
+		}
+		conns = append(conns, conn)
+	}
+	for _, conn := range dialed {
+		conn.Close()
+	}
+	e.conns[serviceName] = conns
+	return conns, nil
+}
+
+// GetConn returns a round robin connection over all instances of serviceName that follows registrations as they change.
+func (e *EtcdDR) GetConn(ctx context.Context, serviceName string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	options := append(append([]grpc.DialOption{}, e.opts...), opts...)
+	options = append(options,
+		grpc.WithResolvers(e),
+		grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"round_robin"}`),
+	)
+	conn, err := grpc.DialContext(ctx, e.schema+":///"+serviceName, options...)
+	if err != nil {
+		return nil, errs.Wrap(err, "serviceName", serviceName)
+	}
+	return conn, nil
+}
+
+func (e *EtcdDR) GetSelfConnTarget() string {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.self
+}
+
+func (e *EtcdDR) AddOption(opts ...grpc.DialOption) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.opts = append(e.opts, opts...)
+}
+
+func (e *EtcdDR) CloseConn(conn *grpc.ClientConn) {
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+// Register puts the instance under a lease and keeps the lease alive until UnRegister or Close.
+// If the lease is lost, for example after a long pause, the instance is registered again.
+func (e *EtcdDR) Register(serviceName, host string, port int, opts ...grpc.DialOption) error {
+	e.lock.Lock()
+	if e.unregister != nil {
+		e.lock.Unlock()
+		return errs.Wrap(errors.New("already registered"), "self", e.self)
+	}
+	e.self = net.JoinHostPort(host, strconv.Itoa(port))
+	e.serviceKey = e.servicePrefix(serviceName) + e.self
+	e.opts = append(e.opts, opts...)
+	e.lock.Unlock()
+	leaseID, err := e.putService(e.ctx)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(e.ctx)
+	e.lock.Lock()
+	e.leaseID = leaseID
+	e.unregister = cancel
+	e.lock.Unlock()
+	go e.keepAlive(ctx, leaseID)
+	return nil
+}
+
+func (e *EtcdDR) putService(ctx context.Context) (int64, error) {
+	leaseID, err := e.client.grant(ctx, leaseTTL)
+	if err != nil {
+		return 0, err
+	}
+	if err := e.client.put(ctx, e.serviceKey, e.self, leaseID); err != nil {
+		return 0, err
+	}
+	return leaseID, nil
+}
+
+func (e *EtcdDR) keepAlive(ctx context.Context, leaseID int64) {
+	ticker := time.NewTicker(leaseTTL * time.Second / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		alive, err := e.client.keepAlive(ctx, leaseID)
+		if err != nil {
+			log.ZWarn(ctx, "etcd lease keepalive failed", err, "key", e.serviceKey)
+			continue
+		}
+		if alive {
+			continue
+		}
+		log.ZWarn(ctx, "etcd lease expired, register again", nil, "key", e.serviceKey)
+		newLeaseID, err := e.putService(ctx)
+		if err != nil {
+			log.ZError(ctx, "etcd register again failed", err, "key", e.serviceKey)
+			continue
+		}
+		leaseID = newLeaseID
+		e.lock.Lock()
+		e.leaseID = leaseID
+		e.lock.Unlock()
+	}
+}
+
+// UnRegister revokes the lease so the instance disappears from resolvers immediately.
+func (e *EtcdDR) UnRegister() error {
+	e.lock.Lock()
+	cancel, leaseID := e.unregister, e.leaseID
+	e.unregister, e.leaseID = nil, 0
+	e.lock.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	return e.client.revoke(context.Background(), leaseID)
+}
+
+// CreateRpcRootNodes is a no-op, etcd keys need no parent nodes.
+func (e *EtcdDR) CreateRpcRootNodes(serviceNames []string) error {
+	return nil
+}
+
+func (e *EtcdDR) RegisterConf2Registry(key string, conf []byte) error {
+	return e.client.put(e.ctx, e.confKey(key), string(conf), 0)
+}
+
+func (e *EtcdDR) GetConfFromRegistry(key string) ([]byte, error) {
+	kvs, err := e.client.get(e.ctx, e.confKey(key), false)
+	if err != nil {
+		return nil, err
+	}
+	if len(kvs) == 0 {
+		return nil, errs.Wrap(errors.New("conf not found"), "key", key)
+	}
+	return []byte(kvs[0].Value), nil
+}
+
+// Close unregisters the instance, stops all watches and closes the connections of GetConns.
+func (e *EtcdDR) Close() {
+	if err := e.UnRegister(); err != nil {
+		log.ZWarn(e.ctx, "etcd unregister failed", err)
+	}
+	e.cancel()
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for _, conns := range e.conns {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}
+	e.conns = make(map[string][]*grpc.ClientConn)
+}
+
+func (e *EtcdDR) GetClientLocalConns() map[string][]*grpc.ClientConn {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	conns := make(map[string][]*grpc.ClientConn, len(e.conns))
+	for serviceName, c := range e.conns {
+		conns[serviceName] = c
+	}
+	return conns
+}
+
+func (e *EtcdDR) GetUserIdHashGatewayHost(ctx context.Context, userId string) (string, error) {
+	return "", nil
+}
+
+// Scheme and Build make EtcdDR the grpc resolver builder of the connections returned by GetConn.
+func (e *EtcdDR) Scheme() string {
+	return e.schema
+}
+
+func (e *EtcdDR) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	ctx, cancel := context.WithCancel(e.ctx)
+	r := &etcdResolver{
+		dr:          e,
+		serviceName: strings.Trim(target.URL.Path, "/"),
+		cc:          cc,
+		ctx:         ctx,
+		cancel:      cancel,
+		refresh:     make(chan struct{}, 1),
+	}
+	r.update()
+	go r.run()
+	return r, nil
+}
+
+type etcdResolver struct {
+	dr          *EtcdDR
+	serviceName string
+	cc          resolver.ClientConn
+	ctx         context.Context
+	cancel      context.CancelFunc
+	refresh     chan struct{}
+}
+
+func (r *etcdResolver) update() {
+	addrs, err := r.dr.getAddrs(r.ctx, r.serviceName)
+	if err != nil {
+		log.ZWarn(r.ctx, "etcd resolve failed", err, "serviceName", r.serviceName)
+		r.cc.ReportError(err)
+		return
+	}
+	state := resolver.State{Addresses: make([]resolver.Address, 0, len(addrs))}
+	for _, addr := range addrs {
+		state.Addresses = append(state.Addresses, resolver.Address{Addr: addr})
+	}
+	if err := r.cc.UpdateState(state); err != nil {
+		log.ZWarn(r.ctx, "etcd resolver update state failed", err, "serviceName", r.serviceName)
+	}
+}
+
+// run watches the service prefix, resolving again on every change and after the watch is re-established.
+func (r *etcdResolver) run() {
+	go func() {
+		for {
+			select {
+			case <-r.ctx.Done():
+				return
+			case <-r.refresh:
+				r.update()
+			}
+		}
+	}()
+	for {
+		err := r.dr.client.watch(r.ctx, r.dr.servicePrefix(r.serviceName), func([]event) { r.ResolveNow(resolver.ResolveNowOptions{}) })
+		if r.ctx.Err() != nil {
+			return
+		}
+		log.ZWarn(r.ctx, "etcd watch broken", err, "serviceName", r.serviceName)
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-time.After(retryInterval):
+		}
+		r.ResolveNow(resolver.ResolveNowOptions{})
+	}
+}
+
+func (r *etcdResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.refresh <- struct{}{}:
+	default:
+	}
+}
+
+func (r *etcdResolver) Close() {
+	r.cancel()
+}
+
+// getEnv returns the value of an environment variable if it exists, otherwise it returns the fallback value.
+func getEnv(key, fallback string) string {
+	if value, exists := os.LookupEnv(key); exists {
+		return value
+	}
+	return fallback
+}
+
+// getEtcdAddrFromEnv returns the etcd addresses combined from the ETCD_ADDRESS and ETCD_PORT environment variables.
+// If the environment variables are not set, it returns the fallback value.
+func getEtcdAddrFromEnv(fallback []string) []string {
+	address, addrExists := os.LookupEnv("ETCD_ADDRESS")
+	port, portExists := os.LookupEnv("ETCD_PORT")
+
+	if addrExists && portExists {
+		addresses := strings.Split(address, ",")
+		for i, addr := range addresses {
+			addresses[i] = addr + ":" + port
+		}
+		return addresses
+	}
+	return fallback
+}
@@ -0,0 +1,193 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eventexport publishes domain events to a kafka topic as versioned JSON, so that analytics and CRM
+// systems consume them without the after callbacks.
+package eventexport
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/google/uuid"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+)
+
+// SchemaVersion is the version of Event and of the data of each type. Fields are only added within a version,
+// renaming or removing one bumps it.
+const SchemaVersion = 1
+
+const (
+	TypeMessageSent       = "message.sent"
+	TypeGroupCreated      = "group.created"
+	TypeGroupMemberJoined = "group.member_joined"
+	TypeFriendAdded       = "friend.added"
+	TypeUserRegistered    = "user.registered"
+)
+
+// Event is the value of a message of the event topic, the key is the id of the entity the event is about so that
+// the events of one conversation, group or user keep their order.
+type Event struct {
+	Schema int    `json:"schema"`
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	// Time (ms) is when the event happened.
+	Time        int64  `json:"time"`
+	AppID       string `json:"appID,omitempty"`
+	OperationID string `json:"operationID,omitempty"`
+	Data        any    `json:"data"`
+}
+
+// MessageSent is the data of TypeMessageSent, the seq is not known yet when the event is published.
+type MessageSent struct {
+	ServerMsgID    string `json:"serverMsgID"`
+	ClientMsgID    string `json:"clientMsgID"`
+	ConversationID string `json:"conversationID"`
+	SendID         string `json:"sendID"`
+	RecvID         string `json:"recvID,omitempty"`
+	GroupID        string `json:"groupID,omitempty"`
+	SessionType    int32  `json:"sessionType"`
+	ContentType    int32  `json:"contentType"`
+	SendTime       int64  `json:"sendTime"`
+}
+
+// GroupCreated is the data of TypeGroupCreated, its members are published as TypeGroupMemberJoined.
+type GroupCreated struct {
+	GroupID       string `json:"groupID"`
+	GroupName     string `json:"groupName"`
+	GroupType     int32  `json:"groupType"`
+	OwnerUserID   string `json:"ownerUserID"`
+	CreatorUserID string `json:"creatorUserID"`
+	MemberCount   int    `json:"memberCount"`
+}
+
+// GroupMemberJoined is the data of TypeGroupMemberJoined.
+type GroupMemberJoined struct {
+	GroupID string   `json:"groupID"`
+	UserIDs []string `json:"userIDs"`
+}
+
+// FriendAdded is the data of TypeFriendAdded, the friendship goes both ways.
+type FriendAdded struct {
+	OwnerUserID  string `json:"ownerUserID"`
+	FriendUserID string `json:"friendUserID"`
+	AddSource    int32  `json:"addSource"`
+}
+
+// UserRegistered is the data of TypeUserRegistered.
+type UserRegistered struct {
+	UserID   string `json:"userID"`
+	Nickname string `json:"nickname"`
+}
+
+type producer interface {
+	SendBytes(ctx context.Context, key string, value []byte) (int32, int64, error)
+}
+
+type queuedEvent struct {
+	ctx   context.Context
+	key   string
+	event *Event
+}
+
+// Exporter publishes events in the background, a nil Exporter drops them.
+type Exporter struct {
+	producer producer
+	// events are the types published, all of them when nil.
+	events map[string]struct{}
+	queue  chan *queuedEvent
+}
+
+// NewExporter returns the exporter of eventExport, nil when it is disabled.
+func NewExporter(config *config.GlobalConfig) (*Exporter, error) {
+	conf := config.EventExport
+	if !conf.Enable {
+		return nil, nil
+	}
+	if conf.Topic == "" {
+		return nil, errs.ErrArgs.Wrap("eventExport.topic is empty")
+	}
+	p, err := kafka.NewKafkaProducer(config.Kafka.Addr, conf.Topic, &kafka.ProducerConfig{
+		ProducerAck:   config.Kafka.ProducerAck,
+		CompressType:  config.Kafka.CompressType,
+		Username:      config.Kafka.Username,
+		Password:      config.Kafka.Password,
+		SASLMechanism: config.Kafka.SASLMechanism,
+	}, kafka.NewTLSConfig(config))
+	if err != nil {
+		return nil, err
+	}
+	return newExporter(p, conf.Events, conf.QueueSize), nil
+}
+
+func newExporter(p producer, events []string, queueSize int) *Exporter {
+	if queueSize <= 0 {
+		queueSize = 1
+	}
+	e := &Exporter{producer: p, queue: make(chan *queuedEvent, queueSize)}
+	if len(events) > 0 {
+		e.events = make(map[string]struct{}, len(events))
+		for _, eventType := range events {
+			e.events[eventType] = struct{}{}
+		}
+	}
+	go e.run()
+	return e
+}
+
+// Publish queues the event of eventType about the entity key, it never blocks the caller. Events are dropped
+// when the queue is full.
+func (e *Exporter) Publish(ctx context.Context, eventType string, key string, data any) {
+	if e == nil {
+		return
+	}
+	if e.events != nil {
+		if _, ok := e.events[eventType]; !ok {
+			return
+		}
+	}
+	event := &Event{
+		Schema:      SchemaVersion,
+		ID:          uuid.NewString(),
+		Type:        eventType,
+		Time:        time.Now().UnixMilli(),
+		AppID:       tenant.GetAppID(ctx),
+		OperationID: mcontext.GetOperationID(ctx),
+		Data:        data,
+	}
+	select {
+	case e.queue <- &queuedEvent{ctx: ctx, key: key, event: event}:
+	default:
+		log.ZWarn(ctx, "event export queue is full, event dropped", nil, "type", eventType, "key", key)
+	}
+}
+
+func (e *Exporter) run() {
+	for q := range e.queue {
+		value, err := json.Marshal(q.event)
+		if err != nil {
+			log.ZError(q.ctx, "marshal event failed", err, "type", q.event.Type, "key", q.key)
+			continue
+		}
+		if _, _, err := e.producer.SendBytes(q.ctx, q.key, value); err != nil {
+			log.ZWarn(q.ctx, "export event failed", err, "type", q.event.Type, "key", q.key)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eventexport
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sent struct {
+	key   string
+	value []byte
+}
+
+type fakeProducer chan sent
+
+func (f fakeProducer) SendBytes(_ context.Context, key string, value []byte) (int32, int64, error) {
+	f <- sent{key: key, value: value}
+	return 0, 0, nil
+}
+
+func TestExporter(t *testing.T) {
+	p := make(fakeProducer, 2)
+	e := newExporter(p, []string{TypeGroupCreated}, 10)
+	ctx := context.Background()
+	e.Publish(ctx, TypeUserRegistered, "u1", &UserRegistered{UserID: "u1"})
+	e.Publish(ctx, TypeGroupCreated, "g1", &GroupCreated{GroupID: "g1", OwnerUserID: "u1", MemberCount: 3})
+
+	select {
+	case s := <-p:
+		assert.Equal(t, "g1", s.key)
+		var event struct {
+			Schema int          `json:"schema"`
+			ID     string       `json:"id"`
+			Type   string       `json:"type"`
+			Data   GroupCreated `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(s.value, &event))
+		assert.Equal(t, SchemaVersion, event.Schema)
+		assert.NotEmpty(t, event.ID)
+		assert.Equal(t, TypeGroupCreated, event.Type)
+		assert.Equal(t, GroupCreated{GroupID: "g1", OwnerUserID: "u1", MemberCount: 3}, event.Data)
+	case <-time.After(time.Second):
+		t.Fatal("the event was not exported")
+	}
+	// Types that are not configured are never sent.
+	select {
+	case s := <-p:
+		t.Fatalf("unexpected event %s", s.value)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestNilExporter(t *testing.T) {
+	var e *Exporter
+	e.Publish(context.Background(), TypeFriendAdded, "u1", &FriendAdded{OwnerUserID: "u1", FriendUserID: "u2"})
+}
@@ -0,0 +1,69 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/callbackgrpc"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+const (
+	TransportHttp = "http"
+	TransportGrpc = "grpc"
+)
+
+var callbackGrpcAddr struct {
+	lock sync.RWMutex
+	addr string
+}
+
+// InitCallback sets the callback.grpcAddr the callbacks whose transport is grpc are sent to.
+func InitCallback(config *config.GlobalConfig) {
+	callbackGrpcAddr.lock.Lock()
+	callbackGrpcAddr.addr = config.Callback.GrpcAddr
+	callbackGrpcAddr.lock.Unlock()
+}
+
+func getCallbackGrpcAddr() string {
+	callbackGrpcAddr.lock.RLock()
+	defer callbackGrpcAddr.lock.RUnlock()
+	return callbackGrpcAddr.addr
+}
+
+// callbackGrpc sends the callback body over the Callback service at addr, signed when the callback has a secret.
+func callbackGrpc(ctx context.Context, addr string, command string, input any, callbackConfig config.CallBackConfig) ([]byte, error) {
+	if callbackConfig.CallbackTimeOut > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, time.Second*time.Duration(callbackConfig.CallbackTimeOut))
+		defer cancel()
+	}
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, errs.Wrap(err, "callbackGrpc: JSON marshal failed")
+	}
+	var header map[string]string
+	if callbackConfig.Secret != "" {
+		if header, err = SignCallback(callbackConfig.Secret, body, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	return callbackgrpc.Call(ctx, addr, command, header, body)
+}
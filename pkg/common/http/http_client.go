@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"time"
@@ -27,6 +28,8 @@ import (
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/openimsdk/open-im-server/v3/pkg/callbackstruct"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/throttle"
 )
 
 var (
@@ -67,7 +70,10 @@ func Post(ctx context.Context, url string, header map[string]string, data any, t
 	if err != nil {
 		return nil, errs.Wrap(err, "Post: JSON marshal failed")
 	}
+	return postBody(ctx, url, header, jsonStr)
+}
 
+func postBody(ctx context.Context, url string, header map[string]string, jsonStr []byte) (content []byte, err error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonStr))
 	if err != nil {
 		return nil, errs.Wrap(err, "Post: NewRequestWithContext failed")
@@ -108,9 +114,29 @@ func PostReturn(ctx context.Context, url string, header map[string]string, input
 }
 
 func callBackPostReturn(ctx context.Context, url, command string, input interface{}, output callbackstruct.CallbackResp, callbackConfig config.CallBackConfig) error {
+	grpcAddr := getCallbackGrpcAddr()
+	// Tenants share the enabled callbacks of the cluster but may receive them on their own url.
+	if tenantConfig, ok := tenant.Lookup(ctx); ok {
+		url = tenantConfig.Callback.CallbackUrl
+		grpcAddr = ""
+	}
+	// Callbacks of tenants with their own url are always posted to it.
+	useGrpc := callbackConfig.Transport == TransportGrpc && grpcAddr != ""
+	if useGrpc {
+		url = "grpc://" + grpcAddr
+	}
+	dest := webhookDestination(url)
 	url = url + "/" + command
-	log.ZInfo(ctx, "callback", "url", url, "input", input, "config", callbackConfig)
-	b, err := Post(ctx, url, nil, input, callbackConfig.CallbackTimeOut)
+	log.ZInfo(ctx, "callback", "url", url, "input", input, "timeout", callbackConfig.CallbackTimeOut, "transport", callbackConfig.Transport)
+	var b []byte
+	err := throttle.Do(ctx, dest, func() (err error) {
+		if useGrpc {
+			b, err = callbackGrpc(ctx, grpcAddr, command, input, callbackConfig)
+		} else {
+			b, err = callbackPost(ctx, url, input, callbackConfig)
+		}
+		return err
+	})
 	if err != nil {
 		if callbackConfig.CallbackFailedContinue != nil && *callbackConfig.CallbackFailedContinue {
 			log.ZInfo(ctx, "callback failed but continue", err, "url", url)
@@ -134,6 +160,75 @@ func callBackPostReturn(ctx context.Context, url, command string, input interfac
 	return nil
 }
 
+// callbackPost posts the callback body, signed when the callback has a secret.
+func callbackPost(ctx context.Context, url string, input any, callbackConfig config.CallBackConfig) ([]byte, error) {
+	if callbackConfig.CallbackTimeOut > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, time.Second*time.Duration(callbackConfig.CallbackTimeOut))
+		defer cancel()
+	}
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, errs.Wrap(err, "callbackPost: JSON marshal failed")
+	}
+	var header map[string]string
+	if callbackConfig.Secret != "" {
+		if header, err = SignCallback(callbackConfig.Secret, body, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	return postBody(ctx, url, header, body)
+}
+
+// webhookDestination names url in the destination throttle, the commands of a callback url share its state.
+func webhookDestination(url string) string {
+	return "webhook:" + url
+}
+
+// PostWebhook posts input to url, signed when secret is set, and fails unless the receiver answers 2xx so that
+// the caller can deliver it again.
+func PostWebhook(ctx context.Context, url string, secret string, input any, timeout int) error {
+	return throttle.Do(ctx, webhookDestination(url), func() error {
+		return postWebhook(ctx, url, secret, input, timeout)
+	})
+}
+
+func postWebhook(ctx context.Context, url string, secret string, input any, timeout int) error {
+	if timeout > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, time.Second*time.Duration(timeout))
+		defer cancel()
+	}
+	body, err := json.Marshal(input)
+	if err != nil {
+		return errs.Wrap(err, "PostWebhook: JSON marshal failed")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return errs.Wrap(err, "PostWebhook: NewRequestWithContext failed")
+	}
+	if secret != "" {
+		header, err := SignCallback(secret, body, time.Now())
+		if err != nil {
+			return err
+		}
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+	}
+	req.Header.Add("content-type", "application/json; charset=utf-8")
+	resp, err := client.Do(req)
+	if err != nil {
+		return errs.Wrap(err, "PostWebhook: client.Do failed")
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errs.ErrNetwork.Wrap(fmt.Sprintf("webhook answered %d", resp.StatusCode))
+	}
+	return nil
+}
+
 func CallBackPostReturn(ctx context.Context, url string, req callbackstruct.CallbackReq, resp callbackstruct.CallbackResp, callbackConfig config.CallBackConfig) error {
 	return callBackPostReturn(ctx, url, req.GetCallbackCommand(), req, resp, callbackConfig)
 }
@@ -0,0 +1,77 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+)
+
+const (
+	SignatureHeader = "X-OpenIM-Signature"
+	TimestampHeader = "X-OpenIM-Timestamp"
+	NonceHeader     = "X-OpenIM-Nonce"
+)
+
+// SignCallback returns the headers signing a callback body. The signature is the hex HMAC-SHA256
+// of "timestamp\nnonce\nbody" keyed with the callback secret, the timestamp is in unix seconds.
+func SignCallback(secret string, body []byte, now time.Time) (map[string]string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errs.Wrap(err, "SignCallback: rand read failed")
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	nonceStr := hex.EncodeToString(nonce)
+	return map[string]string{
+		SignatureHeader: callbackSignature(secret, timestamp, nonceStr, body),
+		TimestampHeader: timestamp,
+		NonceHeader:     nonceStr,
+	}, nil
+}
+
+// VerifyCallback checks the signature headers of a received callback, requests whose timestamp is
+// further than maxSkew from now are rejected. To fully prevent replays the receiver should also
+// remember the nonces it has accepted during the last maxSkew.
+func VerifyCallback(secret string, header http.Header, body []byte, maxSkew time.Duration, now time.Time) error {
+	signature, timestamp, nonce := header.Get(SignatureHeader), header.Get(TimestampHeader), header.Get(NonceHeader)
+	if signature == "" || timestamp == "" || nonce == "" {
+		return errs.ErrArgs.Wrap("callback signature headers missing")
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errs.ErrArgs.Wrap("callback timestamp invalid")
+	}
+	if skew := now.Sub(time.Unix(unix, 0)); skew > maxSkew || skew < -maxSkew {
+		return errs.ErrNoPermission.Wrap("callback timestamp expired")
+	}
+	if !hmac.Equal([]byte(signature), []byte(callbackSignature(secret, timestamp, nonce, body))) {
+		return errs.ErrNoPermission.Wrap("callback signature mismatch")
+	}
+	return nil
+}
+
+func callbackSignature(secret, timestamp, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + nonce + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
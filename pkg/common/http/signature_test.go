@@ -0,0 +1,53 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestVerifyCallback(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"callbackCommand":"callbackBeforeSendSingleMsgCommand"}`)
+	signed, err := SignCallback("secret", body, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := http.Header{}
+	for k, v := range signed {
+		header.Set(k, v)
+	}
+	tests := []struct {
+		name    string
+		secret  string
+		body    []byte
+		now     time.Time
+		wantErr bool
+	}{
+		{name: "valid", secret: "secret", body: body, now: now},
+		{name: "wrong secret", secret: "other", body: body, now: now, wantErr: true},
+		{name: "tampered body", secret: "secret", body: []byte(`{}`), now: now, wantErr: true},
+		{name: "expired", secret: "secret", body: body, now: now.Add(10 * time.Minute), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyCallback(tt.secret, header, tt.body, 5*time.Minute, tt.now); (err != nil) != tt.wantErr {
+				t.Errorf("VerifyCallback() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
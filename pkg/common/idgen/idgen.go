@@ -0,0 +1,168 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package idgen creates the ids of users, groups and messages with the strategy set in idGenerator.strategy.
+package idgen
+
+import (
+	"context"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OpenIMSDK/tools/discoveryregistry"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/bwmarrin/snowflake"
+	"github.com/google/uuid"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+)
+
+const (
+	StrategyLegacy    = "legacy"
+	StrategyUUIDv7    = "uuidv7"
+	StrategySnowflake = "snowflake"
+)
+
+// Generator returns a new unique id on every call.
+type Generator interface {
+	NewID() string
+}
+
+// IDGenerator creates the ids of one rpc service, a nil gen keeps the legacy id of each kind.
+type IDGenerator struct {
+	gen          Generator
+	tenantPrefix bool
+}
+
+// New returns the generator of config, a snowflake generator leases its worker id from client when
+// idGenerator.snowflake.workerID is negative.
+func New(config *config.GlobalConfig, client discoveryregistry.SvcDiscoveryRegistry) (*IDGenerator, error) {
+	conf := config.IDGenerator
+	// Isolated apps only accept ids carrying their prefix.
+	g := &IDGenerator{tenantPrefix: conf.TenantPrefix || config.Tenant.Isolation}
+	switch conf.Strategy {
+	case "", StrategyLegacy:
+	case StrategyUUIDv7:
+		g.gen = uuidV7Generator{}
+	case StrategySnowflake:
+		workerID := conf.Snowflake.WorkerID
+		if workerID < 0 {
+			var err error
+			if workerID, err = LeaseWorkerID(client); err != nil {
+				return nil, err
+			}
+		}
+		gen, err := NewSnowflake(workerID, conf.Snowflake.Epoch)
+		if err != nil {
+			return nil, err
+		}
+		g.gen = gen
+	default:
+		return nil, errs.ErrArgs.Wrap("unknown id generator strategy " + conf.Strategy)
+	}
+	return g, nil
+}
+
+// UserID returns a new user id, legacy ids are 10 digits.
+func (g *IDGenerator) UserID(ctx context.Context) string {
+	if g.gen == nil {
+		return g.withTenant(ctx, legacyUserID())
+	}
+	return g.withTenant(ctx, g.gen.NewID())
+}
+
+// GroupID returns a new group id, legacy ids are decimal numbers of up to 10 digits.
+func (g *IDGenerator) GroupID(ctx context.Context) string {
+	if g.gen == nil {
+		return g.withTenant(ctx, legacyGroupID(ctx))
+	}
+	return g.withTenant(ctx, g.gen.NewID())
+}
+
+// MsgID returns a new server msg id, legacy ids are md5 hashes.
+func (g *IDGenerator) MsgID(ctx context.Context, sendID string) string {
+	if g.gen == nil {
+		return g.withTenant(ctx, legacyMsgID(sendID))
+	}
+	return g.withTenant(ctx, g.gen.NewID())
+}
+
+func (g *IDGenerator) withTenant(ctx context.Context, id string) string {
+	if !g.tenantPrefix {
+		return id
+	}
+	if appID := tenant.GetAppID(ctx); appID != "" {
+		return appID + tenant.IDSeparator + id
+	}
+	return id
+}
+
+type uuidV7Generator struct{}
+
+func (uuidV7Generator) NewID() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+type snowflakeGenerator struct {
+	node *snowflake.Node
+}
+
+// NewSnowflake returns a snowflake generator for workerID (0-1023). The epoch (unix ms) is process wide,
+// zero keeps the default of the snowflake package.
+func NewSnowflake(workerID int64, epoch int64) (Generator, error) {
+	if epoch > 0 {
+		snowflake.Epoch = epoch
+	}
+	node, err := snowflake.NewNode(workerID)
+	if err != nil {
+		return nil, errs.ErrArgs.Wrap("snowflake worker id " + strconv.FormatInt(workerID, 10) + ": " + err.Error())
+	}
+	return &snowflakeGenerator{node: node}, nil
+}
+
+func (s *snowflakeGenerator) NewID() string {
+	return s.node.Generate().String()
+}
+
+func legacyUserID() string {
+	const l = 10
+	data := make([]byte, l)
+	rand.Read(data)
+	chars := []byte("0123456789")
+	for i := 0; i < len(data); i++ {
+		if i == 0 {
+			data[i] = chars[1:][data[i]%9]
+		} else {
+			data[i] = chars[data[i]%10]
+		}
+	}
+	return string(data)
+}
+
+func legacyGroupID(ctx context.Context) string {
+	id := utils.Md5(strings.Join([]string{mcontext.GetOperationID(ctx), strconv.FormatInt(time.Now().UnixNano(), 10), strconv.Itoa(rand.Int())}, ",;,"))
+	bi := big.NewInt(0)
+	bi.SetString(id[0:8], 16)
+	return bi.String()
+}
+
+func legacyMsgID(sendID string) string {
+	t := time.Now().Format("2006-01-02 15:04:05")
+	return utils.Md5(t + "-" + sendID + "-" + strconv.Itoa(rand.Int()))
+}
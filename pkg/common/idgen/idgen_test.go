@@ -0,0 +1,59 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idgen
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+)
+
+func TestSnowflakeSortable(t *testing.T) {
+	gen, err := NewSnowflake(1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last int64
+	for i := 0; i < 1000; i++ {
+		id, err := strconv.ParseInt(gen.NewID(), 10, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= last {
+			t.Fatalf("id %d after %d", id, last)
+		}
+		last = id
+	}
+	if _, err := NewSnowflake(maxWorkerID+1, 0); err == nil {
+		t.Error("worker id out of range accepted")
+	}
+}
+
+func TestTenantPrefix(t *testing.T) {
+	g := &IDGenerator{gen: uuidV7Generator{}, tenantPrefix: true}
+	ctx := tenant.WithAppID(context.Background(), "app1")
+	if id := g.GroupID(ctx); len(id) != len("app1_")+36 || id[:5] != "app1_" {
+		t.Errorf("group id = %s", id)
+	}
+	if id := g.GroupID(context.Background()); len(id) != 36 {
+		t.Errorf("group id = %s", id)
+	}
+	g = &IDGenerator{}
+	if id := g.UserID(ctx); len(id) != 10 {
+		t.Errorf("user id = %s", id)
+	}
+}
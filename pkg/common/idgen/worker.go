@@ -0,0 +1,105 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package idgen
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/OpenIMSDK/tools/discoveryregistry"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/google/uuid"
+)
+
+const (
+	maxWorkerID     = 1023
+	workerKeyPrefix = "snowflake_worker_"
+	workerLeaseTTL  = time.Minute
+	// workerSettle is the time waited before reading a claim back, the config store of the registry has no
+	// compare-and-set, so of two instances claiming the same id at once the last writer wins.
+	workerSettle = 500 * time.Millisecond
+)
+
+type workerLease struct {
+	Owner  string `json:"owner"`
+	Expire int64  `json:"expire"`
+}
+
+// LeaseWorkerID claims the lowest free snowflake worker id in the config store of the registry and renews the
+// claim until the process exits. A claim is free when it is missing or was not renewed within workerLeaseTTL.
+func LeaseWorkerID(client discoveryregistry.SvcDiscoveryRegistry) (int64, error) {
+	owner := uuid.NewString()
+	for workerID := int64(0); workerID <= maxWorkerID; workerID++ {
+		key := workerKeyPrefix + strconv.FormatInt(workerID, 10)
+		if lease, ok := getWorkerLease(client, key); ok && lease.Owner != owner && lease.Expire > time.Now().UnixMilli() {
+			continue
+		}
+		if err := putWorkerLease(client, key, owner); err != nil {
+			return 0, err
+		}
+		time.Sleep(workerSettle)
+		lease, ok := getWorkerLease(client, key)
+		if !ok {
+			return 0, errs.ErrArgs.Wrap("the discovery registry does not store config, set idGenerator.snowflake.workerID")
+		}
+		if lease.Owner != owner {
+			continue
+		}
+		go renewWorkerLease(client, key, owner)
+		log.ZInfo(context.Background(), "leased snowflake worker id", "workerID", workerID)
+		return workerID, nil
+	}
+	return 0, errs.ErrInternalServer.Wrap("no free snowflake worker id")
+}
+
+// getWorkerLease returns the claim stored under key, a missing or unreadable claim is reported as not ok.
+func getWorkerLease(client discoveryregistry.SvcDiscoveryRegistry, key string) (*workerLease, bool) {
+	data, err := client.GetConfFromRegistry(key)
+	if err != nil || len(data) == 0 {
+		return nil, false
+	}
+	var lease workerLease
+	if err := json.Unmarshal(data, &lease); err != nil {
+		return nil, false
+	}
+	return &lease, true
+}
+
+func putWorkerLease(client discoveryregistry.SvcDiscoveryRegistry, key string, owner string) error {
+	data, err := json.Marshal(&workerLease{Owner: owner, Expire: time.Now().Add(workerLeaseTTL).UnixMilli()})
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return errs.Wrap(client.RegisterConf2Registry(key, data), "lease snowflake worker id", key)
+}
+
+// renewWorkerLease keeps the claim alive, it stops when another instance took the id over after a renewal was
+// missed, which leaves two instances with one worker id until one of them restarts.
+func renewWorkerLease(client discoveryregistry.SvcDiscoveryRegistry, key string, owner string) {
+	ticker := time.NewTicker(workerLeaseTTL / 3)
+	defer ticker.Stop()
+	for range ticker.C {
+		if lease, ok := getWorkerLease(client, key); ok && lease.Owner != owner {
+			log.ZError(context.Background(), "snowflake worker id taken over by another instance", nil, "key", key)
+			return
+		}
+		if err := putWorkerLease(client, key, owner); err != nil {
+			log.ZWarn(context.Background(), "renew snowflake worker id failed", err, "key", key)
+		}
+	}
+}
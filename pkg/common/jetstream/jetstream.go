@@ -0,0 +1,295 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jetstream implements mq on NATS JetStream. Every topic is the subject "stream.topic" of a single
+// stream and every consumer group is a durable pull consumer per topic shared by the instances of a service.
+package jetstream
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/nats-io/nats.go"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// keyHeader carries the message key, jetstream has no key of its own.
+	keyHeader              = "Openim-Key"
+	deadLetterReasonHeader = "Openim-Dead-Letter-Reason"
+
+	fetchBatch = 100
+	fetchWait  = time.Second
+)
+
+// Client is a connection to NATS with the stream holding the topics.
+type Client struct {
+	conn    *nats.Conn
+	js      nats.JetStreamContext
+	stream  string
+	ackWait time.Duration
+}
+
+// NewClient connects to NATS and creates the stream when it does not exist yet.
+func NewClient(config *config.GlobalConfig) (*Client, error) {
+	conf := config.Nats
+	if len(conf.Addr) == 0 {
+		return nil, errs.ErrArgs.Wrap("nats addr is empty")
+	}
+	if conf.Stream == "" {
+		return nil, errs.ErrArgs.Wrap("nats stream is empty")
+	}
+	opts := []nats.Option{nats.Name("openim"), nats.MaxReconnects(-1)}
+	if conf.Username != "" {
+		opts = append(opts, nats.UserInfo(conf.Username, conf.Password))
+	}
+	conn, err := nats.Connect(strings.Join(conf.Addr, ","), opts...)
+	if err != nil {
+		return nil, errs.Wrap(err, "connect nats failed", strings.Join(conf.Addr, ","))
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, errs.Wrap(err, "nats jetstream")
+	}
+	c := &Client{conn: conn, js: js, stream: conf.Stream, ackWait: time.Duration(conf.AckWait) * time.Second}
+	if err := c.ensureStream(time.Duration(conf.MaxAge) * time.Hour); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Client) ensureStream(maxAge time.Duration) error {
+	_, err := c.js.StreamInfo(c.stream)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, nats.ErrStreamNotFound) {
+		return errs.Wrap(err, "get nats stream", c.stream)
+	}
+	_, err = c.js.AddStream(&nats.StreamConfig{
+		Name:     c.stream,
+		Subjects: []string{c.stream + ".>"},
+		MaxAge:   maxAge,
+		Storage:  nats.FileStorage,
+	})
+	// Another instance may have created it in the meantime.
+	if err != nil && !errors.Is(err, nats.ErrStreamNameAlreadyInUse) {
+		return errs.Wrap(err, "create nats stream", c.stream)
+	}
+	return nil
+}
+
+func (c *Client) subject(topic string) string {
+	return c.stream + "." + topic
+}
+
+func (c *Client) Close() {
+	c.conn.Close()
+}
+
+// Producer publishes to one topic, it implements mq.Producer.
+type Producer struct {
+	client  *Client
+	subject string
+}
+
+func (c *Client) NewProducer(topic string) *Producer {
+	return &Producer{client: c, subject: c.subject(topic)}
+}
+
+// SendMessage returns the stream sequence of the message as its offset.
+func (p *Producer) SendMessage(ctx context.Context, key string, msg proto.Message) (int32, int64, error) {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return 0, 0, errs.Wrap(err, "nats proto Marshal err")
+	}
+	header, err := headerWithContext(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	header.Set(keyHeader, key)
+	ack, err := p.client.js.PublishMsg(&nats.Msg{Subject: p.subject, Data: data, Header: header})
+	if err != nil {
+		log.ZWarn(ctx, "nats publish error", err, "subject", p.subject, "key", key)
+		return 0, 0, errs.Wrap(err, p.subject)
+	}
+	return 0, int64(ack.Sequence), nil
+}
+
+// headerWithContext stores the operation id, user, platform, connection and app id of ctx in a header.
+func headerWithContext(ctx context.Context) (nats.Header, error) {
+	operationID, opUserID, platform, connID, err := mcontext.GetCtxInfos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	header := nats.Header{}
+	header.Set(constant.OperationID, operationID)
+	header.Set(constant.OpUserID, opUserID)
+	header.Set(constant.OpUserPlatform, platform)
+	header.Set(constant.ConnID, connID)
+	if appID := tenant.GetAppID(ctx); appID != "" {
+		header.Set(tenant.AppIDKey, appID)
+	}
+	return header, nil
+}
+
+// contextWithHeader is the reverse of headerWithContext.
+func contextWithHeader(header nats.Header) context.Context {
+	values := []string{
+		header.Get(constant.OperationID),
+		header.Get(constant.OpUserID),
+		header.Get(constant.OpUserPlatform),
+		header.Get(constant.ConnID),
+	}
+	return tenant.WithAppID(mcontext.WithMustInfoCtx(values), header.Get(tenant.AppIDKey))
+}
+
+// ConsumerGroup implements mq.ConsumerGroup. Messages are delivered again after ackWait until they are acked.
+type ConsumerGroup struct {
+	client           *Client
+	groupID          string
+	topics           []string
+	subs             []*nats.Subscription
+	deadLetterSuffix string
+}
+
+// NewConsumerGroup creates or resumes the durable consumers of groupID, a new group starts at the newest message,
+// or at the oldest one kept in the stream when oldest is set.
+func NewConsumerGroup(config *config.GlobalConfig, groupID string, topics []string, oldest bool) (*ConsumerGroup, error) {
+	client, err := NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	g := &ConsumerGroup{client: client, groupID: groupID, topics: topics, deadLetterSuffix: config.MQ.DeadLetterSuffix}
+	for _, topic := range topics {
+		deliver := nats.DeliverNew()
+		if oldest {
+			deliver = nats.DeliverAll()
+		}
+		opts := []nats.SubOpt{nats.BindStream(client.stream), nats.AckExplicit(), deliver, nats.ManualAck()}
+		if client.ackWait > 0 {
+			opts = append(opts, nats.AckWait(client.ackWait))
+		}
+		sub, err := client.js.PullSubscribe(client.subject(topic), durableName(groupID, topic), opts...)
+		if err != nil {
+			client.Close()
+			return nil, errs.Wrap(err, "nats pull subscribe", groupID, topic)
+		}
+		g.subs = append(g.subs, sub)
+	}
+	return g, nil
+}
+
+// durableName returns the consumer name of groupID on topic, names can not contain subject tokens.
+func durableName(groupID string, topic string) string {
+	return strings.NewReplacer(".", "_", "*", "_", ">", "_").Replace(groupID + "_" + topic)
+}
+
+func (g *ConsumerGroup) Consume(ctx context.Context, handler mq.Handler) {
+	log.ZDebug(ctx, "register consumer group", "groupID", g.groupID)
+	var wg sync.WaitGroup
+	for i := range g.topics {
+		wg.Add(1)
+		go func(topic string, sub *nats.Subscription) {
+			defer wg.Done()
+			g.consume(ctx, topic, sub, handler)
+		}(g.topics[i], g.subs[i])
+	}
+	wg.Wait()
+}
+
+func (g *ConsumerGroup) consume(ctx context.Context, topic string, sub *nats.Subscription, handler mq.Handler) {
+	messages := make(chan *mq.Message, fetchBatch)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := handler.ConsumeClaim(ctx, mq.NewClaim(topic, messages)); err != nil {
+			log.ZWarn(ctx, "consume claim err", err, "groupID", g.groupID, "topic", topic)
+		}
+	}()
+	defer func() {
+		close(messages)
+		<-done
+	}()
+	for ctx.Err() == nil {
+		msgs, err := sub.Fetch(fetchBatch, nats.MaxWait(fetchWait))
+		if err != nil {
+			if errors.Is(err, nats.ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+				continue
+			}
+			if errors.Is(err, nats.ErrConnectionClosed) || errors.Is(err, nats.ErrBadSubscription) {
+				return
+			}
+			log.ZWarn(ctx, "nats fetch err", err, "groupID", g.groupID, "topic", topic)
+			time.Sleep(fetchWait)
+			continue
+		}
+		for _, msg := range msgs {
+			msg := msg
+			m := mq.NewMessage(contextWithHeader(msg.Header), topic, msg.Header.Get(keyHeader), msg.Data, func() {
+				if err := msg.Ack(); err != nil {
+					log.ZWarn(ctx, "nats ack err", err, "groupID", g.groupID, "topic", topic)
+				}
+			}, g.deadLetterFunc(topic, msg))
+			if meta, err := msg.Metadata(); err == nil {
+				m.Lag = int64(meta.NumPending)
+			}
+			select {
+			case messages <- m:
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// deadLetterFunc returns the function moving msg to the dead letter topic of topic, nil without one.
+func (g *ConsumerGroup) deadLetterFunc(topic string, msg *nats.Msg) func(reason error) error {
+	if g.deadLetterSuffix == "" {
+		return nil
+	}
+	return func(reason error) error {
+		header := nats.Header{}
+		for key, values := range msg.Header {
+			header[key] = values
+		}
+		header.Set(deadLetterReasonHeader, reason.Error())
+		_, err := g.client.js.PublishMsg(&nats.Msg{
+			Subject: g.client.subject(mq.DeadLetterTopic(topic, g.deadLetterSuffix)),
+			Data:    msg.Data,
+			Header:  header,
+		})
+		return errs.Wrap(err)
+	}
+}
+
+// Close closes the connection, the durable consumers are kept so that the group resumes where it stopped.
+func (g *ConsumerGroup) Close() error {
+	g.client.Close()
+	return nil
+}
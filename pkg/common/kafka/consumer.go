@@ -34,23 +34,7 @@ func NewKafkaConsumer(addr []string, topic string, config *config.GlobalConfig)
 	p := Consumer{}
 	p.Topic = topic
 	p.addr = addr
-	consumerConfig := sarama.NewConfig()
-	if config.Kafka.Username != "" && config.Kafka.Password != "" {
-		consumerConfig.Net.SASL.Enable = true
-		consumerConfig.Net.SASL.User = config.Kafka.Username
-		consumerConfig.Net.SASL.Password = config.Kafka.Password
-	}
-	var tlsConfig *TLSConfig
-	if config.Kafka.TLS != nil {
-		tlsConfig = &TLSConfig{
-			CACrt:              config.Kafka.TLS.CACrt,
-			ClientCrt:          config.Kafka.TLS.ClientCrt,
-			ClientKey:          config.Kafka.TLS.ClientKey,
-			ClientKeyPwd:       config.Kafka.TLS.ClientKeyPwd,
-			InsecureSkipVerify: false,
-		}
-	}
-	err := SetupTLSConfig(consumerConfig, tlsConfig)
+	consumerConfig, err := NewClientConfig(config)
 	if err != nil {
 		return nil, err
 	}
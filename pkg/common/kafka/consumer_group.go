@@ -22,12 +22,19 @@ import (
 	"github.com/IBM/sarama"
 	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
 )
 
+// deadLetterReasonKey is the header the consumer error is stored in on dead letter messages.
+const deadLetterReasonKey = "deadLetterReason"
+
 type MConsumerGroup struct {
 	sarama.ConsumerGroup
 	groupID string
 	topics  []string
+
+	deadLetterSuffix string
+	deadLetter       sarama.SyncProducer
 }
 
 type MConsumerGroupConfig struct {
@@ -36,6 +43,10 @@ type MConsumerGroupConfig struct {
 	IsReturnErr    bool
 	UserName       string
 	Password       string
+	// SASLMechanism is one of PLAIN, SCRAM-SHA-256 and SCRAM-SHA-512, PLAIN when empty.
+	SASLMechanism string
+	// DeadLetterSuffix names the dead letter topic of each topic, empty drops the messages instead.
+	DeadLetterSuffix string
 }
 
 func NewMConsumerGroup(consumerConfig *MConsumerGroupConfig, topics, addrs []string, groupID string, tlsConfig *TLSConfig) (*MConsumerGroup, error) {
@@ -43,33 +54,47 @@ func NewMConsumerGroup(consumerConfig *MConsumerGroupConfig, topics, addrs []str
 	consumerGroupConfig.Version = consumerConfig.KafkaVersion
 	consumerGroupConfig.Consumer.Offsets.Initial = consumerConfig.OffsetsInitial
 	consumerGroupConfig.Consumer.Return.Errors = consumerConfig.IsReturnErr
-	if consumerConfig.UserName != "" && consumerConfig.Password != "" {
-		consumerGroupConfig.Net.SASL.Enable = true
-		consumerGroupConfig.Net.SASL.User = consumerConfig.UserName
-		consumerGroupConfig.Net.SASL.Password = consumerConfig.Password
+	if err := SetupSASLConfig(consumerGroupConfig, consumerConfig.UserName, consumerConfig.Password, consumerConfig.SASLMechanism); err != nil {
+		return nil, err
+	}
+	if err := SetupTLSConfig(consumerGroupConfig, tlsConfig); err != nil {
+		return nil, err
 	}
-
-	SetupTLSConfig(consumerGroupConfig, tlsConfig)
 	consumerGroup, err := sarama.NewConsumerGroup(addrs, groupID, consumerGroupConfig)
 	if err != nil {
 		return nil, errs.Wrap(err, strings.Join(topics, ","), strings.Join(addrs, ","), groupID, consumerConfig.UserName, consumerConfig.Password)
 	}
 
-	return &MConsumerGroup{
-		consumerGroup,
-		groupID,
-		topics,
-	}, nil
+	mc := &MConsumerGroup{
+		ConsumerGroup:    consumerGroup,
+		groupID:          groupID,
+		topics:           topics,
+		deadLetterSuffix: consumerConfig.DeadLetterSuffix,
+	}
+	if mc.deadLetterSuffix != "" {
+		producerConfig := sarama.NewConfig()
+		producerConfig.Version = consumerGroupConfig.Version
+		producerConfig.Net = consumerGroupConfig.Net
+		producerConfig.Producer.Return.Successes = true
+		producerConfig.Producer.RequiredAcks = sarama.WaitForAll
+		mc.deadLetter, err = sarama.NewSyncProducer(addrs, producerConfig)
+		if err != nil {
+			_ = consumerGroup.Close()
+			return nil, errs.Wrap(err, "create dead letter producer failed", groupID)
+		}
+	}
+	return mc, nil
 }
 
 func (mc *MConsumerGroup) GetContextFromMsg(cMsg *sarama.ConsumerMessage) context.Context {
 	return GetContextWithMQHeader(cMsg.Headers)
 }
 
-func (mc *MConsumerGroup) RegisterHandleAndConsumer(ctx context.Context, handler sarama.ConsumerGroupHandler) {
+// Consume implements mq.ConsumerGroup, it rejoins the group after every rebalance.
+func (mc *MConsumerGroup) Consume(ctx context.Context, handler mq.Handler) {
 	log.ZDebug(ctx, "register consumer group", "groupID", mc.groupID)
 	for {
-		err := mc.ConsumerGroup.Consume(ctx, mc.topics, handler)
+		err := mc.ConsumerGroup.Consume(ctx, mc.topics, &groupHandler{group: mc, handler: handler})
 		if errors.Is(err, sarama.ErrClosedConsumerGroup) {
 			return
 		}
@@ -83,5 +108,62 @@ func (mc *MConsumerGroup) RegisterHandleAndConsumer(ctx context.Context, handler
 }
 
 func (mc *MConsumerGroup) Close() error {
+	if mc.deadLetter != nil {
+		_ = mc.deadLetter.Close()
+	}
 	return mc.ConsumerGroup.Close()
 }
+
+// deadLetterFunc returns the function moving msg to its dead letter topic, nil without one.
+func (mc *MConsumerGroup) deadLetterFunc(msg *sarama.ConsumerMessage) func(reason error) error {
+	if mc.deadLetter == nil {
+		return nil
+	}
+	return func(reason error) error {
+		headers := make([]sarama.RecordHeader, 0, len(msg.Headers)+1)
+		for _, header := range msg.Headers {
+			headers = append(headers, *header)
+		}
+		headers = append(headers, sarama.RecordHeader{Key: []byte(deadLetterReasonKey), Value: []byte(reason.Error())})
+		_, _, err := mc.deadLetter.SendMessage(&sarama.ProducerMessage{
+			Topic:   mq.DeadLetterTopic(msg.Topic, mc.deadLetterSuffix),
+			Key:     sarama.ByteEncoder(msg.Key),
+			Value:   sarama.ByteEncoder(msg.Value),
+			Headers: headers,
+		})
+		return errs.Wrap(err)
+	}
+}
+
+// groupHandler hands the claims of a sarama session over to an mq.Handler.
+type groupHandler struct {
+	group   *MConsumerGroup
+	handler mq.Handler
+}
+
+func (groupHandler) Setup(_ sarama.ConsumerGroupSession) error   { return nil }
+func (groupHandler) Cleanup(_ sarama.ConsumerGroupSession) error { return nil }
+
+func (h *groupHandler) ConsumeClaim(sess sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	log.ZDebug(sess.Context(), "consumer group claim", "groupID", h.group.groupID, "topic", claim.Topic(),
+		"partition", claim.Partition(), "highWaterMarkOffset", claim.HighWaterMarkOffset())
+	messages := make(chan *mq.Message)
+	go func() {
+		defer close(messages)
+		for msg := range claim.Messages() {
+			msg := msg
+			m := mq.NewMessage(GetContextWithMQHeader(msg.Headers), msg.Topic, string(msg.Key), msg.Value,
+				func() { sess.MarkMessage(msg, "") }, h.group.deadLetterFunc(msg))
+			m.Partition = msg.Partition
+			if lag := claim.HighWaterMarkOffset() - msg.Offset - 1; lag > 0 {
+				m.Lag = lag
+			}
+			select {
+			case messages <- m:
+			case <-sess.Context().Done():
+				return
+			}
+		}
+	}()
+	return h.handler.ConsumeClaim(sess.Context(), mq.NewClaim(claim.Topic(), messages))
+}
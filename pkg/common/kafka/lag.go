@@ -0,0 +1,81 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"github.com/IBM/sarama"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+// LagReader reports how far consumer groups are behind the newest offsets of their topics.
+type LagReader struct {
+	client sarama.Client
+	admin  sarama.ClusterAdmin
+}
+
+func NewLagReader(config *config.GlobalConfig) (*LagReader, error) {
+	clientConfig, err := NewClientConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	client, err := sarama.NewClient(config.Kafka.Addr, clientConfig)
+	if err != nil {
+		return nil, errs.Wrap(err, "NewLagReader: creating client failed")
+	}
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		_ = client.Close()
+		return nil, errs.Wrap(err, "NewLagReader: creating cluster admin failed")
+	}
+	return &LagReader{client: client, admin: admin}, nil
+}
+
+// Lag returns the sum over the partitions of topic of the newest offset minus the offset committed by groupID.
+// Partitions without a committed offset count from the oldest offset still retained.
+func (l *LagReader) Lag(groupID string, topic string) (int64, error) {
+	partitions, err := l.client.Partitions(topic)
+	if err != nil {
+		return 0, errs.Wrap(err, topic)
+	}
+	offsets, err := l.admin.ListConsumerGroupOffsets(groupID, map[string][]int32{topic: partitions})
+	if err != nil {
+		return 0, errs.Wrap(err, groupID, topic)
+	}
+	var lag int64
+	for _, partition := range partitions {
+		newest, err := l.client.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return 0, errs.Wrap(err, topic)
+		}
+		committed := int64(-1)
+		if block := offsets.GetBlock(topic, partition); block != nil {
+			committed = block.Offset
+		}
+		if committed < 0 {
+			if committed, err = l.client.GetOffset(topic, partition, sarama.OffsetOldest); err != nil {
+				return 0, errs.Wrap(err, topic)
+			}
+		}
+		if newest > committed {
+			lag += newest - committed
+		}
+	}
+	return lag, nil
+}
+
+func (l *LagReader) Close() error {
+	return l.admin.Close()
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/OpenIMSDK/tools/errs"
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -34,7 +35,7 @@ const maxRetry = 10 // number of retries
 
 var errEmptyMsg = errors.New("kafka binary msg is empty")
 
-// Producer represents a Kafka producer.
+// Producer represents a Kafka producer, it implements mq.Producer.
 type Producer struct {
 	addr     []string
 	topic    string
@@ -47,6 +48,8 @@ type ProducerConfig struct {
 	CompressType string
 	Username     string
 	Password     string
+	// SASLMechanism is one of PLAIN, SCRAM-SHA-256 and SCRAM-SHA-512, PLAIN when empty.
+	SASLMechanism string
 }
 
 // NewKafkaProducer initializes a new Kafka producer.
@@ -76,17 +79,17 @@ func NewKafkaProducer(addr []string, topic string, producerConfig *ProducerConfi
 	kafkaAddr := getKafkaAddrFromEnv(addr) // Updated to use the new function
 
 	// Configure SASL authentication if credentials are provided
-	if kafkaUsername != "" && kafkaPassword != "" {
-		p.config.Net.SASL.Enable = true
-		p.config.Net.SASL.User = kafkaUsername
-		p.config.Net.SASL.Password = kafkaPassword
+	if err := SetupSASLConfig(p.config, kafkaUsername, kafkaPassword, producerConfig.SASLMechanism); err != nil {
+		return nil, err
 	}
 
 	// Set the Kafka address
 	p.addr = kafkaAddr
 
 	// Set up TLS configuration (if required)
-	SetupTLSConfig(p.config, tlsConfig)
+	if err := SetupTLSConfig(p.config, tlsConfig); err != nil {
+		return nil, err
+	}
 
 	// Create the producer with retries
 	var err error
@@ -136,21 +139,32 @@ func GetMQHeaderWithContext(ctx context.Context) ([]sarama.RecordHeader, error)
 	if err != nil {
 		return nil, err
 	}
-	return []sarama.RecordHeader{
+	header := []sarama.RecordHeader{
 		{Key: []byte(constant.OperationID), Value: []byte(operationID)},
 		{Key: []byte(constant.OpUserID), Value: []byte(opUserID)},
 		{Key: []byte(constant.OpUserPlatform), Value: []byte(platform)},
 		{Key: []byte(constant.ConnID), Value: []byte(connID)},
-	}, nil
+	}
+	if appID := tenant.GetAppID(ctx); appID != "" {
+		header = append(header, sarama.RecordHeader{Key: []byte(tenant.AppIDKey), Value: []byte(appID)})
+	}
+	return header, nil
 }
 
 // GetContextWithMQHeader creates a context from message queue headers.
 func GetContextWithMQHeader(header []*sarama.RecordHeader) context.Context {
-	var values []string
+	var (
+		values []string
+		appID  string
+	)
 	for _, recordHeader := range header {
+		if string(recordHeader.Key) == tenant.AppIDKey {
+			appID = string(recordHeader.Value)
+			continue
+		}
 		values = append(values, string(recordHeader.Value))
 	}
-	return mcontext.WithMustInfoCtx(values) // Attach extracted values to context
+	return tenant.WithAppID(mcontext.WithMustInfoCtx(values), appID) // Attach extracted values to context
 }
 
 // SendMessage sends a message to the Kafka topic configured in the Producer.
@@ -165,12 +179,16 @@ func (p *Producer) SendMessage(ctx context.Context, key string, msg proto.Messag
 	if len(bMsg) == 0 {
 		return 0, 0, errs.Wrap(errEmptyMsg, "")
 	}
+	return p.SendBytes(ctx, key, bMsg)
+}
 
+// SendBytes sends value as it is to the Kafka topic configured in the Producer, for values that are not protobuf.
+func (p *Producer) SendBytes(ctx context.Context, key string, value []byte) (int32, int64, error) {
 	// Prepare Kafka message
 	kMsg := &sarama.ProducerMessage{
 		Topic: p.topic,
 		Key:   sarama.StringEncoder(key),
-		Value: sarama.ByteEncoder(bMsg),
+		Value: sarama.ByteEncoder(value),
 	}
 
 	// Validate message key and value
@@ -20,7 +20,17 @@ import (
 	"strings"
 
 	"github.com/IBM/sarama"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/tls"
+	"github.com/xdg-go/scram"
+)
+
+// SASL mechanisms accepted in kafka.saslMechanism.
+const (
+	SASLPlain       = "PLAIN"
+	SASLScramSHA256 = "SCRAM-SHA-256"
+	SASLScramSHA512 = "SCRAM-SHA-512"
 )
 
 type TLSConfig struct {
@@ -31,6 +41,59 @@ type TLSConfig struct {
 	InsecureSkipVerify bool
 }
 
+// NewTLSConfig returns the TLS config of kafka in the config file, nil when TLS is not configured.
+func NewTLSConfig(config *config.GlobalConfig) *TLSConfig {
+	if config.Kafka.TLS == nil {
+		return nil
+	}
+	return &TLSConfig{
+		CACrt:              config.Kafka.TLS.CACrt,
+		ClientCrt:          config.Kafka.TLS.ClientCrt,
+		ClientKey:          config.Kafka.TLS.ClientKey,
+		ClientKeyPwd:       config.Kafka.TLS.ClientKeyPwd,
+		InsecureSkipVerify: config.Kafka.TLS.InsecureSkipVerify,
+	}
+}
+
+// NewClientConfig returns a sarama config with the SASL and TLS settings of the config file.
+func NewClientConfig(config *config.GlobalConfig) (*sarama.Config, error) {
+	cfg := sarama.NewConfig()
+	if err := SetupSASLConfig(cfg, config.Kafka.Username, config.Kafka.Password, config.Kafka.SASLMechanism); err != nil {
+		return nil, err
+	}
+	if err := SetupTLSConfig(cfg, NewTLSConfig(config)); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// SetupSASLConfig enables SASL when username and password are set, mechanism is PLAIN when empty.
+func SetupSASLConfig(cfg *sarama.Config, username, password, mechanism string) error {
+	if username == "" || password == "" {
+		return nil
+	}
+	cfg.Net.SASL.Enable = true
+	cfg.Net.SASL.User = username
+	cfg.Net.SASL.Password = password
+	switch strings.ToUpper(mechanism) {
+	case "", SASLPlain:
+		cfg.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+	case SASLScramSHA256:
+		cfg.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
+		cfg.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+			return &scramClient{HashGeneratorFcn: scram.SHA256}
+		}
+	case SASLScramSHA512:
+		cfg.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+		cfg.Net.SASL.SCRAMClientGeneratorFunc = func() sarama.SCRAMClient {
+			return &scramClient{HashGeneratorFcn: scram.SHA512}
+		}
+	default:
+		return errs.ErrArgs.Wrap("unsupported kafka sasl mechanism " + mechanism)
+	}
+	return nil
+}
+
 // SetupTLSConfig set up the TLS config from config file.
 func SetupTLSConfig(cfg *sarama.Config, tlsConfig *TLSConfig) error {
 	if tlsConfig != nil {
@@ -0,0 +1,140 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package loglevel publishes log levels for the services. The tools logger can not change its level once it
+// is built, so a service reads the level published for it when it starts and builds its logger only once.
+package loglevel
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/OpenIMSDK/tools/discoveryregistry"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	kdisc "github.com/openimsdk/open-im-server/v3/pkg/common/discoveryregister"
+)
+
+// registryKeyPrefix is followed by the service name, every service keeps its own Setting in the discovery registry.
+const registryKeyPrefix = "log_level_"
+
+const (
+	MinLevel = 0
+	MaxLevel = 6
+)
+
+// ServiceNames are the services a level can be published for, named as their command.
+var ServiceNames = []string{
+	"api", "msgGateway", "msgTransfer", "cronTask",
+	"auth", "conversation", "friend", "group", "msg", "push", "third", "user",
+}
+
+// Setting is a log level published for a service.
+type Setting struct {
+	Level      int   `json:"level"`
+	UpdateTime int64 `json:"updateTime"`
+}
+
+var (
+	lock  sync.Mutex
+	level int
+)
+
+func registryKey(serviceName string) string {
+	return registryKeyPrefix + serviceName
+}
+
+// Init builds the logger with the level published for the service, or the level of the log config when none
+// is published or the registry can not be read.
+func Init(prefixName string, name string, config *config.GlobalConfig) error {
+	newLevel := config.Log.RemainLogLevel
+	if setting := loadSetting(name, config); setting != nil {
+		newLevel = setting.Level
+	}
+	logConfig := config.Log
+	if err := log.InitFromConfig(
+		prefixName,
+		name,
+		newLevel,
+		logConfig.IsStdout,
+		logConfig.IsJson,
+		logConfig.StorageLocation,
+		logConfig.RemainRotationCount,
+		logConfig.RotationTime,
+	); err != nil {
+		return err
+	}
+	lock.Lock()
+	level = newLevel
+	lock.Unlock()
+	return nil
+}
+
+func loadSetting(name string, config *config.GlobalConfig) *Setting {
+	client, err := kdisc.NewDiscoveryRegister(config)
+	if err != nil {
+		return nil
+	}
+	defer client.Close()
+	setting, err := Published(client, name)
+	if err != nil || setting == nil || setting.Level < MinLevel || setting.Level > MaxLevel {
+		return nil
+	}
+	return setting
+}
+
+// Current returns the level the logger of this process was built with.
+func Current() int {
+	lock.Lock()
+	defer lock.Unlock()
+	return level
+}
+
+// Check returns an error when newLevel is not a valid level.
+func Check(newLevel int) error {
+	if newLevel < MinLevel || newLevel > MaxLevel {
+		return errs.ErrArgs.Wrap(fmt.Sprintf("log level must be between %d and %d", MinLevel, MaxLevel))
+	}
+	return nil
+}
+
+// Publish stores the setting of serviceName in the registry, instances of the service started afterwards use it.
+func Publish(client discoveryregistry.SvcDiscoveryRegistry, serviceName string, setting *Setting) error {
+	if err := Check(setting.Level); err != nil {
+		return err
+	}
+	data, err := json.Marshal(setting)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return client.RegisterConf2Registry(registryKey(serviceName), data)
+}
+
+// Published returns the setting stored for serviceName, nil when there is none.
+func Published(client discoveryregistry.SvcDiscoveryRegistry, serviceName string) (*Setting, error) {
+	data, err := client.GetConfFromRegistry(registryKey(serviceName))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var setting Setting
+	if err := json.Unmarshal(data, &setting); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &setting, nil
+}
@@ -0,0 +1,134 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mq is the message queue msg, msgtransfer and push exchange messages through. It is implemented
+// by kafka and by nats jetstream, mqbuild picks one of them from the config.
+package mq
+
+import (
+	"context"
+	"sync"
+
+	"github.com/OpenIMSDK/tools/log"
+	"google.golang.org/protobuf/proto"
+)
+
+// Producer is the send side of a topic.
+type Producer interface {
+	// SendMessage publishes msg under key, messages of one key are consumed in the order they were sent.
+	// It returns the partition and offset the message was stored at, backends without partitions return 0.
+	SendMessage(ctx context.Context, key string, msg proto.Message) (int32, int64, error)
+}
+
+// Message is a message consumed from a topic.
+type Message struct {
+	Topic string
+	Key   string
+	Value []byte
+	// Partition is the partition the message was stored in, Lag the number of messages stored after it when it was
+	// delivered. Backends without partitions leave Partition 0.
+	Partition int32
+	Lag       int64
+
+	ctx        context.Context
+	ack        func()
+	ackOnce    sync.Once
+	deadLetter func(reason error) error
+}
+
+// NewMessage is called by the backends, ctx carries the operation id, user and app id the message was sent with.
+// ack commits the message and deadLetter moves it to the dead letter topic, deadLetter may be nil.
+func NewMessage(ctx context.Context, topic string, key string, value []byte, ack func(), deadLetter func(reason error) error) *Message {
+	return &Message{Topic: topic, Key: key, Value: value, ctx: ctx, ack: ack, deadLetter: deadLetter}
+}
+
+// Context returns the context the message was sent in.
+func (m *Message) Context() context.Context {
+	return m.ctx
+}
+
+// Ack commits the message, a message that is not acked is delivered again to the consumer group.
+// Acking a message more than once has no effect.
+func (m *Message) Ack() {
+	m.ackOnce.Do(m.ack)
+}
+
+// DeadLetter moves a message the consumer can not process to the dead letter topic of its topic and acks it.
+// The message is only acked when no dead letter topic is configured. It may be called after Ack.
+func (m *Message) DeadLetter(reason error) {
+	if m.deadLetter != nil {
+		if err := m.deadLetter(reason); err != nil {
+			log.ZError(m.ctx, "move message to dead letter topic failed", err, "topic", m.Topic, "key", m.Key, "reason", reason)
+		} else {
+			log.ZWarn(m.ctx, "message moved to dead letter topic", reason, "topic", m.Topic, "key", m.Key)
+		}
+	}
+	m.Ack()
+}
+
+// Claim is the stream of messages of one topic handed to a Handler.
+type Claim interface {
+	Topic() string
+	// Messages is closed when the group rebalances or is closed.
+	Messages() <-chan *Message
+}
+
+// Handler consumes the claims of a ConsumerGroup, ConsumeClaim is called concurrently for each claim and
+// returns when the Messages channel is closed or ctx is done.
+type Handler interface {
+	ConsumeClaim(ctx context.Context, claim Claim) error
+}
+
+// ConsumerGroup shares the messages of its topics between the instances of a service.
+type ConsumerGroup interface {
+	// Consume delivers messages to handler until ctx is done or the group is closed.
+	Consume(ctx context.Context, handler Handler)
+	Close() error
+}
+
+// Pauser is implemented by the consumer groups able to stop fetching partitions while staying in the group,
+// partitions maps topics to partitions. Partitions the instance does not own are ignored, and a partition claimed
+// after a rebalance is not paused.
+type Pauser interface {
+	Pause(partitions map[string][]int32)
+	Resume(partitions map[string][]int32)
+	PauseAll()
+	ResumeAll()
+}
+
+// DeadLetterTopic returns the dead letter topic of topic, empty when suffix is empty.
+func DeadLetterTopic(topic string, suffix string) string {
+	if suffix == "" {
+		return ""
+	}
+	return topic + suffix
+}
+
+// NewClaim returns a Claim over messages.
+func NewClaim(topic string, messages <-chan *Message) Claim {
+	return &claim{topic: topic, messages: messages}
+}
+
+type claim struct {
+	topic    string
+	messages <-chan *Message
+}
+
+func (c *claim) Topic() string {
+	return c.topic
+}
+
+func (c *claim) Messages() <-chan *Message {
+	return c.messages
+}
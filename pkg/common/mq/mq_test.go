@@ -0,0 +1,51 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mq
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDeadLetterTopic(t *testing.T) {
+	if topic := DeadLetterTopic("toPush", "-dlq"); topic != "toPush-dlq" {
+		t.Errorf("topic = %s", topic)
+	}
+	if topic := DeadLetterTopic("toPush", ""); topic != "" {
+		t.Errorf("topic = %s", topic)
+	}
+}
+
+func TestMessageAck(t *testing.T) {
+	var acks, deadLetters int
+	msg := NewMessage(context.Background(), "toPush", "key", nil, func() { acks++ }, func(error) error {
+		deadLetters++
+		return nil
+	})
+	msg.Ack()
+	msg.DeadLetter(errors.New("bad msg"))
+	msg.Ack()
+	if acks != 1 || deadLetters != 1 {
+		t.Errorf("acks = %d, deadLetters = %d", acks, deadLetters)
+	}
+
+	acks = 0
+	msg = NewMessage(context.Background(), "toPush", "key", nil, func() { acks++ }, nil)
+	msg.DeadLetter(errors.New("bad msg"))
+	if acks != 1 {
+		t.Errorf("acks = %d", acks)
+	}
+}
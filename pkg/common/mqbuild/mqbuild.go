@@ -0,0 +1,140 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mqbuild creates the producers and consumer groups of the message queue backend set in mq.backend.
+package mqbuild
+
+import (
+	"sync"
+
+	"github.com/IBM/sarama"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/jetstream"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+)
+
+const (
+	BackendKafka = "kafka"
+	BackendNats  = "nats"
+)
+
+// natsProducer is the connection shared by the producers of a process.
+var natsProducer struct {
+	lock   sync.Mutex
+	client *jetstream.Client
+}
+
+// Backend returns the configured backend, kafka when it is not set.
+func Backend(config *config.GlobalConfig) string {
+	if config.MQ.Backend == "" {
+		return BackendKafka
+	}
+	return config.MQ.Backend
+}
+
+// NewProducer returns a producer sending to topic, or to the dedicated topic of the app a message is sent for.
+func NewProducer(config *config.GlobalConfig, topic string) (mq.Producer, error) {
+	producer, err := newProducer(config, topic)
+	if err != nil {
+		return nil, err
+	}
+	return newTenantProducer(config, topic, producer)
+}
+
+func newProducer(config *config.GlobalConfig, topic string) (mq.Producer, error) {
+	switch Backend(config) {
+	case BackendKafka:
+		return kafka.NewKafkaProducer(config.Kafka.Addr, topic, &kafka.ProducerConfig{
+			ProducerAck:   config.Kafka.ProducerAck,
+			CompressType:  config.Kafka.CompressType,
+			Username:      config.Kafka.Username,
+			Password:      config.Kafka.Password,
+			SASLMechanism: config.Kafka.SASLMechanism,
+		}, kafka.NewTLSConfig(config))
+	case BackendNats:
+		natsProducer.lock.Lock()
+		defer natsProducer.lock.Unlock()
+		if natsProducer.client == nil {
+			client, err := jetstream.NewClient(config)
+			if err != nil {
+				return nil, err
+			}
+			natsProducer.client = client
+		}
+		return natsProducer.client.NewProducer(topic), nil
+	default:
+		return nil, errs.ErrArgs.Wrap("unknown mq backend " + config.MQ.Backend)
+	}
+}
+
+// NewConsumerGroup returns the consumer group groupID of topics and of the dedicated topics of the apps,
+// a new group starts at the newest messages.
+func NewConsumerGroup(config *config.GlobalConfig, groupID string, topics []string) (mq.ConsumerGroup, error) {
+	topics, shared := appTopics(config, topics)
+	group, err := newConsumerGroup(config, groupID, topics, false)
+	if err != nil {
+		return nil, err
+	}
+	if shared == nil {
+		return group, nil
+	}
+	return &tenantConsumerGroup{ConsumerGroup: group, shared: shared}, nil
+}
+
+// NewDeadLetterConsumerGroup returns the consumer group groupID of the dead letter topics of topic and of the
+// dedicated topics of the apps, a new group starts at the oldest messages. The handler sees every claim under
+// topic, the messages keep the app id they were sent with.
+func NewDeadLetterConsumerGroup(config *config.GlobalConfig, groupID string, topic string) (mq.ConsumerGroup, error) {
+	if config.MQ.DeadLetterSuffix == "" {
+		return nil, errs.ErrArgs.Wrap("mq.deadLetterSuffix is empty, there are no dead letter topics")
+	}
+	topics := []string{mq.DeadLetterTopic(topic, config.MQ.DeadLetterSuffix)}
+	for _, appID := range config.Tenant.DedicatedTopicApps {
+		topics = append(topics, mq.DeadLetterTopic(AppTopic(topic, appID), config.MQ.DeadLetterSuffix))
+	}
+	shared := make(map[string]string, len(topics))
+	for _, deadLetterTopic := range topics {
+		shared[deadLetterTopic] = topic
+	}
+	group, err := newConsumerGroup(config, groupID, topics, true)
+	if err != nil {
+		return nil, err
+	}
+	return &tenantConsumerGroup{ConsumerGroup: group, shared: shared}, nil
+}
+
+func newConsumerGroup(config *config.GlobalConfig, groupID string, topics []string, oldest bool) (mq.ConsumerGroup, error) {
+	switch Backend(config) {
+	case BackendKafka:
+		offsetsInitial := sarama.OffsetNewest
+		if oldest {
+			offsetsInitial = sarama.OffsetOldest
+		}
+		return kafka.NewMConsumerGroup(&kafka.MConsumerGroupConfig{
+			KafkaVersion:     sarama.V2_0_0_0,
+			OffsetsInitial:   offsetsInitial,
+			IsReturnErr:      false,
+			UserName:         config.Kafka.Username,
+			Password:         config.Kafka.Password,
+			SASLMechanism:    config.Kafka.SASLMechanism,
+			DeadLetterSuffix: config.MQ.DeadLetterSuffix,
+		}, topics, config.Kafka.Addr, groupID, kafka.NewTLSConfig(config))
+	case BackendNats:
+		return jetstream.NewConsumerGroup(config, groupID, topics, oldest)
+	default:
+		return nil, errs.ErrArgs.Wrap("unknown mq backend " + config.MQ.Backend)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqbuild
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"google.golang.org/protobuf/proto"
+)
+
+// AppTopic returns the topic dedicated to appID, see tenant.dedicatedTopicApps.
+func AppTopic(topic string, appID string) string {
+	return topic + "-" + appID
+}
+
+// tenantProducer sends the messages of the apps with a dedicated topic to it, the others to the shared topic.
+type tenantProducer struct {
+	mq.Producer
+	apps map[string]mq.Producer
+}
+
+func newTenantProducer(config *config.GlobalConfig, topic string, producer mq.Producer) (mq.Producer, error) {
+	if len(config.Tenant.DedicatedTopicApps) == 0 {
+		return producer, nil
+	}
+	p := &tenantProducer{Producer: producer, apps: make(map[string]mq.Producer, len(config.Tenant.DedicatedTopicApps))}
+	for _, appID := range config.Tenant.DedicatedTopicApps {
+		app, err := newProducer(config, AppTopic(topic, appID))
+		if err != nil {
+			return nil, err
+		}
+		p.apps[appID] = app
+	}
+	return p, nil
+}
+
+func (p *tenantProducer) SendMessage(ctx context.Context, key string, msg proto.Message) (int32, int64, error) {
+	if app, ok := p.apps[tenant.GetAppID(ctx)]; ok {
+		return app.SendMessage(ctx, key, msg)
+	}
+	return p.Producer.SendMessage(ctx, key, msg)
+}
+
+// tenantConsumerGroup consumes the dedicated topics of the apps along with the shared ones, the handler sees
+// their claims under the shared topic.
+type tenantConsumerGroup struct {
+	mq.ConsumerGroup
+	shared map[string]string
+}
+
+// appTopics returns topics with the dedicated topics of the apps and the shared topic of each of them.
+func appTopics(config *config.GlobalConfig, topics []string) ([]string, map[string]string) {
+	if len(config.Tenant.DedicatedTopicApps) == 0 {
+		return topics, nil
+	}
+	all := append([]string{}, topics...)
+	shared := make(map[string]string)
+	for _, topic := range topics {
+		for _, appID := range config.Tenant.DedicatedTopicApps {
+			all = append(all, AppTopic(topic, appID))
+			shared[AppTopic(topic, appID)] = topic
+		}
+	}
+	return all, shared
+}
+
+func (g *tenantConsumerGroup) Consume(ctx context.Context, handler mq.Handler) {
+	g.ConsumerGroup.Consume(ctx, &tenantHandler{Handler: handler, shared: g.shared})
+}
+
+// Pause, Resume, PauseAll and ResumeAll implement mq.Pauser when the wrapped group does, the partitions are those
+// of the dedicated topics themselves.
+func (g *tenantConsumerGroup) Pause(partitions map[string][]int32) {
+	if pauser, ok := g.ConsumerGroup.(mq.Pauser); ok {
+		pauser.Pause(partitions)
+	}
+}
+
+func (g *tenantConsumerGroup) Resume(partitions map[string][]int32) {
+	if pauser, ok := g.ConsumerGroup.(mq.Pauser); ok {
+		pauser.Resume(partitions)
+	}
+}
+
+func (g *tenantConsumerGroup) PauseAll() {
+	if pauser, ok := g.ConsumerGroup.(mq.Pauser); ok {
+		pauser.PauseAll()
+	}
+}
+
+func (g *tenantConsumerGroup) ResumeAll() {
+	if pauser, ok := g.ConsumerGroup.(mq.Pauser); ok {
+		pauser.ResumeAll()
+	}
+}
+
+type tenantHandler struct {
+	mq.Handler
+	shared map[string]string
+}
+
+func (h *tenantHandler) ConsumeClaim(ctx context.Context, claim mq.Claim) error {
+	if topic, ok := h.shared[claim.Topic()]; ok {
+		claim = mq.NewClaim(topic, claim.Messages())
+	}
+	return h.Handler.ConsumeClaim(ctx, claim)
+}
@@ -0,0 +1,71 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqbuild
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"google.golang.org/protobuf/proto"
+)
+
+type countProducer struct {
+	sent int
+}
+
+func (p *countProducer) SendMessage(ctx context.Context, key string, msg proto.Message) (int32, int64, error) {
+	p.sent++
+	return 0, 0, nil
+}
+
+func TestTenantProducer(t *testing.T) {
+	shared, dedicated := &countProducer{}, &countProducer{}
+	p := &tenantProducer{Producer: shared, apps: map[string]mq.Producer{"a": dedicated}}
+	ctx := context.Background()
+	_, _, _ = p.SendMessage(ctx, "k", nil)
+	_, _, _ = p.SendMessage(tenant.WithAppID(ctx, "b"), "k", nil)
+	_, _, _ = p.SendMessage(tenant.WithAppID(ctx, "a"), "k", nil)
+	if shared.sent != 2 || dedicated.sent != 1 {
+		t.Fatalf("shared %d dedicated %d", shared.sent, dedicated.sent)
+	}
+}
+
+type topicHandler struct {
+	topics []string
+}
+
+func (h *topicHandler) ConsumeClaim(ctx context.Context, claim mq.Claim) error {
+	h.topics = append(h.topics, claim.Topic())
+	return nil
+}
+
+func TestAppTopics(t *testing.T) {
+	conf := &config.GlobalConfig{}
+	conf.Tenant.DedicatedTopicApps = []string{"a"}
+	topics, shared := appTopics(conf, []string{"toPush"})
+	if len(topics) != 2 || topics[1] != "toPush-a" || shared["toPush-a"] != "toPush" {
+		t.Fatalf("topics %v shared %v", topics, shared)
+	}
+	h := &topicHandler{}
+	th := &tenantHandler{Handler: h, shared: shared}
+	_ = th.ConsumeClaim(context.Background(), mq.NewClaim("toPush-a", nil))
+	_ = th.ConsumeClaim(context.Background(), mq.NewClaim("toPush", nil))
+	if h.topics[0] != "toPush" || h.topics[1] != "toPush" {
+		t.Fatalf("handler saw %v", h.topics)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prommetrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	DestinationCallCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "destination_call_total",
+		Help: "The number of calls to webhook urls and offline push providers by destination and result",
+	}, []string{"destination", "result"})
+	DestinationStateGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "destination_state",
+		Help: "The state of a webhook url or offline push provider, 0 healthy, 1 throttled, 2 disabled",
+	}, []string{"destination"})
+)
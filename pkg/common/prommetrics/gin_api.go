@@ -14,7 +14,10 @@
 
 package prommetrics
 
-import ginprom "github.com/openimsdk/open-im-server/v3/pkg/common/ginprometheus"
+import (
+	ginprom "github.com/openimsdk/open-im-server/v3/pkg/common/ginprometheus"
+	"github.com/prometheus/client_golang/prometheus"
+)
 
 /*
 labels := prometheus.Labels{"label_one": "any", "label_two": "value"}
@@ -28,3 +31,19 @@ var (
 		Args:        []string{"label_one", "label_two"},
 	}
 )
+
+// RegisterGinCusCollectors registers the collectors name updates outside of the gin middleware, the gin metrics are
+// served from the default registry.
+func RegisterGinCusCollectors(name string) error {
+	var collectors []prometheus.Collector
+	switch name {
+	case "Api":
+		collectors = []prometheus.Collector{TokenStandbyCounter}
+	}
+	for _, collector := range collectors {
+		if err := prometheus.Register(collector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -23,4 +23,8 @@ var (
 		Name: "user_login_total",
 		Help: "The number of user login",
 	})
+	TokenStandbyCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "token_standby_total",
+		Help: "The number of tokens verified without the token cache, by whether they were accepted",
+	}, []string{"result"})
 )
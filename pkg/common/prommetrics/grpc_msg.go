@@ -35,4 +35,20 @@ var (
 		Name: "group_chat_msg_process_failed_total",
 		Help: "The number of group chat msg failed processed",
 	})
+	MsgPriorityCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "msg_priority_total",
+		Help: "The number of msg sent by priority",
+	}, []string{"priority"})
+	MsgPriorityThrottledCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "msg_priority_throttled_total",
+		Help: "The number of msg held back by the rate of their priority",
+	}, []string{"priority"})
+	RecentMsgCacheHitCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "recent_msg_cache_hit_total",
+		Help: "The number of msg pulls served from the latest messages of the conversation alone",
+	})
+	RecentMsgCacheMissCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "recent_msg_cache_miss_total",
+		Help: "The number of msg pulls that read part of the msgs from the msg cache",
+	})
 )
@@ -23,4 +23,16 @@ var (
 		Name: "online_user_num",
 		Help: "The number of online user num",
 	})
+	OnlinePlatformGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "online_platform_conn_num",
+		Help: "The number of online connections by platform",
+	}, []string{"platform"})
+	PushRedeliveredCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "push_redelivered_total",
+		Help: "The number of pushes written again because the client did not ack them",
+	})
+	PushOutOfSyncCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "push_out_of_sync_total",
+		Help: "The number of conversations a client was told to pull after its pushes went unacked",
+	})
 )
@@ -23,4 +23,20 @@ var (
 		Name: "msg_offline_push_failed_total",
 		Help: "The number of msg failed offline pushed",
 	})
+	MsgOfflinePushPriorityCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "msg_offline_push_priority_total",
+		Help: "The number of msg offline pushed by priority",
+	}, []string{"priority"})
+	MsgOfflinePushRetryCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "msg_offline_push_retry_total",
+		Help: "The number of offline push retries by result",
+	}, []string{"result"})
+	MsgOfflinePushDeadLetterCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "msg_offline_push_dead_letter_total",
+		Help: "The number of offline pushes dead-lettered after exhausting their retries",
+	})
+	PushConsumerLagGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "push_consumer_lag",
+		Help: "The number of messages not consumed yet in the partitions consumed by the instance",
+	}, []string{"topic", "partition"})
 )
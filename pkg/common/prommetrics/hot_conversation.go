@@ -0,0 +1,34 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prommetrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	HotConversationPromotedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "hot_conversation_promoted_total",
+		Help: "The number of conversations promoted to the hot local cache",
+	})
+	HotConversationEvictedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "hot_conversation_evicted_total",
+		Help: "The number of conversations evicted from the hot local cache",
+	})
+	HotConversationGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "hot_conversation_num",
+		Help: "The number of conversations currently hot",
+	})
+)
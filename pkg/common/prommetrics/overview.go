@@ -0,0 +1,140 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prommetrics
+
+import (
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Metric families read into an OverviewReport.
+const (
+	grpcHandledTotal        = "grpc_server_handled_total"
+	onlineUserNum           = "online_user_num"
+	onlinePlatformConnNum   = "online_platform_conn_num"
+	singleChatMsgSuccessNum = "single_chat_msg_process_success_total"
+	groupChatMsgSuccessNum  = "group_chat_msg_process_success_total"
+)
+
+// RpcCalls counts the calls of one grpc service handled in the report interval.
+type RpcCalls struct {
+	Total  int64 `json:"total"`
+	Errors int64 `json:"errors"`
+}
+
+// OverviewReport is what one instance publishes for the system overview. Counters are the increase over the
+// Interval seconds before UpdateTime, gauges are the current value.
+type OverviewReport struct {
+	OnlineUsers     int64                `json:"onlineUsers,omitempty"`
+	OnlinePlatforms map[string]int64     `json:"onlinePlatforms,omitempty"`
+	MsgProcessed    int64                `json:"msgProcessed,omitempty"`
+	RpcCalls        map[string]*RpcCalls `json:"rpcCalls,omitempty"`
+	Interval        int64                `json:"interval"`
+	UpdateTime      int64                `json:"updateTime"`
+}
+
+type counterSample struct {
+	time   time.Time
+	values map[string]float64
+}
+
+// OverviewCollector builds OverviewReports from the metrics of one registry, counters are reported as their
+// increase over a sliding window.
+type OverviewCollector struct {
+	gatherer prometheus.Gatherer
+	window   time.Duration
+	samples  []counterSample
+}
+
+func NewOverviewCollector(gatherer prometheus.Gatherer, window time.Duration) *OverviewCollector {
+	return &OverviewCollector{gatherer: gatherer, window: window}
+}
+
+// Collect gathers the metrics and returns the report of the window ending now. Until the collector has run
+// for a whole window the report covers the time since its first call.
+func (o *OverviewCollector) Collect() (*OverviewReport, error) {
+	families, err := o.gatherer.Gather()
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	now := time.Now()
+	// base is the newest sample at least a window old, older samples are no longer needed.
+	for len(o.samples) > 1 && now.Sub(o.samples[1].time) >= o.window {
+		o.samples = o.samples[1:]
+	}
+	base := counterSample{time: now}
+	if len(o.samples) > 0 {
+		base = o.samples[0]
+	}
+	report := &OverviewReport{Interval: int64(now.Sub(base.time).Seconds()), UpdateTime: now.UnixMilli()}
+	current := make(map[string]float64)
+	for _, family := range families {
+		switch family.GetName() {
+		case onlineUserNum:
+			for _, metric := range family.GetMetric() {
+				report.OnlineUsers += int64(metric.GetGauge().GetValue())
+			}
+		case onlinePlatformConnNum:
+			report.OnlinePlatforms = make(map[string]int64)
+			for _, metric := range family.GetMetric() {
+				report.OnlinePlatforms[labelValue(metric, "platform")] += int64(metric.GetGauge().GetValue())
+			}
+		case singleChatMsgSuccessNum, groupChatMsgSuccessNum:
+			for _, metric := range family.GetMetric() {
+				current[family.GetName()] = metric.GetCounter().GetValue()
+				report.MsgProcessed += delta(base.values, family.GetName(), metric.GetCounter().GetValue())
+			}
+		case grpcHandledTotal:
+			report.RpcCalls = make(map[string]*RpcCalls)
+			for _, metric := range family.GetMetric() {
+				service, code := labelValue(metric, "grpc_service"), labelValue(metric, "grpc_code")
+				key := service + "/" + labelValue(metric, "grpc_method") + "/" + code
+				current[key] = metric.GetCounter().GetValue()
+				calls, ok := report.RpcCalls[service]
+				if !ok {
+					calls = &RpcCalls{}
+					report.RpcCalls[service] = calls
+				}
+				n := delta(base.values, key, metric.GetCounter().GetValue())
+				calls.Total += n
+				if code != "OK" {
+					calls.Errors += n
+				}
+			}
+		}
+	}
+	o.samples = append(o.samples, counterSample{time: now, values: current})
+	return report, nil
+}
+
+// delta returns the increase of a counter since base, a counter missing from base or reset since counts from zero.
+func delta(base map[string]float64, key string, value float64) int64 {
+	if last, ok := base[key]; ok && value >= last {
+		return int64(value - last)
+	}
+	return int64(value)
+}
+
+func labelValue(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
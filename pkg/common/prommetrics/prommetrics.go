@@ -31,16 +31,22 @@ func NewGrpcPromObj(cusMetrics []prometheus.Collector) (*prometheus.Registry, *g
 	return reg, grpcMetrics, nil
 }
 
+// GetGrpcCusMetrics returns the custom metrics of the rpc service, every service calls webhooks and reports the
+// state of their destinations.
 func GetGrpcCusMetrics(registerName string, config *config2.GlobalConfig) []prometheus.Collector {
+	return append(grpcCusMetrics(registerName, config), DestinationCallCounter, DestinationStateGauge)
+}
+
+func grpcCusMetrics(registerName string, config *config2.GlobalConfig) []prometheus.Collector {
 	switch registerName {
 	case config.RpcRegisterName.OpenImMessageGatewayName:
-		return []prometheus.Collector{OnlineUserGauge}
+		return []prometheus.Collector{OnlineUserGauge, OnlinePlatformGauge, PushRedeliveredCounter, PushOutOfSyncCounter, TokenStandbyCounter}
 	case config.RpcRegisterName.OpenImMsgName:
-		return []prometheus.Collector{SingleChatMsgProcessSuccessCounter, SingleChatMsgProcessFailedCounter, GroupChatMsgProcessSuccessCounter, GroupChatMsgProcessFailedCounter}
+		return []prometheus.Collector{SingleChatMsgProcessSuccessCounter, SingleChatMsgProcessFailedCounter, GroupChatMsgProcessSuccessCounter, GroupChatMsgProcessFailedCounter, MsgPriorityCounter, MsgPriorityThrottledCounter, HotConversationPromotedCounter, HotConversationEvictedCounter, HotConversationGauge, RecentMsgCacheHitCounter, RecentMsgCacheMissCounter}
 	case "Transfer":
-		return []prometheus.Collector{MsgInsertRedisSuccessCounter, MsgInsertRedisFailedCounter, MsgInsertMongoSuccessCounter, MsgInsertMongoFailedCounter, SeqSetFailedCounter}
+		return []prometheus.Collector{MsgInsertRedisSuccessCounter, MsgInsertRedisFailedCounter, MsgInsertMongoSuccessCounter, MsgInsertMongoFailedCounter, MsgInsertMongoDeadLetterCounter, SeqSetFailedCounter, SeqSegmentLeaseCounter, SeqSegmentRecoverCounter, SeqSegmentConflictCounter}
 	case config.RpcRegisterName.OpenImPushName:
-		return []prometheus.Collector{MsgOfflinePushFailedCounter}
+		return []prometheus.Collector{MsgOfflinePushFailedCounter, MsgOfflinePushPriorityCounter, MsgOfflinePushRetryCounter, MsgOfflinePushDeadLetterCounter, PushConsumerLagGauge, HotConversationPromotedCounter, HotConversationEvictedCounter, HotConversationGauge}
 	case config.RpcRegisterName.OpenImAuthName:
 		return []prometheus.Collector{UserLoginCounter}
 	default:
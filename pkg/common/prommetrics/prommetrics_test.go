@@ -59,14 +59,17 @@ func TestNewGrpcPromObj(t *testing.T) {
 
 func TestGetGrpcCusMetrics(t *testing.T) {
 	conf := config2.NewGlobalConfig()
-
-	config2.InitConfig(conf, "../../config")
+	conf.RpcRegisterName.OpenImMessageGatewayName = "MessageGateway"
+	conf.RpcRegisterName.OpenImMsgName = "Msg"
+	conf.RpcRegisterName.OpenImPushName = "Push"
+	conf.RpcRegisterName.OpenImAuthName = "Auth"
 	// Test various cases based on the switch statement in the GetGrpcCusMetrics function.
 	testCases := []struct {
 		name     string
 		expected int // The expected number of metrics for each case.
 	}{
-		{conf.RpcRegisterName.OpenImMessageGatewayName, 1},
+		{conf.RpcRegisterName.OpenImMessageGatewayName, 7},
+		{conf.RpcRegisterName.OpenImPushName, 10},
 	}
 
 	for _, tc := range testCases {
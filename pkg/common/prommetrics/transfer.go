@@ -35,8 +35,24 @@ var (
 		Name: "msg_insert_mongo_failed_total",
 		Help: "The number of failed insert msg to mongo",
 	})
+	MsgInsertMongoDeadLetterCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "msg_insert_mongo_dead_letter_total",
+		Help: "The number of msgs moved to the dead letter topic after failing to be inserted to mongo",
+	})
 	SeqSetFailedCounter = prometheus.NewCounter(prometheus.CounterOpts{
 		Name: "seq_set_failed_total",
 		Help: "The number of failed set seq",
 	})
+	SeqSegmentLeaseCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "seq_segment_lease_total",
+		Help: "The number of seq segments leased from mongo",
+	})
+	SeqSegmentRecoverCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "seq_segment_recover_total",
+		Help: "The number of seq segments lost in redis and recovered from the mongo high-water mark",
+	})
+	SeqSegmentConflictCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "seq_segment_conflict_total",
+		Help: "The number of leased seq segments dropped because another allocator changed the segment first",
+	})
 )
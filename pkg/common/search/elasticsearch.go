@@ -0,0 +1,277 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+// esDoc is the indexed form of a message, its id is conversationID:seq so reindexing is idempotent.
+type esDoc struct {
+	ConversationID string `json:"conversation_id"`
+	Seq            int64  `json:"seq"`
+	ClientMsgID    string `json:"client_msg_id"`
+	SendID         string `json:"send_id"`
+	RecvID         string `json:"recv_id"`
+	GroupID        string `json:"group_id"`
+	SessionType    int32  `json:"session_type"`
+	ContentType    int32  `json:"content_type"`
+	SendTime       int64  `json:"send_time"`
+	Text           string `json:"text"`
+}
+
+type esSearchResp struct {
+	Hits struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			Score     float64             `json:"_score"`
+			Source    esDoc               `json:"_source"`
+			Highlight map[string][]string `json:"highlight"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+type elasticsearch struct {
+	address    string
+	index      string
+	groupIndex string
+	username   string
+	password   string
+	client     *http.Client
+}
+
+func newElasticsearch(config *config.GlobalConfig) (*elasticsearch, error) {
+	conf := config.Search.Elasticsearch
+	if conf.Address == "" {
+		return nil, errs.ErrArgs.Wrap("elasticsearch address is empty")
+	}
+	if conf.Index == "" {
+		return nil, errs.ErrArgs.Wrap("elasticsearch index is empty")
+	}
+	return &elasticsearch{
+		address:    strings.TrimSuffix(conf.Address, "/"),
+		index:      conf.Index,
+		groupIndex: conf.GroupIndex,
+		username:   conf.Username,
+		password:   conf.Password,
+		client:     &http.Client{Timeout: time.Duration(conf.Timeout) * time.Second},
+	}, nil
+}
+
+func (e *elasticsearch) do(ctx context.Context, method string, path string, contentType string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, e.address+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if e.username != "" {
+		req.SetBasicAuth(e.username, e.password)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, errs.Wrap(err, "elasticsearch request failed")
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, errs.Wrap(fmt.Errorf("elasticsearch %s %s: status %d: %s", method, path, resp.StatusCode, data))
+	}
+	return data, nil
+}
+
+func (e *elasticsearch) Index(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) error {
+	var buf bytes.Buffer
+	for _, msg := range msgs {
+		text := Text(msg)
+		if text == "" {
+			continue
+		}
+		action := map[string]any{"index": map[string]any{"_id": conversationID + ":" + strconv.FormatInt(msg.Seq, 10)}}
+		doc := &esDoc{
+			ConversationID: conversationID,
+			Seq:            msg.Seq,
+			ClientMsgID:    msg.ClientMsgID,
+			SendID:         msg.SendID,
+			RecvID:         msg.RecvID,
+			GroupID:        msg.GroupID,
+			SessionType:    msg.SessionType,
+			ContentType:    msg.ContentType,
+			SendTime:       msg.SendTime,
+			Text:           text,
+		}
+		for _, line := range []any{action, doc} {
+			data, err := json.Marshal(line)
+			if err != nil {
+				return errs.Wrap(err)
+			}
+			buf.Write(data)
+			buf.WriteByte('\n')
+		}
+	}
+	if buf.Len() == 0 {
+		return nil
+	}
+	data, err := e.do(ctx, http.MethodPost, "/"+e.index+"/_bulk", "application/x-ndjson", buf.Bytes())
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Errors bool `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return errs.Wrap(err)
+	}
+	if resp.Errors {
+		return errs.Wrap(fmt.Errorf("elasticsearch bulk index reported errors: %s", data))
+	}
+	return nil
+}
+
+func (e *elasticsearch) Delete(ctx context.Context, conversationID string, seqs []int64) error {
+	if len(seqs) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, seq := range seqs {
+		action := map[string]any{"delete": map[string]any{"_id": conversationID + ":" + strconv.FormatInt(seq, 10)}}
+		data, err := json.Marshal(action)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	data, err := e.do(ctx, http.MethodPost, "/"+e.index+"/_bulk", "application/x-ndjson", buf.Bytes())
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Items []map[string]struct {
+			Status int `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return errs.Wrap(err)
+	}
+	// Messages that were never indexed answer 404, which is fine.
+	for _, item := range resp.Items {
+		for _, result := range item {
+			if result.Status/100 != 2 && result.Status != http.StatusNotFound {
+				return errs.Wrap(fmt.Errorf("elasticsearch bulk delete reported errors: %s", data))
+			}
+		}
+	}
+	return nil
+}
+
+func (e *elasticsearch) Search(ctx context.Context, query *Query) (int64, []*Hit, error) {
+	filter := []any{}
+	if len(query.ConversationIDs) > 0 {
+		filter = append(filter, map[string]any{"terms": map[string]any{"conversation_id": query.ConversationIDs}})
+	}
+	if query.SendID != "" {
+		filter = append(filter, map[string]any{"term": map[string]any{"send_id": query.SendID}})
+	}
+	if len(query.ContentTypes) > 0 {
+		filter = append(filter, map[string]any{"terms": map[string]any{"content_type": query.ContentTypes}})
+	}
+	if query.StartTime > 0 || query.EndTime > 0 {
+		sendTime := map[string]any{}
+		if query.StartTime > 0 {
+			sendTime["gte"] = query.StartTime
+		}
+		if query.EndTime > 0 {
+			sendTime["lte"] = query.EndTime
+		}
+		filter = append(filter, map[string]any{"range": map[string]any{"send_time": sendTime}})
+	}
+	if len(query.MinSendTimes) > 0 {
+		bounded := make([]string, 0, len(query.MinSendTimes))
+		should := []any{}
+		for conversationID, minSendTime := range query.MinSendTimes {
+			bounded = append(bounded, conversationID)
+			should = append(should, map[string]any{"bool": map[string]any{"filter": []any{
+				map[string]any{"term": map[string]any{"conversation_id": conversationID}},
+				map[string]any{"range": map[string]any{"send_time": map[string]any{"gte": minSendTime}}},
+			}}})
+		}
+		should = append(should, map[string]any{"bool": map[string]any{"must_not": []any{
+			map[string]any{"terms": map[string]any{"conversation_id": bounded}},
+		}}})
+		filter = append(filter, map[string]any{"bool": map[string]any{"should": should, "minimum_should_match": 1}})
+	}
+	pageNumber := query.PageNumber
+	if pageNumber < 1 {
+		pageNumber = 1
+	}
+	body, err := json.Marshal(map[string]any{
+		"from": (pageNumber - 1) * query.ShowNumber,
+		"size": query.ShowNumber,
+		"query": map[string]any{
+			"bool": map[string]any{
+				"must":   []any{map[string]any{"match": map[string]any{"text": query.Keyword}}},
+				"filter": filter,
+			},
+		},
+		"highlight": map[string]any{"fields": map[string]any{"text": map[string]any{}}},
+		"sort":      []any{"_score", map[string]any{"send_time": "desc"}},
+	})
+	if err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	data, err := e.do(ctx, http.MethodPost, "/"+e.index+"/_search", "application/json", body)
+	if err != nil {
+		return 0, nil, err
+	}
+	var resp esSearchResp
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	hits := make([]*Hit, 0, len(resp.Hits.Hits))
+	for _, h := range resp.Hits.Hits {
+		hits = append(hits, &Hit{
+			ConversationID: h.Source.ConversationID,
+			Seq:            h.Source.Seq,
+			ClientMsgID:    h.Source.ClientMsgID,
+			SendID:         h.Source.SendID,
+			RecvID:         h.Source.RecvID,
+			GroupID:        h.Source.GroupID,
+			SessionType:    h.Source.SessionType,
+			ContentType:    h.Source.ContentType,
+			SendTime:       h.Source.SendTime,
+			Text:           h.Source.Text,
+			Score:          h.Score,
+			Highlights:     h.Highlight["text"],
+		})
+	}
+	return resp.Hits.Total.Value, hits, nil
+}
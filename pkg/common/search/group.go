@@ -0,0 +1,118 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+// GroupDoc is a group listed in the public directory.
+type GroupDoc struct {
+	GroupID      string   `json:"group_id"`
+	GroupName    string   `json:"group_name"`
+	Tags         []string `json:"tags"`
+	Introduction string   `json:"introduction"`
+}
+
+// GroupBackend keeps the public group directory and answers relevance ranked keyword queries over the group
+// names and tags.
+type GroupBackend interface {
+	IndexGroup(ctx context.Context, doc *GroupDoc) error
+	DeleteGroup(ctx context.Context, groupID string) error
+	// SearchGroups returns the ids of the groups matching keyword, best match first.
+	SearchGroups(ctx context.Context, keyword string, pageNumber int32, showNumber int32) (total int64, groupIDs []string, err error)
+}
+
+// NewGroupBackend returns the configured group directory backend, nil when the directory stays on mongo.
+func NewGroupBackend(config *config.GlobalConfig) (GroupBackend, error) {
+	switch config.Search.Backend {
+	case "", BackendMongo:
+		return nil, nil
+	case BackendElasticsearch:
+		if config.Search.Elasticsearch.GroupIndex == "" {
+			return nil, errs.ErrArgs.Wrap("elasticsearch groupIndex is empty")
+		}
+		return newElasticsearch(config)
+	default:
+		return nil, errs.ErrArgs.Wrap("unknown search backend " + config.Search.Backend)
+	}
+}
+
+func (e *elasticsearch) IndexGroup(ctx context.Context, doc *GroupDoc) error {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	_, err = e.do(ctx, http.MethodPut, "/"+e.groupIndex+"/_doc/"+url.PathEscape(doc.GroupID), "application/json", body)
+	return err
+}
+
+func (e *elasticsearch) DeleteGroup(ctx context.Context, groupID string) error {
+	// A missing document is not an error, the group may never have been listed.
+	body, err := json.Marshal(map[string]any{"query": map[string]any{"term": map[string]any{"_id": groupID}}})
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	_, err = e.do(ctx, http.MethodPost, "/"+e.groupIndex+"/_delete_by_query", "application/json", body)
+	return err
+}
+
+func (e *elasticsearch) SearchGroups(ctx context.Context, keyword string, pageNumber int32, showNumber int32) (int64, []string, error) {
+	if pageNumber < 1 {
+		pageNumber = 1
+	}
+	body, err := json.Marshal(map[string]any{
+		"from":    (pageNumber - 1) * showNumber,
+		"size":    showNumber,
+		"_source": false,
+		"query": map[string]any{
+			"multi_match": map[string]any{
+				"query":  keyword,
+				"fields": []string{"group_name^3", "tags^2", "introduction"},
+			},
+		},
+	})
+	if err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	data, err := e.do(ctx, http.MethodPost, "/"+e.groupIndex+"/_search", "application/json", body)
+	if err != nil {
+		return 0, nil, err
+	}
+	var resp struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				ID string `json:"_id"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return 0, nil, errs.Wrap(err)
+	}
+	groupIDs := make([]string, 0, len(resp.Hits.Hits))
+	for _, hit := range resp.Hits.Hits {
+		groupIDs = append(groupIDs, hit.ID)
+	}
+	return resp.Hits.Total.Value, groupIDs, nil
+}
@@ -0,0 +1,107 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package search
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+const (
+	BackendMongo         = "mongo"
+	BackendElasticsearch = "elasticsearch"
+)
+
+// Query is a full-text message search, the filters are ignored when empty.
+type Query struct {
+	Keyword         string
+	ConversationIDs []string
+	SendID          string
+	ContentTypes    []int32
+	// StartTime and EndTime bound the send time, in ms.
+	StartTime int64
+	EndTime   int64
+	// MinSendTimes bounds the send time (ms) per conversation, for members who may not read older history.
+	MinSendTimes map[string]int64
+	PageNumber   int32
+	ShowNumber   int32
+}
+
+// Hit is an indexed message matching a Query.
+type Hit struct {
+	ConversationID string   `json:"conversationID"`
+	Seq            int64    `json:"seq"`
+	ClientMsgID    string   `json:"clientMsgID"`
+	SendID         string   `json:"sendID"`
+	RecvID         string   `json:"recvID"`
+	GroupID        string   `json:"groupID"`
+	SessionType    int32    `json:"sessionType"`
+	ContentType    int32    `json:"contentType"`
+	SendTime       int64    `json:"sendTime"`
+	Text           string   `json:"text"`
+	Score          float64  `json:"score"`
+	Highlights     []string `json:"highlights"`
+}
+
+// Backend indexes messages and answers relevance ranked full-text queries.
+type Backend interface {
+	// Index adds the text messages among msgs, which belong to conversationID, to the index.
+	Index(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) error
+	// Delete removes the messages of conversationID with seqs from the index.
+	Delete(ctx context.Context, conversationID string, seqs []int64) error
+	Search(ctx context.Context, query *Query) (total int64, hits []*Hit, err error)
+}
+
+// NewBackend returns the configured full-text backend, nil when search stays on mongo.
+func NewBackend(config *config.GlobalConfig) (Backend, error) {
+	switch config.Search.Backend {
+	case "", BackendMongo:
+		return nil, nil
+	case BackendElasticsearch:
+		return newElasticsearch(config)
+	default:
+		return nil, errs.ErrArgs.Wrap("unknown search backend " + config.Search.Backend)
+	}
+}
+
+// Text returns the searchable text of a message, empty for content types that are not indexed.
+func Text(msg *sdkws.MsgData) string {
+	var elem struct {
+		Content  string `json:"content"`
+		Text     string `json:"text"`
+		FileName string `json:"fileName"`
+	}
+	switch msg.ContentType {
+	case constant.Text, constant.AtText, constant.Quote, constant.File:
+	default:
+		return ""
+	}
+	if err := json.Unmarshal(msg.Content, &elem); err != nil {
+		return ""
+	}
+	switch msg.ContentType {
+	case constant.Text:
+		return elem.Content
+	case constant.File:
+		return elem.FileName
+	default:
+		return elem.Text
+	}
+}
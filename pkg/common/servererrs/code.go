@@ -0,0 +1,34 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package servererrs holds the error codes this server adds to the ones of the tools errs package. Every code is
+// declared here so a new one can not collide with an existing one.
+package servererrs
+
+// Message errors, next to the message codes of the tools errs package.
+const (
+	ContentTypeNotAllowedError = 1450 // The content type of a message is not allowed in its conversation.
+	MsgSendingError            = 1451 // A retried message is still being sent.
+	LegalHoldError             = 1452 // A delete is refused because of a legal hold.
+	DestinationThrottledError  = 1453 // A webhook url or push provider is throttled or disabled.
+	MsgTooLargeError           = 1454 // The payload of a message is over the limit of its content type.
+	MsgBlockedError            = 1455 // A message is rejected by a moderation rule.
+	AntiSpamError              = 1456 // A message is rejected by the anti-spam limits.
+	NotificationQuotaError     = 1457 // A notification account is over its quota.
+)
+
+// Api errors, in a range the tools errs package does not use.
+const (
+	TooManyRequestsError = 1801 // A request is rejected because its bulkhead pool or user limit is full.
+)
@@ -0,0 +1,29 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servererrs
+
+import "github.com/OpenIMSDK/tools/errs"
+
+var (
+	ErrContentTypeNotAllowed = errs.NewCodeError(ContentTypeNotAllowedError, "ContentTypeNotAllowedError")
+	ErrMsgSending            = errs.NewCodeError(MsgSendingError, "MsgSendingError")
+	ErrLegalHold             = errs.NewCodeError(LegalHoldError, "LegalHoldError")
+	ErrThrottled             = errs.NewCodeError(DestinationThrottledError, "DestinationThrottledError")
+	ErrMsgTooLarge           = errs.NewCodeError(MsgTooLargeError, "MsgTooLargeError")
+	ErrMsgBlocked            = errs.NewCodeError(MsgBlockedError, "MsgBlockedError")
+	ErrAntiSpam              = errs.NewCodeError(AntiSpamError, "AntiSpamError")
+	ErrNotificationQuota     = errs.NewCodeError(NotificationQuotaError, "NotificationQuotaError")
+	ErrTooManyRequests       = errs.NewCodeError(TooManyRequestsError, "TooManyRequestsError")
+)
@@ -0,0 +1,68 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package startrpc
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	overviewReportInterval = time.Second * 10
+	overviewWindow         = time.Minute
+)
+
+// reportOverview publishes the metrics of this instance for the system overview every overviewReportInterval.
+func reportOverview(config *config.GlobalConfig, rpcRegisterName string, instance string, gatherer prometheus.Gatherer) error {
+	rdb, err := cache.NewRedis(config)
+	if err != nil {
+		return err
+	}
+	overviewCache := cache.NewOverviewCache(rdb)
+	collector := prommetrics.NewOverviewCollector(gatherer, overviewWindow)
+	// The first report has no window to compare its counters with.
+	if _, err := collector.Collect(); err != nil {
+		return err
+	}
+	go func() {
+		ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+		ticker := time.NewTicker(overviewReportInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			report, err := collector.Collect()
+			if err != nil {
+				log.ZWarn(ctx, "collect overview failed", err)
+				continue
+			}
+			data, err := json.Marshal(report)
+			if err != nil {
+				log.ZWarn(ctx, "marshal overview failed", err)
+				continue
+			}
+			if err := overviewCache.SetReport(ctx, rpcRegisterName, instance, string(data), overviewReportInterval*3); err != nil {
+				log.ZWarn(ctx, "set overview report failed", err)
+			}
+		}
+	}()
+	return nil
+}
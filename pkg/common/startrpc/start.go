@@ -16,6 +16,7 @@ package startrpc
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -36,6 +37,9 @@ import (
 	config2 "github.com/openimsdk/open-im-server/v3/pkg/common/config"
 	kdisc "github.com/openimsdk/open-im-server/v3/pkg/common/discoveryregister"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/topology"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/version"
 	util "github.com/openimsdk/open-im-server/v3/pkg/util/genutil"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -87,6 +91,10 @@ func Start(
 		options = append(options, mw.GrpcServer())
 	}
 
+	if err := tenant.Init(config); err != nil {
+		return err
+	}
+
 	srv := grpc.NewServer(options...)
 	once := sync.Once{}
 	defer func() {
@@ -97,6 +105,10 @@ func Start(
 	if err != nil {
 		return err
 	}
+	buildInfo := version.NewBuildInfo(rpcRegisterName, config)
+	if err := version.Register(srv, buildInfo); err != nil {
+		return err
+	}
 	err = client.Register(
 		rpcRegisterName,
 		registerIP,
@@ -106,6 +118,22 @@ func Start(
 	if err != nil {
 		return errs.Wrap(err)
 	}
+	registerAddr := net.JoinHostPort(registerIP, strconv.Itoa(rpcPort))
+	buildData, err := json.Marshal(buildInfo)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	if err := client.RegisterConf2Registry(version.RegistryKey(rpcRegisterName, registerAddr), buildData); err != nil {
+		return errs.Wrap(err)
+	}
+	if err := topology.Report(config, rpcRegisterName, registerAddr); err != nil {
+		return err
+	}
+	if config.Prometheus.Enable {
+		if err := reportOverview(config, rpcRegisterName, registerAddr, reg); err != nil {
+			return err
+		}
+	}
 
 	var (
 		netDone    = make(chan struct{}, 2)
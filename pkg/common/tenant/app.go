@@ -0,0 +1,116 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+// IDSeparator separates the app prefix from the rest of an id when tenant.isolation is set.
+const IDSeparator = "_"
+
+// AppOf returns the registered app id belongs to, empty when id carries no app prefix.
+func (s *Store) AppOf(id string) string {
+	i := strings.Index(id, IDSeparator)
+	if i <= 0 {
+		return ""
+	}
+	if _, ok := s.App(id[:i]); !ok {
+		return ""
+	}
+	return id[:i]
+}
+
+// CheckIDs rejects the ids that do not belong to appID: ids of another app, and for an app every id without
+// its prefix. Empty ids and the admin user ids of the cluster are shared by all apps.
+func (s *Store) CheckIDs(appID string, ids ...string) error {
+	for _, id := range ids {
+		if id == "" || utils.IsContain(id, s.base.IMAdmin.UserID) || utils.IsContain(id, s.base.Manager.UserID) {
+			continue
+		}
+		if s.AppOf(id) != appID {
+			return errs.ErrNoPermission.Wrap(fmt.Sprintf("id %s does not belong to app %q", id, appID))
+		}
+	}
+	return nil
+}
+
+// IsolationEnabled reports whether the apps are isolated from each other, see tenant.isolation.
+func IsolationEnabled() bool {
+	store := defaultStore.Load()
+	return store != nil && store.base.Tenant.Isolation
+}
+
+// Secret returns the secret the app ctx belongs to uses to get tokens and register users, the global secret
+// for the default tenant. With isolation an unregistered app has no secret.
+func Secret(ctx context.Context, base *config.GlobalConfig) (string, error) {
+	store := defaultStore.Load()
+	appID := GetAppID(ctx)
+	if store == nil || appID == "" {
+		return base.Secret, nil
+	}
+	if app, ok := store.App(appID); ok {
+		return app.Secret, nil
+	}
+	if store.base.Tenant.Isolation {
+		return "", errs.ErrArgs.Wrap(fmt.Sprintf("app %s is not registered", appID))
+	}
+	return base.Secret, nil
+}
+
+// IsAppManager reports whether userID manages the app ctx belongs to.
+func IsAppManager(ctx context.Context, userID string) bool {
+	store := defaultStore.Load()
+	return store != nil && store.isManager(GetAppID(ctx), userID)
+}
+
+// IsAppAdmin reports whether userID may use the admin apis as a manager of the app ctx belongs to. Without
+// tenant.isolation nothing keeps the ids those apis act on inside the app, so app managers are no admins then.
+func IsAppAdmin(ctx context.Context, userID string) bool {
+	return IsolationEnabled() && IsAppManager(ctx, userID)
+}
+
+// ManagesUser reports whether managerID manages the app ctx belongs to and userID belongs to that app.
+func ManagesUser(ctx context.Context, managerID string, userID string) bool {
+	store := defaultStore.Load()
+	if store == nil {
+		return false
+	}
+	appID := GetAppID(ctx)
+	return store.isManager(appID, managerID) && store.AppOf(userID) == appID
+}
+
+func (s *Store) isManager(appID string, userID string) bool {
+	if appID == "" || userID == "" {
+		return false
+	}
+	app, ok := s.App(appID)
+	return ok && utils.IsContain(userID, app.ManagerUserIDs)
+}
+
+// CheckIDs rejects the ids that do not belong to the app ctx belongs to. It does nothing unless
+// tenant.isolation is set.
+func CheckIDs(ctx context.Context, ids ...string) error {
+	if !IsolationEnabled() {
+		return nil
+	}
+	return defaultStore.Load().CheckIDs(GetAppID(ctx), ids...)
+}
@@ -0,0 +1,86 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+)
+
+func TestCheckIDs(t *testing.T) {
+	base := &config.GlobalConfig{}
+	base.IMAdmin.UserID = []string{"imAdmin"}
+	store := NewStore(base, nil, nil)
+	store.apps = map[string]*relation.AppModel{"a": {AppID: "a"}, "b": {AppID: "b"}}
+
+	if err := store.CheckIDs("a", "a_1", "a_2", "imAdmin", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CheckIDs("", "1", "unknown_1", "imAdmin"); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		appID string
+		id    string
+	}{
+		{"a", "b_1"},
+		{"a", "1"},
+		{"a", "unknown_1"},
+		{"", "a_1"},
+	} {
+		if err := store.CheckIDs(c.appID, c.id); err == nil {
+			t.Fatalf("id %s must be rejected for app %q", c.id, c.appID)
+		}
+	}
+}
+
+func TestAppManager(t *testing.T) {
+	base := &config.GlobalConfig{}
+	store := NewStore(base, nil, nil)
+	store.apps = map[string]*relation.AppModel{
+		"a": {AppID: "a", ManagerUserIDs: []string{"a_manager"}},
+		"b": {AppID: "b", ManagerUserIDs: []string{"b_manager"}},
+	}
+	defaultStore.Store(store)
+	defer defaultStore.Store(nil)
+
+	ctxA := WithAppID(context.Background(), "a")
+	if !ManagesUser(ctxA, "a_manager", "a_1") {
+		t.Fatal("a manager must manage the users of its app")
+	}
+	// A manager of b sending its request for app a, and a manager of a naming a user of b or of no app.
+	if ManagesUser(ctxA, "b_manager", "a_1") {
+		t.Fatal("b manager must not manage the users of app a")
+	}
+	for _, userID := range []string{"b_1", "1", ""} {
+		if ManagesUser(ctxA, "a_manager", userID) {
+			t.Fatalf("a manager must not manage user %q", userID)
+		}
+	}
+	if ManagesUser(context.Background(), "a_manager", "a_1") {
+		t.Fatal("a request without app manages no user")
+	}
+
+	if IsAppAdmin(ctxA, "a_manager") {
+		t.Fatal("app managers are no admins without isolation")
+	}
+	base.Tenant.Isolation = true
+	if !IsAppAdmin(ctxA, "a_manager") || IsAppAdmin(ctxA, "b_manager") {
+		t.Fatal("with isolation only the managers of the app are admins")
+	}
+}
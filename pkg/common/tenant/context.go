@@ -0,0 +1,47 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"context"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/utils"
+)
+
+// AppIDKey names the app a request belongs to, in the api header, the msggateway connection query,
+// the rpc metadata and the kafka message headers.
+const AppIDKey = "appID"
+
+// WithAppID attaches appID to ctx as an rpc custom header, so it is carried to the rpc servers called with ctx.
+func WithAppID(ctx context.Context, appID string) context.Context {
+	if appID == "" {
+		return ctx
+	}
+	keys, _ := ctx.Value(constant.RpcCustomHeader).([]string)
+	if !utils.IsContain(AppIDKey, keys) {
+		keys = append(append([]string{}, keys...), AppIDKey)
+	}
+	ctx = context.WithValue(ctx, AppIDKey, []string{appID})
+	return context.WithValue(ctx, constant.RpcCustomHeader, keys)
+}
+
+// GetAppID returns the app ctx belongs to, empty for the default tenant.
+func GetAppID(ctx context.Context) string {
+	if values, ok := ctx.Value(AppIDKey).([]string); ok && len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"fmt"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"gopkg.in/yaml.v3"
+)
+
+// overridable lists the config sections a tenant may override and, when not nil, the only keys of the
+// section it may set. Callbacks keep the enabled set of the cluster, a tenant only points them elsewhere.
+var overridable = map[string][]string{
+	"push":          nil,
+	"iosPush":       nil,
+	"callback":      {"url"},
+	"messageVerify": nil,
+	"msgEdit":       nil,
+}
+
+// Merge returns a copy of base with overrides applied. Overrides is a yaml document shaped like config.yaml,
+// keys it does not set keep the value of base.
+func Merge(base *config.GlobalConfig, overrides string) (*config.GlobalConfig, error) {
+	var sections map[string]any
+	if err := yaml.Unmarshal([]byte(overrides), &sections); err != nil {
+		return nil, errs.ErrArgs.Wrap("invalid tenant overrides: " + err.Error())
+	}
+	for section, value := range sections {
+		keys, ok := overridable[section]
+		if !ok {
+			return nil, errs.ErrArgs.Wrap(fmt.Sprintf("config section %s can not be overridden per tenant", section))
+		}
+		if keys == nil {
+			continue
+		}
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return nil, errs.ErrArgs.Wrap(fmt.Sprintf("config section %s must be a mapping", section))
+		}
+		for key := range fields {
+			if !utils.IsContain(key, keys) {
+				return nil, errs.ErrArgs.Wrap(fmt.Sprintf("config key %s.%s can not be overridden per tenant", section, key))
+			}
+		}
+	}
+	data, err := yaml.Marshal(base)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	merged := &config.GlobalConfig{}
+	if err := yaml.Unmarshal(data, merged); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if err := yaml.Unmarshal([]byte(overrides), merged); err != nil {
+		return nil, errs.ErrArgs.Wrap("invalid tenant overrides: " + err.Error())
+	}
+	return merged, nil
+}
@@ -0,0 +1,58 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+func TestMerge(t *testing.T) {
+	base := &config.GlobalConfig{}
+	base.Push.Enable = "fcm"
+	base.Push.Fcm.ServiceAccount = "cluster.json"
+	base.Callback.CallbackUrl = "http://cluster/callback"
+	base.Callback.CallbackBeforeSendSingleMsg.Enable = true
+	base.MsgEdit.Window = 60
+
+	merged, err := Merge(base, "push:\n  fcm:\n    serviceAccount: tenant.json\ncallback:\n  url: http://tenant/callback\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.Push.Fcm.ServiceAccount != "tenant.json" || merged.Callback.CallbackUrl != "http://tenant/callback" {
+		t.Fatalf("overrides not applied: %+v %+v", merged.Push.Fcm, merged.Callback.CallbackUrl)
+	}
+	if merged.Push.Enable != "fcm" || !merged.Callback.CallbackBeforeSendSingleMsg.Enable || merged.MsgEdit.Window != 60 {
+		t.Fatal("keys not overridden must keep the base value")
+	}
+	if base.Push.Fcm.ServiceAccount != "cluster.json" || base.Callback.CallbackUrl != "http://cluster/callback" {
+		t.Fatal("base must not be modified")
+	}
+}
+
+func TestMergeRejects(t *testing.T) {
+	base := &config.GlobalConfig{}
+	for _, overrides := range []string{
+		"mongo:\n  database: other\n",
+		"callback:\n  beforeSendSingleMsg:\n    enable: false\n",
+		"callback: http://tenant/callback\n",
+		"push: [",
+	} {
+		if _, err := Merge(base, overrides); err == nil {
+			t.Fatalf("overrides %q must be rejected", overrides)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/mgo"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/table/relation"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+)
+
+// defaultReloadInterval is used when tenant.reloadInterval is not set.
+const defaultReloadInterval = 30 * time.Second
+
+type tenantConfig struct {
+	updateTime time.Time
+	config     *config.GlobalConfig
+}
+
+// Store keeps the merged config of every tenant and the registered apps, and reloads them from the
+// tenant config and app collections.
+type Store struct {
+	base      *config.GlobalConfig
+	source    relation.TenantConfigModelInterface
+	appSource relation.AppModelInterface
+	lock      sync.RWMutex
+	tenants   map[string]*tenantConfig
+	apps      map[string]*relation.AppModel
+}
+
+func NewStore(base *config.GlobalConfig, source relation.TenantConfigModelInterface, appSource relation.AppModelInterface) *Store {
+	return &Store{
+		base:      base,
+		source:    source,
+		appSource: appSource,
+		tenants:   make(map[string]*tenantConfig),
+		apps:      make(map[string]*relation.AppModel),
+	}
+}
+
+// Reload reads all tenant configs and apps. Tenants whose overrides did not change keep their merged config,
+// and a tenant whose new overrides are invalid keeps its last valid one.
+func (s *Store) Reload(ctx context.Context) error {
+	models, err := s.source.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	appModels, err := s.appSource.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	apps := make(map[string]*relation.AppModel, len(appModels))
+	for _, app := range appModels {
+		apps[app.AppID] = app
+	}
+	s.lock.RLock()
+	old := s.tenants
+	s.lock.RUnlock()
+	tenants := make(map[string]*tenantConfig, len(models))
+	for _, model := range models {
+		if t, ok := old[model.AppID]; ok && t.updateTime.Equal(model.UpdateTime) {
+			tenants[model.AppID] = t
+			continue
+		}
+		merged, err := Merge(s.base, model.Overrides)
+		if err != nil {
+			log.ZWarn(ctx, "invalid tenant config is ignored", err, "appID", model.AppID)
+			if t, ok := old[model.AppID]; ok {
+				tenants[model.AppID] = t
+			}
+			continue
+		}
+		tenants[model.AppID] = &tenantConfig{updateTime: model.UpdateTime, config: merged}
+	}
+	s.lock.Lock()
+	s.tenants = tenants
+	s.apps = apps
+	s.lock.Unlock()
+	return nil
+}
+
+// Get returns the merged config of appID, false when the app has no overrides.
+func (s *Store) Get(appID string) (*config.GlobalConfig, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	t, ok := s.tenants[appID]
+	if !ok {
+		return nil, false
+	}
+	return t.config, true
+}
+
+// App returns the registered app appID.
+func (s *Store) App(appID string) (*relation.AppModel, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	app, ok := s.apps[appID]
+	return app, ok
+}
+
+func (s *Store) reloadLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+		if err := s.Reload(ctx); err != nil {
+			log.ZError(ctx, "reload tenant configs failed", err)
+		}
+	}
+}
+
+var defaultStore atomic.Pointer[Store]
+
+// Init loads the tenant configs and apps used by Lookup, Resolve and the app checks and reloads them every tenant.reloadInterval
+// seconds. It does nothing unless tenant.enable is set.
+func Init(config *config.GlobalConfig) error {
+	if !config.Tenant.Enable {
+		return nil
+	}
+	mongo, err := unrelation.NewMongo(config)
+	if err != nil {
+		return err
+	}
+	source, err := mgo.NewTenantConfigMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	appSource, err := mgo.NewAppMongo(mongo.GetDatabase(config.Mongo.Database))
+	if err != nil {
+		return err
+	}
+	store := NewStore(config, source, appSource)
+	if err := store.Reload(mcontext.NewCtx(utils.GetSelfFuncName())); err != nil {
+		return err
+	}
+	interval := time.Duration(config.Tenant.ReloadInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultReloadInterval
+	}
+	go store.reloadLoop(interval)
+	defaultStore.Store(store)
+	return nil
+}
+
+// Lookup returns the config of the app ctx belongs to, false when ctx carries no app or the app has no overrides.
+func Lookup(ctx context.Context) (*config.GlobalConfig, bool) {
+	store := defaultStore.Load()
+	if store == nil {
+		return nil, false
+	}
+	appID := GetAppID(ctx)
+	if appID == "" {
+		return nil, false
+	}
+	return store.Get(appID)
+}
+
+// Resolve returns the config of the app ctx belongs to, or base for the default tenant.
+func Resolve(ctx context.Context, base *config.GlobalConfig) *config.GlobalConfig {
+	if conf, ok := Lookup(ctx); ok {
+		return conf
+	}
+	return base
+}
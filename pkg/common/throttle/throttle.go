@@ -0,0 +1,350 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package throttle tracks the error rate and latency of the calls to each outgoing destination, a webhook url or
+// an offline push provider, and throttles or disables the failing ones so that they fail fast instead of holding
+// up the calls to the others. Each process keeps its own view of the destinations it calls.
+package throttle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+)
+
+type State int
+
+const (
+	StateHealthy State = iota
+	// StateThrottled admits throttledRate calls per second.
+	StateThrottled
+	// StateDisabled admits no call until the disable duration passed, then a single probe decides whether the
+	// destination recovered.
+	StateDisabled
+)
+
+func (s State) String() string {
+	switch s {
+	case StateHealthy:
+		return "healthy"
+	case StateThrottled:
+		return "throttled"
+	case StateDisabled:
+		return "disabled"
+	default:
+		return fmt.Sprintf("state(%d)", int(s))
+	}
+}
+
+type Config struct {
+	// Window is the number of latest calls the error rate and latency are computed on, MinCalls of them are
+	// needed before a destination is throttled or disabled.
+	Window   int
+	MinCalls int
+	// ThrottleErrorPercent and SlowLatency (ms) throttle a destination, DisableErrorPercent disables it.
+	ThrottleErrorPercent int
+	DisableErrorPercent  int
+	SlowLatency          int
+	ThrottledRate        int
+	// DisableDuration (s) doubles after each failed probe up to MaxDisableDuration.
+	DisableDuration    int
+	MaxDisableDuration int
+	// AlertUrl receives an Alert each time a destination is disabled or recovers.
+	AlertUrl string
+}
+
+// Alert is posted to alertUrl when a destination changes state.
+type Alert struct {
+	Destination  string `json:"destination"`
+	From         string `json:"from"`
+	To           string `json:"to"`
+	ErrorPercent int    `json:"errorPercent"`
+	AvgLatency   int64  `json:"avgLatency"`
+	Time         int64  `json:"time"`
+}
+
+type result struct {
+	failed  bool
+	latency time.Duration
+}
+
+type destination struct {
+	state   State
+	results []result
+	next    int
+	// second and admitted count the calls admitted in the current second while throttled.
+	second   int64
+	admitted int
+	// disabledUntil, disables and probing are set while disabled.
+	disabledUntil time.Time
+	disables      int
+	probing       bool
+}
+
+func (d *destination) reset() {
+	d.results = d.results[:0]
+	d.next = 0
+}
+
+func (d *destination) add(r result, window int) {
+	if len(d.results) < window {
+		d.results = append(d.results, r)
+		return
+	}
+	d.results[d.next] = r
+	d.next = (d.next + 1) % window
+}
+
+// stats returns the error percent and the average latency of the recorded calls.
+func (d *destination) stats() (int, time.Duration) {
+	if len(d.results) == 0 {
+		return 0, 0
+	}
+	var failed int
+	var latency time.Duration
+	for _, r := range d.results {
+		if r.failed {
+			failed++
+		}
+		latency += r.latency
+	}
+	return failed * 100 / len(d.results), latency / time.Duration(len(d.results))
+}
+
+type Guard struct {
+	conf         Config
+	now          func() time.Time
+	alert        func(ctx context.Context, alert *Alert)
+	lock         sync.Mutex
+	destinations map[string]*destination
+}
+
+func NewGuard(conf Config) *Guard {
+	g := &Guard{conf: conf, now: time.Now, destinations: make(map[string]*destination)}
+	g.alert = g.postAlert
+	return g
+}
+
+var (
+	defaultLock  sync.RWMutex
+	defaultGuard *Guard
+)
+
+// Init sets up the guard used by Do from destinationThrottle, calls are not guarded unless it is enabled.
+func Init(config *config.GlobalConfig) {
+	conf := config.DestinationThrottle
+	var g *Guard
+	if conf.Enable {
+		g = NewGuard(Config{
+			Window:               conf.Window,
+			MinCalls:             conf.MinCalls,
+			ThrottleErrorPercent: conf.ThrottleErrorPercent,
+			DisableErrorPercent:  conf.DisableErrorPercent,
+			SlowLatency:          conf.SlowLatency,
+			ThrottledRate:        conf.ThrottledRate,
+			DisableDuration:      conf.DisableDuration,
+			MaxDisableDuration:   conf.MaxDisableDuration,
+			AlertUrl:             conf.AlertUrl,
+		})
+	}
+	defaultLock.Lock()
+	defaultGuard = g
+	defaultLock.Unlock()
+}
+
+// Do calls fn through the guard set up by Init, see Guard.Do.
+func Do(ctx context.Context, dest string, fn func() error) error {
+	defaultLock.RLock()
+	g := defaultGuard
+	defaultLock.RUnlock()
+	if g == nil {
+		return fn()
+	}
+	return g.Do(ctx, dest, fn)
+}
+
+// Do calls fn unless dest is throttled or disabled, and records whether it failed and how long it took.
+func (g *Guard) Do(ctx context.Context, dest string, fn func() error) error {
+	probe, err := g.admit(dest)
+	if err != nil {
+		prommetrics.DestinationCallCounter.WithLabelValues(dest, "rejected").Inc()
+		return err
+	}
+	start := g.now()
+	err = fn()
+	g.record(ctx, dest, probe, result{failed: err != nil, latency: g.now().Sub(start)})
+	if err != nil {
+		prommetrics.DestinationCallCounter.WithLabelValues(dest, "failed").Inc()
+	} else {
+		prommetrics.DestinationCallCounter.WithLabelValues(dest, "success").Inc()
+	}
+	return err
+}
+
+// State returns the current state of dest.
+func (g *Guard) State(dest string) State {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if d, ok := g.destinations[dest]; ok {
+		return d.state
+	}
+	return StateHealthy
+}
+
+// admit reports whether a call to dest may be made, probe is true for the single call made to a disabled
+// destination once its disable duration passed.
+func (g *Guard) admit(dest string) (probe bool, err error) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	d, ok := g.destinations[dest]
+	if !ok {
+		return false, nil
+	}
+	now := g.now()
+	switch d.state {
+	case StateThrottled:
+		if second := now.Unix(); second != d.second {
+			d.second, d.admitted = second, 0
+		}
+		if d.admitted >= g.conf.ThrottledRate {
+			return false, servererrs.ErrThrottled.Wrap(dest + " is throttled")
+		}
+		d.admitted++
+	case StateDisabled:
+		if d.probing || now.Before(d.disabledUntil) {
+			return false, servererrs.ErrThrottled.Wrap(dest + " is disabled")
+		}
+		d.probing = true
+		return true, nil
+	}
+	return false, nil
+}
+
+func (g *Guard) record(ctx context.Context, dest string, probe bool, r result) {
+	g.lock.Lock()
+	d, ok := g.destinations[dest]
+	if !ok {
+		d = &destination{}
+		g.destinations[dest] = d
+	}
+	from := d.state
+	if probe {
+		d.probing = false
+		if r.failed {
+			d.disables++
+			d.disabledUntil = g.now().Add(g.disableDuration(d.disables))
+		} else {
+			d.state = StateHealthy
+			d.disables = 0
+			d.reset()
+		}
+	} else if d.state != StateDisabled {
+		d.add(r, g.conf.Window)
+		if len(d.results) >= g.conf.MinCalls {
+			errorPercent, latency := d.stats()
+			switch {
+			case errorPercent >= g.conf.DisableErrorPercent:
+				d.state = StateDisabled
+				d.disables = 1
+				d.disabledUntil = g.now().Add(g.disableDuration(d.disables))
+				d.reset()
+			case errorPercent >= g.conf.ThrottleErrorPercent || (g.conf.SlowLatency > 0 && latency >= time.Duration(g.conf.SlowLatency)*time.Millisecond):
+				d.state = StateThrottled
+			default:
+				d.state = StateHealthy
+			}
+		}
+	}
+	to := d.state
+	errorPercent, latency := d.stats()
+	g.lock.Unlock()
+	if from == to {
+		return
+	}
+	prommetrics.DestinationStateGauge.WithLabelValues(dest).Set(float64(to))
+	log.ZWarn(ctx, "destination state changed", nil, "destination", dest, "from", from.String(), "to", to.String(),
+		"errorPercent", errorPercent, "avgLatency", latency)
+	if to == StateDisabled || from == StateDisabled {
+		g.alert(ctx, &Alert{
+			Destination:  dest,
+			From:         from.String(),
+			To:           to.String(),
+			ErrorPercent: errorPercent,
+			AvgLatency:   latency.Milliseconds(),
+			Time:         g.now().UnixMilli(),
+		})
+	}
+}
+
+// disableDuration doubles DisableDuration after each of the disables, up to MaxDisableDuration.
+func (g *Guard) disableDuration(disables int) time.Duration {
+	base := time.Duration(g.conf.DisableDuration) * time.Second
+	max := time.Duration(g.conf.MaxDisableDuration) * time.Second
+	if base <= 0 {
+		base = time.Second
+	}
+	if max < base {
+		max = base
+	}
+	if disables > 30 {
+		return max
+	}
+	duration := base << (disables - 1)
+	if duration <= 0 || duration > max {
+		return max
+	}
+	return duration
+}
+
+// postAlert posts alert to alertUrl in the background, it is only logged when no url is set.
+func (g *Guard) postAlert(ctx context.Context, alert *Alert) {
+	if alert.To == StateDisabled.String() {
+		log.ZError(ctx, "destination disabled", nil, "destination", alert.Destination, "errorPercent", alert.ErrorPercent,
+			"avgLatency", alert.AvgLatency)
+	}
+	if g.conf.AlertUrl == "" {
+		return
+	}
+	body, err := json.Marshal(alert)
+	if err != nil {
+		log.ZError(ctx, "marshal destination alert failed", err)
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(mcontext.NewCtx("destinationAlert"), 5*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.conf.AlertUrl, bytes.NewReader(body))
+		if err != nil {
+			log.ZError(ctx, "destination alert request failed", err, "destination", alert.Destination)
+			return
+		}
+		req.Header.Set("content-type", "application/json; charset=utf-8")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			log.ZError(ctx, "post destination alert failed", err, "destination", alert.Destination)
+			return
+		}
+		resp.Body.Close()
+	}()
+}
@@ -0,0 +1,144 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package throttle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mw/specialerror"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+)
+
+var errDown = errors.New("down")
+
+func newTestGuard(now *time.Time, alerts *[]*Alert) *Guard {
+	g := NewGuard(Config{
+		Window:               10,
+		MinCalls:             4,
+		ThrottleErrorPercent: 20,
+		DisableErrorPercent:  50,
+		SlowLatency:          1000,
+		ThrottledRate:        2,
+		DisableDuration:      10,
+		MaxDisableDuration:   40,
+	})
+	g.now = func() time.Time { return *now }
+	g.alert = func(_ context.Context, alert *Alert) { *alerts = append(*alerts, alert) }
+	return g
+}
+
+func call(g *Guard, err error) error {
+	return g.Do(context.Background(), "dest", func() error { return err })
+}
+
+func isThrottled(err error) bool {
+	return servererrs.ErrThrottled.Is(specialerror.ErrCode(errs.Unwrap(err)))
+}
+
+func TestGuardDisableAndProbe(t *testing.T) {
+	now := time.Unix(1000, 0)
+	var alerts []*Alert
+	g := newTestGuard(&now, &alerts)
+	for i := 0; i < 4; i++ {
+		if err := call(g, errDown); err != errDown {
+			t.Fatalf("call %d: got %v, want the error of the destination", i, err)
+		}
+	}
+	if state := g.State("dest"); state != StateDisabled {
+		t.Fatalf("got %s, want disabled", state)
+	}
+	if len(alerts) != 1 || alerts[0].To != StateDisabled.String() {
+		t.Fatalf("got alerts %v, want one disabled alert", alerts)
+	}
+	if err := call(g, nil); !isThrottled(err) {
+		t.Fatalf("got %v, want ErrThrottled while disabled", err)
+	}
+
+	// A failed probe doubles the disable duration.
+	now = now.Add(10 * time.Second)
+	if err := call(g, errDown); err != errDown {
+		t.Fatalf("probe: got %v", err)
+	}
+	now = now.Add(10 * time.Second)
+	if err := call(g, nil); !isThrottled(err) {
+		t.Fatalf("got %v, want ErrThrottled before the doubled duration passed", err)
+	}
+	now = now.Add(10 * time.Second)
+	if err := call(g, nil); err != nil {
+		t.Fatalf("probe: got %v", err)
+	}
+	if state := g.State("dest"); state != StateHealthy {
+		t.Fatalf("got %s, want healthy after a successful probe", state)
+	}
+	if len(alerts) != 2 || alerts[1].To != StateHealthy.String() {
+		t.Fatalf("got alerts %v, want a recovered alert", alerts)
+	}
+}
+
+func TestGuardThrottle(t *testing.T) {
+	now := time.Unix(1000, 0)
+	var alerts []*Alert
+	g := newTestGuard(&now, &alerts)
+	for _, err := range []error{nil, nil, nil, nil, errDown, errDown} {
+		_ = call(g, err)
+	}
+	if state := g.State("dest"); state != StateThrottled {
+		t.Fatalf("got %s, want throttled at 33%% errors", state)
+	}
+	now = now.Add(time.Second)
+	var rejected int
+	for i := 0; i < 5; i++ {
+		if err := call(g, nil); isThrottled(err) {
+			rejected++
+		}
+	}
+	if rejected != 3 {
+		t.Fatalf("got %d rejected calls, want 3 over the rate of 2 per second", rejected)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("got alerts %v, throttling is not alerted", alerts)
+	}
+	// The failures leave the window once enough calls succeeded.
+	for i := 0; i < 10 && g.State("dest") != StateHealthy; i++ {
+		now = now.Add(time.Second)
+		_ = call(g, nil)
+		_ = call(g, nil)
+	}
+	if state := g.State("dest"); state != StateHealthy {
+		t.Fatalf("got %s, want healthy once the error rate dropped", state)
+	}
+}
+
+func TestGuardSlowLatency(t *testing.T) {
+	now := time.Unix(1000, 0)
+	var alerts []*Alert
+	g := newTestGuard(&now, &alerts)
+	for i := 0; i < 4; i++ {
+		_ = g.Do(context.Background(), "dest", func() error {
+			now = now.Add(2 * time.Second)
+			return nil
+		})
+	}
+	if state := g.State("dest"); state != StateThrottled {
+		t.Fatalf("got %s, want throttled when slow", state)
+	}
+	if state := g.State("other"); state != StateHealthy {
+		t.Fatalf("got %s, want other destinations unaffected", state)
+	}
+}
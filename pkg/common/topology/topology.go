@@ -0,0 +1,96 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package topology publishes the running instances of every component for the topology view of the admin api.
+package topology
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+)
+
+// Components that are not in the discovery registry, the rpc services report under their register name.
+const (
+	ComponentApi         = "openim-api"
+	ComponentMsgTransfer = "openim-msgtransfer"
+)
+
+// ReportInterval is how often an instance reports, it is dropped from the view after missing three reports.
+const ReportInterval = 10 * time.Second
+
+var startTime = time.Now()
+
+// Instance is what a running process reports about itself.
+type Instance struct {
+	Address    string `json:"address"`
+	Version    string `json:"version"`
+	Hostname   string `json:"hostname"`
+	PID        int    `json:"pid"`
+	StartTime  int64  `json:"startTime"`
+	UpdateTime int64  `json:"updateTime"`
+}
+
+// Report publishes this process as an instance of component every ReportInterval until it exits. address is the
+// address the instance is registered or reached at, a process without one is keyed by hostname and pid.
+func Report(globalConfig *config.GlobalConfig, component string, address string) error {
+	rdb, err := cache.NewRedis(globalConfig)
+	if err != nil {
+		return err
+	}
+	topologyCache := cache.NewTopologyCache(rdb)
+	hostname, _ := os.Hostname()
+	instance := &Instance{
+		Address:   address,
+		Version:   config.Version,
+		Hostname:  hostname,
+		PID:       os.Getpid(),
+		StartTime: startTime.UnixMilli(),
+	}
+	key := address
+	if key == "" {
+		key = hostname + ":" + strconv.Itoa(instance.PID)
+	}
+	ctx := mcontext.NewCtx(utils.GetSelfFuncName())
+	report := func(ctx context.Context) error {
+		instance.UpdateTime = time.Now().UnixMilli()
+		data, err := json.Marshal(instance)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+		return topologyCache.SetReport(ctx, component, key, string(data), ReportInterval*3)
+	}
+	if err := report(ctx); err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(ReportInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := report(ctx); err != nil {
+				log.ZWarn(ctx, "report topology instance failed", err, "component", component, "instance", key)
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import (
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+)
+
+// protocolModule is the module of the protocol shared by the server and the clients.
+const protocolModule = "github.com/OpenIMSDK/protocol"
+
+// registryKeyPrefix prefixes the discovery registry keys the build info of the instances is published under.
+const registryKeyPrefix = "openim-version_"
+
+// BuildInfo is what a service reports about its build: GET /version of the api and the Version gRPC service of
+// every rpc service return it, and every rpc instance publishes it in the discovery registry under RegistryKey.
+type BuildInfo struct {
+	Service    string `json:"service"`
+	Version    string `json:"version"`
+	GitVersion string `json:"gitVersion"`
+	GitCommit  string `json:"gitCommit"`
+	BuildDate  string `json:"buildDate"`
+	GoVersion  string `json:"goVersion"`
+	// ProtocolVersion is the version of the protocol module the service was built with.
+	ProtocolVersion string `json:"protocolVersion"`
+	// Features are the config sections that are switched on by their enable field.
+	Features []string `json:"features"`
+}
+
+func NewBuildInfo(service string, conf *config.GlobalConfig) *BuildInfo {
+	info := Get()
+	return &BuildInfo{
+		Service:         service,
+		Version:         strings.TrimSpace(config.Version),
+		GitVersion:      info.GitVersion,
+		GitCommit:       info.GitCommit,
+		BuildDate:       info.BuildDate,
+		GoVersion:       runtime.Version(),
+		ProtocolVersion: ProtocolVersion(),
+		Features:        EnabledFeatures(conf),
+	}
+}
+
+// ProtocolVersion returns the version of the protocol module, empty when the binary carries no module information.
+func ProtocolVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != protocolModule {
+			continue
+		}
+		if dep.Replace != nil {
+			return dep.Replace.Version
+		}
+		return dep.Version
+	}
+	return ""
+}
+
+// EnabledFeatures returns the yaml names of the top level sections of conf whose enable field is true, in the
+// order of the config.
+func EnabledFeatures(conf *config.GlobalConfig) []string {
+	features := make([]string, 0)
+	if conf == nil {
+		return features
+	}
+	v := reflect.ValueOf(conf).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() != reflect.Struct {
+			continue
+		}
+		enable := field.FieldByName("Enable")
+		if !enable.IsValid() || enable.Kind() != reflect.Bool || !enable.Bool() {
+			continue
+		}
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		features = append(features, name)
+	}
+	return features
+}
+
+// RegistryKey is the discovery registry key of the build info of the instance of service at address.
+func RegistryKey(service string, address string) string {
+	return registryKeyPrefix + service + "_" + address
+}
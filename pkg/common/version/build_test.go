@@ -0,0 +1,57 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestEnabledFeatures(t *testing.T) {
+	var conf config.GlobalConfig
+	assert.Empty(t, EnabledFeatures(&conf))
+	conf.Archive.Enable = true
+	conf.AntiSpam.Enable = true
+	features := EnabledFeatures(&conf)
+	assert.Contains(t, features, "archive")
+	assert.Contains(t, features, "antiSpam")
+	assert.Len(t, features, 2)
+}
+
+func TestGetBuildInfo(t *testing.T) {
+	l := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	info := &BuildInfo{Service: "openim-rpc-msg", Version: "v3.5.0", ProtocolVersion: "v0.0.55", Features: []string{"archive"}}
+	assert.NoError(t, Register(srv, info))
+	go srv.Serve(l)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial("passthrough:///version",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return l.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	defer conn.Close()
+	got, err := GetBuildInfo(context.Background(), conn)
+	assert.NoError(t, err)
+	assert.Equal(t, info, got)
+}
@@ -0,0 +1,97 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package version
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mcontext"
+	"github.com/OpenIMSDK/tools/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	ServiceName = "openim.version.Version"
+
+	getVersionMethod = "/" + ServiceName + "/GetVersion"
+)
+
+type versionServer interface {
+	getVersion() []byte
+}
+
+type server struct {
+	data []byte
+}
+
+func (s *server) getVersion() []byte {
+	return s.data
+}
+
+// Register serves info as the Version service of s.
+func Register(s *grpc.Server, info *BuildInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	s.RegisterService(&serviceDesc, &server{data: data})
+	return nil
+}
+
+// GetBuildInfo calls the Version service of the instance behind conn.
+func GetBuildInfo(ctx context.Context, conn *grpc.ClientConn) (*BuildInfo, error) {
+	operationID := mcontext.GetOperationID(ctx)
+	if operationID == "" {
+		operationID = utils.OperationIDGenerator()
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, constant.OperationID, operationID)
+	var resp wrapperspb.BytesValue
+	if err := conn.Invoke(ctx, getVersionMethod, &emptypb.Empty{}, &resp); err != nil {
+		return nil, errs.Wrap(err, "get version of "+conn.Target())
+	}
+	var info BuildInfo
+	if err := json.Unmarshal(resp.Value, &info); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &info, nil
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*versionServer)(nil),
+	Methods:     []grpc.MethodDesc{{MethodName: "GetVersion", Handler: getVersionHandler}},
+	Streams:     []grpc.StreamDesc{},
+	Metadata:    "version.proto",
+}
+
+func getVersionHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	handle := func(ctx context.Context, req any) (any, error) {
+		return wrapperspb.Bytes(srv.(versionServer).getVersion()), nil
+	}
+	if interceptor == nil {
+		return handle(ctx, in)
+	}
+	return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: getVersionMethod}, handle)
+}
@@ -0,0 +1,43 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package contentpolicy decides which content types a content policy restricts.
+package contentpolicy
+
+import (
+	"fmt"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+)
+
+// Applies reports whether policies restrict contentType. Notifications and typing states are never restricted,
+// a text-only channel still shows who is typing.
+func Applies(contentType int32) bool {
+	return contentType < constant.NotificationBegin && contentType != constant.Typing
+}
+
+// Check returns servererrs.ErrContentTypeNotAllowed when allowed is a policy that does not list contentType, a nil
+// allowed is no policy.
+func Check(allowed []int32, contentType int32) error {
+	if allowed == nil || !Applies(contentType) {
+		return nil
+	}
+	for _, t := range allowed {
+		if t == contentType {
+			return nil
+		}
+	}
+	return servererrs.ErrContentTypeNotAllowed.Wrap(fmt.Sprintf("content type %d is not allowed in this conversation", contentType))
+}
@@ -0,0 +1,43 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contentpolicy
+
+import (
+	"testing"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mw/specialerror"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+)
+
+func TestCheck(t *testing.T) {
+	textOnly := []int32{constant.Text, constant.AtText}
+	if err := Check(nil, constant.File); err != nil {
+		t.Errorf("no policy: %v", err)
+	}
+	if err := Check(textOnly, constant.Text); err != nil {
+		t.Errorf("allowed text: %v", err)
+	}
+	if err := Check(textOnly, constant.Typing); err != nil {
+		t.Errorf("typing: %v", err)
+	}
+	if err := Check(textOnly, constant.GroupCreatedNotification); err != nil {
+		t.Errorf("notification: %v", err)
+	}
+	if err := Check(textOnly, constant.File); !servererrs.ErrContentTypeNotAllowed.Is(specialerror.ErrCode(errs.Unwrap(err))) {
+		t.Errorf("file: %v", err)
+	}
+}
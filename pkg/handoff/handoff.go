@@ -0,0 +1,84 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package handoff signs the tokens of conversation handoff links, the deep links opening a conversation of a user
+// on another of their platforms.
+package handoff
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrTokenInvalid = errors.New("handoff token invalid")
+	ErrTokenExpired = errors.New("handoff token expired")
+)
+
+// Grant is what a handoff token opens, Seq and ClientMsgID are empty when it opens the conversation at its end.
+type Grant struct {
+	ID             string `json:"i"`
+	UserID         string `json:"u"`
+	ConversationID string `json:"c"`
+	Seq            int64  `json:"s,omitempty"`
+	ClientMsgID    string `json:"m,omitempty"`
+	Expire         int64  `json:"e"`
+}
+
+// NewID returns a random id identifying a grant for single use.
+func NewID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Token signs grant, the token is base64url(grant) "." base64url(HMAC-SHA256(secret, grant)).
+func Token(secret string, grant *Grant) string {
+	data, _ := json.Marshal(grant)
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + signature(secret, payload)
+}
+
+// Parse checks the signature and expiry of token and returns its grant.
+func Parse(secret string, token string, now time.Time) (*Grant, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(signature(secret, payload))) {
+		return nil, ErrTokenInvalid
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, ErrTokenInvalid
+	}
+	var grant Grant
+	if err := json.Unmarshal(data, &grant); err != nil || grant.ID == "" {
+		return nil, ErrTokenInvalid
+	}
+	if now.Unix() > grant.Expire {
+		return nil, ErrTokenExpired
+	}
+	return &grant, nil
+}
+
+func signature(secret string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
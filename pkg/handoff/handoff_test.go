@@ -0,0 +1,42 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	grant := &Grant{ID: NewID(), UserID: "user1", ConversationID: "si_user1_user2", Seq: 42, Expire: now.Add(time.Minute).Unix()}
+	token := Token("secret", grant)
+	got, err := Parse("secret", token, now)
+	if err != nil || *got != *grant {
+		t.Fatalf("Parse() = %+v, %v", got, err)
+	}
+	if _, err := Parse("other", token, now); err != ErrTokenInvalid {
+		t.Errorf("wrong secret error = %v", err)
+	}
+	if _, err := Parse("secret", token, now.Add(2*time.Minute)); err != ErrTokenExpired {
+		t.Errorf("expired error = %v", err)
+	}
+	if _, err := Parse("secret", "x"+token, now); err != ErrTokenInvalid {
+		t.Errorf("tampered error = %v", err)
+	}
+	if _, err := Parse("secret", Token("secret", &Grant{UserID: "user1", Expire: grant.Expire}), now); err != ErrTokenInvalid {
+		t.Errorf("missing id error = %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loginpolicy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// Matrix is the multi-login policy applied when enabled instead of multiLoginPolicy.
+// Platforms are split into groups, a user keeps at most MaxOnline sessions per group and the oldest are kicked first.
+// Platforms not listed in any group are never kicked.
+type Matrix struct {
+	Enable bool                      `json:"enable"`
+	Groups []config.LoginPolicyGroup `json:"groups"`
+}
+
+// FromConfig returns the matrix configured in loginPolicyMatrix.
+func FromConfig(conf *config.GlobalConfig) *Matrix {
+	return &Matrix{Enable: conf.LoginPolicyMatrix.Enable, Groups: conf.LoginPolicyMatrix.Groups}
+}
+
+// Load returns the matrix edited at runtime, falling back to the configured one when it was never edited.
+func Load(ctx context.Context, msgCache cache.MsgModel, conf *config.GlobalConfig) (*Matrix, error) {
+	val, err := msgCache.GetLoginPolicyMatrix(ctx)
+	if err != nil {
+		if errs.Unwrap(err) == redis.Nil {
+			return FromConfig(conf), nil
+		}
+		return nil, err
+	}
+	var matrix Matrix
+	if err := json.Unmarshal([]byte(val), &matrix); err != nil {
+		return nil, errs.Wrap(err, "login policy matrix unmarshal failed")
+	}
+	return &matrix, nil
+}
+
+// Save validates the matrix and stores it, the gateway and auth rpc pick it up on the next login.
+func Save(ctx context.Context, msgCache cache.MsgModel, matrix *Matrix) error {
+	if err := matrix.Check(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(matrix)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	return msgCache.SetLoginPolicyMatrix(ctx, string(data))
+}
+
+// Check reports an invalid platform ID, a negative limit or a platform listed in more than one group.
+func (m *Matrix) Check() error {
+	platforms := make(map[int]string)
+	for _, group := range m.Groups {
+		if group.MaxOnline < 0 {
+			return errs.ErrArgs.Wrap(fmt.Sprintf("group %s maxOnline must not be negative", group.Name))
+		}
+		for _, platformID := range group.PlatformIDs {
+			if constant.PlatformIDToName(platformID) == "" {
+				return errs.ErrArgs.Wrap(fmt.Sprintf("group %s has invalid platformID %d", group.Name, platformID))
+			}
+			if name, ok := platforms[platformID]; ok {
+				return errs.ErrArgs.Wrap(fmt.Sprintf("platformID %d is in both group %s and %s", platformID, name, group.Name))
+			}
+			platforms[platformID] = group.Name
+		}
+	}
+	return nil
+}
+
+// Group returns the group limiting platformID, nil if the matrix is disabled or the platform is unrestricted.
+func (m *Matrix) Group(platformID int) *config.LoginPolicyGroup {
+	if !m.Enable {
+		return nil
+	}
+	for i, group := range m.Groups {
+		if group.MaxOnline == 0 {
+			continue
+		}
+		for _, id := range group.PlatformIDs {
+			if id == platformID {
+				return &m.Groups[i]
+			}
+		}
+	}
+	return nil
+}
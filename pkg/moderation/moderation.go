@@ -0,0 +1,232 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package moderation filters the text of messages with keyword and regex rules and describes the messages sent to
+// an external reviewer.
+package moderation
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+)
+
+// Actions of a rule.
+const (
+	// ActionBlock rejects the message.
+	ActionBlock = "block"
+	// ActionReplace replaces each match with the replacement of the rule, *** when it is empty.
+	ActionReplace = "replace"
+	// ActionFlag lets the message through and records it for review.
+	ActionFlag = "flag"
+)
+
+const defaultReplacement = "***"
+
+// Rule matches Pattern case-insensitively, as a regular expression when Regex is set.
+type Rule struct {
+	RuleID      string
+	Pattern     string
+	Regex       bool
+	Action      string
+	Replacement string
+}
+
+type compiledRule struct {
+	*Rule
+	re *regexp.Regexp
+}
+
+// Filter applies a set of rules, it is safe for concurrent use.
+type Filter struct {
+	rules []*compiledRule
+}
+
+func compile(rule *Rule) (*regexp.Regexp, error) {
+	switch rule.Action {
+	case ActionBlock, ActionReplace, ActionFlag:
+	default:
+		return nil, errs.ErrArgs.Wrap("unknown moderation action " + rule.Action)
+	}
+	if rule.Pattern == "" {
+		return nil, errs.ErrArgs.Wrap("the pattern is empty")
+	}
+	pattern := rule.Pattern
+	if !rule.Regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, errs.ErrArgs.Wrap("invalid pattern " + err.Error())
+	}
+	return re, nil
+}
+
+// Validate returns the error NewFilter would return for rule.
+func Validate(rule *Rule) error {
+	_, err := compile(rule)
+	return err
+}
+
+// NewFilter compiles rules, block rules are applied before replace rules.
+func NewFilter(rules []*Rule) (*Filter, error) {
+	f := &Filter{rules: make([]*compiledRule, 0, len(rules))}
+	for _, action := range []string{ActionBlock, ActionReplace, ActionFlag} {
+		for _, rule := range rules {
+			if rule.Action != action {
+				continue
+			}
+			re, err := compile(rule)
+			if err != nil {
+				return nil, err
+			}
+			f.rules = append(f.rules, &compiledRule{Rule: rule, re: re})
+		}
+	}
+	return f, nil
+}
+
+// Result is what the rules decided for a text.
+type Result struct {
+	// BlockedBy is the rule that blocks the text, empty when it is not blocked.
+	BlockedBy string
+	// Text is the text after the replace rules, Replaced reports whether one of them matched.
+	Text     string
+	Replaced bool
+	// Flags are the flag rules that matched.
+	Flags []string
+}
+
+// Apply runs the rules over text.
+func (f *Filter) Apply(text string) *Result {
+	res := &Result{Text: text}
+	if f == nil || text == "" {
+		return res
+	}
+	for _, rule := range f.rules {
+		switch rule.Action {
+		case ActionBlock:
+			if rule.re.MatchString(res.Text) {
+				res.BlockedBy = rule.RuleID
+				return res
+			}
+		case ActionReplace:
+			if !rule.re.MatchString(res.Text) {
+				continue
+			}
+			replacement := rule.Replacement
+			if replacement == "" {
+				replacement = defaultReplacement
+			}
+			res.Text = rule.re.ReplaceAllLiteralString(res.Text, replacement)
+			res.Replaced = true
+		case ActionFlag:
+			if rule.re.MatchString(res.Text) {
+				res.Flags = append(res.Flags, rule.RuleID)
+			}
+		}
+	}
+	return res
+}
+
+// textKey is the key of the text in the content of contentType, empty for content types that are not filtered.
+func textKey(contentType int32) string {
+	switch contentType {
+	case constant.Text:
+		return "content"
+	case constant.AtText, constant.Quote:
+		return "text"
+	default:
+		return ""
+	}
+}
+
+// Text returns the filtered text of msg, false for content types that are not filtered.
+func Text(msg *sdkws.MsgData) (string, bool) {
+	key := textKey(msg.ContentType)
+	if key == "" {
+		return "", false
+	}
+	var content map[string]any
+	if err := json.Unmarshal(msg.Content, &content); err != nil {
+		return "", false
+	}
+	text, _ := content[key].(string)
+	return text, true
+}
+
+// SetText replaces the text of msg, the other fields of its content are kept.
+func SetText(msg *sdkws.MsgData, text string) error {
+	key := textKey(msg.ContentType)
+	if key == "" {
+		return errs.ErrArgs.Wrap("the content type has no text")
+	}
+	var content map[string]json.RawMessage
+	if err := json.Unmarshal(msg.Content, &content); err != nil {
+		return errs.Wrap(err)
+	}
+	value, err := json.Marshal(text)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	content[key] = value
+	data, err := json.Marshal(content)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	msg.Content = data
+	return nil
+}
+
+// ImageURL returns the url of the picture of a picture message.
+func ImageURL(msg *sdkws.MsgData) string {
+	if msg.ContentType != constant.Picture {
+		return ""
+	}
+	var elem struct {
+		SourcePicture struct {
+			Url string `json:"url"`
+		} `json:"sourcePicture"`
+	}
+	if err := json.Unmarshal(msg.Content, &elem); err != nil {
+		return ""
+	}
+	return elem.SourcePicture.Url
+}
+
+// Verdicts of a reviewer.
+const (
+	VerdictPass = "pass"
+	VerdictFlag = "flag"
+)
+
+// ReviewReq is posted to the external reviewer after the message is sent, Text or ImageURL is set.
+type ReviewReq struct {
+	ServerMsgID    string `json:"serverMsgID"`
+	ClientMsgID    string `json:"clientMsgID"`
+	ConversationID string `json:"conversationID"`
+	SendID         string `json:"sendID"`
+	ContentType    int32  `json:"contentType"`
+	Text           string `json:"text,omitempty"`
+	ImageURL       string `json:"imageURL,omitempty"`
+}
+
+// ReviewResp is the answer of the reviewer, a flagged message is recorded with Reason for the admins.
+type ReviewResp struct {
+	Verdict string `json:"verdict"`
+	Reason  string `json:"reason"`
+}
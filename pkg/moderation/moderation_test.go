@@ -0,0 +1,59 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moderation
+
+import (
+	"testing"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	f, err := NewFilter([]*Rule{
+		{RuleID: "flag", Pattern: "crypto", Action: ActionFlag},
+		{RuleID: "replace", Pattern: "darn", Action: ActionReplace},
+		{RuleID: "phone", Pattern: `\d{3}-\d{4}`, Regex: true, Action: ActionReplace, Replacement: "[phone]"},
+		{RuleID: "block", Pattern: "a.b", Action: ActionBlock},
+	})
+	assert.NoError(t, err)
+
+	res := f.Apply("Darn, call 555-1234 about CRYPTO")
+	assert.Empty(t, res.BlockedBy)
+	assert.True(t, res.Replaced)
+	assert.Equal(t, "***, call [phone] about CRYPTO", res.Text)
+	assert.Equal(t, []string{"flag"}, res.Flags)
+
+	// Keywords are matched literally.
+	assert.Empty(t, f.Apply("axb").BlockedBy)
+	assert.Equal(t, "block", f.Apply("darn A.B").BlockedBy)
+
+	_, err = NewFilter([]*Rule{{RuleID: "bad", Pattern: "(", Regex: true, Action: ActionBlock}})
+	assert.Error(t, err)
+	assert.Error(t, Validate(&Rule{Pattern: "x", Action: "drop"}))
+}
+
+func TestSetText(t *testing.T) {
+	msg := &sdkws.MsgData{ContentType: constant.Quote, Content: []byte(`{"text":"darn","quoteMessage":{"clientMsgID":"c1"}}`)}
+	text, ok := Text(msg)
+	assert.True(t, ok)
+	assert.Equal(t, "darn", text)
+	assert.NoError(t, SetText(msg, "***"))
+	assert.JSONEq(t, `{"text":"***","quoteMessage":{"clientMsgID":"c1"}}`, string(msg.Content))
+
+	_, ok = Text(&sdkws.MsgData{ContentType: constant.Picture, Content: []byte(`{}`)})
+	assert.False(t, ok)
+}
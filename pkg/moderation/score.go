@@ -0,0 +1,146 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moderation
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// Categories a scorer rates messages in.
+const (
+	CategoryProfanity = "profanity"
+	CategoryNudity    = "nudity"
+)
+
+// Sensitivity levels of a conversation, from off to strict. A stricter level flags lower scores.
+const (
+	SensitivityOff    = "off"
+	SensitivityLow    = "low"
+	SensitivityMedium = "medium"
+	SensitivityStrict = "strict"
+)
+
+var sensitivityRank = map[string]int{
+	SensitivityOff:    0,
+	SensitivityLow:    1,
+	SensitivityMedium: 2,
+	SensitivityStrict: 3,
+}
+
+// ValidSensitivity reports whether level is one of the sensitivity levels.
+func ValidSensitivity(level string) bool {
+	_, ok := sensitivityRank[level]
+	return ok
+}
+
+// SensitivityInBounds reports whether level lies between min and max, a bound that is not a level is open.
+func SensitivityInBounds(level string, min string, max string) bool {
+	rank := sensitivityRank[level]
+	if r, ok := sensitivityRank[min]; ok && rank < r {
+		return false
+	}
+	if r, ok := sensitivityRank[max]; ok && rank > r {
+		return false
+	}
+	return true
+}
+
+// Thresholds are the lowest scores flagged at each level, nothing is flagged at SensitivityOff.
+type Thresholds struct {
+	Low    float64
+	Medium float64
+	Strict float64
+}
+
+// Of returns the threshold of level, false when messages are not scored at that level.
+func (t Thresholds) Of(level string) (float64, bool) {
+	switch level {
+	case SensitivityLow:
+		return t.Low, true
+	case SensitivityMedium:
+		return t.Medium, true
+	case SensitivityStrict:
+		return t.Strict, true
+	default:
+		return 0, false
+	}
+}
+
+// Scores rates a message in each category from 0 to 1.
+type Scores map[string]float64
+
+// Merge keeps the highest score of each category.
+func (s Scores) Merge(other Scores) {
+	for category, score := range other {
+		if score > s[category] {
+			s[category] = score
+		}
+	}
+}
+
+// Exceeded returns the categories scored at or above threshold, sorted.
+func (s Scores) Exceeded(threshold float64) []string {
+	var categories []string
+	for category, score := range s {
+		if score >= threshold {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// Scorer rates sent messages, the text or picture of req is set. Scorers are called in the background after the
+// message is sent and their scores are merged.
+type Scorer interface {
+	Score(ctx context.Context, req *ReviewReq) (Scores, error)
+}
+
+// WordScorer rates the profanity of a text by the number of listed words it contains, one word scores 0.5 and
+// each further word halves the distance to 1.
+type WordScorer struct {
+	words []string
+}
+
+func NewWordScorer(words []string) *WordScorer {
+	s := &WordScorer{words: make([]string, 0, len(words))}
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			s.words = append(s.words, word)
+		}
+	}
+	return s
+}
+
+func (s *WordScorer) Score(_ context.Context, req *ReviewReq) (Scores, error) {
+	if req.Text == "" {
+		return nil, nil
+	}
+	text := strings.ToLower(req.Text)
+	score := 0.0
+	for _, word := range s.words {
+		if strings.Contains(text, word) {
+			score += (1 - score) / 2
+		}
+	}
+	return Scores{CategoryProfanity: score}, nil
+}
+
+// ScoreResp is the answer of an external scorer, e.g. an image classifier rating nudity.
+type ScoreResp struct {
+	Scores Scores `json:"scores"`
+}
@@ -0,0 +1,58 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package moderation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSensitivityInBounds(t *testing.T) {
+	assert.True(t, SensitivityInBounds(SensitivityMedium, SensitivityLow, SensitivityStrict))
+	assert.False(t, SensitivityInBounds(SensitivityOff, SensitivityLow, SensitivityStrict))
+	assert.False(t, SensitivityInBounds(SensitivityStrict, "", SensitivityMedium))
+	assert.True(t, SensitivityInBounds(SensitivityOff, "", ""))
+}
+
+func TestThresholds(t *testing.T) {
+	thresholds := Thresholds{Low: 0.9, Medium: 0.7, Strict: 0.5}
+	threshold, ok := thresholds.Of(SensitivityStrict)
+	assert.True(t, ok)
+	assert.Equal(t, 0.5, threshold)
+	_, ok = thresholds.Of(SensitivityOff)
+	assert.False(t, ok)
+}
+
+func TestScores(t *testing.T) {
+	scores := Scores{CategoryProfanity: 0.2}
+	scores.Merge(Scores{CategoryProfanity: 0.6, CategoryNudity: 0.9})
+	scores.Merge(Scores{CategoryProfanity: 0.1})
+	assert.Equal(t, Scores{CategoryProfanity: 0.6, CategoryNudity: 0.9}, scores)
+	assert.Equal(t, []string{CategoryNudity, CategoryProfanity}, scores.Exceeded(0.5))
+	assert.Empty(t, scores.Exceeded(0.95))
+}
+
+func TestWordScorer(t *testing.T) {
+	s := NewWordScorer([]string{" Darn ", "heck", ""})
+	scores, err := s.Score(context.Background(), &ReviewReq{Text: "DARN it"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, scores[CategoryProfanity])
+	scores, _ = s.Score(context.Background(), &ReviewReq{Text: "darn, what the heck"})
+	assert.Equal(t, 0.75, scores[CategoryProfanity])
+	scores, _ = s.Score(context.Background(), &ReviewReq{ImageURL: "http://example.com/a.png"})
+	assert.Nil(t, scores)
+}
@@ -0,0 +1,179 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package msgexport writes the history of a conversation in the formats offered for compliance and data
+// portability exports, JSON, CSV and a self-contained HTML page.
+package msgexport
+
+import (
+	"crypto/md5"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatHTML = "html"
+
+	// partSeparator joins the part hashes into the hash of an upload, it matches the object storage controller.
+	partSeparator = ","
+)
+
+type Export struct {
+	ConversationID string `json:"conversationID"`
+	StartTime      int64  `json:"startTime"`
+	EndTime        int64  `json:"endTime"`
+	ExportTime     int64  `json:"exportTime"`
+	// Truncated is set when the time range held more messages than were exported.
+	Truncated bool   `json:"truncated"`
+	Msgs      []*Msg `json:"msgs"`
+}
+
+type Msg struct {
+	Seq            int64  `json:"seq"`
+	ClientMsgID    string `json:"clientMsgID"`
+	ServerMsgID    string `json:"serverMsgID"`
+	SendID         string `json:"sendID"`
+	SenderNickname string `json:"senderNickname"`
+	SessionType    int32  `json:"sessionType"`
+	ContentType    int32  `json:"contentType"`
+	Content        string `json:"content"`
+	SendTime       int64  `json:"sendTime"`
+	Revoked        bool   `json:"revoked"`
+}
+
+// ValidFormat reports whether format is one of the supported formats.
+func ValidFormat(format string) bool {
+	switch format {
+	case FormatJSON, FormatCSV, FormatHTML:
+		return true
+	default:
+		return false
+	}
+}
+
+// ContentType returns the media type of format.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
+
+// Encode writes export to w in format.
+func Encode(w io.Writer, format string, export *Export) error {
+	switch format {
+	case FormatJSON:
+		return json.NewEncoder(w).Encode(export)
+	case FormatCSV:
+		return encodeCSV(w, export)
+	case FormatHTML:
+		return pageTemplate.Execute(w, export)
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+var csvHeader = []string{"seq", "clientMsgID", "serverMsgID", "sendID", "senderNickname", "sessionType", "contentType", "sendTime", "sendTimeUTC", "revoked", "content"}
+
+func encodeCSV(w io.Writer, export *Export) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, msg := range export.Msgs {
+		record := []string{
+			strconv.FormatInt(msg.Seq, 10),
+			msg.ClientMsgID,
+			msg.ServerMsgID,
+			msg.SendID,
+			msg.SenderNickname,
+			strconv.Itoa(int(msg.SessionType)),
+			strconv.Itoa(int(msg.ContentType)),
+			strconv.FormatInt(msg.SendTime, 10),
+			formatTime(msg.SendTime),
+			strconv.FormatBool(msg.Revoked),
+			msg.Content,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatTime(ms int64) string {
+	return time.UnixMilli(ms).UTC().Format(time.RFC3339)
+}
+
+var pageTemplate = template.Must(template.New("export").Funcs(template.FuncMap{"time": formatTime}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.ConversationID}}</title>
+<style>
+body{font-family:sans-serif;margin:2em}
+table{border-collapse:collapse;width:100%}
+th,td{border:1px solid #ccc;padding:4px 8px;text-align:left;vertical-align:top}
+td.content{white-space:pre-wrap;word-break:break-all}
+tr.revoked td{color:#999;text-decoration:line-through}
+</style>
+</head>
+<body>
+<h1>{{.ConversationID}}</h1>
+<p>{{time .StartTime}} - {{time .EndTime}}, exported {{time .ExportTime}}{{if .Truncated}}, truncated{{end}}</p>
+<table>
+<tr><th>Seq</th><th>Time (UTC)</th><th>Sender</th><th>Type</th><th>Content</th></tr>
+{{range .Msgs}}<tr{{if .Revoked}} class="revoked"{{end}}><td>{{.Seq}}</td><td>{{time .SendTime}}</td><td>{{.SenderNickname}} ({{.SendID}})</td><td>{{.ContentType}}</td><td class="content">{{.Content}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// SplitParts cuts data into parts of partSize bytes, it returns the parts, the md5 of each part and the hash
+// the object storage expects for the whole upload.
+func SplitParts(data []byte, partSize int64) ([][]byte, []string, string) {
+	if partSize <= 0 {
+		partSize = int64(len(data))
+	}
+	var parts [][]byte
+	for len(data) > 0 || len(parts) == 0 {
+		n := partSize
+		if n > int64(len(data)) {
+			n = int64(len(data))
+		}
+		parts = append(parts, data[:n])
+		data = data[n:]
+	}
+	partHashes := make([]string, len(parts))
+	for i, part := range parts {
+		sum := md5.Sum(part)
+		partHashes[i] = hex.EncodeToString(sum[:])
+	}
+	sum := md5.Sum([]byte(strings.Join(partHashes, partSeparator)))
+	return parts, partHashes, hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,89 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgexport
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/csv"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func testExport() *Export {
+	return &Export{
+		ConversationID: "si_u1_u2",
+		StartTime:      1700000000000,
+		EndTime:        1700000100000,
+		Msgs: []*Msg{
+			{Seq: 1, SendID: "u1", SenderNickname: "Alice", ContentType: 101, Content: `{"content":"hi, <b>there</b>"}`, SendTime: 1700000000001},
+			{Seq: 2, SendID: "u2", ContentType: 101, Content: "line1\nline2", SendTime: 1700000000002, Revoked: true},
+		},
+	}
+}
+
+func TestEncodeCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, FormatCSV, testExport()); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("records = %d, want 3", len(records))
+	}
+	last := len(csvHeader) - 1
+	if records[1][last] != `{"content":"hi, <b>there</b>"}` || records[2][last] != "line1\nline2" || records[2][9] != "true" {
+		t.Fatalf("records = %q", records)
+	}
+}
+
+func TestEncodeHTMLEscapes(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, FormatHTML, testExport()); err != nil {
+		t.Fatal(err)
+	}
+	page := buf.String()
+	if strings.Contains(page, "<b>there</b>") {
+		t.Fatal("content is not escaped")
+	}
+	if !strings.Contains(page, `class="revoked"`) {
+		t.Fatal("revoked message is not marked")
+	}
+}
+
+func TestEncodeUnknownFormat(t *testing.T) {
+	if err := Encode(&bytes.Buffer{}, "xml", testExport()); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestSplitParts(t *testing.T) {
+	data := []byte("0123456789")
+	parts, partHashes, hash := SplitParts(data, 4)
+	if len(parts) != 3 || string(parts[2]) != "89" {
+		t.Fatalf("parts = %q", parts)
+	}
+	sum := md5.Sum([]byte(strings.Join(partHashes, ",")))
+	if hash != hex.EncodeToString(sum[:]) {
+		t.Fatalf("hash = %s", hash)
+	}
+	if parts, _, _ := SplitParts(nil, 4); len(parts) != 1 || len(parts[0]) != 0 {
+		t.Fatalf("empty data parts = %q", parts)
+	}
+}
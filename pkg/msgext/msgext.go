@@ -0,0 +1,97 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package msgext is the part of the msg rpc the protocol module does not define, see rpcext.
+package msgext
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+	"google.golang.org/grpc"
+)
+
+const ServiceName = "openim.msg.MsgExt"
+
+// Server is implemented by the msg rpc.
+type Server interface {
+	SetMsgReaction(ctx context.Context, req *apistruct.SetMsgReactionReq) (*apistruct.SetMsgReactionResp, error)
+	RemoveMsgReaction(ctx context.Context, req *apistruct.SetMsgReactionReq) (*apistruct.SetMsgReactionResp, error)
+	GetMsgReactions(ctx context.Context, req *apistruct.GetMsgReactionsReq) (*apistruct.GetMsgReactionsResp, error)
+	EditMsg(ctx context.Context, req *apistruct.EditMsgReq) (*apistruct.EditMsgResp, error)
+	GetMsgEditHistory(ctx context.Context, req *apistruct.GetMsgEditHistoryReq) (*apistruct.GetMsgEditHistoryResp, error)
+	DeleteMsgsPhysical(ctx context.Context, req *apistruct.DeleteMsgsPhysicalReq) (*apistruct.DeleteMsgsPhysicalResp, error)
+}
+
+// Register serves srv as the MsgExt service of s.
+func Register(s *grpc.Server, srv Server) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+// Client calls the MsgExt service, its methods can be passed to a2r.Call.
+type Client struct {
+	conn grpc.ClientConnInterface
+}
+
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{conn: conn}
+}
+
+func (c *Client) SetMsgReaction(ctx context.Context, req *apistruct.SetMsgReactionReq, opts ...grpc.CallOption) (*apistruct.SetMsgReactionResp, error) {
+	return invoke[apistruct.SetMsgReactionReq, apistruct.SetMsgReactionResp](ctx, c.conn, "SetMsgReaction", req, opts...)
+}
+
+func (c *Client) RemoveMsgReaction(ctx context.Context, req *apistruct.SetMsgReactionReq, opts ...grpc.CallOption) (*apistruct.SetMsgReactionResp, error) {
+	return invoke[apistruct.SetMsgReactionReq, apistruct.SetMsgReactionResp](ctx, c.conn, "RemoveMsgReaction", req, opts...)
+}
+
+func (c *Client) GetMsgReactions(ctx context.Context, req *apistruct.GetMsgReactionsReq, opts ...grpc.CallOption) (*apistruct.GetMsgReactionsResp, error) {
+	return invoke[apistruct.GetMsgReactionsReq, apistruct.GetMsgReactionsResp](ctx, c.conn, "GetMsgReactions", req, opts...)
+}
+
+func (c *Client) EditMsg(ctx context.Context, req *apistruct.EditMsgReq, opts ...grpc.CallOption) (*apistruct.EditMsgResp, error) {
+	return invoke[apistruct.EditMsgReq, apistruct.EditMsgResp](ctx, c.conn, "EditMsg", req, opts...)
+}
+
+func (c *Client) GetMsgEditHistory(ctx context.Context, req *apistruct.GetMsgEditHistoryReq, opts ...grpc.CallOption) (*apistruct.GetMsgEditHistoryResp, error) {
+	return invoke[apistruct.GetMsgEditHistoryReq, apistruct.GetMsgEditHistoryResp](ctx, c.conn, "GetMsgEditHistory", req, opts...)
+}
+
+func (c *Client) DeleteMsgsPhysical(ctx context.Context, req *apistruct.DeleteMsgsPhysicalReq, opts ...grpc.CallOption) (*apistruct.DeleteMsgsPhysicalResp, error) {
+	return invoke[apistruct.DeleteMsgsPhysicalReq, apistruct.DeleteMsgsPhysicalResp](ctx, c.conn, "DeleteMsgsPhysical", req, opts...)
+}
+
+func invoke[A, B any](ctx context.Context, conn grpc.ClientConnInterface, name string, req *A, opts ...grpc.CallOption) (*B, error) {
+	return rpcext.Invoke[A, B](ctx, conn, ServiceName, name, req, opts...)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*Server)(nil),
+	Methods: []grpc.MethodDesc{
+		method("SetMsgReaction", Server.SetMsgReaction),
+		method("RemoveMsgReaction", Server.RemoveMsgReaction),
+		method("GetMsgReactions", Server.GetMsgReactions),
+		method("EditMsg", Server.EditMsg),
+		method("GetMsgEditHistory", Server.GetMsgEditHistory),
+		method("DeleteMsgsPhysical", Server.DeleteMsgsPhysical),
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "msgext.proto",
+}
+
+func method[A, B any](name string, call func(srv Server, ctx context.Context, req *A) (*B, error)) grpc.MethodDesc {
+	return rpcext.Method(ServiceName, name, call)
+}
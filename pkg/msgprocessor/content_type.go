@@ -0,0 +1,38 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgprocessor
+
+// MsgEditNotification tells the conversation members that the content of a message was edited.
+// The protocol does not define a content type for it, so it is kept here inside the notification range.
+const MsgEditNotification = 2103
+
+// MsgGapSummaryNotification replaces the oldest seqs of a conversation that were not synced because the offline
+// backlog exceeded offlineSync.maxSeqs, its content is a GapSummaryElem.
+const MsgGapSummaryNotification = 2104
+
+// Lifecycle notifications of a scheduled meeting room, their content is a MeetingRoomElem.
+const (
+	MeetingStartingSoonNotification = 2105
+	MeetingStartedNotification      = 2106
+	MeetingEndedNotification        = 2107
+)
+
+// FriendLabelChangedNotification tells the other devices of a user that one of their friend labels changed,
+// its content is a FriendLabelChangedTips.
+const FriendLabelChangedNotification = 2108
+
+// NotificationProfileChangedNotification tells the other devices of a user that one of their notification sound
+// profiles changed, its content is a NotificationProfileChangedTips.
+const NotificationProfileChangedNotification = 2109
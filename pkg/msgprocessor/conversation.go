@@ -144,6 +144,11 @@ func IsNotification(conversationID string) bool {
 	return strings.HasPrefix(conversationID, "n_")
 }
 
+// IsGroupConversationID reports whether the conversation is a group chat.
+func IsGroupConversationID(conversationID string) bool {
+	return strings.HasPrefix(conversationID, "sg_") || strings.HasPrefix(conversationID, "g_")
+}
+
 func IsNotificationByMsg(msg *sdkws.MsgData) bool {
 	return !Options(msg.Options).IsNotNotification()
 }
@@ -0,0 +1,41 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgprocessor
+
+import (
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+)
+
+// Ephemeral conversation states carried by constant.Typing messages.
+const (
+	EphemeralTyping    = 1
+	EphemeralRecording = 2
+	EphemeralUploading = 3
+)
+
+// EphemeralStateElem is the content of a constant.Typing message.
+// Clients sending the legacy typing content without a state are treated as EphemeralTyping.
+type EphemeralStateElem struct {
+	MsgTips string `json:"msgTips,omitempty"`
+	State   int32  `json:"state"`
+	// TTL is set by the server, seconds the receiver shows the state unless it is refreshed.
+	TTL int64 `json:"ttl"`
+}
+
+// IsEphemeral reports whether the message is an ephemeral state, relayed online only and never stored.
+func IsEphemeral(msg *sdkws.MsgData) bool {
+	return msg.ContentType == constant.Typing
+}
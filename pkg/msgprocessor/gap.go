@@ -0,0 +1,56 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgprocessor
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+)
+
+// GapSummaryElem is the content of a MsgGapSummaryNotification, the seqs BeginSeq to EndSeq of the conversation
+// were left out of the sync and can be pulled as history.
+type GapSummaryElem struct {
+	ConversationID string `json:"conversationID"`
+	BeginSeq       int64  `json:"beginSeq"`
+	EndSeq         int64  `json:"endSeq"`
+}
+
+// CapSyncRange keeps the newest maxSeqs seqs of [begin, end] and returns the new begin and the skipped range.
+// ok is false if nothing is skipped, maxSeqs 0 disables the cap.
+func CapSyncRange(begin, end, maxSeqs int64) (newBegin, gapBegin, gapEnd int64, ok bool) {
+	if maxSeqs <= 0 || end-begin+1 <= maxSeqs {
+		return begin, 0, 0, false
+	}
+	newBegin = end - maxSeqs + 1
+	return newBegin, begin, newBegin - 1, true
+}
+
+// NewGapSummaryMsg builds the marker sent in place of the skipped seqs, it has no seq and is never stored.
+func NewGapSummaryMsg(conversationID string, gapBegin, gapEnd int64) *sdkws.MsgData {
+	content, _ := json.Marshal(&GapSummaryElem{ConversationID: conversationID, BeginSeq: gapBegin, EndSeq: gapEnd})
+	now := time.Now().UnixMilli()
+	return &sdkws.MsgData{
+		ClientMsgID: fmt.Sprintf("gap_%s_%d_%d", conversationID, gapBegin, gapEnd),
+		ServerMsgID: fmt.Sprintf("gap_%s_%d_%d", conversationID, gapBegin, gapEnd),
+		ContentType: MsgGapSummaryNotification,
+		Content:     content,
+		SendTime:    now,
+		CreateTime:  now,
+		Options:     NewOptions(),
+	}
+}
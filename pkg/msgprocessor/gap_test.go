@@ -0,0 +1,39 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgprocessor
+
+import "testing"
+
+func TestCapSyncRange(t *testing.T) {
+	tests := []struct {
+		name                                string
+		begin, end, maxSeqs                 int64
+		wantBegin, wantGapBegin, wantGapEnd int64
+		wantOK                              bool
+	}{
+		{name: "disabled", begin: 1, end: 5000, maxSeqs: 0, wantBegin: 1},
+		{name: "under cap", begin: 1, end: 2000, maxSeqs: 2000, wantBegin: 1},
+		{name: "over cap", begin: 1, end: 5000, maxSeqs: 2000, wantBegin: 3001, wantGapBegin: 1, wantGapEnd: 3000, wantOK: true},
+		{name: "one over", begin: 10, end: 12, maxSeqs: 2, wantBegin: 11, wantGapBegin: 10, wantGapEnd: 10, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			begin, gapBegin, gapEnd, ok := CapSyncRange(tt.begin, tt.end, tt.maxSeqs)
+			if begin != tt.wantBegin || gapBegin != tt.wantGapBegin || gapEnd != tt.wantGapEnd || ok != tt.wantOK {
+				t.Errorf("CapSyncRange() = %d, %d, %d, %v", begin, gapBegin, gapEnd, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgprocessor
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+)
+
+// LocalizedPushKey is the key in MsgData.Ex carrying the offline push title and body of a message per language,
+// e.g. {"localizedPush":{"en":{"title":"Order shipped"},"zh-CN":{"title":"订单已发货"}}}. Bots and business
+// notifications set it so that each recipient is pushed in the language of their notification profile. Variants
+// without a title are ignored.
+const LocalizedPushKey = "localizedPush"
+
+type LocalizedPush struct {
+	Title string `json:"title"`
+	Desc  string `json:"desc"`
+}
+
+// GetLocalizedPushes returns the localized pushes of the message by language, nil if it has none.
+func GetLocalizedPushes(msg *sdkws.MsgData) map[string]*LocalizedPush {
+	if msg.Ex == "" {
+		return nil
+	}
+	var ex struct {
+		LocalizedPush map[string]*LocalizedPush `json:"localizedPush"`
+	}
+	if err := json.Unmarshal([]byte(msg.Ex), &ex); err != nil {
+		return nil
+	}
+	return ex.LocalizedPush
+}
+
+// SelectLocalizedPush returns the push for language. It tries the language itself, then its base language (zh
+// for zh-TW), then any other variant of the base language, and then the same for defaultLanguage. Languages are
+// compared ignoring case, with _ and - alike. It returns nil when nothing matches.
+func SelectLocalizedPush(pushes map[string]*LocalizedPush, language string, defaultLanguage string) *LocalizedPush {
+	if len(pushes) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(pushes))
+	for key, push := range pushes {
+		if push != nil && push.Title != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	normalized := make(map[string]*LocalizedPush, len(keys))
+	for _, key := range keys {
+		if _, ok := normalized[normalizeLanguage(key)]; !ok {
+			normalized[normalizeLanguage(key)] = pushes[key]
+		}
+	}
+	for _, lang := range []string{language, defaultLanguage} {
+		lang = normalizeLanguage(lang)
+		if lang == "" {
+			continue
+		}
+		if push, ok := normalized[lang]; ok {
+			return push
+		}
+		base := baseLanguage(lang)
+		if push, ok := normalized[base]; ok {
+			return push
+		}
+		for _, key := range keys {
+			if baseLanguage(normalizeLanguage(key)) == base {
+				return pushes[key]
+			}
+		}
+	}
+	return nil
+}
+
+func normalizeLanguage(language string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(language), "_", "-"))
+}
+
+func baseLanguage(language string) string {
+	if i := strings.IndexByte(language, '-'); i >= 0 {
+		return language[:i]
+	}
+	return language
+}
@@ -0,0 +1,64 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgprocessor
+
+import (
+	"testing"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+)
+
+func TestGetLocalizedPushes(t *testing.T) {
+	msg := &sdkws.MsgData{Ex: `{"priority":1,"localizedPush":{"en":{"title":"Hi","desc":"Hello"}}}`}
+	pushes := GetLocalizedPushes(msg)
+	if push := pushes["en"]; push == nil || push.Title != "Hi" || push.Desc != "Hello" {
+		t.Fatalf("pushes = %v", pushes)
+	}
+	if pushes := GetLocalizedPushes(&sdkws.MsgData{Ex: `{"localizedPush":"en"}`}); pushes != nil {
+		t.Fatalf("malformed pushes = %v", pushes)
+	}
+}
+
+func TestSelectLocalizedPush(t *testing.T) {
+	pushes := map[string]*LocalizedPush{
+		"en":    {Title: "en"},
+		"zh_CN": {Title: "zh-CN"},
+		"zh-TW": {Title: "zh-TW"},
+		"fr-CA": {Title: "fr-CA"},
+		"de":    {},
+	}
+	tests := []struct {
+		language string
+		want     string
+	}{
+		{language: "en", want: "en"},
+		{language: "zh-cn", want: "zh-CN"},
+		{language: "zh_TW", want: "zh-TW"},
+		{language: "en-GB", want: "en"},
+		{language: "zh", want: "zh-TW"},
+		{language: "fr", want: "fr-CA"},
+		{language: "de", want: "en"},
+		{language: "", want: "en"},
+	}
+	for _, tt := range tests {
+		push := SelectLocalizedPush(pushes, tt.language, "en")
+		if push == nil || push.Title != tt.want {
+			t.Errorf("SelectLocalizedPush(%q) = %v, want %s", tt.language, push, tt.want)
+		}
+	}
+	if push := SelectLocalizedPush(pushes, "ja", "ko"); push != nil {
+		t.Errorf("SelectLocalizedPush(ja) = %v, want nil", push)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgprocessor
+
+import (
+	"encoding/json"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/utils"
+)
+
+// MeetingRoomElem is the content of the meeting room notifications, Status is the status of the room when sent.
+type MeetingRoomElem struct {
+	RoomID     string `json:"roomID"`
+	Title      string `json:"title"`
+	HostUserID string `json:"hostUserID"`
+	StartTime  int64  `json:"startTime"`
+	EndTime    int64  `json:"endTime"`
+	Status     int32  `json:"status"`
+}
+
+// NewMeetingRoomMsg builds the notification of a meeting room from its host to one participant,
+// it is kept in the history of their single chat and offline pushed.
+func NewMeetingRoomMsg(contentType int32, recvID string, elem *MeetingRoomElem) *sdkws.MsgData {
+	detail, _ := json.Marshal(elem)
+	content, _ := json.Marshal(&sdkws.NotificationElem{Detail: string(detail)})
+	options := NewOptions(WithHistory(true), WithPersistent(), WithOfflinePush(true))
+	// The host gets its own notification, it must not be synced once per participant.
+	options[constant.IsSenderSync] = false
+	return &sdkws.MsgData{
+		SendID:      elem.HostUserID,
+		RecvID:      recvID,
+		ClientMsgID: utils.GetMsgID(elem.HostUserID),
+		SessionType: constant.SingleChatType,
+		MsgFrom:     constant.SysMsgType,
+		ContentType: contentType,
+		Content:     content,
+		CreateTime:  utils.GetCurrentTimestampByMill(),
+		Options:     options,
+		OfflinePushInfo: &sdkws.OfflinePushInfo{
+			Title: elem.Title,
+		},
+	}
+}
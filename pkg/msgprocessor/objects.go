@@ -0,0 +1,86 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgprocessor
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/OpenIMSDK/protocol/constant"
+)
+
+type objectURL struct {
+	URL string `json:"url"`
+}
+
+// mediaElem has the urls of the picture, voice, video and file elems.
+type mediaElem struct {
+	SourcePicture   *objectURL `json:"sourcePicture"`
+	BigPicture      *objectURL `json:"bigPicture"`
+	SnapshotPicture *objectURL `json:"snapshotPicture"`
+	SourceURL       string     `json:"sourceUrl"`
+	VideoURL        string     `json:"videoUrl"`
+	SnapshotURL     string     `json:"snapshotUrl"`
+}
+
+// ObjectURLs returns the distinct urls of the pictures, voices, videos and files a message content refers to.
+func ObjectURLs(contentType int32, content string) []string {
+	switch contentType {
+	case constant.Picture, constant.Voice, constant.Video, constant.File:
+	default:
+		return nil
+	}
+	var elem mediaElem
+	if err := json.Unmarshal([]byte(content), &elem); err != nil {
+		return nil
+	}
+	urls := []string{elem.SourceURL, elem.VideoURL, elem.SnapshotURL}
+	for _, picture := range []*objectURL{elem.SourcePicture, elem.BigPicture, elem.SnapshotPicture} {
+		if picture != nil {
+			urls = append(urls, picture.URL)
+		}
+	}
+	res := make([]string, 0, len(urls))
+	seen := make(map[string]struct{}, len(urls))
+	for _, u := range urls {
+		if u == "" {
+			continue
+		}
+		if _, ok := seen[u]; ok {
+			continue
+		}
+		seen[u] = struct{}{}
+		res = append(res, u)
+	}
+	return res
+}
+
+// ObjectName returns the name of the object of rawURL when it is served by the object api at apiURL.
+func ObjectName(apiURL string, rawURL string) (string, bool) {
+	if apiURL == "" {
+		return "", false
+	}
+	if !strings.HasSuffix(apiURL, "/") {
+		apiURL += "/"
+	}
+	name := strings.TrimPrefix(rawURL, apiURL+"object/")
+	if name == rawURL || name == "" {
+		return "", false
+	}
+	if i := strings.IndexAny(name, "?#"); i >= 0 {
+		name = name[:i]
+	}
+	return name, name != ""
+}
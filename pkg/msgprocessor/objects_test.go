@@ -0,0 +1,80 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgprocessor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/OpenIMSDK/protocol/constant"
+)
+
+func TestObjectURLs(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType int32
+		content     string
+		want        []string
+	}{
+		{name: "text", contentType: constant.Text, content: `{"content":"http://a/object/x"}`, want: nil},
+		{name: "not json", contentType: constant.File, content: "file", want: nil},
+		{
+			name:        "picture",
+			contentType: constant.Picture,
+			content:     `{"sourcePicture":{"url":"http://a/object/p"},"bigPicture":{"url":"http://a/object/p"},"snapshotPicture":{"url":"http://a/object/s"}}`,
+			want:        []string{"http://a/object/p", "http://a/object/s"},
+		},
+		{name: "voice", contentType: constant.Voice, content: `{"sourceUrl":"http://a/object/v"}`, want: []string{"http://a/object/v"}},
+		{
+			name:        "video",
+			contentType: constant.Video,
+			content:     `{"videoUrl":"http://a/object/v","snapshotUrl":"http://a/object/s"}`,
+			want:        []string{"http://a/object/v", "http://a/object/s"},
+		},
+		{name: "file", contentType: constant.File, content: `{"sourceUrl":"http://a/object/f","fileName":"f"}`, want: []string{"http://a/object/f"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ObjectURLs(tt.contentType, tt.content)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ObjectURLs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObjectName(t *testing.T) {
+	tests := []struct {
+		apiURL string
+		rawURL string
+		want   string
+		ok     bool
+	}{
+		{apiURL: "http://api:10002", rawURL: "http://api:10002/object/u/a.png", want: "u/a.png", ok: true},
+		{apiURL: "http://api:10002/", rawURL: "http://api:10002/object/a.png?x=1", want: "a.png", ok: true},
+		{apiURL: "http://api:10002", rawURL: "https://cdn/a.png", ok: false},
+		{apiURL: "http://api:10002", rawURL: "http://api:10002/object/", ok: false},
+		{apiURL: "", rawURL: "/object/a.png", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := ObjectName(tt.apiURL, tt.rawURL)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ObjectName(%q, %q) = %q, %v, want %q, %v", tt.apiURL, tt.rawURL, got, ok, tt.want, tt.ok)
+		}
+	}
+}
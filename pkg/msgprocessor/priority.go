@@ -0,0 +1,74 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgprocessor
+
+import (
+	"encoding/json"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+)
+
+// Message priorities. High priority messages such as calls and one time passwords skip batching and are
+// pushed with the provider's high priority flag, bulk messages may be throttled by the msg rpc.
+const (
+	PriorityNormal = 0
+	PriorityHigh   = 1
+	PriorityBulk   = 2
+)
+
+// PriorityKey is the key in MsgData.Ex carrying the priority of a message.
+// The protocol has no field for it, so like the thread parent it travels in the ex json.
+const PriorityKey = "priority"
+
+// GetPriority returns the priority of the message, PriorityNormal if it has none or an unknown one.
+func GetPriority(msg *sdkws.MsgData) int32 {
+	if msg.Ex == "" {
+		return PriorityNormal
+	}
+	var ex map[string]any
+	if err := json.Unmarshal([]byte(msg.Ex), &ex); err != nil {
+		return PriorityNormal
+	}
+	priority, _ := ex[PriorityKey].(float64)
+	switch int32(priority) {
+	case PriorityHigh, PriorityBulk:
+		return int32(priority)
+	default:
+		return PriorityNormal
+	}
+}
+
+// SetPriority sets the priority of the message, keeping the other keys of ex.
+func SetPriority(msg *sdkws.MsgData, priority int32) {
+	ex := make(map[string]any)
+	if msg.Ex != "" {
+		_ = json.Unmarshal([]byte(msg.Ex), &ex)
+	}
+	ex[PriorityKey] = priority
+	data, _ := json.Marshal(ex)
+	msg.Ex = string(data)
+}
+
+// PriorityName returns the name of priority, used as metrics label.
+func PriorityName(priority int32) string {
+	switch priority {
+	case PriorityHigh:
+		return "high"
+	case PriorityBulk:
+		return "bulk"
+	default:
+		return "normal"
+	}
+}
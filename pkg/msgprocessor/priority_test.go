@@ -0,0 +1,54 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgprocessor
+
+import (
+	"testing"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+)
+
+func TestGetPriority(t *testing.T) {
+	tests := []struct {
+		name string
+		ex   string
+		want int32
+	}{
+		{name: "empty ex", ex: "", want: PriorityNormal},
+		{name: "not json", ex: "plain text", want: PriorityNormal},
+		{name: "unknown", ex: `{"priority":9}`, want: PriorityNormal},
+		{name: "high", ex: `{"priority":1}`, want: PriorityHigh},
+		{name: "bulk", ex: `{"priority":2,"parentMsgID":"root"}`, want: PriorityBulk},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetPriority(&sdkws.MsgData{Ex: tt.ex}); got != tt.want {
+				t.Errorf("GetPriority() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetPriority(t *testing.T) {
+	msg := &sdkws.MsgData{}
+	SetParentMsgID(msg, "root")
+	SetPriority(msg, PriorityHigh)
+	if got := GetPriority(msg); got != PriorityHigh {
+		t.Errorf("GetPriority() = %v, want %v", got, PriorityHigh)
+	}
+	if got := GetParentMsgID(msg); got != "root" {
+		t.Errorf("GetParentMsgID() = %v, want root", got)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgprocessor
+
+import (
+	"encoding/json"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+)
+
+// ParentMsgIDKey is the key in MsgData.Ex carrying the clientMsgID of the thread root a message replies to.
+// The protocol has no field for it, so it travels in the ex json.
+const ParentMsgIDKey = "parentMsgID"
+
+// GetParentMsgID returns the thread root the message replies to, empty if it is not a thread reply.
+func GetParentMsgID(msg *sdkws.MsgData) string {
+	if msg.Ex == "" {
+		return ""
+	}
+	var ex map[string]any
+	if err := json.Unmarshal([]byte(msg.Ex), &ex); err != nil {
+		return ""
+	}
+	parentMsgID, _ := ex[ParentMsgIDKey].(string)
+	return parentMsgID
+}
+
+// SetParentMsgID marks the message as a reply in the thread of parentMsgID, keeping the other keys of ex.
+func SetParentMsgID(msg *sdkws.MsgData, parentMsgID string) {
+	ex := make(map[string]any)
+	if msg.Ex != "" {
+		_ = json.Unmarshal([]byte(msg.Ex), &ex)
+	}
+	ex[ParentMsgIDKey] = parentMsgID
+	data, _ := json.Marshal(ex)
+	msg.Ex = string(data)
+}
@@ -0,0 +1,52 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgprocessor
+
+import (
+	"testing"
+
+	"github.com/OpenIMSDK/protocol/sdkws"
+)
+
+func TestGetParentMsgID(t *testing.T) {
+	tests := []struct {
+		name string
+		ex   string
+		want string
+	}{
+		{name: "empty ex", ex: "", want: ""},
+		{name: "not json", ex: "plain text", want: ""},
+		{name: "no parent", ex: `{"a":1}`, want: ""},
+		{name: "parent", ex: `{"parentMsgID":"root"}`, want: "root"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetParentMsgID(&sdkws.MsgData{Ex: tt.ex}); got != tt.want {
+				t.Errorf("GetParentMsgID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetParentMsgID(t *testing.T) {
+	msg := &sdkws.MsgData{Ex: `{"a":"b"}`}
+	SetParentMsgID(msg, "root")
+	if got := GetParentMsgID(msg); got != "root" {
+		t.Errorf("GetParentMsgID() = %v, want root", got)
+	}
+	if msg.Ex != `{"a":"b","parentMsgID":"root"}` {
+		t.Errorf("ex keys not kept: %s", msg.Ex)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package msgsize limits the payload size of the messages users send by content type.
+package msgsize
+
+import (
+	"fmt"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+)
+
+// Limit returns the maximum payload size in bytes of contentType, 0 is no limit.
+func Limit(config *config.GlobalConfig, contentType int32) int {
+	if limit, ok := config.MsgSize.ContentTypes[contentType]; ok {
+		return limit
+	}
+	return config.MsgSize.Default
+}
+
+// PayloadSize is the size counted against the limit, the content and the ex of msg.
+func PayloadSize(msg *sdkws.MsgData) int {
+	return len(msg.Content) + len(msg.Ex)
+}
+
+// Check returns servererrs.ErrMsgTooLarge when the payload of msg is over the limit of its content type.
+// Notifications are built by the server and are not limited.
+func Check(config *config.GlobalConfig, msg *sdkws.MsgData) error {
+	if msg.ContentType >= constant.NotificationBegin && msg.ContentType <= constant.NotificationEnd {
+		return nil
+	}
+	limit := Limit(config, msg.ContentType)
+	if size := PayloadSize(msg); limit > 0 && size > limit {
+		return servererrs.ErrMsgTooLarge.Wrap(fmt.Sprintf("the payload of content type %d is %d bytes, over the limit of %d bytes", msg.ContentType, size, limit))
+	}
+	return nil
+}
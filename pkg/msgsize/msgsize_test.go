@@ -0,0 +1,56 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package msgsize
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/mw/specialerror"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/servererrs"
+)
+
+func TestCheck(t *testing.T) {
+	conf := config.NewGlobalConfig()
+	conf.MsgSize.Default = 100
+	conf.MsgSize.ContentTypes = map[int32]int{constant.Text: 10, constant.Custom: 0}
+	msg := func(contentType int32, size int) *sdkws.MsgData {
+		return &sdkws.MsgData{ContentType: contentType, Content: []byte(strings.Repeat("a", size))}
+	}
+	if err := Check(conf, msg(constant.Text, 10)); err != nil {
+		t.Errorf("text at the limit: %v", err)
+	}
+	if err := Check(conf, msg(constant.Text, 11)); !servererrs.ErrMsgTooLarge.Is(specialerror.ErrCode(errs.Unwrap(err))) {
+		t.Errorf("text over the limit: %v", err)
+	}
+	if err := Check(conf, msg(constant.Custom, 1000)); err != nil {
+		t.Errorf("custom without a limit: %v", err)
+	}
+	if err := Check(conf, msg(constant.Picture, 101)); !servererrs.ErrMsgTooLarge.Is(specialerror.ErrCode(errs.Unwrap(err))) {
+		t.Errorf("picture over the default: %v", err)
+	}
+	if err := Check(conf, msg(constant.GroupCreatedNotification, 1000)); err != nil {
+		t.Errorf("notification: %v", err)
+	}
+	ex := msg(constant.Text, 6)
+	ex.Ex = "12345"
+	if err := Check(conf, ex); err == nil {
+		t.Errorf("the ex counts against the limit")
+	}
+}
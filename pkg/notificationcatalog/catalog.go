@@ -0,0 +1,256 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notificationcatalog describes every notification the server generates, so clients can decode
+// them without reading the server source, and checks the payloads built by the server against it.
+package notificationcatalog
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+)
+
+// Version changes whenever an entry is added or removed or a payload changes shape.
+const Version = 2
+
+// Entry describes one notification content type. Unless Raw is set the content of the message is a
+// sdkws.NotificationElem whose detail is the JSON payload.
+type Entry struct {
+	ContentType int32   `json:"contentType"`
+	Name        string  `json:"name"`
+	Category    string  `json:"category"`
+	I18nKey     string  `json:"i18nKey"`
+	Raw         bool    `json:"raw"`
+	Payload     *Schema `json:"payload,omitempty"`
+
+	payloadType reflect.Type
+}
+
+type entryOpt func(*Entry)
+
+// required marks payload fields that must not be empty.
+func required(names ...string) entryOpt {
+	return func(e *Entry) {
+		e.Payload.Required = append(e.Payload.Required, names...)
+	}
+}
+
+// raw marks a content type whose message content is the payload itself.
+func raw() entryOpt {
+	return func(e *Entry) {
+		e.Raw = true
+	}
+}
+
+func newEntry(contentType int32, category string, name string, payload any, opts ...entryOpt) *Entry {
+	e := &Entry{
+		ContentType: contentType,
+		Name:        name,
+		Category:    category,
+		I18nKey:     "notification." + name,
+	}
+	if payload != nil {
+		e.payloadType = reflect.TypeOf(payload)
+		e.Payload = schemaOf(e.payloadType, make(map[reflect.Type]bool))
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+var (
+	entries = map[string]*Entry{}
+	// contentTypes holds the entries of each content type, the protocol gives a few notifications the same
+	// content type (e.g. ClearConversationNotification and MsgRevokeNotification), their payloads tell them apart.
+	contentTypes = map[int32][]*Entry{}
+)
+
+func register(es ...*Entry) {
+	for _, e := range es {
+		if _, ok := entries[e.Name]; ok {
+			panic(fmt.Sprintf("notification %s registered twice", e.Name))
+		}
+		entries[e.Name] = e
+		contentTypes[e.ContentType] = append(contentTypes[e.ContentType], e)
+	}
+}
+
+func init() {
+	register(
+		// group
+		newEntry(constant.GroupCreatedNotification, "group", "groupCreated", &sdkws.GroupCreatedTips{}, required("group")),
+		newEntry(constant.GroupInfoSetNotification, "group", "groupInfoSet", &sdkws.GroupInfoSetTips{}, required("group")),
+		newEntry(constant.JoinGroupApplicationNotification, "group", "joinGroupApplication", &sdkws.JoinGroupApplicationTips{}, required("group")),
+		newEntry(constant.MemberQuitNotification, "group", "memberQuit", &sdkws.MemberQuitTips{}, required("group")),
+		newEntry(constant.GroupApplicationAcceptedNotification, "group", "groupApplicationAccepted", &sdkws.GroupApplicationAcceptedTips{}, required("group")),
+		newEntry(constant.GroupApplicationRejectedNotification, "group", "groupApplicationRejected", &sdkws.GroupApplicationRejectedTips{}, required("group")),
+		newEntry(constant.GroupOwnerTransferredNotification, "group", "groupOwnerTransferred", &sdkws.GroupOwnerTransferredTips{}, required("group")),
+		newEntry(constant.MemberKickedNotification, "group", "memberKicked", &sdkws.MemberKickedTips{}, required("group")),
+		newEntry(constant.MemberInvitedNotification, "group", "memberInvited", &sdkws.MemberInvitedTips{}, required("group")),
+		newEntry(constant.MemberEnterNotification, "group", "memberEnter", &sdkws.MemberEnterTips{}, required("group")),
+		newEntry(constant.GroupDismissedNotification, "group", "groupDismissed", &sdkws.GroupDismissedTips{}, required("group")),
+		newEntry(constant.GroupMutedNotification, "group", "groupMuted", &sdkws.GroupMutedTips{}, required("group")),
+		newEntry(constant.GroupCancelMutedNotification, "group", "groupCancelMuted", &sdkws.GroupCancelMutedTips{}, required("group")),
+		newEntry(constant.GroupMemberMutedNotification, "group", "groupMemberMuted", &sdkws.GroupMemberMutedTips{}, required("group")),
+		newEntry(constant.GroupMemberCancelMutedNotification, "group", "groupMemberCancelMuted", &sdkws.GroupMemberCancelMutedTips{}, required("group")),
+		newEntry(constant.GroupMemberInfoSetNotification, "group", "groupMemberInfoSet", &sdkws.GroupMemberInfoSetTips{}, required("group")),
+		newEntry(constant.GroupMemberSetToAdminNotification, "group", "groupMemberSetToAdmin", &sdkws.GroupMemberInfoSetTips{}, required("group")),
+		newEntry(constant.GroupMemberSetToOrdinaryUserNotification, "group", "groupMemberSetToOrdinaryUser", &sdkws.GroupMemberInfoSetTips{}, required("group")),
+		newEntry(constant.GroupInfoSetAnnouncementNotification, "group", "groupInfoSetAnnouncement", &sdkws.GroupInfoSetAnnouncementTips{}, required("group")),
+		newEntry(constant.GroupInfoSetNameNotification, "group", "groupInfoSetName", &sdkws.GroupInfoSetNameTips{}, required("group")),
+		newEntry(constant.SuperGroupUpdateNotification, "group", "superGroupUpdate", nil),
+		// user
+		newEntry(constant.UserInfoUpdatedNotification, "user", "userInfoUpdated", &sdkws.UserInfoUpdatedTips{}, required("userID")),
+		newEntry(constant.UserStatusChangeNotification, "user", "userStatusChanged", &sdkws.UserStatusChangeTips{}),
+		newEntry(constant.UserCommandAddNotification, "user", "userCommandAdd", &sdkws.UserCommandAddTips{}),
+		newEntry(constant.UserCommandUpdateNotification, "user", "userCommandUpdate", &sdkws.UserCommandUpdateTips{}),
+		newEntry(constant.UserCommandDeleteNotification, "user", "userCommandDelete", &sdkws.UserCommandDeleteTips{}),
+		// friend
+		newEntry(constant.FriendApplicationNotification, "friend", "friendApplicationAdded", &sdkws.FriendApplicationTips{}, required("fromToUserID")),
+		newEntry(constant.FriendApplicationApprovedNotification, "friend", "friendApplicationApproved", &sdkws.FriendApplicationApprovedTips{}, required("fromToUserID")),
+		// The rejection is sent with the same payload as the approval.
+		newEntry(constant.FriendApplicationRejectedNotification, "friend", "friendApplicationRejected", &sdkws.FriendApplicationApprovedTips{}, required("fromToUserID")),
+		newEntry(constant.FriendAddedNotification, "friend", "friendAdded", &sdkws.FriendAddedTips{}, required("friend")),
+		newEntry(constant.FriendDeletedNotification, "friend", "friendDeleted", &sdkws.FriendDeletedTips{}, required("fromToUserID")),
+		newEntry(constant.FriendRemarkSetNotification, "friend", "friendRemarkSet", &sdkws.FriendInfoChangedTips{}, required("fromToUserID")),
+		newEntry(constant.BlackAddedNotification, "friend", "blackAdded", &sdkws.BlackAddedTips{}, required("fromToUserID")),
+		newEntry(constant.BlackDeletedNotification, "friend", "blackDeleted", &sdkws.BlackDeletedTips{}, required("fromToUserID")),
+		newEntry(constant.FriendInfoUpdatedNotification, "friend", "friendInfoUpdated", &sdkws.UserInfoUpdatedTips{}, required("userID")),
+		newEntry(constant.FriendsInfoUpdateNotification, "friend", "friendsInfoUpdate", &sdkws.FriendsInfoUpdateTips{}, required("fromToUserID")),
+		newEntry(msgprocessor.FriendLabelChangedNotification, "friend", "friendLabelChanged", &apistruct.FriendLabelChangedTips{}, required("ownerUserID", "labelID")),
+		// conversation
+		newEntry(constant.ConversationChangeNotification, "conversation", "conversationChanged", &sdkws.ConversationUpdateTips{}),
+		newEntry(constant.ConversationUnreadNotification, "conversation", "conversationUnread", &sdkws.ConversationHasReadTips{}),
+		newEntry(constant.ConversationPrivateChatNotification, "conversation", "conversationSetPrivate", &sdkws.ConversationSetPrivateTips{}),
+		newEntry(constant.ClearConversationNotification, "conversation", "clearConversation", &sdkws.ClearConversationTips{}, required("userID")),
+		newEntry(msgprocessor.NotificationProfileChangedNotification, "conversation", "notificationProfileChanged", &apistruct.NotificationProfileChangedTips{}, required("ownerUserID")),
+		// msg
+		newEntry(constant.MsgRevokeNotification, "msg", "msgRevoked", &sdkws.RevokeMsgTips{}, required("conversationID")),
+		newEntry(constant.HasReadReceipt, "msg", "hasReadReceipt", &sdkws.MarkAsReadTips{}, required("conversationID")),
+		newEntry(constant.DeleteMsgsNotification, "msg", "msgsDeleted", &sdkws.DeleteMsgsTips{}, required("userID", "conversationID")),
+		newEntry(msgprocessor.MsgEditNotification, "msg", "msgEdited", &apistruct.MsgEditTips{}, required("conversationID", "clientMsgID")),
+		newEntry(constant.ReactionMessageModifier, "msg", "msgReactionSet", &apistruct.MsgReactionTips{}, required("conversationID", "clientMsgID")),
+		newEntry(constant.ReactionMessageDeleter, "msg", "msgReactionRemoved", &apistruct.MsgReactionTips{}, required("conversationID", "clientMsgID")),
+		newEntry(msgprocessor.MsgGapSummaryNotification, "msg", "msgGapSummary", &msgprocessor.GapSummaryElem{}, raw(), required("conversationID")),
+		newEntry(constant.BusinessNotification, "business", "business", &BusinessElem{}, required("key")),
+		// meeting
+		newEntry(msgprocessor.MeetingStartingSoonNotification, "meeting", "meetingStartingSoon", &msgprocessor.MeetingRoomElem{}, required("roomID")),
+		newEntry(msgprocessor.MeetingStartedNotification, "meeting", "meetingStarted", &msgprocessor.MeetingRoomElem{}, required("roomID")),
+		newEntry(msgprocessor.MeetingEndedNotification, "meeting", "meetingEnded", &msgprocessor.MeetingRoomElem{}, required("roomID")),
+	)
+}
+
+// BusinessElem is the payload of the business notifications sent by app managers.
+type BusinessElem struct {
+	Key  string `json:"key"`
+	Data string `json:"data"`
+}
+
+// Entries returns the catalog ordered by content type, then by name.
+func Entries() []*Entry {
+	es := make([]*Entry, 0, len(entries))
+	for _, e := range entries {
+		es = append(es, e)
+	}
+	sort.Slice(es, func(i, j int) bool {
+		if es[i].ContentType == es[j].ContentType {
+			return es[i].Name < es[j].Name
+		}
+		return es[i].ContentType < es[j].ContentType
+	})
+	return es
+}
+
+// Lookup returns the entries of contentType, more than one when the protocol shares the content type.
+func Lookup(contentType int32) []*Entry {
+	return contentTypes[contentType]
+}
+
+// Validate checks that payload is what the catalog declares for contentType, so a notification can not
+// be sent with a payload its clients do not expect.
+func Validate(contentType int32, payload any) error {
+	es := contentTypes[contentType]
+	switch len(es) {
+	case 0:
+		return errs.ErrInternalServer.Wrap(fmt.Sprintf("notification content type %d is not in the catalog", contentType))
+	case 1:
+		return es[0].validate(payload)
+	}
+	names := make([]string, 0, len(es))
+	for _, e := range es {
+		if e.accepts(payload) {
+			return e.validate(payload)
+		}
+		names = append(names, e.Name)
+	}
+	return errs.ErrInternalServer.Wrap(fmt.Sprintf("notification content type %d payload is %T, want the payload of one of %v", contentType, payload, names))
+}
+
+// accepts reports whether payload has the type of the payload of e.
+func (e *Entry) accepts(payload any) bool {
+	v := reflect.ValueOf(payload)
+	if e.payloadType == nil {
+		return !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil())
+	}
+	return v.IsValid() && indirect(v.Type()) == indirect(e.payloadType)
+}
+
+func (e *Entry) validate(payload any) error {
+	v := reflect.ValueOf(payload)
+	if e.payloadType == nil {
+		if payload != nil && !(v.Kind() == reflect.Pointer && v.IsNil()) {
+			return errs.ErrInternalServer.Wrap(fmt.Sprintf("notification %s has no payload", e.Name))
+		}
+		return nil
+	}
+	if !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return errs.ErrInternalServer.Wrap(fmt.Sprintf("notification %s payload is nil", e.Name))
+	}
+	if indirect(v.Type()) != indirect(e.payloadType) {
+		return errs.ErrInternalServer.Wrap(fmt.Sprintf("notification %s payload is %s, want %s", e.Name, v.Type(), e.payloadType))
+	}
+	v = reflect.Indirect(v)
+	for _, name := range e.Payload.Required {
+		field, ok := fieldByJSONName(v, name)
+		if !ok || field.IsZero() {
+			return errs.ErrInternalServer.Wrap(fmt.Sprintf("notification %s payload misses %s", e.Name, name))
+		}
+	}
+	return nil
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// Catalog is the published form of the catalog, clients can cache it until Version changes.
+type Catalog struct {
+	Version int      `json:"version"`
+	Entries []*Entry `json:"entries"`
+}
+
+func Get() *Catalog {
+	return &Catalog{Version: Version, Entries: Entries()}
+}
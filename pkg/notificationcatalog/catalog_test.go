@@ -0,0 +1,70 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notificationcatalog
+
+import (
+	"testing"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType int32
+		payload     any
+		wantErr     bool
+	}{
+		{name: "valid", contentType: constant.GroupCreatedNotification, payload: &sdkws.GroupCreatedTips{Group: &sdkws.GroupInfo{GroupID: "g1"}}},
+		{name: "missing required", contentType: constant.GroupCreatedNotification, payload: &sdkws.GroupCreatedTips{}, wantErr: true},
+		{name: "wrong payload", contentType: constant.GroupCreatedNotification, payload: &sdkws.GroupDismissedTips{Group: &sdkws.GroupInfo{}}, wantErr: true},
+		{name: "nil payload", contentType: constant.GroupCreatedNotification, payload: (*sdkws.GroupCreatedTips)(nil), wantErr: true},
+		{name: "no payload", contentType: constant.SuperGroupUpdateNotification, payload: nil},
+		{name: "value payload", contentType: msgprocessor.MeetingStartedNotification, payload: msgprocessor.MeetingRoomElem{RoomID: "r1"}},
+		{name: "unknown content type", contentType: 1, payload: &sdkws.GroupCreatedTips{}, wantErr: true},
+		{name: "shared content type revoke", contentType: constant.MsgRevokeNotification, payload: &sdkws.RevokeMsgTips{ConversationID: "c1"}},
+		{name: "shared content type clear", contentType: constant.ClearConversationNotification, payload: &sdkws.ClearConversationTips{UserID: "u1"}},
+		{name: "shared content type missing required", contentType: constant.MsgRevokeNotification, payload: &sdkws.RevokeMsgTips{}, wantErr: true},
+		{name: "shared content type wrong payload", contentType: constant.MsgRevokeNotification, payload: &sdkws.GroupCreatedTips{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.contentType, tt.payload); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEntries(t *testing.T) {
+	es := Entries()
+	for i, e := range es {
+		if i > 0 && (es[i-1].ContentType > e.ContentType || es[i-1].ContentType == e.ContentType && es[i-1].Name >= e.Name) {
+			t.Errorf("entries not ordered at %s", e.Name)
+		}
+		if e.Payload == nil && e.payloadType != nil {
+			t.Errorf("entry %s has no schema", e.Name)
+		}
+		if e.Payload != nil {
+			for _, name := range e.Payload.Required {
+				if _, ok := e.Payload.Properties[name]; !ok {
+					t.Errorf("entry %s requires unknown field %s", e.Name, name)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright © 2023 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offlinepush
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/msgprocessor"
+)
+
+// OfflinePusher Offline Pusher.
+type OfflinePusher interface {
+	Push(ctx context.Context, userIDs []string, title, content string, opts *Opts) error
+}
+
+// Opts opts.
+type Opts struct {
+	Signal        *Signal
+	IOSPushSound  string
+	IOSBadgeCount bool
+	Ex            string
+	// Sound is the sound key the receivers picked in their notification profile, it takes precedence over
+	// IOSPushSound and is also played on Android.
+	Sound string
+	// ContentType and SessionType of the pushed message, used by providers that classify notifications.
+	ContentType int32
+	SessionType int32
+	// PlatformID limits the push to devices of one platform when set, see push.platformProviders.
+	PlatformID int
+	// Priority is the msgprocessor priority of the message, mapped to the delivery priority of the provider.
+	Priority int32
+}
+
+// IOSSound returns the sound to play on iOS devices.
+func (o *Opts) IOSSound() string {
+	if o.Sound != "" {
+		return o.Sound
+	}
+	return o.IOSPushSound
+}
+
+// IsHighPriority reports whether the message must be delivered immediately, e.g. a call or a one time password.
+func (o *Opts) IsHighPriority() bool {
+	return o.Priority == msgprocessor.PriorityHigh
+}
+
+// IsBulkPriority reports whether the message may be delivered with the power saving priority of the provider.
+func (o *Opts) IsBulkPriority() bool {
+	return o.Priority == msgprocessor.PriorityBulk
+}
+
+// Signal message id.
+type Signal struct {
+	ClientMsgID string
+}
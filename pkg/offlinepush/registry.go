@@ -0,0 +1,242 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package offlinepush
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/OpenIMSDK/tools/log"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/tenant"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/throttle"
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultProvider is used when push.enable names no registered provider.
+const DefaultProvider = "dummy"
+
+// Factory creates an offline pusher from the global config.
+type Factory func(config *config.GlobalConfig, cache cache.MsgModel) (OfflinePusher, error)
+
+var (
+	factoriesLock sync.RWMutex
+	factories     = make(map[string]Factory)
+)
+
+// Register makes a provider selectable by name in push.enable and push.platformProviders.
+// It is meant to be called from the init function of the provider package and panics if the name is taken.
+func Register(name string, factory Factory) {
+	factoriesLock.Lock()
+	defer factoriesLock.Unlock()
+	if factory == nil {
+		panic("offlinepush: register nil factory for " + name)
+	}
+	if _, ok := factories[name]; ok {
+		panic("offlinepush: register called twice for " + name)
+	}
+	factories[name] = factory
+}
+
+// Providers returns the names of all registered providers.
+func Providers() []string {
+	factoriesLock.RLock()
+	defer factoriesLock.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newProvider creates the provider registered as name, scope is the app id of a tenant provider and keeps its
+// destination state apart from the provider of the cluster.
+func newProvider(name string, config *config.GlobalConfig, cache cache.MsgModel, scope string) (OfflinePusher, error) {
+	factoriesLock.RLock()
+	factory, ok := factories[name]
+	factoriesLock.RUnlock()
+	if !ok {
+		return nil, errs.Wrap(fmt.Errorf("offline push provider %s is not registered, registered providers %v", name, Providers()))
+	}
+	pusher, err := factory(config, cache)
+	if err != nil {
+		return nil, err
+	}
+	dest := "push:" + name
+	if scope != "" {
+		dest = "push:" + scope + ":" + name
+	}
+	return &throttledPusher{dest: dest, pusher: pusher}, nil
+}
+
+// throttledPusher fails at once while its provider is throttled or disabled, see destinationThrottle.
+type throttledPusher struct {
+	dest   string
+	pusher OfflinePusher
+}
+
+func (p *throttledPusher) Push(ctx context.Context, userIDs []string, title, content string, opts *Opts) error {
+	return throttle.Do(ctx, p.dest, func() error {
+		return p.pusher.Push(ctx, userIDs, title, content, opts)
+	})
+}
+
+// NewOfflinePusher creates the provider named in push.enable. When push.platformProviders is set, users
+// with a device token on one of those platforms are pushed through the provider configured for it instead.
+// With tenant.enable set, pushes of a tenant with push overrides go through providers built from its config.
+func NewOfflinePusher(config *config.GlobalConfig, cache cache.MsgModel) (OfflinePusher, error) {
+	pusher, err := newOfflinePusher(config, cache, "")
+	if err != nil {
+		return nil, err
+	}
+	if !config.Tenant.Enable {
+		return pusher, nil
+	}
+	return &tenantPusher{cache: cache, defaultPusher: pusher, pushers: make(map[string]*tenantProvider)}, nil
+}
+
+func newOfflinePusher(config *config.GlobalConfig, cache cache.MsgModel, scope string) (OfflinePusher, error) {
+	name := config.Push.Enable
+	factoriesLock.RLock()
+	_, ok := factories[name]
+	factoriesLock.RUnlock()
+	if !ok {
+		name = DefaultProvider
+	}
+	defaultPusher, err := newProvider(name, config, cache, scope)
+	if err != nil {
+		return nil, err
+	}
+	if len(config.Push.PlatformProviders) == 0 {
+		return defaultPusher, nil
+	}
+	p := &platformPusher{cache: cache, defaultPusher: defaultPusher}
+	pushers := map[string]OfflinePusher{name: defaultPusher}
+	platformIDs := make([]int, 0, len(config.Push.PlatformProviders))
+	for platformID := range config.Push.PlatformProviders {
+		platformIDs = append(platformIDs, platformID)
+	}
+	sort.Ints(platformIDs)
+	for _, platformID := range platformIDs {
+		providerName := config.Push.PlatformProviders[platformID]
+		pusher, ok := pushers[providerName]
+		if !ok {
+			if pusher, err = newProvider(providerName, config, cache, scope); err != nil {
+				return nil, err
+			}
+			pushers[providerName] = pusher
+		}
+		p.routes = append(p.routes, platformRoute{platformID: platformID, pusher: pusher})
+	}
+	return p, nil
+}
+
+type platformRoute struct {
+	platformID int
+	pusher     OfflinePusher
+}
+
+// platformPusher pushes each user through the providers of the platforms the user has a device token on,
+// users without a token on any routed platform are pushed through the default provider.
+type platformPusher struct {
+	cache         cache.MsgModel
+	defaultPusher OfflinePusher
+	routes        []platformRoute
+}
+
+func (p *platformPusher) Push(ctx context.Context, userIDs []string, title, content string, opts *Opts) error {
+	routed := make(map[string]struct{}, len(userIDs))
+	g := errgroup.Group{}
+	for _, route := range p.routes {
+		var platformUserIDs []string
+		for _, userID := range userIDs {
+			if token, err := p.cache.GetFcmToken(ctx, userID, route.platformID); err == nil && token != "" {
+				platformUserIDs = append(platformUserIDs, userID)
+				routed[userID] = struct{}{}
+			}
+		}
+		if len(platformUserIDs) == 0 {
+			continue
+		}
+		route := route
+		platformOpts := *opts
+		platformOpts.PlatformID = route.platformID
+		g.Go(func() error {
+			if err := route.pusher.Push(ctx, platformUserIDs, title, content, &platformOpts); err != nil {
+				log.ZWarn(ctx, "platform offline push failed", err, "platformID", route.platformID, "userIDs", platformUserIDs)
+				return err
+			}
+			return nil
+		})
+	}
+	var restUserIDs []string
+	for _, userID := range userIDs {
+		if _, ok := routed[userID]; !ok {
+			restUserIDs = append(restUserIDs, userID)
+		}
+	}
+	if len(restUserIDs) > 0 {
+		g.Go(func() error {
+			return p.defaultPusher.Push(ctx, restUserIDs, title, content, opts)
+		})
+	}
+	return g.Wait()
+}
+
+type tenantProvider struct {
+	config *config.GlobalConfig
+	pusher OfflinePusher
+}
+
+// tenantPusher pushes through the providers of the tenant the push belongs to. They are built on first use
+// and rebuilt when the tenant config is reloaded with changes.
+type tenantPusher struct {
+	cache         cache.MsgModel
+	defaultPusher OfflinePusher
+	lock          sync.Mutex
+	pushers       map[string]*tenantProvider
+}
+
+func (p *tenantPusher) Push(ctx context.Context, userIDs []string, title, content string, opts *Opts) error {
+	pusher, err := p.pusher(ctx)
+	if err != nil {
+		return err
+	}
+	return pusher.Push(ctx, userIDs, title, content, opts)
+}
+
+func (p *tenantPusher) pusher(ctx context.Context) (OfflinePusher, error) {
+	conf, ok := tenant.Lookup(ctx)
+	if !ok {
+		return p.defaultPusher, nil
+	}
+	appID := tenant.GetAppID(ctx)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if provider, ok := p.pushers[appID]; ok && provider.config == conf {
+		return provider.pusher, nil
+	}
+	pusher, err := newOfflinePusher(conf, p.cache, appID)
+	if err != nil {
+		return nil, err
+	}
+	p.pushers[appID] = &tenantProvider{config: conf, pusher: pusher}
+	return pusher, nil
+}
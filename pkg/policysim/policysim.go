@@ -0,0 +1,179 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policysim evaluates proposed policies against a sample of past messages without enforcing them.
+package policysim
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// TopSenderNum is the number of senders returned in TopSenders.
+const TopSenderNum = 10
+
+// Msg is a sampled message reduced to what the policies look at, the api fills SendID and ConversationID with
+// aliases so that nothing returned by a simulation identifies a user.
+type Msg struct {
+	ConversationID string
+	SendID         string
+	SendTime       int64
+	Text           string
+}
+
+// Anonymizer replaces ids with a salted hash, the same id maps to the same alias within one simulation only.
+type Anonymizer struct {
+	salt string
+}
+
+func NewAnonymizer(salt string) *Anonymizer {
+	return &Anonymizer{salt: salt}
+}
+
+func (a *Anonymizer) Alias(id string) string {
+	sum := sha256.Sum256([]byte(a.salt + ":" + id))
+	return hex.EncodeToString(sum[:6])
+}
+
+type WordFilterResult struct {
+	// Matched is the number of messages containing at least one word.
+	Matched int64
+	// Words is the number of messages containing each word.
+	Words map[string]int64
+	// Senders and Conversations are the distinct senders and conversations of the matched messages.
+	Senders       int64
+	Conversations int64
+	TopSenders    []*SenderCount
+}
+
+type SenderCount struct {
+	SendID string
+	Count  int64
+}
+
+// WordFilter matches the words case-insensitively anywhere in the text of msgs.
+func WordFilter(words []string, msgs []*Msg) *WordFilterResult {
+	res := &WordFilterResult{Words: make(map[string]int64, len(words))}
+	lower := make(map[string]string, len(words))
+	for _, word := range words {
+		if word == "" {
+			continue
+		}
+		res.Words[word] = 0
+		lower[word] = strings.ToLower(word)
+	}
+	senders := make(map[string]int64)
+	conversations := make(map[string]struct{})
+	for _, msg := range msgs {
+		if msg.Text == "" {
+			continue
+		}
+		text := strings.ToLower(msg.Text)
+		var matched bool
+		for word, l := range lower {
+			if strings.Contains(text, l) {
+				res.Words[word]++
+				matched = true
+			}
+		}
+		if matched {
+			res.Matched++
+			senders[msg.SendID]++
+			conversations[msg.ConversationID] = struct{}{}
+		}
+	}
+	res.Senders = int64(len(senders))
+	res.Conversations = int64(len(conversations))
+	res.TopSenders = topSenders(senders)
+	return res
+}
+
+type RateLimitResult struct {
+	// Limited is the number of messages over the limit.
+	Limited int64
+	// Senders is the number of senders with at least one limited message.
+	Senders    int64
+	TopSenders []*SenderCount
+}
+
+// RateLimit allows each sender limit messages per window (ms) and counts the messages it would reject.
+// Rejected messages do not count against the limit.
+func RateLimit(limit int, window int64, msgs []*Msg) *RateLimitResult {
+	res := &RateLimitResult{}
+	if limit <= 0 || window <= 0 {
+		return res
+	}
+	senders := make(map[string]int64)
+	bySender := make(map[string][]int64)
+	for _, msg := range msgs {
+		bySender[msg.SendID] = append(bySender[msg.SendID], msg.SendTime)
+	}
+	for sendID, times := range bySender {
+		sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+		accepted := make([]int64, 0, len(times))
+		var limited int64
+		for _, t := range times {
+			for len(accepted) > 0 && accepted[0] <= t-window {
+				accepted = accepted[1:]
+			}
+			if len(accepted) >= limit {
+				limited++
+				continue
+			}
+			accepted = append(accepted, t)
+		}
+		if limited > 0 {
+			res.Limited += limited
+			senders[sendID] = limited
+		}
+	}
+	res.Senders = int64(len(senders))
+	res.TopSenders = topSenders(senders)
+	return res
+}
+
+// topSenders returns the TopSenderNum senders with the highest count.
+func topSenders(counts map[string]int64) []*SenderCount {
+	res := make([]*SenderCount, 0, len(counts))
+	for sendID, count := range counts {
+		res = append(res, &SenderCount{SendID: sendID, Count: count})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count == res[j].Count {
+			return res[i].SendID < res[j].SendID
+		}
+		return res[i].Count > res[j].Count
+	})
+	if len(res) > TopSenderNum {
+		res = res[:TopSenderNum]
+	}
+	return res
+}
+
+// Covered returns the number of msgs sent in one of conversationIDs.
+func Covered(conversationIDs []string, msgs []*Msg) int64 {
+	set := make(map[string]struct{}, len(conversationIDs))
+	for _, conversationID := range conversationIDs {
+		set[conversationID] = struct{}{}
+	}
+	var n int64
+	for _, msg := range msgs {
+		if _, ok := set[msg.ConversationID]; ok {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,59 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policysim
+
+import "testing"
+
+func TestWordFilter(t *testing.T) {
+	msgs := []*Msg{
+		{ConversationID: "c1", SendID: "u1", Text: "Buy CHEAP watches"},
+		{ConversationID: "c1", SendID: "u1", Text: "cheap and spam"},
+		{ConversationID: "c2", SendID: "u2", Text: "hello"},
+		{ConversationID: "c2", SendID: "u3"},
+	}
+	res := WordFilter([]string{"cheap", "spam", ""}, msgs)
+	if res.Matched != 2 || res.Words["cheap"] != 2 || res.Words["spam"] != 1 {
+		t.Errorf("matched = %d, words = %v", res.Matched, res.Words)
+	}
+	if res.Senders != 1 || res.Conversations != 1 {
+		t.Errorf("senders = %d, conversations = %d", res.Senders, res.Conversations)
+	}
+	if len(res.TopSenders) != 1 || res.TopSenders[0].SendID != "u1" || res.TopSenders[0].Count != 2 {
+		t.Errorf("top senders = %v", res.TopSenders)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	var msgs []*Msg
+	for _, sendTime := range []int64{0, 100, 200, 300, 1200, 1250} {
+		msgs = append(msgs, &Msg{SendID: "u1", SendTime: sendTime})
+	}
+	msgs = append(msgs, &Msg{SendID: "u2", SendTime: 0}, &Msg{SendID: "u2", SendTime: 2000})
+	res := RateLimit(2, 1000, msgs)
+	// u1 is allowed at 0 and 100, rejected at 200 and 300, and allowed again at 1200 and 1250.
+	if res.Limited != 2 || res.Senders != 1 {
+		t.Errorf("limited = %d, senders = %d", res.Limited, res.Senders)
+	}
+	if res := RateLimit(0, 1000, msgs); res.Limited != 0 {
+		t.Errorf("limited = %d with no limit", res.Limited)
+	}
+}
+
+func TestCovered(t *testing.T) {
+	msgs := []*Msg{{ConversationID: "c1"}, {ConversationID: "c2"}, {ConversationID: "c1"}}
+	if n := Covered([]string{"c1", "c3"}, msgs); n != 2 {
+		t.Errorf("covered = %d", n)
+	}
+}
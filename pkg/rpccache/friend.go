@@ -21,6 +21,7 @@ import (
 	"github.com/openimsdk/localcache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/cachekey"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	"github.com/redis/go-redis/v9"
 )
@@ -39,7 +40,7 @@ func NewFriendLocalCache(client rpcclient.FriendRpcClient, cli redis.UniversalCl
 		),
 	}
 	if lc.Enable() {
-		go subscriberRedisDeleteCache(context.Background(), cli, lc.Topic, x.local.DelLocal)
+		go cache.SubscribeDeleteCache(context.Background(), cli, lc.Topic, x.local.DelLocal)
 	}
 	return x
 }
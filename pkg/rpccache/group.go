@@ -23,13 +23,19 @@ import (
 	"github.com/openimsdk/localcache"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/cachekey"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
 	"github.com/openimsdk/open-im-server/v3/pkg/rpcclient"
 	"github.com/redis/go-redis/v9"
 )
 
-func NewGroupLocalCache(client rpcclient.GroupRpcClient, cli redis.UniversalClient) *GroupLocalCache {
+// hotSlotNum is the number of slots of the hot tier, which holds two keys per hot group.
+const hotSlotNum = 16
+
+// NewGroupLocalCache creates the group local cache. With hot enabled the info and member ids of busy groups are
+// kept in a separate tier for hot.Expire seconds, it relies on the local cache topic to drop changed entries.
+func NewGroupLocalCache(client rpcclient.GroupRpcClient, cli redis.UniversalClient, hot config.HotConversation) *GroupLocalCache {
 	lc := config.Config.LocalCache.Group
-	log.ZDebug(context.Background(), "GroupLocalCache", "topic", lc.Topic, "slotNum", lc.SlotNum, "slotSize", lc.SlotSize, "enable", lc.Enable())
+	log.ZDebug(context.Background(), "GroupLocalCache", "topic", lc.Topic, "slotNum", lc.SlotNum, "slotSize", lc.SlotSize, "enable", lc.Enable(), "hot", hot.Enable)
 	x := &GroupLocalCache{
 		client: client,
 		local: localcache.New[any](
@@ -40,15 +46,59 @@ func NewGroupLocalCache(client rpcclient.GroupRpcClient, cli redis.UniversalClie
 			localcache.WithLocalFailedTTL(lc.Failed()),
 		),
 	}
+	if lc.Enable() && hot.Enable {
+		x.hotLocal = localcache.New[any](
+			localcache.WithLocalSlotNum(hotSlotNum),
+			localcache.WithLocalSlotSize(hot.MaxNum*2/hotSlotNum+1),
+			localcache.WithLinkSlotNum(hotSlotNum),
+			localcache.WithLocalSuccessTTL(hot.ExpireDuration()),
+			localcache.WithLocalFailedTTL(lc.Failed()),
+		)
+		x.hot = newHotDetector(hot.Threshold, hot.WindowDuration(), hot.ExpireDuration(), hot.MaxNum, func(groupID string) {
+			x.hotLocal.DelLocal(context.Background(), cachekey.GetGroupInfoKey(groupID), cachekey.GetGroupMemberIDsKey(groupID))
+		})
+	}
 	if lc.Enable() {
-		go subscriberRedisDeleteCache(context.Background(), cli, lc.Topic, x.local.DelLocal)
+		go cache.SubscribeDeleteCache(context.Background(), cli, lc.Topic, x.delLocal)
 	}
 	return x
 }
 
 type GroupLocalCache struct {
-	client rpcclient.GroupRpcClient
-	local  localcache.Cache[any]
+	client   rpcclient.GroupRpcClient
+	local    localcache.Cache[any]
+	hotLocal localcache.Cache[any]
+	hot      *hotDetector
+}
+
+func (g *GroupLocalCache) delLocal(ctx context.Context, keys ...string) {
+	g.local.DelLocal(ctx, keys...)
+	if g.hotLocal != nil {
+		g.hotLocal.DelLocal(ctx, keys...)
+	}
+}
+
+// cache returns the tier that holds the entries of the group.
+func (g *GroupLocalCache) cache(groupID string) localcache.Cache[any] {
+	if g.hot != nil && g.hot.IsHot(groupID) {
+		return g.hotLocal
+	}
+	return g.local
+}
+
+// RecordMsg counts a message sent to the group. When the group becomes hot its info and member ids are loaded
+// into the hot tier right away, so that the fan-out of the following messages finds them there.
+func (g *GroupLocalCache) RecordMsg(ctx context.Context, groupID string) {
+	if g.hot == nil || !g.hot.Record(groupID) {
+		return
+	}
+	log.ZInfo(ctx, "group promoted to hot local cache", "groupID", groupID)
+	if _, err := g.GetGroupInfo(ctx, groupID); err != nil {
+		log.ZWarn(ctx, "warm up hot group info failed", err, "groupID", groupID)
+	}
+	if _, err := g.getGroupMemberIDs(ctx, groupID); err != nil {
+		log.ZWarn(ctx, "warm up hot group member ids failed", err, "groupID", groupID)
+	}
 }
 
 func (g *GroupLocalCache) getGroupMemberIDs(ctx context.Context, groupID string) (val *listMap[string], err error) {
@@ -60,7 +110,7 @@ func (g *GroupLocalCache) getGroupMemberIDs(ctx context.Context, groupID string)
 			log.ZError(ctx, "GroupLocalCache getGroupMemberIDs return", err)
 		}
 	}()
-	return localcache.AnyValue[*listMap[string]](g.local.Get(ctx, cachekey.GetGroupMemberIDsKey(groupID), func(ctx context.Context) (any, error) {
+	return localcache.AnyValue[*listMap[string]](g.cache(groupID).Get(ctx, cachekey.GetGroupMemberIDsKey(groupID), func(ctx context.Context) (any, error) {
 		log.ZDebug(ctx, "GroupLocalCache getGroupMemberIDs rpc", "groupID", groupID)
 		return newListMap(g.client.GetGroupMemberIDs(ctx, groupID))
 	}))
@@ -90,7 +140,7 @@ func (g *GroupLocalCache) GetGroupInfo(ctx context.Context, groupID string) (val
 			log.ZError(ctx, "GroupLocalCache GetGroupInfo return", err)
 		}
 	}()
-	return localcache.AnyValue[*sdkws.GroupInfo](g.local.Get(ctx, cachekey.GetGroupInfoKey(groupID), func(ctx context.Context) (any, error) {
+	return localcache.AnyValue[*sdkws.GroupInfo](g.cache(groupID).Get(ctx, cachekey.GetGroupInfoKey(groupID), func(ctx context.Context) (any, error) {
 		log.ZDebug(ctx, "GroupLocalCache GetGroupInfo rpc", "groupID", groupID)
 		return g.client.GetGroupInfoCache(ctx, groupID)
 	}))
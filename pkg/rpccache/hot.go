@@ -0,0 +1,110 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpccache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/prommetrics"
+)
+
+// hotDetector counts the messages of each conversation in fixed windows. A conversation that reaches threshold
+// messages within one window is promoted and stays hot until expire has passed since it last reached it.
+type hotDetector struct {
+	threshold int
+	window    time.Duration
+	expire    time.Duration
+	maxNum    int
+	onEvict   func(id string)
+
+	lock        sync.Mutex
+	windowStart time.Time
+	counts      map[string]int
+	hot         map[string]time.Time
+}
+
+func newHotDetector(threshold int, window time.Duration, expire time.Duration, maxNum int, onEvict func(id string)) *hotDetector {
+	return &hotDetector{
+		threshold:   threshold,
+		window:      window,
+		expire:      expire,
+		maxNum:      maxNum,
+		onEvict:     onEvict,
+		windowStart: time.Now(),
+		counts:      make(map[string]int),
+		hot:         make(map[string]time.Time),
+	}
+}
+
+// Record counts one message of the conversation and returns true when it makes the conversation hot.
+func (h *hotDetector) Record(id string) bool {
+	now := time.Now()
+	h.lock.Lock()
+	evicted := h.rotate(now)
+	h.counts[id]++
+	promoted := false
+	if h.counts[id] >= h.threshold {
+		if _, ok := h.hot[id]; ok {
+			h.hot[id] = now.Add(h.expire)
+		} else if len(h.hot) < h.maxNum {
+			h.hot[id] = now.Add(h.expire)
+			promoted = true
+		}
+	}
+	num := len(h.hot)
+	h.lock.Unlock()
+	h.evicted(evicted)
+	if promoted {
+		prommetrics.HotConversationPromotedCounter.Inc()
+		prommetrics.HotConversationGauge.Set(float64(num))
+	}
+	return promoted
+}
+
+func (h *hotDetector) IsHot(id string) bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	expireAt, ok := h.hot[id]
+	return ok && time.Now().Before(expireAt)
+}
+
+// rotate starts a new window once the current one is over and returns the conversations that expired, the
+// caller holds the lock.
+func (h *hotDetector) rotate(now time.Time) []string {
+	if now.Sub(h.windowStart) < h.window {
+		return nil
+	}
+	h.windowStart = now
+	h.counts = make(map[string]int)
+	var evicted []string
+	for id, expireAt := range h.hot {
+		if !now.Before(expireAt) {
+			delete(h.hot, id)
+			evicted = append(evicted, id)
+		}
+	}
+	if len(evicted) > 0 {
+		prommetrics.HotConversationGauge.Set(float64(len(h.hot)))
+	}
+	return evicted
+}
+
+func (h *hotDetector) evicted(ids []string) {
+	for _, id := range ids {
+		prommetrics.HotConversationEvictedCounter.Inc()
+		h.onEvict(id)
+	}
+}
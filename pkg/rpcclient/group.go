@@ -207,6 +207,20 @@ func (g *GroupRpcClient) DismissGroup(ctx context.Context, groupID string) error
 	return err
 }
 
+func (g *GroupRpcClient) TransferGroupOwner(ctx context.Context, groupID string, oldOwnerUserID string, newOwnerUserID string) error {
+	_, err := g.Client.TransferGroupOwner(ctx, &group.TransferGroupOwnerReq{
+		GroupID:        groupID,
+		OldOwnerUserID: oldOwnerUserID,
+		NewOwnerUserID: newOwnerUserID,
+	})
+	return err
+}
+
+func (g *GroupRpcClient) MuteGroup(ctx context.Context, groupID string) error {
+	_, err := g.Client.MuteGroup(ctx, &group.MuteGroupReq{GroupID: groupID})
+	return err
+}
+
 func (g *GroupRpcClient) NotificationUserInfoUpdate(ctx context.Context, userID string) error {
 	_, err := g.Client.NotificationUserInfoUpdate(ctx, &group.NotificationUserInfoUpdateReq{
 		UserID: userID,
@@ -27,6 +27,8 @@ import (
 	"github.com/OpenIMSDK/tools/log"
 	"github.com/OpenIMSDK/tools/utils"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/msgext"
+	"github.com/openimsdk/open-im-server/v3/pkg/notificationcatalog"
 	util "github.com/openimsdk/open-im-server/v3/pkg/util/genutil"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/proto"
@@ -129,6 +131,8 @@ func newSessionTypeConf() map[int32]int32 {
 type Message struct {
 	conn   grpc.ClientConnInterface
 	Client msg.MsgClient
+	// Ext calls the msg rpc methods the protocol module does not define.
+	Ext    *msgext.Client
 	discov discoveryregistry.SvcDiscoveryRegistry
 	Config *config.GlobalConfig
 }
@@ -139,7 +143,7 @@ func NewMessage(discov discoveryregistry.SvcDiscoveryRegistry, config *config.Gl
 		util.ExitWithError(err)
 	}
 	client := msg.NewMsgClient(conn)
-	return &Message{discov: discov, conn: conn, Client: client, Config: config}
+	return &Message{discov: discov, conn: conn, Client: client, Ext: msgext.NewClient(conn), Config: config}
 }
 
 type MessageRpcClient Message
@@ -259,6 +263,10 @@ func WithRpcGetUserName() NotificationOptions {
 }
 
 func (s *NotificationSender) NotificationWithSesstionType(ctx context.Context, sendID, recvID string, contentType, sesstionType int32, m proto.Message, opts ...NotificationOptions) (err error) {
+	// A payload the catalog does not describe is a server bug, it is reported but the notification is still sent.
+	if err := notificationcatalog.Validate(contentType, m); err != nil {
+		log.ZError(ctx, "notification does not match the catalog", err, "contentType", contentType)
+	}
 	n := sdkws.NotificationElem{Detail: utils.StructToJsonString(m)}
 	content, err := json.Marshal(&n)
 	if err != nil {
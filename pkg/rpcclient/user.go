@@ -25,6 +25,7 @@ import (
 	"github.com/OpenIMSDK/tools/utils"
 	"github.com/openimsdk/open-im-server/v3/pkg/authverify"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/userext"
 	util "github.com/openimsdk/open-im-server/v3/pkg/util/genutil"
 	"google.golang.org/grpc"
 )
@@ -33,6 +34,8 @@ import (
 type User struct {
 	conn   grpc.ClientConnInterface
 	Client user.UserClient
+	// Ext calls the user rpc methods the protocol module does not define.
+	Ext    *userext.Client
 	Discov discoveryregistry.SvcDiscoveryRegistry
 	Config *config.GlobalConfig
 }
@@ -44,7 +47,7 @@ func NewUser(discov discoveryregistry.SvcDiscoveryRegistry, config *config.Globa
 		util.ExitWithError(err)
 	}
 	client := user.NewUserClient(conn)
-	return &User{Discov: discov, Client: client, conn: conn, Config: config}
+	return &User{Discov: discov, Client: client, Ext: userext.NewClient(conn), conn: conn, Config: config}
 }
 
 // UserRpcClient represents the structure for a User RPC client.
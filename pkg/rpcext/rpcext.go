@@ -0,0 +1,75 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rpcext serves and calls the rpc methods the protocol module does not define. Like the Version service,
+// such methods carry the JSON of their requests and responses in a BytesValue.
+package rpcext
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Invoke calls the method name of serviceName with the JSON of req and decodes the JSON response.
+func Invoke[A, B any](ctx context.Context, conn grpc.ClientConnInterface, serviceName string, name string, req *A, opts ...grpc.CallOption) (*B, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	var out wrapperspb.BytesValue
+	if err := conn.Invoke(ctx, "/"+serviceName+"/"+name, wrapperspb.Bytes(data), &out, opts...); err != nil {
+		return nil, err
+	}
+	var resp B
+	if err := json.Unmarshal(out.Value, &resp); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return &resp, nil
+}
+
+// Method describes the method name of serviceName, it decodes the JSON request, calls it on the server of type S
+// and encodes its response.
+func Method[S, A, B any](serviceName string, name string, call func(srv S, ctx context.Context, req *A) (*B, error)) grpc.MethodDesc {
+	fullMethod := "/" + serviceName + "/" + name
+	handler := func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		in := new(wrapperspb.BytesValue)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		handle := func(ctx context.Context, req any) (any, error) {
+			var a A
+			if err := json.Unmarshal(req.(*wrapperspb.BytesValue).Value, &a); err != nil {
+				return nil, errs.ErrArgs.Wrap(err.Error())
+			}
+			b, err := call(srv.(S), ctx, &a)
+			if err != nil {
+				return nil, err
+			}
+			data, err := json.Marshal(b)
+			if err != nil {
+				return nil, errs.Wrap(err)
+			}
+			return wrapperspb.Bytes(data), nil
+		}
+		if interceptor == nil {
+			return handle(ctx, in)
+		}
+		return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}, handle)
+	}
+	return grpc.MethodDesc{MethodName: name, Handler: handler}
+}
@@ -0,0 +1,69 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rpcext
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type echoReq struct {
+	Text string `json:"text"`
+}
+
+type echoResp struct {
+	Text string `json:"text"`
+}
+
+type echoServer interface {
+	Echo(ctx context.Context, req *echoReq) (*echoResp, error)
+}
+
+type echo struct{}
+
+func (echo) Echo(_ context.Context, req *echoReq) (*echoResp, error) {
+	if req.Text == "" {
+		return nil, errs.ErrArgs.Wrap("text is empty")
+	}
+	return &echoResp{Text: req.Text + req.Text}, nil
+}
+
+func TestInvoke(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	s := grpc.NewServer()
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "test.Echo",
+		HandlerType: (*echoServer)(nil),
+		Methods:     []grpc.MethodDesc{Method("test.Echo", "Echo", echoServer.Echo)},
+	}, echo{})
+	go s.Serve(lis)
+	defer s.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+	resp, err := Invoke[echoReq, echoResp](context.Background(), conn, "test.Echo", "Echo", &echoReq{Text: "ab"})
+	assert.NoError(t, err)
+	assert.Equal(t, "abab", resp.Text)
+	_, err = Invoke[echoReq, echoResp](context.Background(), conn, "test.Echo", "Echo", &echoReq{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrRoomTokenInvalid = errors.New("room token invalid")
+	ErrRoomTokenExpired = errors.New("room token expired")
+)
+
+type roomGrant struct {
+	RoomID string `json:"r"`
+	UserID string `json:"u"`
+	Expire int64  `json:"e"`
+}
+
+// RoomToken grants userID to join roomID until expire, the media server checks it with ParseRoomToken.
+// The token is base64url(grant) "." base64url(HMAC-SHA256(secret, grant)).
+func RoomToken(secret string, roomID string, userID string, expire time.Time) string {
+	grant, _ := json.Marshal(&roomGrant{RoomID: roomID, UserID: userID, Expire: expire.Unix()})
+	payload := base64.RawURLEncoding.EncodeToString(grant)
+	return payload + "." + roomTokenSignature(secret, payload)
+}
+
+// ParseRoomToken returns the room and user granted by token.
+func ParseRoomToken(secret string, token string, now time.Time) (roomID string, userID string, err error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(roomTokenSignature(secret, payload))) {
+		return "", "", ErrRoomTokenInvalid
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", "", ErrRoomTokenInvalid
+	}
+	var grant roomGrant
+	if err := json.Unmarshal(data, &grant); err != nil {
+		return "", "", ErrRoomTokenInvalid
+	}
+	if now.Unix() > grant.Expire {
+		return "", "", ErrRoomTokenExpired
+	}
+	return grant.RoomID, grant.UserID, nil
+}
+
+func roomTokenSignature(secret string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,38 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRoomToken(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	token := RoomToken("secret", "room1", "user1", now.Add(time.Hour))
+	roomID, userID, err := ParseRoomToken("secret", token, now)
+	if err != nil || roomID != "room1" || userID != "user1" {
+		t.Fatalf("ParseRoomToken() = %s, %s, %v", roomID, userID, err)
+	}
+	if _, _, err := ParseRoomToken("other", token, now); err != ErrRoomTokenInvalid {
+		t.Errorf("wrong secret error = %v", err)
+	}
+	if _, _, err := ParseRoomToken("secret", token, now.Add(2*time.Hour)); err != ErrRoomTokenExpired {
+		t.Errorf("expired error = %v", err)
+	}
+	if _, _, err := ParseRoomToken("secret", "x"+token, now); err != ErrRoomTokenInvalid {
+		t.Errorf("tampered error = %v", err)
+	}
+}
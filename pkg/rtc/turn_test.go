@@ -0,0 +1,30 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rtc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTurnCredential(t *testing.T) {
+	username, credential := TurnCredential("secret", "user1", time.Unix(1700000000, 0))
+	if username != "1700000000:user1" {
+		t.Errorf("username = %s", username)
+	}
+	if credential != "gkgA4ano0Zn9R9Tq0ApCVdpOn/U=" {
+		t.Errorf("credential = %s", credential)
+	}
+}
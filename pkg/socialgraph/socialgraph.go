@@ -0,0 +1,242 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package socialgraph converts the friends and groups of a user to and from the exchange formats used to move
+// them between deployments, JSON and vCard 4.0 (RFC 6350).
+package socialgraph
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Version is the version of the JSON format.
+	Version = 1
+
+	FormatJSON  = "json"
+	FormatVCard = "vcard"
+
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+
+	userUIDPrefix  = "urn:openim:user:"
+	groupUIDPrefix = "urn:openim:group:"
+	// vcardLineLen is the number of octets after which a line is folded.
+	vcardLineLen = 75
+)
+
+type Graph struct {
+	Version    int       `json:"version"`
+	UserID     string    `json:"userID"`
+	ExportTime int64     `json:"exportTime"`
+	Friends    []*Friend `json:"friends"`
+	Groups     []*Group  `json:"groups"`
+}
+
+type Friend struct {
+	UserID   string `json:"userID"`
+	Nickname string `json:"nickname"`
+	Remark   string `json:"remark"`
+	AddTime  int64  `json:"addTime"`
+}
+
+type Group struct {
+	GroupID   string `json:"groupID"`
+	GroupName string `json:"groupName"`
+	Role      string `json:"role"`
+	JoinTime  int64  `json:"joinTime"`
+}
+
+// EncodeVCard writes one vCard per friend and one group vCard per group, the owner of the graph is not written.
+func EncodeVCard(graph *Graph) string {
+	var b strings.Builder
+	for _, friend := range graph.Friends {
+		writeCard(&b, [][2]string{
+			{"KIND", "individual"},
+			{"UID", userUIDPrefix + friend.UserID},
+			{"FN", escape(displayName(friend.Nickname, friend.UserID))},
+			{"NICKNAME", escape(friend.Remark)},
+			{"X-OPENIM-ADD-TIME", strconv.FormatInt(friend.AddTime, 10)},
+		})
+	}
+	for _, group := range graph.Groups {
+		writeCard(&b, [][2]string{
+			{"KIND", "group"},
+			{"UID", groupUIDPrefix + group.GroupID},
+			{"FN", escape(displayName(group.GroupName, group.GroupID))},
+			{"X-OPENIM-ROLE", group.Role},
+			{"X-OPENIM-JOIN-TIME", strconv.FormatInt(group.JoinTime, 10)},
+		})
+	}
+	return b.String()
+}
+
+// DecodeVCard reads the vCards written by EncodeVCard, cards without an OpenIM uid are skipped.
+func DecodeVCard(data string) (*Graph, error) {
+	graph := &Graph{Version: Version, Friends: []*Friend{}, Groups: []*Group{}}
+	lines, err := unfold(data)
+	if err != nil {
+		return nil, err
+	}
+	var card map[string]string
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("vcard line %d: missing colon", i+1)
+		}
+		// Parameters such as TYPE are not used.
+		name, _, _ = strings.Cut(strings.ToUpper(name), ";")
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCARD"):
+			card = make(map[string]string)
+		case name == "END" && strings.EqualFold(value, "VCARD"):
+			if card == nil {
+				return nil, fmt.Errorf("vcard line %d: END without BEGIN", i+1)
+			}
+			if err := addCard(graph, card); err != nil {
+				return nil, fmt.Errorf("vcard line %d: %w", i+1, err)
+			}
+			card = nil
+		case card != nil:
+			card[name] = value
+		}
+	}
+	if card != nil {
+		return nil, fmt.Errorf("vcard: missing END")
+	}
+	return graph, nil
+}
+
+func addCard(graph *Graph, card map[string]string) error {
+	uid := card["UID"]
+	switch {
+	case strings.HasPrefix(uid, userUIDPrefix):
+		addTime, err := parseTime(card["X-OPENIM-ADD-TIME"])
+		if err != nil {
+			return err
+		}
+		graph.Friends = append(graph.Friends, &Friend{
+			UserID:   strings.TrimPrefix(uid, userUIDPrefix),
+			Nickname: unescape(card["FN"]),
+			Remark:   unescape(card["NICKNAME"]),
+			AddTime:  addTime,
+		})
+	case strings.HasPrefix(uid, groupUIDPrefix):
+		joinTime, err := parseTime(card["X-OPENIM-JOIN-TIME"])
+		if err != nil {
+			return err
+		}
+		role := card["X-OPENIM-ROLE"]
+		if role == "" {
+			role = RoleMember
+		}
+		graph.Groups = append(graph.Groups, &Group{
+			GroupID:   strings.TrimPrefix(uid, groupUIDPrefix),
+			GroupName: unescape(card["FN"]),
+			Role:      role,
+			JoinTime:  joinTime,
+		})
+	}
+	return nil
+}
+
+func parseTime(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	t, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return t, nil
+}
+
+func displayName(name string, id string) string {
+	if name == "" {
+		return id
+	}
+	return name
+}
+
+func writeCard(b *strings.Builder, props [][2]string) {
+	b.WriteString("BEGIN:VCARD\r\nVERSION:4.0\r\n")
+	for _, prop := range props {
+		if prop[1] == "" {
+			continue
+		}
+		fold(b, prop[0]+":"+prop[1])
+	}
+	b.WriteString("END:VCARD\r\n")
+}
+
+// fold splits line into lines of at most vcardLineLen octets, continuation lines start with a space. Lines are
+// only split between runes.
+func fold(b *strings.Builder, line string) {
+	limit := vcardLineLen
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space counts against the limit of the continuation line.
+		limit = vcardLineLen - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
+
+func unfold(data string) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("vcard: %w", err)
+	}
+	return lines, nil
+}
+
+var (
+	escaper   = strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`)
+	unescaper = strings.NewReplacer(`\\`, `\`, `\,`, ",", `\;`, ";", `\n`, "\n", `\N`, "\n")
+)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+func unescape(s string) string {
+	return unescaper.Replace(s)
+}
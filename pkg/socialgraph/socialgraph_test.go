@@ -0,0 +1,65 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package socialgraph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVCardRoundTrip(t *testing.T) {
+	graph := &Graph{
+		Version: Version,
+		Friends: []*Friend{
+			{UserID: "u1", Nickname: "Alice, A.", Remark: "work; team\nlead", AddTime: 1700000000000},
+			{UserID: "u2", Nickname: strings.Repeat("长名字", 20)},
+		},
+		Groups: []*Group{
+			{GroupID: "g1", GroupName: "Book club", Role: RoleAdmin, JoinTime: 1700000001000},
+		},
+	}
+	data := EncodeVCard(graph)
+	for _, line := range strings.Split(data, "\r\n") {
+		if len(line) > vcardLineLen {
+			t.Fatalf("line longer than %d octets: %q", vcardLineLen, line)
+		}
+	}
+	got, err := DecodeVCard(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, graph) {
+		t.Fatalf("round trip = %+v, want %+v", got, graph)
+	}
+}
+
+func TestDecodeVCard(t *testing.T) {
+	data := "BEGIN:VCARD\nVERSION:4.0\nFN:Not from OpenIM\nEND:VCARD\n" +
+		"BEGIN:VCARD\nVERSION:4.0\nUID:urn:openim:group:g2\nFN;CHARSET=UTF-8:Team\nEND:VCARD\n"
+	graph, err := DecodeVCard(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graph.Friends) != 0 || len(graph.Groups) != 1 {
+		t.Fatalf("friends %d groups %d, want 0 and 1", len(graph.Friends), len(graph.Groups))
+	}
+	if group := graph.Groups[0]; group.GroupID != "g2" || group.GroupName != "Team" || group.Role != RoleMember {
+		t.Fatalf("group = %+v", group)
+	}
+	if _, err := DecodeVCard("BEGIN:VCARD\nUID:urn:openim:user:u1\n"); err == nil {
+		t.Fatal("expected an error for a card without END")
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package testkit provides fixtures for integration tests against OpenIM.
+//
+// Registry is an in-process discovery registry over bufconn listeners, MsgCache an in-memory cache.MsgModel
+// and Queue an in-memory stand-in for the kafka topics, see controller.NewCommonMsgDatabaseWithProducers.
+// StartRPC and StartAPI boot the services in process on a Registry, the services still open their own
+// mongo and redis connections from config, so those must be reachable.
+package testkit
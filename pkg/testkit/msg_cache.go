@@ -0,0 +1,607 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/OpenIMSDK/protocol/constant"
+	"github.com/OpenIMSDK/protocol/sdkws"
+	"github.com/OpenIMSDK/tools/errs"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+)
+
+var _ cache.MsgModel = (*MsgCache)(nil)
+
+// MsgCache is an in-memory cache.MsgModel. Missing keys report redis.Nil like the Redis implementation,
+// expirations and the size of the latest messages are ignored.
+type MsgCache struct {
+	lock    sync.Mutex
+	values  map[string]string
+	hashes  map[string]map[string]string
+	sets    map[string]map[int64]map[string]struct{}
+	msgs    map[string]map[int64]*sdkws.MsgData
+	recent  map[string]map[int64]*sdkws.MsgData
+	zsets   map[string]map[string]int64
+	latency []int64
+}
+
+func NewMsgCache() *MsgCache {
+	return &MsgCache{
+		values: make(map[string]string),
+		hashes: make(map[string]map[string]string),
+		sets:   make(map[string]map[int64]map[string]struct{}),
+		msgs:   make(map[string]map[int64]*sdkws.MsgData),
+		recent: make(map[string]map[int64]*sdkws.MsgData),
+		zsets:  make(map[string]map[string]int64),
+	}
+}
+
+func (m *MsgCache) set(key string, value any) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	switch v := value.(type) {
+	case string:
+		m.values[key] = v
+	case int64:
+		m.values[key] = strconv.FormatInt(v, 10)
+	case int:
+		m.values[key] = strconv.Itoa(v)
+	}
+}
+
+func (m *MsgCache) get(key string) (string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	val, ok := m.values[key]
+	if !ok {
+		return "", errs.Wrap(redis.Nil)
+	}
+	return val, nil
+}
+
+func (m *MsgCache) getInt64(key string) (int64, error) {
+	val, err := m.get(key)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(val, 10, 64)
+}
+
+func (m *MsgCache) del(key string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.values, key)
+}
+
+func (m *MsgCache) getInt64s(keys []string, keyFn func(key string) string) map[string]int64 {
+	res := make(map[string]int64, len(keys))
+	for _, key := range keys {
+		if val, err := m.getInt64(keyFn(key)); err == nil && val != 0 {
+			res[key] = val
+		}
+	}
+	return res
+}
+
+func (m *MsgCache) setInt64s(seqs map[string]int64, keyFn func(key string) string) {
+	for key, seq := range seqs {
+		m.set(keyFn(key), seq)
+	}
+}
+
+func (m *MsgCache) hash(key string) map[string]string {
+	h, ok := m.hashes[key]
+	if !ok {
+		h = make(map[string]string)
+		m.hashes[key] = h
+	}
+	return h
+}
+
+func maxSeqKey(conversationID string) string { return "MAX_SEQ:" + conversationID }
+
+func minSeqKey(conversationID string) string { return "MIN_SEQ:" + conversationID }
+
+func userMinSeqKey(conversationID, userID string) string {
+	return "CON_USER_MIN_SEQ:" + conversationID + "u:" + userID
+}
+
+func hasReadSeqKey(conversationID, userID string) string {
+	return "HAS_READ_SEQ:" + userID + ":" + conversationID
+}
+
+func tokenKey(userID string, platformID int) string {
+	return "UID_PID_TOKEN_STATUS:" + userID + ":" + constant.PlatformIDToName(platformID)
+}
+
+func reactionKey(clientMsgID string, sessionType int32) string {
+	return "EX:" + strconv.Itoa(int(sessionType)) + ":" + clientMsgID
+}
+
+func (m *MsgCache) SetMaxSeq(ctx context.Context, conversationID string, maxSeq int64) error {
+	m.set(maxSeqKey(conversationID), maxSeq)
+	return nil
+}
+
+func (m *MsgCache) GetMaxSeqs(ctx context.Context, conversationIDs []string) (map[string]int64, error) {
+	return m.getInt64s(conversationIDs, maxSeqKey), nil
+}
+
+func (m *MsgCache) GetMaxSeq(ctx context.Context, conversationID string) (int64, error) {
+	return m.getInt64(maxSeqKey(conversationID))
+}
+
+func (m *MsgCache) SetMinSeq(ctx context.Context, conversationID string, minSeq int64) error {
+	m.set(minSeqKey(conversationID), minSeq)
+	return nil
+}
+
+func (m *MsgCache) SetMinSeqs(ctx context.Context, seqs map[string]int64) error {
+	m.setInt64s(seqs, minSeqKey)
+	return nil
+}
+
+func (m *MsgCache) GetMinSeqs(ctx context.Context, conversationIDs []string) (map[string]int64, error) {
+	return m.getInt64s(conversationIDs, minSeqKey), nil
+}
+
+func (m *MsgCache) GetMinSeq(ctx context.Context, conversationID string) (int64, error) {
+	return m.getInt64(minSeqKey(conversationID))
+}
+
+func (m *MsgCache) GetConversationUserMinSeq(ctx context.Context, conversationID string, userID string) (int64, error) {
+	return m.getInt64(userMinSeqKey(conversationID, userID))
+}
+
+func (m *MsgCache) GetConversationUserMinSeqs(ctx context.Context, conversationID string, userIDs []string) (map[string]int64, error) {
+	return m.getInt64s(userIDs, func(userID string) string { return userMinSeqKey(conversationID, userID) }), nil
+}
+
+func (m *MsgCache) SetConversationUserMinSeq(ctx context.Context, conversationID string, userID string, minSeq int64) error {
+	m.set(userMinSeqKey(conversationID, userID), minSeq)
+	return nil
+}
+
+func (m *MsgCache) SetConversationUserMinSeqs(ctx context.Context, conversationID string, seqs map[string]int64) error {
+	m.setInt64s(seqs, func(userID string) string { return userMinSeqKey(conversationID, userID) })
+	return nil
+}
+
+func (m *MsgCache) SetUserConversationsMinSeqs(ctx context.Context, userID string, seqs map[string]int64) error {
+	m.setInt64s(seqs, func(conversationID string) string { return userMinSeqKey(conversationID, userID) })
+	return nil
+}
+
+func (m *MsgCache) SetHasReadSeq(ctx context.Context, userID string, conversationID string, hasReadSeq int64) error {
+	m.set(hasReadSeqKey(conversationID, userID), hasReadSeq)
+	return nil
+}
+
+func (m *MsgCache) SetHasReadSeqs(ctx context.Context, conversationID string, hasReadSeqs map[string]int64) error {
+	m.setInt64s(hasReadSeqs, func(userID string) string { return hasReadSeqKey(conversationID, userID) })
+	return nil
+}
+
+func (m *MsgCache) UserSetHasReadSeqs(ctx context.Context, userID string, hasReadSeqs map[string]int64) error {
+	m.setInt64s(hasReadSeqs, func(conversationID string) string { return hasReadSeqKey(conversationID, userID) })
+	return nil
+}
+
+func (m *MsgCache) GetHasReadSeqs(ctx context.Context, userID string, conversationIDs []string) (map[string]int64, error) {
+	return m.getInt64s(conversationIDs, func(conversationID string) string { return hasReadSeqKey(conversationID, userID) }), nil
+}
+
+func (m *MsgCache) GetHasReadSeq(ctx context.Context, userID string, conversationID string) (int64, error) {
+	return m.getInt64(hasReadSeqKey(conversationID, userID))
+}
+
+func (m *MsgCache) SetGroupMemberReadSeqs(ctx context.Context, conversationID string, hasReadSeqs map[string]int64) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.zsets[conversationID] == nil {
+		m.zsets[conversationID] = make(map[string]int64)
+	}
+	for userID, seq := range hasReadSeqs {
+		if seq > m.zsets[conversationID][userID] {
+			m.zsets[conversationID][userID] = seq
+		}
+	}
+	return nil
+}
+
+func (m *MsgCache) GetGroupReadMembers(ctx context.Context, conversationID string, seq int64) ([]string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var userIDs []string
+	for userID, readSeq := range m.zsets[conversationID] {
+		if readSeq >= seq {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	sort.Strings(userIDs)
+	return userIDs, nil
+}
+
+func (m *MsgCache) SetFcmToken(ctx context.Context, account string, platformID int, fcmToken string, expireTime int64) error {
+	m.set("FCM_TOKEN:"+account+":"+strconv.Itoa(platformID), fcmToken)
+	return nil
+}
+
+func (m *MsgCache) GetFcmToken(ctx context.Context, account string, platformID int) (string, error) {
+	return m.get("FCM_TOKEN:" + account + ":" + strconv.Itoa(platformID))
+}
+
+func (m *MsgCache) DelFcmToken(ctx context.Context, account string, platformID int) error {
+	m.del("FCM_TOKEN:" + account + ":" + strconv.Itoa(platformID))
+	return nil
+}
+
+func (m *MsgCache) IncrUserBadgeUnreadCountSum(ctx context.Context, userID string) (int, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	key := "USER_BADGE_UNREAD_COUNT_SUM:" + userID
+	val, _ := strconv.Atoi(m.values[key])
+	val++
+	m.values[key] = strconv.Itoa(val)
+	return val, nil
+}
+
+func (m *MsgCache) SetUserBadgeUnreadCountSum(ctx context.Context, userID string, value int) error {
+	m.set("USER_BADGE_UNREAD_COUNT_SUM:"+userID, value)
+	return nil
+}
+
+func (m *MsgCache) GetUserBadgeUnreadCountSum(ctx context.Context, userID string) (int, error) {
+	val, err := m.getInt64("USER_BADGE_UNREAD_COUNT_SUM:" + userID)
+	return int(val), err
+}
+
+func (m *MsgCache) SetGetuiToken(ctx context.Context, token string, expireTime int64) error {
+	m.set("GETUI_TOKEN", token)
+	return nil
+}
+
+func (m *MsgCache) GetGetuiToken(ctx context.Context) (string, error) {
+	return m.get("GETUI_TOKEN")
+}
+
+func (m *MsgCache) SetGetuiTaskID(ctx context.Context, taskID string, expireTime int64) error {
+	m.set("GETUI_TASK_ID", taskID)
+	return nil
+}
+
+func (m *MsgCache) GetGetuiTaskID(ctx context.Context) (string, error) {
+	return m.get("GETUI_TASK_ID")
+}
+
+func (m *MsgCache) SetHmsToken(ctx context.Context, token string, expireTime int64) error {
+	m.set("HMS_TOKEN", token)
+	return nil
+}
+
+func (m *MsgCache) GetHmsToken(ctx context.Context) (string, error) {
+	return m.get("HMS_TOKEN")
+}
+
+func (m *MsgCache) DelHmsToken(ctx context.Context) error {
+	m.del("HMS_TOKEN")
+	return nil
+}
+
+func (m *MsgCache) AddDeliveryLatency(ctx context.Context, latency int64) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.latency = append([]int64{latency}, m.latency...)
+	if len(m.latency) > 1000 {
+		m.latency = m.latency[:1000]
+	}
+	return nil
+}
+
+func (m *MsgCache) GetDeliveryLatencies(ctx context.Context) ([]int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]int64(nil), m.latency...), nil
+}
+
+func (m *MsgCache) AddTokenFlag(ctx context.Context, userID string, platformID int, token string, flag int) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.hash(tokenKey(userID, platformID))[token] = strconv.Itoa(flag)
+	return nil
+}
+
+func (m *MsgCache) GetTokensWithoutError(ctx context.Context, userID string, platformID int) (map[string]int, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	tokens := make(map[string]int)
+	for token, flag := range m.hashes[tokenKey(userID, platformID)] {
+		tokens[token], _ = strconv.Atoi(flag)
+	}
+	return tokens, nil
+}
+
+func (m *MsgCache) SetTokenMapByUidPid(ctx context.Context, userID string, platformID int, tokens map[string]int) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	h := m.hash(tokenKey(userID, platformID))
+	for token, flag := range tokens {
+		h[token] = strconv.Itoa(flag)
+	}
+	return nil
+}
+
+func (m *MsgCache) DeleteTokenByUidPid(ctx context.Context, userID string, platformID int, fields []string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	h := m.hash(tokenKey(userID, platformID))
+	for _, field := range fields {
+		delete(h, field)
+	}
+	return nil
+}
+
+func (m *MsgCache) SetLoginPolicyMatrix(ctx context.Context, matrix string) error {
+	m.set("LOGIN_POLICY_MATRIX", matrix)
+	return nil
+}
+
+func (m *MsgCache) GetLoginPolicyMatrix(ctx context.Context) (string, error) {
+	return m.get("LOGIN_POLICY_MATRIX")
+}
+
+func (m *MsgCache) SetTokenFingerprint(ctx context.Context, token string, fingerprint string, expiration time.Duration) error {
+	m.set("TOKEN_FINGERPRINT:"+token, fingerprint)
+	return nil
+}
+
+func (m *MsgCache) GetTokenFingerprint(ctx context.Context, token string) (string, error) {
+	return m.get("TOKEN_FINGERPRINT:" + token)
+}
+
+func (m *MsgCache) SetTokenSession(ctx context.Context, token string, fields map[string]string, expiration time.Duration) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	h := m.hash("TOKEN_SESSION:" + token)
+	for field, value := range fields {
+		h[field] = value
+	}
+	return nil
+}
+
+func (m *MsgCache) GetTokenSessions(ctx context.Context, tokens []string) (map[string]map[string]string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	sessions := make(map[string]map[string]string)
+	for _, token := range tokens {
+		h := m.hashes["TOKEN_SESSION:"+token]
+		if len(h) == 0 {
+			continue
+		}
+		fields := make(map[string]string, len(h))
+		for field, value := range h {
+			fields[field] = value
+		}
+		sessions[token] = fields
+	}
+	return sessions, nil
+}
+
+func (m *MsgCache) DelTokenSession(ctx context.Context, token string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.hashes, "TOKEN_SESSION:"+token)
+	return nil
+}
+
+func (m *MsgCache) GetMessagesBySeq(ctx context.Context, conversationID string, seqs []int64) ([]*sdkws.MsgData, []int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var (
+		seqMsgs    []*sdkws.MsgData
+		failedSeqs []int64
+	)
+	for _, seq := range seqs {
+		msg, ok := m.msgs[conversationID][seq]
+		if !ok || msg.Status == constant.MsgDeleted {
+			failedSeqs = append(failedSeqs, seq)
+			continue
+		}
+		seqMsgs = append(seqMsgs, proto.Clone(msg).(*sdkws.MsgData))
+	}
+	return seqMsgs, failedSeqs, nil
+}
+
+func (m *MsgCache) SetMessageToCache(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) (int, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.msgs[conversationID] == nil {
+		m.msgs[conversationID] = make(map[int64]*sdkws.MsgData)
+	}
+	for _, msg := range msgs {
+		m.msgs[conversationID][msg.Seq] = proto.Clone(msg).(*sdkws.MsgData)
+	}
+	return len(msgs), nil
+}
+
+func (m *MsgCache) AddRecentMessages(ctx context.Context, conversationID string, msgs []*sdkws.MsgData) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.recent[conversationID] == nil {
+		m.recent[conversationID] = make(map[int64]*sdkws.MsgData)
+	}
+	for _, msg := range msgs {
+		m.recent[conversationID][msg.Seq] = proto.Clone(msg).(*sdkws.MsgData)
+	}
+	return nil
+}
+
+func (m *MsgCache) GetRecentMessages(ctx context.Context, conversationID string, begin, end int64) ([]*sdkws.MsgData, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var msgs []*sdkws.MsgData
+	for seq, msg := range m.recent[conversationID] {
+		if seq >= begin && seq <= end {
+			msgs = append(msgs, proto.Clone(msg).(*sdkws.MsgData))
+		}
+	}
+	sort.Slice(msgs, func(i, j int) bool { return msgs[i].Seq < msgs[j].Seq })
+	return msgs, nil
+}
+
+func (m *MsgCache) UserDeleteMsgs(ctx context.Context, conversationID string, seqs []int64, userID string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.sets[conversationID] == nil {
+		m.sets[conversationID] = make(map[int64]map[string]struct{})
+	}
+	for _, seq := range seqs {
+		if m.sets[conversationID][seq] == nil {
+			m.sets[conversationID][seq] = make(map[string]struct{})
+		}
+		m.sets[conversationID][seq][userID] = struct{}{}
+	}
+	return nil
+}
+
+func (m *MsgCache) DelUserDeleteMsgsList(ctx context.Context, conversationID string, seqs []int64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, seq := range seqs {
+		delete(m.sets[conversationID], seq)
+	}
+}
+
+func (m *MsgCache) DeleteMessages(ctx context.Context, conversationID string, seqs []int64) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, seq := range seqs {
+		delete(m.msgs[conversationID], seq)
+		delete(m.recent[conversationID], seq)
+	}
+	return nil
+}
+
+func (m *MsgCache) GetUserDelList(ctx context.Context, userID, conversationID string) ([]int64, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var seqs []int64
+	for seq, users := range m.sets[conversationID] {
+		if _, ok := users[userID]; ok {
+			seqs = append(seqs, seq)
+		}
+	}
+	return seqs, nil
+}
+
+func (m *MsgCache) CleanUpOneConversationAllMsg(ctx context.Context, conversationID string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.msgs, conversationID)
+	delete(m.recent, conversationID)
+	return nil
+}
+
+func (m *MsgCache) DelMsgFromCache(ctx context.Context, conversationID string, seqs []int64) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, seq := range seqs {
+		if msg, ok := m.msgs[conversationID][seq]; ok {
+			msg.Status = constant.MsgDeleted
+		}
+		delete(m.recent[conversationID], seq)
+	}
+	return nil
+}
+
+func (m *MsgCache) SetSendMsgStatus(ctx context.Context, id string, status int32) error {
+	m.set("SEND_MSG_FAILED_FLAG:"+id, int(status))
+	return nil
+}
+
+func (m *MsgCache) GetSendMsgStatus(ctx context.Context, id string) (int32, error) {
+	val, err := m.getInt64("SEND_MSG_FAILED_FLAG:" + id)
+	return int32(val), err
+}
+
+func (m *MsgCache) JudgeMessageReactionExist(ctx context.Context, clientMsgID string, sessionType int32) (bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	_, ok := m.hashes[reactionKey(clientMsgID, sessionType)]
+	return ok, nil
+}
+
+func (m *MsgCache) GetOneMessageAllReactionList(ctx context.Context, clientMsgID string, sessionType int32) (map[string]string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	res := make(map[string]string)
+	for k, v := range m.hashes[reactionKey(clientMsgID, sessionType)] {
+		res[k] = v
+	}
+	return res, nil
+}
+
+func (m *MsgCache) DeleteOneMessageKey(ctx context.Context, clientMsgID string, sessionType int32, subKey string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.hashes[reactionKey(clientMsgID, sessionType)], subKey)
+	return nil
+}
+
+func (m *MsgCache) SetMessageReactionExpire(ctx context.Context, clientMsgID string, sessionType int32, expiration time.Duration) (bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	_, ok := m.hashes[reactionKey(clientMsgID, sessionType)]
+	return ok, nil
+}
+
+func (m *MsgCache) GetMessageTypeKeyValue(ctx context.Context, clientMsgID string, sessionType int32, typeKey string) (string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	val, ok := m.hashes[reactionKey(clientMsgID, sessionType)][typeKey]
+	if !ok {
+		return "", errs.Wrap(redis.Nil)
+	}
+	return val, nil
+}
+
+func (m *MsgCache) SetMessageTypeKeyValue(ctx context.Context, clientMsgID string, sessionType int32, typeKey, value string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.hash(reactionKey(clientMsgID, sessionType))[typeKey] = value
+	return nil
+}
+
+func (m *MsgCache) LockMessageTypeKey(ctx context.Context, clientMsgID string, typeKey string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	key := "EX_LOCK:" + clientMsgID + "_" + typeKey
+	if _, ok := m.values[key]; !ok {
+		m.values[key] = "1"
+	}
+	return nil
+}
+
+func (m *MsgCache) UnLockMessageTypeKey(ctx context.Context, clientMsgID string, typeKey string) error {
+	m.del("EX_LOCK:" + clientMsgID + "_" + typeKey)
+	return nil
+}
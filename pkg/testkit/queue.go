@@ -0,0 +1,89 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit
+
+import (
+	"context"
+	"sync"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mq"
+	"google.golang.org/protobuf/proto"
+)
+
+// QueueMessage is a message recorded by Queue.
+type QueueMessage struct {
+	Topic     string
+	Key       string
+	Partition int32
+	Offset    int64
+	Msg       proto.Message
+}
+
+// QueueHandler consumes a message sent to a subscribed topic.
+type QueueHandler func(ctx context.Context, msg *QueueMessage)
+
+// Queue is an in-memory message queue standing in for Kafka. Every topic has a single partition.
+type Queue struct {
+	lock     sync.Mutex
+	msgs     map[string][]*QueueMessage
+	handlers map[string][]QueueHandler
+}
+
+func NewQueue() *Queue {
+	return &Queue{
+		msgs:     make(map[string][]*QueueMessage),
+		handlers: make(map[string][]QueueHandler),
+	}
+}
+
+// Producer returns an mq.Producer writing to topic.
+func (q *Queue) Producer(topic string) mq.Producer {
+	return &queueProducer{queue: q, topic: topic}
+}
+
+// Subscribe registers a handler called synchronously for every message sent to topic afterwards.
+func (q *Queue) Subscribe(topic string, handler QueueHandler) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.handlers[topic] = append(q.handlers[topic], handler)
+}
+
+// Messages returns the messages sent to topic so far.
+func (q *Queue) Messages(topic string) []*QueueMessage {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return append([]*QueueMessage(nil), q.msgs[topic]...)
+}
+
+func (q *Queue) send(ctx context.Context, topic string, key string, msg proto.Message) (int32, int64, error) {
+	q.lock.Lock()
+	m := &QueueMessage{Topic: topic, Key: key, Offset: int64(len(q.msgs[topic])), Msg: proto.Clone(msg)}
+	q.msgs[topic] = append(q.msgs[topic], m)
+	handlers := append([]QueueHandler(nil), q.handlers[topic]...)
+	q.lock.Unlock()
+	for _, handler := range handlers {
+		handler(ctx, m)
+	}
+	return m.Partition, m.Offset, nil
+}
+
+type queueProducer struct {
+	queue *Queue
+	topic string
+}
+
+func (p *queueProducer) SendMessage(ctx context.Context, key string, msg proto.Message) (int32, int64, error) {
+	return p.queue.send(ctx, p.topic, key, msg)
+}
@@ -0,0 +1,168 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/OpenIMSDK/tools/discoveryregistry"
+	"github.com/OpenIMSDK/tools/errs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bufSize = 1024 * 1024
+
+var _ discoveryregistry.SvcDiscoveryRegistry = (*Registry)(nil)
+
+// Registry is an in-process discovery registry, every service is served on an in-memory bufconn listener
+// so no ports or zookeeper are needed.
+type Registry struct {
+	lock      sync.Mutex
+	listeners map[string]*bufconn.Listener
+	conns     map[string][]*grpc.ClientConn
+	confs     map[string][]byte
+	opts      []grpc.DialOption
+	self      string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		listeners: make(map[string]*bufconn.Listener),
+		conns:     make(map[string][]*grpc.ClientConn),
+		confs:     make(map[string][]byte),
+	}
+}
+
+// Listener returns the listener of serviceName, a grpc server serving on it is reachable through GetConn.
+func (r *Registry) Listener(serviceName string) net.Listener {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.listener(serviceName)
+}
+
+func (r *Registry) listener(serviceName string) *bufconn.Listener {
+	l, ok := r.listeners[serviceName]
+	if !ok {
+		l = bufconn.Listen(bufSize)
+		r.listeners[serviceName] = l
+	}
+	return l
+}
+
+func (r *Registry) GetConns(ctx context.Context, serviceName string, opts ...grpc.DialOption) ([]*grpc.ClientConn, error) {
+	conn, err := r.GetConn(ctx, serviceName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return []*grpc.ClientConn{conn}, nil
+}
+
+func (r *Registry) GetConn(ctx context.Context, serviceName string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if conns := r.conns[serviceName]; len(conns) > 0 {
+		return conns[0], nil
+	}
+	l := r.listener(serviceName)
+	options := append(append([]grpc.DialOption{}, r.opts...), opts...)
+	options = append(options,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return l.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	conn, err := grpc.DialContext(ctx, "passthrough:///"+serviceName, options...)
+	if err != nil {
+		return nil, errs.Wrap(err, "serviceName", serviceName)
+	}
+	r.conns[serviceName] = append(r.conns[serviceName], conn)
+	return conn, nil
+}
+
+func (r *Registry) GetSelfConnTarget() string {
+	return r.self
+}
+
+func (r *Registry) AddOption(opts ...grpc.DialOption) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.opts = append(r.opts, opts...)
+}
+
+func (r *Registry) CloseConn(conn *grpc.ClientConn) {
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+func (r *Registry) Register(serviceName, host string, port int, opts ...grpc.DialOption) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.listener(serviceName)
+	r.self = serviceName
+	return nil
+}
+
+func (r *Registry) UnRegister() error {
+	return nil
+}
+
+func (r *Registry) CreateRpcRootNodes(serviceNames []string) error {
+	return nil
+}
+
+func (r *Registry) RegisterConf2Registry(key string, conf []byte) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.confs[key] = conf
+	return nil
+}
+
+func (r *Registry) GetConfFromRegistry(key string) ([]byte, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	conf, ok := r.confs[key]
+	if !ok {
+		return nil, errs.Wrap(errors.New("conf not found"), "key", key)
+	}
+	return conf, nil
+}
+
+// Close closes all client connections and listeners.
+func (r *Registry) Close() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, conns := range r.conns {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}
+	for _, l := range r.listeners {
+		l.Close()
+	}
+	r.conns = make(map[string][]*grpc.ClientConn)
+	r.listeners = make(map[string]*bufconn.Listener)
+}
+
+func (r *Registry) GetClientLocalConns() map[string][]*grpc.ClientConn {
+	return nil
+}
+
+func (r *Registry) GetUserIdHashGatewayHost(ctx context.Context, userId string) (string, error) {
+	return "", nil
+}
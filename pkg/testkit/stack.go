@@ -0,0 +1,71 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testkit
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OpenIMSDK/tools/discoveryregistry"
+	"github.com/OpenIMSDK/tools/mw"
+	"github.com/openimsdk/open-im-server/v3/internal/api"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/config"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/cache"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/db/unrelation"
+	"google.golang.org/grpc"
+)
+
+// RpcStartFunc matches the Start function of every rpc service under internal/rpc.
+type RpcStartFunc func(config *config.GlobalConfig, client discoveryregistry.SvcDiscoveryRegistry, server *grpc.Server) error
+
+// StartRPC starts the rpc service serviceName in process, served on the registry listener.
+// The server is stopped when the test finishes.
+func StartRPC(t testing.TB, registry *Registry, config *config.GlobalConfig, serviceName string, start RpcStartFunc) {
+	t.Helper()
+	registry.AddOption(mw.GrpcClient())
+	srv := grpc.NewServer(mw.GrpcServer())
+	if err := start(config, registry, srv); err != nil {
+		t.Fatalf("start rpc %s: %v", serviceName, err)
+	}
+	if err := registry.Register(serviceName, "", 0); err != nil {
+		t.Fatalf("register rpc %s: %v", serviceName, err)
+	}
+	listener := registry.Listener(serviceName)
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+	t.Cleanup(srv.Stop)
+}
+
+// StartAPI starts the http api on an httptest server, rpc calls are resolved through registry.
+// The server is closed when the test finishes.
+func StartAPI(t testing.TB, registry *Registry, config *config.GlobalConfig) *httptest.Server {
+	t.Helper()
+	rdb, err := cache.NewRedis(config)
+	if err != nil {
+		t.Fatalf("connect redis: %v", err)
+	}
+	mongo, err := unrelation.NewMongo(config)
+	if err != nil {
+		t.Fatalf("connect mongo: %v", err)
+	}
+	router, err := api.NewGinRouter(registry, rdb, mongo, config)
+	if err != nil {
+		t.Fatalf("init api router: %v", err)
+	}
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
@@ -0,0 +1,127 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package userext is the part of the user rpc the protocol module does not define, see rpcext.
+package userext
+
+import (
+	"context"
+
+	"github.com/openimsdk/open-im-server/v3/pkg/apistruct"
+	"github.com/openimsdk/open-im-server/v3/pkg/rpcext"
+	"google.golang.org/grpc"
+)
+
+const ServiceName = "openim.user.UserExt"
+
+// Server is implemented by the user rpc.
+type Server interface {
+	SetNotificationAccountInfo(ctx context.Context, req *apistruct.SetNotificationAccountInfoReq) (*apistruct.SetNotificationAccountInfoResp, error)
+	GetNotificationAccountsInfo(ctx context.Context, req *apistruct.GetNotificationAccountsInfoReq) (*apistruct.GetNotificationAccountsInfoResp, error)
+	SetNotificationQuota(ctx context.Context, req *apistruct.SetNotificationQuotaReq) (*apistruct.SetNotificationQuotaResp, error)
+	GetNotificationQuota(ctx context.Context, req *apistruct.GetNotificationQuotaReq) (*apistruct.GetNotificationQuotaResp, error)
+	SetNotificationOptOut(ctx context.Context, req *apistruct.SetNotificationOptOutReq) (*apistruct.SetNotificationOptOutResp, error)
+	GetNotificationOptOut(ctx context.Context, req *apistruct.GetNotificationOptOutReq) (*apistruct.GetNotificationOptOutResp, error)
+	FollowNotificationAccount(ctx context.Context, req *apistruct.FollowNotificationAccountReq) (*apistruct.FollowNotificationAccountResp, error)
+	UnfollowNotificationAccount(ctx context.Context, req *apistruct.FollowNotificationAccountReq) (*apistruct.FollowNotificationAccountResp, error)
+	GetNotificationAccountFollowers(ctx context.Context, req *apistruct.GetNotificationAccountFollowersReq) (*apistruct.GetNotificationAccountFollowersResp, error)
+	GetFollowedNotificationAccounts(ctx context.Context, req *apistruct.GetFollowedNotificationAccountsReq) (*apistruct.GetFollowedNotificationAccountsResp, error)
+	GetNotificationAccountFollowerCounts(ctx context.Context, req *apistruct.GetNotificationAccountFollowerCountsReq) (*apistruct.GetNotificationAccountFollowerCountsResp, error)
+}
+
+// Register serves srv as the UserExt service of s.
+func Register(s *grpc.Server, srv Server) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+// Client calls the UserExt service, its methods can be passed to a2r.Call.
+type Client struct {
+	conn grpc.ClientConnInterface
+}
+
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{conn: conn}
+}
+
+func (c *Client) SetNotificationAccountInfo(ctx context.Context, req *apistruct.SetNotificationAccountInfoReq, opts ...grpc.CallOption) (*apistruct.SetNotificationAccountInfoResp, error) {
+	return invoke[apistruct.SetNotificationAccountInfoReq, apistruct.SetNotificationAccountInfoResp](ctx, c.conn, "SetNotificationAccountInfo", req, opts...)
+}
+
+func (c *Client) GetNotificationAccountsInfo(ctx context.Context, req *apistruct.GetNotificationAccountsInfoReq, opts ...grpc.CallOption) (*apistruct.GetNotificationAccountsInfoResp, error) {
+	return invoke[apistruct.GetNotificationAccountsInfoReq, apistruct.GetNotificationAccountsInfoResp](ctx, c.conn, "GetNotificationAccountsInfo", req, opts...)
+}
+
+func (c *Client) SetNotificationQuota(ctx context.Context, req *apistruct.SetNotificationQuotaReq, opts ...grpc.CallOption) (*apistruct.SetNotificationQuotaResp, error) {
+	return invoke[apistruct.SetNotificationQuotaReq, apistruct.SetNotificationQuotaResp](ctx, c.conn, "SetNotificationQuota", req, opts...)
+}
+
+func (c *Client) GetNotificationQuota(ctx context.Context, req *apistruct.GetNotificationQuotaReq, opts ...grpc.CallOption) (*apistruct.GetNotificationQuotaResp, error) {
+	return invoke[apistruct.GetNotificationQuotaReq, apistruct.GetNotificationQuotaResp](ctx, c.conn, "GetNotificationQuota", req, opts...)
+}
+
+func (c *Client) SetNotificationOptOut(ctx context.Context, req *apistruct.SetNotificationOptOutReq, opts ...grpc.CallOption) (*apistruct.SetNotificationOptOutResp, error) {
+	return invoke[apistruct.SetNotificationOptOutReq, apistruct.SetNotificationOptOutResp](ctx, c.conn, "SetNotificationOptOut", req, opts...)
+}
+
+func (c *Client) GetNotificationOptOut(ctx context.Context, req *apistruct.GetNotificationOptOutReq, opts ...grpc.CallOption) (*apistruct.GetNotificationOptOutResp, error) {
+	return invoke[apistruct.GetNotificationOptOutReq, apistruct.GetNotificationOptOutResp](ctx, c.conn, "GetNotificationOptOut", req, opts...)
+}
+
+func (c *Client) FollowNotificationAccount(ctx context.Context, req *apistruct.FollowNotificationAccountReq, opts ...grpc.CallOption) (*apistruct.FollowNotificationAccountResp, error) {
+	return invoke[apistruct.FollowNotificationAccountReq, apistruct.FollowNotificationAccountResp](ctx, c.conn, "FollowNotificationAccount", req, opts...)
+}
+
+func (c *Client) UnfollowNotificationAccount(ctx context.Context, req *apistruct.FollowNotificationAccountReq, opts ...grpc.CallOption) (*apistruct.FollowNotificationAccountResp, error) {
+	return invoke[apistruct.FollowNotificationAccountReq, apistruct.FollowNotificationAccountResp](ctx, c.conn, "UnfollowNotificationAccount", req, opts...)
+}
+
+func (c *Client) GetNotificationAccountFollowers(ctx context.Context, req *apistruct.GetNotificationAccountFollowersReq, opts ...grpc.CallOption) (*apistruct.GetNotificationAccountFollowersResp, error) {
+	return invoke[apistruct.GetNotificationAccountFollowersReq, apistruct.GetNotificationAccountFollowersResp](ctx, c.conn, "GetNotificationAccountFollowers", req, opts...)
+}
+
+func (c *Client) GetFollowedNotificationAccounts(ctx context.Context, req *apistruct.GetFollowedNotificationAccountsReq, opts ...grpc.CallOption) (*apistruct.GetFollowedNotificationAccountsResp, error) {
+	return invoke[apistruct.GetFollowedNotificationAccountsReq, apistruct.GetFollowedNotificationAccountsResp](ctx, c.conn, "GetFollowedNotificationAccounts", req, opts...)
+}
+
+func (c *Client) GetNotificationAccountFollowerCounts(ctx context.Context, req *apistruct.GetNotificationAccountFollowerCountsReq, opts ...grpc.CallOption) (*apistruct.GetNotificationAccountFollowerCountsResp, error) {
+	return invoke[apistruct.GetNotificationAccountFollowerCountsReq, apistruct.GetNotificationAccountFollowerCountsResp](ctx, c.conn, "GetNotificationAccountFollowerCounts", req, opts...)
+}
+
+func invoke[A, B any](ctx context.Context, conn grpc.ClientConnInterface, name string, req *A, opts ...grpc.CallOption) (*B, error) {
+	return rpcext.Invoke[A, B](ctx, conn, ServiceName, name, req, opts...)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*Server)(nil),
+	Methods: []grpc.MethodDesc{
+		method("SetNotificationAccountInfo", Server.SetNotificationAccountInfo),
+		method("GetNotificationAccountsInfo", Server.GetNotificationAccountsInfo),
+		method("SetNotificationQuota", Server.SetNotificationQuota),
+		method("GetNotificationQuota", Server.GetNotificationQuota),
+		method("SetNotificationOptOut", Server.SetNotificationOptOut),
+		method("GetNotificationOptOut", Server.GetNotificationOptOut),
+		method("FollowNotificationAccount", Server.FollowNotificationAccount),
+		method("UnfollowNotificationAccount", Server.UnfollowNotificationAccount),
+		method("GetNotificationAccountFollowers", Server.GetNotificationAccountFollowers),
+		method("GetFollowedNotificationAccounts", Server.GetFollowedNotificationAccounts),
+		method("GetNotificationAccountFollowerCounts", Server.GetNotificationAccountFollowerCounts),
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "userext.proto",
+}
+
+func method[A, B any](name string, call func(srv Server, ctx context.Context, req *A) (*B, error)) grpc.MethodDesc {
+	return rpcext.Method(ServiceName, name, call)
+}
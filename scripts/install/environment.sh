@@ -134,9 +134,18 @@ def "OPENIM_SERVER_ADDRESS" "${DOCKER_BRIDGE_GATEWAY}" # OpenIM服务地址
 # OpenIM Websocket端口
 readonly OPENIM_WS_PORT=${OPENIM_WS_PORT:-'10001'}
 
+# OpenIM MQTT端口
+readonly OPENIM_MQTT_PORT=${OPENIM_MQTT_PORT:-'1883'}
+
 # OpenIM API端口
 readonly API_OPENIM_PORT=${API_OPENIM_PORT:-'10002'}
 def "API_LISTEN_IP" "0.0.0.0" # API的监听IP
+def "API_BULKHEAD_ENABLE" "false" # 是否启用API并发隔离
+def "API_BULKHEAD_MSG" "2000"     # 消息接口最大并发数
+def "API_BULKHEAD_OBJECT" "200"   # 对象存储接口最大并发数
+def "API_BULKHEAD_GROUP" "500"    # 群组接口最大并发数
+def "API_BULKHEAD_PER_USER" "20"  # 单个用户最大并发请求数
+def "API_BULKHEAD_WAIT" "100"     # 等待并发槽位的时间(毫秒)
 
 ###################### openim-chat 配置信息 ######################
 def "OPENIM_CHAT_DATA_DIR" "./openim-chat/${CHAT_IMAGE_VERSION}"
@@ -166,6 +175,19 @@ def "ZOOKEEPER_ADDRESS" "${DOCKER_BRIDGE_GATEWAY}" # Zookeeper的地址
 def "ZOOKEEPER_USERNAME" ""                        # Zookeeper的用户名
 def "ZOOKEEPER_PASSWORD" ""                        # Zookeeper的密码
 
+###################### Etcd 配置信息 ######################
+def "ETCD_SCHEMA" "openim"                         # Etcd的模式
+def "ETCD_PORT" "2379"                             # Etcd的端口
+def "ETCD_ADDRESS" "${DOCKER_BRIDGE_GATEWAY}"      # Etcd的地址
+def "ETCD_USERNAME" ""                             # Etcd的用户名
+def "ETCD_PASSWORD" ""                             # Etcd的密码
+
+###################### Consul 配置信息 ######################
+def "CONSUL_SCHEMA" "openim"                       # Consul的模式
+def "CONSUL_PORT" "8500"                           # Consul的端口
+def "CONSUL_ADDRESS" "${DOCKER_BRIDGE_GATEWAY}"    # Consul的地址
+def "CONSUL_TOKEN" ""                              # Consul的ACL令牌
+
 ###################### MongoDB 配置信息 ######################
 def "MONGO_URI"                                # MongoDB的URI
 def "MONGO_PORT" "37017"                       # MongoDB的端口
@@ -180,6 +202,21 @@ def "MONGO_OPENIM_USERNAME" "openIM"
 readonly MONGO_OPENIM_PASSWORD=${MONGO_OPENIM_PASSWORD:-"${PASSWORD}"}
 
 def "MONGO_MAX_POOL_SIZE" "100"                # 最大连接池大小
+def "MONGO_READ_PREFERENCE_MODE" "primary"     # 历史消息、搜索和统计等重读取的读偏好
+def "MONGO_READ_PREFERENCE_MAX_STALENESS" "0"  # 从节点最大延迟（秒），0为不限制
+
+###################### PostgreSQL 配置信息 ######################
+def "POSTGRES_ENABLE" "false"                     # 是否使用PostgreSQL存储用户、好友、群组和会话
+def "POSTGRES_PORT" "5432"                        # PostgreSQL的端口
+def "POSTGRES_ADDRESS" "${DOCKER_BRIDGE_GATEWAY}" # PostgreSQL的地址
+def "POSTGRES_DATABASE" "${DATABASE_NAME}"        # PostgreSQL的数据库名
+def "POSTGRES_USERNAME" "openIM"                  # PostgreSQL的用户名
+# PostgreSQL的密码
+readonly POSTGRES_PASSWORD=${POSTGRES_PASSWORD:-"${PASSWORD}"}
+def "POSTGRES_SSL_MODE" "disable"                 # PostgreSQL的SSL模式
+def "POSTGRES_MAX_OPEN_CONN" "100"                # 最大打开连接数
+def "POSTGRES_MAX_IDLE_CONN" "10"                 # 最大空闲连接数
+def "POSTGRES_MAX_LIFE_TIME" "60"                 # 连接最大存活时间(秒)
 
 ###################### Object 配置信息 ######################
 # app要能访问到此ip和端口或域名
@@ -212,6 +249,17 @@ def "OSS_ACCESS_KEY_ID"                                                 # 阿里
 def "OSS_ACCESS_KEY_SECRET"                                             # 阿里云OSS的密钥
 def "OSS_SESSION_TOKEN"                                                 # 阿里云OSS的会话令牌
 def "OSS_PUBLIC_READ" "false"                                           # 公有读
+def "AZURE_ENDPOINT"                                                    # Azure Blob存储的端点URL
+def "AZURE_ACCOUNT_NAME"                                                # Azure存储账户名称
+def "AZURE_ACCOUNT_KEY"                                                 # Azure存储账户密钥
+def "AZURE_CONTAINER" "openim"                                          # Azure Blob存储的容器名称
+def "AZURE_PUBLIC_READ" "false"                                         # 公有读
+def "GCS_ENDPOINT"                                                      # Google Cloud Storage的端点URL
+def "GCS_BUCKET" "openim"                                               # Google Cloud Storage的存储桶名称
+def "GCS_CREDENTIALS_FILE"                                              # GCS服务账号密钥文件路径
+def "GCS_PUBLIC_READ" "false"                                           # 公有读
+def "LOCAL_OBJECT_DIR" "${OPENIM_ROOT}/_output/object/"                 # 本地对象存储目录
+def "LOCAL_OBJECT_QUOTA_MB" "0"                                         # 本地对象存储配额(MB)，0为不限制
 
 #七牛云配置信息
 def "KODO_ENDPOINT" "http://s3.cn-east-1.qiniucs.com"                    # 七牛云OSS的端点URL
@@ -239,15 +287,34 @@ readonly REDIS_PASSWORD=${REDIS_PASSWORD:-"${PASSWORD}"}
 ###################### Kafka 配置信息 ######################
 def "KAFKA_USERNAME"                                        # `Kafka` 的用户名
 def "KAFKA_PASSWORD"                                        # `Kafka` 的密码
+def "KAFKA_SASL_MECHANISM" "PLAIN"                          # `Kafka` 的SASL认证机制，可选 PLAIN、SCRAM-SHA-256、SCRAM-SHA-512
 def "KAFKA_PORT" "19094"                                    # `Kafka` 的端口
 def "KAFKA_ADDRESS" "${DOCKER_BRIDGE_GATEWAY}"              # `Kafka` 的地址
 def "KAFKA_LATESTMSG_REDIS_TOPIC" "latestMsgToRedis"        # `Kafka` 的最新消息到Redis的主题
+def "KAFKA_LATESTMSG_REDIS_HIGH_PRIORITY_TOPIC" "latestMsgToRedisHighPriority" # `Kafka` 的高优先级消息到Redis的主题
 def "KAFKA_OFFLINEMSG_MONGO_TOPIC" "offlineMsgToMongoMysql" # `Kafka` 的离线消息到Mongo的主题
 def "KAFKA_MSG_PUSH_TOPIC" "msgToPush"                      # `Kafka` 的消息到推送的主题
 def "KAFKA_CONSUMERGROUPID_REDIS" "redis"                   # `Kafka` 的消费组ID到Redis
 def "KAFKA_CONSUMERGROUPID_MONGO" "mongo"                   # `Kafka` 的消费组ID到Mongo
 def "KAFKA_CONSUMERGROUPID_MYSQL" "mysql"                   # `Kafka` 的消费组ID到MySql
 def "KAFKA_CONSUMERGROUPID_PUSH" "push"                     # `Kafka` 的消费组ID到推送
+def "KAFKA_BUSINESS_NOTIFICATION_TOPIC" "businessNotification" # `Kafka` 的业务通知主题
+def "KAFKA_CONSUMERGROUPID_BUSINESS_NOTIFICATION" "businessNotification" # `Kafka` 的消费组ID到业务通知
+
+###################### 消息队列 配置信息 ######################
+def "MQ_BACKEND" "kafka"                                    # 消息队列后端, kafka 或 nats
+def "MQ_DEAD_LETTER_SUFFIX" "-dlq"                          # 死信主题的后缀
+def "EVENT_EXPORT_ENABLE" "false"                            # 是否将领域事件发布到Kafka
+def "EVENT_EXPORT_TOPIC" "openim-events"                    # 领域事件的Kafka主题
+def "EVENT_EXPORT_EVENTS" ""                                # 发布的事件类型列表, 为空时发布全部
+def "EVENT_EXPORT_QUEUE_SIZE" "10000"                       # 每个服务待发布事件的队列长度
+def "NATS_ADDRESS" "nats://${DOCKER_BRIDGE_GATEWAY}"        # NATS的地址
+def "NATS_PORT" "4222"                                      # NATS的端口
+def "NATS_USERNAME"                                         # NATS的用户名
+def "NATS_PASSWORD"                                         # NATS的密码
+def "NATS_STREAM" "openim"                                  # JetStream 的流名称
+def "NATS_MAX_AGE" "24"                                     # 消息在流中保留的小时数
+def "NATS_ACK_WAIT" "30"                                    # 未确认消息重新投递的秒数
 
 ###################### openim-web 配置信息 ######################
 def "OPENIM_WEB_PORT" "11001"                       # openim-web的端口
@@ -344,7 +411,17 @@ def "LOG_WITH_STACK" "false"                        # 日志是否带有堆栈
 def "WEBSOCKET_MAX_CONN_NUM" "100000" # Websocket最大连接数
 def "WEBSOCKET_MAX_MSG_LEN" "4096"    # Websocket最大消息长度
 def "WEBSOCKET_TIMEOUT" "10"          # Websocket超时
+def "MQTT_ENABLE" "false"             # 是否启用MQTT接入
+def "SKIP_MUTED_BACKGROUND" "false"   # 后台应用不实时推送免打扰会话消息
+def "CONFORMANCE_ENABLE" "false"      # 是否以协议一致性测试模式运行msg_gateway
+def "CONFORMANCE_RECORD_DIR" ""       # 一致性测试会话记录目录
+def "PUSH_ACK_ENABLE" "false"         # 是否启用推送确认与重投
+def "PUSH_ACK_TIMEOUT" "3000"         # 推送确认超时时间(毫秒)
+def "PUSH_ACK_MAX_ATTEMPTS" "3"       # 单条消息最多推送次数
+def "PUSH_ACK_MAX_PENDING" "1000"     # 单个连接最多未确认消息数
+def "PUSH_ACK_REDELIVER_RATE" "200"   # 每个网关每秒最多重投消息数
 def "PUSH_ENABLE" "getui"             # 推送是否启用
+def "PUSH_DEFAULT_LANGUAGE" "en"      # 未设置语言的用户使用的推送语言
 # GeTui推送URL
 readonly GETUI_PUSH_URL=${GETUI_PUSH_URL:-'https://restapi.getui.com/v2/$appId'}
 def "GETUI_MASTER_SECRET" ""          # GeTui主密钥
@@ -357,11 +434,37 @@ def "JPNS_APP_KEY" ""                 # JPNS应用密钥
 def "JPNS_MASTER_SECRET" ""           # JPNS主密钥
 def "JPNS_PUSH_URL" ""                # JPNS推送URL
 def "JPNS_PUSH_INTENT" ""             # JPNS推送意图
+def "APNS_AUTH_KEY" ""                # APNs .p8密钥文件
+def "APNS_KEY_ID" ""                  # APNs密钥ID
+def "APNS_TEAM_ID" ""                 # APNs团队ID
+def "HMS_APP_ID" ""                   # 华为推送应用ID
+def "HMS_CLIENT_SECRET" ""            # 华为推送应用密钥
+def "HMS_INTENT" ""                   # 华为推送点击跳转Intent
 def "IM_ADMIN_USERID" "imAdmin"       # IM管理员ID
 def "IM_ADMIN_NAME" "imAdmin"         # IM管理员昵称
 def "MULTILOGIN_POLICY" "1"           # 多登录策略
+def "LOGIN_POLICY_MATRIX_ENABLE" "false" # 是否启用多端登录策略矩阵
+def "MSG_EDIT_ENABLE" "true"          # 是否允许编辑消息
+def "MSG_EDIT_WINDOW" "86400"         # 消息可编辑时间窗口(秒)
+def "CLOCK_SKEW_ENABLE" "true"        # 是否按服务器时间校正消息发送时间
+def "CLOCK_SKEW_TOLERANCE" "60000"    # 发送时间与服务器时间允许的偏差(毫秒)
+def "EPHEMERAL_TYPING_TTL" "5"        # 正在输入状态有效期(秒)
+def "EPHEMERAL_RECORDING_TTL" "10"    # 正在录音状态有效期(秒)
+def "EPHEMERAL_UPLOADING_TTL" "30"    # 正在上传状态有效期(秒)
+def "EPHEMERAL_RATE_LIMIT" "2"        # 每个发送者每秒允许的状态数
+def "EPHEMERAL_CONVERSATION_RATE_LIMIT" "10" # 每个会话每秒允许转发的状态数
+def "SEARCH_BACKEND" "mongo"          # 消息搜索后端(mongo或elasticsearch)
+def "SEARCH_ES_ADDRESS" ""            # Elasticsearch地址
+def "SEARCH_ES_INDEX" "openim_msg"    # Elasticsearch消息索引
+def "SEARCH_ES_GROUP_INDEX" "openim_group" # Elasticsearch公开群组索引
+def "SEARCH_ES_USERNAME" ""           # Elasticsearch用户名
+def "SEARCH_ES_PASSWORD" ""           # Elasticsearch密码
+def "SEARCH_ES_TIMEOUT" "5"           # Elasticsearch请求超时时间(秒)
 def "CHAT_PERSISTENCE_MYSQL" "true"   # 聊天持久化MySQL
 def "MSG_CACHE_TIMEOUT" "86400"       # 消息缓存超时
+def "RECENT_MSG_CACHE_ENABLE" "true"   # 是否缓存活跃会话的最新消息
+def "RECENT_MSG_CACHE_SIZE" "50"       # 每个会话缓存的最新消息数量
+def "RECENT_MSG_CACHE_EXPIRE" "86400"  # 会话无新消息后最新消息缓存的过期时间（秒）
 def "GROUP_MSG_READ_RECEIPT" "true"   # 群消息已读回执启用
 def "SINGLE_MSG_READ_RECEIPT" "true"  # 单一消息已读回执启用
 def "RETAIN_CHAT_RECORDS" "365"       # 保留聊天记录
@@ -372,7 +475,154 @@ readonly MSG_DESTRUCT_TIME=${MSG_DESTRUCT_TIME:-'0 2 * * *'}
 # 密钥
 readonly SECRET=${SECRET:-"${PASSWORD}"}
 def "TOKEN_EXPIRE" "90"         # Token到期时间
+def "TOKEN_SIGNING_METHOD" "HS256" # Token签名算法
+def "TOKEN_SIGNING_KEY_ID" ""   # Token签名密钥ID
+def "TOKEN_SECRET_RETIRED" "false" # 不再接受使用secret签名的Token
+def "TOKEN_FINGERPRINT_ENABLE" "false" # 是否将Token绑定设备指纹
+def "TOKEN_FINGERPRINT_GRACE" "true"   # 设备指纹不匹配时仅记录日志
+def "TOKEN_STANDBY_ENABLE" "false"     # Redis不可用时仅校验Token签名与有效期
+def "TOKEN_STANDBY_MAX_AGE" "24"       # 降级校验接受的Token最长签发时间(小时)
 def "FRIEND_VERIFY" "false"     # 朋友验证
+def "MSG_SIZE_DEFAULT" "51200"  # 消息内容默认最大字节数
+def "MSG_SIZE_TEXT" "16384"     # 文本消息最大字节数
+def "MSG_SIZE_CUSTOM" "32768"   # 自定义消息最大字节数
+def "MSG_SIZE_QUOTE" "16384"    # 引用消息最大字节数
+def "MODERATION_ENABLE" "false"         # 是否启用内容审核
+def "MODERATION_RELOAD_INTERVAL" "30"   # 审核规则重新加载间隔（秒）
+def "MODERATION_REVIEWER_URL" ""        # 外部审核服务地址
+def "MODERATION_REVIEWER_TIMEOUT" "5"   # 外部审核超时时间（秒）
+def "MODERATION_REVIEWER_SECRET" ""     # 外部审核签名密钥
+def "MODERATION_REVIEWER_CONCURRENCY" "10" # 外部审核最大并发数
+def "MODERATION_SCORING_ENABLE" "false" # 是否启用消息评分
+def "MODERATION_SCORING_SCORERS" "words" # 评分器列表（words、http）
+def "MODERATION_SCORING_WORDS" ""       # 脏话评分词列表
+def "MODERATION_SCORING_URL" ""         # 外部评分服务地址
+def "MODERATION_SCORING_TIMEOUT" "5"    # 外部评分超时时间（秒）
+def "MODERATION_SCORING_SECRET" ""      # 外部评分签名密钥
+def "MODERATION_SCORING_CONCURRENCY" "10" # 评分最大并发数
+def "MODERATION_SCORING_THRESHOLD_LOW" "0.9" # 低敏感度标记阈值
+def "MODERATION_SCORING_THRESHOLD_MEDIUM" "0.7" # 中敏感度标记阈值
+def "MODERATION_SCORING_THRESHOLD_STRICT" "0.5" # 严格敏感度标记阈值
+def "MODERATION_SCORING_SINGLE_LEVEL" "off" # 单聊默认敏感度
+def "MODERATION_SCORING_GROUP_LEVEL" "strict" # 群聊默认敏感度
+def "MODERATION_SCORING_OWNER_MIN_LEVEL" "low" # 群主可设置的最低敏感度
+def "MODERATION_SCORING_OWNER_MAX_LEVEL" "strict" # 群主可设置的最高敏感度
+def "BUSINESS_NOTIFICATION_FANOUT_RATE" "200" # 业务通知每秒分发数量
+def "MSG_PRIORITY_BULK_RATE" "200"    # 批量优先级消息每秒发送数量
+def "MSG_PRIORITY_BULK_BURST" "1000"  # 批量优先级消息突发数量
+def "ARCHIVE_ENABLE" "false"    # 是否启用合规归档
+def "ARCHIVE_ENDPOINT" ""       # 归档服务地址
+def "ARCHIVE_TOKEN" ""          # 归档服务令牌
+def "ARCHIVE_BATCH_SIZE" "500"  # 每批归档消息数量
+def "ARCHIVE_TIMEOUT" "10"      # 归档请求超时时间(秒)
+def "ARCHIVE_CRON_TIME" "*/5 * * * *" # 归档任务执行周期
+def "SCHEDULED_MSG_ENABLE" "true"       # 是否启用定时消息
+def "SCHEDULED_MSG_MAX_AHEAD" "2592000" # 定时消息最长提前时间(秒)
+def "SCHEDULED_MSG_BATCH_SIZE" "100"    # 每次发送的到期定时消息数量
+def "SCHEDULED_MSG_CRON_TIME" "@every 5s" # 定时消息任务执行周期
+def "USER_PURGE_ENABLE" "false"          # 是否启用用户数据清除
+def "USER_PURGE_BATCH_SIZE" "10"         # 每次执行的用户清除数量
+def "USER_PURGE_CRON_TIME" "*/5 * * * *" # 用户清除任务执行周期
+def "USER_PURGE_GROUP_SUCCESSION" "transfer" # 被清除用户所拥有群的处理方式 transfer/dismiss/freeze
+def "USER_PURGE_DELETE_GRACE_PERIOD" "604800" # 注销账号到清除数据的宽限期（秒）
+def "GROUP_BATCH_ENABLE" "false"          # 是否启用群组批量任务
+def "GROUP_BATCH_BATCH_SIZE" "5"          # 每次执行的群组批量任务数量
+def "GROUP_BATCH_CRON_TIME" "* * * * *"   # 群组批量任务执行周期
+def "GROUP_BATCH_MAX_ITEMS" "1000"        # 每个批量任务最多包含的群数量
+def "BROADCAST_ENABLE" "false"            # 是否启用全员广播消息
+def "BROADCAST_CRON_TIME" "* * * * *"     # 广播任务执行周期
+def "BROADCAST_RATE" "100"                # 广播每秒发送的消息数量
+def "BROADCAST_PAGE_SIZE" "100"           # 广播每页发送的用户数量
+def "BROADCAST_MAX_RUN_TIME" "50"         # 每次执行广播任务的最长秒数
+def "BROADCAST_MAX_RECV_IDS" "100000"     # 每个广播任务最多指定的用户数量
+def "GROUP_MEMBER_WEBHOOK_ENABLE" "false"       # 是否启用群成员变更 Webhook
+def "GROUP_MEMBER_WEBHOOK_URL" ""               # 群成员变更 Webhook 地址
+def "GROUP_MEMBER_WEBHOOK_SECRET" ""            # 群成员变更 Webhook 签名密钥,为空不签名
+def "GROUP_MEMBER_WEBHOOK_GROUP_IDS" ""         # 订阅的群ID,逗号分隔,为空订阅所有群
+def "GROUP_MEMBER_WEBHOOK_EVENTS" ""            # 订阅的事件 join/leave/kick/roleChange,逗号分隔,为空订阅所有事件
+def "GROUP_MEMBER_WEBHOOK_CRON_TIME" "@every 5s" # 群成员变更 Webhook 投递周期
+def "GROUP_MEMBER_WEBHOOK_BATCH_SIZE" "100"     # 每批投递的事件数量
+def "GROUP_MEMBER_WEBHOOK_MAX_ATTEMPTS" "10"    # 事件最多投递次数
+def "GROUP_MEMBER_WEBHOOK_BASE_INTERVAL" "10"   # 首次重试间隔(秒)
+def "GROUP_MEMBER_WEBHOOK_MAX_INTERVAL" "600"   # 最大重试间隔(秒)
+def "GROUP_MEMBER_WEBHOOK_TIMEOUT" "5"          # 投递超时时间(秒)
+def "OFFLINE_SYNC_MAX_SEQS" "0"         # 重连同步每个会话最多拉取的消息数量,0为不限制
+def "PUSH_RETRY_ENABLE" "false"         # 是否重试失败的离线推送
+def "PUSH_RETRY_MAX_ATTEMPTS" "5"       # 离线推送最多尝试次数,超过后进入死信队列
+def "PUSH_RETRY_BASE_INTERVAL" "10"     # 首次重试间隔(秒),之后每次翻倍
+def "PUSH_RETRY_MAX_INTERVAL" "600"     # 最大重试间隔(秒)
+def "PUSH_RETRY_BATCH_SIZE" "100"       # 每次扫描重试的推送数量
+def "PUSH_RETRY_SCAN_INTERVAL" "5"      # 重试扫描间隔(秒)
+def "PUSH_READINESS_ENABLE" "false"             # 推送消费积压过多时是否报告未就绪
+def "PUSH_READINESS_MAX_PARTITION_LAG" "10000"  # 单个分区最大积压消息数
+def "PUSH_READINESS_MAX_TOTAL_LAG" "50000"      # 所有分区最大积压消息数
+def "PUSH_READINESS_CHECK_INTERVAL" "5"         # 积压检查间隔(秒)
+def "DESTINATION_THROTTLE_ENABLE" "false"       # 是否按目标限流或停用失败的回调地址和推送渠道
+def "DESTINATION_THROTTLE_WINDOW" "50"          # 统计错误率和延迟的最近调用次数
+def "DESTINATION_THROTTLE_MIN_CALLS" "20"       # 限流或停用前至少需要的调用次数
+def "DESTINATION_THROTTLE_ERROR_PERCENT" "20"   # 触发限流的错误率(百分比)
+def "DESTINATION_DISABLE_ERROR_PERCENT" "50"    # 触发停用的错误率(百分比)
+def "DESTINATION_THROTTLE_SLOW_LATENCY" "3000"  # 触发限流的平均延迟(毫秒)
+def "DESTINATION_THROTTLED_RATE" "10"           # 限流时每秒允许的调用次数
+def "DESTINATION_DISABLE_DURATION" "30"         # 首次停用时长(秒),探测失败后翻倍
+def "DESTINATION_MAX_DISABLE_DURATION" "600"    # 最长停用时长(秒)
+def "DESTINATION_THROTTLE_ALERT_URL" ""         # 目标停用或恢复时通知的地址
+def "MEETING_ENABLE" "false"            # 是否启用预约会议
+def "MEETING_JOIN_SECRET" "${PASSWORD}" # 会议入会令牌签名密钥
+def "MEETING_JOIN_TOKEN_TTL" "3600"     # 入会令牌有效期(秒)
+def "MEETING_REMIND_BEFORE" "600"       # 会议开始前多少秒发送提醒
+def "MEETING_BATCH_SIZE" "100"          # 每次处理的会议数量
+def "MEETING_CRON_TIME" "* * * * *"     # 会议状态任务执行周期
+def "RTC_ENABLE" "false"                # 是否启用ICE服务器下发
+def "RTC_TURN_SECRET" "${PASSWORD}"     # coturn的static-auth-secret
+def "RTC_TURN_URI" "turn:${OPENIM_IP}:3478?transport=udp" # TURN服务器地址
+def "RTC_STUN_URI" "stun:${OPENIM_IP}:3478"    # STUN服务器地址
+def "RTC_TTL" "86400"                   # TURN临时凭证有效期(秒)
+def "TENANT_ENABLE" "false"             # 是否启用租户配置覆盖
+def "TENANT_RELOAD_INTERVAL" "30"       # 租户配置重新加载间隔(秒)
+def "TENANT_ISOLATION" "false"          # 是否隔离各应用的用户和群组
+def "TENANT_DEDICATED_TOPIC_APPS" ""     # 使用独立Kafka主题的应用ID列表
+def "HOT_CONVERSATION_ENABLE" "false"   # 是否启用热点会话检测
+def "HOT_CONVERSATION_THRESHOLD" "100"  # 窗口内成为热点会话的消息数
+def "HOT_CONVERSATION_WINDOW" "10"      # 热点检测窗口(秒)
+def "HOT_CONVERSATION_EXPIRE" "600"     # 热点会话本地缓存时间(秒)
+def "HOT_CONVERSATION_MAX_NUM" "1000"   # 单实例最大热点会话数
+def "REDIS_LOCAL_TIER_ENABLE" "false"   # 是否在Redis前启用进程内缓存
+def "RECEIPT_COMPACTION_ENABLE" "false"        # 是否启用已读回执压缩
+def "RECEIPT_COMPACTION_RETAIN_DAYS" "30"      # 已读回执明细保留天数
+def "RECEIPT_COMPACTION_BATCH_SIZE" "500"      # 每批加载的已读回执数量
+def "RECEIPT_COMPACTION_CRON_TIME" "30 3 * * *" # 已读回执压缩任务执行周期
+def "POLICY_SIMULATION_MAX_HOURS" "24"       # 策略模拟最多回溯的小时数
+def "POLICY_SIMULATION_SAMPLE_SIZE" "2000"   # 每次策略模拟的最大采样消息数
+def "ID_GENERATOR_STRATEGY" "legacy"         # ID生成策略，可选 legacy、uuidv7、snowflake
+def "ID_GENERATOR_TENANT_PREFIX" "false"     # 是否为生成的ID添加应用ID前缀
+def "ID_GENERATOR_SNOWFLAKE_EPOCH" "1704067200000" # Snowflake起始时间(毫秒时间戳)
+def "ID_GENERATOR_SNOWFLAKE_WORKER_ID" "-1"  # Snowflake机器ID，-1表示从注册中心租用
+def "UNDO_SEND_ENABLE" "false"               # 是否启用撤销发送窗口
+def "UNDO_SEND_WINDOW" "5"                   # 消息发出前可撤销的时间(秒)
+def "UNDO_SEND_BATCH_SIZE" "100"             # 每次扫描释放的暂存消息数量
+def "MSG_DEDUP_ENABLE" "false"              # 是否启用消息发送去重
+def "MSG_DEDUP_WINDOW" "600"                # 相同clientMsgID去重时间窗口(秒)
+def "ANTI_SPAM_ENABLE" "false"              # 是否启用防刷屏限制
+def "ANTI_SPAM_RATE" "10"                   # 每个用户每秒最多发送消息数
+def "ANTI_SPAM_REPEAT_LIMIT" "5"            # 时间窗口内相同内容最多发送次数
+def "ANTI_SPAM_REPEAT_WINDOW" "60"          # 相同内容计数时间窗口(秒)
+def "ANTI_SPAM_NEW_ACCOUNT_AGE" "86400"     # 新账号判定时长(秒)
+def "ANTI_SPAM_NEW_ACCOUNT_RATE" "1"        # 新账号每秒最多发送消息数
+def "NOTIFICATION_QUOTA_ENABLE" "false"     # 是否启用通知账号发送配额
+def "NOTIFICATION_QUOTA_RATE" "20"          # 每个通知账号每秒最多发送消息数
+def "NOTIFICATION_QUOTA_DAILY_CAP" "10"     # 通知账号每天最多向每个用户发送消息数
+def "ONBOARDING_AUTO_JOIN" "false"          # 注册时是否自动加入默认群组
+def "SEQ_ALLOCATOR_ENABLE" "false"          # 是否按号段分配会话seq
+def "SEQ_ALLOCATOR_SEGMENT_SIZE" "100"      # 每次从mongo租用的seq数量
+def "HANDOFF_ENABLE" "false"                 # 是否启用会话接力链接
+def "HANDOFF_SECRET" "${PASSWORD}"           # 接力令牌签名密钥
+def "HANDOFF_TTL" "300"                      # 接力令牌有效期(秒)
+def "HANDOFF_AUDIT_SIZE" "100"               # 每个用户保留的接力审计记录数
+def "MSG_EXPORT_ENABLE" "false"              # 是否启用会话消息导出
+def "MSG_EXPORT_MAX_MSGS" "100000"           # 每次导出的最大消息数量
+def "MSG_IMPORT_ENABLE" "false"              # 是否启用历史消息导入
+def "MSG_IMPORT_MAX_BATCH" "1000"            # 每次导入请求的最大消息数量
 def "IOS_PUSH_SOUND" "xxx"      # IOS推送声音
 def "IOS_BADGE_COUNT" "true"    # IOS徽章计数
 def "IOS_PRODUCTION" "false"    # IOS生产
@@ -380,6 +630,9 @@ def "IOS_PRODUCTION" "false"    # IOS生产
 def "CALLBACK_ENABLE" "false"          # 是否开启 Callback
 def "CALLBACK_TIMEOUT" "5"            # 最长超时时间
 def "CALLBACK_FAILED_CONTINUE" "true" # 失败后是否继续
+def "CALLBACK_SECRET" ""               # Callback 请求签名密钥,为空不签名
+def "CALLBACK_GRPC_ADDR" ""            # gRPC Callback 服务地址
+def "CALLBACK_MSG_TRANSPORT" "http"    # 发送消息相关Callback的传输方式, http 或 grpc
 ###################### Prometheus 配置信息 ######################
 # 是否启用 Prometheus
 readonly PROMETHEUS_ENABLE=${PROMETHEUS_ENABLE:-'true'}
@@ -25,7 +25,9 @@ import (
 
 	"github.com/IBM/sarama"
 
+	"github.com/openimsdk/open-im-server/v3/pkg/common/jetstream"
 	"github.com/openimsdk/open-im-server/v3/pkg/common/kafka"
+	"github.com/openimsdk/open-im-server/v3/pkg/common/mqbuild"
 
 	"github.com/OpenIMSDK/tools/component"
 	"github.com/OpenIMSDK/tools/errs"
@@ -104,7 +106,11 @@ func main() {
 		{name: "Redis", function: checkRedis, config: conf},
 		{name: "Minio", function: checkMinio, config: conf},
 		{name: "Zookeeper", function: checkZookeeper, config: conf},
-		{name: "Kafka", function: checkKafka, config: conf},
+	}
+	if mqbuild.Backend(conf) == mqbuild.BackendNats {
+		checks = append(checks, checkFunc{name: "Nats", function: checkNats, config: conf})
+	} else {
+		checks = append(checks, checkFunc{name: "Kafka", function: checkKafka, config: conf})
 	}
 
 	for i := 0; i < maxRetry; i++ {
@@ -214,19 +220,26 @@ func checkZookeeper(config *config.GlobalConfig) error {
 	return err
 }
 
-// checkKafka checks the Kafka connection
-func checkKafka(config *config.GlobalConfig) error {
-	// Prioritize environment variables
-	kafkaStu := &component.Kafka{
-		Username: config.Kafka.Username,
-		Password: config.Kafka.Password,
-		Addr:     config.Kafka.Addr,
+// checkNats checks the NATS connection and creates the JetStream stream
+func checkNats(config *config.GlobalConfig) error {
+	client, err := jetstream.NewClient(config)
+	if err != nil {
+		return err
 	}
+	client.Close()
+	return nil
+}
 
-	kafkaClient, err := component.CheckKafka(kafkaStu)
+// checkKafka checks the Kafka connection
+func checkKafka(config *config.GlobalConfig) error {
+	clientConfig, err := kafka.NewClientConfig(config)
 	if err != nil {
 		return err
 	}
+	kafkaClient, err := sarama.NewClient(config.Kafka.Addr, clientConfig)
+	if err != nil {
+		return errs.Wrap(err, "connect kafka failed", strings.Join(config.Kafka.Addr, ","))
+	}
 	defer kafkaClient.Close()
 
 	// Verify if necessary topics exist
@@ -247,42 +260,29 @@ func checkKafka(config *config.GlobalConfig) error {
 		}
 	}
 
-	var tlsConfig *kafka.TLSConfig
-	if config.Kafka.TLS != nil {
-		tlsConfig = &kafka.TLSConfig{
-			CACrt:              config.Kafka.TLS.CACrt,
-			ClientCrt:          config.Kafka.TLS.ClientCrt,
-			ClientKey:          config.Kafka.TLS.ClientKey,
-			ClientKeyPwd:       config.Kafka.TLS.ClientKeyPwd,
-			InsecureSkipVerify: config.Kafka.TLS.InsecureSkipVerify,
-		}
-	}
-
-	_, err = kafka.NewMConsumerGroup(&kafka.MConsumerGroupConfig{
+	tlsConfig := kafka.NewTLSConfig(config)
+	groupConfig := &kafka.MConsumerGroupConfig{
 		KafkaVersion:   sarama.V2_0_0_0,
 		OffsetsInitial: sarama.OffsetNewest,
 		IsReturnErr:    false,
 		UserName:       config.Kafka.Username,
 		Password:       config.Kafka.Password,
-	}, []string{config.Kafka.LatestMsgToRedis.Topic},
+		SASLMechanism:  config.Kafka.SASLMechanism,
+	}
+
+	_, err = kafka.NewMConsumerGroup(groupConfig, []string{config.Kafka.LatestMsgToRedis.Topic},
 		config.Kafka.Addr, config.Kafka.ConsumerGroupID.MsgToRedis, tlsConfig)
 	if err != nil {
 		return err
 	}
 
-	_, err = kafka.NewMConsumerGroup(&kafka.MConsumerGroupConfig{
-		KafkaVersion:   sarama.V2_0_0_0,
-		OffsetsInitial: sarama.OffsetNewest, IsReturnErr: false,
-	}, []string{config.Kafka.MsgToMongo.Topic},
+	_, err = kafka.NewMConsumerGroup(groupConfig, []string{config.Kafka.MsgToMongo.Topic},
 		config.Kafka.Addr, config.Kafka.ConsumerGroupID.MsgToMongo, tlsConfig)
 	if err != nil {
 		return err
 	}
 
-	_, err = kafka.NewMConsumerGroup(&kafka.MConsumerGroupConfig{
-		KafkaVersion:   sarama.V2_0_0_0,
-		OffsetsInitial: sarama.OffsetNewest, IsReturnErr: false,
-	}, []string{config.Kafka.MsgToPush.Topic}, config.Kafka.Addr,
+	_, err = kafka.NewMConsumerGroup(groupConfig, []string{config.Kafka.MsgToPush.Topic}, config.Kafka.Addr,
 		config.Kafka.ConsumerGroupID.MsgToPush, tlsConfig)
 	if err != nil {
 		return err
@@ -330,6 +330,7 @@ func configGetEnv(config *config.GlobalConfig) error {
 
 	config.Kafka.Username = getEnv("KAFKA_USERNAME", config.Kafka.Username)
 	config.Kafka.Password = getEnv("KAFKA_PASSWORD", config.Kafka.Password)
+	config.Kafka.SASLMechanism = getEnv("KAFKA_SASL_MECHANISM", config.Kafka.SASLMechanism)
 	config.Kafka.Addr = getArrEnv("KAFKA_ADDRESS", "KAFKA_PORT", config.Kafka.Addr)
 	config.Object.Minio.Endpoint = getMinioAddr("MINIO_ENDPOINT", "MINIO_ADDRESS", "MINIO_PORT", config.Object.Minio.Endpoint)
 	return nil
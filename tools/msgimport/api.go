@@ -0,0 +1,108 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// adminPlatformID is the platform of the admin token, constant.AdminPlatformID.
+const adminPlatformID = 10
+
+type Api struct {
+	Api    string
+	UserID string
+	Secret string
+	Token  string
+	Client *http.Client
+}
+
+type ImportMsgsResp struct {
+	ConversationID string `json:"conversationID"`
+	Imported       int    `json:"imported"`
+	Skipped        int    `json:"skipped"`
+	FirstSeq       int64  `json:"firstSeq"`
+	LastSeq        int64  `json:"lastSeq"`
+}
+
+func (a *Api) post(ctx context.Context, path string, req any, resp any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, a.Api+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("operationID", "msgimport_"+strconv.FormatInt(time.Now().UnixNano(), 10))
+	if a.Token != "" {
+		request.Header.Set("token", a.Token)
+	}
+	response, err := a.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("api %s status %s body %s", path, response.Status, data)
+	}
+	var base struct {
+		ErrCode int             `json:"errCode"`
+		ErrMsg  string          `json:"errMsg"`
+		ErrDlt  string          `json:"errDlt"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &base); err != nil {
+		return err
+	}
+	if base.ErrCode != 0 {
+		return fmt.Errorf("api %s errCode %d errMsg %s errDlt %s", path, base.ErrCode, base.ErrMsg, base.ErrDlt)
+	}
+	if resp != nil {
+		return json.Unmarshal(base.Data, resp)
+	}
+	return nil
+}
+
+func (a *Api) GetToken(ctx context.Context) (string, error) {
+	req := map[string]any{"userID": a.UserID, "secret": a.Secret, "platformID": adminPlatformID}
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := a.post(ctx, "/auth/user_token", req, &resp); err != nil {
+		return "", err
+	}
+	return resp.Token, nil
+}
+
+func (a *Api) ImportMsgs(ctx context.Context, req *ImportMsgsReq) (*ImportMsgsResp, error) {
+	var resp ImportMsgsResp
+	if err := a.post(ctx, "/msg/import_msgs", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,154 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+const (
+	singleChatType     = 1
+	groupChatType      = 2
+	superGroupChatType = 3
+)
+
+// Line is one message of the input file.
+type Line struct {
+	SessionType      int32           `json:"sessionType"`
+	SendID           string          `json:"sendID"`
+	RecvID           string          `json:"recvID"`
+	GroupID          string          `json:"groupID"`
+	ClientMsgID      string          `json:"clientMsgID"`
+	SenderNickname   string          `json:"senderNickname"`
+	SenderFaceURL    string          `json:"senderFaceURL"`
+	SenderPlatformID int32           `json:"senderPlatformID"`
+	ContentType      int32           `json:"contentType"`
+	Content          json.RawMessage `json:"content"`
+	SendTime         int64           `json:"sendTime"`
+	Ex               string          `json:"ex"`
+}
+
+type ImportMsg struct {
+	ClientMsgID      string          `json:"clientMsgID"`
+	SendID           string          `json:"sendID"`
+	SenderNickname   string          `json:"senderNickname"`
+	SenderFaceURL    string          `json:"senderFaceURL"`
+	SenderPlatformID int32           `json:"senderPlatformID"`
+	ContentType      int32           `json:"contentType"`
+	Content          json.RawMessage `json:"content"`
+	SendTime         int64           `json:"sendTime"`
+	Ex               string          `json:"ex"`
+}
+
+type ImportMsgsReq struct {
+	SessionType int32        `json:"sessionType"`
+	UserIDs     []string     `json:"userIDs,omitempty"`
+	GroupID     string       `json:"groupID,omitempty"`
+	Msgs        []*ImportMsg `json:"msgs"`
+}
+
+// Batch is a request for consecutive lines of one conversation, First and Last are 1-based line numbers.
+type Batch struct {
+	First int
+	Last  int
+	Req   *ImportMsgsReq
+}
+
+// conversationKey identifies the conversation of line, the two users of a single chat in either direction.
+func conversationKey(line *Line) (string, error) {
+	switch line.SessionType {
+	case singleChatType:
+		if line.SendID == "" || line.RecvID == "" {
+			return "", fmt.Errorf("single chat needs sendID and recvID")
+		}
+		userIDs := []string{line.SendID, line.RecvID}
+		sort.Strings(userIDs)
+		return "si_" + userIDs[0] + "_" + userIDs[1], nil
+	case groupChatType, superGroupChatType:
+		if line.GroupID == "" {
+			return "", fmt.Errorf("group chat needs groupID")
+		}
+		return "sg_" + line.GroupID, nil
+	default:
+		return "", fmt.Errorf("unsupported sessionType %d", line.SessionType)
+	}
+}
+
+// Batches reads r and calls fn with the messages of consecutive lines of the same conversation, at most size
+// per batch. Lines before start and blank lines are skipped, the file should be grouped by conversation so that
+// each conversation keeps its send time order across batches.
+func Batches(r io.Reader, start int, size int, fn func(batch *Batch) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	var (
+		batch *Batch
+		key   string
+	)
+	flush := func() error {
+		if batch == nil {
+			return nil
+		}
+		b := batch
+		batch = nil
+		return fn(b)
+	}
+	var n int
+	for scanner.Scan() {
+		n++
+		if n < start || len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var line Line
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		lineKey, err := conversationKey(&line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		if batch != nil && (lineKey != key || len(batch.Req.Msgs) >= size) {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		if batch == nil {
+			key = lineKey
+			batch = &Batch{First: n, Req: &ImportMsgsReq{SessionType: line.SessionType, GroupID: line.GroupID}}
+			if line.SessionType == singleChatType {
+				batch.Req.UserIDs = []string{line.SendID, line.RecvID}
+			}
+		}
+		batch.Last = n
+		batch.Req.Msgs = append(batch.Req.Msgs, &ImportMsg{
+			ClientMsgID:      line.ClientMsgID,
+			SendID:           line.SendID,
+			SenderNickname:   line.SenderNickname,
+			SenderFaceURL:    line.SenderFaceURL,
+			SenderPlatformID: line.SenderPlatformID,
+			ContentType:      line.ContentType,
+			Content:          line.Content,
+			SendTime:         line.SendTime,
+			Ex:               line.Ex,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
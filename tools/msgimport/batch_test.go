@@ -0,0 +1,64 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBatches(t *testing.T) {
+	input := strings.Join([]string{
+		`{"sessionType":1,"sendID":"u1","recvID":"u2","contentType":101,"sendTime":1}`,
+		`{"sessionType":1,"sendID":"u2","recvID":"u1","contentType":101,"sendTime":2}`,
+		`{"sessionType":1,"sendID":"u1","recvID":"u2","contentType":101,"sendTime":3}`,
+		``,
+		`{"sessionType":3,"sendID":"u1","groupID":"g1","contentType":101,"sendTime":4}`,
+	}, "\n")
+	var batches []*Batch
+	err := Batches(strings.NewReader(input), 1, 2, func(batch *Batch) error {
+		batches = append(batches, batch)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != 3 {
+		t.Fatalf("batches = %d, want 3", len(batches))
+	}
+	if b := batches[0]; b.First != 1 || b.Last != 2 || len(b.Req.Msgs) != 2 || len(b.Req.UserIDs) != 2 {
+		t.Fatalf("first batch = %+v", b)
+	}
+	if b := batches[1]; b.First != 3 || b.Last != 3 {
+		t.Fatalf("second batch = %+v", b)
+	}
+	if b := batches[2]; b.First != 5 || b.Req.GroupID != "g1" || b.Req.UserIDs != nil {
+		t.Fatalf("group batch = %+v", b)
+	}
+}
+
+func TestBatchesStartAndErrors(t *testing.T) {
+	input := "not json\n" + `{"sessionType":1,"sendID":"u1","recvID":"u2","sendTime":1}`
+	var n int
+	if err := Batches(strings.NewReader(input), 2, 10, func(*Batch) error { n++; return nil }); err != nil || n != 1 {
+		t.Fatalf("n = %d err = %v", n, err)
+	}
+	if err := Batches(strings.NewReader(input), 1, 10, func(*Batch) error { return nil }); err == nil {
+		t.Fatal("expected an error for the first line")
+	}
+	if err := Batches(strings.NewReader(`{"sessionType":4,"sendID":"u1"}`), 1, 10, func(*Batch) error { return nil }); err == nil {
+		t.Fatal("expected an error for the session type")
+	}
+}
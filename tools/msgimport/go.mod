@@ -0,0 +1,3 @@
+module github.com/openimsdk/open-im-server/v3/tools/msgimport
+
+go 1.19
@@ -0,0 +1,79 @@
+// Copyright © 2024 OpenIM. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// msgimport migrates the history of another IM system into OpenIM through /msg/import_msgs.
+//
+// The input is a JSON lines file, one message per line grouped by conversation and in send time order within it:
+//
+//	{"sessionType":1,"sendID":"u1","recvID":"u2","contentType":101,"content":{"content":"hi"},"sendTime":1700000000000}
+//	{"sessionType":3,"sendID":"u1","groupID":"g1","contentType":101,"content":{"content":"hello"},"sendTime":1700000000001}
+//
+// Each batch is imported once, when a batch fails the import stops and prints the line to resume from with -start.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+func main() {
+	var (
+		api     Api
+		path    string
+		start   int
+		batch   int
+		timeout int64
+	)
+	flag.StringVar(&path, "file", "msgs.jsonl", "Path of the JSON lines file to import")
+	flag.IntVar(&start, "start", 1, "Line to start from, to resume a stopped import")
+	flag.IntVar(&batch, "batch", 500, "Messages per request, at most msgImport.maxBatch")
+	flag.Int64Var(&timeout, "timeout", 30000, "Request timeout in milliseconds")
+	flag.StringVar(&api.Api, "api", "http://127.0.0.1:10002", "API endpoint for the IM service")
+	flag.StringVar(&api.UserID, "userID", "openIM123456", "IM administrator's user ID")
+	flag.StringVar(&api.Secret, "secret", "openIM123", "Secret for the IM configuration")
+	flag.Parse()
+	if batch <= 0 {
+		log.Fatalln("batch must be positive")
+	}
+	api.Client = &http.Client{Timeout: time.Duration(timeout) * time.Millisecond}
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalln("open file:", err)
+	}
+	defer file.Close()
+	ctx := context.Background()
+	if api.Token, err = api.GetToken(ctx); err != nil {
+		log.Fatalln("get admin token:", err)
+	}
+	var imported, skipped int
+	err = Batches(file, start, batch, func(b *Batch) error {
+		resp, err := api.ImportMsgs(ctx, b.Req)
+		if err != nil {
+			log.Printf("lines %d-%d failed, resume with -start=%d: %v", b.First, b.Last, b.First, err)
+			return err
+		}
+		imported += resp.Imported
+		skipped += resp.Skipped
+		log.Printf("lines %d-%d imported %d skipped %d into %s seq %d-%d", b.First, b.Last, resp.Imported, resp.Skipped, resp.ConversationID, resp.FirstSeq, resp.LastSeq)
+		return nil
+	})
+	log.Printf("imported %d msgs, skipped %d notifications", imported, skipped)
+	if err != nil {
+		os.Exit(1)
+	}
+}